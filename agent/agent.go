@@ -58,6 +58,10 @@ const (
 	StorageAddr      = "STORAGE_ADDR"
 	AgentServiceName = "AGENT_SERVICE_NAME"
 	MongoOplogSize   = "MONGO_OPLOG_SIZE"
+	MongoJournal     = "MONGO_JOURNAL"
+	MongoPrealloc    = "MONGO_PREALLOC"
+	MongoCacheSize   = "MONGO_CACHE_SIZE"
+	APITrace         = "API_TRACE"
 )
 
 // The Config interface is the sole way that the agent gets access to the
@@ -163,6 +167,10 @@ type ConfigSetterOnly interface {
 	// SetAPIHostPorts sets the API host/port addresses to connect to.
 	SetAPIHostPorts(servers [][]network.HostPort)
 
+	// SetCACert sets the CA certificate used to validate the state
+	// and API servers' certificates.
+	SetCACert(caCert string)
+
 	// Migrate takes an existing agent config and applies the given
 	// parameters to change it.
 	//
@@ -472,6 +480,10 @@ func (c *configInternal) SetAPIHostPorts(servers [][]network.HostPort) {
 	c.apiDetails.addresses = addrs
 }
 
+func (c *configInternal) SetCACert(caCert string) {
+	c.caCert = caCert
+}
+
 func (c *configInternal) SetValue(key, value string) {
 	if value == "" {
 		delete(c.values, key)
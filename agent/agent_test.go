@@ -688,3 +688,13 @@ func (*suite) TestSetAPIHostPorts(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(addrs, gc.DeepEquals, []string{"0.1.2.3:123", "0.1.2.5:125"})
 }
+
+func (*suite) TestSetCACert(c *gc.C) {
+	conf, err := agent.NewAgentConfig(attributeParams)
+	c.Assert(err, gc.IsNil)
+	c.Assert(conf.CACert(), gc.Equals, attributeParams.CACert)
+
+	conf.SetCACert("new-ca-cert")
+	c.Assert(conf.CACert(), gc.Equals, "new-ca-cert")
+	c.Assert(conf.APIInfo().CACert, gc.Equals, "new-ca-cert")
+}
@@ -145,7 +145,11 @@ func initMongoAdminUser(info mongo.Info, dialOpts mongo.DialOpts, password strin
 
 // initBootstrapMachine initializes the initial bootstrap machine in state.
 func initBootstrapMachine(c ConfigSetter, st *state.State, cfg BootstrapMachineConfig) (*state.Machine, error) {
-	logger.Infof("initialising bootstrap machine with config: %+v", cfg)
+	logCfg := cfg
+	if logCfg.SharedSecret != "" {
+		logCfg.SharedSecret = "REDACTED"
+	}
+	logger.Infof("initialising bootstrap machine with config: %+v", logCfg)
 
 	jobs := make([]state.MachineJob, len(cfg.Jobs))
 	for i, job := range cfg.Jobs {
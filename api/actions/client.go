@@ -61,6 +61,14 @@ func (c *Client) ListCompleted(arg params.Tags) (params.ActionsByReceivers, erro
 	return results, err
 }
 
+// Actions takes a list of ActionTags, and returns the full Action for
+// each ID, whether it is pending or has already completed.
+func (c *Client) Actions(arg params.ActionTags) (params.ActionResults, error) {
+	results := params.ActionResults{}
+	err := c.facade.FacadeCall("Actions", arg, &results)
+	return results, err
+}
+
 // Cancel attempts to cancel a queued up Action from running.
 func (c *Client) Cancel(arg params.Actions) (params.ActionResults, error) {
 	results := params.ActionResults{}
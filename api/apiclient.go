@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"time"
 
 	"code.google.com/p/go.net/websocket"
@@ -17,6 +18,7 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/utils/parallel"
 
+	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/network"
@@ -31,6 +33,10 @@ var logger = loggo.GetLogger("juju.api")
 var PingPeriod = 1 * time.Minute
 
 type State struct {
+	// mu guards the connection and the details that change when
+	// the client reconnects, along with the watcher records.
+	mu sync.Mutex
+
 	client *rpc.Conn
 	conn   *websocket.Conn
 
@@ -69,6 +75,27 @@ type State struct {
 	// certPool holds the cert pool that is used to authenticate the tls
 	// connections to the API.
 	certPool *x509.CertPool
+
+	// info and opts hold the parameters the client was opened with.
+	// If opts.Reconnect is set, they are used to reconnect when the
+	// connection fails.
+	info *Info
+	opts DialOpts
+
+	// reconnectMu ensures that only one reconnection is attempted
+	// at a time, and generation counts the connections made.
+	reconnectMu sync.Mutex
+	generation  int
+
+	// subscriptions records the calls that created active watchers,
+	// and watcherIds maps the client's ids for those watchers to
+	// their ids on the current connection.
+	subscriptions []*watcherSubscription
+	watcherIds    map[string]string
+	lastWatcherId int
+
+	// notifyWatcherMux is created on first use by NotifyWatcherMux.
+	notifyWatcherMux *watcher.NotifyWatcherMux
 }
 
 // Info encapsulates information about a server holding juju state and
@@ -113,6 +140,18 @@ type DialOpts struct {
 	// RetryDelay is the amount of time to wait between
 	// unsucssful connection attempts.
 	RetryDelay time.Duration
+
+	// Reconnect specifies whether the client should transparently
+	// reconnect when its connection fails. It tries all known API
+	// server addresses in turn, doubling the delay between rounds
+	// from RetryDelay until Timeout expires, and resubscribes any
+	// active watchers. A call interrupted by the failure is made
+	// again once reconnected.
+	Reconnect bool
+
+	// MaxRetryDelay limits the delay between rounds of reconnection
+	// attempts. If zero, a delay of one minute is used.
+	MaxRetryDelay time.Duration
 }
 
 // DefaultDialOpts returns a DialOpts representing the default
@@ -183,9 +222,11 @@ func Open(info *Info, opts DialOpts) (*State, error) {
 		serverRoot: "https://" + conn.Config().Location.Host,
 		// why are the contents of the tag (username and password) written into the
 		// state structure BEFORE login ?!?
-		tag:      toString(info.Tag),
-		password: info.Password,
-		certPool: pool,
+		tag:        toString(info.Tag),
+		password:   info.Password,
+		certPool:   pool,
+		info:       info,
+		watcherIds: make(map[string]string),
 	}
 	if info.Tag != nil || info.Password != "" {
 		if err := st.Login(info.Tag.String(), info.Password, info.Nonce); err != nil {
@@ -195,6 +236,9 @@ func Open(info *Info, opts DialOpts) (*State, error) {
 	}
 	st.broken = make(chan struct{})
 	st.closed = make(chan struct{})
+	// Only enable reconnection once logged in, so that
+	// a failed login is reported rather than retried.
+	st.opts = opts
 	go st.heartbeatMonitor()
 	return st, nil
 }
@@ -287,8 +331,21 @@ func (s *State) Ping() error {
 // This fills out the rpc.Request on the given facade, version for a given
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
-func (s *State) APICall(facade string, version int, id, method string, args, response interface{}) error {
-	err := s.client.Call(rpc.Request{
+func (s *State) APICall(facade string, version int, id, method string, args, response interface{}) (err error) {
+	if Tracing() {
+		defer traceAPICall(facade, version, id, method, time.Now(), &err)
+	}
+	if s.opts.Reconnect {
+		return s.reconnectingCall(facade, version, id, method, args, response)
+	}
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+	return callClient(client, facade, version, id, method, args, response)
+}
+
+func callClient(client *rpc.Conn, facade string, version int, id, method string, args, response interface{}) error {
+	err := client.Call(rpc.Request{
 		Type:    facade,
 		Version: version,
 		Id:      id,
@@ -298,12 +355,15 @@ func (s *State) APICall(facade string, version int, id, method string, args, res
 }
 
 func (s *State) Close() error {
-	err := s.client.Close()
 	select {
 	case <-s.closed:
 	default:
 		close(s.closed)
 	}
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+	err := client.Close()
 	<-s.broken
 	return err
 }
@@ -317,16 +377,22 @@ func (s *State) Broken() <-chan struct{} {
 // functions can tickle parts of the API that the conventional entry
 // points don't reach. This is exported for testing purposes only.
 func (s *State) RPCClient() *rpc.Conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.client
 }
 
 // Addr returns the address used to connect to the API server.
 func (s *State) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.addr
 }
 
 // EnvironTag returns the tag of the environment we are connected to.
 func (s *State) EnvironTag() (names.EnvironTag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return names.ParseEnvironTag(s.environTag)
 }
 
@@ -339,6 +405,8 @@ func (s *State) EnvironTag() (names.EnvironTag, error) {
 // be invoked both within and outside the environment (think
 // private clouds).
 func (s *State) APIHostPorts() [][]network.HostPort {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	hostPorts := make([][]network.HostPort, len(s.hostPorts))
 	for i, server := range s.hostPorts {
 		hostPorts[i] = append([]network.HostPort{}, server...)
@@ -348,6 +416,8 @@ func (s *State) APIHostPorts() [][]network.HostPort {
 
 // AllFacadeVersions returns what versions we know about for all facades
 func (s *State) AllFacadeVersions() map[string][]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	facades := make(map[string][]int, len(s.facadeVersions))
 	for name, versions := range s.facadeVersions {
 		facades[name] = append([]int{}, versions...)
@@ -362,5 +432,7 @@ func (s *State) AllFacadeVersions() map[string][]int {
 // Facade we will want to use. It needs to line up the versions that the server
 // reports to us, with the versions that our client knows how to use.
 func (s *State) BestFacadeVersion(facade string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return bestVersion(facadeVersions[facade], s.facadeVersions[facade])
 }
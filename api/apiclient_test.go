@@ -154,6 +154,63 @@ func (s *apiclientSuite) TestOpenPassesEnvironTag(c *gc.C) {
 	st.Close()
 }
 
+func (s *apiclientSuite) TestNoReconnectByDefault(c *gc.C) {
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{})
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	err = api.BreakConnection(st)
+	c.Assert(err, gc.IsNil)
+	err = st.Ping()
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *apiclientSuite) TestReconnect(c *gc.C) {
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{
+		Timeout:    coretesting.LongWait,
+		RetryDelay: coretesting.ShortWait,
+		Reconnect:  true,
+	})
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+	client := st.RPCClient()
+
+	err = api.BreakConnection(st)
+	c.Assert(err, gc.IsNil)
+	err = st.Ping()
+	c.Assert(err, gc.IsNil)
+	c.Assert(st.RPCClient(), gc.Not(gc.Equals), client)
+	select {
+	case <-st.Broken():
+		c.Fatalf("connection reported broken after reconnecting")
+	default:
+	}
+}
+
+func (s *apiclientSuite) TestReconnectResubscribesWatchers(c *gc.C) {
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{
+		Timeout:    coretesting.LongWait,
+		RetryDelay: coretesting.ShortWait,
+		Reconnect:  true,
+	})
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+	w, err := st.Client().WatchAll()
+	c.Assert(err, gc.IsNil)
+	defer w.Stop()
+	deltas, err := w.Next()
+	c.Assert(err, gc.IsNil)
+	c.Assert(deltas, gc.Not(gc.HasLen), 0)
+
+	// After reconnecting, the watcher is resubscribed and
+	// sends its initial deltas again.
+	err = api.BreakConnection(st)
+	c.Assert(err, gc.IsNil)
+	resent, err := w.Next()
+	c.Assert(err, gc.IsNil)
+	c.Assert(resent, gc.HasLen, len(deltas))
+}
+
 func (s *apiclientSuite) TestDialWebsocketStopped(c *gc.C) {
 	stopped := make(chan struct{})
 	f := api.NewWebsocketDialer(nil, api.DialOpts{})
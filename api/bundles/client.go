@@ -0,0 +1,53 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundles
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to bundle deployment.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Bundles client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Bundles")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Deploy starts a deployment of the bundle with the given bundle.yaml
+// contents. It returns the id of the deployment, which runs on the
+// state server, and the charms added to the environment for it.
+func (c *Client) Deploy(bundleYAML string) (string, []params.BundleChange, error) {
+	var result params.BundleDeployResult
+	args := params.BundleDeploy{YAML: bundleYAML}
+	if err := c.facade.FacadeCall("Deploy", args, &result); err != nil {
+		return "", nil, err
+	}
+	return result.Id, result.Changes, nil
+}
+
+// DeploymentStatus returns the status of the bundle deployment with
+// the given id.
+func (c *Client) DeploymentStatus(id string) (params.BundleDeploymentStatus, error) {
+	var result params.BundleDeploymentStatus
+	err := c.facade.FacadeCall("DeploymentStatus", params.BundleDeployment{Id: id}, &result)
+	return result, err
+}
+
+// WatchDeployment returns a watcher that notifies when the bundle
+// deployment with the given id makes a change or finishes.
+func (c *Client) WatchDeployment(id string) (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	err := c.facade.FacadeCall("WatchDeployment", params.BundleDeployment{Id: id}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return watcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
+}
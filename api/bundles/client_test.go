@@ -0,0 +1,69 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundles_test
+
+import (
+	"fmt"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	charmtesting "gopkg.in/juju/charm.v4/testing"
+
+	"github.com/juju/juju/api/bundles"
+	"github.com/juju/juju/apiserver/charms"
+	"github.com/juju/juju/apiserver/client"
+	jujutesting "github.com/juju/juju/juju/testing"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type bundlesSuite struct {
+	jujutesting.JujuConnSuite
+	client *bundles.Client
+}
+
+var _ = gc.Suite(&bundlesSuite{})
+
+func (s *bundlesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.client = bundles.NewClient(s.APIState)
+	store := charmtesting.NewMockCharmStore()
+	s.PatchValue(&charms.CharmStore, store)
+	s.PatchValue(&client.CharmStore, store)
+	bundle := charmtesting.Charms.CharmArchive(c.MkDir(), "wordpress")
+	curl := charm.MustParseURL(fmt.Sprintf("cs:precise/wordpress-%d", bundle.Revision()))
+	err := store.SetCharm(curl, bundle)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *bundlesSuite) TestDeploy(c *gc.C) {
+	id, changes, err := s.client.Deploy(`services: {wordpress: {charm: "cs:precise/wordpress", num_units: 1}}`)
+	c.Assert(err, gc.IsNil)
+	c.Assert(changes, gc.HasLen, 1)
+	c.Assert(changes[0].Kind, gc.Equals, "add-charm")
+
+	w, err := s.client.WatchDeployment(id)
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	wc.AssertOneChange()
+
+	status, err := s.client.DeploymentStatus(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(status.Status, gc.Equals, "pending")
+	c.Assert(status.Changes, gc.HasLen, 0)
+
+	deployment, err := s.State.BundleDeployment(id)
+	c.Assert(err, gc.IsNil)
+	err = deployment.Start()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	status, err = s.client.DeploymentStatus(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(status.Status, gc.Equals, "running")
+}
+
+func (s *bundlesSuite) TestDeployInvalidBundle(c *gc.C) {
+	_, _, err := s.client.Deploy("series: precise")
+	c.Assert(err, gc.ErrorMatches, "invalid bundle: bundle has no services")
+}
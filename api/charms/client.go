@@ -0,0 +1,48 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charms provides a client for the Charms API facade.
+package charms
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the Charms API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the Charms API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Charms")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Resolve resolves a charm store reference to a fully qualified charm
+// URL, including series and revision. If ref does not specify a
+// series, the given series is used when the charm is available for it;
+// otherwise the series preferred by the charm store is used.
+func (c *Client) Resolve(ref *charm.Reference, series string) (*charm.URL, error) {
+	args := params.ResolveCharmsWithSeries{
+		References: []charm.Reference{*ref},
+		Series:     series,
+	}
+	var results params.ResolveCharmResults
+	if err := c.facade.FacadeCall("Resolve", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.URLs) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.URLs))
+	}
+	result := results.URLs[0]
+	if result.Error != "" {
+		return nil, errors.New(result.Error)
+	}
+	return result.URL, nil
+}
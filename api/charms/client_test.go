@@ -0,0 +1,55 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charms_test
+
+import (
+	"fmt"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	charmtesting "gopkg.in/juju/charm.v4/testing"
+
+	"github.com/juju/juju/api/charms"
+	apiservercharms "github.com/juju/juju/apiserver/charms"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type charmsSuite struct {
+	jujutesting.JujuConnSuite
+
+	client *charms.Client
+	store  *charmtesting.MockCharmStore
+}
+
+var _ = gc.Suite(&charmsSuite{})
+
+func (s *charmsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.client = charms.NewClient(s.APIState)
+	c.Assert(s.client, gc.NotNil)
+	s.store = charmtesting.NewMockCharmStore()
+	s.PatchValue(&apiservercharms.CharmStore, s.store)
+
+	bundle := charmtesting.Charms.CharmArchive(c.MkDir(), "wordpress")
+	curl := charm.MustParseURL(fmt.Sprintf("cs:precise/wordpress-%d", bundle.Revision()))
+	err := s.store.SetCharm(curl, bundle)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *charmsSuite) TestResolve(c *gc.C) {
+	s.store.DefaultSeries = "precise"
+	ref, err := charm.ParseReference("cs:wordpress")
+	c.Assert(err, gc.IsNil)
+	curl, err := s.client.Resolve(ref, "trusty")
+	c.Assert(err, gc.IsNil)
+	c.Assert(curl.String(), gc.Equals, "cs:precise/wordpress-3")
+}
+
+func (s *charmsSuite) TestResolveError(c *gc.C) {
+	ref, err := charm.ParseReference("local:precise/wordpress")
+	c.Assert(err, gc.IsNil)
+	curl, err := s.client.Resolve(ref, "")
+	c.Assert(err, gc.ErrorMatches, "only charm store charm references are supported, with cs: schema")
+	c.Assert(curl, gc.IsNil)
+}
@@ -0,0 +1,47 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleanups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the pending state cleanups.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Cleanups client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Cleanups")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// List returns all pending cleanups, oldest first.
+func (c *Client) List() ([]params.CleanupInfo, error) {
+	var result params.CleanupsResult
+	if err := c.facade.FacadeCall("List", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Cleanups, nil
+}
+
+// Run runs all pending cleanups immediately.
+func (c *Client) Run() error {
+	return c.facade.FacadeCall("Run", nil, nil)
+}
+
+// Cancel removes the cleanup with the given id without running it.
+func (c *Client) Cancel(id string) error {
+	var results params.ErrorResults
+	args := params.CleanupIds{Ids: []string{id}}
+	if err := c.facade.FacadeCall("Cancel", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -0,0 +1,47 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleanups_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/cleanups"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type cleanupsSuite struct {
+	jujutesting.JujuConnSuite
+	client *cleanups.Client
+}
+
+var _ = gc.Suite(&cleanupsSuite{})
+
+func (s *cleanupsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.client = cleanups.NewClient(s.APIState)
+}
+
+func (s *cleanupsSuite) TestListRunCancel(c *gc.C) {
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = svc.Destroy()
+	c.Assert(err, gc.IsNil)
+
+	infos, err := s.client.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(infos, gc.HasLen, 1)
+	c.Assert(infos[0].Kind, gc.Equals, "units")
+
+	err = s.client.Cancel(infos[0].Id)
+	c.Assert(err, gc.IsNil)
+	err = s.client.Cancel(infos[0].Id)
+	c.Assert(err, gc.ErrorMatches, `cleanup ".*" not found`)
+
+	err = s.client.Run()
+	c.Assert(err, gc.IsNil)
+	infos, err = s.client.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(infos, gc.HasLen, 0)
+}
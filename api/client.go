@@ -54,6 +54,9 @@ type AgentStatus struct {
 	Version string
 	Life    string
 	Err     error
+
+	// Since holds the time the status last changed, if known.
+	Since *time.Time
 }
 
 // MachineStatus holds status info about a machine.
@@ -77,9 +80,14 @@ type MachineStatus struct {
 	Id            string
 	Containers    map[string]MachineStatus
 	Hardware      string
+	Constraints   string
 	Jobs          []params.MachineJob
 	HasVote       bool
 	WantsVote     bool
+
+	// HardwareUtilization summarises the resource usage last
+	// reported by the machine agent.
+	HardwareUtilization string
 }
 
 // ServiceStatus holds status info about a service.
@@ -90,9 +98,28 @@ type ServiceStatus struct {
 	Life          string
 	Relations     map[string][]string
 	Networks      NetworksSpecification
+	Constraints   string
 	CanUpgradeTo  string
+	CharmPinned   bool
 	SubordinateTo []string
 	Units         map[string]UnitStatus
+
+	// Status summarizes the statuses of the service's units, so that
+	// the health of the service can be seen at a glance.
+	Status AggregateStatus
+}
+
+// AggregateStatus summarizes the statuses of a group of units.
+type AggregateStatus struct {
+	// Status holds the worst status of any of the units.
+	Status params.Status
+
+	// Info describes how many units have that status, for example
+	// "2 units in error".
+	Info string
+
+	// Counts holds the number of units with each status.
+	Counts map[params.Status]int
 }
 
 // UnitStatus holds status info about a unit.
@@ -161,6 +188,18 @@ func (c *Client) Status(patterns []string) (*Status, error) {
 	return &result, nil
 }
 
+// StatusNoStore returns the status of the juju environment like
+// Status, but without the newer charm revisions available from the
+// charm store.
+func (c *Client) StatusNoStore(patterns []string) (*Status, error) {
+	var result Status
+	p := params.StatusParams{Patterns: patterns, NoStore: true}
+	if err := c.facade.FacadeCall("FullStatus", p, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // LegacyMachineStatus holds just the instance-id of a machine.
 type LegacyMachineStatus struct {
 	InstanceId string // Not type instance.Id just to match original api.
@@ -201,6 +240,24 @@ func (c *Client) ServiceUnset(service string, options []string) error {
 	return c.facade.FacadeCall("ServiceUnset", p, nil)
 }
 
+// ResolveUnits clears the errors of the units given by name or glob
+// pattern (e.g. "wordpress/*"). Units matched by pattern are only
+// resolved if they are in an error state.
+func (c *Client) ResolveUnits(retry bool, patterns ...string) ([]params.ResolveUnitResult, error) {
+	return c.resolveUnits(params.ResolveUnits{Patterns: patterns, Retry: retry})
+}
+
+// ResolveAllUnits clears the errors of every unit in an error state.
+func (c *Client) ResolveAllUnits(retry bool) ([]params.ResolveUnitResult, error) {
+	return c.resolveUnits(params.ResolveUnits{All: true, Retry: retry})
+}
+
+func (c *Client) resolveUnits(args params.ResolveUnits) ([]params.ResolveUnitResult, error) {
+	var results params.ResolveUnitsResults
+	err := c.facade.FacadeCall("ResolveUnits", args, &results)
+	return results.Results, err
+}
+
 // Resolved clears errors on a unit.
 func (c *Client) Resolved(unit string, retry bool) error {
 	p := params.Resolved{
@@ -210,6 +267,35 @@ func (c *Client) Resolved(unit string, retry bool) error {
 	return c.facade.FacadeCall("Resolved", p, nil)
 }
 
+// AddUnitGroup saves a named group of units, defined by unit name
+// patterns.
+func (c *Client) AddUnitGroup(name string, patterns ...string) error {
+	p := params.UnitGroup{Name: name, Patterns: patterns}
+	return c.facade.FacadeCall("AddUnitGroup", p, nil)
+}
+
+// RemoveUnitGroup removes the named unit group.
+func (c *Client) RemoveUnitGroup(name string) error {
+	p := params.UnitGroupName{Name: name}
+	return c.facade.FacadeCall("RemoveUnitGroup", p, nil)
+}
+
+// UnitGroups returns all the unit groups in the environment.
+func (c *Client) UnitGroups() ([]params.UnitGroup, error) {
+	var result params.UnitGroups
+	err := c.facade.FacadeCall("UnitGroups", nil, &result)
+	return result.Groups, err
+}
+
+// UnitGroupUnits returns the names of the units currently in the named
+// unit group.
+func (c *Client) UnitGroupUnits(name string) ([]string, error) {
+	var result params.UnitGroupUnitsResult
+	p := params.UnitGroupName{Name: name}
+	err := c.facade.FacadeCall("UnitGroupUnits", p, &result)
+	return result.Units, err
+}
+
 // RetryProvisioning updates the provisioning status of a machine allowing the
 // provisioner to retry.
 func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error) {
@@ -241,6 +327,25 @@ func (c *Client) PrivateAddress(target string) (string, error) {
 	return results.PrivateAddress, err
 }
 
+// DebugHooksTranscript returns the transcript of the most recent
+// debug-hooks session on the given unit, and the time it was last
+// updated.
+func (c *Client) DebugHooksTranscript(unitName string) (string, time.Time, error) {
+	var results params.DebugHooksTranscriptResults
+	p := params.DebugHooksTranscript{UnitName: unitName}
+	err := c.facade.FacadeCall("DebugHooksTranscript", p, &results)
+	return results.Transcript, results.Updated, err
+}
+
+// UnitStatusHistory returns up to size of the most recent status
+// transitions of the given unit, newest first.
+func (c *Client) UnitStatusHistory(unitName string, size int) ([]params.StatusHistoryEntry, error) {
+	var results params.StatusHistoryResults
+	p := params.StatusHistory{UnitName: unitName, Size: size}
+	err := c.facade.FacadeCall("UnitStatusHistory", p, &results)
+	return results.Statuses, err
+}
+
 // ServiceSetYAML sets configuration options on a service
 // given options in YAML format.
 func (c *Client) ServiceSetYAML(service string, yaml string) error {
@@ -267,6 +372,21 @@ func (c *Client) AddRelation(endpoints ...string) (*params.AddRelationResults, e
 	return &addRelRes, err
 }
 
+// AddRelationVia adds a relation between the specified endpoints whose
+// traffic should use the named network, if it is not empty. If the
+// endpoints could refer to more than one relation, no relation is added
+// and the candidate relations are returned in the results.
+func (c *Client) AddRelationVia(network string, endpoints ...string) (*params.AddRelationResults, error) {
+	var addRelRes params.AddRelationResults
+	params := params.AddRelation{
+		Endpoints:        endpoints,
+		Network:          network,
+		ReportCandidates: true,
+	}
+	err := c.facade.FacadeCall("AddRelation", params, &addRelRes)
+	return &addRelRes, err
+}
+
 // DestroyRelation removes the relation between the specified endpoints.
 func (c *Client) DestroyRelation(endpoints ...string) error {
 	params := params.DestroyRelation{Endpoints: endpoints}
@@ -342,6 +462,13 @@ func (c *Client) ServiceUnexpose(service string) error {
 	return c.facade.FacadeCall("ServiceUnexpose", params, nil)
 }
 
+// ServiceUnexposePorts changes the juju-managed firewall to close the
+// given ports of an exposed service, leaving its other ports exposed.
+func (c *Client) ServiceUnexposePorts(service string, ports []network.PortRange) error {
+	params := params.ServiceUnexposePorts{ServiceName: service, Ports: ports}
+	return c.facade.FacadeCall("ServiceUnexposePorts", params, nil)
+}
+
 // ServiceDeployWithNetworks works exactly like ServiceDeploy, but
 // allows the specification of requested networks that must be present
 // on the machines where the service is deployed. Another way to specify
@@ -380,6 +507,12 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	return c.facade.FacadeCall("ServiceUpdate", args, nil)
 }
 
+// SetUnitChaos replaces the faults that a unit's agent injects into
+// its hook execution.
+func (c *Client) SetUnitChaos(args params.SetUnitChaos) error {
+	return c.facade.FacadeCall("SetUnitChaos", args, nil)
+}
+
 // ServiceSetCharm sets the charm for a given service.
 func (c *Client) ServiceSetCharm(serviceName string, charmUrl string, force bool) error {
 	args := params.ServiceSetCharm{
@@ -420,6 +553,87 @@ func (c *Client) DestroyServiceUnits(unitNames ...string) error {
 	return c.facade.FacadeCall("DestroyServiceUnits", params, nil)
 }
 
+// DestroyUnits destroys the units given by name or glob pattern
+// (e.g. "wordpress/*"), and returns the names of the destroyed units.
+func (c *Client) DestroyUnits(patterns ...string) ([]string, error) {
+	return c.destroyUnits(params.DestroyUnits{Patterns: patterns})
+}
+
+// DestroyServiceUnitCount destroys the given number of the service's
+// highest-numbered units, and returns their names.
+func (c *Client) DestroyServiceUnitCount(service string, numUnits int) ([]string, error) {
+	return c.destroyUnits(params.DestroyUnits{
+		ServiceName: service,
+		NumUnits:    numUnits,
+	})
+}
+
+func (c *Client) destroyUnits(args params.DestroyUnits) ([]string, error) {
+	var result params.DestroyUnitsResults
+	err := c.facade.FacadeCall("DestroyUnits", args, &result)
+	return result.UnitNames, err
+}
+
+// ServiceSetUnitCount adds or destroys units of the service so that it
+// has exactly count units, and returns the names of the units added
+// and destroyed.
+func (c *Client) ServiceSetUnitCount(service string, count int) (added, destroyed []string, err error) {
+	args := params.ServiceSetUnitCount{
+		ServiceName: service,
+		Count:       count,
+	}
+	var result params.ServiceSetUnitCountResults
+	err = c.facade.FacadeCall("ServiceSetUnitCount", args, &result)
+	return result.Added, result.Destroyed, err
+}
+
+// ExportBundle returns the environment's services and relations
+// rendered as a deployable bundle.yaml.
+func (c *Client) ExportBundle() (string, error) {
+	var result params.ExportBundleResult
+	err := c.facade.FacadeCall("ExportBundle", nil, &result)
+	return result.YAML, err
+}
+
+// AddNetwork adds a provider network with the given name to the
+// environment, so that services can be deployed with bindings to it.
+func (c *Client) AddNetwork(name string, providerId network.Id, cidr string, vlanTag int) error {
+	args := params.AddNetworks{
+		Networks: []params.Network{{
+			Tag:        names.NewNetworkTag(name).String(),
+			ProviderId: providerId,
+			CIDR:       cidr,
+			VLANTag:    vlanTag,
+		}},
+	}
+	var result params.ErrorResults
+	if err := c.facade.FacadeCall("AddNetworks", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// ListNetworks returns all the networks known in the environment.
+func (c *Client) ListNetworks() ([]params.Network, error) {
+	var result params.NetworksResult
+	err := c.facade.FacadeCall("ListNetworks", nil, &result)
+	return result.Networks, err
+}
+
+// MachinePorts returns the port ranges opened on each of the given
+// machines, or on every machine in the environment if none are given.
+func (c *Client) MachinePorts(machines ...names.MachineTag) ([]params.MachineOpenedPorts, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(machines)),
+	}
+	for i, tag := range machines {
+		args.Entities[i].Tag = tag.String()
+	}
+	var result params.MachineOpenedPortsResults
+	err := c.facade.FacadeCall("MachinePorts", args, &result)
+	return result.Machines, err
+}
+
 // ServiceDestroy destroys a given service.
 func (c *Client) ServiceDestroy(service string) error {
 	params := params.ServiceDestroy{
@@ -459,6 +673,40 @@ func (c *Client) SetEnvironmentConstraints(constraints constraints.Value) error
 	return c.facade.FacadeCall("SetEnvironmentConstraints", params, nil)
 }
 
+// GetConstraints returns the constraints set on the environment,
+// service or machine with the given tag, and the constraints that
+// take effect for it once merged with the environment constraints.
+func (c *Client) GetConstraints(tag string) (cons, effective constraints.Value, err error) {
+	var results params.EntityConstraintsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag}},
+	}
+	if err := c.facade.FacadeCall("GetConstraints", args, &results); err != nil {
+		return cons, effective, err
+	}
+	if len(results.Results) != 1 {
+		return cons, effective, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return cons, effective, result.Error
+	}
+	return result.Constraints, result.Effective, nil
+}
+
+// SetConstraints replaces the constraints set on the environment,
+// service or machine with the given tag.
+func (c *Client) SetConstraints(tag string, cons constraints.Value) error {
+	var results params.ErrorResults
+	args := params.SetEntityConstraints{
+		Entities: []params.EntityConstraints{{Tag: tag, Constraints: cons}},
+	}
+	if err := c.facade.FacadeCall("SetConstraints", args, &results); err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
 // CharmInfo holds information about a charm.
 type CharmInfo struct {
 	Revision int
@@ -543,6 +791,24 @@ func (c *Client) UnshareEnvironment(users []names.UserTag) error {
 	return result.Combine()
 }
 
+// GrantEnvironment gives the given user the specified access ("read"
+// or "write") to the environment, sharing it with them if necessary.
+func (c *Client) GrantEnvironment(user names.UserTag, access string) error {
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.String(),
+			Action:  params.GrantEnvUser,
+			Access:  access,
+		}},
+	}
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ShareEnvironment", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
 // WatchAll holds the id of the newly-created AllWatcher.
 type WatchAll struct {
 	AllWatcherId string
@@ -558,6 +824,16 @@ func (c *Client) WatchAll() (*AllWatcher, error) {
 	return newAllWatcher(c.st, &info.AllWatcherId), nil
 }
 
+// WatchAllFiltered returns an AllWatcher that only reports deltas for
+// the entity kinds and ids selected by the given filter.
+func (c *Client) WatchAllFiltered(filter params.AllWatcherFilter) (*AllWatcher, error) {
+	info := new(WatchAll)
+	if err := c.facade.FacadeCall("WatchAllFiltered", filter, info); err != nil {
+		return nil, err
+	}
+	return newAllWatcher(c.st, &info.AllWatcherId), nil
+}
+
 // GetAnnotations returns annotations that have been set on the given entity.
 func (c *Client) GetAnnotations(tag string) (map[string]string, error) {
 	args := params.GetAnnotations{tag}
@@ -589,6 +865,15 @@ func (c *Client) EnvironmentGet() (map[string]interface{}, error) {
 	return result.Config, err
 }
 
+// EnvironmentSchema returns a description of every configuration
+// attribute known to the environment, including those specific to its
+// provider.
+func (c *Client) EnvironmentSchema() (map[string]params.EnvironConfigAttribute, error) {
+	result := params.EnvironmentSchemaResults{}
+	err := c.facade.FacadeCall("EnvironmentSchema", nil, &result)
+	return result.Attributes, err
+}
+
 // EnvironmentSet sets the given key-value pairs in the environment.
 func (c *Client) EnvironmentSet(config map[string]interface{}) error {
 	args := params.EnvironmentSet{Config: config}
@@ -608,6 +893,22 @@ func (c *Client) SetEnvironAgentVersion(version version.Number) error {
 	return c.facade.FacadeCall("SetEnvironAgentVersion", args, nil)
 }
 
+// SetAgentVersionPin holds the agents of the machine or service with
+// the given tag at no later than the given version. A zero version
+// clears the pin.
+func (c *Client) SetAgentVersionPin(tag names.Tag, version version.Number) error {
+	args := params.SetAgentVersionPin{Tag: tag.String(), Version: version}
+	return c.facade.FacadeCall("SetAgentVersionPin", args, nil)
+}
+
+// SetServiceCharmPin pins the service to its current charm, so that
+// newer revisions are not reported by status and the charm is only
+// changed when forced. Pinned false removes the pin.
+func (c *Client) SetServiceCharmPin(service string, pinned bool) error {
+	args := params.SetServiceCharmPin{ServiceName: service, Pinned: pinned}
+	return c.facade.FacadeCall("SetServiceCharmPin", args, nil)
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
@@ -651,7 +952,15 @@ func (c *Client) Run(run params.RunParams) ([]params.RunResult, error) {
 // will fail if there are any manually-provisioned non-manager machines
 // in state.
 func (c *Client) DestroyEnvironment() error {
-	return c.facade.FacadeCall("DestroyEnvironment", nil, nil)
+	return c.DestroyEnvironmentWithToken("")
+}
+
+// DestroyEnvironmentWithToken is like DestroyEnvironment, but also
+// passes a confirmation token, which must hold the environment's UUID
+// if the environment is destroy-protected.
+func (c *Client) DestroyEnvironmentWithToken(confirmationToken string) error {
+	args := params.DestroyEnvironment{ConfirmationToken: confirmationToken}
+	return c.facade.FacadeCall("DestroyEnvironment", args, nil)
 }
 
 // AddLocalCharm prepares the given charm with a local: schema in its
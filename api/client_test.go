@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"code.google.com/p/go.net/websocket"
 	"github.com/juju/errors"
@@ -203,6 +204,20 @@ func (s *clientSuite) TestUnshareEnvironmentRealAPIServer(c *gc.C) {
 	c.Assert(errors.IsNotFound(err), jc.IsTrue)
 }
 
+func (s *clientSuite) TestGrantEnvironmentRealAPIServer(c *gc.C) {
+	client := s.APIState.Client()
+	user := names.NewUserTag("foo@ubuntuone")
+	err := client.GrantEnvironment(user, "read")
+	c.Assert(err, gc.IsNil)
+
+	envUser, err := s.State.EnvironmentUser(user)
+	c.Assert(err, gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+
+	err = client.GrantEnvironment(user, "admin")
+	c.Assert(err, gc.ErrorMatches, `could not grant environment access: environment access "admin" not valid`)
+}
+
 func (s *clientSuite) TestWatchDebugLogConnected(c *gc.C) {
 	// Shows both the unmarshalling of a real error, and
 	// that the api server is connected.
@@ -386,6 +401,51 @@ func (s *clientSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	c.Assert(err, gc.Equals, someErr) // Confirms that the correct facade was called
 }
 
+func (s *clientSuite) TestWaitFor(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+
+	var events []api.WaitEvent
+	err = s.APIState.Client().WaitFor([]string{"wordpress"}, 0, func(event api.WaitEvent) {
+		events = append(events, event)
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(events, gc.DeepEquals, []api.WaitEvent{{
+		Kind:   "unit",
+		Id:     "wordpress/0",
+		Status: params.StatusStarted,
+		Ready:  true,
+	}})
+}
+
+func (s *clientSuite) TestWaitForTimeout(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+
+	err = s.APIState.Client().WaitFor([]string{"wordpress/0"}, 50*time.Millisecond, nil)
+	c.Assert(err, gc.Equals, api.ErrWaitTimeout)
+}
+
+func (s *clientSuite) TestWaitForError(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(state.StatusError, "hook failed: \"install\"", nil)
+	c.Assert(err, gc.IsNil)
+
+	err = s.APIState.Client().WaitFor(nil, 0, nil)
+	c.Assert(err, gc.ErrorMatches, `unit wordpress/0 is in error: hook failed: "install"`)
+}
+
+func (s *clientSuite) TestWaitForUnknownEntity(c *gc.C) {
+	err := s.APIState.Client().WaitFor([]string{"mysql"}, 0, nil)
+	c.Assert(err, gc.ErrorMatches, `no machines, services or units match "mysql"`)
+}
+
 // badReader raises err when Read is called.
 type badReader struct {
 	err error
@@ -0,0 +1,118 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package crossenvrelations provides the client side of the API used
+// to offer services to other environments, and to establish relations
+// to those offers.
+package crossenvrelations
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides methods for offering services to other environments
+// and for consuming those offers.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new Client based on an existing authenticated
+// API connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "CrossEnvRelations")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Offer makes the named endpoints of a service available to other
+// environments under the given URL.
+func (c *Client) Offer(url, serviceName string, endpoints []string, description string) error {
+	args := params.ServiceOffers{
+		Offers: []params.ServiceOffer{{
+			URL:         url,
+			ServiceName: serviceName,
+			Endpoints:   endpoints,
+			Description: description,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("Offer", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListOffers returns all the service offers made by the environment.
+func (c *Client) ListOffers() ([]params.ServiceOffer, error) {
+	var result params.ServiceOffers
+	if err := c.facade.FacadeCall("ListOffers", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Offers, nil
+}
+
+// Withdraw removes the service offer with the given URL.
+func (c *Client) Withdraw(url string) error {
+	args := params.ServiceOfferURLs{URLs: []string{url}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("Withdraw", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RegisterRemoteRelation records that a service in another environment
+// is consuming the offer with the given URL, and returns the details
+// the consuming environment needs to relate to the offered service.
+func (c *Client) RegisterRemoteRelation(rel params.RemoteRelation) (params.RemoteRelationResult, error) {
+	args := params.RemoteRelations{Relations: []params.RemoteRelation{rel}}
+	var results params.RemoteRelationResults
+	if err := c.facade.FacadeCall("RegisterRemoteRelations", args, &results); err != nil {
+		return params.RemoteRelationResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.RemoteRelationResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.RemoteRelationResult{}, result.Error
+	}
+	return result, nil
+}
+
+// UnregisterRemoteRelation records that a service in another
+// environment no longer consumes the offer with the given URL.
+func (c *Client) UnregisterRemoteRelation(rel params.RemoteRelation) error {
+	args := params.RemoteRelations{Relations: []params.RemoteRelation{rel}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("UnregisterRemoteRelations", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// EnterRemoteScope records that a unit of a consuming service has
+// joined its remote relation, and publishes the unit's relation
+// settings to the offering environment.
+func (c *Client) EnterRemoteScope(unit params.RemoteRelationUnit) error {
+	args := params.RemoteRelationUnits{Units: []params.RemoteRelationUnit{unit}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("EnterRemoteScope", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// LeaveRemoteScope records that a unit of a consuming service has
+// departed its remote relation.
+func (c *Client) LeaveRemoteScope(unit params.RemoteRelationUnit) error {
+	args := params.RemoteRelationUnits{Units: []params.RemoteRelationUnit{unit}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("LeaveRemoteScope", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -0,0 +1,82 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossenvrelations_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/crossenvrelations"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/juju/osenv"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type crossEnvRelationsSuite struct {
+	jujutesting.JujuConnSuite
+
+	client *crossenvrelations.Client
+}
+
+var _ = gc.Suite(&crossEnvRelationsSuite{})
+
+func (s *crossEnvRelationsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, feature.CrossEnvRelations)
+	s.client = crossenvrelations.NewClient(s.APIState)
+	c.Assert(s.client, gc.NotNil)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *crossEnvRelationsSuite) TestOfferAndConsume(c *gc.C) {
+	err := s.client.Offer("local:/u/admin/blog", "wordpress", []string{"url"}, "a shared blog")
+	c.Assert(err, gc.IsNil)
+	offers, err := s.client.ListOffers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(offers, jc.DeepEquals, []params.ServiceOffer{{
+		URL:         "local:/u/admin/blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+		Description: "a shared blog",
+	}})
+
+	result, err := s.client.RegisterRemoteRelation(params.RemoteRelation{
+		OfferURL:         "local:/u/admin/blog",
+		ConsumerEnvUUID:  "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService:  "haproxy",
+		IngressAddresses: []string{"10.0.0.1"},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.ServiceName, gc.Equals, "wordpress")
+	c.Assert(result.Endpoints, jc.DeepEquals, []params.RemoteEndpoint{
+		{Name: "url", Interface: "http", Role: "provider"},
+	})
+
+	unit := params.RemoteRelationUnit{
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "haproxy",
+		Unit:            "haproxy/0",
+		Settings:        params.RelationSettings{"private-address": "10.0.0.1"},
+	}
+	err = s.client.EnterRemoteScope(unit)
+	c.Assert(err, gc.IsNil)
+	err = s.client.LeaveRemoteScope(unit)
+	c.Assert(err, gc.IsNil)
+	rel := params.RemoteRelation{
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "haproxy",
+	}
+	err = s.client.UnregisterRemoteRelation(rel)
+	c.Assert(err, gc.IsNil)
+	err = s.client.EnterRemoteScope(unit)
+	c.Assert(err, gc.ErrorMatches, `cannot enter scope of remote relation to "local:/u/admin/blog" for unit "haproxy/0": remote relation not found`)
+
+	err = s.client.Withdraw("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	err = s.client.Withdraw("local:/u/admin/blog")
+	c.Assert(err, gc.ErrorMatches, `cannot remove service offer "local:/u/admin/blog": service offer "local:/u/admin/blog" not found`)
+}
@@ -0,0 +1,85 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package environmentmanager provides the client side of the API used
+// to create and list the environments hosted by a state server.
+package environmentmanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides methods that the Juju client command uses to manage
+// the environments hosted by a state server.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new Client based on an existing authenticated
+// API connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "EnvironmentManager")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// CreateEnvironment creates a new environment owned by the given user.
+// The given configuration attributes, which must include the
+// environment's name, override those of the environment the client is
+// connected to.
+func (c *Client) CreateEnvironment(owner names.UserTag, config map[string]interface{}) (params.Environment, error) {
+	var result params.Environment
+	args := params.EnvironmentCreateArgs{
+		OwnerTag: owner.String(),
+		Config:   config,
+	}
+	if err := c.facade.FacadeCall("CreateEnvironment", args, &result); err != nil {
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// ListEnvironments returns the environments that the given user has
+// access to.
+func (c *Client) ListEnvironments(user names.UserTag) ([]params.Environment, error) {
+	var result params.EnvironmentList
+	args := params.Entity{Tag: user.String()}
+	if err := c.facade.FacadeCall("ListEnvironments", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Environments, nil
+}
+
+// ExportEnvironment returns a description of the environment the
+// client is connected to, in the format defined by the migration
+// package.
+func (c *Client) ExportEnvironment() ([]byte, error) {
+	var result params.SerializedEnvironment
+	if err := c.facade.FacadeCall("ExportEnvironment", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Bytes, nil
+}
+
+// ImportEnvironment recreates the described environment in the state
+// server the client is connected to. The result holds the details to
+// pass to SetMigrationTarget on the original environment.
+func (c *Client) ImportEnvironment(description []byte) (params.ImportedEnvironment, error) {
+	var result params.ImportedEnvironment
+	args := params.SerializedEnvironment{Bytes: description}
+	if err := c.facade.FacadeCall("ImportEnvironment", args, &result); err != nil {
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// SetMigrationTarget records that the environment the client is
+// connected to has been imported into another state server, so that
+// the environment's agents reconnect to it.
+func (c *Client) SetMigrationTarget(target params.MigrationTarget) error {
+	return c.facade.FacadeCall("SetMigrationTarget", target, nil)
+}
@@ -0,0 +1,77 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environmentmanager_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/environmentmanager"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/juju/osenv"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type environmentManagerSuite struct {
+	jujutesting.JujuConnSuite
+
+	client *environmentmanager.Client
+}
+
+var _ = gc.Suite(&environmentManagerSuite{})
+
+func (s *environmentManagerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, feature.JES)
+	s.client = environmentmanager.NewClient(s.APIState)
+	c.Assert(s.client, gc.NotNil)
+}
+
+func (s *environmentManagerSuite) TestCreateAndListEnvironments(c *gc.C) {
+	owner := s.AdminUserTag(c)
+	env, err := s.client.CreateEnvironment(owner, map[string]interface{}{"name": "hosted"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(env.Name, gc.Equals, "hosted")
+	c.Assert(env.OwnerTag, gc.Equals, owner.String())
+
+	envs, err := s.client.ListEnvironments(owner)
+	c.Assert(err, gc.IsNil)
+	c.Assert(envs, gc.HasLen, 2)
+	var names []string
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	c.Assert(names, jc.SameContents, []string{"dummyenv", "hosted"})
+}
+
+func (s *environmentManagerSuite) TestLoginToHostedEnvironment(c *gc.C) {
+	env, err := s.client.CreateEnvironment(s.AdminUserTag(c), map[string]interface{}{"name": "hosted"})
+	c.Assert(err, gc.IsNil)
+
+	info := s.APIInfo(c)
+	info.EnvironTag = names.NewEnvironTag(env.UUID)
+	st, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	envTag, err := st.EnvironTag()
+	c.Assert(err, gc.IsNil)
+	c.Assert(envTag.Id(), gc.Equals, env.UUID)
+	envConfig, err := st.Client().EnvironmentGet()
+	c.Assert(err, gc.IsNil)
+	c.Assert(envConfig["name"], gc.Equals, "hosted")
+}
+
+func (s *environmentManagerSuite) TestLoginToHostedEnvironmentRequiresFeatureFlag(c *gc.C) {
+	env, err := s.client.CreateEnvironment(s.AdminUserTag(c), map[string]interface{}{"name": "hosted"})
+	c.Assert(err, gc.IsNil)
+
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, "")
+	info := s.APIInfo(c)
+	info.EnvironTag = names.NewEnvironTag(env.UUID)
+	_, err = api.Open(info, api.DialOpts{})
+	c.Assert(err, gc.ErrorMatches, `unknown environment: "`+env.UUID+`"`)
+}
@@ -23,6 +23,14 @@ func SetServerRoot(c *Client, root string) {
 	c.st.serverRoot = root
 }
 
+// BreakConnection closes the underlying websocket connection
+// of the given State, as if the network had failed.
+func BreakConnection(st *State) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.conn.Close()
+}
+
 // PatchEnvironTag patches the value of the environment tag.
 // It returns a function that reverts the change.
 func PatchEnvironTag(st *State, envTag string) func() {
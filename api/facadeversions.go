@@ -12,24 +12,32 @@ var facadeVersions = map[string]int{
 	"Agent":                1,
 	"AllWatcher":           0,
 	"Backups":              0,
+	"Bundles":              0,
+	"Cleanups":             0,
+	"CrossEnvRelations":    0,
 	"Deployer":             0,
 	"KeyUpdater":           0,
 	"HighAvailability":     1,
 	"Machiner":             0,
+	"Maintenance":          0,
 	"Networker":            0,
 	"StringsWatcher":       0,
 	"Environment":          0,
+	"EnvironmentManager":   0,
 	"KeyManager":           0,
 	"Logger":               0,
 	"MetricsManager":       0,
 	"Pinger":               0,
+	"PortForwarder":        0,
 	"Provisioner":          0,
 	"Reboot":               1,
 	"RelationUnitsWatcher": 0,
 	"UserManager":          0,
 	"CharmRevisionUpdater": 0,
+	"Charms":               0,
 	"Client":               0,
 	"NotifyWatcher":        0,
+	"NotifyWatcherMux":     0,
 	"Upgrader":             0,
 	"Firewaller":           1,
 	"Rsyslog":              0,
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 )
 
 const firewallerFacade = "Firewaller"
@@ -114,3 +115,32 @@ func (st *State) WatchOpenedPorts() (watcher.StringsWatcher, error) {
 	w := watcher.NewStringsWatcher(st.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// MachinePorts returns a map of network.PortRange to unit tag for all
+// port ranges opened on the given machine, across all its networks.
+func (st *State) MachinePorts(tag names.MachineTag) (map[network.PortRange]names.UnitTag, error) {
+	var results params.MachinePortsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	err := st.facade.FacadeCall("MachinePorts", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	ports := make(map[network.PortRange]names.UnitTag)
+	for _, portRange := range result.Ports {
+		unitTag, err := names.ParseUnitTag(portRange.UnitTag)
+		if err != nil {
+			return nil, err
+		}
+		ports[portRange.PortRange] = unitTag
+	}
+	return ports, nil
+}
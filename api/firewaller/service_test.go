@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/api/firewaller"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 	statetesting "github.com/juju/juju/state/testing"
 )
 
@@ -94,3 +95,18 @@ func (s *serviceSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *serviceSuite) TestUnexposedPorts(c *gc.C) {
+	ports, err := s.apiService.UnexposedPorts()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ports, gc.HasLen, 0)
+
+	err = s.service.SetExposed()
+	c.Assert(err, gc.IsNil)
+	err = s.service.UnexposePorts([]network.PortRange{{53, 53, "udp"}})
+	c.Assert(err, gc.IsNil)
+
+	ports, err = s.apiService.UnexposedPorts()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ports, jc.DeepEquals, []network.PortRange{{53, 53, "udp"}})
+}
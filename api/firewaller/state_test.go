@@ -4,10 +4,13 @@
 package firewaller_test
 
 import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
@@ -105,3 +108,24 @@ func (s *stateSuite) TestWatchOpenedPorts(c *gc.C) {
 	statetesting.AssertStop(c, w)
 	wc.AssertClosed()
 }
+
+func (s *stateSuite) TestMachinePorts(c *gc.C) {
+	machineTag := s.machines[0].Tag().(names.MachineTag)
+
+	// No ports opened at first.
+	ports, err := s.firewaller.MachinePorts(machineTag)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ports, gc.HasLen, 0)
+
+	err = s.units[0].OpenPorts("tcp", 1234, 1400)
+	c.Assert(err, gc.IsNil)
+	err = s.units[0].OpenPort("udp", 4321)
+	c.Assert(err, gc.IsNil)
+	ports, err = s.firewaller.MachinePorts(machineTag)
+	c.Assert(err, gc.IsNil)
+	unitTag := s.units[0].Tag().(names.UnitTag)
+	c.Assert(ports, jc.DeepEquals, map[network.PortRange]names.UnitTag{
+		network.PortRange{FromPort: 1234, ToPort: 1400, Protocol: "tcp"}: unitTag,
+		network.PortRange{FromPort: 4321, ToPort: 4321, Protocol: "udp"}: unitTag,
+	})
+}
@@ -87,3 +87,23 @@ func (m *Machine) EnsureDead() error {
 func (m *Machine) Watch() (watcher.NotifyWatcher, error) {
 	return common.Watch(m.st.facade, m.tag)
 }
+
+// SetUtilization records the resource usage of the machine's host.
+// Memory and disk sizes are in megabytes.
+func (m *Machine) SetUtilization(loadAverage float64, memoryUsed, memoryTotal, diskFree uint64) error {
+	var result params.ErrorResults
+	args := params.SetMachinesUtilization{
+		MachineUtilization: []params.MachineUtilization{{
+			Tag:         m.tag.String(),
+			LoadAverage: loadAverage,
+			MemoryUsed:  memoryUsed,
+			MemoryTotal: memoryTotal,
+			DiskFree:    diskFree,
+		}},
+	}
+	err := m.st.facade.FacadeCall("SetMachineUtilization", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
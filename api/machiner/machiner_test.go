@@ -147,6 +147,21 @@ func (s *machinerSuite) TestSetMachineAddresses(c *gc.C) {
 	c.Assert(s.machine.MachineAddresses(), gc.DeepEquals, addresses)
 }
 
+func (s *machinerSuite) TestSetUtilization(c *gc.C) {
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, gc.IsNil)
+
+	err = machine.SetUtilization(0.75, 512, 1024, 2048)
+	c.Assert(err, gc.IsNil)
+
+	u, err := s.machine.Utilization()
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.LoadAverage, gc.Equals, 0.75)
+	c.Assert(u.MemoryUsed, gc.Equals, uint64(512))
+	c.Assert(u.MemoryTotal, gc.Equals, uint64(1024))
+	c.Assert(u.DiskFree, gc.Equals, uint64(2048))
+}
+
 func (s *machinerSuite) TestWatch(c *gc.C) {
 	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
 	c.Assert(err, gc.IsNil)
@@ -0,0 +1,40 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maintenance
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to state database maintenance.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Maintenance client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Maintenance")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// DatabaseStatus returns the size and index health of each collection
+// in the state database, and the replication lag of each state server.
+func (c *Client) DatabaseStatus() (params.DatabaseStatusResult, error) {
+	var result params.DatabaseStatusResult
+	if err := c.facade.FacadeCall("DatabaseStatus", nil, &result); err != nil {
+		return params.DatabaseStatusResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// CompactDatabase compacts the state database, or repairs it if repair
+// is true.
+func (c *Client) CompactDatabase(repair bool) error {
+	args := params.CompactDatabase{Repair: repair}
+	return c.facade.FacadeCall("CompactDatabase", args, nil)
+}
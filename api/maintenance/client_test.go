@@ -0,0 +1,32 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maintenance_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/maintenance"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type maintenanceSuite struct {
+	jujutesting.JujuConnSuite
+	client *maintenance.Client
+}
+
+var _ = gc.Suite(&maintenanceSuite{})
+
+func (s *maintenanceSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.client = maintenance.NewClient(s.APIState)
+}
+
+func (s *maintenanceSuite) TestDatabaseStatusAndCompact(c *gc.C) {
+	result, err := s.client.DatabaseStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Collections, gc.Not(gc.HasLen), 0)
+
+	err = s.client.CompactDatabase(false)
+	c.Assert(err, gc.IsNil)
+}
@@ -0,0 +1,70 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package portforwarder
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// State provides access to a port forwarder worker's view of the state.
+type State struct {
+	facade base.FacadeCaller
+}
+
+// NewState returns a version of the state that provides functionality
+// required by the port forwarder worker.
+func NewState(caller base.APICaller) *State {
+	return &State{base.NewFacadeCaller(caller, "PortForwarder")}
+}
+
+// ForwardedPorts returns the port ranges opened by units of exposed
+// services in the containers of the given host machine, which should
+// be forwarded from the host to the containers.
+func (st *State) ForwardedPorts(hostTag names.MachineTag) ([]params.PortForward, error) {
+	var results params.PortForwardsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: hostTag.String()}},
+	}
+	err := st.facade.FacadeCall("ForwardedPorts", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Forwards, nil
+}
+
+// WatchForwardedPorts returns a watcher that notifies when the ports
+// that should be forwarded to the containers of the given host machine
+// may have changed.
+func (st *State) WatchForwardedPorts(hostTag names.MachineTag) (watcher.NotifyWatcher, error) {
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: hostTag.String()}},
+	}
+	err := st.facade.FacadeCall("WatchForwardedPorts", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(st.facade.RawAPICaller(), result)
+	return w, nil
+}
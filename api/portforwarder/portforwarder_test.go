@@ -0,0 +1,91 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package portforwarder_test
+
+import (
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/portforwarder"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type portForwarderSuite struct {
+	jujutesting.JujuConnSuite
+
+	rawMachine    *state.Machine
+	portForwarder *portforwarder.State
+}
+
+var _ = gc.Suite(&portForwarderSuite{})
+
+func (s *portForwarderSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	st, machine := s.OpenAPIAsNewMachine(c)
+	s.rawMachine = machine
+	s.portForwarder = st.PortForwarder()
+	c.Assert(s.portForwarder, gc.NotNil)
+}
+
+func (s *portForwarderSuite) TestForwardedPorts(c *gc.C) {
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.rawMachine.Id(), instance.LXC)
+	c.Assert(err, gc.IsNil)
+	err = container.SetMachineAddresses(network.NewAddress("10.0.3.5", network.ScopeUnknown))
+	c.Assert(err, gc.IsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err = service.SetExposed()
+	c.Assert(err, gc.IsNil)
+	unit, err := service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(container)
+	c.Assert(err, gc.IsNil)
+	err = unit.OpenPorts("tcp", 8000, 8080)
+	c.Assert(err, gc.IsNil)
+
+	forwards, err := s.portForwarder.ForwardedPorts(s.rawMachine.Tag().(names.MachineTag))
+	c.Assert(err, gc.IsNil)
+	c.Assert(forwards, gc.DeepEquals, []params.PortForward{{
+		ContainerId: container.Id(),
+		Address:     "10.0.3.5",
+		Protocol:    "tcp",
+		FromPort:    8000,
+		ToPort:      8080,
+	}})
+}
+
+func (s *portForwarderSuite) TestForwardedPortsOtherMachine(c *gc.C) {
+	_, err := s.portForwarder.ForwardedPorts(names.NewMachineTag("42"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *portForwarderSuite) TestWatchForwardedPorts(c *gc.C) {
+	w, err := s.portForwarder.WatchForwardedPorts(s.rawMachine.Tag().(names.MachineTag))
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	// Initial event.
+	wc.AssertOneChange()
+
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.rawMachine.Id(), instance.LXC)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *portForwarderSuite) TestWatchForwardedPortsOtherMachine(c *gc.C) {
+	_, err := s.portForwarder.WatchForwardedPorts(names.NewMachineTag("42"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
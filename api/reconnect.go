@@ -0,0 +1,374 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.google.com/p/go.net/websocket"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/jsoncodec"
+)
+
+// defaultMaxRetryDelay is the longest the client waits between rounds
+// of reconnection attempts when DialOpts.MaxRetryDelay is not set.
+const defaultMaxRetryDelay = 1 * time.Minute
+
+// deadConnectionWait is how long a failed call waits for the
+// underlying connection to be reported dead before assuming that
+// the failure was not caused by a broken connection.
+var deadConnectionWait = 1 * time.Second
+
+// watcherSubscription records an API call that created watchers,
+// so that it can be made again to resubscribe them after the
+// client reconnects.
+type watcherSubscription struct {
+	facade   string
+	version  int
+	id       string
+	method   string
+	args     interface{}
+	response reflect.Type
+
+	// watcherIds holds the client's ids for the watchers returned
+	// by the original call, in the order they appear in its
+	// response. Ids of watchers that have since been stopped are
+	// empty.
+	watcherIds []string
+}
+
+// reconnectingCall makes an API call on a client that reconnects
+// when its connection fails. If the connection fails while making
+// the call, the client reconnects and the call is made once more.
+func (s *State) reconnectingCall(facade string, version int, id, method string, args, response interface{}) error {
+	isWatcher := strings.HasSuffix(facade, "Watcher") && id != ""
+	for attempt := 0; ; attempt++ {
+		s.mu.Lock()
+		client, generation := s.client, s.generation
+		callId := id
+		if isWatcher {
+			// Calls on unknown or stopped watchers are made
+			// with an empty id, and fail on the server.
+			callId = s.watcherIds[id]
+		}
+		s.mu.Unlock()
+
+		err := callClient(client, facade, version, callId, method, args, response)
+		if attempt == 0 && isBrokenConnection(client, err) {
+			logger.Infof("connection to %q failed: %v; reconnecting", s.Addr(), err)
+			if err := s.reconnect(generation); err != nil {
+				return err
+			}
+			continue
+		}
+		if err == nil {
+			s.recordWatchers(facade, version, id, method, args, response)
+		}
+		return err
+	}
+}
+
+// isBrokenConnection reports whether err, returned from a call on
+// client, was caused by the connection failing.
+func isBrokenConnection(client *rpc.Conn, err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, ok := err.(*rpc.RequestError); ok {
+		return false
+	}
+	select {
+	case <-client.Dead():
+		return true
+	case <-time.After(deadConnectionWait):
+		return false
+	}
+}
+
+// reconnect replaces the client's connection, which failed while
+// it had the given generation. It tries each known API server
+// address in turn, backing off exponentially between rounds until
+// the dial timeout expires, then logs in again and resubscribes
+// all active watchers.
+func (s *State) reconnect(failedGeneration int) error {
+	s.reconnectMu.Lock()
+	defer s.reconnectMu.Unlock()
+	s.mu.Lock()
+	generation := s.generation
+	s.mu.Unlock()
+	if generation != failedGeneration {
+		// Another call has already reconnected.
+		return nil
+	}
+
+	delay := s.opts.RetryDelay
+	if delay <= 0 {
+		delay = DefaultDialOpts().RetryDelay
+	}
+	maxDelay := s.opts.MaxRetryDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRetryDelay
+	}
+	deadline := time.Now().Add(s.opts.Timeout)
+	var lastErr error
+	for {
+		for _, addr := range s.reconnectAddrs() {
+			select {
+			case <-s.closed:
+				return errors.New("connection closed")
+			default:
+			}
+			newSt, err := s.dialAndLogin(addr)
+			if err == nil {
+				if err := s.replaceConnection(newSt); err != nil {
+					return err
+				}
+				logger.Infof("reconnected to %q", addr)
+				s.resubscribeWatchers()
+				return nil
+			}
+			logger.Debugf("cannot reconnect to %q: %v", addr, err)
+			lastErr = err
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return errors.Annotate(lastErr, "cannot reconnect to API server")
+		}
+		select {
+		case <-time.After(delay):
+		case <-s.closed:
+			return errors.New("connection closed")
+		}
+		if delay *= 2; delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// reconnectAddrs returns all the API server addresses known to the
+// client, starting with the one after the address currently in use.
+func (s *State) reconnectAddrs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var addrs []string
+	seen := make(map[string]bool)
+	add := func(addr string) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	for _, addr := range s.info.Addrs {
+		add(addr)
+	}
+	for _, server := range s.hostPorts {
+		for _, hostPort := range server {
+			add(hostPort.NetAddr())
+		}
+	}
+	for i, addr := range addrs {
+		if addr == s.addr {
+			return append(addrs[i+1:], addrs[:i+1]...)
+		}
+	}
+	return addrs
+}
+
+// dialAndLogin connects to the API server at addr and logs in with
+// the client's credentials. It returns the new connection wrapped
+// in a State that does not itself reconnect.
+func (s *State) dialAndLogin(addr string) (*State, error) {
+	cfg, err := setUpWebsocket(addr, s.info.EnvironTag.Id(), s.certPool)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := websocket.DialConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := rpc.NewConn(jsoncodec.NewWebsocket(conn), nil)
+	client.Start()
+	broken := make(chan struct{})
+	close(broken)
+	newSt := &State{
+		client:     client,
+		conn:       conn,
+		addr:       conn.Config().Location.Host,
+		serverRoot: "https://" + conn.Config().Location.Host,
+		certPool:   s.certPool,
+		broken:     broken,
+		closed:     make(chan struct{}),
+	}
+	if s.tag != "" || s.password != "" {
+		if err := newSt.Login(s.tag, s.password, s.info.Nonce); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return newSt, nil
+}
+
+// replaceConnection makes the client use the connection and login
+// details of newSt, and closes the connection that failed. The new
+// connection is discarded if the client has been closed meanwhile.
+func (s *State) replaceConnection(newSt *State) error {
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+		s.mu.Unlock()
+		newSt.client.Close()
+		return errors.New("connection closed")
+	default:
+	}
+	oldClient := s.client
+	s.client = newSt.client
+	s.conn = newSt.conn
+	s.addr = newSt.addr
+	s.serverRoot = newSt.serverRoot
+	s.environTag = newSt.environTag
+	s.hostPorts = newSt.hostPorts
+	s.facadeVersions = newSt.facadeVersions
+	s.authTag = newSt.authTag
+	s.generation++
+	s.mu.Unlock()
+	oldClient.Close()
+	return nil
+}
+
+// recordWatchers records the call that produced response if the
+// response holds the ids of any new watchers. The server's watcher
+// ids in the response are replaced with ids allocated by the client,
+// which stay valid when the watchers are resubscribed.
+func (s *State) recordWatchers(facade string, version int, id, method string, args, response interface{}) {
+	if strings.HasSuffix(facade, "Watcher") && method == "Stop" {
+		s.forgetWatcher(id)
+		return
+	}
+	if response == nil || reflect.TypeOf(response).Kind() != reflect.Ptr {
+		return
+	}
+	fields := watcherIdFields(response)
+	if len(fields) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub := &watcherSubscription{
+		facade:     facade,
+		version:    version,
+		id:         id,
+		method:     method,
+		args:       args,
+		response:   reflect.TypeOf(response).Elem(),
+		watcherIds: make([]string, len(fields)),
+	}
+	for i, field := range fields {
+		if serverId := field.String(); serverId != "" {
+			s.lastWatcherId++
+			clientId := strconv.Itoa(s.lastWatcherId)
+			s.watcherIds[clientId] = serverId
+			sub.watcherIds[i] = clientId
+			field.SetString(clientId)
+		}
+	}
+	s.subscriptions = append(s.subscriptions, sub)
+}
+
+// forgetWatcher stops tracking the watcher with the given id, so
+// that it is not resubscribed on reconnection.
+func (s *State) forgetWatcher(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.watcherIds, id)
+	subs := s.subscriptions[:0]
+	for _, sub := range s.subscriptions {
+		active := false
+		for i, watcherId := range sub.watcherIds {
+			if watcherId == id {
+				sub.watcherIds[i] = ""
+			}
+			active = active || sub.watcherIds[i] != ""
+		}
+		if active {
+			subs = append(subs, sub)
+		}
+	}
+	s.subscriptions = subs
+}
+
+// resubscribeWatchers repeats the calls that created the client's
+// active watchers, and arranges for calls on the original watcher
+// ids to be made on the new watchers instead. Each new watcher
+// sends its initial event again.
+func (s *State) resubscribeWatchers() {
+	s.mu.Lock()
+	client := s.client
+	subs := append([]*watcherSubscription(nil), s.subscriptions...)
+	s.mu.Unlock()
+	for _, sub := range subs {
+		response := reflect.New(sub.response).Interface()
+		err := callClient(client, sub.facade, sub.version, sub.id, sub.method, sub.args, response)
+		fields := watcherIdFields(response)
+		if err == nil && len(fields) != len(sub.watcherIds) {
+			err = errors.New("unexpected number of watchers")
+		}
+		s.mu.Lock()
+		for i, watcherId := range sub.watcherIds {
+			if watcherId == "" {
+				continue
+			}
+			if err != nil {
+				// Calls on the watcher will fail with an
+				// unknown watcher error.
+				s.watcherIds[watcherId] = ""
+			} else {
+				s.watcherIds[watcherId] = fields[i].String()
+			}
+		}
+		s.mu.Unlock()
+		if err != nil {
+			logger.Warningf("cannot resubscribe watchers from %s.%s: %v", sub.facade, sub.method, err)
+		}
+	}
+}
+
+// watcherIdFields returns all the string fields named <Kind>WatcherId
+// in the given API response, in order.
+func watcherIdFields(response interface{}) []reflect.Value {
+	var fields []reflect.Value
+	var find func(v reflect.Value)
+	find = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if !v.IsNil() {
+				find(v.Elem())
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				find(v.Index(i))
+			}
+		case reflect.Struct:
+			t := v.Type()
+			for i := 0; i < v.NumField(); i++ {
+				field := t.Field(i)
+				if field.PkgPath != "" {
+					continue
+				}
+				if field.Type.Kind() == reflect.String && strings.HasSuffix(field.Name, "WatcherId") {
+					fields = append(fields, v.Field(i))
+					continue
+				}
+				find(v.Field(i))
+			}
+		}
+	}
+	if response != nil {
+		find(reflect.ValueOf(response))
+	}
+	return fields
+}
@@ -20,6 +20,12 @@ type RsyslogConfig struct {
 	// Port is only used by state servers as the port to listen on.
 	Port      int
 	HostPorts []network.HostPort
+	// ExternalHost, if set, is the host:port of an external syslog
+	// collector which agents forward their logs to instead of the
+	// state servers. ExternalCACert holds the certificate of the CA
+	// that signed the collector's certificate.
+	ExternalHost   string
+	ExternalCACert string
 }
 
 // State provides access to the Rsyslog API facade.
@@ -91,8 +97,10 @@ func (st *State) GetRsyslogConfig(agentTag string) (*RsyslogConfig, error) {
 		return nil, result.Error
 	}
 	return &RsyslogConfig{
-		CACert:    result.CACert,
-		Port:      result.Port,
-		HostPorts: result.HostPorts,
+		CACert:         result.CACert,
+		Port:           result.Port,
+		HostPorts:      result.HostPorts,
+		ExternalHost:   result.ExternalHost,
+		ExternalCACert: result.ExternalCACert,
 	}, nil
 }
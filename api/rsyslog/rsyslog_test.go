@@ -75,4 +75,38 @@ func (s *rsyslogSuite) TestWatchForRsyslogChanges(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *rsyslogSuite) TestGetRsyslogConfigExternalHost(c *gc.C) {
+	err := s.APIState.Client().EnvironmentSet(map[string]interface{}{
+		"syslog-host":    "logs.example.com:6514",
+		"syslog-ca-cert": coretesting.CACert,
+	})
+	c.Assert(err, gc.IsNil)
+
+	cfg, err := s.rsyslog.GetRsyslogConfig(s.machine.Tag().String())
+	c.Assert(err, gc.IsNil)
+	c.Assert(cfg.ExternalHost, gc.Equals, "logs.example.com:6514")
+	c.Assert(cfg.ExternalCACert, gc.Equals, coretesting.CACert)
+}
+
+func (s *rsyslogSuite) TestWatchForRsyslogChangesEnvironConfig(c *gc.C) {
+	w, err := s.rsyslog.WatchForRsyslogChanges(s.machine.Tag().String())
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, w)
+
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	// Initial event
+	wc.AssertOneChange()
+
+	// Pointing at an external collector changes the configuration.
+	err = s.APIState.Client().EnvironmentSet(map[string]interface{}{
+		"syslog-host":    "logs.example.com:6514",
+		"syslog-ca-cert": coretesting.CACert,
+	})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 // SetRsyslogCACert is tested in apiserver/rsyslog
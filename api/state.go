@@ -20,11 +20,13 @@ import (
 	apilogger "github.com/juju/juju/api/logger"
 	"github.com/juju/juju/api/machiner"
 	"github.com/juju/juju/api/networker"
+	"github.com/juju/juju/api/portforwarder"
 	"github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/api/reboot"
 	"github.com/juju/juju/api/rsyslog"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/api/upgrader"
+	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 )
@@ -176,6 +178,21 @@ func addAddress(servers [][]network.HostPort, addr string) ([][]network.HostPort
 	return result, nil
 }
 
+// NotifyWatcherMux returns the connection's NotifyWatcherMux, which
+// lets many NotifyWatchers wait for changes with a single outstanding
+// API call. It returns nil if the API server does not support it.
+func (st *State) NotifyWatcherMux() *watcher.NotifyWatcherMux {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.facadeVersions["NotifyWatcherMux"]; !ok {
+		return nil
+	}
+	if st.notifyWatcherMux == nil {
+		st.notifyWatcherMux = watcher.NewNotifyWatcherMux(st)
+	}
+	return st.notifyWatcherMux
+}
+
 // Client returns an object that can be used
 // to access client-specific functionality.
 func (st *State) Client() *Client {
@@ -213,7 +230,9 @@ func (st *State) Uniter() (*uniter.State, error) {
 		logger.Warningf("ignoring invalid environ tag: %v", err)
 	}
 	charmsURL := uniter.CharmsURL(st.Addr(), envTag)
-	return uniter.NewState(st, unitTag, charmsURL), nil
+	uniterState := uniter.NewState(st, unitTag, charmsURL)
+	uniterState.SetCharmStorePassword(st.password)
+	return uniterState, nil
 }
 
 // Firewaller returns a version of the state that provides functionality
@@ -258,6 +277,11 @@ func (st *State) Logger() *apilogger.State {
 	return apilogger.NewState(st)
 }
 
+// PortForwarder returns access to the PortForwarder API
+func (st *State) PortForwarder() *portforwarder.State {
+	return portforwarder.NewState(st)
+}
+
 // KeyUpdater returns access to the KeyUpdater API
 func (st *State) KeyUpdater() *keyupdater.State {
 	return keyupdater.NewState(st)
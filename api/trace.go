@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/loggo"
+)
+
+var traceLogger = loggo.GetLogger("juju.api.trace")
+
+// tracing is non-zero when API calls are being traced.
+var tracing int32
+
+// SetTracing enables or disables the tracing of API calls. While
+// enabled, the facade, method, duration and error of every call made
+// by any API connection in the process are logged at INFO level to
+// the juju.api.trace module.
+func SetTracing(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&tracing, value)
+}
+
+// Tracing reports whether API calls are being traced.
+func Tracing() bool {
+	return atomic.LoadInt32(&tracing) != 0
+}
+
+// traceAPICall logs a call to the given facade method that started
+// at the given time and finished with the error pointed to by err.
+func traceAPICall(facade string, version int, id, method string, start time.Time, err *error) {
+	// The logger worker resets the logging levels whenever the
+	// environment's logging-config changes, so make sure that the
+	// traces asked for are not filtered out.
+	if !traceLogger.IsInfoEnabled() {
+		traceLogger.SetLogLevel(loggo.INFO)
+	}
+	duration := time.Since(start)
+	if id != "" {
+		facade += "(" + id + ")"
+	}
+	if *err != nil {
+		traceLogger.Infof("%s[v%d].%s took %v: error: %v", facade, version, method, duration, *err)
+		return
+	}
+	traceLogger.Infof("%s[v%d].%s took %v", facade, version, method, duration)
+}
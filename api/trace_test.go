@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api_test
+
+import (
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type traceSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&traceSuite{})
+
+func (s *traceSuite) TearDownTest(c *gc.C) {
+	api.SetTracing(false)
+	s.JujuConnSuite.TearDownTest(c)
+}
+
+func (s *traceSuite) TestTracing(c *gc.C) {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("api-trace-tester", &tw, loggo.INFO), gc.IsNil)
+	defer loggo.RemoveWriter("api-trace-tester")
+
+	c.Assert(api.Tracing(), jc.IsFalse)
+	err := s.APIState.Ping()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(traces(tw.Log()), gc.HasLen, 0)
+
+	api.SetTracing(true)
+	c.Assert(api.Tracing(), jc.IsTrue)
+	err = s.APIState.Ping()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.APICall("Client", 0, "", "NoSuchMethod", nil, nil)
+	c.Assert(err, gc.NotNil)
+	messages := traces(tw.Log())
+	c.Assert(messages, gc.HasLen, 2)
+	c.Check(messages[0], gc.Matches, `Pinger\[v0\]\.Ping took .*`)
+	c.Check(messages[1], gc.Matches, `Client\[v0\]\.NoSuchMethod took .*: error: .*`)
+
+	api.SetTracing(false)
+	err = s.APIState.Ping()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(traces(tw.Log()), gc.HasLen, 2)
+}
+
+// traces returns the messages logged by the API call tracer.
+func traces(logs []loggo.TestLogValues) []string {
+	var messages []string
+	for _, log := range logs {
+		if log.Module == "juju.api.trace" {
+			messages = append(messages, log.Message)
+		}
+	}
+	return messages
+}
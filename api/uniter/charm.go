@@ -63,6 +63,27 @@ func (c *Charm) ArchiveURL() *url.URL {
 	return &archiveURL
 }
 
+// StoreArchiveURL returns the url to the charm archive in the API
+// server's charm store proxy, or nil if the charm is not a charm store
+// charm.
+func (c *Charm) StoreArchiveURL() *url.URL {
+	if c.curl.Schema != "cs" {
+		return nil
+	}
+	archiveURL := *c.st.charmsURL
+	archiveURL.Path = path.Join(path.Dir(archiveURL.Path), "charmstore")
+	q := archiveURL.Query()
+	q.Set("url", c.curl.String())
+	archiveURL.RawQuery = q.Encode()
+	return &archiveURL
+}
+
+// ArchiveCredentials returns the tag and password with which the
+// unit downloads from the charm store proxy.
+func (c *Charm) ArchiveCredentials() (user, password string) {
+	return c.st.unitTag.String(), c.st.password
+}
+
 // ArchiveSha256 returns the SHA256 digest of the charm archive
 // (bundle) bytes.
 //
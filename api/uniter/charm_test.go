@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/names"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/testing"
@@ -62,6 +63,28 @@ func (s *charmSuite) TestArchiveURL(c *gc.C) {
 	c.Assert(archiveURL, gc.DeepEquals, url)
 }
 
+func (s *charmSuite) TestStoreArchiveURL(c *gc.C) {
+	c.Assert(s.apiCharm.StoreArchiveURL(), gc.IsNil)
+
+	storeCharm, err := s.uniter.Charm(charm.MustParseURL("cs:quantal/wordpress-3"))
+	c.Assert(err, gc.IsNil)
+	apiInfo := s.APIInfo(c)
+	url, err := url.Parse(fmt.Sprintf(
+		"https://%s/environment/%s/charmstore?url=%s",
+		apiInfo.Addrs[0],
+		apiInfo.EnvironTag.Id(),
+		url.QueryEscape("cs:quantal/wordpress-3"),
+	))
+	c.Assert(err, gc.IsNil)
+	c.Assert(storeCharm.StoreArchiveURL(), gc.DeepEquals, url)
+}
+
+func (s *charmSuite) TestArchiveCredentials(c *gc.C) {
+	user, password := s.apiCharm.ArchiveCredentials()
+	c.Assert(user, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Assert(password, gc.Not(gc.Equals), "")
+}
+
 func (s *charmSuite) TestArchiveSha256(c *gc.C) {
 	archiveSha256, err := s.apiCharm.ArchiveSha256()
 	c.Assert(err, gc.IsNil)
@@ -17,10 +17,11 @@ import (
 // Relation represents a relation between one or two service
 // endpoints.
 type Relation struct {
-	st   *State
-	tag  names.RelationTag
-	id   int
-	life params.Life
+	st      *State
+	tag     names.RelationTag
+	id      int
+	life    params.Life
+	network string
 }
 
 // Tag returns the relation tag.
@@ -46,6 +47,12 @@ func (r *Relation) Life() params.Life {
 	return r.life
 }
 
+// Network returns the name of the network that the relation's traffic
+// should use, or "" if the relation was not added with one.
+func (r *Relation) Network() string {
+	return r.network
+}
+
 // Refresh refreshes the contents of the relation from the underlying
 // state. It returns an error that satisfies errors.IsNotFound if the
 // relation has been removed.
@@ -55,7 +62,7 @@ func (r *Relation) Refresh() error {
 		return err
 	}
 	// NOTE: The life cycle information is the only
-	// thing that can change - id, tag and endpoint
+	// thing that can change - id, tag, endpoint and network
 	// information are static.
 	r.life = result.Life
 
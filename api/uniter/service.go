@@ -5,7 +5,9 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v4"
 
@@ -157,3 +159,55 @@ func (s *Service) ownerTag() (names.UserTag, error) {
 	}
 	return names.ParseUserTag(result.Result)
 }
+
+// HookPolicy holds the hook execution timeout, retry policy and
+// resource limits that apply to a service's units. A zero Timeout
+// means that hooks may run indefinitely; zero limits are not applied.
+type HookPolicy struct {
+	Timeout    time.Duration
+	RetryCount int
+	RetryDelay time.Duration
+
+	// MemoryLimit holds the most memory, in megabytes, that a hook
+	// and the processes it starts may use.
+	MemoryLimit uint64
+
+	// CPULimit holds the most CPU time a hook and the processes it
+	// starts may use.
+	CPULimit time.Duration
+
+	// StopGracePeriod holds how long the stop hook may run before it
+	// is killed. A stop hook that is killed is not treated as failed.
+	StopGracePeriod time.Duration
+}
+
+// HookPolicy returns the hook execution timeout and retry policy that
+// applies to the service's units.
+func (s *Service) HookPolicy() (HookPolicy, error) {
+	if s.st.BestAPIVersion() < 1 {
+		return HookPolicy{}, errors.NotImplementedf("service.HookPolicy() (need V1+)")
+	}
+	var results params.HookPolicyResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("HookPolicy", args, &results)
+	if err != nil {
+		return HookPolicy{}, err
+	}
+	if len(results.Results) != 1 {
+		return HookPolicy{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return HookPolicy{}, result.Error
+	}
+	return HookPolicy{
+		Timeout:         result.Timeout,
+		RetryCount:      result.RetryCount,
+		RetryDelay:      result.RetryDelay,
+		MemoryLimit:     result.MemoryLimit,
+		CPULimit:        result.CPULimit,
+		StopGracePeriod: result.StopGracePeriod,
+	}, nil
+}
@@ -6,6 +6,7 @@ package uniter_test
 import (
 	"net/url"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -13,6 +14,8 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
 
@@ -136,6 +139,26 @@ func (s *serviceSuite) TestOwnerTagV1(c *gc.C) {
 	c.Assert(tag, gc.Equals, s.AdminUserTag(c))
 }
 
+func (s *serviceSuite) TestHookPolicy(c *gc.C) {
+	retryCount := 4
+	err := s.wordpressService.SetHookPolicy(state.HookPolicy{RetryCount: &retryCount})
+	c.Assert(err, gc.IsNil)
+
+	policy, err := s.apiService.HookPolicy()
+	c.Assert(err, gc.IsNil)
+	c.Assert(policy, gc.Equals, uniter.HookPolicy{
+		RetryCount: 4,
+		RetryDelay: config.DefaultHookRetryDelay,
+	})
+}
+
+func (s *serviceSuite) TestHookPolicyV0(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV0)
+
+	_, err := s.apiService.HookPolicy()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
 func (s *serviceSuite) patchNewState(
 	c *gc.C,
 	patchFunc func(_ base.APICaller, _ names.UnitTag, _ *url.URL) *uniter.State,
@@ -83,3 +83,27 @@ func (s *stateSuite) TestAllMachinePortsV1(c *gc.C) {
 		network.PortRange{1, 8, "udp"}:     params.RelationUnit{Unit: wordpressUnit1.Tag().String()},
 	})
 }
+
+func (s *stateSuite) TestMachineAddressesV0NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV0)
+
+	addresses, err := s.uniter.MachineAddresses(s.wordpressMachine.Tag().(names.MachineTag))
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "MachineAddresses() (need V1+) not implemented")
+	c.Assert(addresses, gc.IsNil)
+}
+
+func (s *stateSuite) TestMachineAddressesV1(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	expected := []network.Address{
+		network.NewAddress("10.0.0.1", network.ScopeCloudLocal),
+		network.NewAddress("8.8.8.8", network.ScopePublic),
+	}
+	err := s.wordpressMachine.SetAddresses(expected...)
+	c.Assert(err, gc.IsNil)
+
+	addresses, err := s.uniter.MachineAddresses(s.wordpressMachine.Tag().(names.MachineTag))
+	c.Assert(err, gc.IsNil)
+	c.Assert(addresses, jc.DeepEquals, expected)
+}
@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -229,6 +230,272 @@ func (u *Unit) AssignedMachine() (names.MachineTag, error) {
 	return names.ParseMachineTag(result.Result)
 }
 
+// SetDebugHooksTranscript records the transcript of the unit's
+// debug-hooks session.
+func (u *Unit) SetDebugHooksTranscript(transcript string) error {
+	if u.st.BestAPIVersion() < 1 {
+		return errors.NotImplementedf("unit.SetDebugHooksTranscript() (need V1+)")
+	}
+	var result params.ErrorResults
+	args := params.EntitiesDebugHooksTranscript{
+		Entities: []params.EntityDebugHooksTranscript{
+			{Tag: u.tag.String(), Transcript: transcript},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetDebugHooksTranscript", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// Chaos holds the faults that the unit's agent should inject into its
+// hook execution for chaos testing.
+type Chaos struct {
+	// FailHooks holds the names of the hooks to fail instead of
+	// running them. The name "*" fails every hook.
+	FailHooks []string
+
+	// HookDelay holds how long to wait before running each hook.
+	HookDelay time.Duration
+
+	// Restart holds whether the agent should restart before running
+	// its next hook.
+	Restart bool
+}
+
+// Chaos returns the faults that the unit's agent should inject into
+// its hook execution.
+func (u *Unit) Chaos() (Chaos, error) {
+	if u.st.BestAPIVersion() < 1 {
+		return Chaos{}, errors.NotImplementedf("unit.Chaos() (need V1+)")
+	}
+	var results params.UnitChaosResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("UnitChaos", args, &results)
+	if err != nil {
+		return Chaos{}, err
+	}
+	if len(results.Results) != 1 {
+		return Chaos{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return Chaos{}, result.Error
+	}
+	return Chaos{
+		FailHooks: result.FailHooks,
+		HookDelay: result.HookDelay,
+		Restart:   result.Restart,
+	}, nil
+}
+
+// ClearChaosRestart records that the unit's agent has restarted as
+// instructed by Chaos.
+func (u *Unit) ClearChaosRestart() error {
+	if u.st.BestAPIVersion() < 1 {
+		return errors.NotImplementedf("unit.ClearChaosRestart() (need V1+)")
+	}
+	var result params.ErrorResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("ClearChaosRestart", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// ClaimLeadership claims, or renews, leadership of the unit's service.
+// It returns true if the unit is now the service's leader, and false
+// if another unit holds leadership.
+func (u *Unit) ClaimLeadership() (bool, error) {
+	if u.st.BestAPIVersion() < 1 {
+		return false, errors.NotImplementedf("unit.ClaimLeadership() (need V1+)")
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("ClaimLeadership", args, &results)
+	if err != nil {
+		return false, err
+	}
+	if len(results.Results) != 1 {
+		return false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
+// IsLeader returns whether the unit currently holds the leadership
+// lease of its service. Unlike ClaimLeadership, it does not claim or
+// renew the lease.
+func (u *Unit) IsLeader() (bool, error) {
+	if u.st.BestAPIVersion() < 1 {
+		return false, errors.NotImplementedf("unit.IsLeader() (need V1+)")
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("Leader", args, &results)
+	if err != nil {
+		return false, err
+	}
+	if len(results.Results) != 1 {
+		return false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
+// LeaderSettings returns the settings written by the leader of the
+// unit's service.
+func (u *Unit) LeaderSettings() (map[string]string, error) {
+	if u.st.BestAPIVersion() < 1 {
+		return nil, errors.NotImplementedf("unit.LeaderSettings() (need V1+)")
+	}
+	var results params.LeaderSettingsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("LeaderSettings", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Settings, nil
+}
+
+// MergeLeaderSettings applies the given changes to the leader settings
+// of the unit's service; keys with empty values are deleted. It fails
+// unless the unit is the service's current leader.
+func (u *Unit) MergeLeaderSettings(settings map[string]string) error {
+	if u.st.BestAPIVersion() < 1 {
+		return errors.NotImplementedf("unit.MergeLeaderSettings() (need V1+)")
+	}
+	var result params.ErrorResults
+	args := params.EntitiesLeaderSettings{
+		Entities: []params.EntityLeaderSettings{
+			{Tag: u.tag.String(), Settings: settings},
+		},
+	}
+	err := u.st.facade.FacadeCall("MergeLeaderSettings", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// WatchLeaderSettings returns a watcher for observing changes to the
+// leader settings of the unit's service.
+func (u *Unit) WatchLeaderSettings() (watcher.NotifyWatcher, error) {
+	if u.st.BestAPIVersion() < 1 {
+		return nil, errors.NotImplementedf("unit.WatchLeaderSettings() (need V1+)")
+	}
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WatchLeaderSettings", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := u.st.newNotifyWatcher(result)
+	return w, nil
+}
+
+// StorageInstances returns the storage instances owned by the unit.
+func (u *Unit) StorageInstances() ([]params.StorageInstance, error) {
+	if u.st.BestAPIVersion() < 1 {
+		return nil, errors.NotImplementedf("unit.StorageInstances() (need V1+)")
+	}
+	var results params.StorageInstancesResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("StorageInstances", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Instances, nil
+}
+
+// WatchStorageInstances returns a watcher for observing changes to
+// the unit's storage instances.
+func (u *Unit) WatchStorageInstances() (watcher.NotifyWatcher, error) {
+	if u.st.BestAPIVersion() < 1 {
+		return nil, errors.NotImplementedf("unit.WatchStorageInstances() (need V1+)")
+	}
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WatchStorageInstances", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := u.st.newNotifyWatcher(result)
+	return w, nil
+}
+
+// StorageDetached records that the unit has finished detaching the
+// storage instance with the given id, which must no longer be alive.
+func (u *Unit) StorageDetached(storageId string) error {
+	if u.st.BestAPIVersion() < 1 {
+		return errors.NotImplementedf("unit.StorageDetached() (need V1+)")
+	}
+	var result params.ErrorResults
+	args := params.EntitiesStorageInstances{
+		Entities: []params.EntityStorageInstance{{
+			Tag:       u.tag.String(),
+			StorageId: storageId,
+		}},
+	}
+	err := u.st.facade.FacadeCall("StorageDetached", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // IsPrincipal returns whether the unit is deployed in its own container,
 // and can therefore have subordinate services deployed alongside it.
 //
@@ -488,7 +755,7 @@ func (u *Unit) WatchConfigSettings() (watcher.NotifyWatcher, error) {
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	w := watcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
+	w := u.st.newNotifyWatcher(result)
 	return w, nil
 }
 
@@ -512,7 +779,7 @@ func (u *Unit) WatchAddresses() (watcher.NotifyWatcher, error) {
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	w := watcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
+	w := u.st.newNotifyWatcher(result)
 	return w, nil
 }
 
@@ -622,6 +889,6 @@ func (u *Unit) WatchMeterStatus() (watcher.NotifyWatcher, error) {
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	w := watcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
+	w := u.st.newNotifyWatcher(result)
 	return w, nil
 }
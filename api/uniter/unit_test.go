@@ -203,6 +203,160 @@ func (s *unitSuite) TestAssignedMachineV1(c *gc.C) {
 	c.Assert(machineTag, gc.Equals, s.wordpressMachine.Tag())
 }
 
+func (s *unitSuite) TestSetDebugHooksTranscriptV0NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV0)
+
+	err := s.apiUnit.SetDebugHooksTranscript("=== install ===\n")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "unit.SetDebugHooksTranscript() (need V1+) not implemented")
+}
+
+func (s *unitSuite) TestSetDebugHooksTranscriptV1(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	err := s.apiUnit.SetDebugHooksTranscript("=== install ===\n")
+	c.Assert(err, gc.IsNil)
+	transcript, _, err := s.wordpressUnit.DebugHooksTranscript()
+	c.Assert(err, gc.IsNil)
+	c.Assert(transcript, gc.Equals, "=== install ===\n")
+}
+
+func (s *unitSuite) TestChaosV0NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV0)
+
+	_, err := s.apiUnit.Chaos()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "unit.Chaos() (need V1+) not implemented")
+	err = s.apiUnit.ClearChaosRestart()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestChaosV1(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"enable-chaos-testing": true}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	err = s.wordpressUnit.SetChaos(state.UnitChaos{
+		FailHooks: []string{"*"},
+		HookDelay: time.Second,
+		Restart:   true,
+	})
+	c.Assert(err, gc.IsNil)
+
+	chaos, err := s.apiUnit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos, jc.DeepEquals, uniter.Chaos{
+		FailHooks: []string{"*"},
+		HookDelay: time.Second,
+		Restart:   true,
+	})
+
+	err = s.apiUnit.ClearChaosRestart()
+	c.Assert(err, gc.IsNil)
+	chaos, err = s.apiUnit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos.Restart, gc.Equals, false)
+}
+
+func (s *unitSuite) TestLeadershipV0NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV0)
+
+	_, err := s.apiUnit.ClaimLeadership()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "unit.ClaimLeadership() (need V1+) not implemented")
+	_, err = s.apiUnit.IsLeader()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = s.apiUnit.LeaderSettings()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = s.apiUnit.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = s.apiUnit.WatchLeaderSettings()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestLeadershipV1(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	isLeader, err := s.apiUnit.IsLeader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(isLeader, jc.IsFalse)
+	isLeader, err = s.apiUnit.ClaimLeadership()
+	c.Assert(err, gc.IsNil)
+	c.Assert(isLeader, jc.IsTrue)
+	isLeader, err = s.apiUnit.IsLeader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(isLeader, jc.IsTrue)
+
+	w, err := s.apiUnit.WatchLeaderSettings()
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	wc.AssertOneChange()
+
+	err = s.apiUnit.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	settings, err := s.apiUnit.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"foo": "bar"})
+
+	// Another unit cannot claim leadership or change the settings.
+	otherUnit, err := s.wordpressService.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = s.wordpressService.ClaimLeadership(otherUnit.Name())
+	c.Assert(errors.Cause(err), gc.Equals, state.ErrLeadershipClaimDenied)
+}
+
+func (s *unitSuite) TestStorageV0NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV0)
+
+	_, err := s.apiUnit.StorageInstances()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "unit.StorageInstances() (need V1+) not implemented")
+	_, err = s.apiUnit.WatchStorageInstances()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = s.apiUnit.StorageDetached("data/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestStorageV1(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	w, err := s.apiUnit.WatchStorageInstances()
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	wc.AssertOneChange()
+
+	data, err := s.wordpressUnit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = data.SetLocation("/dev/sdb", nil)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	instances, err := s.apiUnit.StorageInstances()
+	c.Assert(err, gc.IsNil)
+	c.Assert(instances, jc.DeepEquals, []params.StorageInstance{{
+		Id:         "data/0",
+		Kind:       "block",
+		Life:       params.Alive,
+		Location:   "/dev/sdb",
+		Attributes: map[string]string{},
+	}})
+
+	// The instance can only be detached once it is no longer alive.
+	err = s.apiUnit.StorageDetached("data/0")
+	c.Assert(err, gc.ErrorMatches, `cannot remove storage instance "data/0": storage instance is alive`)
+	err = data.Destroy()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = s.apiUnit.StorageDetached("data/0")
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	instances, err = s.apiUnit.StorageInstances()
+	c.Assert(err, gc.IsNil)
+	c.Assert(instances, gc.HasLen, 0)
+}
+
 func (s *unitSuite) TestIsPrincipal(c *gc.C) {
 	ok, err := s.apiUnit.IsPrincipal()
 	c.Assert(err, gc.IsNil)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 )
@@ -30,6 +31,10 @@ type State struct {
 
 	// charmsURL is the root URL used to fetch charm archives.
 	charmsURL *url.URL
+
+	// password holds the unit's password, with which it downloads
+	// charm archives from the API server's charm store proxy.
+	password string
 }
 
 // newStateForVersion creates a new client-side Uniter facade for the
@@ -68,12 +73,37 @@ func newStateV1(caller base.APICaller, authTag names.UnitTag, charmsURL *url.URL
 // Defined like this to allow patching during tests.
 var NewState = newStateV1
 
+// SetCharmStorePassword sets the password with which the unit
+// authenticates to the API server's charm store proxy.
+func (st *State) SetCharmStorePassword(password string) {
+	st.password = password
+}
+
 // BestAPIVersion returns the API version that we were able to
 // determine is supported by both the client and the API Server.
 func (st *State) BestAPIVersion() int {
 	return st.facade.BestAPIVersion()
 }
 
+// notifyWatcherMuxer is implemented by API connections that can
+// multiplex NotifyWatchers.
+type notifyWatcherMuxer interface {
+	NotifyWatcherMux() *watcher.NotifyWatcherMux
+}
+
+// newNotifyWatcher returns a NotifyWatcher for the given result. As
+// the uniter runs many watchers, they share the connection's
+// NotifyWatcherMux when the API server supports it.
+func (st *State) newNotifyWatcher(result params.NotifyWatchResult) watcher.NotifyWatcher {
+	caller := st.facade.RawAPICaller()
+	if muxer, ok := caller.(notifyWatcherMuxer); ok {
+		if mux := muxer.NotifyWatcherMux(); mux != nil {
+			return mux.NewNotifyWatcher(result)
+		}
+	}
+	return watcher.NewNotifyWatcher(caller, result)
+}
+
 // life requests the lifecycle of the given entity from the server.
 func (st *State) life(tag names.Tag) (params.Life, error) {
 	return common.Life(st.facade, tag)
@@ -191,10 +221,11 @@ func (st *State) Relation(relationTag names.RelationTag) (*Relation, error) {
 		return nil, err
 	}
 	return &Relation{
-		id:   result.Id,
-		tag:  relationTag,
-		life: result.Life,
-		st:   st,
+		id:      result.Id,
+		tag:     relationTag,
+		life:    result.Life,
+		network: result.Network,
+		st:      st,
 	}, nil
 }
 
@@ -258,10 +289,11 @@ func (st *State) RelationById(id int) (*Relation, error) {
 	}
 	relationTag := names.NewRelationTag(result.Key)
 	return &Relation{
-		id:   result.Id,
-		tag:  relationTag,
-		life: result.Life,
-		st:   st,
+		id:      result.Id,
+		tag:     relationTag,
+		life:    result.Life,
+		network: result.Network,
+		st:      st,
 	}, nil
 }
 
@@ -318,6 +350,30 @@ func (st *State) AllMachinePorts(machineTag names.MachineTag) (map[network.PortR
 	return portsMap, nil
 }
 
+// MachineAddresses returns all the addresses of the given machine.
+func (st *State) MachineAddresses(machineTag names.MachineTag) ([]network.Address, error) {
+	if st.BestAPIVersion() < 1 {
+		// MachineAddresses() was introduced in UniterAPIV1.
+		return nil, errors.NotImplementedf("MachineAddresses() (need V1+)")
+	}
+	var results params.MachineAddressesResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: machineTag.String()}},
+	}
+	err := st.facade.FacadeCall("MachineAddresses", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Addresses, nil
+}
+
 // environment1dot16 requests just the UUID of the current environment, when
 // using an older API server that does not support CurrentEnvironment API call.
 func (st *State) environment1dot16() (*Environment, error) {
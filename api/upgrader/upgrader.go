@@ -113,3 +113,49 @@ func (st *State) WatchAPIVersion(agentTag string) (watcher.NotifyWatcher, error)
 	w := watcher.NewNotifyWatcher(st.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// WatchMigrationTarget returns a watcher that notifies when the
+// environment of the agent with the given tag may have been moved to
+// another state server.
+func (st *State) WatchMigrationTarget(agentTag string) (watcher.NotifyWatcher, error) {
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: agentTag}},
+	}
+	err := st.facade.FacadeCall("WatchMigrationTarget", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(st.facade.RawAPICaller(), result)
+	return w, nil
+}
+
+// MigrationTarget returns the API addresses and CA certificate of the
+// state server that the environment of the agent with the given tag
+// has been moved to. The error satisfies params.IsCodeNotFound if the
+// environment has not been moved.
+func (st *State) MigrationTarget(agentTag string) (*params.MigrationTarget, error) {
+	var results params.MigrationTargetResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: agentTag}},
+	}
+	err := st.facade.FacadeCall("MigrationTarget", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
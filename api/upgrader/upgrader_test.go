@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/api/upgrader"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	coretesting "github.com/juju/juju/testing"
@@ -132,6 +133,45 @@ func (s *machineUpgraderSuite) TestWatchAPIVersion(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *machineUpgraderSuite) TestMigrationTarget(c *gc.C) {
+	w, err := s.st.WatchMigrationTarget(s.rawMachine.Tag().String())
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	// Initial event
+	wc.AssertOneChange()
+
+	_, err = s.st.MigrationTarget(s.rawMachine.Tag().String())
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+
+	hostPorts := [][]network.HostPort{
+		network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+	}
+	env, err := s.BackingState.Environment()
+	c.Assert(err, gc.IsNil)
+	err = env.SetMigrationTarget(state.MigrationTarget{
+		APIHostPorts: hostPorts,
+		CACert:       "new-ca-cert",
+	})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	target, err := s.st.MigrationTarget(s.rawMachine.Tag().String())
+	c.Assert(err, gc.IsNil)
+	c.Assert(target, jc.DeepEquals, &params.MigrationTarget{
+		APIHostPorts: hostPorts,
+		CACert:       "new-ca-cert",
+	})
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *machineUpgraderSuite) TestMigrationTargetWrongMachine(c *gc.C) {
+	_, err := s.st.MigrationTarget("machine-42")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
 func (s *machineUpgraderSuite) TestDesiredVersion(c *gc.C) {
 	cur := version.Current
 	curTools := &tools.Tools{Version: cur, URL: ""}
@@ -6,6 +6,7 @@ package usermanager
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -89,6 +90,11 @@ func (c *Client) EnableUser(username string) error {
 	return c.userCall(username, "EnableUser")
 }
 
+// RemoveUser removes a user, along with its access to all environments.
+func (c *Client) RemoveUser(username string) error {
+	return c.userCall(username, "RemoveUser")
+}
+
 // IncludeDisabled is a type alias to avoid bare true/false values
 // in calls to the client method.
 type IncludeDisabled bool
@@ -166,3 +172,35 @@ func (c *Client) SetPassword(username, password string) error {
 	}
 	return results.OneError()
 }
+
+// CreateAPIToken makes a new API token with the given scope ("read" or
+// "write") for the logged in user, valid for the given duration, and
+// returns its id and the token.
+func (c *Client) CreateAPIToken(scope, description string, validFor time.Duration) (id, token string, err error) {
+	args := params.CreateAPIToken{Scope: scope, Description: description, ValidFor: validFor}
+	var result params.CreateAPITokenResult
+	if err := c.facade.FacadeCall("CreateAPIToken", args, &result); err != nil {
+		return "", "", errors.Trace(err)
+	}
+	return result.Id, result.Token, nil
+}
+
+// APITokens returns the API tokens of the logged in user.
+func (c *Client) APITokens() ([]params.APITokenInfo, error) {
+	var result params.APITokensResult
+	if err := c.facade.FacadeCall("APITokens", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Tokens, nil
+}
+
+// RemoveAPIToken revokes the logged in user's API token with the given
+// id.
+func (c *Client) RemoveAPIToken(id string) error {
+	args := params.APITokenIds{Ids: []string{id}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveAPIToken", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -102,6 +102,26 @@ func (s *usermanagerSuite) TestEnableUserBadName(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `"not@home" is not a valid username`)
 }
 
+func (s *usermanagerSuite) TestRemoveUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+
+	err := s.usermanager.RemoveUser(user.Name())
+	c.Assert(err, gc.IsNil)
+
+	err = user.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *usermanagerSuite) TestRemoveUserBadName(c *gc.C) {
+	err := s.usermanager.RemoveUser("not@home")
+	c.Assert(err, gc.ErrorMatches, `"not@home" is not a valid username`)
+}
+
+func (s *usermanagerSuite) TestRemoveAdminUserFails(c *gc.C) {
+	err := s.usermanager.RemoveUser(s.AdminUserTag(c).Name())
+	c.Assert(err, gc.ErrorMatches, `failed to remove user: cannot remove user "admin": cannot remove state server environment owner`)
+}
+
 func (s *usermanagerSuite) TestCantRemoveAdminUser(c *gc.C) {
 	err := s.usermanager.DisableUser(s.AdminUserTag(c).Name())
 	c.Assert(err, gc.ErrorMatches, "failed to disable user: cannot disable state server environment owner")
@@ -0,0 +1,203 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ErrWaitTimeout is returned by Client.WaitFor when the entities
+// being waited for do not reach their goal state in time.
+var ErrWaitTimeout = errors.New("timed out waiting for entities")
+
+// WaitEvent describes a change in the status of an entity being
+// waited for by Client.WaitFor.
+type WaitEvent struct {
+	Kind   string        `json:"kind"`
+	Id     string        `json:"id"`
+	Status params.Status `json:"status"`
+	Info   string        `json:"info,omitempty"`
+	Ready  bool          `json:"ready"`
+}
+
+// WaitFor blocks until all the entities matched by the given patterns
+// have reached their goal state: machines must be provisioned and their
+// agents started, and unit agents must be started with no pending hooks.
+// A pattern is a machine id, a unit name or a service name, which matches
+// all of the service's units; with no patterns, all machines and units in
+// the environment are waited for.
+//
+// If notify is not nil, it is called with each change in the status of
+// a matched entity. An error is returned as soon as any matched entity
+// is in an error state. If timeout is non-zero and the goal state has
+// not been reached when it expires, ErrWaitTimeout is returned.
+func (c *Client) WaitFor(patterns []string, timeout time.Duration, notify func(WaitEvent)) error {
+	watcher, err := c.WatchAll()
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+	expired := make(chan struct{})
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			close(expired)
+			watcher.Stop()
+		})
+		defer timer.Stop()
+	}
+	w := newWaitState(patterns)
+	for initial := true; ; initial = false {
+		deltas, err := watcher.Next()
+		if err != nil {
+			select {
+			case <-expired:
+				return ErrWaitTimeout
+			default:
+			}
+			return err
+		}
+		for _, delta := range deltas {
+			if event, changed := w.update(delta); changed && notify != nil {
+				notify(event)
+			}
+		}
+		// The first set of deltas describes the whole environment,
+		// so any pattern that matches nothing then is a mistake.
+		if initial {
+			if err := w.checkMatched(); err != nil {
+				return err
+			}
+		}
+		if err := w.failed(); err != nil {
+			return err
+		}
+		if w.ready() {
+			return nil
+		}
+	}
+}
+
+// waitState holds the most recent status of the entities being
+// waited for.
+type waitState struct {
+	patterns []string
+	services map[string]bool
+	entities map[params.EntityId]WaitEvent
+}
+
+func newWaitState(patterns []string) *waitState {
+	return &waitState{
+		patterns: patterns,
+		services: make(map[string]bool),
+		entities: make(map[params.EntityId]WaitEvent),
+	}
+}
+
+// update records the change described by the delta, and returns the
+// resulting event and whether the status of a matched entity changed.
+func (w *waitState) update(delta params.Delta) (WaitEvent, bool) {
+	var event WaitEvent
+	switch info := delta.Entity.(type) {
+	case *params.ServiceInfo:
+		if delta.Removed {
+			delete(w.services, info.Name)
+		} else {
+			w.services[info.Name] = true
+		}
+		return event, false
+	case *params.MachineInfo:
+		if !w.matches(info.Id, "") {
+			return event, false
+		}
+		event = WaitEvent{
+			Kind:   "machine",
+			Id:     info.Id,
+			Status: info.Status,
+			Info:   info.StatusInfo,
+			Ready:  info.InstanceId != "" && info.Status == params.StatusStarted,
+		}
+	case *params.UnitInfo:
+		if !w.matches(info.Name, info.Service) {
+			return event, false
+		}
+		event = WaitEvent{
+			Kind:   "unit",
+			Id:     info.Name,
+			Status: info.Status,
+			Info:   info.StatusInfo,
+			Ready:  info.Status == params.StatusStarted,
+		}
+	default:
+		return event, false
+	}
+	id := delta.Entity.EntityId()
+	if delta.Removed {
+		delete(w.entities, id)
+		return event, false
+	}
+	if previous, ok := w.entities[id]; ok && previous == event {
+		return event, false
+	}
+	w.entities[id] = event
+	return event, true
+}
+
+// matches reports whether an entity with the given id, belonging to
+// the given service, is matched by the patterns.
+func (w *waitState) matches(id, service string) bool {
+	if len(w.patterns) == 0 {
+		return true
+	}
+	for _, pattern := range w.patterns {
+		if pattern == id || (service != "" && pattern == service) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMatched returns an error if any pattern does not match a known
+// service or entity.
+func (w *waitState) checkMatched() error {
+	for _, pattern := range w.patterns {
+		if w.services[pattern] {
+			continue
+		}
+		found := false
+		for id := range w.entities {
+			if id.Id == pattern {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("no machines, services or units match %q", pattern)
+		}
+	}
+	return nil
+}
+
+// failed returns an error if any matched entity is in an error state.
+func (w *waitState) failed() error {
+	for _, event := range w.entities {
+		if event.Status == params.StatusError {
+			return errors.Errorf("%s %s is in error: %s", event.Kind, event.Id, event.Info)
+		}
+	}
+	return nil
+}
+
+// ready reports whether all matched entities have reached their goal state.
+func (w *waitState) ready() bool {
+	for _, event := range w.entities {
+		if !event.Ready {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,249 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package watcher
+
+import (
+	"sync"
+
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// NotifyWatcherMux multiplexes the NotifyWatchers of an API
+// connection, so that a single outstanding NotifyWatcherMux.Next
+// call waits for changes on all of them, rather than one
+// NotifyWatcher.Next call per watcher.
+type NotifyWatcherMux struct {
+	caller base.APICaller
+	tomb   tomb.Tomb
+
+	mu       sync.Mutex
+	watchers map[string]*muxNotifyWatcher
+
+	// changed is signalled when the set of watchers changes.
+	changed chan struct{}
+}
+
+// NewNotifyWatcherMux returns a NotifyWatcherMux that makes its calls
+// using the given caller. It stops when Stop is called or when a call
+// fails, taking its watchers with it.
+func NewNotifyWatcherMux(caller base.APICaller) *NotifyWatcherMux {
+	m := &NotifyWatcherMux{
+		caller:   caller,
+		watchers: make(map[string]*muxNotifyWatcher),
+		changed:  make(chan struct{}, 1),
+	}
+	go func() {
+		defer m.tomb.Done()
+		m.tomb.Kill(m.loop())
+		m.killWatchers()
+	}()
+	return m
+}
+
+// Stop stops the NotifyWatcherMux and all its watchers.
+func (m *NotifyWatcherMux) Stop() error {
+	m.tomb.Kill(nil)
+	return m.tomb.Wait()
+}
+
+// NewNotifyWatcher turns the result of an API call returning a
+// NotifyWatchResult into a local NotifyWatcher that waits for
+// changes through the NotifyWatcherMux.
+func (m *NotifyWatcherMux) NewNotifyWatcher(result params.NotifyWatchResult) NotifyWatcher {
+	w := &muxNotifyWatcher{
+		mux:     m,
+		id:      result.NotifyWatcherId,
+		changed: make(chan struct{}, 1),
+		out:     make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	select {
+	case <-m.tomb.Dying():
+		w.tomb.Kill(m.tomb.Err())
+		return w
+	default:
+	}
+	m.watchers[w.id] = w
+	m.signalChanged()
+	return w
+}
+
+// remove stops multiplexing the watcher with the given id.
+func (m *NotifyWatcherMux) remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.watchers, id)
+	m.signalChanged()
+}
+
+// signalChanged notes that the set of watchers has changed. It must
+// be called with m.mu held.
+func (m *NotifyWatcherMux) signalChanged() {
+	select {
+	case m.changed <- struct{}{}:
+	default:
+	}
+}
+
+type nextResult struct {
+	changes params.NotifyWatcherChanges
+	err     error
+}
+
+func (m *NotifyWatcherMux) loop() error {
+	version := m.caller.BestFacadeVersion("NotifyWatcherMux")
+	results := make(chan nextResult)
+	inflight := false
+	for {
+		if !inflight {
+			if ids := m.ids(); len(ids) > 0 {
+				inflight = true
+				go func() {
+					var r nextResult
+					args := params.NotifyWatcherIds{NotifyWatcherIds: ids}
+					r.err = m.caller.APICall("NotifyWatcherMux", version, "", "Next", args, &r.changes)
+					results <- r
+				}()
+			}
+		}
+		select {
+		case <-m.tomb.Dying():
+			if inflight {
+				m.interrupt(version)
+				<-results
+			}
+			return tomb.ErrDying
+		case <-m.changed:
+			// Make the outstanding call return, so that
+			// the next one waits on the new set of watchers.
+			if inflight {
+				m.interrupt(version)
+			}
+		case r := <-results:
+			inflight = false
+			if r.err != nil {
+				return r.err
+			}
+			m.deliver(r.changes)
+		}
+	}
+}
+
+// interrupt makes any outstanding Next call return.
+func (m *NotifyWatcherMux) interrupt(version int) {
+	if err := m.caller.APICall("NotifyWatcherMux", version, "", "Interrupt", nil, nil); err != nil {
+		logger.Debugf("cannot interrupt NotifyWatcherMux: %v", err)
+	}
+}
+
+// ids returns the ids of the multiplexed watchers.
+func (m *NotifyWatcherMux) ids() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]string, 0, len(m.watchers))
+	for id := range m.watchers {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// deliver notifies the watchers that have changed, and kills those
+// that have failed.
+func (m *NotifyWatcherMux) deliver(changes params.NotifyWatcherChanges) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, change := range changes.Changes {
+		w, ok := m.watchers[change.Id]
+		if !ok {
+			continue
+		}
+		if change.Error != nil {
+			delete(m.watchers, change.Id)
+			w.tomb.Kill(change.Error)
+			continue
+		}
+		select {
+		case w.changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// killWatchers kills all the multiplexed watchers with the error
+// that killed the NotifyWatcherMux.
+func (m *NotifyWatcherMux) killWatchers() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err := m.tomb.Err()
+	if err == tomb.ErrStillAlive {
+		err = nil
+	}
+	for id, w := range m.watchers {
+		w.tomb.Kill(err)
+		delete(m.watchers, id)
+	}
+}
+
+// muxNotifyWatcher is a NotifyWatcher whose changes are waited
+// for by a NotifyWatcherMux.
+type muxNotifyWatcher struct {
+	mux      *NotifyWatcherMux
+	id       string
+	tomb     tomb.Tomb
+	changed  chan struct{}
+	out      chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *muxNotifyWatcher) loop() error {
+	for {
+		// Send the initial event, then one after each change.
+		select {
+		case w.out <- struct{}{}:
+		case <-w.tomb.Dying():
+			return nil
+		}
+		select {
+		case <-w.changed:
+		case <-w.tomb.Dying():
+			return nil
+		}
+	}
+}
+
+// Changes returns a channel that receives a value when the watched
+// entity changes in some way.
+func (w *muxNotifyWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+// Stop stops the watcher, and the watcher on the server.
+func (w *muxNotifyWatcher) Stop() error {
+	w.tomb.Kill(nil)
+	err := w.tomb.Wait()
+	w.stopOnce.Do(func() {
+		w.mux.remove(w.id)
+		caller := w.mux.caller
+		version := caller.BestFacadeVersion("NotifyWatcher")
+		if err := caller.APICall("NotifyWatcher", version, w.id, "Stop", nil, nil); err != nil {
+			logger.Errorf("error trying to stop watcher: %v", err)
+		}
+	})
+	return err
+}
+
+// Err returns any error encountered while running or shutting down,
+// or tomb.ErrStillAlive if the watcher is still running.
+func (w *muxNotifyWatcher) Err() error {
+	return w.tomb.Err()
+}
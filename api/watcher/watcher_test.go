@@ -100,6 +100,38 @@ func (s *watcherSuite) TestNotifyWatcherStopsWithPendingSend(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *watcherSuite) TestNotifyWatcherMux(c *gc.C) {
+	mux := watcher.NewNotifyWatcherMux(s.stateAPI)
+	defer func() {
+		c.Assert(mux.Stop(), gc.IsNil)
+	}()
+
+	// Watch the machine twice through the same NotifyWatcherMux.
+	var wcs []statetesting.NotifyWatcherC
+	for i := 0; i < 2; i++ {
+		var results params.NotifyWatchResults
+		args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+		err := s.stateAPI.APICall("Machiner", s.stateAPI.BestFacadeVersion("Machiner"), "", "Watch", args, &results)
+		c.Assert(err, gc.IsNil)
+		c.Assert(results.Results, gc.HasLen, 1)
+		result := results.Results[0]
+		c.Assert(result.Error, gc.IsNil)
+
+		w := mux.NewNotifyWatcher(result)
+		defer statetesting.AssertStop(c, w)
+		wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+		wc.AssertOneChange()
+		wcs = append(wcs, wc)
+	}
+
+	// A change is delivered to both watchers.
+	err := s.rawMachine.Destroy()
+	c.Assert(err, gc.IsNil)
+	for _, wc := range wcs {
+		wc.AssertOneChange()
+	}
+}
+
 func (s *watcherSuite) TestWatchUnitsKeepsEvents(c *gc.C) {
 	// Create two services, relate them, and add one unit to each - a
 	// principal and a subordinate.
@@ -4,6 +4,7 @@
 package actions
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 
@@ -132,6 +133,53 @@ func (a *ActionsAPI) Cancel(arg params.ActionTags) (params.ActionResults, error)
 	return response, nil
 }
 
+// Actions takes a list of ActionTags, and returns the full Action for
+// each ID, whether it is still queued or has already been run.
+func (a *ActionsAPI) Actions(arg params.ActionTags) (params.ActionResults, error) {
+	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
+	for i, tag := range arg.Actions {
+		current := &response.Results[i]
+		receiver, err := tagToActionReceiver(a.state, tag.PrefixTag())
+		if err != nil {
+			current.Error = common.ServerError(err)
+			continue
+		}
+
+		action, err := a.state.ActionByTag(tag)
+		if err == nil {
+			current.Action = &params.Action{
+				Tag:        tag,
+				Receiver:   receiver.Tag(),
+				Name:       action.Name(),
+				Parameters: action.Parameters(),
+			}
+			current.Status = string(state.ActionPending)
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			current.Error = common.ServerError(err)
+			continue
+		}
+
+		result, err := a.state.ActionResultByTag(tag)
+		if err != nil {
+			current.Error = common.ServerError(err)
+			continue
+		}
+		current.Action = &params.Action{
+			Tag:        tag,
+			Receiver:   receiver.Tag(),
+			Name:       result.Name(),
+			Parameters: result.Parameters(),
+		}
+		current.Status = string(result.Status())
+		output, message := result.Results()
+		current.Message = message
+		current.Output = output
+	}
+	return response, nil
+}
+
 // ServicesCharmActions returns a slice of charm Actions for a slice of services.
 func (a *ActionsAPI) ServicesCharmActions(args params.ServiceTags) (params.ServicesCharmActionsResults, error) {
 	result := params.ServicesCharmActionsResults{}
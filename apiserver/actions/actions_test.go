@@ -491,6 +491,47 @@ func (s *actionsSuite) TestCancel(c *gc.C) {
 
 }
 
+func (s *actionsSuite) TestActions(c *gc.C) {
+	pending, err := s.wordpressUnit.AddAction("backup", map[string]interface{}{"outfile": "foo.bz2"})
+	c.Assert(err, gc.IsNil)
+	done, err := s.mysqlUnit.AddAction("snapshot", map[string]interface{}{"outfile": "bar.bz2"})
+	c.Assert(err, gc.IsNil)
+	output := map[string]interface{}{"outfile": "bar.bz2"}
+	_, err = done.Finish(state.ActionResults{state.ActionCompleted, output, "all done"})
+	c.Assert(err, gc.IsNil)
+
+	arg := params.ActionTags{Actions: []names.ActionTag{
+		pending.ActionTag(),
+		done.ActionTag(),
+		names.JoinActionTag(s.mysqlUnit.Name(), 42),
+	}}
+	results, err := s.actions.Actions(arg)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+
+	c.Check(results.Results[0], jc.DeepEquals, params.ActionResult{
+		Action: &params.Action{
+			Tag:        pending.ActionTag(),
+			Receiver:   s.wordpressUnit.Tag(),
+			Name:       "backup",
+			Parameters: map[string]interface{}{"outfile": "foo.bz2"},
+		},
+		Status: params.ActionPending,
+	})
+	c.Check(results.Results[1], jc.DeepEquals, params.ActionResult{
+		Action: &params.Action{
+			Tag:        done.ActionTag(),
+			Receiver:   s.mysqlUnit.Tag(),
+			Name:       "snapshot",
+			Parameters: map[string]interface{}{"outfile": "bar.bz2"},
+		},
+		Status:  params.ActionCompleted,
+		Message: "all done",
+		Output:  output,
+	})
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `action result "mysql/0_ar_42" not found`)
+}
+
 func (s *actionsSuite) TestServicesCharmActions(c *gc.C) {
 	actionSchemas := map[string]map[string]interface{}{
 		"outfile": map[string]interface{}{
@@ -15,7 +15,6 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
-	"github.com/juju/juju/state/presence"
 )
 
 type adminApiFactory func(srv *Server, root *apiHandler, reqNotifier *requestNotifier) interface{}
@@ -105,7 +104,7 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 	}
 
 	// authedApi is the API method finder we'll use after getting logged in.
-	var authedApi rpc.MethodFinder = newApiRoot(a.srv, a.root.resources, a.root)
+	var authedApi rpc.MethodFinder = newApiRoot(a.root.state, a.root.resources, a.root)
 
 	// Use the login validation function, if one was specified.
 	if a.srv.validator != nil {
@@ -136,7 +135,7 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 		isUser = true
 	}
 
-	entity, err := doCheckCreds(a.srv.state, req)
+	entity, err := doCheckCreds(a.root.state, req)
 	if err != nil {
 		if a.maintenanceInProgress() {
 			// An upgrade, restore or similar operation is in
@@ -150,6 +149,22 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 		}
 		return fail, err
 	}
+	if readOnly, err := hasReadOnlyAccess(a.root.state, entity, req.Credentials); err != nil {
+		return fail, err
+	} else if readOnly {
+		authedApi = newReadOnlyRoot(authedApi)
+	}
+	if id, _, isToken := state.ParseAPIToken(req.Credentials); isToken {
+		st := a.root.state
+		authedApi = newTokenRoot(authedApi, func() error {
+			if err := st.CheckAPIToken(id); errors.IsUnauthorized(err) {
+				return common.ErrBadCreds
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			return nil
+		})
+	}
 	a.root.entity = entity
 
 	if a.reqNotifier != nil {
@@ -234,14 +249,20 @@ func checkCreds(st *state.State, req params.LoginRequest) (state.Entity, error)
 		return nil, errors.Trace(err)
 	}
 
-	authenticator, err := authentication.FindEntityAuthenticator(entity)
-	if err != nil {
-		return nil, err
-	}
+	if _, _, isToken := state.ParseAPIToken(req.Credentials); isToken {
+		if err := checkAPIToken(st, entity, req.Credentials); err != nil {
+			return nil, err
+		}
+	} else {
+		authenticator, err := authentication.FindEntityAuthenticator(entity)
+		if err != nil {
+			return nil, err
+		}
 
-	if err = authenticator.Authenticate(entity, req.Credentials, req.Nonce); err != nil {
-		logger.Debugf("bad credentials")
-		return nil, err
+		if err = authenticator.Authenticate(entity, req.Credentials, req.Nonce); err != nil {
+			logger.Debugf("bad credentials")
+			return nil, err
+		}
 	}
 
 	// For user logins, ensure the user is allowed to access the environment.
@@ -255,6 +276,46 @@ func checkCreds(st *state.State, req params.LoginRequest) (state.Entity, error)
 	return entity, nil
 }
 
+// checkAPIToken checks that token is a valid API token of entity,
+// which must be an enabled user.
+func checkAPIToken(st *state.State, entity state.Entity, token string) error {
+	user, ok := entity.(*state.User)
+	if !ok || user.IsDisabled() {
+		return common.ErrBadCreds
+	}
+	apiToken, err := st.AuthenticateAPIToken(token)
+	if errors.IsUnauthorized(err) || (err == nil && apiToken.UserTag() != user.UserTag()) {
+		logger.Debugf("bad API token")
+		return common.ErrBadCreds
+	}
+	return errors.Trace(err)
+}
+
+// hasReadOnlyAccess reports whether entity is a user that has only
+// been granted read access to the environment, or that logged in with
+// the given credentials as a read-scoped API token.
+func hasReadOnlyAccess(st *state.State, entity state.Entity, credentials string) (bool, error) {
+	user, ok := entity.Tag().(names.UserTag)
+	if !ok {
+		return false, nil
+	}
+	envUser, err := st.EnvironmentUser(user)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if envUser.Access() == state.EnvironmentReadAccess {
+		return true, nil
+	}
+	if id, _, isToken := state.ParseAPIToken(credentials); isToken {
+		token, err := st.APIToken(id)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		return token.Scope() == state.EnvironmentReadAccess, nil
+	}
+	return false, nil
+}
+
 func getAndUpdateLastLoginForEntity(entity state.Entity) *time.Time {
 	if user, ok := entity.(*state.User); ok {
 		result := user.LastLogin()
@@ -276,18 +337,10 @@ func checkForValidMachineAgent(entity state.Entity, req params.LoginRequest) err
 	return nil
 }
 
-// machinePinger wraps a presence.Pinger.
-type machinePinger struct {
-	*presence.Pinger
-}
-
-// Stop implements Pinger.Stop() as Pinger.Kill(), needed at
-// connection closing time to properly stop the wrapped pinger.
-func (p *machinePinger) Stop() error {
-	if err := p.Pinger.Stop(); err != nil {
-		return err
-	}
-	return p.Pinger.Kill()
+// agentPresenceStarter is implemented by entities whose agents
+// report their presence while they are connected.
+type agentPresenceStarter interface {
+	StartAgentPresence() (*state.AgentPresence, error)
 }
 
 func startPingerIfAgent(root *apiHandler, entity state.Entity) error {
@@ -295,17 +348,21 @@ func startPingerIfAgent(root *apiHandler, entity state.Entity) error {
 	// announce it's now alive, and set up the API pinger
 	// so that the connection will be terminated if a sufficient
 	// interval passes between pings.
-	agentPresencer, ok := entity.(presence.Presencer)
+	//
+	// The presence of all the agents on a machine is reported by
+	// a single pinger; stopping the resource when the connection
+	// closes immediately reports that this agent is dead.
+	agentPresencer, ok := entity.(agentPresenceStarter)
 	if !ok {
 		return nil
 	}
 
-	pinger, err := agentPresencer.SetAgentPresence()
+	agentPresence, err := agentPresencer.StartAgentPresence()
 	if err != nil {
 		return err
 	}
 
-	root.getResources().Register(&machinePinger{pinger})
+	root.getResources().Register(agentPresence)
 	action := func() {
 		if err := root.getRpcConn().Close(); err != nil {
 			logger.Errorf("error closing the RPC connection: %v", err)
@@ -533,6 +533,128 @@ func (s *loginSuite) TestNonEnvironUserLoginFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
 }
 
+func (s *loginSuite) TestReadOnlyUserLogin(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password"})
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	err = envUser.SetAccess(state.EnvironmentReadAccess)
+	c.Assert(err, gc.IsNil)
+
+	info.Password = "dummy-password"
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	// Calls that only read the environment are allowed.
+	var statusResult api.Status
+	err = st.APICall("Client", 0, "", "FullStatus", params.StatusParams{}, &statusResult)
+	c.Assert(err, gc.IsNil)
+
+	// Calls that change it are not.
+	err = st.APICall("Client", 0, "", "DestroyEnvironment", nil, nil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(params.IsCodeUnauthorized(err), jc.IsTrue)
+}
+
+func (s *loginSuite) TestReadTokenLogin(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password"})
+	_, token, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentReadAccess, "ci", time.Hour)
+	c.Assert(err, gc.IsNil)
+
+	info.Password = token
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	// Calls that only read the environment are allowed.
+	var statusResult api.Status
+	err = st.APICall("Client", 0, "", "FullStatus", params.StatusParams{}, &statusResult)
+	c.Assert(err, gc.IsNil)
+
+	// Calls that change it are not, although the user has write access.
+	err = st.APICall("Client", 0, "", "DestroyEnvironment", nil, nil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	// Nor may the token change the user's password.
+	args := params.EntityPasswords{
+		Changes: []params.EntityPassword{{Tag: user.Tag().String(), Password: "new"}},
+	}
+	err = st.APICall("UserManager", 0, "", "SetPassword", args, nil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loginSuite) TestWriteTokenLogin(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password"})
+	_, token, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentWriteAccess, "ci", time.Hour)
+	c.Assert(err, gc.IsNil)
+
+	info.Password = token
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	err = st.APICall("Client", 0, "", "EnvironmentSet", params.EnvironmentSet{
+		Config: map[string]interface{}{"some-key": "value"},
+	}, nil)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *loginSuite) TestRevokedTokenEndsSession(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password"})
+	apiToken, token, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentReadAccess, "ci", time.Hour)
+	c.Assert(err, gc.IsNil)
+
+	info.Password = token
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	var statusResult api.Status
+	err = st.APICall("Client", 0, "", "FullStatus", params.StatusParams{}, &statusResult)
+	c.Assert(err, gc.IsNil)
+
+	// Once the token is revoked, the connection made with it can make
+	// no further calls.
+	err = apiToken.Remove()
+	c.Assert(err, gc.IsNil)
+	err = st.APICall("Client", 0, "", "FullStatus", params.StatusParams{}, &statusResult)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginSuite) TestBadTokenLoginFails(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password"})
+	other := s.Factory.MakeUser(c, &factory.UserParams{Name: "other"})
+	apiToken, token, err := s.State.AddAPIToken(other.UserTag(), state.EnvironmentWriteAccess, "", time.Hour)
+	c.Assert(err, gc.IsNil)
+
+	// Another user's token is refused.
+	info.Password = token
+	info.Tag = user.UserTag()
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+
+	// As is a revoked one.
+	err = apiToken.Remove()
+	c.Assert(err, gc.IsNil)
+	info.Tag = other.UserTag()
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
 func (s *loginV0Suite) TestLoginReportsEnvironTag(c *gc.C) {
 	st, cleanup := s.setupServer(c)
 	defer cleanup()
@@ -10,17 +10,24 @@ import (
 	_ "github.com/juju/juju/apiserver/actions"
 	_ "github.com/juju/juju/apiserver/agent"
 	_ "github.com/juju/juju/apiserver/backups"
+	_ "github.com/juju/juju/apiserver/bundles"
 	_ "github.com/juju/juju/apiserver/charmrevisionupdater"
+	_ "github.com/juju/juju/apiserver/charms"
+	_ "github.com/juju/juju/apiserver/cleanups"
 	_ "github.com/juju/juju/apiserver/client"
+	_ "github.com/juju/juju/apiserver/crossenvrelations"
 	_ "github.com/juju/juju/apiserver/deployer"
 	_ "github.com/juju/juju/apiserver/environment"
+	_ "github.com/juju/juju/apiserver/environmentmanager"
 	_ "github.com/juju/juju/apiserver/firewaller"
 	_ "github.com/juju/juju/apiserver/keymanager"
 	_ "github.com/juju/juju/apiserver/keyupdater"
 	_ "github.com/juju/juju/apiserver/logger"
 	_ "github.com/juju/juju/apiserver/machine"
+	_ "github.com/juju/juju/apiserver/maintenance"
 	_ "github.com/juju/juju/apiserver/metricsmanager"
 	_ "github.com/juju/juju/apiserver/networker"
+	_ "github.com/juju/juju/apiserver/portforwarder"
 	_ "github.com/juju/juju/apiserver/provisioner"
 	_ "github.com/juju/juju/apiserver/reboot"
 	_ "github.com/juju/juju/apiserver/rsyslog"
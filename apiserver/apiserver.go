@@ -14,15 +14,20 @@ import (
 
 	"code.google.com/p/go.net/websocket"
 	"github.com/bmizerany/pat"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 	"github.com/juju/utils"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/featureflag"
 )
 
 var logger = loggo.GetLogger("juju.apiserver")
@@ -43,8 +48,12 @@ type Server struct {
 	validator         LoginValidator
 	adminApiFactories map[int]adminApiFactory
 
-	mu          sync.Mutex // protects the fields that follow
-	environUUID string
+	mu sync.Mutex // protects the fields that follow
+
+	// envStates holds the States opened for API connections to
+	// environments hosted by the state server, other than its own,
+	// keyed by environment UUID.
+	envStates map[string]*state.State
 }
 
 // LoginValidator functions are used to decide whether login requests
@@ -164,10 +173,12 @@ func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body int
 }
 
 func (n *requestNotifier) join(req *http.Request) {
+	apiMetrics.connectionOpened()
 	logger.Infof("[%X] API connection from %s", n.id, req.RemoteAddr)
 }
 
 func (n *requestNotifier) leave() {
+	apiMetrics.connectionClosed()
 	logger.Infof("[%X] %s API connection terminated after %v", n.id, n.tag(), time.Since(n.start))
 }
 
@@ -188,6 +199,7 @@ func handleAll(mux *pat.PatternServeMux, pattern string, handler http.Handler) {
 
 func (srv *Server) run(lis net.Listener) {
 	defer srv.tomb.Done()
+	defer srv.closeEnvironStates()
 	defer srv.wg.Wait() // wait for any outstanding requests to complete.
 	srv.wg.Add(1)
 	go func() {
@@ -206,57 +218,160 @@ func (srv *Server) run(lis net.Listener) {
 	// registered first.
 	mux := pat.New()
 	// For backwards compatibility we register all the old paths
-	handleAll(mux, "/environment/:envuuid/log",
-		&debugLogHandler{
-			httpHandler: httpHandler{state: srv.state},
-			logDir:      srv.logDir},
-	)
-	handleAll(mux, "/environment/:envuuid/charms",
-		&charmsHandler{
-			httpHandler: httpHandler{state: srv.state},
-			dataDir:     srv.dataDir},
-	)
+	handleAll(mux, "/environment/:envuuid/log", srv.debugLogEndpoint())
+	handleAll(mux, "/environment/:envuuid/charms", srv.charmsEndpoint())
+	handleAll(mux, "/environment/:envuuid/charmstore", srv.charmStoreEndpoint())
 	// TODO: We can switch from handleAll to mux.Post/Get/etc for entries
 	// where we only want to support specific request methods. However, our
 	// tests currently assert that errors come back as application/json and
 	// pat only does "text/plain" responses.
-	handleAll(mux, "/environment/:envuuid/tools",
-		&toolsUploadHandler{toolsHandler{
-			httpHandler{state: srv.state},
-		}},
-	)
-	handleAll(mux, "/environment/:envuuid/tools/:version",
-		&toolsDownloadHandler{toolsHandler{
-			httpHandler{state: srv.state},
-		}},
-	)
+	handleAll(mux, "/environment/:envuuid/tools", srv.toolsUploadEndpoint())
+	handleAll(mux, "/environment/:envuuid/tools/:version", srv.toolsDownloadEndpoint())
+	handleAll(mux, "/environment/:envuuid/introspection/txn", srv.txnMetricsEndpoint())
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
 	// For backwards compatibility we register all the old paths
-	handleAll(mux, "/log",
-		&debugLogHandler{
-			httpHandler: httpHandler{state: srv.state},
-			logDir:      srv.logDir},
-	)
-	handleAll(mux, "/charms",
-		&charmsHandler{
-			httpHandler: httpHandler{state: srv.state},
-			dataDir:     srv.dataDir},
-	)
-	handleAll(mux, "/tools",
-		&toolsUploadHandler{toolsHandler{
-			httpHandler{state: srv.state},
-		}},
-	)
-	handleAll(mux, "/tools/:version",
-		&toolsDownloadHandler{toolsHandler{
-			httpHandler{state: srv.state},
-		}},
-	)
+	handleAll(mux, "/log", srv.debugLogEndpoint())
+	handleAll(mux, "/charms", srv.charmsEndpoint())
+	handleAll(mux, "/charmstore", srv.charmStoreEndpoint())
+	handleAll(mux, "/tools", srv.toolsUploadEndpoint())
+	handleAll(mux, "/tools/:version", srv.toolsDownloadEndpoint())
+	handleAll(mux, "/introspection/txn", srv.txnMetricsEndpoint())
+	handleAll(mux, "/metrics", srv.metricsEndpoint())
+	handleAll(mux, "/health", &healthHandler{
+		session: srv.state.MongoSession(),
+		dying:   srv.tomb.Dying(),
+	})
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
 	// The error from http.Serve is not interesting.
 	http.Serve(lis, mux)
 }
 
+// debugLogEndpoint returns the handler streaming the environment's
+// log over a websocket.
+func (srv *Server) debugLogEndpoint() http.Handler {
+	h := &debugLogHandler{
+		httpHandler: httpHandler{state: srv.state},
+		logDir:      srv.logDir,
+	}
+	return &httpEndpoint{
+		httpHandler: h.httpHandler,
+		websocket:   true,
+		handler:     h,
+	}
+}
+
+// maxCharmUploadSize returns the largest charm archive, in bytes,
+// that may be uploaded. The environment configuration is read for each
+// upload, so that changes to max-charm-upload-size apply immediately.
+func (srv *Server) maxCharmUploadSize() int64 {
+	return srv.maxUploadSize((*config.Config).MaxCharmUploadSize, defaultMaxCharmUploadSize)
+}
+
+// maxToolsUploadSize returns the largest tools tarball, in bytes, that
+// may be uploaded. The environment configuration is read for each
+// upload, so that changes to max-tools-upload-size apply immediately.
+func (srv *Server) maxToolsUploadSize() int64 {
+	return srv.maxUploadSize((*config.Config).MaxToolsUploadSize, defaultMaxToolsUploadSize)
+}
+
+func (srv *Server) maxUploadSize(get func(*config.Config) int64, defaultSize int64) int64 {
+	cfg, err := srv.state.EnvironConfig()
+	if err != nil {
+		logger.Warningf("cannot read upload limit, using default of %d bytes: %v", defaultSize, err)
+		return defaultSize
+	}
+	if size := get(cfg); size > 0 {
+		return size
+	}
+	return defaultSize
+}
+
+// charmsEndpoint returns the handler for charm uploads and downloads.
+func (srv *Server) charmsEndpoint() http.Handler {
+	h := &charmsHandler{
+		httpHandler: httpHandler{state: srv.state},
+		dataDir:     srv.dataDir,
+	}
+	return &httpEndpoint{
+		httpHandler: h.httpHandler,
+		sender:      h,
+		authMethods: []string{"POST"},
+		maxBodySize: srv.maxCharmUploadSize,
+		handler:     h,
+	}
+}
+
+// charmStoreEndpoint returns the handler for charm store downloads
+// proxied through the API server. Unit agents may use it to fetch
+// the charms they deploy.
+func (srv *Server) charmStoreEndpoint() http.Handler {
+	h := &charmStoreHandler{charmsHandler{
+		httpHandler: httpHandler{state: srv.state},
+		dataDir:     srv.dataDir,
+	}}
+	return &httpEndpoint{
+		httpHandler: h.httpHandler,
+		sender:      h,
+		authMethods: []string{"*"},
+		allowAgents: true,
+		handler:     h,
+	}
+}
+
+// toolsUploadEndpoint returns the handler for tools uploads.
+func (srv *Server) toolsUploadEndpoint() http.Handler {
+	h := &toolsUploadHandler{toolsHandler{
+		httpHandler{state: srv.state},
+	}}
+	return &httpEndpoint{
+		httpHandler: h.httpHandler,
+		sender:      h,
+		authMethods: []string{"*"},
+		maxBodySize: srv.maxToolsUploadSize,
+		handler:     h,
+	}
+}
+
+// toolsDownloadEndpoint returns the handler for tools downloads,
+// which agents use before they have credentials.
+func (srv *Server) toolsDownloadEndpoint() http.Handler {
+	h := &toolsDownloadHandler{toolsHandler{
+		httpHandler{state: srv.state},
+	}}
+	return &httpEndpoint{
+		httpHandler: h.httpHandler,
+		sender:      h,
+		handler:     h,
+	}
+}
+
+// txnMetricsEndpoint returns the handler reporting the state
+// server's transaction metrics.
+func (srv *Server) txnMetricsEndpoint() http.Handler {
+	h := &txnMetricsHandler{httpHandler{state: srv.state}}
+	return &httpEndpoint{
+		httpHandler: h.httpHandler,
+		sender:      h,
+		authMethods: []string{"*"},
+		handler:     h,
+	}
+}
+
+// metricsEndpoint returns the handler for the state server's metrics.
+func (srv *Server) metricsEndpoint() http.Handler {
+	h := &metricsHandler{
+		httpHandler: httpHandler{state: srv.state},
+		metrics:     apiMetrics,
+		logDir:      srv.logDir,
+	}
+	return &httpEndpoint{
+		httpHandler: h.httpHandler,
+		sender:      h,
+		authMethods: []string{"*"},
+		handler:     h,
+	}
+}
+
 func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 	reqNotifier := newRequestNotifier()
 	reqNotifier.join(req)
@@ -287,48 +402,63 @@ func (srv *Server) Addr() string {
 	return srv.addr
 }
 
-func (srv *Server) validateEnvironUUID(envUUID string) error {
-	if envUUID == "" {
-		// We allow the environUUID to be empty for 2 cases
-		// 1) Compatibility with older clients
-		// 2) On first connect. The environment UUID is currently
-		//    generated by 'jujud bootstrap-state', and we haven't
-		//    threaded that information all the way back to the 'juju
-		//    bootstrap' process to be able to cache the value until
-		//    after we've connected one time.
-		return nil
-	}
-	if srv.getEnvironUUID() == "" {
-		env, err := srv.state.Environment()
-		if err != nil {
-			return err
-		}
-		srv.setEnvironUUID(env.UUID())
+// stateForEnviron returns the State that serves API connections to the
+// environment with the given UUID. Connections that name no environment
+// are served by the state server's own environment, for compatibility
+// with older clients and because "juju bootstrap" does not know the
+// environment UUID until after it first connects. Each other
+// environment hosted by the state server has a single State, opened
+// when it is first needed and shared by all its connections; they are
+// only served when the "jes" feature flag is set.
+func (srv *Server) stateForEnviron(envUUID string) (*state.State, error) {
+	if envUUID == "" || envUUID == srv.state.EnvironTag().Id() {
+		return srv.state, nil
 	}
-	return srv.checkEnvironUUID(envUUID)
-}
-
-// checkEnvironUUID checks if the expected envionUUID matches the
-// current environUUID set on this Server. It returns nil for a match
-// and an error on mismatch.
-func (srv *Server) checkEnvironUUID(expected string) error {
-	actual := srv.getEnvironUUID()
-	if actual != expected {
-		return common.UnknownEnvironmentError(expected)
+	if !featureflag.Enabled(feature.JES) {
+		// Hosted environments are not yet fully isolated from one
+		// another, so they are only served when asked for.
+		return nil, common.UnknownEnvironmentError(envUUID)
 	}
-	return nil
-}
-
-func (srv *Server) getEnvironUUID() string {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
-	return srv.environUUID
+	if st, ok := srv.envStates[envUUID]; ok {
+		return st, nil
+	}
+	if !names.IsValidEnvironment(envUUID) {
+		return nil, common.UnknownEnvironmentError(envUUID)
+	}
+	envTag := names.NewEnvironTag(envUUID)
+	env, err := srv.state.GetEnvironment(envTag)
+	if errors.IsNotFound(err) {
+		return nil, common.UnknownEnvironmentError(envUUID)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if env.ServerTag() != srv.state.EnvironTag() {
+		// The environment is known, but hosted elsewhere.
+		return nil, common.UnknownEnvironmentError(envUUID)
+	}
+	st, err := srv.state.ForEnviron(envTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if srv.envStates == nil {
+		srv.envStates = make(map[string]*state.State)
+	}
+	srv.envStates[envUUID] = st
+	return st, nil
 }
 
-func (srv *Server) setEnvironUUID(uuid string) {
+// closeEnvironStates closes the States opened by stateForEnviron.
+func (srv *Server) closeEnvironStates() {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
-	srv.environUUID = uuid
+	for uuid, st := range srv.envStates {
+		if err := st.Close(); err != nil {
+			logger.Warningf("error closing state for environment %q: %v", uuid, err)
+		}
+	}
+	srv.envStates = nil
 }
 
 func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifier, envUUID string) error {
@@ -336,18 +466,18 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
-	var notifier rpc.RequestNotifier
+	var debugNotifier rpc.RequestNotifier
 	if logger.EffectiveLogLevel() <= loggo.DEBUG {
-		// Incur request monitoring overhead only if we
+		// Incur request logging overhead only if we
 		// know we'll need it.
-		notifier = reqNotifier
+		debugNotifier = reqNotifier
 	}
-	conn := rpc.NewConn(codec, notifier)
+	conn := rpc.NewConn(codec, &metricsNotifier{apiMetrics, debugNotifier})
 
-	var err error
 	var h *apiHandler
-	if err = srv.validateEnvironUUID(envUUID); err == nil {
-		h, err = newApiHandler(srv, conn, reqNotifier)
+	st, err := srv.stateForEnviron(envUUID)
+	if err == nil {
+		h, err = newApiHandler(srv, st, conn, reqNotifier)
 	}
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
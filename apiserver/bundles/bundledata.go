@@ -0,0 +1,102 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundles
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// bundleData holds the contents of a bundle.yaml file.
+type bundleData struct {
+	// Series holds the default series for charm references that
+	// do not specify one.
+	Series string `yaml:"series"`
+
+	// Services holds the services to deploy, by name.
+	Services map[string]*serviceSpec `yaml:"services"`
+
+	// Relations holds the relations to add, each given as a pair
+	// of endpoints in the form "service" or "service:relation".
+	Relations [][]string `yaml:"relations"`
+}
+
+// serviceSpec describes a service in a bundle.
+type serviceSpec struct {
+	// Charm holds the charm store reference of the service's charm.
+	Charm string `yaml:"charm"`
+
+	// NumUnits holds the number of units to add to the service.
+	NumUnits int `yaml:"num_units"`
+
+	// To holds placement directives for the service's units, as
+	// accepted by "juju add-unit --to", one per unit. Units beyond
+	// the end of the list are placed on new machines.
+	To []string `yaml:"to"`
+
+	// Options holds the service's configuration settings.
+	Options map[string]interface{} `yaml:"options"`
+
+	// Constraints holds the service's constraints.
+	Constraints string `yaml:"constraints"`
+
+	// Expose holds whether the service is exposed.
+	Expose bool `yaml:"expose"`
+}
+
+// parseBundle parses the given bundle.yaml contents and checks that
+// the bundle is well formed.
+func parseBundle(data string) (*bundleData, error) {
+	var bundle bundleData
+	if err := goyaml.Unmarshal([]byte(data), &bundle); err != nil {
+		return nil, errors.Annotate(err, "cannot parse bundle")
+	}
+	if len(bundle.Services) == 0 {
+		return nil, errors.New("bundle has no services")
+	}
+	for name, spec := range bundle.Services {
+		if !names.IsValidService(name) {
+			return nil, errors.Errorf("invalid service name %q", name)
+		}
+		if spec == nil || spec.Charm == "" {
+			return nil, errors.Errorf("no charm specified for service %q", name)
+		}
+		if spec.NumUnits < 0 {
+			return nil, errors.Errorf("negative number of units specified for service %q", name)
+		}
+		if len(spec.To) > spec.NumUnits {
+			return nil, errors.Errorf("too many placement directives for service %q", name)
+		}
+	}
+	for _, endpoints := range bundle.Relations {
+		if len(endpoints) != 2 {
+			return nil, errors.Errorf("relation %q must have exactly two endpoints", strings.Join(endpoints, " "))
+		}
+	}
+	return &bundle, nil
+}
+
+// serviceNames returns the names of the bundle's services in
+// alphabetical order, so that deployment is deterministic.
+func (b *bundleData) serviceNames() []string {
+	serviceNames := make([]string, 0, len(b.Services))
+	for name := range b.Services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+	return serviceNames
+}
+
+// endpointService returns the name of the service of a relation
+// endpoint given as "service" or "service:relation".
+func endpointService(endpoint string) string {
+	if i := strings.Index(endpoint, ":"); i != -1 {
+		return endpoint[:i]
+	}
+	return endpoint
+}
@@ -0,0 +1,300 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package bundles implements the API used by clients to deploy
+// bundles of services, relations and units in a single call.
+package bundles
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v4"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/charms"
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.bundles")
+
+func init() {
+	common.RegisterStandardFacade("Bundles", 0, NewBundlesAPI)
+}
+
+// BundlesAPI serves the Bundles API methods.
+type BundlesAPI struct {
+	st         *state.State
+	resources  *common.Resources
+	authorizer common.Authorizer
+	client     *client.Client
+	charms     *charms.API
+}
+
+// NewBundlesAPI creates a new instance of the Bundles API facade.
+func NewBundlesAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*BundlesAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	clientAPI, err := client.NewClient(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	charmsAPI, err := charms.NewAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &BundlesAPI{
+		st:         st,
+		resources:  resources,
+		authorizer: authorizer,
+		client:     clientAPI,
+		charms:     charmsAPI,
+	}, nil
+}
+
+// Deploy checks the given bundle against the environment, adds the
+// bundle's charms, and records a deployment of the bundle, which the
+// bundle deployer on the state server then runs. The result identifies
+// the deployment, so that its progress can be followed with
+// DeploymentStatus and WatchDeployment.
+//
+// The whole bundle is checked before the deployment is recorded, so
+// that a bundle with an unknown charm, invalid settings or constraints,
+// or a placement on a machine that does not exist makes no changes
+// other than adding charms. If the deployment fails part way through
+// regardless, the bundle deployer destroys the services it deployed and
+// the machines it started. The deployment runs entirely on the state
+// server, so it completes, or is undone, even if the client disconnects.
+func (api *BundlesAPI) Deploy(args params.BundleDeploy) (params.BundleDeployResult, error) {
+	bundle, err := parseBundle(args.YAML)
+	if err != nil {
+		return params.BundleDeployResult{}, errors.Annotate(err, "invalid bundle")
+	}
+	d := &deployment{
+		api:      api,
+		bundle:   bundle,
+		services: make(map[string]*serviceDeployment),
+	}
+	if err := d.prepare(); err != nil {
+		return params.BundleDeployResult{}, errors.Annotate(err, "cannot deploy bundle")
+	}
+	owner, ok := api.authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return params.BundleDeployResult{}, errors.Trace(common.ErrPerm)
+	}
+	deployment, err := api.st.AddBundleDeployment(owner, d.plan())
+	if err != nil {
+		return params.BundleDeployResult{}, errors.Annotate(err, "cannot deploy bundle")
+	}
+	return params.BundleDeployResult{
+		Id:      deployment.Id(),
+		Changes: d.changes,
+	}, nil
+}
+
+// DeploymentStatus returns the status of the given bundle deployment,
+// and the changes it has made so far.
+func (api *BundlesAPI) DeploymentStatus(args params.BundleDeployment) (params.BundleDeploymentStatus, error) {
+	deployment, err := api.st.BundleDeployment(args.Id)
+	if err != nil {
+		return params.BundleDeploymentStatus{}, errors.Trace(err)
+	}
+	changes := make([]params.BundleChange, len(deployment.Changes()))
+	for i, change := range deployment.Changes() {
+		changes[i] = params.BundleChange{Kind: change.Kind, Id: change.Id}
+	}
+	return params.BundleDeploymentStatus{
+		Status:  string(deployment.Status()),
+		Changes: changes,
+		Error:   deployment.Error(),
+	}, nil
+}
+
+// WatchDeployment returns a NotifyWatcher that notifies when the given
+// bundle deployment makes a change or finishes.
+func (api *BundlesAPI) WatchDeployment(args params.BundleDeployment) (params.NotifyWatchResult, error) {
+	deployment, err := api.st.BundleDeployment(args.Id)
+	if err != nil {
+		return params.NotifyWatchResult{}, errors.Trace(err)
+	}
+	watch := deployment.Watch()
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: api.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{}, watcher.EnsureErr(watch)
+}
+
+// deployment holds the state of a single bundle deployment.
+type deployment struct {
+	api      *BundlesAPI
+	bundle   *bundleData
+	services map[string]*serviceDeployment
+	changes  []params.BundleChange
+}
+
+// serviceDeployment holds the checked parameters for deploying a
+// single service of a bundle.
+type serviceDeployment struct {
+	spec        *serviceSpec
+	charmURL    *charm.URL
+	configYAML  string
+	constraints constraints.Value
+}
+
+// record records a change made by the deployment.
+func (d *deployment) record(kind, id string) {
+	logger.Infof("bundle deployment: %s %s", kind, id)
+	d.changes = append(d.changes, params.BundleChange{Kind: kind, Id: id})
+}
+
+// prepare checks the bundle against the environment, and adds the
+// bundle's charms so that the services' settings can be checked.
+func (d *deployment) prepare() error {
+	series := d.bundle.Series
+	if series == "" {
+		envConfig, err := d.api.st.EnvironConfig()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		series, _ = envConfig.DefaultSeries()
+	}
+	for _, name := range d.bundle.serviceNames() {
+		spec := d.bundle.Services[name]
+		_, err := d.api.st.Service(name)
+		if err == nil {
+			return errors.Errorf("service %q already exists", name)
+		} else if !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		curl, err := d.resolveCharm(spec.Charm, series)
+		if err != nil {
+			return errors.Annotatef(err, "cannot resolve charm for service %q", name)
+		}
+		sd, err := d.prepareService(name, spec, curl)
+		if err != nil {
+			return errors.Annotatef(err, "service %q", name)
+		}
+		d.services[name] = sd
+	}
+	for _, endpoints := range d.bundle.Relations {
+		for _, endpoint := range endpoints {
+			name := endpointService(endpoint)
+			if _, ok := d.services[name]; ok {
+				continue
+			}
+			if _, err := d.api.st.Service(name); err != nil {
+				return errors.Annotatef(err, "cannot add relation %q", strings.Join(endpoints, " "))
+			}
+		}
+	}
+	return nil
+}
+
+// resolveCharm resolves the given charm store reference and adds the
+// charm to the environment.
+func (d *deployment) resolveCharm(charmRef, series string) (*charm.URL, error) {
+	ref, err := charm.ParseReference(charmRef)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := d.api.charms.Resolve(params.ResolveCharmsWithSeries{
+		References: []charm.Reference{ref},
+		Series:     series,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resolved.URLs[0].Error != "" {
+		return nil, errors.New(resolved.URLs[0].Error)
+	}
+	curl := resolved.URLs[0].URL
+	if err := d.api.client.AddCharm(params.CharmURL{URL: curl.String()}); err != nil {
+		return nil, err
+	}
+	d.record("add-charm", curl.String())
+	return curl, nil
+}
+
+// prepareService checks the given service's settings, constraints
+// and unit placements.
+func (d *deployment) prepareService(name string, spec *serviceSpec, curl *charm.URL) (*serviceDeployment, error) {
+	ch, err := d.api.st.Charm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sd := &serviceDeployment{
+		spec:     spec,
+		charmURL: curl,
+	}
+	if len(spec.Options) > 0 {
+		configYAML, err := goyaml.Marshal(map[string]interface{}{name: spec.Options})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := ch.Config().ParseSettingsYAML(configYAML, name); err != nil {
+			return nil, err
+		}
+		sd.configYAML = string(configYAML)
+	}
+	if spec.Constraints != "" {
+		if sd.constraints, err = constraints.Parse(spec.Constraints); err != nil {
+			return nil, err
+		}
+	}
+	if ch.Meta().Subordinate {
+		if spec.NumUnits != 0 || spec.Constraints != "" {
+			return nil, errors.New("subordinate service must be deployed without units or constraints")
+		}
+		return sd, nil
+	}
+	for _, directive := range spec.To {
+		placement, err := instance.ParseUnitPlacement(directive)
+		if err != nil {
+			return nil, err
+		}
+		if placement.Directive == "" || placement.Scope == "" {
+			continue
+		}
+		if placement.Scope == instance.MachineScope || isContainerType(placement.Scope) {
+			if _, err := d.api.st.Machine(placement.Directive); err != nil {
+				return nil, errors.Annotatef(err, "cannot place unit with %q", directive)
+			}
+		}
+	}
+	return sd, nil
+}
+
+// plan returns the plan that the bundle deployer follows to deploy
+// the prepared bundle.
+func (d *deployment) plan() state.BundlePlan {
+	plan := state.BundlePlan{Relations: d.bundle.Relations}
+	for _, name := range d.bundle.serviceNames() {
+		sd := d.services[name]
+		plan.Services = append(plan.Services, state.BundleService{
+			Name:        name,
+			CharmURL:    sd.charmURL.String(),
+			ConfigYAML:  sd.configYAML,
+			Constraints: sd.constraints.String(),
+			Expose:      sd.spec.Expose,
+			NumUnits:    sd.spec.NumUnits,
+			To:          sd.spec.To,
+		})
+	}
+	return plan
+}
+
+func isContainerType(scope string) bool {
+	_, err := instance.ParseContainerType(scope)
+	return err == nil
+}
@@ -0,0 +1,237 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundles_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	charmtesting "gopkg.in/juju/charm.v4/testing"
+
+	"github.com/juju/juju/apiserver/bundles"
+	"github.com/juju/juju/apiserver/charms"
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type bundlesSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *bundles.BundlesAPI
+	resources  *common.Resources
+	authorizer apiservertesting.FakeAuthorizer
+	store      *charmtesting.MockCharmStore
+	charmURLs  map[string]string
+}
+
+var _ = gc.Suite(&bundlesSuite{})
+
+func (s *bundlesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	var err error
+	s.api, err = bundles.NewBundlesAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, gc.IsNil)
+	s.store = charmtesting.NewMockCharmStore()
+	s.PatchValue(&charms.CharmStore, s.store)
+	s.PatchValue(&client.CharmStore, s.store)
+	s.charmURLs = make(map[string]string)
+	for _, name := range []string{"wordpress", "mysql", "logging"} {
+		bundle := charmtesting.Charms.CharmArchive(c.MkDir(), name)
+		curl := charm.MustParseURL(fmt.Sprintf("cs:precise/%s-%d", name, bundle.Revision()))
+		err := s.store.SetCharm(curl, bundle)
+		c.Assert(err, gc.IsNil)
+		s.charmURLs[name] = curl.String()
+	}
+}
+
+func (s *bundlesSuite) TestNewBundlesAPIRefusesNonClient(c *gc.C) {
+	anAuthoriser := s.authorizer
+	anAuthoriser.Tag = names.NewMachineTag("1")
+	endPoint, err := bundles.NewBundlesAPI(s.State, nil, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *bundlesSuite) TestDeploy(c *gc.C) {
+	machine, err := s.State.AddMachine("precise", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	result, err := s.api.Deploy(params.BundleDeploy{YAML: fmt.Sprintf(`
+series: precise
+services:
+  wordpress:
+    charm: cs:wordpress
+    num_units: 2
+    to: [%q]
+    options:
+      blog-title: Bundled
+    expose: true
+  mysql:
+    charm: cs:precise/mysql
+    num_units: 1
+    constraints: mem=4G
+relations:
+  - ["wordpress:db", "mysql:server"]
+`, machine.Id())})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Changes, gc.DeepEquals, []params.BundleChange{
+		{Kind: "add-charm", Id: s.charmURLs["mysql"]},
+		{Kind: "add-charm", Id: s.charmURLs["wordpress"]},
+	})
+
+	// The deployment itself is left to the bundle deployer.
+	deployment, err := s.State.BundleDeployment(result.Id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(deployment.Status(), gc.Equals, state.BundleDeploymentPending)
+	c.Assert(deployment.Owner(), gc.Equals, s.AdminUserTag(c))
+	c.Assert(deployment.Plan(), gc.DeepEquals, state.BundlePlan{
+		Services: []state.BundleService{{
+			Name:        "mysql",
+			CharmURL:    s.charmURLs["mysql"],
+			Constraints: "mem=4096M",
+			NumUnits:    1,
+		}, {
+			Name:       "wordpress",
+			CharmURL:   s.charmURLs["wordpress"],
+			ConfigYAML: "wordpress:\n  blog-title: Bundled\n",
+			Expose:     true,
+			NumUnits:   2,
+			To:         []string{machine.Id()},
+		}},
+		Relations: [][]string{{"wordpress:db", "mysql:server"}},
+	})
+	_, err = s.State.Service("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+var deployErrorTests = []struct {
+	about string
+	yaml  string
+	err   string
+}{{
+	about: "invalid yaml",
+	yaml:  "services: [",
+	err:   "invalid bundle: cannot parse bundle: .*",
+}, {
+	about: "no services",
+	yaml:  "series: precise",
+	err:   "invalid bundle: bundle has no services",
+}, {
+	about: "no charm",
+	yaml:  "services: {wordpress: {num_units: 1}}",
+	err:   `invalid bundle: no charm specified for service "wordpress"`,
+}, {
+	about: "too many placements",
+	yaml:  `services: {wordpress: {charm: "cs:precise/wordpress", num_units: 1, to: ["0", "1"]}}`,
+	err:   `invalid bundle: too many placement directives for service "wordpress"`,
+}, {
+	about: "unknown charm",
+	yaml:  `services: {wordpress: {charm: "cs:precise/unknown"}}`,
+	err:   `cannot deploy bundle: cannot resolve charm for service "wordpress": .*`,
+}, {
+	about: "invalid option",
+	yaml:  `services: {wordpress: {charm: "cs:precise/wordpress", options: {unknown: 1}}}`,
+	err:   `cannot deploy bundle: service "wordpress": .*unknown option "unknown"`,
+}, {
+	about: "invalid constraints",
+	yaml:  `services: {wordpress: {charm: "cs:precise/wordpress", constraints: "bad=1"}}`,
+	err:   `cannot deploy bundle: service "wordpress": .*unknown constraint "bad"`,
+}, {
+	about: "subordinate with units",
+	yaml:  `services: {logging: {charm: "cs:precise/logging", num_units: 1}}`,
+	err:   `cannot deploy bundle: service "logging": subordinate service must be deployed without units or constraints`,
+}, {
+	about: "placement on unknown machine",
+	yaml:  `services: {wordpress: {charm: "cs:precise/wordpress", num_units: 1, to: ["lxc:42"]}}`,
+	err:   `cannot deploy bundle: service "wordpress": cannot place unit with "lxc:42": machine 42 not found`,
+}, {
+	about: "relation to unknown service",
+	yaml:  `{services: {wordpress: {charm: "cs:precise/wordpress"}}, relations: [[wordpress, mysql]]}`,
+	err:   `cannot deploy bundle: cannot add relation "wordpress mysql": service "mysql" not found`,
+}}
+
+func (s *bundlesSuite) TestDeployErrors(c *gc.C) {
+	for i, test := range deployErrorTests {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := s.api.Deploy(params.BundleDeploy{YAML: test.yaml})
+		c.Check(err, gc.ErrorMatches, test.err)
+		deployments, err := s.State.UnfinishedBundleDeployments()
+		c.Assert(err, gc.IsNil)
+		c.Check(deployments, gc.HasLen, 0)
+	}
+}
+
+func (s *bundlesSuite) TestDeployExistingService(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := s.api.Deploy(params.BundleDeploy{YAML: `services: {wordpress: {charm: "cs:precise/wordpress"}}`})
+	c.Assert(err, gc.ErrorMatches, `cannot deploy bundle: service "wordpress" already exists`)
+}
+
+func (s *bundlesSuite) TestDeploymentStatus(c *gc.C) {
+	result, err := s.api.Deploy(params.BundleDeploy{YAML: `services: {wordpress: {charm: "cs:precise/wordpress"}}`})
+	c.Assert(err, gc.IsNil)
+	status, err := s.api.DeploymentStatus(params.BundleDeployment{Id: result.Id})
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.DeepEquals, params.BundleDeploymentStatus{
+		Status:  "pending",
+		Changes: []params.BundleChange{},
+	})
+
+	deployment, err := s.State.BundleDeployment(result.Id)
+	c.Assert(err, gc.IsNil)
+	err = deployment.Start()
+	c.Assert(err, gc.IsNil)
+	err = deployment.RecordChange("deploy", "wordpress")
+	c.Assert(err, gc.IsNil)
+	err = deployment.Finish(fmt.Errorf("boom"))
+	c.Assert(err, gc.IsNil)
+
+	status, err = s.api.DeploymentStatus(params.BundleDeployment{Id: result.Id})
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.DeepEquals, params.BundleDeploymentStatus{
+		Status:  "failed",
+		Changes: []params.BundleChange{{Kind: "deploy", Id: "wordpress"}},
+		Error:   "boom",
+	})
+}
+
+func (s *bundlesSuite) TestDeploymentStatusNotFound(c *gc.C) {
+	_, err := s.api.DeploymentStatus(params.BundleDeployment{Id: "42"})
+	c.Assert(err, gc.ErrorMatches, `bundle deployment "42" not found`)
+}
+
+func (s *bundlesSuite) TestWatchDeployment(c *gc.C) {
+	result, err := s.api.Deploy(params.BundleDeploy{YAML: `services: {wordpress: {charm: "cs:precise/wordpress"}}`})
+	c.Assert(err, gc.IsNil)
+	watchResult, err := s.api.WatchDeployment(params.BundleDeployment{Id: result.Id})
+	c.Assert(err, gc.IsNil)
+	c.Assert(watchResult.NotifyWatcherId, gc.Not(gc.Equals), "")
+	resource := s.resources.Get(watchResult.NotifyWatcherId)
+	c.Assert(resource, gc.NotNil)
+
+	w := resource.(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	deployment, err := s.State.BundleDeployment(result.Id)
+	c.Assert(err, gc.IsNil)
+	err = deployment.Start()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
@@ -50,6 +50,14 @@ func NewCharmRevisionUpdaterAPI(
 // UpdateLatestRevisions retrieves the latest revision information from the charm store for all deployed charms
 // and records this information in state.
 func (api *CharmRevisionUpdaterAPI) UpdateLatestRevisions() (params.ErrorResult, error) {
+	cfg, err := api.state.EnvironConfig()
+	if err != nil {
+		return params.ErrorResult{Error: common.ServerError(err)}, nil
+	}
+	if cfg.OfflineMode() {
+		logger.Debugf("offline-mode is set; not retrieving charm revision information")
+		return params.ErrorResult{}, nil
+	}
 	// First get the uuid for the environment to use when querying the charm store.
 	env, err := api.state.Environment()
 	if err != nil {
@@ -129,3 +129,22 @@ func (s *charmVersionSuite) TestEnvironmentUUIDUsed(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(s.Server.Metadata, gc.DeepEquals, []string{"environment_uuid=" + env.UUID()})
 }
+
+func (s *charmVersionSuite) TestUpdateRevisionsOfflineMode(c *gc.C) {
+	s.AddMachine(c, "0", state.JobManageEnviron)
+	s.SetupScenario(c)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"offline-mode": true,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.charmrevisionupdater.UpdateLatestRevisions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Error, gc.IsNil)
+
+	// The charm store was not contacted.
+	c.Assert(s.Server.Metadata, gc.HasLen, 0)
+	curl := charm.MustParseURL("cs:quantal/mysql")
+	_, err = s.State.LatestPlaceholderCharm(curl)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -39,18 +39,12 @@ type charmsHandler struct {
 // response related to a charm bundle.
 type bundleContentSenderFunc func(w http.ResponseWriter, r *http.Request, bundle *charm.CharmArchive)
 
+// ServeHTTP implements http.Handler. Environment validation and
+// authentication of POST requests are left to the httpEndpoint
+// wrapping the handler.
 func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if err := h.validateEnvironUUID(r); err != nil {
-		h.sendError(w, http.StatusNotFound, err.Error())
-		return
-	}
-
 	switch r.Method {
 	case "POST":
-		if err := h.authenticate(r); err != nil {
-			h.authError(w, h)
-			return
-		}
 		// Add a local charm to the store provider.
 		// Requires a "series" query specifying the series to use for the charm.
 		charmURL, err := h.processPost(r)
@@ -226,7 +220,7 @@ func (h *charmsHandler) processPost(r *http.Request) (*charm.URL, error) {
 		return nil, err
 	}
 	// Now we need to repackage it with the reserved URL, upload it to
-	// provider storage and update the state.
+	// environment storage and update the state.
 	err = h.repackageAndUploadCharm(archive, preparedURL)
 	if err != nil {
 		return nil, err
@@ -403,7 +397,7 @@ func (h *charmsHandler) processGet(r *http.Request) (string, string, error) {
 	return charmArchivePath, filePath, nil
 }
 
-// downloadCharm downloads the given charm name from the provider storage and
+// downloadCharm downloads the given charm name from the environment storage and
 // saves the corresponding zip archive to the given charmArchivePath.
 func (h *charmsHandler) downloadCharm(curl *charm.URL, charmArchivePath string) error {
 	storage := h.state.Storage()
@@ -415,7 +409,7 @@ func (h *charmsHandler) downloadCharm(curl *charm.URL, charmArchivePath string)
 	// Use the storage to retrieve and save the charm archive.
 	reader, _, err := storage.Get(ch.StoragePath())
 	if err != nil {
-		return errors.Annotate(err, "charm not found in the provider storage")
+		return errors.Annotate(err, "charm not found in the environment storage")
 	}
 	defer reader.Close()
 	data, err := ioutil.ReadAll(reader)
@@ -0,0 +1,118 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charms implements the API used by clients to resolve charm
+// references against the charm store.
+package charms
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.charms")
+
+func init() {
+	common.RegisterStandardFacade("Charms", 0, NewAPI)
+}
+
+// CharmStore is the charm repository used to resolve charm references.
+// It is a variable so that it can be replaced for testing.
+var CharmStore charm.Repository = charm.Store
+
+// API serves the Charms API methods.
+type API struct {
+	st *state.State
+}
+
+// NewAPI creates a new instance of the Charms API facade.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{st: st}, nil
+}
+
+// Resolve resolves each of the given charm store references to a
+// fully qualified charm URL, including series and revision.
+//
+// A reference that does not specify a series is resolved for
+// args.Series if the charm is available for that series, falling back
+// to the series preferred by the charm store otherwise. A reference
+// that does not specify a revision is resolved to the latest revision.
+func (api *API) Resolve(args params.ResolveCharmsWithSeries) (params.ResolveCharmResults, error) {
+	envConfig, err := api.st.EnvironConfig()
+	if err != nil {
+		return params.ResolveCharmResults{}, err
+	}
+	repo := config.SpecializeCharmRepo(CharmStore, envConfig)
+
+	results := params.ResolveCharmResults{
+		URLs: make([]params.ResolveCharmResult, len(args.References)),
+	}
+	for i, ref := range args.References {
+		curl, err := resolveCharm(repo, ref, args.Series)
+		if err != nil {
+			results.URLs[i].Error = err.Error()
+			continue
+		}
+		results.URLs[i].URL = curl
+	}
+	return results, nil
+}
+
+func resolveCharm(repo charm.Repository, ref charm.Reference, series string) (*charm.URL, error) {
+	if ref.Schema != "cs" {
+		return nil, errors.New("only charm store charm references are supported, with cs: schema")
+	}
+	if ref.Series != "" {
+		curl, err := ref.URL("")
+		if err != nil {
+			return nil, err
+		}
+		return latestRevision(repo, curl)
+	}
+	var seriesErr error
+	if series != "" {
+		curl, err := ref.URL(series)
+		if err != nil {
+			return nil, err
+		}
+		resolved, err := latestRevision(repo, curl)
+		if err == nil {
+			return resolved, nil
+		}
+		logger.Debugf("charm %q not available for series %q, falling back: %v", ref.String(), series, err)
+		seriesErr = err
+	}
+	curl, err := repo.Resolve(&ref)
+	if err == nil {
+		curl, err = latestRevision(repo, curl)
+	}
+	if err != nil && seriesErr != nil {
+		// Report why the charm could not be found for the
+		// requested series, rather than why the fallback failed.
+		return nil, seriesErr
+	}
+	return curl, err
+}
+
+// latestRevision checks that the charm identified by curl exists in
+// the repository, and fills in the latest revision if curl does not
+// specify one.
+func latestRevision(repo charm.Repository, curl *charm.URL) (*charm.URL, error) {
+	revision, err := charm.Latest(repo, curl)
+	if err != nil {
+		return nil, err
+	}
+	if curl.Revision >= 0 {
+		return curl, nil
+	}
+	return curl.WithRevision(revision), nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charms_test
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	charmtesting "gopkg.in/juju/charm.v4/testing"
+
+	"github.com/juju/juju/apiserver/charms"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type charmsSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *charms.API
+	authorizer apiservertesting.FakeAuthorizer
+	store      *charmtesting.MockCharmStore
+}
+
+var _ = gc.Suite(&charmsSuite{})
+
+func (s *charmsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = charms.NewAPI(s.State, nil, s.authorizer)
+	c.Assert(err, gc.IsNil)
+	s.store = charmtesting.NewMockCharmStore()
+	s.PatchValue(&charms.CharmStore, s.store)
+}
+
+func (s *charmsSuite) addCharm(c *gc.C, series, name string) {
+	bundle := charmtesting.Charms.CharmArchive(c.MkDir(), name)
+	curl := charm.MustParseURL(fmt.Sprintf("cs:%s/%s-%d", series, name, bundle.Revision()))
+	err := s.store.SetCharm(curl, bundle)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *charmsSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	anAuthoriser := s.authorizer
+	anAuthoriser.Tag = names.NewMachineTag("1")
+	endPoint, err := charms.NewAPI(s.State, nil, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+var resolveTests = []struct {
+	about       string
+	ref         string
+	series      string
+	storeSeries string
+	expectURL   string
+	expectError string
+}{{
+	about:     "series from the reference",
+	ref:       "cs:trusty/wordpress",
+	series:    "precise",
+	expectURL: "cs:trusty/wordpress-3",
+}, {
+	about:     "explicit revision",
+	ref:       "cs:precise/wordpress-1",
+	expectURL: "cs:precise/wordpress-1",
+}, {
+	about:     "target series",
+	ref:       "cs:wordpress",
+	series:    "trusty",
+	expectURL: "cs:trusty/wordpress-3",
+}, {
+	about:       "fallback to store series",
+	ref:         "cs:mysql",
+	series:      "trusty",
+	storeSeries: "precise",
+	expectURL:   "cs:precise/mysql-1",
+}, {
+	about:       "no target series",
+	ref:         "cs:mysql",
+	storeSeries: "precise",
+	expectURL:   "cs:precise/mysql-1",
+}, {
+	about:       "unresolvable series",
+	ref:         "cs:mysql",
+	series:      "trusty",
+	expectError: `charm not found in mock store: cs:trusty/mysql`,
+}, {
+	about:       "unresolvable series without target series",
+	ref:         "cs:mysql",
+	expectError: "charm url series is not resolved",
+}, {
+	about:       "unknown charm",
+	ref:         "cs:precise/hl3",
+	expectError: `charm not found in mock store: cs:precise/hl3`,
+}, {
+	about:       "local charm",
+	ref:         "local:precise/wordpress",
+	expectError: "only charm store charm references are supported, with cs: schema",
+}}
+
+func (s *charmsSuite) TestResolve(c *gc.C) {
+	s.addCharm(c, "precise", "wordpress")
+	s.addCharm(c, "trusty", "wordpress")
+	s.addCharm(c, "precise", "mysql")
+
+	for i, test := range resolveTests {
+		c.Logf("test %d: %s", i, test.about)
+		s.store.DefaultSeries = test.storeSeries
+		ref, err := charm.ParseReference(test.ref)
+		c.Assert(err, gc.IsNil)
+		results, err := s.api.Resolve(params.ResolveCharmsWithSeries{
+			References: []charm.Reference{*ref},
+			Series:     test.series,
+		})
+		c.Assert(err, gc.IsNil)
+		c.Assert(results.URLs, gc.HasLen, 1)
+		result := results.URLs[0]
+		if test.expectError != "" {
+			c.Check(result.URL, gc.IsNil)
+			c.Check(result.Error, gc.Matches, test.expectError)
+			continue
+		}
+		c.Check(result.Error, gc.Equals, "")
+		c.Check(result.URL, gc.DeepEquals, charm.MustParseURL(test.expectURL))
+	}
+}
@@ -116,6 +116,13 @@ func (s *charmsSuite) TestGETDoesNotRequireAuth(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected url=CharmURL query argument")
 }
 
+func (s *charmsSuite) TestResponseHasRequestId(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.charmsURI(c, ""), "", nil)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.Header.Get("X-Juju-Request-Id"), gc.Matches, "[0-9A-F]+")
+}
+
 func (s *charmsSuite) TestRequiresPOSTorGET(c *gc.C) {
 	resp, err := s.authRequest(c, "PUT", s.charmsURI(c, ""), "", nil)
 	c.Assert(err, gc.IsNil)
@@ -191,6 +198,23 @@ func (s *charmsSuite) TestUploadBumpsRevision(c *gc.C) {
 	c.Assert(sch.BundleSha256(), gc.Not(gc.Equals), "")
 }
 
+func (s *charmsSuite) TestUploadLimitFollowsEnvironConfig(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"max-charm-upload-size": 1}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	body := make([]byte, 1024*1024+1)
+	resp, err := s.authRequest(c, "POST", s.charmsURI(c, "?series=quantal"), s.archiveContentType, bytes.NewReader(body))
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusRequestEntityTooLarge, "request body exceeds the limit of 1048576 bytes")
+
+	// The limit is read for each upload, so raising it takes effect
+	// without restarting the API server.
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"max-charm-upload-size": 2}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	resp, err = s.authRequest(c, "POST", s.charmsURI(c, "?series=quantal"), s.archiveContentType, bytes.NewReader(body))
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "cannot open charm archive: zip: not a valid zip file")
+}
+
 func (s *charmsSuite) TestUploadRespectsLocalRevision(c *gc.C) {
 	// Make a dummy charm dir with revision 123.
 	dir := charmtesting.Charms.ClonedDir(c.MkDir(), "dummy")
@@ -0,0 +1,186 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/environs/config"
+)
+
+// charmStoreCacheSize holds the most space, in bytes, that the cached
+// charm store archives may take up. When adding an archive takes the
+// cache over this size, the least recently used archives are removed.
+var charmStoreCacheSize int64 = 1 << 30
+
+// charmStoreHandler proxies charm archive downloads from the charm
+// store through the API server, so that machines without access to
+// the charm store can fetch store charms. Archives are cached on disk,
+// so while it stays in the cache each charm revision is downloaded
+// from the store only once.
+type charmStoreHandler struct {
+	charmsHandler
+}
+
+// ServeHTTP implements http.Handler.
+func (h *charmStoreHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		// Requires "url" (a charm store URL including the revision)
+		// to be included in the query.
+		charmArchivePath, err := h.processGet(r)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				h.sendError(w, http.StatusNotFound, err.Error())
+			} else {
+				h.sendError(w, http.StatusBadRequest, err.Error())
+			}
+			return
+		}
+		sendBundleContent(w, r, charmArchivePath, h.archiveSender)
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+	}
+}
+
+// processGet handles a charm store archive GET request after
+// authentication. It returns the path of the cached archive.
+func (h *charmStoreHandler) processGet(r *http.Request) (string, error) {
+	curlString := r.URL.Query().Get("url")
+	if curlString == "" {
+		return "", fmt.Errorf("expected url=CharmURL query argument")
+	}
+	curl, err := charm.ParseURL(curlString)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot parse charm URL")
+	}
+	if curl.Schema != "cs" {
+		return "", fmt.Errorf("only charm store charm URLs are supported, with cs: schema")
+	}
+	if curl.Revision < 0 {
+		return "", fmt.Errorf("charm URL must include revision")
+	}
+	// Charm store URLs with a revision always refer to the same
+	// archive, so a cached archive never needs refreshing.
+	charmArchivePath := filepath.Join(h.dataDir, "charmstore-cache", charm.Quote(curl.String())+".zip")
+	if _, err := os.Stat(charmArchivePath); os.IsNotExist(err) {
+		if err := h.downloadStoreCharm(curl, charmArchivePath); err != nil {
+			return "", errors.Annotate(err, "unable to retrieve and save the charm")
+		}
+		if err := trimCharmStoreCache(filepath.Dir(charmArchivePath), charmArchivePath); err != nil {
+			logger.Warningf("cannot trim the charm store cache: %v", err)
+		}
+	} else if err != nil {
+		return "", errors.Annotate(err, "cannot access the charm store cache")
+	} else {
+		// The modification time records when the archive was last
+		// used, for the cache's eviction.
+		now := time.Now()
+		if err := os.Chtimes(charmArchivePath, now, now); err != nil {
+			logger.Warningf("cannot mark cached charm archive as used: %v", err)
+		}
+	}
+	return charmArchivePath, nil
+}
+
+// trimCharmStoreCache removes the least recently used archives from the
+// charm store cache until it fits within charmStoreCacheSize. The
+// archive at keepPath, which has just been added, is never removed.
+func trimCharmStoreCache(cacheDir, keepPath string) error {
+	infos, err := ioutil.ReadDir(cacheDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var archives []os.FileInfo
+	var total int64
+	for _, info := range infos {
+		// Temporary files of downloads in progress are not counted.
+		if info.IsDir() || filepath.Ext(info.Name()) != ".zip" {
+			continue
+		}
+		archives = append(archives, info)
+		total += info.Size()
+	}
+	sort.Sort(byModTime(archives))
+	for _, info := range archives {
+		if total <= charmStoreCacheSize {
+			break
+		}
+		path := filepath.Join(cacheDir, info.Name())
+		if path == keepPath {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Trace(err)
+		}
+		logger.Debugf("removed %s from the charm store cache", info.Name())
+		total -= info.Size()
+	}
+	return nil
+}
+
+// byModTime sorts files oldest first.
+type byModTime []os.FileInfo
+
+func (b byModTime) Len() int           { return len(b) }
+func (b byModTime) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byModTime) Less(i, j int) bool { return b[i].ModTime().Before(b[j].ModTime()) }
+
+// downloadStoreCharm downloads the given charm from the charm store and
+// saves it to the given charmArchivePath.
+func (h *charmStoreHandler) downloadStoreCharm(curl *charm.URL, charmArchivePath string) error {
+	envConfig, err := h.state.EnvironConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot get environment config")
+	}
+	if envConfig.OfflineMode() {
+		return errors.New("charm store access is disabled by offline-mode")
+	}
+	store := config.SpecializeCharmRepo(client.CharmStore, envConfig)
+	ch, err := store.Get(curl)
+	if err != nil {
+		return errors.Annotatef(err, "cannot download charm %q", curl)
+	}
+	archive, ok := ch.(*charm.CharmArchive)
+	if !ok {
+		return errors.Errorf("expected a charm archive, got %T", ch)
+	}
+	source, err := os.Open(archive.Path)
+	if err != nil {
+		return errors.Annotate(err, "cannot read downloaded charm")
+	}
+	defer source.Close()
+	// As in charmsHandler.downloadCharm, the archive is written to a
+	// temporary file in the cache directory, which is then atomically
+	// renamed, to avoid races between concurrent requests.
+	cacheDir := filepath.Dir(charmArchivePath)
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return errors.Annotate(err, "cannot create the charm store cache")
+	}
+	tempCharmArchive, err := ioutil.TempFile(cacheDir, "charm")
+	if err != nil {
+		return errors.Annotate(err, "cannot create charm archive temp file")
+	}
+	defer tempCharmArchive.Close()
+	if _, err := io.Copy(tempCharmArchive, source); err != nil {
+		os.Remove(tempCharmArchive.Name())
+		return errors.Annotate(err, "error processing charm archive download")
+	}
+	if err := os.Rename(tempCharmArchive.Name(), charmArchivePath); err != nil {
+		os.Remove(tempCharmArchive.Name())
+		return errors.Annotate(err, "error renaming the charm archive")
+	}
+	return nil
+}
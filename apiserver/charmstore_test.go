@@ -0,0 +1,172 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	charmtesting "gopkg.in/juju/charm.v4/testing"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/testing/factory"
+)
+
+type charmStoreSuite struct {
+	authHttpSuite
+	store *charmtesting.MockCharmStore
+}
+
+var _ = gc.Suite(&charmStoreSuite{})
+
+func (s *charmStoreSuite) SetUpTest(c *gc.C) {
+	s.authHttpSuite.SetUpTest(c)
+	s.store = charmtesting.NewMockCharmStore()
+	s.PatchValue(&client.CharmStore, charm.Repository(s.store))
+}
+
+func (s *charmStoreSuite) charmStoreURI(c *gc.C, query string) string {
+	uri := s.baseURL(c)
+	uri.Path += "/charmstore"
+	uri.RawQuery = query
+	return uri.String()
+}
+
+// addStoreCharm adds the dummy charm to the mock charm store, and
+// returns its archive contents.
+func (s *charmStoreSuite) addStoreCharm(c *gc.C) []byte {
+	archive := charmtesting.Charms.CharmArchive(c.MkDir(), "dummy")
+	err := s.store.SetCharm(charm.MustParseURL("cs:quantal/dummy-1"), archive)
+	c.Assert(err, gc.IsNil)
+	data, err := ioutil.ReadFile(archive.Path)
+	c.Assert(err, gc.IsNil)
+	return data
+}
+
+func (s *charmStoreSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.charmStoreURI(c, "url=cs:quantal/dummy-1"), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *charmStoreSuite) TestAcceptsUnitAgent(c *gc.C) {
+	data := s.addStoreCharm(c)
+	unit := s.Factory.MakeUnit(c, nil)
+	err := unit.SetPassword("unit-password-1234567890")
+	c.Assert(err, gc.IsNil)
+	uri := s.charmStoreURI(c, "url="+url.QueryEscape("cs:quantal/dummy-1"))
+	resp, err := s.sendRequest(c, unit.Tag().String(), "unit-password-1234567890", "GET", uri, "", nil)
+	c.Assert(err, gc.IsNil)
+	body := assertResponse(c, resp, http.StatusOK, "application/zip")
+	c.Assert(body, gc.DeepEquals, data)
+}
+
+func (s *charmStoreSuite) TestRefusesMachineAgent(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{Password: "machine-password"})
+	uri := s.charmStoreURI(c, "url="+url.QueryEscape("cs:quantal/dummy-1"))
+	resp, err := s.sendRequest(c, machine.Tag().String(), "machine-password", "GET", uri, "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *charmStoreSuite) TestRequiresGET(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.charmStoreURI(c, ""), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *charmStoreSuite) TestRequiresStoreCharmURL(c *gc.C) {
+	for i, test := range []struct {
+		query string
+		err   string
+	}{{
+		query: "",
+		err:   "expected url=CharmURL query argument",
+	}, {
+		query: "url=local:quantal/dummy-1",
+		err:   "only charm store charm URLs are supported, with cs: schema",
+	}, {
+		query: "url=cs:quantal/dummy",
+		err:   "charm URL must include revision",
+	}} {
+		c.Logf("test %d: %q", i, test.query)
+		resp, err := s.authRequest(c, "GET", s.charmStoreURI(c, test.query), "", nil)
+		c.Assert(err, gc.IsNil)
+		s.assertErrorResponse(c, resp, http.StatusBadRequest, test.err)
+	}
+}
+
+func (s *charmStoreSuite) TestGetReturnsArchiveBytes(c *gc.C) {
+	data := s.addStoreCharm(c)
+	uri := s.charmStoreURI(c, "url="+url.QueryEscape("cs:quantal/dummy-1"))
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, gc.IsNil)
+	body := assertResponse(c, resp, http.StatusOK, "application/zip")
+	c.Assert(body, gc.DeepEquals, data)
+}
+
+func (s *charmStoreSuite) TestGetUsesCache(c *gc.C) {
+	data := s.addStoreCharm(c)
+	uri := s.charmStoreURI(c, "url="+url.QueryEscape("cs:quantal/dummy-1"))
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, gc.IsNil)
+	assertResponse(c, resp, http.StatusOK, "application/zip")
+
+	// The charm is served from the cache once the store no longer has it.
+	s.PatchValue(&client.CharmStore, charm.Repository(charmtesting.NewMockCharmStore()))
+	resp, err = s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, gc.IsNil)
+	body := assertResponse(c, resp, http.StatusOK, "application/zip")
+	c.Assert(body, gc.DeepEquals, data)
+}
+
+func (s *charmStoreSuite) TestGetMissingCharm(c *gc.C) {
+	uri := s.charmStoreURI(c, "url="+url.QueryEscape("cs:quantal/dummy-1"))
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(resp.StatusCode, gc.Not(gc.Equals), http.StatusOK)
+	body := assertResponse(c, resp, resp.StatusCode, "application/json")
+	c.Check(jsonResponse(c, body).Error, gc.Matches,
+		`unable to retrieve and save the charm: cannot download charm "cs:quantal/dummy-1": .*`)
+}
+
+func (s *charmStoreSuite) TestGetOfflineMode(c *gc.C) {
+	s.addStoreCharm(c)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"offline-mode": true}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	uri := s.charmStoreURI(c, "url="+url.QueryEscape("cs:quantal/dummy-1"))
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest,
+		`unable to retrieve and save the charm: charm store access is disabled by offline-mode`)
+}
+
+func (s *charmStoreSuite) TestCacheEvictsLeastRecentlyUsed(c *gc.C) {
+	for _, name := range []string{"dummy", "wordpress"} {
+		archive := charmtesting.Charms.CharmArchive(c.MkDir(), name)
+		err := s.store.SetCharm(charm.MustParseURL("cs:quantal/"+name+"-1"), archive)
+		c.Assert(err, gc.IsNil)
+	}
+	// The cache only has room for one archive.
+	s.PatchValue(apiserver.CharmStoreCacheSize, int64(1))
+	get := func(curl string) int {
+		uri := s.charmStoreURI(c, "url="+url.QueryEscape(curl))
+		resp, err := s.authRequest(c, "GET", uri, "", nil)
+		c.Assert(err, gc.IsNil)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	c.Assert(get("cs:quantal/dummy-1"), gc.Equals, http.StatusOK)
+	c.Assert(get("cs:quantal/wordpress-1"), gc.Equals, http.StatusOK)
+
+	// Once the store no longer has them, only the most recently used
+	// archive can be served.
+	s.PatchValue(&client.CharmStore, charm.Repository(charmtesting.NewMockCharmStore()))
+	c.Assert(get("cs:quantal/wordpress-1"), gc.Equals, http.StatusOK)
+	c.Assert(get("cs:quantal/dummy-1"), gc.Not(gc.Equals), http.StatusOK)
+}
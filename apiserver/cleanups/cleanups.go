@@ -0,0 +1,71 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleanups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Cleanups", 0, NewCleanupsAPI)
+}
+
+// CleanupsAPI allows clients to inspect and manage the cleanups that
+// state schedules when entities are destroyed.
+type CleanupsAPI struct {
+	st *state.State
+}
+
+// NewCleanupsAPI creates a new instance of the Cleanups API facade.
+func NewCleanupsAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*CleanupsAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	return &CleanupsAPI{st: st}, nil
+}
+
+// List returns all pending cleanups, oldest first.
+func (api *CleanupsAPI) List() (params.CleanupsResult, error) {
+	infos, err := api.st.Cleanups()
+	if err != nil {
+		return params.CleanupsResult{}, errors.Trace(err)
+	}
+	result := params.CleanupsResult{
+		Cleanups: make([]params.CleanupInfo, len(infos)),
+	}
+	for i, info := range infos {
+		result.Cleanups[i] = params.CleanupInfo{
+			Id:        info.Id,
+			Kind:      info.Kind,
+			Prefix:    info.Prefix,
+			Created:   info.Created,
+			Failures:  info.Failures,
+			LastError: info.LastError,
+		}
+	}
+	return result, nil
+}
+
+// Run runs all pending cleanups immediately, rather than waiting for
+// the cleaner worker to do so. Cleanups that fail are left pending,
+// with the failure recorded for List to report.
+func (api *CleanupsAPI) Run() error {
+	return errors.Trace(api.st.Cleanup())
+}
+
+// Cancel removes the cleanups with the given ids without running them.
+func (api *CleanupsAPI) Cancel(args params.CleanupIds) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		err := api.st.CancelCleanup(id)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
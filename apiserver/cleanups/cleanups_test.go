@@ -0,0 +1,103 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleanups_test
+
+import (
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/cleanups"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/juju/testing"
+)
+
+type cleanupsSuite struct {
+	testing.JujuConnSuite
+
+	api *cleanups.CleanupsAPI
+}
+
+var _ = gc.Suite(&cleanupsSuite{})
+
+func (s *cleanupsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("admin"),
+	}
+	var err error
+	s.api, err = cleanups.NewCleanupsAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *cleanupsSuite) destroyServiceWithUnit(c *gc.C) {
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = svc.Destroy()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *cleanupsSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := cleanups.NewCleanupsAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *cleanupsSuite) TestList(c *gc.C) {
+	result, err := s.api.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Cleanups, gc.HasLen, 0)
+
+	s.destroyServiceWithUnit(c)
+	result, err = s.api.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Cleanups, gc.HasLen, 1)
+	info := result.Cleanups[0]
+	c.Assert(info.Kind, gc.Equals, "units")
+	c.Assert(info.Prefix, gc.Equals, "wordpress")
+	c.Assert(info.Created.IsZero(), gc.Equals, false)
+}
+
+func (s *cleanupsSuite) TestRun(c *gc.C) {
+	s.destroyServiceWithUnit(c)
+	err := s.api.Run()
+	c.Assert(err, gc.IsNil)
+
+	// The unit and then the service are removed.
+	_, err = s.State.Service("wordpress")
+	c.Assert(err, gc.ErrorMatches, `service "wordpress" not found`)
+}
+
+func (s *cleanupsSuite) TestCancel(c *gc.C) {
+	s.destroyServiceWithUnit(c)
+	infos, err := s.State.Cleanups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(infos, gc.HasLen, 1)
+
+	result, err := s.api.Cancel(params.CleanupIds{
+		Ids: []string{infos[0].Id, infos[0].Id, "foo"},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{&params.Error{
+				Message: `cleanup "` + infos[0].Id + `" not found`,
+				Code:    params.CodeNotFound,
+			}},
+			{&params.Error{Message: `invalid cleanup id "foo"`}},
+		},
+	})
+	infos, err = s.State.Cleanups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(infos, gc.HasLen, 0)
+
+	// The unit is left alone.
+	_, err = s.State.Unit("wordpress/0")
+	c.Assert(err, gc.IsNil)
+}
@@ -202,6 +202,11 @@ var scenarioStatus = &api.Status{
 				"logging-directory": []string{"wordpress"},
 			},
 			SubordinateTo: []string{"wordpress"},
+			Status: api.AggregateStatus{
+				Status: "pending",
+				Info:   "2 units pending",
+				Counts: map[params.Status]int{"pending": 2},
+			},
 		},
 		"mysql": api.ServiceStatus{
 			Charm:         "local:quantal/mysql-1",
@@ -253,6 +258,11 @@ var scenarioStatus = &api.Status{
 					},
 				},
 			},
+			Status: api.AggregateStatus{
+				Status: "error",
+				Info:   "1 unit in error",
+				Counts: map[params.Status]int{"error": 1, "pending": 1},
+			},
 		},
 	},
 	Relations: []api.RelationStatus{
@@ -0,0 +1,36 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// SetUnitChaos replaces the faults that a unit's agent injects into
+// its hook execution. It fails unless the environment's
+// enable-chaos-testing setting is true.
+func (c *Client) SetUnitChaos(args params.SetUnitChaos) error {
+	cfg, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !cfg.EnableChaosTesting() {
+		return errors.New("chaos testing is disabled; set enable-chaos-testing to enable it")
+	}
+	if args.HookDelay < 0 {
+		return errors.New("hook delay must not be negative")
+	}
+	unit, err := c.api.state.Unit(args.UnitName)
+	if err != nil {
+		return err
+	}
+	return unit.SetChaos(state.UnitChaos{
+		FailHooks: args.FailHooks,
+		HookDelay: args.HookDelay,
+		Restart:   args.Restart,
+	})
+}
@@ -7,24 +7,28 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v4"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/highavailability"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/version"
 )
 
@@ -79,6 +83,43 @@ func (c *Client) WatchAll() (params.AllWatcherId, error) {
 	}, nil
 }
 
+// WatchAllFiltered initiates a watcher for entities in the connected
+// environment that only reports deltas for the entity kinds and ids
+// selected by the given filter.
+func (c *Client) WatchAllFiltered(args params.AllWatcherFilter) (params.AllWatcherId, error) {
+	for _, pattern := range args.Patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return params.AllWatcherId{}, errors.Errorf("invalid pattern %q", pattern)
+		}
+	}
+	w := c.api.state.WatchFiltered(allWatcherFilter(args))
+	return params.AllWatcherId{
+		AllWatcherId: c.api.resources.Register(w),
+	}, nil
+}
+
+// allWatcherFilter returns a multiwatcher.Filter selecting the deltas
+// described by args.
+func allWatcherFilter(args params.AllWatcherFilter) multiwatcher.Filter {
+	kinds := set.NewStrings(args.Kinds...)
+	return func(d params.Delta) bool {
+		id := d.Entity.EntityId()
+		if !kinds.IsEmpty() && !kinds.Contains(id.Kind) {
+			return false
+		}
+		if len(args.Patterns) == 0 {
+			return true
+		}
+		name := fmt.Sprint(id.Id)
+		for _, pattern := range args.Patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // ServiceSet implements the server side of Client.ServiceSet. Values set to an
 // empty string will be unset.
 //
@@ -212,6 +253,47 @@ func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.Private
 	return results, fmt.Errorf("unknown unit or machine %q", p.Target)
 }
 
+// DebugHooksTranscript returns the transcript of the most recent
+// debug-hooks session on a unit.
+func (c *Client) DebugHooksTranscript(p params.DebugHooksTranscript) (params.DebugHooksTranscriptResults, error) {
+	unit, err := c.api.state.Unit(p.UnitName)
+	if err != nil {
+		return params.DebugHooksTranscriptResults{}, err
+	}
+	transcript, updated, err := unit.DebugHooksTranscript()
+	if err != nil {
+		return params.DebugHooksTranscriptResults{}, err
+	}
+	return params.DebugHooksTranscriptResults{
+		Transcript: transcript,
+		Updated:    updated,
+	}, nil
+}
+
+// UnitStatusHistory returns the most recent status transitions of a
+// unit, newest first.
+func (c *Client) UnitStatusHistory(p params.StatusHistory) (params.StatusHistoryResults, error) {
+	unit, err := c.api.state.Unit(p.UnitName)
+	if err != nil {
+		return params.StatusHistoryResults{}, err
+	}
+	history, err := unit.StatusHistory(p.Size)
+	if err != nil {
+		return params.StatusHistoryResults{}, err
+	}
+	result := params.StatusHistoryResults{
+		Statuses: make([]params.StatusHistoryEntry, len(history)),
+	}
+	for i, entry := range history {
+		result.Statuses[i] = params.StatusHistoryEntry{
+			Status: params.Status(entry.Status),
+			Info:   entry.Info,
+			Since:  entry.Since,
+		}
+	}
+	return result, nil
+}
+
 // ServiceExpose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) ServiceExpose(args params.ServiceExpose) error {
@@ -232,6 +314,16 @@ func (c *Client) ServiceUnexpose(args params.ServiceUnexpose) error {
 	return svc.ClearExposed()
 }
 
+// ServiceUnexposePorts changes the juju-managed firewall to close the
+// given ports of an exposed service, leaving its other ports exposed.
+func (c *Client) ServiceUnexposePorts(args params.ServiceUnexposePorts) error {
+	svc, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return svc.UnexposePorts(args.Ports)
+}
+
 var CharmStore charm.Repository = charm.Store
 
 func networkTagsToNames(tags []string) ([]string, error) {
@@ -356,7 +448,17 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	}
 	// Update service's constraints.
 	if args.Constraints != nil {
-		return service.SetConstraints(*args.Constraints)
+		if err = service.SetConstraints(*args.Constraints); err != nil {
+			return err
+		}
+	}
+	// Replace service's hook policy overrides.
+	if args.HookPolicy != nil {
+		return service.SetHookPolicy(state.HookPolicy{
+			Timeout:    args.HookPolicy.Timeout,
+			RetryCount: args.HookPolicy.RetryCount,
+			RetryDelay: args.HookPolicy.RetryDelay,
+		})
 	}
 	return nil
 }
@@ -367,6 +469,11 @@ func (c *Client) serviceSetCharm(service *state.Service, url string, force bool)
 	if err != nil {
 		return err
 	}
+	if !force {
+		if err := c.checkCharmPin(service, curl); err != nil {
+			return err
+		}
+	}
 	sch, err := c.api.state.Charm(curl)
 	if errors.IsNotFound(err) {
 		// Charms should be added before trying to use them, with
@@ -380,6 +487,27 @@ func (c *Client) serviceSetCharm(service *state.Service, url string, force bool)
 	return service.SetCharm(sch, force)
 }
 
+// checkCharmPin returns an error if the service is held at its current
+// charm, either by its own pin or by the environment's
+// charm-revision-policy, and curl names a different charm.
+func (c *Client) checkCharmPin(service *state.Service, curl *charm.URL) error {
+	current, _ := service.CharmURL()
+	if current != nil && *current == *curl {
+		return nil
+	}
+	if service.IsCharmPinned() {
+		return fmt.Errorf("service %q charm is pinned; use --force to upgrade", service.Name())
+	}
+	cfg, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.CharmRevisionPolicy() == config.CharmRevisionLocked {
+		return fmt.Errorf("charm-revision-policy is %q; use --force to upgrade service %q", config.CharmRevisionLocked, service.Name())
+	}
+	return nil
+}
+
 // serviceSetCharm1dot16 sets the charm for the given service in 1.16
 // compatibility mode. Remove this when support for 1.16 is dropped.
 func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL, force bool) error {
@@ -551,13 +679,100 @@ func (c *Client) SetEnvironmentConstraints(args params.SetConstraints) error {
 	return c.api.state.SetEnvironConstraints(args.Constraints)
 }
 
+// GetConstraints returns the constraints set on each of the given
+// environment, service and machine entities, along with the
+// constraints that take effect for it. Service constraints take
+// precedence over environment constraints for new units; machine
+// constraints were resolved against both when the machine was added,
+// and so take effect as they are.
+func (c *Client) GetConstraints(args params.Entities) (params.EntityConstraintsResults, error) {
+	results := params.EntityConstraintsResults{
+		Results: make([]params.EntityConstraintsResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		cons, effective, err := c.entityConstraints(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Constraints = cons
+		results.Results[i].Effective = effective
+	}
+	return results, nil
+}
+
+func (c *Client) entityConstraints(tagString string) (cons, effective constraints.Value, err error) {
+	tag, err := names.ParseTag(tagString)
+	if err != nil {
+		return cons, effective, err
+	}
+	entity, err := c.api.state.FindEntity(tag)
+	if err != nil {
+		return cons, effective, err
+	}
+	switch entity := entity.(type) {
+	case *state.Environment:
+		cons, err = c.api.state.EnvironConstraints()
+		return cons, cons, err
+	case *state.Service:
+		if cons, err = entity.Constraints(); err != nil {
+			return cons, effective, err
+		}
+		effective, err = entity.EffectiveConstraints()
+		return cons, effective, err
+	case *state.Machine:
+		cons, err = entity.Constraints()
+		return cons, cons, err
+	}
+	return cons, effective, common.NotSupportedError(tag, "constraints")
+}
+
+// SetConstraints replaces the constraints set on each of the given
+// environment, service and machine entities. Machine constraints can
+// only be changed before the machine is provisioned.
+func (c *Client) SetConstraints(args params.SetEntityConstraints) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		err := c.setEntityConstraints(arg.Tag, arg.Constraints)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (c *Client) setEntityConstraints(tagString string, cons constraints.Value) error {
+	tag, err := names.ParseTag(tagString)
+	if err != nil {
+		return err
+	}
+	entity, err := c.api.state.FindEntity(tag)
+	if err != nil {
+		return err
+	}
+	switch entity := entity.(type) {
+	case *state.Environment:
+		return c.api.state.SetEnvironConstraints(cons)
+	case *state.Service:
+		return entity.SetConstraints(cons)
+	case *state.Machine:
+		return entity.SetConstraints(cons)
+	}
+	return common.NotSupportedError(tag, "constraints")
+}
+
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults, error) {
 	inEps, err := c.api.state.InferEndpoints(args.Endpoints...)
+	if ambiguous, ok := err.(*state.AmbiguousRelationError); ok && args.ReportCandidates {
+		return params.AddRelationResults{
+			Candidates: relationCandidates(ambiguous.Candidates),
+		}, nil
+	}
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
-	rel, err := c.api.state.AddRelation(inEps...)
+	rel, err := c.api.state.AddRelationVia(args.Network, inEps...)
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
@@ -572,6 +787,18 @@ func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults
 	return params.AddRelationResults{Endpoints: outEps}, nil
 }
 
+// relationCandidates returns the names of the endpoints of each of
+// the given candidate relations.
+func relationCandidates(candidates [][]state.Endpoint) [][]string {
+	result := make([][]string, len(candidates))
+	for i, eps := range candidates {
+		for _, ep := range eps {
+			result[i] = append(result[i], ep.String())
+		}
+	}
+	return result
+}
+
 // DestroyRelation removes the relation between the specified endpoints.
 func (c *Client) DestroyRelation(args params.DestroyRelation) error {
 	eps, err := c.api.state.InferEndpoints(args.Endpoints...)
@@ -842,6 +1069,12 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 				err = errors.Annotate(err, "could not unshare environment")
 				result.Results[i].Error = common.ServerError(err)
 			}
+		case params.GrantEnvUser:
+			err := c.grantEnvironment(user, createdBy, state.EnvironmentAccess(arg.Access))
+			if err != nil {
+				err = errors.Annotate(err, "could not grant environment access")
+				result.Results[i].Error = common.ServerError(err)
+			}
 		default:
 			result.Results[i].Error = common.ServerError(errors.Errorf("unknown action %q", arg.Action))
 		}
@@ -849,6 +1082,22 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	return result, nil
 }
 
+// grantEnvironment gives user the given access to the environment,
+// sharing the environment with the user first if necessary.
+func (c *Client) grantEnvironment(user, createdBy names.UserTag, access state.EnvironmentAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	envUser, err := c.api.state.EnvironmentUser(user)
+	if errors.IsNotFound(err) {
+		envUser, err = c.api.state.AddEnvironmentUser(user, createdBy)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return envUser.SetAccess(access)
+}
+
 // GetAnnotations returns annotations about a given entity.
 func (c *Client) GetAnnotations(args params.GetAnnotations) (params.GetAnnotationsResults, error) {
 	nothing := params.GetAnnotationsResults{}
@@ -931,7 +1180,8 @@ func (c *Client) AgentVersion() (params.AgentVersionResult, error) {
 }
 
 // EnvironmentGet implements the server-side part of the
-// get-environment CLI command.
+// get-environment CLI command. Secret values are only returned to
+// the environment's owner.
 func (c *Client) EnvironmentGet() (params.EnvironmentGetResults, error) {
 	result := params.EnvironmentGetResults{}
 	// Get the existing environment config from the state.
@@ -939,8 +1189,16 @@ func (c *Client) EnvironmentGet() (params.EnvironmentGetResults, error) {
 	if err != nil {
 		return result, err
 	}
-	result.Config = config.AllAttrs()
-	return result, nil
+	env, err := c.api.state.Environment()
+	if err != nil {
+		return result, err
+	}
+	if c.api.auth.GetAuthTag() == names.Tag(env.Owner()) {
+		result.Config = config.AllAttrs()
+		return result, nil
+	}
+	result.Config, err = redactEnvironConfig(config)
+	return result, err
 }
 
 // EnvironmentSet implements the server-side part of the
@@ -976,6 +1234,38 @@ func (c *Client) SetEnvironAgentVersion(args params.SetEnvironAgentVersion) erro
 	return c.api.state.SetEnvironAgentVersion(args.Version)
 }
 
+// SetAgentVersionPin holds the agents of the given machine or service
+// at no later than the given version, or clears the pin if the version
+// is zero.
+func (c *Client) SetAgentVersionPin(args params.SetAgentVersionPin) error {
+	tag, err := names.ParseTag(args.Tag)
+	if err != nil {
+		return err
+	}
+	entity, err := c.api.state.FindEntity(tag)
+	if err != nil {
+		return err
+	}
+	pinner, ok := entity.(state.AgentVersionPinner)
+	if !ok {
+		return errors.NotSupportedf("pinning agent version of %q", args.Tag)
+	}
+	if args.Version == version.Zero {
+		return pinner.ClearAgentVersionPin()
+	}
+	return pinner.SetAgentVersionPin(args.Version)
+}
+
+// SetServiceCharmPin pins the given service to its current charm, or
+// removes the pin.
+func (c *Client) SetServiceCharmPin(args params.SetServiceCharmPin) error {
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return service.SetCharmPinned(args.Pinned)
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
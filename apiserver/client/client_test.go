@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -187,6 +188,37 @@ func (s *serverSuite) TestShareEnvironmentAddRemoteUser(c *gc.C) {
 	c.Assert(envUser.LastConnection(), gc.IsNil)
 }
 
+func (s *serverSuite) TestShareEnvironmentGrantAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.GrantEnvUser,
+			Access:  "read",
+		}}}
+
+	// Granting access shares the environment if necessary.
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+
+	args.Changes[0].Access = "write"
+	result, err = s.client.ShareEnvironment(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+
+	args.Changes[0].Access = "admin"
+	result, err = s.client.ShareEnvironment(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `could not grant environment access: environment access "admin" not valid`)
+}
+
 func (s *serverSuite) TestShareEnvironmentInvalidTags(c *gc.C) {
 	for _, testParam := range []struct {
 		tag      string
@@ -300,6 +332,41 @@ func (s *serverSuite) TestSetEnvironAgentVersion(c *gc.C) {
 	c.Assert(agentVersion, gc.Equals, "9.8.7")
 }
 
+func (s *serverSuite) TestSetAgentVersionPin(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	pinned := version.MustParse("1.2.3")
+	for _, entity := range []state.AgentVersionPinner{machine, service} {
+		err = s.client.SetAgentVersionPin(params.SetAgentVersionPin{
+			Tag:     entity.Tag().String(),
+			Version: pinned,
+		})
+		c.Assert(err, gc.IsNil)
+		v, ok, err := entity.AgentVersionPin()
+		c.Assert(err, gc.IsNil)
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(v, gc.Equals, pinned)
+
+		err = s.client.SetAgentVersionPin(params.SetAgentVersionPin{
+			Tag: entity.Tag().String(),
+		})
+		c.Assert(err, gc.IsNil)
+		_, ok, err = entity.AgentVersionPin()
+		c.Assert(err, gc.IsNil)
+		c.Assert(ok, jc.IsFalse)
+	}
+}
+
+func (s *serverSuite) TestSetAgentVersionPinUnsupported(c *gc.C) {
+	err := s.client.SetAgentVersionPin(params.SetAgentVersionPin{
+		Tag:     s.AdminUserTag(c).String(),
+		Version: version.MustParse("1.2.3"),
+	})
+	c.Assert(err, gc.ErrorMatches, `pinning agent version of "user-admin(@local)?" not supported`)
+}
+
 func (s *serverSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	// Create a provisioned state server.
 	machine, err := s.State.AddMachine("series", state.JobManageEnviron)
@@ -648,11 +715,17 @@ func (s *clientSuite) TestClientAnnotations(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	environment, err := s.State.Environment()
 	c.Assert(err, gc.IsNil)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, gc.IsNil)
+	relation, err := s.State.AddRelation(eps...)
+	c.Assert(err, gc.IsNil)
 	type taggedAnnotator interface {
 		state.Annotator
 		state.Entity
 	}
-	entities := []taggedAnnotator{service, unit, machine, environment}
+	entities := []taggedAnnotator{service, unit, machine, environment, relation}
 	for i, t := range clientAnnotationsTests {
 		for _, entity := range entities {
 			id := entity.Tag().String() // this is WRONG, it should be Tag().Id() but the code is wrong.
@@ -794,6 +867,25 @@ func (s *clientSuite) TestClientServiceUnexpose(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestClientServiceUnexposePorts(c *gc.C) {
+	svc := s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	ports := []network.PortRange{{8080, 8080, "tcp"}}
+
+	err := s.APIState.Client().ServiceUnexposePorts("unknown-service", ports)
+	c.Assert(err, gc.ErrorMatches, `service "unknown-service" not found`)
+	err = s.APIState.Client().ServiceUnexposePorts("dummy-service", ports)
+	c.Assert(err, gc.ErrorMatches, `cannot unexpose ports of service "dummy-service": service is not exposed`)
+
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().ServiceUnexposePorts("dummy-service", ports)
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsExposed(), jc.IsTrue)
+	c.Assert(svc.UnexposedPorts(), jc.DeepEquals, ports)
+}
+
 var serviceDestroyTests = []struct {
 	about   string
 	service string
@@ -980,6 +1072,163 @@ func (s *clientSuite) TestDestroySubordinateUnits(c *gc.C) {
 	assertLife(c, logging0, state.Alive)
 }
 
+func (s *clientSuite) TestDestroyUnitsPatterns(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	var units []*state.Unit
+	for i := 0; i < 2; i++ {
+		unit, err := wordpress.AddUnit()
+		c.Assert(err, gc.IsNil)
+		units = append(units, unit)
+	}
+	mysql0, err := mysql.AddUnit()
+	c.Assert(err, gc.IsNil)
+
+	unitNames, err := s.APIState.Client().DestroyUnits("wordpress/*", "wordpress/1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(unitNames, jc.SameContents, []string{"wordpress/0", "wordpress/1"})
+	assertLife(c, units[0], state.Dying)
+	assertLife(c, units[1], state.Dying)
+	assertLife(c, mysql0, state.Alive)
+
+	// Dying units are no longer matched.
+	_, err = s.APIState.Client().DestroyUnits("wordpress/*")
+	c.Assert(err, gc.ErrorMatches, `no units match "wordpress/\*"`)
+
+	_, err = s.APIState.Client().DestroyUnits("mysql")
+	c.Assert(err, gc.ErrorMatches, `invalid unit name or pattern "mysql"`)
+	_, err = s.APIState.Client().DestroyUnits()
+	c.Assert(err, gc.ErrorMatches, "no units specified")
+	assertLife(c, mysql0, state.Alive)
+}
+
+func (s *clientSuite) TestDestroyServiceUnitCount(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	units := make([]*state.Unit, 11)
+	for i := range units {
+		unit, err := wordpress.AddUnit()
+		c.Assert(err, gc.IsNil)
+		units[i] = unit
+	}
+
+	// Units are ordered numerically, so wordpress/10 is the highest.
+	unitNames, err := s.APIState.Client().DestroyServiceUnitCount("wordpress", 2)
+	c.Assert(err, gc.IsNil)
+	c.Assert(unitNames, gc.DeepEquals, []string{"wordpress/10", "wordpress/9"})
+	assertLife(c, units[10], state.Dying)
+	assertLife(c, units[9], state.Dying)
+	assertLife(c, units[8], state.Alive)
+
+	_, err = s.APIState.Client().DestroyServiceUnitCount("wordpress", 10)
+	c.Assert(err, gc.ErrorMatches, `cannot remove 10 units: service "wordpress" has 9 units`)
+	_, err = s.APIState.Client().DestroyServiceUnitCount("wordpress", 0)
+	c.Assert(err, gc.ErrorMatches, "must remove at least one unit")
+	_, err = s.APIState.Client().DestroyServiceUnitCount("unknown", 1)
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+	assertLife(c, units[8], state.Alive)
+}
+
+func (s *clientSuite) TestServiceSetUnitCount(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	units := make([]*state.Unit, 4)
+	for i := range units {
+		unit, err := wordpress.AddUnit()
+		c.Assert(err, gc.IsNil)
+		units[i] = unit
+	}
+	err := units[3].SetStatus(state.StatusError, "gaaah", nil)
+	c.Assert(err, gc.IsNil)
+
+	// Units in an error state are destroyed last.
+	added, destroyed, err := s.APIState.Client().ServiceSetUnitCount("wordpress", 2)
+	c.Assert(err, gc.IsNil)
+	c.Assert(added, gc.HasLen, 0)
+	c.Assert(destroyed, gc.DeepEquals, []string{"wordpress/2", "wordpress/1"})
+	assertLife(c, units[0], state.Alive)
+	assertLife(c, units[1], state.Dying)
+	assertLife(c, units[2], state.Dying)
+	assertLife(c, units[3], state.Alive)
+
+	// Dying units are not counted.
+	added, destroyed, err = s.APIState.Client().ServiceSetUnitCount("wordpress", 3)
+	c.Assert(err, gc.IsNil)
+	c.Assert(added, gc.DeepEquals, []string{"wordpress/4"})
+	c.Assert(destroyed, gc.HasLen, 0)
+
+	added, destroyed, err = s.APIState.Client().ServiceSetUnitCount("wordpress", 3)
+	c.Assert(err, gc.IsNil)
+	c.Assert(added, gc.HasLen, 0)
+	c.Assert(destroyed, gc.HasLen, 0)
+
+	_, _, err = s.APIState.Client().ServiceSetUnitCount("wordpress", -1)
+	c.Assert(err, gc.ErrorMatches, "unit count must not be negative")
+	_, _, err = s.APIState.Client().ServiceSetUnitCount("unknown", 1)
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+}
+
+func (s *clientSuite) TestAddAndListNetworks(c *gc.C) {
+	networks, err := s.APIState.Client().ListNetworks()
+	c.Assert(err, gc.IsNil)
+	c.Assert(networks, gc.HasLen, 0)
+
+	err = s.APIState.Client().AddNetwork("db", "provider-net-42", "10.0.42.0/24", 0)
+	c.Assert(err, gc.IsNil)
+	err = s.APIState.Client().AddNetwork("storage", "provider-vlan-43", "", 43)
+	c.Assert(err, gc.IsNil)
+	err = s.APIState.Client().AddNetwork("db", "provider-net-44", "", 0)
+	c.Assert(err, gc.ErrorMatches, `cannot add network "db": network "db" already exists`)
+
+	db, err := s.State.Network("db")
+	c.Assert(err, gc.IsNil)
+	c.Assert(db.ProviderId(), gc.Equals, network.Id("provider-net-42"))
+
+	networks, err = s.APIState.Client().ListNetworks()
+	c.Assert(err, gc.IsNil)
+	c.Assert(networks, jc.SameContents, []params.Network{{
+		Tag:        "network-db",
+		ProviderId: "provider-net-42",
+		CIDR:       "10.0.42.0/24",
+	}, {
+		Tag:        "network-storage",
+		ProviderId: "provider-vlan-43",
+		VLANTag:    43,
+	}})
+}
+
+func (s *clientSuite) TestMachinePorts(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, gc.IsNil)
+	err = unit.OpenPorts("tcp", 80, 90)
+	c.Assert(err, gc.IsNil)
+
+	expect := []params.MachineOpenedPorts{{
+		MachineTag: machine.Tag().String(),
+		Ports: []params.MachinePortRange{{
+			UnitTag:   unit.Tag().String(),
+			PortRange: network.PortRange{FromPort: 80, ToPort: 90, Protocol: "tcp"},
+		}},
+	}, {
+		MachineTag: other.Tag().String(),
+	}}
+	ports, err := s.APIState.Client().MachinePorts()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ports, jc.DeepEquals, expect)
+
+	ports, err = s.APIState.Client().MachinePorts(other.Tag().(names.MachineTag))
+	c.Assert(err, gc.IsNil)
+	c.Assert(ports, jc.DeepEquals, expect[1:])
+
+	_, err = s.APIState.Client().MachinePorts(names.NewMachineTag("42"))
+	c.Assert(err, gc.ErrorMatches, "machine 42 not found")
+}
+
 func (s *clientSuite) testClientUnitResolved(c *gc.C, retry bool, expectedResolvedMode state.ResolvedMode) {
 	// Setup:
 	s.setUpScenario(c)
@@ -1007,6 +1256,86 @@ func (s *clientSuite) TestClientUnitResolvedRetry(c *gc.C) {
 	s.testClientUnitResolved(c, true, state.ResolvedRetryHooks)
 }
 
+func (s *clientSuite) setUpResolveUnits(c *gc.C) map[string]*state.Unit {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	units := make(map[string]*state.Unit)
+	for _, svc := range []*state.Service{wordpress, wordpress, wordpress, mysql} {
+		unit, err := svc.AddUnit()
+		c.Assert(err, gc.IsNil)
+		units[unit.Name()] = unit
+	}
+	for _, name := range []string{"wordpress/0", "wordpress/1", "mysql/0"} {
+		err := units[name].SetStatus(state.StatusError, "gaaah", nil)
+		c.Assert(err, gc.IsNil)
+	}
+	return units
+}
+
+func (s *clientSuite) assertResolvedModes(c *gc.C, units map[string]*state.Unit, expect map[string]state.ResolvedMode) {
+	for name, unit := range units {
+		err := unit.Refresh()
+		c.Assert(err, gc.IsNil)
+		c.Check(unit.Resolved(), gc.Equals, expect[name], gc.Commentf("unit %s", name))
+	}
+}
+
+func (s *clientSuite) TestClientResolveUnitsPatterns(c *gc.C) {
+	units := s.setUpResolveUnits(c)
+
+	results, err := s.APIState.Client().ResolveUnits(true, "wordpress/*")
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.DeepEquals, []params.ResolveUnitResult{
+		{UnitName: "wordpress/0"},
+		{UnitName: "wordpress/1"},
+	})
+	s.assertResolvedModes(c, units, map[string]state.ResolvedMode{
+		"wordpress/0": state.ResolvedRetryHooks,
+		"wordpress/1": state.ResolvedRetryHooks,
+		"wordpress/2": state.ResolvedNone,
+		"mysql/0":     state.ResolvedNone,
+	})
+
+	// Units given by name are resolved even if they are not in an
+	// error state, so that the problem is reported.
+	results, err = s.APIState.Client().ResolveUnits(false, "wordpress/2", "mysql")
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].UnitName, gc.Equals, "wordpress/2")
+	c.Assert(results[0].Error, gc.ErrorMatches, `unit "wordpress/2" is not in an error state`)
+	c.Assert(results[1], gc.DeepEquals, params.ResolveUnitResult{UnitName: "mysql/0"})
+	c.Assert(units["mysql/0"].Refresh(), gc.IsNil)
+	c.Assert(units["mysql/0"].Resolved(), gc.Equals, state.ResolvedNoHooks)
+
+	_, err = s.APIState.Client().ResolveUnits(false, "wordpress/+")
+	c.Assert(err, gc.ErrorMatches, `pattern "wordpress/\+" contains invalid characters`)
+	_, err = s.APIState.Client().ResolveUnits(false)
+	c.Assert(err, gc.ErrorMatches, "no units specified")
+}
+
+func (s *clientSuite) TestClientResolveAllUnits(c *gc.C) {
+	units := s.setUpResolveUnits(c)
+
+	results, err := s.APIState.Client().ResolveAllUnits(false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.DeepEquals, []params.ResolveUnitResult{
+		{UnitName: "mysql/0"},
+		{UnitName: "wordpress/0"},
+		{UnitName: "wordpress/1"},
+	})
+	s.assertResolvedModes(c, units, map[string]state.ResolvedMode{
+		"wordpress/0": state.ResolvedNoHooks,
+		"wordpress/1": state.ResolvedNoHooks,
+		"wordpress/2": state.ResolvedNone,
+		"mysql/0":     state.ResolvedNoHooks,
+	})
+
+	// Units already resolved are skipped.
+	results, err = s.APIState.Client().ResolveAllUnits(true)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 0)
+}
+
 func (s *clientSuite) TestClientServiceDeployCharmErrors(c *gc.C) {
 	_, restore := makeMockCharmStore()
 	defer restore()
@@ -1354,6 +1683,34 @@ func (s *clientSuite) TestClientServiceUpdateSetConstraints(c *gc.C) {
 	c.Assert(obtained, gc.DeepEquals, cons)
 }
 
+func (s *clientSuite) TestClientServiceUpdateSetHookPolicy(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	// Override the hook policy for the service.
+	timeout := 10 * time.Minute
+	retryCount := 3
+	args := params.ServiceUpdate{
+		ServiceName: "dummy",
+		HookPolicy: &params.ServiceHookPolicy{
+			Timeout:    &timeout,
+			RetryCount: &retryCount,
+		},
+	}
+	err := s.APIState.Client().ServiceUpdate(args)
+	c.Assert(err, gc.IsNil)
+
+	// Ensure the overrides have been recorded.
+	service, err := s.State.Service("dummy")
+	c.Assert(err, gc.IsNil)
+	c.Assert(service.HookPolicy(), gc.DeepEquals, state.HookPolicy{
+		Timeout:    &timeout,
+		RetryCount: &retryCount,
+	})
+	results, err := s.APIState.Client().ServiceGet("dummy")
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.HookPolicy, gc.DeepEquals, *args.HookPolicy)
+}
+
 func (s *clientSuite) TestClientServiceUpdateAllParams(c *gc.C) {
 	store, restore := makeMockCharmStore()
 	defer restore()
@@ -1468,6 +1825,72 @@ func (s *clientSuite) TestClientServiceSetCharmForce(c *gc.C) {
 	c.Assert(force, gc.Equals, true)
 }
 
+func (s *clientSuite) TestClientServiceSetCharmPinned(c *gc.C) {
+	store, restore := makeMockCharmStore()
+	defer restore()
+	curl, _ := addCharm(c, store, "dummy")
+	err := s.APIState.Client().ServiceDeploy(
+		curl.String(), "service", 3, "", constraints.Value{}, "",
+	)
+	c.Assert(err, gc.IsNil)
+	err = s.APIState.Client().SetServiceCharmPin("service", true)
+	c.Assert(err, gc.IsNil)
+	addCharm(c, store, "wordpress")
+	err = s.APIState.Client().ServiceSetCharm(
+		"service", "cs:precise/wordpress-3", false,
+	)
+	c.Assert(err, gc.ErrorMatches, `service "service" charm is pinned; use --force to upgrade`)
+
+	// Forcing the upgrade crosses the pin.
+	err = s.APIState.Client().ServiceSetCharm(
+		"service", "cs:precise/wordpress-3", true,
+	)
+	c.Assert(err, gc.IsNil)
+	service, err := s.State.Service("service")
+	c.Assert(err, gc.IsNil)
+	charm, _, err := service.Charm()
+	c.Assert(err, gc.IsNil)
+	c.Assert(charm.URL().String(), gc.Equals, "cs:precise/wordpress-3")
+	c.Assert(service.IsCharmPinned(), jc.IsTrue)
+}
+
+func (s *clientSuite) TestClientServiceSetCharmRevisionPolicyLocked(c *gc.C) {
+	store, restore := makeMockCharmStore()
+	defer restore()
+	curl, _ := addCharm(c, store, "dummy")
+	err := s.APIState.Client().ServiceDeploy(
+		curl.String(), "service", 3, "", constraints.Value{}, "",
+	)
+	c.Assert(err, gc.IsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"charm-revision-policy": "locked",
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	addCharm(c, store, "wordpress")
+	err = s.APIState.Client().ServiceSetCharm(
+		"service", "cs:precise/wordpress-3", false,
+	)
+	c.Assert(err, gc.ErrorMatches, `charm-revision-policy is "locked"; use --force to upgrade service "service"`)
+}
+
+func (s *clientSuite) TestClientSetServiceCharmPin(c *gc.C) {
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := s.APIState.Client().SetServiceCharmPin("wordpress", true)
+	c.Assert(err, gc.IsNil)
+	err = service.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(service.IsCharmPinned(), jc.IsTrue)
+
+	err = s.APIState.Client().SetServiceCharmPin("wordpress", false)
+	c.Assert(err, gc.IsNil)
+	err = service.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(service.IsCharmPinned(), jc.IsFalse)
+
+	err = s.APIState.Client().SetServiceCharmPin("mysql", true)
+	c.Assert(err, gc.ErrorMatches, `service "mysql" not found`)
+}
+
 func (s *clientSuite) TestClientServiceSetCharmInvalidService(c *gc.C) {
 	_, restore := makeMockCharmStore()
 	defer restore()
@@ -1566,6 +1989,43 @@ func (s *clientSuite) TestSuccessfullyAddRelationSwapped(c *gc.C) {
 	s.assertAddRelation(c, endpoints)
 }
 
+func (s *clientSuite) TestAddRelationReportsCandidates(c *gc.C) {
+	s.AddTestingService(c, "ms", s.AddTestingCharm(c, "mysql-alternative"))
+	s.AddTestingService(c, "wp", s.AddTestingCharm(c, "wordpress"))
+
+	// Without asking for candidates, ambiguity is an error.
+	_, err := s.APIState.Client().AddRelation("wp", "ms")
+	c.Assert(err, gc.ErrorMatches, `ambiguous relation: "wp ms" could refer to "wp:db ms:dev"; "wp:db ms:prod"`)
+
+	res, err := s.APIState.Client().AddRelationVia("", "wp", "ms")
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.Endpoints, gc.HasLen, 0)
+	c.Assert(res.Candidates, gc.DeepEquals, [][]string{
+		{"wp:db", "ms:dev"},
+		{"wp:db", "ms:prod"},
+	})
+	rels, err := s.State.AllRelations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(rels, gc.HasLen, 0)
+}
+
+func (s *clientSuite) TestAddRelationVia(c *gc.C) {
+	s.setUpScenario(c)
+	_, err := s.State.AddNetwork(state.NetworkInfo{
+		Name:       "backend",
+		ProviderId: "backend-id",
+		CIDR:       "10.1.0.0/16",
+	})
+	c.Assert(err, gc.IsNil)
+	res, err := s.APIState.Client().AddRelationVia("backend", "wordpress", "mysql")
+	c.Assert(err, gc.IsNil)
+	c.Assert(res.Candidates, gc.HasLen, 0)
+	c.Assert(res.Endpoints, gc.HasLen, 2)
+	rel, err := s.State.KeyRelation("wordpress:db mysql:server")
+	c.Assert(err, gc.IsNil)
+	c.Assert(rel.Network(), gc.Equals, "backend")
+}
+
 func (s *clientSuite) TestCallWithOnlyOneEndpoint(c *gc.C) {
 	s.setUpScenario(c)
 	endpoints := []string{"wordpress"}
@@ -1704,6 +2164,42 @@ func (s *clientSuite) TestClientWatchAll(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestClientWatchAllFiltered(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err := mysql.AddUnit()
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+
+	watcher, err := s.APIState.Client().WatchAllFiltered(params.AllWatcherFilter{
+		Kinds:    []string{"service", "unit"},
+		Patterns: []string{"mysql", "mysql/*"},
+	})
+	c.Assert(err, gc.IsNil)
+	defer func() {
+		err := watcher.Stop()
+		c.Assert(err, gc.IsNil)
+	}()
+	deltas, err := watcher.Next()
+	c.Assert(err, gc.IsNil)
+	var ids []params.EntityId
+	for _, d := range deltas {
+		ids = append(ids, d.Entity.EntityId())
+	}
+	c.Assert(ids, jc.SameContents, []params.EntityId{
+		{Kind: "service", Id: "mysql"},
+		{Kind: "unit", Id: "mysql/0"},
+	})
+}
+
+func (s *clientSuite) TestClientWatchAllFilteredInvalidPattern(c *gc.C) {
+	_, err := s.APIState.Client().WatchAllFiltered(params.AllWatcherFilter{
+		Patterns: []string{"[mysql"},
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid pattern "\[mysql"`)
+}
+
 func (s *clientSuite) TestClientSetServiceConstraints(c *gc.C) {
 	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
 
@@ -1760,6 +2256,75 @@ func (s *clientSuite) TestClientGetEnvironmentConstraints(c *gc.C) {
 	c.Assert(obtained, gc.DeepEquals, cons)
 }
 
+func (s *clientSuite) TestClientGetConstraints(c *gc.C) {
+	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=4G cpu-cores=2"))
+	c.Assert(err, gc.IsNil)
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	err = service.SetConstraints(constraints.MustParse("mem=8G"))
+	c.Assert(err, gc.IsNil)
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("arch=amd64"),
+	})
+	c.Assert(err, gc.IsNil)
+	client := s.APIState.Client()
+
+	cons, effective, err := client.GetConstraints(s.State.EnvironTag().String())
+	c.Assert(err, gc.IsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=4G cpu-cores=2"))
+	c.Assert(effective, gc.DeepEquals, cons)
+
+	cons, effective, err = client.GetConstraints(service.Tag().String())
+	c.Assert(err, gc.IsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=8G"))
+	c.Assert(effective, gc.DeepEquals, constraints.MustParse("mem=8G cpu-cores=2"))
+
+	// Machine constraints were merged with the environment
+	// constraints when the machine was added.
+	cons, effective, err = client.GetConstraints(machine.Tag().String())
+	c.Assert(err, gc.IsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("arch=amd64 mem=4G cpu-cores=2"))
+	c.Assert(effective, gc.DeepEquals, cons)
+
+	_, _, err = client.GetConstraints("service-missing")
+	c.Assert(err, gc.ErrorMatches, `service "missing" not found`)
+	_, _, err = client.GetConstraints(names.NewLocalUserTag("admin").String())
+	c.Assert(err, gc.ErrorMatches, `entity "user-admin.*" does not support constraints`)
+}
+
+func (s *clientSuite) TestClientSetConstraints(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	client := s.APIState.Client()
+
+	cons := constraints.MustParse("mem=4G")
+	err = client.SetConstraints(s.State.EnvironTag().String(), cons)
+	c.Assert(err, gc.IsNil)
+	obtained, err := s.State.EnvironConstraints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(obtained, gc.DeepEquals, cons)
+
+	err = client.SetConstraints(service.Tag().String(), cons)
+	c.Assert(err, gc.IsNil)
+	obtained, err = service.Constraints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(obtained, gc.DeepEquals, cons)
+
+	err = client.SetConstraints(machine.Tag().String(), cons)
+	c.Assert(err, gc.IsNil)
+	obtained, err = machine.Constraints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(obtained, gc.DeepEquals, cons)
+
+	// Provisioned machines keep their constraints.
+	err = machine.SetProvisioned("i-0", "fake_nonce", nil)
+	c.Assert(err, gc.IsNil)
+	err = client.SetConstraints(machine.Tag().String(), constraints.MustParse("mem=8G"))
+	c.Assert(err, gc.ErrorMatches, "cannot set constraints: machine is already provisioned")
+}
+
 func (s *clientSuite) TestClientServiceCharmRelations(c *gc.C) {
 	s.setUpScenario(c)
 	_, err := s.APIState.Client().ServiceCharmRelations("blah")
@@ -1782,6 +2347,44 @@ func (s *clientSuite) TestClientPublicAddressErrors(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unit "wordpress/0" has no public address`)
 }
 
+func (s *clientSuite) TestClientDebugHooksTranscript(c *gc.C) {
+	s.setUpScenario(c)
+	_, _, err := s.APIState.Client().DebugHooksTranscript("wordpress/0")
+	c.Assert(err, gc.ErrorMatches, `debug-hooks transcript for unit "wordpress/0" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+	_, _, err = s.APIState.Client().DebugHooksTranscript("wordpress/42")
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/42" not found`)
+
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	err = unit.SetDebugHooksTranscript("=== config-changed ===\n")
+	c.Assert(err, gc.IsNil)
+	transcript, updated, err := s.APIState.Client().DebugHooksTranscript("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(transcript, gc.Equals, "=== config-changed ===\n")
+	c.Assert(updated.IsZero(), jc.IsFalse)
+}
+
+func (s *clientSuite) TestClientUnitStatusHistory(c *gc.C) {
+	s.setUpScenario(c)
+	_, err := s.APIState.Client().UnitStatusHistory("wordpress/42", 20)
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/42" not found`)
+
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(state.StatusInstalled, "", nil)
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(state.StatusError, "hook failed", nil)
+	c.Assert(err, gc.IsNil)
+	history, err := s.APIState.Client().UnitStatusHistory("wordpress/0", 2)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, params.StatusError)
+	c.Assert(history[0].Info, gc.Equals, "hook failed")
+	c.Assert(history[0].Since.IsZero(), jc.IsFalse)
+	c.Assert(history[1].Status, gc.Equals, params.StatusInstalled)
+}
+
 func (s *clientSuite) TestClientPublicAddressMachine(c *gc.C) {
 	s.setUpScenario(c)
 
@@ -1856,6 +2459,48 @@ func (s *clientSuite) TestClientPrivateAddressUnit(c *gc.C) {
 	c.Assert(addr, gc.Equals, "private")
 }
 
+func (s *clientSuite) TestClientEnvironmentSchema(c *gc.C) {
+	attrs, err := s.APIState.Client().EnvironmentSchema()
+	c.Assert(err, gc.IsNil)
+	c.Check(attrs["default-series"], jc.DeepEquals, params.EnvironConfigAttribute{
+		Type:    "string",
+		Default: "",
+	})
+	c.Check(attrs["firewall-mode"], jc.DeepEquals, params.EnvironConfigAttribute{
+		Type:      "string",
+		Default:   "instance",
+		Immutable: true,
+	})
+	c.Check(attrs["admin-secret"].Secret, jc.IsTrue)
+	c.Check(attrs["development"].Type, gc.Equals, "bool")
+	c.Check(attrs["api-port"].Type, gc.Equals, "int")
+	// The dummy provider's attributes are included too.
+	c.Check(attrs["secret"], jc.DeepEquals, params.EnvironConfigAttribute{
+		Type:     "string",
+		Default:  "pork",
+		Secret:   true,
+		Provider: true,
+	})
+	c.Check(attrs["state-server"], jc.DeepEquals, params.EnvironConfigAttribute{
+		Type:     "bool",
+		Provider: true,
+	})
+}
+
+func (s *clientSuite) TestClientEnvironmentGetRedactsSecretsForNonOwner(c *gc.C) {
+	attrs, err := s.APIState.Client().EnvironmentGet()
+	c.Assert(err, gc.IsNil)
+	c.Assert(attrs["secret"], gc.Equals, "pork")
+
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "password"})
+	st := s.OpenAPIAs(c, user.Tag(), "password")
+	defer st.Close()
+	attrs, err = st.Client().EnvironmentGet()
+	c.Assert(err, gc.IsNil)
+	c.Assert(attrs["secret"], gc.Equals, "REDACTED")
+	c.Assert(attrs["name"], gc.Equals, "dummyenv")
+}
+
 func (s *clientSuite) TestClientEnvironmentGet(c *gc.C) {
 	envConfig, err := s.State.EnvironConfig()
 	c.Assert(err, gc.IsNil)
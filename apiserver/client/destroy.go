@@ -7,14 +7,22 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 )
 
 // DestroyEnvironment destroys all services and non-manager machine
-// instances in the environment.
-func (c *Client) DestroyEnvironment() error {
+// instances in the environment. If the environment is destroy-protected,
+// the environment's UUID must be supplied as the confirmation token.
+func (c *Client) DestroyEnvironment(args params.DestroyEnvironment) error {
+	if err := checkDestroyConfirmation(c.api.state, args.ConfirmationToken); err != nil {
+		return err
+	}
+
 	// TODO(axw) 2013-08-30 bug 1218688
 	//
 	// There's a race here: a client might add a manual machine
@@ -73,6 +81,22 @@ func (c *Client) DestroyEnvironment() error {
 	return nil
 }
 
+// checkDestroyConfirmation returns an error if the environment is
+// destroy-protected and token is not the environment's UUID.
+func checkDestroyConfirmation(st *state.State, token string) error {
+	envConfig, err := st.EnvironConfig()
+	if err != nil {
+		return err
+	}
+	if !envConfig.DestroyProtected() {
+		return nil
+	}
+	if token != st.EnvironTag().Id() {
+		return errors.New("environment is destroy-protected: the environment UUID must be given as the confirmation token")
+	}
+	return nil
+}
+
 // destroyInstances directly destroys all non-manager,
 // non-manual machine instances.
 func destroyInstances(st *state.State, machines []*state.Machine) error {
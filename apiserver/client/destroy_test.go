@@ -148,3 +148,23 @@ func (s *destroyEnvironmentSuite) TestDestroyEnvironmentWithContainers(c *gc.C)
 		}
 	}
 }
+
+func (s *destroyEnvironmentSuite) TestDestroyEnvironmentProtected(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"destroy-protected": true}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	client := s.APIState.Client()
+	err = client.DestroyEnvironment()
+	c.Assert(err, gc.ErrorMatches, "environment is destroy-protected: the environment UUID must be given as the confirmation token")
+	err = client.DestroyEnvironmentWithToken("not-the-uuid")
+	c.Assert(err, gc.ErrorMatches, "environment is destroy-protected: .*")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	c.Assert(env.Life(), gc.Equals, state.Alive)
+
+	err = client.DestroyEnvironmentWithToken(s.State.EnvironTag().Id())
+	c.Assert(err, gc.IsNil)
+	err = env.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(env.Life(), gc.Equals, state.Dying)
+}
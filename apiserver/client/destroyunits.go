@@ -0,0 +1,155 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// DestroyUnits destroys either the units given by name or glob pattern
+// (e.g. "wordpress/*"), or the given number of highest-numbered units of
+// a service. It returns the names of the units it attempted to destroy.
+func (c *Client) DestroyUnits(args params.DestroyUnits) (params.DestroyUnitsResults, error) {
+	var unitNames []string
+	var err error
+	switch {
+	case args.ServiceName != "" && len(args.Patterns) > 0:
+		err = errors.New("cannot specify both a service and units")
+	case args.ServiceName != "":
+		unitNames, err = c.highestServiceUnits(args.ServiceName, args.NumUnits)
+	default:
+		unitNames, err = c.matchUnitPatterns(args.Patterns)
+	}
+	if err != nil {
+		return params.DestroyUnitsResults{}, err
+	}
+	err = c.DestroyServiceUnits(params.DestroyServiceUnits{UnitNames: unitNames})
+	if err != nil {
+		return params.DestroyUnitsResults{}, err
+	}
+	return params.DestroyUnitsResults{UnitNames: unitNames}, nil
+}
+
+// matchUnitPatterns returns the names of the units given by the
+// patterns. Unit names are returned as given, so that any errors
+// are reported when destroying them; glob patterns only match
+// alive principal units, and each must match at least one.
+func (c *Client) matchUnitPatterns(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, errors.New("no units specified")
+	}
+	var unitNames []string
+	seen := make(set.Strings)
+	add := func(name string) {
+		if !seen.Contains(name) {
+			seen.Add(name)
+			unitNames = append(unitNames, name)
+		}
+	}
+	var units []*state.Unit
+	for _, pattern := range patterns {
+		if names.IsValidUnit(pattern) {
+			add(pattern)
+			continue
+		}
+		if !strings.Contains(pattern, "/") {
+			return nil, errors.Errorf("invalid unit name or pattern %q", pattern)
+		}
+		matcher, err := NewUnitMatcher([]string{pattern})
+		if err != nil {
+			return nil, err
+		}
+		if units == nil {
+			if units, err = c.allUnits(); err != nil {
+				return nil, err
+			}
+		}
+		matched := false
+		for _, unit := range units {
+			if unit.Life() != state.Alive || !unit.IsPrincipal() {
+				continue
+			}
+			if matcher.matchString(unit.Name()) {
+				matched = true
+				add(unit.Name())
+			}
+		}
+		if !matched {
+			return nil, errors.Errorf("no units match %q", pattern)
+		}
+	}
+	return unitNames, nil
+}
+
+// highestServiceUnits returns the names of the given number of
+// highest-numbered alive units of the service.
+func (c *Client) highestServiceUnits(serviceName string, numUnits int) ([]string, error) {
+	if numUnits < 1 {
+		return nil, errors.New("must remove at least one unit")
+	}
+	svc, err := c.api.state.Service(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.IsPrincipal() {
+		return nil, errors.Errorf("service %q is a subordinate", serviceName)
+	}
+	units, err := svc.AllUnits()
+	if err != nil {
+		return nil, err
+	}
+	var alive []*state.Unit
+	for _, unit := range units {
+		if unit.Life() == state.Alive {
+			alive = append(alive, unit)
+		}
+	}
+	if numUnits > len(alive) {
+		return nil, errors.Errorf("cannot remove %d units: service %q has %d units", numUnits, serviceName, len(alive))
+	}
+	sort.Sort(sort.Reverse(byUnitNumber(alive)))
+	unitNames := make([]string, numUnits)
+	for i := range unitNames {
+		unitNames[i] = alive[i].Name()
+	}
+	return unitNames, nil
+}
+
+func (c *Client) allUnits() ([]*state.Unit, error) {
+	services, err := c.api.state.AllServices()
+	if err != nil {
+		return nil, err
+	}
+	var units []*state.Unit
+	for _, svc := range services {
+		svcUnits, err := svc.AllUnits()
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, svcUnits...)
+	}
+	return units, nil
+}
+
+type byUnitNumber []*state.Unit
+
+func (u byUnitNumber) Len() int      { return len(u) }
+func (u byUnitNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u byUnitNumber) Less(i, j int) bool {
+	return unitNumber(u[i].Name()) < unitNumber(u[j].Name())
+}
+
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
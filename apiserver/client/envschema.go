@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
+
+// EnvironmentSchema returns a description of every configuration
+// attribute known to the environment, including those specific to
+// its provider.
+func (c *Client) EnvironmentSchema() (params.EnvironmentSchemaResults, error) {
+	cfg, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return params.EnvironmentSchemaResults{}, errors.Trace(err)
+	}
+	attrs, err := environSchema(cfg)
+	if err != nil {
+		return params.EnvironmentSchemaResults{}, errors.Trace(err)
+	}
+	result := params.EnvironmentSchemaResults{
+		Attributes: make(map[string]params.EnvironConfigAttribute),
+	}
+	for name, attr := range attrs {
+		result.Attributes[name] = params.EnvironConfigAttribute{
+			Type:      attr.Type,
+			Default:   attr.Default,
+			Immutable: attr.Immutable,
+			Secret:    attr.Secret,
+			Provider:  attr.provider,
+		}
+	}
+	return result, nil
+}
+
+// schemaAttr describes an attribute of an environment's configuration.
+type schemaAttr struct {
+	config.Attr
+	provider bool
+}
+
+// environSchema returns the attributes known to the environment with
+// the given configuration, including those specific to its provider.
+func environSchema(cfg *config.Config) (map[string]schemaAttr, error) {
+	provider, err := environs.Provider(cfg.Type())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	attrs := make(map[string]schemaAttr)
+	for name, attr := range config.Schema() {
+		attrs[name] = schemaAttr{Attr: attr}
+	}
+	if source, ok := provider.(environs.ConfigSchemaSource); ok {
+		for name, attr := range source.ConfigSchema() {
+			attrs[name] = schemaAttr{Attr: attr, provider: true}
+		}
+	}
+	// Whatever the provider reports as secret is secret, even if its
+	// schema does not say so.
+	secrets, err := provider.SecretAttrs(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name := range secrets {
+		attr := attrs[name]
+		attr.Secret = true
+		attrs[name] = attr
+	}
+	return attrs, nil
+}
+
+// redactEnvironConfig returns the attributes of cfg with the values of
+// secret attributes replaced.
+func redactEnvironConfig(cfg *config.Config) (map[string]interface{}, error) {
+	attrs, err := environSchema(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	schema := make(map[string]config.Attr, len(attrs))
+	for name, attr := range attrs {
+		schema[name] = attr.Attr
+	}
+	return config.RedactSecrets(cfg.AllAttrs(), schema), nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// exportedBundle holds an environment rendered as a bundle, in the
+// format accepted by the Bundles facade.
+type exportedBundle struct {
+	Services  map[string]*exportedService `yaml:"services"`
+	Relations [][]string                  `yaml:"relations,omitempty"`
+}
+
+// exportedService holds a service rendered as part of a bundle.
+type exportedService struct {
+	Charm       string                 `yaml:"charm"`
+	NumUnits    int                    `yaml:"num_units,omitempty"`
+	To          []string               `yaml:"to,omitempty"`
+	Options     map[string]interface{} `yaml:"options,omitempty"`
+	Constraints string                 `yaml:"constraints,omitempty"`
+	Expose      bool                   `yaml:"expose,omitempty"`
+}
+
+// ExportBundle renders the environment's services, with their charms,
+// settings that differ from the charms' defaults, constraints, unit
+// placements and relations, as a bundle that can be deployed with the
+// Bundles facade.
+//
+// Units on dedicated machines are not given placements, so that they
+// are deployed to new machines. Units in containers, or sharing a
+// machine with units of other services, are placed on the same
+// machine ids, which must therefore exist wherever the bundle is
+// deployed.
+func (c *Client) ExportBundle() (params.ExportBundleResult, error) {
+	services, err := c.api.state.AllServices()
+	if err != nil {
+		return params.ExportBundleResult{}, errors.Trace(err)
+	}
+	bundle := exportedBundle{
+		Services: make(map[string]*exportedService),
+	}
+	serviceUnits := make(map[string][]*state.Unit)
+	unitMachines := make(map[string]string)
+	machineUnits := make(map[string]int)
+	for _, svc := range services {
+		if svc.Life() != state.Alive {
+			continue
+		}
+		exported, err := exportService(svc)
+		if err != nil {
+			return params.ExportBundleResult{}, errors.Annotatef(err, "cannot export service %q", svc.Name())
+		}
+		bundle.Services[svc.Name()] = exported
+		if !svc.IsPrincipal() {
+			continue
+		}
+		units, err := aliveUnits(svc)
+		if err != nil {
+			return params.ExportBundleResult{}, errors.Trace(err)
+		}
+		exported.NumUnits = len(units)
+		serviceUnits[svc.Name()] = units
+		for _, unit := range units {
+			machineId, err := unit.AssignedMachineId()
+			if state.IsNotAssigned(err) {
+				continue
+			} else if err != nil {
+				return params.ExportBundleResult{}, errors.Trace(err)
+			}
+			unitMachines[unit.Name()] = machineId
+			machineUnits[machineId]++
+		}
+	}
+	// Placements can only be decided once it is known which machines
+	// host units of more than one service.
+	for serviceName, units := range serviceUnits {
+		exported := bundle.Services[serviceName]
+		for _, unit := range units {
+			machineId, ok := unitMachines[unit.Name()]
+			if !ok {
+				continue
+			}
+			if parentId := state.ParentId(machineId); parentId != "" {
+				containerType := state.ContainerTypeFromId(machineId)
+				exported.To = append(exported.To, fmt.Sprintf("%s:%s", containerType, parentId))
+			} else if machineUnits[machineId] > 1 {
+				exported.To = append(exported.To, machineId)
+			}
+		}
+	}
+	relations, err := c.api.state.AllRelations()
+	if err != nil {
+		return params.ExportBundleResult{}, errors.Trace(err)
+	}
+	for _, rel := range relations {
+		endpoints := rel.Endpoints()
+		if rel.Life() != state.Alive || len(endpoints) != 2 {
+			// Peer relations are added when their service is deployed.
+			continue
+		}
+		pair := []string{endpoints[0].String(), endpoints[1].String()}
+		sort.Strings(pair)
+		bundle.Relations = append(bundle.Relations, pair)
+	}
+	sort.Sort(byEndpoints(bundle.Relations))
+	data, err := goyaml.Marshal(bundle)
+	if err != nil {
+		return params.ExportBundleResult{}, errors.Trace(err)
+	}
+	return params.ExportBundleResult{YAML: string(data)}, nil
+}
+
+// exportService renders everything about the service except its units.
+func exportService(svc *state.Service) (*exportedService, error) {
+	ch, _, err := svc.Charm()
+	if err != nil {
+		return nil, err
+	}
+	exported := &exportedService{
+		Charm:  ch.URL().String(),
+		Expose: svc.IsExposed(),
+	}
+	settings, err := svc.ConfigSettings()
+	if err != nil {
+		return nil, err
+	}
+	defaults := ch.Config().DefaultSettings()
+	for name, value := range settings {
+		if value == nil || value == defaults[name] {
+			continue
+		}
+		if exported.Options == nil {
+			exported.Options = make(map[string]interface{})
+		}
+		exported.Options[name] = value
+	}
+	cons, err := svc.Constraints()
+	if err != nil {
+		return nil, err
+	}
+	exported.Constraints = cons.String()
+	return exported, nil
+}
+
+// aliveUnits returns the service's alive units, lowest-numbered first.
+func aliveUnits(svc *state.Service) ([]*state.Unit, error) {
+	units, err := svc.AllUnits()
+	if err != nil {
+		return nil, err
+	}
+	var alive []*state.Unit
+	for _, unit := range units {
+		if unit.Life() == state.Alive {
+			alive = append(alive, unit)
+		}
+	}
+	sort.Sort(byUnitNumber(alive))
+	return alive, nil
+}
+
+type byEndpoints [][]string
+
+func (r byEndpoints) Len() int      { return len(r) }
+func (r byEndpoints) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byEndpoints) Less(i, j int) bool {
+	if r[i][0] != r[j][0] {
+		return r[i][0] < r[j][0]
+	}
+	return r[i][1] < r[j][1]
+}
@@ -0,0 +1,107 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"fmt"
+
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type exportBundleSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&exportBundleSuite{})
+
+func (s *exportBundleSuite) addRelation(c *gc.C, endpoints ...string) {
+	eps, err := s.State.InferEndpoints(endpoints...)
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *exportBundleSuite) addUnit(c *gc.C, svc *state.Service, machine *state.Machine) {
+	unit, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	if machine != nil {
+		err = unit.AssignToMachine(machine)
+		c.Assert(err, gc.IsNil)
+	}
+}
+
+func (s *exportBundleSuite) TestExportBundle(c *gc.C) {
+	wordpressCharm := s.AddTestingCharm(c, "wordpress")
+	mysqlCharm := s.AddTestingCharm(c, "mysql")
+	loggingCharm := s.AddTestingCharm(c, "logging")
+	wordpress := s.AddTestingService(c, "wordpress", wordpressCharm)
+	mysql := s.AddTestingService(c, "mysql", mysqlCharm)
+	s.AddTestingService(c, "logging", loggingCharm)
+	s.addRelation(c, "wordpress", "mysql")
+	s.addRelation(c, "wordpress", "logging")
+
+	err := wordpress.UpdateConfigSettings(map[string]interface{}{"blog-title": "Exported"})
+	c.Assert(err, gc.IsNil)
+	err = wordpress.SetExposed()
+	c.Assert(err, gc.IsNil)
+	err = mysql.SetConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, gc.IsNil)
+
+	// wordpress/0 shares a machine with mysql/0, wordpress/1 is in a
+	// container and wordpress/2 has a machine of its own.
+	shared, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, shared.Id(), instance.LXC)
+	c.Assert(err, gc.IsNil)
+	dedicated, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	s.addUnit(c, wordpress, shared)
+	s.addUnit(c, wordpress, container)
+	s.addUnit(c, wordpress, dedicated)
+	s.addUnit(c, mysql, shared)
+	s.addUnit(c, mysql, nil)
+
+	bundleYAML, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, gc.IsNil)
+	var bundle, expect interface{}
+	err = goyaml.Unmarshal([]byte(bundleYAML), &bundle)
+	c.Assert(err, gc.IsNil)
+	err = goyaml.Unmarshal([]byte(fmt.Sprintf(`
+services:
+  logging:
+    charm: %s
+  mysql:
+    charm: %s
+    num_units: 2
+    to: [%q]
+    constraints: mem=4096M
+  wordpress:
+    charm: %s
+    num_units: 3
+    to: [%q, %q]
+    options:
+      blog-title: Exported
+    expose: true
+relations:
+  - ["logging:logging-directory", "wordpress:logging-dir"]
+  - ["mysql:server", "wordpress:db"]
+`, loggingCharm.URL(), mysqlCharm.URL(), shared.Id(), wordpressCharm.URL(),
+		shared.Id(), "lxc:"+shared.Id())), &expect)
+	c.Assert(err, gc.IsNil)
+	c.Assert(bundle, gc.DeepEquals, expect)
+}
+
+func (s *exportBundleSuite) TestExportBundleEmpty(c *gc.C) {
+	bundleYAML, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, gc.IsNil)
+	c.Assert(bundleYAML, gc.Equals, "services: {}\n")
+}
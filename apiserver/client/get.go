@@ -32,14 +32,23 @@ func (c *Client) ServiceGet(args params.ServiceGet) (params.ServiceGetResults, e
 			return params.ServiceGetResults{}, err
 		}
 	}
+	policy := service.HookPolicy()
 	return params.ServiceGetResults{
 		Service:     args.ServiceName,
 		Charm:       charm.Meta().Name,
 		Config:      configInfo,
 		Constraints: constraints,
+		HookPolicy: params.ServiceHookPolicy{
+			Timeout:    policy.Timeout,
+			RetryCount: policy.RetryCount,
+			RetryDelay: policy.RetryDelay,
+		},
 	}, nil
 }
 
+// describe returns, for each option in config, its description, type
+// and current value, and whether that value is the charm's default
+// rather than one set by the user.
 func describe(settings charm.Settings, config *charm.Config) map[string]interface{} {
 	results := make(map[string]interface{})
 	for name, option := range config.Options {
@@ -49,6 +58,7 @@ func describe(settings charm.Settings, config *charm.Config) map[string]interfac
 		}
 		if value := settings[name]; value != nil {
 			info["value"] = value
+			info["default"] = false
 		} else {
 			if option.Default != nil {
 				info["value"] = option.Default
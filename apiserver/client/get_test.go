@@ -68,6 +68,7 @@ var getTests = []struct {
 				"description": "A descriptive title used for the service.",
 				"type":        "string",
 				"value":       "Look To Windward",
+				"default":     false,
 			},
 			"outlook": map[string]interface{}{
 				"description": "No default outlook.",
@@ -78,6 +79,7 @@ var getTests = []struct {
 				"description": "The name of the initial account (given admin permissions).",
 				"type":        "string",
 				"value":       "admin001",
+				"default":     false,
 			},
 			"skill-level": map[string]interface{}{
 				"description": "A number indicating skill.",
@@ -111,11 +113,13 @@ var getTests = []struct {
 				"description": "No default outlook.",
 				"type":        "string",
 				"value":       "phlegmatic",
+				"default":     false,
 			},
 			"username": map[string]interface{}{
 				"description": "The name of the initial account (given admin permissions).",
 				"type":        "string",
 				"value":       "foobie",
+				"default":     false,
 			},
 			"skill-level": map[string]interface{}{
 				"description": "A number indicating skill.",
@@ -125,7 +129,8 @@ var getTests = []struct {
 				// API does not preserve int types. This used
 				// to be int64() but we end up with a type
 				// mismatch when comparing the content
-				"value": float64(0),
+				"value":   float64(0),
+				"default": false,
 			},
 		},
 	},
@@ -186,6 +191,7 @@ func (s *getSuite) TestServiceGetMaxResolutionInt(c *gc.C) {
 		"description": "A number indicating skill.",
 		"type":        "int",
 		"value":       asFloat,
+		"default":     false,
 	})
 }
 
@@ -0,0 +1,53 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// AddNetworks adds the given provider networks to the environment,
+// so that services can be deployed with bindings to them.
+func (c *Client) AddNetworks(args params.AddNetworks) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Networks)),
+	}
+	for i, network := range args.Networks {
+		tag, err := names.ParseNetworkTag(network.Tag)
+		if err == nil {
+			_, err = c.api.state.AddNetwork(state.NetworkInfo{
+				Name:       tag.Id(),
+				ProviderId: network.ProviderId,
+				CIDR:       network.CIDR,
+				VLANTag:    network.VLANTag,
+			})
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ListNetworks returns all the networks known in the environment.
+func (c *Client) ListNetworks() (params.NetworksResult, error) {
+	networks, err := c.api.state.AllNetworks()
+	if err != nil {
+		return params.NetworksResult{}, err
+	}
+	result := params.NetworksResult{
+		Networks: make([]params.Network, len(networks)),
+	}
+	for i, network := range networks {
+		result.Networks[i] = params.Network{
+			Tag:        network.Tag().String(),
+			ProviderId: network.ProviderId(),
+			CIDR:       network.CIDR(),
+			VLANTag:    network.VLANTag(),
+		}
+	}
+	return result, nil
+}
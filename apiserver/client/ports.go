@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// MachinePorts returns the port ranges opened on each given machine,
+// with the tags of the units that opened them. When no machines are
+// given, every machine in the environment is reported.
+func (c *Client) MachinePorts(args params.Entities) (params.MachineOpenedPortsResults, error) {
+	var machines []*state.Machine
+	if len(args.Entities) == 0 {
+		all, err := c.api.state.AllMachines()
+		if err != nil {
+			return params.MachineOpenedPortsResults{}, err
+		}
+		machines = all
+	}
+	for _, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			return params.MachineOpenedPortsResults{}, err
+		}
+		machine, err := c.api.state.Machine(tag.Id())
+		if err != nil {
+			return params.MachineOpenedPortsResults{}, err
+		}
+		machines = append(machines, machine)
+	}
+	result := params.MachineOpenedPortsResults{
+		Machines: make([]params.MachineOpenedPorts, len(machines)),
+	}
+	for i, machine := range machines {
+		ports, err := common.MachinePortRanges(machine)
+		if err != nil {
+			return params.MachineOpenedPortsResults{}, errors.Annotatef(err, "cannot get ports of machine %s", machine.Id())
+		}
+		result.Machines[i] = params.MachineOpenedPorts{
+			MachineTag: machine.Tag().String(),
+			Ports:      ports,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// ResolveUnits marks the errors of several units resolved in a single
+// call. Units given by name are always resolved, so that any problem
+// with them is reported; units matched by glob pattern (e.g.
+// "wordpress/*"), or by All, are resolved only if they are in an
+// error state and not already resolved. A failure for any one unit is
+// reported in its result without stopping the others.
+func (c *Client) ResolveUnits(args params.ResolveUnits) (params.ResolveUnitsResults, error) {
+	var unitNames []string
+	var patterns []string
+	switch {
+	case args.All && len(args.Patterns) > 0:
+		return params.ResolveUnitsResults{}, errors.New("cannot specify both units and all")
+	case !args.All && len(args.Patterns) == 0:
+		return params.ResolveUnitsResults{}, errors.New("no units specified")
+	}
+	for _, pattern := range args.Patterns {
+		if names.IsValidUnit(pattern) {
+			unitNames = append(unitNames, pattern)
+		} else {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if args.All || len(patterns) > 0 {
+		matched, err := c.unitsToResolve(patterns, args.All)
+		if err != nil {
+			return params.ResolveUnitsResults{}, err
+		}
+		unitNames = append(unitNames, matched...)
+	}
+	results := params.ResolveUnitsResults{}
+	seen := make(map[string]bool)
+	for _, name := range unitNames {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		result := params.ResolveUnitResult{UnitName: name}
+		unit, err := c.api.state.Unit(name)
+		if err == nil {
+			err = unit.Resolve(args.Retry)
+		}
+		result.Error = common.ServerError(err)
+		results.Results = append(results.Results, result)
+	}
+	return results, nil
+}
+
+// unitsToResolve returns the sorted names of the alive units in an
+// error state, not yet resolved, that match any of the patterns, or
+// all such units if all is true.
+func (c *Client) unitsToResolve(patterns []string, all bool) ([]string, error) {
+	matcher, err := NewUnitMatcher(patterns)
+	if err != nil {
+		return nil, err
+	}
+	units, err := c.allUnits()
+	if err != nil {
+		return nil, err
+	}
+	var unitNames []string
+	for _, unit := range units {
+		if unit.Life() != state.Alive || unit.Resolved() != state.ResolvedNone {
+			continue
+		}
+		if !all && !matcher.matchString(unit.Name()) {
+			continue
+		}
+		status, _, _, err := unit.Status()
+		if err != nil {
+			return nil, err
+		}
+		if status == state.StatusError {
+			unitNames = append(unitNames, unit.Name())
+		}
+	}
+	sort.Strings(unitNames)
+	return unitNames, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state"
+)
+
+// ServiceSetUnitCount adds or destroys units of a service so that it
+// has exactly the given number of alive units. When units must be
+// destroyed, the highest-numbered units not in an error state are
+// chosen first. It returns the names of the units added or destroyed.
+func (c *Client) ServiceSetUnitCount(args params.ServiceSetUnitCount) (params.ServiceSetUnitCountResults, error) {
+	var result params.ServiceSetUnitCountResults
+	if args.Count < 0 {
+		return result, errors.New("unit count must not be negative")
+	}
+	svc, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return result, err
+	}
+	if !svc.IsPrincipal() {
+		return result, errors.Errorf("service %q is a subordinate", args.ServiceName)
+	}
+	units, err := svc.AllUnits()
+	if err != nil {
+		return result, err
+	}
+	var alive []*state.Unit
+	for _, unit := range units {
+		if unit.Life() == state.Alive {
+			alive = append(alive, unit)
+		}
+	}
+	switch delta := args.Count - len(alive); {
+	case delta > 0:
+		added, err := juju.AddUnits(c.api.state, svc, delta, "")
+		if err != nil {
+			return result, err
+		}
+		for _, unit := range added {
+			result.Added = append(result.Added, unit.Name())
+		}
+	case delta < 0:
+		destroy, err := unitsToDestroy(alive, -delta)
+		if err != nil {
+			return result, err
+		}
+		err = c.DestroyServiceUnits(params.DestroyServiceUnits{UnitNames: destroy})
+		if err != nil {
+			return result, err
+		}
+		result.Destroyed = destroy
+	}
+	return result, nil
+}
+
+// unitsToDestroy returns the names of n of the given units, preferring
+// units that are not in an error state, and then the highest-numbered.
+func unitsToDestroy(units []*state.Unit, n int) ([]string, error) {
+	byClean := make(byCleanUnitNumber, len(units))
+	for i, unit := range units {
+		status, _, _, err := unit.Status()
+		if err != nil {
+			return nil, err
+		}
+		byClean[i] = cleanUnit{unit, status != state.StatusError}
+	}
+	sort.Sort(byClean)
+	unitNames := make([]string, n)
+	for i := range unitNames {
+		unitNames[i] = byClean[i].unit.Name()
+	}
+	return unitNames, nil
+}
+
+type cleanUnit struct {
+	unit  *state.Unit
+	clean bool
+}
+
+// byCleanUnitNumber orders clean units before those in an error
+// state, and otherwise orders units from the highest number down.
+type byCleanUnitNumber []cleanUnit
+
+func (u byCleanUnitNumber) Len() int      { return len(u) }
+func (u byCleanUnitNumber) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u byCleanUnitNumber) Less(i, j int) bool {
+	if u[i].clean != u[j].clean {
+		return u[i].clean
+	}
+	return unitNumber(u[i].unit.Name()) > unitNumber(u[j].unit.Name())
+}
@@ -6,6 +6,7 @@ package client
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
@@ -14,6 +15,7 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/tools"
@@ -27,8 +29,12 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 	}
 	var noStatus api.Status
 	var context statusContext
+	context.charmsLocked = cfg.CharmRevisionPolicy() == config.CharmRevisionLocked
+	// Revision information is not reported when the caller or the
+	// environment asks for charm store lookups to be skipped.
+	lookupRevisions := !args.NoStore && !cfg.OfflineMode()
 	if context.services, context.units, context.latestCharms, err =
-		fetchAllServicesAndUnits(c.api.state, len(args.Patterns) <= 0); err != nil {
+		fetchAllServicesAndUnits(c.api.state, len(args.Patterns) <= 0, lookupRevisions); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch services and units")
 	} else if context.machines, err = fetchMachines(c.api.state, nil); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch machines")
@@ -127,6 +133,9 @@ type statusContext struct {
 	units        map[string]map[string]*state.Unit
 	networks     map[string]*state.Network
 	latestCharms map[charm.URL]string
+	// charmsLocked records whether the environment's
+	// charm-revision-policy pins every service to its charm.
+	charmsLocked bool
 }
 
 // fetchMachines returns a map from top level machine id to machines, where machines[0] is the host
@@ -163,9 +172,11 @@ func fetchMachines(st *state.State, machineIds *set.Strings) (map[string][]*stat
 
 // fetchAllServicesAndUnits returns a map from service name to service,
 // a map from service name to unit name to unit, and a map from base charm URL to latest URL.
+// The latest URLs are only looked up if lookupRevisions is true.
 func fetchAllServicesAndUnits(
 	st *state.State,
 	matchAny bool,
+	lookupRevisions bool,
 ) (map[string]*state.Service, map[string]map[string]*state.Unit, map[charm.URL]string, error) {
 
 	svcMap := make(map[string]*state.Service)
@@ -190,7 +201,7 @@ func fetchAllServicesAndUnits(
 			// Record the base URL for the service's charm so that
 			// the latest store revision can be looked up.
 			charmURL, _ := s.CharmURL()
-			if charmURL.Schema == "cs" {
+			if lookupRevisions && charmURL.Schema == "cs" {
 				latestCharms[*charmURL.WithRevision(-1)] = ""
 			}
 		}
@@ -340,6 +351,22 @@ func makeMachineStatus(machine *state.Machine) (status api.MachineStatus) {
 	} else {
 		status.Hardware = hc.String()
 	}
+	utilization, err := machine.Utilization()
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			status.HardwareUtilization = "error"
+		}
+	} else {
+		status.HardwareUtilization = utilization.String()
+	}
+	cons, err := machine.Constraints()
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			status.Constraints = "error"
+		}
+	} else {
+		status.Constraints = cons.String()
+	}
 	status.Containers = make(map[string]api.MachineStatus)
 	return
 }
@@ -432,17 +459,97 @@ func (context *statusContext) processServices() map[string]api.ServiceStatus {
 	for _, s := range context.services {
 		servicesMap[s.Name()] = context.processService(s)
 	}
+	// Subordinate units are only reported alongside their principals,
+	// so the unit statuses of every service are gathered before any
+	// service's status is summarized.
+	unitStatuses := make(map[string][]params.Status)
+	for _, service := range servicesMap {
+		for unitName, unit := range service.Units {
+			serviceName := strings.Split(unitName, "/")[0]
+			unitStatuses[serviceName] = append(unitStatuses[serviceName], unitStatus(unit))
+			for subName, sub := range unit.Subordinates {
+				serviceName := strings.Split(subName, "/")[0]
+				unitStatuses[serviceName] = append(unitStatuses[serviceName], unitStatus(sub))
+			}
+		}
+	}
+	for name, service := range servicesMap {
+		service.Status = aggregateStatus(unitStatuses[name])
+		servicesMap[name] = service
+	}
 	return servicesMap
 }
 
+// statusSeverity orders unit statuses from the worst to the best.
+var statusSeverity = []params.Status{
+	params.StatusError,
+	params.StatusDown,
+	params.StatusStopped,
+	params.StatusPending,
+	params.StatusInstalled,
+	params.StatusStarted,
+}
+
+// severity returns the position of the given status in statusSeverity.
+// Unknown statuses are considered the least severe.
+func severity(status params.Status) int {
+	for i, s := range statusSeverity {
+		if s == status {
+			return i
+		}
+	}
+	return len(statusSeverity)
+}
+
+// unitStatus returns the status of the unit that counts towards its
+// service's status: the worse of the unit's status and its agent's
+// presence.
+func unitStatus(unit api.UnitStatus) params.Status {
+	status := unit.Agent.Status
+	if status == "" || severity(unit.AgentState) < severity(status) {
+		status = unit.AgentState
+	}
+	return status
+}
+
+// aggregateStatus summarizes the given unit statuses.
+func aggregateStatus(statuses []params.Status) api.AggregateStatus {
+	var out api.AggregateStatus
+	if len(statuses) == 0 {
+		return out
+	}
+	out.Counts = make(map[params.Status]int)
+	out.Status = statuses[0]
+	for _, status := range statuses {
+		out.Counts[status]++
+		if severity(status) < severity(out.Status) {
+			out.Status = status
+		}
+	}
+	n := out.Counts[out.Status]
+	units := "units"
+	if n == 1 {
+		units = "unit"
+	}
+	description := string(out.Status)
+	if out.Status == params.StatusError {
+		description = "in error"
+	}
+	out.Info = fmt.Sprintf("%d %s %s", n, units, description)
+	return out
+}
+
 func (context *statusContext) processService(service *state.Service) (status api.ServiceStatus) {
 	serviceCharmURL, _ := service.CharmURL()
 	status.Charm = serviceCharmURL.String()
 	status.Exposed = service.IsExposed()
 	status.Life = processLife(service)
+	status.CharmPinned = service.IsCharmPinned()
 
+	// Newer revisions are not reported for services that are
+	// intentionally held at their current charm.
 	latestCharm, ok := context.latestCharms[*serviceCharmURL.WithRevision(-1)]
-	if ok && latestCharm != serviceCharmURL.String() {
+	if ok && latestCharm != serviceCharmURL.String() && !status.CharmPinned && !context.charmsLocked {
 		status.CanUpgradeTo = latestCharm
 	}
 	var err error
@@ -464,6 +571,12 @@ func (context *statusContext) processService(service *state.Service) (status api
 			status.Err = err
 			return
 		}
+		effective, err := service.EffectiveConstraints()
+		if err != nil {
+			status.Err = err
+			return
+		}
+		status.Constraints = effective.String()
 	}
 	if len(networks) > 0 || cons.HaveNetworks() {
 		// Only the explicitly requested networks (using "juju deploy
@@ -491,6 +604,7 @@ func (context *statusContext) processUnits(units map[string]*state.Unit, service
 
 func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string) (status api.UnitStatus) {
 	status.PublicAddress, _ = unit.PublicAddress()
+	status.PublicAddress = context.reachableAddress(unit, status.PublicAddress)
 	unitPorts, _ := unit.OpenedPorts()
 	for _, port := range unitPorts {
 		status.OpenedPorts = append(status.OpenedPorts, port.String())
@@ -519,6 +633,39 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 	return
 }
 
+// reachableAddress returns the address through which the given unit's
+// opened ports can be reached from outside the environment. Units of
+// exposed services in containers without a public address of their own
+// are reached through the public address of the host machine, whose
+// agent forwards their ports to the container.
+func (context *statusContext) reachableAddress(unit *state.Unit, address string) string {
+	machineId, err := unit.AssignedMachineId()
+	if err != nil {
+		return address
+	}
+	hostId := state.ParentId(machineId)
+	if hostId == "" || state.ParentId(hostId) != "" {
+		return address
+	}
+	service := context.services[unit.ServiceName()]
+	if service == nil || !service.IsExposed() {
+		return address
+	}
+	if network.NewAddress(address, network.ScopeUnknown).Scope == network.ScopePublic {
+		return address
+	}
+	machines := context.machines[hostId]
+	if len(machines) == 0 {
+		return address
+	}
+	hostAddress := network.SelectPublicAddress(machines[0].Addresses())
+	if hostAddress == "" || network.NewAddress(hostAddress, network.ScopeUnknown).Scope == network.ScopeCloudLocal {
+		// The host is no more reachable than the container.
+		return address
+	}
+	return hostAddress
+}
+
 func (context *statusContext) unitByName(name string) *state.Unit {
 	serviceName := strings.Split(name, "/")[0]
 	return context.units[serviceName][name]
@@ -562,6 +709,7 @@ type stateAgent interface {
 	AgentPresence() (bool, error)
 	AgentTools() (*tools.Tools, error)
 	Status() (state.Status, string, map[string]interface{}, error)
+	StatusSince() (time.Time, error)
 }
 
 // processAgent retrieves version and status information from the given entity.
@@ -583,6 +731,9 @@ func processAgent(entity stateAgent) (
 	if out.Err != nil {
 		return
 	}
+	if since, err := entity.StatusSince(); err == nil && !since.IsZero() {
+		out.Since = &since
+	}
 
 	if out.Status == params.StatusPending {
 		// The status is pending - there's no point
@@ -627,7 +778,7 @@ func filterStatusData(status map[string]interface{}) map[string]interface{} {
 	out := make(map[string]interface{})
 	for name, value := range status {
 		// use a set here if we end up with a larger whitelist
-		if name == "relation-id" {
+		if name == "relation-id" || name == "provisioning" {
 			out[name] = value
 		}
 	}
@@ -4,10 +4,16 @@
 package client_test
 
 import (
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
@@ -44,6 +50,115 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.Series, gc.Equals, machine.Series())
 }
 
+func (s *statusSuite) TestFullStatusMachineConstraints(c *gc.C) {
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Constraints: constraints.MustParse("instance-type=m1.small root-disk=16G"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, gc.IsNil)
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, gc.IsNil)
+	resultMachine, ok := status.Machines[machine.Id()]
+	c.Assert(ok, gc.Equals, true)
+	c.Check(resultMachine.Constraints, gc.Equals, "instance-type=m1.small root-disk=16384M")
+}
+
+func (s *statusSuite) TestFullStatusMachineUtilization(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetUtilization(state.MachineUtilization{
+		LoadAverage: 0.5,
+		MemoryUsed:  512,
+		MemoryTotal: 2048,
+		DiskFree:    10240,
+	})
+	c.Assert(err, gc.IsNil)
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, gc.IsNil)
+	resultMachine, ok := status.Machines[machine.Id()]
+	c.Assert(ok, gc.Equals, true)
+	c.Check(resultMachine.HardwareUtilization, gc.Equals, "load=0.50 mem=512M/2048M disk-free=10240M")
+}
+
+func (s *statusSuite) TestFullStatusCharmPinned(c *gc.C) {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress", Revision: "1"})
+	svc := s.Factory.MakeService(c, &factory.ServiceParams{Charm: ch})
+	err := s.State.AddStoreCharmPlaceholder(charm.MustParseURL("cs:quantal/wordpress-2"))
+	c.Assert(err, gc.IsNil)
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(status.Services[svc.Name()].CanUpgradeTo, gc.Equals, "cs:quantal/wordpress-2")
+	c.Check(status.Services[svc.Name()].CharmPinned, gc.Equals, false)
+
+	err = svc.SetCharmPinned(true)
+	c.Assert(err, gc.IsNil)
+	status, err = s.APIState.Client().Status(nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(status.Services[svc.Name()].CanUpgradeTo, gc.Equals, "")
+	c.Check(status.Services[svc.Name()].CharmPinned, gc.Equals, true)
+}
+
+func (s *statusSuite) TestFullStatusCharmRevisionPolicyLocked(c *gc.C) {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress", Revision: "1"})
+	svc := s.Factory.MakeService(c, &factory.ServiceParams{Charm: ch})
+	err := s.State.AddStoreCharmPlaceholder(charm.MustParseURL("cs:quantal/wordpress-2"))
+	c.Assert(err, gc.IsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"charm-revision-policy": "locked",
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(status.Services[svc.Name()].CanUpgradeTo, gc.Equals, "")
+}
+
+func (s *statusSuite) TestFullStatusOfflineMode(c *gc.C) {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress", Revision: "1"})
+	svc := s.Factory.MakeService(c, &factory.ServiceParams{Charm: ch})
+	err := s.State.AddStoreCharmPlaceholder(charm.MustParseURL("cs:quantal/wordpress-2"))
+	c.Assert(err, gc.IsNil)
+
+	status, err := s.APIState.Client().StatusNoStore(nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(status.Services[svc.Name()].CanUpgradeTo, gc.Equals, "")
+
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"offline-mode": true,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	status, err = s.APIState.Client().Status(nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(status.Services[svc.Name()].CanUpgradeTo, gc.Equals, "")
+}
+
+func (s *statusSuite) TestFullStatusServiceStatus(c *gc.C) {
+	svc := s.Factory.MakeService(c, nil)
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(status.Services[svc.Name()].Status, jc.DeepEquals, api.AggregateStatus{})
+
+	u0 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: svc})
+	err = u0.SetStatus(state.StatusError, "hook failed", nil)
+	c.Assert(err, gc.IsNil)
+	u1 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: svc})
+	err = u1.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+
+	// The started unit's agent is not running, so it is reported down.
+	status, err = s.APIState.Client().Status(nil)
+	c.Assert(err, gc.IsNil)
+	c.Check(status.Services[svc.Name()].Status, jc.DeepEquals, api.AggregateStatus{
+		Status: params.StatusError,
+		Info:   "1 unit in error",
+		Counts: map[params.Status]int{
+			params.StatusError: 1,
+			params.StatusDown:  1,
+		},
+	})
+}
+
 func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	machine := s.addMachine(c)
 	instanceId := "i-fakeinstance"
@@ -107,3 +222,32 @@ func (s *statusUnitTestSuite) TestProcessMachinesWithEmbeddedContainers(c *gc.C)
 	c.Check(hostContainer, gc.HasLen, 2)
 	c.Check(hostContainer[lxcHost.Id()].Containers, gc.HasLen, 1)
 }
+
+func (s *statusSuite) TestContainerUnitReachableAddress(c *gc.C) {
+	host := s.addMachine(c)
+	err := host.SetAddresses(network.NewAddress("54.1.2.3", network.ScopeUnknown))
+	c.Assert(err, gc.IsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXC)
+	c.Assert(err, gc.IsNil)
+	err = container.SetMachineAddresses(network.NewAddress("10.0.3.5", network.ScopeUnknown))
+	c.Assert(err, gc.IsNil)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(container)
+	c.Assert(err, gc.IsNil)
+
+	unitAddress := func() string {
+		status, err := s.APIState.Client().Status(nil)
+		c.Assert(err, gc.IsNil)
+		return status.Services["wordpress"].Units["wordpress/0"].PublicAddress
+	}
+	// Ports are only forwarded for exposed services.
+	c.Assert(unitAddress(), gc.Equals, "10.0.3.5")
+	err = wordpress.SetExposed()
+	c.Assert(err, gc.IsNil)
+	c.Assert(unitAddress(), gc.Equals, "54.1.2.3")
+}
@@ -0,0 +1,56 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/juju/apiserver/params"
+)
+
+// AddUnitGroup saves a named group of units, defined by unit name
+// patterns, for later use as the target of other operations.
+func (c *Client) AddUnitGroup(args params.UnitGroup) error {
+	_, err := c.api.state.AddUnitGroup(args.Name, args.Patterns)
+	return err
+}
+
+// RemoveUnitGroup removes the named unit group.
+func (c *Client) RemoveUnitGroup(args params.UnitGroupName) error {
+	group, err := c.api.state.UnitGroup(args.Name)
+	if err != nil {
+		return err
+	}
+	return group.Remove()
+}
+
+// UnitGroups returns all the unit groups in the environment.
+func (c *Client) UnitGroups() (params.UnitGroups, error) {
+	groups, err := c.api.state.AllUnitGroups()
+	if err != nil {
+		return params.UnitGroups{}, err
+	}
+	result := params.UnitGroups{
+		Groups: make([]params.UnitGroup, len(groups)),
+	}
+	for i, group := range groups {
+		result.Groups[i] = params.UnitGroup{
+			Name:     group.Name(),
+			Patterns: group.Patterns(),
+		}
+	}
+	return result, nil
+}
+
+// UnitGroupUnits returns the names of the units currently in the
+// named unit group.
+func (c *Client) UnitGroupUnits(args params.UnitGroupName) (params.UnitGroupUnitsResult, error) {
+	group, err := c.api.state.UnitGroup(args.Name)
+	if err != nil {
+		return params.UnitGroupUnitsResult{}, err
+	}
+	names, err := group.UnitNames()
+	if err != nil {
+		return params.UnitGroupUnitsResult{}, err
+	}
+	return params.UnitGroupUnitsResult{Units: names}, nil
+}
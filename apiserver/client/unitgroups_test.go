@@ -0,0 +1,68 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type unitGroupsSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&unitGroupsSuite{})
+
+func (s *unitGroupsSuite) TestAddUnitGroup(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.AddUnitGroup("canary", "wordpress/0", "logging")
+	c.Assert(err, gc.IsNil)
+
+	group, err := s.State.UnitGroup("canary")
+	c.Assert(err, gc.IsNil)
+	c.Assert(group.Patterns(), gc.DeepEquals, []string{"wordpress/0", "logging"})
+
+	err = client.AddUnitGroup("canary", "mysql")
+	c.Assert(err, gc.ErrorMatches, `cannot add unit group "canary": unit group "canary" already exists`)
+	c.Assert(params.IsCodeAlreadyExists(err), jc.IsTrue)
+}
+
+func (s *unitGroupsSuite) TestUnitGroups(c *gc.C) {
+	s.setUpScenario(c)
+	client := s.APIState.Client()
+	err := client.AddUnitGroup("web", "wordpress")
+	c.Assert(err, gc.IsNil)
+	err = client.AddUnitGroup("canary", "wordpress/0", "logging/1")
+	c.Assert(err, gc.IsNil)
+
+	groups, err := client.UnitGroups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(groups, gc.DeepEquals, []params.UnitGroup{
+		{Name: "canary", Patterns: []string{"wordpress/0", "logging/1"}},
+		{Name: "web", Patterns: []string{"wordpress"}},
+	})
+
+	units, err := client.UnitGroupUnits("canary")
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.DeepEquals, []string{"logging/1", "wordpress/0"})
+	units, err = client.UnitGroupUnits("web")
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.DeepEquals, []string{"wordpress/0", "wordpress/1"})
+}
+
+func (s *unitGroupsSuite) TestRemoveUnitGroup(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.AddUnitGroup("canary", "wordpress/0")
+	c.Assert(err, gc.IsNil)
+	err = client.RemoveUnitGroup("canary")
+	c.Assert(err, gc.IsNil)
+
+	err = client.RemoveUnitGroup("canary")
+	c.Assert(err, gc.ErrorMatches, `unit group "canary" not found`)
+	c.Assert(params.IsCodeNotFound(err), jc.IsTrue)
+	_, err = client.UnitGroupUnits("canary")
+	c.Assert(err, gc.ErrorMatches, `unit group "canary" not found`)
+}
@@ -0,0 +1,111 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// MigrationTargetAccessor defines the methods needed by
+// MigrationTargetGetter.
+type MigrationTargetAccessor interface {
+	MigrationTarget() (*state.MigrationTarget, error)
+	WatchMigrationTarget() state.NotifyWatcher
+}
+
+// MigrationTargetGetter implements the common MigrationTarget and
+// WatchMigrationTarget methods, which tell an environment's agents
+// about the state server that the environment has been moved to.
+type MigrationTargetGetter struct {
+	st         MigrationTargetAccessor
+	resources  *Resources
+	getCanRead GetAuthFunc
+}
+
+// NewMigrationTargetGetter returns a new MigrationTargetGetter. The
+// GetAuthFunc will be used on each invocation of the methods to
+// determine current permissions.
+func NewMigrationTargetGetter(st MigrationTargetAccessor, resources *Resources, getCanRead GetAuthFunc) *MigrationTargetGetter {
+	return &MigrationTargetGetter{
+		st:         st,
+		resources:  resources,
+		getCanRead: getCanRead,
+	}
+}
+
+func (m *MigrationTargetGetter) watch() (string, error) {
+	watch := m.st.WatchMigrationTarget()
+	// Consume the initial event. Technically, API
+	// calls to Watch 'transmit' the initial event
+	// in the Watch response. But NotifyWatchers
+	// have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		return m.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
+
+// WatchMigrationTarget starts a NotifyWatcher for each given agent,
+// which notifies when the agent's environment may have been moved to
+// another state server.
+func (m *MigrationTargetGetter) WatchMigrationTarget(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canRead, err := m.getCanRead()
+	if err != nil {
+		return params.NotifyWatchResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = ServerError(ErrPerm)
+			continue
+		}
+		err = ErrPerm
+		if canRead(tag) {
+			result.Results[i].NotifyWatcherId, err = m.watch()
+		}
+		result.Results[i].Error = ServerError(err)
+	}
+	return result, nil
+}
+
+// MigrationTarget returns, for each given agent, the API addresses
+// and CA certificate of the state server that the agent's environment
+// has been moved to. The error has the code params.CodeNotFound if the
+// environment has not been moved.
+func (m *MigrationTargetGetter) MigrationTarget(args params.Entities) (params.MigrationTargetResults, error) {
+	result := params.MigrationTargetResults{
+		Results: make([]params.MigrationTargetResult, len(args.Entities)),
+	}
+	canRead, err := m.getCanRead()
+	if err != nil {
+		return params.MigrationTargetResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = ServerError(ErrPerm)
+			continue
+		}
+		err = ErrPerm
+		if canRead(tag) {
+			var target *state.MigrationTarget
+			if target, err = m.st.MigrationTarget(); err == nil {
+				result.Results[i].Result = &params.MigrationTarget{
+					APIHostPorts: target.APIHostPorts,
+					CACert:       target.CACert,
+				}
+			}
+		}
+		result.Results[i].Error = ServerError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+type migrationTargetSuite struct{}
+
+var _ = gc.Suite(&migrationTargetSuite{})
+
+type fakeMigrationTargetAccessor struct {
+	target *state.MigrationTarget
+}
+
+func (*fakeMigrationTargetAccessor) WatchMigrationTarget() state.NotifyWatcher {
+	changes := make(chan struct{}, 1)
+	// Simulate initial event.
+	changes <- struct{}{}
+	return &fakeNotifyWatcher{changes}
+}
+
+func (f *fakeMigrationTargetAccessor) MigrationTarget() (*state.MigrationTarget, error) {
+	if f.target == nil {
+		return nil, errors.NotFoundf("migration target")
+	}
+	return f.target, nil
+}
+
+func canReadUnitX0() (common.AuthFunc, error) {
+	x0 := u("x/0")
+	return func(tag names.Tag) bool {
+		return tag == x0
+	}, nil
+}
+
+func (*migrationTargetSuite) TestWatchMigrationTarget(c *gc.C) {
+	resources := common.NewResources()
+	defer resources.StopAll()
+	m := common.NewMigrationTargetGetter(&fakeMigrationTargetAccessor{}, resources, canReadUnitX0)
+	result, err := m.WatchMigrationTarget(params.Entities{[]params.Entity{
+		{"unit-x-0"}, {"unit-x-1"}, {"invalid"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{"1", nil},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	c.Assert(resources.Get("1"), gc.NotNil)
+}
+
+func (*migrationTargetSuite) TestMigrationTarget(c *gc.C) {
+	hostPorts := [][]network.HostPort{
+		network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+	}
+	st := &fakeMigrationTargetAccessor{
+		target: &state.MigrationTarget{APIHostPorts: hostPorts, CACert: "ca-cert"},
+	}
+	m := common.NewMigrationTargetGetter(st, common.NewResources(), canReadUnitX0)
+	result, err := m.MigrationTarget(params.Entities{[]params.Entity{
+		{"unit-x-0"}, {"unit-x-1"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.MigrationTargetResults{
+		Results: []params.MigrationTargetResult{
+			{Result: &params.MigrationTarget{APIHostPorts: hostPorts, CACert: "ca-cert"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (*migrationTargetSuite) TestMigrationTargetNotMoved(c *gc.C) {
+	m := common.NewMigrationTargetGetter(&fakeMigrationTargetAccessor{}, common.NewResources(), canReadUnitX0)
+	result, err := m.MigrationTarget(params.Entities{[]params.Entity{{"unit-x-0"}}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Result, gc.IsNil)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "migration target not found")
+	c.Assert(params.IsCodeNotFound(result.Results[0].Error), gc.Equals, true)
+}
+
+func (*migrationTargetSuite) TestMigrationTargetError(c *gc.C) {
+	getCanRead := func() (common.AuthFunc, error) {
+		return nil, fmt.Errorf("pow")
+	}
+	m := common.NewMigrationTargetGetter(&fakeMigrationTargetAccessor{}, common.NewResources(), getCanRead)
+	_, err := m.MigrationTarget(params.Entities{[]params.Entity{{"unit-x-0"}}})
+	c.Assert(err, gc.ErrorMatches, "pow")
+	_, err = m.WatchMigrationTarget(params.Entities{[]params.Entity{{"unit-x-0"}}})
+	c.Assert(err, gc.ErrorMatches, "pow")
+}
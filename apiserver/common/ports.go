@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// MachinePortRanges returns the port ranges opened on the given
+// machine across all its networks, with the tags of the units that
+// opened them. Within each network the port ranges are sorted, as
+// API results require a stable order.
+func MachinePortRanges(machine *state.Machine) ([]params.MachinePortRange, error) {
+	allPorts, err := machine.AllPorts()
+	if err != nil {
+		return nil, err
+	}
+	var result []params.MachinePortRange
+	for _, ports := range allPorts {
+		portRangesToUnits := ports.AllPortRanges()
+		portRanges := make([]network.PortRange, 0, len(portRangesToUnits))
+		for portRange := range portRangesToUnits {
+			portRanges = append(portRanges, portRange)
+		}
+		network.SortPortRanges(portRanges)
+		for _, portRange := range portRanges {
+			unitName := portRangesToUnits[portRange]
+			result = append(result, params.MachinePortRange{
+				UnitTag:   names.NewUnitTag(unitName).String(),
+				PortRange: portRange,
+			})
+		}
+	}
+	return result, nil
+}
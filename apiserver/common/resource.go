@@ -7,8 +7,20 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"sync/atomic"
 )
 
+// registeredCount holds the number of resources registered with
+// Register, across all connections, that have not yet been stopped.
+var registeredCount int64
+
+// RegisteredResourceCount returns the number of resources registered
+// with Register across all API connections and not yet stopped. In
+// practice these are the watchers held open for clients.
+func RegisteredResourceCount() int64 {
+	return atomic.LoadInt64(&registeredCount)
+}
+
 // Resource represents any resource that should be cleaned up when an
 // API connection terminates. The Stop method will be called when
 // that happens.
@@ -48,6 +60,7 @@ func (rs *Resources) Register(r Resource) string {
 	rs.maxId++
 	id := strconv.FormatUint(rs.maxId, 10)
 	rs.resources[id] = r
+	atomic.AddInt64(&registeredCount, 1)
 	return id
 }
 
@@ -89,7 +102,10 @@ func (rs *Resources) Stop(id string) error {
 	err := r.Stop()
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
-	delete(rs.resources, id)
+	if _, ok := rs.resources[id]; ok {
+		delete(rs.resources, id)
+		rs.unregistered(id)
+	}
 	return err
 }
 
@@ -97,14 +113,24 @@ func (rs *Resources) Stop(id string) error {
 func (rs *Resources) StopAll() {
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
-	for _, r := range rs.resources {
+	for id, r := range rs.resources {
 		if err := r.Stop(); err != nil {
 			logger.Errorf("error stopping %T resource: %v", r, err)
 		}
+		rs.unregistered(id)
 	}
 	rs.resources = make(map[string]Resource)
 }
 
+// unregistered updates the registered resource count when the
+// resource with the given id is removed. Named resources are not
+// counted.
+func (rs *Resources) unregistered(id string) {
+	if _, err := strconv.ParseUint(id, 10, 64); err == nil {
+		atomic.AddInt64(&registeredCount, -1)
+	}
+}
+
 // Count returns the number of resources currently held.
 func (rs *Resources) Count() int {
 	rs.mu.Lock()
@@ -156,3 +156,22 @@ func (resourceSuite) TestStringResource(c *gc.C) {
 	asStr := rs.Get(id).(common.StringResource).String()
 	c.Check(asStr, gc.Equals, "foobar")
 }
+
+func (resourceSuite) TestRegisteredResourceCount(c *gc.C) {
+	initial := common.RegisteredResourceCount()
+	rs := common.NewResources()
+	id := rs.Register(&fakeResource{})
+	rs.Register(&fakeResource{})
+	err := rs.RegisterNamed("named", &fakeResource{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(common.RegisteredResourceCount(), gc.Equals, initial+2)
+
+	err = rs.Stop(id)
+	c.Assert(err, gc.IsNil)
+	err = rs.Stop(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(common.RegisteredResourceCount(), gc.Equals, initial+1)
+
+	rs.StopAll()
+	c.Assert(common.RegisteredResourceCount(), gc.Equals, initial)
+}
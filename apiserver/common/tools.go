@@ -130,6 +130,10 @@ func (t *ToolsGetter) oneAgentTools(canRead AuthFunc, tag names.Tag, agentVersio
 	if err != nil {
 		return nil, err
 	}
+	agentVersion, err = PinnedAgentVersion(entity, agentVersion)
+	if err != nil {
+		return nil, err
+	}
 	toolsFinder := NewToolsFinder(t.configGetter, t.toolsStorageGetter, t.urlGetter)
 	list, err := toolsFinder.findTools(params.FindToolsParams{
 		Number:       agentVersion,
@@ -144,6 +148,39 @@ func (t *ToolsGetter) oneAgentTools(canRead AuthFunc, tag names.Tag, agentVersio
 	return list[0], nil
 }
 
+// agentVersionPinner is implemented by entities whose agents may be
+// held at an earlier version than the environment's agent-version.
+type agentVersionPinner interface {
+	EffectiveAgentVersionPin() (version.Number, bool, error)
+}
+
+// PinnedAgentVersion returns the version the agent of the given entity
+// should run in place of desired. An agent pinned at an earlier version
+// is held back at that version, but never downgraded: if it already
+// runs a later version than its pin, it stays at that version.
+func PinnedAgentVersion(entity state.Entity, desired version.Number) (version.Number, error) {
+	pinner, ok := entity.(agentVersionPinner)
+	if !ok {
+		return desired, nil
+	}
+	pinned, isPinned, err := pinner.EffectiveAgentVersionPin()
+	if err != nil {
+		return version.Number{}, err
+	}
+	if !isPinned || pinned.Compare(desired) >= 0 {
+		return desired, nil
+	}
+	if tooler, ok := entity.(state.AgentTooler); ok {
+		if current, err := tooler.AgentTools(); err == nil && current.Version.Number.Compare(pinned) > 0 {
+			if current.Version.Number.Compare(desired) < 0 {
+				return current.Version.Number, nil
+			}
+			return desired, nil
+		}
+	}
+	return pinned, nil
+}
+
 // ToolsSetter implements a common Tools method for use by various
 // facades.
 type ToolsSetter struct {
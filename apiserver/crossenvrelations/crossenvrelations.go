@@ -0,0 +1,228 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package crossenvrelations implements the API used to offer services
+// to other environments, and to establish relations to those offers.
+package crossenvrelations
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/featureflag"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.crossenvrelations")
+
+func init() {
+	common.RegisterStandardFacade("CrossEnvRelations", 0, NewAPI)
+}
+
+// API serves the cross-environment relation API methods.
+type API struct {
+	st *state.State
+}
+
+// NewAPI creates a new instance of the CrossEnvRelations API facade.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	if !featureflag.Enabled(feature.CrossEnvRelations) {
+		return nil, errors.NotSupportedf("cross-environment relations")
+	}
+	return &API{st: st}, nil
+}
+
+// Offer makes the given service endpoints available for use by
+// services in other environments.
+func (api *API) Offer(args params.ServiceOffers) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Offers)),
+	}
+	for i, offer := range args.Offers {
+		err := api.st.AddServiceOffer(state.ServiceOffer{
+			URL:         offer.URL,
+			ServiceName: offer.ServiceName,
+			Endpoints:   offer.Endpoints,
+			Description: offer.Description,
+		})
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ListOffers returns all the service offers made by the environment.
+func (api *API) ListOffers() (params.ServiceOffers, error) {
+	offers, err := api.st.ServiceOffers()
+	if err != nil {
+		return params.ServiceOffers{}, err
+	}
+	result := params.ServiceOffers{
+		Offers: make([]params.ServiceOffer, len(offers)),
+	}
+	for i, offer := range offers {
+		result.Offers[i] = params.ServiceOffer{
+			URL:         offer.URL,
+			ServiceName: offer.ServiceName,
+			Endpoints:   offer.Endpoints,
+			Description: offer.Description,
+		}
+	}
+	return result, nil
+}
+
+// Withdraw removes the service offers with the given URLs.
+func (api *API) Withdraw(args params.ServiceOfferURLs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.URLs)),
+	}
+	for i, url := range args.URLs {
+		err := api.st.RemoveServiceOffer(url)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RegisterRemoteRelations records that services in other environments
+// are consuming service offers, together with the addresses their
+// units will connect from. The offered service is not exposed: its
+// ports must only become reachable from those addresses, which the
+// firewaller cannot yet arrange. The offered endpoints, and the
+// addresses and opened ports of the offered service's units, are
+// returned for the consuming environment to configure its side of
+// the relation.
+func (api *API) RegisterRemoteRelations(args params.RemoteRelations) (params.RemoteRelationResults, error) {
+	result := params.RemoteRelationResults{
+		Results: make([]params.RemoteRelationResult, len(args.Relations)),
+	}
+	for i, rel := range args.Relations {
+		one, err := api.registerOneRemoteRelation(rel)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i] = one
+	}
+	return result, nil
+}
+
+func (api *API) registerOneRemoteRelation(rel params.RemoteRelation) (params.RemoteRelationResult, error) {
+	var nothing params.RemoteRelationResult
+	err := api.st.AddRemoteRelation(state.RemoteRelation{
+		OfferURL:         rel.OfferURL,
+		ConsumerEnvUUID:  rel.ConsumerEnvUUID,
+		ConsumerService:  rel.ConsumerService,
+		IngressAddresses: rel.IngressAddresses,
+	})
+	if err != nil {
+		return nothing, err
+	}
+	logger.Infof("service %q in environment %s is consuming %q",
+		rel.ConsumerService, rel.ConsumerEnvUUID, rel.OfferURL)
+	offer, err := api.st.ServiceOffer(rel.OfferURL)
+	if err != nil {
+		return nothing, err
+	}
+	service, err := api.st.Service(offer.ServiceName)
+	if err != nil {
+		return nothing, err
+	}
+	result := params.RemoteRelationResult{
+		EnvUUID:     api.st.EnvironTag().Id(),
+		ServiceName: offer.ServiceName,
+	}
+	for _, name := range offer.Endpoints {
+		ep, err := service.Endpoint(name)
+		if err != nil {
+			return nothing, err
+		}
+		result.Endpoints = append(result.Endpoints, params.RemoteEndpoint{
+			Name:      ep.Name,
+			Interface: ep.Interface,
+			Role:      string(ep.Role),
+		})
+	}
+	units, err := service.AllUnits()
+	if err != nil {
+		return nothing, err
+	}
+	seen := make(map[network.PortRange]bool)
+	for _, unit := range units {
+		if addr, ok := unit.PublicAddress(); ok {
+			result.Addresses = append(result.Addresses, addr)
+		}
+		ports, err := unit.OpenedPorts()
+		if err != nil {
+			return nothing, err
+		}
+		for _, portRange := range ports {
+			if !seen[portRange] {
+				seen[portRange] = true
+				result.Ports = append(result.Ports, portRange)
+			}
+		}
+	}
+	network.SortPortRanges(result.Ports)
+	return result, nil
+}
+
+// UnregisterRemoteRelations records that services in other
+// environments no longer consume service offers. Any of their units
+// still in the relations' scopes leave them.
+func (api *API) UnregisterRemoteRelations(args params.RemoteRelations) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Relations)),
+	}
+	for i, rel := range args.Relations {
+		err := api.st.RemoveRemoteRelation(remoteRelation(rel.OfferURL, rel.ConsumerEnvUUID, rel.ConsumerService))
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// EnterRemoteScope records that units of consuming services have
+// joined their remote relations, and publishes their relation
+// settings to the offering environment. Units already in scope have
+// their settings replaced.
+func (api *API) EnterRemoteScope(args params.RemoteRelationUnits) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Units)),
+	}
+	for i, unit := range args.Units {
+		rel := remoteRelation(unit.OfferURL, unit.ConsumerEnvUUID, unit.ConsumerService)
+		err := api.st.EnterRemoteScope(rel, state.RemoteRelationUnit{
+			Name:     unit.Unit,
+			Settings: unit.Settings,
+		})
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// LeaveRemoteScope records that units of consuming services have
+// departed their remote relations.
+func (api *API) LeaveRemoteScope(args params.RemoteRelationUnits) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Units)),
+	}
+	for i, unit := range args.Units {
+		rel := remoteRelation(unit.OfferURL, unit.ConsumerEnvUUID, unit.ConsumerService)
+		err := api.st.LeaveRemoteScope(rel, unit.Unit)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func remoteRelation(offerURL, consumerEnvUUID, consumerService string) state.RemoteRelation {
+	return state.RemoteRelation{
+		OfferURL:        offerURL,
+		ConsumerEnvUUID: consumerEnvUUID,
+		ConsumerService: consumerService,
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossenvrelations_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/crossenvrelations"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/juju/osenv"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type crossEnvRelationsSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *crossenvrelations.API
+	authorizer apiservertesting.FakeAuthorizer
+	wordpress  *state.Service
+}
+
+var _ = gc.Suite(&crossEnvRelationsSuite{})
+
+func (s *crossEnvRelationsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, feature.CrossEnvRelations)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = crossenvrelations.NewAPI(s.State, nil, s.authorizer)
+	c.Assert(err, gc.IsNil)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *crossEnvRelationsSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	anAuthoriser := s.authorizer
+	anAuthoriser.Tag = names.NewMachineTag("1")
+	endPoint, err := crossenvrelations.NewAPI(s.State, nil, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *crossEnvRelationsSuite) TestNewAPIRequiresFeatureFlag(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, "")
+	endPoint, err := crossenvrelations.NewAPI(s.State, nil, s.authorizer)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *crossEnvRelationsSuite) TestOfferListWithdraw(c *gc.C) {
+	offer := params.ServiceOffer{
+		URL:         "local:/u/admin/blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+		Description: "a shared blog",
+	}
+	results, err := s.api.Offer(params.ServiceOffers{Offers: []params.ServiceOffer{
+		offer,
+		{URL: "local:/u/admin/db", ServiceName: "mysql", Endpoints: []string{"server"}},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot add service offer "local:/u/admin/db": service "mysql" not found`)
+
+	offers, err := s.api.ListOffers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(offers, jc.DeepEquals, params.ServiceOffers{Offers: []params.ServiceOffer{offer}})
+
+	results, err = s.api.Withdraw(params.ServiceOfferURLs{URLs: []string{"local:/u/admin/blog"}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.OneError(), gc.IsNil)
+	offers, err = s.api.ListOffers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(offers.Offers, gc.HasLen, 0)
+}
+
+func (s *crossEnvRelationsSuite) TestRegisterRemoteRelations(c *gc.C) {
+	err := s.State.AddServiceOffer(state.ServiceOffer{
+		URL:         "local:/u/admin/blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+	})
+	c.Assert(err, gc.IsNil)
+	f := factory.NewFactory(s.State)
+	machine := f.MakeMachine(c, nil)
+	err = machine.SetAddresses(network.NewAddress("8.8.8.8", network.ScopePublic))
+	c.Assert(err, gc.IsNil)
+	unit := f.MakeUnit(c, &factory.UnitParams{Service: s.wordpress, Machine: machine})
+	err = unit.OpenPorts("tcp", 8080, 8081)
+	c.Assert(err, gc.IsNil)
+	err = unit.OpenPort("tcp", 80)
+	c.Assert(err, gc.IsNil)
+
+	consumerEnvUUID := "ffffffff-ffff-ffff-ffff-ffffffffffff"
+	results, err := s.api.RegisterRemoteRelations(params.RemoteRelations{Relations: []params.RemoteRelation{{
+		OfferURL:         "local:/u/admin/blog",
+		ConsumerEnvUUID:  consumerEnvUUID,
+		ConsumerService:  "haproxy",
+		IngressAddresses: []string{"10.0.0.1"},
+	}, {
+		OfferURL:        "local:/u/admin/missing",
+		ConsumerEnvUUID: consumerEnvUUID,
+		ConsumerService: "haproxy",
+	}}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0], jc.DeepEquals, params.RemoteRelationResult{
+		EnvUUID:     s.State.EnvironTag().Id(),
+		ServiceName: "wordpress",
+		Endpoints: []params.RemoteEndpoint{
+			{Name: "url", Interface: "http", Role: "provider"},
+		},
+		Addresses: []string{"8.8.8.8"},
+		Ports: []network.PortRange{
+			{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+			{FromPort: 8080, ToPort: 8081, Protocol: "tcp"},
+		},
+	})
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot add remote relation to "local:/u/admin/missing": service offer "local:/u/admin/missing" not found`)
+
+	rels, err := s.State.RemoteRelations("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	c.Assert(rels, jc.DeepEquals, []state.RemoteRelation{{
+		OfferURL:         "local:/u/admin/blog",
+		ConsumerEnvUUID:  consumerEnvUUID,
+		ConsumerService:  "haproxy",
+		IngressAddresses: []string{"10.0.0.1"},
+	}})
+	err = s.wordpress.Refresh()
+	c.Assert(err, gc.IsNil)
+	// Exposing the service would open it to everyone, not just
+	// the consumer.
+	c.Assert(s.wordpress.IsExposed(), jc.IsFalse)
+}
+
+func (s *crossEnvRelationsSuite) TestRemoteScope(c *gc.C) {
+	err := s.State.AddServiceOffer(state.ServiceOffer{
+		URL:         "local:/u/admin/blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+	})
+	c.Assert(err, gc.IsNil)
+	rel := state.RemoteRelation{
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "haproxy",
+	}
+	err = s.State.AddRemoteRelation(rel)
+	c.Assert(err, gc.IsNil)
+
+	args := params.RemoteRelationUnits{Units: []params.RemoteRelationUnit{{
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "haproxy",
+		Unit:            "haproxy/0",
+		Settings:        params.RelationSettings{"private-address": "10.0.0.1"},
+	}, {
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "nagios",
+		Unit:            "nagios/0",
+	}}}
+	results, err := s.api.EnterRemoteScope(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot enter scope of remote relation to "local:/u/admin/blog" for unit "nagios/0": remote relation not found`)
+	units, err := s.State.RemoteRelationUnits(rel)
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, jc.DeepEquals, []state.RemoteRelationUnit{{
+		Name:     "haproxy/0",
+		Settings: map[string]string{"private-address": "10.0.0.1"},
+	}})
+
+	results, err = s.api.LeaveRemoteScope(params.RemoteRelationUnits{Units: args.Units[:1]})
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.OneError(), gc.IsNil)
+	units, err = s.State.RemoteRelationUnits(rel)
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *crossEnvRelationsSuite) TestUnregisterRemoteRelations(c *gc.C) {
+	err := s.State.AddServiceOffer(state.ServiceOffer{
+		URL:         "local:/u/admin/blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+	})
+	c.Assert(err, gc.IsNil)
+	rel := state.RemoteRelation{
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "haproxy",
+	}
+	err = s.State.AddRemoteRelation(rel)
+	c.Assert(err, gc.IsNil)
+	err = s.State.EnterRemoteScope(rel, state.RemoteRelationUnit{Name: "haproxy/0"})
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.api.UnregisterRemoteRelations(params.RemoteRelations{Relations: []params.RemoteRelation{{
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "haproxy",
+	}, {
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "nagios",
+	}}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot remove remote relation to "local:/u/admin/blog": remote relation not found`)
+
+	rels, err := s.State.RemoteRelations("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	c.Assert(rels, gc.HasLen, 0)
+	units, err := s.State.RemoteRelationUnits(rel)
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
@@ -20,6 +20,7 @@ import (
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/utils/entitylog"
 )
 
 // debugLogHandler takes requests to watch the debug log.
@@ -34,6 +35,8 @@ var maxLinesReached = fmt.Errorf("max lines reached")
 // Args for the HTTP request are as follows:
 //   includeEntity -> []string - lists entity tags to include in the response
 //      - tags may finish with a '*' to match a prefix e.g.: unit-mysql-*, machine-2
+//      - a line matches if either the agent that logged it or the entity
+//        it is tagged with (see the entitylog package) matches
 //      - if none are set, then all lines are considered included
 //   includeModule -> []string - lists logging modules to include in the response
 //      - if none are set, then all lines are considered included
@@ -177,6 +180,9 @@ type logLine struct {
 	agent  string
 	level  loggo.Level
 	module string
+	// entity holds the tag of the entity the message concerns, when
+	// the agent tagged the message with one.
+	entity string
 }
 
 func parseLogLine(line string) *logLine {
@@ -199,12 +205,25 @@ func parseLogLine(line string) *logLine {
 		if level, valid := loggo.ParseLevel(fields[levelField]); valid {
 			result.level = level
 			result.module = fields[moduleField]
+			result.entity = parseEntity(fields[moduleField+1:])
 		}
 	}
 
 	return result
 }
 
+// parseEntity returns the entity tag from the prefix of a log message,
+// given the fields following the module. The message may be preceded
+// by the location of the logging call.
+func parseEntity(fields []string) string {
+	for i := 0; i < len(fields) && i < 2; i++ {
+		if tag, ok := entitylog.ParsePrefix(fields[i]); ok {
+			return tag
+		}
+	}
+	return ""
+}
+
 // logStream runs the tailer to read a log file and stream
 // it via a web socket.
 type logStream struct {
@@ -272,17 +291,31 @@ func (stream *logStream) countedFilterLine(line []byte) bool {
 	return result
 }
 
+// matchEntity reports whether the agent that logged the line, or the
+// entity the line is tagged with, matches the given tag.
+func (line *logLine) matchEntity(value string) bool {
+	for _, tag := range []string{line.agent, line.entity} {
+		if tag == "" {
+			continue
+		}
+		// special handling, if ends with '*', check prefix
+		if strings.HasSuffix(value, "*") {
+			if strings.HasPrefix(tag, value[:len(value)-1]) {
+				return true
+			}
+		} else if tag == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (stream *logStream) checkIncludeEntity(line *logLine) bool {
 	if len(stream.includeEntity) == 0 {
 		return true
 	}
 	for _, value := range stream.includeEntity {
-		// special handling, if ends with '*', check prefix
-		if strings.HasSuffix(value, "*") {
-			if strings.HasPrefix(line.agent, value[:len(value)-1]) {
-				return true
-			}
-		} else if line.agent == value {
+		if line.matchEntity(value) {
 			return true
 		}
 	}
@@ -303,12 +336,7 @@ func (stream *logStream) checkIncludeModule(line *logLine) bool {
 
 func (stream *logStream) exclude(line *logLine) bool {
 	for _, value := range stream.excludeEntity {
-		// special handling, if ends with '*', check prefix
-		if strings.HasSuffix(value, "*") {
-			if strings.HasPrefix(line.agent, value[:len(value)-1]) {
-				return true
-			}
-		} else if line.agent == value {
+		if line.matchEntity(value) {
 			return true
 		}
 	}
@@ -34,6 +34,17 @@ func (s *debugInternalSuite) TestParseLogLine(c *gc.C) {
 	c.Assert(logLine.module, gc.Equals, "juju.cmd.jujud")
 }
 
+func (s *debugInternalSuite) TestParseLogLineWithEntity(c *gc.C) {
+	line := `machine-0: 2014-03-24 22:34:25 INFO juju.worker.deployer deployer.go:148 [unit-mysql-0] deploying unit "mysql/0"`
+	logLine := parseLogLine(line)
+	c.Assert(logLine.agent, gc.Equals, "machine-0")
+	c.Assert(logLine.module, gc.Equals, "juju.worker.deployer")
+	c.Assert(logLine.entity, gc.Equals, "unit-mysql-0")
+
+	line = "machine-0: 2014-03-24 22:34:25 INFO juju.cmd.jujud machine.go:127 machine agent machine-0 start (1.17.7.1-trusty-amd64 [gc])"
+	c.Assert(parseLogLine(line).entity, gc.Equals, "")
+}
+
 func (s *debugInternalSuite) TestParseLogLineMachineMultiline(c *gc.C) {
 	line := "machine-1: continuation line"
 	logLine := parseLogLine(line)
@@ -103,6 +114,20 @@ func (s *debugInternalSuite) TestCheckIncludeEntity(c *gc.C) {
 	c.Check(checkIncludeEntity("machine-0-lxc-0", "machine-0-lxc-*"), jc.IsTrue)
 }
 
+func checkIncludeTaggedEntity(agent, entity string, include ...string) bool {
+	stream := &logStream{includeEntity: include}
+	line := &logLine{agent: agent, entity: entity}
+	return stream.checkIncludeEntity(line)
+}
+
+func (s *debugInternalSuite) TestCheckIncludeTaggedEntity(c *gc.C) {
+	c.Check(checkIncludeTaggedEntity("machine-0", "unit-mysql-0", "unit-mysql-0"), jc.IsTrue)
+	c.Check(checkIncludeTaggedEntity("machine-0", "unit-mysql-0", "unit-mysql-*"), jc.IsTrue)
+	c.Check(checkIncludeTaggedEntity("machine-0", "unit-mysql-0", "machine-0"), jc.IsTrue)
+	c.Check(checkIncludeTaggedEntity("machine-0", "unit-mysql-1", "unit-mysql-0"), jc.IsFalse)
+	c.Check(checkIncludeTaggedEntity("machine-0", "", "unit-mysql-0"), jc.IsFalse)
+}
+
 func checkIncludeModule(logValue string, module ...string) bool {
 	stream := &logStream{includeModule: module}
 	line := &logLine{module: logValue}
@@ -135,6 +160,13 @@ func (s *debugInternalSuite) TestCheckExcludeEntity(c *gc.C) {
 	c.Check(checkExcludeEntity("machine-0-lxc-0", "machine-0-lxc-*"), jc.IsTrue)
 }
 
+func (s *debugInternalSuite) TestCheckExcludeTaggedEntity(c *gc.C) {
+	stream := &logStream{excludeEntity: []string{"unit-mysql-0"}}
+	c.Check(stream.exclude(&logLine{agent: "machine-0", entity: "unit-mysql-0"}), jc.IsTrue)
+	c.Check(stream.exclude(&logLine{agent: "machine-0", entity: "unit-mysql-1"}), jc.IsFalse)
+	c.Check(stream.exclude(&logLine{agent: "machine-0"}), jc.IsFalse)
+}
+
 func checkExcludeModule(logValue string, module ...string) bool {
 	stream := &logStream{excludeModule: module}
 	line := &logLine{module: logValue}
@@ -0,0 +1,237 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package environmentmanager implements the API used to create, list
+// and migrate the environments hosted by a state server.
+package environmentmanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/featureflag"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.environmentmanager")
+
+func init() {
+	common.RegisterStandardFacade("EnvironmentManager", 0, NewEnvironmentManagerAPI)
+}
+
+// EnvironmentManagerAPI implements the environment manager API.
+type EnvironmentManagerAPI struct {
+	state      *state.State
+	authorizer common.Authorizer
+}
+
+// NewEnvironmentManagerAPI creates a new instance of the
+// EnvironmentManager API facade.
+func NewEnvironmentManagerAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*EnvironmentManagerAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &EnvironmentManagerAPI{
+		state:      st,
+		authorizer: authorizer,
+	}, nil
+}
+
+// authCheck returns common.ErrPerm unless the logged in user is the
+// given user or the owner of the state server environment.
+func (em *EnvironmentManagerAPI) authCheck(user names.UserTag) error {
+	authTag, ok := em.authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return common.ErrPerm
+	}
+	if authTag == user {
+		return nil
+	}
+	ssEnv, err := em.state.StateServerEnvironment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if authTag != ssEnv.Owner() {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// newEnvironConfig returns the configuration for a new environment.
+// The given attributes override those of the environment the call was
+// made to, and the new environment is given a fresh UUID.
+func (em *EnvironmentManagerAPI) newEnvironConfig(args map[string]interface{}) (*config.Config, error) {
+	if name, _ := args["name"].(string); name == "" {
+		return nil, errors.New("environment name must be specified")
+	}
+	if _, ok := args["uuid"]; ok {
+		return nil, errors.New("uuid is generated, you cannot specify one")
+	}
+	baseCfg, err := em.state.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	attrs := baseCfg.AllAttrs()
+	for key, value := range args {
+		attrs[key] = value
+	}
+	attrs["uuid"] = uuid.String()
+	return config.New(config.NoDefaults, attrs)
+}
+
+// CreateEnvironment creates a new environment hosted by the state
+// server, owned by the given user.
+func (em *EnvironmentManagerAPI) CreateEnvironment(args params.EnvironmentCreateArgs) (params.Environment, error) {
+	result := params.Environment{}
+	if !featureflag.Enabled(feature.JES) {
+		return result, errors.NotSupportedf("hosting more than one environment")
+	}
+	owner, err := names.ParseUserTag(args.OwnerTag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := em.authCheck(owner); err != nil {
+		return result, errors.Trace(err)
+	}
+	cfg, err := em.newEnvironConfig(args.Config)
+	if err != nil {
+		return result, errors.Annotate(err, "invalid environment config")
+	}
+	env, st, err := em.state.NewHostedEnvironment(cfg, owner)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	defer st.Close()
+	logger.Infof("created environment %q (%s) for %s", env.Name(), env.UUID(), owner.Username())
+
+	result.Name = env.Name()
+	result.UUID = env.UUID()
+	result.OwnerTag = env.Owner().String()
+	return result, nil
+}
+
+// ListEnvironments returns the environments that the given user has
+// access to.
+func (em *EnvironmentManagerAPI) ListEnvironments(user params.Entity) (params.EnvironmentList, error) {
+	result := params.EnvironmentList{}
+	userTag, err := names.ParseUserTag(user.Tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := em.authCheck(userTag); err != nil {
+		return result, errors.Trace(err)
+	}
+	envs, err := em.state.EnvironmentsForUser(userTag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, env := range envs {
+		result.Environments = append(result.Environments, params.Environment{
+			Name:     env.Name(),
+			UUID:     env.UUID(),
+			OwnerTag: env.Owner().String(),
+		})
+	}
+	return result, nil
+}
+
+// ExportEnvironment returns a description of the environment the
+// connection is logged in to, from which it can be recreated in
+// another state server.
+func (em *EnvironmentManagerAPI) ExportEnvironment() (params.SerializedEnvironment, error) {
+	result := params.SerializedEnvironment{}
+	env, err := em.state.Environment()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := em.authCheck(env.Owner()); err != nil {
+		return result, errors.Trace(err)
+	}
+	model, err := em.state.Export()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if result.Bytes, err = migration.Serialize(model); err != nil {
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// ImportEnvironment recreates an exported environment in the state
+// server. Only the owner of the state server environment may import
+// environments. The result includes the state server's API addresses
+// and CA certificate, to be passed to SetMigrationTarget on the
+// original environment.
+func (em *EnvironmentManagerAPI) ImportEnvironment(args params.SerializedEnvironment) (params.ImportedEnvironment, error) {
+	result := params.ImportedEnvironment{}
+	if !featureflag.Enabled(feature.JES) {
+		return result, errors.NotSupportedf("hosting more than one environment")
+	}
+	ssEnv, err := em.state.StateServerEnvironment()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := em.authCheck(ssEnv.Owner()); err != nil {
+		return result, errors.Trace(err)
+	}
+	model, err := migration.Deserialize(args.Bytes)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	env, st, err := em.state.Import(model)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	defer st.Close()
+	logger.Infof("imported environment %q (%s)", env.Name(), env.UUID())
+
+	result.Environment = params.Environment{
+		Name:     env.Name(),
+		UUID:     env.UUID(),
+		OwnerTag: env.Owner().String(),
+	}
+	if result.Target.APIHostPorts, err = em.state.APIHostPorts(); err != nil {
+		return result, errors.Annotate(err, "cannot get API addresses")
+	}
+	cfg, err := em.state.EnvironConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Target.CACert, _ = cfg.CACert()
+	return result, nil
+}
+
+// SetMigrationTarget records that the environment the connection is
+// logged in to has been imported into the given state server, so that
+// the environment's agents reconnect to it.
+func (em *EnvironmentManagerAPI) SetMigrationTarget(args params.MigrationTarget) error {
+	if !featureflag.Enabled(feature.JES) {
+		return errors.NotSupportedf("hosting more than one environment")
+	}
+	env, err := em.state.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := em.authCheck(env.Owner()); err != nil {
+		return errors.Trace(err)
+	}
+	return env.SetMigrationTarget(state.MigrationTarget{
+		APIHostPorts: args.APIHostPorts,
+		CACert:       args.CACert,
+	})
+}
@@ -0,0 +1,196 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environmentmanager_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/environmentmanager"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/juju/osenv"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type envManagerSuite struct {
+	jujutesting.JujuConnSuite
+
+	envmanager *environmentmanager.EnvironmentManagerAPI
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&envManagerSuite{})
+
+func (s *envManagerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, feature.JES)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.envmanager, err = environmentmanager.NewEnvironmentManagerAPI(s.State, nil, s.authorizer)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *envManagerSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	anAuthoriser := s.authorizer
+	anAuthoriser.Tag = names.NewMachineTag("1")
+	endPoint, err := environmentmanager.NewEnvironmentManagerAPI(s.State, nil, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *envManagerSuite) TestCreateEnvironment(c *gc.C) {
+	owner := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	env, err := s.envmanager.CreateEnvironment(params.EnvironmentCreateArgs{
+		OwnerTag: owner.String(),
+		Config:   map[string]interface{}{"name": "bobs-env"},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(env.Name, gc.Equals, "bobs-env")
+	c.Assert(env.OwnerTag, gc.Equals, owner.String())
+
+	stateServerEnv, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	c.Assert(env.UUID, gc.Not(gc.Equals), stateServerEnv.UUID())
+
+	hosted, err := s.State.GetEnvironment(names.NewEnvironTag(env.UUID))
+	c.Assert(err, gc.IsNil)
+	c.Assert(hosted.ServerTag(), gc.Equals, stateServerEnv.EnvironTag())
+
+	result, err := s.envmanager.ListEnvironments(params.Entity{Tag: owner.String()})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Environments, gc.DeepEquals, []params.Environment{env})
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentRequiresFeatureFlag(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, "")
+	_, err := s.envmanager.CreateEnvironment(params.EnvironmentCreateArgs{
+		OwnerTag: s.AdminUserTag(c).String(),
+		Config:   map[string]interface{}{"name": "test-env"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentValidatesConfig(c *gc.C) {
+	owner := s.AdminUserTag(c).String()
+	_, err := s.envmanager.CreateEnvironment(params.EnvironmentCreateArgs{
+		OwnerTag: owner,
+		Config:   map[string]interface{}{},
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid environment config: environment name must be specified")
+
+	_, err = s.envmanager.CreateEnvironment(params.EnvironmentCreateArgs{
+		OwnerTag: owner,
+		Config: map[string]interface{}{
+			"name": "other",
+			"uuid": "fake",
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid environment config: uuid is generated, you cannot specify one")
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentForOtherUserRequiresAdmin(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	s.authorizer.Tag = bob
+	envmanager, err := environmentmanager.NewEnvironmentManagerAPI(s.State, nil, s.authorizer)
+	c.Assert(err, gc.IsNil)
+
+	_, err = envmanager.CreateEnvironment(params.EnvironmentCreateArgs{
+		OwnerTag: s.AdminUserTag(c).String(),
+		Config:   map[string]interface{}{"name": "not-bobs"},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	_, err = envmanager.ListEnvironments(params.Entity{Tag: s.AdminUserTag(c).String()})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *envManagerSuite) TestExportEnvironment(c *gc.C) {
+	result, err := s.envmanager.ExportEnvironment()
+	c.Assert(err, gc.IsNil)
+	model, err := migration.Deserialize(result.Bytes)
+	c.Assert(err, gc.IsNil)
+	c.Assert(model.Config["name"], gc.Equals, "dummyenv")
+	c.Assert(model.Config["uuid"], gc.Equals, s.State.EnvironTag().Id())
+	c.Assert(model.Owner, gc.Equals, s.AdminUserTag(c).Username())
+}
+
+func (s *envManagerSuite) TestImportExistingEnvironment(c *gc.C) {
+	exported, err := s.envmanager.ExportEnvironment()
+	c.Assert(err, gc.IsNil)
+	_, err = s.envmanager.ImportEnvironment(exported)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment "dummyenv": environment with UUID ".*" already exists`)
+}
+
+func (s *envManagerSuite) TestImportEnvironmentRequiresFeatureFlag(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, "")
+	exported, err := s.envmanager.ExportEnvironment()
+	c.Assert(err, gc.IsNil)
+	_, err = s.envmanager.ImportEnvironment(exported)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *envManagerSuite) TestImportInvalidDescription(c *gc.C) {
+	_, err := s.envmanager.ImportEnvironment(params.SerializedEnvironment{
+		Bytes: []byte("version: 99\n"),
+	})
+	c.Assert(err, gc.ErrorMatches, "environment description version 99 not supported")
+}
+
+func (s *envManagerSuite) TestExportImportRequireAdmin(c *gc.C) {
+	exported, err := s.envmanager.ExportEnvironment()
+	c.Assert(err, gc.IsNil)
+
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	s.authorizer.Tag = bob
+	envmanager, err := environmentmanager.NewEnvironmentManagerAPI(s.State, nil, s.authorizer)
+	c.Assert(err, gc.IsNil)
+
+	_, err = envmanager.ExportEnvironment()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = envmanager.ImportEnvironment(exported)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *envManagerSuite) TestSetMigrationTarget(c *gc.C) {
+	hostPorts := [][]network.HostPort{
+		network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+	}
+	err := s.envmanager.SetMigrationTarget(params.MigrationTarget{
+		APIHostPorts: hostPorts,
+		CACert:       coretesting.CACert,
+	})
+	c.Assert(err, gc.IsNil)
+
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	target, err := env.MigrationTarget()
+	c.Assert(err, gc.IsNil)
+	c.Assert(target.APIHostPorts, jc.DeepEquals, hostPorts)
+	c.Assert(target.CACert, gc.Equals, coretesting.CACert)
+}
+
+func (s *envManagerSuite) TestSetMigrationTargetRequiresAdmin(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	s.authorizer.Tag = bob
+	envmanager, err := environmentmanager.NewEnvironmentManagerAPI(s.State, nil, s.authorizer)
+	c.Assert(err, gc.IsNil)
+
+	err = envmanager.SetMigrationTarget(params.MigrationTarget{
+		APIHostPorts: [][]network.HostPort{
+			network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+		},
+		CACert: coretesting.CACert,
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -19,6 +19,7 @@ var (
 	NewPingTimeout        = newPingTimeout
 	MaxClientPingInterval = &maxClientPingInterval
 	MongoPingInterval     = &mongoPingInterval
+	CharmStoreCacheSize   = &charmStoreCacheSize
 )
 
 const LoginRateLimit = loginRateLimit
@@ -49,8 +50,7 @@ func NewErrRoot(err error) *errRoot {
 // Just enough to let you probe some of the interfaces of ApiHandler, but not
 // enough to actually do any RPC calls
 func TestingApiRoot(st *state.State) rpc.MethodFinder {
-	srv := &Server{state: st}
-	h := newApiRoot(srv, common.NewResources(), nil)
+	h := newApiRoot(st, common.NewResources(), nil)
 	return h
 }
 
@@ -132,3 +132,18 @@ func TestingAboutToRestoreRoot(st *state.State) *aboutToRestoreRoot {
 	r := TestingApiRoot(st)
 	return newAboutToRestoreRoot(r)
 }
+
+// TestingTokenRoot returns a limited "tokenRoot" as if the user had
+// logged in with an API token, which checkToken reports the validity
+// of.
+func TestingTokenRoot(st *state.State, checkToken func() error) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newTokenRoot(r, checkToken)
+}
+
+// TestingReadOnlyRoot returns a limited "readOnlyRoot" as if the
+// user had only been granted read access to the environment.
+func TestingReadOnlyRoot(st *state.State) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newReadOnlyRoot(r)
+}
@@ -73,6 +73,30 @@ func (s *firewallerSuite) TestGetExposed(c *gc.C) {
 	s.testGetExposed(c, s.firewaller)
 }
 
+func (s *firewallerSuite) TestGetUnexposedPorts(c *gc.C) {
+	err := s.service.SetExposed()
+	c.Assert(err, gc.IsNil)
+	err = s.service.UnexposePorts([]network.PortRange{{8080, 8080, "tcp"}})
+	c.Assert(err, gc.IsNil)
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.service.Tag().String()},
+	}})
+	result, err := s.firewaller.GetUnexposedPorts(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.PortRangesResults{
+		Results: []params.PortRangesResult{
+			{PortRanges: []network.PortRange{{8080, 8080, "tcp"}}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`service "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *firewallerSuite) TestOpenedPortsNotImplemented(c *gc.C) {
 	apiservertesting.AssertNotImplemented(c, s.firewaller, "OpenedPorts")
 }
@@ -180,6 +204,42 @@ func (s *firewallerSuite) TestGetMachinePorts(c *gc.C) {
 
 }
 
+func (s *firewallerSuite) TestMachinePorts(c *gc.C) {
+	s.openPorts(c)
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: s.machines[1].Tag().String()},
+		{Tag: s.machines[2].Tag().String()},
+		{Tag: s.service.Tag().String()},
+		{Tag: s.units[0].Tag().String()},
+	}})
+	unit0Tag := s.units[0].Tag().String()
+	unit2Tag := s.units[2].Tag().String()
+	result, err := s.firewaller.MachinePorts(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.MachinePortsResults{
+		Results: []params.MachinePortsResult{
+			{Ports: []params.MachinePortRange{
+				{UnitTag: unit0Tag, PortRange: network.PortRange{1234, 1400, "tcp"}},
+				{UnitTag: unit0Tag, PortRange: network.PortRange{4321, 4321, "tcp"}},
+			}},
+			{Ports: nil},
+			{Ports: []params.MachinePortRange{
+				{UnitTag: unit2Tag, PortRange: network.PortRange{1111, 2222, "udp"}},
+			}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *firewallerSuite) TestGetMachineActiveNetworks(c *gc.C) {
 	s.openPorts(c)
 
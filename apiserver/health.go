@@ -0,0 +1,103 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/replicaset"
+)
+
+const (
+	healthOK = "ok"
+
+	// healthUnreachable is reported when mongo cannot be reached.
+	// The cause is logged rather than reported, since it can name
+	// internal addresses.
+	healthUnreachable = "unreachable"
+)
+
+// healthHandler reports whether the state server is fit to serve API
+// requests, so that load balancers in front of highly available state
+// servers can route around unhealthy ones. It requires no
+// authentication and reveals nothing about the environment.
+type healthHandler struct {
+	session *mgo.Session
+	dying   <-chan struct{}
+}
+
+// ServeHTTP implements http.Handler. The response status is 200 when
+// the state server is healthy and 503 otherwise.
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	result := h.check()
+	statusCode := http.StatusOK
+	if !result.Healthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		logger.Errorf("cannot marshal health result: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if r.Method == "GET" {
+		w.Write(body)
+	}
+}
+
+func (h *healthHandler) check() params.HealthResult {
+	result := params.HealthResult{
+		Mongo:          healthOK,
+		ReplicaSetRole: "unknown",
+		APIServer:      "running",
+	}
+	select {
+	case <-h.dying:
+		result.APIServer = "stopping"
+	default:
+	}
+	session := h.session.Copy()
+	defer session.Close()
+	if err := session.Ping(); err != nil {
+		logger.Warningf("health check cannot ping mongo: %v", err)
+		result.Mongo = healthUnreachable
+		return result
+	}
+	isMaster, err := replicaset.IsMaster(session)
+	if err != nil {
+		logger.Warningf("health check cannot get replica set status: %v", err)
+		result.Mongo = healthUnreachable
+		return result
+	}
+	result.ReplicaSetRole = replicaSetRole(isMaster)
+	switch result.ReplicaSetRole {
+	case "primary", "secondary", "standalone":
+		result.Healthy = result.APIServer == "running"
+	}
+	return result
+}
+
+func replicaSetRole(results *replicaset.IsMasterResults) string {
+	switch {
+	case results.ReplicaSetName == "" && results.IsMaster:
+		return "standalone"
+	case results.IsMaster:
+		return "primary"
+	case results.Secondary:
+		return "secondary"
+	case results.Arbiter:
+		return "arbiter"
+	}
+	return "unknown"
+}
@@ -0,0 +1,52 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type healthSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&healthSuite{})
+
+func (s *healthSuite) healthURI(c *gc.C) string {
+	uri := s.baseURL(c)
+	uri.Path = "/health"
+	return uri.String()
+}
+
+func (s *healthSuite) TestHealthRequiresNoAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.healthURI(c), "", nil)
+	c.Assert(err, gc.IsNil)
+	body := assertResponse(c, resp, http.StatusOK, "application/json")
+	var result params.HealthResult
+	err = json.Unmarshal(body, &result)
+	c.Assert(err, gc.IsNil)
+	c.Check(result.Healthy, gc.Equals, true)
+	c.Check(result.Mongo, gc.Equals, "ok")
+	c.Check(result.APIServer, gc.Equals, "running")
+	c.Check(result.ReplicaSetRole, gc.Matches, "primary|standalone")
+}
+
+func (s *healthSuite) TestHealthHead(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "HEAD", s.healthURI(c), "", nil)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+}
+
+func (s *healthSuite) TestHealthRequiresGETOrHEAD(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "POST", s.healthURI(c), "", nil)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusMethodNotAllowed)
+}
@@ -28,7 +28,14 @@ type httpHandler struct {
 
 // authenticate parses HTTP basic authentication and authorizes the
 // request by looking up the provided tag and password against state.
+// Only users may authenticate.
 func (h *httpHandler) authenticate(r *http.Request) error {
+	return h.authenticateEntity(r, false)
+}
+
+// authenticateEntity is like authenticate, but also accepts the
+// credentials of unit agents if allowAgents is true.
+func (h *httpHandler) authenticateEntity(r *http.Request, allowAgents bool) error {
 	parts := strings.Fields(r.Header.Get("Authorization"))
 	if len(parts) != 2 || parts[0] != "Basic" {
 		// Invalid header format or no header provided.
@@ -44,16 +51,37 @@ func (h *httpHandler) authenticate(r *http.Request) error {
 	if len(tagPass) != 2 {
 		return fmt.Errorf("invalid request format")
 	}
-	// Only allow users, not agents.
-	if _, err := names.ParseUserTag(tagPass[0]); err != nil {
+	tag, err := names.ParseTag(tagPass[0])
+	if err != nil {
+		return common.ErrBadCreds
+	}
+	switch tag.(type) {
+	case names.UserTag:
+	case names.UnitTag:
+		if !allowAgents {
+			return common.ErrBadCreds
+		}
+	default:
+		// Other agents have no use for the HTTP endpoints.
 		return common.ErrBadCreds
 	}
 	// Ensure the credentials are correct.
-	_, err = checkCreds(h.state, params.LoginRequest{
+	entity, err := checkCreds(h.state, params.LoginRequest{
 		AuthTag:     tagPass[0],
 		Credentials: tagPass[1],
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	// Users with read access to the environment may only download.
+	if _, isUser := tag.(names.UserTag); isUser && r.Method != "GET" {
+		if readOnly, err := hasReadOnlyAccess(h.state, entity, tagPass[1]); err != nil {
+			return err
+		} else if readOnly {
+			return common.ErrPerm
+		}
+	}
+	return nil
 }
 
 func (h *httpHandler) getEnvironUUID(r *http.Request) string {
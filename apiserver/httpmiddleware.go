@@ -0,0 +1,147 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// defaultMaxCharmUploadSize is the largest charm archive that
+	// may be uploaded when the environment configuration does not
+	// specify a limit, or cannot be read.
+	defaultMaxCharmUploadSize = 1 << 30
+
+	// defaultMaxToolsUploadSize is the largest tools tarball that
+	// may be uploaded when the environment configuration does not
+	// specify a limit, or cannot be read.
+	defaultMaxToolsUploadSize = 1 << 30
+
+	// requestIdHeader holds the name of the response header
+	// carrying the id assigned to each HTTP request, which is
+	// also recorded in the access log.
+	requestIdHeader = "X-Juju-Request-Id"
+)
+
+var httpRequestCounter int64
+
+// httpEndpoint wraps one of the API server's plain HTTP handlers
+// with the checks common to all of them, so that each endpoint
+// behaves consistently: requests are given an id and logged,
+// the environment UUID in the path is validated, authentication is
+// required for the given methods, and request bodies are limited
+// in size.
+type httpEndpoint struct {
+	httpHandler
+
+	// sender is used to report errors in the format expected
+	// by clients of the wrapped handler.
+	sender errorSender
+
+	// authMethods holds the request methods that require the
+	// client to authenticate as a user; "*" matches any method.
+	authMethods []string
+
+	// allowAgents holds whether unit agents may authenticate as
+	// well as users.
+	allowAgents bool
+
+	// maxBodySize, if not nil, returns the maximum size of a
+	// request body in bytes. It is called for each request, so
+	// that a changed limit applies without restarting the server.
+	maxBodySize func() int64
+
+	// websocket holds whether the handler serves a websocket. Such
+	// a handler reports errors over the socket once it is open, so
+	// it validates the environment UUID and authenticates the
+	// client itself, and the endpoint only gives its requests an
+	// id and logs them.
+	websocket bool
+
+	handler http.Handler
+}
+
+func (e *httpEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := atomic.AddInt64(&httpRequestCounter, 1)
+	rw := &accessLogResponseWriter{ResponseWriter: w, status: http.StatusOK}
+	rw.Header().Set(requestIdHeader, fmt.Sprintf("%X", id))
+	start := time.Now()
+	defer func() {
+		logger.Infof("[%X] HTTP %s %s from %s: status %d, %d bytes in %v",
+			id, r.Method, r.URL.Path, r.RemoteAddr, rw.status, rw.written, time.Since(start))
+	}()
+
+	if e.websocket {
+		e.handler.ServeHTTP(rw, r)
+		return
+	}
+	if err := e.validateEnvironUUID(r); err != nil {
+		e.sender.sendError(rw, http.StatusNotFound, err.Error())
+		return
+	}
+	if e.requiresAuth(r.Method) {
+		if err := e.authenticateEntity(r, e.allowAgents); err != nil {
+			e.authError(rw, e.sender)
+			return
+		}
+	}
+	if e.maxBodySize != nil {
+		maxBodySize := e.maxBodySize()
+		if r.ContentLength > maxBodySize {
+			e.sender.sendError(rw, http.StatusRequestEntityTooLarge, bodyTooLargeMessage(maxBodySize))
+			return
+		}
+		r.Body = http.MaxBytesReader(rw, r.Body, maxBodySize)
+	}
+	e.handler.ServeHTTP(rw, r)
+}
+
+func (e *httpEndpoint) requiresAuth(method string) bool {
+	for _, m := range e.authMethods {
+		if m == method || m == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func bodyTooLargeMessage(maxBodySize int64) string {
+	return fmt.Sprintf("request body exceeds the limit of %d bytes", maxBodySize)
+}
+
+// accessLogResponseWriter records the status code and the size of a
+// response for the access log.
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+func (w *accessLogResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogResponseWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.written += int64(n)
+	return n, err
+}
+
+// Hijack implements http.Hijacker, so that handlers serving
+// websockets can be wrapped too.
+func (w *accessLogResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
@@ -0,0 +1,112 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type httpEndpointSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&httpEndpointSuite{})
+
+type recordingSender struct {
+	statusCode int
+	message    string
+}
+
+func (s *recordingSender) sendError(w http.ResponseWriter, statusCode int, message string) {
+	s.statusCode = statusCode
+	s.message = message
+	w.WriteHeader(statusCode)
+}
+
+func (s *httpEndpointSuite) TestRequestIdAndStatus(c *gc.C) {
+	endpoint := &httpEndpoint{
+		sender: &recordingSender{},
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}),
+	}
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/charms", nil)
+		c.Assert(err, gc.IsNil)
+		endpoint.ServeHTTP(w, req)
+		c.Check(w.Code, gc.Equals, http.StatusTeapot)
+		c.Check(w.Header().Get(requestIdHeader), gc.Matches, "[0-9A-F]+")
+	}
+}
+
+func (s *httpEndpointSuite) TestRejectsDeclaredOversizedBody(c *gc.C) {
+	sender := &recordingSender{}
+	endpoint := &httpEndpoint{
+		sender:      sender,
+		maxBodySize: func() int64 { return 4 },
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.Errorf("handler called unexpectedly")
+		}),
+	}
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/charms", strings.NewReader("too long"))
+	c.Assert(err, gc.IsNil)
+	endpoint.ServeHTTP(w, req)
+	c.Check(w.Code, gc.Equals, http.StatusRequestEntityTooLarge)
+	c.Check(sender.message, gc.Equals, "request body exceeds the limit of 4 bytes")
+}
+
+func (s *httpEndpointSuite) TestLimitsUndeclaredBody(c *gc.C) {
+	var readErr error
+	endpoint := &httpEndpoint{
+		sender:      &recordingSender{},
+		maxBodySize: func() int64 { return 4 },
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, readErr = ioutil.ReadAll(r.Body)
+		}),
+	}
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/charms", ioutil.NopCloser(strings.NewReader("too long")))
+	c.Assert(err, gc.IsNil)
+	c.Assert(req.ContentLength, gc.Equals, int64(0))
+	endpoint.ServeHTTP(w, req)
+	c.Assert(readErr, gc.ErrorMatches, ".*request body too large")
+}
+
+func (s *httpEndpointSuite) TestAuthRequiredForMethods(c *gc.C) {
+	endpoint := &httpEndpoint{
+		authMethods: []string{"POST"},
+	}
+	c.Check(endpoint.requiresAuth("POST"), gc.Equals, true)
+	c.Check(endpoint.requiresAuth("GET"), gc.Equals, false)
+	endpoint.authMethods = []string{"*"}
+	c.Check(endpoint.requiresAuth("GET"), gc.Equals, true)
+}
+
+func (s *httpEndpointSuite) TestWebsocketLeavesChecksToHandler(c *gc.C) {
+	called := false
+	endpoint := &httpEndpoint{
+		authMethods: []string{"*"},
+		websocket:   true,
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}),
+	}
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/log", nil)
+	c.Assert(err, gc.IsNil)
+	endpoint.ServeHTTP(w, req)
+	c.Check(called, gc.Equals, true)
+	c.Check(w.Header().Get(requestIdHeader), gc.Matches, "[0-9A-F]+")
+}
@@ -0,0 +1,76 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// txnMetricsHandler reports the state server's transaction metrics
+// over HTTPS, so that contention in the transaction layer can be
+// diagnosed.
+type txnMetricsHandler struct {
+	httpHandler
+}
+
+// ServeHTTP implements http.Handler. Environment validation and
+// authentication are left to the httpEndpoint wrapping the handler.
+func (h *txnMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		metrics := txnMetricsParams(state.TransactionMetrics())
+		h.sendJSON(w, http.StatusOK, &params.TxnMetricsResult{Metrics: &metrics})
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+	}
+}
+
+// sendJSON sends a JSON-encoded response to the client.
+func (h *txnMetricsHandler) sendJSON(w http.ResponseWriter, statusCode int, response *params.TxnMetricsResult) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	w.Write(body)
+	return nil
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *txnMetricsHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	logger.Debugf("sending error: %v %v", statusCode, message)
+	err := common.ServerError(errors.New(message))
+	if err := h.sendJSON(w, statusCode, &params.TxnMetricsResult{Error: err}); err != nil {
+		logger.Errorf("failed to send error: %v", err)
+	}
+}
+
+func txnMetricsParams(m state.TxnMetrics) params.TxnMetrics {
+	latency := make([]params.TxnLatencyBucket, len(m.Latency))
+	for i, bucket := range m.Latency {
+		latency[i] = params.TxnLatencyBucket{
+			UpperBoundMs: int64(bucket.UpperBound / time.Millisecond),
+			Count:        bucket.Count,
+		}
+	}
+	return params.TxnMetrics{
+		Transactions:        m.Transactions,
+		Attempts:            m.Attempts,
+		Retries:             m.Retries,
+		Aborted:             m.Aborted,
+		ExcessiveContention: m.ExcessiveContention,
+		Failed:              m.Failed,
+		Latency:             latency,
+	}
+}
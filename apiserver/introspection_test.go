@@ -0,0 +1,78 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type introspectionSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&introspectionSuite{})
+
+func (s *introspectionSuite) TestTxnMetricsRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.txnMetricsURI(c, ""), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *introspectionSuite) TestTxnMetricsRequiresGET(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.txnMetricsURI(c, ""), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *introspectionSuite) TestTxnMetricsRejectsWrongEnvUUIDPath(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.txnMetricsURI(c, "dead-beef-123456"), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `unknown environment: "dead-beef-123456"`)
+}
+
+func (s *introspectionSuite) TestTxnMetrics(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	environ, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	for _, uuid := range []string{"", environ.UUID()} {
+		resp, err := s.authRequest(c, "GET", s.txnMetricsURI(c, uuid), "", nil)
+		c.Assert(err, gc.IsNil)
+		body := assertResponse(c, resp, http.StatusOK, "application/json")
+		var result params.TxnMetricsResult
+		err = json.Unmarshal(body, &result)
+		c.Assert(err, gc.IsNil)
+		c.Assert(result.Error, gc.IsNil)
+		c.Assert(result.Metrics, gc.NotNil)
+		c.Check(result.Metrics.Transactions > 0, gc.Equals, true)
+		c.Check(result.Metrics.Attempts >= result.Metrics.Transactions, gc.Equals, true)
+		c.Check(result.Metrics.Latency, gc.Not(gc.HasLen), 0)
+	}
+}
+
+func (s *introspectionSuite) txnMetricsURI(c *gc.C, uuid string) string {
+	uri := s.baseURL(c)
+	if uuid == "" {
+		uri.Path = "/introspection/txn"
+	} else {
+		uri.Path = fmt.Sprintf("/environment/%s/introspection/txn", uuid)
+	}
+	return uri.String()
+}
+
+func (s *introspectionSuite) assertErrorResponse(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, "application/json")
+	var result params.TxnMetricsResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error, gc.ErrorMatches, expError)
+}
@@ -91,3 +91,39 @@ func (api *MachinerAPI) SetMachineAddresses(args params.SetMachinesAddresses) (p
 	}
 	return results, nil
 }
+
+// SetMachineUtilization records the resource usage reported by machine
+// agents for their hosts.
+func (api *MachinerAPI) SetMachineUtilization(args params.SetMachinesUtilization) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.MachineUtilization)),
+	}
+	canModify, err := api.getCanModify()
+	if err != nil {
+		return results, err
+	}
+	for i, arg := range args.MachineUtilization {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canModify(tag) {
+			var m *state.Machine
+			m, err = api.getMachine(tag)
+			if err == nil {
+				err = m.SetUtilization(state.MachineUtilization{
+					LoadAverage: arg.LoadAverage,
+					MemoryUsed:  arg.MemoryUsed,
+					MemoryTotal: arg.MemoryTotal,
+					DiskFree:    arg.DiskFree,
+				})
+			} else if errors.IsNotFound(err) {
+				err = common.ErrPerm
+			}
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
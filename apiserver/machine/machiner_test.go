@@ -4,6 +4,8 @@
 package machine_test
 
 import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/names"
@@ -184,6 +186,33 @@ func (s *machinerSuite) TestSetMachineAddresses(c *gc.C) {
 	c.Assert(s.machine0.MachineAddresses(), gc.HasLen, 0)
 }
 
+func (s *machinerSuite) TestSetMachineUtilization(c *gc.C) {
+	args := params.SetMachinesUtilization{MachineUtilization: []params.MachineUtilization{
+		{Tag: "machine-1", LoadAverage: 0.5, MemoryUsed: 512, MemoryTotal: 1024, DiskFree: 2048},
+		{Tag: "machine-0", LoadAverage: 0.5},
+		{Tag: "machine-42", LoadAverage: 0.5},
+	}}
+
+	result, err := s.machiner.SetMachineUtilization(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	u, err := s.machine1.Utilization()
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.LoadAverage, gc.Equals, 0.5)
+	c.Assert(u.MemoryUsed, gc.Equals, uint64(512))
+	c.Assert(u.MemoryTotal, gc.Equals, uint64(1024))
+	c.Assert(u.DiskFree, gc.Equals, uint64(2048))
+	_, err = s.machine0.Utilization()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *machinerSuite) TestWatch(c *gc.C) {
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 
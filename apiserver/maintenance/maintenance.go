@@ -0,0 +1,84 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maintenance
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Maintenance", 0, NewMaintenanceAPI)
+}
+
+// MaintenanceAPI allows clients to inspect and maintain the state
+// database without logging in to the state servers.
+type MaintenanceAPI struct {
+	st *state.State
+}
+
+// NewMaintenanceAPI creates a new instance of the Maintenance API facade.
+// The database is shared by every environment the state server hosts,
+// so only the owner of the state server environment may use it.
+func NewMaintenanceAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*MaintenanceAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	authTag, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	ssEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if authTag != ssEnv.Owner() {
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	return &MaintenanceAPI{st: st}, nil
+}
+
+// DatabaseStatus reports the size and index health of each collection
+// in the state database, and the replication lag of each state server.
+func (api *MaintenanceAPI) DatabaseStatus() (params.DatabaseStatusResult, error) {
+	status, err := api.st.DatabaseStatus()
+	if err != nil {
+		return params.DatabaseStatusResult{}, errors.Trace(err)
+	}
+	result := params.DatabaseStatusResult{
+		Collections: make([]params.CollectionStatus, len(status.Collections)),
+		Members:     make([]params.ReplicaSetMemberStatus, len(status.Members)),
+	}
+	for i, coll := range status.Collections {
+		result.Collections[i] = params.CollectionStatus{
+			Name:           coll.Name,
+			Count:          coll.Count,
+			Size:           coll.Size,
+			StorageSize:    coll.StorageSize,
+			IndexSize:      coll.IndexSize,
+			Indexes:        coll.Indexes,
+			MissingIndexes: coll.MissingIndexes,
+			Capped:         coll.Capped,
+		}
+	}
+	for i, member := range status.Members {
+		result.Members[i] = params.ReplicaSetMemberStatus{
+			Address: member.Address,
+			State:   member.State,
+			Healthy: member.Healthy,
+			Lag:     member.Lag,
+		}
+	}
+	return result, nil
+}
+
+// CompactDatabase compacts, or if requested repairs, the state
+// database. It blocks other database access while it runs.
+func (api *MaintenanceAPI) CompactDatabase(args params.CompactDatabase) error {
+	return errors.Trace(api.st.CompactDatabase(args.Repair))
+}
@@ -0,0 +1,72 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package maintenance_test
+
+import (
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/maintenance"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type maintenanceSuite struct {
+	testing.JujuConnSuite
+
+	api *maintenance.MaintenanceAPI
+}
+
+var _ = gc.Suite(&maintenanceSuite{})
+
+func (s *maintenanceSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = maintenance.NewMaintenanceAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *maintenanceSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := maintenance.NewMaintenanceAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *maintenanceSuite) TestNewAPIRefusesOtherUsers(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: bob.UserTag(),
+	}
+	_, err := maintenance.NewMaintenanceAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *maintenanceSuite) TestDatabaseStatus(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	result, err := s.api.DatabaseStatus()
+	c.Assert(err, gc.IsNil)
+	var found bool
+	for _, coll := range result.Collections {
+		if coll.Name == "services" {
+			found = true
+			c.Check(coll.Count, gc.Equals, int64(1))
+			c.Check(coll.MissingIndexes, gc.HasLen, 0)
+		}
+	}
+	c.Assert(found, gc.Equals, true)
+}
+
+func (s *maintenanceSuite) TestCompactDatabase(c *gc.C) {
+	err := s.api.CompactDatabase(params.CompactDatabase{})
+	c.Assert(err, gc.IsNil)
+}
@@ -0,0 +1,201 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+// serverMetrics holds the counters maintained by the API server for
+// the /metrics endpoint.
+type serverMetrics struct {
+	connections int64
+
+	mu         sync.Mutex
+	calls      map[string]int64
+	callErrors map[string]int64
+}
+
+var apiMetrics = newServerMetrics()
+
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		calls:      make(map[string]int64),
+		callErrors: make(map[string]int64),
+	}
+}
+
+// connectionOpened records that an API connection has been made.
+func (m *serverMetrics) connectionOpened() {
+	atomic.AddInt64(&m.connections, 1)
+}
+
+// connectionClosed records that an API connection has terminated.
+func (m *serverMetrics) connectionClosed() {
+	atomic.AddInt64(&m.connections, -1)
+}
+
+// callCompleted records an API call to the given facade, and whether
+// it failed.
+func (m *serverMetrics) callCompleted(facade string, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[facade]++
+	if failed {
+		m.callErrors[facade]++
+	}
+}
+
+// metricsNotifier is an rpc.RequestNotifier that records the calls
+// made on an API connection. It is attached to every connection,
+// whatever the log level, and passes requests on to next, if it is
+// not nil.
+type metricsNotifier struct {
+	metrics *serverMetrics
+	next    rpc.RequestNotifier
+}
+
+// ServerRequest implements rpc.RequestNotifier.
+func (n *metricsNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ServerRequest(hdr, body)
+	}
+}
+
+// ServerReply implements rpc.RequestNotifier.
+func (n *metricsNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
+	if req.Type != "Pinger" || req.Action != "Ping" {
+		n.metrics.callCompleted(req.Type, hdr.Error != "")
+	}
+	if n.next != nil {
+		n.next.ServerReply(req, hdr, body, timeSpent)
+	}
+}
+
+// ClientRequest implements rpc.RequestNotifier.
+func (n *metricsNotifier) ClientRequest(hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ClientRequest(hdr, body)
+	}
+}
+
+// ClientReply implements rpc.RequestNotifier.
+func (n *metricsNotifier) ClientReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ClientReply(req, hdr, body)
+	}
+}
+
+// metricsHandler serves the state server's internal metrics in the
+// Prometheus text exposition format. It is only enabled when the
+// environment's enable-metrics-endpoint setting is true, and, like the
+// other HTTP endpoints, only serves clients that authenticate as a
+// user.
+type metricsHandler struct {
+	httpHandler
+	metrics *serverMetrics
+	logDir  string
+}
+
+// ServeHTTP implements http.Handler. Authentication is left to the
+// httpEndpoint wrapping the handler.
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.state.EnvironConfig()
+	if err != nil {
+		logger.Errorf("cannot read environment config: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "cannot read environment config")
+		return
+	}
+	if !cfg.EnableMetricsEndpoint() {
+		h.sendError(w, http.StatusNotFound, "metrics endpoint is disabled")
+		return
+	}
+	if r.Method != "GET" {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.render())
+}
+
+// sendError sends a plain text error response.
+func (h *metricsHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(statusCode)
+	fmt.Fprintln(w, message)
+}
+
+// render returns the current metrics in the Prometheus text format.
+func (h *metricsHandler) render() []byte {
+	var buf bytes.Buffer
+	writeHeader := func(name, kind, help string) {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	h.metrics.mu.Lock()
+	calls := copyCounts(h.metrics.calls)
+	callErrors := copyCounts(h.metrics.callErrors)
+	h.metrics.mu.Unlock()
+
+	writeHeader("juju_apiserver_calls_total", "counter", "API calls served, by facade.")
+	for _, facade := range sortedKeys(calls) {
+		fmt.Fprintf(&buf, "juju_apiserver_calls_total{facade=%q} %d\n", facade, calls[facade])
+	}
+	writeHeader("juju_apiserver_call_errors_total", "counter", "API calls that returned an error, by facade.")
+	for _, facade := range sortedKeys(callErrors) {
+		fmt.Fprintf(&buf, "juju_apiserver_call_errors_total{facade=%q} %d\n", facade, callErrors[facade])
+	}
+
+	writeHeader("juju_apiserver_connections", "gauge", "Open API connections.")
+	fmt.Fprintf(&buf, "juju_apiserver_connections %d\n", atomic.LoadInt64(&h.metrics.connections))
+
+	writeHeader("juju_apiserver_watchers", "gauge", "Watchers held open on behalf of API clients.")
+	fmt.Fprintf(&buf, "juju_apiserver_watchers %d\n", common.RegisteredResourceCount())
+
+	txn := state.TransactionMetrics()
+	writeHeader("juju_state_txn_total", "counter", "State transactions run.")
+	fmt.Fprintf(&buf, "juju_state_txn_total %d\n", txn.Transactions)
+	writeHeader("juju_state_txn_retries_total", "counter", "State transaction attempts retried after an assertion failure.")
+	fmt.Fprintf(&buf, "juju_state_txn_retries_total %d\n", txn.Retries)
+	writeHeader("juju_state_txn_aborted_total", "counter", "State transactions aborted.")
+	fmt.Fprintf(&buf, "juju_state_txn_aborted_total %d\n", txn.Aborted)
+	writeHeader("juju_state_txn_failed_total", "counter", "State transactions that failed with an error.")
+	fmt.Fprintf(&buf, "juju_state_txn_failed_total %d\n", txn.Failed)
+
+	if info, err := os.Stat(filepath.Join(h.logDir, "all-machines.log")); err == nil {
+		writeHeader("juju_log_bytes", "gauge", "Size of the aggregated environment log.")
+		fmt.Fprintf(&buf, "juju_log_bytes %d\n", info.Size())
+	}
+	return buf.Bytes()
+}
+
+func copyCounts(counts map[string]int64) map[string]int64 {
+	result := make(map[string]int64, len(counts))
+	for k, v := range counts {
+		result[k] = v
+	}
+	return result
+}
+
+func sortedKeys(counts map[string]int64) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing/factory"
+)
+
+type metricsSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+func (s *metricsSuite) metricsURI(c *gc.C) string {
+	uri := s.baseURL(c)
+	uri.Path = "/metrics"
+	return uri.String()
+}
+
+func (s *metricsSuite) enableMetrics(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"enable-metrics-endpoint": true}, nil, nil)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *metricsSuite) TestMetricsRequiresAuth(c *gc.C) {
+	s.enableMetrics(c)
+	resp, err := s.sendRequest(c, "", "", "GET", s.metricsURI(c), "", nil)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusUnauthorized)
+}
+
+func (s *metricsSuite) TestMetricsRefusesAgents(c *gc.C) {
+	s.enableMetrics(c)
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{Password: "machine-password"})
+	resp, err := s.sendRequest(c, machine.Tag().String(), "machine-password", "GET", s.metricsURI(c), "", nil)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusUnauthorized)
+}
+
+func (s *metricsSuite) TestMetricsDisabledByDefault(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.metricsURI(c), "", nil)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNotFound)
+}
+
+func (s *metricsSuite) TestMetricsRequiresGET(c *gc.C) {
+	s.enableMetrics(c)
+	resp, err := s.authRequest(c, "POST", s.metricsURI(c), "", nil)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusMethodNotAllowed)
+}
+
+func (s *metricsSuite) TestMetrics(c *gc.C) {
+	s.enableMetrics(c)
+	// Make an API call so that there is a facade to report.
+	_, err := s.APIState.Client().EnvironmentGet()
+	c.Assert(err, gc.IsNil)
+
+	resp, err := s.authRequest(c, "GET", s.metricsURI(c), "", nil)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "text/plain; version=0.0.4")
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, gc.IsNil)
+	text := string(body)
+	c.Check(text, gc.Matches, `(?s).*# TYPE juju_apiserver_calls_total counter\n.*`)
+	c.Check(text, gc.Matches, `(?s).*\njuju_apiserver_calls_total\{facade="Client"\} [1-9][0-9]*\n.*`)
+	c.Check(text, gc.Matches, `(?s).*\njuju_apiserver_connections [1-9][0-9]*\n.*`)
+	c.Check(text, gc.Matches, `(?s).*\njuju_apiserver_watchers [0-9]+\n.*`)
+	c.Check(text, gc.Matches, `(?s).*\njuju_state_txn_total [1-9][0-9]*\n.*`)
+	c.Check(text, gc.Matches, `(?s).*\njuju_state_txn_retries_total [0-9]+\n.*`)
+}
+
+func (s *metricsSuite) callCount(c *gc.C, facade string) int {
+	resp, err := s.authRequest(c, "GET", s.metricsURI(c), "", nil)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, gc.IsNil)
+	re := regexp.MustCompile(`\njuju_apiserver_calls_total\{facade="` + facade + `"\} ([0-9]+)\n`)
+	match := re.FindSubmatch(body)
+	if match == nil {
+		return 0
+	}
+	count, err := strconv.Atoi(string(match[1]))
+	c.Assert(err, gc.IsNil)
+	return count
+}
+
+func (s *metricsSuite) TestMetricsRecordedWithoutDebugLogging(c *gc.C) {
+	s.enableMetrics(c)
+	apiLogger := loggo.GetLogger("juju.apiserver")
+	oldLevel := apiLogger.LogLevel()
+	apiLogger.SetLogLevel(loggo.INFO)
+	defer apiLogger.SetLogLevel(oldLevel)
+
+	before := s.callCount(c, "Client")
+	userTag, err := names.ParseUserTag(s.userTag)
+	c.Assert(err, gc.IsNil)
+	st := s.OpenAPIAs(c, userTag, s.password)
+	defer st.Close()
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.callCount(c, "Client"), gc.Equals, before+1)
+}
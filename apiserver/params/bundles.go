@@ -0,0 +1,59 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// BundleDeploy holds the parameters for a Bundles.Deploy call.
+type BundleDeploy struct {
+	// YAML holds the contents of the bundle's bundle.yaml file.
+	YAML string
+}
+
+// BundleChange describes a single change made to the environment
+// while deploying a bundle.
+type BundleChange struct {
+	// Kind holds the kind of change: "add-charm", "deploy", "expose",
+	// "add-relation" or "add-unit".
+	Kind string
+
+	// Id identifies what was changed: a charm URL, service name,
+	// unit name, or the relation's endpoints separated by a space.
+	Id string
+}
+
+// BundleDeployResult holds the result of a Bundles.Deploy call.
+type BundleDeployResult struct {
+	// Id identifies the deployment, which runs on the state server.
+	Id string
+
+	// Changes holds the charms added before the deployment was
+	// started, in the order in which they were added.
+	Changes []BundleChange
+}
+
+// BundleDeployment identifies a bundle deployment.
+type BundleDeployment struct {
+	Id string
+}
+
+// BundleDeploymentStatus holds the result of a
+// Bundles.DeploymentStatus call.
+type BundleDeploymentStatus struct {
+	// Status holds how far the deployment has got: "pending",
+	// "running", "done" or "failed".
+	Status string
+
+	// Changes holds the changes made by the deployment so far, in
+	// the order in which they were made. The changes of a failed
+	// deployment have been undone.
+	Changes []BundleChange
+
+	// Error holds why a failed deployment failed.
+	Error string
+}
+
+// ExportBundleResult holds the result of a Client.ExportBundle call.
+type ExportBundleResult struct {
+	// YAML holds the environment rendered as a bundle.yaml file.
+	YAML string
+}
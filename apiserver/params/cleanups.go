@@ -0,0 +1,28 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// CleanupInfo describes a pending state cleanup.
+type CleanupInfo struct {
+	Id        string
+	Kind      string
+	Prefix    string
+	Created   time.Time
+	Failures  int
+	LastError string
+}
+
+// CleanupsResult holds the pending state cleanups, oldest first.
+type CleanupsResult struct {
+	Cleanups []CleanupInfo
+}
+
+// CleanupIds holds the ids of state cleanups to operate on.
+type CleanupIds struct {
+	Ids []string
+}
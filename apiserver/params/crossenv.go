@@ -0,0 +1,85 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"github.com/juju/juju/network"
+)
+
+// ServiceOffer describes the endpoints of a service offered for use
+// by services in other environments.
+type ServiceOffer struct {
+	URL         string
+	ServiceName string
+	Endpoints   []string
+	Description string
+}
+
+// ServiceOffers holds a list of service offers.
+type ServiceOffers struct {
+	Offers []ServiceOffer
+}
+
+// ServiceOfferURLs holds the URLs of service offers.
+type ServiceOfferURLs struct {
+	URLs []string
+}
+
+// RemoteRelation describes the use of a service offer by a service in
+// another environment, and the addresses from which the consuming
+// service's units will connect.
+type RemoteRelation struct {
+	OfferURL         string
+	ConsumerEnvUUID  string
+	ConsumerService  string
+	IngressAddresses []string
+}
+
+// RemoteRelations holds the parameters for making a
+// RegisterRemoteRelations API call.
+type RemoteRelations struct {
+	Relations []RemoteRelation
+}
+
+// RemoteEndpoint describes an offered service endpoint.
+type RemoteEndpoint struct {
+	Name      string
+	Interface string
+	Role      string
+}
+
+// RemoteRelationResult holds the details a consuming environment
+// needs to relate to a service offer, or an error. Ports holds the
+// port ranges opened by the offered service's units, which the
+// consuming service's units must be able to reach.
+type RemoteRelationResult struct {
+	Error       *Error
+	EnvUUID     string
+	ServiceName string
+	Endpoints   []RemoteEndpoint
+	Addresses   []string
+	Ports       []network.PortRange
+}
+
+// RemoteRelationResults holds the results of a RegisterRemoteRelations
+// API call.
+type RemoteRelationResults struct {
+	Results []RemoteRelationResult
+}
+
+// RemoteRelationUnit describes a unit of a consuming service in the
+// scope of a remote relation, and its relation settings.
+type RemoteRelationUnit struct {
+	OfferURL        string
+	ConsumerEnvUUID string
+	ConsumerService string
+	Unit            string
+	Settings        RelationSettings
+}
+
+// RemoteRelationUnits holds the parameters for making an
+// EnterRemoteScope or LeaveRemoteScope API call.
+type RemoteRelationUnits struct {
+	Units []RemoteRelationUnit
+}
@@ -0,0 +1,66 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"github.com/juju/juju/network"
+)
+
+// EnvironmentCreateArgs holds the parameters for creating a new
+// environment hosted by the state server.
+type EnvironmentCreateArgs struct {
+	// OwnerTag is the tag of the user that will own the environment.
+	OwnerTag string
+
+	// Config holds the configuration of the new environment. It must
+	// include the environment's name; any attributes not given are
+	// taken from the environment the call is made to.
+	Config map[string]interface{}
+}
+
+// Environment holds the summary of an environment.
+type Environment struct {
+	Name     string
+	UUID     string
+	OwnerTag string
+}
+
+// EnvironmentList holds a list of environments.
+type EnvironmentList struct {
+	Environments []Environment
+}
+
+// SerializedEnvironment holds the description of an environment, in
+// the format defined by the migration package.
+type SerializedEnvironment struct {
+	Bytes []byte
+}
+
+// ImportedEnvironment holds the summary of an imported environment,
+// and the details its agents need to connect to the state server it
+// was imported into.
+type ImportedEnvironment struct {
+	Environment Environment
+	Target      MigrationTarget
+}
+
+// MigrationTarget holds the API addresses and CA certificate of the
+// state server that an environment has been moved to.
+type MigrationTarget struct {
+	APIHostPorts [][]network.HostPort
+	CACert       string
+}
+
+// MigrationTargetResult holds the state server that an environment
+// has been moved to, or an error.
+type MigrationTargetResult struct {
+	Result *MigrationTarget
+	Error  *Error
+}
+
+// MigrationTargetResults holds the results of a bulk MigrationTarget
+// call.
+type MigrationTargetResults struct {
+	Results []MigrationTargetResult
+}
@@ -0,0 +1,26 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// HealthResult holds the response to a request for a state server's
+// health. Each component is reported as "ok" or as a description of
+// the problem.
+type HealthResult struct {
+	// Healthy reports whether the state server is fit to serve
+	// API requests.
+	Healthy bool
+
+	// Mongo reports whether the state server can reach its database:
+	// "ok" or "unreachable".
+	Mongo string
+
+	// ReplicaSetRole holds the replica set role of the state server's
+	// mongo instance: "primary", "secondary", "arbiter", "standalone"
+	// or "unknown".
+	ReplicaSetRole string
+
+	// APIServer reports whether the API server is running or is
+	// shutting down.
+	APIServer string
+}
@@ -70,6 +70,19 @@ type PortsResult struct {
 	Ports []network.Port
 }
 
+// PortRangesResults holds the bulk operation result of an API call
+// that returns a slice of network.PortRange.
+type PortRangesResults struct {
+	Results []PortRangesResult
+}
+
+// PortRangesResult holds the result of an API call that returns a
+// slice of network.PortRange or an error.
+type PortRangesResult struct {
+	Error      *Error
+	PortRanges []network.PortRange
+}
+
 // MachinePorts holds a machine and network tags. It's used when
 // referring to opened ports on the machine for a network.
 type MachinePorts struct {
@@ -173,6 +186,20 @@ type BoolResults struct {
 // RelationSettings holds relation settings names and values.
 type RelationSettings map[string]string
 
+// ReservedRelationSettingsPrefix starts the names of relation settings
+// that are reserved for use by Juju; charms may not set them.
+const ReservedRelationSettingsPrefix = "juju-"
+
+// Size returns the size in bytes of the settings, counting the length
+// of every key and value.
+func (s RelationSettings) Size() int {
+	size := 0
+	for k, v := range s {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
 // RelationSettingsResult holds a relation settings map or an error.
 type RelationSettingsResult struct {
 	Error    *Error
@@ -260,6 +287,7 @@ type RelationResult struct {
 	Id       int
 	Key      string
 	Endpoint Endpoint
+	Network  string
 }
 
 // RelationResults holds the result of an API call that returns
@@ -307,6 +335,83 @@ type EntitiesCharmURL struct {
 	Entities []EntityCharmURL
 }
 
+// EntityDebugHooksTranscript holds a unit's tag and the transcript
+// of its debug-hooks session.
+type EntityDebugHooksTranscript struct {
+	Tag        string
+	Transcript string
+}
+
+// EntitiesDebugHooksTranscript holds the parameters for making a
+// SetDebugHooksTranscript API call.
+type EntitiesDebugHooksTranscript struct {
+	Entities []EntityDebugHooksTranscript
+}
+
+// LeaderSettings holds the settings written by a service's leader
+// for the other units of the service to read.
+type LeaderSettings map[string]string
+
+// LeaderSettingsResult holds a service's leader settings or an error.
+type LeaderSettingsResult struct {
+	Error    *Error
+	Settings LeaderSettings
+}
+
+// LeaderSettingsResults holds multiple leader settings or errors.
+type LeaderSettingsResults struct {
+	Results []LeaderSettingsResult
+}
+
+// EntityLeaderSettings holds a unit's tag and changes to be made to
+// its service's leader settings; empty values delete keys.
+type EntityLeaderSettings struct {
+	Tag      string
+	Settings LeaderSettings
+}
+
+// EntitiesLeaderSettings holds the parameters for making a
+// MergeLeaderSettings API call.
+type EntitiesLeaderSettings struct {
+	Entities []EntityLeaderSettings
+}
+
+// StorageInstance describes a block device or filesystem owned by a
+// unit. Location is empty until the instance is attached.
+type StorageInstance struct {
+	Id         string
+	Kind       string
+	Life       Life
+	Location   string
+	Attributes map[string]string
+}
+
+// StorageInstancesResult holds the storage instances of a unit or an
+// error.
+type StorageInstancesResult struct {
+	Error     *Error
+	Instances []StorageInstance
+}
+
+// StorageInstancesResults holds the storage instances of multiple
+// units or errors.
+type StorageInstancesResults struct {
+	Results []StorageInstancesResult
+}
+
+// EntityStorageInstance holds a unit's tag and the id of one of its
+// storage instances.
+type EntityStorageInstance struct {
+	Tag       string
+	StorageId string
+}
+
+// EntitiesStorageInstances holds the parameters for making a
+// StorageDetached API call.
+type EntitiesStorageInstances struct {
+	Entities []EntityStorageInstance
+}
+
 // BytesResult holds the result of an API call that returns a slice
 // of bytes.
 type BytesResult struct {
@@ -460,11 +565,39 @@ type MachineAddresses struct {
 	Addresses []network.Address
 }
 
+// MachineAddressesResult holds the addresses of a machine or an error.
+type MachineAddressesResult struct {
+	Error     *Error
+	Addresses []network.Address
+}
+
+// MachineAddressesResults holds the results of the
+// UniterAPI.MachineAddresses() API call.
+type MachineAddressesResults struct {
+	Results []MachineAddressesResult
+}
+
 // SetMachinesAddresses holds the parameters for making a SetMachineAddresses call.
 type SetMachinesAddresses struct {
 	MachineAddresses []MachineAddresses
 }
 
+// MachineUtilization holds a machine tag and the resource usage of the
+// machine's host. Memory and disk sizes are in megabytes.
+type MachineUtilization struct {
+	Tag         string
+	LoadAverage float64
+	MemoryUsed  uint64
+	MemoryTotal uint64
+	DiskFree    uint64
+}
+
+// SetMachinesUtilization holds the parameters for making a
+// SetMachineUtilization call.
+type SetMachinesUtilization struct {
+	MachineUtilization []MachineUtilization
+}
+
 // ConstraintsResult holds machine constraints or an error.
 type ConstraintsResult struct {
 	Error       *Error
@@ -547,6 +680,25 @@ type NotifyWatchResults struct {
 	Results []NotifyWatchResult
 }
 
+// NotifyWatcherIds holds the ids of NotifyWatchers whose changes
+// are waited for together by a NotifyWatcherMux.Next call.
+type NotifyWatcherIds struct {
+	NotifyWatcherIds []string
+}
+
+// NotifyWatcherChange reports that a NotifyWatcher has changed, or
+// an error (if it has stopped or failed).
+type NotifyWatcherChange struct {
+	Id    string
+	Error *Error
+}
+
+// NotifyWatcherChanges holds the results of a NotifyWatcherMux.Next
+// call: one entry for each watcher that changed or failed.
+type NotifyWatcherChanges struct {
+	Changes []NotifyWatcherChange
+}
+
 // StringsWatchResult holds a StringsWatcher id, changes and an error
 // (if any).
 type StringsWatchResult struct {
@@ -684,3 +836,26 @@ type MeterStatusResult struct {
 type MeterStatusResults struct {
 	Results []MeterStatusResult
 }
+
+// PortForward describes a port range opened by a unit in a container,
+// to be forwarded from the host machine to the container's address.
+type PortForward struct {
+	ContainerId string
+	Address     string
+	Protocol    string
+	FromPort    int
+	ToPort      int
+}
+
+// PortForwardsResult holds the port forwards required by a host
+// machine, or an error.
+type PortForwardsResult struct {
+	Forwards []PortForward
+	Error    *Error
+}
+
+// PortForwardsResults holds the port forwards required by multiple
+// host machines.
+type PortForwardsResults struct {
+	Results []PortForwardsResult
+}
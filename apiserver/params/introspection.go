@@ -0,0 +1,31 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// TxnLatencyBucket counts the state transactions that completed
+// within a range of latencies.
+type TxnLatencyBucket struct {
+	// UpperBoundMs holds the longest latency counted in the bucket,
+	// in milliseconds. It is zero for the final, unbounded bucket.
+	UpperBoundMs int64
+	Count        int64
+}
+
+// TxnMetrics holds counts of the transactions run by a state server.
+type TxnMetrics struct {
+	Transactions        int64
+	Attempts            int64
+	Retries             int64
+	Aborted             int64
+	ExcessiveContention int64
+	Failed              int64
+	Latency             []TxnLatencyBucket
+}
+
+// TxnMetricsResult holds the response to a request for a state
+// server's transaction metrics.
+type TxnMetricsResult struct {
+	Metrics *TxnMetrics `json:",omitempty"`
+	Error   *Error      `json:",omitempty"`
+}
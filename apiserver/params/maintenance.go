@@ -0,0 +1,44 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// CollectionStatus describes the size and index health of a collection
+// in the state database.
+type CollectionStatus struct {
+	Name           string
+	Count          int64
+	Size           int64
+	StorageSize    int64
+	IndexSize      int64
+	Indexes        []string
+	MissingIndexes []string
+	Capped         bool
+}
+
+// ReplicaSetMemberStatus describes a member of the state server replica
+// set.
+type ReplicaSetMemberStatus struct {
+	Address string
+	State   string
+	Healthy bool
+	Lag     time.Duration
+}
+
+// DatabaseStatusResult holds the health of the state database.
+type DatabaseStatusResult struct {
+	Collections []CollectionStatus
+	Members     []ReplicaSetMemberStatus
+}
+
+// CompactDatabase holds the arguments for compacting the state
+// database.
+type CompactDatabase struct {
+	// Repair requests a full repair of the database rather than a
+	// compaction of each collection.
+	Repair bool
+}
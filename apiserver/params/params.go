@@ -90,12 +90,24 @@ type ErrorResult struct {
 // The endpoints specified are unordered.
 type AddRelation struct {
 	Endpoints []string
+
+	// Network optionally names the network that the relation's
+	// traffic should use.
+	Network string
+
+	// ReportCandidates requests that, when the endpoints could refer
+	// to more than one relation, the candidates are returned in the
+	// results instead of an error, and no relation is added.
+	ReportCandidates bool
 }
 
 // AddRelationResults holds the results of a AddRelation call. The Endpoints
-// field maps service names to the involved endpoints.
+// field maps service names to the involved endpoints. Candidates holds the
+// fully qualified endpoints of each relation that ambiguous endpoints could
+// refer to, when requested.
 type AddRelationResults struct {
-	Endpoints map[string]charm.Relation
+	Endpoints  map[string]charm.Relation
+	Candidates [][]string
 }
 
 // DestroyRelation holds the parameters for making the DestroyRelation call.
@@ -165,6 +177,13 @@ type DestroyMachines struct {
 	Force        bool
 }
 
+// DestroyEnvironment holds parameters for the DestroyEnvironment call.
+type DestroyEnvironment struct {
+	// ConfirmationToken must hold the environment's UUID if the
+	// environment is destroy-protected.
+	ConfirmationToken string
+}
+
 // ServiceDeploy holds the parameters for making the ServiceDeploy call.
 type ServiceDeploy struct {
 	ServiceName   string
@@ -186,6 +205,60 @@ type ServiceUpdate struct {
 	SettingsStrings map[string]string
 	SettingsYAML    string // Takes precedence over SettingsStrings if both are present.
 	Constraints     *constraints.Value
+	HookPolicy      *ServiceHookPolicy
+}
+
+// ServiceHookPolicy holds a service's overrides of the environment's
+// hook-timeout, hook-retry-count and hook-retry-delay settings. Nil
+// fields are not overridden.
+type ServiceHookPolicy struct {
+	Timeout    *time.Duration
+	RetryCount *int
+	RetryDelay *time.Duration
+}
+
+// HookPolicyResult holds the hook execution timeout, retry policy and
+// resource limits that apply to a service's units, or an error. The
+// memory limit is in megabytes.
+type HookPolicyResult struct {
+	Error           *Error
+	Timeout         time.Duration
+	RetryCount      int
+	RetryDelay      time.Duration
+	MemoryLimit     uint64
+	CPULimit        time.Duration
+	StopGracePeriod time.Duration
+}
+
+// HookPolicyResults holds the results of a HookPolicy call.
+type HookPolicyResults struct {
+	Results []HookPolicyResult
+}
+
+// SetUnitChaos holds the parameters for a SetUnitChaos call: the
+// faults that a unit's agent should inject into its hook execution.
+// Hooks named in FailHooks, or every hook if it holds "*", fail
+// without being run; each hook is delayed by HookDelay; and the agent
+// restarts before its next hook if Restart is true.
+type SetUnitChaos struct {
+	UnitName  string
+	FailHooks []string
+	HookDelay time.Duration
+	Restart   bool
+}
+
+// UnitChaosResult holds the faults that a unit's agent should inject
+// into its hook execution, or an error.
+type UnitChaosResult struct {
+	Error     *Error
+	FailHooks []string
+	HookDelay time.Duration
+	Restart   bool
+}
+
+// UnitChaosResults holds the results of a UnitChaos call.
+type UnitChaosResults struct {
+	Results []UnitChaosResult
 }
 
 // ServiceSetCharm sets the charm for a given service.
@@ -235,6 +308,7 @@ type ServiceGetResults struct {
 	Charm       string
 	Config      map[string]interface{}
 	Constraints constraints.Value
+	HookPolicy  ServiceHookPolicy
 }
 
 // ServiceCharmRelations holds parameters for making the ServiceCharmRelations call.
@@ -252,6 +326,12 @@ type ServiceUnexpose struct {
 	ServiceName string
 }
 
+// ServiceUnexposePorts holds parameters for the ServiceUnexposePorts call.
+type ServiceUnexposePorts struct {
+	ServiceName string
+	Ports       []network.PortRange
+}
+
 // PublicAddress holds parameters for the PublicAddress call.
 type PublicAddress struct {
 	Target string
@@ -272,12 +352,62 @@ type PrivateAddressResults struct {
 	PrivateAddress string
 }
 
+// DebugHooksTranscript holds parameters for the DebugHooksTranscript call.
+type DebugHooksTranscript struct {
+	UnitName string
+}
+
+// DebugHooksTranscriptResults holds results of the DebugHooksTranscript call.
+type DebugHooksTranscriptResults struct {
+	Transcript string
+	Updated    time.Time
+}
+
+// StatusHistory holds the parameters for the UnitStatusHistory call.
+type StatusHistory struct {
+	UnitName string
+	Size     int
+}
+
+// StatusHistoryEntry holds a single status transition of a unit.
+type StatusHistoryEntry struct {
+	Status Status
+	Info   string
+	Since  time.Time
+}
+
+// StatusHistoryResults holds the result of the UnitStatusHistory call,
+// newest transition first.
+type StatusHistoryResults struct {
+	Statuses []StatusHistoryEntry
+}
+
 // Resolved holds parameters for the Resolved call.
 type Resolved struct {
 	UnitName string
 	Retry    bool
 }
 
+// ResolveUnits holds parameters for the ResolveUnits call. Either
+// Patterns, holding unit names or glob patterns, or All should be
+// given.
+type ResolveUnits struct {
+	Patterns []string
+	All      bool
+	Retry    bool
+}
+
+// ResolveUnitResult holds the outcome of resolving a single unit.
+type ResolveUnitResult struct {
+	UnitName string
+	Error    *Error
+}
+
+// ResolveUnitsResults holds results of the ResolveUnits call.
+type ResolveUnitsResults struct {
+	Results []ResolveUnitResult
+}
+
 // ResolvedResults holds results of the Resolved call.
 type ResolvedResults struct {
 	Service  string
@@ -303,6 +433,55 @@ type DestroyServiceUnits struct {
 	UnitNames []string
 }
 
+// DestroyUnits holds parameters for the DestroyUnits call. Either
+// Patterns, holding unit names or glob patterns, or ServiceName and
+// NumUnits should be given.
+type DestroyUnits struct {
+	Patterns    []string
+	ServiceName string
+	NumUnits    int
+}
+
+// DestroyUnitsResults holds results of the DestroyUnits call.
+type DestroyUnitsResults struct {
+	UnitNames []string
+}
+
+// ServiceSetUnitCount holds parameters for the ServiceSetUnitCount call.
+type ServiceSetUnitCount struct {
+	ServiceName string
+	Count       int
+}
+
+// ServiceSetUnitCountResults holds the names of the units added and
+// destroyed by the ServiceSetUnitCount call.
+type ServiceSetUnitCountResults struct {
+	Added     []string
+	Destroyed []string
+}
+
+// AddNetworks holds the parameters for making the AddNetworks call.
+type AddNetworks struct {
+	Networks []Network
+}
+
+// NetworksResult holds the result of the ListNetworks call.
+type NetworksResult struct {
+	Networks []Network
+}
+
+// MachineOpenedPorts holds the port ranges opened on a machine, as
+// returned by the MachinePorts call.
+type MachineOpenedPorts struct {
+	MachineTag string
+	Ports      []MachinePortRange
+}
+
+// MachineOpenedPortsResults holds the result of the MachinePorts call.
+type MachineOpenedPortsResults struct {
+	Machines []MachineOpenedPorts
+}
+
 // ServiceDestroy holds the parameters for making the ServiceDestroy call.
 type ServiceDestroy struct {
 	ServiceName string
@@ -362,6 +541,33 @@ type SetConstraints struct {
 	Constraints constraints.Value
 }
 
+// EntityConstraintsResult holds the constraints set on an environment,
+// service or machine, and those that take effect when the environment
+// constraints are merged with them, or an error.
+type EntityConstraintsResult struct {
+	Error       *Error
+	Constraints constraints.Value
+	Effective   constraints.Value
+}
+
+// EntityConstraintsResults holds the results of a GetConstraints call.
+type EntityConstraintsResults struct {
+	Results []EntityConstraintsResult
+}
+
+// EntityConstraints holds the constraints to set on the entity with
+// the given tag.
+type EntityConstraints struct {
+	Tag         string
+	Constraints constraints.Value
+}
+
+// SetEntityConstraints holds the parameters for making a SetConstraints
+// call.
+type SetEntityConstraints struct {
+	Entities []EntityConstraints
+}
+
 // CharmInfo stores parameters for a CharmInfo call.
 type CharmInfo struct {
 	CharmURL string
@@ -372,6 +578,14 @@ type ResolveCharms struct {
 	References []charm.Reference
 }
 
+// ResolveCharmsWithSeries holds the parameters for a Charms.Resolve
+// call. Series, if set, is tried first for any reference that does
+// not specify a series.
+type ResolveCharmsWithSeries struct {
+	References []charm.Reference
+	Series     string
+}
+
 // ResolveCharmResult holds the result of resolving a charm reference to a URL, or any error that occurred.
 type ResolveCharmResult struct {
 	URL   *charm.URL `json:",omitempty"`
@@ -388,6 +602,18 @@ type AllWatcherId struct {
 	AllWatcherId string
 }
 
+// AllWatcherFilter restricts the deltas returned by an AllWatcher.
+type AllWatcherFilter struct {
+	// Kinds holds the entity kinds to report, such as "service" or
+	// "unit". If empty, entities of all kinds are reported.
+	Kinds []string
+
+	// Patterns holds shell file name patterns, as accepted by
+	// path.Match, to match against entity ids. If empty, entities
+	// with any id are reported.
+	Patterns []string
+}
+
 // AllWatcherNextResults holds deltas returned from calling AllWatcher.Next().
 type AllWatcherNextResults struct {
 	Deltas []Delta
@@ -690,6 +916,33 @@ type EnvironmentGetResults struct {
 	Config map[string]interface{}
 }
 
+// EnvironConfigAttribute describes an environment configuration
+// attribute.
+type EnvironConfigAttribute struct {
+	// Type holds one of "string", "int" or "bool".
+	Type string
+
+	// Default holds the attribute's default value, if any.
+	Default interface{} `json:",omitempty"`
+
+	// Immutable reports whether the attribute cannot be changed
+	// once the environment is created.
+	Immutable bool
+
+	// Secret reports whether the attribute holds sensitive data.
+	Secret bool
+
+	// Provider reports whether the attribute is specific to the
+	// environment's provider.
+	Provider bool
+}
+
+// EnvironmentSchemaResults contains the result of the EnvironmentSchema
+// client API call.
+type EnvironmentSchemaResults struct {
+	Attributes map[string]EnvironConfigAttribute
+}
+
 // EnvironmentSet contains the arguments for EnvironmentSet client API
 // call.
 type EnvironmentSet struct {
@@ -714,12 +967,17 @@ type EnvironAction string
 const (
 	AddEnvUser    EnvironAction = "add"
 	RemoveEnvUser EnvironAction = "remove"
+	GrantEnvUser  EnvironAction = "grant"
 )
 
 // ModifyEnvironUser stores the parameters used for a Client.ShareEnvironment call.
 type ModifyEnvironUser struct {
 	UserTag string        `json:"user-tag"`
 	Action  EnvironAction `json:"action"`
+
+	// Access holds the access to grant the user to the
+	// environment, "read" or "write", when Action is GrantEnvUser.
+	Access string `json:"access,omitempty"`
 }
 
 // SetEnvironAgentVersion contains the arguments for
@@ -728,6 +986,20 @@ type SetEnvironAgentVersion struct {
 	Version version.Number
 }
 
+// SetAgentVersionPin contains the arguments for the
+// SetAgentVersionPin client API call. A zero Version clears the pin.
+type SetAgentVersionPin struct {
+	Tag     string
+	Version version.Number
+}
+
+// SetServiceCharmPin contains the arguments for the
+// SetServiceCharmPin client API call.
+type SetServiceCharmPin struct {
+	ServiceName string
+	Pinned      bool
+}
+
 // DeployerConnectionValues containers the result of deployer.ConnectionInfo
 // API call.
 type DeployerConnectionValues struct {
@@ -738,6 +1010,10 @@ type DeployerConnectionValues struct {
 // StatusParams holds parameters for the Status call.
 type StatusParams struct {
 	Patterns []string
+
+	// NoStore requests that status does not report newer charm
+	// revisions available from the charm store.
+	NoStore bool
 }
 
 // SetRsyslogCertParams holds parameters for the SetRsyslogCert call.
@@ -754,6 +1030,12 @@ type RsyslogConfigResult struct {
 	// logs to.
 	Port      int
 	HostPorts []network.HostPort
+	// ExternalHost, if set, holds the host:port of an external
+	// syslog collector to forward logs to instead of HostPorts,
+	// and ExternalCACert the certificate of the CA that signed
+	// its certificate.
+	ExternalHost   string
+	ExternalCACert string
 }
 
 // RsyslogConfigResults is the bulk form of RyslogConfigResult
@@ -0,0 +1,26 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// UnitGroup describes a named group of units, defined by unit name
+// patterns.
+type UnitGroup struct {
+	Name     string
+	Patterns []string
+}
+
+// UnitGroups holds a list of unit groups.
+type UnitGroups struct {
+	Groups []UnitGroup
+}
+
+// UnitGroupName holds the name of a unit group.
+type UnitGroupName struct {
+	Name string
+}
+
+// UnitGroupUnitsResult holds the names of the units in a unit group.
+type UnitGroupUnitsResult struct {
+	Units []string
+}
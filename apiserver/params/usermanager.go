@@ -57,3 +57,40 @@ type AddUserResult struct {
 	Tag   string `json:"tag,omitempty"`
 	Error *Error `json:"error,omitempty"`
 }
+
+// CreateAPIToken holds the parameters for making an API token for the
+// logged in user.
+type CreateAPIToken struct {
+	// Scope is "read" or "write".
+	Scope       string `json:"scope"`
+	Description string `json:"description,omitempty"`
+
+	// ValidFor holds how long the token may be used for.
+	ValidFor time.Duration `json:"valid-for"`
+}
+
+// CreateAPITokenResult holds a newly made API token. The token itself
+// is only ever returned here.
+type CreateAPITokenResult struct {
+	Id    string `json:"id"`
+	Token string `json:"token"`
+}
+
+// APITokenInfo describes an API token without revealing it.
+type APITokenInfo struct {
+	Id          string    `json:"id"`
+	Scope       string    `json:"scope"`
+	Description string    `json:"description,omitempty"`
+	DateCreated time.Time `json:"date-created"`
+	Expires     time.Time `json:"expires"`
+}
+
+// APITokensResult holds the API tokens of the logged in user.
+type APITokensResult struct {
+	Tokens []APITokenInfo `json:"tokens"`
+}
+
+// APITokenIds holds the ids of API tokens to operate on.
+type APITokenIds struct {
+	Ids []string `json:"ids"`
+}
@@ -0,0 +1,236 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package portforwarder
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.portforwarder")
+
+// sshPort is the port on which the host's ssh server listens.
+const sshPort = 22
+
+func init() {
+	common.RegisterStandardFacade("PortForwarder", 0, NewPortForwarderAPI)
+}
+
+// PortForwarderAPI provides access to the PortForwarder API facade,
+// used by machine agents to forward the ports opened by units in
+// their containers.
+type PortForwarderAPI struct {
+	st         *state.State
+	resources  *common.Resources
+	authorizer common.Authorizer
+}
+
+// NewPortForwarderAPI creates a new server-side PortForwarder API facade.
+func NewPortForwarderAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*PortForwarderAPI, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	return &PortForwarderAPI{
+		st:         st,
+		resources:  resources,
+		authorizer: authorizer,
+	}, nil
+}
+
+// WatchForwardedPorts starts a NotifyWatcher for each given host
+// machine, which notifies when the ports forwarded to the host's
+// containers may have changed.
+func (api *PortForwarderAPI) WatchForwardedPorts(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil || !api.authorizer.AuthOwner(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		host, err := api.st.Machine(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		watch := host.WatchForwardedPorts()
+		// Consume the initial event. Technically, API
+		// calls to Watch 'transmit' the initial event
+		// in the Watch response. But NotifyWatchers
+		// have no state to transmit.
+		if _, ok := <-watch.Changes(); ok {
+			result.Results[i].NotifyWatcherId = api.resources.Register(watch)
+		} else {
+			result.Results[i].Error = common.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return result, nil
+}
+
+// ForwardedPorts returns, for each given host machine, the port ranges
+// opened by units of exposed services on its containers, along with
+// the container addresses they should be forwarded to. As with the
+// firewaller, port ranges that conflict with a service's unexposed
+// ports are not forwarded. Neither are port ranges that include a port
+// the host itself listens on, or that a unit on the host has opened.
+func (api *PortForwarderAPI) ForwardedPorts(args params.Entities) (params.PortForwardsResults, error) {
+	result := params.PortForwardsResults{
+		Results: make([]params.PortForwardsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !api.authorizer.AuthOwner(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		forwards, err := api.forwardedPorts(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Forwards = forwards
+	}
+	return result, nil
+}
+
+func (api *PortForwarderAPI) forwardedPorts(hostId string) ([]params.PortForward, error) {
+	host, err := api.st.Machine(hostId)
+	if err != nil {
+		return nil, err
+	}
+	containerIds, err := host.Containers()
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := api.reservedPorts(host)
+	if err != nil {
+		return nil, err
+	}
+	services := make(map[string]*state.Service)
+	var forwards []params.PortForward
+	for _, containerId := range containerIds {
+		container, err := api.st.Machine(containerId)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		address := network.SelectInternalAddress(container.Addresses(), false)
+		if address == "" {
+			// The container's agent has not yet reported its addresses.
+			continue
+		}
+		units, err := container.Units()
+		if err != nil {
+			return nil, err
+		}
+		for _, unit := range units {
+			serviceName := unit.ServiceName()
+			service, ok := services[serviceName]
+			if !ok {
+				service, err = unit.Service()
+				if err != nil {
+					return nil, err
+				}
+				services[serviceName] = service
+			}
+			if !service.IsExposed() {
+				continue
+			}
+			ports, err := unit.OpenedPorts()
+			if err != nil {
+				return nil, err
+			}
+			for _, port := range ports {
+				if !isExposed(service, port) {
+					continue
+				}
+				if conflict, ok := findConflict(reserved, port); ok {
+					logger.Warningf(
+						"not forwarding %v to container %s: conflicts with %v on machine %s",
+						port, containerId, conflict, hostId,
+					)
+					continue
+				}
+				forwards = append(forwards, params.PortForward{
+					ContainerId: containerId,
+					Address:     address,
+					Protocol:    port.Protocol,
+					FromPort:    port.FromPort,
+					ToPort:      port.ToPort,
+				})
+			}
+		}
+	}
+	return forwards, nil
+}
+
+// isExposed reports whether the given port range of the service's
+// units should be reachable from outside, as the firewaller decides.
+func isExposed(service *state.Service, portRange network.PortRange) bool {
+	if !service.IsExposed() {
+		return false
+	}
+	for _, unexposed := range service.UnexposedPorts() {
+		if portRange.ConflictsWith(unexposed) {
+			return false
+		}
+	}
+	return true
+}
+
+// reservedPorts returns the port ranges that must not be forwarded
+// from the host machine: those of the host's ssh server, of the
+// state server, in case the host runs one, and those opened by units
+// on the host itself.
+func (api *PortForwarderAPI) reservedPorts(host *state.Machine) ([]network.PortRange, error) {
+	cfg, err := api.st.EnvironConfig()
+	if err != nil {
+		return nil, err
+	}
+	var reserved []network.PortRange
+	for _, port := range []int{sshPort, cfg.APIPort(), cfg.StatePort()} {
+		reserved = append(reserved, network.PortRange{
+			FromPort: port,
+			ToPort:   port,
+			Protocol: "tcp",
+		})
+	}
+	allPorts, err := host.AllPorts()
+	if err != nil {
+		return nil, err
+	}
+	for _, ports := range allPorts {
+		for portRange := range ports.AllPortRanges() {
+			reserved = append(reserved, portRange)
+		}
+	}
+	return reserved, nil
+}
+
+func findConflict(reserved []network.PortRange, port network.PortRange) (network.PortRange, bool) {
+	for _, portRange := range reserved {
+		if portRange.ConflictsWith(port) {
+			return portRange, true
+		}
+	}
+	return network.PortRange{}, false
+}
@@ -0,0 +1,216 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package portforwarder_test
+
+import (
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/portforwarder"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/instance"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type portForwarderSuite struct {
+	jujutesting.JujuConnSuite
+
+	host       *state.Machine
+	container  *state.Machine
+	service    *state.Service
+	api        *portforwarder.PortForwarderAPI
+	resources  *common.Resources
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&portForwarderSuite{})
+
+func (s *portForwarderSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	var err error
+	s.host, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	s.container, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.host.Id(), instance.LXC)
+	c.Assert(err, gc.IsNil)
+	err = s.container.SetMachineAddresses(network.NewAddress("10.0.3.5", network.ScopeUnknown))
+	c.Assert(err, gc.IsNil)
+
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(s.container)
+	c.Assert(err, gc.IsNil)
+	err = unit.OpenPorts("tcp", 80, 80)
+	c.Assert(err, gc.IsNil)
+
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	s.authorizer = apiservertesting.FakeAuthorizer{Tag: s.host.Tag()}
+	s.api, err = portforwarder.NewPortForwarderAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *portForwarderSuite) TestNewPortForwarderAPIRefusesNonMachineAgent(c *gc.C) {
+	authorizer := s.authorizer
+	authorizer.Tag = names.NewUnitTag("wordpress/0")
+	api, err := portforwarder.NewPortForwarderAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *portForwarderSuite) TestForwardedPorts(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.host.Tag().String()},
+		{Tag: s.container.Tag().String()},
+		{Tag: "unit-wordpress-0"},
+	}}
+	result, err := s.api.ForwardedPorts(args)
+	c.Assert(err, gc.IsNil)
+	// Ports of unexposed services are not forwarded.
+	c.Assert(result, gc.DeepEquals, params.PortForwardsResults{
+		Results: []params.PortForwardsResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.service.SetExposed()
+	c.Assert(err, gc.IsNil)
+	result, err = s.api.ForwardedPorts(params.Entities{Entities: args.Entities[:1]})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.PortForwardsResults{
+		Results: []params.PortForwardsResult{{
+			Forwards: []params.PortForward{{
+				ContainerId: s.container.Id(),
+				Address:     "10.0.3.5",
+				Protocol:    "tcp",
+				FromPort:    80,
+				ToPort:      80,
+			}},
+		}},
+	})
+}
+
+func (s *portForwarderSuite) TestForwardedPortsExcludesHostPorts(c *gc.C) {
+	err := s.service.SetExposed()
+	c.Assert(err, gc.IsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(s.container)
+	c.Assert(err, gc.IsNil)
+	// The container's ssh, API and state server ports are never
+	// forwarded, nor are ports opened by units on the host itself.
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, gc.IsNil)
+	for _, port := range []int{22, cfg.APIPort(), cfg.StatePort(), 8080} {
+		err = unit.OpenPorts("tcp", port, port)
+		c.Assert(err, gc.IsNil)
+	}
+	err = unit.OpenPorts("udp", 8080, 8080)
+	c.Assert(err, gc.IsNil)
+	hostService := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	hostUnit, err := hostService.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = hostUnit.AssignToMachine(s.host)
+	c.Assert(err, gc.IsNil)
+	err = hostUnit.OpenPorts("tcp", 8000, 8080)
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.api.ForwardedPorts(params.Entities{Entities: []params.Entity{
+		{Tag: s.host.Tag().String()},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.PortForwardsResults{
+		Results: []params.PortForwardsResult{{
+			Forwards: []params.PortForward{{
+				ContainerId: s.container.Id(),
+				Address:     "10.0.3.5",
+				Protocol:    "tcp",
+				FromPort:    80,
+				ToPort:      80,
+			}, {
+				ContainerId: s.container.Id(),
+				Address:     "10.0.3.5",
+				Protocol:    "udp",
+				FromPort:    8080,
+				ToPort:      8080,
+			}},
+		}},
+	})
+}
+
+func (s *portForwarderSuite) TestForwardedPortsPartiallyUnexposed(c *gc.C) {
+	err := s.service.SetExposed()
+	c.Assert(err, gc.IsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(s.container)
+	c.Assert(err, gc.IsNil)
+	err = unit.OpenPorts("tcp", 443, 443)
+	c.Assert(err, gc.IsNil)
+	err = s.service.UnexposePorts([]network.PortRange{{
+		FromPort: 8080,
+		ToPort:   8080,
+		Protocol: "tcp",
+	}})
+	c.Assert(err, gc.IsNil)
+	err = unit.OpenPorts("tcp", 8000, 8080)
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.host.Tag().String()}}}
+	result, err := s.api.ForwardedPorts(args)
+	c.Assert(err, gc.IsNil)
+	// Only the port ranges that do not overlap an unexposed port
+	// are forwarded.
+	c.Assert(result, gc.DeepEquals, params.PortForwardsResults{
+		Results: []params.PortForwardsResult{{
+			Forwards: []params.PortForward{{
+				ContainerId: s.container.Id(),
+				Address:     "10.0.3.5",
+				Protocol:    "tcp",
+				FromPort:    80,
+				ToPort:      80,
+			}, {
+				ContainerId: s.container.Id(),
+				Address:     "10.0.3.5",
+				Protocol:    "tcp",
+				FromPort:    443,
+				ToPort:      443,
+			}},
+		}},
+	})
+}
+
+func (s *portForwarderSuite) TestWatchForwardedPorts(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.host.Tag().String()},
+		{Tag: s.container.Tag().String()},
+	}}
+	result, err := s.api.WatchForwardedPorts(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	w := s.resources.Get("1").(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+	err = s.service.SetExposed()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
@@ -0,0 +1,83 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// readOnlyRoot restricts API calls to those that do not change the
+// environment, for users granted read access to it.
+type readOnlyRoot struct {
+	rpc.MethodFinder
+}
+
+// newReadOnlyRoot returns a new readOnlyRoot.
+func newReadOnlyRoot(finder rpc.MethodFinder) *readOnlyRoot {
+	return &readOnlyRoot{finder}
+}
+
+// readOnlyMethods holds the methods that may be called by users
+// with read access to an environment, keyed by facade. A nil set
+// allows every method of the facade.
+var readOnlyMethods = map[string]set.Strings{
+	"AllWatcher": nil,
+	"Pinger":     nil,
+	"Charms":     nil,
+	"Client": set.NewStrings(
+		"AgentVersion",
+		"APIHostPorts",
+		"CharmInfo",
+		"EnvironmentGet",
+		"EnvironmentInfo",
+		"FindTools",
+		"FullStatus",
+		"GetAnnotations",
+		"GetConstraints",
+		"GetEnvironmentConstraints",
+		"GetServiceConstraints",
+		"ListNetworks",
+		"PrivateAddress",
+		"PublicAddress",
+		"ServiceCharmRelations",
+		"ServiceGet",
+		"ServiceGetCharmURL",
+		"Status",
+		"UnitGroups",
+		"UnitGroupUnits",
+		"WatchAll",
+		"WatchAllFiltered",
+		"WatchDebugLog",
+	),
+	"KeyManager": set.NewStrings("ListKeys"),
+	// Users may still manage their own account.
+	"UserManager": set.NewStrings("APITokens", "SetPassword", "UserInfo"),
+}
+
+// IsMethodAllowedReadOnly reports whether the given method may be
+// called by a user with read access to the environment.
+func IsMethodAllowedReadOnly(rootName, methodName string) bool {
+	methods, ok := readOnlyMethods[rootName]
+	if !ok {
+		return false
+	}
+	return methods == nil || methods.Contains(methodName)
+}
+
+// FindMethod returns common.ErrPerm for API calls that could change
+// the environment.
+func (r *readOnlyRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !IsMethodAllowedReadOnly(rootName, methodName) {
+		return nil, common.ErrPerm
+	}
+	return caller, nil
+}
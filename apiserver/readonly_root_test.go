@@ -0,0 +1,53 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type readOnlyRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&readOnlyRootSuite{})
+
+func (r *readOnlyRootSuite) TestFindAllowedMethod(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot(nil)
+
+	caller, err := root.FindMethod("Client", 0, "FullStatus")
+
+	c.Assert(err, gc.IsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (r *readOnlyRootSuite) TestFindAllowedFacade(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot(nil)
+
+	caller, err := root.FindMethod("Pinger", 0, "Ping")
+
+	c.Assert(err, gc.IsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (r *readOnlyRootSuite) TestFindDisallowedMethod(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot(nil)
+
+	caller, err := root.FindMethod("Client", 0, "ServiceDeploy")
+
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *readOnlyRootSuite) TestFindNonExistentMethod(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot(nil)
+
+	caller, err := root.FindMethod("Foo", 0, "Bar")
+
+	c.Assert(err, gc.ErrorMatches, `unknown object type "Foo"`)
+	c.Assert(caller, gc.IsNil)
+}
@@ -51,9 +51,9 @@ type apiHandler struct {
 var _ = (*apiHandler)(nil)
 
 // newApiHandler returns a new apiHandler.
-func newApiHandler(srv *Server, rpcConn *rpc.Conn, reqNotifier *requestNotifier) (*apiHandler, error) {
+func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier *requestNotifier) (*apiHandler, error) {
 	r := &apiHandler{
-		state:     srv.state,
+		state:     st,
 		resources: common.NewResources(),
 		rpcConn:   rpcConn,
 	}
@@ -122,9 +122,9 @@ type apiRoot struct {
 }
 
 // newApiRoot returns a new apiRoot.
-func newApiRoot(srv *Server, resources *common.Resources, authorizer common.Authorizer) *apiRoot {
+func newApiRoot(st *state.State, resources *common.Resources, authorizer common.Authorizer) *apiRoot {
 	r := &apiRoot{
-		state:       srv.state,
+		state:       st,
 		resources:   resources,
 		authorizer:  authorizer,
 		objectCache: make(map[objectKey]reflect.Value),
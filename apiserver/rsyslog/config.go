@@ -27,8 +27,10 @@ func newRsyslogConfig(envCfg *config.Config, api *RsyslogAPI) (*apirsyslog.Rsysl
 	apiAddresses := network.NewAddresses(bareAddrs...)
 
 	return &apirsyslog.RsyslogConfig{
-		CACert:    envCfg.RsyslogCACert(),
-		Port:      port,
-		HostPorts: network.AddressesWithPort(apiAddresses, port),
+		CACert:         envCfg.RsyslogCACert(),
+		Port:           port,
+		HostPorts:      network.AddressesWithPort(apiAddresses, port),
+		ExternalHost:   envCfg.SyslogHost(),
+		ExternalCACert: envCfg.SyslogCACert(),
 	}, nil
 }
@@ -72,9 +72,11 @@ func (api *RsyslogAPI) GetRsyslogConfig(args params.Entities) (params.RsyslogCon
 		rsyslogCfg, err := newRsyslogConfig(cfg, api)
 		if err == nil {
 			result.Results[i] = params.RsyslogConfigResult{
-				CACert:    rsyslogCfg.CACert,
-				Port:      rsyslogCfg.Port,
-				HostPorts: rsyslogCfg.HostPorts,
+				CACert:         rsyslogCfg.CACert,
+				Port:           rsyslogCfg.Port,
+				HostPorts:      rsyslogCfg.HostPorts,
+				ExternalHost:   rsyslogCfg.ExternalHost,
+				ExternalCACert: rsyslogCfg.ExternalCACert,
 			}
 		} else {
 			result.Results[i].Error = common.ServerError(err)
@@ -85,6 +87,9 @@ func (api *RsyslogAPI) GetRsyslogConfig(args params.Entities) (params.RsyslogCon
 
 // WatchForRsyslogChanges starts a watcher to track if there are changes
 // that require we update the rsyslog.d configurations for a machine and/or unit.
+// Changes to the state server addresses and to the environment
+// configuration, which may point at an external syslog collector,
+// are both reported.
 func (api *RsyslogAPI) WatchForRsyslogChanges(args params.Entities) (params.NotifyWatchResults, error) {
 	result := params.NotifyWatchResults{
 		Results: make([]params.NotifyWatchResult, len(args.Entities)),
@@ -92,7 +97,10 @@ func (api *RsyslogAPI) WatchForRsyslogChanges(args params.Entities) (params.Noti
 	for i := range args.Entities {
 		err := common.ErrPerm
 		if api.authorizer.AuthMachineAgent() || api.authorizer.AuthUnitAgent() {
-			watch := api.st.WatchAPIHostPorts()
+			watch := newMultiNotifyWatcher(
+				api.st.WatchAPIHostPorts(),
+				api.st.WatchForEnvironConfigChanges(),
+			)
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -0,0 +1,118 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rsyslog
+
+import (
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// multiNotifyWatcher combines several NotifyWatchers, sending a
+// single event whenever any of them reports a change.
+type multiNotifyWatcher struct {
+	tomb     tomb.Tomb
+	watchers []state.NotifyWatcher
+	changes  chan struct{}
+}
+
+var _ state.NotifyWatcher = (*multiNotifyWatcher)(nil)
+
+func newMultiNotifyWatcher(watchers ...state.NotifyWatcher) *multiNotifyWatcher {
+	w := &multiNotifyWatcher{
+		watchers: watchers,
+		changes:  make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.changes)
+		w.tomb.Kill(w.loop())
+		for _, sub := range w.watchers {
+			sub.Stop()
+		}
+	}()
+	return w
+}
+
+func (w *multiNotifyWatcher) loop() error {
+	// Consume the initial event of each watcher; they are
+	// reported together as this watcher's initial event.
+	for _, sub := range w.watchers {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-sub.Changes():
+			if !ok {
+				return watcher.EnsureErr(sub)
+			}
+		}
+	}
+	changed := make(chan struct{}, 1)
+	for _, sub := range w.watchers {
+		go w.forward(sub, changed)
+	}
+	out := w.changes
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-changed:
+			out = w.changes
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// forward notes each change reported by sub on changed, until
+// sub or the multiNotifyWatcher stops.
+func (w *multiNotifyWatcher) forward(sub state.NotifyWatcher, changed chan<- struct{}) {
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return
+		case _, ok := <-sub.Changes():
+			if !ok {
+				select {
+				case <-w.tomb.Dying():
+					// sub was stopped by us.
+				default:
+					w.tomb.Kill(watcher.EnsureErr(sub))
+				}
+				return
+			}
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Changes implements state.NotifyWatcher.
+func (w *multiNotifyWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Kill implements state.NotifyWatcher.
+func (w *multiNotifyWatcher) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait implements state.NotifyWatcher.
+func (w *multiNotifyWatcher) Wait() error {
+	return w.tomb.Wait()
+}
+
+// Stop implements state.NotifyWatcher.
+func (w *multiNotifyWatcher) Stop() error {
+	w.Kill()
+	return w.Wait()
+}
+
+// Err implements state.NotifyWatcher.
+func (w *multiNotifyWatcher) Err() error {
+	return w.tomb.Err()
+}
@@ -0,0 +1,56 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// tokenRoot restricts the API calls of users that logged in with an
+// API token, so that a leaked token cannot be used to take over the
+// user's account, and so that the connection becomes useless once the
+// token is revoked or expires.
+type tokenRoot struct {
+	rpc.MethodFinder
+	checkToken func() error
+}
+
+// newTokenRoot returns a new tokenRoot. The checkToken function is
+// called before every API call, and must return an error if the token
+// is no longer valid.
+func newTokenRoot(finder rpc.MethodFinder, checkToken func() error) *tokenRoot {
+	return &tokenRoot{finder, checkToken}
+}
+
+// tokenDeniedMethods holds the methods that may not be called by users
+// that logged in with an API token, keyed by facade.
+var tokenDeniedMethods = map[string]set.Strings{
+	"UserManager": set.NewStrings(
+		"CreateAPIToken",
+		"RemoveAPIToken",
+		"SetPassword",
+	),
+}
+
+// FindMethod returns an error for all API calls once the token is no
+// longer valid, and common.ErrPerm for API calls that manage the
+// user's credentials.
+func (r *tokenRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	if err := r.checkToken(); err != nil {
+		logger.Debugf("refusing %s.%s: %v", rootName, methodName, err)
+		return nil, err
+	}
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if tokenDeniedMethods[rootName].Contains(methodName) {
+		return nil, common.ErrPerm
+	}
+	return caller, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/testing"
+)
+
+func validToken() error {
+	return nil
+}
+
+type tokenRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&tokenRootSuite{})
+
+func (r *tokenRootSuite) TestFindAllowedMethod(c *gc.C) {
+	root := apiserver.TestingTokenRoot(nil, validToken)
+
+	caller, err := root.FindMethod("UserManager", 0, "UserInfo")
+
+	c.Assert(err, gc.IsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (r *tokenRootSuite) TestFindDisallowedMethod(c *gc.C) {
+	root := apiserver.TestingTokenRoot(nil, validToken)
+
+	for _, method := range []string{"CreateAPIToken", "RemoveAPIToken", "SetPassword"} {
+		caller, err := root.FindMethod("UserManager", 0, method)
+
+		c.Check(err, gc.ErrorMatches, "permission denied")
+		c.Check(caller, gc.IsNil)
+	}
+}
+
+func (r *tokenRootSuite) TestFindMethodWithInvalidToken(c *gc.C) {
+	root := apiserver.TestingTokenRoot(nil, func() error {
+		return common.ErrBadCreds
+	})
+
+	caller, err := root.FindMethod("UserManager", 0, "UserInfo")
+
+	c.Assert(err, gc.Equals, common.ErrBadCreds)
+	c.Assert(caller, gc.IsNil)
+}
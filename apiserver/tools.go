@@ -42,12 +42,9 @@ type toolsDownloadHandler struct {
 	toolsHandler
 }
 
+// ServeHTTP implements http.Handler. Environment validation is left
+// to the httpEndpoint wrapping the handler.
 func (h *toolsDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if err := h.validateEnvironUUID(r); err != nil {
-		h.sendError(w, http.StatusNotFound, err.Error())
-		return
-	}
-
 	switch r.Method {
 	case "GET":
 		tarball, err := h.processGet(r)
@@ -62,17 +59,9 @@ func (h *toolsDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// ServeHTTP implements http.Handler. Environment validation and
+// authentication are left to the httpEndpoint wrapping the handler.
 func (h *toolsUploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if err := h.authenticate(r); err != nil {
-		h.authError(w, h)
-		return
-	}
-
-	if err := h.validateEnvironUUID(r); err != nil {
-		h.sendError(w, http.StatusNotFound, err.Error())
-		return
-	}
-
 	switch r.Method {
 	case "POST":
 		// Add tools to storage.
@@ -7,6 +7,7 @@ package uniter
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -1009,6 +1010,11 @@ func (u *uniterBaseAPI) UpdateSettings(args params.RelationUnitsSettings) (param
 	if err != nil {
 		return params.ErrorResults{}, err
 	}
+	cfg, err := u.st.EnvironConfig()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	maxSize := cfg.MaxRelationSettingsSize()
 	for i, arg := range args.RelationUnits {
 		unit, err := names.ParseUnitTag(arg.Unit)
 		if err != nil {
@@ -1016,6 +1022,9 @@ func (u *uniterBaseAPI) UpdateSettings(args params.RelationUnitsSettings) (param
 			continue
 		}
 		relUnit, err := u.getRelationUnit(canAccess, arg.Relation, unit)
+		if err == nil {
+			err = checkReservedSettings(arg.Settings)
+		}
 		if err == nil {
 			var settings *state.Settings
 			settings, err = relUnit.Settings()
@@ -1027,6 +1036,9 @@ func (u *uniterBaseAPI) UpdateSettings(args params.RelationUnitsSettings) (param
 						settings.Set(k, v)
 					}
 				}
+				err = checkSettingsSize(settings.Map(), maxSize)
+			}
+			if err == nil {
 				_, err = settings.Write()
 			}
 		}
@@ -1035,6 +1047,31 @@ func (u *uniterBaseAPI) UpdateSettings(args params.RelationUnitsSettings) (param
 	return result, nil
 }
 
+// checkReservedSettings returns an error if any of the given relation
+// settings is reserved for use by Juju.
+func checkReservedSettings(settings params.RelationSettings) error {
+	for k := range settings {
+		if strings.HasPrefix(k, params.ReservedRelationSettingsPrefix) {
+			return errors.Errorf("cannot set %q: keys starting with %q are reserved",
+				k, params.ReservedRelationSettingsPrefix)
+		}
+	}
+	return nil
+}
+
+// checkSettingsSize returns an error if the given relation settings
+// are larger than maxSize bytes.
+func checkSettingsSize(settings map[string]interface{}, maxSize int) error {
+	converted := make(params.RelationSettings)
+	for k, v := range settings {
+		converted[k] = fmt.Sprint(v)
+	}
+	if size := converted.Size(); size > maxSize {
+		return errors.Errorf("relation settings too large: %d bytes exceeds the limit of %d bytes", size, maxSize)
+	}
+	return nil
+}
+
 // WatchRelationUnits returns a RelationUnitsWatcher for observing
 // changes to every unit in the supplied relation that is visible to
 // the supplied unit. See also state/watcher.go:RelationUnit.Watch().
@@ -1238,6 +1275,7 @@ func (u *uniterBaseAPI) prepareRelationResult(rel *state.Relation, unit *state.U
 			ServiceName: ep.ServiceName,
 			Relation:    ep.Relation,
 		},
+		Network: rel.Network(),
 	}, nil
 }
 
@@ -1811,6 +1811,48 @@ func (s *uniterBaseSuite) testUpdateSettings(
 	})
 }
 
+func (s *uniterBaseSuite) testUpdateSettingsLimits(
+	c *gc.C,
+	facade interface {
+		UpdateSettings(args params.RelationUnitsSettings) (params.ErrorResults, error)
+	},
+) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"max-relation-settings-size": 32,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.wordpressUnit)
+	c.Assert(err, gc.IsNil)
+	err = relUnit.EnterScope(map[string]interface{}{"some": "settings"})
+	c.Assert(err, gc.IsNil)
+
+	args := params.RelationUnitsSettings{RelationUnits: []params.RelationUnitSettings{{
+		Relation: rel.Tag().String(),
+		Unit:     "unit-wordpress-0",
+		Settings: params.RelationSettings{"juju-private": "value"},
+	}, {
+		Relation: rel.Tag().String(),
+		Unit:     "unit-wordpress-0",
+		Settings: params.RelationSettings{"other": "a value that is much too long"},
+	}}}
+	result, err := facade.UpdateSettings(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{&params.Error{Message: `cannot set "juju-private": keys starting with "juju-" are reserved`}},
+			{&params.Error{Message: "relation settings too large: 46 bytes exceeds the limit of 32 bytes"}},
+		},
+	})
+
+	// The settings were not changed.
+	readSettings, err := relUnit.ReadSettings(s.wordpressUnit.Name())
+	c.Assert(err, gc.IsNil)
+	c.Assert(readSettings, gc.DeepEquals, map[string]interface{}{
+		"some": "settings",
+	})
+}
+
 func (s *uniterBaseSuite) testWatchRelationUnits(
 	c *gc.C,
 	facade interface {
@@ -257,6 +257,10 @@ func (s *uniterV0Suite) TestUpdateSettings(c *gc.C) {
 	s.testUpdateSettings(c, s.uniter)
 }
 
+func (s *uniterV0Suite) TestUpdateSettingsLimits(c *gc.C) {
+	s.testUpdateSettingsLimits(c, s.uniter)
+}
+
 func (s *uniterV0Suite) TestWatchRelationUnits(c *gc.C) {
 	s.testWatchRelationUnits(c, s.uniter)
 }
@@ -321,6 +325,10 @@ func (s *uniterV0Suite) TestAllMachinePortsV0NotImplemented(c *gc.C) {
 	apiservertesting.AssertNotImplemented(c, s.uniter, "AllMachinePorts")
 }
 
+func (s *uniterV0Suite) TestSetDebugHooksTranscriptV0NotImplemented(c *gc.C) {
+	apiservertesting.AssertNotImplemented(c, s.uniter, "SetDebugHooksTranscript")
+}
+
 func (s *uniterV0Suite) TestRequestReboot(c *gc.C) {
 	args := params.Entities{Entities: []params.Entity{
 		{Tag: s.machine0.Tag().String()},
@@ -11,8 +11,8 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
-	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
 )
 
 func init() {
@@ -74,6 +74,35 @@ func (u *UniterAPIV1) AllMachinePorts(args params.Entities) (params.MachinePorts
 	return result, nil
 }
 
+// MachineAddresses returns all the addresses of each given machine.
+func (u *UniterAPIV1) MachineAddresses(args params.Entities) (params.MachineAddressesResults, error) {
+	result := params.MachineAddressesResults{
+		Results: make([]params.MachineAddressesResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessMachine()
+	if err != nil {
+		return params.MachineAddressesResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := u.getMachine(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Addresses = machine.Addresses()
+	}
+	return result, nil
+}
+
 // ServiceOwner returns the owner user for each given service tag.
 func (u *UniterAPIV1) ServiceOwner(args params.Entities) (params.StringResults, error) {
 	result := params.StringResults{
@@ -103,6 +132,53 @@ func (u *UniterAPIV1) ServiceOwner(args params.Entities) (params.StringResults,
 	return result, nil
 }
 
+// HookPolicy returns, for each given service tag, the hook execution
+// timeout and retry policy that applies to the service's units: the
+// service's overrides of the environment's settings, merged with them.
+// The environment's hook resource limits and stop hook grace period
+// are returned with it.
+func (u *UniterAPIV1) HookPolicy(args params.Entities) (params.HookPolicyResults, error) {
+	result := params.HookPolicyResults{
+		Results: make([]params.HookPolicyResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessService()
+	if err != nil {
+		return params.HookPolicyResults{}, err
+	}
+	cfg, err := u.st.EnvironConfig()
+	if err != nil {
+		return params.HookPolicyResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := u.getService(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		timeout, retryCount, retryDelay, err := service.EffectiveHookPolicy()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Timeout = timeout
+		result.Results[i].RetryCount = retryCount
+		result.Results[i].RetryDelay = retryDelay
+		result.Results[i].MemoryLimit = cfg.HookMemoryLimit()
+		result.Results[i].CPULimit = cfg.HookCPULimit()
+		result.Results[i].StopGracePeriod = cfg.StopHookGracePeriod()
+	}
+	return result, nil
+}
+
 // AssignedMachine returns the machine tag for each given unit tag, or
 // an error satisfying params.IsCodeNotAssigned when a unit has no
 // assigned machine.
@@ -139,6 +215,418 @@ func (u *UniterAPIV1) AssignedMachine(args params.Entities) (params.StringResult
 	return result, nil
 }
 
+// SetDebugHooksTranscript records the transcript of the debug-hooks
+// session for each given unit.
+func (u *UniterAPIV1) SetDebugHooksTranscript(args params.EntitiesDebugHooksTranscript) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.SetDebugHooksTranscript(entity.Transcript)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// UnitChaos returns, for each given unit tag, the faults that the
+// unit's agent should inject into its hook execution. No faults are
+// returned unless the environment's enable-chaos-testing setting is
+// true.
+func (u *UniterAPIV1) UnitChaos(args params.Entities) (params.UnitChaosResults, error) {
+	result := params.UnitChaosResults{
+		Results: make([]params.UnitChaosResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.UnitChaosResults{}, err
+	}
+	cfg, err := u.st.EnvironConfig()
+	if err != nil {
+		return params.UnitChaosResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !cfg.EnableChaosTesting() {
+			continue
+		}
+		chaos, err := unit.Chaos()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].FailHooks = chaos.FailHooks
+		result.Results[i].HookDelay = chaos.HookDelay
+		result.Results[i].Restart = chaos.Restart
+	}
+	return result, nil
+}
+
+// ClearChaosRestart records, for each given unit, that the unit's
+// agent has restarted as instructed by UnitChaos.
+func (u *UniterAPIV1) ClearChaosRestart(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.ClearChaosRestart()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ClaimLeadership claims, or renews, leadership of its service for
+// each given unit. The result is true if the unit is now the leader,
+// and false if another unit holds leadership.
+func (u *UniterAPIV1) ClaimLeadership(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.BoolResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := u.getUnitService(tag)
+		if err == nil {
+			err = service.ClaimLeadership(tag.Id())
+		}
+		switch errors.Cause(err) {
+		case nil:
+			result.Results[i].Result = true
+		case state.ErrLeadershipClaimDenied:
+			result.Results[i].Result = false
+		default:
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// Leader returns whether each given unit holds the leadership lease
+// of its service. Unlike ClaimLeadership, it does not claim or renew
+// the lease.
+func (u *UniterAPIV1) Leader(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.BoolResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		var leader string
+		service, err := u.getUnitService(tag)
+		if err == nil {
+			leader, err = service.Leader()
+		}
+		switch {
+		case err == nil:
+			result.Results[i].Result = leader == tag.Id()
+		case errors.IsNotFound(err) && service != nil:
+			// No unit holds the lease.
+			result.Results[i].Result = false
+		default:
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// LeaderSettings returns the leader settings of the service of each
+// given unit.
+func (u *UniterAPIV1) LeaderSettings(args params.Entities) (params.LeaderSettingsResults, error) {
+	result := params.LeaderSettingsResults{
+		Results: make([]params.LeaderSettingsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.LeaderSettingsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := u.getUnitService(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		settings, err := service.LeaderSettings()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Settings = params.LeaderSettings(settings)
+	}
+	return result, nil
+}
+
+// MergeLeaderSettings applies the given changes to the leader
+// settings of each unit's service. Only the service's current
+// leader may change its leader settings.
+func (u *UniterAPIV1) MergeLeaderSettings(args params.EntitiesLeaderSettings) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var service *state.Service
+			service, err = u.getUnitService(tag)
+			if err == nil {
+				err = service.UpdateLeaderSettings(tag.Id(), entity.Settings)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WatchLeaderSettings returns a NotifyWatcher for observing changes
+// to the leader settings of each given unit's service.
+func (u *UniterAPIV1) WatchLeaderSettings(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		watcherId := ""
+		if canAccess(tag) {
+			watcherId, err = u.watchOneLeaderSettings(tag)
+		}
+		result.Results[i].NotifyWatcherId = watcherId
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// StorageInstances returns the storage instances owned by each given
+// unit.
+func (u *UniterAPIV1) StorageInstances(args params.Entities) (params.StorageInstancesResults, error) {
+	result := params.StorageInstancesResults{
+		Results: make([]params.StorageInstancesResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StorageInstancesResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		instances, err := unit.StorageInstances()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Instances = make([]params.StorageInstance, len(instances))
+		for j, instance := range instances {
+			location, _ := instance.Location()
+			result.Results[i].Instances[j] = params.StorageInstance{
+				Id:         instance.Id(),
+				Kind:       string(instance.Kind()),
+				Life:       params.Life(instance.Life().String()),
+				Location:   location,
+				Attributes: instance.Attributes(),
+			}
+		}
+	}
+	return result, nil
+}
+
+// WatchStorageInstances returns a NotifyWatcher for observing changes
+// to the storage instances of each given unit.
+func (u *UniterAPIV1) WatchStorageInstances(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		watcherId := ""
+		if canAccess(tag) {
+			watcherId, err = u.watchOneStorageInstances(tag)
+		}
+		result.Results[i].NotifyWatcherId = watcherId
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// StorageDetached records, for each given unit and storage instance,
+// that the unit has finished detaching the instance, which is then
+// removed. Only instances that are no longer alive can be detached.
+func (u *UniterAPIV1) StorageDetached(args params.EntitiesStorageInstances) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				var instance *state.StorageInstance
+				instance, err = unit.StorageInstance(entity.StorageId)
+				if err == nil {
+					err = instance.Remove()
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV1) getUnitService(tag names.UnitTag) (*state.Service, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return nil, err
+	}
+	return unit.Service()
+}
+
+func (u *UniterAPIV1) watchOneLeaderSettings(tag names.UnitTag) (string, error) {
+	service, err := u.getUnitService(tag)
+	if err != nil {
+		return "", err
+	}
+	watch := service.WatchLeaderSettings()
+	// Consume the initial event; see watchOneUnitConfigSettings.
+	if _, ok := <-watch.Changes(); ok {
+		return u.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
+
+func (u *UniterAPIV1) watchOneStorageInstances(tag names.UnitTag) (string, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return "", err
+	}
+	watch := unit.WatchStorageInstances()
+	// Consume the initial event; see watchOneUnitConfigSettings.
+	if _, ok := <-watch.Changes(); ok {
+		return u.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
+
 func (u *UniterAPIV1) getMachine(tag names.MachineTag) (*state.Machine, error) {
 	return u.st.Machine(tag.Id())
 }
@@ -155,28 +643,10 @@ func (u *UniterAPIV1) getOneMachinePorts(canAccess common.AuthFunc, machineTag s
 	if err != nil {
 		return params.MachinePortsResult{Error: common.ServerError(err)}
 	}
-	allPorts, err := machine.AllPorts()
+	resultPorts, err := common.MachinePortRanges(machine)
 	if err != nil {
 		return params.MachinePortsResult{Error: common.ServerError(err)}
 	}
-	var resultPorts []params.MachinePortRange
-	for _, ports := range allPorts {
-		// AllPortRanges gives a map, but apis require a stable order
-		// for results, so sort the port ranges.
-		portRangesToUnits := ports.AllPortRanges()
-		portRanges := make([]network.PortRange, 0, len(portRangesToUnits))
-		for portRange := range portRangesToUnits {
-			portRanges = append(portRanges, portRange)
-		}
-		network.SortPortRanges(portRanges)
-		for _, portRange := range portRanges {
-			unitName := portRangesToUnits[portRange]
-			resultPorts = append(resultPorts, params.MachinePortRange{
-				UnitTag:   names.NewUnitTag(unitName).String(),
-				PortRange: portRange,
-			})
-		}
-	}
 	return params.MachinePortsResult{
 		Ports: resultPorts,
 	}
@@ -4,6 +4,9 @@
 package uniter_test
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -14,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/uniter"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 )
 
 type uniterV1Suite struct {
@@ -258,6 +262,10 @@ func (s *uniterV1Suite) TestUpdateSettings(c *gc.C) {
 	s.testUpdateSettings(c, s.uniter)
 }
 
+func (s *uniterV1Suite) TestUpdateSettingsLimits(c *gc.C) {
+	s.testUpdateSettingsLimits(c, s.uniter)
+}
+
 func (s *uniterV1Suite) TestWatchRelationUnits(c *gc.C) {
 	s.testWatchRelationUnits(c, s.uniter)
 }
@@ -325,6 +333,42 @@ func (s *uniterV1Suite) TestServiceOwner(c *gc.C) {
 	})
 }
 
+func (s *uniterV1Suite) TestHookPolicy(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"hook-timeout":           600,
+		"hook-retry-count":       2,
+		"hook-memory-limit":      256,
+		"stop-hook-grace-period": 120,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	retryDelay := time.Minute
+	err = s.wordpress.SetHookPolicy(state.HookPolicy{RetryDelay: &retryDelay})
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+		{Tag: "service-wordpress"},
+		{Tag: "service-mysql"},
+		{Tag: "service-foo"},
+	}}
+	result, err := s.uniter.HookPolicy(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.HookPolicyResults{
+		Results: []params.HookPolicyResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{
+				Timeout:         10 * time.Minute,
+				RetryCount:      2,
+				RetryDelay:      time.Minute,
+				MemoryLimit:     256,
+				StopGracePeriod: 2 * time.Minute,
+			},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterV1Suite) TestAssignedMachine(c *gc.C) {
 	args := params.Entities{Entities: []params.Entity{
 		{Tag: "unit-mysql-0"},
@@ -356,6 +400,329 @@ func (s *uniterV1Suite) TestAssignedMachine(c *gc.C) {
 	})
 }
 
+func (s *uniterV1Suite) TestSetDebugHooksTranscript(c *gc.C) {
+	args := params.EntitiesDebugHooksTranscript{Entities: []params.EntityDebugHooksTranscript{
+		{Tag: "unit-mysql-0", Transcript: "foo"},
+		{Tag: "unit-wordpress-0", Transcript: "=== install ===\n"},
+		{Tag: "unit-foo-42", Transcript: "bar"},
+		{Tag: "service-wordpress", Transcript: "baz"},
+	}}
+	result, err := s.uniter.SetDebugHooksTranscript(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	transcript, _, err := s.wordpressUnit.DebugHooksTranscript()
+	c.Assert(err, gc.IsNil)
+	c.Assert(transcript, gc.Equals, "=== install ===\n")
+}
+
+func (s *uniterV1Suite) TestUnitChaos(c *gc.C) {
+	err := s.wordpressUnit.SetChaos(state.UnitChaos{
+		FailHooks: []string{"install"},
+		HookDelay: time.Minute,
+		Restart:   true,
+	})
+	c.Assert(err, gc.IsNil)
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+		{Tag: "service-wordpress"},
+	}}
+
+	// No faults are injected while chaos testing is disabled.
+	result, err := s.uniter.UnitChaos(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.UnitChaosResults{
+		Results: []params.UnitChaosResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"enable-chaos-testing": true}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	result, err = s.uniter.UnitChaos(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results[1], jc.DeepEquals, params.UnitChaosResult{
+		FailHooks: []string{"install"},
+		HookDelay: time.Minute,
+		Restart:   true,
+	})
+}
+
+func (s *uniterV1Suite) TestClearChaosRestart(c *gc.C) {
+	err := s.wordpressUnit.SetChaos(state.UnitChaos{Restart: true})
+	c.Assert(err, gc.IsNil)
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.ClearChaosRestart(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	chaos, err := s.wordpressUnit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos.Restart, gc.Equals, false)
+}
+
+func (s *uniterV1Suite) TestClaimLeadership(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+		{Tag: "service-wordpress"},
+	}}
+	result, err := s.uniter.ClaimLeadership(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	leader, err := s.wordpress.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "wordpress/0")
+}
+
+func (s *uniterV1Suite) TestLeader(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	expectResult := func(isLeader bool) params.BoolResults {
+		return params.BoolResults{
+			Results: []params.BoolResult{
+				{Error: apiservertesting.ErrUnauthorized},
+				{Result: isLeader},
+				{Error: apiservertesting.ErrUnauthorized},
+			},
+		}
+	}
+	result, err := s.uniter.Leader(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, expectResult(false))
+
+	// Reading the lease does not claim it.
+	_, err = s.wordpress.Leader()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.wordpress.ClaimLeadership(s.wordpressUnit.Name())
+	c.Assert(err, gc.IsNil)
+	result, err = s.uniter.Leader(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, expectResult(true))
+}
+
+func (s *uniterV1Suite) TestClaimLeadershipDenied(c *gc.C) {
+	otherUnit, err := s.wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = s.wordpress.ClaimLeadership(otherUnit.Name())
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: "unit-wordpress-0"}}}
+	result, err := s.uniter.ClaimLeadership(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{{Result: false}},
+	})
+
+	mergeArgs := params.EntitiesLeaderSettings{Entities: []params.EntityLeaderSettings{
+		{Tag: "unit-wordpress-0", Settings: params.LeaderSettings{"foo": "bar"}},
+	}}
+	mergeResult, err := s.uniter.MergeLeaderSettings(mergeArgs)
+	c.Assert(err, gc.IsNil)
+	c.Assert(mergeResult.Results, gc.HasLen, 1)
+	c.Assert(mergeResult.Results[0].Error, gc.ErrorMatches, `cannot update leader settings for service "wordpress": unit is not the service leader`)
+}
+
+func (s *uniterV1Suite) TestLeaderSettings(c *gc.C) {
+	err := s.wordpress.ClaimLeadership(s.wordpressUnit.Name())
+	c.Assert(err, gc.IsNil)
+
+	mergeArgs := params.EntitiesLeaderSettings{Entities: []params.EntityLeaderSettings{
+		{Tag: "unit-mysql-0", Settings: params.LeaderSettings{"foo": "baz"}},
+		{Tag: "unit-wordpress-0", Settings: params.LeaderSettings{"foo": "bar", "gone": ""}},
+		{Tag: "unit-foo-42", Settings: params.LeaderSettings{"foo": "baz"}},
+	}}
+	mergeResult, err := s.uniter.MergeLeaderSettings(mergeArgs)
+	c.Assert(err, gc.IsNil)
+	c.Assert(mergeResult, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "service-wordpress"},
+	}}
+	result, err := s.uniter.LeaderSettings(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.LeaderSettingsResults{
+		Results: []params.LeaderSettingsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Settings: params.LeaderSettings{"foo": "bar"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterV1Suite) TestWatchLeaderSettings(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WatchLeaderSettings(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event, and
+	// that a change to the leader settings is reported.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+	err = s.wordpress.ClaimLeadership(s.wordpressUnit.Name())
+	c.Assert(err, gc.IsNil)
+	err = s.wordpress.UpdateLeaderSettings(s.wordpressUnit.Name(), map[string]string{"foo": "bar"})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+}
+
+func (s *uniterV1Suite) TestStorageInstances(c *gc.C) {
+	data, err := s.wordpressUnit.AddStorageInstance("data", state.StorageKindFilesystem)
+	c.Assert(err, gc.IsNil)
+	err = data.SetLocation("/srv/data", map[string]string{"size": "1024"})
+	c.Assert(err, gc.IsNil)
+	_, err = s.wordpressUnit.AddStorageInstance("logs", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.StorageInstances(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.StorageInstancesResults{
+		Results: []params.StorageInstancesResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Instances: []params.StorageInstance{{
+				Id:         "data/0",
+				Kind:       "filesystem",
+				Life:       params.Alive,
+				Location:   "/srv/data",
+				Attributes: map[string]string{"size": "1024"},
+			}, {
+				Id:         "logs/1",
+				Kind:       "block",
+				Life:       params.Alive,
+				Attributes: map[string]string{},
+			}}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterV1Suite) TestWatchStorageInstances(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WatchStorageInstances(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event, and
+	// that a new storage instance is reported.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+	_, err = s.wordpressUnit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+}
+
+func (s *uniterV1Suite) TestStorageDetached(c *gc.C) {
+	data, err := s.wordpressUnit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	logs, err := s.wordpressUnit.AddStorageInstance("logs", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	err = data.Destroy()
+	c.Assert(err, gc.IsNil)
+
+	args := params.EntitiesStorageInstances{Entities: []params.EntityStorageInstance{
+		{Tag: "unit-mysql-0", StorageId: "data/0"},
+		{Tag: "unit-wordpress-0", StorageId: "data/0"},
+		{Tag: "unit-wordpress-0", StorageId: "logs/1"},
+		{Tag: "unit-wordpress-0", StorageId: "logs/2"},
+	}}
+	result, err := s.uniter.StorageDetached(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{&params.Error{Message: `cannot remove storage instance "logs/1": storage instance is alive`}},
+			{apiservertesting.NotFoundError(`storage instance "logs/2"`)},
+		},
+	})
+
+	_, err = s.wordpressUnit.StorageInstance("data/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = logs.Refresh()
+	c.Assert(err, gc.IsNil)
+}
+
 func (s *uniterV1Suite) TestAllMachinePorts(c *gc.C) {
 	// Verify no ports are opened yet on the machine or unit.
 	machinePorts, err := s.machine0.AllPorts()
@@ -409,6 +776,32 @@ func (s *uniterV1Suite) TestAllMachinePorts(c *gc.C) {
 	})
 }
 
+func (s *uniterV1Suite) TestMachineAddresses(c *gc.C) {
+	addresses := []network.Address{
+		network.NewAddress("10.0.0.1", network.ScopeCloudLocal),
+		network.NewAddress("8.8.8.8", network.ScopePublic),
+	}
+	err := s.machine0.SetAddresses(addresses...)
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+		{Tag: "machine-0"},
+		{Tag: "machine-1"},
+		{Tag: "machine-42"},
+	}}
+	result, err := s.uniter.MachineAddresses(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.MachineAddressesResults{
+		Results: []params.MachineAddressesResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Addresses: addresses},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterV1Suite) TestRequestReboot(c *gc.C) {
 	args := params.Entities{Entities: []params.Entity{
 		{Tag: s.machine0.Tag().String()},
@@ -16,6 +16,7 @@ import (
 // UnitUpgraderAPI provides access to the UnitUpgrader API facade.
 type UnitUpgraderAPI struct {
 	*common.ToolsSetter
+	*common.MigrationTargetGetter
 
 	st         *state.State
 	resources  *common.Resources
@@ -36,10 +37,11 @@ func NewUnitUpgraderAPI(
 		return authorizer.AuthOwner, nil
 	}
 	return &UnitUpgraderAPI{
-		ToolsSetter: common.NewToolsSetter(st, getCanWrite),
-		st:          st,
-		resources:   resources,
-		authorizer:  authorizer,
+		ToolsSetter:           common.NewToolsSetter(st, getCanWrite),
+		MigrationTargetGetter: common.NewMigrationTargetGetter(st, resources, getCanWrite),
+		st:                    st,
+		resources:             resources,
+		authorizer:            authorizer,
 	}, nil
 }
 
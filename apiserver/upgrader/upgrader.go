@@ -52,12 +52,15 @@ type Upgrader interface {
 	DesiredVersion(args params.Entities) (params.VersionResults, error)
 	Tools(args params.Entities) (params.ToolsResults, error)
 	SetTools(args params.EntitiesVersion) (params.ErrorResults, error)
+	WatchMigrationTarget(args params.Entities) (params.NotifyWatchResults, error)
+	MigrationTarget(args params.Entities) (params.MigrationTargetResults, error)
 }
 
 // UpgraderAPI provides access to the Upgrader API facade.
 type UpgraderAPI struct {
 	*common.ToolsGetter
 	*common.ToolsSetter
+	*common.MigrationTargetGetter
 
 	st         *state.State
 	resources  *common.Resources
@@ -82,11 +85,12 @@ func NewUpgraderAPI(
 	}
 	urlGetter := common.NewToolsURLGetter(env.UUID(), st)
 	return &UpgraderAPI{
-		ToolsGetter: common.NewToolsGetter(st, st, st, urlGetter, getCanReadWrite),
-		ToolsSetter: common.NewToolsSetter(st, getCanReadWrite),
-		st:          st,
-		resources:   resources,
-		authorizer:  authorizer,
+		ToolsGetter:           common.NewToolsGetter(st, st, st, urlGetter, getCanReadWrite),
+		ToolsSetter:           common.NewToolsSetter(st, getCanReadWrite),
+		MigrationTargetGetter: common.NewMigrationTargetGetter(st, resources, getCanReadWrite),
+		st:                    st,
+		resources:             resources,
+		authorizer:            authorizer,
 	}, nil
 }
 
@@ -103,7 +107,7 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.st.WatchForEnvironConfigChanges()
+			watch := u.st.WatchAgentVersion()
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -179,12 +183,28 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
-			if !isNewerVersion || u.entityIsManager(tag) {
-				results[i].Version = &agentVersion
-			} else {
+			desired := agentVersion
+			if isNewerVersion && !u.entityIsManager(tag) {
 				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, version.Current.Number)
-				results[i].Version = &version.Current.Number
+				desired = version.Current.Number
+			}
+			// Agents pinned to an earlier version, or hosting units
+			// of a service that is, are held back at that version.
+			entity, findErr := u.st.FindEntity(tag)
+			if findErr != nil {
+				results[i].Error = common.ServerError(findErr)
+				continue
+			}
+			pinned, pinErr := common.PinnedAgentVersion(entity, desired)
+			if pinErr != nil {
+				results[i].Error = common.ServerError(pinErr)
+				continue
+			}
+			if pinned != desired {
+				logger.Debugf("desired version is %s, but agent %s is held at %s", desired, tag, pinned)
+				desired = pinned
 			}
+			results[i].Version = &desired
 			err = nil
 		}
 		results[i].Error = common.ServerError(err)
@@ -16,6 +16,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/upgrader"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/version"
@@ -91,6 +92,13 @@ func (s *upgraderSuite) TestWatchAPIVersion(c *gc.C) {
 	err = statetesting.SetAgentVersion(s.State, version.MustParse("3.4.567.8"))
 	c.Assert(err, gc.IsNil)
 	wc.AssertOneChange()
+
+	err = s.rawMachine.SetAgentVersionPin(version.MustParse("1.2.3"))
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = s.rawMachine.ClearAgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
 	statetesting.AssertStop(c, w)
 	wc.AssertClosed()
 }
@@ -324,3 +332,139 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, version.Current.Number)
 }
+
+func (s *upgraderSuite) apiMachineUpgrader(c *gc.C) *upgrader.UpgraderAPI {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: s.apiMachine.Tag(),
+	}
+	upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer)
+	c.Assert(err, gc.IsNil)
+	return upgraderAPI
+}
+
+func (s *upgraderSuite) assertDesiredVersion(c *gc.C, upgraderAPI *upgrader.UpgraderAPI, tag names.Tag, expected version.Number) {
+	args := params.Entities{Entities: []params.Entity{{Tag: tag.String()}}}
+	results, err := upgraderAPI.DesiredVersion(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	c.Check(*results.Results[0].Version, gc.Equals, expected)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldByMachinePin(c *gc.C) {
+	newVersion := s.bumpDesiredAgentVersion(c)
+	upgraderAPI := s.apiMachineUpgrader(c)
+
+	err := s.apiMachine.SetAgentVersionPin(version.Current.Number)
+	c.Assert(err, gc.IsNil)
+	s.assertDesiredVersion(c, upgraderAPI, s.apiMachine.Tag(), version.Current.Number)
+
+	err = s.apiMachine.ClearAgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	s.assertDesiredVersion(c, upgraderAPI, s.apiMachine.Tag(), newVersion)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldByServicePin(c *gc.C) {
+	s.bumpDesiredAgentVersion(c)
+	upgraderAPI := s.apiMachineUpgrader(c)
+
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(s.apiMachine)
+	c.Assert(err, gc.IsNil)
+
+	err = svc.SetAgentVersionPin(version.Current.Number)
+	c.Assert(err, gc.IsNil)
+	s.assertDesiredVersion(c, upgraderAPI, s.apiMachine.Tag(), version.Current.Number)
+}
+
+func (s *upgraderSuite) TestDesiredVersionNeverDowngrades(c *gc.C) {
+	// The pin is set before the agent reports its version, which
+	// is later than the pin.
+	err := s.rawMachine.SetAgentVersionPin(version.MustParse("1.2.3"))
+	c.Assert(err, gc.IsNil)
+	s.bumpDesiredAgentVersion(c)
+	s.assertDesiredVersion(c, s.upgrader, s.rawMachine.Tag(), version.Current.Number)
+}
+
+func (s *upgraderSuite) TestToolsHeldByPin(c *gc.C) {
+	s.bumpDesiredAgentVersion(c)
+	err := s.rawMachine.SetAgentVersionPin(version.Current.Number)
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	results, err := s.upgrader.Tools(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[0].Tools.Version, gc.DeepEquals, version.Current)
+}
+
+func (s *upgraderSuite) TestDesiredVersionIgnoresLaterPin(c *gc.C) {
+	later := version.Current.Number
+	later.Major++
+	err := s.rawMachine.SetAgentVersionPin(later)
+	c.Assert(err, gc.IsNil)
+	s.assertDesiredVersion(c, s.upgrader, s.rawMachine.Tag(), version.Current.Number)
+}
+
+func (s *upgraderSuite) TestMigrationTarget(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	watchResults, err := s.upgrader.WatchMigrationTarget(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(watchResults.Results, gc.HasLen, 1)
+	c.Assert(watchResults.Results[0].Error, gc.IsNil)
+	w := s.resources.Get(watchResults.Results[0].NotifyWatcherId).(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	results, err := s.upgrader.MigrationTarget(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	target := state.MigrationTarget{
+		APIHostPorts: [][]network.HostPort{
+			network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+		},
+		CACert: "new-ca-cert",
+	}
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	err = env.SetMigrationTarget(target)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	results, err = s.upgrader.MigrationTarget(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.DeepEquals, []params.MigrationTargetResult{{
+		Result: &params.MigrationTarget{
+			APIHostPorts: target.APIHostPorts,
+			CACert:       target.CACert,
+		},
+	}})
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *upgraderSuite) TestMigrationTargetRefusesWrongAgent(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = names.NewMachineTag("12354")
+	anUpgrader, err := upgrader.NewUpgraderAPI(s.State, s.resources, anAuthorizer)
+	c.Assert(err, gc.IsNil)
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := anUpgrader.MigrationTarget(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	watchResults, err := anUpgrader.WatchMigrationTarget(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(watchResults.Results, gc.HasLen, 1)
+	c.Assert(watchResults.Results[0].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+}
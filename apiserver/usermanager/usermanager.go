@@ -26,6 +26,9 @@ type UserManager interface {
 	EnableUser(args params.Entities) (params.ErrorResults, error)
 	SetPassword(args params.EntityPasswords) (params.ErrorResults, error)
 	UserInfo(args params.UserInfoRequest) (params.UserInfoResults, error)
+	CreateAPIToken(args params.CreateAPIToken) (params.CreateAPITokenResult, error)
+	APITokens() (params.APITokensResult, error)
+	RemoveAPIToken(args params.APITokenIds) (params.ErrorResults, error)
 }
 
 // UserManagerAPI implements the user manager interface and is the concrete
@@ -111,16 +114,22 @@ func (api *UserManagerAPI) getUser(tag string) (*state.User, error) {
 // EnableUser enables one or more users.  If the user is already enabled,
 // the action is consided a success.
 func (api *UserManagerAPI) EnableUser(users params.Entities) (params.ErrorResults, error) {
-	return api.enableUserImpl(users, "enable", (*state.User).Enable)
+	return api.userActionImpl(users, "enable", (*state.User).Enable)
 }
 
 // DisableUser disables one or more users.  If the user is already disabled,
 // the action is consided a success.
 func (api *UserManagerAPI) DisableUser(users params.Entities) (params.ErrorResults, error) {
-	return api.enableUserImpl(users, "disable", (*state.User).Disable)
+	return api.userActionImpl(users, "disable", (*state.User).Disable)
 }
 
-func (api *UserManagerAPI) enableUserImpl(args params.Entities, action string, method func(*state.User) error) (params.ErrorResults, error) {
+// RemoveUser removes one or more users, along with their access to
+// all environments.
+func (api *UserManagerAPI) RemoveUser(users params.Entities) (params.ErrorResults, error) {
+	return api.userActionImpl(users, "remove", (*state.User).Remove)
+}
+
+func (api *UserManagerAPI) userActionImpl(args params.Entities, action string, method func(*state.User) error) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Entities)),
 	}
@@ -233,6 +242,68 @@ func (api *UserManagerAPI) SetPassword(args params.EntityPasswords) (params.Erro
 	return result, nil
 }
 
+// CreateAPIToken makes a new API token with which the logged in user
+// may log in instead of with their password.
+func (api *UserManagerAPI) CreateAPIToken(args params.CreateAPIToken) (params.CreateAPITokenResult, error) {
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return params.CreateAPITokenResult{}, common.ErrPerm
+	}
+	token, secret, err := api.state.AddAPIToken(loggedInUser, state.EnvironmentAccess(args.Scope), args.Description, args.ValidFor)
+	if err != nil {
+		return params.CreateAPITokenResult{}, errors.Trace(err)
+	}
+	logger.Infof("user %q made %s API token %q, valid until %v", loggedInUser.Name(), token.Scope(), token.Id(), token.Expires())
+	return params.CreateAPITokenResult{Id: token.Id(), Token: secret}, nil
+}
+
+// APITokens returns the API tokens of the logged in user.
+func (api *UserManagerAPI) APITokens() (params.APITokensResult, error) {
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return params.APITokensResult{}, common.ErrPerm
+	}
+	tokens, err := api.state.APITokens(loggedInUser)
+	if err != nil {
+		return params.APITokensResult{}, errors.Trace(err)
+	}
+	result := params.APITokensResult{
+		Tokens: make([]params.APITokenInfo, len(tokens)),
+	}
+	for i, token := range tokens {
+		result.Tokens[i] = params.APITokenInfo{
+			Id:          token.Id(),
+			Scope:       string(token.Scope()),
+			Description: token.Description(),
+			DateCreated: token.DateCreated(),
+			Expires:     token.Expires(),
+		}
+	}
+	return result, nil
+}
+
+// RemoveAPIToken revokes API tokens of the logged in user.
+func (api *UserManagerAPI) RemoveAPIToken(args params.APITokenIds) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, common.ErrPerm
+	}
+	for i, id := range args.Ids {
+		token, err := api.state.APIToken(id)
+		if err == nil && token.UserTag() != loggedInUser {
+			err = errors.NotFoundf("API token %q", id)
+		}
+		if err == nil {
+			err = token.Remove()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 func (api *UserManagerAPI) getLoggedInUser() (names.UserTag, error) {
 	switch tag := api.authorizer.GetAuthTag().(type) {
 	case names.UserTag:
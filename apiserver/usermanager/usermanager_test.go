@@ -168,6 +168,54 @@ func (s *userManagerSuite) TestEnableUser(c *gc.C) {
 	c.Assert(barb.IsDisabled(), jc.IsFalse)
 }
 
+func (s *userManagerSuite) TestRemoveUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+
+	args := params.Entities{
+		Entities: []params.Entity{
+			{alex.Tag().String()},
+			{s.AdminUserTag(c).String()},
+			{names.NewLocalUserTag("ellie").String()},
+			{"not-a-tag"},
+		}}
+	result, err := s.usermanager.RemoveUser(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: &params.Error{
+				Message: `failed to remove user: cannot remove user "admin": cannot remove state server environment owner`,
+			}},
+			{Error: &params.Error{
+				Message: "permission denied",
+				Code:    params.CodeUnauthorized,
+			}},
+			{Error: &params.Error{
+				Message: `"not-a-tag" is not a valid tag`,
+			}},
+		}})
+	_, err = s.State.User(alex.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestRemoveUserAsNormalUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, gc.IsNil)
+
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb"})
+
+	args := params.Entities{
+		[]params.Entity{{barb.Tag().String()}},
+	}
+	_, err = usermanager.RemoveUser(args)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	err = barb.Refresh()
+	c.Assert(err, gc.IsNil)
+}
+
 func (s *userManagerSuite) TestDisableUserAsNormalUser(c *gc.C) {
 	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
 	usermanager, err := usermanager.NewUserManagerAPI(
@@ -0,0 +1,283 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterFacade(
+		"NotifyWatcherMux", 0, newNotifyWatcherMux,
+		reflect.TypeOf((*srvNotifyWatcherMux)(nil)),
+	)
+}
+
+// notifyWatcherMuxResource is the name under which each connection's
+// notifyWatcherMux is registered in its resources.
+const notifyWatcherMuxResource = "notifyWatcherMux"
+
+// notifyWatcherMux is the single watch shared by all NotifyWatcherMux
+// calls made on a connection. One goroutine receives the changes of
+// every NotifyWatcher the agent has waited on, and fans them out to
+// Next calls as they are made. Changes that arrive while no Next call
+// is outstanding are kept until the next one.
+type notifyWatcherMux struct {
+	tomb      tomb.Tomb
+	resources *common.Resources
+
+	// requests receives the Next calls made on the connection.
+	requests chan nextRequest
+
+	// wake is signalled by Interrupt to release a blocked Next.
+	wake chan struct{}
+}
+
+// nextRequest holds the ids of the watchers a Next call waits on,
+// the watchers found for those ids, and the channel on which to reply.
+type nextRequest struct {
+	ids      []string
+	watchers map[string]state.NotifyWatcher
+	reply    chan params.NotifyWatcherChanges
+}
+
+func newNotifyWatcherMuxResource(resources *common.Resources) *notifyWatcherMux {
+	m := &notifyWatcherMux{
+		resources: resources,
+		requests:  make(chan nextRequest),
+		wake:      make(chan struct{}, 1),
+	}
+	go func() {
+		defer m.tomb.Done()
+		m.tomb.Kill(m.loop())
+	}()
+	return m
+}
+
+// Stop implements common.Resource.
+func (m *notifyWatcherMux) Stop() error {
+	m.tomb.Kill(nil)
+	return m.tomb.Wait()
+}
+
+// connNotifyWatcherMux returns the notifyWatcherMux registered in the
+// given resources, registering a new one if necessary.
+func connNotifyWatcherMux(resources *common.Resources) *notifyWatcherMux {
+	for {
+		if m, ok := resources.Get(notifyWatcherMuxResource).(*notifyWatcherMux); ok {
+			return m
+		}
+		m := newNotifyWatcherMuxResource(resources)
+		if err := resources.RegisterNamed(notifyWatcherMuxResource, m); err == nil {
+			return m
+		}
+		// Another call registered one first; use that.
+		m.Stop()
+	}
+}
+
+// next waits for changes on the watchers with the given ids, as
+// described by srvNotifyWatcherMux.Next.
+func (m *notifyWatcherMux) next(ids []string) (params.NotifyWatcherChanges, error) {
+	// The watchers are looked up here rather than in loop, because
+	// the resources are locked while they are being stopped, and
+	// stopping them waits for loop to finish.
+	req := nextRequest{
+		ids:      ids,
+		watchers: make(map[string]state.NotifyWatcher),
+		reply:    make(chan params.NotifyWatcherChanges, 1),
+	}
+	for _, id := range ids {
+		if w, ok := m.resources.Get(id).(state.NotifyWatcher); ok {
+			req.watchers[id] = w
+		}
+	}
+	select {
+	case m.requests <- req:
+	case <-m.tomb.Dying():
+		return params.NotifyWatcherChanges{}, common.ErrStoppedWatcher
+	}
+	select {
+	case result := <-req.reply:
+		return result, nil
+	case <-m.tomb.Dying():
+		return params.NotifyWatcherChanges{}, common.ErrStoppedWatcher
+	}
+}
+
+// interrupt releases any blocked call to next, or the following one
+// if there is none.
+func (m *notifyWatcherMux) interrupt() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (m *notifyWatcherMux) loop() error {
+	// watched holds the watchers whose changes are being received.
+	watched := make(map[string]state.NotifyWatcher)
+	// pending holds the changes not yet reported by a Next call.
+	pending := make(map[string]params.NotifyWatcherChange)
+	// waiting holds the outstanding Next call, if any.
+	var waiting *nextRequest
+
+	// reply reports any pending changes of the watchers that the
+	// outstanding Next call waits on.
+	reply := func() {
+		if waiting == nil {
+			return
+		}
+		var result params.NotifyWatcherChanges
+		for _, id := range waiting.ids {
+			if change, ok := pending[id]; ok {
+				result.Changes = append(result.Changes, change)
+				delete(pending, id)
+			}
+		}
+		if len(result.Changes) > 0 {
+			waiting.reply <- result
+			waiting = nil
+		}
+	}
+
+	const (
+		dyingCase = iota
+		requestCase
+		wakeCase
+		firstWatcherCase
+	)
+	for {
+		cases := []reflect.SelectCase{{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(m.tomb.Dying()),
+		}, {
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(m.requests),
+		}, {
+			Dir: reflect.SelectRecv,
+		}}
+		if waiting != nil {
+			cases[wakeCase].Chan = reflect.ValueOf(m.wake)
+		}
+		var ids []string
+		for id, w := range watched {
+			if _, ok := pending[id]; ok {
+				// The change has yet to be reported; any
+				// further ones make no difference.
+				continue
+			}
+			ids = append(ids, id)
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(w.Changes()),
+			})
+		}
+		chosen, value, ok := reflect.Select(cases)
+		switch chosen {
+		case dyingCase:
+			return tomb.ErrDying
+		case requestCase:
+			if waiting != nil {
+				// A new call replaces the outstanding one,
+				// which returns without reporting anything.
+				waiting.reply <- params.NotifyWatcherChanges{}
+			}
+			req := value.Interface().(nextRequest)
+			for _, id := range req.ids {
+				if _, ok := watched[id]; ok {
+					continue
+				}
+				if w, ok := req.watchers[id]; ok {
+					watched[id] = w
+					continue
+				}
+				pending[id] = params.NotifyWatcherChange{
+					Id:    id,
+					Error: common.ServerError(common.ErrUnknownWatcher),
+				}
+			}
+			waiting = &req
+			reply()
+		case wakeCase:
+			waiting.reply <- params.NotifyWatcherChanges{}
+			waiting = nil
+		default:
+			id := ids[chosen-firstWatcherCase]
+			change := params.NotifyWatcherChange{Id: id}
+			if !ok {
+				// The watcher has stopped, so stop receiving
+				// from it. Unless it is being waited on now,
+				// forget it: if it is waited on again, it will
+				// be found to have stopped then.
+				w := watched[id]
+				delete(watched, id)
+				if !waitingOn(waiting, id) {
+					continue
+				}
+				err := w.Err()
+				if err == nil {
+					err = common.ErrStoppedWatcher
+				}
+				change.Error = common.ServerError(err)
+			}
+			pending[id] = change
+			reply()
+		}
+	}
+}
+
+// waitingOn reports whether the given Next call waits on the watcher
+// with the given id.
+func waitingOn(req *nextRequest, id string) bool {
+	if req == nil {
+		return false
+	}
+	for _, reqId := range req.ids {
+		if reqId == id {
+			return true
+		}
+	}
+	return false
+}
+
+// srvNotifyWatcherMux lets an agent wait for changes on many
+// NotifyWatchers with a single outstanding call, rather than
+// calling NotifyWatcher.Next on each of them. All the calls made
+// on a connection share one notifyWatcherMux.
+type srvNotifyWatcherMux struct {
+	mux *notifyWatcherMux
+}
+
+func newNotifyWatcherMux(st *state.State, resources *common.Resources, auth common.Authorizer, id string) (interface{}, error) {
+	if !isAgent(auth) {
+		return nil, common.ErrPerm
+	}
+	if id != "" {
+		return nil, common.ErrBadId
+	}
+	return &srvNotifyWatcherMux{
+		mux: connNotifyWatcherMux(resources),
+	}, nil
+}
+
+// Next returns when at least one of the given NotifyWatchers has
+// changed or stopped since it was last reported, reporting all the
+// watchers that have. It returns without reporting any watchers if
+// Interrupt is called meanwhile.
+func (m *srvNotifyWatcherMux) Next(args params.NotifyWatcherIds) (params.NotifyWatcherChanges, error) {
+	return m.mux.next(args.NotifyWatcherIds)
+}
+
+// Interrupt makes an outstanding call to Next return without
+// reporting any watchers, so the client can wait on a new set.
+func (m *srvNotifyWatcherMux) Interrupt() {
+	m.mux.interrupt()
+}
@@ -0,0 +1,188 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type watcherMuxSuite struct {
+	testing.BaseSuite
+	resources *common.Resources
+	mux       *notifyWatcherMux
+}
+
+var _ = gc.Suite(&watcherMuxSuite{})
+
+func (s *watcherMuxSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	s.mux = connNotifyWatcherMux(s.resources)
+}
+
+func (s *watcherMuxSuite) TestOneMuxPerConnection(c *gc.C) {
+	c.Assert(connNotifyWatcherMux(s.resources), gc.Equals, s.mux)
+}
+
+func (s *watcherMuxSuite) TestNextReportsChangesMadeBetweenCalls(c *gc.C) {
+	w0 := newFakeNotifyWatcher()
+	id0 := s.resources.Register(w0)
+	w1 := newFakeNotifyWatcher()
+	id1 := s.resources.Register(w1)
+	ids := []string{id0, id1}
+
+	result, err := s.mux.next(ids)
+	c.Assert(err, gc.IsNil)
+	assertChanged(c, result, id0, id1)
+
+	// Both watchers change while no call is outstanding; the
+	// changes are kept for the next call.
+	w0.change(c)
+	w1.change(c)
+	result, err = s.mux.next(ids)
+	c.Assert(err, gc.IsNil)
+	assertChanged(c, result, id0, id1)
+
+	// Only the watchers waited on are reported.
+	w1.change(c)
+	w0.change(c)
+	result, err = s.mux.next([]string{id0})
+	c.Assert(err, gc.IsNil)
+	assertChanged(c, result, id0)
+	result, err = s.mux.next(ids)
+	c.Assert(err, gc.IsNil)
+	assertChanged(c, result, id1)
+}
+
+func (s *watcherMuxSuite) TestNextReportsUnknownAndStoppedWatchers(c *gc.C) {
+	w := newFakeNotifyWatcher()
+	id := s.resources.Register(w)
+	result, err := s.mux.next([]string{id, "99"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Changes, jc.DeepEquals, []params.NotifyWatcherChange{{
+		Id:    "99",
+		Error: common.ServerError(common.ErrUnknownWatcher),
+	}})
+
+	result, err = s.mux.next([]string{id})
+	c.Assert(err, gc.IsNil)
+	assertChanged(c, result, id)
+
+	done := make(chan params.NotifyWatcherChanges)
+	go func() {
+		result, err := s.mux.next([]string{id})
+		c.Check(err, gc.IsNil)
+		done <- result
+	}()
+	w.Stop()
+	select {
+	case result := <-done:
+		c.Assert(result.Changes, jc.DeepEquals, []params.NotifyWatcherChange{{
+			Id:    id,
+			Error: common.ServerError(common.ErrStoppedWatcher),
+		}})
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for stopped watcher")
+	}
+}
+
+func (s *watcherMuxSuite) TestInterrupt(c *gc.C) {
+	id := s.resources.Register(newFakeNotifyWatcher())
+	result, err := s.mux.next([]string{id})
+	c.Assert(err, gc.IsNil)
+	assertChanged(c, result, id)
+
+	s.mux.interrupt()
+	result, err = s.mux.next([]string{id})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Changes, gc.HasLen, 0)
+}
+
+func (s *watcherMuxSuite) TestStopReleasesNext(c *gc.C) {
+	id := s.resources.Register(newFakeNotifyWatcher())
+	result, err := s.mux.next([]string{id})
+	c.Assert(err, gc.IsNil)
+	assertChanged(c, result, id)
+
+	done := make(chan error)
+	go func() {
+		_, err := s.mux.next([]string{id})
+		done <- err
+	}()
+	s.resources.StopAll()
+	select {
+	case err := <-done:
+		c.Assert(err, gc.Equals, common.ErrStoppedWatcher)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for Next to return")
+	}
+}
+
+// assertChanged asserts that the result reports exactly the given
+// watchers as changed, in any order.
+func assertChanged(c *gc.C, result params.NotifyWatcherChanges, ids ...string) {
+	var got []string
+	for _, change := range result.Changes {
+		c.Assert(change.Error, gc.IsNil)
+		got = append(got, change.Id)
+	}
+	c.Assert(got, jc.SameContents, ids)
+}
+
+// fakeNotifyWatcher is a state.NotifyWatcher whose changes are
+// sent by the test.
+type fakeNotifyWatcher struct {
+	tomb    tomb.Tomb
+	changes chan struct{}
+}
+
+func newFakeNotifyWatcher() *fakeNotifyWatcher {
+	w := &fakeNotifyWatcher{changes: make(chan struct{}, 1)}
+	// Send the initial event.
+	w.changes <- struct{}{}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.changes)
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+// change sends a change, and waits until it has been received.
+func (w *fakeNotifyWatcher) change(c *gc.C) {
+	select {
+	case w.changes <- struct{}{}:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out sending change")
+	}
+	// Wait for the change to be received, so that the next one
+	// is not coalesced with it.
+	for i := 0; len(w.changes) > 0; i++ {
+		if i > 1000 {
+			c.Fatalf("timed out waiting for change to be received")
+		}
+		time.Sleep(testing.ShortWait / 10)
+	}
+}
+
+func (w *fakeNotifyWatcher) Changes() <-chan struct{} { return w.changes }
+func (w *fakeNotifyWatcher) Kill()                    { w.tomb.Kill(nil) }
+func (w *fakeNotifyWatcher) Wait() error              { return w.tomb.Wait() }
+func (w *fakeNotifyWatcher) Err() error               { return w.tomb.Err() }
+
+func (w *fakeNotifyWatcher) Stop() error {
+	w.tomb.Kill(nil)
+	return w.tomb.Wait()
+}
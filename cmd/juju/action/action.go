@@ -0,0 +1,170 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/api/actions"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+var actionDoc = `
+"juju action" is used to queue charm actions on units, to check on
+their progress, and to retrieve their results.
+`
+
+const actionPurpose = "execute, manage, monitor, and retrieve results of actions"
+
+// Command is the top-level command wrapping all action functionality.
+type Command struct {
+	cmd.SuperCommand
+}
+
+// NewSuperCommand returns a new action super-command.
+func NewSuperCommand() cmd.Command {
+	actionCmd := Command{
+		SuperCommand: *cmd.NewSuperCommand(
+			cmd.SuperCommandParams{
+				Name:        "action",
+				Doc:         actionDoc,
+				UsagePrefix: "juju",
+				Purpose:     actionPurpose,
+			},
+		),
+	}
+	actionCmd.Register(envcmd.Wrap(&DoCommand{}))
+	actionCmd.Register(envcmd.Wrap(&FetchCommand{}))
+	actionCmd.Register(envcmd.Wrap(&StatusCommand{}))
+	return &actionCmd
+}
+
+// APIClient represents the action API client functionality used by
+// the action command.
+type APIClient interface {
+	io.Closer
+
+	// Enqueue takes a list of Actions and queues them up to be executed
+	// by the designated ActionReceiver.
+	Enqueue(params.Actions) (params.ActionResults, error)
+
+	// Actions fetches the Actions identified by the given tags.
+	Actions(params.ActionTags) (params.ActionResults, error)
+
+	// ListAll returns all of the Actions that have been queued or run
+	// by each of the given ActionReceivers.
+	ListAll(params.Tags) (params.ActionsByReceivers, error)
+
+	// ServiceCharmActions returns the charm.Actions defined by the
+	// charm of the given service.
+	ServiceCharmActions(names.ServiceTag) (*charm.Actions, error)
+}
+
+// ActionCommandBase is the base type for action sub-commands.
+type ActionCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+// NewActionAPIClient returns a client for the action api endpoint.
+func (c *ActionCommandBase) NewActionAPIClient() (APIClient, error) {
+	return newAPIClient(c)
+}
+
+var newAPIClient = func(c *ActionCommandBase) (APIClient, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return actions.NewClient(root), nil
+}
+
+// getUnitGroupUnits returns the names of the units currently in the
+// named unit group.
+var getUnitGroupUnits = func(c *ActionCommandBase, name string) ([]string, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer client.Close()
+	return client.UnitGroupUnits(name)
+}
+
+// parseActionId converts the user supplied action id into an ActionTag.
+func parseActionId(id string) (names.ActionTag, error) {
+	if !names.IsValidAction(id) {
+		return names.ActionTag{}, errors.Errorf("invalid action id %q", id)
+	}
+	return names.NewActionTag(id), nil
+}
+
+// formatActionResult converts a params.ActionResult into a map suitable
+// for rendering with cmd.Output.
+func formatActionResult(result params.ActionResult) map[string]interface{} {
+	formatted := map[string]interface{}{
+		"status": result.Status,
+	}
+	if result.Action != nil {
+		formatted["id"] = result.Action.Tag.Id()
+		formatted["action"] = result.Action.Name
+		if receiver := result.Action.Tag.PrefixTag(); receiver != nil {
+			formatted["unit"] = receiver.Id()
+		}
+	}
+	if result.Message != "" {
+		formatted["message"] = result.Message
+	}
+	if len(result.Output) > 0 {
+		formatted["results"] = result.Output
+	}
+	return formatted
+}
+
+// conform ensures all keys of any nested maps are strings, so that the
+// value decoded from YAML can be serialised as JSON for the API.
+func conform(input interface{}) (interface{}, error) {
+	switch typedInput := input.(type) {
+	case map[string]interface{}:
+		newMap := make(map[string]interface{})
+		for key, value := range typedInput {
+			newValue, err := conform(value)
+			if err != nil {
+				return nil, err
+			}
+			newMap[key] = newValue
+		}
+		return newMap, nil
+	case map[interface{}]interface{}:
+		newMap := make(map[string]interface{})
+		for key, value := range typedInput {
+			typedKey, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("map keyed with non-string value %v", key)
+			}
+			newValue, err := conform(value)
+			if err != nil {
+				return nil, err
+			}
+			newMap[typedKey] = newValue
+		}
+		return newMap, nil
+	case []interface{}:
+		newSlice := make([]interface{}, len(typedInput))
+		for i, value := range typedInput {
+			newValue, err := conform(value)
+			if err != nil {
+				return nil, err
+			}
+			newSlice[i] = newValue
+		}
+		return newSlice, nil
+	}
+	return input, nil
+}
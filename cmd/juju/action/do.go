@@ -0,0 +1,213 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	goyaml "gopkg.in/yaml.v1"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const doDoc = `
+Queue an action for execution on a given unit.  The action must be
+declared in the actions.yaml of the unit's charm, and its parameters
+are validated against the schema declared there before being queued.
+
+Parameters may be supplied in a YAML file with --params, and/or as
+key=value pairs on the command line; values given on the command line
+override those from the file.  The id of the queued action is printed,
+and can be used with "juju action fetch" to retrieve its results.
+
+With --group, the action is queued on every unit currently in the named
+unit group instead of a single unit, and the id of each queued action is
+printed alongside its unit.
+
+Examples:
+
+    juju action do mysql/0 backup
+    juju action do mysql/0 backup --params backup.yaml
+    juju action do mysql/0 backup outfile=/tmp/db.bz2 compress=true
+    juju action do --group canary backup
+`
+
+// DoCommand enqueues an Action for running on the given unit with given
+// params.
+type DoCommand struct {
+	ActionCommandBase
+	unitTag    names.UnitTag
+	group      string
+	actionName string
+	paramsYAML cmd.FileVar
+	args       []string
+}
+
+// Info implements Command.Info.
+func (c *DoCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "do",
+		Args:    "(<unit> | --group <name>) <action name> [key=value ...]",
+		Purpose: "queue an action for execution",
+		Doc:     doDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *DoCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
+	f.StringVar(&c.group, "group", "", "queue the action on all units in the named unit group")
+}
+
+// Init implements Command.Init.
+func (c *DoCommand) Init(args []string) error {
+	if c.group == "" {
+		if len(args) == 0 {
+			return errors.New("no unit specified")
+		}
+		unitName := args[0]
+		if !names.IsValidUnit(unitName) {
+			return errors.Errorf("invalid unit name %q", unitName)
+		}
+		c.unitTag = names.NewUnitTag(unitName)
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return errors.New("no action specified")
+	}
+	c.actionName, args = args[0], args[1:]
+	for _, arg := range args {
+		if !strings.Contains(arg, "=") {
+			return errors.Errorf("argument %q must be of the form key=value", arg)
+		}
+	}
+	c.args = args
+	return nil
+}
+
+// actionParams builds the action parameters from the params file, if
+// any, overlaid with the key=value command line arguments.
+func (c *DoCommand) actionParams(ctx *cmd.Context) (map[string]interface{}, error) {
+	actionParams := map[string]interface{}{}
+	if c.paramsYAML.Path != "" {
+		data, err := c.paramsYAML.Read(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := goyaml.Unmarshal(data, &actionParams); err != nil {
+			return nil, errors.Annotate(err, "cannot parse params file")
+		}
+	}
+	for _, arg := range c.args {
+		parts := strings.SplitN(arg, "=", 2)
+		var value interface{}
+		if err := goyaml.Unmarshal([]byte(parts[1]), &value); err != nil {
+			return nil, errors.Annotatef(err, "cannot parse value for %q", parts[0])
+		}
+		actionParams[parts[0]] = value
+	}
+	conformed, err := conform(actionParams)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return conformed.(map[string]interface{}), nil
+}
+
+// Run implements Command.Run.
+func (c *DoCommand) Run(ctx *cmd.Context) error {
+	actionParams, err := c.actionParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.NewActionAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	unitTags := []names.UnitTag{c.unitTag}
+	if c.group != "" {
+		unitNames, err := getUnitGroupUnits(&c.ActionCommandBase, c.group)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(unitNames) == 0 {
+			return errors.Errorf("unit group %q has no units", c.group)
+		}
+		unitTags = make([]names.UnitTag, len(unitNames))
+		for i, unitName := range unitNames {
+			unitTags[i] = names.NewUnitTag(unitName)
+		}
+	}
+
+	// Validate the parameters against the action's schema once for
+	// each service involved.
+	validated := make(map[string]bool)
+	actions := make([]params.Action, len(unitTags))
+	for i, unitTag := range unitTags {
+		serviceName := names.UnitService(unitTag.Id())
+		if !validated[serviceName] {
+			specs, err := client.ServiceCharmActions(names.NewServiceTag(serviceName))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			spec, ok := specs.ActionSpecs[c.actionName]
+			if !ok {
+				return errors.Errorf("action %q not defined on unit %q", c.actionName, unitTag.Id())
+			}
+			if _, err := spec.ValidateParams(actionParams); err != nil {
+				return errors.Annotatef(err, "invalid parameters for action %q", c.actionName)
+			}
+			validated[serviceName] = true
+		}
+		actions[i] = params.Action{
+			Receiver:   unitTag,
+			Name:       c.actionName,
+			Parameters: actionParams,
+		}
+	}
+
+	results, err := client.Enqueue(params.Actions{Actions: actions})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != len(actions) {
+		return errors.Errorf("expected %d result(s), got %d", len(actions), len(results.Results))
+	}
+	if c.group == "" {
+		result := results.Results[0]
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.Action == nil {
+			return errors.New("action failed to enqueue")
+		}
+		fmt.Fprintf(ctx.Stdout, "Action queued with id: %s\n", result.Action.Tag.Id())
+		return nil
+	}
+	failed := false
+	for i, result := range results.Results {
+		unitName := unitTags[i].Id()
+		switch {
+		case result.Error != nil:
+			ctx.Infof("%s: %v", unitName, result.Error)
+			failed = true
+		case result.Action == nil:
+			ctx.Infof("%s: action failed to enqueue", unitName)
+			failed = true
+		default:
+			fmt.Fprintf(ctx.Stdout, "%s: action queued with id: %s\n", unitName, result.Action.Tag.Id())
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
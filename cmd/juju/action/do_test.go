@@ -0,0 +1,181 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type DoSuite struct {
+	BaseActionSuite
+	client *fakeAPIClient
+}
+
+var _ = gc.Suite(&DoSuite{})
+
+func (s *DoSuite) SetUpTest(c *gc.C) {
+	s.BaseActionSuite.SetUpTest(c)
+	s.client = &fakeAPIClient{
+		charmActions: &charm.Actions{
+			ActionSpecs: map[string]charm.ActionSpec{
+				"backup": charm.ActionSpec{
+					Description: "back up the database",
+					Params: map[string]interface{}{
+						"title": "backup",
+						"type":  "object",
+						"properties": map[string]interface{}{
+							"outfile": map[string]interface{}{
+								"type": "string",
+							},
+							"level": map[string]interface{}{
+								"type": "integer",
+							},
+						},
+					},
+				},
+			},
+		},
+		results: params.ActionResults{
+			Results: []params.ActionResult{{
+				Action: &params.Action{
+					Tag:  names.JoinActionTag("mysql/0", 1),
+					Name: "backup",
+				},
+				Status: params.ActionPending,
+			}},
+		},
+	}
+	s.patchAPIClient(s.client)
+}
+
+func (s *DoSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no unit specified",
+	}, {
+		args: []string{"mysql/0"},
+		err:  "no action specified",
+	}, {
+		args: []string{"mysql", "backup"},
+		err:  `invalid unit name "mysql"`,
+	}, {
+		args: []string{"mysql/0", "backup", "outfile"},
+		err:  `argument "outfile" must be of the form key=value`,
+	}, {
+		args: []string{"--group", "canary"},
+		err:  "no action specified",
+	}, {
+		args: []string{"--group", "canary", "backup", "outfile"},
+		err:  `argument "outfile" must be of the form key=value`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := testing.RunCommand(c, s.command, append([]string{"do"}, test.args...)...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *DoSuite) TestRun(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.command, "do", "mysql/0", "backup", "outfile=foo.bz2", "level=3")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "Action queued with id: mysql/0_a_1\n")
+	c.Check(s.client.enqueued, jc.DeepEquals, params.Actions{
+		Actions: []params.Action{{
+			Receiver: names.NewUnitTag("mysql/0"),
+			Name:     "backup",
+			Parameters: map[string]interface{}{
+				"outfile": "foo.bz2",
+				"level":   3,
+			},
+		}},
+	})
+}
+
+func (s *DoSuite) TestRunParamsFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "params.yaml")
+	err := ioutil.WriteFile(path, []byte("outfile: foo.bz2\nlevel: 1\n"), 0644)
+	c.Assert(err, gc.IsNil)
+
+	_, err = testing.RunCommand(c, s.command, "do", "mysql/0", "backup", "--params", path, "level=9")
+	c.Assert(err, gc.IsNil)
+	c.Check(s.client.enqueued.Actions, gc.HasLen, 1)
+	c.Check(s.client.enqueued.Actions[0].Parameters, jc.DeepEquals, map[string]interface{}{
+		"outfile": "foo.bz2",
+		"level":   9,
+	})
+}
+
+func (s *DoSuite) TestRunUndefinedAction(c *gc.C) {
+	_, err := testing.RunCommand(c, s.command, "do", "mysql/0", "snapshot")
+	c.Check(err, gc.ErrorMatches, `action "snapshot" not defined on unit "mysql/0"`)
+	c.Check(s.client.enqueued.Actions, gc.HasLen, 0)
+}
+
+func (s *DoSuite) TestRunInvalidParams(c *gc.C) {
+	_, err := testing.RunCommand(c, s.command, "do", "mysql/0", "backup", "level=high")
+	c.Check(err, gc.ErrorMatches, `invalid parameters for action "backup": .*`)
+	c.Check(s.client.enqueued.Actions, gc.HasLen, 0)
+}
+
+func (s *DoSuite) TestRunEnqueueError(c *gc.C) {
+	s.client.results.Results[0] = params.ActionResult{
+		Error: &params.Error{Message: "unit is dead"},
+	}
+	_, err := testing.RunCommand(c, s.command, "do", "mysql/0", "backup")
+	c.Check(err, gc.ErrorMatches, "unit is dead")
+}
+
+func (s *DoSuite) patchUnitGroup(units ...string) {
+	s.PatchValue(action.GetUnitGroupUnits, func(_ *action.ActionCommandBase, name string) ([]string, error) {
+		if name != "canary" {
+			return nil, errors.NotFoundf("unit group %q", name)
+		}
+		return units, nil
+	})
+}
+
+func (s *DoSuite) TestRunGroup(c *gc.C) {
+	s.patchUnitGroup("mysql/0", "mysql/1")
+	s.client.results.Results = append(s.client.results.Results, params.ActionResult{
+		Error: &params.Error{Message: "unit is dead"},
+	})
+	ctx, err := testing.RunCommand(c, s.command, "do", "--group", "canary", "backup", "level=3")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Check(testing.Stdout(ctx), gc.Equals, "mysql/0: action queued with id: mysql/0_a_1\n")
+	c.Check(testing.Stderr(ctx), gc.Equals, "mysql/1: unit is dead\n")
+	params3 := map[string]interface{}{"level": 3}
+	c.Check(s.client.enqueued, jc.DeepEquals, params.Actions{
+		Actions: []params.Action{{
+			Receiver:   names.NewUnitTag("mysql/0"),
+			Name:       "backup",
+			Parameters: params3,
+		}, {
+			Receiver:   names.NewUnitTag("mysql/1"),
+			Name:       "backup",
+			Parameters: params3,
+		}},
+	})
+}
+
+func (s *DoSuite) TestRunGroupErrors(c *gc.C) {
+	s.patchUnitGroup()
+	_, err := testing.RunCommand(c, s.command, "do", "--group", "missing", "backup")
+	c.Check(err, gc.ErrorMatches, `unit group "missing" not found`)
+	_, err = testing.RunCommand(c, s.command, "do", "--group", "canary", "backup")
+	c.Check(err, gc.ErrorMatches, `unit group "canary" has no units`)
+	c.Check(s.client.enqueued.Actions, gc.HasLen, 0)
+}
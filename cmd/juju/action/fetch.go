@@ -0,0 +1,78 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const fetchDoc = `
+Show the status and results of an action, given the id printed by
+"juju action do".  An action that has not yet been run is reported as
+pending.
+`
+
+// FetchCommand fetches the results of an action by id.
+type FetchCommand struct {
+	ActionCommandBase
+	actionTag names.ActionTag
+	out       cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *FetchCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "fetch",
+		Args:    "<action id>",
+		Purpose: "show results of an action by id",
+		Doc:     fetchDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *FetchCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *FetchCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no action id specified")
+	}
+	tag, err := parseActionId(args[0])
+	if err != nil {
+		return err
+	}
+	c.actionTag = tag
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *FetchCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewActionAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	results, err := client.Actions(params.ActionTags{
+		Actions: []names.ActionTag{c.actionTag},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return c.out.Write(ctx, formatActionResult(result))
+}
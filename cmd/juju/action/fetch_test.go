@@ -0,0 +1,72 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type FetchSuite struct {
+	BaseActionSuite
+	client *fakeAPIClient
+}
+
+var _ = gc.Suite(&FetchSuite{})
+
+func (s *FetchSuite) SetUpTest(c *gc.C) {
+	s.BaseActionSuite.SetUpTest(c)
+	s.client = &fakeAPIClient{
+		results: params.ActionResults{
+			Results: []params.ActionResult{{
+				Action: &params.Action{
+					Tag:  names.JoinActionTag("mysql/0", 1),
+					Name: "backup",
+				},
+				Status:  params.ActionCompleted,
+				Message: "done",
+				Output:  map[string]interface{}{"outfile": "foo.bz2"},
+			}},
+		},
+	}
+	s.patchAPIClient(s.client)
+}
+
+func (s *FetchSuite) TestInitErrors(c *gc.C) {
+	_, err := testing.RunCommand(c, s.command, "fetch")
+	c.Check(err, gc.ErrorMatches, "no action id specified")
+	_, err = testing.RunCommand(c, s.command, "fetch", "foo")
+	c.Check(err, gc.ErrorMatches, `invalid action id "foo"`)
+	_, err = testing.RunCommand(c, s.command, "fetch", "mysql/0_a_1", "extra")
+	c.Check(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *FetchSuite) TestRun(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.command, "fetch", "mysql/0_a_1")
+	c.Assert(err, gc.IsNil)
+	c.Check(s.client.fetched, jc.DeepEquals, params.ActionTags{
+		Actions: []names.ActionTag{names.JoinActionTag("mysql/0", 1)},
+	})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+action: backup
+id: mysql/0_a_1
+message: done
+results:
+  outfile: foo.bz2
+status: completed
+unit: mysql/0
+`[1:])
+}
+
+func (s *FetchSuite) TestRunError(c *gc.C) {
+	s.client.results.Results[0] = params.ActionResult{
+		Error: &params.Error{Message: `action "mysql/0_a_1" not found`},
+	}
+	_, err := testing.RunCommand(c, s.command, "fetch", "mysql/0_a_1")
+	c.Check(err, gc.ErrorMatches, `action "mysql/0_a_1" not found`)
+}
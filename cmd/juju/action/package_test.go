@@ -0,0 +1,78 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	jujutesting "github.com/juju/juju/testing"
+)
+
+func TestPackage(t *testing.T) {
+	gc.TestingT(t)
+}
+
+type BaseActionSuite struct {
+	jujutesting.FakeJujuHomeSuite
+	command *action.Command
+}
+
+func (s *BaseActionSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.command = action.NewSuperCommand().(*action.Command)
+}
+
+func (s *BaseActionSuite) patchAPIClient(client *fakeAPIClient) {
+	s.PatchValue(action.NewAPIClient,
+		func(c *action.ActionCommandBase) (action.APIClient, error) {
+			return client, nil
+		},
+	)
+}
+
+type fakeAPIClient struct {
+	charmActions *charm.Actions
+	results      params.ActionResults
+	byReceivers  params.ActionsByReceivers
+	err          error
+
+	enqueued params.Actions
+	fetched  params.ActionTags
+	listed   params.Tags
+}
+
+var _ action.APIClient = (*fakeAPIClient)(nil)
+
+func (c *fakeAPIClient) Enqueue(args params.Actions) (params.ActionResults, error) {
+	c.enqueued = args
+	return c.results, c.err
+}
+
+func (c *fakeAPIClient) Actions(args params.ActionTags) (params.ActionResults, error) {
+	c.fetched = args
+	return c.results, c.err
+}
+
+func (c *fakeAPIClient) ListAll(args params.Tags) (params.ActionsByReceivers, error) {
+	c.listed = args
+	return c.byReceivers, c.err
+}
+
+func (c *fakeAPIClient) ServiceCharmActions(tag names.ServiceTag) (*charm.Actions, error) {
+	if c.charmActions == nil {
+		return nil, errors.NotFoundf("service %q", tag.Id())
+	}
+	return c.charmActions, nil
+}
+
+func (c *fakeAPIClient) Close() error {
+	return nil
+}
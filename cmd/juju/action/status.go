@@ -0,0 +1,78 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const statusDoc = `
+Show the status of all actions, pending and completed, that have been
+queued on the given unit.
+`
+
+// StatusCommand shows the status of the actions queued on a unit.
+type StatusCommand struct {
+	ActionCommandBase
+	unitTag names.UnitTag
+	out     cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *StatusCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "status",
+		Args:    "<unit>",
+		Purpose: "show status of all actions queued on a unit",
+		Doc:     statusDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *StatusCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit specified")
+	}
+	if !names.IsValidUnit(args[0]) {
+		return errors.Errorf("invalid unit name %q", args[0])
+	}
+	c.unitTag = names.NewUnitTag(args[0])
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *StatusCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewActionAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	results, err := client.ListAll(params.Tags{Tags: []names.Tag{c.unitTag}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Actions) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results.Actions))
+	}
+	result := results.Actions[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	formatted := make([]map[string]interface{}, len(result.Actions))
+	for i, action := range result.Actions {
+		formatted[i] = formatActionResult(action)
+	}
+	return c.out.Write(ctx, map[string]interface{}{"actions": formatted})
+}
@@ -0,0 +1,71 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type StatusSuite struct {
+	BaseActionSuite
+	client *fakeAPIClient
+}
+
+var _ = gc.Suite(&StatusSuite{})
+
+func (s *StatusSuite) SetUpTest(c *gc.C) {
+	s.BaseActionSuite.SetUpTest(c)
+	s.client = &fakeAPIClient{
+		byReceivers: params.ActionsByReceivers{
+			Actions: []params.ActionsByReceiver{{
+				Receiver: names.NewUnitTag("mysql/0"),
+				Actions: []params.ActionResult{{
+					Action: &params.Action{
+						Tag:  names.JoinActionTag("mysql/0", 2),
+						Name: "snapshot",
+					},
+					Status: params.ActionPending,
+				}, {
+					Action: &params.Action{
+						Tag:  names.JoinActionTag("mysql/0", 1),
+						Name: "backup",
+					},
+					Status: params.ActionFailed,
+				}},
+			}},
+		},
+	}
+	s.patchAPIClient(s.client)
+}
+
+func (s *StatusSuite) TestInitErrors(c *gc.C) {
+	_, err := testing.RunCommand(c, s.command, "status")
+	c.Check(err, gc.ErrorMatches, "no unit specified")
+	_, err = testing.RunCommand(c, s.command, "status", "mysql")
+	c.Check(err, gc.ErrorMatches, `invalid unit name "mysql"`)
+}
+
+func (s *StatusSuite) TestRun(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.command, "status", "mysql/0")
+	c.Assert(err, gc.IsNil)
+	c.Check(s.client.listed, jc.DeepEquals, params.Tags{
+		Tags: []names.Tag{names.NewUnitTag("mysql/0")},
+	})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+actions:
+- action: snapshot
+  id: mysql/0_a_2
+  status: pending
+  unit: mysql/0
+- action: backup
+  id: mysql/0_a_1
+  status: failed
+  unit: mysql/0
+`[1:])
+}
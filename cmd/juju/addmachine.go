@@ -35,8 +35,9 @@ To add a container to an existing machine, use the <container>:<machinenumber>
 format.
 
 When adding a new machine, you may specify constraints for the machine to be
-provisioned.  Constraints cannot be combined with deploying a container to an
-existing machine.
+provisioned.  When adding a container to an existing machine, the mem,
+cpu-cores and cpu-power constraints are applied as resource limits on the
+container.
 
 Currently, the only supported container type is lxc.
 
@@ -52,6 +53,9 @@ Examples:
    juju add-machine lxc                  (starts a new machine with an lxc container)
    juju add-machine lxc -n 2             (starts 2 new machines with an lxc container)
    juju add-machine lxc:4                (starts a new lxc container on machine 4)
+   juju add-machine lxc:4 --constraints mem=2G
+                                         (starts a new lxc container on machine 4
+                                          limited to 2GB RAM)
    juju add-machine --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju add-machine ssh:user@10.10.0.3   (manually provisions a machine with ssh)
 
@@ -0,0 +1,83 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/network"
+)
+
+const addNetworkDoc = `
+Add a provider network to the environment, giving it a juju name so that
+services can be bound to it with "juju deploy --networks". The network is
+identified by its provider-specific id, and may optionally have a CIDR and
+a VLAN tag.
+
+Examples:
+   juju add-network db provider-net-42 --cidr 10.0.42.0/24
+   juju add-network storage provider-net-43 --vlan-tag 43
+
+See Also:
+   juju list-networks
+   juju help deploy
+`
+
+// AddNetworkCommand adds a provider network to the environment.
+type AddNetworkCommand struct {
+	envcmd.EnvCommandBase
+	Name       string
+	ProviderId network.Id
+	CIDR       string
+	VLANTag    int
+}
+
+func (c *AddNetworkCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-network",
+		Args:    "<name> <provider-id>",
+		Purpose: "add a provider network to the environment",
+		Doc:     addNetworkDoc,
+	}
+}
+
+func (c *AddNetworkCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.CIDR, "cidr", "", "the CIDR of the network")
+	f.IntVar(&c.VLANTag, "vlan-tag", 0, "the VLAN tag of the network (0 for no VLAN)")
+}
+
+func (c *AddNetworkCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no network name specified")
+	case 1:
+		return errors.New("no provider id specified")
+	}
+	if !names.IsValidNetwork(args[0]) {
+		return errors.Errorf("invalid network name %q", args[0])
+	}
+	c.Name = args[0]
+	c.ProviderId = network.Id(args[1])
+	if c.VLANTag < 0 || c.VLANTag > 4094 {
+		return errors.Errorf("invalid VLAN tag %d: must be between 0 and 4094", c.VLANTag)
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+func (c *AddNetworkCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.AddNetwork(c.Name, c.ProviderId, c.CIDR, c.VLANTag); err != nil {
+		return err
+	}
+	ctx.Infof("added network %q", c.Name)
+	return nil
+}
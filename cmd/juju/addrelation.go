@@ -4,17 +4,40 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 )
 
+const addRelationDoc = `
+Add a relation between two services. When the named services could be
+related in more than one way, the possible relations are listed and one
+of them may be chosen; alternatively, give the relation names explicitly.
+
+The --via flag records the network that the relation's traffic should
+use. Units then publish their address in that network as their
+private-address setting in the relation.
+
+Examples:
+
+    juju add-relation wordpress mysql
+    juju add-relation wordpress:db mysql:server
+    juju add-relation --via backend wordpress mysql
+`
+
 // AddRelationCommand adds a relation between two service endpoints.
 type AddRelationCommand struct {
 	envcmd.EnvCommandBase
 	Endpoints []string
+	Network   string
 }
 
 func (c *AddRelationCommand) Info() *cmd.Info {
@@ -22,23 +45,65 @@ func (c *AddRelationCommand) Info() *cmd.Info {
 		Name:    "add-relation",
 		Args:    "<service1>[:<relation name1>] <service2>[:<relation name2>]",
 		Purpose: "add a relation between two services",
+		Doc:     addRelationDoc,
 	}
 }
 
+func (c *AddRelationCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Network, "via", "", "network the relation's traffic should use")
+}
+
 func (c *AddRelationCommand) Init(args []string) error {
 	if len(args) != 2 {
 		return fmt.Errorf("a relation must involve two services")
 	}
+	if c.Network != "" && !names.IsValidNetwork(c.Network) {
+		return fmt.Errorf("invalid network name %q", c.Network)
+	}
 	c.Endpoints = args
 	return nil
 }
 
-func (c *AddRelationCommand) Run(_ *cmd.Context) error {
+func (c *AddRelationCommand) Run(ctx *cmd.Context) error {
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
 	}
 	defer client.Close()
-	_, err = client.AddRelation(c.Endpoints...)
+	result, err := client.AddRelationVia(c.Network, c.Endpoints...)
+	if err != nil || len(result.Candidates) == 0 {
+		return err
+	}
+	endpoints, err := chooseRelation(ctx, c.Endpoints, result.Candidates)
+	if err != nil {
+		return err
+	}
+	_, err = client.AddRelationVia(c.Network, endpoints...)
 	return err
 }
+
+// chooseRelation lists the candidate relations that the given
+// endpoints could refer to, and returns the endpoints of the one
+// chosen by the user.
+func chooseRelation(ctx *cmd.Context, endpoints []string, candidates [][]string) ([]string, error) {
+	fmt.Fprintf(ctx.Stdout, "%q could refer to more than one relation:\n", strings.Join(endpoints, " "))
+	for i, cand := range candidates {
+		fmt.Fprintf(ctx.Stdout, "  %d) %s\n", i+1, strings.Join(cand, " "))
+	}
+	fmt.Fprintf(ctx.Stdout, "Choose a relation [1-%d]: ", len(candidates))
+	scanner := bufio.NewScanner(ctx.Stdin)
+	scanner.Scan()
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil || n < 1 || n > len(candidates) {
+		keys := make([]string, len(candidates))
+		for i, cand := range candidates {
+			keys[i] = fmt.Sprintf("%q", strings.Join(cand, " "))
+		}
+		return nil, fmt.Errorf("ambiguous relation: %q could refer to %s",
+			strings.Join(endpoints, " "), strings.Join(keys, "; "))
+	}
+	return candidates[n-1], nil
+}
@@ -4,11 +4,15 @@
 package main
 
 import (
+	"strings"
+
+	"github.com/juju/cmd"
 	gc "gopkg.in/check.v1"
 	charmtesting "gopkg.in/juju/charm.v4/testing"
 
 	"github.com/juju/juju/cmd/envcmd"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
 
@@ -151,3 +155,63 @@ func (s *AddRelationSuite) TestAddRelation(c *gc.C) {
 		}
 	}
 }
+
+func runAddRelationWithInput(c *gc.C, input string, args ...string) (*cmd.Context, error) {
+	com := envcmd.Wrap(&AddRelationCommand{})
+	if err := testing.InitCommand(com, args); err != nil {
+		return nil, err
+	}
+	ctx := testing.Context(c)
+	ctx.Stdin = strings.NewReader(input)
+	return ctx, com.Run(ctx)
+}
+
+func (s *AddRelationSuite) deployAmbiguous(c *gc.C) {
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "wordpress")
+	err := runDeploy(c, "local:wordpress", "wp")
+	c.Assert(err, gc.IsNil)
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "mysql-alternative")
+	err = runDeploy(c, "local:mysql-alternative", "ms")
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *AddRelationSuite) TestAddRelationChooseCandidate(c *gc.C) {
+	s.deployAmbiguous(c)
+	ctx, err := runAddRelationWithInput(c, "2\n", "wp", "ms")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `"wp ms" could refer to more than one relation:
+  1) wp:db ms:dev
+  2) wp:db ms:prod
+Choose a relation [1-2]: `)
+	_, err = s.State.KeyRelation("wp:db ms:prod")
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *AddRelationSuite) TestAddRelationNoCandidateChosen(c *gc.C) {
+	s.deployAmbiguous(c)
+	_, err := runAddRelationWithInput(c, "", "wp", "ms")
+	c.Assert(err, gc.ErrorMatches, `ambiguous relation: "wp ms" could refer to "wp:db ms:dev"; "wp:db ms:prod"`)
+	rels, err := s.State.AllRelations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(rels, gc.HasLen, 0)
+}
+
+func (s *AddRelationSuite) TestAddRelationVia(c *gc.C) {
+	s.deployAmbiguous(c)
+	_, err := s.State.AddNetwork(state.NetworkInfo{
+		Name:       "backend",
+		ProviderId: "backend-id",
+		CIDR:       "10.1.0.0/16",
+	})
+	c.Assert(err, gc.IsNil)
+	err = runAddRelation(c, "--via", "backend", "wp", "ms:dev")
+	c.Assert(err, gc.IsNil)
+	rel, err := s.State.KeyRelation("wp:db ms:dev")
+	c.Assert(err, gc.IsNil)
+	c.Assert(rel.Network(), gc.Equals, "backend")
+}
+
+func (s *AddRelationSuite) TestAddRelationInvalidNetwork(c *gc.C) {
+	err := runAddRelation(c, "--via", "bad network", "wp", "ms")
+	c.Assert(err, gc.ErrorMatches, `invalid network name "bad network"`)
+}
@@ -6,15 +6,14 @@ package main
 import (
 	"errors"
 	"fmt"
-	"regexp"
 
 	"github.com/juju/cmd"
-	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider"
 )
 
@@ -27,7 +26,7 @@ type UnitCommandBase struct {
 
 func (c *UnitCommandBase) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumUnits, "num-units", 1, "")
-	f.StringVar(&c.ToMachineSpec, "to", "", "the machine or container to deploy the unit in, bypasses constraints")
+	f.StringVar(&c.ToMachineSpec, "to", "", "the machine, container or placement directive to deploy the unit to, bypasses constraints")
 }
 
 func (c *UnitCommandBase) Init(args []string) error {
@@ -38,10 +37,9 @@ func (c *UnitCommandBase) Init(args []string) error {
 		if c.NumUnits > 1 {
 			return errors.New("cannot use --num-units > 1 with --to")
 		}
-		if !isMachineOrNewContainer(c.ToMachineSpec) {
+		if !isValidPlacement(c.ToMachineSpec) {
 			return fmt.Errorf("invalid --to parameter %q", c.ToMachineSpec)
 		}
-
 	}
 	return nil
 }
@@ -81,13 +79,17 @@ have already been deployed via juju deploy.
 
 By default, services are deployed to newly provisioned machines.  Alternatively,
 service units can be added to a specific existing machine using the --to
-argument.
+argument. The --to argument also accepts a placement directive for a new
+machine, such as an availability zone or a MAAS host name, which is passed
+to the environment's provider.
 
 Examples:
- juju add-unit mysql -n 5          (Add 5 mysql units on 5 new machines)
- juju add-unit mysql --to 23       (Add a mysql unit to machine 23)
- juju add-unit mysql --to 24/lxc/3 (Add unit to lxc container 3 on host machine 24)
- juju add-unit mysql --to lxc:25   (Add unit to a new lxc container on host machine 25)
+ juju add-unit mysql -n 5                 (Add 5 mysql units on 5 new machines)
+ juju add-unit mysql --to 23              (Add a mysql unit to machine 23)
+ juju add-unit mysql --to 24/lxc/3        (Add unit to lxc container 3 on host machine 24)
+ juju add-unit mysql --to lxc:25          (Add unit to a new lxc container on host machine 25)
+ juju add-unit mysql --to zone=us-east-1b (Add unit to a new machine in zone us-east-1b)
+ juju add-unit mysql --to host.maas       (Add unit to the MAAS node host.maas)
 `
 
 func (c *AddUnitCommand) Info() *cmd.Info {
@@ -139,16 +141,10 @@ func (c *AddUnitCommand) Run(_ *cmd.Context) error {
 	return err
 }
 
-const (
-	deployTarget = "^(" + names.ContainerTypeSnippet + ":)?" + names.MachineSnippet + "$"
-)
-
-var (
-	validMachineOrNewContainer = regexp.MustCompile(deployTarget)
-)
-
-// isMachineOrNewContainer returns whether spec is a valid machine id
-// or new container definition.
-func isMachineOrNewContainer(spec string) bool {
-	return validMachineOrNewContainer.MatchString(spec)
+// isValidPlacement returns whether spec is a valid unit placement
+// directive: a machine id, a new container definition, or a directive
+// for the provider to place a new machine.
+func isValidPlacement(spec string) bool {
+	_, err := instance.ParseUnitPlacement(spec)
+	return err == nil
 }
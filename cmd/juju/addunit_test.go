@@ -134,6 +134,25 @@ func (s *AddUnitSuite) TestForceMachineNewContainer(c *gc.C) {
 	s.assertForceMachine(c, svc, 3, 2, machine.Id())
 }
 
+func (s *AddUnitSuite) TestForceMachineProviderPlacement(c *gc.C) {
+	curl := s.setupService(c)
+
+	// The dummy provider accepts only the "valid" placement directive.
+	err := runAddUnit(c, "some-service-name", "--to", "dummyenv:valid")
+	c.Assert(err, gc.IsNil)
+	svc, _ := s.AssertService(c, "some-service-name", curl, 2, 0)
+	units, err := svc.AllUnits()
+	c.Assert(err, gc.IsNil)
+	mid, err := units[1].AssignedMachineId()
+	c.Assert(err, gc.IsNil)
+	machine, err := s.State.Machine(mid)
+	c.Assert(err, gc.IsNil)
+	c.Assert(machine.Placement(), gc.Equals, "valid")
+
+	err = runAddUnit(c, "some-service-name", "--to", "zone=us-east-1b")
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "some-service-name/2" to machine: .*zone=us-east-1b placement is invalid`)
+}
+
 func (s *AddUnitSuite) TestNonLocalCannotHostUnits(c *gc.C) {
 	err := runAddUnit(c, "some-service-name", "--to", "0")
 	c.Assert(err, gc.Not(gc.ErrorMatches), "machine 0 is the state server for a local environment and cannot host units")
@@ -172,7 +191,7 @@ var _ = gc.Suite(&namesSuite{})
 func (*namesSuite) TestNameChecks(c *gc.C) {
 	assertMachineOrNewContainer := func(s string, expect bool) {
 		c.Logf("%s -> %v", s, expect)
-		c.Assert(isMachineOrNewContainer(s), gc.Equals, expect)
+		c.Assert(isValidPlacement(s), gc.Equals, expect)
 	}
 	assertMachineOrNewContainer("0", true)
 	assertMachineOrNewContainer("00", false)
@@ -189,4 +208,8 @@ func (*namesSuite) TestNameChecks(c *gc.C) {
 	assertMachineOrNewContainer("0/lxc/01", false)
 	assertMachineOrNewContainer("0/lxc/10", true)
 	assertMachineOrNewContainer("0/kvm/4", true)
+	assertMachineOrNewContainer("lxc", true)
+	assertMachineOrNewContainer("zone=us-east-1b", true)
+	assertMachineOrNewContainer("host.maas", true)
+	assertMachineOrNewContainer("bigglesplop", false)
 }
@@ -9,10 +9,12 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api/backups"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/utils/anonymize"
 )
 
 var backupsDoc = `
@@ -62,6 +64,13 @@ type APIClient interface {
 // CommandBase is the base type for backups sub-commands.
 type CommandBase struct {
 	envcmd.EnvCommandBase
+	// Anonymize indicates that identifying information should be
+	// removed from the printed metadata.
+	Anonymize bool
+
+	// anonymizer replaces identifying information with the same
+	// placeholders throughout the command's output.
+	anonymizer *anonymize.Anonymizer
 }
 
 // NewAPIClient returns a client for the backups api endpoint.
@@ -77,8 +86,32 @@ var newAPIClient = func(c *CommandBase) (APIClient, error) {
 	return backups.NewClient(root), nil
 }
 
-// dumpMetadata writes the formatted backup metadata to stdout.
-func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetadataResult) {
+// addAnonymizeFlag adds the --anonymize flag to commands that print
+// backup metadata.
+func (c *CommandBase) addAnonymizeFlag(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Anonymize, "anonymize", false, "replace host names, environment IDs and notes with placeholders")
+}
+
+// dumpMetadata writes the formatted backup metadata to stdout. If
+// --anonymize was given, the values that identify the environment
+// are replaced with placeholders, which are the same for the same
+// values throughout the command's output, so that the output may be
+// attached to a public bug report.
+func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetadataResult) error {
+	if c.Anonymize {
+		if c.anonymizer == nil {
+			anonymizer, err := anonymize.New()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			c.anonymizer = anonymizer
+		}
+		anonymized := *result
+		anonymized.Notes = c.anonymizer.Identifier(result.Notes)
+		anonymized.Environment = c.anonymizer.Identifier(result.Environment)
+		anonymized.Hostname = c.anonymizer.Identifier(result.Hostname)
+		result = &anonymized
+	}
 	fmt.Fprintf(ctx.Stdout, "backup ID:       %q\n", result.ID)
 	fmt.Fprintf(ctx.Stdout, "started:         %v\n", result.Started)
 	fmt.Fprintf(ctx.Stdout, "finished:        %v\n", result.Finished)
@@ -92,4 +125,5 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "machine ID:      %q\n", result.Machine)
 	fmt.Fprintf(ctx.Stdout, "created on host: %q\n", result.Hostname)
 	fmt.Fprintf(ctx.Stdout, "juju version:    %v\n", result.Version)
+	return nil
 }
@@ -41,6 +41,7 @@ func (c *CreateCommand) Info() *cmd.Info {
 // SetFlags implements Command.SetFlags.
 func (c *CreateCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Quiet, "quiet", false, "do not print the metadata")
+	c.addAnonymizeFlag(f)
 }
 
 // Init implements Command.Init.
@@ -67,7 +68,9 @@ func (c *CreateCommand) Run(ctx *cmd.Context) error {
 	}
 
 	if !c.Quiet {
-		c.dumpMetadata(ctx, result)
+		if err := c.dumpMetadata(ctx, result); err != nil {
+			return err
+		}
 	}
 
 	fmt.Fprintln(ctx.Stdout, result.ID)
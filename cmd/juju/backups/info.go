@@ -6,10 +6,13 @@ package backups
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
 )
 
 const infoDoc = `
-"info" provides the metadata associated with a backup.
+"info" provides the metadata associated with a backup.  With --anonymize,
+the host name, environment ID and notes are replaced with placeholders so
+that the output can be attached to a public bug report.
 `
 
 // InfoCommand is the sub-command for creating a new backup.
@@ -29,6 +32,11 @@ func (c *InfoCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *InfoCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.addAnonymizeFlag(f)
+}
+
 // Init implements Command.Init.
 func (c *InfoCommand) Init(args []string) error {
 	if len(args) == 0 {
@@ -55,6 +63,5 @@ func (c *InfoCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
-	c.dumpMetadata(ctx, result)
-	return nil
+	return c.dumpMetadata(ctx, result)
 }
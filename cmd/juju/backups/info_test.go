@@ -57,3 +57,28 @@ func (s *infoSuite) TestError(c *gc.C) {
 
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *infoSuite) TestAnonymize(c *gc.C) {
+	s.metaresult.Notes = "my notes"
+	s.metaresult.Environment = "some-env-uuid"
+	s.metaresult.Hostname = "juju-host-1"
+	s.metaresult.Machine = "0"
+	s.setSuccess()
+	ctx, err := testing.RunCommand(c, s.command, "info", "--anonymize", "spam")
+	c.Assert(err, gc.IsNil)
+
+	out := testing.Stdout(ctx)
+	c.Check(out, gc.Matches, `(?s).*notes:           "anon-[0-9a-f]{8}"\n.*`)
+	c.Check(out, gc.Matches, `(?s).*environment ID:  "anon-[0-9a-f]{8}"\n.*`)
+	c.Check(out, gc.Matches, `(?s).*created on host: "anon-[0-9a-f]{8}"\n.*`)
+	c.Check(out, gc.Matches, `(?s).*machine ID:      "0"\n.*`)
+	for _, secret := range []string{"my notes", "some-env-uuid", "juju-host-1"} {
+		c.Check(strings.Contains(out, secret), gc.Equals, false)
+	}
+
+	// Each run uses a new salt, so the placeholders cannot be
+	// correlated across outputs.
+	ctx, err = testing.RunCommand(c, s.command, "info", "--anonymize", "spam")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Not(gc.Equals), out)
+}
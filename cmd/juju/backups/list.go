@@ -35,6 +35,7 @@ func (c *ListCommand) Info() *cmd.Info {
 // SetFlags implements Command.SetFlags.
 func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Brief, "brief", false, "only print IDs")
+	c.addAnonymizeFlag(f)
 }
 
 // Init implements Command.Init.
@@ -65,15 +66,17 @@ func (c *ListCommand) Run(ctx *cmd.Context) error {
 
 	if c.Brief {
 		fmt.Fprintln(ctx.Stdout, result.List[0].ID)
-	} else {
-		c.dumpMetadata(ctx, &result.List[0])
+	} else if err := c.dumpMetadata(ctx, &result.List[0]); err != nil {
+		return err
 	}
 	for _, resultItem := range result.List[1:] {
 		if c.Brief {
 			fmt.Fprintln(ctx.Stdout, resultItem.ID)
 		} else {
 			fmt.Fprintln(ctx.Stdout)
-			c.dumpMetadata(ctx, &resultItem)
+			if err := c.dumpMetadata(ctx, &resultItem); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -0,0 +1,95 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/downloader"
+	"github.com/juju/juju/environs/config"
+)
+
+// charmArchiveHostnameVerification determines whether the certificate
+// of the server a charm archive is downloaded from is verified. It is
+// a variable so that tests can use a self-signed server.
+var charmArchiveHostnameVerification = utils.VerifySSLHostnames
+
+// isCharmArchiveURL reports whether the given charm name refers to a
+// charm archive to be downloaded over HTTPS, rather than to a charm URL.
+func isCharmArchiveURL(name string) bool {
+	return strings.HasPrefix(name, "https://")
+}
+
+// addCharmArchiveViaAPI downloads the charm archive at the given URL,
+// checks it against the expected SHA256 digest, and uploads it to the
+// environment as a local charm for the environment's default series.
+// The charm URL of the added charm is displayed on stdout.
+func addCharmArchiveViaAPI(client *api.Client, ctx *cmd.Context, archiveURL, digest string, conf *config.Config) (*charm.URL, error) {
+	series, ok := conf.DefaultSeries()
+	if !ok {
+		return nil, errors.New("cannot deploy a charm archive by URL: default-series is not set in the environment")
+	}
+	ch, err := downloadCharmArchive(archiveURL, digest)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(ch.Path)
+
+	curl := &charm.URL{
+		Schema:   "local",
+		Name:     ch.Meta().Name,
+		Revision: ch.Revision(),
+		Series:   series,
+	}
+	stateCurl, err := client.AddLocalCharm(curl, ch)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Infof("Added charm %q to the environment.", stateCurl)
+	return stateCurl, nil
+}
+
+// downloadCharmArchive downloads the charm archive at the given URL to
+// a temporary file and verifies that its SHA256 digest matches the
+// expected one. The caller is responsible for removing the file at the
+// returned archive's Path.
+func downloadCharmArchive(archiveURL, digest string) (_ *charm.CharmArchive, err error) {
+	dl := downloader.New(archiveURL, "", charmArchiveHostnameVerification)
+	defer dl.Stop()
+	status := <-dl.Done()
+	if status.Err != nil {
+		return nil, status.Err
+	}
+	file := status.File
+	defer file.Close()
+	defer func() {
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, errors.Annotatef(err, "cannot read charm archive from %q", archiveURL)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(actual, digest) {
+		return nil, fmt.Errorf("charm archive from %q has SHA256 digest %s, expected %s", archiveURL, actual, digest)
+	}
+	ch, err := charm.ReadCharmArchive(file.Name())
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid charm archive from %q", archiveURL)
+	}
+	return ch, nil
+}
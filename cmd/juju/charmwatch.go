@@ -0,0 +1,128 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/api"
+)
+
+// localCharmPollInterval is how often a watched local charm is checked
+// for changes.
+var localCharmPollInterval = 2 * time.Second
+
+// localCharmWatcher upgrades a service whenever the source of its
+// local charm changes on disk.
+type localCharmWatcher struct {
+	client      *api.Client
+	repo        charm.Repository
+	curl        *charm.URL
+	serviceName string
+}
+
+// charmPath returns the path of the charm directory or archive that the
+// repository currently holds for the watched charm.
+func (w *localCharmWatcher) charmPath() (string, charm.Charm, error) {
+	ch, err := w.repo.Get(w.curl.WithRevision(-1))
+	if err != nil {
+		return "", nil, err
+	}
+	switch ch := ch.(type) {
+	case *charm.CharmDir:
+		return ch.Path, ch, nil
+	case *charm.CharmArchive:
+		return ch.Path, ch, nil
+	}
+	return "", nil, errors.Errorf("cannot watch charm %q: not a local charm", w.curl)
+}
+
+// run polls the watched charm until stop is signalled, upgrading the
+// service each time the charm's contents change.
+func (w *localCharmWatcher) run(ctx *cmd.Context, stop <-chan os.Signal) error {
+	path, _, err := w.charmPath()
+	if err != nil {
+		return err
+	}
+	last, err := fingerprintPath(path)
+	if err != nil {
+		return err
+	}
+	ctx.Infof("Watching %s for changes; interrupt to stop.", path)
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(localCharmPollInterval):
+		}
+		path, ch, err := w.charmPath()
+		if err != nil {
+			logger.Warningf("cannot read charm: %v", err)
+			continue
+		}
+		current, err := fingerprintPath(path)
+		if err != nil {
+			logger.Warningf("cannot read charm: %v", err)
+			continue
+		}
+		if current == last {
+			continue
+		}
+		last = current
+		if err := w.upgrade(ctx, ch); err != nil {
+			// A charm being edited may be temporarily broken, so
+			// report the failure and keep watching.
+			ctx.Infof("Cannot upgrade %q: %v", w.serviceName, err)
+		}
+	}
+}
+
+// upgrade adds ch to the environment and switches the service to it.
+// The state server gives the charm a new revision if needed.
+func (w *localCharmWatcher) upgrade(ctx *cmd.Context, ch charm.Charm) error {
+	curl, err := w.client.AddLocalCharm(w.curl.WithRevision(ch.Revision()), ch)
+	if err != nil {
+		return err
+	}
+	if err := w.client.ServiceSetCharm(w.serviceName, curl.String(), false); err != nil {
+		return err
+	}
+	ctx.Infof("Upgraded %q to charm %q.", w.serviceName, curl)
+	w.curl = curl
+	return nil
+}
+
+// fingerprintPath returns a digest of the names, sizes and modification
+// times of the file or directory tree at path. Hidden files and
+// directories, such as VCS metadata and editor swap files, are ignored.
+func fingerprintPath(path string) (string, error) {
+	hash := sha256.New()
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != path && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		fmt.Fprintf(hash, "%s %d %d %v\n", p, info.Size(), info.ModTime().UnixNano(), info.Mode())
+		return nil
+	})
+	if err != nil {
+		return "", errors.Annotate(err, "cannot read charm")
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
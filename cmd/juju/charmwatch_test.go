@@ -0,0 +1,95 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	charmtesting "gopkg.in/juju/charm.v4/testing"
+
+	"github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type LocalCharmWatcherSuite struct {
+	testing.RepoSuite
+}
+
+var _ = gc.Suite(&LocalCharmWatcherSuite{})
+
+func (s *LocalCharmWatcherSuite) TestFingerprintPath(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte("name: foo"), 0644)
+	c.Assert(err, gc.IsNil)
+	first, err := fingerprintPath(dir)
+	c.Assert(err, gc.IsNil)
+
+	// Hidden files are ignored.
+	err = ioutil.WriteFile(filepath.Join(dir, ".metadata.yaml.swp"), []byte("junk"), 0644)
+	c.Assert(err, gc.IsNil)
+	err = os.Mkdir(filepath.Join(dir, ".bzr"), 0755)
+	c.Assert(err, gc.IsNil)
+	fingerprint, err := fingerprintPath(dir)
+	c.Assert(err, gc.IsNil)
+	c.Assert(fingerprint, gc.Equals, first)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte("name: foobar"), 0644)
+	c.Assert(err, gc.IsNil)
+	fingerprint, err = fingerprintPath(dir)
+	c.Assert(err, gc.IsNil)
+	c.Assert(fingerprint, gc.Not(gc.Equals), first)
+}
+
+func (s *LocalCharmWatcherSuite) TestUpgradeOnChange(c *gc.C) {
+	s.PatchValue(&localCharmPollInterval, 10*time.Millisecond)
+	dirPath := charmtesting.Charms.ClonedDirPath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy")
+	c.Assert(err, gc.IsNil)
+	curl := charm.MustParseURL("local:trusty/dummy-1")
+	s.AssertService(c, "dummy", curl, 1, 0)
+
+	client := s.APIState.Client()
+	repo, err := charm.InferRepository(curl.Reference(), filepath.Dir(s.SeriesPath))
+	c.Assert(err, gc.IsNil)
+	w := &localCharmWatcher{
+		client:      client,
+		repo:        repo,
+		curl:        curl,
+		serviceName: "dummy",
+	}
+	stop := make(chan os.Signal)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.run(coretesting.Context(c), stop)
+	}()
+
+	err = ioutil.WriteFile(filepath.Join(dirPath, "README"), []byte("changed"), 0644)
+	c.Assert(err, gc.IsNil)
+	service, err := s.State.Service("dummy")
+	c.Assert(err, gc.IsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := service.Refresh()
+		c.Assert(err, gc.IsNil)
+		if chURL, _ := service.CharmURL(); chURL.Revision > 1 {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("service not upgraded")
+		}
+	}
+	s.AssertService(c, "dummy", charm.MustParseURL("local:trusty/dummy-2"), 1, 0)
+
+	close(stop)
+	select {
+	case err := <-done:
+		c.Assert(err, gc.IsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("watcher did not stop")
+	}
+}
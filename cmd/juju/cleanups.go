@@ -0,0 +1,147 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/cleanups"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const listCleanupsDoc = `
+List the cleanups that the environment has scheduled but not yet
+completed, such as the removal of the units of a destroyed service.
+Cleanups normally run in the background shortly after they are
+scheduled; a cleanup that remains listed for a long time, or that
+reports failures, indicates that removal has stalled.
+
+With --run, all pending cleanups are run immediately before listing
+whatever remains.
+`
+
+// ListCleanupsCommand lists the pending state cleanups.
+type ListCleanupsCommand struct {
+	envcmd.EnvCommandBase
+	out      cmd.Output
+	RunFirst bool
+}
+
+func (c *ListCleanupsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-cleanups",
+		Purpose: "list pending environment cleanups",
+		Doc:     listCleanupsDoc,
+	}
+}
+
+func (c *ListCleanupsCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.RunFirst, "run", false, "run all pending cleanups before listing")
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *ListCleanupsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// cleanupInfo holds the formatted details of a pending cleanup.
+type cleanupInfo struct {
+	Id        string `yaml:"id" json:"id"`
+	Kind      string `yaml:"kind" json:"kind"`
+	Prefix    string `yaml:"prefix" json:"prefix"`
+	Age       string `yaml:"age" json:"age"`
+	Failures  int    `yaml:"failures,omitempty" json:"failures,omitempty"`
+	LastError string `yaml:"last-error,omitempty" json:"last-error,omitempty"`
+}
+
+func formatCleanups(infos []params.CleanupInfo, now time.Time) []cleanupInfo {
+	result := make([]cleanupInfo, len(infos))
+	for i, info := range infos {
+		age := now.Sub(info.Created) / time.Second * time.Second
+		result[i] = cleanupInfo{
+			Id:        info.Id,
+			Kind:      info.Kind,
+			Prefix:    info.Prefix,
+			Age:       age.String(),
+			Failures:  info.Failures,
+			LastError: info.LastError,
+		}
+	}
+	return result
+}
+
+func (c *ListCleanupsCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	client := cleanups.NewClient(root)
+	defer client.Close()
+
+	if c.RunFirst {
+		if err := client.Run(); err != nil {
+			return err
+		}
+	}
+	infos, err := client.List()
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatCleanups(infos, time.Now()))
+}
+
+const cancelCleanupDoc = `
+Cancel pending cleanups, as listed by "juju list-cleanups", without
+running them. This is a last resort for cleanups that keep failing;
+any documents they would have removed are left in place.
+`
+
+// CancelCleanupCommand cancels pending state cleanups.
+type CancelCleanupCommand struct {
+	envcmd.EnvCommandBase
+	Ids []string
+}
+
+func (c *CancelCleanupCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cancel-cleanup",
+		Args:    "<cleanup id> [...]",
+		Purpose: "cancel pending environment cleanups",
+		Doc:     cancelCleanupDoc,
+	}
+}
+
+func (c *CancelCleanupCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no cleanup id specified")
+	}
+	c.Ids = args
+	return nil
+}
+
+func (c *CancelCleanupCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	client := cleanups.NewClient(root)
+	defer client.Close()
+
+	var failed bool
+	for _, id := range c.Ids {
+		if err := client.Cancel(id); err != nil {
+			fmt.Fprintf(ctx.Stderr, "cannot cancel cleanup %q: %v\n", id, err)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
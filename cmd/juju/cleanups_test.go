@@ -0,0 +1,87 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing"
+)
+
+type cleanupsSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&cleanupsSuite{})
+
+func (s *cleanupsSuite) destroyServiceWithUnit(c *gc.C) {
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = svc.Destroy()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *cleanupsSuite) TestListCleanups(c *gc.C) {
+	s.destroyServiceWithUnit(c)
+	context, err := testing.RunCommand(c, envcmd.Wrap(&ListCleanupsCommand{}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Matches, ""+
+		"- id: [0-9a-f]{24}\n"+
+		"  kind: units\n"+
+		"  prefix: wordpress\n"+
+		"  age: .*\n")
+}
+
+func (s *cleanupsSuite) TestListCleanupsRun(c *gc.C) {
+	s.destroyServiceWithUnit(c)
+	context, err := testing.RunCommand(c, envcmd.Wrap(&ListCleanupsCommand{}), "--run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "[]\n")
+}
+
+func (s *cleanupsSuite) TestFormatCleanups(c *gc.C) {
+	now := time.Date(2014, 10, 1, 12, 0, 0, 0, time.UTC)
+	infos := []params.CleanupInfo{{
+		Id:        "543a3b1f2d3b2a5f3c000001",
+		Kind:      "units",
+		Prefix:    "wordpress",
+		Created:   now.Add(-90*time.Second - 300*time.Millisecond),
+		Failures:  2,
+		LastError: "boom",
+	}}
+	c.Assert(formatCleanups(infos, now), gc.DeepEquals, []cleanupInfo{{
+		Id:        "543a3b1f2d3b2a5f3c000001",
+		Kind:      "units",
+		Prefix:    "wordpress",
+		Age:       "1m30s",
+		Failures:  2,
+		LastError: "boom",
+	}})
+}
+
+func (s *cleanupsSuite) TestCancelCleanupInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&CancelCleanupCommand{}), nil)
+	c.Assert(err, gc.ErrorMatches, "no cleanup id specified")
+}
+
+func (s *cleanupsSuite) TestCancelCleanup(c *gc.C) {
+	s.destroyServiceWithUnit(c)
+	infos, err := s.State.Cleanups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(infos, gc.HasLen, 1)
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&CancelCleanupCommand{}), infos[0].Id, "bad")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(context), gc.Equals, `cannot cancel cleanup "bad": invalid cleanup id "bad"`+"\n")
+	infos, err = s.State.Cleanups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(infos, gc.HasLen, 0)
+}
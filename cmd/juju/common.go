@@ -11,6 +11,8 @@ import (
 	"gopkg.in/juju/charm.v4"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/charms"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
@@ -99,28 +101,55 @@ func environFromNameProductionFunc(
 }
 
 // resolveCharmURL returns a resolved charm URL, given a charm location string.
-// If the series is not resolved, the environment default-series is used, or if
-// not set, the series is resolved with the state server.
-func resolveCharmURL(url string, client *api.Client, conf *config.Config) (*charm.URL, error) {
+// Charm store charms are resolved by the state server, which prefers the
+// environment default-series when the series is not specified and falls back
+// to the series preferred by the charm store. Local charms use the
+// default-series if the series is not specified.
+func resolveCharmURL(url string, root *api.State, conf *config.Config) (*charm.URL, error) {
 	ref, err := charm.ParseReference(url)
 	if err != nil {
 		return nil, err
 	}
+	defaultSeries, _ := conf.DefaultSeries()
+	if ref.Schema == "cs" {
+		curl, err := charms.NewClient(root).Resolve(ref, defaultSeries)
+		if !params.IsCodeNotImplemented(err) {
+			return curl, err
+		}
+		// The state server is too old to support the Charms facade.
+		logger.Debugf("Charms.Resolve not supported by the API server")
+	}
 	// If series is not set, use configured default series
 	if ref.Series == "" {
-		if defaultSeries, ok := conf.DefaultSeries(); ok {
-			ref.Series = defaultSeries
-		}
+		ref.Series = defaultSeries
 	}
 	if ref.Series != "" {
 		return ref.URL("")
 	}
 	// Otherwise, look up the best supported series for this charm
 	if ref.Schema != "local" {
-		return client.ResolveCharm(ref)
+		return root.Client().ResolveCharm(ref)
 	}
 	possibleURL := *ref
 	possibleURL.Series = "precise"
 	logger.Errorf("The series is not specified in the environment (default-series) or with the charm. Did you mean:\n\t%s", &possibleURL)
 	return nil, fmt.Errorf("cannot resolve series for charm: %q", ref)
 }
+
+// latestCharmURL returns curl with its revision set to the latest
+// available. The latest revision of a charm store charm is looked up by
+// the state server where possible, so the client does not need access
+// to the charm store.
+func latestCharmURL(root *api.State, repo charm.Repository, curl *charm.URL) (*charm.URL, error) {
+	if curl.Schema == "cs" {
+		latest, err := charms.NewClient(root).Resolve(curl.Reference(), "")
+		if !params.IsCodeNotImplemented(err) {
+			return latest, err
+		}
+	}
+	revision, err := charm.Latest(repo, curl)
+	if err != nil {
+		return nil, err
+	}
+	return curl.WithRevision(revision), nil
+}
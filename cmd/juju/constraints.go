@@ -7,9 +7,12 @@ import (
 	"fmt"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/constraints"
 )
@@ -17,7 +20,14 @@ import (
 const getConstraintsDoc = `
 get-constraints returns a list of constraints that have been set on
 the environment using juju set-constraints.  You can also view constraints set
-for a specific service by using juju get-constraints <service>.
+for a specific service by using juju get-constraints <service>, or for a
+specific machine by using juju get-constraints <machine>.
+
+With --effective, the constraints that take effect are shown instead.  For a
+service, these are the service constraints merged with the environment
+constraints, as used when provisioning machines for new units.  Machine
+constraints were merged with the environment constraints when the machine was
+added, so they take effect as they are.
 
 See Also:
    juju help constraints
@@ -28,17 +38,27 @@ const setConstraintsDoc = `
 set-constraints sets machine constraints on the system, which are used as the
 default constraints for all new machines provisioned in the environment (unless
 overridden).  You can also set constraints on a specific service by using juju
-set-constraints <service>.
+set-constraints --service <service>, or on a machine that has not yet been
+provisioned by using juju set-constraints --machine <machine>.
+
+Constraints are merged in the following order, later values taking precedence
+over earlier ones:
 
-Constraints set on a service are combined with environment constraints for
-commands (such as juju deploy) that provision machines for services.  Where
-environment and service constraints overlap, the service constraints take
-precedence.
+   1. environment constraints
+   2. service constraints (set with juju deploy --constraints or with
+      set-constraints --service), for machines provisioned for the service's
+      units by commands such as juju deploy and juju add-unit
+   3. constraints given to juju add-machine --constraints
+
+The result is recorded as the machine's constraints when the machine is added,
+so later changes to environment or service constraints do not affect existing
+machines.  Use juju get-constraints --effective to see the merged result.
 
 Examples:
 
    set-constraints mem=8G                         (all new machines in the environment must have at least 8GB of RAM)
    set-constraints --service wordpress mem=4G     (all new wordpress machines can ignore the 8G constraint above, and require only 4G)
+   set-constraints --machine 3 cpu-cores=4        (unprovisioned machine 3 must have at least 4 cores)
 
 See Also:
    juju help constraints
@@ -48,18 +68,21 @@ See Also:
    juju help add-unit
 `
 
-// GetConstraintsCommand shows the constraints for a service or environment.
+// GetConstraintsCommand shows the constraints for a service, machine or
+// environment.
 type GetConstraintsCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	MachineId   string
+	Effective   bool
 	out         cmd.Output
 }
 
 func (c *GetConstraintsCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "get-constraints",
-		Args:    "[<service>]",
-		Purpose: "view constraints on the environment or a service",
+		Args:    "[<service> | <machine>]",
+		Purpose: "view constraints on the environment, a service or a machine",
 		Doc:     getConstraintsDoc,
 	}
 }
@@ -74,18 +97,40 @@ func (c *GetConstraintsCommand) SetFlags(f *gnuflag.FlagSet) {
 		"yaml":        cmd.FormatYaml,
 		"json":        cmd.FormatJson,
 	})
+	f.BoolVar(&c.Effective, "effective", false, "show the constraints that take effect")
 }
 
 func (c *GetConstraintsCommand) Init(args []string) error {
 	if len(args) > 0 {
-		if !names.IsValidService(args[0]) {
-			return fmt.Errorf("invalid service name %q", args[0])
+		switch {
+		case names.IsValidMachine(args[0]):
+			c.MachineId = args[0]
+		case names.IsValidService(args[0]):
+			c.ServiceName = args[0]
+		default:
+			return fmt.Errorf("invalid service name or machine id %q", args[0])
 		}
-		c.ServiceName, args = args[0], args[1:]
+		args = args[1:]
 	}
 	return cmd.CheckEmpty(args)
 }
 
+// constraintsTag returns the tag of the entity whose constraints are
+// managed: the given machine or service, or else the environment.
+func constraintsTag(client *api.Client, serviceName, machineId string) (string, error) {
+	switch {
+	case machineId != "":
+		return names.NewMachineTag(machineId).String(), nil
+	case serviceName != "":
+		return names.NewServiceTag(serviceName).String(), nil
+	}
+	info, err := client.EnvironmentInfo()
+	if err != nil {
+		return "", err
+	}
+	return names.NewEnvironTag(info.UUID).String(), nil
+}
+
 func (c *GetConstraintsCommand) Run(ctx *cmd.Context) error {
 	apiclient, err := c.NewAPIClient()
 	if err != nil {
@@ -93,22 +138,43 @@ func (c *GetConstraintsCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiclient.Close()
 
-	var cons constraints.Value
-	if c.ServiceName == "" {
-		cons, err = apiclient.GetEnvironmentConstraints()
-	} else {
-		cons, err = apiclient.GetServiceConstraints(c.ServiceName)
+	tag, err := constraintsTag(apiclient, c.ServiceName, c.MachineId)
+	if err != nil {
+		return err
+	}
+	cons, effective, err := apiclient.GetConstraints(tag)
+	if params.IsCodeNotImplemented(err) {
+		cons, effective, err = c.getConstraintsV0(apiclient)
 	}
 	if err != nil {
 		return err
 	}
+	if c.Effective {
+		return c.out.Write(ctx, effective)
+	}
 	return c.out.Write(ctx, cons)
 }
 
-// SetConstraintsCommand shows the constraints for a service or environment.
+// getConstraintsV0 gets environment or service constraints from an API
+// server that does not support GetConstraints.
+func (c *GetConstraintsCommand) getConstraintsV0(client *api.Client) (cons, effective constraints.Value, err error) {
+	if c.MachineId != "" || c.Effective {
+		return cons, effective, errors.New("this juju server does not support machine or effective constraints")
+	}
+	if c.ServiceName == "" {
+		cons, err = client.GetEnvironmentConstraints()
+	} else {
+		cons, err = client.GetServiceConstraints(c.ServiceName)
+	}
+	return cons, cons, err
+}
+
+// SetConstraintsCommand sets the constraints for a service, machine or
+// environment.
 type SetConstraintsCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	MachineId   string
 	Constraints constraints.Value
 }
 
@@ -116,7 +182,7 @@ func (c *SetConstraintsCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "set-constraints",
 		Args:    "[key=[value] ...]",
-		Purpose: "set constraints on the environment or a service",
+		Purpose: "set constraints on the environment, a service or a machine",
 		Doc:     setConstraintsDoc,
 	}
 }
@@ -124,12 +190,20 @@ func (c *SetConstraintsCommand) Info() *cmd.Info {
 func (c *SetConstraintsCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.ServiceName, "s", "", "set service constraints")
 	f.StringVar(&c.ServiceName, "service", "", "")
+	f.StringVar(&c.MachineId, "m", "", "set constraints of an unprovisioned machine")
+	f.StringVar(&c.MachineId, "machine", "", "")
 }
 
 func (c *SetConstraintsCommand) Init(args []string) (err error) {
+	if c.ServiceName != "" && c.MachineId != "" {
+		return fmt.Errorf("cannot set constraints on both a service and a machine")
+	}
 	if c.ServiceName != "" && !names.IsValidService(c.ServiceName) {
 		return fmt.Errorf("invalid service name %q", c.ServiceName)
 	}
+	if c.MachineId != "" && !names.IsValidMachine(c.MachineId) {
+		return fmt.Errorf("invalid machine id %q", c.MachineId)
+	}
 	c.Constraints, err = constraints.Parse(args...)
 	return err
 }
@@ -140,8 +214,20 @@ func (c *SetConstraintsCommand) Run(_ *cmd.Context) (err error) {
 		return err
 	}
 	defer apiclient.Close()
-	if c.ServiceName == "" {
-		return apiclient.SetEnvironmentConstraints(c.Constraints)
+	tag, err := constraintsTag(apiclient, c.ServiceName, c.MachineId)
+	if err != nil {
+		return err
+	}
+	err = apiclient.SetConstraints(tag, c.Constraints)
+	if !params.IsCodeNotImplemented(err) {
+		return err
+	}
+	// Fall back to the calls supported by older API servers.
+	switch {
+	case c.MachineId != "":
+		return errors.New("this juju server does not support setting machine constraints")
+	case c.ServiceName != "":
+		return apiclient.SetServiceConstraints(c.ServiceName, c.Constraints)
 	}
-	return apiclient.SetServiceConstraints(c.ServiceName, c.Constraints)
+	return apiclient.SetEnvironmentConstraints(c.Constraints)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -90,6 +91,22 @@ func (s *ConstraintsCommandsSuite) TestSetErrors(c *gc.C) {
 	assertSetError(c, 2, `malformed constraint "="`, "=")
 	assertSetError(c, 2, `malformed constraint "="`, "-s", "s", "=")
 	assertSetError(c, 1, `service "missing" not found`, "-s", "missing")
+	assertSetError(c, 2, `invalid machine id "badmachine"`, "-m", "badmachine")
+	assertSetError(c, 2, `cannot set constraints on both a service and a machine`, "-s", "svc", "-m", "0")
+	assertSetError(c, 1, `machine 42 not found`, "-m", "42")
+}
+
+func (s *ConstraintsCommandsSuite) TestSetMachine(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	assertSet(c, "-m", m.Id(), "mem=4G")
+	cons, err := m.Constraints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.Value{Mem: uint64p(4096)})
+
+	err = m.SetProvisioned("i-0", "fake_nonce", nil)
+	c.Assert(err, gc.IsNil)
+	assertSetError(c, 1, "cannot set constraints: machine is already provisioned", "--machine", m.Id(), "mem=8G")
 }
 
 func assertGet(c *gc.C, stdout string, args ...string) {
@@ -139,7 +156,28 @@ func assertGetError(c *gc.C, code int, stderr string, args ...string) {
 }
 
 func (s *ConstraintsCommandsSuite) TestGetErrors(c *gc.C) {
-	assertGetError(c, 2, `invalid service name "badname-0"`, "badname-0")
+	assertGetError(c, 2, `invalid service name or machine id "badname-0"`, "badname-0")
 	assertGetError(c, 2, `unrecognized args: \["blether"\]`, "goodname", "blether")
 	assertGetError(c, 1, `service "missing" not found`, "missing")
+	assertGetError(c, 1, `machine 42 not found`, "42")
+}
+
+func (s *ConstraintsCommandsSuite) TestGetServiceEffective(c *gc.C) {
+	err := s.State.SetEnvironConstraints(constraints.Value{CpuCores: uint64p(2), Mem: uint64p(4096)})
+	c.Assert(err, gc.IsNil)
+	svc := s.AddTestingService(c, "svc", s.AddTestingCharm(c, "dummy"))
+	err = svc.SetConstraints(constraints.Value{Mem: uint64p(8192)})
+	c.Assert(err, gc.IsNil)
+	assertGet(c, "mem=8192M\n", "svc")
+	assertGet(c, "cpu-cores=2 mem=8192M\n", "--effective", "svc")
+}
+
+func (s *ConstraintsCommandsSuite) TestGetMachineValues(c *gc.C) {
+	_, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.Value{CpuCores: uint64p(4)},
+	})
+	c.Assert(err, gc.IsNil)
+	assertGet(c, "cpu-cores=4\n", "0")
 }
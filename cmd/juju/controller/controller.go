@@ -0,0 +1,57 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/api/maintenance"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const controllerCommandDoc = `
+"juju controller" is used to inspect and maintain the state servers that
+run the environment, without logging in to their machines.
+`
+
+const controllerCommandPurpose = "inspect and maintain the state servers"
+
+// NewSuperCommand creates the controller supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	controllercmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "controller",
+		Doc:         controllerCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     controllerCommandPurpose,
+	})
+	controllercmd.Register(envcmd.Wrap(&DBCompactCommand{}))
+	controllercmd.Register(envcmd.Wrap(&DBStatusCommand{}))
+	return controllercmd
+}
+
+// MaintenanceAPI defines the API methods that the controller
+// subcommands use.
+type MaintenanceAPI interface {
+	DatabaseStatus() (params.DatabaseStatusResult, error)
+	CompactDatabase(repair bool) error
+	Close() error
+}
+
+// ControllerCommandBase is a helper base structure that has a method to
+// get the maintenance API.
+type ControllerCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+func (c *ControllerCommandBase) getMaintenanceAPI() (MaintenanceAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return maintenance.NewClient(root), nil
+}
+
+var getMaintenanceAPI = (*ControllerCommandBase).getMaintenanceAPI
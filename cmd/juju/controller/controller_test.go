@@ -0,0 +1,100 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"os"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/testing"
+)
+
+type ControllerCommandSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ControllerCommandSuite{})
+
+var expectedControllerCommandNames = []string{
+	"db-compact",
+	"db-status",
+	"help",
+}
+
+func (s *ControllerCommandSuite) TestHelp(c *gc.C) {
+	// Check the help output
+	ctx, err := testing.RunCommand(c, controller.NewSuperCommand(), "--help")
+	c.Assert(err, gc.IsNil)
+
+	// Check that we have registered all the sub commands by
+	// inspecting the help output.
+	var namesFound []string
+	commandHelp := strings.SplitAfter(testing.Stdout(ctx), "commands:")[1]
+	commandHelp = strings.TrimSpace(commandHelp)
+	for _, line := range strings.Split(commandHelp, "\n") {
+		namesFound = append(namesFound, strings.TrimSpace(strings.Split(line, " - ")[0]))
+	}
+	c.Assert(namesFound, gc.DeepEquals, expectedControllerCommandNames)
+}
+
+type BaseSuite struct {
+	testing.BaseSuite
+	mock *mockMaintenanceAPI
+}
+
+func (s *BaseSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	memstore := configstore.NewMem()
+	s.PatchValue(&configstore.Default, func() (configstore.Storage, error) {
+		return memstore, nil
+	})
+	os.Setenv(osenv.JujuEnvEnvKey, "testing")
+	info := memstore.CreateInfo("testing")
+	info.SetBootstrapConfig(map[string]interface{}{"random": "extra data"})
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"localhost:12345"},
+		CACert:      testing.CACert,
+		EnvironUUID: "env-uuid",
+	})
+	info.SetAPICredentials(configstore.APICredentials{
+		User:     "user-test",
+		Password: "password",
+	})
+	err := info.Write()
+	c.Assert(err, gc.IsNil)
+	s.mock = &mockMaintenanceAPI{}
+	s.PatchValue(controller.GetMaintenanceAPI, func(*controller.ControllerCommandBase) (controller.MaintenanceAPI, error) {
+		return s.mock, nil
+	})
+}
+
+type mockMaintenanceAPI struct {
+	status    params.DatabaseStatusResult
+	compacted []bool
+	err       error
+}
+
+var _ controller.MaintenanceAPI = (*mockMaintenanceAPI)(nil)
+
+func (m *mockMaintenanceAPI) DatabaseStatus() (params.DatabaseStatusResult, error) {
+	return m.status, m.err
+}
+
+func (m *mockMaintenanceAPI) CompactDatabase(repair bool) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.compacted = append(m.compacted, repair)
+	return nil
+}
+
+func (m *mockMaintenanceAPI) Close() error {
+	return nil
+}
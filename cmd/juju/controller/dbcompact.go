@@ -0,0 +1,88 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+const dbCompactCommandDoc = `
+Reclaim unused space in the state database by compacting each of its
+collections. With --repair, the database is instead rebuilt from
+scratch, which also recovers from corruption but needs free disk space
+as large as the database itself.
+
+Both operations block all access to the state database until they
+finish, so the environment cannot be used in the meantime. Only run
+this command during a maintenance window.
+`
+
+// DBCompactCommand compacts or repairs the state database.
+type DBCompactCommand struct {
+	ControllerCommandBase
+	Repair    bool
+	assumeYes bool
+}
+
+// Info implements Command.Info.
+func (c *DBCompactCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "db-compact",
+		Purpose: "compact or repair the state database",
+		Doc:     dbCompactCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *DBCompactCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Repair, "repair", false, "repair the whole database rather than compacting collections")
+	f.BoolVar(&c.assumeYes, "y", false, "Do not ask for confirmation")
+	f.BoolVar(&c.assumeYes, "yes", false, "")
+}
+
+// Init implements Command.Init.
+func (c *DBCompactCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+var dbCompactMsg = `
+WARNING! this command will block all access to the state database
+until the %s finishes.
+
+Continue [y/N]? `[1:]
+
+// Run implements Command.Run.
+func (c *DBCompactCommand) Run(ctx *cmd.Context) error {
+	operation := "compaction"
+	if c.Repair {
+		operation = "repair"
+	}
+	if !c.assumeYes {
+		fmt.Fprintf(ctx.Stdout, dbCompactMsg, operation)
+
+		scanner := bufio.NewScanner(ctx.Stdin)
+		scanner.Scan()
+		err := scanner.Err()
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("database %s aborted: %s", operation, err)
+		}
+		answer := strings.ToLower(scanner.Text())
+		if answer != "y" && answer != "yes" {
+			return errors.Errorf("database %s aborted", operation)
+		}
+	}
+	client, err := getMaintenanceAPI(&c.ControllerCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.CompactDatabase(c.Repair)
+}
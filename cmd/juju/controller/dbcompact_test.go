@@ -0,0 +1,55 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"bytes"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/testing"
+)
+
+type DBCompactSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&DBCompactSuite{})
+
+func (s *DBCompactSuite) TestCompact(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&controller.DBCompactCommand{}), "-y")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mock.compacted, gc.DeepEquals, []bool{false})
+}
+
+func (s *DBCompactSuite) TestRepair(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&controller.DBCompactCommand{}), "--repair", "--yes")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mock.compacted, gc.DeepEquals, []bool{true})
+}
+
+func (s *DBCompactSuite) TestConfirm(c *gc.C) {
+	command := envcmd.Wrap(&controller.DBCompactCommand{})
+	err := testing.InitCommand(command, nil)
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	ctx.Stdin = bytes.NewBufferString("y\n")
+	err = command.Run(ctx)
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Matches, "(?s)WARNING! .* compaction finishes.*")
+	c.Assert(s.mock.compacted, gc.DeepEquals, []bool{false})
+}
+
+func (s *DBCompactSuite) TestAbort(c *gc.C) {
+	command := envcmd.Wrap(&controller.DBCompactCommand{})
+	err := testing.InitCommand(command, []string{"--repair"})
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	ctx.Stdin = bytes.NewBufferString("n\n")
+	err = command.Run(ctx)
+	c.Assert(err, gc.ErrorMatches, "database repair aborted")
+	c.Assert(s.mock.compacted, gc.HasLen, 0)
+}
@@ -0,0 +1,101 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const dbStatusCommandDoc = `
+Show the size and index health of each collection in the state
+database, and how far each state server's copy of the database lags
+behind the primary. A collection whose storage size is much larger
+than its size has space that "juju controller db-compact" can reclaim.
+A collection with missing indexes will be slow to query; restarting
+the state servers recreates them.
+`
+
+// DBStatusCommand shows the health of the state database.
+type DBStatusCommand struct {
+	ControllerCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *DBStatusCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "db-status",
+		Purpose: "show the size and health of the state database",
+		Doc:     dbStatusCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *DBStatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *DBStatusCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+type collectionStatus struct {
+	Documents      int64    `json:"documents" yaml:"documents"`
+	Size           int64    `json:"size" yaml:"size"`
+	StorageSize    int64    `json:"storage-size" yaml:"storage-size"`
+	IndexSize      int64    `json:"index-size" yaml:"index-size"`
+	Indexes        []string `json:"indexes" yaml:"indexes"`
+	MissingIndexes []string `json:"missing-indexes,omitempty" yaml:"missing-indexes,omitempty"`
+	Capped         bool     `json:"capped,omitempty" yaml:"capped,omitempty"`
+}
+
+type memberStatus struct {
+	Address string `json:"address" yaml:"address"`
+	State   string `json:"state" yaml:"state"`
+	Healthy bool   `json:"healthy" yaml:"healthy"`
+	Lag     string `json:"lag" yaml:"lag"`
+}
+
+type dbStatus struct {
+	Collections map[string]collectionStatus `json:"collections" yaml:"collections"`
+	ReplicaSet  []memberStatus              `json:"replica-set,omitempty" yaml:"replica-set,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *DBStatusCommand) Run(ctx *cmd.Context) error {
+	client, err := getMaintenanceAPI(&c.ControllerCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	status, err := client.DatabaseStatus()
+	if err != nil {
+		return err
+	}
+	result := dbStatus{
+		Collections: make(map[string]collectionStatus),
+	}
+	for _, coll := range status.Collections {
+		result.Collections[coll.Name] = collectionStatus{
+			Documents:      coll.Count,
+			Size:           coll.Size,
+			StorageSize:    coll.StorageSize,
+			IndexSize:      coll.IndexSize,
+			Indexes:        coll.Indexes,
+			MissingIndexes: coll.MissingIndexes,
+			Capped:         coll.Capped,
+		}
+	}
+	for _, member := range status.Members {
+		result.ReplicaSet = append(result.ReplicaSet, memberStatus{
+			Address: member.Address,
+			State:   member.State,
+			Healthy: member.Healthy,
+			Lag:     member.Lag.String(),
+		})
+	}
+	return c.out.Write(ctx, result)
+}
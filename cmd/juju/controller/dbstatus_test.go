@@ -0,0 +1,73 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/testing"
+)
+
+type DBStatusSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&DBStatusSuite{})
+
+func (s *DBStatusSuite) TestDBStatus(c *gc.C) {
+	s.mock.status = params.DatabaseStatusResult{
+		Collections: []params.CollectionStatus{{
+			Name:           "units",
+			Count:          3,
+			Size:           1024,
+			StorageSize:    8192,
+			IndexSize:      4096,
+			Indexes:        []string{"_id_", "service_1"},
+			MissingIndexes: []string{"principal"},
+		}},
+		Members: []params.ReplicaSetMemberStatus{{
+			Address: "10.0.0.1:37017",
+			State:   "PRIMARY",
+			Healthy: true,
+		}, {
+			Address: "10.0.0.2:37017",
+			State:   "SECONDARY",
+			Healthy: true,
+			Lag:     2 * time.Second,
+		}},
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&controller.DBStatusCommand{}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"collections:\n"+
+		"  units:\n"+
+		"    documents: 3\n"+
+		"    size: 1024\n"+
+		"    storage-size: 8192\n"+
+		"    index-size: 4096\n"+
+		"    indexes:\n"+
+		"    - _id_\n"+
+		"    - service_1\n"+
+		"    missing-indexes:\n"+
+		"    - principal\n"+
+		"replica-set:\n"+
+		"- address: 10.0.0.1:37017\n"+
+		"  state: PRIMARY\n"+
+		"  healthy: true\n"+
+		"  lag: 0s\n"+
+		"- address: 10.0.0.2:37017\n"+
+		"  state: SECONDARY\n"+
+		"  healthy: true\n"+
+		"  lag: 2s\n")
+}
+
+func (s *DBStatusSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&controller.DBStatusCommand{}, []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
@@ -0,0 +1,159 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/environmentmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/configstore"
+)
+
+const createEnvironmentDoc = `
+Create a new environment hosted by the state server of the current
+environment, and owned by the current user.
+
+The new environment's configuration is copied from the current
+environment, except for any attributes specified as key=value pairs.
+Its name must not be used by any other environment known to the
+client.
+
+Once created, the new environment is added to the client's known
+environments, and may be used by specifying its name with -e.
+
+Examples:
+   juju create-environment staging
+   juju create-environment staging default-series=trusty
+
+See Also:
+   juju switch
+`
+
+// CreateEnvironmentCommand creates a new environment on the state
+// server of the current environment.
+type CreateEnvironmentCommand struct {
+	envcmd.EnvCommandBase
+	Name   string
+	Config map[string]interface{}
+}
+
+func (c *CreateEnvironmentCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create-environment",
+		Args:    "<name> [key=value ...]",
+		Purpose: "create an environment on the current state server",
+		Doc:     createEnvironmentDoc,
+	}
+}
+
+func (c *CreateEnvironmentCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no environment name specified")
+	}
+	c.Name = args[0]
+	c.Config = map[string]interface{}{"name": c.Name}
+	for i, arg := range args[1:] {
+		bits := strings.SplitN(arg, "=", 2)
+		if len(bits) < 2 {
+			return errors.Errorf(`missing "=" in arg %d: %q`, i+2, arg)
+		}
+		key := bits[0]
+		switch key {
+		case "name", "uuid":
+			return errors.Errorf("%s cannot be specified as a config attribute", key)
+		}
+		if _, exists := c.Config[key]; exists {
+			return errors.Errorf("key %q specified more than once", key)
+		}
+		c.Config[key] = bits[1]
+	}
+	return nil
+}
+
+// CreateEnvironmentAPI defines the API methods that the
+// create-environment command uses.
+type CreateEnvironmentAPI interface {
+	CreateEnvironment(owner names.UserTag, config map[string]interface{}) (params.Environment, error)
+	Close() error
+}
+
+var getCreateEnvironmentAPI = func(c *CreateEnvironmentCommand) (CreateEnvironmentAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return environmentmanager.NewClient(root), nil
+}
+
+func (c *CreateEnvironmentCommand) Run(ctx *cmd.Context) error {
+	store, err := configstore.Default()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := checkNoEnvironmentInfo(store, c.Name); err != nil {
+		return err
+	}
+	client, err := getCreateEnvironmentAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	creds, err := c.ConnectionCredentials()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := client.CreateEnvironment(names.NewUserTag(creds.User), c.Config)
+	if err != nil {
+		return err
+	}
+	if err := writeHostedEnvironmentInfo(store, &c.EnvCommandBase, env); err != nil {
+		return err
+	}
+	ctx.Infof("created environment %q (%s)", env.Name, env.UUID)
+	return nil
+}
+
+// checkNoEnvironmentInfo returns an error if the client already knows
+// of an environment with the given name.
+func checkNoEnvironmentInfo(store configstore.Storage, name string) error {
+	if _, err := store.ReadInfo(name); err == nil {
+		return errors.Errorf("environment %q already exists", name)
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// writeHostedEnvironmentInfo records the details of the given
+// environment, which is hosted by the state server of the command's
+// environment. The new environment is served by the same API servers
+// as the command's environment, and is accessed with the same
+// credentials.
+func writeHostedEnvironmentInfo(store configstore.Storage, c *envcmd.EnvCommandBase, env params.Environment) error {
+	creds, err := c.ConnectionCredentials()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	endpoint, err := c.ConnectionEndpoint(false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info := store.CreateInfo(env.Name)
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   endpoint.Addresses,
+		CACert:      endpoint.CACert,
+		EnvironUUID: env.UUID,
+	})
+	info.SetAPICredentials(creds)
+	if err := info.Write(); err != nil {
+		return errors.Annotatef(err, "cannot save details of environment %q", env.Name)
+	}
+	return nil
+}
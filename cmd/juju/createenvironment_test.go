@@ -0,0 +1,150 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/testing"
+)
+
+type CreateEnvironmentSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockCreateEnvironmentAPI
+	store   configstore.Storage
+}
+
+var _ = gc.Suite(&CreateEnvironmentSuite{})
+
+func (s *CreateEnvironmentSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockCreateEnvironmentAPI{}
+	s.PatchValue(&getCreateEnvironmentAPI, func(*CreateEnvironmentCommand) (CreateEnvironmentAPI, error) {
+		return s.mockAPI, nil
+	})
+	var err error
+	s.store, err = configstore.Default()
+	c.Assert(err, gc.IsNil)
+	info := s.store.CreateInfo(testing.SampleEnvName)
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"127.0.0.1:12345"},
+		CACert:      testing.CACert,
+		EnvironUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+	})
+	info.SetAPICredentials(configstore.APICredentials{
+		User:     "bob",
+		Password: "sekrit",
+	})
+	err = info.Write()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *CreateEnvironmentSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&CreateEnvironmentCommand{}), args...)
+}
+
+func (s *CreateEnvironmentSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		err    string
+		config map[string]interface{}
+	}{{
+		err: "no environment name specified",
+	}, {
+		args:   []string{"staging"},
+		config: map[string]interface{}{"name": "staging"},
+	}, {
+		args: []string{"staging", "default-series=trusty"},
+		config: map[string]interface{}{
+			"name":           "staging",
+			"default-series": "trusty",
+		},
+	}, {
+		args: []string{"staging", "default-series"},
+		err:  `missing "=" in arg 2: "default-series"`,
+	}, {
+		args: []string{"staging", "uuid=fake"},
+		err:  "uuid cannot be specified as a config attribute",
+	}, {
+		args: []string{"staging", "a=b", "a=c"},
+		err:  `key "a" specified more than once`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &CreateEnvironmentCommand{}
+		err := testing.InitCommand(command, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, gc.IsNil)
+		c.Check(command.Config, gc.DeepEquals, test.config)
+	}
+}
+
+func (s *CreateEnvironmentSuite) TestCreateEnvironment(c *gc.C) {
+	ctx, err := s.run(c, "staging", "default-series=trusty")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.owner, gc.Equals, names.NewUserTag("bob"))
+	c.Assert(s.mockAPI.config, gc.DeepEquals, map[string]interface{}{
+		"name":           "staging",
+		"default-series": "trusty",
+	})
+	c.Assert(testing.Stderr(ctx), gc.Equals, `created environment "staging" (fake-uuid)`+"\n")
+
+	info, err := s.store.ReadInfo("staging")
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.APIEndpoint(), gc.DeepEquals, configstore.APIEndpoint{
+		Addresses:   []string{"127.0.0.1:12345"},
+		CACert:      testing.CACert,
+		EnvironUUID: "fake-uuid",
+	})
+	c.Assert(info.APICredentials(), gc.DeepEquals, configstore.APICredentials{
+		User:     "bob",
+		Password: "sekrit",
+	})
+}
+
+func (s *CreateEnvironmentSuite) TestCreateEnvironmentExistingName(c *gc.C) {
+	_, err := s.run(c, testing.SampleEnvName)
+	c.Assert(err, gc.ErrorMatches, `environment "erewhemos" already exists`)
+	c.Assert(s.mockAPI.config, gc.IsNil)
+}
+
+func (s *CreateEnvironmentSuite) TestCreateEnvironmentFails(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := s.run(c, "staging")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	_, err = s.store.ReadInfo("staging")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+type mockCreateEnvironmentAPI struct {
+	owner  names.UserTag
+	config map[string]interface{}
+	err    error
+}
+
+func (m *mockCreateEnvironmentAPI) CreateEnvironment(owner names.UserTag, config map[string]interface{}) (params.Environment, error) {
+	m.owner = owner
+	m.config = config
+	if m.err != nil {
+		return params.Environment{}, m.err
+	}
+	return params.Environment{
+		Name:     config["name"].(string),
+		UUID:     "fake-uuid",
+		OwnerTag: owner.String(),
+	}, nil
+}
+
+func (*mockCreateEnvironmentAPI) Close() error {
+	return nil
+}
@@ -6,11 +6,13 @@ package main
 import (
 	"encoding/base64"
 	"fmt"
+	"path"
 	"sort"
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v4/hooks"
+	"launchpad.net/gnuflag"
 
 	unitdebug "github.com/juju/juju/worker/uniter/debug"
 )
@@ -18,22 +20,46 @@ import (
 // DebugHooksCommand is responsible for launching a ssh shell on a given unit or machine.
 type DebugHooksCommand struct {
 	SSHCommand
-	hooks []string
+	hooks      []string
+	transcript bool
 }
 
 const debugHooksDoc = `
 Interactively debug a hook remotely on a service unit.
+
+Hooks to debug may be given by name or by a glob pattern; hooks that
+match none of them are run as usual. If no hooks are given, or any of
+them is "*", all hooks are debugged.
+
+The output of the debugged hooks is recorded, and the transcript of the
+most recent debug-hooks session on a unit can be shown with --transcript.
+
+Examples:
+
+Debug the config-changed hook and all relation hooks of the first mysql
+unit:
+
+    juju debug-hooks mysql/0 config-changed '*-relation-*'
+
+Show the transcript of the last debug-hooks session on that unit:
+
+    juju debug-hooks --transcript mysql/0
 `
 
 func (c *DebugHooksCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "debug-hooks",
-		Args:    "<unit name> [hook names]",
+		Args:    "<unit name> [hook names or patterns]",
 		Purpose: "launch a tmux session to debug a hook",
 		Doc:     debugHooksDoc,
 	}
 }
 
+func (c *DebugHooksCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SSHCommand.SetFlags(f)
+	f.BoolVar(&c.transcript, "transcript", false, "show the transcript of the unit's last debug-hooks session")
+}
+
 func (c *DebugHooksCommand) Init(args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("no unit name specified")
@@ -42,6 +68,9 @@ func (c *DebugHooksCommand) Init(args []string) error {
 	if !names.IsValidUnit(c.Target) {
 		return fmt.Errorf("%q is not a valid unit name", c.Target)
 	}
+	if c.transcript {
+		return cmd.CheckEmpty(args[1:])
+	}
 
 	// If any of the hooks is "*", then debug all hooks.
 	c.hooks = append([]string{}, args[1:]...)
@@ -75,7 +104,7 @@ func (c *DebugHooksCommand) validateHooks() error {
 		}
 	}
 	for _, hook := range c.hooks {
-		if !validHooks[hook] {
+		if !matchesHook(hook, validHooks) {
 			names := make([]string, 0, len(validHooks))
 			for hookName, _ := range validHooks {
 				names = append(names, hookName)
@@ -88,10 +117,43 @@ func (c *DebugHooksCommand) validateHooks() error {
 	return nil
 }
 
+// matchesHook reports whether the given hook name or glob
+// pattern matches any of the given valid hook names.
+func matchesHook(pattern string, validHooks map[string]bool) bool {
+	if validHooks[pattern] {
+		return true
+	}
+	for hook := range validHooks {
+		if matched, _ := path.Match(pattern, hook); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// showTranscript writes the transcript of the last debug-hooks
+// session on c.Target to the context's stdout.
+func (c *DebugHooksCommand) showTranscript(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	transcript, _, err := client.DebugHooksTranscript(c.Target)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(ctx.Stdout, transcript)
+	return err
+}
+
 // Run ensures c.Target is a unit, and resolves its address,
 // and connects to it via SSH to execute the debug-hooks
 // script.
 func (c *DebugHooksCommand) Run(ctx *cmd.Context) error {
+	if c.transcript {
+		return c.showTranscript(ctx)
+	}
 	var err error
 	c.apiClient, err = c.initAPIClient()
 	if err != nil {
@@ -31,6 +31,17 @@ const defaultLineCount = 10
 const debuglogDoc = `
 Stream the consolidated debug log file. This file contains the log messages
 from all nodes in the environment.
+
+The --include and --exclude flags select messages by entity tag, such as
+unit-mysql-0 or machine-1; a tag ending in '*' matches any tag with that
+prefix. A message matches an entity if that entity's agent logged it, or
+if the message concerns that entity: messages that a machine agent logs
+about one of the units it runs are tagged with the unit, so
+
+    juju debug-log --include unit-mysql-0
+
+shows them alongside the unit agent's own messages, even when several
+units share the machine.
 `
 
 func (c *DebugLogCommand) Info() *cmd.Info {
@@ -26,12 +26,19 @@ type DeployCommand struct {
 	envcmd.EnvCommandBase
 	UnitCommandBase
 	CharmName    string
+	ArchiveURL   string
+	SHA256       string
 	ServiceName  string
 	Config       cmd.FileVar
 	Constraints  constraints.Value
 	Networks     string
 	BumpRevision bool   // Remove this once the 1.16 support is dropped.
 	RepoPath     string // defaults to JUJU_REPOSITORY
+
+	// UpgradeOnChange causes the command to keep running after the
+	// deployment, upgrading the service whenever its local charm
+	// changes.
+	UpgradeOnChange bool
 }
 
 const deployDoc = `
@@ -55,6 +62,13 @@ However, for local charms, when the default-series is not specified in the
 environment, one must specify the series. For example:
   local:precise/mysql
 
+<charm name> can also be an https URL of a charm archive, such as one
+published on an internal artifact server, in which case the SHA256 digest
+of the archive must be given with --sha256.  The archive is downloaded
+and verified by the client, then added to the environment as a local
+charm for the environment's default-series:
+  juju deploy https://charms.example.com/mysql-12.charm --sha256 <digest>
+
 <service name>, if omitted, will be derived from <charm name>.
 
 Constraints can be specified when using deploy by specifying the --constraints
@@ -63,6 +77,9 @@ machines provisioned with add-unit will use the same constraints (unless changed
 by set-constraints).
 
 Charms can be deployed to a specific machine using the --to argument.
+The --to argument also accepts a placement directive for a new machine, such
+as an availability zone or a MAAS host name, which is passed to the
+environment's provider.
 If the destination is an LXC container the default is to use lxc-clone
 to create the container where possible. For Ubuntu deployments, lxc-clone
 is supported for the trusty OS series and later. A 'template' container is
@@ -81,10 +98,19 @@ explicitly ask Juju to create full containers and not overlays by specifying
 the following in the provider configuration:
   lxc-clone-aufs: false
 
+When developing a local charm, the --upgrade-on-change flag keeps the
+deploy command running after the service is deployed. Whenever the charm
+in the local repository changes, it is added to the environment with a
+new revision and the service is upgraded to it. Interrupt the command to
+stop watching.
+  juju deploy --upgrade-on-change local:precise/mycharm
+
 Examples:
-   juju deploy mysql --to 23       (deploy to machine 23)
-   juju deploy mysql --to 24/lxc/3 (deploy to lxc container 3 on host machine 24)
-   juju deploy mysql --to lxc:25   (deploy to a new lxc container on host machine 25)
+   juju deploy mysql --to 23              (deploy to machine 23)
+   juju deploy mysql --to 24/lxc/3        (deploy to lxc container 3 on host machine 24)
+   juju deploy mysql --to lxc:25          (deploy to a new lxc container on host machine 25)
+   juju deploy mysql --to zone=us-east-1b (deploy to a new machine in zone us-east-1b)
+   juju deploy mysql --to host.maas       (deploy to the MAAS node host.maas)
 
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
@@ -126,6 +152,8 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "set service constraints")
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
+	f.StringVar(&c.SHA256, "sha256", "", "expected SHA256 digest of a charm archive deployed by URL")
+	f.BoolVar(&c.UpgradeOnChange, "upgrade-on-change", false, "upgrade the service whenever its local charm changes")
 }
 
 func (c *DeployCommand) Init(args []string) error {
@@ -137,9 +165,26 @@ func (c *DeployCommand) Init(args []string) error {
 		c.ServiceName = args[1]
 		fallthrough
 	case 1:
-		if _, err := charm.InferURL(args[0], "fake"); err != nil {
+		if isCharmArchiveURL(args[0]) {
+			if c.SHA256 == "" {
+				return errors.New("--sha256 is required when deploying a charm archive by URL")
+			}
+			if c.UpgradeOnChange {
+				return errors.New("--upgrade-on-change can only be used with local charms")
+			}
+			c.ArchiveURL = args[0]
+			break
+		}
+		curl, err := charm.InferURL(args[0], "fake")
+		if err != nil {
 			return fmt.Errorf("invalid charm name %q", args[0])
 		}
+		if c.UpgradeOnChange && curl.Schema != "local" {
+			return errors.New("--upgrade-on-change can only be used with local charms")
+		}
+		if c.SHA256 != "" {
+			return errors.New("--sha256 can only be used when deploying a charm archive by URL")
+		}
 		c.CharmName = args[0]
 	case 0:
 		return errors.New("no charm specified")
@@ -150,10 +195,11 @@ func (c *DeployCommand) Init(args []string) error {
 }
 
 func (c *DeployCommand) Run(ctx *cmd.Context) error {
-	client, err := c.NewAPIClient()
+	root, err := c.NewAPIRoot()
 	if err != nil {
 		return err
 	}
+	client := root.Client()
 	defer client.Close()
 
 	conf, err := getClientConfig(client)
@@ -165,19 +211,12 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	curl, err := resolveCharmURL(c.CharmName, client, conf)
-	if err != nil {
-		return err
+	var curl *charm.URL
+	if c.ArchiveURL != "" {
+		curl, err = addCharmArchiveViaAPI(client, ctx, c.ArchiveURL, c.SHA256, conf)
+	} else {
+		curl, err = c.addCharm(root, client, ctx, conf)
 	}
-
-	repo, err := charm.InferRepository(curl.Reference(), ctx.AbsPath(c.RepoPath))
-	if err != nil {
-		return err
-	}
-
-	repo = config.SpecializeCharmRepo(repo, conf)
-
-	curl, err = addCharmViaAPI(client, ctx, curl, repo)
 	if err != nil {
 		return err
 	}
@@ -250,7 +289,44 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 			c.Constraints,
 			c.ToMachineSpec)
 	}
-	return err
+	if err != nil || !c.UpgradeOnChange {
+		return err
+	}
+	return c.watchLocalCharm(ctx, client, curl, serviceName)
+}
+
+// watchLocalCharm upgrades the deployed service whenever its local
+// charm changes, until the command is interrupted.
+func (c *DeployCommand) watchLocalCharm(ctx *cmd.Context, client *api.Client, curl *charm.URL, serviceName string) error {
+	repo, err := charm.InferRepository(curl.Reference(), ctx.AbsPath(c.RepoPath))
+	if err != nil {
+		return err
+	}
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+	w := &localCharmWatcher{
+		client:      client,
+		repo:        repo,
+		curl:        curl,
+		serviceName: serviceName,
+	}
+	return w.run(ctx, interrupted)
+}
+
+// addCharm resolves the command's charm name to a charm URL and adds
+// the charm to state.
+func (c *DeployCommand) addCharm(root *api.State, client *api.Client, ctx *cmd.Context, conf *config.Config) (*charm.URL, error) {
+	curl, err := resolveCharmURL(c.CharmName, root, conf)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := charm.InferRepository(curl.Reference(), ctx.AbsPath(c.RepoPath))
+	if err != nil {
+		return nil, err
+	}
+	repo = config.SpecializeCharmRepo(repo, conf)
+	return addCharmViaAPI(client, ctx, curl, repo)
 }
 
 // addCharmViaAPI calls the appropriate client API calls to add the
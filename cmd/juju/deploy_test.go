@@ -4,6 +4,11 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 
 	"github.com/juju/errors"
@@ -62,6 +67,18 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"https://example.com/dummy.charm"},
+		err:  `--sha256 is required when deploying a charm archive by URL`,
+	}, {
+		args: []string{"craziness", "--sha256", "abcdef"},
+		err:  `--sha256 can only be used when deploying a charm archive by URL`,
+	}, {
+		args: []string{"craziness", "--upgrade-on-change"},
+		err:  `--upgrade-on-change can only be used with local charms`,
+	}, {
+		args: []string{"https://example.com/dummy.charm", "--sha256", "abcdef", "--upgrade-on-change"},
+		err:  `--upgrade-on-change can only be used with local charms`,
 	},
 }
 
@@ -122,6 +139,37 @@ func (s *DeploySuite) TestCharmBundle(c *gc.C) {
 	s.AssertService(c, "some-service-name", curl, 1, 0)
 }
 
+func (s *DeploySuite) serveCharmArchive(c *gc.C, name string) (archiveURL, digest string) {
+	path := charmtesting.Charms.CharmArchivePath(c.MkDir(), name)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, gc.IsNil)
+	hash := sha256.Sum256(data)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	s.PatchValue(&charmArchiveHostnameVerification, utils.NoVerifySSLHostnames)
+	return server.URL + "/" + name + ".charm", hex.EncodeToString(hash[:])
+}
+
+func (s *DeploySuite) TestCharmArchiveURL(c *gc.C) {
+	archiveURL, digest := s.serveCharmArchive(c, "dummy")
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), archiveURL, "--sha256", digest)
+	c.Assert(err, gc.IsNil)
+	output := strings.Split(coretesting.Stderr(ctx), "\n")
+	c.Assert(output[0], gc.Equals, `Added charm "local:trusty/dummy-1" to the environment.`)
+	curl := charm.MustParseURL("local:trusty/dummy-1")
+	s.AssertService(c, "dummy", curl, 1, 0)
+}
+
+func (s *DeploySuite) TestCharmArchiveURLChecksumMismatch(c *gc.C) {
+	archiveURL, _ := s.serveCharmArchive(c, "dummy")
+	err := runDeploy(c, archiveURL, "--sha256", strings.Repeat("0", 64))
+	c.Assert(err, gc.ErrorMatches, `charm archive from ".*" has SHA256 digest [0-9a-f]+, expected 0+`)
+	_, err = s.State.Service("dummy")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *DeploySuite) TestSubordinateCharm(c *gc.C) {
 	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "logging")
 	err := runDeploy(c, "local:logging")
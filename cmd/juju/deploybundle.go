@@ -0,0 +1,146 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/bundles"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const deployBundleDoc = `
+Deploys the services, relations and units described by a bundle file.
+The whole deployment is performed by the state server, so it completes
+even if the connection to the client is lost. The command reports each
+change as the state server makes it, and returns when the deployment
+has finished.
+
+The bundle is checked before any changes are made: an unknown charm,
+invalid settings or constraints, or a placement on a machine that does
+not exist cause the deployment to fail without changing the
+environment. If the deployment fails part way through, the services it
+deployed and the machines it started are removed again. Services that
+already exist in the environment cannot be
+redeployed, but may be named in relations.
+
+Only charm store charms are supported. An example bundle:
+
+    series: trusty
+    services:
+      wordpress:
+        charm: cs:wordpress
+        num_units: 2
+        to: ["lxc:0"]
+        expose: true
+      mysql:
+        charm: cs:mysql
+        num_units: 1
+        options:
+          dataset-size: 50%
+        constraints: mem=4G
+    relations:
+      - ["wordpress:db", "mysql:db"]
+
+Units beyond those given placements in "to" are deployed to newly
+provisioned machines.
+`
+
+// DeployBundleCommand deploys a bundle of services.
+type DeployBundleCommand struct {
+	envcmd.EnvCommandBase
+	BundlePath string
+}
+
+func (c *DeployBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "deploy-bundle",
+		Args:    "<bundle file>",
+		Purpose: "deploy a bundle of services",
+		Doc:     deployBundleDoc,
+	}
+}
+
+func (c *DeployBundleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no bundle file specified")
+	}
+	c.BundlePath = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *DeployBundleCommand) Run(ctx *cmd.Context) error {
+	data, err := ioutil.ReadFile(ctx.AbsPath(c.BundlePath))
+	if err != nil {
+		return err
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	client := bundles.NewClient(root)
+	defer client.Close()
+
+	id, changes, err := client.Deploy(string(data))
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("deploy-bundle is not supported by the API server")
+	}
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		ctx.Infof("%s", describeBundleChange(change))
+	}
+	return waitForBundleDeployment(ctx, client, id)
+}
+
+// waitForBundleDeployment reports the changes made by the given bundle
+// deployment as they are made, and returns when it has finished.
+func waitForBundleDeployment(ctx *cmd.Context, client *bundles.Client, id string) error {
+	w, err := client.WatchDeployment(id)
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	reported := 0
+	for _ = range w.Changes() {
+		status, err := client.DeploymentStatus(id)
+		if err != nil {
+			return err
+		}
+		for _, change := range status.Changes[reported:] {
+			ctx.Infof("%s", describeBundleChange(change))
+		}
+		reported = len(status.Changes)
+		switch status.Status {
+		case "done":
+			return nil
+		case "failed":
+			return errors.Errorf("cannot deploy bundle: %s; changes have been undone", status.Error)
+		}
+	}
+	return errors.Annotatef(w.Err(), "lost track of bundle deployment %q, which continues on the state server", id)
+}
+
+// describeBundleChange returns a description of a change made while
+// deploying a bundle.
+func describeBundleChange(change params.BundleChange) string {
+	switch change.Kind {
+	case "add-charm":
+		return "added charm " + change.Id
+	case "deploy":
+		return "deployed service " + change.Id
+	case "expose":
+		return "exposed service " + change.Id
+	case "add-relation":
+		return "added relation " + change.Id
+	case "add-unit":
+		return "added unit " + change.Id
+	}
+	return change.Kind + " " + change.Id
+}
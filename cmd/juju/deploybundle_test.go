@@ -0,0 +1,117 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	charmtesting "gopkg.in/juju/charm.v4/testing"
+
+	"github.com/juju/juju/apiserver/charms"
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/bundledeployer"
+)
+
+type DeployBundleSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&DeployBundleSuite{})
+
+func (s *DeployBundleSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no bundle file specified",
+	}, {
+		args: []string{"bundle.yaml", "other"},
+		err:  `unrecognized args: \["other"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&DeployBundleCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *DeployBundleSuite) TestDeployBundle(c *gc.C) {
+	store := charmtesting.NewMockCharmStore()
+	s.PatchValue(&charms.CharmStore, store)
+	s.PatchValue(&client.CharmStore, store)
+	bundle := charmtesting.Charms.CharmArchive(c.MkDir(), "wordpress")
+	curl := charm.MustParseURL(fmt.Sprintf("cs:precise/wordpress-%d", bundle.Revision()))
+	err := store.SetCharm(curl, bundle)
+	c.Assert(err, gc.IsNil)
+
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err = ioutil.WriteFile(path, []byte(`
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+    num_units: 1
+    expose: true
+`), 0644)
+	c.Assert(err, gc.IsNil)
+
+	deployer := bundledeployer.NewBundleDeployer(s.State)
+	defer worker.Stop(deployer)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&DeployBundleCommand{}), path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, fmt.Sprintf(`added charm %s
+deployed service wordpress
+exposed service wordpress
+added unit wordpress/0
+`, curl))
+	svc, err := s.State.Service("wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(svc.IsExposed(), gc.Equals, true)
+}
+
+func (s *DeployBundleSuite) TestDeployBundleFailure(c *gc.C) {
+	store := charmtesting.NewMockCharmStore()
+	s.PatchValue(&charms.CharmStore, store)
+	s.PatchValue(&client.CharmStore, store)
+	for _, name := range []string{"wordpress", "logging"} {
+		bundle := charmtesting.Charms.CharmArchive(c.MkDir(), name)
+		curl := charm.MustParseURL(fmt.Sprintf("cs:precise/%s-%d", name, bundle.Revision()))
+		err := store.SetCharm(curl, bundle)
+		c.Assert(err, gc.IsNil)
+	}
+
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(`
+services:
+  wordpress:
+    charm: cs:precise/wordpress
+  logging:
+    charm: cs:precise/logging
+relations:
+  - ["wordpress:db", logging]
+`), 0644)
+	c.Assert(err, gc.IsNil)
+
+	deployer := bundledeployer.NewBundleDeployer(s.State)
+	defer worker.Stop(deployer)
+	_, err = testing.RunCommand(c, envcmd.Wrap(&DeployBundleCommand{}), path)
+	c.Assert(err, gc.ErrorMatches, `cannot deploy bundle: cannot add relation "wordpress:db logging": .*; changes have been undone`)
+	services, err := s.State.AllServices()
+	c.Assert(err, gc.IsNil)
+	for _, svc := range services {
+		c.Check(svc.Life(), gc.Not(gc.Equals), state.Alive)
+	}
+}
+
+func (s *DeployBundleSuite) TestDeployBundleMissingFile(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&DeployBundleCommand{}), filepath.Join(c.MkDir(), "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, "open .*missing.yaml: no such file or directory")
+}
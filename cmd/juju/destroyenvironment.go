@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/juju"
 )
@@ -25,16 +26,27 @@ var DoubleEnvironmentError = errors.New("you cannot supply both -e and the envna
 // DestroyEnvironmentCommand destroys an environment.
 type DestroyEnvironmentCommand struct {
 	cmd.CommandBase
-	envName   string
-	assumeYes bool
-	force     bool
+	envName           string
+	assumeYes         bool
+	force             bool
+	confirmationToken string
 }
 
+const destroyEnvDoc = `
+Destroys the environment, terminating all of its machines and releasing
+any other resources associated with it.
+
+An environment with the destroy-protected setting set to true can only
+be destroyed if its UUID is given with --confirmation-token. The UUID
+can be found with "juju api-info environ-uuid".
+`
+
 func (c *DestroyEnvironmentCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "destroy-environment",
 		Args:    "<environment name>",
 		Purpose: "terminate all machines and other associated resources for an environment",
+		Doc:     destroyEnvDoc,
 	}
 }
 
@@ -44,6 +56,7 @@ func (c *DestroyEnvironmentCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.force, "force", false, "Forcefully destroy the environment, directly through the environment provider")
 	f.StringVar(&c.envName, "e", "", "juju environment to operate in")
 	f.StringVar(&c.envName, "environment", "", "juju environment to operate in")
+	f.StringVar(&c.confirmationToken, "confirmation-token", "", "the environment UUID, required to destroy a destroy-protected environment")
 }
 
 func (c *DestroyEnvironmentCommand) Init(args []string) error {
@@ -84,6 +97,9 @@ func (c *DestroyEnvironmentCommand) Run(ctx *cmd.Context) (result error) {
 		}
 		return err
 	}
+	if err := c.checkConfirmationToken(environ.Config()); err != nil {
+		return err
+	}
 	if !c.assumeYes {
 		fmt.Fprintf(ctx.Stdout, destroyEnvMsg, c.envName, environ.Config().Type())
 
@@ -123,7 +139,7 @@ to be cleaned up.
 			return fmt.Errorf("cannot connect to API: %v", err)
 		}
 		defer apiclient.Close()
-		err = apiclient.DestroyEnvironment()
+		err = apiclient.DestroyEnvironmentWithToken(c.confirmationToken)
 		if err != nil && !params.IsCodeNotImplemented(err) {
 			return fmt.Errorf("destroying environment: %v", err)
 		}
@@ -131,6 +147,26 @@ to be cleaned up.
 	return environs.Destroy(environ, store)
 }
 
+// checkConfirmationToken checks the confirmation token against the
+// environment's local configuration. The API server performs the same
+// check against the environment's current configuration.
+func (c *DestroyEnvironmentCommand) checkConfirmationToken(cfg *config.Config) error {
+	if !cfg.DestroyProtected() {
+		return nil
+	}
+	uuid, ok := cfg.UUID()
+	if !ok {
+		if c.force {
+			return errors.New("cannot verify confirmation token for destroy-protected environment without its UUID")
+		}
+		return nil
+	}
+	if c.confirmationToken != uuid {
+		return errors.New("environment is destroy-protected: the environment UUID must be given with --confirmation-token")
+	}
+	return nil
+}
+
 var destroyEnvMsg = `
 WARNING! this command will destroy the %q environment (type: %s)
 This includes all machines, services, data and other resources.
@@ -45,6 +45,24 @@ func (s *destroyEnvSuite) TestDestroyEnvironmentCommand(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *destroyEnvSuite) TestDestroyEnvironmentCommandProtected(c *gc.C) {
+	env, err := environs.PrepareFromName("dummyenv", cmdtesting.NullContext(c), s.ConfigStore)
+	c.Assert(err, gc.IsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"destroy-protected": true}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	// Without the confirmation token, the API server refuses.
+	opc, errc := cmdtesting.RunCommand(cmdtesting.NullContext(c), new(DestroyEnvironmentCommand), "dummyenv", "--yes")
+	c.Check(<-errc, gc.ErrorMatches, "destroying environment: environment is destroy-protected: .*")
+	c.Check(<-opc, gc.IsNil)
+	assertEnvironNotDestroyed(c, env, s.ConfigStore)
+
+	opc, errc = cmdtesting.RunCommand(cmdtesting.NullContext(c), new(DestroyEnvironmentCommand),
+		"dummyenv", "--yes", "--confirmation-token", s.State.EnvironTag().Id())
+	c.Check(<-errc, gc.IsNil)
+	c.Check((<-opc).(dummy.OpDestroy).Env, gc.Equals, "dummyenv")
+}
+
 func (s *destroyEnvSuite) TestDestroyEnvironmentCommandEFlag(c *gc.C) {
 	// Prepare the environment so we can destroy it.
 	_, err := environs.PrepareFromName("dummyenv", cmdtesting.NullContext(c), s.ConfigStore)
@@ -5,20 +5,25 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/juju/cmd"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/utils/anonymize"
 )
 
 // GetEnvironmentCommand is able to output either the entire environment or
 // the requested value in a format of the user's choosing.
 type GetEnvironmentCommand struct {
 	envcmd.EnvCommandBase
-	key string
-	out cmd.Output
+	key         string
+	anonymize   bool
+	showSecrets bool
+	out         cmd.Output
 }
 
 const getEnvHelpDoc = `
@@ -28,9 +33,17 @@ for the environment are output using the selected formatter.
 A single environment value can be output by adding the environment key name to
 the end of the command line.
 
+Values of settings that hold credentials, such as a provider's access
+keys, are shown as REDACTED unless --show-secrets is given.
+
+With --anonymize, certificates and keys are replaced with a placeholder
+as well as the values of secret settings, even if --show-secrets is
+given, so that the output can be attached to a public bug report.
+
 Example:
   
   juju get-environment default-series  (returns the default series for the environment)
+  juju get-environment --anonymize     (returns all values, with secrets removed)
 `
 
 func (c *GetEnvironmentCommand) Info() *cmd.Info {
@@ -45,6 +58,8 @@ func (c *GetEnvironmentCommand) Info() *cmd.Info {
 
 func (c *GetEnvironmentCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.anonymize, "anonymize", false, "replace secret values with a placeholder")
+	f.BoolVar(&c.showSecrets, "show-secrets", false, "show the values of secret settings")
 }
 
 func (c *GetEnvironmentCommand) Init(args []string) (err error) {
@@ -64,6 +79,24 @@ func (c *GetEnvironmentCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	if c.anonymize || !c.showSecrets {
+		schema, err := client.EnvironmentSchema()
+		if err != nil && !params.IsCodeNotImplemented(err) {
+			return err
+		}
+		isSecret := func(key string) bool {
+			return schema[key].Secret
+		}
+		if c.anonymize {
+			attrs = anonymize.Config(attrs, isSecret)
+		} else {
+			for key, value := range attrs {
+				if isSecret(key) && value != "" {
+					attrs[key] = anonymize.Redacted
+				}
+			}
+		}
+	}
 	if c.key != "" {
 		if value, found := attrs[c.key]; found {
 			return c.out.Write(ctx, value)
@@ -127,9 +160,44 @@ func (c *SetEnvironmentCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
+	schema, err := client.EnvironmentSchema()
+	if params.IsCodeNotImplemented(err) {
+		// Older servers cannot describe their configuration, so
+		// leave all validation to them.
+		schema = nil
+	} else if err != nil {
+		return err
+	}
+	if err := validateEnvironmentValues(c.values, schema); err != nil {
+		return err
+	}
 	return client.EnvironmentSet(c.values)
 }
 
+// validateEnvironmentValues checks that each of the given values can be
+// parsed as the type the environment schema expects. Attributes that
+// are not in the schema are left for the server to check.
+func validateEnvironmentValues(values attributes, schema map[string]params.EnvironConfigAttribute) error {
+	for key, value := range values {
+		attr, ok := schema[key]
+		if !ok {
+			continue
+		}
+		s, _ := value.(string)
+		var err error
+		switch attr.Type {
+		case "int":
+			_, err = strconv.Atoi(s)
+		case "bool":
+			_, err = strconv.ParseBool(s)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s: expected %s", s, key, attr.Type)
+		}
+	}
+	return nil
+}
+
 // UnsetEnvironment
 type UnsetEnvironmentCommand struct {
 	envcmd.EnvCommandBase
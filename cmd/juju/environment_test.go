@@ -77,6 +77,30 @@ func (s *GetEnvironmentSuite) TestAllValues(c *gc.C) {
 	}
 }
 
+func (s *GetEnvironmentSuite) TestSecretsRedacted(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&GetEnvironmentCommand{}), "secret")
+	c.Assert(err, gc.IsNil)
+	c.Assert(strings.TrimSpace(testing.Stdout(context)), gc.Equals, "REDACTED")
+
+	context, err = testing.RunCommand(c, envcmd.Wrap(&GetEnvironmentCommand{}), "--show-secrets", "secret")
+	c.Assert(err, gc.IsNil)
+	c.Assert(strings.TrimSpace(testing.Stdout(context)), gc.Equals, "pork")
+}
+
+func (s *GetEnvironmentSuite) TestAnonymize(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&GetEnvironmentCommand{}), "--anonymize", "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	output := testing.Stdout(context)
+	c.Check(output, gc.Matches, `(?s).*\nca-cert: REDACTED\n.*`)
+	c.Check(output, gc.Matches, `(?s).*\nsecret: REDACTED\n.*`)
+	c.Check(output, gc.Matches, `(?s).*\nname: dummyenv\n.*`)
+	c.Check(strings.Contains(output, "BEGIN CERTIFICATE"), jc.IsFalse)
+
+	context, err = testing.RunCommand(c, envcmd.Wrap(&GetEnvironmentCommand{}), "--anonymize", "ca-cert")
+	c.Assert(err, gc.IsNil)
+	c.Assert(strings.TrimSpace(testing.Stdout(context)), gc.Equals, "REDACTED")
+}
+
 type SetEnvironmentSuite struct {
 	jujutesting.RepoSuite
 }
@@ -183,6 +207,13 @@ var immutableConfigTests = map[string]string{
 	"api-port":      "666",
 }
 
+func (s *SetEnvironmentSuite) TestInvalidValueTypes(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&SetEnvironmentCommand{}), "ssl-hostname-verification=maybe")
+	c.Assert(err, gc.ErrorMatches, `invalid value "maybe" for ssl-hostname-verification: expected bool`)
+	_, err = testing.RunCommand(c, envcmd.Wrap(&SetEnvironmentCommand{}), "hook-timeout=soon")
+	c.Assert(err, gc.ErrorMatches, `invalid value "soon" for hook-timeout: expected int`)
+}
+
 func (s *SetEnvironmentSuite) TestImmutableConfigValues(c *gc.C) {
 	for name, value := range immutableConfigTests {
 		param := fmt.Sprintf("%s=%s", name, value)
@@ -0,0 +1,210 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju"
+)
+
+const environmentsDoc = `
+List all the environments defined in environments.yaml or known from
+previous bootstraps, with the API addresses last cached for each of them.
+The current environment is marked with an asterisk.
+
+With --status, each bootstrapped environment is queried concurrently and
+a one-line summary of its health is shown.
+
+See Also:
+    juju switch
+`
+
+// EnvironmentsCommand lists the configured environments.
+type EnvironmentsCommand struct {
+	cmd.CommandBase
+	out    cmd.Output
+	status bool
+}
+
+// EnvironmentInfo holds the details of an environment shown by
+// EnvironmentsCommand.
+type EnvironmentInfo struct {
+	Name      string   `yaml:"name" json:"name"`
+	Current   bool     `yaml:"current,omitempty" json:"current,omitempty"`
+	Addresses []string `yaml:"addresses,omitempty" json:"addresses,omitempty"`
+	Status    string   `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+func (c *EnvironmentsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "environments",
+		Purpose: "list configured environments",
+		Doc:     environmentsDoc,
+	}
+}
+
+func (c *EnvironmentsCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.status, "status", false, "query each bootstrapped environment for a status summary")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatEnvironmentsTabular,
+	})
+}
+
+func (c *EnvironmentsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *EnvironmentsCommand) Run(ctx *cmd.Context) error {
+	store, err := configstore.Default()
+	if err != nil {
+		return errors.Annotate(err, "cannot open environment info storage")
+	}
+	endpoints, err := store.APIEndpoints()
+	if err != nil {
+		return errors.Annotate(err, "cannot read API endpoints")
+	}
+	current := os.Getenv("JUJU_ENV")
+	if current == "" {
+		current = envcmd.ReadCurrentEnvironment()
+	}
+
+	known := make(map[string]bool)
+	for name := range endpoints {
+		known[name] = true
+	}
+	// Passing through the empty string reads the default environments.yaml file.
+	environments, err := environs.ReadEnvirons("")
+	if err == nil {
+		for _, name := range environments.Names() {
+			known[name] = true
+		}
+		if current == "" {
+			current = environments.Default
+		}
+	} else if !environs.IsNoEnv(err) {
+		return err
+	}
+
+	infos := make([]EnvironmentInfo, 0, len(known))
+	for name := range known {
+		infos = append(infos, EnvironmentInfo{
+			Name:      name,
+			Current:   name == current,
+			Addresses: endpoints[name].Addresses,
+		})
+	}
+	sort.Sort(environmentInfos(infos))
+	if c.status {
+		queryEnvironmentStatuses(infos)
+	}
+	return c.out.Write(ctx, infos)
+}
+
+// queryEnvironmentStatuses concurrently fills in the status summary
+// of each of the given environments.
+func queryEnvironmentStatuses(infos []EnvironmentInfo) {
+	var wg sync.WaitGroup
+	for i := range infos {
+		info := &infos[i]
+		if len(info.Addresses) == 0 {
+			info.Status = "not bootstrapped"
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summary, err := environmentStatusSummary(info.Name)
+			if err != nil {
+				summary = fmt.Sprintf("error: %v", err)
+			}
+			info.Status = summary
+		}()
+	}
+	wg.Wait()
+}
+
+// environmentStatusSummary connects to the named environment and
+// returns a one-line summary of its status. It is a variable so
+// that it can be replaced for testing.
+var environmentStatusSummary = func(envName string) (string, error) {
+	client, err := juju.NewAPIClientFromName(envName)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	status, err := client.Status(nil)
+	if err != nil {
+		return "", err
+	}
+	var units, errored int
+	for _, machine := range status.Machines {
+		if machine.Agent.Status == params.StatusError {
+			errored++
+		}
+	}
+	for _, service := range status.Services {
+		for _, unit := range service.Units {
+			units++
+			if unit.Agent.Status == params.StatusError {
+				errored++
+			}
+		}
+	}
+	summary := "ok"
+	if errored > 0 {
+		summary = fmt.Sprintf("%d agents in error", errored)
+	}
+	return fmt.Sprintf("%s (%d machines, %d services, %d units)",
+		summary, len(status.Machines), len(status.Services), units), nil
+}
+
+func formatEnvironmentsTabular(value interface{}) ([]byte, error) {
+	infos, ok := value.([]EnvironmentInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", infos, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "NAME\tADDRESSES\tSTATUS\n")
+	for _, info := range infos {
+		name := info.Name
+		if info.Current {
+			name += "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, strings.Join(info.Addresses, ","), info.Status)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+type environmentInfos []EnvironmentInfo
+
+func (e environmentInfos) Len() int           { return len(e) }
+func (e environmentInfos) Less(i, j int) bool { return e[i].Name < e[j].Name }
+func (e environmentInfos) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
@@ -0,0 +1,91 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"os"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/testing"
+)
+
+type EnvironmentsSuite struct {
+	testing.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&EnvironmentsSuite{})
+
+func (s *EnvironmentsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	testing.WriteEnvironments(c, testing.MultipleEnvConfig)
+	s.writeEnvironInfo(c, "erewhemos-2", "10.0.0.1:17070")
+	s.writeEnvironInfo(c, "other", "10.0.0.2:17070", "10.0.0.3:17070")
+}
+
+func (s *EnvironmentsSuite) writeEnvironInfo(c *gc.C, envName string, addresses ...string) {
+	store, err := configstore.Default()
+	c.Assert(err, gc.IsNil)
+	info := store.CreateInfo(envName)
+	info.SetAPIEndpoint(configstore.APIEndpoint{Addresses: addresses})
+	err = info.Write()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *EnvironmentsSuite) TestInit(c *gc.C) {
+	_, err := testing.RunCommand(c, &EnvironmentsCommand{}, "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func (s *EnvironmentsSuite) TestList(c *gc.C) {
+	context, err := testing.RunCommand(c, &EnvironmentsCommand{}, "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+- name: erewhemos
+  current: true
+- name: erewhemos-2
+  addresses:
+  - 10.0.0.1:17070
+- name: other
+  addresses:
+  - 10.0.0.2:17070
+  - 10.0.0.3:17070
+`[1:])
+}
+
+func (s *EnvironmentsSuite) TestListJujuEnv(c *gc.C) {
+	os.Setenv("JUJU_ENV", "other")
+	context, err := testing.RunCommand(c, &EnvironmentsCommand{}, "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+- name: erewhemos
+- name: erewhemos-2
+  addresses:
+  - 10.0.0.1:17070
+- name: other
+  current: true
+  addresses:
+  - 10.0.0.2:17070
+  - 10.0.0.3:17070
+`[1:])
+}
+
+func (s *EnvironmentsSuite) TestStatus(c *gc.C) {
+	s.PatchValue(&environmentStatusSummary, func(envName string) (string, error) {
+		if envName == "other" {
+			return "", errors.New("connection refused")
+		}
+		return "ok (1 machines, 0 services, 0 units)", nil
+	})
+	context, err := testing.RunCommand(c, &EnvironmentsCommand{}, "--status")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"NAME         ADDRESSES                      STATUS\n"+
+		"erewhemos*                                  not bootstrapped\n"+
+		"erewhemos-2  10.0.0.1:17070                 ok (1 machines, 0 services, 0 units)\n"+
+		"other        10.0.0.2:17070,10.0.0.3:17070  error: connection refused\n",
+	)
+}
@@ -0,0 +1,100 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const exportBundleDoc = `
+Write the current environment's services as a bundle that can be
+deployed with "juju deploy-bundle", to reproduce the environment
+elsewhere or to recover it.
+
+The bundle holds each service's charm, including its revision, the
+settings that differ from the charm's defaults, its constraints, its
+number of units and whether it is exposed, along with the relations
+between services.
+
+Units on machines of their own are deployed to new machines. Units in
+containers, or that share a machine with units of another service, are
+placed on the same machine ids, which must exist in the environment
+the bundle is deployed to.
+
+Examples:
+   juju export-bundle -o production-bundle.yaml
+
+See Also:
+   juju deploy-bundle
+`
+
+// ExportBundleCommand writes the environment's services as a bundle.
+type ExportBundleCommand struct {
+	envcmd.EnvCommandBase
+	OutPath string
+}
+
+func (c *ExportBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-bundle",
+		Purpose: "write the environment's services as a deployable bundle",
+		Doc:     exportBundleDoc,
+	}
+}
+
+func (c *ExportBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.OutPath, "o", "", "the file to write the bundle to (defaults to stdout)")
+	f.StringVar(&c.OutPath, "output", "", "")
+}
+
+func (c *ExportBundleCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// ExportBundleAPI defines the API methods that the export-bundle
+// command uses.
+type ExportBundleAPI interface {
+	ExportBundle() (string, error)
+	Close() error
+}
+
+var getExportBundleAPI = func(c *ExportBundleCommand) (ExportBundleAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *ExportBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := getExportBundleAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	data, err := client.ExportBundle()
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("export-bundle is not supported by the API server")
+	}
+	if err != nil {
+		return err
+	}
+	if c.OutPath == "" {
+		_, err := ctx.Stdout.Write([]byte(data))
+		return err
+	}
+	path := ctx.AbsPath(c.OutPath)
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		return errors.Annotate(err, "cannot write bundle")
+	}
+	ctx.Infof("bundle written to %s", path)
+	return nil
+}
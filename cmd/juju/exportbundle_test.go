@@ -0,0 +1,75 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type ExportBundleSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockExportBundleAPI
+}
+
+var _ = gc.Suite(&ExportBundleSuite{})
+
+func (s *ExportBundleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockExportBundleAPI{data: "services: {}\n"}
+	s.PatchValue(&getExportBundleAPI, func(*ExportBundleCommand) (ExportBundleAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func (s *ExportBundleSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}), args...)
+}
+
+func (s *ExportBundleSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ExportBundleCommand{}, []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *ExportBundleSuite) TestExportToStdout(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "services: {}\n")
+}
+
+func (s *ExportBundleSuite) TestExportToFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	ctx, err := s.run(c, "-o", path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "services: {}\n")
+}
+
+func (s *ExportBundleSuite) TestExportFails(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockExportBundleAPI struct {
+	data string
+	err  error
+}
+
+func (m *mockExportBundleAPI) ExportBundle() (string, error) {
+	return m.data, m.err
+}
+
+func (*mockExportBundleAPI) Close() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/environmentmanager"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const exportEnvironmentDoc = `
+Write a description of the current environment, from which it can be
+recreated in another state server with "juju import-environment".
+
+The description holds the environment's configuration and users, and
+its charms, machines, services, units and relations. It also holds the
+credentials of the environment's agents, so it must be kept private.
+All the environment's entities must be alive when it is exported.
+
+Examples:
+   juju export-environment -o production.yaml
+
+See Also:
+   juju import-environment
+`
+
+// ExportEnvironmentCommand writes a description of an environment.
+type ExportEnvironmentCommand struct {
+	envcmd.EnvCommandBase
+	OutPath string
+}
+
+func (c *ExportEnvironmentCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-environment",
+		Purpose: "write a description of the environment for migration",
+		Doc:     exportEnvironmentDoc,
+	}
+}
+
+func (c *ExportEnvironmentCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.OutPath, "o", "", "the file to write the description to (defaults to stdout)")
+	f.StringVar(&c.OutPath, "output", "", "")
+}
+
+func (c *ExportEnvironmentCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// ExportEnvironmentAPI defines the API methods that the
+// export-environment command uses.
+type ExportEnvironmentAPI interface {
+	ExportEnvironment() ([]byte, error)
+	Close() error
+}
+
+var getExportEnvironmentAPI = func(c *ExportEnvironmentCommand) (ExportEnvironmentAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return environmentmanager.NewClient(root), nil
+}
+
+func (c *ExportEnvironmentCommand) Run(ctx *cmd.Context) error {
+	client, err := getExportEnvironmentAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	data, err := client.ExportEnvironment()
+	if err != nil {
+		return err
+	}
+	if c.OutPath == "" {
+		_, err := ctx.Stdout.Write(data)
+		return err
+	}
+	path := ctx.AbsPath(c.OutPath)
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.Annotate(err, "cannot write environment description")
+	}
+	ctx.Infof("environment description written to %s", path)
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type ExportEnvironmentSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockExportEnvironmentAPI
+}
+
+var _ = gc.Suite(&ExportEnvironmentSuite{})
+
+func (s *ExportEnvironmentSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockExportEnvironmentAPI{data: []byte("version: 1\n")}
+	s.PatchValue(&getExportEnvironmentAPI, func(*ExportEnvironmentCommand) (ExportEnvironmentAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func (s *ExportEnvironmentSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&ExportEnvironmentCommand{}), args...)
+}
+
+func (s *ExportEnvironmentSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ExportEnvironmentCommand{}, []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *ExportEnvironmentSuite) TestExportToStdout(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "version: 1\n")
+}
+
+func (s *ExportEnvironmentSuite) TestExportToFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "env.yaml")
+	ctx, err := s.run(c, "-o", path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "version: 1\n")
+}
+
+func (s *ExportEnvironmentSuite) TestExportFails(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockExportEnvironmentAPI struct {
+	data []byte
+	err  error
+}
+
+func (m *mockExportEnvironmentAPI) ExportEnvironment() ([]byte, error) {
+	return m.data, m.err
+}
+
+func (*mockExportEnvironmentAPI) Close() error {
+	return nil
+}
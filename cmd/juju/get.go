@@ -10,20 +10,42 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/utils/anonymize"
 )
 
 // GetCommand retrieves the configuration of a service.
 type GetCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	ValuesOnly  bool
+	Anonymize   bool
 	out         cmd.Output
 }
 
+const getDoc = `
+Show the configuration options of a service: for each option, its
+description, type and value, and whether the value is the charm's
+default or has been set by the user.
+
+With --values, only the user-set option values are shown, in the form
+accepted by "juju set --file", so that a service's configuration can be
+saved and applied again later:
+
+    juju get --values mysql > mysql.yaml
+    juju set --file mysql.yaml mysql
+
+With --anonymize, the values of options set by the user are replaced
+with a placeholder, so that the output can be attached to a public bug
+report. Charms do not say which of their options are secret, so none
+is assumed not to be; the charm's defaults are shown.
+`
+
 func (c *GetCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "get",
 		Args:    "<service>",
 		Purpose: "get service configuration options",
+		Doc:     getDoc,
 	}
 }
 
@@ -32,6 +54,8 @@ func (c *GetCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
 		"yaml": cmd.FormatYaml,
 	})
+	f.BoolVar(&c.ValuesOnly, "values", false, "show only user-set values, as accepted by juju set --file")
+	f.BoolVar(&c.Anonymize, "anonymize", false, "replace secret values with a placeholder")
 }
 
 func (c *GetCommand) Init(args []string) error {
@@ -57,10 +81,54 @@ func (c *GetCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	options := results.Config
+	if c.Anonymize {
+		options = anonymizeOptions(options)
+	}
+	if c.ValuesOnly {
+		return c.out.Write(ctx, userSetValues(options))
+	}
 	resultsMap := map[string]interface{}{
 		"service":  results.Service,
 		"charm":    results.Charm,
-		"settings": results.Config,
+		"settings": options,
 	}
 	return c.out.Write(ctx, resultsMap)
 }
+
+// userSetValues returns the values of the options, as described by
+// ServiceGet, that have been set by the user.
+func userSetValues(options map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	for name, option := range options {
+		info, ok := option.(map[string]interface{})
+		if !ok || info["default"] == true {
+			continue
+		}
+		values[name] = info["value"]
+	}
+	return values
+}
+
+// anonymizeOptions returns a copy of the options, as described by
+// ServiceGet, with the values set by the user replaced by a
+// placeholder.
+func anonymizeOptions(options map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(options))
+	for name, option := range options {
+		info, ok := option.(map[string]interface{})
+		if !ok {
+			result[name] = option
+			continue
+		}
+		anonymized := make(map[string]interface{}, len(info))
+		for key, value := range info {
+			anonymized[key] = value
+		}
+		if value, ok := info["value"]; ok {
+			anonymized["value"] = anonymize.Value(value, info["default"] != true)
+		}
+		result[name] = anonymized
+	}
+	return result
+}
@@ -36,6 +36,7 @@ var getTests = []struct {
 					"description": "A descriptive title used for the service.",
 					"type":        "string",
 					"value":       "Nearly There",
+					"default":     false,
 				},
 				"skill-level": map[string]interface{}{
 					"description": "A number indicating skill.",
@@ -86,3 +87,50 @@ func (s *GetSuite) TestGetConfig(c *gc.C) {
 		c.Assert(actual, gc.DeepEquals, expected)
 	}
 }
+
+func (s *GetSuite) TestGetValues(c *gc.C) {
+	sch := s.AddTestingCharm(c, "dummy")
+	svc := s.AddTestingService(c, "dummy-service", sch)
+	err := svc.UpdateConfigSettings(charm.Settings{"title": "Nearly There", "skill-level": 9000})
+	c.Assert(err, gc.IsNil)
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&GetCommand{}), "--values", "dummy-service")
+	c.Assert(err, gc.IsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "skill-level: 9000\ntitle: Nearly There\n")
+}
+
+func (s *GetSuite) TestAnonymizeOptions(c *gc.C) {
+	options := map[string]interface{}{
+		"title": map[string]interface{}{
+			"type":    "string",
+			"value":   "My Title",
+			"default": true,
+		},
+		"api-password": map[string]interface{}{
+			"type":    "string",
+			"value":   "hunter2",
+			"default": false,
+		},
+		"ssl-cert": map[string]interface{}{
+			"type":    "string",
+			"default": true,
+		},
+	}
+	c.Assert(anonymizeOptions(options), gc.DeepEquals, map[string]interface{}{
+		"title": map[string]interface{}{
+			"type":    "string",
+			"value":   "My Title",
+			"default": true,
+		},
+		"api-password": map[string]interface{}{
+			"type":    "string",
+			"value":   "REDACTED",
+			"default": false,
+		},
+		"ssl-cert": map[string]interface{}{
+			"type":    "string",
+			"default": true,
+		},
+	})
+	// The original options are unchanged.
+	c.Assert(options["api-password"].(map[string]interface{})["value"], gc.Equals, "hunter2")
+}
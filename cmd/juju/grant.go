@@ -0,0 +1,86 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state"
+)
+
+const grantDoc = `
+Grant a user read or write access to the current environment. The
+environment is shared with the user if it has not been already.
+
+A user with read access can view the environment's status and
+configuration, but cannot change it.
+
+Examples:
+   juju grant bob read
+   juju grant alice@local write
+
+See Also:
+   juju user add
+`
+
+// GrantCommand grants a user access to an environment.
+type GrantCommand struct {
+	envcmd.EnvCommandBase
+	UserName string
+	Access   state.EnvironmentAccess
+}
+
+func (c *GrantCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "grant",
+		Args:    "<user> <read|write>",
+		Purpose: "grant a user access to the environment",
+		Doc:     grantDoc,
+	}
+}
+
+func (c *GrantCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no user specified")
+	case 1:
+		return errors.New("no access level specified")
+	}
+	if !names.IsValidUser(args[0]) {
+		return errors.Errorf("invalid user name %q", args[0])
+	}
+	c.UserName = args[0]
+	c.Access = state.EnvironmentAccess(args[1])
+	if err := c.Access.Validate(); err != nil {
+		return err
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+// GrantEnvironmentAPI defines the client API methods that the grant
+// command uses.
+type GrantEnvironmentAPI interface {
+	GrantEnvironment(user names.UserTag, access string) error
+	Close() error
+}
+
+var getGrantEnvironmentAPI = func(c *GrantCommand) (GrantEnvironmentAPI, error) {
+	return c.NewAPIClient()
+}
+
+func (c *GrantCommand) Run(ctx *cmd.Context) error {
+	client, err := getGrantEnvironmentAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.GrantEnvironment(names.NewUserTag(c.UserName), string(c.Access)); err != nil {
+		return err
+	}
+	ctx.Infof("granted %s access to %s", c.Access, c.UserName)
+	return nil
+}
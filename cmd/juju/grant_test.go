@@ -0,0 +1,88 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type GrantSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockGrantAPI
+}
+
+var _ = gc.Suite(&GrantSuite{})
+
+func (s *GrantSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockGrantAPI{}
+	s.PatchValue(&getGrantEnvironmentAPI, func(*GrantCommand) (GrantEnvironmentAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func (s *GrantSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&GrantCommand{}), args...)
+}
+
+func (s *GrantSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no user specified",
+	}, {
+		args: []string{"bob"},
+		err:  "no access level specified",
+	}, {
+		args: []string{"not/valid", "read"},
+		err:  `invalid user name "not/valid"`,
+	}, {
+		args: []string{"bob", "admin"},
+		err:  `environment access "admin" not valid`,
+	}, {
+		args: []string{"bob", "read", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(&GrantCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *GrantSuite) TestGrant(c *gc.C) {
+	ctx, err := s.run(c, "bob", "read")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.user, gc.Equals, names.NewUserTag("bob"))
+	c.Assert(s.mockAPI.access, gc.Equals, "read")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "granted read access to bob\n")
+}
+
+func (s *GrantSuite) TestGrantFails(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := s.run(c, "bob", "write")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockGrantAPI struct {
+	user   names.UserTag
+	access string
+	err    error
+}
+
+func (m *mockGrantAPI) GrantEnvironment(user names.UserTag, access string) error {
+	m.user = user
+	m.access = access
+	return m.err
+}
+
+func (*mockGrantAPI) Close() error {
+	return nil
+}
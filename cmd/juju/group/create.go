@@ -0,0 +1,62 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package group
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+const createCommandDoc = `
+Create a named group of units, defined by one or more unit name patterns.
+A pattern is either a unit name or a service name, and may use "*" as a
+wildcard.
+
+Examples:
+  juju group create canary web/0 web/1
+  juju group create backends 'db-*' cache
+`
+
+// CreateCommand creates a unit group.
+type CreateCommand struct {
+	GroupCommandBase
+	Name     string
+	Patterns []string
+}
+
+// Info implements Command.Info.
+func (c *CreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<name> <pattern> [...]",
+		Purpose: "create a named group of units",
+		Doc:     createCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *CreateCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no group name specified")
+	case 1:
+		return errors.New("no unit patterns specified")
+	}
+	c.Name, c.Patterns = args[0], args[1:]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *CreateCommand) Run(ctx *cmd.Context) error {
+	client, err := getUnitGroupAPI(&c.GroupCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.AddUnitGroup(c.Name, c.Patterns...); err != nil {
+		return err
+	}
+	ctx.Infof("Unit group %q created", c.Name)
+	return nil
+}
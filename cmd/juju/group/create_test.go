@@ -0,0 +1,64 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package group_test
+
+import (
+	"errors"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/group"
+	"github.com/juju/juju/testing"
+)
+
+type CreateSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&CreateSuite{})
+
+func (s *CreateSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+		name     string
+		patterns []string
+	}{{
+		errMatch: "no group name specified",
+	}, {
+		args:     []string{"canary"},
+		errMatch: "no unit patterns specified",
+	}, {
+		args:     []string{"canary", "web/0", "web/1"},
+		name:     "canary",
+		patterns: []string{"web/0", "web/1"},
+	}} {
+		c.Logf("test %d, args %v", i, test.args)
+		command := &group.CreateCommand{}
+		err := testing.InitCommand(command, test.args)
+		if test.errMatch == "" {
+			c.Assert(err, gc.IsNil)
+			c.Assert(command.Name, gc.Equals, test.name)
+			c.Assert(command.Patterns, gc.DeepEquals, test.patterns)
+		} else {
+			c.Assert(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *CreateSuite) TestCreate(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&group.CreateCommand{}), "canary", "web/0", "web/1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Unit group \"canary\" created\n")
+	c.Assert(s.mock.groups, gc.DeepEquals, map[string][]string{
+		"canary": {"web/0", "web/1"},
+	})
+}
+
+func (s *CreateSuite) TestCreateError(c *gc.C) {
+	s.mock.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&group.CreateCommand{}), "canary", "web")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,6 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package group
+
+var GetUnitGroupAPI = &getUnitGroupAPI
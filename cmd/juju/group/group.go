@@ -0,0 +1,66 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package group
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const groupCommandDoc = `
+"juju group" is used to manage named groups of units. A unit group is
+defined by one or more unit name patterns, and may be used as the target
+of "juju run", "juju resolved", "juju set" and "juju action do" with the
+--group option, so that an operation can be staged across the same units
+repeatedly.
+
+A pattern is either a unit name, such as "web/0", or a service name, such
+as "web", which matches all the units of that service. Patterns may use
+"*" as a wildcard. The units in a group are evaluated each time the group
+is used, so a group defined by service name tracks units as they are
+added and removed.
+
+Examples:
+  juju group create canary web/0 web/1
+  juju run --group canary "hostname"
+`
+
+const groupCommandPurpose = "manage named groups of units"
+
+// NewSuperCommand creates the group supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	groupcmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "group",
+		Doc:         groupCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     groupCommandPurpose,
+	})
+	groupcmd.Register(envcmd.Wrap(&CreateCommand{}))
+	groupcmd.Register(envcmd.Wrap(&ListCommand{}))
+	groupcmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	return groupcmd
+}
+
+// UnitGroupAPI defines the API methods that the group subcommands use.
+type UnitGroupAPI interface {
+	AddUnitGroup(name string, patterns ...string) error
+	RemoveUnitGroup(name string) error
+	UnitGroups() ([]params.UnitGroup, error)
+	Close() error
+}
+
+// GroupCommandBase is a helper base structure that has a method to get
+// the unit group API.
+type GroupCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+func (c *GroupCommandBase) getUnitGroupAPI() (UnitGroupAPI, error) {
+	return c.NewAPIClient()
+}
+
+var getUnitGroupAPI = (*GroupCommandBase).getUnitGroupAPI
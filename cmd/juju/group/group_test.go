@@ -0,0 +1,115 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package group_test
+
+import (
+	"os"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/group"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/testing"
+)
+
+type GroupCommandSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&GroupCommandSuite{})
+
+var expectedGroupCommandNames = []string{
+	"create",
+	"help",
+	"list",
+	"remove",
+}
+
+func (s *GroupCommandSuite) TestHelp(c *gc.C) {
+	// Check the help output
+	ctx, err := testing.RunCommand(c, group.NewSuperCommand(), "--help")
+	c.Assert(err, gc.IsNil)
+
+	// Check that we have registered all the sub commands by
+	// inspecting the help output.
+	var namesFound []string
+	commandHelp := strings.SplitAfter(testing.Stdout(ctx), "commands:")[1]
+	commandHelp = strings.TrimSpace(commandHelp)
+	for _, line := range strings.Split(commandHelp, "\n") {
+		namesFound = append(namesFound, strings.TrimSpace(strings.Split(line, " - ")[0]))
+	}
+	c.Assert(namesFound, gc.DeepEquals, expectedGroupCommandNames)
+}
+
+type BaseSuite struct {
+	testing.BaseSuite
+	mock *mockUnitGroupAPI
+}
+
+func (s *BaseSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	memstore := configstore.NewMem()
+	s.PatchValue(&configstore.Default, func() (configstore.Storage, error) {
+		return memstore, nil
+	})
+	os.Setenv(osenv.JujuEnvEnvKey, "testing")
+	info := memstore.CreateInfo("testing")
+	info.SetBootstrapConfig(map[string]interface{}{"random": "extra data"})
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"localhost:12345"},
+		CACert:      testing.CACert,
+		EnvironUUID: "env-uuid",
+	})
+	info.SetAPICredentials(configstore.APICredentials{
+		User:     "user-test",
+		Password: "password",
+	})
+	err := info.Write()
+	c.Assert(err, gc.IsNil)
+	s.mock = &mockUnitGroupAPI{groups: make(map[string][]string)}
+	s.PatchValue(group.GetUnitGroupAPI, func(*group.GroupCommandBase) (group.UnitGroupAPI, error) {
+		return s.mock, nil
+	})
+}
+
+type mockUnitGroupAPI struct {
+	groups map[string][]string
+	err    error
+}
+
+var _ group.UnitGroupAPI = (*mockUnitGroupAPI)(nil)
+
+func (m *mockUnitGroupAPI) AddUnitGroup(name string, patterns ...string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.groups[name] = patterns
+	return nil
+}
+
+func (m *mockUnitGroupAPI) RemoveUnitGroup(name string) error {
+	if m.err != nil {
+		return m.err
+	}
+	delete(m.groups, name)
+	return nil
+}
+
+func (m *mockUnitGroupAPI) UnitGroups() ([]params.UnitGroup, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var result []params.UnitGroup
+	for name, patterns := range m.groups {
+		result = append(result, params.UnitGroup{Name: name, Patterns: patterns})
+	}
+	return result, nil
+}
+
+func (m *mockUnitGroupAPI) Close() error {
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package group
+
+import (
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const listCommandDoc = `
+List the named groups of units in the environment, with the unit name
+patterns that define them.
+`
+
+// ListCommand lists the unit groups.
+type ListCommand struct {
+	GroupCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list named groups of units",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	client, err := getUnitGroupAPI(&c.GroupCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	groups, err := client.UnitGroups()
+	if err != nil {
+		return err
+	}
+	result := make(map[string][]string)
+	for _, group := range groups {
+		result[group.Name] = group.Patterns
+	}
+	return c.out.Write(ctx, result)
+}
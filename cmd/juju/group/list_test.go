@@ -0,0 +1,42 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package group_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/group"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) TestList(c *gc.C) {
+	s.mock.groups["canary"] = []string{"web/0", "web/1"}
+	s.mock.groups["db"] = []string{"mysql"}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&group.ListCommand{}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"canary:\n"+
+		"- web/0\n"+
+		"- web/1\n"+
+		"db:\n"+
+		"- mysql\n")
+}
+
+func (s *ListSuite) TestListEmpty(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&group.ListCommand{}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "{}\n")
+}
+
+func (s *ListSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&group.ListCommand{}, []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
@@ -0,0 +1,55 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package group
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+const removeCommandDoc = `
+Remove a named group of units. The units in the group are not affected.
+
+Examples:
+  juju group remove canary
+`
+
+// RemoveCommand removes a unit group.
+type RemoveCommand struct {
+	GroupCommandBase
+	Name string
+}
+
+// Info implements Command.Info.
+func (c *RemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<name>",
+		Purpose: "remove a named group of units",
+		Doc:     removeCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *RemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no group name specified")
+	}
+	c.Name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *RemoveCommand) Run(ctx *cmd.Context) error {
+	client, err := getUnitGroupAPI(&c.GroupCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.RemoveUnitGroup(c.Name); err != nil {
+		return err
+	}
+	ctx.Infof("Unit group %q removed", c.Name)
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package group_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/group"
+	"github.com/juju/juju/testing"
+)
+
+type RemoveSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&RemoveSuite{})
+
+func (s *RemoveSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&group.RemoveCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no group name specified")
+	err = testing.InitCommand(&group.RemoveCommand{}, []string{"canary", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *RemoveSuite) TestRemove(c *gc.C) {
+	s.mock.groups["canary"] = []string{"web/0"}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&group.RemoveCommand{}), "canary")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Unit group \"canary\" removed\n")
+	c.Assert(s.mock.groups, gc.HasLen, 0)
+}
@@ -4,7 +4,9 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/juju/cmd"
@@ -31,6 +33,9 @@ func (dummyHookContext) PublicAddress() (string, bool) {
 func (dummyHookContext) PrivateAddress() (string, bool) {
 	return "", false
 }
+func (dummyHookContext) MachineAddresses() []network.Address {
+	return nil
+}
 func (dummyHookContext) OpenPort(protocol string, port int) error {
 	return nil
 }
@@ -62,17 +67,34 @@ func (dummyHookContext) OwnerTag() string {
 
 type HelpToolCommand struct {
 	cmd.CommandBase
-	tool string
+	tool   string
+	schema bool
 }
 
+const helpToolDoc = `
+With no arguments, help-tool lists the tools available to charm hooks;
+given a tool name, it shows that tool's help.
+
+With --schema, a JSON description of the tools (or of the named tool) is
+printed instead, including each tool's arguments and flags. The
+description is generated from the tools juju actually provides, so
+charm helper libraries can use it to stay in sync.
+`
+
 func (t *HelpToolCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "help-tool",
 		Args:    "[tool]",
 		Purpose: "show help on a juju charm tool",
+		Doc:     helpToolDoc,
+		Aliases: []string{"hook-tools"},
 	}
 }
 
+func (t *HelpToolCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&t.schema, "schema", false, "print a JSON description of the tools")
+}
+
 func (t *HelpToolCommand) Init(args []string) error {
 	tool, err := cmd.ZeroOrOneArgs(args)
 	if err == nil {
@@ -81,8 +103,74 @@ func (t *HelpToolCommand) Init(args []string) error {
 	return err
 }
 
+// toolSchema describes a hook tool.
+type toolSchema struct {
+	Name    string           `json:"name"`
+	Args    string           `json:"args,omitempty"`
+	Purpose string           `json:"purpose"`
+	Doc     string           `json:"doc,omitempty"`
+	Aliases []string         `json:"aliases,omitempty"`
+	Flags   []toolFlagSchema `json:"flags"`
+}
+
+// toolFlagSchema describes a single flag of a hook tool.
+type toolFlagSchema struct {
+	Name    string `json:"name"`
+	Usage   string `json:"usage"`
+	Default string `json:"default"`
+}
+
+// newToolSchema returns the description of the given hook tool.
+func newToolSchema(c cmd.Command) toolSchema {
+	info := c.Info()
+	f := gnuflag.NewFlagSet(info.Name, gnuflag.ContinueOnError)
+	c.SetFlags(f)
+	schema := toolSchema{
+		Name:    info.Name,
+		Args:    info.Args,
+		Purpose: info.Purpose,
+		Doc:     strings.TrimSpace(info.Doc),
+		Aliases: info.Aliases,
+		Flags:   []toolFlagSchema{},
+	}
+	f.VisitAll(func(flag *gnuflag.Flag) {
+		schema.Flags = append(schema.Flags, toolFlagSchema{
+			Name:    flag.Name,
+			Usage:   flag.Usage,
+			Default: flag.DefValue,
+		})
+	})
+	return schema
+}
+
+func (c *HelpToolCommand) writeSchema(ctx *cmd.Context, hookctx jujuc.Context) error {
+	var names []string
+	if c.tool == "" {
+		names = jujuc.CommandNames()
+	} else {
+		names = []string{c.tool}
+	}
+	tools := make([]toolSchema, 0, len(names))
+	for _, name := range names {
+		tool, err := jujuc.NewCommand(hookctx, name)
+		if err != nil {
+			return err
+		}
+		tools = append(tools, newToolSchema(tool))
+	}
+	data, err := json.MarshalIndent(tools, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "%s\n", data)
+	return nil
+}
+
 func (c *HelpToolCommand) Run(ctx *cmd.Context) error {
 	var hookctx dummyHookContext
+	if c.schema {
+		return c.writeSchema(ctx, hookctx)
+	}
 	if c.tool == "" {
 		// Ripped from SuperCommand. We could Run() a SuperCommand
 		// with "help commands", but then the implicit "help" command
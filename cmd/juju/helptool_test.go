@@ -4,8 +4,10 @@
 package main
 
 import (
+	"encoding/json"
 	"strings"
 
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/testing"
@@ -20,8 +22,9 @@ var _ = gc.Suite(&HelpToolSuite{})
 
 func (suite *HelpToolSuite) TestHelpToolHelp(c *gc.C) {
 	output := badrun(c, 0, "help", "help-tool")
-	c.Assert(output, gc.Equals, `usage: juju help-tool [tool]
+	c.Assert(output, gc.Matches, `usage: juju help-tool \[options\] \[tool\]
 purpose: show help on a juju charm tool
+(.|\n)*--schema(.|\n)*aliases: hook-tools
 `)
 }
 
@@ -45,3 +48,44 @@ options:
 relation-get prints the value(.|\n)*`
 	c.Assert(output, gc.Matches, expectedHelp)
 }
+
+func (suite *HelpToolSuite) TestHelpToolSchema(c *gc.C) {
+	output := badrun(c, 0, "hook-tools", "--schema")
+	var tools []map[string]interface{}
+	err := json.Unmarshal([]byte(output), &tools)
+	c.Assert(err, gc.IsNil)
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool["name"].(string)
+	}
+	c.Assert(names, gc.DeepEquals, jujuc.CommandNames())
+}
+
+func (suite *HelpToolSuite) TestHelpToolSchemaName(c *gc.C) {
+	output := badrun(c, 0, "help-tool", "--schema", "relation-get")
+	var tools []struct {
+		Name    string
+		Args    string
+		Purpose string
+		Flags   []struct{ Name, Usage, Default string }
+	}
+	err := json.Unmarshal([]byte(output), &tools)
+	c.Assert(err, gc.IsNil)
+	c.Assert(tools, gc.HasLen, 1)
+	tool := tools[0]
+	c.Assert(tool.Name, gc.Equals, "relation-get")
+	c.Assert(tool.Args, gc.Equals, "<key> <unit id>")
+	c.Assert(tool.Purpose, gc.Equals, "get relation settings")
+	flags := make(map[string]string)
+	for _, flag := range tool.Flags {
+		flags[flag.Name] = flag.Default
+	}
+	_, ok := flags["r"]
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(flags["format"], gc.Equals, "smart")
+}
+
+func (suite *HelpToolSuite) TestHelpToolSchemaUnknownTool(c *gc.C) {
+	output := badrun(c, 1, "help-tool", "--schema", "no-such-tool")
+	c.Assert(output, gc.Matches, "error: .*no-such-tool.*\n")
+}
@@ -0,0 +1,164 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/environmentmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/migration"
+)
+
+const importEnvironmentDoc = `
+Recreate an environment written by "juju export-environment" in the
+state server of the current environment. The imported environment
+keeps its name and UUID, and is added to the client's known
+environments.
+
+If --source names the environment as it is known on its original state
+server, that environment's agents are told to reconnect to the new
+state server, and the client's details of the environment are replaced
+by those of the imported one. The agents reconnect when they next
+check for upgrades.
+
+Examples:
+   juju import-environment -e new-server --source production production.yaml
+
+See Also:
+   juju export-environment
+`
+
+// ImportEnvironmentCommand recreates an exported environment.
+type ImportEnvironmentCommand struct {
+	envcmd.EnvCommandBase
+	Path   string
+	Source string
+}
+
+func (c *ImportEnvironmentCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "import-environment",
+		Args:    "<file>",
+		Purpose: "recreate an exported environment on the current state server",
+		Doc:     importEnvironmentDoc,
+	}
+}
+
+func (c *ImportEnvironmentCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Source, "source", "", "the environment on the original state server, whose agents should be moved")
+}
+
+func (c *ImportEnvironmentCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no environment description specified")
+	}
+	c.Path = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// ImportEnvironmentAPI defines the API methods that the
+// import-environment command uses.
+type ImportEnvironmentAPI interface {
+	ImportEnvironment(description []byte) (params.ImportedEnvironment, error)
+	Close() error
+}
+
+var getImportEnvironmentAPI = func(c *ImportEnvironmentCommand) (ImportEnvironmentAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return environmentmanager.NewClient(root), nil
+}
+
+// MigrationSourceAPI defines the API methods that the
+// import-environment command uses on the original environment.
+type MigrationSourceAPI interface {
+	SetMigrationTarget(target params.MigrationTarget) error
+	Close() error
+}
+
+var getMigrationSourceAPI = func(c *ImportEnvironmentCommand) (MigrationSourceAPI, error) {
+	root, err := juju.NewAPIFromName(c.Source)
+	if err != nil {
+		return nil, err
+	}
+	return environmentmanager.NewClient(root), nil
+}
+
+func (c *ImportEnvironmentCommand) Run(ctx *cmd.Context) error {
+	data, err := ioutil.ReadFile(ctx.AbsPath(c.Path))
+	if err != nil {
+		return errors.Annotate(err, "cannot read environment description")
+	}
+	model, err := migration.Deserialize(data)
+	if err != nil {
+		return err
+	}
+	name, _ := model.Config["name"].(string)
+	store, err := configstore.Default()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var sourceInfo configstore.EnvironInfo
+	if c.Source != "" {
+		if sourceInfo, err = store.ReadInfo(c.Source); err != nil {
+			return errors.Annotatef(err, "cannot read details of environment %q", c.Source)
+		}
+		uuid, _ := model.Config["uuid"].(string)
+		if sourceInfo.APIEndpoint().EnvironUUID != uuid {
+			return errors.Errorf("environment %q is not the exported environment", c.Source)
+		}
+	}
+	if c.Source != name {
+		if err := checkNoEnvironmentInfo(store, name); err != nil {
+			return err
+		}
+	}
+	client, err := getImportEnvironmentAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	imported, err := client.ImportEnvironment(data)
+	if err != nil {
+		return err
+	}
+	env := imported.Environment
+	ctx.Infof("imported environment %q (%s)", env.Name, env.UUID)
+	if c.Source != "" {
+		if err := c.moveAgents(imported.Target); err != nil {
+			return err
+		}
+		ctx.Infof("agents of environment %q told to move to the new state server", c.Source)
+		if c.Source == name {
+			if err := sourceInfo.Destroy(); err != nil {
+				return errors.Annotatef(err, "cannot remove details of environment %q", name)
+			}
+		}
+	}
+	return writeHostedEnvironmentInfo(store, &c.EnvCommandBase, env)
+}
+
+// moveAgents tells the agents of the source environment to connect to
+// the given state server.
+func (c *ImportEnvironmentCommand) moveAgents(target params.MigrationTarget) error {
+	client, err := getMigrationSourceAPI(c)
+	if err != nil {
+		return errors.Annotatef(err, "cannot connect to environment %q", c.Source)
+	}
+	defer client.Close()
+	if err := client.SetMigrationTarget(target); err != nil {
+		return errors.Annotatef(err, "cannot move agents of environment %q", c.Source)
+	}
+	return nil
+}
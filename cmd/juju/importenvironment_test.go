@@ -0,0 +1,195 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type ImportEnvironmentSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI    *mockImportEnvironmentAPI
+	mockSource *mockMigrationSourceAPI
+	store      configstore.Storage
+	path       string
+}
+
+var _ = gc.Suite(&ImportEnvironmentSuite{})
+
+const importedEnvironment = `
+version: 1
+owner: bob@local
+config:
+  name: production
+  uuid: deadbeef-0bad-400d-8000-4b1d0d06f00e
+`
+
+func (s *ImportEnvironmentSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockImportEnvironmentAPI{}
+	s.PatchValue(&getImportEnvironmentAPI, func(*ImportEnvironmentCommand) (ImportEnvironmentAPI, error) {
+		return s.mockAPI, nil
+	})
+	s.mockSource = &mockMigrationSourceAPI{}
+	s.PatchValue(&getMigrationSourceAPI, func(c *ImportEnvironmentCommand) (MigrationSourceAPI, error) {
+		s.mockSource.name = c.Source
+		return s.mockSource, nil
+	})
+	var err error
+	s.store, err = configstore.Default()
+	c.Assert(err, gc.IsNil)
+	info := s.store.CreateInfo(testing.SampleEnvName)
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"127.0.0.1:12345"},
+		CACert:      testing.CACert,
+		EnvironUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+	})
+	info.SetAPICredentials(configstore.APICredentials{
+		User:     "admin",
+		Password: "sekrit",
+	})
+	err = info.Write()
+	c.Assert(err, gc.IsNil)
+
+	s.path = filepath.Join(c.MkDir(), "env.yaml")
+	err = ioutil.WriteFile(s.path, []byte(importedEnvironment), 0600)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *ImportEnvironmentSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&ImportEnvironmentCommand{}), args...)
+}
+
+func (s *ImportEnvironmentSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ImportEnvironmentCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no environment description specified")
+	err = testing.InitCommand(&ImportEnvironmentCommand{}, []string{"a", "b"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+func (s *ImportEnvironmentSuite) TestImport(c *gc.C) {
+	ctx, err := s.run(c, s.path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(s.mockAPI.data), gc.Equals, importedEnvironment)
+	c.Assert(testing.Stderr(ctx), gc.Equals,
+		`imported environment "production" (deadbeef-0bad-400d-8000-4b1d0d06f00e)`+"\n")
+
+	info, err := s.store.ReadInfo("production")
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.APIEndpoint().EnvironUUID, gc.Equals, "deadbeef-0bad-400d-8000-4b1d0d06f00e")
+	c.Assert(info.APIEndpoint().Addresses, gc.DeepEquals, []string{"127.0.0.1:12345"})
+	c.Assert(info.APICredentials().User, gc.Equals, "admin")
+}
+
+func (s *ImportEnvironmentSuite) TestImportFromSource(c *gc.C) {
+	info := s.store.CreateInfo("production")
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"10.0.0.1:17070"},
+		CACert:      "old-cert",
+		EnvironUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00e",
+	})
+	err := info.Write()
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := s.run(c, "--source", "production", s.path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals,
+		`imported environment "production" (deadbeef-0bad-400d-8000-4b1d0d06f00e)`+"\n"+
+			`agents of environment "production" told to move to the new state server`+"\n")
+	c.Assert(s.mockSource.name, gc.Equals, "production")
+	c.Assert(s.mockSource.target, jc.DeepEquals, &params.MigrationTarget{
+		APIHostPorts: [][]network.HostPort{
+			network.AddressesWithPort(network.NewAddresses("127.0.0.1"), 12345),
+		},
+		CACert: testing.CACert,
+	})
+
+	info, err = s.store.ReadInfo("production")
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.APIEndpoint().Addresses, gc.DeepEquals, []string{"127.0.0.1:12345"})
+	c.Assert(info.APIEndpoint().CACert, gc.Equals, testing.CACert)
+}
+
+func (s *ImportEnvironmentSuite) TestImportFromOtherSource(c *gc.C) {
+	_, err := s.run(c, "--source", testing.SampleEnvName, s.path)
+	c.Assert(err, gc.ErrorMatches, `environment "erewhemos" is not the exported environment`)
+	c.Assert(s.mockAPI.data, gc.IsNil)
+}
+
+func (s *ImportEnvironmentSuite) TestImportKnownEnvironment(c *gc.C) {
+	info := s.store.CreateInfo("production")
+	err := info.Write()
+	c.Assert(err, gc.IsNil)
+	_, err = s.run(c, s.path)
+	c.Assert(err, gc.ErrorMatches, `environment "production" already exists`)
+	c.Assert(s.mockAPI.data, gc.IsNil)
+}
+
+func (s *ImportEnvironmentSuite) TestImportInvalidDescription(c *gc.C) {
+	err := ioutil.WriteFile(s.path, []byte("version: 2\n"), 0600)
+	c.Assert(err, gc.IsNil)
+	_, err = s.run(c, s.path)
+	c.Assert(err, gc.ErrorMatches, "environment description version 2 not supported")
+}
+
+func (s *ImportEnvironmentSuite) TestImportFails(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := s.run(c, s.path)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockImportEnvironmentAPI struct {
+	data []byte
+	err  error
+}
+
+func (m *mockImportEnvironmentAPI) ImportEnvironment(data []byte) (params.ImportedEnvironment, error) {
+	m.data = data
+	if m.err != nil {
+		return params.ImportedEnvironment{}, m.err
+	}
+	return params.ImportedEnvironment{
+		Environment: params.Environment{
+			Name:     "production",
+			UUID:     "deadbeef-0bad-400d-8000-4b1d0d06f00e",
+			OwnerTag: "user-bob@local",
+		},
+		Target: params.MigrationTarget{
+			APIHostPorts: [][]network.HostPort{
+				network.AddressesWithPort(network.NewAddresses("127.0.0.1"), 12345),
+			},
+			CACert: testing.CACert,
+		},
+	}, nil
+}
+
+func (*mockImportEnvironmentAPI) Close() error {
+	return nil
+}
+
+type mockMigrationSourceAPI struct {
+	name   string
+	target *params.MigrationTarget
+}
+
+func (m *mockMigrationSourceAPI) SetMigrationTarget(target params.MigrationTarget) error {
+	m.target = &target
+	return nil
+}
+
+func (*mockMigrationSourceAPI) Close() error {
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/network"
+)
+
+const listNetworksDoc = `
+List the provider networks known in the environment, which services can
+be bound to with "juju deploy --networks".
+
+See Also:
+   juju add-network
+`
+
+// ListNetworksCommand lists the networks known in the environment.
+type ListNetworksCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+// NetworkInfo holds the details of a network shown by
+// ListNetworksCommand.
+type NetworkInfo struct {
+	Name       string     `yaml:"name" json:"name"`
+	ProviderId network.Id `yaml:"provider-id" json:"provider-id"`
+	CIDR       string     `yaml:"cidr,omitempty" json:"cidr,omitempty"`
+	VLANTag    int        `yaml:"vlan-tag,omitempty" json:"vlan-tag,omitempty"`
+}
+
+func (c *ListNetworksCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-networks",
+		Purpose: "list the networks known in the environment",
+		Doc:     listNetworksDoc,
+	}
+}
+
+func (c *ListNetworksCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatNetworksTabular,
+	})
+}
+
+func (c *ListNetworksCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ListNetworksCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	networks, err := client.ListNetworks()
+	if err != nil {
+		return err
+	}
+	infos := make([]NetworkInfo, len(networks))
+	for i, n := range networks {
+		tag, err := names.ParseNetworkTag(n.Tag)
+		if err != nil {
+			return err
+		}
+		infos[i] = NetworkInfo{
+			Name:       tag.Id(),
+			ProviderId: n.ProviderId,
+			CIDR:       n.CIDR,
+			VLANTag:    n.VLANTag,
+		}
+	}
+	sort.Sort(networkInfos(infos))
+	return c.out.Write(ctx, infos)
+}
+
+func formatNetworksTabular(value interface{}) ([]byte, error) {
+	infos, ok := value.([]NetworkInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", infos, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "NAME\tPROVIDER-ID\tCIDR\tVLAN-TAG\n")
+	for _, info := range infos {
+		vlanTag := ""
+		if info.VLANTag != 0 {
+			vlanTag = fmt.Sprint(info.VLANTag)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, info.ProviderId, info.CIDR, vlanTag)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+type networkInfos []NetworkInfo
+
+func (n networkInfos) Len() int           { return len(n) }
+func (n networkInfos) Less(i, j int) bool { return n[i].Name < n[j].Name }
+func (n networkInfos) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const listPortsDoc = `
+List the port ranges opened on machines, and the units that opened
+them. Without arguments every machine in the environment is listed.
+This shows what the firewaller is asked to open, which is useful when
+auditing an environment or when exposing a service has no effect.
+
+Examples:
+
+    juju list-ports
+    juju list-ports 0 3
+
+See Also:
+   juju expose
+`
+
+// ListPortsCommand lists the port ranges opened on machines.
+type ListPortsCommand struct {
+	envcmd.EnvCommandBase
+	out      cmd.Output
+	Machines []names.MachineTag
+}
+
+// MachinePortsInfo holds the port ranges opened on a machine, as
+// shown by ListPortsCommand.
+type MachinePortsInfo struct {
+	Machine string          `yaml:"machine" json:"machine"`
+	Ports   []PortRangeInfo `yaml:"ports,omitempty" json:"ports,omitempty"`
+}
+
+// PortRangeInfo holds a port range opened on a machine and the unit
+// that opened it.
+type PortRangeInfo struct {
+	Range string `yaml:"range" json:"range"`
+	Unit  string `yaml:"unit" json:"unit"`
+}
+
+func (c *ListPortsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-ports",
+		Args:    "[<machine> ...]",
+		Purpose: "list the port ranges opened on machines",
+		Doc:     listPortsDoc,
+	}
+}
+
+func (c *ListPortsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatPortsTabular,
+	})
+}
+
+func (c *ListPortsCommand) Init(args []string) error {
+	c.Machines = make([]names.MachineTag, len(args))
+	for i, arg := range args {
+		if !names.IsValidMachine(arg) {
+			return errors.Errorf("invalid machine id %q", arg)
+		}
+		c.Machines[i] = names.NewMachineTag(arg)
+	}
+	return nil
+}
+
+func (c *ListPortsCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	machines, err := client.MachinePorts(c.Machines...)
+	if err != nil {
+		return err
+	}
+	infos := make([]MachinePortsInfo, len(machines))
+	for i, m := range machines {
+		tag, err := names.ParseMachineTag(m.MachineTag)
+		if err != nil {
+			return err
+		}
+		infos[i].Machine = tag.Id()
+		for _, p := range m.Ports {
+			unitTag, err := names.ParseUnitTag(p.UnitTag)
+			if err != nil {
+				return err
+			}
+			infos[i].Ports = append(infos[i].Ports, PortRangeInfo{
+				Range: p.PortRange.String(),
+				Unit:  unitTag.Id(),
+			})
+		}
+	}
+	return c.out.Write(ctx, infos)
+}
+
+func formatPortsTabular(value interface{}) ([]byte, error) {
+	infos, ok := value.([]MachinePortsInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", infos, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "MACHINE\tPORTS\tUNIT\n")
+	for _, info := range infos {
+		if len(info.Ports) == 0 {
+			fmt.Fprintf(tw, "%s\t\t\n", info.Machine)
+			continue
+		}
+		for _, p := range info.Ports {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", info.Machine, p.Range, p.Unit)
+		}
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type ListPortsSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&ListPortsSuite{})
+
+func (s *ListPortsSuite) TestInit(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ListPortsCommand{}), "0", "foo")
+	c.Assert(err, gc.ErrorMatches, `invalid machine id "foo"`)
+}
+
+func (s *ListPortsSuite) TestListPorts(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, gc.IsNil)
+	err = unit.OpenPorts("tcp", 80, 90)
+	c.Assert(err, gc.IsNil)
+	err = unit.OpenPort("udp", 53)
+	c.Assert(err, gc.IsNil)
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&ListPortsCommand{}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"MACHINE  PORTS      UNIT\n"+
+		"0        80-90/tcp  wordpress/0\n"+
+		"0        53/udp     wordpress/0\n"+
+		"1                   \n",
+	)
+
+	context, err = testing.RunCommand(c, envcmd.Wrap(&ListPortsCommand{}), "--format", "yaml", "1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+- machine: "1"
+`[1:])
+
+	_, err = testing.RunCommand(c, envcmd.Wrap(&ListPortsCommand{}), "42")
+	c.Assert(err, gc.ErrorMatches, "machine 42 not found")
+}
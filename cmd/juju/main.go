@@ -12,10 +12,15 @@ import (
 
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/action"
 	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/cmd/juju/group"
 	"github.com/juju/juju/cmd/juju/user"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju"
+	"github.com/juju/juju/utils/featureflag"
 	// Import the providers.
 	_ "github.com/juju/juju/provider/all"
 )
@@ -97,9 +102,23 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Creation commands.
 	r.Register(wrapEnvCommand(&BootstrapCommand{}))
 	r.Register(wrapEnvCommand(&AddMachineCommand{}))
+	r.Register(wrapEnvCommand(&AddNetworkCommand{}))
 	r.Register(wrapEnvCommand(&DeployCommand{}))
+	r.Register(wrapEnvCommand(&DeployBundleCommand{}))
 	r.Register(wrapEnvCommand(&AddRelationCommand{}))
 	r.Register(wrapEnvCommand(&AddUnitCommand{}))
+	r.Register(wrapEnvCommand(&ScaleServiceCommand{}))
+	if featureflag.Enabled(feature.CrossEnvRelations) {
+		r.Register(wrapEnvCommand(&OfferCommand{}))
+		r.Register(wrapEnvCommand(&ConsumeCommand{}))
+	}
+	r.Register(wrapEnvCommand(&ExportBundleCommand{}))
+	if featureflag.Enabled(feature.JES) {
+		r.Register(wrapEnvCommand(&CreateEnvironmentCommand{}))
+		r.Register(wrapEnvCommand(&ExportEnvironmentCommand{}))
+		r.Register(wrapEnvCommand(&ImportEnvironmentCommand{}))
+	}
+	r.Register(wrapEnvCommand(&GrantCommand{}))
 
 	// Destruction commands.
 	r.Register(wrapEnvCommand(&RemoveMachineCommand{}))
@@ -110,9 +129,17 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Reporting commands.
 	r.Register(wrapEnvCommand(&StatusCommand{}))
+	r.Register(wrapEnvCommand(&StatusHistoryCommand{}))
 	r.Register(&SwitchCommand{})
+	r.Register(&EnvironmentsCommand{})
 	r.Register(wrapEnvCommand(&EndpointCommand{}))
 	r.Register(wrapEnvCommand(&APIInfoCommand{}))
+	r.Register(wrapEnvCommand(&WaitCommand{}))
+	r.Register(wrapEnvCommand(&ListNetworksCommand{}))
+	if featureflag.Enabled(feature.CrossEnvRelations) {
+		r.Register(wrapEnvCommand(&ListOffersCommand{}))
+	}
+	r.Register(wrapEnvCommand(&ListPortsCommand{}))
 
 	// Error resolution and debugging commands.
 	r.Register(wrapEnvCommand(&RunCommand{}))
@@ -122,6 +149,11 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&DebugLogCommand{}))
 	r.Register(wrapEnvCommand(&DebugHooksCommand{}))
 	r.Register(wrapEnvCommand(&RetryProvisioningCommand{}))
+	r.Register(wrapEnvCommand(&ListCleanupsCommand{}))
+	r.Register(wrapEnvCommand(&CancelCleanupCommand{}))
+	r.Register(wrapEnvCommand(&CreateTokenCommand{}))
+	r.Register(wrapEnvCommand(&ListTokensCommand{}))
+	r.Register(wrapEnvCommand(&RevokeTokenCommand{}))
 
 	// Configuration commands.
 	r.Register(&InitCommand{})
@@ -130,6 +162,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&UnsetCommand{}))
 	r.Register(wrapEnvCommand(&GetConstraintsCommand{}))
 	r.Register(wrapEnvCommand(&SetConstraintsCommand{}))
+	r.Register(wrapEnvCommand(&SetHookPolicyCommand{}))
+	r.Register(wrapEnvCommand(&SetUnitChaosCommand{}))
 	r.Register(wrapEnvCommand(&GetEnvironmentCommand{}))
 	r.Register(wrapEnvCommand(&SetEnvironmentCommand{}))
 	r.Register(wrapEnvCommand(&UnsetEnvironmentCommand{}))
@@ -137,6 +171,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&SyncToolsCommand{}))
 	r.Register(wrapEnvCommand(&UnexposeCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeJujuCommand{}))
+	r.Register(wrapEnvCommand(&PinAgentVersionCommand{}))
+	r.Register(wrapEnvCommand(&PinCharmCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeCharmCommand{}))
 
 	// Charm publishing commands.
@@ -145,15 +181,24 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Charm tool commands.
 	r.Register(&HelpToolCommand{})
 
+	// Queue and inspect charm actions.
+	r.Register(action.NewSuperCommand())
+
 	// Manage backups.
 	r.Register(backups.NewCommand())
 
+	// Inspect and maintain the state servers.
+	r.Register(controller.NewSuperCommand())
+
 	// Manage authorized ssh keys.
 	r.Register(NewAuthorizedKeysCommand())
 
 	// Manage users and access
 	r.Register(user.NewSuperCommand())
 
+	// Manage named groups of units.
+	r.Register(group.NewSuperCommand())
+
 	// Manage state server availability.
 	r.Register(wrapEnvCommand(&EnsureAvailabilityCommand{}))
 }
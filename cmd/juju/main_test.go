@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/juju/juju/cmd/envcmd"
 	cmdtesting "github.com/juju/juju/cmd/testing"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/osenv"
 	_ "github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/testing"
@@ -163,7 +165,9 @@ func (s *MainSuite) TestActualRunJujuArgOrder(c *gc.C) {
 }
 
 var commandNames = []string{
+	"action",
 	"add-machine",
+	"add-network",
 	"add-relation",
 	"add-unit",
 	"api-endpoints",
@@ -172,9 +176,13 @@ var commandNames = []string{
 	"authorized-keys",
 	"backups",
 	"bootstrap",
+	"cancel-cleanup",
+	"controller",
+	"create-token",
 	"debug-hooks",
 	"debug-log",
 	"deploy",
+	"deploy-bundle",
 	"destroy-environment",
 	"destroy-machine",
 	"destroy-relation",
@@ -182,15 +190,26 @@ var commandNames = []string{
 	"destroy-unit",
 	"ensure-availability",
 	"env", // alias for switch
+	"environments",
+	"export-bundle",
 	"expose",
 	"generate-config", // alias for init
 	"get",
 	"get-constraints",
 	"get-env", // alias for get-environment
 	"get-environment",
+	"grant",
+	"group",
 	"help",
 	"help-tool",
+	"hook-tools", // alias for help-tool
 	"init",
+	"list-cleanups",
+	"list-networks",
+	"list-ports",
+	"list-tokens",
+	"pin-agent-version",
+	"pin-charm",
 	"publish",
 	"remove-machine",  // alias for destroy-machine
 	"remove-relation", // alias for destroy-relation
@@ -198,15 +217,20 @@ var commandNames = []string{
 	"remove-unit",     // alias for destroy-unit
 	"resolved",
 	"retry-provisioning",
+	"revoke-token",
 	"run",
+	"scale-service",
 	"scp",
 	"set",
 	"set-constraints",
 	"set-env", // alias for set-environment
 	"set-environment",
+	"set-hook-policy",
+	"set-unit-chaos",
 	"ssh",
 	"stat", // alias for status
 	"status",
+	"status-history",
 	"switch",
 	"sync-tools",
 	"terminate-machine", // alias for destroy-machine
@@ -218,11 +242,41 @@ var commandNames = []string{
 	"upgrade-juju",
 	"user",
 	"version",
+	"wait",
 }
 
 func (s *MainSuite) TestHelpCommands(c *gc.C) {
 	// Check that we have correctly registered all the commands
 	// by checking the help output.
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, "")
+	c.Assert(helpCommandNames(c), jc.DeepEquals, commandNames)
+}
+
+func (s *MainSuite) TestHelpCommandsWithJES(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, feature.JES)
+	expected := append([]string{
+		"create-environment",
+		"export-environment",
+		"import-environment",
+	}, commandNames...)
+	sort.Strings(expected)
+	c.Assert(helpCommandNames(c), jc.DeepEquals, expected)
+}
+
+func (s *MainSuite) TestHelpCommandsWithCrossEnvRelations(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, feature.CrossEnvRelations)
+	expected := append([]string{
+		"consume",
+		"list-offers",
+		"offer",
+	}, commandNames...)
+	sort.Strings(expected)
+	c.Assert(helpCommandNames(c), jc.DeepEquals, expected)
+}
+
+// helpCommandNames returns the names of the commands listed by
+// "juju help commands".
+func helpCommandNames(c *gc.C) []string {
 	defer osenv.SetJujuHome(osenv.SetJujuHome(c.MkDir()))
 	out := badrun(c, 0, "help", "commands")
 	lines := strings.Split(out, "\n")
@@ -235,7 +289,7 @@ func (s *MainSuite) TestHelpCommands(c *gc.C) {
 		names = append(names, f[0])
 	}
 	// The names should be output in alphabetical order, so don't sort.
-	c.Assert(names, jc.DeepEquals, commandNames)
+	return names
 }
 
 var topicNames = []string{
@@ -0,0 +1,76 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type NetworksSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&NetworksSuite{})
+
+func (s *NetworksSuite) TestAddNetworkInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no network name specified"},
+		{[]string{"db"}, "no provider id specified"},
+		{[]string{"db!", "provider-net"}, `invalid network name "db!"`},
+		{[]string{"db", "provider-net", "extra"}, `unrecognized args: \["extra"\]`},
+		{[]string{"--vlan-tag", "4095", "db", "provider-net"}, "invalid VLAN tag 4095: must be between 0 and 4094"},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := testing.RunCommand(c, envcmd.Wrap(&AddNetworkCommand{}), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *NetworksSuite) TestAddNetwork(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AddNetworkCommand{}),
+		"db", "provider-net-42", "--cidr", "10.0.42.0/24", "--vlan-tag", "42")
+	c.Assert(err, gc.IsNil)
+	db, err := s.State.Network("db")
+	c.Assert(err, gc.IsNil)
+	c.Assert(db.ProviderId(), gc.Equals, network.Id("provider-net-42"))
+	c.Assert(db.CIDR(), gc.Equals, "10.0.42.0/24")
+	c.Assert(db.VLANTag(), gc.Equals, 42)
+}
+
+func (s *NetworksSuite) TestListNetworks(c *gc.C) {
+	for _, args := range [][]string{
+		{"storage", "provider-net-43"},
+		{"db", "provider-net-42", "--cidr", "10.0.42.0/24", "--vlan-tag", "42"},
+	} {
+		_, err := testing.RunCommand(c, envcmd.Wrap(&AddNetworkCommand{}), args...)
+		c.Assert(err, gc.IsNil)
+	}
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&ListNetworksCommand{}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"NAME     PROVIDER-ID      CIDR          VLAN-TAG\n"+
+		"db       provider-net-42  10.0.42.0/24  42\n"+
+		"storage  provider-net-43                \n",
+	)
+
+	context, err = testing.RunCommand(c, envcmd.Wrap(&ListNetworksCommand{}), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+- name: db
+  provider-id: provider-net-42
+  cidr: 10.0.42.0/24
+  vlan-tag: 42
+- name: storage
+  provider-id: provider-net-43
+`[1:])
+}
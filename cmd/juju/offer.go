@@ -0,0 +1,297 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/crossenvrelations"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state"
+)
+
+const offerDoc = `
+Offer one or more endpoints of a service for use by services in other
+environments. The offer is identified by a URL, which consuming
+environments pass to "juju consume".
+
+Examples:
+   juju offer nagios:monitors local:/u/admin/monitoring
+   juju offer mysql:db,db-admin local:/u/admin/db --description "shared db"
+
+See Also:
+   juju list-offers
+   juju consume
+`
+
+// OfferCommand offers service endpoints to other environments.
+type OfferCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Endpoints   []string
+	URL         string
+	Description string
+}
+
+func (c *OfferCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "offer",
+		Args:    "<service>:<endpoint>[,...] <url>",
+		Purpose: "offer service endpoints for use in other environments",
+		Doc:     offerDoc,
+	}
+}
+
+func (c *OfferCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Description, "description", "", "a description of the offer")
+}
+
+func (c *OfferCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service endpoints specified")
+	case 1:
+		return errors.New("no offer URL specified")
+	}
+	parts := strings.SplitN(args[0], ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return errors.Errorf(`expected "<service>:<endpoint>[,...]", got %q`, args[0])
+	}
+	if !names.IsValidService(parts[0]) {
+		return errors.Errorf("invalid service name %q", parts[0])
+	}
+	c.ServiceName = parts[0]
+	c.Endpoints = strings.Split(parts[1], ",")
+	if !state.IsValidOfferURL(args[1]) {
+		return errors.Errorf("invalid offer URL %q", args[1])
+	}
+	c.URL = args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+func (c *OfferCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	client := crossenvrelations.NewClient(root)
+	defer client.Close()
+	if err := client.Offer(c.URL, c.ServiceName, c.Endpoints, c.Description); err != nil {
+		return err
+	}
+	ctx.Infof("offered %s:%s as %q", c.ServiceName, strings.Join(c.Endpoints, ","), c.URL)
+	return nil
+}
+
+// ListOffersCommand lists the service offers made by an environment.
+type ListOffersCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+func (c *ListOffersCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-offers",
+		Purpose: "list the service endpoints offered to other environments",
+	}
+}
+
+func (c *ListOffersCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+func (c *ListOffersCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// OfferInfo holds the details of a service offer shown by
+// ListOffersCommand.
+type OfferInfo struct {
+	URL         string   `yaml:"url" json:"url"`
+	Service     string   `yaml:"service" json:"service"`
+	Endpoints   []string `yaml:"endpoints" json:"endpoints"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+func (c *ListOffersCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	client := crossenvrelations.NewClient(root)
+	defer client.Close()
+	offers, err := client.ListOffers()
+	if err != nil {
+		return err
+	}
+	infos := make([]OfferInfo, len(offers))
+	for i, offer := range offers {
+		infos[i] = OfferInfo{
+			URL:         offer.URL,
+			Service:     offer.ServiceName,
+			Endpoints:   offer.Endpoints,
+			Description: offer.Description,
+		}
+	}
+	return c.out.Write(ctx, infos)
+}
+
+const consumeDoc = `
+Consume a service offered by another environment from a service in this
+environment. The offering environment is told the addresses of the local
+service's units, and in return reports the offered endpoints and the
+addresses of the offered service's units. The offered service is not
+exposed automatically: until access can be limited to the consuming
+units, its ports must be opened to them by other means.
+
+Examples:
+   juju consume central local:/u/admin/monitoring wordpress
+
+See Also:
+   juju offer
+`
+
+// ConsumeCommand registers a local service as a consumer of a service
+// offered by another environment.
+type ConsumeCommand struct {
+	envcmd.EnvCommandBase
+	OfferingEnv string
+	URL         string
+	ServiceName string
+	out         cmd.Output
+}
+
+func (c *ConsumeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "consume",
+		Args:    "<offering-environment> <url> <service>",
+		Purpose: "consume a service offered by another environment",
+		Doc:     consumeDoc,
+	}
+}
+
+func (c *ConsumeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+func (c *ConsumeCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no offering environment specified")
+	case 1:
+		return errors.New("no offer URL specified")
+	case 2:
+		return errors.New("no service specified")
+	}
+	c.OfferingEnv = args[0]
+	if !state.IsValidOfferURL(args[1]) {
+		return errors.Errorf("invalid offer URL %q", args[1])
+	}
+	c.URL = args[1]
+	if !names.IsValidService(args[2]) {
+		return errors.Errorf("invalid service name %q", args[2])
+	}
+	c.ServiceName = args[2]
+	return cmd.CheckEmpty(args[3:])
+}
+
+// remoteRelationRegistrar registers remote relations with an
+// offering environment.
+type remoteRelationRegistrar interface {
+	RegisterRemoteRelation(params.RemoteRelation) (params.RemoteRelationResult, error)
+	Close() error
+}
+
+// newRemoteRelationRegistrar connects to the named offering
+// environment. It is a variable so that it can be replaced for testing.
+var newRemoteRelationRegistrar = func(envName string) (remoteRelationRegistrar, error) {
+	root, err := juju.NewAPIFromName(envName)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot connect to environment %q", envName)
+	}
+	return crossenvrelations.NewClient(root), nil
+}
+
+// ConsumeInfo holds the details of a consumed offer shown by
+// ConsumeCommand.
+type ConsumeInfo struct {
+	Environment string         `yaml:"environment" json:"environment"`
+	Service     string         `yaml:"service" json:"service"`
+	Endpoints   []EndpointInfo `yaml:"endpoints" json:"endpoints"`
+	Addresses   []string       `yaml:"addresses,omitempty" json:"addresses,omitempty"`
+}
+
+// EndpointInfo holds the details of an offered endpoint.
+type EndpointInfo struct {
+	Name      string `yaml:"name" json:"name"`
+	Interface string `yaml:"interface" json:"interface"`
+	Role      string `yaml:"role" json:"role"`
+}
+
+func (c *ConsumeCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+	envTag, err := root.EnvironTag()
+	if err != nil {
+		return err
+	}
+	status, err := root.Client().Status([]string{c.ServiceName})
+	if err != nil {
+		return err
+	}
+	service, ok := status.Services[c.ServiceName]
+	if !ok {
+		return errors.NotFoundf("service %q", c.ServiceName)
+	}
+	var ingress []string
+	for _, unit := range service.Units {
+		if unit.PublicAddress != "" {
+			ingress = append(ingress, unit.PublicAddress)
+		}
+	}
+
+	remote, err := newRemoteRelationRegistrar(c.OfferingEnv)
+	if err != nil {
+		return err
+	}
+	defer remote.Close()
+	result, err := remote.RegisterRemoteRelation(params.RemoteRelation{
+		OfferURL:         c.URL,
+		ConsumerEnvUUID:  envTag.Id(),
+		ConsumerService:  c.ServiceName,
+		IngressAddresses: ingress,
+	})
+	if err != nil {
+		return err
+	}
+	info := ConsumeInfo{
+		Environment: result.EnvUUID,
+		Service:     result.ServiceName,
+		Addresses:   result.Addresses,
+	}
+	for _, ep := range result.Endpoints {
+		info.Endpoints = append(info.Endpoints, EndpointInfo{
+			Name:      ep.Name,
+			Interface: ep.Interface,
+			Role:      ep.Role,
+		})
+	}
+	return c.out.Write(ctx, info)
+}
@@ -0,0 +1,166 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/juju/osenv"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type OfferSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&OfferSuite{})
+
+func (s *OfferSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, feature.CrossEnvRelations)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *OfferSuite) TestOfferInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no service endpoints specified"},
+		{[]string{"wordpress:url"}, "no offer URL specified"},
+		{[]string{"wordpress", "local:/u/admin/blog"}, `expected "<service>:<endpoint>\[,...\]", got "wordpress"`},
+		{[]string{"word press:url", "local:/u/admin/blog"}, `invalid service name "word press"`},
+		{[]string{"wordpress:url", "blog"}, `invalid offer URL "blog"`},
+		{[]string{"wordpress:url", "local:/u/admin/blog", "extra"}, `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := testing.RunCommand(c, envcmd.Wrap(&OfferCommand{}), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *OfferSuite) TestOffer(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&OfferCommand{}),
+		"wordpress:url", "local:/u/admin/blog", "--description", "a shared blog")
+	c.Assert(err, gc.IsNil)
+	offer, err := s.State.ServiceOffer("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	c.Assert(*offer, jc.DeepEquals, state.ServiceOffer{
+		URL:         "local:/u/admin/blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+		Description: "a shared blog",
+	})
+
+	_, err = testing.RunCommand(c, envcmd.Wrap(&OfferCommand{}),
+		"wordpress:foo", "local:/u/admin/other")
+	c.Assert(err, gc.ErrorMatches, `cannot add service offer "local:/u/admin/other": service "wordpress" has no "foo" relation`)
+}
+
+func (s *OfferSuite) TestListOffers(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&OfferCommand{}),
+		"wordpress:url", "local:/u/admin/blog", "--description", "a shared blog")
+	c.Assert(err, gc.IsNil)
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&ListOffersCommand{}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+- url: local:/u/admin/blog
+  service: wordpress
+  endpoints:
+  - url
+  description: a shared blog
+`[1:])
+}
+
+type fakeRegistrar struct {
+	rel    params.RemoteRelation
+	result params.RemoteRelationResult
+	closed bool
+}
+
+func (f *fakeRegistrar) RegisterRemoteRelation(rel params.RemoteRelation) (params.RemoteRelationResult, error) {
+	f.rel = rel
+	return f.result, nil
+}
+
+func (f *fakeRegistrar) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *OfferSuite) TestConsumeInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no offering environment specified"},
+		{[]string{"central"}, "no offer URL specified"},
+		{[]string{"central", "local:/u/admin/blog"}, "no service specified"},
+		{[]string{"central", "blog", "wordpress"}, `invalid offer URL "blog"`},
+		{[]string{"central", "local:/u/admin/blog", "word press"}, `invalid service name "word press"`},
+		{[]string{"central", "local:/u/admin/blog", "wordpress", "extra"}, `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := testing.RunCommand(c, envcmd.Wrap(&ConsumeCommand{}), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *OfferSuite) TestConsume(c *gc.C) {
+	fake := &fakeRegistrar{
+		result: params.RemoteRelationResult{
+			EnvUUID:     "ffffffff-ffff-ffff-ffff-ffffffffffff",
+			ServiceName: "mysql",
+			Endpoints: []params.RemoteEndpoint{{
+				Name:      "server",
+				Interface: "mysql",
+				Role:      "provider",
+			}},
+			Addresses: []string{"10.0.0.1"},
+		},
+	}
+	var envName string
+	s.PatchValue(&newRemoteRelationRegistrar, func(name string) (remoteRelationRegistrar, error) {
+		envName = name
+		return fake, nil
+	})
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&ConsumeCommand{}),
+		"central", "local:/u/admin/db", "wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(envName, gc.Equals, "central")
+	c.Assert(fake.closed, jc.IsTrue)
+	c.Assert(fake.rel, jc.DeepEquals, params.RemoteRelation{
+		OfferURL:        "local:/u/admin/db",
+		ConsumerEnvUUID: s.State.EnvironTag().Id(),
+		ConsumerService: "wordpress",
+	})
+	c.Assert(testing.Stdout(context), gc.Equals, `
+environment: ffffffff-ffff-ffff-ffff-ffffffffffff
+service: mysql
+endpoints:
+- name: server
+  interface: mysql
+  role: provider
+addresses:
+- 10.0.0.1
+`[1:])
+}
+
+func (s *OfferSuite) TestConsumeUnknownService(c *gc.C) {
+	s.PatchValue(&newRemoteRelationRegistrar, func(name string) (remoteRelationRegistrar, error) {
+		c.Fatalf("offering environment should not be contacted")
+		return nil, nil
+	})
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ConsumeCommand{}),
+		"central", "local:/u/admin/db", "mysql")
+	c.Assert(err, gc.NotNil)
+}
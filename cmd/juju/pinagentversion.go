@@ -0,0 +1,88 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/version"
+)
+
+const pinAgentVersionDoc = `
+Hold the agents of a machine, or of all the units of a service, at no
+later than the given version while the rest of the environment is
+upgraded with "juju upgrade-juju".  A service pin applies to the
+machines hosting the service's units.  Use --clear to release the pin,
+after which the agents will upgrade to the environment's agent-version.
+
+Examples:
+
+    juju pin-agent-version mysql 1.20.11
+    juju pin-agent-version 3 1.20.11
+    juju pin-agent-version --clear mysql
+`
+
+// PinAgentVersionCommand holds the agents of a machine or service at a
+// specific agent version.
+type PinAgentVersionCommand struct {
+	envcmd.EnvCommandBase
+	Tag     names.Tag
+	Version version.Number
+	Clear   bool
+}
+
+func (c *PinAgentVersionCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "pin-agent-version",
+		Args:    "<machine | service> [<version>]",
+		Purpose: "hold a machine's or service's agents at a version",
+		Doc:     pinAgentVersionDoc,
+	}
+}
+
+func (c *PinAgentVersionCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Clear, "clear", false, "remove the agent version pin")
+}
+
+func (c *PinAgentVersionCommand) Init(args []string) (err error) {
+	if len(args) == 0 {
+		return fmt.Errorf("no machine or service specified")
+	}
+	switch target := args[0]; {
+	case names.IsValidMachine(target):
+		c.Tag = names.NewMachineTag(target)
+	case names.IsValidService(target):
+		c.Tag = names.NewServiceTag(target)
+	default:
+		return fmt.Errorf("invalid machine or service %q", target)
+	}
+	args = args[1:]
+	if c.Clear {
+		return cmd.CheckEmpty(args)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("no version specified")
+	}
+	if c.Version, err = version.Parse(args[0]); err != nil {
+		return err
+	}
+	if c.Version == version.Zero {
+		return fmt.Errorf("cannot pin to version %s", c.Version)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *PinAgentVersionCommand) Run(_ *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.SetAgentVersionPin(c.Tag, c.Version)
+}
@@ -0,0 +1,86 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type PinAgentVersionSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&PinAgentVersionSuite{})
+
+func runPinAgentVersion(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&PinAgentVersionCommand{}), args...)
+}
+
+func (s *PinAgentVersionSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no machine or service specified",
+	}, {
+		args: []string{"mysql/0", "1.2.3"},
+		err:  `invalid machine or service "mysql/0"`,
+	}, {
+		args: []string{"mysql"},
+		err:  "no version specified",
+	}, {
+		args: []string{"mysql", "bad"},
+		err:  `invalid version "bad"`,
+	}, {
+		args: []string{"mysql", "0.0.0"},
+		err:  "cannot pin to version 0.0.0",
+	}, {
+		args: []string{"--clear", "mysql", "1.2.3"},
+		err:  `unrecognized args: \["1.2.3"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(envcmd.Wrap(&PinAgentVersionCommand{}), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *PinAgentVersionSuite) TestPinService(c *gc.C) {
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := runPinAgentVersion(c, "wordpress", "1.2.3")
+	c.Assert(err, gc.IsNil)
+	v, ok, err := svc.AgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(v, gc.Equals, version.MustParse("1.2.3"))
+
+	_, err = runPinAgentVersion(c, "--clear", "wordpress")
+	c.Assert(err, gc.IsNil)
+	_, ok, err = svc.AgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *PinAgentVersionSuite) TestPinMachine(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	_, err = runPinAgentVersion(c, m.Id(), "1.2.3")
+	c.Assert(err, gc.IsNil)
+	v, ok, err := m.AgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(v, gc.Equals, version.MustParse("1.2.3"))
+}
+
+func (s *PinAgentVersionSuite) TestPinUnknownService(c *gc.C) {
+	_, err := runPinAgentVersion(c, "mysql", "1.2.3")
+	c.Assert(err, gc.ErrorMatches, `service "mysql" not found`)
+}
@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const pinCharmDoc = `
+Hold a service at its current charm. "juju status" does not report
+newer revisions of a pinned service's charm, and "juju upgrade-charm"
+refuses to change the charm unless --force is given.  Use --clear to
+release the pin.
+
+Every service is treated as pinned while the environment's
+charm-revision-policy setting is "locked".
+
+Examples:
+
+    juju pin-charm mysql
+    juju pin-charm --clear mysql
+`
+
+// PinCharmCommand holds a service at its current charm.
+type PinCharmCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Clear       bool
+}
+
+func (c *PinCharmCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "pin-charm",
+		Args:    "<service>",
+		Purpose: "hold a service at its current charm",
+		Doc:     pinCharmDoc,
+	}
+}
+
+func (c *PinCharmCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Clear, "clear", false, "remove the charm pin")
+}
+
+func (c *PinCharmCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no service specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return fmt.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *PinCharmCommand) Run(_ *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.SetServiceCharmPin(c.ServiceName, !c.Clear)
+}
@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing"
+)
+
+type PinCharmSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&PinCharmSuite{})
+
+func runPinCharm(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&PinCharmCommand{}), args...)
+}
+
+func (s *PinCharmSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service specified",
+	}, {
+		args: []string{"mysql/0"},
+		err:  `invalid service name "mysql/0"`,
+	}, {
+		args: []string{"mysql", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(envcmd.Wrap(&PinCharmCommand{}), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *PinCharmSuite) TestPinCharm(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := runPinCharm(c, "wordpress")
+	c.Assert(err, gc.IsNil)
+	svc, err := s.State.Service("wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(svc.IsCharmPinned(), jc.IsTrue)
+
+	_, err = runPinCharm(c, "--clear", "wordpress")
+	c.Assert(err, gc.IsNil)
+	err = svc.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(svc.IsCharmPinned(), jc.IsFalse)
+}
+
+func (s *PinCharmSuite) TestPinCharmUnknownService(c *gc.C) {
+	_, err := runPinCharm(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, `service "mysql" not found`)
+}
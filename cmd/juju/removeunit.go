@@ -5,48 +5,115 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 )
 
+const removeUnitDoc = `
+Remove service units from the environment. Units may be given by name,
+or by a glob pattern matching unit names, such as "wordpress/*".
+
+Alternatively, --num-units removes the given number of the service's
+highest-numbered units:
+
+    juju remove-unit --num-units 2 wordpress
+
+Subordinate units cannot be removed directly; remove the relation to
+their principal service instead.
+`
+
 // RemoveUnitCommand is responsible for destroying service units.
 type RemoveUnitCommand struct {
 	envcmd.EnvCommandBase
-	UnitNames []string
+	UnitNames   []string
+	ServiceName string
+	NumUnits    int
 }
 
 func (c *RemoveUnitCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "remove-unit",
-		Args:    "<unit> [...]",
+		Args:    "<unit> [...] | --num-units <n> <service>",
 		Purpose: "remove service units from the environment",
+		Doc:     removeUnitDoc,
 		Aliases: []string{"destroy-unit"},
 	}
 }
 
+func (c *RemoveUnitCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.NumUnits, "num-units", 0, "remove the given number of the service's highest-numbered units")
+}
+
 func (c *RemoveUnitCommand) Init(args []string) error {
+	if c.NumUnits != 0 {
+		if c.NumUnits < 0 {
+			return fmt.Errorf("--num-units must be a positive integer")
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("no service specified")
+		}
+		if !names.IsValidService(args[0]) {
+			return fmt.Errorf("invalid service name %q", args[0])
+		}
+		c.ServiceName = args[0]
+		return cmd.CheckEmpty(args[1:])
+	}
 	c.UnitNames = args
 	if len(c.UnitNames) == 0 {
 		return fmt.Errorf("no units specified")
 	}
 	for _, name := range c.UnitNames {
-		if !names.IsValidUnit(name) {
+		if !names.IsValidUnit(name) && !isUnitPattern(name) {
 			return fmt.Errorf("invalid unit name %q", name)
 		}
 	}
 	return nil
 }
 
+// isUnitPattern reports whether the name is a glob pattern matching
+// unit names.
+func isUnitPattern(name string) bool {
+	return strings.Contains(name, "/") && strings.ContainsAny(name, "*?[")
+}
+
 // Run connects to the environment specified on the command line and destroys
 // units therein.
-func (c *RemoveUnitCommand) Run(_ *cmd.Context) error {
+func (c *RemoveUnitCommand) Run(ctx *cmd.Context) error {
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
 	}
 	defer client.Close()
-	return client.DestroyServiceUnits(c.UnitNames...)
+	var unitNames []string
+	switch {
+	case c.ServiceName != "":
+		unitNames, err = client.DestroyServiceUnitCount(c.ServiceName, c.NumUnits)
+	case c.hasPatterns():
+		unitNames, err = client.DestroyUnits(c.UnitNames...)
+	default:
+		// Plain unit names are destroyed with the original call, so
+		// that older API servers are still supported.
+		return client.DestroyServiceUnits(c.UnitNames...)
+	}
+	if err != nil {
+		return err
+	}
+	for _, name := range unitNames {
+		ctx.Infof("removing unit %s", name)
+	}
+	return nil
+}
+
+func (c *RemoveUnitCommand) hasPatterns() bool {
+	for _, name := range c.UnitNames {
+		if isUnitPattern(name) {
+			return true
+		}
+	}
+	return false
 }
@@ -42,3 +42,74 @@ func (s *RemoveUnitSuite) TestRemoveUnit(c *gc.C) {
 		c.Assert(u.Life(), gc.Equals, state.Dying)
 	}
 }
+
+func (s *RemoveUnitSuite) TestRemoveUnitPattern(c *gc.C) {
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "-n", "2", "local:dummy", "dummy")
+	c.Assert(err, gc.IsNil)
+	curl := charm.MustParseURL(fmt.Sprintf("local:%s/dummy-1", testing.FakeDefaultSeries))
+	svc, _ := s.AssertService(c, "dummy", curl, 2, 0)
+
+	err = runRemoveUnit(c, "dummy/*")
+	c.Assert(err, gc.IsNil)
+	units, err := svc.AllUnits()
+	c.Assert(err, gc.IsNil)
+	for _, u := range units {
+		c.Assert(u.Life(), gc.Equals, state.Dying)
+	}
+
+	err = runRemoveUnit(c, "dummy/*")
+	c.Assert(err, gc.ErrorMatches, `no units match "dummy/\*"`)
+}
+
+func (s *RemoveUnitSuite) TestRemoveUnitNumUnits(c *gc.C) {
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "-n", "3", "local:dummy", "dummy")
+	c.Assert(err, gc.IsNil)
+	curl := charm.MustParseURL(fmt.Sprintf("local:%s/dummy-1", testing.FakeDefaultSeries))
+	s.AssertService(c, "dummy", curl, 3, 0)
+
+	err = runRemoveUnit(c, "--num-units", "2", "dummy")
+	c.Assert(err, gc.IsNil)
+	for name, life := range map[string]state.Life{
+		"dummy/0": state.Alive,
+		"dummy/1": state.Dying,
+		"dummy/2": state.Dying,
+	} {
+		unit, err := s.State.Unit(name)
+		c.Assert(err, gc.IsNil)
+		c.Assert(unit.Life(), gc.Equals, life)
+	}
+}
+
+func (s *RemoveUnitSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no units specified",
+	}, {
+		args: []string{"dummy"},
+		err:  `invalid unit name "dummy"`,
+	}, {
+		args: []string{"dummy*"},
+		err:  `invalid unit name "dummy\*"`,
+	}, {
+		args: []string{"--num-units", "2"},
+		err:  "no service specified",
+	}, {
+		args: []string{"--num-units", "-1", "dummy"},
+		err:  "--num-units must be a positive integer",
+	}, {
+		args: []string{"--num-units", "2", "dummy/0"},
+		err:  `invalid service name "dummy/0"`,
+	}, {
+		args: []string{"--num-units", "2", "dummy", "other"},
+		err:  `unrecognized args: \["other"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := runRemoveUnit(c, t.args...)
+		c.Assert(err, gc.ErrorMatches, t.err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 )
 
@@ -17,40 +18,127 @@ import (
 type ResolvedCommand struct {
 	envcmd.EnvCommandBase
 	UnitName string
+	Patterns []string
+	All      bool
+	Group    string
 	Retry    bool
+	NoRetry  bool
 }
 
+const resolvedDoc = `
+Marks the errors of a unit resolved, so that the unit may continue.
+
+Several units may be given at once, by name or by a glob pattern matching
+unit names, such as "wordpress/*"; units matched by a pattern are marked
+resolved only if they are in an error state. If --all is specified, every
+unit in an error state is marked resolved:
+
+    juju resolved --all --retry
+
+If --group is specified instead of a unit, all the units currently in the
+named unit group are marked resolved.
+
+When several units are marked resolved, a failure for any one unit is
+reported without stopping the others.
+
+Failed hooks are not re-executed unless --retry is specified; --no-retry
+may be used to make this explicit.
+`
+
 func (c *ResolvedCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "resolved",
-		Args:    "<unit>",
+		Args:    "<unit> [...] | --all",
 		Purpose: "marks unit errors resolved",
+		Doc:     resolvedDoc,
 	}
 }
 
 func (c *ResolvedCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Retry, "r", false, "re-execute failed hooks")
 	f.BoolVar(&c.Retry, "retry", false, "")
+	f.BoolVar(&c.NoRetry, "no-retry", false, "do not re-execute failed hooks")
+	f.BoolVar(&c.All, "all", false, "mark the errors of all units in an error state resolved")
+	f.StringVar(&c.Group, "group", "", "mark the errors of all units in the named unit group resolved")
 }
 
 func (c *ResolvedCommand) Init(args []string) error {
-	if len(args) > 0 {
-		c.UnitName = args[0]
-		if !names.IsValidUnit(c.UnitName) {
-			return fmt.Errorf("invalid unit name %q", c.UnitName)
+	if c.Retry && c.NoRetry {
+		return fmt.Errorf("cannot specify both --retry and --no-retry")
+	}
+	if c.Group != "" || c.All {
+		if c.Group != "" && c.All {
+			return fmt.Errorf("cannot specify both --all and --group")
+		}
+		if len(args) > 0 {
+			if c.All {
+				return fmt.Errorf("cannot specify both units and --all")
+			}
+			return fmt.Errorf("cannot specify both a unit and --group")
 		}
-		args = args[1:]
-	} else {
+		return nil
+	}
+	if len(args) == 0 {
 		return fmt.Errorf("no unit specified")
 	}
-	return cmd.CheckEmpty(args)
+	for _, name := range args {
+		if !names.IsValidUnit(name) && !isUnitPattern(name) {
+			return fmt.Errorf("invalid unit name %q", name)
+		}
+	}
+	if len(args) == 1 && names.IsValidUnit(args[0]) {
+		c.UnitName = args[0]
+		return nil
+	}
+	c.Patterns = args
+	return nil
 }
 
-func (c *ResolvedCommand) Run(_ *cmd.Context) error {
+func (c *ResolvedCommand) Run(ctx *cmd.Context) error {
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
 	}
 	defer client.Close()
-	return client.Resolved(c.UnitName, c.Retry)
+	var results []params.ResolveUnitResult
+	switch {
+	case c.UnitName != "":
+		// A single unit is resolved with the original call, so that
+		// older API servers are still supported.
+		return client.Resolved(c.UnitName, c.Retry)
+	case c.All:
+		results, err = client.ResolveAllUnits(c.Retry)
+	case len(c.Patterns) > 0:
+		results, err = client.ResolveUnits(c.Retry, c.Patterns...)
+	default:
+		units, err := client.UnitGroupUnits(c.Group)
+		if err != nil {
+			return err
+		}
+		for _, unit := range units {
+			result := params.ResolveUnitResult{UnitName: unit}
+			if err := client.Resolved(unit, c.Retry); err != nil {
+				result.Error = &params.Error{Message: err.Error()}
+			}
+			results = append(results, result)
+		}
+	}
+	if params.IsCodeNotImplemented(err) {
+		return fmt.Errorf("resolving several units at once is not supported by the API server")
+	} else if err != nil {
+		return err
+	}
+	failed := false
+	for _, result := range results {
+		if result.Error != nil {
+			ctx.Infof("%s: %v", result.UnitName, result.Error)
+			failed = true
+		} else if c.Group == "" {
+			ctx.Infof("marked %s resolved", result.UnitName)
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
 }
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"github.com/juju/cmd"
 	gc "gopkg.in/check.v1"
 	charmtesting "gopkg.in/juju/charm.v4/testing"
 
@@ -68,7 +69,13 @@ var resolvedTests = []struct {
 		mode: state.ResolvedRetryHooks,
 	}, {
 		args: []string{"dummy/4", "roflcopter"},
-		err:  `unrecognized args: \["roflcopter"\]`,
+		err:  `invalid unit name "roflcopter"`,
+	}, {
+		args: []string{"dummy/4", "--retry", "--no-retry"},
+		err:  `cannot specify both --retry and --no-retry`,
+	}, {
+		args: []string{"dummy/4", "--all"},
+		err:  `cannot specify both units and --all`,
 	},
 }
 
@@ -99,3 +106,81 @@ func (s *ResolvedSuite) TestResolved(c *gc.C) {
 		}
 	}
 }
+
+func (s *ResolvedSuite) TestResolvedGroup(c *gc.C) {
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "-n", "3", "local:dummy", "dummy")
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AddUnitGroup("canary", []string{"dummy/1", "dummy/2"})
+	c.Assert(err, gc.IsNil)
+
+	err = runResolved(c, []string{"dummy/0", "--group", "canary"})
+	c.Assert(err, gc.ErrorMatches, "cannot specify both a unit and --group")
+
+	for _, name := range []string{"dummy/0", "dummy/1"} {
+		u, err := s.State.Unit(name)
+		c.Assert(err, gc.IsNil)
+		err = u.SetStatus(state.StatusError, "lol borken", nil)
+		c.Assert(err, gc.IsNil)
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ResolvedCommand{}), "--group", "canary", "--retry")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals, `dummy/2: unit "dummy/2" is not in an error state`+"\n")
+
+	for name, mode := range map[string]state.ResolvedMode{
+		"dummy/0": state.ResolvedNone,
+		"dummy/1": state.ResolvedRetryHooks,
+		"dummy/2": state.ResolvedNone,
+	} {
+		unit, err := s.State.Unit(name)
+		c.Assert(err, gc.IsNil)
+		c.Assert(unit.Resolved(), gc.Equals, mode)
+	}
+}
+
+func (s *ResolvedSuite) TestResolvedPatterns(c *gc.C) {
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "-n", "3", "local:dummy", "dummy")
+	c.Assert(err, gc.IsNil)
+	for _, name := range []string{"dummy/0", "dummy/2"} {
+		u, err := s.State.Unit(name)
+		c.Assert(err, gc.IsNil)
+		err = u.SetStatus(state.StatusError, "lol borken", nil)
+		c.Assert(err, gc.IsNil)
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ResolvedCommand{}), "dummy/*", "--retry")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "marked dummy/0 resolved\nmarked dummy/2 resolved\n")
+
+	for name, mode := range map[string]state.ResolvedMode{
+		"dummy/0": state.ResolvedRetryHooks,
+		"dummy/1": state.ResolvedNone,
+		"dummy/2": state.ResolvedRetryHooks,
+	} {
+		unit, err := s.State.Unit(name)
+		c.Assert(err, gc.IsNil)
+		c.Check(unit.Resolved(), gc.Equals, mode)
+	}
+}
+
+func (s *ResolvedSuite) TestResolvedAll(c *gc.C) {
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "-n", "2", "local:dummy", "dummy")
+	c.Assert(err, gc.IsNil)
+	u, err := s.State.Unit("dummy/1")
+	c.Assert(err, gc.IsNil)
+	err = u.SetStatus(state.StatusError, "lol borken", nil)
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ResolvedCommand{}), "--all", "--no-retry")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "marked dummy/1 resolved\n")
+	err = u.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Resolved(), gc.Equals, state.ResolvedNoHooks)
+
+	// Nothing is left to resolve.
+	ctx, err = testing.RunCommand(c, envcmd.Wrap(&ResolvedCommand{}), "--all")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "")
+}
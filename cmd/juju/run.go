@@ -27,6 +27,7 @@ type RunCommand struct {
 	machines []string
 	services []string
 	units    []string
+	group    string
 	commands string
 }
 
@@ -52,6 +53,10 @@ is equivalent to
 Commands run for services or units are executed in a 'hook context' for
 the unit.
 
+If the target is a unit group, created with "juju group create", the
+command is run on all the units currently in that group, as if they had
+been specified with --unit.
+
 --all is provided as a simple way to run the command on all the machines
 in the environment.  If you specify --all you cannot provide additional
 targets.
@@ -74,6 +79,7 @@ func (c *RunCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewStringsValue(nil, &c.machines), "machine", "one or more machine ids")
 	f.Var(cmd.NewStringsValue(nil, &c.services), "service", "one or more service names")
 	f.Var(cmd.NewStringsValue(nil, &c.units), "unit", "one or more unit ids")
+	f.StringVar(&c.group, "group", "", "the name of a unit group")
 }
 
 func (c *RunCommand) Init(args []string) error {
@@ -92,9 +98,12 @@ func (c *RunCommand) Init(args []string) error {
 		if len(c.units) != 0 {
 			return fmt.Errorf("You cannot specify --all and individual units")
 		}
+		if c.group != "" {
+			return fmt.Errorf("You cannot specify --all and a unit group")
+		}
 	} else {
-		if len(c.machines) == 0 && len(c.services) == 0 && len(c.units) == 0 {
-			return fmt.Errorf("You must specify a target, either through --all, --machine, --service or --unit")
+		if len(c.machines) == 0 && len(c.services) == 0 && len(c.units) == 0 && c.group == "" {
+			return fmt.Errorf("You must specify a target, either through --all, --machine, --service, --unit or --group")
 		}
 	}
 
@@ -182,12 +191,20 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 	if c.all {
 		runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
 	} else {
+		units := c.units
+		if c.group != "" {
+			groupUnits, err := client.UnitGroupUnits(c.group)
+			if err != nil {
+				return err
+			}
+			units = append(units, groupUnits...)
+		}
 		params := params.RunParams{
 			Commands: c.commands,
 			Timeout:  c.timeout,
 			Machines: c.machines,
 			Services: c.services,
-			Units:    c.units,
+			Units:    units,
 		}
 		runResults, err = client.Run(params)
 	}
@@ -223,6 +240,7 @@ type RunClient interface {
 	Close() error
 	RunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error)
 	Run(run params.RunParams) ([]params.RunResult, error)
+	UnitGroupUnits(name string) ([]string, error)
 }
 
 // Here we need the signature to be correct for the interface.
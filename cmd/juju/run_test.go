@@ -32,6 +32,7 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 		machines []string
 		units    []string
 		services []string
+		group    string
 		commands string
 		errMatch string
 	}{{
@@ -40,7 +41,7 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 	}, {
 		message:  "no target",
 		args:     []string{"sudo reboot"},
-		errMatch: "You must specify a target, either through --all, --machine, --service or --unit",
+		errMatch: "You must specify a target, either through --all, --machine, --service, --unit or --group",
 	}, {
 		message:  "too many args",
 		args:     []string{"--all", "sudo reboot", "oops"},
@@ -98,6 +99,15 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 			"The following run targets are not valid:\n" +
 			"  \"foo\" is not a valid unit name\n" +
 			"  \"2\" is not a valid unit name",
+	}, {
+		message:  "all and unit group",
+		args:     []string{"--all", "--group=canary", "sudo reboot"},
+		errMatch: `You cannot specify --all and a unit group`,
+	}, {
+		message:  "command to unit group",
+		args:     []string{"--group=canary", "sudo reboot"},
+		commands: "sudo reboot",
+		group:    "canary",
 	}, {
 		message:  "command to mixed valid targets",
 		args:     []string{"--machine=0", "--unit=wordpress/0,wordpress/1", "--service=mysql", "sudo reboot"},
@@ -114,6 +124,7 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 			c.Check(runCmd.machines, gc.DeepEquals, test.machines)
 			c.Check(runCmd.services, gc.DeepEquals, test.services)
 			c.Check(runCmd.units, gc.DeepEquals, test.units)
+			c.Check(runCmd.group, gc.Equals, test.group)
 			c.Check(runCmd.commands, gc.Equals, test.commands)
 		}
 	}
@@ -267,6 +278,41 @@ func (s *RunSuite) TestRunForMachineAndUnit(c *gc.C) {
 	c.Check(testing.Stdout(context), gc.Equals, string(jsonFormatted)+"\n")
 }
 
+func (s *RunSuite) TestRunForUnitGroup(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.groups = map[string][]string{"canary": {"unit/0", "unit/1"}}
+	response0 := mockResponse{
+		stdout:    "bumblebee",
+		machineId: "1",
+		unitId:    "unit/0",
+	}
+	response1 := mockResponse{
+		stdout:    "jazz",
+		machineId: "2",
+		unitId:    "unit/1",
+	}
+	mock.setResponse("unit/0", response0)
+	mock.setResponse("unit/1", response1)
+
+	unformatted := ConvertRunResults([]params.RunResult{
+		makeRunResult(response0),
+		makeRunResult(response1),
+	})
+
+	jsonFormatted, err := cmd.FormatJson(unformatted)
+	c.Assert(err, gc.IsNil)
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&RunCommand{}),
+		"--format=json", "--group=canary", "hostname",
+	)
+	c.Assert(err, gc.IsNil)
+
+	c.Check(testing.Stdout(context), gc.Equals, string(jsonFormatted)+"\n")
+
+	_, err = testing.RunCommand(c, envcmd.Wrap(&RunCommand{}), "--group=missing", "hostname")
+	c.Assert(err, gc.ErrorMatches, `unit group "missing" not found`)
+}
+
 func (s *RunSuite) TestAllMachines(c *gc.C) {
 	mock := s.setupMockAPI()
 	mock.setMachinesAlive("0", "1")
@@ -363,6 +409,7 @@ type mockRunAPI struct {
 	// machines, services, units
 	machines  map[string]bool
 	responses map[string]params.RunResult
+	groups    map[string][]string
 }
 
 type mockResponse struct {
@@ -449,3 +496,11 @@ func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.RunResult, error)
 
 	return result, nil
 }
+
+func (m *mockRunAPI) UnitGroupUnits(name string) ([]string, error) {
+	units, found := m.groups[name]
+	if !found {
+		return nil, fmt.Errorf("unit group %q not found", name)
+	}
+	return units, nil
+}
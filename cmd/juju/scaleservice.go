@@ -0,0 +1,86 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const scaleServiceDoc = `
+Adds or removes units of a service so that it has exactly the given
+number of units. New units are deployed to newly provisioned machines,
+as with add-unit. When units must be removed, the highest-numbered
+units that are not in an error state are removed first.
+
+Example:
+
+    juju scale-service wordpress 10
+`
+
+// ScaleServiceCommand sets the number of units of a service.
+type ScaleServiceCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Count       int
+}
+
+func (c *ScaleServiceCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "scale-service",
+		Args:    "<service> <count>",
+		Purpose: "set the number of units of a service",
+		Doc:     scaleServiceDoc,
+	}
+}
+
+func (c *ScaleServiceCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service specified")
+	case 1:
+		return errors.New("no unit count specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return fmt.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	count, err := strconv.Atoi(args[1])
+	if err != nil || count < 0 {
+		return fmt.Errorf("invalid unit count %q", args[1])
+	}
+	c.Count = count
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Run connects to the environment specified on the command line and
+// adds or removes units of the service.
+func (c *ScaleServiceCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	added, destroyed, err := client.ServiceSetUnitCount(c.ServiceName, c.Count)
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("scale-service is not supported by the API server")
+	}
+	if err != nil {
+		return err
+	}
+	for _, name := range added {
+		ctx.Infof("adding unit %s", name)
+	}
+	for _, name := range destroyed {
+		ctx.Infof("removing unit %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	charmtesting "gopkg.in/juju/charm.v4/testing"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type ScaleServiceSuite struct {
+	jujutesting.RepoSuite
+}
+
+var _ = gc.Suite(&ScaleServiceSuite{})
+
+func runScaleService(c *gc.C, args ...string) error {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ScaleServiceCommand{}), args...)
+	return err
+}
+
+func (s *ScaleServiceSuite) TestScaleService(c *gc.C) {
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "-n", "3", "local:dummy", "dummy")
+	c.Assert(err, gc.IsNil)
+	curl := charm.MustParseURL(fmt.Sprintf("local:%s/dummy-1", testing.FakeDefaultSeries))
+	s.AssertService(c, "dummy", curl, 3, 0)
+
+	err = runScaleService(c, "dummy", "1")
+	c.Assert(err, gc.IsNil)
+	for name, life := range map[string]state.Life{
+		"dummy/0": state.Alive,
+		"dummy/1": state.Dying,
+		"dummy/2": state.Dying,
+	} {
+		unit, err := s.State.Unit(name)
+		c.Assert(err, gc.IsNil)
+		c.Assert(unit.Life(), gc.Equals, life)
+	}
+
+	err = runScaleService(c, "dummy", "2")
+	c.Assert(err, gc.IsNil)
+	unit, err := s.State.Unit("dummy/3")
+	c.Assert(err, gc.IsNil)
+	c.Assert(unit.Life(), gc.Equals, state.Alive)
+}
+
+func (s *ScaleServiceSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no service specified",
+	}, {
+		args: []string{"dummy"},
+		err:  "no unit count specified",
+	}, {
+		args: []string{"dummy/0", "2"},
+		err:  `invalid service name "dummy/0"`,
+	}, {
+		args: []string{"dummy", "lots"},
+		err:  `invalid unit count "lots"`,
+	}, {
+		args: []string{"dummy", "2", "other"},
+		err:  `unrecognized args: \["other"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := runScaleService(c, t.args...)
+		c.Assert(err, gc.ErrorMatches, t.err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/utils/ssh"
 )
@@ -16,6 +17,7 @@ import (
 // SCPCommand is responsible for launching a scp command to copy files to/from remote machine(s)
 type SCPCommand struct {
 	SSHCommon
+	recursive bool
 }
 
 const scpDoc = `
@@ -23,7 +25,10 @@ Launch an scp command to copy files. Each argument <file1> ... <file2>
 is either local file path or remote locations of the form <target>:<path>,
 where <target> can be either a machine id as listed by "juju status" in the
 "machines" section or a unit name as listed in the "services" section.
-Any extra arguments to scp can be passed after at the end. In case OpenSSH
+Any extra arguments to scp can be passed after at the end. When both the
+source and the destination are remote, the files are copied through the
+local machine, so the remote machines never need to reach each other or
+hold each other's keys. In case OpenSSH
 scp command cannot be found in the system PATH environment variable, this
 command is also not available for use. Please refer to the man page of scp(1)
 for the supported extra arguments.
@@ -37,16 +42,25 @@ Copy a single file from machine 2 to the local machine:
 Copy 2 files from two units to the local backup/ directory, passing -v
 to scp as an extra argument:
 
-    juju scp -v ubuntu/0:/path/file1 ubuntu/1:/path/file2 backup/
+    juju scp ubuntu/0:/path/file1 ubuntu/1:/path/file2 backup/ -v
 
 Recursively copy the directory /var/log/mongodb/ on the first mongodb
 server to the local directory remote-logs:
 
     juju scp -r mongodb/0:/var/log/mongodb/ remote-logs/
 
+Recursively copy the backups directory of a mysql unit to a wordpress unit:
+
+    juju scp -r mysql/0:/var/backups/ wordpress/1:/tmp/
+
 Copy a local file to the second apache unit of the environment "testing":
 
     juju scp -e testing foo.txt apache2/1:
+
+Copy a file from machine 3, which is on a private subnet, through the
+API server:
+
+    juju scp --proxy-private 3:/var/log/syslog .
 `
 
 func (c *SCPCommand) Info() *cmd.Info {
@@ -58,6 +72,12 @@ func (c *SCPCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *SCPCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SSHCommon.SetFlags(f)
+	f.BoolVar(&c.recursive, "r", false, "recursively copy entire directories")
+	f.BoolVar(&c.recursive, "recursive", false, "")
+}
+
 func (c *SCPCommand) Init(args []string) error {
 	if len(args) < 2 {
 		return fmt.Errorf("at least two arguments required")
@@ -66,17 +86,47 @@ func (c *SCPCommand) Init(args []string) error {
 	return nil
 }
 
+// scpOptionsWithValue holds the scp options that consume the
+// following argument as their value.
+var scpOptionsWithValue = map[string]bool{
+	"-c": true,
+	"-F": true,
+	"-i": true,
+	"-l": true,
+	"-o": true,
+	"-P": true,
+	"-S": true,
+}
+
 // expandArgs takes a list of arguments and looks for ones in the form of
 // 0:some/path or service/0:some/path, and translates them into
 // ubuntu@machine:some/path so they can be passed as arguments to scp, and pass
-// the rest verbatim on to scp
+// the rest verbatim on to scp. When a remote location is copied to a
+// remote destination, -3 is prepended so that scp copies through the
+// local host using the local keys.
 func expandArgs(args []string, hostFromTarget func(string) (string, error)) ([]string, error) {
 	outArgs := make([]string, len(args))
+	remoteSource, remoteDest, hasThree := false, false, false
+	skipValue := false
 	for i, arg := range args {
+		if skipValue {
+			skipValue = false
+			outArgs[i] = arg
+			continue
+		}
+		if arg == "-3" {
+			hasThree = true
+		}
 		v := strings.SplitN(arg, ":", 2)
 		if strings.HasPrefix(arg, "-") || len(v) <= 1 {
 			// Can't be an interesting target, so just pass it along
 			outArgs[i] = arg
+			skipValue = scpOptionsWithValue[arg]
+			if !strings.HasPrefix(arg, "-") {
+				// A local file; any remote seen so far is a source.
+				remoteSource = remoteSource || remoteDest
+				remoteDest = false
+			}
 			continue
 		}
 		host, err := hostFromTarget(v[0])
@@ -84,6 +134,11 @@ func expandArgs(args []string, hostFromTarget func(string) (string, error)) ([]s
 			return nil, err
 		}
 		outArgs[i] = "ubuntu@" + net.JoinHostPort(host, v[1])
+		remoteSource = remoteSource || remoteDest
+		remoteDest = true
+	}
+	if remoteSource && remoteDest && !hasThree {
+		outArgs = append([]string{"-3"}, outArgs...)
 	}
 	return outArgs, nil
 }
@@ -106,5 +161,11 @@ func (c *SCPCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	if c.recursive {
+		args = append([]string{"-r"}, args...)
+	}
+	if err := c.ensureProxyCommand(options); err != nil {
+		return err
+	}
 	return ssh.Copy(args, options)
 }
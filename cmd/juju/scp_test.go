@@ -30,11 +30,12 @@ type SCPSuite struct {
 type expandArgsSuite struct{}
 
 var scpTests = []struct {
-	about  string
-	args   []string
-	result string
-	proxy  bool
-	error  string
+	about     string
+	args      []string
+	result    string
+	proxy     bool
+	recursive bool
+	error     string
 }{
 	{
 		about:  "scp from machine 0 to current dir",
@@ -55,15 +56,15 @@ var scpTests = []struct {
 	}, {
 		about:  "scp from machine 0 to unit mysql/0",
 		args:   []string{"0:foo", "mysql/0:/foo"},
-		result: commonArgsNoProxy + "ubuntu@dummyenv-0.dns:foo ubuntu@dummyenv-0.dns:/foo\n",
+		result: commonArgsNoProxy + "-3 ubuntu@dummyenv-0.dns:foo ubuntu@dummyenv-0.dns:/foo\n",
 	}, {
 		about:  "scp from machine 0 to unit mysql/0 and extra args",
 		args:   []string{"0:foo", "mysql/0:/foo", "-q"},
-		result: commonArgsNoProxy + "ubuntu@dummyenv-0.dns:foo ubuntu@dummyenv-0.dns:/foo -q\n",
+		result: commonArgsNoProxy + "-3 ubuntu@dummyenv-0.dns:foo ubuntu@dummyenv-0.dns:/foo -q\n",
 	}, {
 		about:  "scp from machine 0 to unit mysql/0 and extra args before",
 		args:   []string{"-q", "-r", "0:foo", "mysql/0:/foo"},
-		result: commonArgsNoProxy + "-q -r ubuntu@dummyenv-0.dns:foo ubuntu@dummyenv-0.dns:/foo\n",
+		result: commonArgsNoProxy + "-3 -q -r ubuntu@dummyenv-0.dns:foo ubuntu@dummyenv-0.dns:/foo\n",
 	}, {
 		about:  "scp two local files to unit mysql/0",
 		args:   []string{"file1", "file2", "mysql/0:/foo/"},
@@ -71,7 +72,7 @@ var scpTests = []struct {
 	}, {
 		about:  "scp from unit mongodb/1 to unit mongodb/0 and multiple extra args",
 		args:   []string{"mongodb/1:foo", "mongodb/0:", "-r", "-v", "-q", "-l5"},
-		result: commonArgsNoProxy + "ubuntu@dummyenv-2.dns:foo ubuntu@dummyenv-1.dns: -r -v -q -l5\n",
+		result: commonArgsNoProxy + "-3 ubuntu@dummyenv-2.dns:foo ubuntu@dummyenv-1.dns: -r -v -q -l5\n",
 	}, {
 		about:  "scp works with IPv6 addresses",
 		args:   []string{"ipv6-svc/0:foo", "bar"},
@@ -79,7 +80,7 @@ var scpTests = []struct {
 	}, {
 		about:  "scp from machine 0 to unit mysql/0 with proxy",
 		args:   []string{"0:foo", "mysql/0:/foo"},
-		result: commonArgs + "ubuntu@dummyenv-0.internal:foo ubuntu@dummyenv-0.internal:/foo\n",
+		result: commonArgs + "-3 ubuntu@dummyenv-0.internal:foo ubuntu@dummyenv-0.internal:/foo\n",
 		proxy:  true,
 	}, {
 		args:   []string{"0:foo", ".", "-rv", "-o", "SomeOption"},
@@ -89,11 +90,24 @@ var scpTests = []struct {
 		result: commonArgsNoProxy + "foo ubuntu@dummyenv-0.dns: -r -v\n",
 	}, {
 		args:   []string{"mongodb/1:foo", "mongodb/0:", "-r", "-v", "-q", "-l5"},
-		result: commonArgsNoProxy + "ubuntu@dummyenv-2.dns:foo ubuntu@dummyenv-1.dns: -r -v -q -l5\n",
+		result: commonArgsNoProxy + "-3 ubuntu@dummyenv-2.dns:foo ubuntu@dummyenv-1.dns: -r -v -q -l5\n",
 	}, {
 		about:  "scp from unit mongodb/1 to unit mongodb/0 with a --",
 		args:   []string{"--", "-r", "-v", "mongodb/1:foo", "mongodb/0:", "-q", "-l5"},
-		result: commonArgsNoProxy + "-- -r -v ubuntu@dummyenv-2.dns:foo ubuntu@dummyenv-1.dns: -q -l5\n",
+		result: commonArgsNoProxy + "-3 -- -r -v ubuntu@dummyenv-2.dns:foo ubuntu@dummyenv-1.dns: -q -l5\n",
+	}, {
+		about:  "scp two remote sources to a unit",
+		args:   []string{"mongodb/0:foo", "mongodb/1:bar", "mysql/0:/baz/"},
+		result: commonArgsNoProxy + "-3 ubuntu@dummyenv-1.dns:foo ubuntu@dummyenv-2.dns:bar ubuntu@dummyenv-0.dns:/baz/\n",
+	}, {
+		about:  "scp two remote sources to the local machine",
+		args:   []string{"mongodb/0:foo", "mongodb/1:bar", "."},
+		result: commonArgsNoProxy + "ubuntu@dummyenv-1.dns:foo ubuntu@dummyenv-2.dns:bar .\n",
+	}, {
+		about:     "scp recursively from unit mysql/0 to unit mongodb/1",
+		args:      []string{"mysql/0:/var/backups/", "mongodb/1:/tmp/"},
+		recursive: true,
+		result:    commonArgsNoProxy + "-r -3 ubuntu@dummyenv-0.dns:/var/backups/ ubuntu@dummyenv-2.dns:/tmp/\n",
 	}, {
 		about: "scp with no such machine",
 		args:  []string{"5:foo", "bar"},
@@ -127,6 +141,7 @@ func (s *SCPSuite) TestSCPCommand(c *gc.C) {
 		ctx := coretesting.Context(c)
 		scpcmd := &SCPCommand{}
 		scpcmd.proxy = t.proxy
+		scpcmd.recursive = t.recursive
 
 		err := envcmd.Wrap(scpcmd).Init(t.args)
 		c.Check(err, gc.IsNil)
@@ -167,7 +182,7 @@ func dummyHostsFromTarget(target string) (string, error) {
 
 func (s *expandArgsSuite) TestSCPExpandArgs(c *gc.C) {
 	for i, t := range scpTests {
-		if t.error != "" {
+		if t.error != "" || t.recursive {
 			// We are just running a focused set of tests on
 			// expandArgs, we aren't implementing the full
 			// hostsFromTargets to actually trigger errors
@@ -210,6 +225,14 @@ var expandTests = []struct {
 		"don't expand params that start with '-'",
 		[]string{"-0:stuff", "0:foo", "."},
 		[]string{"-0:stuff", "ubuntu@dummyenv-0.dns:foo", "."},
+	}, {
+		"don't treat option values as the destination",
+		[]string{"0:foo", "mysql/0:/foo", "-o", "SomeOption"},
+		[]string{"-3", "ubuntu@dummyenv-0.dns:foo", "ubuntu@dummyenv-0.dns:/foo", "-o", "SomeOption"},
+	}, {
+		"don't add -3 twice",
+		[]string{"-3", "0:foo", "mysql/0:/foo"},
+		[]string{"-3", "ubuntu@dummyenv-0.dns:foo", "ubuntu@dummyenv-0.dns:/foo"},
 	},
 }
 
@@ -8,12 +8,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
+	"github.com/juju/names"
+	goyaml "gopkg.in/yaml.v1"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/envcmd"
 )
 
@@ -21,8 +25,10 @@ import (
 type SetCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName     string
+	Group           string
 	SettingsStrings map[string]string
 	SettingsYAML    cmd.FileVar
+	SettingsFile    cmd.FileVar
 }
 
 const setDoc = `
@@ -36,6 +42,15 @@ size of this value is 5M.
 
 Option values may be any UTF-8 encoded string. UTF-8 is accepted on the command
 line and in configuration files.
+
+The --file flag applies all the options in a YAML file mapping option names
+directly to values, as written by "juju get --values". Every option in the file
+is checked against the charm's configuration schema before any is changed, and
+all unknown options and values of the wrong type are reported together. An
+option with a null value is reset to its default.
+
+The --group flag may be used instead of a service name to set the options on
+every service with units in the named unit group.
 `
 
 const maxValueSize = 5242880
@@ -43,7 +58,7 @@ const maxValueSize = 5242880
 func (c *SetCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "set",
-		Args:    "<service> name=value ...",
+		Args:    "(<service> | --group <name>) name=value ...",
 		Purpose: "set service config options",
 		Doc:     setDoc,
 	}
@@ -51,15 +66,32 @@ func (c *SetCommand) Info() *cmd.Info {
 
 func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.SettingsYAML, "config", "path to yaml-formatted service config")
+	f.Var(&c.SettingsFile, "file", "path to yaml file of option values to apply")
+	f.StringVar(&c.Group, "group", "", "set options on the services of all units in the named unit group")
 }
 
 func (c *SetCommand) Init(args []string) error {
-	if len(args) == 0 || len(strings.Split(args[0], "=")) > 1 {
+	if c.Group != "" {
+		if len(args) > 0 && !strings.Contains(args[0], "=") {
+			return errors.New("cannot specify both a service name and --group")
+		}
+		// The remaining arguments are handled as if they followed a
+		// service name.
+		args = append([]string{""}, args...)
+	} else if len(args) == 0 || len(strings.Split(args[0], "=")) > 1 {
 		return errors.New("no service name specified")
 	}
 	if c.SettingsYAML.Path != "" && len(args) > 1 {
 		return errors.New("cannot specify --config when using key=value arguments")
 	}
+	if c.SettingsFile.Path != "" {
+		if c.SettingsYAML.Path != "" {
+			return errors.New("cannot specify both --config and --file")
+		}
+		if len(args) > 1 {
+			return errors.New("cannot specify --file when using key=value arguments")
+		}
+	}
 	c.ServiceName = args[0]
 	settings, err := parse(args[1:])
 	if err != nil {
@@ -69,7 +101,8 @@ func (c *SetCommand) Init(args []string) error {
 	return nil
 }
 
-// Run updates the configuration of a service.
+// Run updates the configuration of a service, or of the services of
+// the units in a unit group.
 func (c *SetCommand) Run(ctx *cmd.Context) error {
 	api, err := c.NewAPIClient()
 	if err != nil {
@@ -77,12 +110,54 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.Group == "" {
+		return c.setService(ctx, api, c.ServiceName)
+	}
+	services, err := groupServices(api, c.Group)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		if err := c.setService(ctx, api, service); err != nil {
+			return fmt.Errorf("cannot set options for service %q: %v", service, err)
+		}
+	}
+	return nil
+}
+
+// groupServices returns the names of the services with units in the
+// named unit group, in sorted order.
+func groupServices(client *api.Client, group string) ([]string, error) {
+	units, err := client.UnitGroupUnits(group)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var services []string
+	for _, unit := range units {
+		service := names.UnitService(unit)
+		if !seen[service] {
+			seen[service] = true
+			services = append(services, service)
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("unit group %q has no units", group)
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// setService updates the configuration of the named service.
+func (c *SetCommand) setService(ctx *cmd.Context, client *api.Client, serviceName string) error {
 	if c.SettingsYAML.Path != "" {
 		b, err := c.SettingsYAML.Read(ctx)
 		if err != nil {
 			return err
 		}
-		return api.ServiceSetYAML(c.ServiceName, string(b))
+		return client.ServiceSetYAML(serviceName, string(b))
+	} else if c.SettingsFile.Path != "" {
+		return c.setFromFile(ctx, client, serviceName)
 	} else if len(c.SettingsStrings) == 0 {
 		return nil
 	}
@@ -110,7 +185,94 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 		}
 		settings[k] = nv
 	}
-	return api.ServiceSet(c.ServiceName, settings)
+	return client.ServiceSet(serviceName, settings)
+}
+
+// setFromFile applies the option values in the file named by --file,
+// after checking all of them against the service's charm config.
+func (c *SetCommand) setFromFile(ctx *cmd.Context, client *api.Client, serviceName string) error {
+	b, err := c.SettingsFile.Read(ctx)
+	if err != nil {
+		return err
+	}
+	var settings map[string]interface{}
+	if err := goyaml.Unmarshal(b, &settings); err != nil {
+		return fmt.Errorf("cannot parse %q: %v", c.SettingsFile.Path, err)
+	}
+	if len(settings) == 0 {
+		return nil
+	}
+	current, err := client.ServiceGet(serviceName)
+	if err != nil {
+		return err
+	}
+	if err := checkSettings(current.Config, settings); err != nil {
+		return fmt.Errorf("invalid settings in %q:%v", c.SettingsFile.Path, err)
+	}
+	b, err = goyaml.Marshal(map[string]interface{}{serviceName: settings})
+	if err != nil {
+		return err
+	}
+	return client.ServiceSetYAML(serviceName, string(b))
+}
+
+// checkSettings checks settings against the option descriptions
+// returned by ServiceGet. The returned error lists every unknown
+// option and every value of the wrong type, one per line.
+func checkSettings(options map[string]interface{}, settings map[string]interface{}) error {
+	keys := make([]string, 0, len(settings))
+	for name := range settings {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	var problems []string
+	for _, name := range keys {
+		info, ok := options[name].(map[string]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: unknown option", name))
+			continue
+		}
+		optionType, _ := info["type"].(string)
+		if err := checkOptionValue(optionType, settings[name]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("\n  " + strings.Join(problems, "\n  "))
+}
+
+// checkOptionValue returns an error if value, as decoded from YAML, is
+// not valid for a charm config option of the given type. A nil value
+// is always valid, and resets the option to its default.
+func checkOptionValue(optionType string, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	var ok bool
+	switch optionType {
+	case "string":
+		_, ok = value.(string)
+	case "int":
+		switch value.(type) {
+		case int, int64:
+			ok = true
+		}
+	case "float":
+		switch value.(type) {
+		case int, int64, float64:
+			ok = true
+		}
+	case "boolean":
+		_, ok = value.(bool)
+	default:
+		return fmt.Errorf("option has unknown type %q", optionType)
+	}
+	if !ok {
+		return fmt.Errorf("expected %s, got %#v", optionType, value)
+	}
+	return nil
 }
 
 // parse parses the option k=v strings into a map of options to be
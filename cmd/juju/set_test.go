@@ -103,6 +103,73 @@ func (s *SetSuite) TestSetConfig(c *gc.C) {
 	})
 }
 
+func (s *SetSuite) TestSetFileInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"dummy-service", "--file", "a.yaml", "--config", "b.yaml"},
+		err:  "cannot specify both --config and --file",
+	}, {
+		args: []string{"dummy-service", "--file", "a.yaml", "username=foo"},
+		err:  "cannot specify --file when using key=value arguments",
+	}} {
+		c.Logf("test %d", i)
+		err := coretesting.InitCommand(envcmd.Wrap(&SetCommand{}), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SetSuite) TestSetFile(c *gc.C) {
+	err := s.svc.UpdateConfigSettings(charm.Settings{"outlook": "cloudy"})
+	c.Assert(err, gc.IsNil)
+	setupSettingsFile(c, s.dir, "settings.yaml", "skill-level: 9000\nusername: admin001\noutlook: null\n")
+	assertSetSuccess(c, s.dir, s.svc, []string{
+		"--file",
+		"settings.yaml",
+	}, charm.Settings{
+		"username":    "admin001",
+		"skill-level": int64(9000),
+	})
+}
+
+func (s *SetSuite) TestSetFileReportsAllProblems(c *gc.C) {
+	setupSettingsFile(c, s.dir, "settings.yaml", "skill-level: lots\nusername: admin001\nfoo: bar\ntitle: 42\n")
+	assertSetFail(c, s.dir, []string{
+		"--file",
+		"settings.yaml",
+	}, `error: invalid settings in "settings.yaml":
+  foo: unknown option
+  skill-level: expected int, got "lots"
+  title: expected string, got 42
+`)
+	settings, err := s.svc.ConfigSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
+
+func (s *SetSuite) TestSetGroup(c *gc.C) {
+	other := s.AddTestingService(c, "other-service", s.AddTestingCharm(c, "dummy"))
+	for _, svc := range []*state.Service{s.svc, other} {
+		_, err := svc.AddUnit()
+		c.Assert(err, gc.IsNil)
+	}
+	_, err := s.State.AddUnitGroup("canary", []string{"dummy-service/0", "other-service"})
+	c.Assert(err, gc.IsNil)
+
+	ctx := coretesting.ContextForDir(c, s.dir)
+	code := cmd.Main(envcmd.Wrap(&SetCommand{}), ctx, []string{"--group", "canary", "username=bob"})
+	c.Assert(code, gc.Equals, 0)
+	for _, svc := range []*state.Service{s.svc, other} {
+		settings, err := svc.ConfigSettings()
+		c.Assert(err, gc.IsNil)
+		c.Assert(settings, gc.DeepEquals, charm.Settings{"username": "bob"})
+	}
+
+	err = coretesting.InitCommand(envcmd.Wrap(&SetCommand{}), []string{"--group", "canary", "dummy-service", "username=bob"})
+	c.Assert(err, gc.ErrorMatches, "cannot specify both a service name and --group")
+}
+
 // assertSetSuccess sets configuration options and checks the expected settings.
 func assertSetSuccess(c *gc.C, dir string, svc *state.Service, args []string, expect charm.Settings) {
 	ctx := coretesting.ContextForDir(c, dir)
@@ -161,3 +228,13 @@ func setupConfigFile(c *gc.C, dir string) string {
 	c.Assert(err, gc.IsNil)
 	return path
 }
+
+// setupSettingsFile creates a file of option values for testing set
+// with the --file argument.
+func setupSettingsFile(c *gc.C, dir, filename, content string) string {
+	ctx := coretesting.ContextForDir(c, dir)
+	path := ctx.AbsPath(filename)
+	err := ioutil.WriteFile(path, []byte(content), 0666)
+	c.Assert(err, gc.IsNil)
+	return path
+}
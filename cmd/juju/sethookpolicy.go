@@ -0,0 +1,105 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const setHookPolicyDoc = `
+Sets how long the hooks of a service's units may run before they are
+killed, and how failed hooks are retried without user intervention.
+The following keys are accepted:
+
+    timeout=<duration>      kill hooks running longer than this (e.g. 10m)
+    retry-count=<n>         retry a failed hook up to n times
+    retry-delay=<duration>  wait this long before the first retry; the
+                            wait doubles with each further retry
+
+Any key not specified falls back to the environment's hook-timeout,
+hook-retry-count and hook-retry-delay settings. Running the command
+with no keys clears all of the service's overrides.
+
+Examples:
+
+    juju set-hook-policy mysql timeout=15m retry-count=3
+    juju set-hook-policy mysql
+`
+
+// SetHookPolicyCommand sets the hook timeout and retry policy of a
+// service.
+type SetHookPolicyCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Policy      params.ServiceHookPolicy
+}
+
+func (c *SetHookPolicyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-hook-policy",
+		Args:    "<service> [timeout=<duration>] [retry-count=<n>] [retry-delay=<duration>]",
+		Purpose: "set the hook timeout and retry policy of a service",
+		Doc:     setHookPolicyDoc,
+	}
+}
+
+func (c *SetHookPolicyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return fmt.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	for _, arg := range args[1:] {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return fmt.Errorf("expected \"key=value\", got %q", arg)
+		}
+		switch key, value := parts[0], parts[1]; key {
+		case "timeout", "retry-delay":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid %s %q", key, value)
+			}
+			if key == "timeout" {
+				c.Policy.Timeout = &d
+			} else {
+				c.Policy.RetryDelay = &d
+			}
+		case "retry-count":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid %s %q", key, value)
+			}
+			c.Policy.RetryCount = &n
+		default:
+			return fmt.Errorf("unknown hook policy key %q", key)
+		}
+	}
+	return nil
+}
+
+// Run replaces the hook policy overrides of the service.
+func (c *SetHookPolicyCommand) Run(_ *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.ServiceUpdate(params.ServiceUpdate{
+		ServiceName: c.ServiceName,
+		HookPolicy:  &c.Policy,
+	})
+}
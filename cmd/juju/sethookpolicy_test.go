@@ -0,0 +1,96 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type SetHookPolicySuite struct {
+	jujutesting.JujuConnSuite
+	svc *state.Service
+}
+
+var _ = gc.Suite(&SetHookPolicySuite{})
+
+func (s *SetHookPolicySuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.svc = s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+}
+
+func runSetHookPolicy(c *gc.C, args ...string) error {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&SetHookPolicyCommand{}), args...)
+	return err
+}
+
+func (s *SetHookPolicySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service name specified",
+	}, {
+		args: []string{"dummy/0"},
+		err:  `invalid service name "dummy/0"`,
+	}, {
+		args: []string{"dummy", "timeout"},
+		err:  `expected "key=value", got "timeout"`,
+	}, {
+		args: []string{"dummy", "timeout=soon"},
+		err:  `invalid timeout "soon"`,
+	}, {
+		args: []string{"dummy", "retry-delay=-1s"},
+		err:  `invalid retry-delay "-1s"`,
+	}, {
+		args: []string{"dummy", "retry-count=-1"},
+		err:  `invalid retry-count "-1"`,
+	}, {
+		args: []string{"dummy", "color=blue"},
+		err:  `unknown hook policy key "color"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&SetHookPolicyCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *SetHookPolicySuite) TestSetHookPolicy(c *gc.C) {
+	err := runSetHookPolicy(c, "dummy-service", "timeout=10m", "retry-count=3")
+	c.Assert(err, gc.IsNil)
+	timeout, count := 10*time.Minute, 3
+	err = s.svc.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.svc.HookPolicy(), gc.DeepEquals, state.HookPolicy{
+		Timeout:    &timeout,
+		RetryCount: &count,
+	})
+
+	// Running the command again replaces all overrides.
+	err = runSetHookPolicy(c, "dummy-service", "retry-delay=1m")
+	c.Assert(err, gc.IsNil)
+	delay := time.Minute
+	err = s.svc.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.svc.HookPolicy(), gc.DeepEquals, state.HookPolicy{
+		RetryDelay: &delay,
+	})
+
+	err = runSetHookPolicy(c, "dummy-service")
+	c.Assert(err, gc.IsNil)
+	err = s.svc.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.svc.HookPolicy(), gc.DeepEquals, state.HookPolicy{})
+}
+
+func (s *SetHookPolicySuite) TestSetHookPolicyUnknownService(c *gc.C) {
+	err := runSetHookPolicy(c, "unknown", "timeout=1m")
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+}
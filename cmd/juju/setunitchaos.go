@@ -0,0 +1,99 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const setUnitChaosDoc = `
+Makes a unit's agent inject faults into its hook execution, so that
+charms can be tested against failures they will meet in production.
+The environment's enable-chaos-testing setting must be true. The
+following keys are accepted:
+
+    fail-hooks=<hook>,...   fail the named hooks without running them;
+                            "*" fails every hook
+    hook-delay=<duration>   wait this long before running each hook
+    restart=true            restart the unit's agent before its next hook
+
+Running the command with no keys clears all of the unit's faults.
+
+Examples:
+
+    juju set-unit-chaos mysql/0 fail-hooks=install,db-relation-joined
+    juju set-unit-chaos mysql/0 hook-delay=30s restart=true
+    juju set-unit-chaos mysql/0
+`
+
+// SetUnitChaosCommand sets the faults that a unit's agent injects
+// into its hook execution.
+type SetUnitChaosCommand struct {
+	envcmd.EnvCommandBase
+	Chaos params.SetUnitChaos
+}
+
+func (c *SetUnitChaosCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-unit-chaos",
+		Args:    "<unit> [fail-hooks=<hook>,...] [hook-delay=<duration>] [restart=true]",
+		Purpose: "inject faults into a unit's hook execution",
+		Doc:     setUnitChaosDoc,
+	}
+}
+
+func (c *SetUnitChaosCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit name specified")
+	}
+	if !names.IsValidUnit(args[0]) {
+		return fmt.Errorf("invalid unit name %q", args[0])
+	}
+	c.Chaos.UnitName = args[0]
+	for _, arg := range args[1:] {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return fmt.Errorf("expected \"key=value\", got %q", arg)
+		}
+		switch key, value := parts[0], parts[1]; key {
+		case "fail-hooks":
+			c.Chaos.FailHooks = strings.Split(value, ",")
+		case "hook-delay":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid %s %q", key, value)
+			}
+			c.Chaos.HookDelay = d
+		case "restart":
+			restart, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q", key, value)
+			}
+			c.Chaos.Restart = restart
+		default:
+			return fmt.Errorf("unknown chaos key %q", key)
+		}
+	}
+	return nil
+}
+
+// Run replaces the faults injected by the unit's agent.
+func (c *SetUnitChaosCommand) Run(_ *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.SetUnitChaos(c.Chaos)
+}
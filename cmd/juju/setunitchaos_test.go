@@ -0,0 +1,90 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type SetUnitChaosSuite struct {
+	jujutesting.JujuConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&SetUnitChaosSuite{})
+
+func (s *SetUnitChaosSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	svc := s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	var err error
+	s.unit, err = svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+}
+
+func runSetUnitChaos(c *gc.C, args ...string) error {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&SetUnitChaosCommand{}), args...)
+	return err
+}
+
+func (s *SetUnitChaosSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no unit name specified",
+	}, {
+		args: []string{"dummy"},
+		err:  `invalid unit name "dummy"`,
+	}, {
+		args: []string{"dummy/0", "restart"},
+		err:  `expected "key=value", got "restart"`,
+	}, {
+		args: []string{"dummy/0", "hook-delay=soon"},
+		err:  `invalid hook-delay "soon"`,
+	}, {
+		args: []string{"dummy/0", "restart=maybe"},
+		err:  `invalid restart "maybe"`,
+	}, {
+		args: []string{"dummy/0", "color=blue"},
+		err:  `unknown chaos key "color"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&SetUnitChaosCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *SetUnitChaosSuite) TestSetUnitChaosDisabled(c *gc.C) {
+	err := runSetUnitChaos(c, "dummy-service/0", "restart=true")
+	c.Assert(err, gc.ErrorMatches, "chaos testing is disabled; set enable-chaos-testing to enable it")
+}
+
+func (s *SetUnitChaosSuite) TestSetUnitChaos(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"enable-chaos-testing": true,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	err = runSetUnitChaos(c, "dummy-service/0", "fail-hooks=install,start", "hook-delay=5s")
+	c.Assert(err, gc.IsNil)
+	chaos, err := s.unit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos, gc.DeepEquals, state.UnitChaos{
+		FailHooks: []string{"install", "start"},
+		HookDelay: 5 * time.Second,
+	})
+
+	err = runSetUnitChaos(c, "dummy-service/0")
+	c.Assert(err, gc.IsNil)
+	chaos, err = s.unit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos, gc.DeepEquals, state.UnitChaos{})
+}
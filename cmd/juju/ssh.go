@@ -17,6 +17,7 @@ import (
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/utils/ssh"
 )
 
@@ -28,16 +29,20 @@ type SSHCommand struct {
 // SSHCommon provides common methods for SSHCommand, SCPCommand and DebugHooksCommand.
 type SSHCommon struct {
 	envcmd.EnvCommandBase
-	proxy     bool
-	pty       bool
-	Target    string
-	Args      []string
-	apiClient sshAPIClient
-	apiAddr   string
+	proxy        bool
+	proxyPrivate bool
+	proxyAllowed bool
+	proxyNeeded  bool
+	pty          bool
+	Target       string
+	Args         []string
+	apiClient    sshAPIClient
+	apiAddr      string
 }
 
 func (c *SSHCommon) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.proxy, "proxy", true, "proxy through the API server")
+	f.BoolVar(&c.proxyPrivate, "proxy-private", false, "proxy through the API server if the target has no public address")
 	f.BoolVar(&c.pty, "pty", true, "enable pseudo-tty allocation")
 }
 
@@ -78,6 +83,15 @@ Connect to the first mysql unit:
 Connect to the first mysql unit and run 'ls -la /var/log/juju':
 
     juju ssh mysql/0 ls -la /var/log/juju
+
+Connect to machine 3, which is on a private subnet, through the API server:
+
+    juju ssh --proxy-private 3
+
+If the environment's "proxy-ssh" setting is true, connections are always
+proxied through the API server. Otherwise the --proxy-private flag causes
+them to be proxied only when the target has no publicly routable address,
+so that machines on private subnets can be reached without further setup.
 `
 
 func (c *SSHCommand) Info() *cmd.Info {
@@ -114,6 +128,7 @@ func (c *SSHCommon) getSSHOptions(enablePty bool) (*ssh.Options, error) {
 		options.EnablePTY()
 	}
 	var err error
+	c.proxyAllowed = c.proxy
 	if c.proxy, err = c.proxySSH(); err != nil {
 		return nil, err
 	} else if c.proxy {
@@ -146,6 +161,9 @@ func (c *SSHCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := c.ensureProxyCommand(options); err != nil {
+		return err
+	}
 	cmd := ssh.Command("ubuntu@"+host, c.Args, options)
 	cmd.Stdin = ctx.Stdin
 	cmd.Stdout = ctx.Stdout
@@ -153,6 +171,16 @@ func (c *SSHCommand) Run(ctx *cmd.Context) error {
 	return cmd.Run()
 }
 
+// ensureProxyCommand sets the proxy command option if hostFromTarget
+// found that a target can only be reached through the API server.
+func (c *SSHCommon) ensureProxyCommand(options *ssh.Options) error {
+	if c.proxy || !c.proxyNeeded {
+		return nil
+	}
+	c.proxy = true
+	return c.setProxyCommand(options)
+}
+
 // proxySSH returns true iff both c.proxy and
 // the proxy-ssh environment configuration
 // are true.
@@ -241,6 +269,11 @@ func (c *SSHCommon) hostFromTarget(target string) (string, error) {
 			addr, err = c.apiClient.PrivateAddress(target)
 		} else {
 			addr, err = c.apiClient.PublicAddress(target)
+			if err == nil && c.proxyAllowed && c.proxyPrivate && !isPublicAddress(addr) {
+				logger.Debugf("%q has no public address, proxying through the API server", target)
+				addr, err = c.apiClient.PrivateAddress(target)
+				c.proxyNeeded = c.proxyNeeded || err == nil
+			}
 		}
 		if err == nil {
 			return addr, nil
@@ -249,6 +282,18 @@ func (c *SSHCommon) hostFromTarget(target string) (string, error) {
 	return "", err
 }
 
+// isPublicAddress reports whether the given address, as returned
+// by the PublicAddress API call, may be reachable from outside the
+// environment. The API server falls back to cloud-local addresses
+// when a target has no public address.
+func isPublicAddress(addr string) bool {
+	switch network.NewAddress(addr, network.ScopeUnknown).Scope {
+	case network.ScopeCloudLocal, network.ScopeMachineLocal, network.ScopeLinkLocal:
+		return false
+	}
+	return true
+}
+
 // AllowInterspersedFlags for ssh/scp is set to false so that
 // flags after the unit name are passed through to ssh, for eg.
 // `juju ssh -v service-name/0 uname -a`.
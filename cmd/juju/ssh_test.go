@@ -140,6 +140,35 @@ func (s *SSHSuite) TestSSHCommandEnvironProxySSH(c *gc.C) {
 	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, sshArgsNoProxy+"ubuntu@dummyenv-0.dns\n")
 }
 
+func (s *SSHSuite) TestSSHCommandProxyPrivate(c *gc.C) {
+	m := s.makeMachines(1, c, false)
+	err := m[0].SetAddresses(network.NewAddress("10.0.0.5", network.ScopeUnknown))
+	c.Assert(err, gc.IsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"proxy-ssh": false}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	// Without --proxy-private the cloud-local address is used directly.
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&SSHCommand{}), "0")
+	c.Assert(err, gc.IsNil)
+	c.Check(coretesting.Stdout(ctx), gc.Equals, sshArgsNoProxy+"ubuntu@10.0.0.5\n")
+
+	// With it, the connection is proxied through the API server.
+	ctx, err = coretesting.RunCommand(c, envcmd.Wrap(&SSHCommand{}), "--proxy-private", "0")
+	c.Assert(err, gc.IsNil)
+	c.Check(coretesting.Stdout(ctx), gc.Equals, sshArgs+"ubuntu@10.0.0.5\n")
+
+	// --proxy=false disables proxying altogether.
+	ctx, err = coretesting.RunCommand(c, envcmd.Wrap(&SSHCommand{}), "--proxy=false", "--proxy-private", "0")
+	c.Assert(err, gc.IsNil)
+	c.Check(coretesting.Stdout(ctx), gc.Equals, sshArgsNoProxy+"ubuntu@10.0.0.5\n")
+
+	// Targets with a public address are not proxied.
+	s.setAddresses(m[0], c)
+	ctx, err = coretesting.RunCommand(c, envcmd.Wrap(&SSHCommand{}), "--proxy-private", "0")
+	c.Assert(err, gc.IsNil)
+	c.Check(coretesting.Stdout(ctx), gc.Equals, sshArgsNoProxy+"ubuntu@dummyenv-0.dns\n")
+}
+
 func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
 	// Check the API client interface used by "juju ssh" against what
 	// the API server will allow during upgrades. Ensure that the API
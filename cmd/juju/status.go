@@ -6,6 +6,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/juju/cmd"
 	"launchpad.net/gnuflag"
@@ -21,6 +22,9 @@ type StatusCommand struct {
 	envcmd.EnvCommandBase
 	out      cmd.Output
 	patterns []string
+	utc      bool
+	relative bool
+	noStore  bool
 }
 
 var statusDoc = `
@@ -52,6 +56,14 @@ will be displayed.
 Wildcards ('*') may be specified in service/unit names to match any sequence
 of characters. For example, 'nova-*' will match any service whose name begins
 with 'nova-': 'nova-compute', 'nova-volume', etc.
+
+The time each machine and unit agent entered its current state is shown
+as agent-state-since, in local time by default. Use --utc to show it in
+UTC, or --relative to show how long ago it was.
+
+Newer charm revisions are reported as can-upgrade-to. Use --no-store to
+leave them out, or set offline-mode in the environment configuration to
+stop Juju from contacting the charm store at all.
 `
 
 func (c *StatusCommand) Info() *cmd.Info {
@@ -72,9 +84,15 @@ func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 		"tabular": FormatTabular,
 		"summary": FormatSummary,
 	})
+	f.BoolVar(&c.utc, "utc", false, "display times in UTC")
+	f.BoolVar(&c.relative, "relative", false, "display times relative to now")
+	f.BoolVar(&c.noStore, "no-store", false, "do not report newer charm revisions from the charm store")
 }
 
 func (c *StatusCommand) Init(args []string) error {
+	if c.utc && c.relative {
+		return fmt.Errorf("cannot specify both --utc and --relative")
+	}
 	c.patterns = args
 	return nil
 }
@@ -88,6 +106,7 @@ Error details:
 
 type statusAPI interface {
 	Status(patterns []string) (*api.Status, error)
+	StatusNoStore(patterns []string) (*api.Status, error)
 	Close() error
 }
 
@@ -103,7 +122,11 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiclient.Close()
 
-	status, err := apiclient.Status(c.patterns)
+	getStatus := apiclient.Status
+	if c.noStore {
+		getStatus = apiclient.StatusNoStore
+	}
+	status, err := getStatus(c.patterns)
 	if err != nil {
 		if status == nil {
 			// Status call completely failed, there is nothing to report
@@ -113,10 +136,51 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 		fmt.Fprintf(ctx.Stderr, "%v\n", err)
 	}
 
-	result := newStatusFormatter(status).format()
+	formatter := newStatusFormatter(status)
+	formatter.formatTime = c.timeFormatter()
+	result := formatter.format()
 	return c.out.Write(ctx, result)
 }
 
+// statusTimeLayout is used to render status times.
+const statusTimeLayout = "02 Jan 2006 15:04:05Z07:00"
+
+// statusNow is replaced in tests.
+var statusNow = time.Now
+
+// timeFormatter returns the function used to render status times,
+// according to the command's flags.
+func (c *StatusCommand) timeFormatter() func(time.Time) string {
+	switch {
+	case c.utc:
+		return func(t time.Time) string {
+			return t.UTC().Format(statusTimeLayout)
+		}
+	case c.relative:
+		return func(t time.Time) string {
+			return relativeTime(t, statusNow())
+		}
+	}
+	return func(t time.Time) string {
+		return t.Local().Format(statusTimeLayout)
+	}
+}
+
+// relativeTime describes t as a time before now, in the largest whole
+// unit that fits.
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+}
+
 type formattedStatus struct {
 	Environment string                   `json:"environment"`
 	Machines    map[string]machineStatus `json:"machines"`
@@ -132,6 +196,7 @@ type machineStatus struct {
 	Err            error                    `json:"-" yaml:",omitempty"`
 	AgentState     params.Status            `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
 	AgentStateInfo string                   `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
+	AgentSince     string                   `json:"agent-state-since,omitempty" yaml:"agent-state-since,omitempty"`
 	AgentVersion   string                   `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
 	DNSName        string                   `json:"dns-name,omitempty" yaml:"dns-name,omitempty"`
 	InstanceId     instance.Id              `json:"instance-id,omitempty" yaml:"instance-id,omitempty"`
@@ -141,6 +206,8 @@ type machineStatus struct {
 	Id             string                   `json:"-" yaml:"-"`
 	Containers     map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
 	Hardware       string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
+	Utilization    string                   `json:"hardware-utilization,omitempty" yaml:"hardware-utilization,omitempty"`
+	Constraints    string                   `json:"constraints,omitempty" yaml:"constraints,omitempty"`
 	HAStatus       string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
 }
 
@@ -170,10 +237,12 @@ type serviceStatus struct {
 	Err           error                 `json:"-" yaml:",omitempty"`
 	Charm         string                `json:"charm" yaml:"charm"`
 	CanUpgradeTo  string                `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
+	CharmPinned   bool                  `json:"charm-pinned,omitempty" yaml:"charm-pinned,omitempty"`
 	Exposed       bool                  `json:"exposed" yaml:"exposed"`
 	Life          string                `json:"life,omitempty" yaml:"life,omitempty"`
 	Relations     map[string][]string   `json:"relations,omitempty" yaml:"relations,omitempty"`
 	Networks      map[string][]string   `json:"networks,omitempty" yaml:"networks,omitempty"`
+	Constraints   string                `json:"constraints,omitempty" yaml:"constraints,omitempty"`
 	SubordinateTo []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
 	Units         map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
 }
@@ -201,6 +270,7 @@ type unitStatus struct {
 	Charm          string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
 	AgentState     params.Status         `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
 	AgentStateInfo string                `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
+	AgentSince     string                `json:"agent-state-since,omitempty" yaml:"agent-state-since,omitempty"`
 	AgentVersion   string                `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
 	Life           string                `json:"life,omitempty" yaml:"life,omitempty"`
 	Machine        string                `json:"machine,omitempty" yaml:"machine,omitempty"`
@@ -252,14 +322,18 @@ func (n networkStatus) GetYAML() (tag string, value interface{}) {
 }
 
 type statusFormatter struct {
-	status    *api.Status
-	relations map[int]api.RelationStatus
+	status     *api.Status
+	relations  map[int]api.RelationStatus
+	formatTime func(time.Time) string
 }
 
 func newStatusFormatter(status *api.Status) *statusFormatter {
 	sf := statusFormatter{
 		status:    status,
 		relations: make(map[int]api.RelationStatus),
+		formatTime: func(t time.Time) string {
+			return t.Local().Format(statusTimeLayout)
+		},
 	}
 	for _, relation := range status.Relations {
 		sf.relations[relation.Id] = relation
@@ -310,6 +384,7 @@ func (sf *statusFormatter) formatMachine(machine api.MachineStatus) machineStatu
 			Id:             machine.Id,
 			Containers:     make(map[string]machineStatus),
 			Hardware:       machine.Hardware,
+			Constraints:    machine.Constraints,
 		}
 	} else {
 		// New server
@@ -327,9 +402,14 @@ func (sf *statusFormatter) formatMachine(machine api.MachineStatus) machineStatu
 			Id:             machine.Id,
 			Containers:     make(map[string]machineStatus),
 			Hardware:       machine.Hardware,
+			Constraints:    machine.Constraints,
+			Utilization:    machine.HardwareUtilization,
 		}
 	}
 
+	if out.AgentState != "" {
+		out.AgentSince = sf.formatSince(machine.Agent.Since)
+	}
 	for k, m := range machine.Containers {
 		out.Containers[k] = sf.formatMachine(m)
 	}
@@ -351,7 +431,9 @@ func (sf *statusFormatter) formatService(name string, service api.ServiceStatus)
 		Life:          service.Life,
 		Relations:     service.Relations,
 		Networks:      make(map[string][]string),
+		Constraints:   service.Constraints,
 		CanUpgradeTo:  service.CanUpgradeTo,
+		CharmPinned:   service.CharmPinned,
 		SubordinateTo: service.SubordinateTo,
 		Units:         make(map[string]unitStatus),
 	}
@@ -380,12 +462,24 @@ func (sf *statusFormatter) formatUnit(unit api.UnitStatus, serviceName string) u
 		Charm:          unit.Charm,
 		Subordinates:   make(map[string]unitStatus),
 	}
+	if out.AgentState != "" {
+		out.AgentSince = sf.formatSince(unit.Agent.Since)
+	}
 	for k, m := range unit.Subordinates {
 		out.Subordinates[k] = sf.formatUnit(m, serviceName)
 	}
 	return out
 }
 
+// formatSince renders the time an agent entered its current state,
+// or returns the empty string if the time is not known.
+func (sf *statusFormatter) formatSince(since *time.Time) string {
+	if since == nil {
+		return ""
+	}
+	return sf.formatTime(*since)
+}
+
 func (sf *statusFormatter) getUnitStatusInfo(unit api.UnitStatus, serviceName string) string {
 	if unit.Agent.Status == "" {
 		// Old server that doesn't support this field and others.
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	jc "github.com/juju/testing/checkers"
@@ -354,6 +355,7 @@ var statusTests = []testCase{
 							"enabled":  L{"net1", "net2"},
 							"disabled": L{"foo", "bar", "no", "good"},
 						},
+						"constraints": "networks=foo,bar,^no,^good",
 					},
 					"no-networks-service": M{
 						"charm":   "cs:quantal/dummy-1",
@@ -361,6 +363,7 @@ var statusTests = []testCase{
 						"networks": M{
 							"disabled": L{"mynet"},
 						},
+						"constraints": "networks=^mynet",
 					},
 				},
 				"networks": M{
@@ -396,6 +399,7 @@ var statusTests = []testCase{
 						"instance-id":                "dummyenv-0",
 						"series":                     "quantal",
 						"hardware":                   "arch=amd64 cpu-cores=2 mem=8192M root-disk=8192M",
+						"constraints":                "cpu-cores=2 mem=8192M root-disk=8192M",
 						"state-server-member-status": "adding-vote",
 					},
 				},
@@ -417,6 +421,7 @@ var statusTests = []testCase{
 						"instance-id":                "dummyenv-0",
 						"series":                     "quantal",
 						"hardware":                   "arch=amd64 cpu-cores=2 mem=8192M root-disk=8192M",
+						"constraints":                "cpu-cores=2 mem=8192M root-disk=8192M",
 						"state-server-member-status": "adding-vote",
 					},
 				},
@@ -2046,14 +2051,36 @@ func (e scopedExpect) step(c *gc.C, ctx *context) {
 		err = format.unmarshal(buf, &expected)
 		c.Assert(err, gc.IsNil)
 
-		// Check the output is as expected.
+		// Check the output is as expected. The times at which agents
+		// entered their states vary from run to run, and are checked
+		// separately.
 		actual := make(M)
 		err = format.unmarshal(stdout, &actual)
 		c.Assert(err, gc.IsNil)
+		removeAgentStateSince(actual)
 		c.Assert(actual, jc.DeepEquals, expected)
 	}
 }
 
+// removeAgentStateSince removes all agent-state-since entries from
+// unmarshalled status output.
+func removeAgentStateSince(v interface{}) {
+	switch v := v.(type) {
+	case M:
+		removeAgentStateSince(map[string]interface{}(v))
+	case map[string]interface{}:
+		delete(v, "agent-state-since")
+		for _, value := range v {
+			removeAgentStateSince(value)
+		}
+	case map[interface{}]interface{}:
+		delete(v, "agent-state-since")
+		for _, value := range v {
+			removeAgentStateSince(value)
+		}
+	}
+}
+
 func (e expect) step(c *gc.C, ctx *context) {
 	scopedExpect{e.what, nil, e.output}.step(c, ctx)
 }
@@ -2073,6 +2100,7 @@ func (s *StatusSuite) TestStatusAllFormats(c *gc.C) {
 type fakeApiClient struct {
 	statusReturn *api.Status
 	patternsUsed []string
+	noStoreUsed  bool
 	closeCalled  bool
 }
 
@@ -2087,6 +2115,11 @@ func (a *fakeApiClient) Status(patterns []string) (*api.Status, error) {
 	return a.statusReturn, nil
 }
 
+func (a *fakeApiClient) StatusNoStore(patterns []string) (*api.Status, error) {
+	a.noStoreUsed = true
+	return a.Status(patterns)
+}
+
 func (a *fakeApiClient) Close() error {
 	a.closeCalled = true
 	return nil
@@ -2707,3 +2740,99 @@ func (s *StatusSuite) TestFilterMultipleHeterogenousPatterns(c *gc.C) {
 
 	c.Assert(string(stdout), gc.Equals, expected[1:])
 }
+
+func (s *StatusSuite) TestStatusAgentStateSince(c *gc.C) {
+	since := time.Date(2014, 10, 1, 12, 30, 0, 0, time.UTC)
+	client := newFakeApiClient(&api.Status{
+		EnvironmentName: "dummyenv",
+		Machines: map[string]api.MachineStatus{
+			"0": {
+				Agent:      api.AgentStatus{Status: "started", Since: &since},
+				Id:         "0",
+				InstanceId: instance.Id("dummyenv-0"),
+				AgentState: "started",
+				Series:     "quantal",
+				Containers: map[string]api.MachineStatus{},
+				Jobs:       []params.MachineJob{params.JobHostUnits},
+			},
+		},
+		Services: map[string]api.ServiceStatus{
+			"mysql": api.ServiceStatus{
+				Charm: "local:quantal/mysql-1",
+				Units: map[string]api.UnitStatus{
+					"mysql/0": api.UnitStatus{
+						Agent:          api.AgentStatus{Status: "error", Info: "blam", Since: &since},
+						Machine:        "0",
+						AgentState:     "error",
+						AgentStateInfo: "blam",
+					},
+				},
+			},
+		},
+	})
+	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
+		return &client, nil
+	})
+	s.PatchValue(&statusNow, func() time.Time {
+		return since.Add(90 * time.Minute)
+	})
+
+	for i, test := range []struct {
+		args     []string
+		expected string
+	}{{
+		args:     []string{"--utc"},
+		expected: "01 Oct 2014 12:30:00Z",
+	}, {
+		args:     []string{"--relative"},
+		expected: "1h ago",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		code, stdout, stderr := runStatus(c, append([]string{"--format", "json"}, test.args...)...)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(string(stderr), gc.Equals, "")
+		var out M
+		err := json.Unmarshal(stdout, &out)
+		c.Assert(err, gc.IsNil)
+		machine := out["machines"].(map[string]interface{})["0"].(map[string]interface{})
+		c.Check(machine["agent-state-since"], gc.Equals, test.expected)
+		service := out["services"].(map[string]interface{})["mysql"].(map[string]interface{})
+		unit := service["units"].(map[string]interface{})["mysql/0"].(map[string]interface{})
+		c.Check(unit["agent-state-since"], gc.Equals, test.expected)
+	}
+}
+
+func (s *StatusSuite) TestStatusUTCAndRelativeConflict(c *gc.C) {
+	code, _, stderr := runStatus(c, "--utc", "--relative")
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(string(stderr), gc.Equals, "error: cannot specify both --utc and --relative\n")
+}
+
+func (s *StatusSuite) TestRelativeTime(c *gc.C) {
+	base := time.Date(2014, 10, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		ago      time.Duration
+		expected string
+	}{
+		{30 * time.Second, "30s ago"},
+		{5 * time.Minute, "5m ago"},
+		{3*time.Hour + 20*time.Minute, "3h ago"},
+		{50 * time.Hour, "2d ago"},
+	} {
+		c.Check(relativeTime(base.Add(-test.ago), base), gc.Equals, test.expected)
+	}
+}
+
+func (s *StatusSuite) TestStatusNoStore(c *gc.C) {
+	client := newFakeApiClient(&api.Status{
+		EnvironmentName: "dummyenv",
+	})
+	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
+		return &client, nil
+	})
+	code, _, stderr := runStatus(c, "--no-store", "mysql")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(string(stderr), gc.Equals, "")
+	c.Assert(client.noStoreUsed, jc.IsTrue)
+	c.Assert(client.patternsUsed, gc.DeepEquals, []string{"mysql"})
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const statusHistoryDoc = `
+Show the most recent status transitions of a unit's agent, newest first,
+so that what happened before the current status can be seen. Times are
+shown in local time by default; use --utc to show them in UTC.
+
+Examples:
+
+    juju status-history mysql/0
+    juju status-history -n 5 mysql/0
+
+See Also:
+   juju status
+`
+
+// StatusHistoryCommand shows the recent status transitions of a unit.
+type StatusHistoryCommand struct {
+	envcmd.EnvCommandBase
+	out      cmd.Output
+	UnitName string
+	Size     int
+	utc      bool
+}
+
+// StatusHistoryInfo holds a single status transition shown by
+// StatusHistoryCommand.
+type StatusHistoryInfo struct {
+	Since  string `yaml:"since" json:"since"`
+	Status string `yaml:"status" json:"status"`
+	Info   string `yaml:"info,omitempty" json:"info,omitempty"`
+}
+
+func (c *StatusHistoryCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "status-history",
+		Args:    "<unit>",
+		Purpose: "show the recent status transitions of a unit",
+		Doc:     statusHistoryDoc,
+	}
+}
+
+func (c *StatusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatStatusHistoryTabular,
+	})
+	f.IntVar(&c.Size, "n", 20, "number of transitions to show")
+	f.BoolVar(&c.utc, "utc", false, "display times in UTC")
+}
+
+func (c *StatusHistoryCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit specified")
+	}
+	c.UnitName, args = args[0], args[1:]
+	if !names.IsValidUnit(c.UnitName) {
+		return errors.Errorf("invalid unit name %q", c.UnitName)
+	}
+	if c.Size <= 0 {
+		return errors.Errorf("invalid number of transitions %d", c.Size)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	history, err := client.UnitStatusHistory(c.UnitName, c.Size)
+	if err != nil {
+		return err
+	}
+	infos := make([]StatusHistoryInfo, len(history))
+	for i, entry := range history {
+		since := entry.Since.Local()
+		if c.utc {
+			since = entry.Since.UTC()
+		}
+		infos[i] = StatusHistoryInfo{
+			Since:  since.Format(statusTimeLayout),
+			Status: string(entry.Status),
+			Info:   entry.Info,
+		}
+	}
+	return c.out.Write(ctx, infos)
+}
+
+func formatStatusHistoryTabular(value interface{}) ([]byte, error) {
+	infos, ok := value.([]StatusHistoryInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", infos, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "SINCE\tSTATUS\tINFO\n")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", info.Since, info.Status, info.Info)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
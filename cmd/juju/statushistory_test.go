@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type StatusHistorySuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+func (s *StatusHistorySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no unit specified"},
+		{[]string{"mysql"}, `invalid unit name "mysql"`},
+		{[]string{"-n", "0", "mysql/0"}, "invalid number of transitions 0"},
+		{[]string{"mysql/0", "extra"}, `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *StatusHistorySuite) TestStatusHistory(c *gc.C) {
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(state.StatusInstalled, "", nil)
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(state.StatusError, "hook failed", nil)
+	c.Assert(err, gc.IsNil)
+
+	context, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "--format", "yaml", "--utc", "wordpress/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Matches, `
+- since: .*Z
+  status: error
+  info: hook failed
+- since: .*Z
+  status: installed
+`[1:])
+
+	context, err = testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "-n", "1", "wordpress/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Matches, "SINCE +STATUS +INFO\n.* +error +hook failed\n")
+}
@@ -5,7 +5,11 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/loggo"
@@ -33,10 +37,12 @@ type SyncToolsCommand struct {
 	dryRun       bool
 	dev          bool
 	public       bool
+	onlySigned   bool
 	source       string
 	stream       string
 	localDir     string
 	destination  string
+	tarball      string
 }
 
 var _ cmd.Command = (*SyncToolsCommand)(nil)
@@ -54,6 +60,16 @@ access the Internet. Alternatively you can specify a local directory as source.
 Sometimes this is because the environment does not have public access,
 and sometimes you just want to avoid having to access data outside of
 the local cloud.
+
+With --require-signed, tools are only copied if they are described by
+simplestreams metadata whose signature can be verified, so that tools
+from a tampered mirror are not copied into the environment. The
+require-signed-tools environment setting does the same for the tools
+that the environment itself finds for its agents.
+
+With --tarball, a single tools tarball named juju-<version>-<series>-<arch>.tgz
+is uploaded directly to the environment, for upgrading environments
+that have no access to any tools source.
 `,
 	}
 }
@@ -64,10 +80,12 @@ func (c *SyncToolsCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.dryRun, "dry-run", false, "don't copy, just print what would be copied")
 	f.BoolVar(&c.dev, "dev", false, "consider development versions as well as released ones\n    DEPRECATED: use --stream instead")
 	f.BoolVar(&c.public, "public", false, "tools are for a public cloud, so generate mirrors information")
+	f.BoolVar(&c.onlySigned, "require-signed", false, "only copy tools described by signed metadata")
 	f.StringVar(&c.source, "source", "", "local source directory")
 	f.StringVar(&c.stream, "stream", "", "simplestreams stream for which to sync metadata")
 	f.StringVar(&c.localDir, "local-dir", "", "local destination directory")
 	f.StringVar(&c.destination, "destination", "", "local destination directory")
+	f.StringVar(&c.tarball, "tarball", "", "upload a single local tools tarball to the environment")
 }
 
 func (c *SyncToolsCommand) Init(args []string) error {
@@ -88,9 +106,28 @@ func (c *SyncToolsCommand) Init(args []string) error {
 	if c.stream == "" {
 		c.stream = envtools.ReleasedStream
 	}
+	if c.tarball != "" {
+		if c.localDir != "" || c.source != "" || c.allVersions || c.versionStr != "" {
+			return fmt.Errorf("--tarball cannot be used with --local-dir, --source, --all or --version")
+		}
+	}
 	return cmd.CheckEmpty(args)
 }
 
+// toolsTarballVersion returns the version of the tools in the tarball
+// with the given path, taken from its file name.
+func toolsTarballVersion(path string) (version.Binary, error) {
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, "juju-") || !strings.HasSuffix(name, ".tgz") {
+		return version.Binary{}, fmt.Errorf("invalid tools tarball name %q: expected juju-<version>-<series>-<arch>.tgz", name)
+	}
+	v, err := version.ParseBinary(strings.TrimSuffix(strings.TrimPrefix(name, "juju-"), ".tgz"))
+	if err != nil {
+		return version.Binary{}, fmt.Errorf("invalid tools tarball name %q: %v", name, err)
+	}
+	return v, nil
+}
+
 // syncToolsAPI provides an interface with a subset of the
 // api.Client API. This exists to enable mocking.
 type syncToolsAPI interface {
@@ -108,6 +145,10 @@ func (c *SyncToolsCommand) Run(ctx *cmd.Context) (resultErr error) {
 	loggo.RegisterWriter("synctools", cmd.NewCommandLogWriter("juju.environs.sync", ctx.Stdout, ctx.Stderr), loggo.INFO)
 	defer loggo.RemoveWriter("synctools")
 
+	if c.tarball != "" {
+		return c.uploadTarball(ctx)
+	}
+
 	sctx := &sync.SyncContext{
 		AllVersions:  c.allVersions,
 		MajorVersion: c.majorVersion,
@@ -115,6 +156,7 @@ func (c *SyncToolsCommand) Run(ctx *cmd.Context) (resultErr error) {
 		DryRun:       c.dryRun,
 		Stream:       c.stream,
 		Source:       c.source,
+		OnlySigned:   c.onlySigned,
 	}
 
 	if c.localDir != "" {
@@ -148,6 +190,34 @@ func (c *SyncToolsCommand) Run(ctx *cmd.Context) (resultErr error) {
 	return syncTools(sctx)
 }
 
+// uploadTarball uploads the tools tarball given with --tarball
+// to the environment.
+func (c *SyncToolsCommand) uploadTarball(ctx *cmd.Context) error {
+	v, err := toolsTarballVersion(c.tarball)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(c.tarball)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if c.dryRun {
+		fmt.Fprintf(ctx.Stdout, "would upload %s tools from %s\n", v, c.tarball)
+		return nil
+	}
+	api, err := getSyncToolsAPI(c)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	if _, err := api.UploadTools(f, v); err != nil {
+		return err
+	}
+	ctx.Infof("uploaded %s tools", v)
+	return nil
+}
+
 // syncToolsAPIAdapter implements sync.ToolsFinder and
 // sync.ToolsUploader, adapting a syncToolsAPI. This
 // enables the use of sync.SyncTools with the client
@@ -6,6 +6,7 @@ package main
 import (
 	"io"
 	"io/ioutil"
+	"path/filepath"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -93,6 +94,13 @@ var syncToolsCommandTests = []struct {
 			MinorVersion: 2,
 		},
 	},
+	{
+		description: "only use signed metadata",
+		args:        []string{"-e", "test-target", "--require-signed"},
+		sctx: &sync.SyncContext{
+			OnlySigned: true,
+		},
+	},
 }
 
 func (s *syncToolsSuite) TestSyncToolsCommand(c *gc.C) {
@@ -109,6 +117,7 @@ func (s *syncToolsSuite) TestSyncToolsCommand(c *gc.C) {
 			c.Assert(sctx.DryRun, gc.Equals, test.sctx.DryRun)
 			c.Assert(sctx.Stream, gc.Equals, test.sctx.Stream)
 			c.Assert(sctx.Source, gc.Equals, test.sctx.Source)
+			c.Assert(sctx.OnlySigned, gc.Equals, test.sctx.OnlySigned)
 
 			c.Assert(sctx.TargetToolsFinder, gc.FitsTypeOf, syncToolsAPIAdapter{})
 			finder := sctx.TargetToolsFinder.(syncToolsAPIAdapter)
@@ -279,3 +288,60 @@ func (f *fakeSyncToolsAPI) UploadTools(r io.Reader, v version.Binary, additional
 func (f *fakeSyncToolsAPI) Close() error {
 	return nil
 }
+
+func (s *syncToolsSuite) TestSyncToolsCommandTarball(c *gc.C) {
+	tarball := filepath.Join(c.MkDir(), "juju-1.21.0-trusty-amd64.tgz")
+	err := ioutil.WriteFile(tarball, []byte("tools"), 0644)
+	c.Assert(err, gc.IsNil)
+	called := false
+	s.fakeSyncToolsAPI.uploadTools = func(r io.Reader, v version.Binary, additionalSeries ...string) (*coretools.Tools, error) {
+		data, err := ioutil.ReadAll(r)
+		c.Assert(err, gc.IsNil)
+		c.Assert(string(data), gc.Equals, "tools")
+		c.Assert(v, gc.Equals, version.MustParseBinary("1.21.0-trusty-amd64"))
+		c.Assert(additionalSeries, gc.HasLen, 0)
+		called = true
+		return &coretools.Tools{Version: v}, nil
+	}
+	s.PatchValue(&syncTools, func(*sync.SyncContext) error {
+		c.Fatalf("unexpected sync")
+		return nil
+	})
+	ctx, err := runSyncToolsCommand(c, "-e", "test-target", "--tarball", tarball)
+	c.Assert(err, gc.IsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "uploaded 1.21.0-trusty-amd64 tools\n")
+}
+
+func (s *syncToolsSuite) TestSyncToolsCommandTarballDryRun(c *gc.C) {
+	tarball := filepath.Join(c.MkDir(), "juju-1.21.0-trusty-amd64.tgz")
+	err := ioutil.WriteFile(tarball, []byte("tools"), 0644)
+	c.Assert(err, gc.IsNil)
+	s.fakeSyncToolsAPI.uploadTools = func(r io.Reader, v version.Binary, additionalSeries ...string) (*coretools.Tools, error) {
+		c.Fatalf("unexpected upload")
+		return nil, nil
+	}
+	ctx, err := runSyncToolsCommand(c, "-e", "test-target", "--tarball", tarball, "--dry-run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "would upload 1.21.0-trusty-amd64 tools from "+tarball+"\n")
+}
+
+func (s *syncToolsSuite) TestSyncToolsCommandTarballErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--tarball", "tools.tgz"},
+		err:  `invalid tools tarball name "tools.tgz": expected juju-<version>-<series>-<arch>.tgz`,
+	}, {
+		args: []string{"--tarball", "juju-1.21.0.tgz"},
+		err:  `invalid tools tarball name "juju-1.21.0.tgz": .*`,
+	}, {
+		args: []string{"--tarball", "juju-1.21.0-trusty-amd64.tgz", "--all"},
+		err:  "--tarball cannot be used with --local-dir, --source, --all or --version",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := runSyncToolsCommand(c, append([]string{"-e", "test-target"}, test.args...)...)
+		c.Assert(err, gc.ErrorMatches, test.err)
+	}
+}
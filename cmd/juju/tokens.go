@@ -0,0 +1,198 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/usermanager"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const createTokenDoc = `
+Make a long-lived API token for the current user, so that automation
+such as a CI system can use the environment without the user's
+password. The token is printed once and cannot be shown again.
+
+To log in with the token, use it in place of the user's password, for
+example as the password in the environment's .jenv file. A token with
+--scope read may only view the environment, whatever the user's own
+access; a token with --scope write has the user's access. Neither may
+be used to change the user's password or to manage tokens.
+
+A token may only be used with the environment it was made for, and
+stops working once the duration given by --valid-for has passed.
+
+Examples:
+
+    juju create-token --scope read --description "CI status checks"
+    juju create-token --scope write --valid-for 24h
+`
+
+// defaultTokenValidity is how long API tokens are valid for unless
+// create-token is told otherwise.
+const defaultTokenValidity = 90 * 24 * time.Hour
+
+// CreateTokenCommand makes an API token for the current user.
+type CreateTokenCommand struct {
+	envcmd.EnvCommandBase
+	Scope       string
+	Description string
+	ValidFor    time.Duration
+}
+
+func (c *CreateTokenCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create-token",
+		Purpose: "make an API token for automation",
+		Doc:     createTokenDoc,
+	}
+}
+
+func (c *CreateTokenCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Scope, "scope", "read", `access granted by the token: "read" or "write"`)
+	f.StringVar(&c.Description, "description", "", "what the token is for")
+	f.DurationVar(&c.ValidFor, "valid-for", defaultTokenValidity, "how long the token may be used for")
+}
+
+func (c *CreateTokenCommand) Init(args []string) error {
+	if c.Scope != "read" && c.Scope != "write" {
+		return fmt.Errorf(`invalid scope %q: expected "read" or "write"`, c.Scope)
+	}
+	if c.ValidFor <= 0 {
+		return fmt.Errorf("invalid validity %v: must be positive", c.ValidFor)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *CreateTokenCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	client := usermanager.NewClient(root)
+	defer client.Close()
+
+	_, token, err := client.CreateAPIToken(c.Scope, c.Description, c.ValidFor)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.Stdout, token)
+	return nil
+}
+
+const listTokensDoc = `
+List the API tokens of the current user, as made by "juju create-token".
+The tokens themselves are not shown.
+`
+
+// ListTokensCommand lists the API tokens of the current user.
+type ListTokensCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+func (c *ListTokensCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list-tokens",
+		Purpose: "list API tokens",
+		Doc:     listTokensDoc,
+	}
+}
+
+func (c *ListTokensCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *ListTokensCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// tokenInfo holds the formatted details of an API token.
+type tokenInfo struct {
+	Id          string `yaml:"id" json:"id"`
+	Scope       string `yaml:"scope" json:"scope"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Created     string `yaml:"created" json:"created"`
+	Expires     string `yaml:"expires" json:"expires"`
+}
+
+func (c *ListTokensCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	client := usermanager.NewClient(root)
+	defer client.Close()
+
+	tokens, err := client.APITokens()
+	if err != nil {
+		return err
+	}
+	result := make([]tokenInfo, len(tokens))
+	for i, token := range tokens {
+		result[i] = tokenInfo{
+			Id:          token.Id,
+			Scope:       token.Scope,
+			Description: token.Description,
+			Created:     token.DateCreated.Format("2006-01-02 15:04:05"),
+			Expires:     token.Expires.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+const revokeTokenDoc = `
+Revoke API tokens of the current user, given their ids as listed by
+"juju list-tokens". The token can no longer be used to log in, and
+connections already made with it can make no further calls.
+`
+
+// RevokeTokenCommand revokes API tokens of the current user.
+type RevokeTokenCommand struct {
+	envcmd.EnvCommandBase
+	Ids []string
+}
+
+func (c *RevokeTokenCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke-token",
+		Args:    "<token id> [...]",
+		Purpose: "revoke API tokens",
+		Doc:     revokeTokenDoc,
+	}
+}
+
+func (c *RevokeTokenCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no token id specified")
+	}
+	c.Ids = args
+	return nil
+}
+
+func (c *RevokeTokenCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	client := usermanager.NewClient(root)
+	defer client.Close()
+
+	var failed bool
+	for _, id := range c.Ids {
+		if err := client.RemoveAPIToken(id); err != nil {
+			fmt.Fprintf(ctx.Stderr, "cannot revoke token %q: %v\n", id, err)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type tokensSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&tokensSuite{})
+
+func (s *tokensSuite) TestCreateTokenInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&CreateTokenCommand{}), []string{"--scope", "admin"})
+	c.Assert(err, gc.ErrorMatches, `invalid scope "admin": expected "read" or "write"`)
+	err = testing.InitCommand(envcmd.Wrap(&CreateTokenCommand{}), []string{"--valid-for", "0"})
+	c.Assert(err, gc.ErrorMatches, `invalid validity 0s: must be positive`)
+	err = testing.InitCommand(envcmd.Wrap(&CreateTokenCommand{}), []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *tokensSuite) TestCreateListRevoke(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&CreateTokenCommand{}), "--description", "ci")
+	c.Assert(err, gc.IsNil)
+	token := strings.TrimSpace(testing.Stdout(context))
+	apiToken, err := s.State.AuthenticateAPIToken(token)
+	c.Assert(err, gc.IsNil)
+	c.Assert(apiToken.Scope(), gc.Equals, state.EnvironmentReadAccess)
+	c.Assert(apiToken.Description(), gc.Equals, "ci")
+	c.Assert(apiToken.Expires().Sub(apiToken.DateCreated()), gc.Equals, 90*24*time.Hour)
+
+	context, err = testing.RunCommand(c, envcmd.Wrap(&ListTokensCommand{}))
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Matches, ""+
+		"- id: "+apiToken.Id()+"\n"+
+		"  scope: read\n"+
+		"  description: ci\n"+
+		"  created: .*\n"+
+		"  expires: .*\n")
+
+	_, err = testing.RunCommand(c, envcmd.Wrap(&RevokeTokenCommand{}), apiToken.Id())
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AuthenticateAPIToken(token)
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *tokensSuite) TestRevokeUnknownToken(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&RevokeTokenCommand{}), "0123456789abcdef")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(context), gc.Equals, `cannot revoke token "0123456789abcdef": API token "0123456789abcdef" not found`+"\n")
+}
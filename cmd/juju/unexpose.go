@@ -7,14 +7,32 @@ import (
 	"errors"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/network"
 )
 
+const unexposeDoc = `
+Unexposing a service closes all the ports its units have opened to the
+outside world. With --ports, only the given ports of an exposed service
+are closed and its other ports remain exposed; the ports are given as a
+comma-separated list of <port>[-<port>]/<protocol> ranges. Exposing the
+service again opens all of its ports.
+
+Examples:
+
+    juju unexpose nginx
+    juju unexpose nginx --ports 8080/tcp
+    juju unexpose nginx --ports 8000-8080/tcp,53/udp
+`
+
 // UnexposeCommand is responsible exposing services.
 type UnexposeCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	Ports       []network.PortRange
+	portArgs    []string
 }
 
 func (c *UnexposeCommand) Info() *cmd.Info {
@@ -22,14 +40,26 @@ func (c *UnexposeCommand) Info() *cmd.Info {
 		Name:    "unexpose",
 		Args:    "<service>",
 		Purpose: "unexpose a service",
+		Doc:     unexposeDoc,
 	}
 }
 
+func (c *UnexposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewStringsValue(nil, &c.portArgs), "ports", "only unexpose these port ranges")
+}
+
 func (c *UnexposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no service name specified")
 	}
 	c.ServiceName = args[0]
+	for _, arg := range c.portArgs {
+		portRange, err := network.ParsePortRange(arg)
+		if err != nil {
+			return err
+		}
+		c.Ports = append(c.Ports, portRange)
+	}
 	return cmd.CheckEmpty(args[1:])
 }
 
@@ -41,5 +71,8 @@ func (c *UnexposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
+	if len(c.Ports) > 0 {
+		return client.ServiceUnexposePorts(c.ServiceName, c.Ports)
+	}
 	return client.ServiceUnexpose(c.ServiceName)
 }
@@ -4,12 +4,14 @@
 package main
 
 import (
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v4"
 	charmtesting "gopkg.in/juju/charm.v4/testing"
 
 	"github.com/juju/juju/cmd/envcmd"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/testing"
 )
 
@@ -49,3 +51,26 @@ func (s *UnexposeSuite) TestUnexpose(c *gc.C) {
 	err = runUnexpose(c, "nonexistent-service")
 	c.Assert(err, gc.ErrorMatches, `service "nonexistent-service" not found`)
 }
+
+func (s *UnexposeSuite) TestUnexposePorts(c *gc.C) {
+	charmtesting.Charms.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "some-service-name")
+	c.Assert(err, gc.IsNil)
+
+	err = runUnexpose(c, "some-service-name", "--ports", "8080/tcp")
+	c.Assert(err, gc.ErrorMatches, `cannot unexpose ports of service "some-service-name": service is not exposed`)
+
+	err = runExpose(c, "some-service-name")
+	c.Assert(err, gc.IsNil)
+	err = runUnexpose(c, "some-service-name", "--ports", "8000-8080/tcp,53/udp")
+	c.Assert(err, gc.IsNil)
+	s.assertExposed(c, "some-service-name", true)
+	svc, err := s.State.Service("some-service-name")
+	c.Assert(err, gc.IsNil)
+	c.Assert(svc.UnexposedPorts(), jc.DeepEquals, []network.PortRange{
+		{8000, 8080, "tcp"}, {53, 53, "udp"},
+	})
+
+	err = runUnexpose(c, "some-service-name", "--ports", "http/tcp")
+	c.Assert(err, gc.ErrorMatches, `invalid port range "http/tcp"`)
+}
@@ -6,12 +6,14 @@ package main
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v4"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
 )
@@ -53,6 +55,9 @@ participating in.
 - All config settings shared by the old and new charms must
 have the same types.
 
+Any settings explicitly set on the service that the new charm does not
+declare are reported, and will be discarded by the switch.
+
 The new charm may add new relations and configuration settings.
 
 --switch and --revision are mutually exclusive. To specify a given revision
@@ -62,6 +67,10 @@ would specify revision number 5 of the wordpress charm.
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
+
+A service held at its charm with "juju pin-charm", or any service while the
+environment's charm-revision-policy is "locked", is only upgraded when --force
+is given.
 `
 
 func (c *UpgradeCharmCommand) Info() *cmd.Info {
@@ -74,7 +83,7 @@ func (c *UpgradeCharmCommand) Info() *cmd.Info {
 }
 
 func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
-	f.BoolVar(&c.Force, "force", false, "upgrade all units immediately, even if in error state")
+	f.BoolVar(&c.Force, "force", false, "upgrade all units immediately, even if in error state or pinned")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
@@ -101,10 +110,11 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 // Run connects to the specified environment and starts the charm
 // upgrade process.
 func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
-	client, err := c.NewAPIClient()
+	root, err := c.NewAPIRoot()
 	if err != nil {
 		return err
 	}
+	client := root.Client()
 	defer client.Close()
 	oldURL, err := client.ServiceGetCharmURL(c.ServiceName)
 	if err != nil {
@@ -122,7 +132,7 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 
 	var newURL *charm.URL
 	if c.SwitchURL != "" {
-		newURL, err = resolveCharmURL(c.SwitchURL, client, conf)
+		newURL, err = resolveCharmURL(c.SwitchURL, root, conf)
 		if err != nil {
 			return err
 		}
@@ -142,11 +152,10 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 	explicitRevision := true
 	if newURL.Revision == -1 {
 		explicitRevision = false
-		latest, err := charm.Latest(repo, newURL)
+		newURL, err = latestCharmURL(root, repo, newURL)
 		if err != nil {
 			return err
 		}
-		newURL = newURL.WithRevision(latest)
 	}
 	if *newURL == *oldURL {
 		if explicitRevision {
@@ -163,6 +172,39 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	if c.SwitchURL != "" {
+		if err := reportDiscardedSettings(ctx, client, c.ServiceName, addedURL); err != nil {
+			return err
+		}
+	}
 
 	return client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force)
 }
+
+// reportDiscardedSettings reports the settings explicitly set on the
+// service that are not declared by the charm it is being switched to,
+// and so will not be carried over.
+func reportDiscardedSettings(ctx *cmd.Context, client *api.Client, serviceName string, newURL *charm.URL) error {
+	current, err := client.ServiceGet(serviceName)
+	if err != nil {
+		return err
+	}
+	info, err := client.CharmInfo(newURL.String())
+	if err != nil {
+		return err
+	}
+	var discarded []string
+	for name, value := range current.Config {
+		if attrs, ok := value.(map[string]interface{}); ok && attrs["default"] == true {
+			continue
+		}
+		if _, ok := info.Config.Options[name]; !ok {
+			discarded = append(discarded, name)
+		}
+	}
+	sort.Strings(discarded)
+	for _, name := range discarded {
+		ctx.Infof("setting %q is not declared by charm %q and will be discarded", name, newURL)
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"gopkg.in/juju/charm.v4"
 	charmtesting "gopkg.in/juju/charm.v4/testing"
 
+	apiservercharms "github.com/juju/juju/apiserver/charms"
 	"github.com/juju/juju/cmd/envcmd"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
@@ -27,9 +28,11 @@ func (s *UpgradeCharmErrorsSuite) SetUpTest(c *gc.C) {
 	s.RepoSuite.SetUpTest(c)
 	mockstore := charmtesting.NewMockStore(c, map[string]int{})
 	s.AddCleanup(func(*gc.C) { mockstore.Close() })
-	s.PatchValue(&charm.Store, &charm.CharmStore{
+	store := &charm.CharmStore{
 		BaseURL: mockstore.Address(),
-	})
+	}
+	s.PatchValue(&charm.Store, store)
+	s.PatchValue(&apiservercharms.CharmStore, charm.Repository(store))
 }
 
 var _ = gc.Suite(&UpgradeCharmErrorsSuite{})
@@ -217,3 +220,39 @@ func (s *UpgradeCharmSuccessSuite) TestSwitch(c *gc.C) {
 	c.Assert(curl.String(), gc.Equals, "local:trusty/myriak-42")
 	s.assertLocalRevision(c, 42, myriakPath)
 }
+
+var mydummyMeta = []byte(`
+name: mydummy
+summary: "That's a dummy charm."
+description: "A dummy charm with fewer config options."
+`)
+
+var mydummyConfig = []byte(`
+options:
+  outlook: {description: No default outlook., type: string}
+`)
+
+func (s *UpgradeCharmSuccessSuite) TestSwitchReportsDiscardedSettings(c *gc.C) {
+	charmtesting.Charms.ClonedDirPath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "dummy")
+	c.Assert(err, gc.IsNil)
+	dummy, err := s.State.Service("dummy")
+	c.Assert(err, gc.IsNil)
+	err = dummy.UpdateConfigSettings(charm.Settings{"title": "aaargh", "outlook": "fine"})
+	c.Assert(err, gc.IsNil)
+
+	mydummyPath := charmtesting.Charms.RenamedClonedDirPath(s.SeriesPath, "dummy", "mydummy")
+	err = ioutil.WriteFile(path.Join(mydummyPath, "metadata.yaml"), mydummyMeta, 0644)
+	c.Assert(err, gc.IsNil)
+	err = ioutil.WriteFile(path.Join(mydummyPath, "config.yaml"), mydummyConfig, 0644)
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&UpgradeCharmCommand{}), "dummy", "--switch=local:mydummy")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stderr(ctx), gc.Matches, `(?s).*setting "title" is not declared by charm "local:trusty/mydummy-\d+" and will be discarded\n`)
+	err = dummy.Refresh()
+	c.Assert(err, gc.IsNil)
+	settings, err := dummy.ConfigSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"outlook": "fine"})
+}
@@ -10,16 +10,20 @@ import (
 	"io"
 	"os"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/sync"
+	"github.com/juju/juju/instance"
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 )
@@ -73,7 +77,13 @@ progress. It will also abort if a previous upgrade was partially
 completed - this can happen if one of the state servers in a high
 availability environment failed to upgrade. If a failed upgrade has
 been resolved, the --reset-previous-upgrade flag can be used to reset
-the environment's upgrade tracking state, allowing further upgrades.`
+the environment's upgrade tracking state, allowing further upgrades.
+
+With --dry-run, nothing is changed; instead the chosen version is reported
+along with an upgrade plan listing, for every machine and unit agent, the
+version it would move from and the tools it would move to, or why it
+would be skipped (for example, because no tools exist for its series and
+architecture).`
 
 func (c *UpgradeJujuCommand) Info() *cmd.Info {
 	return &cmd.Info{
@@ -135,6 +145,7 @@ type upgradeJujuAPI interface {
 	UploadTools(r io.Reader, vers version.Binary, additionalSeries ...string) (*coretools.Tools, error)
 	AbortCurrentUpgrade() error
 	SetEnvironAgentVersion(version version.Number) error
+	Status(patterns []string) (*api.Status, error)
 	Close() error
 }
 
@@ -185,6 +196,13 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	ctx.Infof("available tools:\n%s", formatTools(context.tools))
 	ctx.Infof("best version:\n    %s", context.chosen)
 	if c.DryRun {
+		status, err := client.Status(nil)
+		if err != nil {
+			return err
+		}
+		if plan := context.plan(status, c.UploadTools); len(plan) > 0 {
+			ctx.Infof("upgrade plan:\n%s", formatUpgradePlan(plan))
+		}
 		ctx.Infof("upgrade to this version by running\n    juju upgrade-juju --version=\"%s\"\n", context.chosen)
 	} else {
 		if c.ResetPrevious {
@@ -400,6 +418,129 @@ func (context *upgradeContext) validate() (err error) {
 	return nil
 }
 
+// upgradeStep describes what an upgrade would do to a single agent.
+type upgradeStep struct {
+	agent string
+	from  string
+	to    version.Binary
+	skip  string
+}
+
+func formatUpgradePlan(plan []upgradeStep) string {
+	formatted := make([]string, len(plan))
+	for i, step := range plan {
+		if step.skip != "" {
+			formatted[i] = fmt.Sprintf("    %s: skipped, %s", step.agent, step.skip)
+		} else {
+			formatted[i] = fmt.Sprintf("    %s: %s -> %s", step.agent, step.from, step.to)
+		}
+	}
+	return strings.Join(formatted, "\n")
+}
+
+// plan returns the upgrade steps for every machine and unit agent in
+// the supplied status, assuming the chosen version has been validated.
+// If uploading is true, tools are expected to be uploaded for the
+// client's operating system and architecture.
+func (context *upgradeContext) plan(status *api.Status, uploading bool) []upgradeStep {
+	var steps []upgradeStep
+	type machineInfo struct {
+		series string
+		arch   string
+	}
+	machines := make(map[string]machineInfo)
+	var addMachines func(map[string]api.MachineStatus)
+	addMachines = func(all map[string]api.MachineStatus) {
+		ids := make([]string, 0, len(all))
+		for id := range all {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			m := all[id]
+			info := machineInfo{series: m.Series}
+			if hc, err := instance.ParseHardware(m.Hardware); err == nil && hc.Arch != nil {
+				info.arch = *hc.Arch
+			}
+			machines[id] = info
+			steps = append(steps, context.planAgent(
+				names.NewMachineTag(id).String(), m.AgentVersion, info.series, info.arch, uploading,
+			))
+			addMachines(m.Containers)
+		}
+	}
+	addMachines(status.Machines)
+
+	var addUnits func(map[string]api.UnitStatus, string)
+	addUnits = func(all map[string]api.UnitStatus, machineId string) {
+		unitNames := make([]string, 0, len(all))
+		for name := range all {
+			unitNames = append(unitNames, name)
+		}
+		sort.Strings(unitNames)
+		for _, name := range unitNames {
+			u := all[name]
+			if u.Machine != "" {
+				machineId = u.Machine
+			}
+			info := machines[machineId]
+			steps = append(steps, context.planAgent(
+				names.NewUnitTag(name).String(), u.AgentVersion, info.series, info.arch, uploading,
+			))
+			addUnits(u.Subordinates, machineId)
+		}
+	}
+	serviceNames := make([]string, 0, len(status.Services))
+	for name := range status.Services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+	for _, name := range serviceNames {
+		addUnits(status.Services[name].Units, "")
+	}
+	return steps
+}
+
+// planAgent returns the upgrade step for a single agent running on a
+// machine with the given series and architecture; arch may be empty if
+// the machine's hardware is not known.
+func (context *upgradeContext) planAgent(agent, current, series, arch string, uploading bool) upgradeStep {
+	step := upgradeStep{agent: agent, from: current}
+	if current == "" {
+		step.skip = "agent has not reported its version"
+		return step
+	}
+	if current == context.chosen.String() {
+		step.skip = fmt.Sprintf("already running %s", current)
+		return step
+	}
+	if uploading {
+		supported := false
+		for _, s := range version.OSSupportedSeries(version.Current.OS) {
+			if s == series {
+				supported = true
+			}
+		}
+		if supported && (arch == "" || arch == version.Current.Arch) {
+			step.to = version.Binary{Number: context.chosen, Series: series, Arch: version.Current.Arch}
+			return step
+		}
+	} else {
+		for _, t := range context.tools {
+			if t.Version.Number == context.chosen && t.Version.Series == series && (arch == "" || t.Version.Arch == arch) {
+				step.to = t.Version
+				return step
+			}
+		}
+	}
+	wanted := context.chosen.String() + "-" + series
+	if arch != "" {
+		wanted += "-" + arch
+	}
+	step.skip = fmt.Sprintf("no tools for %s", wanted)
+	return step
+}
+
 // uploadVersion returns a copy of the supplied version with a build number
 // higher than any of the supplied tools that share its major, minor and patch.
 func uploadVersion(vers version.Number, existing coretools.List) version.Number {
@@ -14,6 +14,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
@@ -22,6 +23,7 @@ import (
 	envtesting "github.com/juju/juju/environs/testing"
 	"github.com/juju/juju/environs/tools"
 	toolstesting "github.com/juju/juju/environs/tools/testing"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -513,6 +515,75 @@ upgrade to this version by running
 	}
 }
 
+func (s *UpgradeJujuSuite) TestUpgradeDryRunPlan(c *gc.C) {
+	s.Reset(c)
+	tools.DefaultBaseURL = ""
+	s.PatchValue(&version.Current, version.MustParseBinary("2.0.0-quantal-amd64"))
+	toolsDir := c.MkDir()
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"agent-version":      "2.0.0",
+		"agent-metadata-url": "file://" + toolsDir + "/tools",
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	stor, err := filestorage.NewFileStorageWriter(toolsDir)
+	c.Assert(err, gc.IsNil)
+	envtesting.MustUploadFakeToolsVersions(stor,
+		version.MustParseBinary("2.1.3-quantal-amd64"),
+		version.MustParseBinary("2.1.3-quantal-i386"),
+	)
+
+	// machine-0 can be upgraded; machine-1 runs a series with no
+	// tools; machine-2 has not started its agent yet.
+	addMachine := func(series, arch, agentVersion string) *state.Machine {
+		m, err := s.State.AddMachine(series, state.JobHostUnits)
+		c.Assert(err, gc.IsNil)
+		hc := instance.MustParseHardware("arch=" + arch)
+		err = m.SetProvisioned(instance.Id("i-"+m.Id()), "fake_nonce", &hc)
+		c.Assert(err, gc.IsNil)
+		if agentVersion != "" {
+			err = m.SetAgentVersion(version.MustParseBinary(agentVersion))
+			c.Assert(err, gc.IsNil)
+		}
+		return m
+	}
+	m0 := addMachine("quantal", "i386", "2.0.0-quantal-i386")
+	addMachine("precise", "amd64", "2.0.0-precise-amd64")
+	addMachine("quantal", "amd64", "")
+	svc := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	u, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = u.AssignToMachine(m0)
+	c.Assert(err, gc.IsNil)
+	err = u.SetAgentVersion(version.MustParseBinary("2.0.0-quantal-i386"))
+	c.Assert(err, gc.IsNil)
+
+	com := &UpgradeJujuCommand{}
+	err = coretesting.InitCommand(envcmd.Wrap(com), []string{"--dry-run"})
+	c.Assert(err, gc.IsNil)
+	ctx := coretesting.Context(c)
+	err = com.Run(ctx)
+	c.Assert(err, gc.IsNil)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, `available tools:
+    2.1.3-quantal-amd64
+    2.1.3-quantal-i386
+best version:
+    2.1.3
+upgrade plan:
+    machine-0: 2.0.0 -> 2.1.3-quantal-i386
+    machine-1: skipped, no tools for 2.1.3-precise-amd64
+    machine-2: skipped, agent has not reported its version
+    unit-dummy-0: 2.0.0 -> 2.1.3-quantal-i386
+upgrade to this version by running
+    juju upgrade-juju --version="2.1.3"
+`)
+
+	// Nothing was changed.
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, gc.IsNil)
+	agentVersion, _ := cfg.AgentVersion()
+	c.Assert(agentVersion, gc.Equals, version.MustParse("2.0.0"))
+}
+
 func (s *UpgradeJujuSuite) TestUpgradeInProgress(c *gc.C) {
 	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
 	fakeAPI.setVersionErr = &params.Error{
@@ -656,6 +727,10 @@ func (a *fakeUpgradeJujuAPI) SetEnvironAgentVersion(v version.Number) error {
 	return a.setVersionErr
 }
 
+func (a *fakeUpgradeJujuAPI) Status(patterns []string) (*api.Status, error) {
+	return &api.Status{}, nil
+}
+
 func (a *fakeUpgradeJujuAPI) Close() error {
 	return nil
 }
@@ -18,6 +18,8 @@ var (
 	GetConnectionCredentials = &getConnectionCredentials
 	// disable and enable
 	GetDisableUserAPI = &getDisableUserAPI
+	// remove
+	GetRemoveUserAPI = &getRemoveUserAPI
 
 	UserFriendlyDuration = userFriendlyDuration
 )
@@ -36,6 +38,10 @@ func (c *EnableCommand) Username() string {
 	return c.user
 }
 
+func (c *RemoveCommand) Username() string {
+	return c.user
+}
+
 var (
 	_ DisenableCommand = (*DisableCommand)(nil)
 	_ DisenableCommand = (*EnableCommand)(nil)
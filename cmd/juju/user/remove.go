@@ -0,0 +1,75 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+const removeUserDoc = `
+Removing a user deletes the user's credentials and their access to all
+environments, so the user can no longer log in. Unlike disabling a user,
+this cannot be undone; the user must be added again with "juju user add".
+The owner of the state server environment cannot be removed.
+
+Examples:
+  juju user remove foobar
+
+See Also:
+  juju user add
+  juju user disable
+`
+
+// RemoveCommand removes users.
+type RemoveCommand struct {
+	UserCommandBase
+	user string
+}
+
+// Info implements Command.Info.
+func (c *RemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<username>",
+		Purpose: "remove a user and their access to the environment",
+		Doc:     removeUserDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *RemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no username supplied")
+	}
+	c.user = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// RemoveUserAPI defines the API methods that the remove command uses.
+type RemoveUserAPI interface {
+	RemoveUser(username string) error
+	Close() error
+}
+
+func (c *RemoveCommand) getRemoveUserAPI() (RemoveUserAPI, error) {
+	return c.NewUserManagerClient()
+}
+
+var getRemoveUserAPI = (*RemoveCommand).getRemoveUserAPI
+
+// Run implements Command.Run.
+func (c *RemoveCommand) Run(ctx *cmd.Context) error {
+	client, err := getRemoveUserAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.RemoveUser(c.user)
+	if err != nil {
+		return err
+	}
+	ctx.Infof("User %q removed", c.user)
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/testing"
+)
+
+type RemoveUserSuite struct {
+	BaseSuite
+	mock mockRemoveUserAPI
+}
+
+var _ = gc.Suite(&RemoveUserSuite{})
+
+func (s *RemoveUserSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mock = mockRemoveUserAPI{}
+	s.PatchValue(user.GetRemoveUserAPI, func(*user.RemoveCommand) (user.RemoveUserAPI, error) {
+		return &s.mock, nil
+	})
+}
+
+func (s *RemoveUserSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+		user     string
+	}{
+		{
+			errMatch: "no username supplied",
+		}, {
+			args:     []string{"username", "password"},
+			errMatch: `unrecognized args: \["password"\]`,
+		}, {
+			args: []string{"username"},
+			user: "username",
+		},
+	} {
+		c.Logf("test %d, args %v", i, test.args)
+		command := &user.RemoveCommand{}
+		err := testing.InitCommand(command, test.args)
+		if test.errMatch == "" {
+			c.Assert(err, gc.IsNil)
+			c.Assert(command.Username(), gc.Equals, test.user)
+		} else {
+			c.Assert(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *RemoveUserSuite) TestRemove(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&user.RemoveCommand{}), "testing")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mock.removed, gc.Equals, "testing")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "User \"testing\" removed\n")
+}
+
+func (s *RemoveUserSuite) TestRemoveFails(c *gc.C) {
+	s.mock.err = errors.New("cannot remove state server environment owner")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&user.RemoveCommand{}), "admin")
+	c.Assert(err, gc.ErrorMatches, "cannot remove state server environment owner")
+}
+
+type mockRemoveUserAPI struct {
+	removed string
+	err     error
+}
+
+var _ user.RemoveUserAPI = (*mockRemoveUserAPI)(nil)
+
+func (m *mockRemoveUserAPI) Close() error {
+	return nil
+}
+
+func (m *mockRemoveUserAPI) RemoveUser(username string) error {
+	m.removed = username
+	return m.err
+}
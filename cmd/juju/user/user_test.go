@@ -29,6 +29,7 @@ var expectedUserCommmandNames = []string{
 	"help",
 	"info",
 	"list",
+	"remove",
 }
 
 func (s *UserCommandSuite) TestHelp(c *gc.C) {
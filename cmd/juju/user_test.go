@@ -5,6 +5,7 @@ package main
 
 import (
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/juju/testing/factory"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -82,6 +83,14 @@ func (s *UserSuite) TestUserEnable(c *gc.C) {
 	c.Assert(user.IsDisabled(), jc.IsFalse)
 }
 
+func (s *UserSuite) TestUserRemove(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "barbara"})
+	_, err := s.RunUserCommand(c, "remove", "barbara")
+	c.Assert(err, gc.IsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *UserSuite) TestUserList(c *gc.C) {
 	ctx, err := s.RunUserCommand(c, "list")
 	c.Assert(err, gc.IsNil)
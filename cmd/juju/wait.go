@@ -0,0 +1,124 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const waitDoc = `
+Wait until the given machines, services and units have reached their goal
+state: machines provisioned with their agents started, and unit agents
+started with no pending hooks. With no arguments, all machines and units
+in the environment are waited for.
+
+Each change in the status of a waited-for entity is printed as it happens;
+with --format json, every change is printed as a JSON object on its own
+line. The command fails as soon as any of the entities is in an error
+state, or when --timeout expires, so it can be used to script deployments:
+
+    juju deploy mysql
+    juju wait --timeout 30m mysql
+`
+
+// WaitCommand blocks until entities in the environment reach
+// their goal state.
+type WaitCommand struct {
+	envcmd.EnvCommandBase
+	out      cmd.Output
+	Patterns []string
+	Timeout  time.Duration
+}
+
+func (c *WaitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "wait",
+		Args:    "[<machine>|<service>|<unit> ...]",
+		Purpose: "wait for machines and units to reach their goal state",
+		Doc:     waitDoc,
+	}
+}
+
+func (c *WaitCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.DurationVar(&c.Timeout, "timeout", 0, "how long to wait before failing (0 waits forever)")
+	c.out.AddFlags(f, "plain", map[string]cmd.Formatter{
+		"plain": formatWaitEventPlain,
+		"json":  cmd.FormatJson,
+	})
+}
+
+func (c *WaitCommand) Init(args []string) error {
+	if c.Timeout < 0 {
+		return errors.New("--timeout must not be negative")
+	}
+	for _, arg := range args {
+		if !names.IsValidMachine(arg) && !names.IsValidService(arg) && !names.IsValidUnit(arg) {
+			return errors.Errorf("invalid machine, service or unit name %q", arg)
+		}
+	}
+	c.Patterns = args
+	return nil
+}
+
+func (c *WaitCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	var writeErr error
+	err = client.WaitFor(c.Patterns, c.Timeout, func(event api.WaitEvent) {
+		if writeErr == nil {
+			writeErr = c.writeEvent(ctx.Stdout, event)
+		}
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+	if err == api.ErrWaitTimeout {
+		return errors.Errorf("timed out after %v", c.Timeout)
+	}
+	return err
+}
+
+// writeEvent writes the event to w as a single line in the chosen
+// output format. The output is written as events arrive rather than
+// through cmd.Output.Write, so that it can be consumed as a stream.
+func (c *WaitCommand) writeEvent(w io.Writer, event api.WaitEvent) error {
+	var data []byte
+	var err error
+	if c.out.Name() == "json" {
+		data, err = json.Marshal(event)
+	} else {
+		data, err = formatWaitEventPlain(event)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+func formatWaitEventPlain(value interface{}) ([]byte, error) {
+	event, ok := value.(api.WaitEvent)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", event, value)
+	}
+	line := fmt.Sprintf("%s %s: %s", event.Kind, event.Id, event.Status)
+	if event.Info != "" {
+		line += fmt.Sprintf(" (%s)", event.Info)
+	}
+	return []byte(line), nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type WaitSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&WaitSuite{})
+
+func (s *WaitSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *WaitSuite) TestInit(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}), "wordpress/x")
+	c.Assert(err, gc.ErrorMatches, `invalid machine, service or unit name "wordpress/x"`)
+	_, err = testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}), "--timeout", "-1s")
+	c.Assert(err, gc.ErrorMatches, "--timeout must not be negative")
+}
+
+func (s *WaitSuite) TestWait(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}), "wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "unit wordpress/0: started\n")
+}
+
+func (s *WaitSuite) TestWaitJSON(c *gc.C) {
+	context, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}), "--format", "json", "wordpress/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals,
+		`{"kind":"unit","id":"wordpress/0","status":"started","ready":true}`+"\n")
+}
+
+func (s *WaitSuite) TestWaitTimeout(c *gc.C) {
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(state.StatusInstalled, "", nil)
+	c.Assert(err, gc.IsNil)
+	context, err := testing.RunCommand(c, envcmd.Wrap(&WaitCommand{}), "--timeout", "50ms", "wordpress")
+	c.Assert(err, gc.ErrorMatches, "timed out after 50ms")
+	c.Assert(testing.Stdout(context), gc.Equals, "unit wordpress/0: installed\n")
+}
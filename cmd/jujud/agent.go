@@ -14,7 +14,6 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils"
-	"github.com/juju/utils/fslock"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/agent"
@@ -26,6 +25,7 @@ import (
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/hooklock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/deployer"
@@ -115,6 +115,15 @@ func (a *AgentConf) SetAPIHostPorts(servers [][]network.HostPort) error {
 	})
 }
 
+// SetStateServer satisfies worker/upgrader/StateServerSetter.
+func (a *AgentConf) SetStateServer(servers [][]network.HostPort, caCert string) error {
+	return a.ChangeConfig(func(c agent.ConfigSetter) error {
+		c.SetAPIHostPorts(servers)
+		c.SetCACert(caCert)
+		return nil
+	})
+}
+
 func importance(err error) int {
 	switch {
 	case err == nil:
@@ -181,12 +190,18 @@ type pinger interface {
 // connectionIsFatal returns a function suitable for passing
 // as the isFatal argument to worker.NewRunner,
 // that diagnoses an error as fatal if the connection
-// has failed or if the error is otherwise fatal.
+// has failed, if the agent has been told to connect to
+// another state server, or if the error is otherwise fatal.
 func connectionIsFatal(conn pinger) func(err error) bool {
 	return func(err error) bool {
 		if isFatal(err) {
 			return true
 		}
+		if err == upgrader.ErrStateServerMoved {
+			// The agent config now points at another state
+			// server, so the connection must be reopened.
+			return true
+		}
 		return connectionIsDead(conn)
 	}
 }
@@ -351,6 +366,15 @@ func (c *closeWorker) Wait() error {
 	return err
 }
 
+// Report implements worker.Reporter, reporting on the workers run by
+// the wrapped worker when it is itself a worker.Reporter.
+func (c *closeWorker) Report() map[string]worker.WorkerReport {
+	if reporter, ok := c.worker.(worker.Reporter); ok {
+		return reporter.Report()
+	}
+	return nil
+}
+
 // newDeployContext gives the tests the opportunity to create a deployer.Context
 // that can be used for testing so as to avoid (1) deploying units to the system
 // running the tests and (2) get access to the *State used internally, so that
@@ -372,10 +396,10 @@ var newRsyslogConfigWorker = func(st *apirsyslog.State, agentConfig agent.Config
 	return rsyslog.NewRsyslogConfigWorker(st, mode, tag, namespace, addrs)
 }
 
-// hookExecutionLock returns an *fslock.Lock suitable for use as a unit
+// hookExecutionLock returns a *hooklock.Lock suitable for use as a unit
 // hook execution lock. Other workers may also use this lock if they
 // require isolation from hook execution.
-func hookExecutionLock(dataDir string) (*fslock.Lock, error) {
+func hookExecutionLock(dataDir string) (*hooklock.Lock, error) {
 	lockDir := filepath.Join(dataDir, "locks")
-	return fslock.NewLock(lockDir, "uniter-hook-execution")
+	return hooklock.New(lockDir)
 }
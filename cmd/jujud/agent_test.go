@@ -206,6 +206,15 @@ func (s *toolSuite) TestConnectionIsFatal(c *gc.C) {
 	}
 }
 
+func (s *toolSuite) TestConnectionIsFatalStateServerMoved(c *gc.C) {
+	var okPinger testPinger = func() error {
+		return nil
+	}
+	c.Assert(connectionIsFatal(okPinger)(upgrader.ErrStateServerMoved), jc.IsTrue)
+	// The agent reconnects rather than exiting.
+	c.Assert(isFatal(upgrader.ErrStateServerMoved), jc.IsFalse)
+}
+
 func mkTools(s string) *coretools.Tools {
 	return &coretools.Tools{
 		Version: version.MustParseBinary(s + "-foo-bar"),
@@ -265,7 +274,8 @@ func runWithTimeout(r runner) error {
 
 // agentSuite is a fixture to be used by agent test suites.
 type agentSuite struct {
-	oldRestartDelay time.Duration
+	oldRestartDelay    time.Duration
+	oldMaxRestartDelay time.Duration
 	testing.JujuConnSuite
 }
 
@@ -277,6 +287,9 @@ func (s *agentSuite) SetUpSuite(c *gc.C) {
 	// a bit when some tests are restarting every 50ms for 10 seconds,
 	// so use a slightly more friendly delay.
 	worker.RestartDelay = 250 * time.Millisecond
+	// Nor let the delay grow while workers keep failing.
+	s.oldMaxRestartDelay = worker.MaxRestartDelay
+	worker.MaxRestartDelay = worker.RestartDelay
 	s.PatchValue(&ensureMongoServer, func(mongo.EnsureServerParams) error {
 		return nil
 	})
@@ -285,6 +298,7 @@ func (s *agentSuite) SetUpSuite(c *gc.C) {
 func (s *agentSuite) TearDownSuite(c *gc.C) {
 	s.JujuConnSuite.TearDownSuite(c)
 	worker.RestartDelay = s.oldRestartDelay
+	worker.MaxRestartDelay = s.oldMaxRestartDelay
 }
 
 func (s *agentSuite) SetUpTest(c *gc.C) {
@@ -243,6 +243,22 @@ func newEnsureServerParams(agentConfig agent.Config) (mongo.EnsureServerParams,
 		}
 	}
 
+	var cacheSize int
+	if cacheSizeString := agentConfig.Value(agent.MongoCacheSize); cacheSizeString != "" {
+		var err error
+		if cacheSize, err = strconv.Atoi(cacheSizeString); err != nil {
+			return mongo.EnsureServerParams{}, fmt.Errorf("invalid cache size: %q", cacheSizeString)
+		}
+	}
+	journal, err := agentConfigBool(agentConfig, agent.MongoJournal, true)
+	if err != nil {
+		return mongo.EnsureServerParams{}, err
+	}
+	prealloc, err := agentConfigBool(agentConfig, agent.MongoPrealloc, false)
+	if err != nil {
+		return mongo.EnsureServerParams{}, err
+	}
+
 	si, ok := agentConfig.StateServingInfo()
 	if !ok {
 		return mongo.EnsureServerParams{}, fmt.Errorf("agent config has no state serving info")
@@ -259,10 +275,28 @@ func newEnsureServerParams(agentConfig agent.Config) (mongo.EnsureServerParams,
 		DataDir:   agentConfig.DataDir(),
 		Namespace: agentConfig.Value(agent.Namespace),
 		OplogSize: oplogSize,
+
+		NoJournal:   !journal,
+		Prealloc:    prealloc,
+		CacheSizeGB: cacheSize,
 	}
 	return params, nil
 }
 
+// agentConfigBool returns the boolean value stored under the given key
+// in the agent configuration, or defaultValue if there is none.
+func agentConfigBool(agentConfig agent.Config, key string, defaultValue bool) (bool, error) {
+	s := agentConfig.Value(key)
+	if s == "" {
+		return defaultValue, nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s value: %q", key, s)
+	}
+	return v, nil
+}
+
 func (c *BootstrapCommand) startMongo(addrs []network.Address, agentConfig agent.Config) error {
 	logger.Debugf("starting mongo")
 
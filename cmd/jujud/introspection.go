@@ -0,0 +1,409 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/juju/sockets"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+)
+
+// initAPITracing enables the tracing of API calls when asked for by
+// the API_TRACE agent configuration value or the JUJU_API_TRACE
+// environment variable.
+func initAPITracing(conf agent.Config) {
+	enabled := false
+	for _, value := range []string{conf.Value(agent.APITrace), os.Getenv(osenv.JujuAPITraceEnvKey)} {
+		if value == "" {
+			continue
+		}
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			logger.Warningf("ignoring invalid API trace flag %q", value)
+			continue
+		}
+		enabled = enabled || on
+	}
+	api.SetTracing(enabled)
+	if enabled {
+		logger.Infof("tracing API calls")
+	}
+}
+
+// introspectionSocketPath returns the path of the socket on which the
+// agent with the given tag answers introspection requests.
+func introspectionSocketPath(dataDir string, tag names.Tag) string {
+	if version.Current.OS == version.Windows {
+		return fmt.Sprintf(`\\.\pipe\%s-introspection`, tag)
+	}
+	return filepath.Join(agent.Dir(dataDir, tag), "introspection.socket")
+}
+
+// introspectionConfigValues holds the keys of the agent configuration
+// values reported by the introspection server. None of them holds
+// secrets.
+var introspectionConfigValues = []string{
+	agent.LxcBridge,
+	agent.ProviderType,
+	agent.ContainerType,
+	agent.Namespace,
+	agent.StorageDir,
+	agent.StorageAddr,
+	agent.AgentServiceName,
+	agent.MongoOplogSize,
+	agent.MongoJournal,
+	agent.MongoPrealloc,
+	agent.MongoCacheSize,
+	agent.APITrace,
+}
+
+// introspectionConfig holds the parts of an agent's configuration
+// reported by the introspection server; credentials and certificates
+// are left out.
+type introspectionConfig struct {
+	Tag               string              `json:"tag"`
+	DataDir           string              `json:"data-dir"`
+	LogDir            string              `json:"log-dir"`
+	Jobs              []params.MachineJob `json:"jobs,omitempty"`
+	Nonce             string              `json:"nonce,omitempty"`
+	APIAddresses      []string            `json:"api-addresses"`
+	UpgradedToVersion string              `json:"upgraded-to-version"`
+	PreferIPv6        bool                `json:"prefer-ipv6"`
+	Values            map[string]string   `json:"values,omitempty"`
+}
+
+func newIntrospectionConfig(conf agent.Config) introspectionConfig {
+	result := introspectionConfig{
+		Tag:               conf.Tag().String(),
+		DataDir:           conf.DataDir(),
+		LogDir:            conf.LogDir(),
+		Jobs:              conf.Jobs(),
+		Nonce:             conf.Nonce(),
+		UpgradedToVersion: conf.UpgradedToVersion().String(),
+		PreferIPv6:        conf.PreferIPv6(),
+	}
+	if addrs, err := conf.APIAddresses(); err == nil {
+		result.APIAddresses = addrs
+	}
+	for _, key := range introspectionConfigValues {
+		if value := conf.Value(key); value != "" {
+			if result.Values == nil {
+				result.Values = make(map[string]string)
+			}
+			result.Values[key] = value
+		}
+	}
+	return result
+}
+
+// introspectionHandler serves an agent's introspection requests:
+//
+//	GET  /debug/goroutines  the stacks of all goroutines
+//	GET  /workers           the state of the agent's workers
+//	GET  /config            the agent's configuration
+//	GET  /api-trace         whether API calls are traced
+//	POST /api-trace         turn API call tracing on or off
+type introspectionHandler struct {
+	config func() agent.Config
+	runner worker.Runner
+}
+
+func (h *introspectionHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case "/debug/goroutines":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		pprof.Lookup("goroutine").WriteTo(w, 2)
+	case "/workers":
+		var reports map[string]worker.WorkerReport
+		if reporter, ok := h.runner.(worker.Reporter); ok {
+			reports = reporter.Report()
+		}
+		sendJSON(w, reports)
+	case "/config":
+		sendJSON(w, newIntrospectionConfig(h.config()))
+	case "/api-trace":
+		if req.Method == "POST" {
+			enabled, err := strconv.ParseBool(req.FormValue("enabled"))
+			if err != nil {
+				http.Error(w, `expected "enabled" to be true or false`, http.StatusBadRequest)
+				return
+			}
+			api.SetTracing(enabled)
+			logger.Infof("API call tracing set to %v", enabled)
+		}
+		sendJSON(w, api.Tracing())
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// sendJSON writes the given value as indented JSON.
+func sendJSON(w http.ResponseWriter, value interface{}) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+// introspectionWorker serves introspection requests on a socket.
+type introspectionWorker struct {
+	tomb     tomb.Tomb
+	listener net.Listener
+}
+
+// newIntrospectionWorker returns a worker that serves introspection
+// requests over HTTP on the given socket until it is killed. The
+// workers reported on are those started by the given runner.
+func newIntrospectionWorker(socketPath string, config func() agent.Config, runner worker.Runner) (worker.Worker, error) {
+	listener, err := sockets.Listen(socketPath)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot listen for introspection requests")
+	}
+	w := &introspectionWorker{listener: listener}
+	server := &http.Server{
+		Handler: &introspectionHandler{config: config, runner: runner},
+	}
+	go func() {
+		defer w.tomb.Done()
+		err := server.Serve(listener)
+		select {
+		case <-w.tomb.Dying():
+		default:
+			w.tomb.Kill(err)
+		}
+	}()
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *introspectionWorker) Kill() {
+	w.tomb.Kill(nil)
+	w.listener.Close()
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *introspectionWorker) Wait() error {
+	return w.tomb.Wait()
+}
+
+// introspectionRequest sends an introspection request to the agent
+// with the given tag and returns the body of the response.
+func introspectionRequest(dataDir string, tag names.Tag, method, path string, form url.Values) ([]byte, error) {
+	socketPath := introspectionSocketPath(dataDir, tag)
+	client := &http.Client{
+		Transport: &http.Transport{
+			Dial: func(_, _ string) (net.Conn, error) {
+				return sockets.Connect(socketPath)
+			},
+		},
+	}
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, "http://localhost"+path, body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot connect to agent %q", tag)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("agent %q: %s", tag, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// parseAgentTag parses the tag of a machine or unit agent.
+func parseAgentTag(arg string) (names.Tag, error) {
+	tag, err := names.ParseTag(arg)
+	if err != nil {
+		return nil, err
+	}
+	switch tag.(type) {
+	case names.MachineTag, names.UnitTag:
+		return tag, nil
+	}
+	return nil, errors.Errorf("%q is not a machine or unit tag", arg)
+}
+
+const introspectDoc = `
+Report on a running agent without disturbing it, through the socket the
+agent serves introspection requests on. The subject is one of:
+
+    goroutines  the stacks of all the agent's goroutines
+    workers     the state of the agent's workers, how many times they
+                have been restarted and the last error they exited with
+    config      the agent's configuration, without its credentials
+`
+
+// introspectionSubjects maps the subjects of the introspect command
+// to the paths they are served on.
+var introspectionSubjects = map[string]string{
+	"goroutines": "/debug/goroutines",
+	"workers":    "/workers",
+	"config":     "/config",
+}
+
+// IntrospectCommand reports on a running agent.
+type IntrospectCommand struct {
+	cmd.CommandBase
+	dataDir string
+	tag     names.Tag
+	path    string
+}
+
+func (c *IntrospectCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "introspect",
+		Args:    "<agent tag> <goroutines|workers|config>",
+		Purpose: "report on a running agent",
+		Doc:     introspectDoc,
+	}
+}
+
+func (c *IntrospectCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.dataDir, "data-dir", DataDir, "directory for juju data")
+}
+
+func (c *IntrospectCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no agent tag specified")
+	}
+	tag, err := parseAgentTag(args[0])
+	if err != nil {
+		return err
+	}
+	c.tag = tag
+	if len(args) == 1 {
+		return errors.New("no subject specified")
+	}
+	path, ok := introspectionSubjects[args[1]]
+	if !ok {
+		return errors.Errorf("unknown subject %q", args[1])
+	}
+	c.path = path
+	return cmd.CheckEmpty(args[2:])
+}
+
+func (c *IntrospectCommand) Run(ctx *cmd.Context) error {
+	data, err := introspectionRequest(c.dataDir, c.tag, "GET", c.path, nil)
+	if err != nil {
+		return err
+	}
+	_, err = ctx.Stdout.Write(data)
+	return err
+}
+
+const apiTraceDoc = `
+Report whether the API calls made by a running agent are traced, or turn
+the tracing on or off. While on, the facade, method, duration and error
+of every call are written to the agent's log.
+
+Tracing can also be turned on when the agent starts by setting API_TRACE
+in the values of its agent.conf, or JUJU_API_TRACE in its environment,
+to true.
+`
+
+// APITraceCommand queries or changes the tracing of a running
+// agent's API calls through its introspection socket.
+type APITraceCommand struct {
+	cmd.CommandBase
+	dataDir string
+	tag     names.Tag
+	enabled *bool
+}
+
+func (c *APITraceCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "api-trace",
+		Args:    "<agent tag> [on|off]",
+		Purpose: "query or change the tracing of an agent's API calls",
+		Doc:     apiTraceDoc,
+	}
+}
+
+func (c *APITraceCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.dataDir, "data-dir", DataDir, "directory for juju data")
+}
+
+func (c *APITraceCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no agent tag specified")
+	}
+	tag, err := parseAgentTag(args[0])
+	if err != nil {
+		return err
+	}
+	c.tag = tag
+	if len(args) > 1 {
+		var enabled bool
+		switch args[1] {
+		case "on":
+			enabled = true
+		case "off":
+		default:
+			return errors.Errorf(`expected "on" or "off", got %q`, args[1])
+		}
+		c.enabled = &enabled
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *APITraceCommand) Run(ctx *cmd.Context) error {
+	method, form := "GET", url.Values(nil)
+	if c.enabled != nil {
+		method, form = "POST", url.Values{"enabled": {strconv.FormatBool(*c.enabled)}}
+	}
+	data, err := introspectionRequest(c.dataDir, c.tag, method, "/api-trace", form)
+	if err != nil {
+		return err
+	}
+	var enabled bool
+	if err := json.Unmarshal(data, &enabled); err != nil {
+		return errors.Annotate(err, "cannot parse agent response")
+	}
+	setting := "off"
+	if enabled {
+		setting = "on"
+	}
+	fmt.Fprintf(ctx.Stdout, "API call tracing is %s for %s\n", setting, c.tag)
+	return nil
+}
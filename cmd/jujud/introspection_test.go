@@ -0,0 +1,184 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+)
+
+type IntrospectionSuite struct {
+	testing.BaseSuite
+	dataDir string
+}
+
+var _ = gc.Suite(&IntrospectionSuite{})
+
+func (s *IntrospectionSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+	s.PatchValue(&DataDir, s.dataDir)
+	s.AddCleanup(func(*gc.C) { api.SetTracing(false) })
+}
+
+// apiTraceConfig is an agent configuration holding only an
+// API_TRACE value.
+type apiTraceConfig struct {
+	agent.Config
+	value string
+}
+
+func (conf apiTraceConfig) Value(key string) string {
+	if key == agent.APITrace {
+		return conf.value
+	}
+	return ""
+}
+
+func (s *IntrospectionSuite) TestInitAPITracing(c *gc.C) {
+	for i, test := range []struct {
+		conf     string
+		env      string
+		expected bool
+	}{
+		{"", "", false},
+		{"true", "", true},
+		{"", "1", true},
+		{"false", "true", true},
+		{"false", "false", false},
+		{"bogus", "", false},
+	} {
+		c.Logf("test %d: conf %q, env %q", i, test.conf, test.env)
+		s.PatchEnvironment(osenv.JujuAPITraceEnvKey, test.env)
+		initAPITracing(apiTraceConfig{value: test.conf})
+		c.Check(api.Tracing(), gc.Equals, test.expected)
+	}
+}
+
+func (s *IntrospectionSuite) TestArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		command  cmd.Command
+		args     []string
+		errMatch string
+	}{
+		{&APITraceCommand{}, nil, "no agent tag specified"},
+		{&APITraceCommand{}, []string{"foo"}, `"foo" is not a valid tag`},
+		{&APITraceCommand{}, []string{"service-wordpress"}, `"service-wordpress" is not a machine or unit tag`},
+		{&APITraceCommand{}, []string{"machine-0", "maybe"}, `expected "on" or "off", got "maybe"`},
+		{&APITraceCommand{}, []string{"machine-0", "on", "off"}, `unrecognized args: \["off"\]`},
+		{&IntrospectCommand{}, nil, "no agent tag specified"},
+		{&IntrospectCommand{}, []string{"machine-0"}, "no subject specified"},
+		{&IntrospectCommand{}, []string{"machine-0", "heap"}, `unknown subject "heap"`},
+		{&IntrospectCommand{}, []string{"machine-0", "workers", "config"}, `unrecognized args: \["config"\]`},
+	} {
+		c.Logf("test %d: %T %v", i, test.command, test.args)
+		err := testing.InitCommand(test.command, test.args)
+		c.Check(err, gc.ErrorMatches, test.errMatch)
+	}
+}
+
+// startIntrospection starts an introspection worker for a unit agent
+// whose only worker is "test".
+func (s *IntrospectionSuite) startIntrospection(c *gc.C) {
+	tag := names.NewUnitTag("wordpress/0")
+	conf, err := agent.NewAgentConfig(agent.AgentConfigParams{
+		DataDir:           s.dataDir,
+		Tag:               tag,
+		UpgradedToVersion: version.Current.Number,
+		Password:          "sekrit",
+		CACert:            "ca cert",
+		APIAddresses:      []string{"localhost:17070"},
+		Values:            map[string]string{agent.ProviderType: "dummy"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.MkdirAll(agent.Dir(s.dataDir, tag), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	runner := worker.NewRunner(func(error) bool { return false }, func(err0, err1 error) bool { return false })
+	s.AddCleanup(func(c *gc.C) { c.Check(worker.Stop(runner), jc.ErrorIsNil) })
+	err = runner.StartWorker("test", func() (worker.Worker, error) {
+		return worker.NewNoOpWorker(), nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	config := func() agent.Config { return conf }
+	w, err := newIntrospectionWorker(introspectionSocketPath(s.dataDir, tag), config, runner)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { c.Check(worker.Stop(w), jc.ErrorIsNil) })
+}
+
+func (s *IntrospectionSuite) TestIntrospectGoroutines(c *gc.C) {
+	s.startIntrospection(c)
+	ctx, err := testing.RunCommand(c, &IntrospectCommand{}, "unit-wordpress-0", "goroutines")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Matches, `(?s)goroutine \d+ \[running\]:.*`)
+}
+
+func (s *IntrospectionSuite) TestIntrospectWorkers(c *gc.C) {
+	s.startIntrospection(c)
+	var reports map[string]worker.WorkerReport
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		ctx, err := testing.RunCommand(c, &IntrospectCommand{}, "unit-wordpress-0", "workers")
+		c.Assert(err, jc.ErrorIsNil)
+		err = json.Unmarshal([]byte(testing.Stdout(ctx)), &reports)
+		c.Assert(err, jc.ErrorIsNil)
+		if reports["test"].State == "started" {
+			break
+		}
+	}
+	c.Assert(reports, jc.DeepEquals, map[string]worker.WorkerReport{
+		"test": {State: "started"},
+	})
+}
+
+func (s *IntrospectionSuite) TestIntrospectConfig(c *gc.C) {
+	s.startIntrospection(c)
+	ctx, err := testing.RunCommand(c, &IntrospectCommand{}, "unit-wordpress-0", "config")
+	c.Assert(err, jc.ErrorIsNil)
+	output := testing.Stdout(ctx)
+	c.Check(output, gc.Not(jc.Contains), "sekrit")
+	var config introspectionConfig
+	err = json.Unmarshal([]byte(output), &config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Tag, gc.Equals, "unit-wordpress-0")
+	c.Assert(config.DataDir, gc.Equals, s.dataDir)
+	c.Assert(config.APIAddresses, jc.DeepEquals, []string{"localhost:17070"})
+	c.Assert(config.UpgradedToVersion, gc.Equals, version.Current.Number.String())
+	c.Assert(config.Values, jc.DeepEquals, map[string]string{agent.ProviderType: "dummy"})
+}
+
+func (s *IntrospectionSuite) TestAPITraceCommand(c *gc.C) {
+	s.startIntrospection(c)
+
+	ctx, err := testing.RunCommand(c, &APITraceCommand{}, "unit-wordpress-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "API call tracing is off for unit-wordpress-0\n")
+
+	ctx, err = testing.RunCommand(c, &APITraceCommand{}, "unit-wordpress-0", "on")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "API call tracing is on for unit-wordpress-0\n")
+	c.Check(api.Tracing(), jc.IsTrue)
+
+	ctx, err = testing.RunCommand(c, &APITraceCommand{}, "unit-wordpress-0", "off")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "API call tracing is off for unit-wordpress-0\n")
+	c.Check(api.Tracing(), jc.IsFalse)
+}
+
+func (s *IntrospectionSuite) TestAgentNotRunning(c *gc.C) {
+	_, err := testing.RunCommand(c, &IntrospectCommand{}, "machine-0", "workers")
+	c.Assert(err, gc.ErrorMatches, `cannot connect to agent "machine-0": .*`)
+}
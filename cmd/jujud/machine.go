@@ -50,6 +50,7 @@ import (
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/bundledeployer"
 	"github.com/juju/juju/worker/charmrevisionworker"
 	"github.com/juju/juju/worker/cleaner"
 	"github.com/juju/juju/worker/deployer"
@@ -63,6 +64,7 @@ import (
 	"github.com/juju/juju/worker/minunitsworker"
 	"github.com/juju/juju/worker/networker"
 	"github.com/juju/juju/worker/peergrouper"
+	"github.com/juju/juju/worker/portforwarder"
 	"github.com/juju/juju/worker/provisioner"
 	"github.com/juju/juju/worker/resumer"
 	"github.com/juju/juju/worker/rsyslog"
@@ -231,6 +233,7 @@ func (a *MachineAgent) Run(_ *cmd.Context) error {
 		return err
 	}
 	logger.Infof("machine agent %v start (%s [%s])", a.Tag(), version.Current, runtime.Compiler)
+	initAPITracing(agentConfig)
 
 	if err := a.upgradeWorkerContext.InitializeUsingAgent(a); err != nil {
 		return errors.Annotate(err, "error during upgradeWorkerContext initialisation")
@@ -247,6 +250,10 @@ func (a *MachineAgent) Run(_ *cmd.Context) error {
 	a.runner.StartWorker("termination", func() (worker.Worker, error) {
 		return terminationworker.NewWorker(), nil
 	})
+	a.runner.StartWorker("introspection", func() (worker.Worker, error) {
+		socketPath := introspectionSocketPath(agentConfig.DataDir(), a.Tag())
+		return newIntrospectionWorker(socketPath, a.CurrentConfig, a.runner)
+	})
 	// At this point, all workers will have been configured to start
 	close(a.workersStarted)
 	err := a.runner.Wait()
@@ -384,6 +391,7 @@ func (a *MachineAgent) APIWorker() (worker.Worker, error) {
 			agentConfig,
 			a.previousAgentVersion,
 			a.upgradeWorkerContext.IsUpgradeRunning,
+			a,
 		), nil
 	})
 	runner.StartWorker("upgrade-steps", func() (worker.Worker, error) {
@@ -395,6 +403,14 @@ func (a *MachineAgent) APIWorker() (worker.Worker, error) {
 	a.startWorkerAfterUpgrade(runner, "machiner", func() (worker.Worker, error) {
 		return machiner.NewMachiner(st.Machiner(), agentConfig), nil
 	})
+	a.startWorkerAfterUpgrade(runner, "utilization-reporter", func() (worker.Worker, error) {
+		return machiner.NewUtilizationReporter(st.Machiner(), agentConfig), nil
+	})
+	if reporter, ok := a.runner.(worker.Reporter); ok {
+		a.startWorkerAfterUpgrade(runner, "crashloop-reporter", func() (worker.Worker, error) {
+			return machiner.NewCrashLoopReporter(st.Machiner(), agentConfig, reporter), nil
+		})
+	}
 	a.startWorkerAfterUpgrade(runner, "apiaddressupdater", func() (worker.Worker, error) {
 		return apiaddressupdater.NewAPIAddressUpdater(st.Machiner(), a), nil
 	})
@@ -445,6 +461,22 @@ func (a *MachineAgent) APIWorker() (worker.Worker, error) {
 				context := newDeployContext(apiDeployer, agentConfig)
 				return deployer.NewDeployer(apiDeployer, context), nil
 			})
+			// Units in containers are not reachable through the host's
+			// addresses unless their opened ports are forwarded. Local
+			// provider containers are reachable directly.
+			if providerType != provider.Local && state.ParentId(a.MachineId) == "" {
+				a.startWorkerAfterUpgrade(runner, "portforwarder", func() (worker.Worker, error) {
+					hostTag := names.NewMachineTag(a.MachineId)
+					var reserved []network.PortRange
+					if info, ok := agentConfig.StateServingInfo(); ok {
+						for _, port := range []int{info.APIPort, info.StatePort} {
+							reserved = append(reserved, network.PortRange{FromPort: port, ToPort: port, Protocol: "tcp"})
+						}
+					}
+					forwarder := portforwarder.NewIptablesForwarder(reserved...)
+					return portforwarder.NewPortForwarder(st.PortForwarder(), hostTag, forwarder), nil
+				})
+			}
 		case params.JobManageEnviron:
 			a.startWorkerAfterUpgrade(singularRunner, "environ-provisioner", func() (worker.Worker, error) {
 				return provisioner.NewEnvironProvisioner(st.Provisioner(), agentConfig), nil
@@ -654,6 +686,9 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 			a.startWorkerAfterUpgrade(singularRunner, "minunitsworker", func() (worker.Worker, error) {
 				return minunitsworker.NewMinUnitsWorker(st), nil
 			})
+			a.startWorkerAfterUpgrade(singularRunner, "bundledeployer", func() (worker.Worker, error) {
+				return bundledeployer.NewBundleDeployer(st), nil
+			})
 		case state.JobManageStateDeprecated:
 			// Legacy environments may set this, but we ignore it.
 		default:
@@ -451,6 +451,7 @@ func (s *MachineSuite) TestManageEnviron(c *gc.C) {
 	}
 
 	c.Assert(s.singularRecord.started(), jc.DeepEquals, []string{
+		"bundledeployer",
 		"charm-revision-updater",
 		"cleaner",
 		"environ-provisioner",
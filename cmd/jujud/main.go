@@ -114,6 +114,8 @@ func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
 	jujud.Register(&BootstrapCommand{})
 	jujud.Register(&MachineAgent{})
 	jujud.Register(&UnitAgent{})
+	jujud.Register(&APITraceCommand{})
+	jujud.Register(&IntrospectCommand{})
 	code = cmd.Main(jujud, ctx, args[1:])
 	return code, nil
 }
@@ -101,7 +101,7 @@ func (s *MainSuite) TestParseErrors(c *gc.C) {
 	msgf := "flag provided but not defined: --cheese"
 	checkMessage(c, msgf, "--cheese", "cavitate")
 
-	cmds := []string{"bootstrap-state", "unit", "machine"}
+	cmds := []string{"bootstrap-state", "unit", "machine", "api-trace"}
 	for _, cmd := range cmds {
 		checkMessage(c, msgf, cmd, "--cheese")
 	}
@@ -118,6 +118,9 @@ func (s *MainSuite) TestParseErrors(c *gc.C) {
 	checkMessage(c, msga, "machine",
 		"--machine-id", "42",
 		"toastie")
+	checkMessage(c, msga, "api-trace",
+		"machine-42", "on",
+		"toastie")
 }
 
 var expectedProviders = []string{
@@ -11,11 +11,11 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/names"
 	"github.com/juju/utils/exec"
-	"github.com/juju/utils/fslock"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/juju/sockets"
+	"github.com/juju/juju/utils/hooklock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/uniter"
 )
@@ -136,9 +136,9 @@ func (c *RunCommand) executeInUnitContext() (*exec.ExecResponse, error) {
 	return &result, err
 }
 
-func getLock() (*fslock.Lock, error) {
+func getLock() (*hooklock.Lock, error) {
 	lockDir := filepath.Join(DataDir, "locks")
-	return fslock.NewLock(lockDir, "uniter-hook-execution")
+	return hooklock.New(lockDir)
 }
 
 // appendProxyToCommands activates proxy settings on platforms
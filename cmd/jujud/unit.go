@@ -81,8 +81,13 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 		return err
 	}
 	agentLogger.Infof("unit agent %v start (%s [%s])", a.Tag().String(), version.Current, runtime.Compiler)
+	initAPITracing(agentConfig)
 	network.InitializeFromConfig(agentConfig)
 	a.runner.StartWorker("api", a.APIWorkers)
+	a.runner.StartWorker("introspection", func() (worker.Worker, error) {
+		socketPath := introspectionSocketPath(agentConfig.DataDir(), a.Tag())
+		return newIntrospectionWorker(socketPath, a.CurrentConfig, a.runner)
+	})
 	err := agentDone(a.runner.Wait())
 	a.tomb.Kill(err)
 	return err
@@ -114,6 +119,7 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 			agentConfig,
 			agentConfig.UpgradedToVersion(),
 			func() bool { return false },
+			a,
 		), nil
 	})
 	runner.StartWorker("logger", func() (worker.Worker, error) {
@@ -68,7 +68,7 @@ func (c *ToolsMetadataCommand) Run(context *cmd.Context) error {
 		sourceDataSource := simplestreams.NewURLDataSource("local source", source, utils.VerifySSLHostnames)
 		toolsList, err = envtools.FindToolsForCloud(
 			[]simplestreams.DataSource{sourceDataSource}, simplestreams.CloudSpec{}, c.stream,
-			version.Current.Major, minorVersion, coretools.Filter{})
+			version.Current.Major, minorVersion, coretools.Filter{}, false)
 	}
 	if err != nil {
 		return err
@@ -30,6 +30,7 @@ type ValidateImageMetadataCommand struct {
 	region       string
 	endpoint     string
 	stream       string
+	regions      []string
 }
 
 var validateImagesMetadataDoc = `
@@ -61,6 +62,15 @@ production. In this case, the metadata is placed in a local directory, a cloud
 provider type is specified (ec2, openstack etc), and the validation is performed
 for each supported region and series.
 
+Private clouds often have several regions to be covered by the same
+metadata. The --regions option takes a comma-separated list of regions,
+and checks that images are found for every one of them, reporting those
+that are not covered. Images are described per region, so the
+availability zones of a region are covered when the region is.
+
+  juju metadata validate-images -p openstack -s trusty -u <auth url> \
+      --regions region-1,region-2 -d <some directory>
+
 Example bash snippet:
 
 #!/bin/bash
@@ -87,14 +97,46 @@ func (c *ValidateImageMetadataCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.region, "r", "", "the region for which to validate (overrides env config region)")
 	f.StringVar(&c.endpoint, "u", "", "the cloud endpoint URL for which to validate (overrides env config endpoint)")
 	f.StringVar(&c.stream, "m", "", "the images stream (defaults to released)")
+	f.Var(newRegionsValue(&c.regions), "regions", "comma-separated regions that must all be covered by the metadata")
+}
+
+// regionsValue implements gnuflag.Value for a comma-separated
+// list of regions.
+type regionsValue struct {
+	regions *[]string
+}
+
+func newRegionsValue(regions *[]string) *regionsValue {
+	return &regionsValue{regions}
+}
+
+func (v *regionsValue) Set(s string) error {
+	var regions []string
+	for _, region := range strings.Split(s, ",") {
+		if region = strings.TrimSpace(region); region != "" {
+			regions = append(regions, region)
+		}
+	}
+	if len(regions) == 0 {
+		return fmt.Errorf("no regions specified")
+	}
+	*v.regions = regions
+	return nil
+}
+
+func (v *regionsValue) String() string {
+	return strings.Join(*v.regions, ",")
 }
 
 func (c *ValidateImageMetadataCommand) Init(args []string) error {
+	if c.region != "" && len(c.regions) > 0 {
+		return fmt.Errorf("cannot specify both -r and --regions")
+	}
 	if c.providerType != "" {
 		if c.series == "" {
 			return fmt.Errorf("series required if provider type is specified")
 		}
-		if c.region == "" {
+		if c.region == "" && len(c.regions) == 0 {
 			return fmt.Errorf("region required if provider type is specified")
 		}
 		if c.metadataDir == "" {
@@ -129,50 +171,95 @@ func (oes *overrideEnvStream) Config() *config.Config {
 }
 
 func (c *ValidateImageMetadataCommand) Run(context *cmd.Context) error {
+	if len(c.regions) > 0 {
+		return c.checkCoverage(context)
+	}
+	params, err := c.lookupParams(context, c.region)
+	if err != nil {
+		return err
+	}
+	image_ids, resolveInfo, err := imagemetadata.ValidateImageMetadata(params)
+	if err != nil {
+		if resolveInfo != nil {
+			metadata := map[string]interface{}{
+				"Resolve Metadata": *resolveInfo,
+			}
+			if metadataYaml, yamlErr := cmd.FormatYaml(metadata); yamlErr == nil {
+				err = fmt.Errorf("%v\n%v", err, string(metadataYaml))
+			}
+		}
+		return err
+	}
+	if len(image_ids) > 0 {
+		metadata := map[string]interface{}{
+			"ImageIds":         image_ids,
+			"Region":           params.Region,
+			"Resolve Metadata": *resolveInfo,
+		}
+		c.out.Write(context, metadata)
+	} else {
+		var sources []string
+		for _, s := range params.Sources {
+			url, err := s.URL("")
+			if err == nil {
+				sources = append(sources, fmt.Sprintf("- %s (%s)", s.Description(), url))
+			}
+		}
+		return fmt.Errorf(
+			"no matching image ids for region %s using sources:\n%s",
+			params.Region, strings.Join(sources, "\n"))
+	}
+	return nil
+}
+
+// lookupParams returns the parameters for looking up images in the
+// given region, taken from the environment or provider and overridden
+// by the command arguments.
+func (c *ValidateImageMetadataCommand) lookupParams(context *cmd.Context, region string) (*simplestreams.MetadataLookupParams, error) {
 	var params *simplestreams.MetadataLookupParams
 
 	if c.providerType == "" {
 		store, err := configstore.Default()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		environ, err := c.prepare(context, store)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		mdLookup, ok := environ.(simplestreams.MetadataValidator)
 		if !ok {
-			return fmt.Errorf("%s provider does not support image metadata validation", environ.Config().Type())
+			return nil, fmt.Errorf("%s provider does not support image metadata validation", environ.Config().Type())
 		}
-		params, err = mdLookup.MetadataLookupParams(c.region)
+		params, err = mdLookup.MetadataLookupParams(region)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		oes := &overrideEnvStream{environ, c.stream}
 		params.Sources, err = environs.ImageMetadataSources(oes)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		prov, err := environs.Provider(c.providerType)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		mdLookup, ok := prov.(simplestreams.MetadataValidator)
 		if !ok {
-			return fmt.Errorf("%s provider does not support image metadata validation", c.providerType)
+			return nil, fmt.Errorf("%s provider does not support image metadata validation", c.providerType)
 		}
-		params, err = mdLookup.MetadataLookupParams(c.region)
+		params, err = mdLookup.MetadataLookupParams(region)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if c.series != "" {
 		params.Series = c.series
 	}
-	if c.region != "" {
-		params.Region = c.region
+	if region != "" {
+		params.Region = region
 	}
 	if c.endpoint != "" {
 		params.Endpoint = c.endpoint
@@ -180,7 +267,7 @@ func (c *ValidateImageMetadataCommand) Run(context *cmd.Context) error {
 	if c.metadataDir != "" {
 		dir := filepath.Join(c.metadataDir, "images")
 		if _, err := os.Stat(dir); err != nil {
-			return err
+			return nil, err
 		}
 		params.Sources = []simplestreams.DataSource{
 			simplestreams.NewURLDataSource(
@@ -188,37 +275,34 @@ func (c *ValidateImageMetadataCommand) Run(context *cmd.Context) error {
 		}
 	}
 	params.Stream = c.stream
+	return params, nil
+}
 
-	image_ids, resolveInfo, err := imagemetadata.ValidateImageMetadata(params)
-	if err != nil {
-		if resolveInfo != nil {
-			metadata := map[string]interface{}{
-				"Resolve Metadata": *resolveInfo,
-			}
-			if metadataYaml, yamlErr := cmd.FormatYaml(metadata); yamlErr == nil {
-				err = fmt.Errorf("%v\n%v", err, string(metadataYaml))
-			}
+// checkCoverage validates the metadata for each of the regions given
+// with --regions, and fails if any of them has no matching images.
+func (c *ValidateImageMetadataCommand) checkCoverage(context *cmd.Context) error {
+	imageIds := make(map[string][]string)
+	var missing []string
+	for _, region := range c.regions {
+		params, err := c.lookupParams(context, region)
+		if err != nil {
+			return err
 		}
-		return err
-	}
-	if len(image_ids) > 0 {
-		metadata := map[string]interface{}{
-			"ImageIds":         image_ids,
-			"Region":           params.Region,
-			"Resolve Metadata": *resolveInfo,
+		ids, _, err := imagemetadata.ValidateImageMetadata(params)
+		if err != nil || len(ids) == 0 {
+			logger.Debugf("no images found for region %s: %v", region, err)
+			missing = append(missing, region)
+			continue
 		}
-		c.out.Write(context, metadata)
-	} else {
-		var sources []string
-		for _, s := range params.Sources {
-			url, err := s.URL("")
-			if err == nil {
-				sources = append(sources, fmt.Sprintf("- %s (%s)", s.Description(), url))
-			}
-		}
-		return fmt.Errorf(
-			"no matching image ids for region %s using sources:\n%s",
-			params.Region, strings.Join(sources, "\n"))
+		imageIds[region] = ids
+	}
+	if len(imageIds) > 0 {
+		c.out.Write(context, map[string]interface{}{
+			"ImageIds": imageIds,
+		})
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("no matching image ids for regions: %s", strings.Join(missing, ", "))
 	}
 	return nil
 }
@@ -43,6 +43,12 @@ var validateInitImageErrorTests = []struct {
 	}, {
 		args: []string{"-p", "ec2", "-s", "series", "-r", "region"},
 		err:  `metadata directory required if provider type is specified`,
+	}, {
+		args: []string{"-p", "ec2", "-s", "series", "-r", "region", "--regions", "a,b", "-d", "dir"},
+		err:  `cannot specify both -r and --regions`,
+	}, {
+		args: []string{"-p", "ec2", "-s", "series", "--regions", " , ", "-d", "dir"},
+		err:  `invalid value " , " for flag --regions: no regions specified`,
 	},
 }
 
@@ -230,3 +236,32 @@ func (s *ValidateImageMetadataSuite) TestOpenstackLocalMetadataNoMatch(c *gc.C)
 	strippedOut = strings.Replace(errOut, "\n", "", -1)
 	c.Check(strippedOut, gc.Matches, `.*Resolve Metadata:.*`)
 }
+
+func (s *ValidateImageMetadataSuite) TestOpenstackLocalMetadataRegionCoverage(c *gc.C) {
+	s.makeLocalMetadata(c, "1234", "region-2", "raring", "some-auth-url", "")
+	s.makeLocalMetadata(c, "5678", "region-3", "raring", "some-auth-url", "")
+	ctx := coretesting.Context(c)
+	code := cmd.Main(
+		envcmd.Wrap(&ValidateImageMetadataCommand{}), ctx, []string{
+			"-p", "openstack", "-s", "raring", "--regions", "region-2,region-3",
+			"-u", "some-auth-url", "-d", s.metadataDir},
+	)
+	c.Assert(code, gc.Equals, 0)
+	strippedOut := strings.Replace(ctx.Stdout.(*bytes.Buffer).String(), "\n", "", -1)
+	c.Check(strippedOut, gc.Matches, `ImageIds:.*region-2:.*"1234".*region-3:.*"5678".*`)
+}
+
+func (s *ValidateImageMetadataSuite) TestOpenstackLocalMetadataRegionCoverageMissing(c *gc.C) {
+	s.makeLocalMetadata(c, "1234", "region-2", "raring", "some-auth-url", "")
+	ctx := coretesting.Context(c)
+	code := cmd.Main(
+		envcmd.Wrap(&ValidateImageMetadataCommand{}), ctx, []string{
+			"-p", "openstack", "-s", "raring", "--regions", "region-1,region-2,region-3",
+			"-u", "some-auth-url", "-d", s.metadataDir},
+	)
+	c.Assert(code, gc.Equals, 1)
+	strippedOut := strings.Replace(ctx.Stdout.(*bytes.Buffer).String(), "\n", "", -1)
+	c.Check(strippedOut, gc.Matches, `ImageIds:.*region-2:.*"1234".*`)
+	errOut := ctx.Stderr.(*bytes.Buffer).String()
+	c.Check(errOut, gc.Matches, "error: no matching image ids for regions: region-1, region-3\n")
+}
@@ -29,6 +29,7 @@ const (
 	Tags         = "tags"
 	InstanceType = "instance-type"
 	Networks     = "networks"
+	Zone         = "zone"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -78,6 +79,11 @@ type Value struct {
 	// negative values are accepted, and the difference is the latter
 	// have a "^" prefix to the name.
 	Networks *[]string `json:"networks,omitempty" yaml:"networks,omitempty"`
+
+	// Zone, if not nil or empty, indicates that a machine must be
+	// provisioned in the named availability zone. Only valid for
+	// providers that can choose a zone when acquiring a machine.
+	Zone *string `json:"zone,omitempty" yaml:"zone,omitempty"`
 }
 
 // fieldNames records a mapping from the constraint tag to struct field name.
@@ -191,6 +197,9 @@ func (v Value) String() string {
 		s := strings.Join(*v.Networks, ",")
 		strs = append(strs, "networks="+s)
 	}
+	if v.Zone != nil {
+		strs = append(strs, "zone="+*v.Zone)
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -330,6 +339,8 @@ func (v *Value) setRaw(raw string) error {
 		err = v.setInstanceType(str)
 	case Networks:
 		err = v.setNetworks(str)
+	case Zone:
+		err = v.setZone(str)
 	default:
 		return fmt.Errorf("unknown constraint %q", name)
 	}
@@ -360,6 +371,8 @@ func (v *Value) SetYAML(tag string, value interface{}) bool {
 			v.Container = &ctype
 		case InstanceType:
 			v.InstanceType = &vstr
+		case Zone:
+			v.Zone = &vstr
 		case CpuCores:
 			v.CpuCores, err = parseUint64(vstr)
 		case CpuPower:
@@ -443,6 +456,14 @@ func (v *Value) setInstanceType(str string) error {
 	return nil
 }
 
+func (v *Value) setZone(str string) error {
+	if v.Zone != nil {
+		return fmt.Errorf("already set")
+	}
+	v.Zone = &str
+	return nil
+}
+
 func (v *Value) setMem(str string) (err error) {
 	if v.Mem != nil {
 		return fmt.Errorf("already set")
@@ -289,6 +289,19 @@ var parseConstraintsTests = []struct {
 		args:    []string{"instance-type="},
 	},
 
+	// zone
+	{
+		summary: "set zone",
+		args:    []string{"zone=rack2"},
+	}, {
+		summary: "zone empty",
+		args:    []string{"zone="},
+	}, {
+		summary: "double set zone together",
+		args:    []string{"zone=rack1 zone=rack2"},
+		err:     `bad "zone" constraint: already set`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -299,7 +312,8 @@ var parseConstraintsTests = []struct {
 		summary: "kitchen sink separately",
 		args: []string{
 			"root-disk=8G", "mem=2T", "cpu-cores=4096", "cpu-power=9001", "arch=armhf",
-			"container=lxc", "tags=foo,bar", "networks=net1,^net2", "instance-type=foo"},
+			"container=lxc", "tags=foo,bar", "networks=net1,^net2", "instance-type=foo",
+			"zone=rack2"},
 	},
 }
 
@@ -461,6 +475,8 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Networks3", constraints.Value{Networks: &[]string{"net1", "^net2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
+	{"Zone1", constraints.Value{Zone: strp("")}},
+	{"Zone2", constraints.Value{Zone: strp("rack2")}},
 	{"All", constraints.Value{
 		Arch:         strp("i386"),
 		Container:    ctypep("lxc"),
@@ -471,6 +487,7 @@ var constraintsRoundtripTests = []roundTrip{
 		Tags:         &[]string{"foo", "bar"},
 		Networks:     &[]string{"net1", "^net2"},
 		InstanceType: strp("foo"),
+		Zone:         strp("rack2"),
 	}},
 }
 
@@ -664,6 +681,11 @@ func (s *ConstraintsSuite) TestAttributesWithValues(c *gc.C) {
 		} else {
 			assertMissing("instance-type")
 		}
+		if cons.Zone != nil {
+			c.Check(obtained["zone"], gc.Equals, *cons.Zone)
+		} else {
+			assertMissing("zone")
+		}
 	}
 }
 
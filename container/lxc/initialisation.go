@@ -4,9 +4,17 @@
 package lxc
 
 import (
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
 	"github.com/juju/utils/apt"
 
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/juju/arch"
 )
 
 var requiredPackages = []string{
@@ -14,6 +22,19 @@ var requiredPackages = []string{
 	"cloud-image-utils",
 }
 
+var (
+	// cloudImageCacheDir is where the ubuntu-cloud template looks for
+	// previously downloaded cloud images.
+	cloudImageCacheDir = "/var/cache/lxc"
+
+	// cloudImageCommandOutput runs the commands used to find and
+	// download cloud images; it is overridden in tests.
+	cloudImageCommandOutput = (*exec.Cmd).Output
+
+	// prefetchCloudImage is overridden in tests.
+	prefetchCloudImage = fetchCloudImage
+)
+
 type containerInitialiser struct {
 	series string
 }
@@ -29,7 +50,19 @@ func NewContainerInitialiser(series string) container.Initialiser {
 
 // Initialise is specified on the container.Initialiser interface.
 func (ci *containerInitialiser) Initialise() error {
-	return ensureDependencies(ci.series)
+	if err := ensureDependencies(ci.series); err != nil {
+		return err
+	}
+	if ci.series == "" {
+		return nil
+	}
+	// Fetching the image now means the first container created on this
+	// host does not have to wait for the download. It is only an
+	// optimisation, so failures are not fatal.
+	if err := prefetchCloudImage(ci.series, arch.HostArch()); err != nil {
+		logger.Warningf("cannot pre-fetch %s cloud image: %v", ci.series, err)
+	}
+	return nil
 }
 
 // ensureDependencies creates a set of install packages using AptGetPreparePackages
@@ -45,3 +78,34 @@ func ensureDependencies(series string) error {
 	}
 	return err
 }
+
+// fetchCloudImage downloads the released cloud image for the given
+// series and architecture into the cache used by the ubuntu-cloud
+// template, unless it is already there.
+func fetchCloudImage(series, arch string) error {
+	cmd := exec.Command("ubuntu-cloudimg-query", series, "released", arch, "--format", "%{url}\n")
+	out, err := cloudImageCommandOutput(cmd)
+	if err != nil {
+		return errors.Annotate(err, "cannot find cloud image")
+	}
+	url := strings.TrimSpace(string(out))
+	dir := filepath.Join(cloudImageCacheDir, "cloud-"+series)
+	imagePath := filepath.Join(dir, path.Base(url))
+	if _, err := os.Stat(imagePath); err == nil {
+		logger.Debugf("cloud image %s already cached", imagePath)
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	// Download to a temporary file so that an interrupted download is
+	// never mistaken for a cached image.
+	partialPath := imagePath + ".part"
+	logger.Infof("fetching cloud image %s", url)
+	cmd = exec.Command("wget", "--quiet", "--output-document", partialPath, url)
+	if _, err := cloudImageCommandOutput(cmd); err != nil {
+		os.Remove(partialPath)
+		return errors.Annotatef(err, "cannot download %s", url)
+	}
+	return os.Rename(partialPath, imagePath)
+}
@@ -4,6 +4,12 @@
 package lxc
 
 import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
 	"github.com/juju/utils/apt"
 	gc "gopkg.in/check.v1"
 
@@ -16,6 +22,13 @@ type InitialiserSuite struct {
 
 var _ = gc.Suite(&InitialiserSuite{})
 
+func (s *InitialiserSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(&prefetchCloudImage, func(series, arch string) error {
+		return nil
+	})
+}
+
 func (s *InitialiserSuite) TestLTSSeriesPackages(c *gc.C) {
 	cmdChan := s.HookCommandOutput(&apt.CommandOutput, []byte{}, nil)
 	container := NewContainerInitialiser("precise")
@@ -43,3 +56,64 @@ func (s *InitialiserSuite) TestNoSeriesPackages(c *gc.C) {
 		"install", "lxc", "cloud-image-utils",
 	})
 }
+
+func (s *InitialiserSuite) TestInitialisePrefetchesImage(c *gc.C) {
+	s.HookCommandOutput(&apt.CommandOutput, []byte{}, nil)
+	var fetched []string
+	s.PatchValue(&prefetchCloudImage, func(series, arch string) error {
+		fetched = append(fetched, series)
+		return errors.New("no network")
+	})
+	err := NewContainerInitialiser("trusty").Initialise()
+	c.Assert(err, gc.IsNil)
+	c.Assert(fetched, gc.DeepEquals, []string{"trusty"})
+}
+
+func (s *InitialiserSuite) TestFetchCloudImageCached(c *gc.C) {
+	cacheDir := c.MkDir()
+	s.PatchValue(&cloudImageCacheDir, cacheDir)
+	imageDir := filepath.Join(cacheDir, "cloud-trusty")
+	err := os.MkdirAll(imageDir, 0755)
+	c.Assert(err, gc.IsNil)
+	err = ioutil.WriteFile(filepath.Join(imageDir, "image.tar.gz"), nil, 0644)
+	c.Assert(err, gc.IsNil)
+
+	url := "http://cloud-images.ubuntu.com/trusty/image.tar.gz"
+	cmdChan := s.HookCommandOutput(&cloudImageCommandOutput, []byte(url+"\n"), nil)
+	err = fetchCloudImage("trusty", "amd64")
+	c.Assert(err, gc.IsNil)
+
+	cmd := <-cmdChan
+	c.Assert(cmd.Args, gc.DeepEquals, []string{
+		"ubuntu-cloudimg-query", "trusty", "released", "amd64", "--format", "%{url}\n",
+	})
+	select {
+	case cmd := <-cmdChan:
+		c.Fatalf("unexpected command %v", cmd.Args)
+	default:
+	}
+}
+
+func (s *InitialiserSuite) TestFetchCloudImageDownloads(c *gc.C) {
+	cacheDir := c.MkDir()
+	s.PatchValue(&cloudImageCacheDir, cacheDir)
+	imagePath := filepath.Join(cacheDir, "cloud-trusty", "image.tar.gz")
+	url := "http://cloud-images.ubuntu.com/trusty/image.tar.gz"
+	var commands [][]string
+	s.PatchValue(&cloudImageCommandOutput, func(cmd *exec.Cmd) ([]byte, error) {
+		commands = append(commands, cmd.Args)
+		if cmd.Args[0] == "wget" {
+			return nil, ioutil.WriteFile(imagePath+".part", []byte("image"), 0644)
+		}
+		return []byte(url + "\n"), nil
+	})
+	err := fetchCloudImage("trusty", "amd64")
+	c.Assert(err, gc.IsNil)
+	c.Assert(commands, gc.HasLen, 2)
+	c.Assert(commands[1], gc.DeepEquals, []string{
+		"wget", "--quiet", "--output-document", imagePath + ".part", url,
+	})
+	data, err := ioutil.ReadFile(imagePath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "image")
+}
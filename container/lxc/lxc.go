@@ -22,6 +22,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/environs/cloudinit"
 	"github.com/juju/juju/instance"
@@ -285,6 +286,9 @@ func (manager *containerManager) CreateContainer(
 	if err := mountHostLogDir(name, manager.logdir); err != nil {
 		return nil, nil, errors.Annotate(err, "failed to mount the directory to log to")
 	}
+	if err := limitContainerResources(name, machineConfig.Constraints); err != nil {
+		return nil, nil, errors.Annotate(err, "failed to set the container resource limits")
+	}
 	// Start the lxc container with the appropriate settings for grabbing the
 	// console output and a log file.
 	consoleFile := filepath.Join(directory, "console.log")
@@ -300,7 +304,10 @@ func (manager *containerManager) CreateContainer(
 	}
 
 	hardware := &instance.HardwareCharacteristics{
-		Arch: &version.Current.Arch,
+		Arch:     &version.Current.Arch,
+		Mem:      positive(machineConfig.Constraints.Mem),
+		CpuCores: positive(machineConfig.Constraints.CpuCores),
+		CpuPower: positive(machineConfig.Constraints.CpuPower),
 	}
 
 	return &lxcInstance{lxcContainer, name}, hardware, nil
@@ -351,6 +358,51 @@ func mountHostLogDir(name, logDir string) error {
 	return appendToContainerConfig(name, line)
 }
 
+// cpuPeriod is the CFS scheduling period, in microseconds, used when
+// limiting the number of cores a container may use.
+const cpuPeriod = 100000
+
+// resourceLimits returns the lxc.conf lines that translate the memory
+// and cpu constraints into cgroup limits on the container. A cpu-power
+// of 100 is taken to be the power of a single core.
+func resourceLimits(cons constraints.Value) string {
+	var lines []string
+	if mem := positive(cons.Mem); mem != nil {
+		lines = append(lines, fmt.Sprintf("lxc.cgroup.memory.limit_in_bytes = %dM", *mem))
+	}
+	if cores := positive(cons.CpuCores); cores != nil {
+		lines = append(lines,
+			fmt.Sprintf("lxc.cgroup.cpu.cfs_period_us = %d", cpuPeriod),
+			fmt.Sprintf("lxc.cgroup.cpu.cfs_quota_us = %d", *cores*cpuPeriod),
+		)
+	}
+	if power := positive(cons.CpuPower); power != nil {
+		lines = append(lines, fmt.Sprintf("lxc.cgroup.cpu.shares = %d", *power*1024/100))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func limitContainerResources(name string, cons constraints.Value) error {
+	limits := resourceLimits(cons)
+	if limits == "" {
+		return nil
+	}
+	logger.Tracef("limiting container resources: %v", cons)
+	return appendToContainerConfig(name, limits)
+}
+
+// positive returns v if it points to a value greater than zero,
+// and nil otherwise.
+func positive(v *uint64) *uint64 {
+	if v == nil || *v == 0 {
+		return nil
+	}
+	return v
+}
+
 func (manager *containerManager) DestroyContainer(id instance.Id) error {
 	start := time.Now()
 	name := string(id)
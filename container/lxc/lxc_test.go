@@ -23,12 +23,15 @@ import (
 	"launchpad.net/golxc"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/lxc"
 	"github.com/juju/juju/container/lxc/mock"
 	lxctesting "github.com/juju/juju/container/lxc/testing"
 	containertesting "github.com/juju/juju/container/testing"
+	"github.com/juju/juju/environs/config"
 	instancetest "github.com/juju/juju/instance/testing"
+	"github.com/juju/juju/provider/dummy"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -473,6 +476,37 @@ lxc.mount.entry=/var/log/juju var/log/juju none defaults,bind 0 0
 	c.Assert(autostartLink, jc.DoesNotExist)
 }
 
+func (s *LxcSuite) TestCreateContainerWithResourceLimits(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	machineConfig, err := containertesting.MockMachineConfig("1/lxc/0")
+	c.Assert(err, gc.IsNil)
+	machineConfig.Config, err = config.New(config.NoDefaults, dummy.SampleConfig())
+	c.Assert(err, gc.IsNil)
+	machineConfig.Constraints = constraints.MustParse("mem=2G cpu-cores=2 cpu-power=50")
+
+	network := container.BridgeNetworkConfig("nic42")
+	instance, hardware, err := manager.CreateContainer(machineConfig, "quantal", network)
+	c.Assert(err, gc.IsNil)
+	c.Assert(hardware.String(), gc.Matches, "arch=.* cpu-cores=2 cpu-power=50 mem=2048M")
+
+	conf, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(instance.Id())))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(conf), jc.Contains, `
+lxc.cgroup.memory.limit_in_bytes = 2048M
+lxc.cgroup.cpu.cfs_period_us = 100000
+lxc.cgroup.cpu.cfs_quota_us = 200000
+lxc.cgroup.cpu.shares = 512
+`[1:])
+}
+
+func (s *LxcSuite) TestCreateContainerWithoutResourceLimits(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	instance := containertesting.CreateContainer(c, manager, "1/lxc/0")
+	conf, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(instance.Id())))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(conf), gc.Not(jc.Contains), "lxc.cgroup")
+}
+
 func (s *LxcSuite) TestDestroyContainerRemovesAutostartLink(c *gc.C) {
 	manager := s.makeManager(c, "test")
 	instance := containertesting.CreateContainer(c, manager, "1/lxc/0")
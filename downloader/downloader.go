@@ -25,11 +25,19 @@ type Status struct {
 	Err error
 }
 
+// Credentials holds the HTTP basic authentication credentials sent
+// with a download request.
+type Credentials struct {
+	User     string
+	Password string
+}
+
 // Download can download a file from the network.
 type Download struct {
 	tomb                 tomb.Tomb
 	done                 chan Status
 	hostnameVerification utils.SSLHostnameVerification
+	credentials          *Credentials
 }
 
 // New returns a new Download instance downloading from the given URL
@@ -37,9 +45,20 @@ type Download struct {
 // os.TempDir(). If disableSSLHostnameVerification is true then a non-
 // validating http client will be used.
 func New(url, dir string, hostnameVerification utils.SSLHostnameVerification) *Download {
+	return newDownload(url, dir, hostnameVerification, nil)
+}
+
+// NewWithCredentials is like New, but authenticates the download
+// request with the given credentials.
+func NewWithCredentials(url, dir string, credentials Credentials, hostnameVerification utils.SSLHostnameVerification) *Download {
+	return newDownload(url, dir, hostnameVerification, &credentials)
+}
+
+func newDownload(url, dir string, hostnameVerification utils.SSLHostnameVerification, credentials *Credentials) *Download {
 	d := &Download{
 		done:                 make(chan Status),
 		hostnameVerification: hostnameVerification,
+		credentials:          credentials,
 	}
 	go d.run(url, dir)
 	return d
@@ -63,7 +82,7 @@ func (d *Download) run(url, dir string) {
 	// TODO(dimitern) 2013-10-03 bug #1234715
 	// Add a testing HTTPS storage to verify the
 	// disableSSLHostnameVerification behavior here.
-	file, err := download(url, dir, d.hostnameVerification)
+	file, err := download(url, dir, d.hostnameVerification, d.credentials)
 	if err != nil {
 		err = fmt.Errorf("cannot download %q: %v", url, err)
 	}
@@ -78,7 +97,7 @@ func (d *Download) run(url, dir string) {
 	}
 }
 
-func download(url, dir string, hostnameVerification utils.SSLHostnameVerification) (file *os.File, err error) {
+func download(url, dir string, hostnameVerification utils.SSLHostnameVerification, credentials *Credentials) (file *os.File, err error) {
 	if dir == "" {
 		dir = os.TempDir()
 	}
@@ -93,7 +112,14 @@ func download(url, dir string, hostnameVerification utils.SSLHostnameVerificatio
 	}()
 	// TODO(rog) make the download operation interruptible.
 	client := utils.GetHTTPClient(hostnameVerification)
-	resp, err := client.Get(url)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if credentials != nil {
+		req.SetBasicAuth(credentials.User, credentials.Password)
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@
 package downloader_test
 
 import (
+	"encoding/base64"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -72,6 +73,21 @@ func (s *suite) TestDownloadWithDisablingSSLHostnameVerification(c *gc.C) {
 	s.testDownload(c, utils.NoVerifySSLHostnames)
 }
 
+func (s *suite) TestDownloadWithCredentials(c *gc.C) {
+	gitjujutesting.Server.Response(200, nil, []byte("archive"))
+	credentials := downloader.Credentials{User: "unit-mysql-0", Password: "secret"}
+	d := downloader.NewWithCredentials(s.URL("/archive.tgz"), c.MkDir(), credentials, utils.VerifySSLHostnames)
+	status := <-d.Done()
+	c.Assert(status.Err, gc.IsNil)
+	defer os.Remove(status.File.Name())
+	defer status.File.Close()
+	assertFileContents(c, status.File, "archive")
+
+	req := gitjujutesting.Server.WaitRequest()
+	auth := base64.StdEncoding.EncodeToString([]byte("unit-mysql-0:secret"))
+	c.Assert(req.Header.Get("Authorization"), gc.Equals, "Basic "+auth)
+}
+
 func (s *suite) TestDownloadError(c *gc.C) {
 	gitjujutesting.Server.Response(404, nil, nil)
 	d := downloader.New(s.URL("/archive.tgz"), c.MkDir(), utils.VerifySSLHostnames)
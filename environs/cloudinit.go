@@ -6,6 +6,7 @@ package environs
 import (
 	"fmt"
 	"path"
+	"strconv"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -193,10 +194,29 @@ func FinishMachineConfig(mcfg *cloudinit.MachineConfig, cfg *config.Config) (err
 	if mcfg.Config, err = BootstrapConfig(cfg); err != nil {
 		return err
 	}
+	setMongoTuning(mcfg.AgentEnvironment, cfg)
 
 	return nil
 }
 
+// setMongoTuning records in the agent environment any mongo settings
+// that differ from the defaults, so that the bootstrap machine agent
+// can configure its database accordingly.
+func setMongoTuning(agentEnv map[string]string, cfg *config.Config) {
+	if size := cfg.MongoOplogSize(); size > 0 {
+		agentEnv[agent.MongoOplogSize] = strconv.Itoa(size)
+	}
+	if !cfg.MongoJournal() {
+		agentEnv[agent.MongoJournal] = "false"
+	}
+	if cfg.MongoPrealloc() {
+		agentEnv[agent.MongoPrealloc] = "true"
+	}
+	if size := cfg.MongoCacheSize(); size > 0 {
+		agentEnv[agent.MongoCacheSize] = strconv.Itoa(size)
+	}
+}
+
 func configureCloudinit(mcfg *cloudinit.MachineConfig, cloudcfg *coreCloudinit.Config) (cloudinit.UserdataConfig, error) {
 	// When bootstrapping, we only want to apt-get update/upgrade
 	// and setup the SSH keys. The rest we leave to cloudinit/sshinit.
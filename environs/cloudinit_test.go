@@ -173,6 +173,30 @@ func (s *CloudInitSuite) TestFinishBootstrapConfig(c *gc.C) {
 	c.Assert(err, gc.NotNil)
 }
 
+func (s *CloudInitSuite) TestFinishBootstrapConfigMongoTuning(c *gc.C) {
+	attrs := dummySampleConfig().Merge(testing.Attrs{
+		"authorized-keys":  "we-are-the-keys",
+		"admin-secret":     "lisboan-pork",
+		"agent-version":    "1.2.3",
+		"state-server":     false,
+		"mongo-oplog-size": 256,
+		"mongo-journal":    false,
+		"mongo-cache-size": 1,
+	})
+	cfg, err := config.New(config.NoDefaults, attrs)
+	c.Assert(err, gc.IsNil)
+	mcfg := &cloudinit.MachineConfig{
+		Bootstrap: true,
+	}
+	err = environs.FinishMachineConfig(mcfg, cfg)
+	c.Assert(err, gc.IsNil)
+	c.Check(mcfg.AgentEnvironment[agent.MongoOplogSize], gc.Equals, "256")
+	c.Check(mcfg.AgentEnvironment[agent.MongoJournal], gc.Equals, "false")
+	c.Check(mcfg.AgentEnvironment[agent.MongoCacheSize], gc.Equals, "1")
+	_, ok := mcfg.AgentEnvironment[agent.MongoPrealloc]
+	c.Check(ok, jc.IsFalse)
+}
+
 func (s *CloudInitSuite) TestUserData(c *gc.C) {
 	s.testUserData(c, false)
 }
@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/version"
 )
 
@@ -33,7 +35,7 @@ const (
 
 	// FwGlobal requests the use of a single firewall group for all machines.
 	// When ports are opened for one machine, all machines will have the same
-	// port opened.
+	// port opened. An environment may later be switched to FwInstance.
 	FwGlobal = "global"
 
 	// FwNone requests that no firewalling should be performed inside
@@ -65,6 +67,23 @@ const (
 	// refresh addresses from the provider each time.
 	DefaultBootstrapSSHAddressesDelay int = 10
 
+	// DefaultHookRetryDelay is how long the unit agent waits before
+	// first retrying a failed hook when hook-retry-delay is not set.
+	DefaultHookRetryDelay = 30 * time.Second
+
+	// DefaultMaxRelationSettingsSize is the largest size, in bytes,
+	// of a unit's settings in a relation when
+	// max-relation-settings-size is not set.
+	DefaultMaxRelationSettingsSize = 512 * 1024
+
+	// CharmRevisionTrackLatest is the charm-revision-policy under
+	// which status reports newer charm revisions for every service.
+	CharmRevisionTrackLatest = "track-latest"
+
+	// CharmRevisionLocked is the charm-revision-policy under which
+	// every service is treated as pinned to its current charm.
+	CharmRevisionLocked = "locked"
+
 	// fallbackLtsSeries is the latest LTS series we'll use, if we fail to
 	// obtain this information from the system.
 	fallbackLtsSeries string = "trusty"
@@ -477,6 +496,19 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if host := cfg.SyslogHost(); host != "" {
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			return fmt.Errorf("invalid syslog-host %q: expected host:port", host)
+		}
+		caCert := cfg.SyslogCACert()
+		if caCert == "" {
+			return fmt.Errorf("syslog-host requires syslog-ca-cert to be set")
+		}
+		if _, err := cert.ParseCert(caCert); err != nil {
+			return errors.Annotate(err, "bad syslog-ca-cert in configuration")
+		}
+	}
+
 	// Ensure that the auth token is a set of key=value pairs.
 	authToken, _ := cfg.CharmStoreAuth()
 	validAuthToken := regexp.MustCompile(`^([^\s=]+=[^\s=]+(,\s*)?)*$`)
@@ -492,6 +524,58 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined["instance-poll-interval"].(int); ok && v <= 0 {
+		return fmt.Errorf("invalid instance-poll-interval %d: must be positive", v)
+	}
+	if v, ok := cfg.defined["hook-timeout"].(int); ok && v <= 0 {
+		return fmt.Errorf("invalid hook-timeout %d: must be positive", v)
+	}
+	if v, ok := cfg.defined["hook-retry-count"].(int); ok && v < 0 {
+		return fmt.Errorf("invalid hook-retry-count %d: must not be negative", v)
+	}
+	if v, ok := cfg.defined["hook-retry-delay"].(int); ok && v <= 0 {
+		return fmt.Errorf("invalid hook-retry-delay %d: must be positive", v)
+	}
+	if v, ok := cfg.defined["stop-hook-grace-period"].(int); ok && v <= 0 {
+		return fmt.Errorf("invalid stop-hook-grace-period %d: must be positive", v)
+	}
+	for _, attr := range []string{"mongo-oplog-size", "mongo-cache-size"} {
+		if v, ok := cfg.defined[attr].(int); ok && v < 0 {
+			return fmt.Errorf("invalid %s %d: must not be negative", attr, v)
+		}
+	}
+	for _, attr := range []string{"public-address-preference", "private-address-preference"} {
+		if v, ok := cfg.defined[attr].(string); ok {
+			if _, err := network.ParseAddressPreferences(v); err != nil {
+				return fmt.Errorf("invalid %s: %v", attr, err)
+			}
+		}
+	}
+	if v, ok := cfg.defined["charm-revision-policy"].(string); ok {
+		switch v {
+		case CharmRevisionTrackLatest, CharmRevisionLocked:
+		default:
+			return fmt.Errorf("invalid charm-revision-policy %q: must be %q or %q", v, CharmRevisionTrackLatest, CharmRevisionLocked)
+		}
+	}
+	if v, ok := cfg.defined["hook-memory-limit"].(int); ok && v <= 0 {
+		return fmt.Errorf("invalid hook-memory-limit %d: must be positive", v)
+	}
+	if v, ok := cfg.defined["hook-cpu-limit"].(int); ok && v <= 0 {
+		return fmt.Errorf("invalid hook-cpu-limit %d: must be positive", v)
+	}
+	if v, ok := cfg.defined["hook-concurrency"].(int); ok && v <= 0 {
+		return fmt.Errorf("invalid hook-concurrency %d: must be positive", v)
+	}
+	if v, ok := cfg.defined["max-relation-settings-size"].(int); ok && v <= 0 {
+		return fmt.Errorf("invalid max-relation-settings-size %d: must be positive", v)
+	}
+	for _, attr := range []string{"max-charm-upload-size", "max-tools-upload-size"} {
+		if v, ok := cfg.defined[attr].(int); ok && v <= 0 {
+			return fmt.Errorf("invalid %s %d: must be positive", attr, v)
+		}
+	}
+
 	// Check the immutable config values.  These can't change
 	if old != nil {
 		for _, attr := range immutableAttributes {
@@ -508,6 +592,15 @@ func Validate(cfg, old *Config) error {
 					newv := cfg.defined[attr]
 					return fmt.Errorf("cannot change %s from %#v to %#v", attr, oldv, newv)
 				}
+			case "firewall-mode":
+				// An environment may move from a global firewall to
+				// per-instance firewalls; the firewaller migrates the
+				// existing instances. Providers that cannot migrate
+				// instances reject the change themselves.
+				newv, oldv := cfg.defined[attr], old.defined[attr]
+				if newv != oldv && !(oldv == FwGlobal && newv == FwInstance) {
+					return fmt.Errorf("cannot change %s from %#v to %#v", attr, oldv, newv)
+				}
 			default:
 				if newv, oldv := cfg.defined[attr], old.defined[attr]; newv != oldv {
 					return fmt.Errorf("cannot change %s from %#v to %#v", attr, oldv, newv)
@@ -672,6 +765,21 @@ func (c *Config) RsyslogCACert() string {
 	return ""
 }
 
+// SyslogHost returns the host:port of an external syslog collector
+// to which agents forward their logs instead of the state servers,
+// or the empty string if logs are forwarded to the state servers.
+func (c *Config) SyslogHost() string {
+	s, _ := c.defined["syslog-host"].(string)
+	return s
+}
+
+// SyslogCACert returns the certificate of the CA that signed the
+// external syslog collector's certificate, in PEM format.
+func (c *Config) SyslogCACert() string {
+	s, _ := c.defined["syslog-ca-cert"].(string)
+	return s
+}
+
 // AuthorizedKeys returns the content for ssh's authorized_keys file.
 func (c *Config) AuthorizedKeys() string {
 	return c.mustString("authorized-keys")
@@ -956,6 +1064,204 @@ func (c *Config) DisableNetworkManagement() (bool, bool) {
 	return v, ok
 }
 
+// DestroyProtected reports whether the environment may only be
+// destroyed when a confirmation token is supplied.
+func (c *Config) DestroyProtected() bool {
+	v, _ := c.defined["destroy-protected"].(bool)
+	return v
+}
+
+// EnableMetricsEndpoint reports whether state servers should serve
+// their internal metrics for monitoring systems to collect.
+func (c *Config) EnableMetricsEndpoint() bool {
+	v, _ := c.defined["enable-metrics-endpoint"].(bool)
+	return v
+}
+
+// OfflineMode reports whether Juju must not contact the charm store
+// or other external services, as in air-gapped environments where such
+// lookups would only time out.
+func (c *Config) OfflineMode() bool {
+	v, _ := c.defined["offline-mode"].(bool)
+	return v
+}
+
+// RequireSignedTools reports whether tools may only be found through
+// simplestreams metadata with a valid signature, so that agents are not
+// given tools described by tampered metadata.
+func (c *Config) RequireSignedTools() bool {
+	v, _ := c.defined["require-signed-tools"].(bool)
+	return v
+}
+
+// EnableChaosTesting reports whether unit agents may be instructed to
+// inject hook failures, delays and restarts, so that charm authors can
+// exercise their charms' error handling.
+func (c *Config) EnableChaosTesting() bool {
+	v, _ := c.defined["enable-chaos-testing"].(bool)
+	return v
+}
+
+// MongoOplogSize returns the size, in MB, of the oplog of the state
+// servers' mongo databases. If it is zero, the size is chosen according
+// to the free disk space, as mongo itself would.
+func (c *Config) MongoOplogSize() int {
+	v, _ := c.defined["mongo-oplog-size"].(int)
+	return v
+}
+
+// MongoJournal reports whether the state servers' mongo databases keep
+// a write-ahead journal. It defaults to true.
+func (c *Config) MongoJournal() bool {
+	if v, ok := c.defined["mongo-journal"].(bool); ok {
+		return v
+	}
+	return true
+}
+
+// MongoPrealloc reports whether the state servers' mongo databases
+// preallocate their data files. It defaults to false, which keeps
+// small environments small.
+func (c *Config) MongoPrealloc() bool {
+	v, _ := c.defined["mongo-prealloc"].(bool)
+	return v
+}
+
+// MongoCacheSize returns the size, in GB, of the WiredTiger cache of
+// the state servers' mongo databases. If it is zero, mongo's default
+// is used.
+func (c *Config) MongoCacheSize() int {
+	v, _ := c.defined["mongo-cache-size"].(int)
+	return v
+}
+
+// InstancePollInterval returns how often the instance poller should
+// refresh the addresses and status of started machines from the
+// provider, and whether the interval has been set.
+func (c *Config) InstancePollInterval() (time.Duration, bool) {
+	if v, ok := c.defined["instance-poll-interval"].(int); ok && v > 0 {
+		return time.Duration(v) * time.Second, true
+	}
+	return 0, false
+}
+
+// HookTimeout returns how long a charm hook may run before the unit
+// agent kills it and treats it as failed. Hooks may run indefinitely
+// if the timeout is zero.
+func (c *Config) HookTimeout() time.Duration {
+	if v, ok := c.defined["hook-timeout"].(int); ok {
+		return time.Duration(v) * time.Second
+	}
+	return 0
+}
+
+// HookRetryCount returns how many times the unit agent retries a failed
+// hook before waiting for juju resolved.
+func (c *Config) HookRetryCount() int {
+	v, _ := c.defined["hook-retry-count"].(int)
+	return v
+}
+
+// HookRetryDelay returns how long the unit agent waits before first
+// retrying a failed hook. The delay doubles with each further retry.
+func (c *Config) HookRetryDelay() time.Duration {
+	if v, ok := c.defined["hook-retry-delay"].(int); ok {
+		return time.Duration(v) * time.Second
+	}
+	return DefaultHookRetryDelay
+}
+
+// StopHookGracePeriod returns how long the unit agent lets a unit's
+// stop hook run before killing it. Unlike other hooks, a stop hook
+// that is killed after its grace period is not treated as failed, so
+// that a stuck hook cannot keep the unit from being removed. If the
+// grace period is zero, a stop hook is subject to hook-timeout and
+// fails if it overruns it, like any other hook.
+func (c *Config) StopHookGracePeriod() time.Duration {
+	v, _ := c.defined["stop-hook-grace-period"].(int)
+	return time.Duration(v) * time.Second
+}
+
+// PublicAddressPreferences returns the preferences that choose which
+// of a machine's addresses is published as its units' public address.
+func (c *Config) PublicAddressPreferences() network.AddressPreferences {
+	return c.addressPreferences("public-address-preference")
+}
+
+// PrivateAddressPreferences returns the preferences that choose which
+// of a machine's addresses is published as its units' private address.
+func (c *Config) PrivateAddressPreferences() network.AddressPreferences {
+	return c.addressPreferences("private-address-preference")
+}
+
+func (c *Config) addressPreferences(attr string) network.AddressPreferences {
+	v, _ := c.defined[attr].(string)
+	// The value was checked by Validate.
+	prefs, _ := network.ParseAddressPreferences(v)
+	return prefs
+}
+
+// CharmRevisionPolicy returns the policy that decides whether newer
+// charm revisions are reported for the environment's services.
+func (c *Config) CharmRevisionPolicy() string {
+	if v, ok := c.defined["charm-revision-policy"].(string); ok {
+		return v
+	}
+	return CharmRevisionTrackLatest
+}
+
+// HookMemoryLimit returns the most memory, in megabytes, that a charm
+// hook and the processes it starts may use. Hook memory is not limited
+// if the limit is zero.
+func (c *Config) HookMemoryLimit() uint64 {
+	v, _ := c.defined["hook-memory-limit"].(int)
+	return uint64(v)
+}
+
+// HookCPULimit returns how much CPU time a charm hook and the processes
+// it starts may use before they are killed. Hook CPU time is not
+// limited if the limit is zero.
+func (c *Config) HookCPULimit() time.Duration {
+	v, _ := c.defined["hook-cpu-limit"].(int)
+	return time.Duration(v) * time.Second
+}
+
+// HookConcurrency returns how many of the units on a machine may run
+// hooks at the same time. Hooks that install packages never run
+// alongside each other, whatever the concurrency.
+func (c *Config) HookConcurrency() int {
+	if v, ok := c.defined["hook-concurrency"].(int); ok {
+		return v
+	}
+	return 1
+}
+
+// MaxRelationSettingsSize returns the largest size, in bytes, that a
+// unit's settings in a relation may grow to. The size counts the
+// length of every key and value.
+func (c *Config) MaxRelationSettingsSize() int {
+	if v, ok := c.defined["max-relation-settings-size"].(int); ok {
+		return v
+	}
+	return DefaultMaxRelationSettingsSize
+}
+
+// MaxCharmUploadSize returns the largest charm archive, in bytes, that
+// the API server accepts. If it is zero, the API server's default
+// applies.
+func (c *Config) MaxCharmUploadSize() int64 {
+	v, _ := c.defined["max-charm-upload-size"].(int)
+	return int64(v) * 1024 * 1024
+}
+
+// MaxToolsUploadSize returns the largest tools tarball, in bytes, that
+// the API server accepts. If it is zero, the API server's default
+// applies.
+func (c *Config) MaxToolsUploadSize() int64 {
+	v, _ := c.defined["max-tools-upload-size"].(int)
+	return int64(v) * 1024 * 1024
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1019,6 +1325,8 @@ var fields = schema.Fields{
 	"api-port":                   schema.ForceInt(),
 	"syslog-port":                schema.ForceInt(),
 	"rsyslog-ca-cert":            schema.String(),
+	"syslog-host":                schema.String(),
+	"syslog-ca-cert":             schema.String(),
 	"logging-config":             schema.String(),
 	"charm-store-auth":           schema.String(),
 	ProvisionerHarvestModeKey:    schema.String(),
@@ -1041,6 +1349,29 @@ var fields = schema.Fields{
 	"enable-os-refresh-update":   schema.Bool(),
 	"enable-os-upgrade":          schema.Bool(),
 	"disable-network-management": schema.Bool(),
+	"destroy-protected":          schema.Bool(),
+	"enable-metrics-endpoint":    schema.Bool(),
+	"enable-chaos-testing":       schema.Bool(),
+	"offline-mode":               schema.Bool(),
+	"require-signed-tools":       schema.Bool(),
+	"mongo-oplog-size":           schema.ForceInt(),
+	"mongo-journal":              schema.Bool(),
+	"mongo-prealloc":             schema.Bool(),
+	"mongo-cache-size":           schema.ForceInt(),
+	"instance-poll-interval":     schema.ForceInt(),
+	"hook-timeout":               schema.ForceInt(),
+	"hook-retry-count":           schema.ForceInt(),
+	"hook-retry-delay":           schema.ForceInt(),
+	"stop-hook-grace-period":     schema.ForceInt(),
+	"hook-memory-limit":          schema.ForceInt(),
+	"hook-cpu-limit":             schema.ForceInt(),
+	"hook-concurrency":           schema.ForceInt(),
+	"max-relation-settings-size": schema.ForceInt(),
+	"max-charm-upload-size":      schema.ForceInt(),
+	"max-tools-upload-size":      schema.ForceInt(),
+	"public-address-preference":  schema.String(),
+	"private-address-preference": schema.String(),
+	"charm-revision-policy":      schema.String(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    schema.String(),
@@ -1070,6 +1401,8 @@ var alwaysOptional = schema.Defaults{
 	"bootstrap-retry-delay":      schema.Omit,
 	"bootstrap-addresses-delay":  schema.Omit,
 	"rsyslog-ca-cert":            schema.Omit,
+	"syslog-host":                schema.Omit,
+	"syslog-ca-cert":             schema.Omit,
 	"http-proxy":                 schema.Omit,
 	"https-proxy":                schema.Omit,
 	"ftp-proxy":                  schema.Omit,
@@ -1080,6 +1413,29 @@ var alwaysOptional = schema.Defaults{
 	"apt-mirror":                 schema.Omit,
 	"lxc-clone":                  schema.Omit,
 	"disable-network-management": schema.Omit,
+	"destroy-protected":          schema.Omit,
+	"enable-metrics-endpoint":    schema.Omit,
+	"enable-chaos-testing":       schema.Omit,
+	"offline-mode":               schema.Omit,
+	"require-signed-tools":       schema.Omit,
+	"mongo-oplog-size":           schema.Omit,
+	"mongo-journal":              schema.Omit,
+	"mongo-prealloc":             schema.Omit,
+	"mongo-cache-size":           schema.Omit,
+	"instance-poll-interval":     schema.Omit,
+	"hook-timeout":               schema.Omit,
+	"hook-retry-count":           schema.Omit,
+	"hook-retry-delay":           schema.Omit,
+	"stop-hook-grace-period":     schema.Omit,
+	"hook-memory-limit":          schema.Omit,
+	"hook-cpu-limit":             schema.Omit,
+	"hook-concurrency":           schema.Omit,
+	"max-relation-settings-size": schema.Omit,
+	"max-charm-upload-size":      schema.Omit,
+	"max-tools-upload-size":      schema.Omit,
+	"public-address-preference":  schema.Omit,
+	"private-address-preference": schema.Omit,
+	"charm-revision-policy":      schema.Omit,
 	AgentStreamKey:               schema.Omit,
 
 	// Deprecated fields, retain for backwards compatibility.
@@ -1143,6 +1499,7 @@ func allDefaults() schema.Defaults {
 		"proxy-ssh":                  true,
 		"prefer-ipv6":                false,
 		"disable-network-management": false,
+		"destroy-protected":          false,
 	}
 	for attr, val := range alwaysOptional {
 		if _, ok := d[attr]; !ok {
@@ -1204,7 +1561,7 @@ func (cfg *Config) ValidateUnknownAttrs(fields schema.Fields, defaults schema.De
 	checker := schema.FieldMap(fields, defaults)
 	coerced, err := checker.Coerce(attrs, nil)
 	if err != nil {
-		logger.Debugf("coercion failed attributes: %#v, checker: %#v, %v", attrs, checker, err)
+		logger.Debugf("coercion failed attributes: %v, checker: %#v, %v", AttrNames(attrs), checker, err)
 		return nil, err
 	}
 	result := coerced.(map[string]interface{})
@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 )
@@ -342,6 +343,280 @@ var configTests = []configTest{
 			"name": "my-name",
 			"disable-network-management": true,
 		},
+	}, {
+		about:       "Invalid destroy-protected flag",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"authorized-keys":   testing.FakeAuthKeys,
+			"destroy-protected": "invalid",
+		},
+		err: `destroy-protected: expected bool, got string\("invalid"\)`,
+	}, {
+		about:       "destroy-protected on",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"destroy-protected": true,
+		},
+	}, {
+		about:       "Invalid enable-metrics-endpoint flag",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"enable-metrics-endpoint": "invalid",
+		},
+		err: `enable-metrics-endpoint: expected bool, got string\("invalid"\)`,
+	}, {
+		about:       "enable-metrics-endpoint on",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"enable-metrics-endpoint": true,
+		},
+	}, {
+		about:       "Invalid enable-chaos-testing flag",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"enable-chaos-testing": "invalid",
+		},
+		err: `enable-chaos-testing: expected bool, got string\("invalid"\)`,
+	}, {
+		about:       "enable-chaos-testing on",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"enable-chaos-testing": true,
+		},
+	}, {
+		about:       "Invalid offline-mode flag",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"offline-mode": "invalid",
+		},
+		err: `offline-mode: expected bool, got string\("invalid"\)`,
+	}, {
+		about:       "offline-mode on",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"offline-mode": true,
+		},
+	}, {
+		about:       "Invalid require-signed-tools flag",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"require-signed-tools": "invalid",
+		},
+		err: `require-signed-tools: expected bool, got string\("invalid"\)`,
+	}, {
+		about:       "require-signed-tools on",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"require-signed-tools": true,
+		},
+	}, {
+		about:       "instance-poll-interval set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"instance-poll-interval": 60,
+		},
+	}, {
+		about:       "Invalid instance-poll-interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"authorized-keys":        testing.FakeAuthKeys,
+			"instance-poll-interval": 0,
+		},
+		err: `invalid instance-poll-interval 0: must be positive`,
+	}, {
+		about:       "hook policy set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"hook-timeout":     600,
+			"hook-retry-count": 3,
+			"hook-retry-delay": 10,
+		},
+	}, {
+		about:       "Invalid hook-timeout",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"hook-timeout": 0,
+		},
+		err: `invalid hook-timeout 0: must be positive`,
+	}, {
+		about:       "stop-hook-grace-period set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"stop-hook-grace-period": 60,
+		},
+	}, {
+		about:       "Invalid stop-hook-grace-period",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"stop-hook-grace-period": -1,
+		},
+		err: `invalid stop-hook-grace-period -1: must be positive`,
+	}, {
+		about:       "mongo tuning set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"mongo-oplog-size": 512,
+			"mongo-journal":    false,
+			"mongo-prealloc":   true,
+			"mongo-cache-size": 2,
+		},
+	}, {
+		about:       "Invalid mongo-oplog-size",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"mongo-oplog-size": -1,
+		},
+		err: `invalid mongo-oplog-size -1: must not be negative`,
+	}, {
+		about:       "Invalid hook-retry-count",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"hook-retry-count": -1,
+		},
+		err: `invalid hook-retry-count -1: must not be negative`,
+	}, {
+		about:       "hook resource limits set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"hook-memory-limit": 512,
+			"hook-cpu-limit":    300,
+		},
+	}, {
+		about:       "hook-concurrency set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"hook-concurrency": 4,
+		},
+	}, {
+		about:       "Invalid hook-concurrency",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"hook-concurrency": 0,
+		},
+		err: `invalid hook-concurrency 0: must be positive`,
+	}, {
+		about:       "Invalid hook-memory-limit",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"hook-memory-limit": -512,
+		},
+		err: `invalid hook-memory-limit -512: must be positive`,
+	}, {
+		about:       "max-relation-settings-size set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                       "my-type",
+			"name":                       "my-name",
+			"max-relation-settings-size": 4096,
+		},
+	}, {
+		about:       "Invalid max-relation-settings-size",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                       "my-type",
+			"name":                       "my-name",
+			"max-relation-settings-size": 0,
+		},
+		err: `invalid max-relation-settings-size 0: must be positive`,
+	}, {
+		about:       "upload size limits set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"max-charm-upload-size": 100,
+			"max-tools-upload-size": 200,
+		},
+	}, {
+		about:       "Invalid max-charm-upload-size",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"max-charm-upload-size": 0,
+		},
+		err: `invalid max-charm-upload-size 0: must be positive`,
+	}, {
+		about:       "address preferences set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                       "my-type",
+			"name":                       "my-name",
+			"public-address-preference":  "public-ip,vpc=private-ip",
+			"private-address-preference": "private-ip",
+		},
+	}, {
+		about:       "Invalid public-address-preference",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                      "my-type",
+			"name":                      "my-name",
+			"public-address-preference": "elastic-ip",
+		},
+		err: `invalid public-address-preference: unknown address preference "elastic-ip"`,
+	}, {
+		about:       "charm-revision-policy set",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"charm-revision-policy": "locked",
+		},
+	}, {
+		about:       "Invalid charm-revision-policy",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"charm-revision-policy": "latest",
+		},
+		err: `invalid charm-revision-policy "latest": must be "track-latest" or "locked"`,
 	}, {
 		about:       "Invalid prefer-ipv6 flag",
 		useDefaults: config.UseDefaults,
@@ -624,6 +899,44 @@ var configTests = []configTest{
 			"syslog-port": "illegal",
 		},
 		err: `syslog-port: expected number, got string\("illegal"\)`,
+	}, {
+		about:       "External syslog host",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"syslog-host":    "logs.example.com:6514",
+			"syslog-ca-cert": caCert,
+		},
+	}, {
+		about:       "External syslog host without port",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"syslog-host":    "logs.example.com",
+			"syslog-ca-cert": caCert,
+		},
+		err: `invalid syslog-host "logs.example.com": expected host:port`,
+	}, {
+		about:       "External syslog host without CA cert",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":        "my-type",
+			"name":        "my-name",
+			"syslog-host": "logs.example.com:6514",
+		},
+		err: "syslog-host requires syslog-ca-cert to be set",
+	}, {
+		about:       "External syslog host with invalid CA cert",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"syslog-host":    "logs.example.com:6514",
+			"syslog-ca-cert": invalidCACert,
+		},
+		err: `bad syslog-ca-cert in configuration: .*`,
 	}, {
 		about:       "Explicit bootstrap timeout",
 		useDefaults: config.UseDefaults,
@@ -1044,6 +1357,10 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	if syslogPort, ok := test.attrs["syslog-port"]; ok {
 		c.Assert(cfg.SyslogPort(), gc.Equals, syslogPort)
 	}
+	if syslogHost, ok := test.attrs["syslog-host"]; ok {
+		c.Assert(cfg.SyslogHost(), gc.Equals, syslogHost)
+		c.Assert(cfg.SyslogCACert(), gc.Equals, test.attrs["syslog-ca-cert"])
+	}
 	if expected, ok := test.attrs["uuid"]; ok {
 		got, exists := cfg.UUID()
 		c.Assert(exists, gc.Equals, ok)
@@ -1148,6 +1465,129 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 		config.DefaultBootstrapSSHAddressesDelay,
 	)
 
+	if v, ok := test.attrs["enable-metrics-endpoint"].(bool); ok {
+		c.Assert(cfg.EnableMetricsEndpoint(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.EnableMetricsEndpoint(), jc.IsFalse)
+	}
+
+	if v, ok := test.attrs["enable-chaos-testing"].(bool); ok {
+		c.Assert(cfg.EnableChaosTesting(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.EnableChaosTesting(), jc.IsFalse)
+	}
+
+	if v, ok := test.attrs["offline-mode"].(bool); ok {
+		c.Assert(cfg.OfflineMode(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.OfflineMode(), jc.IsFalse)
+	}
+
+	if v, ok := test.attrs["require-signed-tools"].(bool); ok {
+		c.Assert(cfg.RequireSignedTools(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.RequireSignedTools(), jc.IsFalse)
+	}
+
+	if v, ok := test.attrs["mongo-oplog-size"].(int); ok {
+		c.Assert(cfg.MongoOplogSize(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.MongoOplogSize(), gc.Equals, 0)
+	}
+	if v, ok := test.attrs["mongo-journal"].(bool); ok {
+		c.Assert(cfg.MongoJournal(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.MongoJournal(), jc.IsTrue)
+	}
+	if v, ok := test.attrs["mongo-prealloc"].(bool); ok {
+		c.Assert(cfg.MongoPrealloc(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.MongoPrealloc(), jc.IsFalse)
+	}
+	if v, ok := test.attrs["mongo-cache-size"].(int); ok {
+		c.Assert(cfg.MongoCacheSize(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.MongoCacheSize(), gc.Equals, 0)
+	}
+
+	pollInterval, pollIntervalSet := cfg.InstancePollInterval()
+	if v, ok := test.attrs["instance-poll-interval"].(int); ok {
+		c.Assert(pollInterval, gc.Equals, time.Duration(v)*time.Second)
+		c.Assert(pollIntervalSet, jc.IsTrue)
+	} else {
+		c.Assert(pollIntervalSet, jc.IsFalse)
+	}
+
+	if v, ok := test.attrs["hook-timeout"].(int); ok {
+		c.Assert(cfg.HookTimeout(), gc.Equals, time.Duration(v)*time.Second)
+	} else {
+		c.Assert(cfg.HookTimeout(), gc.Equals, time.Duration(0))
+	}
+	if v, ok := test.attrs["hook-retry-count"].(int); ok {
+		c.Assert(cfg.HookRetryCount(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.HookRetryCount(), gc.Equals, 0)
+	}
+	if v, ok := test.attrs["hook-retry-delay"].(int); ok {
+		c.Assert(cfg.HookRetryDelay(), gc.Equals, time.Duration(v)*time.Second)
+	} else {
+		c.Assert(cfg.HookRetryDelay(), gc.Equals, config.DefaultHookRetryDelay)
+	}
+	if v, ok := test.attrs["stop-hook-grace-period"].(int); ok {
+		c.Assert(cfg.StopHookGracePeriod(), gc.Equals, time.Duration(v)*time.Second)
+	} else {
+		c.Assert(cfg.StopHookGracePeriod(), gc.Equals, time.Duration(0))
+	}
+	if v, ok := test.attrs["hook-memory-limit"].(int); ok {
+		c.Assert(cfg.HookMemoryLimit(), gc.Equals, uint64(v))
+	} else {
+		c.Assert(cfg.HookMemoryLimit(), gc.Equals, uint64(0))
+	}
+	if v, ok := test.attrs["charm-revision-policy"].(string); ok {
+		c.Assert(cfg.CharmRevisionPolicy(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.CharmRevisionPolicy(), gc.Equals, config.CharmRevisionTrackLatest)
+	}
+	if v, ok := test.attrs["public-address-preference"].(string); ok {
+		expect, err := network.ParseAddressPreferences(v)
+		c.Assert(err, gc.IsNil)
+		c.Assert(cfg.PublicAddressPreferences(), gc.DeepEquals, expect)
+	} else {
+		c.Assert(cfg.PublicAddressPreferences(), gc.DeepEquals, network.AddressPreferences{})
+	}
+	if v, ok := test.attrs["private-address-preference"].(string); ok {
+		expect, err := network.ParseAddressPreferences(v)
+		c.Assert(err, gc.IsNil)
+		c.Assert(cfg.PrivateAddressPreferences(), gc.DeepEquals, expect)
+	} else {
+		c.Assert(cfg.PrivateAddressPreferences(), gc.DeepEquals, network.AddressPreferences{})
+	}
+	if v, ok := test.attrs["hook-cpu-limit"].(int); ok {
+		c.Assert(cfg.HookCPULimit(), gc.Equals, time.Duration(v)*time.Second)
+	} else {
+		c.Assert(cfg.HookCPULimit(), gc.Equals, time.Duration(0))
+	}
+	if v, ok := test.attrs["hook-concurrency"].(int); ok {
+		c.Assert(cfg.HookConcurrency(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.HookConcurrency(), gc.Equals, 1)
+	}
+	if v, ok := test.attrs["max-relation-settings-size"].(int); ok {
+		c.Assert(cfg.MaxRelationSettingsSize(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.MaxRelationSettingsSize(), gc.Equals, config.DefaultMaxRelationSettingsSize)
+	}
+	if v, ok := test.attrs["max-charm-upload-size"].(int); ok {
+		c.Assert(cfg.MaxCharmUploadSize(), gc.Equals, int64(v)*1024*1024)
+	} else {
+		c.Assert(cfg.MaxCharmUploadSize(), gc.Equals, int64(0))
+	}
+	if v, ok := test.attrs["max-tools-upload-size"].(int); ok {
+		c.Assert(cfg.MaxToolsUploadSize(), gc.Equals, int64(v)*1024*1024)
+	} else {
+		c.Assert(cfg.MaxToolsUploadSize(), gc.Equals, int64(0))
+	}
+
 	if v, ok := test.attrs["image-stream"]; ok {
 		c.Assert(cfg.ImageStream(), gc.Equals, v)
 	} else {
@@ -1312,10 +1752,14 @@ var validationTests = []validationTest{{
 	old:   testing.Attrs{"agent-version": "1.9.27"},
 	err:   `cannot clear agent-version`,
 }, {
-	about: "Can't change the firewall-mode (global->instance)",
+	about: "Can change the firewall-mode (global->instance)",
 	old:   testing.Attrs{"firewall-mode": config.FwGlobal},
 	new:   testing.Attrs{"firewall-mode": config.FwInstance},
-	err:   `cannot change firewall-mode from "global" to "instance"`,
+}, {
+	about: "Can't change the firewall-mode (instance->global)",
+	old:   testing.Attrs{"firewall-mode": config.FwInstance},
+	new:   testing.Attrs{"firewall-mode": config.FwGlobal},
+	err:   `cannot change firewall-mode from "instance" to "global"`,
 }, {
 	about: "Can't change the firewall-mode (global->none)",
 	old:   testing.Attrs{"firewall-mode": config.FwGlobal},
@@ -1567,6 +2011,63 @@ func (s *ConfigSuite) TestAptProxyConfigMap(c *gc.C) {
 	c.Assert(cfg.AptProxySettings(), gc.DeepEquals, proxySettings)
 }
 
+func (s *ConfigSuite) TestSchema(c *gc.C) {
+	attrs := config.Schema()
+	c.Assert(attrs["default-series"], gc.Equals, config.Attr{Type: config.StringType, Default: ""})
+	c.Assert(attrs["api-port"], gc.Equals, config.Attr{Type: config.IntType, Default: config.DefaultAPIPort, Immutable: true})
+	c.Assert(attrs["development"], gc.Equals, config.Attr{Type: config.BoolType, Default: false})
+	c.Assert(attrs["uuid"], gc.Equals, config.Attr{Type: config.StringType})
+	c.Assert(attrs["admin-secret"], gc.Equals, config.Attr{Type: config.StringType, Default: "", Secret: true})
+	c.Assert(attrs["hook-timeout"], gc.Equals, config.Attr{Type: config.IntType})
+}
+
+func (s *ConfigSuite) TestFieldsSchema(c *gc.C) {
+	attrs := config.FieldsSchema(schema.Fields{
+		"name":  schema.String(),
+		"count": schema.ForceInt(),
+		"flag":  schema.Bool(),
+	}, schema.Defaults{
+		"count": 3,
+		"flag":  schema.Omit,
+	})
+	c.Assert(attrs, gc.DeepEquals, map[string]config.Attr{
+		"name":  {Type: config.StringType},
+		"count": {Type: config.IntType, Default: 3},
+		"flag":  {Type: config.BoolType},
+	})
+}
+
+func (s *ConfigSuite) TestRedactSecrets(c *gc.C) {
+	attrs := map[string]interface{}{
+		"name":         "my-name",
+		"admin-secret": "top-secret",
+		"api-key":      "not-in-schema",
+		"empty":        "",
+	}
+	schema := map[string]config.Attr{
+		"admin-secret": {Type: config.StringType, Secret: true},
+		"empty":        {Type: config.StringType, Secret: true},
+	}
+	redacted := config.RedactSecrets(attrs, schema)
+	c.Assert(redacted, gc.DeepEquals, map[string]interface{}{
+		"name":         "my-name",
+		"admin-secret": "REDACTED",
+		"api-key":      "not-in-schema",
+		"empty":        "",
+	})
+	// The original is untouched.
+	c.Assert(attrs["admin-secret"], gc.Equals, "top-secret")
+}
+
+func (s *ConfigSuite) TestAttrNames(c *gc.C) {
+	names := config.AttrNames(map[string]interface{}{
+		"name":         "my-name",
+		"admin-secret": "top-secret",
+		"state-port":   1234,
+	})
+	c.Assert(names, gc.DeepEquals, []string{"admin-secret", "name", "state-port"})
+}
+
 func (s *ConfigSuite) TestGenerateStateServerCertAndKey(c *gc.C) {
 	// Add a cert.
 	s.FakeHomeSuite.Home.AddFiles(c, gitjujutesting.TestFile{".ssh/id_rsa.pub", "rsa\n"})
@@ -0,0 +1,119 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"sort"
+
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/utils/anonymize"
+)
+
+// Attribute types reported by Schema.
+const (
+	StringType = "string"
+	IntType    = "int"
+	BoolType   = "bool"
+)
+
+// Attr describes an environment configuration attribute.
+type Attr struct {
+	// Type holds the type of the attribute's value; one of
+	// StringType, IntType or BoolType.
+	Type string
+
+	// Default holds the value the attribute takes when it is not
+	// specified, or nil if it has none.
+	Default interface{}
+
+	// Immutable reports whether the attribute must not change
+	// during the lifetime of an environment.
+	Immutable bool
+
+	// Secret reports whether the attribute holds credentials or
+	// other sensitive data that must not be shown or logged.
+	Secret bool
+}
+
+// secretAttributes holds the names of the attributes common to all
+// providers that hold sensitive data.
+var secretAttributes = []string{
+	"admin-secret",
+	"ca-private-key",
+}
+
+// Schema returns a description of every environment configuration
+// attribute that is common to all providers.
+func Schema() map[string]Attr {
+	attrs := FieldsSchema(fields, defaults, secretAttributes...)
+	for _, name := range immutableAttributes {
+		attr := attrs[name]
+		attr.Immutable = true
+		attrs[name] = attr
+	}
+	return attrs
+}
+
+// FieldsSchema returns a description of the attributes checked by the
+// given fields, taking their defaults from the given defaults and
+// marking the named secrets. It is used by providers to describe their
+// own attributes.
+func FieldsSchema(fields schema.Fields, defaults schema.Defaults, secrets ...string) map[string]Attr {
+	attrs := make(map[string]Attr, len(fields))
+	for name, checker := range fields {
+		attr := Attr{Type: checkerType(checker)}
+		if value, ok := defaults[name]; ok && value != schema.Omit {
+			attr.Default = value
+		}
+		attrs[name] = attr
+	}
+	for _, name := range secrets {
+		if attr, ok := attrs[name]; ok {
+			attr.Secret = true
+			attrs[name] = attr
+		}
+	}
+	return attrs
+}
+
+// RedactSecrets returns a copy of attrs in which the non-empty values
+// of the attributes the schema marks as secret are replaced with
+// anonymize.Redacted.
+func RedactSecrets(attrs map[string]interface{}, schema map[string]Attr) map[string]interface{} {
+	result := make(map[string]interface{}, len(attrs))
+	for name, value := range attrs {
+		if schema[name].Secret && value != "" && value != nil {
+			value = anonymize.Redacted
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// AttrNames returns the sorted names of the given attributes. It is
+// used to log which attributes a configuration holds without logging
+// their values, which may include secrets that are not known here,
+// such as a provider's credentials.
+func AttrNames(attrs map[string]interface{}) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkerType returns the type of value accepted by the given
+// checker. The schema package does not expose its checkers' types, so
+// they are discovered by trying representative values.
+func checkerType(checker schema.Checker) string {
+	if _, err := checker.Coerce(true, nil); err == nil {
+		return BoolType
+	}
+	if _, err := checker.Coerce(1, nil); err == nil {
+		return IntType
+	}
+	return StringType
+}
@@ -46,6 +46,21 @@ type EnvironInfoData struct {
 	Config       map[string]interface{} `json:"bootstrap-config,omitempty" yaml:"bootstrap-config,omitempty"`
 }
 
+// EndpointCacheData is the serialisation structure for the API endpoint
+// cache file, which records the last known API endpoints of all the
+// environments in the store so that they can be listed without reading
+// each environment's JENV file.
+type EndpointCacheData struct {
+	Environments map[string]CachedEndpointData `json:"environments" yaml:"environments"`
+}
+
+// CachedEndpointData holds the cached API endpoint of an environment.
+type CachedEndpointData struct {
+	EnvironUUID  string   `json:"environ-uuid,omitempty" yaml:"environ-uuid,omitempty"`
+	StateServers []string `json:"state-servers" yaml:"state-servers"`
+	CACert       string   `json:"ca-cert" yaml:"ca-cert"`
+}
+
 type environInfo struct {
 	mu sync.Mutex
 
@@ -126,6 +141,33 @@ func (d *diskStore) List() ([]string, error) {
 	return envs, nil
 }
 
+// APIEndpoints implements Storage.APIEndpoints.
+func (d *diskStore) APIEndpoints() (map[string]APIEndpoint, error) {
+	lock, err := fslock.NewLock(d.dir, lockName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	err = lock.LockWithTimeout(lockTimeout, "reading endpoints")
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read endpoints")
+	}
+	defer lock.Unlock()
+
+	cache, err := d.readEndpointCache()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	endpoints := make(map[string]APIEndpoint)
+	for name, endpoint := range cache.Environments {
+		endpoints[name] = APIEndpoint{
+			Addresses:   endpoint.StateServers,
+			CACert:      endpoint.CACert,
+			EnvironUUID: endpoint.EnvironUUID,
+		}
+	}
+	return endpoints, nil
+}
+
 // ReadInfo implements Storage.ReadInfo.
 func (d *diskStore) ReadInfo(envName string) (EnvironInfo, error) {
 	// TODO: first try the new format, and if it doesn't exist, read the old format.
@@ -246,6 +288,18 @@ func (info *environInfo) Write() error {
 	if err := info.writeJENVFile(); err != nil {
 		return errors.Trace(err)
 	}
+	d := &diskStore{dir: info.environmentDir}
+	err = d.updateEndpointCache(func(cache *EndpointCacheData) {
+		cache.Environments[info.name] = CachedEndpointData{
+			EnvironUUID:  info.environmentUUID,
+			StateServers: info.apiEndpoints,
+			CACert:       info.caCert,
+		}
+	})
+	if err != nil {
+		// The JENV file remains the source of truth.
+		logger.Warningf("cannot update API endpoint cache: %v", err)
+	}
 
 	info.initialized = true
 	return nil
@@ -259,12 +313,93 @@ func (info *environInfo) Destroy() error {
 		err := os.Remove(info.path)
 		if os.IsNotExist(err) {
 			return errors.New("environment info has already been removed")
+		} else if err != nil {
+			return err
+		}
+		if err := info.removeCachedEndpoint(); err != nil {
+			logger.Warningf("cannot update API endpoint cache: %v", err)
 		}
-		return err
 	}
 	return nil
 }
 
+// removeCachedEndpoint removes the environment from the API endpoint
+// cache.
+func (info *environInfo) removeCachedEndpoint() error {
+	lock, err := fslock.NewLock(info.environmentDir, lockName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = lock.LockWithTimeout(lockTimeout, "writing endpoints")
+	if err != nil {
+		return errors.Annotatef(err, "cannot write endpoints")
+	}
+	defer lock.Unlock()
+
+	d := &diskStore{dir: info.environmentDir}
+	return d.updateEndpointCache(func(cache *EndpointCacheData) {
+		delete(cache.Environments, info.name)
+	})
+}
+
+const endpointCacheFilename = "api-endpoints.yaml"
+
+// readEndpointCache reads the API endpoint cache file. If there is no
+// cache file, the cache is built from the store's JENV files. The
+// caller must hold the store's lock.
+func (d *diskStore) readEndpointCache() (*EndpointCacheData, error) {
+	cache := &EndpointCacheData{
+		Environments: make(map[string]CachedEndpointData),
+	}
+	path := filepath.Join(d.dir, endpointCacheFilename)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		names, err := d.List()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, name := range names {
+			info, err := d.readJENVFile(name)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			cache.Environments[name] = CachedEndpointData{
+				EnvironUUID:  info.environmentUUID,
+				StateServers: info.apiEndpoints,
+				CACert:       info.caCert,
+			}
+		}
+		return cache, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := goyaml.Unmarshal(data, cache); err != nil {
+		return nil, errors.Annotatef(err, "error unmarshalling %q", path)
+	}
+	if cache.Environments == nil {
+		cache.Environments = make(map[string]CachedEndpointData)
+	}
+	return cache, nil
+}
+
+// updateEndpointCache reads the API endpoint cache file, applies the
+// given update to it and writes it back. The caller must hold the
+// store's lock.
+func (d *diskStore) updateEndpointCache(update func(*EndpointCacheData)) error {
+	cache, err := d.readEndpointCache()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	update(cache)
+	data, err := goyaml.Marshal(cache)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal API endpoint cache")
+	}
+	path := filepath.Join(d.dir, endpointCacheFilename)
+	logger.Debugf("writing API endpoint cache to %s", path)
+	return errors.Annotate(ioutil.WriteFile(path, data, 0600), "cannot write API endpoint cache")
+}
+
 const jenvExtension = ".jenv"
 
 func jenvFilename(basedir, envName string) string {
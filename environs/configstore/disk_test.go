@@ -53,7 +53,7 @@ func (s *diskInterfaceSuite) TearDownTest(c *gc.C) {
 	entries, err := ioutil.ReadDir(storePath(s.dir, ""))
 	c.Assert(err, gc.IsNil)
 	for _, entry := range entries {
-		if !strings.HasSuffix(entry.Name(), ".jenv") {
+		if !strings.HasSuffix(entry.Name(), ".jenv") && entry.Name() != "api-endpoints.yaml" {
 			c.Errorf("found possible stray temp file %q", entry.Name())
 		}
 	}
@@ -117,6 +117,51 @@ func (*diskStoreSuite) TestRead(c *gc.C) {
 	})
 }
 
+func (*diskStoreSuite) TestAPIEndpointsWithoutCache(c *gc.C) {
+	dir := c.MkDir()
+	err := os.Mkdir(storePath(dir, ""), 0700)
+	c.Assert(err, gc.IsNil)
+	err = ioutil.WriteFile(storePath(dir, "someenv"), []byte(sampleInfo), 0666)
+	c.Assert(err, gc.IsNil)
+	store, err := configstore.NewDisk(dir)
+	c.Assert(err, gc.IsNil)
+
+	// With no cache file, the endpoints are read from the JENV files.
+	endpoints, err := store.APIEndpoints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(endpoints, gc.DeepEquals, map[string]configstore.APIEndpoint{
+		"someenv": {
+			Addresses: []string{"example.com", "kremvax.ru"},
+			CACert:    "first line\nsecond line",
+		},
+	})
+}
+
+func (*diskStoreSuite) TestWriteUpdatesEndpointCache(c *gc.C) {
+	dir := c.MkDir()
+	store, err := configstore.NewDisk(dir)
+	c.Assert(err, gc.IsNil)
+	info := store.CreateInfo("someenv")
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"example.com:17070"},
+		CACert:      "a cert",
+		EnvironUUID: "90168e4c-2f10-4e9c-83c2-feedfacee5a9",
+	})
+	err = info.Write()
+	c.Assert(err, gc.IsNil)
+
+	data, err := ioutil.ReadFile(filepath.Join(storePath(dir, ""), "api-endpoints.yaml"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, `
+environments:
+  someenv:
+    environ-uuid: 90168e4c-2f10-4e9c-83c2-feedfacee5a9
+    state-servers:
+    - example.com:17070
+    ca-cert: a cert
+`[1:])
+}
+
 func (*diskStoreSuite) TestReadNotFound(c *gc.C) {
 	dir := c.MkDir()
 	store, err := configstore.NewDisk(dir)
@@ -51,6 +51,11 @@ type Storage interface {
 	// List returns a slice of existing environment names that the Storage
 	// knows about.
 	List() ([]string, error)
+
+	// APIEndpoints returns the cached API endpoint information of all
+	// the environments that the Storage knows about, keyed by
+	// environment name.
+	APIEndpoints() (map[string]APIEndpoint, error)
 }
 
 // EnvironInfo holds information associated with an environment.
@@ -62,6 +62,50 @@ func (s *interfaceSuite) TestList(c *gc.C) {
 	c.Assert(environs, jc.SameContents, []string{"enva", "envb", "envc"})
 }
 
+func (s *interfaceSuite) TestAPIEndpoints(c *gc.C) {
+	store := s.NewStore(c)
+	endpoints, err := store.APIEndpoints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(endpoints, gc.HasLen, 0)
+
+	s.createInitialisedEnvironment(c, store, "enva")
+	info := store.CreateInfo("envb")
+	endpoint := configstore.APIEndpoint{
+		Addresses:   []string{"example.com"},
+		CACert:      "a cert",
+		EnvironUUID: "dead-beef",
+	}
+	info.SetAPIEndpoint(endpoint)
+	err = info.Write()
+	c.Assert(err, gc.IsNil)
+
+	endpoints, err = store.APIEndpoints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(endpoints, jc.DeepEquals, map[string]configstore.APIEndpoint{
+		"enva": {},
+		"envb": endpoint,
+	})
+
+	// Updating an environment's endpoint updates the cached endpoints.
+	info, err = store.ReadInfo("enva")
+	c.Assert(err, gc.IsNil)
+	info.SetAPIEndpoint(endpoint)
+	err = info.Write()
+	c.Assert(err, gc.IsNil)
+	endpoints, err = store.APIEndpoints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(endpoints["enva"], jc.DeepEquals, endpoint)
+
+	// Destroying an environment removes its cached endpoint.
+	err = info.Destroy()
+	c.Assert(err, gc.IsNil)
+	endpoints, err = store.APIEndpoints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(endpoints, jc.DeepEquals, map[string]configstore.APIEndpoint{
+		"envb": endpoint,
+	})
+}
+
 func (s *interfaceSuite) TestSetAPIEndpointAndCredentials(c *gc.C) {
 	store := s.NewStore(c)
 
@@ -67,6 +67,17 @@ func (m *memStore) List() ([]string, error) {
 	return envs, nil
 }
 
+// APIEndpoints implements Storage.APIEndpoints.
+func (m *memStore) APIEndpoints() (map[string]APIEndpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	endpoints := make(map[string]APIEndpoint)
+	for name, info := range m.envs {
+		endpoints[name] = info.APIEndpoint()
+	}
+	return endpoints, nil
+}
+
 // ReadInfo implements Storage.ReadInfo.
 func (m *memStore) ReadInfo(envName string) (EnvironInfo, error) {
 	m.mu.Lock()
@@ -53,6 +53,14 @@ type EnvironProvider interface {
 	SecretAttrs(cfg *config.Config) (map[string]string, error)
 }
 
+// ConfigSchemaSource is implemented by providers that can describe the
+// provider-specific attributes of their environment configuration.
+type ConfigSchemaSource interface {
+	// ConfigSchema returns the provider-specific configuration
+	// attributes, keyed by name.
+	ConfigSchema() map[string]config.Attr
+}
+
 // EnvironStorage implements storage access for an environment.
 type EnvironStorage interface {
 	// Storage returns storage specific to the environment.
@@ -186,6 +194,27 @@ type Environ interface {
 	state.Prechecker
 }
 
+// InstanceFirewallMigrator is implemented by environs whose instances,
+// started under the FwGlobal firewall mode, can be moved onto their own
+// firewalls when the environment switches to the FwInstance mode.
+type InstanceFirewallMigrator interface {
+	// EnsureInstanceFirewall ensures that the instance with the given
+	// id, hosting the machine with the given id, has a firewall of its
+	// own, as if it had been started under the FwInstance mode. It does
+	// not change the ports opened for the instance.
+	EnsureInstanceFirewall(machineId string, id instance.Id) error
+
+	// RemoveGlobalFirewall removes the instances with the given ids
+	// from the firewall shared under the FwGlobal mode, and removes
+	// that firewall.
+	RemoveGlobalFirewall(ids []instance.Id) error
+
+	// HasGlobalFirewall reports whether the firewall shared under the
+	// FwGlobal mode still exists, so that a migration that was
+	// interrupted can be finished.
+	HasGlobalFirewall() (bool, error)
+}
+
 // BootstrapContext is an interface that is passed to
 // Environ.Bootstrap, providing a means of obtaining
 // information about and manipulating the context in which
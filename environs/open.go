@@ -65,7 +65,7 @@ func ConfigForName(name string, store configstore.Storage) (*config.Config, Conf
 		if len(info.BootstrapConfig()) == 0 {
 			return nil, ConfigFromNowhere, EmptyConfig{fmt.Errorf("environment has no bootstrap configuration data")}
 		}
-		logger.Debugf("ConfigForName found bootstrap config %#v", info.BootstrapConfig())
+		logger.Debugf("ConfigForName found bootstrap config with attributes %v", config.AttrNames(info.BootstrapConfig()))
 		cfg, err := config.New(config.NoDefaults, info.BootstrapConfig())
 		return cfg, ConfigFromInfo, err
 	} else if !errors.IsNotFound(err) {
@@ -54,6 +54,11 @@ type SyncContext struct {
 	// Source, if non-empty, specifies a directory in the local file system
 	// to use as a source.
 	Source string
+
+	// OnlySigned controls whether the source tools may only be found
+	// through signed metadata. Tools without a SHA-256 hash to verify
+	// their contents against are then not copied.
+	OnlySigned bool
 }
 
 // ToolsFinder provides an interface for finding tools of a specified version.
@@ -96,14 +101,16 @@ func SyncTools(syncContext *SyncContext) error {
 	}
 	sourceTools, err := envtools.FindToolsForCloud(
 		[]simplestreams.DataSource{sourceDataSource}, simplestreams.CloudSpec{},
-		syncContext.Stream, syncContext.MajorVersion, syncContext.MinorVersion, coretools.Filter{})
+		syncContext.Stream, syncContext.MajorVersion, syncContext.MinorVersion, coretools.Filter{},
+		syncContext.OnlySigned)
 	// For backwards compatibility with cloud storage, if there are no tools in the specified stream,
 	// double check the release stream.
 	// TODO - remove this when we no longer need to support cloud storage upgrades.
 	if err == envtools.ErrNoTools {
 		sourceTools, err = envtools.FindToolsForCloud(
 			[]simplestreams.DataSource{sourceDataSource}, simplestreams.CloudSpec{},
-			envtools.ReleasedStream, syncContext.MajorVersion, syncContext.MinorVersion, coretools.Filter{})
+			envtools.ReleasedStream, syncContext.MajorVersion, syncContext.MinorVersion, coretools.Filter{},
+			syncContext.OnlySigned)
 	}
 	if err != nil {
 		return err
@@ -139,7 +146,7 @@ func SyncTools(syncContext *SyncContext) error {
 		return nil
 	}
 
-	err = copyTools(syncContext.Stream, missing, syncContext.TargetToolsUploader)
+	err = copyTools(syncContext.Stream, missing, syncContext.TargetToolsUploader, syncContext.OnlySigned)
 	if err != nil {
 		return err
 	}
@@ -162,10 +169,11 @@ func selectSourceDatasource(syncContext *SyncContext) (simplestreams.DataSource,
 }
 
 // copyTools copies a set of tools from the source to the target.
-func copyTools(stream string, tools []*coretools.Tools, u ToolsUploader) error {
+// If onlySigned is true, tools without a SHA-256 hash are not copied.
+func copyTools(stream string, tools []*coretools.Tools, u ToolsUploader, onlySigned bool) error {
 	for _, tool := range tools {
 		logger.Infof("copying %s from %s", tool.Version, tool.URL)
-		if err := copyOneToolsPackage(stream, tool, u); err != nil {
+		if err := copyOneToolsPackage(stream, tool, u, onlySigned); err != nil {
 			return err
 		}
 	}
@@ -173,8 +181,11 @@ func copyTools(stream string, tools []*coretools.Tools, u ToolsUploader) error {
 }
 
 // copyOneToolsPackage copies one tool from the source to the target.
-func copyOneToolsPackage(stream string, tools *coretools.Tools, u ToolsUploader) error {
+func copyOneToolsPackage(stream string, tools *coretools.Tools, u ToolsUploader, onlySigned bool) error {
 	toolsName := envtools.StorageName(tools.Version)
+	if onlySigned && tools.SHA256 == "" {
+		return errors.Errorf("no SHA-256 hash for %v in signed metadata", tools.Version)
+	}
 	logger.Infof("downloading %q %v (%v)", stream, toolsName, tools.URL)
 	resp, err := utils.GetValidatingHTTPClient().Get(tools.URL)
 	if err != nil {
@@ -173,6 +173,25 @@ func (s *syncSuite) TestSyncing(c *gc.C) {
 	}
 }
 
+func (s *syncSuite) TestSyncingOnlySignedIgnoresUnsignedTools(c *gc.C) {
+	s.setUpTest(c)
+	defer s.tearDownTest(c)
+
+	uploader := fakeToolsUploader{
+		uploaded: make(map[version.Binary]bool),
+	}
+	ctx := &sync.SyncContext{
+		Source:              s.localStorage,
+		OnlySigned:          true,
+		TargetToolsFinder:   mockToolsFinder{},
+		TargetToolsUploader: &uploader,
+	}
+	// The test tools are described by unsigned metadata only.
+	err := sync.SyncTools(ctx)
+	c.Assert(err, gc.Equals, envtools.ErrNoTools)
+	c.Assert(uploader.uploaded, gc.HasLen, 0)
+}
+
 type fakeToolsUploader struct {
 	uploaded map[version.Binary]bool
 }
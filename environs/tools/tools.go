@@ -108,22 +108,28 @@ func FindTools(env environs.Environ, majorVersion, minorVersion int,
 	if stream == "" || env.Config().Development() {
 		stream = TestingStream
 	}
-	return FindToolsForCloud(sources, cloudSpec, stream, majorVersion, minorVersion, filter)
+	onlySigned := env.Config().RequireSignedTools()
+	if onlySigned {
+		logger.Infof("only using signed tools metadata")
+	}
+	return FindToolsForCloud(sources, cloudSpec, stream, majorVersion, minorVersion, filter, onlySigned)
 }
 
 // FindToolsForCloud returns a List containing all tools in the given stream, with a given
 // major.minor version number and cloudSpec, filtered by filter.
 // If minorVersion = -1, then only majorVersion is considered.
+// If onlySigned is true, tools are only found through metadata whose signature
+// is verified against the tools public key.
 // If no *available* tools have the supplied major.minor version number, or match the
 // supplied filter, the function returns a *NotFoundError.
 func FindToolsForCloud(sources []simplestreams.DataSource, cloudSpec simplestreams.CloudSpec, stream string,
-	majorVersion, minorVersion int, filter coretools.Filter) (list coretools.List, err error) {
+	majorVersion, minorVersion int, filter coretools.Filter, onlySigned bool) (list coretools.List, err error) {
 
 	toolsConstraint, err := makeToolsConstraint(cloudSpec, stream, majorVersion, minorVersion, filter)
 	if err != nil {
 		return nil, err
 	}
-	toolsMetadata, _, err := Fetch(sources, toolsConstraint, false)
+	toolsMetadata, _, err := Fetch(sources, toolsConstraint, onlySigned)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			err = ErrNoTools
@@ -179,6 +179,15 @@ func (s *SimpleStreamsToolsSuite) TestFindTools(c *gc.C) {
 	}
 }
 
+func (s *SimpleStreamsToolsSuite) TestFindToolsRequireSigned(c *gc.C) {
+	s.reset(c, map[string]interface{}{"require-signed-tools": true})
+	// The uploaded tools are described by unsigned metadata only.
+	s.uploadCustom(c, envtesting.V110p...)
+	s.uploadPublic(c, envtesting.VAll...)
+	_, err := envtools.FindTools(s.env, 1, -1, coretools.Filter{})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *SimpleStreamsToolsSuite) TestFindToolsFiltering(c *gc.C) {
 	var tw loggo.TestWriter
 	c.Assert(loggo.RegisterWriter("filter-tester", &tw, loggo.DEBUG), gc.IsNil)
@@ -0,0 +1,19 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package feature holds the names of the development feature flags
+// that enable unfinished features. See utils/featureflag.
+package feature
+
+// JES enables hosting more than one environment in a state server:
+// the create-environment command, and API connections to environments
+// other than the state server's own. Hosted environments do not yet
+// have their own statuses, annotations, relations and watchers.
+const JES = "jes"
+
+// CrossEnvRelations enables offering services to other environments:
+// the offer, list-offers and consume commands, and the
+// CrossEnvRelations API facade. The firewaller cannot yet limit access
+// to an offered service to the consumers' addresses, and the uniter
+// does not yet run hooks for remote relations.
+const CrossEnvRelations = "cross-env-relations"
@@ -5,6 +5,7 @@ package instance
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/juju/names"
@@ -74,6 +75,29 @@ func ParsePlacement(directive string) (*Placement, error) {
 	return nil, ErrPlacementScopeMissing
 }
 
+var (
+	providerKeyValueDirective = regexp.MustCompile(`^[a-z][a-z0-9-]*=[^\s:]+$`)
+	providerHostDirective     = regexp.MustCompile(`^[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+$`)
+)
+
+// ParseUnitPlacement parses a unit placement directive, as given to the
+// --to option of "juju deploy" and "juju add-unit". In addition to the
+// directives accepted by ParsePlacement, it accepts unscoped directives
+// to be evaluated by the environment's provider when starting a new
+// machine for the unit: either a key=value pair, such as
+// "zone=us-east-1b", or a host name, such as "host.maas". These are
+// returned with an empty Scope.
+func ParseUnitPlacement(directive string) (*Placement, error) {
+	placement, err := ParsePlacement(directive)
+	if err != ErrPlacementScopeMissing {
+		return placement, err
+	}
+	if providerKeyValueDirective.MatchString(directive) || providerHostDirective.MatchString(directive) {
+		return &Placement{Directive: directive}, nil
+	}
+	return nil, fmt.Errorf("invalid placement directive %q", directive)
+}
+
 // MustParsePlacement attempts to parse the specified string and create
 // a corresponding Placement structure, panicking if an error occurs.
 func MustParsePlacement(directive string) *Placement {
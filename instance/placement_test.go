@@ -74,3 +74,60 @@ func (s *PlacementSuite) TestParsePlacement(c *gc.C) {
 		}
 	}
 }
+
+func (s *PlacementSuite) TestParseUnitPlacement(c *gc.C) {
+	parseUnitPlacementTests := []struct {
+		arg    string
+		expect *instance.Placement
+		err    string
+	}{{
+		arg: "",
+	}, {
+		arg:    "3",
+		expect: &instance.Placement{Scope: instance.MachineScope, Directive: "3"},
+	}, {
+		arg:    "3/lxc/2",
+		expect: &instance.Placement{Scope: instance.MachineScope, Directive: "3/lxc/2"},
+	}, {
+		arg:    "lxc:3",
+		expect: &instance.Placement{Scope: string(instance.LXC), Directive: "3"},
+	}, {
+		arg:    "kvm",
+		expect: &instance.Placement{Scope: string(instance.KVM)},
+	}, {
+		arg:    "zone=us-east-1b",
+		expect: &instance.Placement{Directive: "zone=us-east-1b"},
+	}, {
+		arg:    "host.maas",
+		expect: &instance.Placement{Directive: "host.maas"},
+	}, {
+		arg:    "myenv:zone=us-east-1b",
+		expect: &instance.Placement{Scope: "myenv", Directive: "zone=us-east-1b"},
+	}, {
+		arg: "bigglesplop",
+		err: `invalid placement directive "bigglesplop"`,
+	}, {
+		arg: "00",
+		err: `invalid placement directive "00"`,
+	}, {
+		arg: ":zone=us-east-1b",
+		err: `invalid placement directive ":zone=us-east-1b"`,
+	}, {
+		arg: "zone=",
+		err: `invalid placement directive "zone="`,
+	}, {
+		arg: "lxc:",
+		err: `invalid value "" for "lxc" scope: expected machine-id`,
+	}}
+
+	for i, t := range parseUnitPlacementTests {
+		c.Logf("test %d: %s", i, t.arg)
+		p, err := instance.ParseUnitPlacement(t.arg)
+		if t.err != "" {
+			c.Assert(err, gc.ErrorMatches, t.err)
+			continue
+		}
+		c.Assert(err, gc.IsNil)
+		c.Assert(p, gc.DeepEquals, t.expect)
+	}
+}
@@ -782,6 +782,10 @@ func (*storageWithWriteNotify) List() ([]string, error) {
 	panic("List not implemented")
 }
 
+func (*storageWithWriteNotify) APIEndpoints() (map[string]configstore.APIEndpoint, error) {
+	panic("APIEndpoints not implemented")
+}
+
 func (s *storageWithWriteNotify) ReadInfo(envName string) (configstore.EnvironInfo, error) {
 	info, err := s.store.ReadInfo(envName)
 	if err != nil {
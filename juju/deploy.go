@@ -5,10 +5,8 @@ package juju
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/juju/errors"
-	"github.com/juju/names"
 	"gopkg.in/juju/charm.v4"
 
 	"github.com/juju/juju/constraints"
@@ -26,9 +24,13 @@ type DeployServiceParams struct {
 	ConfigSettings charm.Settings
 	Constraints    constraints.Value
 	NumUnits       int
-	// ToMachineSpec is either:
+	// ToMachineSpec is a unit placement directive, as parsed by
+	// instance.ParseUnitPlacement; it is either:
 	// - an existing machine/container id eg "1" or "1/lxc/2"
 	// - a new container on an existing machine eg "lxc:1"
+	// - a new container on a new machine eg "lxc"
+	// - a provider directive for a new machine eg "zone=us-east-1b"
+	//   or "host.maas"
 	// Use string to avoid ambiguity around machine 0.
 	ToMachineSpec string
 	// Networks holds a list of networks to required to start on boot.
@@ -125,52 +127,15 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 			if n != 1 {
 				return nil, fmt.Errorf("cannot add multiple units of service %q to a single machine", svc.Name())
 			}
-			// machineIdSpec may be an existing machine or container, eg 3/lxc/2
-			// or a new container on a machine, eg lxc:3
-			mid := machineIdSpec
-			var containerType instance.ContainerType
-			specParts := strings.SplitN(machineIdSpec, ":", 2)
-			if len(specParts) > 1 {
-				firstPart := specParts[0]
-				var err error
-				if containerType, err = instance.ParseContainerType(firstPart); err == nil {
-					mid = specParts[1]
-				} else {
-					mid = machineIdSpec
-				}
-			}
-			if !names.IsValidMachine(mid) {
-				return nil, fmt.Errorf("invalid force machine id %q", mid)
-			}
-			var unitCons *constraints.Value
-			unitCons, err = unit.Constraints()
+			placement, err := instance.ParseUnitPlacement(machineIdSpec)
 			if err != nil {
 				return nil, err
 			}
-
-			var err error
-			var m *state.Machine
-			// If a container is to be used, create it.
-			if containerType != "" {
-				// Create the new machine marked as dirty so that
-				// nothing else will grab it before we assign the unit to it.
-				template := state.MachineTemplate{
-					Series:            unit.Series(),
-					Jobs:              []state.MachineJob{state.JobHostUnits},
-					Dirty:             true,
-					Constraints:       *unitCons,
-					RequestedNetworks: networks,
-				}
-				m, err = st.AddMachineInsideMachine(template, mid, containerType)
-			} else {
-				m, err = st.Machine(mid)
-			}
+			m, err := placementMachine(st, unit, placement, networks)
 			if err != nil {
 				return nil, fmt.Errorf("cannot assign unit %q to machine: %v", unit.Name(), err)
 			}
-			err = unit.AssignToMachine(m)
-
-			if err != nil {
+			if err := unit.AssignToMachine(m); err != nil {
 				return nil, err
 			}
 		} else if err := st.AssignUnit(unit, policy); err != nil {
@@ -180,3 +145,42 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 	}
 	return units, nil
 }
+
+// placementMachine returns the machine that the unit should be assigned
+// to according to the placement directive, adding a new machine or
+// container if the directive calls for one.
+func placementMachine(st *state.State, unit *state.Unit, placement *instance.Placement, networks []string) (*state.Machine, error) {
+	if placement.Scope == instance.MachineScope {
+		return st.Machine(placement.Directive)
+	}
+	unitCons, err := unit.Constraints()
+	if err != nil {
+		return nil, err
+	}
+	// Create the new machine marked as dirty so that nothing else
+	// will grab it before we assign the unit to it.
+	template := state.MachineTemplate{
+		Series:            unit.Series(),
+		Jobs:              []state.MachineJob{state.JobHostUnits},
+		Dirty:             true,
+		Constraints:       *unitCons,
+		RequestedNetworks: networks,
+	}
+	if containerType, err := instance.ParseContainerType(placement.Scope); err == nil {
+		if placement.Directive == "" {
+			return st.AddMachineInsideNewMachine(template, template, containerType)
+		}
+		return st.AddMachineInsideMachine(template, placement.Directive, containerType)
+	}
+	if placement.Scope != "" {
+		env, err := st.Environment()
+		if err != nil {
+			return nil, err
+		}
+		if placement.Scope != env.Name() && placement.Scope != env.UUID() {
+			return nil, fmt.Errorf("invalid environment name %q", placement.Scope)
+		}
+	}
+	template.Placement = placement.Directive
+	return st.AddOneMachine(template)
+}
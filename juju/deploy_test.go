@@ -213,6 +213,69 @@ func (s *DeployLocalSuite) TestDeployForceMachineIdWithContainer(c *gc.C) {
 	c.Assert(machineCons, gc.DeepEquals, *unitCons)
 }
 
+func (s *DeployLocalSuite) TestDeployWithNewContainerOnNewMachine(c *gc.C) {
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: string(instance.LXC),
+		})
+	c.Assert(err, gc.IsNil)
+	units, err := service.AllUnits()
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.HasLen, 1)
+	id, err := units[0].AssignedMachineId()
+	c.Assert(err, gc.IsNil)
+	c.Assert(id, gc.Equals, "0/lxc/0")
+}
+
+func (s *DeployLocalSuite) TestDeployWithProviderPlacement(c *gc.C) {
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "dummyenv:valid",
+		})
+	c.Assert(err, gc.IsNil)
+	units, err := service.AllUnits()
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.HasLen, 1)
+	id, err := units[0].AssignedMachineId()
+	c.Assert(err, gc.IsNil)
+	machine, err := s.State.Machine(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(machine.Placement(), gc.Equals, "valid")
+}
+
+func (s *DeployLocalSuite) TestDeployWithInvalidPlacement(c *gc.C) {
+	for i, test := range []struct {
+		spec string
+		err  string
+	}{{
+		spec: "bigglesplop",
+		err:  `invalid placement directive "bigglesplop"`,
+	}, {
+		spec: "otherenv:zone=us-east-1b",
+		err:  `cannot assign unit "bob\d*/0" to machine: invalid environment name "otherenv"`,
+	}, {
+		// The dummy provider only accepts the "valid" directive.
+		spec: "zone=us-east-1b",
+		err:  `cannot assign unit "bob\d*/0" to machine: .*zone=us-east-1b placement is invalid`,
+	}} {
+		c.Logf("test %d: %s", i, test.spec)
+		_, err := juju.DeployService(s.State,
+			juju.DeployServiceParams{
+				ServiceName:   fmt.Sprintf("bob%d", i),
+				Charm:         s.charm,
+				NumUnits:      1,
+				ToMachineSpec: test.spec,
+			})
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 func (s *DeployLocalSuite) assertCharm(c *gc.C, service *state.Service, expect *charm.URL) {
 	curl, force := service.CharmURL()
 	c.Assert(curl, gc.DeepEquals, expect)
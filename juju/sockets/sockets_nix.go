@@ -12,6 +12,11 @@ func Dial(socketPath string) (*rpc.Client, error) {
 	return rpc.Dial("unix", socketPath)
 }
 
+// Connect returns a connection to the given socket.
+func Connect(socketPath string) (net.Conn, error) {
+	return net.Dial("unix", socketPath)
+}
+
 func Listen(socketPath string) (net.Listener, error) {
 	// In case the unix socket is present, delete it.
 	if err := os.Remove(socketPath); err != nil {
@@ -15,6 +15,11 @@ func Dial(socketPath string) (*rpc.Client, error) {
 	return rpc.NewClient(conn), nil
 }
 
+// Connect returns a connection to the given named pipe.
+func Connect(socketPath string) (net.Conn, error) {
+	return npipe.Dial(socketPath)
+}
+
 func Listen(socketPath string) (net.Listener, error) {
 	listener, err := npipe.Listen(socketPath)
 	if err != nil {
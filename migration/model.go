@@ -0,0 +1,209 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package migration defines the format used to describe an
+// environment when it is moved from one state server to another.
+//
+// A description holds everything needed to recreate the environment's
+// model in another state server: its configuration and users, and its
+// charms, machines, services, units and relations, their annotations,
+// and the storage instances owned by its units. The agents of the
+// environment are not affected by an export, and keep their identities
+// and passwords when the environment is imported.
+package migration
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v4"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// Version is the version of the description format written by
+// Serialize. Descriptions of any other version are refused by
+// Deserialize.
+const Version = 1
+
+// Model describes an environment.
+type Model struct {
+	Version     int                    `yaml:"version"`
+	Owner       string                 `yaml:"owner"`
+	Config      map[string]interface{} `yaml:"config"`
+	Constraints string                 `yaml:"constraints,omitempty"`
+	Users       []User                 `yaml:"users,omitempty"`
+	Charms      []Charm                `yaml:"charms,omitempty"`
+	Machines    []Machine              `yaml:"machines,omitempty"`
+	Services    []Service              `yaml:"services,omitempty"`
+	Relations   []Relation             `yaml:"relations,omitempty"`
+	Annotations map[string]string      `yaml:"annotations,omitempty"`
+}
+
+// User describes a user that the environment is shared with.
+type User struct {
+	Name        string `yaml:"name"`
+	DisplayName string `yaml:"display-name,omitempty"`
+	CreatedBy   string `yaml:"created-by"`
+	Access      string `yaml:"access,omitempty"`
+}
+
+// Charm describes a charm used by the environment's services.
+type Charm struct {
+	URL          string         `yaml:"url"`
+	Meta         *charm.Meta    `yaml:"meta"`
+	Config       *charm.Config  `yaml:"config,omitempty"`
+	Actions      *charm.Actions `yaml:"actions,omitempty"`
+	StoragePath  string         `yaml:"storage-path"`
+	BundleSha256 string         `yaml:"bundle-sha256"`
+
+	// Archive holds the base64-encoded charm archive, so that
+	// the importing state server can serve it to unit agents.
+	Archive string `yaml:"archive"`
+}
+
+// Status describes the status of a machine or unit.
+type Status struct {
+	Status string                 `yaml:"status"`
+	Info   string                 `yaml:"info,omitempty"`
+	Data   map[string]interface{} `yaml:"data,omitempty"`
+}
+
+// Address describes a network address of a machine.
+type Address struct {
+	Value       string `yaml:"value"`
+	Type        string `yaml:"type"`
+	NetworkName string `yaml:"network-name,omitempty"`
+	Scope       string `yaml:"scope,omitempty"`
+}
+
+// PortRange describes a range of ports opened by a unit.
+type PortRange struct {
+	Unit     string `yaml:"unit"`
+	FromPort int    `yaml:"from-port"`
+	ToPort   int    `yaml:"to-port"`
+	Protocol string `yaml:"protocol"`
+}
+
+// OpenedPorts describes the ports opened on a machine for one
+// network.
+type OpenedPorts struct {
+	Network string      `yaml:"network"`
+	Ports   []PortRange `yaml:"ports"`
+}
+
+// Instance describes the provider instance of a provisioned machine.
+type Instance struct {
+	Id       string `yaml:"id"`
+	Status   string `yaml:"status,omitempty"`
+	Hardware string `yaml:"hardware,omitempty"`
+}
+
+// Machine describes a machine, including any containers it hosts.
+type Machine struct {
+	Id                string            `yaml:"id"`
+	Nonce             string            `yaml:"nonce,omitempty"`
+	Series            string            `yaml:"series"`
+	ContainerType     string            `yaml:"container-type,omitempty"`
+	Jobs              []string          `yaml:"jobs"`
+	PasswordHash      string            `yaml:"password-hash,omitempty"`
+	Placement         string            `yaml:"placement,omitempty"`
+	Constraints       string            `yaml:"constraints,omitempty"`
+	RequestedNetworks []string          `yaml:"requested-networks,omitempty"`
+	Instance          *Instance         `yaml:"instance,omitempty"`
+	Addresses         []Address         `yaml:"addresses,omitempty"`
+	MachineAddresses  []Address         `yaml:"machine-addresses,omitempty"`
+	Status            Status            `yaml:"status"`
+	OpenedPorts       []OpenedPorts     `yaml:"opened-ports,omitempty"`
+	Annotations       map[string]string `yaml:"annotations,omitempty"`
+	Containers        []Machine         `yaml:"containers,omitempty"`
+}
+
+// Service describes a service and its units.
+type Service struct {
+	Name              string                 `yaml:"name"`
+	Series            string                 `yaml:"series"`
+	Subordinate       bool                   `yaml:"subordinate,omitempty"`
+	CharmURL          string                 `yaml:"charm-url"`
+	ForceCharm        bool                   `yaml:"force-charm,omitempty"`
+	Exposed           bool                   `yaml:"exposed,omitempty"`
+	MinUnits          int                    `yaml:"min-units,omitempty"`
+	UnitSeq           int                    `yaml:"unit-seq"`
+	Owner             string                 `yaml:"owner"`
+	Settings          map[string]interface{} `yaml:"settings,omitempty"`
+	Constraints       string                 `yaml:"constraints,omitempty"`
+	RequestedNetworks []string               `yaml:"requested-networks,omitempty"`
+	Annotations       map[string]string      `yaml:"annotations,omitempty"`
+	Units             []Unit                 `yaml:"units,omitempty"`
+}
+
+// Unit describes a service unit.
+type Unit struct {
+	Name         string            `yaml:"name"`
+	Machine      string            `yaml:"machine,omitempty"`
+	Principal    string            `yaml:"principal,omitempty"`
+	Subordinates []string          `yaml:"subordinates,omitempty"`
+	CharmURL     string            `yaml:"charm-url,omitempty"`
+	PasswordHash string            `yaml:"password-hash,omitempty"`
+	Constraints  string            `yaml:"constraints,omitempty"`
+	Status       Status            `yaml:"status"`
+	Annotations  map[string]string `yaml:"annotations,omitempty"`
+	Storage      []StorageInstance `yaml:"storage,omitempty"`
+}
+
+// StorageInstance describes a block device or filesystem owned by a
+// unit. An instance without a location has not yet been attached to
+// the unit.
+type StorageInstance struct {
+	Id         string            `yaml:"id"`
+	Kind       string            `yaml:"kind"`
+	Location   string            `yaml:"location,omitempty"`
+	Attributes map[string]string `yaml:"attributes,omitempty"`
+}
+
+// Endpoint describes one end of a relation.
+type Endpoint struct {
+	ServiceName string `yaml:"service"`
+	Name        string `yaml:"name"`
+	Role        string `yaml:"role"`
+	Interface   string `yaml:"interface"`
+	Optional    bool   `yaml:"optional,omitempty"`
+	Limit       int    `yaml:"limit,omitempty"`
+	Scope       string `yaml:"scope"`
+}
+
+// RelationScope describes a unit that has entered a relation's scope,
+// and the settings it has published to the relation.
+type RelationScope struct {
+	Key      string                 `yaml:"key"`
+	Settings map[string]interface{} `yaml:"settings,omitempty"`
+}
+
+// Relation describes a relation between services.
+type Relation struct {
+	Id        int             `yaml:"id"`
+	Key       string          `yaml:"key"`
+	Endpoints []Endpoint      `yaml:"endpoints"`
+	Scopes    []RelationScope `yaml:"scopes,omitempty"`
+}
+
+// Serialize returns the serialized form of the given description,
+// which is marked with the current format version.
+func Serialize(model *Model) ([]byte, error) {
+	m := *model
+	m.Version = Version
+	data, err := goyaml.Marshal(&m)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot serialize environment description")
+	}
+	return data, nil
+}
+
+// Deserialize parses a description written by Serialize.
+func Deserialize(data []byte) (*Model, error) {
+	var model Model
+	if err := goyaml.Unmarshal(data, &model); err != nil {
+		return nil, errors.Annotate(err, "cannot parse environment description")
+	}
+	if model.Version != Version {
+		return nil, errors.NotSupportedf("environment description version %d", model.Version)
+	}
+	return &model, nil
+}
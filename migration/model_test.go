@@ -0,0 +1,105 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	stdtesting "testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/migration"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type ModelSuite struct{}
+
+var _ = gc.Suite(&ModelSuite{})
+
+func (*ModelSuite) TestSerializeRoundTrip(c *gc.C) {
+	model := &migration.Model{
+		Owner:       "admin@local",
+		Config:      map[string]interface{}{"name": "foo", "type": "dummy"},
+		Constraints: "mem=4G",
+		Users: []migration.User{{
+			Name:      "admin@local",
+			CreatedBy: "admin@local",
+		}},
+		Charms: []migration.Charm{{
+			URL: "local:quantal/wordpress-3",
+			Meta: &charm.Meta{
+				Name:    "wordpress",
+				Summary: "a blog",
+				Provides: map[string]charm.Relation{
+					"url": {Name: "url", Role: charm.RoleProvider, Interface: "http", Scope: charm.ScopeGlobal},
+				},
+			},
+			StoragePath:  "charms/wordpress",
+			BundleSha256: "abcdef",
+		}},
+		Machines: []migration.Machine{{
+			Id:     "0",
+			Series: "quantal",
+			Jobs:   []string{"JobHostUnits"},
+			Instance: &migration.Instance{
+				Id:       "i-0",
+				Hardware: "arch=amd64 mem=4096M",
+			},
+			Status: migration.Status{Status: "started"},
+			Containers: []migration.Machine{{
+				Id:            "0/lxc/0",
+				Series:        "quantal",
+				ContainerType: "lxc",
+				Jobs:          []string{"JobHostUnits"},
+				Status:        migration.Status{Status: "pending"},
+			}},
+		}},
+		Services: []migration.Service{{
+			Name:     "wordpress",
+			Series:   "quantal",
+			CharmURL: "local:quantal/wordpress-3",
+			UnitSeq:  1,
+			Owner:    "user-admin",
+			Settings: map[string]interface{}{"blog-title": "hello"},
+			Units: []migration.Unit{{
+				Name:        "wordpress/0",
+				Machine:     "0",
+				Status:      migration.Status{Status: "started"},
+				Annotations: map[string]string{"owner": "ops"},
+				Storage: []migration.StorageInstance{{
+					Id:         "data/0",
+					Kind:       "filesystem",
+					Location:   "/srv/data",
+					Attributes: map[string]string{"size": "1024"},
+				}},
+			}},
+		}},
+	}
+	data, err := migration.Serialize(model)
+	c.Assert(err, gc.IsNil)
+	c.Assert(model.Version, gc.Equals, 0)
+
+	result, err := migration.Deserialize(data)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Version, gc.Equals, migration.Version)
+	result.Version = 0
+	c.Assert(result, jc.DeepEquals, model)
+}
+
+func (*ModelSuite) TestDeserializeRefusesOtherVersions(c *gc.C) {
+	_, err := migration.Deserialize([]byte("version: 99\nowner: admin@local\n"))
+	c.Assert(err, gc.ErrorMatches, "environment description version 99 not supported")
+
+	_, err = migration.Deserialize([]byte("owner: admin@local\n"))
+	c.Assert(err, gc.ErrorMatches, "environment description version 0 not supported")
+}
+
+func (*ModelSuite) TestDeserializeInvalid(c *gc.C) {
+	_, err := migration.Deserialize([]byte("machines: {"))
+	c.Assert(err, gc.ErrorMatches, "cannot parse environment description: .*")
+}
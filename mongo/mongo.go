@@ -169,6 +169,19 @@ type EnsureServerParams struct {
 	// calculate a default size according to the
 	// algorithm defined in Mongo.
 	OplogSize int
+
+	// NoJournal disables Mongo's write-ahead journal. This trades
+	// durability for disk space and write speed.
+	NoJournal bool
+
+	// Prealloc allows Mongo to preallocate its data files. It is
+	// disabled by default, which keeps small machines small.
+	Prealloc bool
+
+	// CacheSizeGB is the size of Mongo's WiredTiger cache. If this
+	// is zero, Mongo's default is used. Setting it requires a mongod
+	// that supports the WiredTiger storage engine.
+	CacheSizeGB int
 }
 
 // EnsureServer ensures that the correct mongo upstart script is installed
@@ -208,7 +221,7 @@ func EnsureServer(args EnsureServerParams) error {
 	}
 	logVersion(mongoPath)
 
-	svc, err := upstartService(args.Namespace, args.DataDir, dbDir, mongoPath, args.StatePort, oplogSizeMB)
+	svc, err := upstartService(args, dbDir, mongoPath, oplogSizeMB)
 	if err != nil {
 		return err
 	}
@@ -248,8 +261,10 @@ func EnsureServer(args EnsureServerParams) error {
 	if err := upstartServiceStop(svc); err != nil {
 		return fmt.Errorf("failed to stop mongo: %v", err)
 	}
-	if err := makeJournalDirs(dbDir); err != nil {
-		return fmt.Errorf("error creating journal directories: %v", err)
+	if !args.NoJournal {
+		if err := makeJournalDirs(dbDir); err != nil {
+			return fmt.Errorf("error creating journal directories: %v", err)
+		}
 	}
 	if err := preallocOplog(dbDir, oplogSizeMB); err != nil {
 		return fmt.Errorf("error creating oplog files: %v", err)
@@ -298,24 +313,32 @@ func sharedSecretPath(dataDir string) string {
 	return filepath.Join(dataDir, SharedSecretFile)
 }
 
-// upstartService returns the upstart config for the mongo state service.
-// It also returns the path to the mongod executable that the upstart config
-// will be using.
-func upstartService(namespace, dataDir, dbDir, mongoPath string, port, oplogSizeMB int) (*upstart.Service, error) {
+// upstartService returns the upstart config for the mongo state service,
+// tuned according to the supplied parameters.
+func upstartService(args EnsureServerParams, dbDir, mongoPath string, oplogSizeMB int) (*upstart.Service, error) {
 	mongoCmd := mongoPath + " --auth" +
 		" --dbpath=" + utils.ShQuote(dbDir) +
 		" --sslOnNormalPorts" +
-		" --sslPEMKeyFile " + utils.ShQuote(sslKeyPath(dataDir)) +
+		" --sslPEMKeyFile " + utils.ShQuote(sslKeyPath(args.DataDir)) +
 		" --sslPEMKeyPassword ignored" +
-		" --port " + fmt.Sprint(port) +
-		" --noprealloc" +
+		" --port " + fmt.Sprint(args.StatePort) +
 		" --syslog" +
 		" --smallfiles" +
-		" --journal" +
-		" --keyFile " + utils.ShQuote(sharedSecretPath(dataDir)) +
+		" --keyFile " + utils.ShQuote(sharedSecretPath(args.DataDir)) +
 		" --replSet " + ReplicaSetName +
 		" --ipv6 " +
 		" --oplogSize " + strconv.Itoa(oplogSizeMB)
+	if args.NoJournal {
+		mongoCmd += " --nojournal"
+	} else {
+		mongoCmd += " --journal"
+	}
+	if !args.Prealloc {
+		mongoCmd += " --noprealloc"
+	}
+	if args.CacheSizeGB > 0 {
+		mongoCmd += " --wiredTigerCacheSizeGB " + strconv.Itoa(args.CacheSizeGB)
+	}
 	conf := common.Conf{
 		Desc: "juju state database",
 		Limit: map[string]string{
@@ -324,7 +347,7 @@ func upstartService(namespace, dataDir, dbDir, mongoPath string, port, oplogSize
 		},
 		Cmd: mongoCmd,
 	}
-	svc := upstart.NewService(ServiceName(namespace), conf)
+	svc := upstart.NewService(ServiceName(args.Namespace), conf)
 	return svc, nil
 }
 
@@ -330,7 +330,7 @@ func (s *MongoSuite) TestInstallMongodServiceExists(c *gc.C) {
 func (s *MongoSuite) TestUpstartServiceWithReplSet(c *gc.C) {
 	dataDir := c.MkDir()
 
-	svc, err := mongo.UpstartService("", dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024)
+	svc, err := mongo.UpstartService(mongo.EnsureServerParams{DataDir: dataDir, StatePort: 1234}, dataDir, mongo.JujuMongodPath, 1024)
 	c.Assert(err, gc.IsNil)
 	c.Assert(strings.Contains(svc.Conf.Cmd, "--replSet"), jc.IsTrue)
 }
@@ -338,7 +338,7 @@ func (s *MongoSuite) TestUpstartServiceWithReplSet(c *gc.C) {
 func (s *MongoSuite) TestUpstartServiceIPv6(c *gc.C) {
 	dataDir := c.MkDir()
 
-	svc, err := mongo.UpstartService("", dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024)
+	svc, err := mongo.UpstartService(mongo.EnsureServerParams{DataDir: dataDir, StatePort: 1234}, dataDir, mongo.JujuMongodPath, 1024)
 	c.Assert(err, gc.IsNil)
 	c.Assert(strings.Contains(svc.Conf.Cmd, "--ipv6"), jc.IsTrue)
 }
@@ -346,12 +346,54 @@ func (s *MongoSuite) TestUpstartServiceIPv6(c *gc.C) {
 func (s *MongoSuite) TestUpstartServiceWithJournal(c *gc.C) {
 	dataDir := c.MkDir()
 
-	svc, err := mongo.UpstartService("", dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024)
+	svc, err := mongo.UpstartService(mongo.EnsureServerParams{DataDir: dataDir, StatePort: 1234}, dataDir, mongo.JujuMongodPath, 1024)
 	c.Assert(err, gc.IsNil)
 	journalPresent := strings.Contains(svc.Conf.Cmd, " --journal ") || strings.HasSuffix(svc.Conf.Cmd, " --journal")
 	c.Assert(journalPresent, jc.IsTrue)
 }
 
+func (s *MongoSuite) TestUpstartServiceDefaultTuning(c *gc.C) {
+	dataDir := c.MkDir()
+
+	svc, err := mongo.UpstartService(mongo.EnsureServerParams{DataDir: dataDir, StatePort: 1234}, dataDir, mongo.JujuMongodPath, 1024)
+	c.Assert(err, gc.IsNil)
+	c.Assert(svc.Conf.Cmd, jc.Contains, " --oplogSize 1024")
+	c.Assert(svc.Conf.Cmd, jc.Contains, " --noprealloc")
+	c.Assert(svc.Conf.Cmd, gc.Not(jc.Contains), "--nojournal")
+	c.Assert(svc.Conf.Cmd, gc.Not(jc.Contains), "--wiredTigerCacheSizeGB")
+}
+
+func (s *MongoSuite) TestUpstartServiceTuning(c *gc.C) {
+	dataDir := c.MkDir()
+
+	svc, err := mongo.UpstartService(mongo.EnsureServerParams{
+		DataDir:     dataDir,
+		StatePort:   1234,
+		NoJournal:   true,
+		Prealloc:    true,
+		CacheSizeGB: 4,
+	}, dataDir, mongo.JujuMongodPath, 2048)
+	c.Assert(err, gc.IsNil)
+	c.Assert(svc.Conf.Cmd, jc.Contains, " --oplogSize 2048")
+	c.Assert(svc.Conf.Cmd, jc.Contains, " --nojournal")
+	c.Assert(svc.Conf.Cmd, jc.Contains, " --wiredTigerCacheSizeGB 4")
+	c.Assert(svc.Conf.Cmd, gc.Not(jc.Contains), " --journal")
+	c.Assert(svc.Conf.Cmd, gc.Not(jc.Contains), "--noprealloc")
+}
+
+func (s *MongoSuite) TestEnsureServerNoJournal(c *gc.C) {
+	dataDir := c.MkDir()
+	dbDir := filepath.Join(dataDir, "db")
+
+	mockShellCommand(c, &s.CleanupSuite, "apt-get")
+
+	args := makeEnsureServerParams(dataDir, "namespace")
+	args.NoJournal = true
+	err := mongo.EnsureServer(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(filepath.Join(dbDir, "journal"), jc.DoesNotExist)
+}
+
 func (s *MongoSuite) TestNoAuthCommandWithJournal(c *gc.C) {
 	dataDir := c.MkDir()
 
@@ -169,6 +169,32 @@ func (p PortRange) String() string {
 	return fmt.Sprintf("%d-%d/%s", p.FromPort, p.ToPort, strings.ToLower(p.Protocol))
 }
 
+// ParsePortRange parses a port range in the format produced by
+// PortRange.String, such as "80/tcp" or "8000-8080/udp". The protocol
+// defaults to tcp when omitted.
+func ParsePortRange(s string) (PortRange, error) {
+	portsPart, protocol := s, "tcp"
+	if i := strings.Index(s, "/"); i >= 0 {
+		portsPart, protocol = s[:i], strings.ToLower(s[i+1:])
+	}
+	parts := strings.SplitN(portsPart, "-", 2)
+	fromPort, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return PortRange{}, errors.Errorf("invalid port range %q", s)
+	}
+	toPort := fromPort
+	if len(parts) == 2 {
+		if toPort, err = strconv.Atoi(parts[1]); err != nil {
+			return PortRange{}, errors.Errorf("invalid port range %q", s)
+		}
+	}
+	portRange := PortRange{FromPort: fromPort, ToPort: toPort, Protocol: protocol}
+	if err := portRange.Validate(); err != nil {
+		return PortRange{}, err
+	}
+	return portRange, nil
+}
+
 type portRangeSlice []PortRange
 
 func (p portRangeSlice) Len() int      { return len(p) }
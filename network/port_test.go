@@ -311,3 +311,43 @@ func (*PortSuite) TestCollapsePorts(c *gc.C) {
 		c.Assert(network.CollapsePorts(t.ports), gc.DeepEquals, t.expected)
 	}
 }
+
+func (p *PortSuite) TestParsePortRange(c *gc.C) {
+	var testCases = []struct {
+		input    string
+		expected network.PortRange
+		err      string
+	}{{
+		input:    "80/tcp",
+		expected: network.PortRange{80, 80, "tcp"},
+	}, {
+		input:    "8000-8080/UDP",
+		expected: network.PortRange{8000, 8080, "udp"},
+	}, {
+		input:    "443",
+		expected: network.PortRange{443, 443, "tcp"},
+	}, {
+		input: "http/tcp",
+		err:   `invalid port range "http/tcp"`,
+	}, {
+		input: "80-/tcp",
+		err:   `invalid port range "80-/tcp"`,
+	}, {
+		input: "90-80/tcp",
+		err:   "invalid port range 90-80/tcp",
+	}, {
+		input: "80/icmp",
+		err:   `invalid protocol "icmp", expected "tcp" or "udp"`,
+	}}
+
+	for i, t := range testCases {
+		c.Logf("test %d: %q", i, t.input)
+		portRange, err := network.ParsePortRange(t.input)
+		if t.err != "" {
+			c.Check(err, gc.ErrorMatches, t.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(portRange, gc.Equals, t.expected)
+	}
+}
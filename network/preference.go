@@ -0,0 +1,132 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AddressPreference names the kind of address that should be published
+// as a unit's public or private address, in preference to the address
+// that would otherwise be selected.
+type AddressPreference string
+
+const (
+	// PreferPublicHostName prefers a public DNS name.
+	PreferPublicHostName AddressPreference = "public-hostname"
+
+	// PreferPublicIP prefers a public IP address.
+	PreferPublicIP AddressPreference = "public-ip"
+
+	// PreferPrivateIP prefers a cloud-local IP address.
+	PreferPrivateIP AddressPreference = "private-ip"
+)
+
+// match reports whether addr is of the preferred kind.
+func (p AddressPreference) match(addr Address) bool {
+	switch p {
+	case PreferPublicHostName:
+		return addr.Scope == ScopePublic && addr.Type == HostName
+	case PreferPublicIP:
+		return addr.Scope == ScopePublic && addr.Type != HostName
+	case PreferPrivateIP:
+		return addr.Scope == ScopeCloudLocal && addr.Type != HostName
+	}
+	return false
+}
+
+func parseAddressPreference(s string) (AddressPreference, error) {
+	switch p := AddressPreference(s); p {
+	case PreferPublicHostName, PreferPublicIP, PreferPrivateIP:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown address preference %q", s)
+}
+
+// AddressPreferences holds the address preference that applies to the
+// addresses on each network, and the preference that applies to all
+// other addresses. The zero value expresses no preference.
+type AddressPreferences struct {
+	Default  AddressPreference
+	Networks map[string]AddressPreference
+}
+
+// ParseAddressPreferences parses a comma-separated list of address
+// preferences. An entry of the form <network>=<preference> applies to
+// addresses on the named network; a single entry without a network
+// applies to all other addresses. For example:
+//
+//	public-ip,vpc-net=private-ip
+func ParseAddressPreferences(s string) (AddressPreferences, error) {
+	var prefs AddressPreferences
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		pref, err := parseAddressPreference(parts[len(parts)-1])
+		if err != nil {
+			return AddressPreferences{}, err
+		}
+		if len(parts) == 1 {
+			if prefs.Default != "" {
+				return AddressPreferences{}, fmt.Errorf("more than one default address preference in %q", s)
+			}
+			prefs.Default = pref
+			continue
+		}
+		if prefs.Networks == nil {
+			prefs.Networks = make(map[string]AddressPreference)
+		}
+		if _, ok := prefs.Networks[parts[0]]; ok {
+			return AddressPreferences{}, fmt.Errorf("more than one address preference for network %q", parts[0])
+		}
+		prefs.Networks[parts[0]] = pref
+	}
+	return prefs, nil
+}
+
+// forAddress returns the preference that applies to addr.
+func (p AddressPreferences) forAddress(addr Address) AddressPreference {
+	if pref, ok := p.Networks[addr.NetworkName]; ok && addr.NetworkName != "" {
+		return pref
+	}
+	return p.Default
+}
+
+// selectPreferred returns the value of the first address that is of
+// the kind preferred for its network, or the empty string if there is
+// no such address.
+func (p AddressPreferences) selectPreferred(addresses []Address) string {
+	for _, addr := range addresses {
+		if p.forAddress(addr).match(addr) {
+			return addr.Value
+		}
+	}
+	return ""
+}
+
+// SelectPublicAddressWithPreferences picks the address to publish as a
+// public endpoint, honouring the given preferences. If no address is
+// of a preferred kind, the address selected by SelectPublicAddress is
+// returned.
+func SelectPublicAddressWithPreferences(addresses []Address, prefs AddressPreferences) string {
+	if addr := prefs.selectPreferred(addresses); addr != "" {
+		return addr
+	}
+	return SelectPublicAddress(addresses)
+}
+
+// SelectInternalAddressWithPreferences picks the address to publish as
+// a unit's private address, honouring the given preferences. If no
+// address is of a preferred kind, the address selected by
+// SelectInternalAddress is returned.
+func SelectInternalAddressWithPreferences(addresses []Address, prefs AddressPreferences) string {
+	if addr := prefs.selectPreferred(addresses); addr != "" {
+		return addr
+	}
+	return SelectInternalAddress(addresses, false)
+}
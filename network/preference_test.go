@@ -0,0 +1,101 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type PreferenceSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&PreferenceSuite{})
+
+func (s *PreferenceSuite) TestParseAddressPreferences(c *gc.C) {
+	for i, test := range []struct {
+		value  string
+		expect network.AddressPreferences
+		err    string
+	}{{
+		value: "",
+	}, {
+		value:  "private-ip",
+		expect: network.AddressPreferences{Default: network.PreferPrivateIP},
+	}, {
+		value: "public-ip, vpc=private-ip,lan=public-hostname",
+		expect: network.AddressPreferences{
+			Default: network.PreferPublicIP,
+			Networks: map[string]network.AddressPreference{
+				"vpc": network.PreferPrivateIP,
+				"lan": network.PreferPublicHostName,
+			},
+		},
+	}, {
+		value: "public-ip,private-ip",
+		err:   `more than one default address preference in "public-ip,private-ip"`,
+	}, {
+		value: "vpc=public-ip,vpc=private-ip",
+		err:   `more than one address preference for network "vpc"`,
+	}, {
+		value: "vpc=elastic-ip",
+		err:   `unknown address preference "elastic-ip"`,
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		prefs, err := network.ParseAddressPreferences(test.value)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, gc.IsNil)
+		c.Check(prefs, gc.DeepEquals, test.expect)
+	}
+}
+
+var preferenceAddresses = []network.Address{
+	{Value: "ec2.example.com", Type: network.HostName, Scope: network.ScopePublic},
+	{Value: "8.8.8.8", Type: network.IPv4Address, Scope: network.ScopePublic},
+	{Value: "10.0.0.1", Type: network.IPv4Address, Scope: network.ScopeCloudLocal},
+	{Value: "10.1.0.1", Type: network.IPv4Address, Scope: network.ScopeCloudLocal, NetworkName: "vpc"},
+}
+
+func (s *PreferenceSuite) TestSelectPublicAddressWithPreferences(c *gc.C) {
+	for i, test := range []struct {
+		prefs  string
+		expect string
+	}{
+		{"", "ec2.example.com"},
+		{"public-ip", "8.8.8.8"},
+		{"private-ip", "10.0.0.1"},
+		{"vpc=private-ip", "10.1.0.1"},
+		{"public-ip,vpc=private-ip", "8.8.8.8"},
+	} {
+		c.Logf("test %d: %q", i, test.prefs)
+		prefs, err := network.ParseAddressPreferences(test.prefs)
+		c.Assert(err, gc.IsNil)
+		addr := network.SelectPublicAddressWithPreferences(preferenceAddresses, prefs)
+		c.Check(addr, gc.Equals, test.expect)
+	}
+}
+
+func (s *PreferenceSuite) TestSelectInternalAddressWithPreferences(c *gc.C) {
+	for i, test := range []struct {
+		prefs  string
+		expect string
+	}{
+		{"", "10.0.0.1"},
+		{"public-ip", "8.8.8.8"},
+		{"public-hostname", "ec2.example.com"},
+		{"vpc=private-ip", "10.1.0.1"},
+	} {
+		c.Logf("test %d: %q", i, test.prefs)
+		prefs, err := network.ParseAddressPreferences(test.prefs)
+		c.Assert(err, gc.IsNil)
+		addr := network.SelectInternalAddressWithPreferences(preferenceAddresses, prefs)
+		c.Check(addr, gc.Equals, test.expect)
+	}
+}
@@ -161,6 +161,14 @@ azure:
     #
     storage-account-name: abcdefghijkl
 
+    # availability-sets-enabled places all units of a service in a
+    # shared availability set, so that Azure's SLA requirements are
+    # met when the service is scaled out. It is enabled by default,
+    # cannot be changed once the environment is prepared, and is
+    # incompatible with placement directives.
+    #
+    # availability-sets-enabled: true
+
     # force-image-name overrides the OS image selection to use a fixed
     # image for all deployments. Most useful for developers.
     #
@@ -198,6 +206,11 @@ func (prov azureEnvironProvider) BoilerplateConfig() string {
 	return boilerplateYAML
 }
 
+// ConfigSchema is specified in the environs.ConfigSchemaSource interface.
+func (azureEnvironProvider) ConfigSchema() map[string]config.Attr {
+	return config.FieldsSchema(configFields, configDefaults, "management-certificate")
+}
+
 // SecretAttrs is specified in the EnvironProvider interface.
 func (prov azureEnvironProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	secretAttrs := make(map[string]string)
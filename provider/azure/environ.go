@@ -495,6 +495,7 @@ func (env *azureEnviron) selectInstanceTypeAndImage(constraint *instances.Instan
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Zone,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -31,6 +31,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/juju/loggo"
@@ -229,6 +230,7 @@ type environState struct {
 	apiServer    *apiserver.Server
 	apiState     *state.State
 	preferIPv6   bool
+	zones        []AvailabilityZone
 }
 
 // environ represents a client's connection to a given environment's
@@ -242,6 +244,31 @@ type environ struct {
 }
 
 var _ environs.Environ = (*environ)(nil)
+var _ common.ZonedEnviron = (*environ)(nil)
+var _ environs.InstanceFirewallMigrator = (*environ)(nil)
+
+// AvailabilityZone describes an availability zone of a dummy
+// environment.
+type AvailabilityZone struct {
+	ZoneName    string
+	Unavailable bool
+}
+
+// Name is specified on the common.AvailabilityZone interface.
+func (z AvailabilityZone) Name() string {
+	return z.ZoneName
+}
+
+// Available is specified on the common.AvailabilityZone interface.
+func (z AvailabilityZone) Available() bool {
+	return !z.Unavailable
+}
+
+// defaultZones holds the availability zones of a new dummy environment.
+var defaultZones = []AvailabilityZone{
+	{ZoneName: "zone1"},
+	{ZoneName: "zone2"},
+}
 
 // discardOperations discards all Operations written to it.
 var discardOperations chan<- Operation
@@ -336,6 +363,7 @@ func newState(name string, ops chan<- Operation, policy state.Policy) *environSt
 		statePolicy: policy,
 		insts:       make(map[instance.Id]*dummyInstance),
 		globalPorts: make(map[network.PortRange]bool),
+		zones:       append([]AvailabilityZone(nil), defaultZones...),
 	}
 	s.storage = newStorageServer(s, "/"+name+"/private")
 	s.listenStorage()
@@ -409,6 +437,26 @@ func SetStorageDelay(d time.Duration) {
 	}
 }
 
+// SetAvailabilityZones replaces the availability zones of any current
+// environment. Instances already started keep their zones.
+func SetAvailabilityZones(zones ...AvailabilityZone) {
+	p := &providerInstance
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, st := range p.state {
+		st.mu.Lock()
+		st.zones = append([]AvailabilityZone(nil), zones...)
+		st.mu.Unlock()
+	}
+}
+
+// SetProviderDelay causes every operation of every dummy environment
+// to be delayed for the given duration, simulating the latency of a
+// real provider. It overrides JUJU_DUMMY_DELAY.
+func SetProviderDelay(d time.Duration) {
+	atomic.StoreInt64((*int64)(&providerDelay), int64(d))
+}
+
 var configFields = schema.Fields{
 	"state-server": schema.Bool(),
 	"broken":       schema.String(),
@@ -550,6 +598,11 @@ func (p *environProvider) prepare(cfg *config.Config) (*config.Config, error) {
 	return cfg.Apply(attrs)
 }
 
+// ConfigSchema is specified in the environs.ConfigSchemaSource interface.
+func (*environProvider) ConfigSchema() map[string]config.Attr {
+	return config.FieldsSchema(configFields, configDefaults, "secret")
+}
+
 func (*environProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	ecfg, err := providerInstance.newConfig(cfg)
 	if err != nil {
@@ -608,7 +661,7 @@ func (e *environ) SupportAddressAllocation(netId network.Id) (bool, error) {
 
 // PrecheckInstance is specified in the state.Prechecker interface.
 func (*environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
-	if placement != "" && placement != "valid" {
+	if placement != "" && placement != "valid" && !strings.HasPrefix(placement, "zone=") {
 		return fmt.Errorf("%s placement is invalid", placement)
 	}
 	return nil
@@ -654,8 +707,13 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 
 	// Create an instance for the bootstrap node.
 	logger.Infof("creating bootstrap instance")
+	zone, err := estate.pickZone("")
+	if err != nil {
+		return "", "", nil, err
+	}
 	i := &dummyInstance{
 		id:           BootstrapInstanceId,
+		zone:         zone,
 		addresses:    network.NewAddresses("localhost"),
 		ports:        make(map[network.PortRange]bool),
 		machineId:    agent.BootstrapMachineId,
@@ -832,7 +890,12 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (instance.Ins
 		addrs = append(addrs, network.NewAddress(fmt.Sprintf("fc00::%x", estate.maxId+1), network.ScopeUnknown))
 	}
 	logger.Debugf("StartInstance addresses: %v", addrs)
+	zone, err := estate.pickZone(args.Placement)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	i := &dummyInstance{
+		zone:         zone,
 		id:           instance.Id(idString),
 		addresses:    addrs,
 		ports:        make(map[network.PortRange]bool),
@@ -967,6 +1030,76 @@ func (e *environ) Instances(ids []instance.Id) (insts []instance.Instance, err e
 	return
 }
 
+// AvailabilityZones is specified on the common.ZonedEnviron interface.
+func (e *environ) AvailabilityZones() ([]common.AvailabilityZone, error) {
+	defer delay()
+	if err := e.checkBroken("AvailabilityZones"); err != nil {
+		return nil, err
+	}
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	zones := make([]common.AvailabilityZone, len(estate.zones))
+	for i, zone := range estate.zones {
+		zones[i] = zone
+	}
+	return zones, nil
+}
+
+// InstanceAvailabilityZoneNames is specified on the common.ZonedEnviron
+// interface.
+func (e *environ) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	insts, err := e.Instances(ids)
+	if err != nil && err != environs.ErrPartialInstances {
+		return nil, err
+	}
+	zones := make([]string, len(insts))
+	for i, inst := range insts {
+		if inst != nil {
+			zones[i] = inst.(*dummyInstance).zone
+		}
+	}
+	return zones, err
+}
+
+// pickZone returns the availability zone for a new instance. A
+// placement of the form "zone=<name>" selects that zone. Otherwise the
+// available zone with the fewest instances is used, so that instances
+// are spread across zones in a predictable order. It must be called
+// with the state's mutex held.
+func (estate *environState) pickZone(placement string) (string, error) {
+	if strings.HasPrefix(placement, "zone=") {
+		name := strings.TrimPrefix(placement, "zone=")
+		for _, zone := range estate.zones {
+			if zone.Name() != name {
+				continue
+			}
+			if !zone.Available() {
+				return "", fmt.Errorf("availability zone %q is unavailable", name)
+			}
+			return name, nil
+		}
+		return "", fmt.Errorf("invalid availability zone %q", name)
+	}
+	counts := make(map[string]int)
+	for _, inst := range estate.insts {
+		counts[inst.zone]++
+	}
+	best := ""
+	for _, zone := range estate.zones {
+		if !zone.Available() {
+			continue
+		}
+		if best == "" || counts[zone.Name()] < counts[best] {
+			best = zone.Name()
+		}
+	}
+	return best, nil
+}
+
 // AllocateAddress requests a new address to be allocated for the
 // given instance on the given network.
 func (env *environ) AllocateAddress(instId instance.Id, netId network.Id) (network.Address, error) {
@@ -1089,6 +1222,64 @@ func (e *environ) Ports() (ports []network.PortRange, err error) {
 	return
 }
 
+// EnsureInstanceFirewall is specified in the
+// environs.InstanceFirewallMigrator interface.
+func (e *environ) EnsureInstanceFirewall(machineId string, id instance.Id) error {
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	inst := estate.insts[id]
+	if inst == nil {
+		return fmt.Errorf("instance %q not found", id)
+	}
+	if inst.machineId != machineId {
+		return fmt.Errorf("instance %q belongs to machine %q, not %q", id, inst.machineId, machineId)
+	}
+	inst.firewallMode = config.FwInstance
+	return nil
+}
+
+// RemoveGlobalFirewall is specified in the
+// environs.InstanceFirewallMigrator interface.
+func (e *environ) RemoveGlobalFirewall(ids []instance.Id) error {
+	estate, err := e.state()
+	if err != nil {
+		return err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	estate.globalPorts = make(map[network.PortRange]bool)
+	for _, id := range ids {
+		if inst := estate.insts[id]; inst != nil {
+			inst.firewallMode = config.FwInstance
+		}
+	}
+	return nil
+}
+
+// HasGlobalFirewall is specified in the
+// environs.InstanceFirewallMigrator interface.
+func (e *environ) HasGlobalFirewall() (bool, error) {
+	estate, err := e.state()
+	if err != nil {
+		return false, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	if len(estate.globalPorts) > 0 {
+		return true, nil
+	}
+	for _, inst := range estate.insts {
+		if inst.firewallMode == config.FwGlobal {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (*environ) Provider() environs.EnvironProvider {
 	return &providerInstance
 }
@@ -1102,6 +1293,7 @@ type dummyInstance struct {
 	series       string
 	firewallMode string
 	stateServer  bool
+	zone         string
 
 	mu        sync.Mutex
 	addresses []network.Address
@@ -1215,8 +1407,9 @@ var providerDelay time.Duration
 
 // pause execution to simulate the latency of a real provider
 func delay() {
-	if providerDelay > 0 {
-		logger.Infof("pausing for %v", providerDelay)
-		<-time.After(providerDelay)
+	d := time.Duration(atomic.LoadInt64((*int64)(&providerDelay)))
+	if d > 0 {
+		logger.Infof("pausing for %v", d)
+		<-time.After(d)
 	}
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/testing"
 )
@@ -192,6 +193,99 @@ func (s *suite) TestPreferIPv6Off(c *gc.C) {
 	c.Assert(addrs, jc.DeepEquals, network.NewAddresses("only-0.dns", "127.0.0.1"))
 }
 
+func (s *suite) TestAvailabilityZones(c *gc.C) {
+	e := s.bootstrapTestEnviron(c, false)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, gc.IsNil)
+	}()
+	zenv, ok := e.(common.ZonedEnviron)
+	c.Assert(ok, jc.IsTrue)
+
+	zones, err := zenv.AvailabilityZones()
+	c.Assert(err, gc.IsNil)
+	c.Assert(zones, jc.DeepEquals, []common.AvailabilityZone{
+		dummy.AvailabilityZone{ZoneName: "zone1"},
+		dummy.AvailabilityZone{ZoneName: "zone2"},
+	})
+
+	dummy.SetAvailabilityZones(
+		dummy.AvailabilityZone{ZoneName: "a"},
+		dummy.AvailabilityZone{ZoneName: "b", Unavailable: true},
+	)
+	zones, err = zenv.AvailabilityZones()
+	c.Assert(err, gc.IsNil)
+	c.Assert(zones, gc.HasLen, 2)
+	c.Assert(zones[0].Name(), gc.Equals, "a")
+	c.Assert(zones[0].Available(), jc.IsTrue)
+	c.Assert(zones[1].Name(), gc.Equals, "b")
+	c.Assert(zones[1].Available(), jc.IsFalse)
+}
+
+func (s *suite) TestStartInstanceSpreadsZones(c *gc.C) {
+	e := s.bootstrapTestEnviron(c, false)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, gc.IsNil)
+	}()
+	zenv := e.(common.ZonedEnviron)
+
+	// The bootstrap instance is in zone1.
+	var ids []instance.Id
+	for _, machineId := range []string{"0", "1", "2"} {
+		inst, _ := jujutesting.AssertStartInstance(c, e, machineId)
+		ids = append(ids, inst.Id())
+	}
+	zones, err := zenv.InstanceAvailabilityZoneNames(append(ids, "missing"))
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(zones, gc.DeepEquals, []string{"zone2", "zone1", "zone2", ""})
+
+	_, err = zenv.InstanceAvailabilityZoneNames([]instance.Id{"missing"})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *suite) TestStartInstanceZonePlacement(c *gc.C) {
+	e := s.bootstrapTestEnviron(c, false)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, gc.IsNil)
+	}()
+	dummy.SetAvailabilityZones(
+		dummy.AvailabilityZone{ZoneName: "zone1"},
+		dummy.AvailabilityZone{ZoneName: "zone2", Unavailable: true},
+	)
+
+	params := environs.StartInstanceParams{Placement: "zone=zone1"}
+	inst, _, _, err := jujutesting.StartInstanceWithParams(e, "0", params, nil)
+	c.Assert(err, gc.IsNil)
+	zones, err := e.(common.ZonedEnviron).InstanceAvailabilityZoneNames([]instance.Id{inst.Id()})
+	c.Assert(err, gc.IsNil)
+	c.Assert(zones, gc.DeepEquals, []string{"zone1"})
+
+	params.Placement = "zone=zone2"
+	_, _, _, err = jujutesting.StartInstanceWithParams(e, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, `availability zone "zone2" is unavailable`)
+
+	params.Placement = "zone=zone3"
+	_, _, _, err = jujutesting.StartInstanceWithParams(e, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, `invalid availability zone "zone3"`)
+}
+
+func (s *suite) TestSetProviderDelay(c *gc.C) {
+	e := s.bootstrapTestEnviron(c, false)
+	defer func() {
+		err := e.Destroy()
+		c.Assert(err, gc.IsNil)
+	}()
+	dummy.SetProviderDelay(100 * time.Millisecond)
+	defer dummy.SetProviderDelay(0)
+
+	start := time.Now()
+	_, err := e.AllInstances()
+	c.Assert(err, gc.IsNil)
+	c.Assert(time.Since(start) >= 100*time.Millisecond, jc.IsTrue)
+}
+
 func assertAllocateAddress(c *gc.C, e environs.Environ, opc chan dummy.Operation, expectInstId instance.Id, expectNetId network.Id, expectAddress network.Address) {
 	select {
 	case op := <-opc:
@@ -262,6 +262,11 @@ func (p environProvider) MetadataLookupParams(region string) (*simplestreams.Met
 	}, nil
 }
 
+// ConfigSchema is specified in the environs.ConfigSchemaSource interface.
+func (environProvider) ConfigSchema() map[string]config.Attr {
+	return config.FieldsSchema(configFields, configDefaults, "access-key", "secret-key")
+}
+
 func (environProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	m := make(map[string]string)
 	ecfg, err := providerInstance.newConfig(cfg)
@@ -393,6 +398,7 @@ func (e *environ) SupportAddressAllocation(netId network.Id) (bool, error) {
 
 var unsupportedConstraints = []string{
 	constraints.Tags,
+	constraints.Zone,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -1083,6 +1089,119 @@ func (e *environ) terminateInstances(ids []instance.Id) error {
 	return firstErr
 }
 
+var _ environs.InstanceFirewallMigrator = (*environ)(nil)
+
+// EnsureInstanceFirewall implements environs.InstanceFirewallMigrator.
+// It adds the instance to its machine's security group, creating the
+// group if necessary. Only instances in a VPC can change security
+// groups after launch, so EC2-Classic instances cannot be migrated.
+func (e *environ) EnsureInstanceFirewall(machineId string, id instance.Id) error {
+	inst, err := e.vpcInstance(id)
+	if err != nil {
+		return err
+	}
+	name := e.machineGroupName(machineId)
+	group, err := e.ensureGroup(name, nil)
+	if err != nil {
+		return errors.Annotatef(err, "cannot create security group %q", name)
+	}
+	for _, g := range inst.SecurityGroups {
+		if g.Id == group.Id {
+			return nil
+		}
+	}
+	if err := e.setInstanceGroups(id, append(inst.SecurityGroups, group)); err != nil {
+		return err
+	}
+	logger.Infof("added instance %q to security group %s", id, name)
+	return nil
+}
+
+// RemoveGlobalFirewall implements environs.InstanceFirewallMigrator.
+func (e *environ) RemoveGlobalFirewall(ids []instance.Id) error {
+	name := e.globalGroupName()
+	for _, id := range ids {
+		inst, err := e.vpcInstance(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		var groups []ec2.SecurityGroup
+		for _, g := range inst.SecurityGroups {
+			if g.Name != name {
+				groups = append(groups, g)
+			}
+		}
+		if len(groups) == len(inst.SecurityGroups) {
+			continue
+		}
+		if err := e.setInstanceGroups(id, groups); err != nil {
+			return err
+		}
+		logger.Infof("removed instance %q from security group %s", id, name)
+	}
+	g, err := e.groupByName(name)
+	if ec2ErrCode(err) == "InvalidGroup.NotFound" {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot get security group %q", name)
+	}
+	if _, err := e.ec2().DeleteSecurityGroup(g); err != nil {
+		return errors.Annotatef(err, "cannot delete security group %q", name)
+	}
+	return nil
+}
+
+// HasGlobalFirewall implements environs.InstanceFirewallMigrator.
+func (e *environ) HasGlobalFirewall() (bool, error) {
+	name := e.globalGroupName()
+	_, err := e.groupByName(name)
+	if ec2ErrCode(err) == "InvalidGroup.NotFound" {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "cannot get security group %q", name)
+	}
+	return true, nil
+}
+
+// vpcInstance returns the instance with the given id, which must be in
+// a VPC so that its security groups can be changed.
+func (e *environ) vpcInstance(id instance.Id) (*ec2.Instance, error) {
+	resp, err := e.ec2().Instances([]string{string(id)}, nil)
+	if ec2ErrCode(err) == "InvalidInstanceID.NotFound" {
+		return nil, errors.NotFoundf("instance %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get instance %q", id)
+	}
+	for _, r := range resp.Reservations {
+		for i := range r.Instances {
+			inst := &r.Instances[i]
+			if inst.InstanceId != string(id) {
+				continue
+			}
+			if inst.VPCId == "" {
+				return nil, errors.NotSupportedf("changing the security groups of EC2-Classic instance %q", id)
+			}
+			return inst, nil
+		}
+	}
+	return nil, errors.NotFoundf("instance %q", id)
+}
+
+// setInstanceGroups replaces the security groups of the instance with
+// the given id.
+func (e *environ) setInstanceGroups(id instance.Id, groups []ec2.SecurityGroup) error {
+	req := &ec2.ModifyInstanceAttribute{
+		InstanceId:     string(id),
+		SecurityGroups: groups,
+	}
+	if _, err := e.ec2().ModifyInstanceAttribute(req, nil); err != nil {
+		return errors.Annotatef(err, "cannot change security groups of instance %q", id)
+	}
+	return nil
+}
+
 func (e *environ) globalGroupName() string {
 	return fmt.Sprintf("%s-global", e.jujuGroupName())
 }
@@ -143,6 +143,10 @@ func validateConfig(cfg, old *config.Config) (*environConfig, error) {
 	if err := config.Validate(cfg, old); err != nil {
 		return nil, err
 	}
+	if old != nil && old.FirewallMode() != cfg.FirewallMode() {
+		return nil, fmt.Errorf("cannot change firewall-mode from %q to %q",
+			old.FirewallMode(), cfg.FirewallMode())
+	}
 
 	newAttrs, err := cfg.ValidateUnknownAttrs(configFields, configDefaults)
 	if err != nil {
@@ -59,6 +59,7 @@ func (env *joyentEnviron) machineFullName(machineId string) string {
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Zone,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -68,6 +68,11 @@ func (joyentProvider) Validate(cfg, old *config.Config) (valid *config.Config, e
 	return cfg.Apply(newEcfg.attrs)
 }
 
+// ConfigSchema is specified in the environs.ConfigSchemaSource interface.
+func (joyentProvider) ConfigSchema() map[string]config.Attr {
+	return config.FieldsSchema(configFields, configDefaults, configSecretFields...)
+}
+
 func (joyentProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	// If you keep configSecretFields up to date, this method should Just Work.
 	ecfg, err := validateConfig(cfg, nil)
@@ -336,10 +336,9 @@ func (env *localEnviron) setLocalStorage() error {
 }
 
 var unsupportedConstraints = []string{
-	constraints.CpuCores,
-	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Zone,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -294,6 +294,11 @@ local:
 `[1:]
 }
 
+// ConfigSchema is specified in the environs.ConfigSchemaSource interface.
+func (environProvider) ConfigSchema() map[string]config.Attr {
+	return config.FieldsSchema(configFields, configDefaults)
+}
+
 // SecretAttrs implements environs.EnvironProvider.SecretAttrs.
 func (environProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	// don't have any secret attrs
@@ -734,10 +734,22 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 		}
 	}
 
-	// If no placement is specified, then automatically spread across
-	// the known zones for optimal spread across the instance distribution
-	// group.
-	if args.Placement == "" {
+	// A zone constraint restricts the node to the named zone; it must
+	// agree with any zone given by placement.
+	if zone := args.Constraints.Zone; zone != nil && *zone != "" {
+		if len(availabilityZones) > 0 && availabilityZones[0] != *zone {
+			return nil, nil, nil, errors.Errorf(
+				"placement zone %q conflicts with zone constraint %q",
+				availabilityZones[0], *zone,
+			)
+		}
+		availabilityZones = []string{*zone}
+	}
+
+	// If no placement or zone constraint is specified, then automatically
+	// spread across the known zones for optimal spread across the instance
+	// distribution group.
+	if args.Placement == "" && len(availabilityZones) == 0 {
 		var group []instance.Id
 		var err error
 		if args.DistributionGroup != nil {
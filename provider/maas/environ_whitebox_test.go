@@ -927,6 +927,29 @@ func (s *environSuite) TestStartInstanceAvailZoneUnknown(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid availability zone "test-unknown"`)
 }
 
+func (s *environSuite) TestStartInstanceZoneConstraint(c *gc.C) {
+	s.newNode(c, "thenode1", "host1", map[string]interface{}{"zone": "test-available"})
+	s.testMAASObject.TestServer.AddZone("test-available", "description")
+	env := s.bootstrap(c)
+	params := environs.StartInstanceParams{
+		Constraints: constraints.MustParse("zone=test-available"),
+	}
+	inst, _, _, err := testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(inst.(*maasInstance).zone(), gc.Equals, "test-available")
+}
+
+func (s *environSuite) TestStartInstanceZoneConstraintConflictsWithPlacement(c *gc.C) {
+	s.testMAASObject.TestServer.AddZone("zone1", "description")
+	env := s.bootstrap(c)
+	params := environs.StartInstanceParams{
+		Placement:   "zone=zone1",
+		Constraints: constraints.MustParse("zone=zone2"),
+	}
+	_, _, _, err := testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, `placement zone "zone1" conflicts with zone constraint "zone2"`)
+}
+
 func (s *environSuite) testStartInstanceAvailZone(c *gc.C, zone string) (instance.Instance, error) {
 	env := s.bootstrap(c)
 	params := environs.StartInstanceParams{Placement: "zone=" + zone}
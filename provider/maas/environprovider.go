@@ -120,6 +120,11 @@ func (maasEnvironProvider) BoilerplateConfig() string {
 	return boilerplateYAML
 }
 
+// ConfigSchema is specified in the environs.ConfigSchemaSource interface.
+func (maasEnvironProvider) ConfigSchema() map[string]config.Attr {
+	return config.FieldsSchema(configFields, configDefaults, "maas-oauth")
+}
+
 // SecretAttrs is specified in the EnvironProvider interface.
 func (prov maasEnvironProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	secretAttrs := make(map[string]string)
@@ -329,6 +329,7 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Zone,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -200,6 +200,11 @@ manual:
 `[1:]
 }
 
+// ConfigSchema is specified in the environs.ConfigSchemaSource interface.
+func (manualProvider) ConfigSchema() map[string]config.Attr {
+	return config.FieldsSchema(configFields, configDefaults, "storage-auth-key")
+}
+
 func (p manualProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	envConfig, err := p.validate(cfg, nil)
 	if err != nil {
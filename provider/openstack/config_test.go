@@ -54,6 +54,7 @@ type configTest struct {
 	useFloatingIP           bool
 	useDefaultSecurityGroup bool
 	network                 string
+	floatingIPPool          string
 	username                string
 	password                string
 	tenantName              string
@@ -153,6 +154,7 @@ func (t configTest) check(c *gc.C) {
 	c.Assert(ecfg.useFloatingIP(), gc.Equals, t.useFloatingIP)
 	c.Assert(ecfg.useDefaultSecurityGroup(), gc.Equals, t.useDefaultSecurityGroup)
 	c.Assert(ecfg.network(), gc.Equals, t.network)
+	c.Assert(ecfg.floatingIPPool(), gc.Equals, t.floatingIPPool)
 	// Default should be true
 	expectedHostnameVerification := true
 	if t.sslHostnameSet {
@@ -436,6 +438,14 @@ var configTests = []configTest{
 			"network": "a-network-label",
 		},
 		network: "a-network-label",
+	}, {
+		summary: "floating ip pool",
+		config: attrs{
+			"use-floating-ip":  true,
+			"floating-ip-pool": "ext-net",
+		},
+		useFloatingIP:  true,
+		floatingIPPool: "ext-net",
 	},
 }
 
@@ -91,6 +91,13 @@ openstack:
     #
     # network: <your network label or uuid>
 
+    # floating-ip-pool restricts the floating IP addresses given to
+    # machines, when use-floating-ip is true, to those in the named
+    # pool. Addresses in the pool must be allocated to the tenant in
+    # advance. It may be omitted to use any pool.
+    #
+    # floating-ip-pool: <your floating IP pool name>
+
     # agent-metadata-url specifies the location of the Juju tools and
     # metadata. It defaults to the global public tools metadata
     # location https://streams.canonical.com/tools.
@@ -291,6 +298,11 @@ func (p environProvider) MetadataLookupParams(region string) (*simplestreams.Met
 	}, nil
 }
 
+// ConfigSchema is specified in the environs.ConfigSchemaSource interface.
+func (environProvider) ConfigSchema() map[string]config.Attr {
+	return config.FieldsSchema(configFields, configDefaults, "username", "password", "tenant-name")
+}
+
 func (p environProvider) SecretAttrs(cfg *config.Config) (map[string]string, error) {
 	m := make(map[string]string)
 	ecfg, err := providerInstance.newConfig(cfg)
@@ -581,6 +593,7 @@ func (e *environ) SupportAddressAllocation(netId network.Id) (bool, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.Zone,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -885,15 +898,22 @@ func (e *environ) resolveNetwork(networkName string) (string, error) {
 }
 
 // allocatePublicIP tries to find an available floating IP address, or
-// allocates a new one, returning it, or an error
+// allocates a new one, returning it, or an error. If floating-ip-pool
+// is set, only an available address in that pool is returned, since
+// new addresses cannot be allocated from a chosen pool.
 func (e *environ) allocatePublicIP() (*nova.FloatingIP, error) {
 	fips, err := e.nova().ListFloatingIPs()
 	if err != nil {
 		return nil, err
 	}
+	pool := e.ecfg().floatingIPPool()
 	var newfip *nova.FloatingIP
 	for _, fip := range fips {
 		newfip = &fip
+		if pool != "" && fip.Pool != pool {
+			newfip = nil
+			continue
+		}
 		if fip.InstanceId != nil && *fip.InstanceId != "" {
 			// unavailable, skip
 			newfip = nil
@@ -904,6 +924,9 @@ func (e *environ) allocatePublicIP() (*nova.FloatingIP, error) {
 			return newfip, nil
 		}
 	}
+	if newfip == nil && pool != "" {
+		return nil, fmt.Errorf("no unassigned floating IP address in pool %q", pool)
+	}
 	if newfip == nil {
 		// allocate a new IP and use it
 		newfip, err = e.nova().AllocateFloatingIP()
@@ -1304,6 +1327,69 @@ func (e *environ) Destroy() error {
 	return nil
 }
 
+var _ environs.InstanceFirewallMigrator = (*environ)(nil)
+
+// EnsureInstanceFirewall implements environs.InstanceFirewallMigrator.
+// It adds the instance to its machine's security group, creating the
+// group if necessary.
+func (e *environ) EnsureInstanceFirewall(machineId string, id instance.Id) error {
+	name := e.machineGroupName(machineId)
+	if _, err := e.ensureGroup(name, nil); err != nil {
+		return jujuerrors.Annotatef(err, "cannot create security group %q", name)
+	}
+	novaClient := e.nova()
+	groups, err := novaClient.GetServerSecurityGroups(string(id))
+	if err != nil {
+		return jujuerrors.Annotatef(err, "cannot get security groups of instance %q", id)
+	}
+	for _, group := range groups {
+		if group.Name == name {
+			return nil
+		}
+	}
+	if err := novaClient.AddServerSecurityGroup(string(id), name); err != nil {
+		return jujuerrors.Annotatef(err, "cannot add security group %q to instance %q", name, id)
+	}
+	logger.Infof("added instance %q to security group %s", id, name)
+	return nil
+}
+
+// RemoveGlobalFirewall implements environs.InstanceFirewallMigrator.
+func (e *environ) RemoveGlobalFirewall(ids []instance.Id) error {
+	name := e.globalGroupName()
+	novaClient := e.nova()
+	for _, id := range ids {
+		groups, err := novaClient.GetServerSecurityGroups(string(id))
+		if gooseerrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return jujuerrors.Annotatef(err, "cannot get security groups of instance %q", id)
+		}
+		for _, group := range groups {
+			if group.Name != name {
+				continue
+			}
+			if err := novaClient.RemoveServerSecurityGroup(string(id), name); err != nil {
+				return jujuerrors.Annotatef(err, "cannot remove security group %q from instance %q", name, id)
+			}
+			logger.Infof("removed instance %q from security group %s", id, name)
+		}
+	}
+	return e.deleteSecurityGroups([]string{name})
+}
+
+// HasGlobalFirewall implements environs.InstanceFirewallMigrator.
+func (e *environ) HasGlobalFirewall() (bool, error) {
+	name := e.globalGroupName()
+	_, err := e.nova().SecurityGroupByName(name)
+	if gooseerrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, jujuerrors.Annotatef(err, "cannot get security group %q", name)
+	}
+	return true, nil
+}
+
 func (e *environ) globalGroupName() string {
 	return fmt.Sprintf("%s-global", e.jujuGroupName())
 }
@@ -423,6 +423,10 @@ type MemberStatus struct {
 	// between the remote member and the local instance.  It is zero for the
 	// member that the session is connected to.
 	Ping time.Duration `bson:"pingMS"`
+
+	// OptimeDate holds the time of the last operation that the member
+	// applied from the oplog.
+	OptimeDate time.Time `bson:"optimeDate"`
 }
 
 // MemberState represents the state of a replica set member.
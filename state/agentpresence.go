@@ -0,0 +1,126 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state/presence"
+)
+
+// AgentPresence reports that an agent is alive for as long as it is
+// not stopped. The presence of all the agents running on a machine
+// that are started through the same State is reported by a single
+// shared pinger, so that the number of presence updates written
+// does not grow with the number of units on each machine.
+type AgentPresence struct {
+	st        *State
+	machineId string
+	key       string
+}
+
+// Stop stops reporting that the agent is alive, and reports
+// immediately that it is dead unless another AgentPresence
+// for the same agent is still running.
+func (p *AgentPresence) Stop() error {
+	return p.st.agentPingers.stop(p.machineId, p.key)
+}
+
+// agentPingers holds the presence pingers shared by the agents
+// on each machine.
+type agentPingers struct {
+	mu       sync.Mutex
+	machines map[string]*sharedPinger
+}
+
+// sharedPinger holds a pinger and counts the users of each of the
+// keys it reports.
+type sharedPinger struct {
+	pinger *presence.Pinger
+	refs   map[string]int
+}
+
+func (ps *agentPingers) start(st *State, machineId, key string) (*AgentPresence, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.machines == nil {
+		ps.machines = make(map[string]*sharedPinger)
+	}
+	shared := ps.machines[machineId]
+	switch {
+	case shared == nil:
+		pinger := presence.NewPinger(st.getPresence(), key)
+		if err := pinger.Start(); err != nil {
+			return nil, err
+		}
+		ps.machines[machineId] = &sharedPinger{
+			pinger: pinger,
+			refs:   map[string]int{key: 1},
+		}
+	case shared.refs[key] == 0:
+		if err := shared.pinger.Add(key); err != nil {
+			return nil, err
+		}
+		shared.refs[key] = 1
+	default:
+		shared.refs[key]++
+	}
+	return &AgentPresence{st: st, machineId: machineId, key: key}, nil
+}
+
+func (ps *agentPingers) stop(machineId, key string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	shared := ps.machines[machineId]
+	if shared == nil || shared.refs[key] == 0 {
+		return nil
+	}
+	if shared.refs[key]--; shared.refs[key] > 0 {
+		return nil
+	}
+	delete(shared.refs, key)
+	if len(shared.refs) > 0 {
+		return shared.pinger.Remove(key)
+	}
+	delete(ps.machines, machineId)
+	if err := shared.pinger.Stop(); err != nil {
+		return err
+	}
+	return shared.pinger.Kill()
+}
+
+// StartAgentPresence starts reporting that the agent for machine m
+// is alive, using the pinger shared by the agents on the machine.
+func (m *Machine) StartAgentPresence() (*AgentPresence, error) {
+	p, err := m.st.agentPingers.start(m.st, m.Id(), m.globalKey())
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot start presence for machine %v", m)
+	}
+	// As in SetAgentPresence, sync so that commands run immediately
+	// after bootstrap see the state server as alive.
+	if m.IsManager() {
+		m.st.pwatcher.Sync()
+	}
+	return p, nil
+}
+
+// StartAgentPresence starts reporting that the agent for unit u is
+// alive, using the pinger shared by the agents on the unit's machine.
+func (u *Unit) StartAgentPresence() (*AgentPresence, error) {
+	machineId, err := u.AssignedMachineId()
+	if IsNotAssigned(err) {
+		// The unit's agent is not running on any machine we
+		// know of, so it does not share a pinger.
+		machineId = "#" + u.globalKey()
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot start presence for unit %q", u)
+	}
+	p, err := u.st.agentPingers.start(u.st, machineId, u.globalKey())
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot start presence for unit %q", u)
+	}
+	return p, nil
+}
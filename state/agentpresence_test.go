@@ -0,0 +1,90 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type AgentPresenceSuite struct {
+	ConnSuite
+	machine *state.Machine
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&AgentPresenceSuite{})
+
+func (s *AgentPresenceSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.unit, err = service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = s.unit.AssignToMachine(s.machine)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *AgentPresenceSuite) assertAlive(c *gc.C, entity interface {
+	AgentPresence() (bool, error)
+}, expect bool) {
+	s.State.StartSync()
+	alive, err := entity.AgentPresence()
+	c.Assert(err, gc.IsNil)
+	c.Assert(alive, gc.Equals, expect)
+}
+
+func (s *AgentPresenceSuite) TestSharedPinger(c *gc.C) {
+	machinePresence, err := s.machine.StartAgentPresence()
+	c.Assert(err, gc.IsNil)
+	unitPresence, err := s.unit.StartAgentPresence()
+	c.Assert(err, gc.IsNil)
+	s.assertAlive(c, s.machine, true)
+	s.assertAlive(c, s.unit, true)
+	c.Assert(state.AgentPingerKeys(s.State, s.machine.Id()), gc.DeepEquals, []string{
+		"m#" + s.machine.Id(), "u#" + s.unit.Name(),
+	})
+
+	// Stopping one agent's presence leaves the others alive.
+	err = machinePresence.Stop()
+	c.Assert(err, gc.IsNil)
+	s.assertAlive(c, s.machine, false)
+	s.assertAlive(c, s.unit, true)
+
+	// The pinger is stopped along with the last agent's presence.
+	err = unitPresence.Stop()
+	c.Assert(err, gc.IsNil)
+	s.assertAlive(c, s.unit, false)
+	c.Assert(state.AgentPingerKeys(s.State, s.machine.Id()), gc.IsNil)
+}
+
+func (s *AgentPresenceSuite) TestSameAgentTwice(c *gc.C) {
+	p1, err := s.unit.StartAgentPresence()
+	c.Assert(err, gc.IsNil)
+	p2, err := s.unit.StartAgentPresence()
+	c.Assert(err, gc.IsNil)
+	s.assertAlive(c, s.unit, true)
+
+	// The agent stays alive while any of its connections remain.
+	err = p1.Stop()
+	c.Assert(err, gc.IsNil)
+	s.assertAlive(c, s.unit, true)
+
+	err = p2.Stop()
+	c.Assert(err, gc.IsNil)
+	s.assertAlive(c, s.unit, false)
+}
+
+func (s *AgentPresenceSuite) TestUnassignedUnit(c *gc.C) {
+	err := s.unit.UnassignFromMachine()
+	c.Assert(err, gc.IsNil)
+	p, err := s.unit.StartAgentPresence()
+	c.Assert(err, gc.IsNil)
+	defer p.Stop()
+	s.assertAlive(c, s.unit, true)
+	c.Assert(state.AgentPingerKeys(s.State, s.machine.Id()), gc.IsNil)
+}
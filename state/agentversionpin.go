@@ -0,0 +1,219 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/version"
+)
+
+// AgentVersionPinner is implemented by entities whose agents can be
+// held at an earlier agent version than the rest of the environment.
+type AgentVersionPinner interface {
+	Entity
+	SetAgentVersionPin(v version.Number) error
+	ClearAgentVersionPin() error
+	AgentVersionPin() (version.Number, bool, error)
+}
+
+var (
+	_ AgentVersionPinner = (*Machine)(nil)
+	_ AgentVersionPinner = (*Service)(nil)
+)
+
+// agentVersionPinDoc records the agent version that the agents of a
+// machine, or of the units of a service, are held at while the rest of
+// the environment is upgraded.
+type agentVersionPinDoc struct {
+	DocID   string         `bson:"_id"`
+	EnvUUID string         `bson:"env-uuid"`
+	Version version.Number `bson:"version"`
+}
+
+// SetAgentVersionPin holds the agents of the machine at no later than
+// the given version, regardless of the environment's agent-version.
+// Pins cannot downgrade agents, so v must be no earlier than the
+// version the machine agent is running.
+func (m *Machine) SetAgentVersionPin(v version.Number) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot pin agent version for machine %v", m)
+	if err := checkPinNotBelowAgent(m, v); err != nil {
+		return err
+	}
+	return setAgentVersionPin(m.st, machinesC, m.doc.DocID, m.globalKey(), v)
+}
+
+// ClearAgentVersionPin removes any agent version pin from the machine.
+func (m *Machine) ClearAgentVersionPin() error {
+	return clearAgentVersionPin(m.st, m.globalKey())
+}
+
+// AgentVersionPin returns the agent version the machine is pinned at,
+// and whether it is pinned at all.
+func (m *Machine) AgentVersionPin() (version.Number, bool, error) {
+	return readAgentVersionPin(m.st, m.globalKey())
+}
+
+// EffectiveAgentVersionPin returns the agent version the machine's
+// agents must be held at: the lowest of the machine's own pin and the
+// pins of the services of all units assigned to the machine. The
+// boolean result reports whether any pin applies.
+func (m *Machine) EffectiveAgentVersionPin() (version.Number, bool, error) {
+	pinned, ok, err := m.AgentVersionPin()
+	if err != nil {
+		return version.Number{}, false, err
+	}
+	units, err := m.Units()
+	if err != nil {
+		return version.Number{}, false, err
+	}
+	seen := make(map[string]bool)
+	for _, unit := range units {
+		serviceName := unit.ServiceName()
+		if seen[serviceName] {
+			continue
+		}
+		seen[serviceName] = true
+		v, found, err := readAgentVersionPin(m.st, serviceGlobalKey(serviceName))
+		if err != nil {
+			return version.Number{}, false, err
+		}
+		if found && (!ok || v.Compare(pinned) < 0) {
+			pinned, ok = v, true
+		}
+	}
+	return pinned, ok, nil
+}
+
+// SetAgentVersionPin holds the agents of all the service's units, and
+// thus the machines they are assigned to, at no later than the given
+// version, regardless of the environment's agent-version. Pins cannot
+// downgrade agents, so v must be no earlier than the version any of
+// those machines' agents is running.
+func (s *Service) SetAgentVersionPin(v version.Number) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot pin agent version for service %q", s)
+	units, err := s.AllUnits()
+	if err != nil {
+		return err
+	}
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		machine, err := s.st.Machine(machineId)
+		if err != nil {
+			return err
+		}
+		if err := checkPinNotBelowAgent(machine, v); err != nil {
+			return err
+		}
+	}
+	return setAgentVersionPin(s.st, servicesC, s.doc.DocID, s.globalKey(), v)
+}
+
+// ClearAgentVersionPin removes any agent version pin from the service.
+func (s *Service) ClearAgentVersionPin() error {
+	return clearAgentVersionPin(s.st, s.globalKey())
+}
+
+// AgentVersionPin returns the agent version the service is pinned at,
+// and whether it is pinned at all.
+func (s *Service) AgentVersionPin() (version.Number, bool, error) {
+	return readAgentVersionPin(s.st, s.globalKey())
+}
+
+// checkPinNotBelowAgent returns an error if the machine's agent is
+// already running a version later than v.
+func checkPinNotBelowAgent(m *Machine, v version.Number) error {
+	tools, err := m.AgentTools()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if tools.Version.Number.Compare(v) > 0 {
+		return errors.Errorf("machine %v is already running agent version %s", m, tools.Version.Number)
+	}
+	return nil
+}
+
+func setAgentVersionPin(st *State, entityColl, entityId, key string, v version.Number) error {
+	if v == version.Zero {
+		return errors.New("agent version must be specified")
+	}
+	doc := agentVersionPinDoc{
+		DocID:   st.docID(key),
+		EnvUUID: st.EnvironTag().Id(),
+		Version: v,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if alive, err := isAlive(st.db, entityColl, entityId); err != nil {
+			return nil, err
+		} else if !alive {
+			return nil, errors.New("entity is no longer alive")
+		}
+		_, pinned, err := readAgentVersionPin(st, key)
+		if err != nil {
+			return nil, err
+		}
+		pinOp := txn.Op{
+			C:      agentVersionPinsC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}
+		if pinned {
+			pinOp = txn.Op{
+				C:      agentVersionPinsC,
+				Id:     doc.DocID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"version", v}}}},
+			}
+		}
+		return []txn.Op{{
+			C:      entityColl,
+			Id:     entityId,
+			Assert: isAliveDoc,
+		}, pinOp}, nil
+	}
+	return st.run(buildTxn)
+}
+
+func clearAgentVersionPin(st *State, key string) error {
+	ops := []txn.Op{removeAgentVersionPinOp(st, key)}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotate(err, "cannot clear agent version pin")
+	}
+	return nil
+}
+
+func readAgentVersionPin(st *State, key string) (version.Number, bool, error) {
+	pins, closer := st.getCollection(agentVersionPinsC)
+	defer closer()
+
+	var doc agentVersionPinDoc
+	if err := pins.FindId(st.docID(key)).One(&doc); err == mgo.ErrNotFound {
+		return version.Number{}, false, nil
+	} else if err != nil {
+		return version.Number{}, false, errors.Annotate(err, "cannot read agent version pin")
+	}
+	return doc.Version, true, nil
+}
+
+// removeAgentVersionPinOp returns the operation required to remove the
+// agent version pin for the entity with the given global key. It is a
+// no-op if the entity is not pinned.
+func removeAgentVersionPinOp(st *State, key string) txn.Op {
+	return txn.Op{
+		C:      agentVersionPinsC,
+		Id:     st.docID(key),
+		Remove: true,
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+type AgentVersionPinSuite struct {
+	ConnSuite
+	machine *state.Machine
+	service *state.Service
+}
+
+var _ = gc.Suite(&AgentVersionPinSuite{})
+
+func (s *AgentVersionPinSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *AgentVersionPinSuite) assertEffectivePin(c *gc.C, expected version.Number, expectedOk bool) {
+	v, ok, err := s.machine.EffectiveAgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, gc.Equals, expectedOk)
+	c.Assert(v, gc.Equals, expected)
+}
+
+func (s *AgentVersionPinSuite) TestMachinePin(c *gc.C) {
+	_, ok, err := s.machine.AgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, jc.IsFalse)
+
+	err = s.machine.SetAgentVersionPin(version.MustParse("1.2.3"))
+	c.Assert(err, gc.IsNil)
+	err = s.machine.SetAgentVersionPin(version.MustParse("1.2.4"))
+	c.Assert(err, gc.IsNil)
+	v, ok, err := s.machine.AgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(v, gc.Equals, version.MustParse("1.2.4"))
+
+	err = s.machine.ClearAgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	_, ok, err = s.machine.AgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, jc.IsFalse)
+
+	// Clearing an absent pin is not an error.
+	err = s.machine.ClearAgentVersionPin()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *AgentVersionPinSuite) TestPinBelowRunningVersionRefused(c *gc.C) {
+	err := s.machine.SetAgentVersion(version.MustParseBinary("1.2.3-quantal-amd64"))
+	c.Assert(err, gc.IsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, gc.IsNil)
+
+	err = s.machine.SetAgentVersionPin(version.MustParse("1.2.2"))
+	c.Assert(err, gc.ErrorMatches, `cannot pin agent version for machine 0: machine 0 is already running agent version 1.2.3`)
+	err = s.service.SetAgentVersionPin(version.MustParse("1.2.2"))
+	c.Assert(err, gc.ErrorMatches, `cannot pin agent version for service "wordpress": machine 0 is already running agent version 1.2.3`)
+
+	err = s.machine.SetAgentVersionPin(version.MustParse("1.2.3"))
+	c.Assert(err, gc.IsNil)
+	err = s.service.SetAgentVersionPin(version.MustParse("1.2.3"))
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *AgentVersionPinSuite) TestSetZeroPin(c *gc.C) {
+	err := s.service.SetAgentVersionPin(version.Zero)
+	c.Assert(err, gc.ErrorMatches, `cannot pin agent version for service "wordpress": agent version must be specified`)
+}
+
+func (s *AgentVersionPinSuite) TestSetPinDyingService(c *gc.C) {
+	_, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = s.service.Destroy()
+	c.Assert(err, gc.IsNil)
+	err = s.service.SetAgentVersionPin(version.MustParse("1.2.3"))
+	c.Assert(err, gc.ErrorMatches, `cannot pin agent version for service "wordpress": entity is no longer alive`)
+}
+
+func (s *AgentVersionPinSuite) TestEffectivePin(c *gc.C) {
+	s.assertEffectivePin(c, version.Zero, false)
+
+	unit, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(s.machine)
+	c.Assert(err, gc.IsNil)
+
+	err = s.service.SetAgentVersionPin(version.MustParse("1.2.3"))
+	c.Assert(err, gc.IsNil)
+	s.assertEffectivePin(c, version.MustParse("1.2.3"), true)
+
+	// The lowest applicable pin wins.
+	err = s.machine.SetAgentVersionPin(version.MustParse("1.3.0"))
+	c.Assert(err, gc.IsNil)
+	s.assertEffectivePin(c, version.MustParse("1.2.3"), true)
+	err = s.machine.SetAgentVersionPin(version.MustParse("1.1.0"))
+	c.Assert(err, gc.IsNil)
+	s.assertEffectivePin(c, version.MustParse("1.1.0"), true)
+
+	err = s.machine.ClearAgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	err = s.service.ClearAgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	s.assertEffectivePin(c, version.Zero, false)
+}
+
+func (s *AgentVersionPinSuite) TestRemovingMachineRemovesPin(c *gc.C) {
+	err := s.machine.SetAgentVersionPin(version.MustParse("1.2.3"))
+	c.Assert(err, gc.IsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.machine.Remove()
+	c.Assert(err, gc.IsNil)
+
+	// The pin is removed along with the machine.
+	_, ok, err := s.machine.AgentVersionPin()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, jc.IsFalse)
+}
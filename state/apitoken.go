@@ -0,0 +1,257 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// APITokenPrefix starts every API token, so that tokens can be told
+// apart from passwords when a user logs in.
+const APITokenPrefix = "juju-token:"
+
+// APIToken is a long-lived credential with which a user may log in to
+// the API of one environment instead of with their password. A token's
+// scope limits what may be done with it: a read token gives read-only
+// access to the environment whatever the user's own access. Tokens
+// expire, and are removed along with their user.
+type APIToken struct {
+	st  *State
+	doc apiTokenDoc
+}
+
+type apiTokenDoc struct {
+	Id          string            `bson:"_id"`
+	EnvUUID     string            `bson:"env-uuid"`
+	User        string            `bson:"user"`
+	Scope       EnvironmentAccess `bson:"scope"`
+	Description string            `bson:"description,omitempty"`
+	SecretHash  string            `bson:"secrethash"`
+	SecretSalt  string            `bson:"secretsalt"`
+	DateCreated time.Time         `bson:"datecreated"`
+	Expires     time.Time         `bson:"expires"`
+}
+
+// Id returns the token's identifier, which is not secret.
+func (t *APIToken) Id() string {
+	return t.doc.Id
+}
+
+// UserTag returns the tag of the user that the token logs in as.
+func (t *APIToken) UserTag() names.UserTag {
+	return names.NewLocalUserTag(t.doc.User)
+}
+
+// Scope returns the access that the token grants.
+func (t *APIToken) Scope() EnvironmentAccess {
+	return t.doc.Scope
+}
+
+// Description returns the description given when the token was made.
+func (t *APIToken) Description() string {
+	return t.doc.Description
+}
+
+// DateCreated returns when the token was made.
+func (t *APIToken) DateCreated() time.Time {
+	return t.doc.DateCreated
+}
+
+// Expires returns when the token stops being valid.
+func (t *APIToken) Expires() time.Time {
+	return t.doc.Expires
+}
+
+// ParseAPIToken splits an API token into its identifier and its
+// secret. It returns false if s is not an API token.
+func ParseAPIToken(s string) (id, secret string, ok bool) {
+	if !strings.HasPrefix(s, APITokenPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(s, APITokenPrefix), ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// AddAPIToken makes a new API token with the given scope for the named
+// user, valid in st's environment for the given duration. The token
+// itself is returned along with its record; it cannot be retrieved
+// again later.
+func (st *State) AddAPIToken(user names.UserTag, scope EnvironmentAccess, description string, validFor time.Duration) (*APIToken, string, error) {
+	if err := scope.Validate(); err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if validFor <= 0 {
+		return nil, "", errors.New("API token validity must be positive")
+	}
+	if !user.IsLocal() {
+		return nil, "", errors.Errorf("cannot make API token for remote user %q", user.Username())
+	}
+	idBytes, err := utils.RandomBytes(8)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	secret, err := utils.RandomPassword()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	salt, err := utils.RandomSalt()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	now := nowToTheSecond()
+	token := &APIToken{
+		st: st,
+		doc: apiTokenDoc{
+			Id:          fmt.Sprintf("%x", idBytes),
+			EnvUUID:     st.EnvironTag().Id(),
+			User:        user.Name(),
+			Scope:       scope,
+			Description: description,
+			SecretHash:  utils.UserPasswordHash(secret, salt),
+			SecretSalt:  salt,
+			DateCreated: now,
+			Expires:     now.Add(validFor),
+		},
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     user.Name(),
+		Assert: txn.DocExists,
+	}, {
+		C:      apiTokensC,
+		Id:     token.doc.Id,
+		Assert: txn.DocMissing,
+		Insert: &token.doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if _, err := st.User(user); err != nil {
+			return nil, "", errors.Trace(err)
+		}
+		return nil, "", errors.New("token id already in use")
+	} else if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return token, APITokenPrefix + token.doc.Id + "." + secret, nil
+}
+
+// APIToken returns the API token with the given id.
+func (st *State) APIToken(id string) (*APIToken, error) {
+	tokens, closer := st.getCollection(apiTokensC)
+	defer closer()
+
+	var doc apiTokenDoc
+	err := tokens.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("API token %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get API token %q", id)
+	}
+	return &APIToken{st: st, doc: doc}, nil
+}
+
+// APITokens returns the API tokens of the given user for st's
+// environment, oldest first, including those that have expired.
+func (st *State) APITokens(user names.UserTag) ([]*APIToken, error) {
+	tokens, closer := st.getCollection(apiTokensC)
+	defer closer()
+
+	var docs []apiTokenDoc
+	query := bson.D{{"env-uuid", st.EnvironTag().Id()}, {"user", user.Name()}}
+	err := tokens.Find(query).Sort("datecreated", "_id").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get API tokens of user %q", user.Name())
+	}
+	result := make([]*APIToken, len(docs))
+	for i, doc := range docs {
+		result[i] = &APIToken{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// AuthenticateAPIToken returns the API token that s is the secret form
+// of. It returns an error satisfying errors.IsUnauthorized if s is not
+// a valid token for st's environment.
+func (st *State) AuthenticateAPIToken(s string) (*APIToken, error) {
+	id, secret, ok := ParseAPIToken(s)
+	if !ok {
+		return nil, errors.Unauthorizedf("invalid API token")
+	}
+	token, err := st.validAPIToken(id)
+	if err != nil {
+		return nil, err
+	}
+	if utils.UserPasswordHash(secret, token.doc.SecretSalt) != token.doc.SecretHash {
+		return nil, errors.Unauthorizedf("invalid API token")
+	}
+	return token, nil
+}
+
+// CheckAPIToken returns an error satisfying errors.IsUnauthorized if
+// the API token with the given id has been revoked or has expired, or
+// is not for st's environment. It allows connections made with a
+// token to be cut off once the token is no longer valid.
+func (st *State) CheckAPIToken(id string) error {
+	_, err := st.validAPIToken(id)
+	return err
+}
+
+func (st *State) validAPIToken(id string) (*APIToken, error) {
+	token, err := st.APIToken(id)
+	if errors.IsNotFound(err) {
+		return nil, errors.Unauthorizedf("invalid API token")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if token.doc.EnvUUID != st.EnvironTag().Id() {
+		return nil, errors.Unauthorizedf("invalid API token")
+	}
+	if !nowToTheSecond().Before(token.doc.Expires) {
+		return nil, errors.Unauthorizedf("API token has expired")
+	}
+	return token, nil
+}
+
+// Remove revokes the token, so that it can no longer be used to log
+// in. Removing a token that has already been removed is not an error.
+func (t *APIToken) Remove() error {
+	ops := []txn.Op{{
+		C:      apiTokensC,
+		Id:     t.doc.Id,
+		Remove: true,
+	}}
+	return onAbort(t.st.runTransaction(ops), nil)
+}
+
+// removeAPITokensOps returns the operations that remove all the API
+// tokens of the given user, in every environment.
+func removeAPITokensOps(st *State, user string) ([]txn.Op, error) {
+	tokens, closer := st.getCollection(apiTokensC)
+	defer closer()
+
+	var docs []apiTokenDoc
+	if err := tokens.Find(bson.D{{"user", user}}).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get API tokens of user %q", user)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      apiTokensC,
+			Id:     doc.Id,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type APITokenSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&APITokenSuite{})
+
+func (s *APITokenSuite) TestAddAndAuthenticate(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	token, secret, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentReadAccess, "status checks", time.Hour)
+	c.Assert(err, gc.IsNil)
+	c.Assert(token.UserTag(), gc.Equals, user.UserTag())
+	c.Assert(token.Scope(), gc.Equals, state.EnvironmentReadAccess)
+	c.Assert(token.Description(), gc.Equals, "status checks")
+	c.Assert(token.Expires(), gc.Equals, token.DateCreated().Add(time.Hour))
+	c.Assert(strings.HasPrefix(secret, state.APITokenPrefix+token.Id()+"."), jc.IsTrue)
+
+	id, _, ok := state.ParseAPIToken(secret)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(id, gc.Equals, token.Id())
+
+	found, err := s.State.AuthenticateAPIToken(secret)
+	c.Assert(err, gc.IsNil)
+	c.Assert(found.Id(), gc.Equals, token.Id())
+
+	_, err = s.State.AuthenticateAPIToken(secret + "x")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	_, err = s.State.AuthenticateAPIToken("not-a-token")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *APITokenSuite) TestAddInvalidScope(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	_, _, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentAccess("admin"), "", time.Hour)
+	c.Assert(err, gc.ErrorMatches, `.*"admin".*`)
+}
+
+func (s *APITokenSuite) TestAddUnknownUser(c *gc.C) {
+	_, _, err := s.State.AddAPIToken(names.NewLocalUserTag("nobody"), state.EnvironmentReadAccess, "", time.Hour)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *APITokenSuite) TestListAndRemove(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	other := s.factory.MakeUser(c, &factory.UserParams{Name: "other"})
+	token1, _, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentReadAccess, "", time.Hour)
+	c.Assert(err, gc.IsNil)
+	token2, secret2, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentWriteAccess, "", time.Hour)
+	c.Assert(err, gc.IsNil)
+	_, _, err = s.State.AddAPIToken(other.UserTag(), state.EnvironmentReadAccess, "", time.Hour)
+	c.Assert(err, gc.IsNil)
+
+	tokens, err := s.State.APITokens(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(tokens, gc.HasLen, 2)
+	ids := []string{tokens[0].Id(), tokens[1].Id()}
+	c.Assert(ids, jc.SameContents, []string{token1.Id(), token2.Id()})
+
+	err = token2.Remove()
+	c.Assert(err, gc.IsNil)
+	err = token2.Remove()
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.State.APIToken(token2.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.AuthenticateAPIToken(secret2)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	tokens, err = s.State.APITokens(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(tokens, gc.HasLen, 1)
+}
+
+func (s *APITokenSuite) TestAddInvalidValidity(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	_, _, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentReadAccess, "", 0)
+	c.Assert(err, gc.ErrorMatches, "API token validity must be positive")
+}
+
+func (s *APITokenSuite) TestExpiredTokenRefused(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	token, secret, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentReadAccess, "", time.Hour)
+	c.Assert(err, gc.IsNil)
+	state.SetAPITokenExpires(c, s.State, token.Id(), time.Now().Add(-time.Minute))
+
+	_, err = s.State.AuthenticateAPIToken(secret)
+	c.Assert(err, gc.ErrorMatches, "API token has expired")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	err = s.State.CheckAPIToken(token.Id())
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+
+	// Expired tokens are still listed, so that they can be revoked.
+	tokens, err := s.State.APITokens(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(tokens, gc.HasLen, 1)
+}
+
+func (s *APITokenSuite) TestTokenScopedToEnvironment(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	token, secret, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentWriteAccess, "", time.Hour)
+	c.Assert(err, gc.IsNil)
+
+	uuid, err := utils.NewUUID()
+	c.Assert(err, gc.IsNil)
+	envTag := names.NewEnvironTag(uuid.String())
+	_, err = s.State.NewEnvironment(envTag, s.envTag, s.owner, "other")
+	c.Assert(err, gc.IsNil)
+	st, err := s.State.ForEnviron(envTag)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	_, err = st.AuthenticateAPIToken(secret)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	err = st.CheckAPIToken(token.Id())
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	tokens, err := st.APITokens(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(tokens, gc.HasLen, 0)
+}
+
+func (s *APITokenSuite) TestRemoveUserRemovesTokens(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	token, secret, err := s.State.AddAPIToken(user.UserTag(), state.EnvironmentWriteAccess, "", time.Hour)
+	c.Assert(err, gc.IsNil)
+
+	err = user.Remove()
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.APIToken(token.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// A new user with the same name does not inherit the token.
+	s.factory.MakeUser(c, &factory.UserParams{Name: "ci"})
+	_, err = s.State.AuthenticateAPIToken(secret)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
@@ -0,0 +1,338 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// BundleDeploymentStatus describes how far a bundle deployment has got.
+type BundleDeploymentStatus string
+
+const (
+	// BundleDeploymentPending means that the deployment has been
+	// accepted, but no changes have been made yet.
+	BundleDeploymentPending BundleDeploymentStatus = "pending"
+
+	// BundleDeploymentRunning means that the deployment is making
+	// changes to the environment. A deployment found running when
+	// the bundle deployer starts was interrupted, and is rolled back.
+	BundleDeploymentRunning BundleDeploymentStatus = "running"
+
+	// BundleDeploymentDone means that every change was made.
+	BundleDeploymentDone BundleDeploymentStatus = "done"
+
+	// BundleDeploymentFailed means that the deployment failed, and the
+	// changes it made have been undone.
+	BundleDeploymentFailed BundleDeploymentStatus = "failed"
+)
+
+// BundleService holds the checked parameters for deploying one
+// service of a bundle.
+type BundleService struct {
+	Name        string   `bson:"name"`
+	CharmURL    string   `bson:"charmurl"`
+	ConfigYAML  string   `bson:"configyaml,omitempty"`
+	Constraints string   `bson:"constraints,omitempty"`
+	Expose      bool     `bson:"expose,omitempty"`
+	NumUnits    int      `bson:"numunits"`
+	To          []string `bson:"to,omitempty"`
+}
+
+// BundlePlan holds everything needed to deploy a bundle, once its
+// charms have been added to the environment and it has been checked.
+type BundlePlan struct {
+	Services  []BundleService `bson:"services"`
+	Relations [][]string      `bson:"relations,omitempty"`
+}
+
+// BundleChange records a single change made while deploying a bundle.
+type BundleChange struct {
+	Kind string `bson:"kind"`
+	Id   string `bson:"id"`
+}
+
+type bundleDeploymentDoc struct {
+	DocID            string                 `bson:"_id"`
+	Id               string                 `bson:"id"`
+	EnvUUID          string                 `bson:"env-uuid"`
+	Owner            string                 `bson:"owner"`
+	Plan             BundlePlan             `bson:"plan"`
+	Status           BundleDeploymentStatus `bson:"status"`
+	Changes          []BundleChange         `bson:"changes"`
+	ExistingMachines []string               `bson:"existingmachines"`
+	Error            string                 `bson:"error,omitempty"`
+	Created          time.Time              `bson:"created"`
+}
+
+// BundleDeployment is a bundle deployment that is run by the bundle
+// deployer on the state server, so that it completes, or is undone,
+// whatever happens to the client that asked for it.
+type BundleDeployment struct {
+	st  *State
+	doc bundleDeploymentDoc
+}
+
+// Id returns the deployment's id.
+func (d *BundleDeployment) Id() string {
+	return d.doc.Id
+}
+
+// Owner returns the tag of the user that asked for the deployment.
+func (d *BundleDeployment) Owner() names.UserTag {
+	return names.NewUserTag(d.doc.Owner)
+}
+
+// Plan returns what the deployment deploys.
+func (d *BundleDeployment) Plan() BundlePlan {
+	return d.doc.Plan
+}
+
+// Status returns how far the deployment has got.
+func (d *BundleDeployment) Status() BundleDeploymentStatus {
+	return d.doc.Status
+}
+
+// Changes returns the changes made so far, in the order in which they
+// were made.
+func (d *BundleDeployment) Changes() []BundleChange {
+	return d.doc.Changes
+}
+
+// ExistingMachines returns the ids of the machines in the environment
+// when the deployment started. Any other machine hosting the bundle's
+// units was added for it, and is removed if the deployment fails.
+func (d *BundleDeployment) ExistingMachines() []string {
+	return d.doc.ExistingMachines
+}
+
+// Error returns why the deployment failed.
+func (d *BundleDeployment) Error() string {
+	return d.doc.Error
+}
+
+// Created returns when the deployment was asked for.
+func (d *BundleDeployment) Created() time.Time {
+	return d.doc.Created
+}
+
+// AddBundleDeployment records a new, pending deployment of the given
+// plan on behalf of the given user.
+func (st *State) AddBundleDeployment(owner names.UserTag, plan BundlePlan) (*BundleDeployment, error) {
+	seq, err := st.sequence("bundledeployment")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id := strconv.Itoa(seq)
+	doc := bundleDeploymentDoc{
+		DocID:   st.docID(id),
+		Id:      id,
+		EnvUUID: st.EnvironTag().Id(),
+		Owner:   owner.Username(),
+		Plan:    plan,
+		Status:  BundleDeploymentPending,
+		Changes: []BundleChange{},
+		Created: nowToTheSecond(),
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := []txn.Op{env.assertAliveOp(), {
+		C:      bundleDeploymentsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.New("cannot add bundle deployment: environment is no longer alive")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot add bundle deployment")
+	}
+	return &BundleDeployment{st: st, doc: doc}, nil
+}
+
+// BundleDeployment returns the bundle deployment with the given id.
+func (st *State) BundleDeployment(id string) (*BundleDeployment, error) {
+	deployments, closer := st.getCollection(bundleDeploymentsC)
+	defer closer()
+
+	var doc bundleDeploymentDoc
+	err := deployments.FindId(st.docID(id)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("bundle deployment %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get bundle deployment %q", id)
+	}
+	return &BundleDeployment{st: st, doc: doc}, nil
+}
+
+// UnfinishedBundleDeployments returns the bundle deployments that are
+// pending or running, oldest first.
+func (st *State) UnfinishedBundleDeployments() ([]*BundleDeployment, error) {
+	deployments, closer := st.getCollection(bundleDeploymentsC)
+	defer closer()
+
+	var docs []bundleDeploymentDoc
+	query := bson.D{
+		{"env-uuid", st.EnvironTag().Id()},
+		{"status", bson.D{{"$in", []BundleDeploymentStatus{
+			BundleDeploymentPending,
+			BundleDeploymentRunning,
+		}}}},
+	}
+	if err := deployments.Find(query).Sort("created", "_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get bundle deployments")
+	}
+	result := make([]*BundleDeployment, len(docs))
+	for i, doc := range docs {
+		result[i] = &BundleDeployment{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// Refresh refreshes the contents of the deployment from the underlying
+// state.
+func (d *BundleDeployment) Refresh() error {
+	fresh, err := d.st.BundleDeployment(d.doc.Id)
+	if err != nil {
+		return err
+	}
+	d.doc = fresh.doc
+	return nil
+}
+
+// Watch returns a watcher for observing changes to the deployment.
+func (d *BundleDeployment) Watch() NotifyWatcher {
+	return newEntityWatcher(d.st, bundleDeploymentsC, d.doc.DocID)
+}
+
+// Start moves a pending deployment to running, recording the machines
+// that exist before it makes any changes. If any of the bundle's
+// services already exists, the deployment fails instead, since undoing
+// it would destroy a service that it did not deploy.
+func (d *BundleDeployment) Start() error {
+	var conflict string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := d.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if d.doc.Status != BundleDeploymentPending {
+			return nil, errors.Errorf("bundle deployment %q is %s, not pending", d.doc.Id, d.doc.Status)
+		}
+		conflict = ""
+		assertPending := bson.D{{"status", BundleDeploymentPending}}
+		var ops []txn.Op
+		for _, svc := range d.doc.Plan.Services {
+			if _, err := d.st.Service(svc.Name); err == nil {
+				conflict = svc.Name
+				return []txn.Op{{
+					C:      bundleDeploymentsC,
+					Id:     d.doc.DocID,
+					Assert: assertPending,
+					Update: bson.D{{"$set", bson.D{
+						{"status", BundleDeploymentFailed},
+						{"error", "service " + strconv.Quote(conflict) + " already exists"},
+					}}},
+				}}, nil
+			} else if !errors.IsNotFound(err) {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, txn.Op{
+				C:      servicesC,
+				Id:     d.st.docID(svc.Name),
+				Assert: txn.DocMissing,
+			})
+		}
+		machines, err := d.st.AllMachines()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		existing := make([]string, len(machines))
+		for i, m := range machines {
+			existing[i] = m.Id()
+		}
+		return append(ops, txn.Op{
+			C:      bundleDeploymentsC,
+			Id:     d.doc.DocID,
+			Assert: assertPending,
+			Update: bson.D{{"$set", bson.D{
+				{"status", BundleDeploymentRunning},
+				{"existingmachines", existing},
+			}}},
+		}), nil
+	}
+	if err := d.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot start bundle deployment %q", d.doc.Id)
+	}
+	if err := d.Refresh(); err != nil {
+		return errors.Trace(err)
+	}
+	if conflict != "" {
+		return errors.Errorf("cannot start bundle deployment %q: service %q already exists", d.doc.Id, conflict)
+	}
+	return nil
+}
+
+// RecordChange records a change made by a running deployment.
+func (d *BundleDeployment) RecordChange(kind, id string) error {
+	change := BundleChange{Kind: kind, Id: id}
+	ops := []txn.Op{{
+		C:      bundleDeploymentsC,
+		Id:     d.doc.DocID,
+		Assert: bson.D{{"status", BundleDeploymentRunning}},
+		Update: bson.D{{"$push", bson.D{{"changes", change}}}},
+	}}
+	if err := d.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot record change to bundle deployment %q: not running", d.doc.Id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot record change to bundle deployment %q", d.doc.Id)
+	}
+	d.doc.Changes = append(d.doc.Changes, change)
+	return nil
+}
+
+// Finish records that a running deployment has finished. If reason is
+// not nil, the deployment failed for that reason and its changes have
+// been undone.
+func (d *BundleDeployment) Finish(reason error) error {
+	status := BundleDeploymentDone
+	var message string
+	if reason != nil {
+		status = BundleDeploymentFailed
+		message = reason.Error()
+	}
+	ops := []txn.Op{{
+		C:      bundleDeploymentsC,
+		Id:     d.doc.DocID,
+		Assert: bson.D{{"status", BundleDeploymentRunning}},
+		Update: bson.D{{"$set", bson.D{
+			{"status", status},
+			{"error", message},
+		}}},
+	}}
+	if err := d.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot finish bundle deployment %q: not running", d.doc.Id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot finish bundle deployment %q", d.doc.Id)
+	}
+	d.doc.Status = status
+	d.doc.Error = message
+	return nil
+}
+
+// WatchBundleDeployments returns a NotifyWatcher that notifies when
+// bundle deployments are added or change.
+func (st *State) WatchBundleDeployments() NotifyWatcher {
+	return newCollectionWatcher(st, bundleDeploymentsC)
+}
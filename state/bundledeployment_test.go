@@ -0,0 +1,144 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type BundleDeploymentSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&BundleDeploymentSuite{})
+
+var testBundlePlan = state.BundlePlan{
+	Services: []state.BundleService{{
+		Name:     "wordpress",
+		CharmURL: "local:quantal/quantal-wordpress-3",
+		NumUnits: 1,
+	}},
+}
+
+func (s *BundleDeploymentSuite) addDeployment(c *gc.C) *state.BundleDeployment {
+	deployment, err := s.State.AddBundleDeployment(s.owner, testBundlePlan)
+	c.Assert(err, gc.IsNil)
+	return deployment
+}
+
+func (s *BundleDeploymentSuite) TestAddBundleDeployment(c *gc.C) {
+	deployment := s.addDeployment(c)
+	c.Assert(deployment.Owner(), gc.Equals, s.owner)
+	c.Assert(deployment.Status(), gc.Equals, state.BundleDeploymentPending)
+	c.Assert(deployment.Changes(), gc.HasLen, 0)
+
+	found, err := s.State.BundleDeployment(deployment.Id())
+	c.Assert(err, gc.IsNil)
+	c.Assert(found.Plan(), gc.DeepEquals, testBundlePlan)
+
+	other := s.addDeployment(c)
+	c.Assert(other.Id(), gc.Not(gc.Equals), deployment.Id())
+	unfinished, err := s.State.UnfinishedBundleDeployments()
+	c.Assert(err, gc.IsNil)
+	c.Assert(unfinished, gc.HasLen, 2)
+	c.Assert(unfinished[0].Id(), gc.Equals, deployment.Id())
+}
+
+func (s *BundleDeploymentSuite) TestBundleDeploymentNotFound(c *gc.C) {
+	_, err := s.State.BundleDeployment("42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *BundleDeploymentSuite) TestLifecycle(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	deployment := s.addDeployment(c)
+
+	err = deployment.RecordChange("deploy", "wordpress")
+	c.Assert(err, gc.ErrorMatches, `cannot record change to bundle deployment ".*": not running`)
+
+	err = deployment.Start()
+	c.Assert(err, gc.IsNil)
+	c.Assert(deployment.Status(), gc.Equals, state.BundleDeploymentRunning)
+	c.Assert(deployment.ExistingMachines(), gc.DeepEquals, []string{machine.Id()})
+	err = deployment.Start()
+	c.Assert(err, gc.ErrorMatches, `bundle deployment ".*" is running, not pending`)
+
+	err = deployment.RecordChange("deploy", "wordpress")
+	c.Assert(err, gc.IsNil)
+	err = deployment.RecordChange("add-unit", "wordpress/0")
+	c.Assert(err, gc.IsNil)
+	err = deployment.Finish(nil)
+	c.Assert(err, gc.IsNil)
+
+	err = deployment.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(deployment.Status(), gc.Equals, state.BundleDeploymentDone)
+	c.Assert(deployment.Changes(), gc.DeepEquals, []state.BundleChange{
+		{Kind: "deploy", Id: "wordpress"},
+		{Kind: "add-unit", Id: "wordpress/0"},
+	})
+	err = deployment.Finish(nil)
+	c.Assert(err, gc.ErrorMatches, `cannot finish bundle deployment ".*": not running`)
+
+	unfinished, err := s.State.UnfinishedBundleDeployments()
+	c.Assert(err, gc.IsNil)
+	c.Assert(unfinished, gc.HasLen, 0)
+}
+
+func (s *BundleDeploymentSuite) TestFinishWithError(c *gc.C) {
+	deployment := s.addDeployment(c)
+	err := deployment.Start()
+	c.Assert(err, gc.IsNil)
+	err = deployment.Finish(errors.New("boom"))
+	c.Assert(err, gc.IsNil)
+
+	err = deployment.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(deployment.Status(), gc.Equals, state.BundleDeploymentFailed)
+	c.Assert(deployment.Error(), gc.Equals, "boom")
+}
+
+func (s *BundleDeploymentSuite) TestStartExistingService(c *gc.C) {
+	deployment := s.addDeployment(c)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	err := deployment.Start()
+	c.Assert(err, gc.ErrorMatches, `cannot start bundle deployment ".*": service "wordpress" already exists`)
+	c.Assert(deployment.Status(), gc.Equals, state.BundleDeploymentFailed)
+	c.Assert(deployment.Error(), gc.Equals, `service "wordpress" already exists`)
+}
+
+func (s *BundleDeploymentSuite) TestWatch(c *gc.C) {
+	deployment := s.addDeployment(c)
+	w := deployment.Watch()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := deployment.Start()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = deployment.RecordChange("deploy", "wordpress")
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+}
+
+func (s *BundleDeploymentSuite) TestWatchBundleDeployments(c *gc.C) {
+	w := s.State.WatchBundleDeployments()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	deployment := s.addDeployment(c)
+	wc.AssertOneChange()
+	err := deployment.Start()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+}
@@ -6,8 +6,11 @@ package state
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 )
@@ -27,18 +30,42 @@ const (
 // cleanupDoc represents a potentially large set of documents that should be
 // removed.
 type cleanupDoc struct {
-	Id     bson.ObjectId `bson:"_id"`
-	Kind   cleanupKind
+	Id      bson.ObjectId `bson:"_id"`
+	EnvUUID string        `bson:"env-uuid"`
+	Kind    cleanupKind
+	Prefix  string
+
+	// Failures and LastError record unsuccessful attempts to run
+	// the cleanup, so that stalled cleanups can be reported.
+	Failures  int    `bson:",omitempty"`
+	LastError string `bson:",omitempty"`
+}
+
+// CleanupInfo describes a pending cleanup.
+type CleanupInfo struct {
+	// Id uniquely identifies the cleanup.
+	Id string
+	// Kind describes what the cleanup removes.
+	Kind string
+	// Prefix identifies the entities the cleanup applies to.
 	Prefix string
+	// Created holds the time at which the cleanup was scheduled.
+	Created time.Time
+	// Failures holds the number of failed attempts to run the cleanup.
+	Failures int
+	// LastError holds the error from the most recent failed attempt,
+	// if any.
+	LastError string
 }
 
 // newCleanupOp returns a txn.Op that creates a cleanup document with a unique
 // id and the supplied kind and prefix.
 func (st *State) newCleanupOp(kind cleanupKind, prefix string) txn.Op {
 	doc := &cleanupDoc{
-		Id:     bson.NewObjectId(),
-		Kind:   kind,
-		Prefix: prefix,
+		Id:      bson.NewObjectId(),
+		EnvUUID: st.EnvironTag().Id(),
+		Kind:    kind,
+		Prefix:  prefix,
 	}
 	return txn.Op{
 		C:      cleanupsC,
@@ -51,7 +78,7 @@ func (st *State) newCleanupOp(kind cleanupKind, prefix string) txn.Op {
 func (st *State) NeedsCleanup() (bool, error) {
 	cleanups, closer := st.getCollection(cleanupsC)
 	defer closer()
-	count, err := cleanups.Count()
+	count, err := cleanups.Find(bson.D{{"env-uuid", st.EnvironTag().Id()}}).Count()
 	if err != nil {
 		return false, err
 	}
@@ -65,7 +92,7 @@ func (st *State) Cleanup() error {
 	var doc cleanupDoc
 	cleanups, closer := st.getCollection(cleanupsC)
 	defer closer()
-	iter := cleanups.Find(nil).Iter()
+	iter := cleanups.Find(bson.D{{"env-uuid", st.EnvironTag().Id()}}).Iter()
 	for iter.Next(&doc) {
 		var err error
 		logger.Debugf("running %q cleanup: %q", doc.Kind, doc.Prefix)
@@ -87,6 +114,7 @@ func (st *State) Cleanup() error {
 		}
 		if err != nil {
 			logger.Warningf("cleanup failed: %v", err)
+			st.recordCleanupFailure(doc.Id, err)
 			continue
 		}
 		ops := []txn.Op{{
@@ -104,13 +132,74 @@ func (st *State) Cleanup() error {
 	return nil
 }
 
+// recordCleanupFailure notes the failure of an attempt to run the
+// cleanup with the given id on its document.
+func (st *State) recordCleanupFailure(id bson.ObjectId, cleanupErr error) {
+	ops := []txn.Op{{
+		C:      cleanupsC,
+		Id:     id,
+		Assert: txn.DocExists,
+		Update: bson.D{
+			{"$inc", bson.D{{"failures", 1}}},
+			{"$set", bson.D{{"lasterror", cleanupErr.Error()}}},
+		},
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		logger.Warningf("cannot record cleanup failure: %v", err)
+	}
+}
+
+// Cleanups returns information about all pending cleanups, oldest
+// first.
+func (st *State) Cleanups() ([]CleanupInfo, error) {
+	cleanups, closer := st.getCollection(cleanupsC)
+	defer closer()
+	var docs []cleanupDoc
+	query := bson.D{{"env-uuid", st.EnvironTag().Id()}}
+	if err := cleanups.Find(query).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read cleanup documents")
+	}
+	infos := make([]CleanupInfo, len(docs))
+	for i, doc := range docs {
+		infos[i] = CleanupInfo{
+			Id:        doc.Id.Hex(),
+			Kind:      string(doc.Kind),
+			Prefix:    doc.Prefix,
+			Created:   doc.Id.Time(),
+			Failures:  doc.Failures,
+			LastError: doc.LastError,
+		}
+	}
+	return infos, nil
+}
+
+// CancelCleanup removes the pending cleanup with the given id without
+// running it. Any documents it would have removed are left behind.
+func (st *State) CancelCleanup(id string) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.Errorf("invalid cleanup id %q", id)
+	}
+	ops := []txn.Op{{
+		C:      cleanupsC,
+		Id:     bson.ObjectIdHex(id),
+		Assert: bson.D{{"env-uuid", st.EnvironTag().Id()}},
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("cleanup %q", id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot cancel cleanup %q", id)
+	}
+	return nil
+}
+
 func (st *State) cleanupRelationSettings(prefix string) error {
 	// Documents marked for cleanup are not otherwise referenced in the
 	// system, and will not be under watch, and are therefore safe to
 	// delete directly.
 	settings, closer := st.getCollection(settingsC)
 	defer closer()
-	sel := bson.D{{"_id", bson.D{{"$regex", "^" + prefix}}}}
+	sel := bson.D{{"_id", bson.D{{"$regex", "^" + st.docID(prefix)}}}}
 	if count, err := settings.Find(sel).Count(); err != nil {
 		return fmt.Errorf("cannot detect cleanup targets: %v", err)
 	} else if count != 0 {
@@ -131,7 +220,7 @@ func (st *State) cleanupServicesForDyingEnvironment() error {
 	services, closer := st.getCollection(servicesC)
 	defer closer()
 	service := Service{st: st}
-	sel := bson.D{{"life", Alive}}
+	sel := bson.D{{"env-uuid", st.EnvironTag().Id()}, {"life", Alive}}
 	iter := services.Find(sel).Iter()
 	for iter.Next(&service.doc) {
 		if err := service.Destroy(); err != nil {
@@ -222,115 +311,184 @@ func (st *State) cleanupRemovedUnit(unitId string) error {
 			return err
 		}
 	}
-	return nil
+	return st.removeStorageInstancesForUnit(unitId)
 }
 
 // cleanupForceDestroyedMachine systematically destroys and removes all entities
-// that depend upon the supplied machine, and removes the machine from state. It's
+// that depend upon the supplied machine, and leaves the machine Dead. It's
 // expected to be used in response to destroy-machine --force.
+//
+// The machine, its containers and every unit they host are made Dead, and
+// the ports opened on them closed, in a single transaction; so no partial
+// destruction can ever be observed, and nothing can be added to any of them
+// while the Dead units and containers are removed in turn. If removal fails
+// part way, the cleanup can safely be run again.
 func (st *State) cleanupForceDestroyedMachine(machineId string) error {
-	machine, err := st.Machine(machineId)
-	if errors.IsNotFound(err) {
-		return nil
-	} else if err != nil {
-		return err
-	}
-	// In an ideal world, we'd call machine.Destroy() here, and thus prevent
-	// new dependencies being added while we clean up the ones we know about.
-	// But machine destruction is unsophisticated, and doesn't allow for
-	// destruction while dependencies exist; so we just have to deal with that
-	// possibility below.
-	if err := st.cleanupContainers(machine); err != nil {
-		return err
-	}
-	for _, unitName := range machine.doc.Principals {
-		if err := st.obliterateUnit(unitName); err != nil {
-			return err
+	var doomed *forceDestroyed
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		machine, err := st.Machine(machineId)
+		if errors.IsNotFound(err) {
+			doomed = nil
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, err
 		}
+		doomed = &forceDestroyed{services: make(set.Strings)}
+		if err := doomed.addMachine(machine); err != nil {
+			return nil, err
+		}
+		if len(doomed.ops) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return doomed.ops, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot force destroy machine %s", machineId)
 	}
-	// We need to refresh the machine at this point, because the local copy
-	// of the document will not reflect changes caused by the unit cleanups
-	// above, and may thus fail immediately.
-	if err := machine.Refresh(); errors.IsNotFound(err) {
+	if doomed == nil {
 		return nil
-	} else if err != nil {
-		return err
 	}
-	// TODO(fwereade): 2013-11-11 bug 1250104
-	// If this fails, it's *probably* due to a race in which new dependencies
-	// were added while we cleaned up the old ones. If the cleanup doesn't run
-	// again -- which it *probably* will anyway -- the issue can be resolved by
-	// force-destroying the machine again; that's better than adding layer
-	// upon layer of complication here.
-	if err := machine.EnsureDead(); err != nil {
-		return err
+	// Subordinates are removed before their principals, and all units
+	// before the containers that hosted them.
+	for _, unit := range doomed.units {
+		if err := unit.Refresh(); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := unit.Remove(); err != nil {
+			return err
+		}
 	}
-	removePortsOps, err := machine.removePortsOps()
-	if err != nil {
-		return err
+	for _, container := range doomed.containers {
+		if err := container.Refresh(); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := container.Remove(); err != nil {
+			return err
+		}
 	}
-	return st.runTransaction(removePortsOps)
-
 	// Note that we do *not* remove the machine entirely: we leave it for the
 	// provisioner to clean up, so that we don't end up with an unreferenced
 	// instance that would otherwise be ignored when in provisioner-safe-mode.
+	return nil
 }
 
-// cleanupContainers recursively calls cleanupForceDestroyedMachine on the supplied
-// machine's containers, and removes them from state entirely.
-func (st *State) cleanupContainers(machine *Machine) error {
-	containerIds, err := machine.Containers()
+// forceDestroyed accumulates the operations that make a force-destroyed
+// machine, its containers and the units they host Dead, and the units and
+// containers that must then be removed.
+type forceDestroyed struct {
+	ops        []txn.Op
+	units      []*Unit
+	containers []*Machine
+	services   set.Strings
+}
+
+// addMachine adds the operations that make the machine, its containers and
+// their units Dead, and close the ports opened on them. Containers are
+// added deepest first.
+func (d *forceDestroyed) addMachine(m *Machine) error {
+	if hasJob(m.doc.Jobs, JobManageEnviron) {
+		return fmt.Errorf("machine %s is required by the environment", m.doc.Id)
+	}
+	// A machine without a container refs document hosts no containers.
+	hasContainerRefs := true
+	containerIds, err := m.Containers()
 	if errors.IsNotFound(err) {
-		return nil
+		hasContainerRefs = false
 	} else if err != nil {
 		return err
 	}
 	for _, containerId := range containerIds {
-		if err := st.cleanupForceDestroyedMachine(containerId); err != nil {
-			return err
-		}
-		container, err := st.Machine(containerId)
+		container, err := m.st.Machine(containerId)
 		if errors.IsNotFound(err) {
-			return nil
+			continue
 		} else if err != nil {
 			return err
 		}
-		if err := container.Remove(); err != nil {
+		if err := d.addMachine(container); err != nil {
 			return err
 		}
+		d.containers = append(d.containers, container)
+	}
+	for _, unitName := range m.doc.Principals {
+		if err := d.addUnit(m.st, unitName); err != nil {
+			return err
+		}
+	}
+	if m.doc.Life != Dead {
+		// Once the machine is Dead, no units or containers can be added
+		// to it; until then, check that we know about all of them.
+		assert := append(bson.D{
+			{"jobs", bson.D{{"$nin", []MachineJob{JobManageEnviron}}}},
+		}, notDeadDoc...)
+		if len(m.doc.Principals) == 0 {
+			assert = append(assert, machineHasNoPrincipals...)
+		} else {
+			assert = append(assert, bson.DocElem{"principals", m.doc.Principals})
+		}
+		d.ops = append(d.ops, txn.Op{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: assert,
+			Update: bson.D{{"$set", bson.D{{"life", Dead}}}},
+		})
+		if hasContainerRefs {
+			childrenAssert := bson.D{{"children", containerIds}}
+			if len(containerIds) == 0 {
+				childrenAssert = machineHasNoContainers
+			}
+			d.ops = append(d.ops, txn.Op{
+				C:      containerRefsC,
+				Id:     m.doc.DocID,
+				Assert: childrenAssert,
+			})
+		}
+	}
+	ports, err := m.AllPorts()
+	if err != nil {
+		return err
+	}
+	for _, p := range ports {
+		d.ops = append(d.ops, p.removeOps()...)
 	}
 	return nil
 }
 
-// obliterateUnit removes a unit from state completely. It is not safe or
-// sane to obliterate any unit in isolation; its only reasonable use is in
-// the context of machine obliteration, in which we can be sure that unclean
-// shutdown of units is not going to leave a machine in a difficult state.
-func (st *State) obliterateUnit(unitName string) error {
+// addUnit adds the operations that make the named unit and its
+// subordinates Dead. Subordinates are added before their principal.
+func (d *forceDestroyed) addUnit(st *State, unitName string) error {
 	unit, err := st.Unit(unitName)
 	if errors.IsNotFound(err) {
 		return nil
 	} else if err != nil {
 		return err
 	}
-	// Unlike the machine, we *can* always destroy the unit, and (at least)
-	// prevent further dependencies being added. If we're really lucky, the
-	// unit will be removed immediately.
-	if err := unit.Destroy(); err != nil {
-		return err
-	}
-	if err := unit.Refresh(); errors.IsNotFound(err) {
-		return nil
-	} else if err != nil {
-		return err
-	}
-	for _, subName := range unit.SubordinateNames() {
-		if err := st.obliterateUnit(subName); err != nil {
+	for _, subName := range unit.doc.Subordinates {
+		if err := d.addUnit(st, subName); err != nil {
 			return err
 		}
 	}
-	if err := unit.EnsureDead(); err != nil {
-		return err
+	if unit.doc.Life != Dead {
+		assert := append(bson.D{}, notDeadDoc...)
+		if len(unit.doc.Subordinates) == 0 {
+			assert = append(assert, unitHasNoSubordinates...)
+		} else {
+			assert = append(assert, bson.DocElem{"subordinates", unit.doc.Subordinates})
+		}
+		d.ops = append(d.ops, txn.Op{
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: assert,
+			Update: bson.D{{"$set", bson.D{{"life", Dead}}}},
+		})
+		if service := unit.ServiceName(); !d.services.Contains(service) {
+			d.services.Add(service)
+			d.ops = append(d.ops, minUnitsTriggerOp(st, service))
+		}
 	}
-	return unit.Remove()
+	d.units = append(d.units, unit)
+	return nil
 }
@@ -244,6 +244,51 @@ func (s *CleanupSuite) TestCleanupForceDestroyedMachineWithContainer(c *gc.C) {
 	assertLife(c, machine, state.Dead)
 }
 
+func (s *CleanupSuite) TestCleanupForceDestroyedMachineClosesPorts(c *gc.C) {
+	// Create a machine with two containers.
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	containers := make([]*state.Machine, 2)
+	for i := range containers {
+		containers[i], err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+			Series: "quantal",
+			Jobs:   []state.MachineJob{state.JobHostUnits},
+		}, machine.Id(), instance.LXC)
+		c.Assert(err, gc.IsNil)
+	}
+
+	// Open ports from units on the machine and on the second container.
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	units := make([]*state.Unit, 2)
+	for i, host := range []*state.Machine{machine, containers[1]} {
+		units[i], err = mysql.AddUnit()
+		c.Assert(err, gc.IsNil)
+		err = units[i].AssignToMachine(host)
+		c.Assert(err, gc.IsNil)
+		err = units[i].OpenPort("tcp", 3306)
+		c.Assert(err, gc.IsNil)
+	}
+
+	err = machine.ForceDestroy()
+	c.Assert(err, gc.IsNil)
+	s.assertCleanupCount(c, 2)
+
+	// Both containers and all units have been removed...
+	for _, container := range containers {
+		err = container.Refresh()
+		c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	}
+	for _, unit := range units {
+		assertRemoved(c, unit)
+	}
+
+	// ...and the machine is Dead, with no ports left open.
+	assertLife(c, machine, state.Dead)
+	ports, err := machine.AllPorts()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ports, gc.HasLen, 0)
+}
+
 func (s *CleanupSuite) TestCleanupDyingUnit(c *gc.C) {
 	// Create active unit, in a relation.
 	prr := NewProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
@@ -355,6 +400,54 @@ func (s *CleanupSuite) TestNothingToCleanup(c *gc.C) {
 	s.assertDoesNotNeedCleanup(c)
 }
 
+func (s *CleanupSuite) TestCleanupsReportsPending(c *gc.C) {
+	cleanups, err := s.State.Cleanups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(cleanups, gc.HasLen, 0)
+
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err = mysql.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = mysql.Destroy()
+	c.Assert(err, gc.IsNil)
+
+	cleanups, err = s.State.Cleanups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(cleanups, gc.HasLen, 1)
+	c.Assert(cleanups[0].Kind, gc.Equals, "units")
+	c.Assert(cleanups[0].Prefix, gc.Equals, "mysql")
+	c.Assert(cleanups[0].Created.IsZero(), jc.IsFalse)
+	c.Assert(cleanups[0].Failures, gc.Equals, 0)
+}
+
+func (s *CleanupSuite) TestCleanupFailureRecorded(c *gc.C) {
+	state.AddCleanup(c, s.State, "bogus", "foo")
+	s.assertCleanupRuns(c)
+	s.assertCleanupRuns(c)
+
+	cleanups, err := s.State.Cleanups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(cleanups, gc.HasLen, 1)
+	c.Assert(cleanups[0].Failures, gc.Equals, 2)
+	c.Assert(cleanups[0].LastError, gc.Equals, `unknown cleanup kind "bogus"`)
+}
+
+func (s *CleanupSuite) TestCancelCleanup(c *gc.C) {
+	state.AddCleanup(c, s.State, "bogus", "foo")
+	cleanups, err := s.State.Cleanups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(cleanups, gc.HasLen, 1)
+
+	err = s.State.CancelCleanup(cleanups[0].Id)
+	c.Assert(err, gc.IsNil)
+	s.assertDoesNotNeedCleanup(c)
+
+	err = s.State.CancelCleanup(cleanups[0].Id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.CancelCleanup("not-an-id")
+	c.Assert(err, gc.ErrorMatches, `invalid cleanup id "not-an-id"`)
+}
+
 func (s *CleanupSuite) assertCleanupRuns(c *gc.C) {
 	err := s.State.Cleanup()
 	c.Assert(err, gc.IsNil)
@@ -0,0 +1,202 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/replicaset"
+)
+
+// CollectionStatus describes the size and indexes of a collection in
+// the state database.
+type CollectionStatus struct {
+	Name string
+
+	// Count holds the number of documents in the collection.
+	Count int64
+
+	// Size holds the total size of the documents, in bytes.
+	Size int64
+
+	// StorageSize holds the space allocated to the documents on disk,
+	// in bytes. The difference from Size is reclaimed by compaction.
+	StorageSize int64
+
+	// IndexSize holds the total size of the collection's indexes, in
+	// bytes.
+	IndexSize int64
+
+	// Indexes holds the names of the collection's indexes.
+	Indexes []string
+
+	// MissingIndexes holds the keys, joined with commas, of any index
+	// that juju requires but that the collection lacks.
+	MissingIndexes []string
+
+	// Capped holds whether the collection is capped. Capped
+	// collections are never compacted.
+	Capped bool
+}
+
+// ReplicaSetMemberStatus describes a member of the state server
+// replica set.
+type ReplicaSetMemberStatus struct {
+	Address string
+	State   string
+	Healthy bool
+
+	// Lag holds how far the member's applied operations trail those
+	// of the primary.
+	Lag time.Duration
+}
+
+// DatabaseStatus describes the health of the state database.
+type DatabaseStatus struct {
+	Collections []CollectionStatus
+
+	// Members is empty if mongo is not running as a replica set.
+	Members []ReplicaSetMemberStatus
+}
+
+// collStats holds the fields of the collStats command's result that
+// DatabaseStatus reports.
+type collStats struct {
+	Count          int64 `bson:"count"`
+	Size           int64 `bson:"size"`
+	StorageSize    int64 `bson:"storageSize"`
+	TotalIndexSize int64 `bson:"totalIndexSize"`
+	Capped         bool  `bson:"capped"`
+}
+
+// DatabaseStatus reports the size and index health of each collection
+// in the state database, and the replication lag of each state server.
+func (st *State) DatabaseStatus() (*DatabaseStatus, error) {
+	names, err := st.db.CollectionNames()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list collections")
+	}
+	result := &DatabaseStatus{}
+	for _, name := range names {
+		if strings.HasPrefix(name, "system.") {
+			continue
+		}
+		status, err := st.collectionStatus(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result.Collections = append(result.Collections, status)
+	}
+	if result.Members, err = st.replicaSetMembers(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result, nil
+}
+
+func (st *State) collectionStatus(name string) (CollectionStatus, error) {
+	var stats collStats
+	if err := st.db.Run(bson.D{{"collStats", name}}, &stats); err != nil {
+		return CollectionStatus{}, errors.Annotatef(err, "cannot get statistics of collection %q", name)
+	}
+	status := CollectionStatus{
+		Name:        name,
+		Count:       stats.Count,
+		Size:        stats.Size,
+		StorageSize: stats.StorageSize,
+		IndexSize:   stats.TotalIndexSize,
+		Capped:      stats.Capped,
+	}
+	existing, err := st.db.C(name).Indexes()
+	if err != nil {
+		return CollectionStatus{}, errors.Annotatef(err, "cannot list indexes of collection %q", name)
+	}
+	present := make(map[string]bool)
+	for _, index := range existing {
+		status.Indexes = append(status.Indexes, index.Name)
+		present[strings.Join(index.Key, ",")] = true
+	}
+	for _, item := range indexes {
+		key := strings.Join(item.key, ",")
+		if item.collection == name && !present[key] {
+			status.MissingIndexes = append(status.MissingIndexes, key)
+		}
+	}
+	sort.Strings(status.Indexes)
+	return status, nil
+}
+
+// replicaSetMembers returns the status of the state server replica set
+// members, or nothing if mongo is not running as a replica set.
+func (st *State) replicaSetMembers() ([]ReplicaSetMemberStatus, error) {
+	session := st.db.Session.Copy()
+	defer session.Close()
+	status, err := replicaset.CurrentStatus(session)
+	if err != nil {
+		if strings.Contains(err.Error(), "--replSet") {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	var primaryOptime time.Time
+	for _, member := range status.Members {
+		if member.State == replicaset.PrimaryState {
+			primaryOptime = member.OptimeDate
+		}
+	}
+	members := make([]ReplicaSetMemberStatus, len(status.Members))
+	for i, member := range status.Members {
+		members[i] = ReplicaSetMemberStatus{
+			Address: member.Address,
+			State:   member.State.String(),
+			Healthy: member.Healthy,
+		}
+		if !primaryOptime.IsZero() && member.OptimeDate.Before(primaryOptime) {
+			members[i].Lag = primaryOptime.Sub(member.OptimeDate)
+		}
+	}
+	return members, nil
+}
+
+// CompactDatabase reclaims the unused space in every uncapped collection
+// of the state database, as reported by DatabaseStatus. If repair is
+// true, the whole database is instead rebuilt with mongo's
+// repairDatabase command, which also recovers from corruption but
+// needs free disk space as large as the database. Either operation
+// blocks all other database access while it runs, so it should only be
+// done during a maintenance window.
+func (st *State) CompactDatabase(repair bool) error {
+	if repair {
+		if err := st.db.Run(bson.D{{"repairDatabase", 1}}, nil); err != nil {
+			return errors.Annotate(err, "cannot repair database")
+		}
+		return nil
+	}
+	names, err := st.db.CollectionNames()
+	if err != nil {
+		return errors.Annotate(err, "cannot list collections")
+	}
+	for _, name := range names {
+		if strings.HasPrefix(name, "system.") {
+			continue
+		}
+		var stats collStats
+		if err := st.db.Run(bson.D{{"collStats", name}}, &stats); err != nil {
+			return errors.Annotatef(err, "cannot get statistics of collection %q", name)
+		}
+		if stats.Capped {
+			continue
+		}
+		logger.Infof("compacting collection %q", name)
+		// force is required to compact the primary of a replica set.
+		if err := st.db.Run(bson.D{{"compact", name}, {"force", true}}, nil); err != nil {
+			return errors.Annotatef(err, "cannot compact collection %q", name)
+		}
+	}
+	return nil
+}
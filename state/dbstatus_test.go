@@ -0,0 +1,71 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type DatabaseStatusSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&DatabaseStatusSuite{})
+
+func findCollection(status *state.DatabaseStatus, name string) (state.CollectionStatus, bool) {
+	for _, coll := range status.Collections {
+		if coll.Name == name {
+			return coll, true
+		}
+	}
+	return state.CollectionStatus{}, false
+}
+
+func (s *DatabaseStatusSuite) TestDatabaseStatus(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	status, err := s.State.DatabaseStatus()
+	c.Assert(err, gc.IsNil)
+
+	services, ok := findCollection(status, "services")
+	c.Assert(ok, jc.IsTrue)
+	c.Check(services.Count, gc.Equals, int64(1))
+	c.Check(services.Size > 0, jc.IsTrue)
+	c.Check(services.Capped, jc.IsFalse)
+	c.Check(services.Indexes, gc.DeepEquals, []string{"_id_"})
+	c.Check(services.MissingIndexes, gc.HasLen, 0)
+
+	units, ok := findCollection(status, "units")
+	c.Assert(ok, jc.IsTrue)
+	c.Check(units.MissingIndexes, gc.HasLen, 0)
+	c.Check(len(units.Indexes) > 1, jc.IsTrue)
+
+	txnLog, ok := findCollection(status, "txns.log")
+	c.Assert(ok, jc.IsTrue)
+	c.Check(txnLog.Capped, jc.IsTrue)
+}
+
+func (s *DatabaseStatusSuite) TestDatabaseStatusMissingIndex(c *gc.C) {
+	err := s.State.MongoSession().DB("juju").C("units").DropIndex("service")
+	c.Assert(err, gc.IsNil)
+
+	status, err := s.State.DatabaseStatus()
+	c.Assert(err, gc.IsNil)
+	units, ok := findCollection(status, "units")
+	c.Assert(ok, jc.IsTrue)
+	c.Check(units.MissingIndexes, gc.DeepEquals, []string{"service"})
+}
+
+func (s *DatabaseStatusSuite) TestCompactDatabase(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	err := s.State.CompactDatabase(false)
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.State.Service("wordpress")
+	c.Assert(err, gc.IsNil)
+}
@@ -0,0 +1,108 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// debugHooksTranscriptDoc records the output of the hooks run in
+// the most recent "juju debug-hooks" session on a unit.
+type debugHooksTranscriptDoc struct {
+	DocID      string    `bson:"_id"`
+	EnvUUID    string    `bson:"env-uuid"`
+	Unit       string    `bson:"unit"`
+	Transcript string    `bson:"transcript"`
+	Updated    time.Time `bson:"updated"`
+}
+
+// SetDebugHooksTranscript records the transcript of the unit's
+// debug-hooks session, replacing any previously recorded transcript.
+func (u *Unit) SetDebugHooksTranscript(transcript string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set debug-hooks transcript for unit %q", u)
+	key := u.globalKey()
+	doc := debugHooksTranscriptDoc{
+		DocID:      u.st.docID(key),
+		EnvUUID:    u.st.EnvironTag().Id(),
+		Unit:       u.Name(),
+		Transcript: transcript,
+		Updated:    nowToTheSecond(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if notDead, err := isNotDead(u.st.db, unitsC, u.doc.DocID); err != nil {
+			return nil, err
+		} else if !notDead {
+			return nil, ErrDead
+		}
+		_, err := readDebugHooksTranscript(u.st, key)
+		op := txn.Op{
+			C:      debugHooksTranscriptsC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}
+		if err == nil {
+			op = txn.Op{
+				C:      debugHooksTranscriptsC,
+				Id:     doc.DocID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{
+					{"transcript", doc.Transcript},
+					{"updated", doc.Updated},
+				}}},
+			}
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		return []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}, op}, nil
+	}
+	return u.st.run(buildTxn)
+}
+
+// DebugHooksTranscript returns the transcript of the unit's most
+// recent debug-hooks session, and the time it was last updated.
+// An error satisfying errors.IsNotFound is returned if no transcript
+// has been recorded.
+func (u *Unit) DebugHooksTranscript() (string, time.Time, error) {
+	doc, err := readDebugHooksTranscript(u.st, u.globalKey())
+	if errors.IsNotFound(err) {
+		return "", time.Time{}, errors.NotFoundf("debug-hooks transcript for unit %q", u)
+	} else if err != nil {
+		return "", time.Time{}, err
+	}
+	return doc.Transcript, doc.Updated, nil
+}
+
+func readDebugHooksTranscript(st *State, key string) (*debugHooksTranscriptDoc, error) {
+	transcripts, closer := st.getCollection(debugHooksTranscriptsC)
+	defer closer()
+
+	var doc debugHooksTranscriptDoc
+	if err := transcripts.FindId(st.docID(key)).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("debug-hooks transcript")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read debug-hooks transcript")
+	}
+	return &doc, nil
+}
+
+// removeDebugHooksTranscriptOp returns the operation required to
+// remove the debug-hooks transcript for the unit with the given
+// global key. It is a no-op if no transcript has been recorded.
+func removeDebugHooksTranscriptOp(st *State, key string) txn.Op {
+	return txn.Op{
+		C:      debugHooksTranscriptsC,
+		Id:     st.docID(key),
+		Remove: true,
+	}
+}
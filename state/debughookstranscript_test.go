@@ -0,0 +1,65 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type DebugHooksTranscriptSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&DebugHooksTranscriptSuite{})
+
+func (s *DebugHooksTranscriptSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit, err = service.AddUnit()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *DebugHooksTranscriptSuite) TestSetDebugHooksTranscript(c *gc.C) {
+	_, _, err := s.unit.DebugHooksTranscript()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.unit.SetDebugHooksTranscript("=== install ===\n")
+	c.Assert(err, gc.IsNil)
+	transcript, updated, err := s.unit.DebugHooksTranscript()
+	c.Assert(err, gc.IsNil)
+	c.Assert(transcript, gc.Equals, "=== install ===\n")
+	c.Assert(updated.IsZero(), jc.IsFalse)
+
+	// Setting the transcript again replaces it.
+	err = s.unit.SetDebugHooksTranscript("=== install ===\n=== start ===\n")
+	c.Assert(err, gc.IsNil)
+	transcript, _, err = s.unit.DebugHooksTranscript()
+	c.Assert(err, gc.IsNil)
+	c.Assert(transcript, gc.Equals, "=== install ===\n=== start ===\n")
+}
+
+func (s *DebugHooksTranscriptSuite) TestSetDebugHooksTranscriptDeadUnit(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.unit.SetDebugHooksTranscript("=== install ===\n")
+	c.Assert(err, gc.ErrorMatches, `cannot set debug-hooks transcript for unit "wordpress/0": not found or dead`)
+}
+
+func (s *DebugHooksTranscriptSuite) TestRemovingUnitRemovesTranscript(c *gc.C) {
+	err := s.unit.SetDebugHooksTranscript("=== install ===\n")
+	c.Assert(err, gc.IsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.unit.Remove()
+	c.Assert(err, gc.IsNil)
+
+	_, _, err = s.unit.DebugHooksTranscript()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
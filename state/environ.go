@@ -9,6 +9,10 @@ import (
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
 )
 
 // environGlobalKey is the key for the environment, its
@@ -29,6 +33,24 @@ type environmentDoc struct {
 	Life       Life
 	Owner      string `bson:"owner"`
 	ServerUUID string `bson:"server-uuid"`
+
+	// MigrationTarget records the state server that the
+	// environment has been moved to, if any.
+	MigrationTarget *migrationTargetDoc `bson:"migration-target,omitempty"`
+}
+
+// migrationTargetDoc records the state server that an environment
+// has been moved to.
+type migrationTargetDoc struct {
+	APIHostPorts [][]hostPort `bson:"apihostports"`
+	CACert       string       `bson:"cacert"`
+}
+
+// MigrationTarget holds the API addresses and CA certificate of the
+// state server that an environment has been moved to.
+type MigrationTarget struct {
+	APIHostPorts [][]network.HostPort
+	CACert       string
 }
 
 // StateServerEnvironment returns the environment that was bootstrapped.
@@ -128,6 +150,96 @@ func (st *State) NewEnvironment(env, server names.EnvironTag, owner names.UserTa
 	return environment, nil
 }
 
+// NewHostedEnvironment creates a new environment with the given
+// configuration and owner, hosted by the same state server as st. The
+// configuration must hold the new environment's UUID. It returns the
+// new environment and a State connected to it, which the caller must
+// close.
+func (st *State) NewHostedEnvironment(cfg *config.Config, owner names.UserTag) (_ *Environment, _ *State, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot create environment %q", cfg.Name())
+	newSt, ops, err := st.newHostedEnvironmentOps(cfg, owner)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			newSt.Close()
+		}
+	}()
+	if err := newSt.runTransaction(ops); err == txn.ErrAborted {
+		return nil, nil, errors.AlreadyExistsf("environment with UUID %q", newSt.EnvironTag().Id())
+	} else if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	env, err := newSt.Environment()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return env, newSt, nil
+}
+
+// newHostedEnvironmentOps returns a State connected to the environment
+// with the given configuration and owner, and the operations needed to
+// create that environment. If no error is returned, the caller must
+// close the State.
+func (st *State) newHostedEnvironmentOps(cfg *config.Config, owner names.UserTag) (_ *State, _ []txn.Op, err error) {
+	uuid, ok := cfg.UUID()
+	if !ok {
+		return nil, nil, errors.Errorf("environment uuid was not supplied")
+	}
+	if err := checkEnvironConfig(cfg); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if cfg, err = st.validate(cfg, nil); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if owner.IsLocal() {
+		if _, err := st.User(owner); err != nil {
+			return nil, nil, errors.Annotate(err, "cannot get owner")
+		}
+	}
+	ssEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	newSt, err := st.ForEnviron(names.NewEnvironTag(uuid))
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	envUserOp, _ := createEnvUserOpAndDoc(uuid, owner, owner, owner.Name())
+	ops := []txn.Op{
+		createConstraintsOp(newSt, environGlobalKey, constraints.Value{}),
+		createSettingsOp(newSt, environGlobalKey, cfg.AllAttrs()),
+		createEnvironmentOp(newSt, owner, cfg.Name(), uuid, ssEnv.UUID()),
+		envUserOp,
+	}
+	return newSt, ops, nil
+}
+
+// EnvironmentsForUser returns the environments that have been shared
+// with the given user, ordered by UUID.
+func (st *State) EnvironmentsForUser(user names.UserTag) ([]*Environment, error) {
+	envUsers, closer := st.getCollection(envUsersC)
+	defer closer()
+
+	var docs []envUserDoc
+	if err := envUsers.Find(bson.D{{"user", user.Username()}}).Sort("envuuid").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get environments for user %q", user.Username())
+	}
+	result := make([]*Environment, 0, len(docs))
+	for _, doc := range docs {
+		env, err := st.GetEnvironment(names.NewEnvironTag(doc.EnvUUID))
+		if errors.IsNotFound(err) {
+			// The environment has been removed.
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = append(result, env)
+	}
+	return result, nil
+}
+
 // Tag returns a name identifying the environment.
 // The returned name will be different from other Tag values returned
 // by any other entities from the same state.
@@ -223,6 +335,60 @@ func (e *Environment) Destroy() error {
 	return err
 }
 
+// SetMigrationTarget records that the environment has been imported
+// into the state server described by target. The environment's agents
+// reconnect to that state server when they next check for upgrades.
+func (e *Environment) SetMigrationTarget(target MigrationTarget) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set migration target of environment %q", e.Name())
+	if len(target.APIHostPorts) == 0 {
+		return errors.New("no API addresses")
+	}
+	if target.CACert == "" {
+		return errors.New("no CA certificate")
+	}
+	doc := &migrationTargetDoc{
+		APIHostPorts: instanceHostPortsToHostPorts(target.APIHostPorts),
+		CACert:       target.CACert,
+	}
+	ops := []txn.Op{{
+		C:      environmentsC,
+		Id:     e.doc.UUID,
+		Assert: isEnvAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"migration-target", doc}}}},
+	}}
+	if err := e.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("environment is no longer alive")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	e.doc.MigrationTarget = doc
+	return nil
+}
+
+// MigrationTarget returns the state server that the environment has
+// been moved to. It returns an error that satisfies errors.IsNotFound
+// if the environment has not been moved.
+func (e *Environment) MigrationTarget() (*MigrationTarget, error) {
+	if e.doc.MigrationTarget == nil {
+		return nil, errors.NotFoundf("migration target")
+	}
+	return &MigrationTarget{
+		APIHostPorts: hostPortsToInstanceHostPorts(e.doc.MigrationTarget.APIHostPorts),
+		CACert:       e.doc.MigrationTarget.CACert,
+	}, nil
+}
+
+// MigrationTarget returns the state server that st's environment has
+// been moved to. It returns an error that satisfies errors.IsNotFound
+// if the environment has not been moved.
+func (st *State) MigrationTarget() (*MigrationTarget, error) {
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return env.MigrationTarget()
+}
+
 // createEnvironmentOp returns the operation needed to create
 // an environment document with the given name and UUID.
 func createEnvironmentOp(st *State, owner names.UserTag, name, uuid, server string) txn.Op {
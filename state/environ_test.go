@@ -10,7 +10,9 @@ import (
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
 )
 
 type EnvironSuite struct {
@@ -74,6 +76,49 @@ func (s *EnvironSuite) TestNewEnvironment(c *gc.C) {
 	assertMatches(env)
 }
 
+func (s *EnvironSuite) TestNewHostedEnvironment(c *gc.C) {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, gc.IsNil)
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"name": "hosted",
+		"uuid": uuid.String(),
+	})
+	env, st, err := s.State.NewHostedEnvironment(cfg, s.owner)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	c.Assert(env.UUID(), gc.Equals, uuid.String())
+	c.Assert(env.Name(), gc.Equals, "hosted")
+	c.Assert(env.Owner(), gc.Equals, s.owner)
+	c.Assert(env.ServerTag(), gc.Equals, s.envTag)
+	c.Assert(st.EnvironTag(), gc.Equals, env.EnvironTag())
+
+	hostedCfg, err := st.EnvironConfig()
+	c.Assert(err, gc.IsNil)
+	c.Assert(hostedCfg.Name(), gc.Equals, "hosted")
+	stateServerCfg, err := s.State.EnvironConfig()
+	c.Assert(err, gc.IsNil)
+	c.Assert(stateServerCfg.Name(), gc.Equals, "testenv")
+
+	// Machines added to one environment are not seen by the other.
+	_, err = st.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	machines, err := s.State.AllMachines()
+	c.Assert(err, gc.IsNil)
+	c.Assert(machines, gc.HasLen, 0)
+	machines, err = st.AllMachines()
+	c.Assert(err, gc.IsNil)
+	c.Assert(machines, gc.HasLen, 1)
+
+	envs, err := s.State.EnvironmentsForUser(s.owner)
+	c.Assert(err, gc.IsNil)
+	c.Assert(envs, gc.HasLen, 2)
+
+	_, _, err = s.State.NewHostedEnvironment(cfg, s.owner)
+	c.Assert(err, gc.ErrorMatches, `cannot create environment "hosted": environment with UUID ".*" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
 func (s *EnvironSuite) TestStateServerEnvironment(c *gc.C) {
 	env, err := s.State.StateServerEnvironment()
 	c.Assert(err, gc.IsNil)
@@ -115,3 +160,39 @@ func (s *EnvironSuite) TestStateServerEnvironmentAccessibleFromOtherEnvironments
 		return env, nil
 	})
 }
+
+func (s *EnvironSuite) TestMigrationTarget(c *gc.C) {
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	_, err = env.MigrationTarget()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	target := state.MigrationTarget{
+		APIHostPorts: [][]network.HostPort{
+			network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+		},
+		CACert: testing.CACert,
+	}
+	err = env.SetMigrationTarget(target)
+	c.Assert(err, gc.IsNil)
+
+	env, err = s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	result, err := env.MigrationTarget()
+	c.Assert(err, gc.IsNil)
+	c.Assert(*result, jc.DeepEquals, target)
+	result, err = s.State.MigrationTarget()
+	c.Assert(err, gc.IsNil)
+	c.Assert(*result, jc.DeepEquals, target)
+}
+
+func (s *EnvironSuite) TestSetMigrationTargetInvalid(c *gc.C) {
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	err = env.SetMigrationTarget(state.MigrationTarget{CACert: testing.CACert})
+	c.Assert(err, gc.ErrorMatches, `cannot set migration target of environment "testenv": no API addresses`)
+	err = env.SetMigrationTarget(state.MigrationTarget{
+		APIHostPorts: [][]network.HostPort{network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070)},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot set migration target of environment "testenv": no CA certificate`)
+}
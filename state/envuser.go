@@ -24,6 +24,29 @@ type EnvironmentUser struct {
 	doc envUserDoc
 }
 
+// EnvironmentAccess describes the access a user has been granted
+// to an environment.
+type EnvironmentAccess string
+
+const (
+	// EnvironmentReadAccess allows a user to view, but not change,
+	// an environment.
+	EnvironmentReadAccess EnvironmentAccess = "read"
+
+	// EnvironmentWriteAccess allows a user to view and change an
+	// environment.
+	EnvironmentWriteAccess EnvironmentAccess = "write"
+)
+
+// Validate returns an error if the access is not known.
+func (access EnvironmentAccess) Validate() error {
+	switch access {
+	case EnvironmentReadAccess, EnvironmentWriteAccess:
+		return nil
+	}
+	return errors.NotValidf("environment access %q", access)
+}
+
 type envUserDoc struct {
 	ID             string     `bson:"_id"`
 	EnvUUID        string     `bson:"envuuid"`
@@ -32,6 +55,10 @@ type envUserDoc struct {
 	CreatedBy      string     `bson:"createdby"`
 	DateCreated    time.Time  `bson:"datecreated"`
 	LastConnection *time.Time `bson:"lastconnection"`
+
+	// Access is empty for users added before access levels
+	// were introduced, who have write access.
+	Access EnvironmentAccess `bson:"access,omitempty"`
 }
 
 // ID returns the ID of the environment user.
@@ -74,6 +101,36 @@ func (e *EnvironmentUser) LastConnection() *time.Time {
 	return e.doc.LastConnection
 }
 
+// Access returns the access the user has been granted to the
+// environment.
+func (e *EnvironmentUser) Access() EnvironmentAccess {
+	if e.doc.Access == "" {
+		return EnvironmentWriteAccess
+	}
+	return e.doc.Access
+}
+
+// SetAccess changes the access the user has been granted to the
+// environment.
+func (e *EnvironmentUser) SetAccess(access EnvironmentAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      envUsersC,
+		Id:     e.ID(),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"access", access}}}},
+	}}
+	if err := e.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("environment user %q", e.UserName())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot set access for envuser %q", e.ID())
+	}
+	e.doc.Access = access
+	return nil
+}
+
 // UpdateLastConnection updates the last connection time of the environment user.
 func (e *EnvironmentUser) UpdateLastConnection() error {
 	timestamp := nowToTheSecond()
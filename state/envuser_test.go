@@ -90,3 +90,32 @@ func (s *EnvUserSuite) TestUpdateLastConnection(c *gc.C) {
 	c.Assert(envUser.LastConnection().After(now) ||
 		envUser.LastConnection().Equal(now), jc.IsTrue)
 }
+
+func (s *EnvUserSuite) TestSetAccess(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "validusername"})
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+
+	err = envUser.SetAccess(state.EnvironmentReadAccess)
+	c.Assert(err, gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+
+	err = envUser.SetAccess("admin")
+	c.Assert(err, gc.ErrorMatches, `environment access "admin" not valid`)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *EnvUserSuite) TestSetAccessRemovedUser(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "validusername"})
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, gc.IsNil)
+	err = s.State.RemoveEnvironmentUser(user.UserTag())
+	c.Assert(err, gc.IsNil)
+
+	err = envUser.SetAccess(state.EnvironmentReadAccess)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"time"
 
 	"github.com/juju/names"
 	jujutxn "github.com/juju/txn"
@@ -83,7 +84,7 @@ func ServiceSettingsRefCount(st *State, serviceName string, curl *charm.URL) (in
 
 	key := serviceSettingsKey(serviceName, curl)
 	var doc settingsRefsDoc
-	if err := settingsRefsCollection.FindId(key).One(&doc); err == nil {
+	if err := settingsRefsCollection.FindId(st.docID(key)).One(&doc); err == nil {
 		return doc.RefCount, nil
 	}
 	return 0, mgo.ErrNotFound
@@ -217,7 +218,7 @@ func MinUnitsRevno(st *State, serviceName string) (int, error) {
 	minUnitsCollection, closer := st.getCollection(minUnitsC)
 	defer closer()
 	var doc minUnitsDoc
-	if err := minUnitsCollection.FindId(serviceName).One(&doc); err != nil {
+	if err := minUnitsCollection.FindId(st.docID(serviceName)).One(&doc); err != nil {
 		return 0, err
 	}
 	return doc.Revno, nil
@@ -240,6 +241,10 @@ func CheckUserExists(st *State, name string) (bool, error) {
 
 var StateServerAvailable = &stateServerAvailable
 
+var LeadershipLeaseDuration = &leadershipLeaseDuration
+
+const StatusHistoryLimit = statusHistoryLimit
+
 func EnsureActionMarker(prefix string) string {
 	return ensureActionMarker(prefix)
 }
@@ -299,3 +304,34 @@ func LocalID(st *State, id string) string {
 func GetUnitEnvUUID(unit *Unit) string {
 	return unit.doc.EnvUUID
 }
+
+// AddCleanup schedules a cleanup of the given kind and prefix.
+func AddCleanup(c *gc.C, st *State, kind, prefix string) {
+	err := st.runTransaction([]txn.Op{st.newCleanupOp(cleanupKind(kind), prefix)})
+	c.Assert(err, gc.IsNil)
+}
+
+// AgentPingerKeys returns the presence keys reported by the pinger
+// shared by the agents on the given machine.
+func AgentPingerKeys(st *State, machineId string) []string {
+	st.agentPingers.mu.Lock()
+	defer st.agentPingers.mu.Unlock()
+	shared := st.agentPingers.machines[machineId]
+	if shared == nil {
+		return nil
+	}
+	return shared.pinger.Keys()
+}
+
+// SetAPITokenExpires changes when the API token with the given id
+// expires.
+func SetAPITokenExpires(c *gc.C, st *State, id string, expires time.Time) {
+	ops := []txn.Op{{
+		C:      apiTokensC,
+		Id:     id,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"expires", expires}}}},
+	}}
+	err := st.runTransaction(ops)
+	c.Assert(err, gc.IsNil)
+}
@@ -0,0 +1,101 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// HookPolicy holds a service's overrides of the environment's
+// hook-timeout, hook-retry-count and hook-retry-delay settings,
+// which control how long its units' hooks may run and how failed
+// hooks are retried. Nil fields are not overridden.
+type HookPolicy struct {
+	Timeout    *time.Duration `bson:"timeout,omitempty"`
+	RetryCount *int           `bson:"retrycount,omitempty"`
+	RetryDelay *time.Duration `bson:"retrydelay,omitempty"`
+}
+
+// validate returns an error if the policy holds negative or zero
+// durations, or a negative retry count.
+func (p HookPolicy) validate() error {
+	if p.Timeout != nil && *p.Timeout <= 0 {
+		return errors.NotValidf("hook timeout %v", *p.Timeout)
+	}
+	if p.RetryCount != nil && *p.RetryCount < 0 {
+		return errors.NotValidf("hook retry count %d", *p.RetryCount)
+	}
+	if p.RetryDelay != nil && *p.RetryDelay <= 0 {
+		return errors.NotValidf("hook retry delay %v", *p.RetryDelay)
+	}
+	return nil
+}
+
+// HookPolicy returns the service's overrides of the environment's
+// hook policy settings.
+func (s *Service) HookPolicy() HookPolicy {
+	if s.doc.HookPolicy == nil {
+		return HookPolicy{}
+	}
+	return *s.doc.HookPolicy
+}
+
+// SetHookPolicy replaces the service's overrides of the environment's
+// hook policy settings.
+func (s *Service) SetHookPolicy(policy HookPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set hook policy for service %q", s)
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	var update bson.D
+	if policy == (HookPolicy{}) {
+		update = bson.D{{"$unset", bson.D{{"hookpolicy", nil}}}}
+	} else {
+		update = bson.D{{"$set", bson.D{{"hookpolicy", &policy}}}}
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: update,
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errNotAlive
+	} else if err != nil {
+		return err
+	}
+	if policy == (HookPolicy{}) {
+		s.doc.HookPolicy = nil
+	} else {
+		s.doc.HookPolicy = &policy
+	}
+	return nil
+}
+
+// EffectiveHookPolicy returns the timeout, retry count and initial
+// retry delay that apply to hooks run by the service's units: the
+// service's overrides, with any values they leave unset taken from
+// the environment configuration.
+func (s *Service) EffectiveHookPolicy() (timeout time.Duration, retryCount int, retryDelay time.Duration, err error) {
+	cfg, err := s.st.EnvironConfig()
+	if err != nil {
+		return 0, 0, 0, errors.Trace(err)
+	}
+	timeout, retryCount, retryDelay = cfg.HookTimeout(), cfg.HookRetryCount(), cfg.HookRetryDelay()
+	policy := s.HookPolicy()
+	if policy.Timeout != nil {
+		timeout = *policy.Timeout
+	}
+	if policy.RetryCount != nil {
+		retryCount = *policy.RetryCount
+	}
+	if policy.RetryDelay != nil {
+		retryDelay = *policy.RetryDelay
+	}
+	return timeout, retryCount, retryDelay, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+type HookPolicySuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&HookPolicySuite{})
+
+func (s *HookPolicySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *HookPolicySuite) TestDefaults(c *gc.C) {
+	c.Assert(s.service.HookPolicy(), gc.DeepEquals, state.HookPolicy{})
+	timeout, retryCount, retryDelay, err := s.service.EffectiveHookPolicy()
+	c.Assert(err, gc.IsNil)
+	c.Assert(timeout, gc.Equals, time.Duration(0))
+	c.Assert(retryCount, gc.Equals, 0)
+	c.Assert(retryDelay, gc.Equals, config.DefaultHookRetryDelay)
+}
+
+func (s *HookPolicySuite) TestSetHookPolicy(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"hook-timeout":     600,
+		"hook-retry-count": 2,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	retryCount := 5
+	retryDelay := time.Minute
+	policy := state.HookPolicy{RetryCount: &retryCount, RetryDelay: &retryDelay}
+	err = s.service.SetHookPolicy(policy)
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.service.HookPolicy(), gc.DeepEquals, policy)
+
+	err = s.service.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.service.HookPolicy(), gc.DeepEquals, policy)
+	timeout, retryCount, retryDelay, err := s.service.EffectiveHookPolicy()
+	c.Assert(err, gc.IsNil)
+	c.Assert(timeout, gc.Equals, 10*time.Minute)
+	c.Assert(retryCount, gc.Equals, 5)
+	c.Assert(retryDelay, gc.Equals, time.Minute)
+
+	// An empty policy clears the overrides.
+	err = s.service.SetHookPolicy(state.HookPolicy{})
+	c.Assert(err, gc.IsNil)
+	err = s.service.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.service.HookPolicy(), gc.DeepEquals, state.HookPolicy{})
+	_, retryCount, _, err = s.service.EffectiveHookPolicy()
+	c.Assert(err, gc.IsNil)
+	c.Assert(retryCount, gc.Equals, 2)
+}
+
+func (s *HookPolicySuite) TestSetHookPolicyInvalid(c *gc.C) {
+	retryCount := -1
+	err := s.service.SetHookPolicy(state.HookPolicy{RetryCount: &retryCount})
+	c.Assert(err, gc.ErrorMatches, `cannot set hook policy for service "wordpress": hook retry count -1 not valid`)
+}
+
+func (s *HookPolicySuite) TestSetHookPolicyNotAlive(c *gc.C) {
+	err := s.service.Destroy()
+	c.Assert(err, gc.IsNil)
+	timeout := time.Minute
+	err = s.service.SetHookPolicy(state.HookPolicy{Timeout: &timeout})
+	c.Assert(err, gc.ErrorMatches, `cannot set hook policy for service "wordpress": not found or not alive`)
+}
@@ -101,6 +101,7 @@ var (
 	_ Annotator = (*Unit)(nil)
 	_ Annotator = (*Service)(nil)
 	_ Annotator = (*Environment)(nil)
+	_ Annotator = (*Relation)(nil)
 )
 
 // NotifyWatcherFactory represents an entity that
@@ -0,0 +1,240 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// leadershipLeaseDuration is the length of time for which a
+// successful leadership claim holds. Leaders must renew their
+// claim before it expires to remain leader.
+var leadershipLeaseDuration = time.Minute
+
+// ErrLeadershipClaimDenied is returned by Service.ClaimLeadership
+// when another unit holds an unexpired leadership lease.
+var ErrLeadershipClaimDenied = stderrors.New("leadership claim denied")
+
+// ErrNotLeader is returned when a unit that is not the current
+// leader of its service attempts to change the leader settings.
+var ErrNotLeader = stderrors.New("unit is not the service leader")
+
+// leadershipDoc records which unit currently leads a service,
+// and when its leadership lease expires.
+type leadershipDoc struct {
+	DocID   string    `bson:"_id"`
+	EnvUUID string    `bson:"env-uuid"`
+	Service string    `bson:"service"`
+	Leader  string    `bson:"leader"`
+	Expiry  time.Time `bson:"expiry"`
+}
+
+// leaderSettingsKey returns the settings collection key for the
+// leader settings of the named service.
+func leaderSettingsKey(serviceName string) string {
+	return fmt.Sprintf("s#%s#leader", serviceName)
+}
+
+// ClaimLeadership claims, or renews, leadership of the service for
+// the named unit. The claim succeeds if the service has no leader,
+// if the current leader's lease has expired, or if the unit is
+// already the leader; otherwise ErrLeadershipClaimDenied is returned.
+// A successful claim holds for leadershipLeaseDuration.
+func (s *Service) ClaimLeadership(unitName string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot claim leadership of service %q for unit %q", s, unitName)
+	unit, err := s.st.Unit(unitName)
+	if err != nil {
+		return err
+	}
+	if unit.ServiceName() != s.doc.Name {
+		return errors.Errorf("unit does not belong to service")
+	}
+	docID := s.st.docID(s.globalKey())
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := unit.Refresh(); err != nil {
+				return nil, err
+			}
+		}
+		if unit.Life() != Alive {
+			return nil, unitNotAliveErr
+		}
+		now := time.Now()
+		expiry := now.Add(leadershipLeaseDuration)
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		doc, err := readLeadership(s.st, docID)
+		if errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      leadershipC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &leadershipDoc{
+					DocID:   docID,
+					EnvUUID: s.st.EnvironTag().Id(),
+					Service: s.doc.Name,
+					Leader:  unitName,
+					Expiry:  expiry,
+				},
+			}), nil
+		} else if err != nil {
+			return nil, err
+		}
+		if doc.Leader != unitName && doc.Expiry.After(now) {
+			return nil, ErrLeadershipClaimDenied
+		}
+		return append(ops, txn.Op{
+			C:  leadershipC,
+			Id: docID,
+			Assert: bson.D{
+				{"leader", doc.Leader},
+				{"expiry", doc.Expiry},
+			},
+			Update: bson.D{{"$set", bson.D{
+				{"leader", unitName},
+				{"expiry", expiry},
+			}}},
+		}), nil
+	}
+	return s.st.run(buildTxn)
+}
+
+// Leader returns the name of the unit currently leading the service.
+// An error satisfying errors.IsNotFound is returned if no unit holds
+// an unexpired leadership lease.
+func (s *Service) Leader() (string, error) {
+	doc, err := readLeadership(s.st, s.st.docID(s.globalKey()))
+	if err != nil {
+		return "", err
+	}
+	if !doc.Expiry.After(time.Now()) {
+		return "", errors.NotFoundf("leader")
+	}
+	return doc.Leader, nil
+}
+
+// LeaderSettings returns the settings written by the service's
+// leaders for the other units of the service to read.
+func (s *Service) LeaderSettings() (map[string]string, error) {
+	settings, err := readSettings(s.st, leaderSettingsKey(s.doc.Name))
+	if errors.IsNotFound(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read leader settings")
+	}
+	result := make(map[string]string)
+	for key, value := range settings.Map() {
+		if value, ok := value.(string); ok {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+// UpdateLeaderSettings applies the given changes to the service's
+// leader settings on behalf of the named unit; keys with empty values
+// are deleted. The change is only made if the unit is the service's
+// current leader, otherwise ErrNotLeader is returned.
+func (s *Service) UpdateLeaderSettings(unitName string, changes map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot update leader settings for service %q", s)
+	docID := s.st.docID(s.globalKey())
+	key := leaderSettingsKey(s.doc.Name)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		now := time.Now()
+		doc, err := readLeadership(s.st, docID)
+		if errors.IsNotFound(err) {
+			return nil, ErrNotLeader
+		} else if err != nil {
+			return nil, err
+		}
+		if doc.Leader != unitName || !doc.Expiry.After(now) {
+			return nil, ErrNotLeader
+		}
+		ops := []txn.Op{{
+			C:  leadershipC,
+			Id: docID,
+			Assert: bson.D{
+				{"leader", unitName},
+				{"expiry", bson.D{{"$gt", now}}},
+			},
+		}}
+		set := bson.M{}
+		unset := bson.M{}
+		for k, v := range changes {
+			if v == "" {
+				unset[escapeReplacer.Replace(k)] = 1
+			} else {
+				set[escapeReplacer.Replace(k)] = v
+			}
+		}
+		_, _, err = readSettingsDoc(s.st, key)
+		if err == mgo.ErrNotFound {
+			if len(set) == 0 {
+				return ops, nil
+			}
+			set["env-uuid"] = s.st.EnvironTag().Id()
+			return append(ops, txn.Op{
+				C:      settingsC,
+				Id:     s.st.docID(key),
+				Assert: txn.DocMissing,
+				Insert: set,
+			}), nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(set) == 0 && len(unset) == 0 {
+			return ops, nil
+		}
+		return append(ops, txn.Op{
+			C:      settingsC,
+			Id:     s.st.docID(key),
+			Assert: txn.DocExists,
+			Update: setUnsetUpdate(set, unset),
+		}), nil
+	}
+	return s.st.run(buildTxn)
+}
+
+// WatchLeaderSettings returns a watcher that notifies of changes
+// to the service's leader settings.
+func (s *Service) WatchLeaderSettings() NotifyWatcher {
+	return newEntityWatcher(s.st, settingsC, s.st.docID(leaderSettingsKey(s.doc.Name)))
+}
+
+func readLeadership(st *State, docID string) (*leadershipDoc, error) {
+	leadership, closer := st.getCollection(leadershipC)
+	defer closer()
+
+	var doc leadershipDoc
+	if err := leadership.FindId(docID).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("leader")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read leadership")
+	}
+	return &doc, nil
+}
+
+// removeLeadershipOps returns the operations required to remove
+// the leadership lease and leader settings of the named service.
+func removeLeadershipOps(st *State, serviceName string) []txn.Op {
+	return []txn.Op{{
+		C:      leadershipC,
+		Id:     st.docID(serviceGlobalKey(serviceName)),
+		Remove: true,
+	}, {
+		C:      settingsC,
+		Id:     st.docID(leaderSettingsKey(serviceName)),
+		Remove: true,
+	}}
+}
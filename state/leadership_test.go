@@ -0,0 +1,132 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type LeadershipSuite struct {
+	ConnSuite
+	service *state.Service
+	unit1   *state.Unit
+}
+
+var _ = gc.Suite(&LeadershipSuite{})
+
+func (s *LeadershipSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	s.unit1, err = s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *LeadershipSuite) TestClaimLeadership(c *gc.C) {
+	_, err := s.service.Leader()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.service.ClaimLeadership("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	leader, err := s.service.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "wordpress/0")
+
+	// The leader can renew its claim; other units are denied.
+	err = s.service.ClaimLeadership("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	err = s.service.ClaimLeadership("wordpress/1")
+	c.Assert(errors.Cause(err), gc.Equals, state.ErrLeadershipClaimDenied)
+	leader, err = s.service.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "wordpress/0")
+}
+
+func (s *LeadershipSuite) TestClaimLeadershipAfterExpiry(c *gc.C) {
+	s.PatchValue(state.LeadershipLeaseDuration, coretesting.ShortWait)
+	err := s.service.ClaimLeadership("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	time.Sleep(2 * coretesting.ShortWait)
+
+	_, err = s.service.Leader()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.service.ClaimLeadership("wordpress/1")
+	c.Assert(err, gc.IsNil)
+	leader, err := s.service.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "wordpress/1")
+}
+
+func (s *LeadershipSuite) TestClaimLeadershipInvalidUnit(c *gc.C) {
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err := mysql.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = s.service.ClaimLeadership("mysql/0")
+	c.Assert(err, gc.ErrorMatches, `cannot claim leadership of service "wordpress" for unit "mysql/0": unit does not belong to service`)
+
+	err = s.unit1.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.service.ClaimLeadership("wordpress/1")
+	c.Assert(err, gc.ErrorMatches, `cannot claim leadership of service "wordpress" for unit "wordpress/1": unit is not alive`)
+}
+
+func (s *LeadershipSuite) TestUpdateLeaderSettings(c *gc.C) {
+	settings, err := s.service.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.HasLen, 0)
+
+	err = s.service.UpdateLeaderSettings("wordpress/0", map[string]string{"foo": "bar"})
+	c.Assert(errors.Cause(err), gc.Equals, state.ErrNotLeader)
+
+	err = s.service.ClaimLeadership("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	err = s.service.UpdateLeaderSettings("wordpress/0", map[string]string{"foo": "bar", "baz.qux": "1"})
+	c.Assert(err, gc.IsNil)
+	err = s.service.UpdateLeaderSettings("wordpress/1", map[string]string{"foo": "nope"})
+	c.Assert(errors.Cause(err), gc.Equals, state.ErrNotLeader)
+	settings, err = s.service.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"foo": "bar", "baz.qux": "1"})
+
+	// Empty values delete keys.
+	err = s.service.UpdateLeaderSettings("wordpress/0", map[string]string{"foo": ""})
+	c.Assert(err, gc.IsNil)
+	settings, err = s.service.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"baz.qux": "1"})
+}
+
+func (s *LeadershipSuite) TestUpdateLeaderSettingsExpiredLease(c *gc.C) {
+	s.PatchValue(state.LeadershipLeaseDuration, coretesting.ShortWait)
+	err := s.service.ClaimLeadership("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	time.Sleep(2 * coretesting.ShortWait)
+	err = s.service.UpdateLeaderSettings("wordpress/0", map[string]string{"foo": "bar"})
+	c.Assert(errors.Cause(err), gc.Equals, state.ErrNotLeader)
+}
+
+func (s *LeadershipSuite) TestWatchLeaderSettings(c *gc.C) {
+	err := s.service.ClaimLeadership("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	w := s.service.WatchLeaderSettings()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err = s.service.UpdateLeaderSettings("wordpress/0", map[string]string{"foo": "bar"})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = s.service.UpdateLeaderSettings("wordpress/0", map[string]string{"foo": "baz"})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+}
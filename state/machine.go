@@ -477,6 +477,20 @@ func IsHasContainersError(err error) bool {
 	return ok
 }
 
+var machineHasNoPrincipals = bson.D{{
+	"$or", []bson.D{
+		{{"principals", bson.D{{"$size", 0}}}},
+		{{"principals", bson.D{{"$exists", false}}}},
+	},
+}}
+
+var machineHasNoContainers = bson.D{{
+	"$or", []bson.D{
+		{{"children", bson.D{{"$size", 0}}}},
+		{{"children", bson.D{{"$exists", false}}}},
+	},
+}}
+
 // advanceLifecycle ensures that the machine's lifecycle is no earlier
 // than the supplied value. If the machine already has that lifecycle
 // value, or a later one, no changes will be made to remote state. If
@@ -513,14 +527,10 @@ func (original *Machine) advanceLifecycle(life Life) (err error) {
 		Id:     m.doc.DocID,
 		Update: bson.D{{"$set", bson.D{{"life", life}}}},
 	}
-	advanceAsserts := bson.D{
+	advanceAsserts := append(bson.D{
 		{"jobs", bson.D{{"$nin", []MachineJob{JobManageEnviron}}}},
-		{"$or", []bson.D{
-			{{"principals", bson.D{{"$size", 0}}}},
-			{{"principals", bson.D{{"$exists", false}}}},
-		}},
 		{"hasvote", bson.D{{"$ne", true}}},
-	}
+	}, machineHasNoPrincipals...)
 	// multiple attempts: one with original data, one with refreshed data, and a final
 	// one intended to determine the cause of failure of the preceding attempt.
 	buildTxn := func(attempt int) ([]txn.Op, error) {
@@ -602,7 +612,7 @@ func (m *Machine) removeNetworkInterfacesOps() ([]txn.Op, error) {
 		Id:     m.doc.DocID,
 		Assert: isDeadDoc,
 	}}
-	sel := bson.D{{"machineid", m.doc.Id}}
+	sel := bson.D{{"env-uuid", m.st.EnvironTag().Id()}, {"machineid", m.doc.Id}}
 	networkInterfaces, closer := m.st.getCollection(networkInterfacesC)
 	defer closer()
 
@@ -647,6 +657,8 @@ func (m *Machine) Remove() (err error) {
 		removeRequestedNetworksOp(m.st, m.globalKey()),
 		annotationRemoveOp(m.st, m.globalKey()),
 		removeRebootDocOp(m.st, m.globalKey()),
+		removeAgentVersionPinOp(m.st, m.globalKey()),
+		removeMachineUtilizationOp(m.st, m.globalKey()),
 	}
 	ifacesOps, err := m.removeNetworkInterfacesOps()
 	if err != nil {
@@ -803,14 +815,15 @@ func (m *Machine) Units() (units []*Unit, err error) {
 	defer closer()
 
 	pudocs := []unitDoc{}
-	err = unitsCollection.Find(bson.D{{"machineid", m.doc.Id}}).All(&pudocs)
+	envUUID := m.st.EnvironTag().Id()
+	err = unitsCollection.Find(bson.D{{"machineid", m.doc.Id}, {"env-uuid", envUUID}}).All(&pudocs)
 	if err != nil {
 		return nil, err
 	}
 	for _, pudoc := range pudocs {
 		units = append(units, newUnit(m.st, &pudoc))
 		docs := []unitDoc{}
-		err = unitsCollection.Find(bson.D{{"principal", pudoc.Name}}).All(&docs)
+		err = unitsCollection.Find(bson.D{{"principal", pudoc.Name}, {"env-uuid", envUUID}}).All(&docs)
 		if err != nil {
 			return nil, err
 		}
@@ -1059,7 +1072,11 @@ func (m *Machine) Networks() ([]*Network, error) {
 	networksCollection, closer := m.st.getCollection(networksC)
 	defer closer()
 
-	sel := bson.D{{"_id", bson.D{{"$in", requestedNetworks}}}}
+	networkIds := make([]string, len(requestedNetworks))
+	for i, name := range requestedNetworks {
+		networkIds[i] = m.st.docID(name)
+	}
+	sel := bson.D{{"_id", bson.D{{"$in", networkIds}}}}
 	err = networksCollection.Find(sel).All(&docs)
 	if err != nil {
 		return nil, err
@@ -1078,7 +1095,8 @@ func (m *Machine) NetworkInterfaces() ([]*NetworkInterface, error) {
 	defer closer()
 
 	docs := []networkInterfaceDoc{}
-	err := networkInterfaces.Find(bson.D{{"machineid", m.doc.Id}}).All(&docs)
+	sel := bson.D{{"env-uuid", m.st.EnvironTag().Id()}, {"machineid", m.doc.Id}}
+	err := networkInterfaces.Find(sel).All(&docs)
 	if err != nil {
 		return nil, err
 	}
@@ -1107,12 +1125,12 @@ func (m *Machine) AddNetworkInterface(args NetworkInterfaceInfo) (iface *Network
 	if args.InterfaceName == "" {
 		return nil, fmt.Errorf("interface name must be not empty")
 	}
-	doc := newNetworkInterfaceDoc(args)
+	doc := newNetworkInterfaceDoc(m.st, args)
 	doc.MachineId = m.doc.Id
 	doc.Id = bson.NewObjectId()
 	ops := []txn.Op{{
 		C:      networksC,
-		Id:     args.NetworkName,
+		Id:     m.st.docID(args.NetworkName),
 		Assert: txn.DocExists,
 	}, {
 		C:      machinesC,
@@ -1153,11 +1171,19 @@ func (m *Machine) AddNetworkInterface(args NetworkInterfaceInfo) (iface *Network
 		if err = networkInterfaces.FindId(doc.Id).One(&doc); err == nil {
 			return newNetworkInterface(m.st, doc), nil
 		}
-		sel := bson.D{{"interfacename", args.InterfaceName}, {"machineid", m.doc.Id}}
+		sel := bson.D{
+			{"env-uuid", m.st.EnvironTag().Id()},
+			{"interfacename", args.InterfaceName},
+			{"machineid", m.doc.Id},
+		}
 		if err = networkInterfaces.Find(sel).One(nil); err == nil {
 			return nil, errors.AlreadyExistsf("%q on machine %q", args.InterfaceName, m.doc.Id)
 		}
-		sel = bson.D{{"macaddress", args.MACAddress}, {"networkname", args.NetworkName}}
+		sel = bson.D{
+			{"env-uuid", m.st.EnvironTag().Id()},
+			{"macaddress", args.MACAddress},
+			{"networkname", args.NetworkName},
+		}
 		if err = networkInterfaces.Find(sel).One(nil); err == nil {
 			return nil, errors.AlreadyExistsf("MAC address %q on network %q", args.MACAddress, args.NetworkName)
 		}
@@ -1246,6 +1272,17 @@ func (m *Machine) Status() (status Status, info string, data map[string]interfac
 	return
 }
 
+// StatusSince returns the time the machine's status last changed. The
+// time is zero if the status was set by a version of juju that did
+// not record it.
+func (m *Machine) StatusSince() (time.Time, error) {
+	doc, err := getStatus(m.st, m.globalKey())
+	if err != nil {
+		return time.Time{}, err
+	}
+	return doc.Since, nil
+}
+
 // SetStatus sets the status of the machine.
 func (m *Machine) SetStatus(status Status, info string, data map[string]interface{}) error {
 	doc := statusDoc{
@@ -1257,15 +1294,30 @@ func (m *Machine) SetStatus(status Status, info string, data map[string]interfac
 	// to be set back to pending (when a retry is to occur).
 	_, err := m.InstanceId()
 	allowPending := IsNotProvisionedError(err)
+	statusOp := updateStatusOp(m.st, m.globalKey(), doc)
+	if !allowPending && status == StatusPending {
+		// A provisioned machine may remain pending, so the provisioner
+		// can report progress, until its agent first sets a status.
+		current, _, _, err := m.Status()
+		if err != nil {
+			return fmt.Errorf("cannot set status of machine %q: %v", m, err)
+		}
+		if current == StatusPending {
+			allowPending = true
+			statusOp.Assert = bson.D{{"status", StatusPending}}
+		}
+	}
 	if err := doc.validateSet(allowPending); err != nil {
 		return err
 	}
+	doc.Since = statusSince(m.st, m.globalKey(), doc)
+	statusOp.Update = bson.D{{"$set", doc}}
 	ops := []txn.Op{{
 		C:      machinesC,
 		Id:     m.doc.DocID,
 		Assert: notDeadDoc,
 	},
-		updateStatusOp(m.st, m.globalKey(), doc),
+		statusOp,
 	}
 	if err := m.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set status of machine %q: %v", m, onAbort(err, errNotAlive))
@@ -1128,6 +1128,58 @@ func (s *MachineSuite) TestWatchMachine(c *gc.C) {
 	testing.NewNotifyWatcherC(c, s.State, w).AssertOneChange()
 }
 
+func (s *MachineSuite) TestWatchForwardedPorts(c *gc.C) {
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	w := s.machine.WatchForwardedPorts()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Adding a container, or changing its addresses, is reported.
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.machine.Id(), instance.LXC)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = container.SetAddresses(network.NewAddress("10.0.3.5", network.ScopeCloudLocal))
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	// Opening ports on the container is reported.
+	unit, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(container)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = unit.OpenPort("tcp", 80)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	// Exposing a service is reported.
+	err = svc.SetExposed()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	// Other machines, and ports opened on them, are not reported.
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	wc.AssertNoChange()
+	otherUnit, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = otherUnit.AssignToMachine(other)
+	c.Assert(err, gc.IsNil)
+	err = otherUnit.OpenPort("tcp", 80)
+	c.Assert(err, gc.IsNil)
+	wc.AssertNoChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *MachineSuite) TestWatchDiesOnStateClose(c *gc.C) {
 	// This test is testing logic in watcher.entityWatcher, which
 	// is also used by:
@@ -1515,9 +1567,25 @@ func (s *MachineSuite) TestGetSetStatusWhileAlive(c *gc.C) {
 func (s *MachineSuite) TestSetStatusPending(c *gc.C) {
 	err := s.machine.SetStatus(state.StatusPending, "", nil)
 	c.Assert(err, gc.IsNil)
-	// Cannot set status to pending once a machine is provisioned.
+	// A provisioned machine may remain pending, recording progress,
+	// until its status is first set to something else.
 	err = s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
 	c.Assert(err, gc.IsNil)
+	err = s.machine.SetStatus(state.StatusPending, "waiting for machine agent", map[string]interface{}{
+		"provisioning": "instance started",
+	})
+	c.Assert(err, gc.IsNil)
+	status, info, data, err := s.machine.Status()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, state.StatusPending)
+	c.Assert(info, gc.Equals, "waiting for machine agent")
+	c.Assert(data, gc.DeepEquals, map[string]interface{}{
+		"provisioning": "instance started",
+	})
+
+	// Cannot set status back to pending once the machine has started.
+	err = s.machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
 	err = s.machine.SetStatus(state.StatusPending, "", nil)
 	c.Assert(err, gc.ErrorMatches, `cannot set status "pending"`)
 }
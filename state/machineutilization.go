@@ -0,0 +1,147 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// MachineUtilization describes the resource usage of a machine's host,
+// as last reported by its machine agent.
+type MachineUtilization struct {
+	// LoadAverage is the one minute load average.
+	LoadAverage float64
+
+	// MemoryUsed and MemoryTotal hold the memory in use and the total
+	// memory, in megabytes.
+	MemoryUsed  uint64
+	MemoryTotal uint64
+
+	// DiskFree holds the space available, in megabytes, on the
+	// filesystem holding the agent's data directory.
+	DiskFree uint64
+
+	// Updated holds the time the utilization was reported.
+	Updated time.Time
+}
+
+// String returns a summary of the utilization suitable for status
+// output.
+func (u MachineUtilization) String() string {
+	return fmt.Sprintf("load=%.2f mem=%dM/%dM disk-free=%dM",
+		u.LoadAverage, u.MemoryUsed, u.MemoryTotal, u.DiskFree)
+}
+
+// machineUtilizationDoc records the most recently reported resource
+// usage of a machine.
+type machineUtilizationDoc struct {
+	DocID       string    `bson:"_id"`
+	EnvUUID     string    `bson:"env-uuid"`
+	LoadAverage float64   `bson:"loadaverage"`
+	MemoryUsed  uint64    `bson:"memoryused"`
+	MemoryTotal uint64    `bson:"memorytotal"`
+	DiskFree    uint64    `bson:"diskfree"`
+	Updated     time.Time `bson:"updated"`
+}
+
+// SetUtilization records the resource usage of the machine, replacing
+// any previously recorded usage. The update time is set by state.
+func (m *Machine) SetUtilization(u MachineUtilization) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set utilization for machine %v", m)
+	key := m.globalKey()
+	doc := machineUtilizationDoc{
+		DocID:       m.st.docID(key),
+		EnvUUID:     m.st.EnvironTag().Id(),
+		LoadAverage: u.LoadAverage,
+		MemoryUsed:  u.MemoryUsed,
+		MemoryTotal: u.MemoryTotal,
+		DiskFree:    u.DiskFree,
+		Updated:     nowToTheSecond(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if notDead, err := isNotDead(m.st.db, machinesC, m.doc.DocID); err != nil {
+			return nil, err
+		} else if !notDead {
+			return nil, ErrDead
+		}
+		_, err := readMachineUtilization(m.st, key)
+		op := txn.Op{
+			C:      machineUtilizationC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}
+		if err == nil {
+			op = txn.Op{
+				C:      machineUtilizationC,
+				Id:     doc.DocID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{
+					{"loadaverage", doc.LoadAverage},
+					{"memoryused", doc.MemoryUsed},
+					{"memorytotal", doc.MemoryTotal},
+					{"diskfree", doc.DiskFree},
+					{"updated", doc.Updated},
+				}}},
+			}
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: notDeadDoc,
+		}, op}, nil
+	}
+	return m.st.run(buildTxn)
+}
+
+// Utilization returns the most recently reported resource usage of
+// the machine. An error satisfying errors.IsNotFound is returned if
+// no usage has been reported.
+func (m *Machine) Utilization() (MachineUtilization, error) {
+	doc, err := readMachineUtilization(m.st, m.globalKey())
+	if errors.IsNotFound(err) {
+		return MachineUtilization{}, errors.NotFoundf("utilization for machine %v", m)
+	} else if err != nil {
+		return MachineUtilization{}, err
+	}
+	return MachineUtilization{
+		LoadAverage: doc.LoadAverage,
+		MemoryUsed:  doc.MemoryUsed,
+		MemoryTotal: doc.MemoryTotal,
+		DiskFree:    doc.DiskFree,
+		Updated:     doc.Updated,
+	}, nil
+}
+
+func readMachineUtilization(st *State, key string) (*machineUtilizationDoc, error) {
+	utilization, closer := st.getCollection(machineUtilizationC)
+	defer closer()
+
+	var doc machineUtilizationDoc
+	if err := utilization.FindId(st.docID(key)).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("machine utilization")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read machine utilization")
+	}
+	return &doc, nil
+}
+
+// removeMachineUtilizationOp returns the operation required to remove
+// the recorded utilization for the machine with the given global key.
+// It is a no-op if no utilization has been recorded.
+func removeMachineUtilizationOp(st *State, key string) txn.Op {
+	return txn.Op{
+		C:      machineUtilizationC,
+		Id:     st.docID(key),
+		Remove: true,
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type MachineUtilizationSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&MachineUtilizationSuite{})
+
+func (s *MachineUtilizationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *MachineUtilizationSuite) TestUtilizationNotFound(c *gc.C) {
+	_, err := s.machine.Utilization()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "utilization for machine 0 not found")
+}
+
+func (s *MachineUtilizationSuite) TestSetUtilization(c *gc.C) {
+	err := s.machine.SetUtilization(state.MachineUtilization{
+		LoadAverage: 0.5,
+		MemoryUsed:  512,
+		MemoryTotal: 2048,
+		DiskFree:    10240,
+	})
+	c.Assert(err, gc.IsNil)
+	err = s.machine.SetUtilization(state.MachineUtilization{
+		LoadAverage: 1.25,
+		MemoryUsed:  1024,
+		MemoryTotal: 2048,
+		DiskFree:    100,
+	})
+	c.Assert(err, gc.IsNil)
+
+	u, err := s.machine.Utilization()
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Updated.IsZero(), jc.IsFalse)
+	c.Assert(u.LoadAverage, gc.Equals, 1.25)
+	c.Assert(u.MemoryUsed, gc.Equals, uint64(1024))
+	c.Assert(u.MemoryTotal, gc.Equals, uint64(2048))
+	c.Assert(u.DiskFree, gc.Equals, uint64(100))
+	c.Assert(u.String(), gc.Equals, "load=1.25 mem=1024M/2048M disk-free=100M")
+}
+
+func (s *MachineUtilizationSuite) TestSetUtilizationDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.machine.SetUtilization(state.MachineUtilization{LoadAverage: 0.5})
+	c.Assert(err, gc.ErrorMatches, "cannot set utilization for machine 0: not found or dead")
+}
+
+func (s *MachineUtilizationSuite) TestRemoveMachineRemovesUtilization(c *gc.C) {
+	err := s.machine.SetUtilization(state.MachineUtilization{LoadAverage: 0.5})
+	c.Assert(err, gc.IsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.machine.Remove()
+	c.Assert(err, gc.IsNil)
+	_, err = s.machine.Utilization()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state/multiwatcher"
@@ -249,12 +250,12 @@ func (r *backingRelation) updated(st *State, store *multiwatcher.Store, id inter
 func (r *backingRelation) removed(st *State, store *multiwatcher.Store, id interface{}) {
 	store.Remove(params.EntityId{
 		Kind: "relation",
-		Id:   id,
+		Id:   st.localID(id.(string)),
 	})
 }
 
 func (r *backingRelation) mongoId() interface{} {
-	return r.Key
+	return r.DocID
 }
 
 type backingAnnotation annotatorDoc
@@ -269,7 +270,7 @@ func (a *backingAnnotation) updated(st *State, store *multiwatcher.Store, id int
 }
 
 func (a *backingAnnotation) removed(st *State, store *multiwatcher.Store, id interface{}) {
-	tag, ok := tagForGlobalKey(id.(string))
+	tag, ok := tagForGlobalKey(st.localID(id.(string)))
 	if !ok {
 		panic(fmt.Errorf("unknown global key %q in state", id))
 	}
@@ -280,13 +281,13 @@ func (a *backingAnnotation) removed(st *State, store *multiwatcher.Store, id int
 }
 
 func (a *backingAnnotation) mongoId() interface{} {
-	return a.GlobalKey
+	return a.DocID
 }
 
 type backingStatus statusDoc
 
 func (s *backingStatus) updated(st *State, store *multiwatcher.Store, id interface{}) error {
-	parentId, ok := backingEntityIdForGlobalKey(id.(string))
+	parentId, ok := backingEntityIdForGlobalKey(st.localID(id.(string)))
 	if !ok {
 		return nil
 	}
@@ -326,7 +327,12 @@ func (s *backingStatus) mongoId() interface{} {
 type backingConstraints constraintsDoc
 
 func (c *backingConstraints) updated(st *State, store *multiwatcher.Store, id interface{}) error {
-	parentId, ok := backingEntityIdForGlobalKey(id.(string))
+	localID, err := st.strictLocalID(id.(string))
+	if err != nil {
+		// The constraints belong to another environment.
+		return nil
+	}
+	parentId, ok := backingEntityIdForGlobalKey(localID)
 	if !ok {
 		return nil
 	}
@@ -358,7 +364,12 @@ func (c *backingConstraints) mongoId() interface{} {
 type backingSettings map[string]interface{}
 
 func (s *backingSettings) updated(st *State, store *multiwatcher.Store, id interface{}) error {
-	parentId, url, ok := backingEntityIdForSettingsKey(id.(string))
+	localID, err := st.strictLocalID(id.(string))
+	if err != nil {
+		// The settings belong to another environment.
+		return nil
+	}
+	parentId, url, ok := backingEntityIdForSettingsKey(localID)
 	if !ok {
 		return nil
 	}
@@ -523,10 +534,11 @@ func newAllWatcherStateBacking(st *State) multiwatcher.Backing {
 	return b
 }
 
-// Watch watches all the collections.
+// Watch watches the documents of the environment in all the collections.
 func (b *allWatcherStateBacking) Watch(in chan<- watcher.Change) {
+	filter := envFilter(b.st, nil)
 	for _, c := range b.collectionByName {
-		b.st.watcher.WatchCollection(c.Name, in)
+		b.st.watcher.WatchCollectionWithFilter(c.Name, in, filter)
 	}
 }
 
@@ -537,11 +549,13 @@ func (b *allWatcherStateBacking) Unwatch(in chan<- watcher.Change) {
 	}
 }
 
-// GetAll fetches all items that we want to watch from the state.
+// GetAll fetches all items of the environment that we want to watch
+// from the state.
 func (b *allWatcherStateBacking) GetAll(all *multiwatcher.Store) error {
 	db, closer := b.st.newDB()
 	defer closer()
 
+	envSelector := bson.D{{"env-uuid", b.st.EnvironTag().Id()}}
 	// TODO(rog) fetch collections concurrently?
 	for _, c := range b.collectionByName {
 		if c.subsidiary {
@@ -549,7 +563,7 @@ func (b *allWatcherStateBacking) GetAll(all *multiwatcher.Store) error {
 		}
 		col := db.C(c.Name)
 		infoSlicePtr := reflect.New(reflect.SliceOf(c.infoType))
-		if err := col.Find(nil).All(infoSlicePtr.Interface()); err != nil {
+		if err := col.Find(envSelector).All(infoSlicePtr.Interface()); err != nil {
 			return fmt.Errorf("cannot get all %s: %v", c.Name, err)
 		}
 		infos := infoSlicePtr.Elem()
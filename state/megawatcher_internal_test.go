@@ -600,7 +600,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "relations",
-					Id: "logging:logging-directory wordpress:logging-dir",
+					Id: st.docID("logging:logging-directory wordpress:logging-dir"),
 				}
 			},
 		}, {
@@ -610,7 +610,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "relations",
-					Id: "logging:logging-directory wordpress:logging-dir",
+					Id: st.docID("logging:logging-directory wordpress:logging-dir"),
 				}
 			},
 		}, {
@@ -627,7 +627,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "relations",
-					Id: "logging:logging-directory wordpress:logging-dir",
+					Id: st.docID("logging:logging-directory wordpress:logging-dir"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -646,7 +646,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "relations",
-					Id: "m#0",
+					Id: st.docID("m#0"),
 				}
 			},
 		}, {
@@ -656,7 +656,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "annotations",
-					Id: "m#0",
+					Id: st.docID("m#0"),
 				}
 			},
 		}, {
@@ -670,7 +670,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "annotations",
-					Id: "m#0",
+					Id: st.docID("m#0"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -702,7 +702,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "annotations",
-					Id: "m#0",
+					Id: st.docID("m#0"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -722,7 +722,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "statuses",
-					Id: "u#wordpress/0",
+					Id: st.docID("u#wordpress/0"),
 				}
 			},
 		}, {
@@ -736,7 +736,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "statuses",
-					Id: "u#wordpress/0",
+					Id: st.docID("u#wordpress/0"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -763,7 +763,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "statuses",
-					Id: "u#wordpress/0",
+					Id: st.docID("u#wordpress/0"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -793,7 +793,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "statuses",
-					Id: "u#wordpress/0",
+					Id: st.docID("u#wordpress/0"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -816,7 +816,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "statuses",
-					Id: "m#0",
+					Id: st.docID("m#0"),
 				}
 			},
 		}, {
@@ -830,7 +830,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "statuses",
-					Id: "m#0",
+					Id: st.docID("m#0"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{&params.MachineInfo{
@@ -854,7 +854,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "statuses",
-					Id: "m#0",
+					Id: st.docID("m#0"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -872,7 +872,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "constraints",
-					Id: "s#wordpress",
+					Id: st.docID("s#wordpress"),
 				}
 			},
 		}, {
@@ -885,7 +885,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "constraints",
-					Id: "s#wordpress",
+					Id: st.docID("s#wordpress"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{&params.ServiceInfo{
@@ -906,7 +906,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "constraints",
-					Id: "s#wordpress",
+					Id: st.docID("s#wordpress"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -923,7 +923,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "settings",
-					Id: "s#wordpress#local:quantal/quantal-wordpress-3",
+					Id: st.docID("s#wordpress#local:quantal/quantal-wordpress-3"),
 				}
 			},
 		}, {
@@ -936,7 +936,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "settings",
-					Id: "s#wordpress#local:quantal/quantal-wordpress-3",
+					Id: st.docID("s#wordpress#local:quantal/quantal-wordpress-3"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{&params.ServiceInfo{
@@ -957,7 +957,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "settings",
-					Id: "s#wordpress#local:quantal/quantal-wordpress-3",
+					Id: st.docID("s#wordpress#local:quantal/quantal-wordpress-3"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -985,7 +985,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "settings",
-					Id: "s#wordpress#local:quantal/quantal-wordpress-3",
+					Id: st.docID("s#wordpress#local:quantal/quantal-wordpress-3"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -1009,7 +1009,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "settings",
-					Id: "s#wordpress#local:quantal/quantal-wordpress-3",
+					Id: st.docID("s#wordpress#local:quantal/quantal-wordpress-3"),
 				}
 			},
 			expectContents: []multiwatcher.EntityInfo{
@@ -1025,7 +1025,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "settings",
-					Id: "m#0",
+					Id: st.docID("m#0"),
 				}
 			},
 		}, {
@@ -1034,7 +1034,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 			change: func(st *State) watcher.Change {
 				return watcher.Change{
 					C:  "settings",
-					Id: "s#foo",
+					Id: st.docID("s#foo"),
 				}
 			},
 		},
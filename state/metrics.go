@@ -109,7 +109,7 @@ func (st *State) MetricBatches() ([]MetricBatch, error) {
 	c, closer := st.getCollection(metricsC)
 	defer closer()
 	docs := []metricBatchDoc{}
-	err := c.Find(nil).All(&docs)
+	err := c.Find(bson.M{"env-uuid": st.EnvironTag().Id()}).All(&docs)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -145,8 +145,9 @@ func (st *State) CleanupOldMetrics() error {
 	// to watch them either; so in this instance it's safe to do an end run around the
 	// mgo/txn package. See State.cleanupRelationSettings for a similar situation.
 	err := c.Remove(bson.M{
-		"sent":    true,
-		"created": bson.M{"$lte": age},
+		"env-uuid": st.EnvironTag().Id(),
+		"sent":     true,
+		"created":  bson.M{"$lte": age},
 	})
 	if err == mgo.ErrNotFound {
 		metricsLogger.Infof("no metrics found to cleanup")
@@ -162,7 +163,8 @@ func (st *State) MetricsToSend(batchSize int) ([]*MetricBatch, error) {
 	c, closer := st.getCollection(metricsC)
 	defer closer()
 	err := c.Find(bson.M{
-		"sent": false,
+		"env-uuid": st.EnvironTag().Id(),
+		"sent":     false,
 	}).Limit(batchSize).All(&docs)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -183,7 +185,8 @@ func (st *State) CountofUnsentMetrics() (int, error) {
 	c, closer := st.getCollection(metricsC)
 	defer closer()
 	return c.Find(bson.M{
-		"sent": false,
+		"env-uuid": st.EnvironTag().Id(),
+		"sent":     false,
 	}).Count()
 }
 
@@ -194,7 +197,8 @@ func (st *State) CountofSentMetrics() (int, error) {
 	c, closer := st.getCollection(metricsC)
 	defer closer()
 	return c.Find(bson.M{
-		"sent": true,
+		"env-uuid": st.EnvironTag().Id(),
+		"sent":     true,
 	}).Count()
 }
 
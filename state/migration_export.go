@@ -0,0 +1,425 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/migration"
+)
+
+// Export returns a description of the environment, from which it can
+// be recreated in another state server with Import. All the
+// environment's machines, services, units, relations and storage
+// instances must be alive, and no unit may be part way through a charm
+// upgrade.
+func (st *State) Export() (_ *migration.Model, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot export environment")
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if env.Life() != Alive {
+		return nil, errors.New("environment is no longer alive")
+	}
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cons, err := st.EnvironConstraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	model := &migration.Model{
+		Version:     migration.Version,
+		Owner:       env.Owner().Username(),
+		Config:      cfg.AllAttrs(),
+		Constraints: cons.String(),
+	}
+	if model.Annotations, err = exportAnnotations(st, env.globalKey()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if model.Users, err = st.exportUsers(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if model.Machines, err = st.exportMachines(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var charmURLs []string
+	for _, service := range services {
+		exported, err := st.exportService(service)
+		if err != nil {
+			return nil, errors.Annotatef(err, "service %q", service.Name())
+		}
+		model.Services = append(model.Services, exported)
+		if !hasString(charmURLs, exported.CharmURL) {
+			charmURLs = append(charmURLs, exported.CharmURL)
+		}
+	}
+	sort.Strings(charmURLs)
+	for _, url := range charmURLs {
+		exported, err := st.exportCharm(url)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		model.Charms = append(model.Charms, exported)
+	}
+	if model.Relations, err = st.exportRelations(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return model, nil
+}
+
+func (st *State) exportUsers() ([]migration.User, error) {
+	envUsers, closer := st.getCollection(envUsersC)
+	defer closer()
+
+	var docs []envUserDoc
+	if err := envUsers.Find(bson.D{{"envuuid", st.EnvironTag().Id()}}).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get environment users")
+	}
+	users := make([]migration.User, len(docs))
+	for i, doc := range docs {
+		users[i] = migration.User{
+			Name:        doc.UserName,
+			DisplayName: doc.DisplayName,
+			CreatedBy:   doc.CreatedBy,
+			Access:      string(doc.Access),
+		}
+	}
+	return users, nil
+}
+
+func (st *State) exportMachines() ([]migration.Machine, error) {
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	exported := make(map[string]migration.Machine)
+	for _, machine := range machines {
+		if machine.Life() != Alive {
+			return nil, errors.Errorf("machine %s is not alive", machine.Id())
+		}
+		m, err := st.exportMachine(machine)
+		if err != nil {
+			return nil, errors.Annotatef(err, "machine %s", machine.Id())
+		}
+		exported[machine.Id()] = m
+	}
+	// Containers are described within their host machines.
+	var nest func(id string) migration.Machine
+	nest = func(id string) migration.Machine {
+		m := exported[id]
+		for _, machine := range machines {
+			if parentId, ok := machine.ParentId(); ok && parentId == id {
+				m.Containers = append(m.Containers, nest(machine.Id()))
+			}
+		}
+		return m
+	}
+	var result []migration.Machine
+	for _, machine := range machines {
+		if _, ok := machine.ParentId(); !ok {
+			result = append(result, nest(machine.Id()))
+		}
+	}
+	return result, nil
+}
+
+func (st *State) exportMachine(m *Machine) (migration.Machine, error) {
+	result := migration.Machine{
+		Id:               m.doc.Id,
+		Nonce:            m.doc.Nonce,
+		Series:           m.doc.Series,
+		ContainerType:    m.doc.ContainerType,
+		PasswordHash:     m.doc.PasswordHash,
+		Placement:        m.doc.Placement,
+		Addresses:        exportAddresses(m.doc.Addresses),
+		MachineAddresses: exportAddresses(m.doc.MachineAddresses),
+	}
+	for _, job := range m.doc.Jobs {
+		result.Jobs = append(result.Jobs, job.String())
+	}
+	cons, err := readConstraints(st, m.globalKey())
+	if err != nil && !errors.IsNotFound(err) {
+		return result, errors.Trace(err)
+	}
+	result.Constraints = cons.String()
+	if result.RequestedNetworks, err = readRequestedNetworks(st, m.globalKey()); err != nil {
+		return result, errors.Trace(err)
+	}
+	instData, err := getInstanceData(st, m.doc.Id)
+	if err == nil {
+		result.Instance = &migration.Instance{
+			Id:       string(instData.InstanceId),
+			Status:   instData.Status,
+			Hardware: hardwareCharacteristics(instData).String(),
+		}
+	} else if !errors.IsNotFound(err) {
+		return result, errors.Trace(err)
+	}
+	if result.Status, err = exportStatus(st, m.globalKey()); err != nil {
+		return result, errors.Trace(err)
+	}
+	if result.Annotations, err = exportAnnotations(st, m.globalKey()); err != nil {
+		return result, errors.Trace(err)
+	}
+	ports, err := m.AllPorts()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, p := range ports {
+		networkName, err := p.NetworkName()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		opened := migration.OpenedPorts{Network: networkName}
+		for _, r := range p.doc.Ports {
+			opened.Ports = append(opened.Ports, migration.PortRange{
+				Unit:     r.UnitName,
+				FromPort: r.FromPort,
+				ToPort:   r.ToPort,
+				Protocol: r.Protocol,
+			})
+		}
+		result.OpenedPorts = append(result.OpenedPorts, opened)
+	}
+	return result, nil
+}
+
+func exportAddresses(addrs []address) []migration.Address {
+	var result []migration.Address
+	for _, addr := range addrs {
+		result = append(result, migration.Address{
+			Value:       addr.Value,
+			Type:        string(addr.AddressType),
+			NetworkName: addr.NetworkName,
+			Scope:       string(addr.Scope),
+		})
+	}
+	return result
+}
+
+// exportAnnotations returns the annotations of the entity with the
+// given global key, or nil if it has none.
+func exportAnnotations(st *State, globalKey string) (map[string]string, error) {
+	annotations, closer := st.getCollection(annotationsC)
+	defer closer()
+
+	var doc annotatorDoc
+	err := annotations.FindId(st.docID(globalKey)).One(&doc)
+	if err == mgo.ErrNotFound || err == nil && len(doc.Annotations) == 0 {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get annotations of %q", globalKey)
+	}
+	return doc.Annotations, nil
+}
+
+func exportStatus(st *State, globalKey string) (migration.Status, error) {
+	doc, err := getStatus(st, globalKey)
+	if err != nil {
+		return migration.Status{}, errors.Trace(err)
+	}
+	return migration.Status{
+		Status: string(doc.Status),
+		Info:   doc.StatusInfo,
+		Data:   doc.StatusData,
+	}, nil
+}
+
+func (st *State) exportService(s *Service) (migration.Service, error) {
+	result := migration.Service{
+		Name:        s.doc.Name,
+		Series:      s.doc.Series,
+		Subordinate: s.doc.Subordinate,
+		CharmURL:    s.doc.CharmURL.String(),
+		ForceCharm:  s.doc.ForceCharm,
+		Exposed:     s.doc.Exposed,
+		MinUnits:    s.doc.MinUnits,
+		UnitSeq:     s.doc.UnitSeq,
+		Owner:       s.doc.OwnerTag,
+	}
+	if s.doc.Life != Alive {
+		return result, errors.New("service is not alive")
+	}
+	settings, err := readSettings(st, s.settingsKey())
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Settings = settings.Map()
+	cons, err := s.Constraints()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Constraints = cons.String()
+	if result.RequestedNetworks, err = readRequestedNetworks(st, s.globalKey()); err != nil {
+		return result, errors.Trace(err)
+	}
+	if result.Annotations, err = exportAnnotations(st, s.globalKey()); err != nil {
+		return result, errors.Trace(err)
+	}
+	units, err := s.AllUnits()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, u := range units {
+		if u.doc.Life != Alive {
+			return result, errors.Errorf("unit %s is not alive", u.doc.Name)
+		}
+		unit := migration.Unit{
+			Name:         u.doc.Name,
+			Machine:      u.doc.MachineId,
+			Principal:    u.doc.Principal,
+			Subordinates: u.doc.Subordinates,
+			PasswordHash: u.doc.PasswordHash,
+		}
+		if u.doc.CharmURL != nil {
+			if *u.doc.CharmURL != *s.doc.CharmURL {
+				return result, errors.Errorf("unit %s is upgrading its charm", u.doc.Name)
+			}
+			unit.CharmURL = u.doc.CharmURL.String()
+		}
+		var cons constraints.Value
+		if cons, err = readConstraints(st, u.globalKey()); err != nil && !errors.IsNotFound(err) {
+			return result, errors.Trace(err)
+		}
+		unit.Constraints = cons.String()
+		if unit.Status, err = exportStatus(st, u.globalKey()); err != nil {
+			return result, errors.Trace(err)
+		}
+		if unit.Annotations, err = exportAnnotations(st, u.globalKey()); err != nil {
+			return result, errors.Trace(err)
+		}
+		if unit.Storage, err = exportStorage(u); err != nil {
+			return result, errors.Trace(err)
+		}
+		result.Units = append(result.Units, unit)
+	}
+	return result, nil
+}
+
+// exportStorage describes the storage instances owned by the unit.
+func exportStorage(u *Unit) ([]migration.StorageInstance, error) {
+	instances, err := u.StorageInstances()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []migration.StorageInstance
+	for _, instance := range instances {
+		if instance.doc.Life != Alive {
+			return nil, errors.Errorf("storage instance %q is not alive", instance.doc.Id)
+		}
+		result = append(result, migration.StorageInstance{
+			Id:         instance.doc.Id,
+			Kind:       string(instance.doc.Kind),
+			Location:   instance.doc.Location,
+			Attributes: instance.Attributes(),
+		})
+	}
+	return result, nil
+}
+
+func (st *State) exportCharm(url string) (migration.Charm, error) {
+	charms, closer := st.getCollection(charmsC)
+	defer closer()
+
+	var doc charmDoc
+	if err := charms.FindId(url).One(&doc); err != nil {
+		return migration.Charm{}, errors.Annotatef(err, "cannot get charm %q", url)
+	}
+	if doc.PendingUpload || doc.Placeholder {
+		return migration.Charm{}, errors.Errorf("charm %q has not been uploaded", url)
+	}
+	archive, err := st.readCharmArchive(doc.StoragePath)
+	if err != nil {
+		return migration.Charm{}, errors.Annotatef(err, "cannot read archive of charm %q", url)
+	}
+	return migration.Charm{
+		URL:          url,
+		Meta:         doc.Meta,
+		Config:       doc.Config,
+		Actions:      doc.Actions,
+		StoragePath:  doc.StoragePath,
+		BundleSha256: doc.BundleSha256,
+		Archive:      base64.StdEncoding.EncodeToString(archive),
+	}, nil
+}
+
+// readCharmArchive returns the contents of the charm archive stored
+// at the given path in the environment's storage.
+func (st *State) readCharmArchive(path string) ([]byte, error) {
+	r, _, err := st.Storage().Get(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// exportRelations describes the relations between the environment's
+// services, and the units in their scopes.
+func (st *State) exportRelations() ([]migration.Relation, error) {
+	relations, err := st.AllRelations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relationScopes, closer := st.getCollection(relationScopesC)
+	defer closer()
+
+	var result []migration.Relation
+	for _, rel := range relations {
+		if rel.doc.Life != Alive {
+			return nil, errors.Errorf("relation %q is not alive", rel.doc.Key)
+		}
+		exported := migration.Relation{
+			Id:  rel.doc.Id,
+			Key: rel.doc.Key,
+		}
+		for _, ep := range rel.doc.Endpoints {
+			exported.Endpoints = append(exported.Endpoints, migration.Endpoint{
+				ServiceName: ep.ServiceName,
+				Name:        ep.Name,
+				Role:        string(ep.Role),
+				Interface:   ep.Interface,
+				Optional:    ep.Optional,
+				Limit:       ep.Limit,
+				Scope:       string(ep.Scope),
+			})
+		}
+		var docs []relationScopeDoc
+		prefix := fmt.Sprintf("r#%d#", rel.doc.Id)
+		sel := bson.D{{"_id", bson.D{{"$regex", "^" + st.docID(prefix)}}}}
+		if err := relationScopes.Find(sel).Sort("_id").All(&docs); err != nil {
+			return nil, errors.Annotatef(err, "cannot get scopes of relation %q", rel.doc.Key)
+		}
+		for _, doc := range docs {
+			if doc.Departing {
+				return nil, errors.Errorf("unit %s is leaving relation %q", doc.unitName(), rel.doc.Key)
+			}
+			settings, err := readSettings(st, doc.Key)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			exported.Scopes = append(exported.Scopes, migration.RelationScope{
+				Key:      doc.Key,
+				Settings: settings.Map(),
+			})
+		}
+		result = append(result, exported)
+	}
+	return result, nil
+}
@@ -0,0 +1,664 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/network"
+)
+
+// Import creates a new environment hosted by st's state server from
+// the given description, which was produced by Export. The environment
+// keeps its UUID, and its machines and units keep their identities
+// and passwords, so that their agents can connect to st's state server
+// once they are given its addresses with SetMigrationTarget on the
+// original environment. The charm archives carried by the description
+// are stored in the new environment's storage.
+//
+// Import returns the new environment and a State connected to it,
+// which the caller must close.
+func (st *State) Import(model *migration.Model) (_ *Environment, _ *State, err error) {
+	if model.Version != migration.Version {
+		return nil, nil, errors.NotSupportedf("environment description version %d", model.Version)
+	}
+	cfg, err := config.New(config.NoDefaults, model.Config)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot import environment")
+	}
+	defer errors.DeferredAnnotatef(&err, "cannot import environment %q", cfg.Name())
+	if !names.IsValidUser(model.Owner) {
+		return nil, nil, errors.Errorf("invalid owner %q", model.Owner)
+	}
+	cons, err := constraints.Parse(model.Constraints)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	newSt, envOps, err := st.newHostedEnvironmentOps(cfg, names.NewUserTag(model.Owner))
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			newSt.Close()
+		}
+	}()
+	// Charms are shared with the state server's other environments,
+	// so the operations must be rebuilt if one is added meanwhile.
+	var imp *importer
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if _, err := st.GetEnvironment(newSt.EnvironTag()); err == nil {
+				return nil, errors.AlreadyExistsf("environment with UUID %q", newSt.EnvironTag().Id())
+			}
+		}
+		imp = &importer{st: newSt, model: model}
+		modelOps, err := imp.ops()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(append([]txn.Op{}, envOps...), modelOps...), nil
+	}
+	if err := newSt.run(buildTxn); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	stor := newSt.Storage()
+	for path, data := range imp.archives {
+		if err := stor.Put(path, bytes.NewReader(data), int64(len(data))); err != nil {
+			return nil, nil, errors.Annotatef(err, "cannot store charm archive %q", path)
+		}
+	}
+	if err := newSt.SetEnvironConstraints(cons); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	// Entities added to the environment later must not reuse the
+	// imported ids.
+	for name, next := range imp.sequences {
+		if err := newSt.ensureSequence(name, next); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	env, err := newSt.Environment()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return env, newSt, nil
+}
+
+// importer builds the operations that add the contents of an
+// environment description to the environment of st.
+type importer struct {
+	st    *State
+	model *migration.Model
+
+	// sequences holds the lowest number that each sequence
+	// must return once the environment has been imported.
+	sequences map[string]int
+
+	// charmURLs holds the charm URL of each service.
+	charmURLs map[string]*charm.URL
+
+	// principals holds the names of the principal units assigned
+	// to each machine.
+	principals map[string][]string
+
+	// relationCounts holds the number of relations each service
+	// takes part in.
+	relationCounts map[string]int
+
+	// archives holds the contents of each charm archive, keyed
+	// by storage path.
+	archives map[string][]byte
+}
+
+func (i *importer) ops() ([]txn.Op, error) {
+	i.sequences = make(map[string]int)
+	i.charmURLs = make(map[string]*charm.URL)
+	i.principals = make(map[string][]string)
+	i.relationCounts = make(map[string]int)
+	i.archives = make(map[string][]byte)
+
+	ops, err := i.envOps()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	annotationOps, err := i.annotationOps(environGlobalKey, i.st.EnvironTag(), i.model.Annotations)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, annotationOps...)
+	charmOps, err := i.charmOps()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, charmOps...)
+	// Relations are examined before services, and units before
+	// machines, so that the service and machine documents can
+	// record the entities that refer to them.
+	relationOps, err := i.relationOps()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, svc := range i.model.Services {
+		serviceOps, err := i.serviceOps(svc)
+		if err != nil {
+			return nil, errors.Annotatef(err, "service %q", svc.Name)
+		}
+		ops = append(ops, serviceOps...)
+	}
+	ops = append(ops, relationOps...)
+	for _, m := range i.model.Machines {
+		machineOps, err := i.machineOps(m)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, machineOps...)
+	}
+	return ops, nil
+}
+
+func (i *importer) envOps() ([]txn.Op, error) {
+	var ops []txn.Op
+	for _, user := range i.model.Users {
+		if user.Name == i.model.Owner {
+			// The owner was added with the environment.
+			continue
+		}
+		if !names.IsValidUser(user.Name) || !names.IsValidUser(user.CreatedBy) {
+			return nil, errors.Errorf("invalid environment user %q", user.Name)
+		}
+		access := EnvironmentAccess(user.Access)
+		if access != "" {
+			if err := access.Validate(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		op, doc := createEnvUserOpAndDoc(
+			i.st.EnvironTag().Id(),
+			names.NewUserTag(user.Name),
+			names.NewUserTag(user.CreatedBy),
+			user.DisplayName,
+		)
+		doc.Access = access
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// charmOps returns the operations that add the described charms, and
+// records their archives for storing once the environment exists.
+// Charms are shared between the environments of a state server, so
+// those it already knows about are left alone.
+func (i *importer) charmOps() ([]txn.Op, error) {
+	charms, closer := i.st.getCollection(charmsC)
+	defer closer()
+
+	var ops []txn.Op
+	for _, ch := range i.model.Charms {
+		curl, err := charm.ParseURL(ch.URL)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ch.Meta == nil {
+			return nil, errors.Errorf("charm %q has no metadata", ch.URL)
+		}
+		archive, err := base64.StdEncoding.DecodeString(ch.Archive)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid archive for charm %q", ch.URL)
+		}
+		if sum := fmt.Sprintf("%x", sha256.Sum256(archive)); sum != ch.BundleSha256 {
+			return nil, errors.Errorf("archive for charm %q does not match its SHA256 hash", ch.URL)
+		}
+		i.archives[ch.StoragePath] = archive
+		if count, err := charms.FindId(curl).Count(); err != nil {
+			return nil, errors.Trace(err)
+		} else if count > 0 {
+			ops = append(ops, txn.Op{
+				C:      charmsC,
+				Id:     curl,
+				Assert: txn.DocExists,
+			})
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      charmsC,
+			Id:     curl,
+			Assert: txn.DocMissing,
+			Insert: &charmDoc{
+				URL:          curl,
+				Meta:         ch.Meta,
+				Config:       ch.Config,
+				Actions:      ch.Actions,
+				BundleSha256: ch.BundleSha256,
+				StoragePath:  ch.StoragePath,
+			},
+		})
+	}
+	return ops, nil
+}
+
+func (i *importer) serviceOps(svc migration.Service) ([]txn.Op, error) {
+	if !names.IsValidService(svc.Name) {
+		return nil, errors.New("invalid service name")
+	}
+	curl, err := charm.ParseURL(svc.CharmURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	i.charmURLs[svc.Name] = curl
+	cons, err := constraints.Parse(svc.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	globalKey := serviceGlobalKey(svc.Name)
+	settingsKey := serviceSettingsKey(svc.Name, curl)
+	doc := &serviceDoc{
+		DocID:         i.st.docID(svc.Name),
+		Name:          svc.Name,
+		EnvUUID:       i.st.EnvironTag().Id(),
+		Series:        svc.Series,
+		Subordinate:   svc.Subordinate,
+		CharmURL:      curl,
+		ForceCharm:    svc.ForceCharm,
+		Life:          Alive,
+		UnitSeq:       svc.UnitSeq,
+		UnitCount:     len(svc.Units),
+		RelationCount: i.relationCounts[svc.Name],
+		Exposed:       svc.Exposed,
+		MinUnits:      svc.MinUnits,
+		OwnerTag:      svc.Owner,
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	},
+		createConstraintsOp(i.st, globalKey, cons),
+		createRequestedNetworksOp(i.st, globalKey, svc.RequestedNetworks),
+		createSettingsOp(i.st, settingsKey, svc.Settings),
+	}
+	if svc.MinUnits > 0 {
+		ops = append(ops, createMinUnitsOp(i.st, svc.Name))
+	}
+	annotationOps, err := i.annotationOps(globalKey, names.NewServiceTag(svc.Name), svc.Annotations)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, annotationOps...)
+	refCount := 1
+	for _, unit := range svc.Units {
+		unitOps, err := i.unitOps(svc, unit)
+		if err != nil {
+			return nil, errors.Annotatef(err, "unit %q", unit.Name)
+		}
+		ops = append(ops, unitOps...)
+		if unit.CharmURL != "" {
+			refCount++
+		}
+	}
+	ops = append(ops, txn.Op{
+		C:      settingsrefsC,
+		Id:     i.st.docID(settingsKey),
+		Assert: txn.DocMissing,
+		Insert: settingsRefsDoc{
+			RefCount: refCount,
+			EnvUUID:  i.st.EnvironTag().Id(),
+		},
+	})
+	return ops, nil
+}
+
+func (i *importer) unitOps(svc migration.Service, unit migration.Unit) ([]txn.Op, error) {
+	if !names.IsValidUnit(unit.Name) || names.UnitService(unit.Name) != svc.Name {
+		return nil, errors.New("invalid unit name")
+	}
+	doc := &unitDoc{
+		DocID:        i.st.docID(unit.Name),
+		Name:         unit.Name,
+		EnvUUID:      i.st.EnvironTag().Id(),
+		Service:      svc.Name,
+		Series:       svc.Series,
+		Principal:    unit.Principal,
+		Subordinates: unit.Subordinates,
+		MachineId:    unit.Machine,
+		Life:         Alive,
+		PasswordHash: unit.PasswordHash,
+	}
+	if unit.CharmURL != "" {
+		if unit.CharmURL != svc.CharmURL {
+			return nil, errors.Errorf("charm %q does not match service charm", unit.CharmURL)
+		}
+		doc.CharmURL = i.charmURLs[svc.Name]
+	}
+	if unit.Principal == "" && unit.Machine != "" {
+		i.principals[unit.Machine] = append(i.principals[unit.Machine], unit.Name)
+	}
+	globalKey := unitGlobalKey(unit.Name)
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	},
+		createStatusOp(i.st, globalKey, importStatus(unit.Status)),
+		createMeterStatusOp(i.st, globalKey, meterStatusDoc{Code: MeterNotSet}),
+	}
+	if !svc.Subordinate {
+		cons, err := constraints.Parse(unit.Constraints)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, createConstraintsOp(i.st, globalKey, cons))
+	}
+	annotationOps, err := i.annotationOps(globalKey, names.NewUnitTag(unit.Name), unit.Annotations)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, annotationOps...)
+	for _, storage := range unit.Storage {
+		storageOp, err := i.storageInstanceOp(unit.Name, storage)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, storageOp)
+	}
+	return ops, nil
+}
+
+// storageInstanceOp returns the operation that adds the given storage
+// instance, owned by the named unit.
+func (i *importer) storageInstanceOp(unitName string, storage migration.StorageInstance) (txn.Op, error) {
+	kind := StorageKind(storage.Kind)
+	switch kind {
+	case StorageKindBlock, StorageKindFilesystem:
+	default:
+		return txn.Op{}, errors.Errorf("storage instance %q has invalid kind %q", storage.Id, storage.Kind)
+	}
+	slash := strings.LastIndex(storage.Id, "/")
+	seq, err := strconv.Atoi(storage.Id[slash+1:])
+	if slash < 1 || err != nil || strings.ContainsAny(storage.Id[:slash], "/#") {
+		return txn.Op{}, errors.Errorf("invalid storage instance id %q", storage.Id)
+	}
+	if seq+1 > i.sequences["storage"] {
+		i.sequences["storage"] = seq + 1
+	}
+	doc := &storageInstanceDoc{
+		DocID:    i.st.docID(storageInstanceKey(unitName, storage.Id)),
+		EnvUUID:  i.st.EnvironTag().Id(),
+		Id:       storage.Id,
+		Kind:     kind,
+		Owner:    unitName,
+		Life:     Alive,
+		Location: storage.Location,
+	}
+	if len(storage.Attributes) > 0 {
+		doc.Attributes = make(map[string]string, len(storage.Attributes))
+		for key, value := range storage.Attributes {
+			doc.Attributes[escapeReplacer.Replace(key)] = value
+		}
+	}
+	return txn.Op{
+		C:      storageInstancesC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}, nil
+}
+
+// annotationOps returns the operations that add the given annotations
+// to the entity with the given global key and tag.
+func (i *importer) annotationOps(globalKey string, tag names.Tag, annotations map[string]string) ([]txn.Op, error) {
+	if len(annotations) == 0 {
+		return nil, nil
+	}
+	for key := range annotations {
+		if strings.Contains(key, ".") {
+			return nil, errors.Errorf("invalid annotation key %q for %s", key, tag)
+		}
+	}
+	doc := &annotatorDoc{
+		DocID:       i.st.docID(globalKey),
+		EnvUUID:     i.st.EnvironTag().Id(),
+		GlobalKey:   globalKey,
+		Tag:         tag.String(),
+		Annotations: annotations,
+	}
+	return []txn.Op{{
+		C:      annotationsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}, nil
+}
+
+func (i *importer) relationOps() ([]txn.Op, error) {
+	var ops []txn.Op
+	for _, rel := range i.model.Relations {
+		doc := &relationDoc{
+			DocID:     i.st.docID(rel.Key),
+			Key:       rel.Key,
+			EnvUUID:   i.st.EnvironTag().Id(),
+			Id:        rel.Id,
+			Life:      Alive,
+			UnitCount: len(rel.Scopes),
+		}
+		for _, ep := range rel.Endpoints {
+			doc.Endpoints = append(doc.Endpoints, Endpoint{
+				ServiceName: ep.ServiceName,
+				Relation: charm.Relation{
+					Name:      ep.Name,
+					Role:      charm.RelationRole(ep.Role),
+					Interface: ep.Interface,
+					Optional:  ep.Optional,
+					Limit:     ep.Limit,
+					Scope:     charm.RelationScope(ep.Scope),
+				},
+			})
+			i.relationCounts[ep.ServiceName]++
+		}
+		if len(doc.Endpoints) == 0 || relationKey(doc.Endpoints) != rel.Key {
+			return nil, errors.Errorf("relation %q has invalid endpoints", rel.Key)
+		}
+		ops = append(ops, txn.Op{
+			C:      relationsC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		})
+		scopePrefix := fmt.Sprintf("r#%d#", rel.Id)
+		for _, scope := range rel.Scopes {
+			if !strings.HasPrefix(scope.Key, scopePrefix) {
+				return nil, errors.Errorf("relation %q has invalid scope %q", rel.Key, scope.Key)
+			}
+			scopeDoc := relationScopeDoc{
+				DocID:   i.st.docID(scope.Key),
+				Key:     scope.Key,
+				EnvUUID: i.st.EnvironTag().Id(),
+			}
+			ops = append(ops, txn.Op{
+				C:      relationScopesC,
+				Id:     scopeDoc.DocID,
+				Assert: txn.DocMissing,
+				Insert: scopeDoc,
+			}, createSettingsOp(i.st, scope.Key, scope.Settings))
+		}
+		if next := rel.Id + 1; next > i.sequences["relation"] {
+			i.sequences["relation"] = next
+		}
+	}
+	return ops, nil
+}
+
+// machineOps returns the operations that add the given machine and
+// the containers it hosts.
+func (i *importer) machineOps(m migration.Machine) ([]txn.Op, error) {
+	if !names.IsValidMachine(m.Id) {
+		return nil, errors.Errorf("invalid machine id %q", m.Id)
+	}
+	doc := &machineDoc{
+		DocID:            i.st.docID(m.Id),
+		Id:               m.Id,
+		EnvUUID:          i.st.EnvironTag().Id(),
+		Nonce:            m.Nonce,
+		Series:           m.Series,
+		ContainerType:    m.ContainerType,
+		Principals:       i.principals[m.Id],
+		Life:             Alive,
+		NoVote:           true,
+		PasswordHash:     m.PasswordHash,
+		Clean:            len(i.principals[m.Id]) == 0,
+		Addresses:        importAddresses(m.Addresses),
+		MachineAddresses: importAddresses(m.MachineAddresses),
+		Placement:        m.Placement,
+	}
+	for _, name := range m.Jobs {
+		job, err := machineJobFromString(name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "machine %s", m.Id)
+		}
+		if job == JobManageEnviron {
+			return nil, errors.Errorf("machine %s is a state server", m.Id)
+		}
+		doc.Jobs = append(doc.Jobs, job)
+	}
+	cons, err := constraints.Parse(m.Constraints)
+	if err != nil {
+		return nil, errors.Annotatef(err, "machine %s", m.Id)
+	}
+	globalKey := machineGlobalKey(m.Id)
+	var children []string
+	for _, container := range m.Containers {
+		children = append(children, container.Id)
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	},
+		createConstraintsOp(i.st, globalKey, cons),
+		createStatusOp(i.st, globalKey, importStatus(m.Status)),
+		createRequestedNetworksOp(i.st, globalKey, m.RequestedNetworks),
+		i.st.insertNewContainerRefOp(m.Id, children...),
+	}
+	annotationOps, err := i.annotationOps(globalKey, names.NewMachineTag(m.Id), m.Annotations)
+	if err != nil {
+		return nil, errors.Annotatef(err, "machine %s", m.Id)
+	}
+	ops = append(ops, annotationOps...)
+	if m.Instance != nil {
+		hc, err := instance.ParseHardware(m.Instance.Hardware)
+		if err != nil {
+			return nil, errors.Annotatef(err, "machine %s", m.Id)
+		}
+		ops = append(ops, txn.Op{
+			C:      instanceDataC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &instanceData{
+				DocID:      doc.DocID,
+				MachineId:  m.Id,
+				InstanceId: instance.Id(m.Instance.Id),
+				EnvUUID:    doc.EnvUUID,
+				Status:     m.Instance.Status,
+				Arch:       hc.Arch,
+				Mem:        hc.Mem,
+				RootDisk:   hc.RootDisk,
+				CpuCores:   hc.CpuCores,
+				CpuPower:   hc.CpuPower,
+				Tags:       hc.Tags,
+			},
+		})
+	}
+	for _, opened := range m.OpenedPorts {
+		pdoc := newPortsDoc(i.st, portsGlobalKey(m.Id, opened.Network))
+		for _, r := range opened.Ports {
+			pdoc.Ports = append(pdoc.Ports, PortRange{
+				UnitName: r.Unit,
+				FromPort: r.FromPort,
+				ToPort:   r.ToPort,
+				Protocol: r.Protocol,
+			})
+		}
+		ops = append(ops, txn.Op{
+			C:      openedPortsC,
+			Id:     pdoc.DocID,
+			Assert: txn.DocMissing,
+			Insert: pdoc,
+		})
+	}
+	i.noteMachineId(m.Id)
+	for _, container := range m.Containers {
+		containerOps, err := i.machineOps(container)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, containerOps...)
+	}
+	return ops, nil
+}
+
+// noteMachineId records that the sequence from which the given
+// machine's id was allocated must not return it again.
+func (i *importer) noteMachineId(id string) {
+	name := "machine"
+	parts := strings.Split(id, "/")
+	if n := len(parts); n > 1 {
+		parentId := strings.Join(parts[:n-2], "/")
+		name = fmt.Sprintf("machine%s%sContainer", parentId, parts[n-2])
+	}
+	seq, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		// The id has already been validated.
+		panic(err)
+	}
+	if seq+1 > i.sequences[name] {
+		i.sequences[name] = seq + 1
+	}
+}
+
+func machineJobFromString(name string) (MachineJob, error) {
+	for job, paramsJob := range jobNames {
+		if string(paramsJob) == name {
+			return job, nil
+		}
+	}
+	return 0, errors.NotValidf("machine job %q", name)
+}
+
+func importAddresses(addrs []migration.Address) []address {
+	var result []address
+	for _, addr := range addrs {
+		result = append(result, address{
+			Value:       addr.Value,
+			AddressType: network.AddressType(addr.Type),
+			NetworkName: addr.NetworkName,
+			Scope:       network.Scope(addr.Scope),
+		})
+	}
+	return result
+}
+
+func importStatus(status migration.Status) statusDoc {
+	return statusDoc{
+		Status:     Status(status.Status),
+		StatusInfo: status.Info,
+		StatusData: status.Data,
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	charmtesting "gopkg.in/juju/charm.v4/testing"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type MigrationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&MigrationSuite{})
+
+const agentPassword = "0123456789abcdefghij"
+
+// addStoredCharm adds the named testing charm, storing its archive
+// in the environment's storage as an upload would.
+func (s *MigrationSuite) addStoredCharm(c *gc.C, name string) *state.Charm {
+	archive := charmtesting.Charms.CharmArchive(c.MkDir(), name)
+	data, err := ioutil.ReadFile(archive.Path)
+	c.Assert(err, gc.IsNil)
+	curl := charm.MustParseURL(fmt.Sprintf("local:quantal/%s-%d", name, archive.Revision()))
+	storagePath := "charms/" + curl.String()
+	err = s.State.Storage().Put(storagePath, bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, gc.IsNil)
+	ch, err := s.State.AddCharm(archive, curl, storagePath, fmt.Sprintf("%x", sha256.Sum256(data)))
+	c.Assert(err, gc.IsNil)
+	return ch
+}
+
+// addEntities populates the environment with a machine hosting a
+// container and two related services.
+func (s *MigrationSuite) addEntities(c *gc.C) {
+	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, gc.IsNil)
+	m0, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	hc := instance.MustParseHardware("arch=amd64 mem=8G")
+	err = m0.SetProvisioned("i-0", "fake_nonce", &hc)
+	c.Assert(err, gc.IsNil)
+	err = m0.SetPassword(agentPassword)
+	c.Assert(err, gc.IsNil)
+	err = m0.SetAnnotations(map[string]string{"rack": "a1"})
+	c.Assert(err, gc.IsNil)
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	err = env.SetAnnotations(map[string]string{"purpose": "testing"})
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, "0", instance.LXC)
+	c.Assert(err, gc.IsNil)
+
+	wordpress := s.AddTestingService(c, "wordpress", s.addStoredCharm(c, "wordpress"))
+	err = wordpress.UpdateConfigSettings(map[string]interface{}{"blog-title": "migrated"})
+	c.Assert(err, gc.IsNil)
+	mysql := s.AddTestingService(c, "mysql", s.addStoredCharm(c, "mysql"))
+	wu, err := wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = wu.AssignToMachine(m0)
+	c.Assert(err, gc.IsNil)
+	err = wu.SetPassword(agentPassword)
+	c.Assert(err, gc.IsNil)
+	err = wu.OpenPort("tcp", 80)
+	c.Assert(err, gc.IsNil)
+	err = wu.SetAnnotations(map[string]string{"owner": "ops"})
+	c.Assert(err, gc.IsNil)
+	data, err := wu.AddStorageInstance("data", state.StorageKindFilesystem)
+	c.Assert(err, gc.IsNil)
+	err = data.SetLocation("/srv/data", map[string]string{"fs.type": "ext4"})
+	c.Assert(err, gc.IsNil)
+	mu, err := mysql.AddUnit()
+	c.Assert(err, gc.IsNil)
+
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, gc.IsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, gc.IsNil)
+	ru, err := rel.Unit(mu)
+	c.Assert(err, gc.IsNil)
+	err = ru.EnterScope(map[string]interface{}{"host": "db.example.com"})
+	c.Assert(err, gc.IsNil)
+}
+
+// moveToNewStateServer replaces the suite's state server with a new
+// one that hosts no environments but its own.
+func (s *MigrationSuite) moveToNewStateServer(c *gc.C) {
+	s.State.Close()
+	s.State = nil
+	err := s.MgoSuite.Session.DB("juju").DropDatabase()
+	c.Assert(err, gc.IsNil)
+	s.State = TestingInitialize(c, s.owner, testing.EnvironConfig(c), &s.policy)
+}
+
+func (s *MigrationSuite) TestExportImport(c *gc.C) {
+	s.addEntities(c)
+	model, err := s.State.Export()
+	c.Assert(err, gc.IsNil)
+	c.Assert(model.Owner, gc.Equals, s.owner.Username())
+	c.Assert(model.Machines, gc.HasLen, 1)
+	c.Assert(model.Machines[0].Containers, gc.HasLen, 1)
+	c.Assert(model.Services, gc.HasLen, 2)
+	c.Assert(model.Charms, gc.HasLen, 2)
+	c.Assert(model.Relations, gc.HasLen, 1)
+	data, err := migration.Serialize(model)
+	c.Assert(err, gc.IsNil)
+
+	s.moveToNewStateServer(c)
+	model, err = migration.Deserialize(data)
+	c.Assert(err, gc.IsNil)
+	env, st, err := s.State.Import(model)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+	c.Assert(env.UUID(), gc.Equals, s.envTag.Id())
+	c.Assert(env.Owner(), gc.Equals, s.owner)
+	s.assertImported(c, env, st)
+}
+
+// assertImported checks that the entities added by addEntities have
+// been imported into the given environment.
+func (s *MigrationSuite) assertImported(c *gc.C, env *state.Environment, st *state.State) {
+	annotations, err := env.Annotations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(annotations, gc.DeepEquals, map[string]string{"purpose": "testing"})
+
+	cons, err := st.EnvironConstraints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=4G"))
+
+	m0, err := st.Machine("0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(m0.PasswordValid(agentPassword), jc.IsTrue)
+	instId, err := m0.InstanceId()
+	c.Assert(err, gc.IsNil)
+	c.Assert(instId, gc.Equals, instance.Id("i-0"))
+	containers, err := m0.Containers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(containers, gc.DeepEquals, []string{"0/lxc/0"})
+	ports, err := m0.AllPorts()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ports, gc.HasLen, 1)
+	annotations, err = m0.Annotations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(annotations, gc.DeepEquals, map[string]string{"rack": "a1"})
+
+	wordpress, err := st.Service("wordpress")
+	c.Assert(err, gc.IsNil)
+	ch, _, err := wordpress.Charm()
+	c.Assert(err, gc.IsNil)
+	r, _, err := st.Storage().Get(ch.StoragePath())
+	c.Assert(err, gc.IsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, gc.IsNil)
+	c.Assert(fmt.Sprintf("%x", sha256.Sum256(data)), gc.Equals, ch.BundleSha256())
+	settings, err := wordpress.ConfigSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings["blog-title"], gc.Equals, "migrated")
+	wu, err := st.Unit("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(wu.PasswordValid(agentPassword), jc.IsTrue)
+	machineId, err := wu.AssignedMachineId()
+	c.Assert(err, gc.IsNil)
+	c.Assert(machineId, gc.Equals, "0")
+	annotations, err = wu.Annotations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(annotations, gc.DeepEquals, map[string]string{"owner": "ops"})
+	storage, err := wu.StorageInstance("data/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(storage.Kind(), gc.Equals, state.StorageKindFilesystem)
+	location, ok := storage.Location()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(location, gc.Equals, "/srv/data")
+	c.Assert(storage.Attributes(), gc.DeepEquals, map[string]string{"fs.type": "ext4"})
+
+	rel, err := st.KeyRelation("wordpress:db mysql:server")
+	c.Assert(err, gc.IsNil)
+	wru, err := rel.Unit(wu)
+	c.Assert(err, gc.IsNil)
+	relSettings, err := wru.ReadSettings("mysql/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(relSettings, gc.DeepEquals, map[string]interface{}{"host": "db.example.com"})
+
+	// New entities do not reuse the imported ids.
+	m1, err := st.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	c.Assert(m1.Id(), gc.Equals, "1")
+	wu1, err := wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	c.Assert(wu1.Name(), gc.Equals, "wordpress/1")
+	storage, err = wu1.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	c.Assert(storage.Id(), gc.Equals, "data/1")
+}
+
+func (s *MigrationSuite) TestImportAlongsideOtherEnvironment(c *gc.C) {
+	s.addEntities(c)
+	model, err := s.State.Export()
+	c.Assert(err, gc.IsNil)
+
+	// The new state server's own environment has entities with the
+	// same names, and uses the same charms.
+	s.moveToNewStateServer(c)
+	s.addEntities(c)
+	env, st, err := s.State.Import(model)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+	s.assertImported(c, env, st)
+
+	_, err = s.State.KeyRelation("wordpress:db mysql:server")
+	c.Assert(err, gc.IsNil)
+	relations, err := st.AllRelations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(relations, gc.HasLen, 1)
+}
+
+func (s *MigrationSuite) TestExportRefusesDyingStorage(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.addStoredCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	data, err := unit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	err = data.Destroy()
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `cannot export environment: service "wordpress": storage instance "data/0" is not alive`)
+}
+
+func (s *MigrationSuite) TestImportExistingEnvironment(c *gc.C) {
+	model, err := s.State.Export()
+	c.Assert(err, gc.IsNil)
+	_, _, err = s.State.Import(model)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment "testenv": environment with UUID ".*" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *MigrationSuite) TestImportRefusesStateServerMachines(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, gc.IsNil)
+	model, err := s.State.Export()
+	c.Assert(err, gc.IsNil)
+
+	s.moveToNewStateServer(c)
+	_, _, err = s.State.Import(model)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment "testenv": machine 0 is a state server`)
+}
+
+func (s *MigrationSuite) TestExportRefusesDyingUnits(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+	err = unit.Destroy()
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `cannot export environment: service "wordpress": unit wordpress/0 is not alive`)
+}
+
+func (s *MigrationSuite) TestImportRefusesOtherVersions(c *gc.C) {
+	_, _, err := s.State.Import(&migration.Model{Version: 99})
+	c.Assert(err, gc.ErrorMatches, "environment description version 99 not supported")
+}
+
+func (s *MigrationSuite) TestImportRefusesCorruptCharmArchive(c *gc.C) {
+	s.addEntities(c)
+	model, err := s.State.Export()
+	c.Assert(err, gc.IsNil)
+	model.Charms[0].Archive = base64.StdEncoding.EncodeToString([]byte("not a charm"))
+
+	s.moveToNewStateServer(c)
+	_, _, err = s.State.Import(model)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment "testenv": archive for charm ".*" does not match its SHA256 hash`)
+}
+
+func (s *MigrationSuite) TestExportRefusesMissingCharmArchive(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	_, err := s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `cannot export environment: cannot read archive of charm ".*": .*`)
+}
@@ -21,9 +21,11 @@ import (
 // ensuring the number of units for the service is never less than the actual
 // alive units: new units are added if required.
 type minUnitsDoc struct {
+	DocID string `bson:"_id"`
 	// ServiceName is safe to be used here in place of its globalKey, since
 	// the referred entity type is always the Service.
-	ServiceName string `bson:"_id"`
+	ServiceName string
+	EnvUUID     string `bson:"env-uuid"`
 	Revno       int
 }
 
@@ -74,12 +76,7 @@ func setMinUnitsOps(service *Service, minUnits int) []txn.Op {
 		Update: bson.D{{"$set", bson.D{{"minunits", minUnits}}}},
 	}}
 	if service.doc.MinUnits == 0 {
-		return append(ops, txn.Op{
-			C:      minUnitsC,
-			Id:     serviceName,
-			Assert: txn.DocMissing,
-			Insert: &minUnitsDoc{ServiceName: serviceName},
-		})
+		return append(ops, createMinUnitsOp(state, serviceName))
 	}
 	if minUnits == 0 {
 		return append(ops, minUnitsRemoveOp(state, serviceName))
@@ -92,6 +89,21 @@ func setMinUnitsOps(service *Service, minUnits int) []txn.Op {
 	return ops
 }
 
+// createMinUnitsOp returns the operation required to create the minimum
+// units document for the service in MongoDB.
+func createMinUnitsOp(st *State, serviceName string) txn.Op {
+	return txn.Op{
+		C:      minUnitsC,
+		Id:     st.docID(serviceName),
+		Assert: txn.DocMissing,
+		Insert: &minUnitsDoc{
+			DocID:       st.docID(serviceName),
+			ServiceName: serviceName,
+			EnvUUID:     st.EnvironTag().Id(),
+		},
+	}
+}
+
 // minUnitsTriggerOp returns the operation required to increase the minimum
 // units revno for the service in MongoDB, ignoring the case of document not
 // existing. This is included in the operations performed when a unit is
@@ -101,7 +113,7 @@ func setMinUnitsOps(service *Service, minUnits int) []txn.Op {
 func minUnitsTriggerOp(st *State, serviceName string) txn.Op {
 	return txn.Op{
 		C:      minUnitsC,
-		Id:     serviceName,
+		Id:     st.docID(serviceName),
 		Update: bson.D{{"$inc", bson.D{{"revno", 1}}}},
 	}
 }
@@ -111,7 +123,7 @@ func minUnitsTriggerOp(st *State, serviceName string) txn.Op {
 func minUnitsRemoveOp(st *State, serviceName string) txn.Op {
 	return txn.Op{
 		C:      minUnitsC,
-		Id:     serviceName,
+		Id:     st.docID(serviceName),
 		Remove: true,
 	}
 }
@@ -181,7 +193,11 @@ func aliveUnitsCount(service *Service) (int, error) {
 	units, closer := service.st.getCollection(unitsC)
 	defer closer()
 
-	query := bson.D{{"service", service.doc.Name}, {"life", Alive}}
+	query := bson.D{
+		{"service", service.doc.Name},
+		{"env-uuid", service.st.EnvironTag().Id()},
+		{"life", Alive},
+	}
 	return units.Find(query).Count()
 }
 
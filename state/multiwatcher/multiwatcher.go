@@ -18,10 +18,16 @@ import (
 
 var logger = loggo.GetLogger("juju.state.multiwatcher")
 
+// Filter reports whether a delta should be sent to a Watcher.
+type Filter func(params.Delta) bool
+
 // Watcher watches any changes to the state.
 type Watcher struct {
 	all *StoreManager
 
+	// filter, if not nil, selects the deltas sent by the Watcher.
+	filter Filter
+
 	// The following fields are maintained by the StoreManager
 	// goroutine.
 	revno   int64
@@ -36,6 +42,16 @@ func NewWatcher(all *StoreManager) *Watcher {
 	}
 }
 
+// NewFilteredWatcher creates a new watcher that observes changes to
+// an underlying store manager, but only reports the deltas selected
+// by the given filter.
+func NewFilteredWatcher(all *StoreManager, filter Filter) *Watcher {
+	return &Watcher{
+		all:    all,
+		filter: filter,
+	}
+}
+
 // Stop stops the watcher.
 func (w *Watcher) Stop() error {
 	select {
@@ -237,6 +253,17 @@ func (sm *StoreManager) respond() {
 		if len(changes) == 0 {
 			continue
 		}
+		if w.filter != nil {
+			changes = filterDeltas(changes, w.filter)
+			if len(changes) == 0 {
+				// The watcher has now seen everything up
+				// to the latest revno, but has nothing to
+				// report; keep waiting.
+				w.revno = sm.all.latestRevno
+				sm.seen(revno)
+				continue
+			}
+		}
 		req.changes = changes
 		w.revno = sm.all.latestRevno
 		req.reply <- true
@@ -250,6 +277,17 @@ func (sm *StoreManager) respond() {
 	}
 }
 
+// filterDeltas returns the deltas selected by filter.
+func filterDeltas(deltas []params.Delta, filter Filter) []params.Delta {
+	var filtered []params.Delta
+	for _, d := range deltas {
+		if filter(d) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
 // seen states that a Watcher has just been given information about
 // all entities newer than the given revno.  We assume it has already
 // seen all the older entities.
@@ -643,6 +643,32 @@ func (*storeManagerSuite) TestRun(c *gc.C) {
 	}, "")
 }
 
+func (*storeManagerSuite) TestRunFiltered(c *gc.C) {
+	b := newTestBacking([]EntityInfo{
+		&MachineInfo{Id: "0"},
+		&ServiceInfo{Name: "logging"},
+		&ServiceInfo{Name: "wordpress"},
+	})
+	sm := NewStoreManager(b)
+	defer func() {
+		c.Check(sm.Stop(), gc.IsNil)
+	}()
+	w := NewFilteredWatcher(sm, func(d params.Delta) bool {
+		return d.Entity.EntityId().Kind == "service"
+	})
+	checkNext(c, w, []params.Delta{
+		{Entity: &ServiceInfo{Name: "logging"}},
+		{Entity: &ServiceInfo{Name: "wordpress"}},
+	}, "")
+
+	// Changes not selected by the filter are not reported.
+	b.updateEntity(&MachineInfo{Id: "0", InstanceId: "i-0"})
+	b.updateEntity(&ServiceInfo{Name: "logging", Exposed: true})
+	checkNext(c, w, []params.Delta{
+		{Entity: &ServiceInfo{Name: "logging", Exposed: true}},
+	}, "")
+}
+
 func (*storeManagerSuite) TestWatcherStop(c *gc.C) {
 	sm := NewStoreManager(newTestBacking(nil))
 	defer func() {
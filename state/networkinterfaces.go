@@ -48,6 +48,7 @@ type NetworkInterfaceInfo struct {
 // a given network.
 type networkInterfaceDoc struct {
 	Id            bson.ObjectId `bson:"_id"`
+	EnvUUID       string        `bson:"env-uuid"`
 	MACAddress    string
 	InterfaceName string
 	NetworkName   string
@@ -181,9 +182,10 @@ func newNetworkInterface(st *State, doc *networkInterfaceDoc) *NetworkInterface
 	return &NetworkInterface{st, *doc}
 }
 
-func newNetworkInterfaceDoc(args NetworkInterfaceInfo) *networkInterfaceDoc {
+func newNetworkInterfaceDoc(st *State, args NetworkInterfaceInfo) *networkInterfaceDoc {
 	// This does not set the machine id.
 	return &networkInterfaceDoc{
+		EnvUUID:       st.EnvironTag().Id(),
 		MACAddress:    args.MACAddress,
 		InterfaceName: args.InterfaceName,
 		NetworkName:   args.NetworkName,
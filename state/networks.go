@@ -37,9 +37,12 @@ type NetworkInfo struct {
 // networkDoc represents a configured network that a machine can be a
 // part of.
 type networkDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+
 	// Name is the network's name. It should be one of the machine's
 	// included networks.
-	Name string `bson:"_id"`
+	Name string `bson:"name"`
 
 	ProviderId network.Id
 	CIDR       string
@@ -50,8 +53,10 @@ func newNetwork(st *State, doc *networkDoc) *Network {
 	return &Network{st, *doc}
 }
 
-func newNetworkDoc(args NetworkInfo) *networkDoc {
+func newNetworkDoc(st *State, args NetworkInfo) *networkDoc {
 	return &networkDoc{
+		DocID:      st.docID(args.Name),
+		EnvUUID:    st.EnvironTag().Id(),
 		Name:       args.Name,
 		ProviderId: args.ProviderId,
 		CIDR:       args.CIDR,
@@ -104,7 +109,7 @@ func (n *Network) Interfaces() ([]*NetworkInterface, error) {
 	defer closer()
 
 	docs := []networkInterfaceDoc{}
-	sel := bson.D{{"networkname", n.doc.Name}}
+	sel := bson.D{{"env-uuid", n.st.EnvironTag().Id()}, {"networkname", n.doc.Name}}
 	err := networkInterfaces.Find(sel).All(&docs)
 	if err != nil {
 		return nil, err
@@ -140,13 +140,27 @@ var indexes = []struct {
 	{unitsC, []string{"machineid"}, false},
 	// TODO(thumper): schema change to remove this index.
 	{usersC, []string{"name"}, false},
-	{networksC, []string{"providerid"}, true},
-	{networkInterfacesC, []string{"interfacename", "machineid"}, true},
-	{networkInterfacesC, []string{"macaddress", "networkname"}, true},
+	{networksC, []string{"env-uuid", "providerid"}, true},
+	{networkInterfacesC, []string{"env-uuid", "interfacename", "machineid"}, true},
+	{networkInterfacesC, []string{"env-uuid", "macaddress", "networkname"}, true},
 	{networkInterfacesC, []string{"networkname"}, false},
 	{networkInterfacesC, []string{"machineid"}, false},
 }
 
+// droppedIndexes holds the indexes created by earlier versions that
+// are no longer wanted. The unique network indexes now include the
+// environment UUID, so that environments hosted by the same state
+// server may use the same provider ids, interface names and MAC
+// addresses.
+var droppedIndexes = []struct {
+	collection string
+	key        []string
+}{
+	{networksC, []string{"providerid"}},
+	{networkInterfacesC, []string{"interfacename", "machineid"}},
+	{networkInterfacesC, []string{"macaddress", "networkname"}},
+}
+
 // The capped collection used for transaction logs defaults to 10MB.
 // It's tweaked in export_test.go to 1MB to avoid the overhead of
 // creating and deleting the large file repeatedly in tests.
@@ -155,6 +169,12 @@ var (
 	logSizeTests = 1000000
 )
 
+// isIndexNotFound returns whether err reports that an index to be
+// dropped does not exist.
+func isIndexNotFound(err error) bool {
+	return strings.Contains(err.Error(), "index not found")
+}
+
 func maybeUnauthorized(err error, msg string) error {
 	if err == nil {
 		return nil
@@ -233,6 +253,12 @@ func newState(session *mgo.Session, mongoInfo *mongo.MongoInfo, policy Policy) (
 		}
 	}()
 
+	for _, item := range droppedIndexes {
+		err := db.C(item.collection).DropIndex(item.key...)
+		if err != nil && !isIndexNotFound(err) {
+			return nil, errors.Annotate(err, "cannot drop database index")
+		}
+	}
 	for _, item := range indexes {
 		index := mgo.Index{Key: item.key, Unique: item.unique}
 		if err := db.C(item.collection).EnsureIndex(index); err != nil {
@@ -141,7 +141,9 @@ func (p PortRange) String() string {
 
 // portsDoc represents the state of ports opened on machines for networks
 type portsDoc struct {
-	Id       string `bson:"_id"`
+	DocID    string `bson:"_id"`
+	Id       string `bson:"id"`
+	EnvUUID  string `bson:"env-uuid"`
 	Ports    []PortRange
 	TxnRevno int64 `bson:"txn-revno"`
 }
@@ -344,7 +346,7 @@ func (p *Ports) Refresh() error {
 	openedPorts, closer := p.st.getCollection(openedPortsC)
 	defer closer()
 
-	err := openedPorts.FindId(p.Id()).One(&p.doc)
+	err := openedPorts.FindId(p.st.docID(p.Id())).One(&p.doc)
 	if err == mgo.ErrNotFound {
 		return errors.NotFoundf(p.String())
 	} else if err != nil {
@@ -404,7 +406,10 @@ func (m *Machine) AllPorts() ([]*Ports, error) {
 
 	idRegex := fmt.Sprintf("m#%s#n#.*", m.Id())
 	docs := []portsDoc{}
-	query := bson.M{"_id": bson.M{"$regex": idRegex}}
+	query := bson.D{
+		{"env-uuid", m.st.EnvironTag().Id()},
+		{"id", bson.D{{"$regex", idRegex}}},
+	}
 	err := openedPorts.Find(query).All(&docs)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -432,7 +437,7 @@ func (st *State) Ports(key string) (*Ports, error) {
 	portsId := portsIdAsString(machineId, networkName)
 
 	var doc portsDoc
-	err = openedPorts.FindId(key).One(&doc)
+	err = openedPorts.FindId(st.docID(key)).One(&doc)
 	if err == mgo.ErrNotFound {
 		return nil, errors.NotFoundf(portsId)
 	}
@@ -443,21 +448,29 @@ func (st *State) Ports(key string) (*Ports, error) {
 	return &Ports{st, doc, false}, nil
 }
 
+// newPortsDoc returns a new ports document, with the given global key,
+// holding the given port ranges.
+func newPortsDoc(st *State, portsId string, ports ...PortRange) *portsDoc {
+	return &portsDoc{
+		DocID:   st.docID(portsId),
+		Id:      portsId,
+		EnvUUID: st.EnvironTag().Id(),
+		Ports:   ports,
+	}
+}
+
 // addPortsDocOps returns the ops for adding a number of port ranges
 // to an new ports document. portsAssert allows specifying an assert
 // statement for on the openedPorts collection op.
 func addPortsDocOps(st *State, machineId, portsId string, portsAssert interface{}, ports ...PortRange) []txn.Op {
-	pdoc := &portsDoc{
-		Id:    portsId,
-		Ports: ports,
-	}
+	pdoc := newPortsDoc(st, portsId, ports...)
 	return []txn.Op{{
 		C:      machinesC,
 		Id:     st.docID(machineId),
 		Assert: notDeadDoc,
 	}, {
 		C:      openedPortsC,
-		Id:     portsId,
+		Id:     pdoc.DocID,
 		Assert: portsAssert,
 		Insert: pdoc,
 	}}
@@ -477,7 +490,7 @@ func updatePortsDocOps(st *State, machineId, portsId string, portsAssert interfa
 		Assert: notDeadDoc,
 	}, {
 		C:      openedPortsC,
-		Id:     portsId,
+		Id:     st.docID(portsId),
 		Assert: portsAssert,
 		Update: bson.D{{"$addToSet", bson.D{{"ports", portRange}}}},
 	}}
@@ -493,7 +506,7 @@ func setPortsDocOps(st *State, machineId, portsId string, portsAssert interface{
 		Assert: notDeadDoc,
 	}, {
 		C:      openedPortsC,
-		Id:     portsId,
+		Id:     st.docID(portsId),
 		Assert: portsAssert,
 		Update: bson.D{{"$set", bson.D{{"ports", ports}}}},
 	}}
@@ -504,7 +517,7 @@ func setPortsDocOps(st *State, machineId, portsId string, portsAssert interface{
 func (p *Ports) removeOps() []txn.Op {
 	return []txn.Op{{
 		C:      openedPortsC,
-		Id:     p.Id(),
+		Id:     p.st.docID(p.Id()),
 		Remove: true,
 	}}
 }
@@ -563,7 +576,7 @@ func getPorts(st *State, machineId, networkName string) (*Ports, error) {
 	var doc portsDoc
 	key := portsGlobalKey(machineId, networkName)
 	stringId := portsIdAsString(machineId, networkName)
-	err := openedPorts.FindId(key).One(&doc)
+	err := openedPorts.FindId(st.docID(key)).One(&doc)
 	if err == mgo.ErrNotFound {
 		return nil, errors.NotFoundf(stringId)
 	}
@@ -580,8 +593,7 @@ func getOrCreatePorts(st *State, machineId, networkName string) (*Ports, error)
 	ports, err := getPorts(st, machineId, networkName)
 	if errors.IsNotFound(err) {
 		key := portsGlobalKey(machineId, networkName)
-		doc := portsDoc{Id: key}
-		ports = &Ports{st, doc, true}
+		ports = &Ports{st, *newPortsDoc(st, key), true}
 	} else if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -10,6 +10,7 @@ package presence
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -462,29 +463,44 @@ func (w *Watcher) sync() error {
 	return nil
 }
 
-// Pinger periodically reports that a specific key is alive, so that
-// watchers interested on that fact can react appropriately.
+// Pinger periodically reports that one or more keys are alive, so that
+// watchers interested on that fact can react appropriately. All the
+// keys are reported with a single update per time slot.
 type Pinger struct {
 	mu       sync.Mutex
 	tomb     tomb.Tomb
 	base     *mgo.Collection
 	pings    *mgo.Collection
 	started  bool
-	beingKey string
-	beingSeq int64
-	fieldKey string // hex(beingKey / 63)
-	fieldBit uint64 // 1 << (beingKey%63)
-	lastSlot int64
 	delta    time.Duration
+	lastSlot int64
+
+	// beingsMu guards beings and serializes pings with changes
+	// to the set of keys reported.
+	beingsMu sync.Mutex
+	beings   []*pingerBeing
+}
+
+// pingerBeing holds the sequence allocated for a key reported
+// by a Pinger.
+type pingerBeing struct {
+	key      string
+	seq      int64
+	fieldKey string // hex(seq / 63)
+	fieldBit uint64 // 1 << (seq % 63)
 }
 
 // NewPinger returns a new Pinger to report that key is alive.
 // It starts reporting after Start is called.
 func NewPinger(base *mgo.Collection, key string) *Pinger {
-	return &Pinger{base: base, pings: pingsC(base), beingKey: key}
+	return &Pinger{
+		base:   base,
+		pings:  pingsC(base),
+		beings: []*pingerBeing{{key: key}},
+	}
 }
 
-// Start starts periodically reporting that p's key is alive.
+// Start starts periodically reporting that p's keys are alive.
 func (p *Pinger) Start() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -495,7 +511,7 @@ func (p *Pinger) Start() error {
 	if err := p.prepare(); err != nil {
 		return errors.Trace(err)
 	}
-	logger.Tracef("starting pinger for %q with seq=%d", p.beingKey, p.beingSeq)
+	logger.Tracef("starting pinger for %s", p.describe())
 	if err := p.ping(); err != nil {
 		return errors.Trace(err)
 	}
@@ -515,6 +531,75 @@ func (p *Pinger) Start() error {
 	return nil
 }
 
+// Add starts reporting that key is alive in addition to p's other
+// keys. If p is started, key is reported alive immediately.
+func (p *Pinger) Add(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.beingsMu.Lock()
+	defer p.beingsMu.Unlock()
+	for _, being := range p.beings {
+		if being.key == key {
+			return errors.Errorf("pinger already reports %q", key)
+		}
+	}
+	being := &pingerBeing{key: key}
+	if !p.started {
+		p.beings = append(p.beings, being)
+		return nil
+	}
+	session := p.base.Database.Session.Copy()
+	defer session.Close()
+	if err := prepareBeing(p.base.With(session), being); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Tracef("adding %q with seq=%d to pinger", being.key, being.seq)
+	// The slot already pinged for p's other keys may safely
+	// be pinged for the new one too.
+	udoc := bson.D{{"$inc", bson.D{{"alive." + being.fieldKey, being.fieldBit}}}}
+	if _, err := p.pings.With(session).UpsertId(p.lastSlot, udoc); err != nil {
+		return errors.Trace(err)
+	}
+	p.beings = append(p.beings, being)
+	return nil
+}
+
+// Remove stops reporting that key is alive. If p is started, key
+// is immediately reported dead.
+func (p *Pinger) Remove(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.beingsMu.Lock()
+	defer p.beingsMu.Unlock()
+	for i, being := range p.beings {
+		if being.key != key {
+			continue
+		}
+		p.beings = append(p.beings[:i], p.beings[i+1:]...)
+		if !p.started {
+			return nil
+		}
+		logger.Tracef("removing %q with seq=%d from pinger", being.key, being.seq)
+		session := p.pings.Database.Session.Copy()
+		defer session.Close()
+		udoc := bson.D{{"$inc", bson.D{{"dead." + being.fieldKey, being.fieldBit}}}}
+		_, err := p.pings.With(session).UpsertId(p.lastSlot, udoc)
+		return errors.Trace(err)
+	}
+	return errors.Errorf("pinger does not report %q", key)
+}
+
+// Keys returns the keys reported by p.
+func (p *Pinger) Keys() []string {
+	p.beingsMu.Lock()
+	defer p.beingsMu.Unlock()
+	keys := make([]string, len(p.beings))
+	for i, being := range p.beings {
+		keys[i] = being.key
+	}
+	return keys
+}
+
 // Stop stops p's periodical ping.
 // Watchers will not notice p has stopped pinging until the
 // previous ping times out.
@@ -522,7 +607,7 @@ func (p *Pinger) Stop() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	if p.started {
-		logger.Tracef("stopping pinger for %q with seq=%d", p.beingKey, p.beingSeq)
+		logger.Tracef("stopping pinger for %s", p.describe())
 	}
 	p.tomb.Kill(nil)
 	err := p.tomb.Wait()
@@ -537,10 +622,10 @@ func (p *Pinger) Kill() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	if p.started {
-		logger.Tracef("killing pinger for %q (was started)", p.beingKey)
+		logger.Tracef("killing pinger for %s (was started)", p.describe())
 		return p.killStarted()
 	}
-	logger.Tracef("killing pinger for %q (was stopped)", p.beingKey)
+	logger.Tracef("killing pinger for %s (was stopped)", p.describe())
 	return p.killStopped()
 }
 
@@ -553,7 +638,7 @@ func (p *Pinger) killStarted() error {
 	p.started = false
 
 	slot := p.lastSlot
-	udoc := bson.D{{"$inc", bson.D{{"dead." + p.fieldKey, p.fieldBit}}}}
+	udoc := bson.D{{"$inc", p.fields("dead")}}
 	session := p.pings.Database.Session.Copy()
 	defer session.Close()
 	pings := p.pings.With(session)
@@ -564,17 +649,14 @@ func (p *Pinger) killStarted() error {
 }
 
 // killStopped kills the pinger while it is not running, by
-// first allocating a new sequence, and then atomically recording
-// the new sequence both as alive and dead at once.
+// first allocating new sequences, and then atomically recording
+// the new sequences both as alive and dead at once.
 func (p *Pinger) killStopped() error {
 	if err := p.prepare(); err != nil {
 		return err
 	}
 	slot := timeSlot(time.Now(), p.delta)
-	udoc := bson.D{{"$inc", bson.D{
-		{"dead." + p.fieldKey, p.fieldBit},
-		{"alive." + p.fieldKey, p.fieldBit},
-	}}}
+	udoc := bson.D{{"$inc", append(p.fields("dead"), p.fields("alive")...)}}
 	session := p.pings.Database.Session.Copy()
 	defer session.Close()
 	pings := p.pings.With(session)
@@ -597,34 +679,80 @@ func (p *Pinger) loop() error {
 	}
 }
 
-// prepare allocates a new unique sequence for the
-// pinger key and prepares the pinger to use it.
+// prepare allocates a new unique sequence for each of the
+// pinger's keys and prepares the pinger to use them.
 func (p *Pinger) prepare() error {
+	p.beingsMu.Lock()
+	defer p.beingsMu.Unlock()
+	session := p.base.Database.Session.Copy()
+	defer session.Close()
+	base := p.base.With(session)
+	for _, being := range p.beings {
+		if err := prepareBeing(base, being); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	p.lastSlot = 0
+	return nil
+}
+
+// prepareBeing allocates a new unique sequence for being
+// and records the key it is used for.
+func prepareBeing(base *mgo.Collection, being *pingerBeing) error {
 	change := mgo.Change{
 		Update:    bson.D{{"$inc", bson.D{{"seq", int64(1)}}}},
 		Upsert:    true,
 		ReturnNew: true,
 	}
-	session := p.base.Database.Session.Copy()
-	defer session.Close()
-	base := p.base.With(session)
 	seqs := seqsC(base)
 	var seq struct{ Seq int64 }
 	if _, err := seqs.FindId("beings").Apply(change, &seq); err != nil {
 		return errors.Trace(err)
 	}
-	p.beingSeq = seq.Seq
-	p.fieldKey = fmt.Sprintf("%x", p.beingSeq/63)
-	p.fieldBit = 1 << uint64(p.beingSeq%63)
-	p.lastSlot = 0
+	being.seq = seq.Seq
+	being.fieldKey = fmt.Sprintf("%x", being.seq/63)
+	being.fieldBit = 1 << uint64(being.seq%63)
 	beings := beingsC(base)
-	return errors.Trace(beings.Insert(beingInfo{p.beingSeq, p.beingKey}))
+	return errors.Trace(beings.Insert(beingInfo{being.seq, being.key}))
+}
+
+// fields returns the fields to be incremented under the given
+// top-level field of a time slot document to record all of the
+// pinger's keys. Keys whose sequences share a field are combined,
+// as a field may only be incremented once in an update.
+func (p *Pinger) fields(top string) bson.D {
+	var fields bson.D
+	index := make(map[string]int)
+	for _, being := range p.beings {
+		name := top + "." + being.fieldKey
+		if i, ok := index[name]; ok {
+			fields[i].Value = fields[i].Value.(uint64) | being.fieldBit
+			continue
+		}
+		index[name] = len(fields)
+		fields = append(fields, bson.DocElem{name, being.fieldBit})
+	}
+	return fields
+}
+
+// describe returns a description of the pinger's keys and
+// sequences for logging.
+func (p *Pinger) describe() string {
+	p.beingsMu.Lock()
+	defer p.beingsMu.Unlock()
+	var parts []string
+	for _, being := range p.beings {
+		parts = append(parts, fmt.Sprintf("%q with seq=%d", being.key, being.seq))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // ping records updates the current time slot with the
-// sequence in use by the pinger.
+// sequences in use by the pinger.
 func (p *Pinger) ping() (err error) {
-	logger.Tracef("pinging %q with seq=%d", p.beingKey, p.beingSeq)
+	p.beingsMu.Lock()
+	defer p.beingsMu.Unlock()
+	logger.Tracef("pinging %d keys", len(p.beings))
 	defer func() {
 		// If the session is killed from underneath us, it panics when we
 		// try to copy it, so deal with that here.
@@ -652,8 +780,11 @@ func (p *Pinger) ping() (err error) {
 		return nil
 	}
 	p.lastSlot = slot
+	if len(p.beings) == 0 {
+		return nil
+	}
 	pings := p.pings.With(session)
-	if _, err = pings.UpsertId(slot, bson.D{{"$inc", bson.D{{"alive." + p.fieldKey, p.fieldBit}}}}); err != nil {
+	if _, err = pings.UpsertId(slot, bson.D{{"$inc", p.fields("alive")}}); err != nil {
 		return errors.Trace(err)
 	}
 	return nil
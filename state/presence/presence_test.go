@@ -257,6 +257,55 @@ func (s *PresenceSuite) TestExpiry(c *gc.C) {
 	assertNoChange(c, ch)
 }
 
+func (s *PresenceSuite) TestMultipleKeys(c *gc.C) {
+	w := presence.NewWatcher(s.presence)
+	p := presence.NewPinger(s.presence, "a")
+	defer w.Stop()
+	defer p.Stop()
+
+	cha := make(chan presence.Change, 1)
+	chb := make(chan presence.Change, 1)
+	chc := make(chan presence.Change, 1)
+	w.Watch("a", cha)
+	w.Watch("b", chb)
+	w.Watch("c", chc)
+	assertChange(c, cha, presence.Change{"a", false})
+	assertChange(c, chb, presence.Change{"b", false})
+	assertChange(c, chc, presence.Change{"c", false})
+
+	// Keys added before starting are reported once started.
+	c.Assert(p.Add("b"), gc.IsNil)
+	c.Assert(p.Add("b"), gc.ErrorMatches, `pinger already reports "b"`)
+	c.Assert(p.Start(), gc.IsNil)
+	w.StartSync()
+	assertChange(c, cha, presence.Change{"a", true})
+	assertChange(c, chb, presence.Change{"b", true})
+	assertNoChange(c, chc)
+
+	// Keys added while started are reported immediately.
+	c.Assert(p.Add("c"), gc.IsNil)
+	c.Assert(p.Keys(), gc.DeepEquals, []string{"a", "b", "c"})
+	w.StartSync()
+	assertChange(c, chc, presence.Change{"c", true})
+	assertNoChange(c, cha)
+	assertNoChange(c, chb)
+
+	// Removed keys are reported dead immediately.
+	c.Assert(p.Remove("a"), gc.IsNil)
+	c.Assert(p.Remove("a"), gc.ErrorMatches, `pinger does not report "a"`)
+	w.StartSync()
+	assertChange(c, cha, presence.Change{"a", false})
+	assertNoChange(c, chb)
+	assertNoChange(c, chc)
+
+	// Killing the pinger reports all its keys dead.
+	c.Assert(p.Kill(), gc.IsNil)
+	w.StartSync()
+	assertChange(c, chb, presence.Change{"b", false})
+	assertChange(c, chc, presence.Change{"c", false})
+	assertNoChange(c, cha)
+}
+
 func (s *PresenceSuite) TestWatchPeriod(c *gc.C) {
 	presence.FakePeriod(1)
 	presence.RealTimeSlot()
@@ -37,24 +37,45 @@ func relationKey(endpoints []Endpoint) string {
 // relationDoc is the internal representation of a Relation in MongoDB.
 // Note the correspondence with RelationInfo in apiserver/params.
 type relationDoc struct {
-	Key       string `bson:"_id"`
+	DocID     string `bson:"_id"`
+	Key       string `bson:"key"`
+	EnvUUID   string `bson:"env-uuid"`
 	Id        int
 	Endpoints []Endpoint
 	Life      Life
 	UnitCount int
+	Network   string `bson:"network,omitempty"`
 }
 
 // Relation represents a relation between one or two service endpoints.
 type Relation struct {
 	st  *State
 	doc relationDoc
+	annotator
 }
 
 func newRelation(st *State, doc *relationDoc) *Relation {
-	return &Relation{
+	relation := &Relation{
 		st:  st,
 		doc: *doc,
 	}
+	relation.annotator = annotator{
+		globalKey: relation.globalKey(),
+		tag:       relation.Tag(),
+		st:        st,
+	}
+	return relation
+}
+
+// relationGlobalKey returns the global database key for the relation
+// with the given key.
+func relationGlobalKey(key string) string {
+	return "r#" + key
+}
+
+// globalKey returns the global database key for the relation.
+func (r *Relation) globalKey() string {
+	return relationGlobalKey(r.doc.Key)
 }
 
 func (r *Relation) String() string {
@@ -74,7 +95,7 @@ func (r *Relation) Refresh() error {
 	defer closer()
 
 	doc := relationDoc{}
-	err := relations.FindId(r.doc.Key).One(&doc)
+	err := relations.FindId(r.doc.DocID).One(&doc)
 	if err == mgo.ErrNotFound {
 		return errors.NotFoundf("relation %v", r)
 	}
@@ -153,7 +174,7 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 	}
 	return []txn.Op{{
 		C:      relationsC,
-		Id:     r.doc.Key,
+		Id:     r.doc.DocID,
 		Assert: bson.D{{"life", Alive}, {"unitcount", bson.D{{"$gt", 0}}}},
 		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
 	}}, false, nil
@@ -167,7 +188,7 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.Op, error) {
 	relOp := txn.Op{
 		C:      relationsC,
-		Id:     r.doc.Key,
+		Id:     r.doc.DocID,
 		Remove: true,
 	}
 	if departingUnit != nil {
@@ -222,7 +243,7 @@ func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.O
 		})
 	}
 	cleanupOp := r.st.newCleanupOp(cleanupRelationSettings, fmt.Sprintf("r#%d#", r.Id()))
-	return append(ops, cleanupOp), nil
+	return append(ops, cleanupOp, annotationRemoveOp(r.st, r.globalKey())), nil
 }
 
 // Id returns the integer internal relation key. This is exposed
@@ -249,6 +270,12 @@ func (r *Relation) Endpoints() []Endpoint {
 	return r.doc.Endpoints
 }
 
+// Network returns the name of the network that the relation's traffic
+// should use, or "" if the relation was not added with one.
+func (r *Relation) Network() string {
+	return r.doc.Network
+}
+
 // RelatedEndpoints returns the endpoints of the relation r with which
 // units of the named service will establish relations. If the service
 // is not part of the relation r, an error will be returned.
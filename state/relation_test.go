@@ -150,6 +150,33 @@ func (s *RelationSuite) TestAddRelation(c *gc.C) {
 	assertOneRelation(c, wordpress, 0, wordpressEP, mysqlEP)
 }
 
+func (s *RelationSuite) TestAddRelationVia(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wordpressEP, err := wordpress.Endpoint("db")
+	c.Assert(err, gc.IsNil)
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysqlEP, err := mysql.Endpoint("server")
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.State.AddRelationVia("backend", wordpressEP, mysqlEP)
+	c.Assert(err, gc.ErrorMatches, `cannot add relation "wordpress:db mysql:server": network "backend" does not exist`)
+	assertNoRelations(c, wordpress)
+
+	_, err = s.State.AddNetwork(state.NetworkInfo{
+		Name:       "backend",
+		ProviderId: "backend-id",
+		CIDR:       "10.1.0.0/16",
+	})
+	c.Assert(err, gc.IsNil)
+	rel, err := s.State.AddRelationVia("backend", wordpressEP, mysqlEP)
+	c.Assert(err, gc.IsNil)
+	c.Assert(rel.Network(), gc.Equals, "backend")
+
+	rel, err = s.State.EndpointsRelation(wordpressEP, mysqlEP)
+	c.Assert(err, gc.IsNil)
+	c.Assert(rel.Network(), gc.Equals, "backend")
+}
+
 func (s *RelationSuite) TestAddRelationSeriesNeedNotMatch(c *gc.C) {
 	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
 	wordpressEP, err := wordpress.Endpoint("db")
@@ -253,6 +280,35 @@ func (s *RelationSuite) TestDestroyPeerRelation(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *RelationSuite) TestAnnotatorForRelation(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, gc.IsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, gc.IsNil)
+	testAnnotator(c, func() (state.Annotator, error) {
+		return s.State.KeyRelation(rel.String())
+	})
+}
+
+func (s *RelationSuite) TestAnnotationRemovalForRelation(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, gc.IsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, gc.IsNil)
+	annotations := map[string]string{"mykey": "myvalue"}
+	err = rel.SetAnnotations(annotations)
+	c.Assert(err, gc.IsNil)
+	err = rel.Destroy()
+	c.Assert(err, gc.IsNil)
+	ann, err := rel.Annotations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ann, gc.DeepEquals, make(map[string]string))
+}
+
 func assertNoRelations(c *gc.C, srv *state.Service) {
 	rels, err := srv.Relations()
 	c.Assert(err, gc.IsNil)
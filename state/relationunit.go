@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/juju/errors"
@@ -39,10 +40,37 @@ func (ru *RelationUnit) Endpoint() Endpoint {
 }
 
 // PrivateAddress returns the private address of the unit and whether it is valid.
+// If the relation was added with a network, the unit's machine address in that
+// network is preferred.
 func (ru *RelationUnit) PrivateAddress() (string, bool) {
+	if name := ru.relation.Network(); name != "" {
+		if address, ok := ru.networkAddress(name); ok {
+			return address, true
+		}
+	}
 	return ru.unit.PrivateAddress()
 }
 
+// networkAddress returns the address of the unit's machine that lies
+// within the named network, and whether there is one.
+func (ru *RelationUnit) networkAddress(name string) (string, bool) {
+	nw, err := ru.st.Network(name)
+	if err != nil {
+		unitLogger.Errorf("relation %v cannot get network %q: %v", ru.relation, name, err)
+		return "", false
+	}
+	_, ipNet, err := net.ParseCIDR(nw.CIDR())
+	if err != nil {
+		return "", false
+	}
+	for _, addr := range ru.unit.addressesOfMachine() {
+		if ip := net.ParseIP(addr.Value); ip != nil && ipNet.Contains(ip) {
+			return addr.Value, true
+		}
+	}
+	return "", false
+}
+
 // ErrCannotEnterScope indicates that a relation unit failed to enter its scope
 // due to either the unit or the relation not being Alive.
 var ErrCannotEnterScope = stderrors.New("cannot enter scope: unit or relation is not alive")
@@ -79,7 +107,8 @@ func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
 	if err != nil {
 		return err
 	}
-	if count, err := relationScopes.FindId(ruKey).Count(); err != nil {
+	ruDocID := ru.st.docID(ruKey)
+	if count, err := relationScopes.FindId(ruDocID).Count(); err != nil {
 		return err
 	} else if count != 0 {
 		return nil
@@ -90,14 +119,14 @@ func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
 	// * TODO(fwereade): check unit status == params.StatusStarted (this
 	//   breaks a bunch of tests in a boring but noisy-to-fix way, and is
 	//   being saved for a followup).
-	unitDocID, relationKey := ru.unit.doc.DocID, ru.relation.doc.Key
+	unitDocID, relationDocID := ru.unit.doc.DocID, ru.relation.doc.DocID
 	ops := []txn.Op{{
 		C:      unitsC,
 		Id:     unitDocID,
 		Assert: isAliveDoc,
 	}, {
 		C:      relationsC,
-		Id:     relationKey,
+		Id:     relationDocID,
 		Assert: isAliveDoc,
 		Update: bson.D{{"$inc", bson.D{{"unitcount", 1}}}},
 	}}
@@ -107,7 +136,7 @@ func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
 	//   before we create the scope doc, because the existence of a scope doc
 	//   is considered to be a guarantee of the existence of a settings doc.
 	settingsChanged := func() (bool, error) { return false, nil }
-	if count, err := db.C(settingsC).FindId(ruKey).Count(); err != nil {
+	if count, err := db.C(settingsC).FindId(ru.st.docID(ruKey)).Count(); err != nil {
 		return err
 	} else if count == 0 {
 		ops = append(ops, createSettingsOp(ru.st, ruKey, settings))
@@ -123,9 +152,13 @@ func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
 	// * Create the scope doc.
 	ops = append(ops, txn.Op{
 		C:      relationScopesC,
-		Id:     ruKey,
+		Id:     ruDocID,
 		Assert: txn.DocMissing,
-		Insert: relationScopeDoc{Key: ruKey},
+		Insert: relationScopeDoc{
+			DocID:   ruDocID,
+			Key:     ruKey,
+			EnvUUID: ru.st.EnvironTag().Id(),
+		},
 	})
 
 	// * If the unit should have a subordinate, and does not, create it.
@@ -141,7 +174,7 @@ func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
 	if err := ru.st.runTransaction(ops); err != txn.ErrAborted {
 		return err
 	}
-	if count, err := relationScopes.FindId(ruKey).Count(); err != nil {
+	if count, err := relationScopes.FindId(ruDocID).Count(); err != nil {
 		return err
 	} else if count != 0 {
 		// The scope document exists, so we're actually already in scope.
@@ -158,7 +191,7 @@ func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
 	} else if !alive {
 		return ErrCannotEnterScope
 	}
-	if alive, err := isAliveWithSession(db.C(relationsC), relationKey); err != nil {
+	if alive, err := isAliveWithSession(db.C(relationsC), relationDocID); err != nil {
 		return err
 	} else if !alive {
 		return ErrCannotEnterScope
@@ -209,7 +242,11 @@ func (ru *RelationUnit) subordinateOps() ([]txn.Op, string, error) {
 		return nil, "", fmt.Errorf("expected single related endpoint, got %v", related)
 	}
 	serviceName, unitName := related[0].ServiceName, ru.unit.doc.Name
-	selSubordinate := bson.D{{"service", serviceName}, {"principal", unitName}}
+	selSubordinate := bson.D{
+		{"service", serviceName},
+		{"principal", unitName},
+		{"env-uuid", ru.st.EnvironTag().Id()},
+	}
 	var lDoc lifeDoc
 	if err := units.Find(selSubordinate).One(&lDoc); err == mgo.ErrNotFound {
 		service, err := ru.st.Service(serviceName)
@@ -241,14 +278,15 @@ func (ru *RelationUnit) PrepareLeaveScope() error {
 	if err != nil {
 		return err
 	}
-	if count, err := relationScopes.FindId(key).Count(); err != nil {
+	docID := ru.st.docID(key)
+	if count, err := relationScopes.FindId(docID).Count(); err != nil {
 		return err
 	} else if count == 0 {
 		return nil
 	}
 	ops := []txn.Op{{
 		C:      relationScopesC,
-		Id:     key,
+		Id:     docID,
 		Update: bson.D{{"$set", bson.D{{"departing", true}}}},
 	}}
 	return ru.st.runTransaction(ops)
@@ -289,6 +327,7 @@ func (ru *RelationUnit) LeaveScope() error {
 	// to have a Dying relation with a smaller-than-real unit count, because
 	// Destroy changes the Life attribute in memory (units could join before
 	// the database is actually changed).
+	docID := ru.st.docID(key)
 	desc := fmt.Sprintf("unit %q in relation %q", ru.unit, ru.relation)
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if attempt > 0 {
@@ -298,7 +337,7 @@ func (ru *RelationUnit) LeaveScope() error {
 				return nil, err
 			}
 		}
-		count, err := relationScopes.FindId(key).Count()
+		count, err := relationScopes.FindId(docID).Count()
 		if err != nil {
 			return nil, fmt.Errorf("cannot examine scope for %s: %v", desc, err)
 		} else if count == 0 {
@@ -306,21 +345,21 @@ func (ru *RelationUnit) LeaveScope() error {
 		}
 		ops := []txn.Op{{
 			C:      relationScopesC,
-			Id:     key,
+			Id:     docID,
 			Assert: txn.DocExists,
 			Remove: true,
 		}}
 		if ru.relation.doc.Life == Alive {
 			ops = append(ops, txn.Op{
 				C:      relationsC,
-				Id:     ru.relation.doc.Key,
+				Id:     ru.relation.doc.DocID,
 				Assert: bson.D{{"life", Alive}},
 				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
 			})
 		} else if ru.relation.doc.UnitCount > 1 {
 			ops = append(ops, txn.Op{
 				C:      relationsC,
-				Id:     ru.relation.doc.Key,
+				Id:     ru.relation.doc.DocID,
 				Assert: bson.D{{"unitcount", bson.D{{"$gt", 1}}}},
 				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
 			})
@@ -360,7 +399,7 @@ func (ru *RelationUnit) inScope(sel bson.D) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	sel = append(sel, bson.D{{"_id", key}}...)
+	sel = append(sel, bson.D{{"_id", ru.st.docID(key)}}...)
 	count, err := relationScopes.Find(sel).Count()
 	if err != nil {
 		return false, err
@@ -426,7 +465,9 @@ func (ru *RelationUnit) key(uname string) (string, error) {
 // relationScopeDoc represents a unit which is in a relation scope.
 // The relation, container, role, and unit are all encoded in the key.
 type relationScopeDoc struct {
-	Key       string `bson:"_id"`
+	DocID     string `bson:"_id"`
+	Key       string `bson:"key"`
+	EnvUUID   string `bson:"env-uuid"`
 	Departing bool
 }
 
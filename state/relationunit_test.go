@@ -55,6 +55,53 @@ func assertNotJoined(c *gc.C, ru *state.RelationUnit) {
 	c.Assert(ok, jc.IsFalse)
 }
 
+func (s *RelationUnitSuite) TestPrivateAddressWithNetwork(c *gc.C) {
+	_, err := s.State.AddNetwork(state.NetworkInfo{
+		Name:       "backend",
+		ProviderId: "backend-id",
+		CIDR:       "10.1.0.0/16",
+	})
+	c.Assert(err, gc.IsNil)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wordpressEP, err := wordpress.Endpoint("db")
+	c.Assert(err, gc.IsNil)
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysqlEP, err := mysql.Endpoint("server")
+	c.Assert(err, gc.IsNil)
+	rel, err := s.State.AddRelationVia("backend", wordpressEP, mysqlEP)
+	c.Assert(err, gc.IsNil)
+
+	unit, err := mysql.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, gc.IsNil)
+	mId, err := unit.AssignedMachineId()
+	c.Assert(err, gc.IsNil)
+	machine, err := s.State.Machine(mId)
+	c.Assert(err, gc.IsNil)
+	err = machine.SetAddresses(
+		network.NewAddress("10.0.0.5", network.ScopeCloudLocal),
+		network.NewAddress("10.1.0.5", network.ScopeCloudLocal),
+	)
+	c.Assert(err, gc.IsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, gc.IsNil)
+
+	// The address in the relation's network is preferred to the
+	// unit's usual private address.
+	address, ok := ru.PrivateAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(address, gc.Equals, "10.1.0.5")
+
+	// Without an address in the network, the usual private address
+	// is used.
+	err = machine.SetAddresses(network.NewAddress("10.0.0.5", network.ScopeCloudLocal))
+	c.Assert(err, gc.IsNil)
+	address, ok = ru.PrivateAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(address, gc.Equals, "10.0.0.5")
+}
+
 func (s *RelationUnitSuite) TestReadSettingsErrors(c *gc.C) {
 	riak := s.AddTestingService(c, "riak", s.AddTestingCharm(c, "riak"))
 	u0, err := riak.AddUnit()
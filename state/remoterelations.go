@@ -0,0 +1,343 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// RemoteRelation records the use of a service offer by a service in
+// another environment.
+type RemoteRelation struct {
+	// OfferURL identifies the service offer being consumed.
+	OfferURL string
+
+	// ConsumerEnvUUID is the UUID of the consuming environment.
+	ConsumerEnvUUID string
+
+	// ConsumerService is the name of the consuming service in its
+	// own environment.
+	ConsumerService string
+
+	// IngressAddresses holds the addresses from which the consuming
+	// service's units will connect to the offered service.
+	IngressAddresses []string
+}
+
+// remoteRelationDoc represents a RemoteRelation in the database.
+type remoteRelationDoc struct {
+	DocID            string   `bson:"_id"`
+	EnvUUID          string   `bson:"env-uuid"`
+	OfferURL         string   `bson:"offer-url"`
+	ConsumerEnvUUID  string   `bson:"consumer-env-uuid"`
+	ConsumerService  string   `bson:"consumer-service"`
+	IngressAddresses []string `bson:"ingress-addresses"`
+}
+
+func remoteRelationKey(offerURL, consumerEnvUUID, consumerService string) string {
+	return fmt.Sprintf("%s#%s#%s", offerURL, consumerEnvUUID, consumerService)
+}
+
+// AddRemoteRelation records that a service in another environment is
+// consuming the service offer identified by rel.OfferURL. If the
+// consumer is already recorded, its ingress addresses are replaced.
+func (st *State) AddRemoteRelation(rel RemoteRelation) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add remote relation to %q", rel.OfferURL)
+	if rel.ConsumerEnvUUID == "" || rel.ConsumerService == "" {
+		return errors.New("consumer environment and service must be specified")
+	}
+	if rel.ConsumerEnvUUID == st.EnvironTag().Id() {
+		return errors.New("offer cannot be consumed by its own environment")
+	}
+	docID := st.docID(remoteRelationKey(rel.OfferURL, rel.ConsumerEnvUUID, rel.ConsumerService))
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.ServiceOffer(rel.OfferURL); err != nil {
+			return nil, err
+		}
+		ops := []txn.Op{{
+			C:      serviceOffersC,
+			Id:     st.docID(rel.OfferURL),
+			Assert: txn.DocExists,
+		}}
+		remoteRelations, closer := st.getCollection(remoteRelationsC)
+		defer closer()
+		if count, err := remoteRelations.FindId(docID).Count(); err != nil {
+			return nil, err
+		} else if count > 0 {
+			return append(ops, txn.Op{
+				C:      remoteRelationsC,
+				Id:     docID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{
+					{"ingress-addresses", rel.IngressAddresses},
+				}}},
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      remoteRelationsC,
+			Id:     docID,
+			Assert: txn.DocMissing,
+			Insert: &remoteRelationDoc{
+				DocID:            docID,
+				EnvUUID:          st.EnvironTag().Id(),
+				OfferURL:         rel.OfferURL,
+				ConsumerEnvUUID:  rel.ConsumerEnvUUID,
+				ConsumerService:  rel.ConsumerService,
+				IngressAddresses: rel.IngressAddresses,
+			},
+		}), nil
+	}
+	return st.run(buildTxn)
+}
+
+// RemoteRelations returns the remote relations made to the service
+// offer with the given URL.
+func (st *State) RemoteRelations(offerURL string) ([]RemoteRelation, error) {
+	docs, err := readRemoteRelations(st, offerURL)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get remote relations to %q", offerURL)
+	}
+	result := make([]RemoteRelation, len(docs))
+	for i, doc := range docs {
+		result[i] = RemoteRelation{
+			OfferURL:         doc.OfferURL,
+			ConsumerEnvUUID:  doc.ConsumerEnvUUID,
+			ConsumerService:  doc.ConsumerService,
+			IngressAddresses: doc.IngressAddresses,
+		}
+	}
+	return result, nil
+}
+
+// RemoveRemoteRelation records that the consuming service no longer
+// uses the service offer identified by rel.OfferURL. Any of its units
+// still in the relation's scope leave it.
+func (st *State) RemoveRemoteRelation(rel RemoteRelation) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove remote relation to %q", rel.OfferURL)
+	key := remoteRelationKey(rel.OfferURL, rel.ConsumerEnvUUID, rel.ConsumerService)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if err := assertRemoteRelationExists(st, key); err != nil {
+			return nil, err
+		}
+		ops := []txn.Op{{
+			C:      remoteRelationsC,
+			Id:     st.docID(key),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}
+		unitOps, err := removeRemoteRelationUnitsOps(st, key)
+		if err != nil {
+			return nil, err
+		}
+		return append(ops, unitOps...), nil
+	}
+	return st.run(buildTxn)
+}
+
+func assertRemoteRelationExists(st *State, key string) error {
+	remoteRelations, closer := st.getCollection(remoteRelationsC)
+	defer closer()
+
+	if count, err := remoteRelations.FindId(st.docID(key)).Count(); err != nil {
+		return err
+	} else if count == 0 {
+		return errors.NotFoundf("remote relation")
+	}
+	return nil
+}
+
+func readRemoteRelations(st *State, offerURL string) ([]remoteRelationDoc, error) {
+	remoteRelations, closer := st.getCollection(remoteRelationsC)
+	defer closer()
+
+	var docs []remoteRelationDoc
+	query := bson.D{
+		{"env-uuid", st.EnvironTag().Id()},
+		{"offer-url", offerURL},
+	}
+	if err := remoteRelations.Find(query).Sort("_id").All(&docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// removeRemoteRelationsOps returns the operations required to remove
+// the records of all remote relations made to the given offer, and of
+// the units in their scopes.
+func removeRemoteRelationsOps(st *State, offerURL string) ([]txn.Op, error) {
+	docs, err := readRemoteRelations(st, offerURL)
+	if err != nil {
+		return nil, err
+	}
+	var ops []txn.Op
+	for _, doc := range docs {
+		ops = append(ops, txn.Op{
+			C:      remoteRelationsC,
+			Id:     doc.DocID,
+			Remove: true,
+		})
+		key := remoteRelationKey(doc.OfferURL, doc.ConsumerEnvUUID, doc.ConsumerService)
+		unitOps, err := removeRemoteRelationUnitsOps(st, key)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, unitOps...)
+	}
+	return ops, nil
+}
+
+// RemoteRelationUnit describes a unit of a consuming service that has
+// entered the scope of a remote relation, and the relation settings
+// it has published to the offered service.
+type RemoteRelationUnit struct {
+	// Name is the name of the unit in its own environment.
+	Name string
+
+	// Settings holds the unit's relation settings.
+	Settings map[string]string
+}
+
+// remoteRelationUnitDoc represents a RemoteRelationUnit in the
+// database.
+type remoteRelationUnitDoc struct {
+	DocID    string            `bson:"_id"`
+	EnvUUID  string            `bson:"env-uuid"`
+	Relation string            `bson:"relation"`
+	Unit     string            `bson:"unit"`
+	Settings map[string]string `bson:"settings"`
+}
+
+func remoteRelationUnitKey(relationKey, unitName string) string {
+	return fmt.Sprintf("%s#%s", relationKey, unitName)
+}
+
+// EnterRemoteScope records that the given unit of the consuming
+// service has entered the scope of the remote relation rel, with the
+// given relation settings. If the unit is already in scope, its
+// settings are replaced.
+func (st *State) EnterRemoteScope(rel RemoteRelation, unit RemoteRelationUnit) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot enter scope of remote relation to %q for unit %q", rel.OfferURL, unit.Name)
+	if !names.IsValidUnit(unit.Name) {
+		return errors.NotValidf("unit name")
+	}
+	if names.UnitService(unit.Name) != rel.ConsumerService {
+		return errors.Errorf("unit does not belong to service %q", rel.ConsumerService)
+	}
+	key := remoteRelationKey(rel.OfferURL, rel.ConsumerEnvUUID, rel.ConsumerService)
+	docID := st.docID(remoteRelationUnitKey(key, unit.Name))
+	settings := make(map[string]string)
+	for k, v := range unit.Settings {
+		settings[escapeReplacer.Replace(k)] = v
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if err := assertRemoteRelationExists(st, key); err != nil {
+			return nil, err
+		}
+		ops := []txn.Op{{
+			C:      remoteRelationsC,
+			Id:     st.docID(key),
+			Assert: txn.DocExists,
+		}}
+		remoteRelationUnits, closer := st.getCollection(remoteRelationUnitsC)
+		defer closer()
+		if count, err := remoteRelationUnits.FindId(docID).Count(); err != nil {
+			return nil, err
+		} else if count > 0 {
+			return append(ops, txn.Op{
+				C:      remoteRelationUnitsC,
+				Id:     docID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"settings", settings}}}},
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      remoteRelationUnitsC,
+			Id:     docID,
+			Assert: txn.DocMissing,
+			Insert: &remoteRelationUnitDoc{
+				DocID:    docID,
+				EnvUUID:  st.EnvironTag().Id(),
+				Relation: key,
+				Unit:     unit.Name,
+				Settings: settings,
+			},
+		}), nil
+	}
+	return st.run(buildTxn)
+}
+
+// LeaveRemoteScope records that the named unit of the consuming
+// service has left the scope of the remote relation rel. It does
+// nothing if the unit is not in scope.
+func (st *State) LeaveRemoteScope(rel RemoteRelation, unitName string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot leave scope of remote relation to %q for unit %q", rel.OfferURL, unitName)
+	key := remoteRelationKey(rel.OfferURL, rel.ConsumerEnvUUID, rel.ConsumerService)
+	ops := []txn.Op{{
+		C:      remoteRelationUnitsC,
+		Id:     st.docID(remoteRelationUnitKey(key, unitName)),
+		Remove: true,
+	}}
+	return st.runTransaction(ops)
+}
+
+// RemoteRelationUnits returns the units of the consuming service that
+// are in the scope of the remote relation rel, ordered by name.
+func (st *State) RemoteRelationUnits(rel RemoteRelation) ([]RemoteRelationUnit, error) {
+	key := remoteRelationKey(rel.OfferURL, rel.ConsumerEnvUUID, rel.ConsumerService)
+	docs, err := readRemoteRelationUnits(st, key)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get units of remote relation to %q", rel.OfferURL)
+	}
+	result := make([]RemoteRelationUnit, len(docs))
+	for i, doc := range docs {
+		settings := make(map[string]string)
+		for k, v := range doc.Settings {
+			settings[unescapeReplacer.Replace(k)] = v
+		}
+		result[i] = RemoteRelationUnit{
+			Name:     doc.Unit,
+			Settings: settings,
+		}
+	}
+	return result, nil
+}
+
+func readRemoteRelationUnits(st *State, relationKey string) ([]remoteRelationUnitDoc, error) {
+	remoteRelationUnits, closer := st.getCollection(remoteRelationUnitsC)
+	defer closer()
+
+	var docs []remoteRelationUnitDoc
+	query := bson.D{
+		{"env-uuid", st.EnvironTag().Id()},
+		{"relation", relationKey},
+	}
+	if err := remoteRelationUnits.Find(query).Sort("unit").All(&docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// removeRemoteRelationUnitsOps returns the operations required to
+// remove the records of the units in the scope of the remote relation
+// with the given key.
+func removeRemoteRelationUnitsOps(st *State, relationKey string) ([]txn.Op, error) {
+	docs, err := readRemoteRelationUnits(st, relationKey)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      remoteRelationUnitsC,
+			Id:     doc.DocID,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
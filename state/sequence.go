@@ -11,14 +11,19 @@ import (
 )
 
 type sequenceDoc struct {
-	Name    string `bson:"_id"`
+	DocID   string `bson:"_id"`
+	Name    string `bson:"name"`
+	EnvUUID string `bson:"env-uuid"`
 	Counter int
 }
 
 func (s *State) sequence(name string) (int, error) {
-	query := s.db.C("sequence").Find(bson.D{{"_id", name}})
+	query := s.db.C(sequenceC).Find(bson.D{{"_id", s.docID(name)}})
 	inc := mgo.Change{
-		Update: bson.M{"$inc": bson.M{"counter": 1}},
+		Update: bson.D{
+			{"$set", bson.D{{"name", name}, {"env-uuid", s.EnvironTag().Id()}}},
+			{"$inc", bson.D{{"counter", 1}}},
+		},
 		Upsert: true,
 	}
 	result := &sequenceDoc{}
@@ -28,3 +33,23 @@ func (s *State) sequence(name string) (int, error) {
 	}
 	return result.Counter, nil
 }
+
+// ensureSequence ensures that the named sequence will not return any
+// number less than next.
+func (s *State) ensureSequence(name string, next int) error {
+	_, err := s.db.C(sequenceC).Upsert(
+		bson.D{{"_id", s.docID(name)}, {"counter", bson.D{{"$lt", next}}}},
+		bson.D{{"$set", bson.D{
+			{"name", name},
+			{"env-uuid", s.EnvironTag().Id()},
+			{"counter", next},
+		}}},
+	)
+	if mgo.IsDup(err) {
+		// The sequence has already passed next.
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("cannot update %q sequence number: %v", name, err)
+	}
+	return nil
+}
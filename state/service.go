@@ -19,6 +19,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/network"
 )
 
 // Service represents the state of a service.
@@ -45,7 +46,12 @@ type serviceDoc struct {
 	Exposed       bool
 	MinUnits      int
 	OwnerTag      string
-	TxnRevno      int64 `bson:"txn-revno"`
+	HookPolicy    *HookPolicy `bson:"hookpolicy,omitempty"`
+	CharmPinned   bool        `bson:"charmpinned,omitempty"`
+	// UnexposedPorts holds the port ranges that are kept closed to
+	// the outside world even though the service is exposed.
+	UnexposedPorts []network.PortRange `bson:"unexposedports,omitempty"`
+	TxnRevno       int64               `bson:"txn-revno"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -159,7 +165,7 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 		if err == errAlreadyDying {
 			relOps = []txn.Op{{
 				C:      relationsC,
-				Id:     rel.doc.Key,
+				Id:     rel.doc.DocID,
 				Assert: bson.D{{"life", Dying}},
 			}}
 		} else if err != nil {
@@ -220,15 +226,17 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		Remove: true,
 	}, {
 		C:      settingsrefsC,
-		Id:     s.settingsKey(),
+		Id:     s.st.docID(s.settingsKey()),
 		Remove: true,
 	}, {
 		C:      settingsC,
-		Id:     s.settingsKey(),
+		Id:     s.st.docID(s.settingsKey()),
 		Remove: true,
 	}}
 	ops = append(ops, removeRequestedNetworksOp(s.st, s.globalKey()))
 	ops = append(ops, removeConstraintsOp(s.st, s.globalKey()))
+	ops = append(ops, removeAgentVersionPinOp(s.st, s.globalKey()))
+	ops = append(ops, removeLeadershipOps(s.st, s.doc.Name)...)
 	return append(ops, annotationRemoveOp(s.st, s.globalKey()))
 }
 
@@ -251,17 +259,112 @@ func (s *Service) ClearExposed() error {
 	return s.setExposed(false)
 }
 
+// setExposed sets the exposed flag of the service. Any ports
+// previously excluded with UnexposePorts are exposed again.
 func (s *Service) setExposed(exposed bool) (err error) {
 	ops := []txn.Op{{
 		C:      servicesC,
 		Id:     s.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: bson.D{
+			{"$set", bson.D{{"exposed", exposed}}},
+			{"$unset", bson.D{{"unexposedports", nil}}},
+		},
 	}}
 	if err := s.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set exposed flag for service %q to %v: %v", s, exposed, onAbort(err, errNotAlive))
 	}
 	s.doc.Exposed = exposed
+	s.doc.UnexposedPorts = nil
+	return nil
+}
+
+// UnexposedPorts returns the port ranges of an exposed service that
+// are kept closed to the outside world. See UnexposePorts.
+func (s *Service) UnexposedPorts() []network.PortRange {
+	ports := make([]network.PortRange, len(s.doc.UnexposedPorts))
+	copy(ports, s.doc.UnexposedPorts)
+	return ports
+}
+
+// UnexposePorts keeps the given port ranges of an exposed service
+// closed to the outside world, while its other ports remain exposed.
+// Any opened port range that overlaps one of the given ranges is
+// closed. The ranges are added to those already unexposed; they are
+// all exposed again by SetExposed and forgotten by ClearExposed.
+func (s *Service) UnexposePorts(ports []network.PortRange) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot unexpose ports of service %q", s)
+	if len(ports) == 0 {
+		return errors.New("no ports specified")
+	}
+	unexposed := make([]network.PortRange, len(ports))
+	for i, portRange := range ports {
+		if err := portRange.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		portRange.Protocol = strings.ToLower(portRange.Protocol)
+		unexposed[i] = portRange
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		if !s.doc.Exposed {
+			return nil, errors.New("service is not exposed")
+		}
+		return []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: bson.D{{"life", Alive}, {"exposed", true}},
+			Update: bson.D{{"$addToSet", bson.D{{"unexposedports", bson.D{{"$each", unexposed}}}}}},
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return err
+	}
+	for _, portRange := range unexposed {
+		if !containsPortRange(s.doc.UnexposedPorts, portRange) {
+			s.doc.UnexposedPorts = append(s.doc.UnexposedPorts, portRange)
+		}
+	}
+	return nil
+}
+
+// containsPortRange reports whether ports holds the given port range.
+func containsPortRange(ports []network.PortRange, portRange network.PortRange) bool {
+	for _, p := range ports {
+		if p == portRange {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCharmPinned returns whether the service is pinned to its current
+// charm, in which case newer revisions of the charm are not reported
+// and the charm is only changed when forced. See SetCharmPinned.
+func (s *Service) IsCharmPinned() bool {
+	return s.doc.CharmPinned
+}
+
+// SetCharmPinned pins the service to its current charm, or removes the
+// pin. See IsCharmPinned.
+func (s *Service) SetCharmPinned(pinned bool) error {
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"charmpinned", pinned}}}},
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return fmt.Errorf("cannot set charm pin for service %q to %v: %v", s, pinned, onAbort(err, errNotAlive))
+	}
+	s.doc.CharmPinned = pinned
 	return nil
 }
 
@@ -364,13 +467,42 @@ func (s *Service) checkRelationsOps(ch *Charm, relations []*Relation) ([]txn.Op,
 		}
 		asserts = append(asserts, txn.Op{
 			C:      relationsC,
-			Id:     rel.doc.Key,
+			Id:     rel.doc.DocID,
 			Assert: txn.DocExists,
 		})
 	}
 	return asserts, nil
 }
 
+// checkSwitchConfig returns an error if ch is a different charm to the
+// service's current one, rather than another revision of it, and any
+// config option declared by both charms has changed type. Such settings
+// could not be carried over meaningfully.
+func (s *Service) checkSwitchConfig(ch *Charm) error {
+	if *ch.URL().WithRevision(-1) == *s.doc.CharmURL.WithRevision(-1) {
+		return nil
+	}
+	oldCh, _, err := s.Charm()
+	if err != nil {
+		return err
+	}
+	oldOptions := oldCh.Config().Options
+	newOptions := ch.Config().Options
+	names := make([]string, 0, len(newOptions))
+	for name := range newOptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		oldOption, ok := oldOptions[name]
+		if ok && oldOption.Type != newOptions[name].Type {
+			return fmt.Errorf("cannot switch service %q to charm %q: config option %q would change type from %q to %q",
+				s, ch, name, oldOption.Type, newOptions[name].Type)
+		}
+	}
+	return nil
+}
+
 // changeCharmOps returns the operations necessary to set a service's
 // charm URL to a new value.
 func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
@@ -387,7 +519,7 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 	// Create or replace service settings.
 	var settingsOp txn.Op
 	newKey := serviceSettingsKey(s.doc.Name, ch.URL())
-	if count, err := settings.FindId(newKey).Count(); err != nil {
+	if count, err := settings.FindId(s.st.docID(newKey)).Count(); err != nil {
 		return nil, err
 	} else if count == 0 {
 		// No settings for this key yet, create it.
@@ -412,8 +544,9 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 		return nil, err
 	}
 
-	// Build the transaction.
-	differentCharm := bson.D{{"charmurl", bson.D{{"$ne", ch.URL()}}}}
+	// Build the transaction. The service's charm must still be the
+	// one the new settings and config types were checked against.
+	sameOldCharm := bson.D{{"charmurl", s.doc.CharmURL}}
 	ops := []txn.Op{
 		// Old settings shouldn't change
 		oldSettings.assertUnchangedOp(),
@@ -425,7 +558,7 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 		{
 			C:      servicesC,
 			Id:     s.doc.DocID,
-			Assert: append(isAliveDoc, differentCharm...),
+			Assert: append(isAliveDoc, sameOldCharm...),
 			Update: bson.D{{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}}},
 		},
 	}
@@ -469,7 +602,6 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 func (s *Service) SetCharm(ch *Charm, force bool) (err error) {
 	services, closer := s.st.getCollection(servicesC)
 	defer closer()
-	settings := services.Database.C(settingsC)
 
 	if ch.Meta().Subordinate != s.doc.Subordinate {
 		return fmt.Errorf("cannot change a service's subordinacy")
@@ -480,13 +612,20 @@ func (s *Service) SetCharm(ch *Charm, force bool) (err error) {
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if attempt > 0 {
 			// If the service is not alive, fail out immediately; otherwise,
-			// data changed underneath us, so retry.
-			if alive, err := isAliveWithSession(settings, s.doc.Name); err != nil {
+			// data changed underneath us, so refresh and retry.
+			if err := s.Refresh(); errors.IsNotFound(err) {
+				return nil, fmt.Errorf("service %q is not alive", s.doc.Name)
+			} else if err != nil {
 				return nil, err
-			} else if !alive {
+			} else if s.doc.Life != Alive {
 				return nil, fmt.Errorf("service %q is not alive", s.doc.Name)
 			}
 		}
+		// Check the config against the service's current charm, which
+		// another client may have changed since the last attempt.
+		if err := s.checkSwitchConfig(ch); err != nil {
+			return nil, err
+		}
 		// Make sure the service doesn't have this charm already.
 		sel := bson.D{{"_id", s.doc.DocID}, {"charmurl", ch.URL()}}
 		var ops []txn.Op
@@ -584,6 +723,7 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 	}
 	sdoc := statusDoc{
 		Status: StatusPending,
+		Since:  nowToTheSecond(),
 	}
 	msdoc := meterStatusDoc{
 		Code: MeterNotSet,
@@ -682,8 +822,11 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	},
 		removeConstraintsOp(s.st, u.globalKey()),
 		removeStatusOp(s.st, u.globalKey()),
+		removeStatusHistoryOp(s.st, u.globalKey()),
 		removeMeterStatusOp(s.st, u.globalKey()),
 		annotationRemoveOp(s.st, u.globalKey()),
+		removeDebugHooksTranscriptOp(s.st, u.globalKey()),
+		removeUnitChaosOp(s.st, u.globalKey()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	)
 	ops = append(ops, portsOps...)
@@ -731,7 +874,8 @@ func allUnits(st *State, service string) (units []*Unit, err error) {
 	defer closer()
 
 	docs := []unitDoc{}
-	err = unitsCollection.Find(bson.D{{"service", service}}).All(&docs)
+	sel := bson.D{{"service", service}, {"env-uuid", st.EnvironTag().Id()}}
+	err = unitsCollection.Find(sel).All(&docs)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get all units from service %q: %v", service, err)
 	}
@@ -752,7 +896,8 @@ func serviceRelations(st *State, name string) (relations []*Relation, err error)
 	defer closer()
 
 	docs := []relationDoc{}
-	err = relationsCollection.Find(bson.D{{"endpoints.servicename", name}}).All(&docs)
+	query := bson.D{{"env-uuid", st.EnvironTag().Id()}, {"endpoints.servicename", name}}
+	err = relationsCollection.Find(query).All(&docs)
 	if err != nil {
 		return nil, err
 	}
@@ -812,6 +957,17 @@ func (s *Service) Constraints() (constraints.Value, error) {
 	return readConstraints(s.st, s.globalKey())
 }
 
+// EffectiveConstraints returns the constraints that apply to new units
+// of the service: the service constraints, with any values they leave
+// unset taken from the environment constraints.
+func (s *Service) EffectiveConstraints() (constraints.Value, error) {
+	cons, err := s.Constraints()
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	return s.st.resolveConstraints(cons)
+}
+
 // SetConstraints replaces the current service constraints.
 func (s *Service) SetConstraints(cons constraints.Value) (err error) {
 	unsupported, err := s.st.validateConstraints(cons)
@@ -855,7 +1011,8 @@ func settingsIncRefOp(st *State, serviceName string, curl *charm.URL, canCreate
 	defer closer()
 
 	key := serviceSettingsKey(serviceName, curl)
-	if count, err := settingsrefs.FindId(key).Count(); err != nil {
+	docID := st.docID(key)
+	if count, err := settingsrefs.FindId(docID).Count(); err != nil {
 		return txn.Op{}, err
 	} else if count == 0 {
 		if !canCreate {
@@ -863,14 +1020,17 @@ func settingsIncRefOp(st *State, serviceName string, curl *charm.URL, canCreate
 		}
 		return txn.Op{
 			C:      settingsrefsC,
-			Id:     key,
+			Id:     docID,
 			Assert: txn.DocMissing,
-			Insert: settingsRefsDoc{1},
+			Insert: settingsRefsDoc{
+				RefCount: 1,
+				EnvUUID:  st.EnvironTag().Id(),
+			},
 		}, nil
 	}
 	return txn.Op{
 		C:      settingsrefsC,
-		Id:     key,
+		Id:     docID,
 		Assert: txn.DocExists,
 		Update: bson.D{{"$inc", bson.D{{"refcount", 1}}}},
 	}, nil
@@ -885,8 +1045,9 @@ func settingsDecRefOps(st *State, serviceName string, curl *charm.URL) ([]txn.Op
 	defer closer()
 
 	key := serviceSettingsKey(serviceName, curl)
+	docID := st.docID(key)
 	var doc settingsRefsDoc
-	if err := settingsrefs.FindId(key).One(&doc); err == mgo.ErrNotFound {
+	if err := settingsrefs.FindId(docID).One(&doc); err == mgo.ErrNotFound {
 		return nil, errors.NotFoundf("service %q settings for charm %q", serviceName, curl)
 	} else if err != nil {
 		return nil, err
@@ -894,18 +1055,18 @@ func settingsDecRefOps(st *State, serviceName string, curl *charm.URL) ([]txn.Op
 	if doc.RefCount == 1 {
 		return []txn.Op{{
 			C:      settingsrefsC,
-			Id:     key,
+			Id:     docID,
 			Assert: bson.D{{"refcount", 1}},
 			Remove: true,
 		}, {
 			C:      settingsC,
-			Id:     key,
+			Id:     docID,
 			Remove: true,
 		}}, nil
 	}
 	return []txn.Op{{
 		C:      settingsrefsC,
-		Id:     key,
+		Id:     docID,
 		Assert: bson.D{{"refcount", bson.D{{"$gt", 1}}}},
 		Update: bson.D{{"$inc", bson.D{{"refcount", -1}}}},
 	}}, nil
@@ -928,4 +1089,5 @@ func settingsDecRefOps(st *State, serviceName string, curl *charm.URL) ([]txn.Op
 // always the same as the settingsDoc's id.
 type settingsRefsDoc struct {
 	RefCount int
+	EnvUUID  string `bson:"env-uuid"`
 }
@@ -16,6 +16,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/testing"
 )
@@ -319,6 +320,53 @@ func (s *ServiceSuite) TestSetCharmConfig(c *gc.C) {
 	}
 }
 
+func (s *ServiceSuite) TestSetCharmSwitchConfigConcurrentUpgrade(c *gc.C) {
+	origCh := s.AddConfigCharm(c, "wordpress", stringConfig, 1)
+	svc := s.AddTestingService(c, "wordpress", origCh)
+	stringCh := s.AddConfigCharm(c, "mysql", stringConfig, 1)
+
+	// Another client upgrades the service to a revision of its charm
+	// whose option has another type, so the switch must be checked
+	// against that revision, not the one svc last saw.
+	floatCh := s.AddConfigCharm(c, "wordpress", floatConfig, 2)
+	defer state.SetBeforeHooks(c, s.State, func() {
+		other, err := s.State.Service("wordpress")
+		c.Assert(err, gc.IsNil)
+		c.Assert(other.SetCharm(floatCh, false), gc.IsNil)
+	}).Check()
+
+	err := svc.SetCharm(stringCh, false)
+	c.Assert(err, gc.ErrorMatches, `cannot switch service "wordpress" to charm "local:quantal/quantal-mysql-1": config option "key" would change type from "float" to "string"`)
+	sch, _, err := svc.Charm()
+	c.Assert(err, gc.IsNil)
+	c.Assert(sch.URL(), gc.DeepEquals, floatCh.URL())
+}
+
+func (s *ServiceSuite) TestSetCharmSwitchConfig(c *gc.C) {
+	origCh := s.AddConfigCharm(c, "wordpress", stringConfig, 1)
+	svc := s.AddTestingService(c, "wordpress", origCh)
+	err := svc.UpdateConfigSettings(charm.Settings{"key": "value"})
+	c.Assert(err, gc.IsNil)
+
+	// Switching to a different charm whose shared option has another
+	// type is refused, and the settings are left alone.
+	floatCh := s.AddConfigCharm(c, "mysql", floatConfig, 1)
+	err = svc.SetCharm(floatCh, false)
+	c.Assert(err, gc.ErrorMatches, `cannot switch service "wordpress" to charm "local:quantal/quantal-mysql-1": config option "key" would change type from "string" to "float"`)
+	sch, _, err := svc.Charm()
+	c.Assert(err, gc.IsNil)
+	c.Assert(sch.URL(), gc.DeepEquals, origCh.URL())
+
+	// Switching to a charm with compatible options carries the
+	// settings over.
+	stringCh := s.AddConfigCharm(c, "mysql", newStringConfig, 2)
+	err = svc.SetCharm(stringCh, false)
+	c.Assert(err, gc.IsNil)
+	settings, err := svc.ConfigSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"key": "value"})
+}
+
 var serviceUpdateConfigSettingsTests = []struct {
 	about   string
 	initial charm.Settings
@@ -767,6 +815,79 @@ func (s *ServiceSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ServiceSuite) TestServiceUnexposePorts(c *gc.C) {
+	c.Assert(s.mysql.UnexposedPorts(), gc.HasLen, 0)
+	ports := []network.PortRange{{8080, 8080, "TCP"}}
+
+	// Ports can only be unexposed on an exposed service.
+	err := s.mysql.UnexposePorts(ports)
+	c.Assert(err, gc.ErrorMatches, `cannot unexpose ports of service "mysql": service is not exposed`)
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.UnexposePorts(nil)
+	c.Assert(err, gc.ErrorMatches, `cannot unexpose ports of service "mysql": no ports specified`)
+	err = s.mysql.UnexposePorts([]network.PortRange{{90, 80, "tcp"}})
+	c.Assert(err, gc.ErrorMatches, `cannot unexpose ports of service "mysql": invalid port range 90-80/tcp`)
+
+	// Unexposed ports accumulate, ignoring duplicates.
+	err = s.mysql.UnexposePorts(ports)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.UnexposePorts([]network.PortRange{{8080, 8080, "tcp"}, {53, 53, "udp"}})
+	c.Assert(err, jc.ErrorIsNil)
+	expected := []network.PortRange{{8080, 8080, "tcp"}, {53, 53, "udp"}}
+	c.Assert(s.mysql.UnexposedPorts(), jc.DeepEquals, expected)
+	c.Assert(ports[0].Protocol, gc.Equals, "TCP")
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.UnexposedPorts(), jc.DeepEquals, expected)
+
+	// Exposing the service again exposes all its ports.
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.UnexposedPorts(), gc.HasLen, 0)
+	err = s.mysql.UnexposePorts(ports)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.UnexposedPorts(), gc.HasLen, 0)
+
+	// A dying service cannot have its ports unexposed.
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.UnexposePorts(ports)
+	c.Assert(err, gc.ErrorMatches, notAliveErr)
+}
+
+func (s *ServiceSuite) TestServiceCharmPinned(c *gc.C) {
+	c.Assert(s.mysql.IsCharmPinned(), jc.IsFalse)
+
+	err := s.mysql.SetCharmPinned(true)
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mysql.IsCharmPinned(), jc.IsTrue)
+
+	// The pin is persisted.
+	svc, err := s.State.Service("mysql")
+	c.Assert(err, gc.IsNil)
+	c.Assert(svc.IsCharmPinned(), jc.IsTrue)
+
+	err = s.mysql.SetCharmPinned(false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mysql.IsCharmPinned(), jc.IsFalse)
+
+	err = s.mysql.Destroy()
+	c.Assert(err, gc.IsNil)
+	err = s.mysql.SetCharmPinned(true)
+	c.Assert(err, gc.ErrorMatches, notAliveErr)
+}
+
 func (s *ServiceSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit()
@@ -1165,6 +1286,16 @@ func (s *ServiceSuite) TestConstraints(c *gc.C) {
 	c.Assert(&cons6, jc.Satisfies, constraints.IsEmpty)
 }
 
+func (s *ServiceSuite) TestEffectiveConstraints(c *gc.C) {
+	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=4G cpu-cores=2"))
+	c.Assert(err, gc.IsNil)
+	err = s.mysql.SetConstraints(constraints.MustParse("mem=8G"))
+	c.Assert(err, gc.IsNil)
+	cons, err := s.mysql.EffectiveConstraints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=8G cpu-cores=2"))
+}
+
 func (s *ServiceSuite) TestSetInvalidConstraints(c *gc.C) {
 	cons := constraints.MustParse("mem=4G instance-type=foo")
 	err := s.mysql.SetConstraints(cons)
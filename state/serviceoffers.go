@@ -0,0 +1,164 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ServiceOffer holds the details of a service's endpoints offered for
+// use by services in other environments.
+type ServiceOffer struct {
+	// URL identifies the offer to consuming environments,
+	// e.g. "local:/u/admin/monitoring".
+	URL string
+
+	// ServiceName is the name of the offered service.
+	ServiceName string
+
+	// Endpoints holds the names of the service endpoints that
+	// may be related to from other environments.
+	Endpoints []string
+
+	// Description describes the offer to potential consumers.
+	Description string
+}
+
+// serviceOfferDoc represents a ServiceOffer in the database.
+type serviceOfferDoc struct {
+	DocID       string   `bson:"_id"`
+	EnvUUID     string   `bson:"env-uuid"`
+	URL         string   `bson:"url"`
+	ServiceName string   `bson:"service"`
+	Endpoints   []string `bson:"endpoints"`
+	Description string   `bson:"description"`
+}
+
+func (doc *serviceOfferDoc) offer() ServiceOffer {
+	return ServiceOffer{
+		URL:         doc.URL,
+		ServiceName: doc.ServiceName,
+		Endpoints:   doc.Endpoints,
+		Description: doc.Description,
+	}
+}
+
+var validOfferURL = regexp.MustCompile(`^[a-z]+:/[a-zA-Z0-9._+-]+(/[a-zA-Z0-9._+-]+)*$`)
+
+// IsValidOfferURL returns whether url is a valid service offer URL.
+func IsValidOfferURL(url string) bool {
+	return validOfferURL.MatchString(url)
+}
+
+// AddServiceOffer offers the given endpoints of a service for use by
+// other environments. The service must be alive, and each endpoint
+// must be one of the service's relation endpoints.
+func (st *State) AddServiceOffer(offer ServiceOffer) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add service offer %q", offer.URL)
+	if !IsValidOfferURL(offer.URL) {
+		return errors.NotValidf("offer URL")
+	}
+	if len(offer.Endpoints) == 0 {
+		return errors.New("no endpoints specified")
+	}
+	service, err := st.Service(offer.ServiceName)
+	if err != nil {
+		return err
+	}
+	if !service.IsPrincipal() {
+		return errors.Errorf("service %q is a subordinate", offer.ServiceName)
+	}
+	for _, name := range offer.Endpoints {
+		if _, err := service.Endpoint(name); err != nil {
+			return err
+		}
+	}
+	doc := serviceOfferDoc{
+		DocID:       st.docID(offer.URL),
+		EnvUUID:     st.EnvironTag().Id(),
+		URL:         offer.URL,
+		ServiceName: offer.ServiceName,
+		Endpoints:   offer.Endpoints,
+		Description: offer.Description,
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     service.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      serviceOffersC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if _, err := st.ServiceOffer(offer.URL); err == nil {
+			return errors.AlreadyExistsf("service offer")
+		}
+		return errors.Errorf("service %q is not alive", offer.ServiceName)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ServiceOffer returns the service offer with the given URL.
+func (st *State) ServiceOffer(url string) (*ServiceOffer, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var doc serviceOfferDoc
+	if err := offers.FindId(st.docID(url)).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("service offer %q", url)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get service offer %q", url)
+	}
+	offer := doc.offer()
+	return &offer, nil
+}
+
+// ServiceOffers returns all the service offers made by the environment,
+// ordered by URL.
+func (st *State) ServiceOffers() ([]ServiceOffer, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var docs []serviceOfferDoc
+	if err := offers.Find(bson.D{{"env-uuid", st.EnvironTag().Id()}}).Sort("url").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get service offers")
+	}
+	result := make([]ServiceOffer, len(docs))
+	for i := range docs {
+		result[i] = docs[i].offer()
+	}
+	return result, nil
+}
+
+// RemoveServiceOffer withdraws the service offer with the given URL,
+// along with the records of any remote relations made to it.
+func (st *State) RemoveServiceOffer(url string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove service offer %q", url)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.ServiceOffer(url); err != nil {
+			return nil, err
+		}
+		ops := []txn.Op{{
+			C:      serviceOffersC,
+			Id:     st.docID(url),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}
+		relOps, err := removeRemoteRelationsOps(st, url)
+		if err != nil {
+			return nil, err
+		}
+		return append(ops, relOps...), nil
+	}
+	return st.run(buildTxn)
+}
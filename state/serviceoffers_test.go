@@ -0,0 +1,204 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ServiceOfferSuite struct {
+	ConnSuite
+	offer state.ServiceOffer
+}
+
+var _ = gc.Suite(&ServiceOfferSuite{})
+
+func (s *ServiceOfferSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.offer = state.ServiceOffer{
+		URL:         "local:/u/admin/blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+		Description: "a shared blog",
+	}
+}
+
+func (s *ServiceOfferSuite) TestAddServiceOffer(c *gc.C) {
+	err := s.State.AddServiceOffer(s.offer)
+	c.Assert(err, gc.IsNil)
+
+	offer, err := s.State.ServiceOffer("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	c.Assert(*offer, jc.DeepEquals, s.offer)
+	offers, err := s.State.ServiceOffers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(offers, jc.DeepEquals, []state.ServiceOffer{s.offer})
+
+	err = s.State.AddServiceOffer(s.offer)
+	c.Assert(err, gc.ErrorMatches, `cannot add service offer "local:/u/admin/blog": service offer already exists`)
+}
+
+func (s *ServiceOfferSuite) TestAddServiceOfferInvalid(c *gc.C) {
+	for i, t := range []struct {
+		about  string
+		modify func(*state.ServiceOffer)
+		err    string
+	}{{
+		about:  "bad URL",
+		modify: func(o *state.ServiceOffer) { o.URL = "blog" },
+		err:    `cannot add service offer "blog": offer URL not valid`,
+	}, {
+		about:  "no endpoints",
+		modify: func(o *state.ServiceOffer) { o.Endpoints = nil },
+		err:    `cannot add service offer "local:/u/admin/blog": no endpoints specified`,
+	}, {
+		about:  "unknown service",
+		modify: func(o *state.ServiceOffer) { o.ServiceName = "mysql" },
+		err:    `cannot add service offer "local:/u/admin/blog": service "mysql" not found`,
+	}, {
+		about:  "unknown endpoint",
+		modify: func(o *state.ServiceOffer) { o.Endpoints = []string{"url", "foo"} },
+		err:    `cannot add service offer "local:/u/admin/blog": service "wordpress" has no "foo" relation`,
+	}} {
+		c.Logf("test %d: %s", i, t.about)
+		offer := s.offer
+		t.modify(&offer)
+		err := s.State.AddServiceOffer(offer)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+	offers, err := s.State.ServiceOffers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(offers, gc.HasLen, 0)
+}
+
+func (s *ServiceOfferSuite) TestRemoteRelations(c *gc.C) {
+	rel := state.RemoteRelation{
+		OfferURL:         "local:/u/admin/blog",
+		ConsumerEnvUUID:  "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService:  "haproxy",
+		IngressAddresses: []string{"10.0.0.1"},
+	}
+	err := s.State.AddRemoteRelation(rel)
+	c.Assert(err, gc.ErrorMatches, `cannot add remote relation to "local:/u/admin/blog": service offer "local:/u/admin/blog" not found`)
+
+	err = s.State.AddServiceOffer(s.offer)
+	c.Assert(err, gc.IsNil)
+	err = s.State.AddRemoteRelation(rel)
+	c.Assert(err, gc.IsNil)
+	rels, err := s.State.RemoteRelations("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	c.Assert(rels, jc.DeepEquals, []state.RemoteRelation{rel})
+
+	// Registering the same consumer again updates its addresses.
+	rel.IngressAddresses = []string{"10.0.0.1", "10.0.0.2"}
+	err = s.State.AddRemoteRelation(rel)
+	c.Assert(err, gc.IsNil)
+	rels, err = s.State.RemoteRelations("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	c.Assert(rels, jc.DeepEquals, []state.RemoteRelation{rel})
+
+	// Consuming an offer from its own environment is not allowed.
+	rel.ConsumerEnvUUID = s.State.EnvironTag().Id()
+	err = s.State.AddRemoteRelation(rel)
+	c.Assert(err, gc.ErrorMatches, `cannot add remote relation to "local:/u/admin/blog": offer cannot be consumed by its own environment`)
+
+	// Removing the offer removes its remote relations.
+	err = s.State.RemoveServiceOffer("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.ServiceOffer("local:/u/admin/blog")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	rels, err = s.State.RemoteRelations("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	c.Assert(rels, gc.HasLen, 0)
+
+	err = s.State.RemoveServiceOffer("local:/u/admin/blog")
+	c.Assert(err, gc.ErrorMatches, `cannot remove service offer "local:/u/admin/blog": service offer "local:/u/admin/blog" not found`)
+}
+
+func (s *ServiceOfferSuite) TestRemoteRelationUnits(c *gc.C) {
+	err := s.State.AddServiceOffer(s.offer)
+	c.Assert(err, gc.IsNil)
+	rel := state.RemoteRelation{
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "haproxy",
+	}
+	unit := state.RemoteRelationUnit{
+		Name:     "haproxy/0",
+		Settings: map[string]string{"private-address": "10.0.0.1", "foo.bar": "baz"},
+	}
+	err = s.State.EnterRemoteScope(rel, unit)
+	c.Assert(err, gc.ErrorMatches, `cannot enter scope of remote relation to "local:/u/admin/blog" for unit "haproxy/0": remote relation not found`)
+
+	err = s.State.AddRemoteRelation(rel)
+	c.Assert(err, gc.IsNil)
+	err = s.State.EnterRemoteScope(rel, unit)
+	c.Assert(err, gc.IsNil)
+	units, err := s.State.RemoteRelationUnits(rel)
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, jc.DeepEquals, []state.RemoteRelationUnit{unit})
+
+	// Entering scope again replaces the unit's settings.
+	unit.Settings = map[string]string{"private-address": "10.0.0.2"}
+	err = s.State.EnterRemoteScope(rel, unit)
+	c.Assert(err, gc.IsNil)
+	other := state.RemoteRelationUnit{Name: "haproxy/1", Settings: map[string]string{}}
+	err = s.State.EnterRemoteScope(rel, other)
+	c.Assert(err, gc.IsNil)
+	units, err = s.State.RemoteRelationUnits(rel)
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, jc.DeepEquals, []state.RemoteRelationUnit{unit, other})
+
+	// Only units of the consuming service may enter scope.
+	err = s.State.EnterRemoteScope(rel, state.RemoteRelationUnit{Name: "nagios/0"})
+	c.Assert(err, gc.ErrorMatches, `cannot enter scope of remote relation to "local:/u/admin/blog" for unit "nagios/0": unit does not belong to service "haproxy"`)
+	err = s.State.EnterRemoteScope(rel, state.RemoteRelationUnit{Name: "haproxy"})
+	c.Assert(err, gc.ErrorMatches, `cannot enter scope of remote relation to "local:/u/admin/blog" for unit "haproxy": unit name not valid`)
+
+	err = s.State.LeaveRemoteScope(rel, "haproxy/0")
+	c.Assert(err, gc.IsNil)
+	err = s.State.LeaveRemoteScope(rel, "haproxy/0")
+	c.Assert(err, gc.IsNil)
+	units, err = s.State.RemoteRelationUnits(rel)
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, jc.DeepEquals, []state.RemoteRelationUnit{other})
+
+	// Removing the remote relation takes the remaining units out of
+	// scope.
+	err = s.State.RemoveRemoteRelation(rel)
+	c.Assert(err, gc.IsNil)
+	units, err = s.State.RemoteRelationUnits(rel)
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.HasLen, 0)
+	rels, err := s.State.RemoteRelations("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	c.Assert(rels, gc.HasLen, 0)
+	err = s.State.RemoveRemoteRelation(rel)
+	c.Assert(err, gc.ErrorMatches, `cannot remove remote relation to "local:/u/admin/blog": remote relation not found`)
+}
+
+func (s *ServiceOfferSuite) TestRemoveServiceOfferRemovesRemoteRelationUnits(c *gc.C) {
+	err := s.State.AddServiceOffer(s.offer)
+	c.Assert(err, gc.IsNil)
+	rel := state.RemoteRelation{
+		OfferURL:        "local:/u/admin/blog",
+		ConsumerEnvUUID: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		ConsumerService: "haproxy",
+	}
+	err = s.State.AddRemoteRelation(rel)
+	c.Assert(err, gc.IsNil)
+	err = s.State.EnterRemoteScope(rel, state.RemoteRelationUnit{Name: "haproxy/0"})
+	c.Assert(err, gc.IsNil)
+
+	err = s.State.RemoveServiceOffer("local:/u/admin/blog")
+	c.Assert(err, gc.IsNil)
+	units, err := s.State.RemoteRelationUnits(rel)
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
@@ -165,7 +165,7 @@ func (c *Settings) Write() ([]ItemChange, error) {
 	sort.Sort(itemChangeSlice(changes))
 	ops := []txn.Op{{
 		C:      settingsC,
-		Id:     c.key,
+		Id:     c.st.docID(c.key),
 		Assert: txn.DocExists,
 		Update: setUnsetUpdate(updates, deletions),
 	}}
@@ -188,10 +188,11 @@ func newSettings(st *State, key string) *Settings {
 	}
 }
 
-// cleanSettingsMap cleans the map of version and _id fields and also unescapes
-// keys coming out of MongoDB.
+// cleanSettingsMap cleans the map of version, _id and env-uuid fields and
+// also unescapes keys coming out of MongoDB.
 func cleanSettingsMap(in map[string]interface{}) {
 	delete(in, "_id")
+	delete(in, "env-uuid")
 	delete(in, "txn-revno")
 	delete(in, "txn-queue")
 	replaceKeys(in, unescapeReplacer.Replace)
@@ -246,7 +247,7 @@ func readSettingsDoc(st *State, key string) (map[string]interface{}, int64, erro
 	defer closer()
 
 	config := map[string]interface{}{}
-	err := settings.FindId(key).One(config)
+	err := settings.FindId(st.docID(key)).One(config)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -268,9 +269,10 @@ var errSettingsExist = fmt.Errorf("cannot overwrite existing settings")
 
 func createSettingsOp(st *State, key string, values map[string]interface{}) txn.Op {
 	newValues := copyMap(values, escapeReplacer.Replace)
+	newValues["env-uuid"] = st.EnvironTag().Id()
 	return txn.Op{
 		C:      settingsC,
-		Id:     key,
+		Id:     st.docID(key),
 		Assert: txn.DocMissing,
 		Insert: newValues,
 	}
@@ -296,7 +298,7 @@ func removeSettings(st *State, key string) error {
 	settings, closer := st.getCollection(settingsC)
 	defer closer()
 
-	err := settings.RemoveId(key)
+	err := settings.RemoveId(st.docID(key))
 	if err == mgo.ErrNotFound {
 		return errors.NotFoundf("settings")
 	}
@@ -334,7 +336,7 @@ func replaceSettingsOp(st *State, key string, values map[string]interface{}) (tx
 func (s *Settings) assertUnchangedOp() txn.Op {
 	return txn.Op{
 		C:      settingsC,
-		Id:     s.key,
+		Id:     s.st.docID(s.key),
 		Assert: bson.D{{"txn-revno", s.txnRevno}},
 	}
 }
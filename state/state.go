@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -67,6 +68,60 @@ const (
 	metricsC           = "metrics"
 	upgradeInfoC       = "upgradeInfo"
 	rebootC            = "reboot"
+	sequenceC          = "sequence"
+
+	// agentVersionPinsC is the collection used to store the agent
+	// versions that machines and services are held at.
+	agentVersionPinsC = "agentversionpins"
+
+	// unitGroupsC is the collection used to store named groups of
+	// units, defined by unit name patterns.
+	unitGroupsC = "unitgroups"
+
+	// debugHooksTranscriptsC is the collection used to store the
+	// output of units' debug-hooks sessions.
+	debugHooksTranscriptsC = "debughookstranscripts"
+
+	// serviceOffersC is the collection used to store the service
+	// endpoints offered for use by other environments.
+	serviceOffersC = "serviceoffers"
+
+	// remoteRelationsC is the collection used to record the use of
+	// service offers by services in other environments.
+	remoteRelationsC = "remoterelations"
+
+	// remoteRelationUnitsC is the collection used to record the
+	// units of consuming services that are in the scope of remote
+	// relations.
+	remoteRelationUnitsC = "remoterelationunits"
+
+	// leadershipC is the collection used to store the leadership
+	// leases of services.
+	leadershipC = "leadership"
+
+	// machineUtilizationC is the collection used to store the
+	// resource usage reported by machine agents.
+	machineUtilizationC = "machineutilization"
+
+	// unitChaosC is the collection used to store the faults that unit
+	// agents are instructed to inject for chaos testing.
+	unitChaosC = "unitchaos"
+
+	// apiTokensC is the collection used to store the tokens that
+	// users mint so that automation can log in without a password.
+	apiTokensC = "apitokens"
+
+	// bundleDeploymentsC is the collection used to store the bundle
+	// deployments run by the bundle deployer on the state server.
+	bundleDeploymentsC = "bundledeployments"
+
+	// statusHistoryC is the collection used to store the recent
+	// status transitions of units.
+	statusHistoryC = "statushistory"
+
+	// storageInstancesC is the collection used to store the block
+	// devices and filesystems owned by units.
+	storageInstancesC = "storageinstances"
 
 	// meterStatusC is the collection used to store meter status information.
 	meterStatusC = "meterStatus"
@@ -102,6 +157,10 @@ type State struct {
 	mu         sync.Mutex
 	allManager *multiwatcher.StoreManager
 	environTag names.EnvironTag
+
+	// agentPingers holds the presence pingers shared by the
+	// agents on each machine.
+	agentPingers agentPingers
 }
 
 // StateServingInfo holds information needed by a state server.
@@ -193,14 +252,24 @@ func (st *State) txnRunner(session *mgo.Session) jujutxn.Runner {
 func (st *State) runTransaction(ops []txn.Op) error {
 	session := st.db.Session.Copy()
 	defer session.Close()
-	return st.txnRunner(session).RunTransaction(ops)
+	start := time.Now()
+	err := st.txnRunner(session).RunTransaction(ops)
+	txnMetrics.record(1, time.Since(start), err)
+	return err
 }
 
 // run is a convenience method delegating to transactionRunner.
 func (st *State) run(transactions jujutxn.TransactionSource) error {
 	session := st.db.Session.Copy()
 	defer session.Close()
-	return st.txnRunner(session).Run(transactions)
+	start := time.Now()
+	attempts := 0
+	err := st.txnRunner(session).Run(func(attempt int) ([]txn.Op, error) {
+		attempts++
+		return transactions(attempt)
+	})
+	txnMetrics.record(attempts, time.Since(start), err)
+	return err
 }
 
 // ResumeTransactions resumes all pending transactions.
@@ -219,6 +288,17 @@ func (st *State) Watch() *multiwatcher.Watcher {
 	return multiwatcher.NewWatcher(st.allManager)
 }
 
+// WatchFiltered is like Watch, but the returned watcher only reports
+// the deltas selected by the given filter.
+func (st *State) WatchFiltered(filter multiwatcher.Filter) *multiwatcher.Watcher {
+	st.mu.Lock()
+	if st.allManager == nil {
+		st.allManager = multiwatcher.NewStoreManager(newAllWatcherStateBacking(st))
+	}
+	st.mu.Unlock()
+	return multiwatcher.NewFilteredWatcher(st.allManager, filter)
+}
+
 func (st *State) EnvironConfig() (*config.Config, error) {
 	settings, err := readSettings(st, environGlobalKey)
 	if err != nil {
@@ -353,7 +433,7 @@ func (st *State) SetEnvironAgentVersion(newVersion version.Number) (err error) {
 				Assert: txn.DocMissing,
 			}, {
 				C:      settingsC,
-				Id:     environGlobalKey,
+				Id:     st.docID(environGlobalKey),
 				Assert: bson.D{{"txn-revno", settings.txnRevno}},
 				Update: bson.D{{"$set", bson.D{{"agent-version", newVersion.String()}}}},
 			},
@@ -474,7 +554,7 @@ func (st *State) AllMachines() (machines []*Machine, err error) {
 	defer closer()
 
 	mdocs := machineDocSlice{}
-	err = machinesCollection.Find(nil).All(&mdocs)
+	err = machinesCollection.Find(bson.D{{"env-uuid", st.EnvironTag().Id()}}).All(&mdocs)
 	if err != nil {
 		return nil, errors.Annotatef(err, "cannot get all machines")
 	}
@@ -642,10 +722,12 @@ func (st *State) parseTag(tag names.Tag) (string, interface{}, error) {
 		id = tag.Name()
 	case names.RelationTag:
 		coll = relationsC
+		id = st.docID(id)
 	case names.EnvironTag:
 		coll = environmentsC
 	case names.NetworkTag:
 		coll = networksC
+		id = st.docID(id)
 	case names.ActionTag:
 		coll = actionsC
 		id = actionIdFromTag(tag)
@@ -1080,14 +1162,17 @@ func (st *State) addPeerRelationsOps(serviceName string, peers map[string]charm.
 		}}
 		relKey := relationKey(eps)
 		relDoc := &relationDoc{
+			DocID:     st.docID(relKey),
 			Key:       relKey,
+			EnvUUID:   st.EnvironTag().Id(),
 			Id:        relId,
 			Endpoints: eps,
 			Life:      Alive,
+			Network:   networkName,
 		}
 		ops = append(ops, txn.Op{
 			C:      relationsC,
-			Id:     relKey,
+			Id:     relDoc.DocID,
 			Assert: txn.DocMissing,
 			Insert: relDoc,
 		})
@@ -1151,9 +1236,12 @@ func (st *State) AddService(name, owner string, ch *Charm, networks []string) (s
 		createSettingsOp(st, svc.settingsKey(), nil),
 		{
 			C:      settingsrefsC,
-			Id:     svc.settingsKey(),
+			Id:     st.docID(svc.settingsKey()),
 			Assert: txn.DocMissing,
-			Insert: settingsRefsDoc{1},
+			Insert: settingsRefsDoc{
+				RefCount: 1,
+				EnvUUID:  st.EnvironTag().Id(),
+			},
 		},
 		{
 			C:      servicesC,
@@ -1209,10 +1297,10 @@ func (st *State) AddNetwork(args NetworkInfo) (n *Network, err error) {
 	if args.VLANTag < 0 || args.VLANTag > 4094 {
 		return nil, errors.Errorf("invalid VLAN tag %d: must be between 0 and 4094", args.VLANTag)
 	}
-	doc := newNetworkDoc(args)
+	doc := newNetworkDoc(st, args)
 	ops := []txn.Op{{
 		C:      networksC,
-		Id:     args.Name,
+		Id:     doc.DocID,
 		Assert: txn.DocMissing,
 		Insert: doc,
 	}}
@@ -1245,7 +1333,7 @@ func (st *State) Network(name string) (*Network, error) {
 	defer closer()
 
 	doc := &networkDoc{}
-	err := networks.FindId(name).One(doc)
+	err := networks.FindId(st.docID(name)).One(doc)
 	if err == mgo.ErrNotFound {
 		return nil, errors.NotFoundf("network %q", name)
 	}
@@ -1261,7 +1349,7 @@ func (st *State) AllNetworks() (networks []*Network, err error) {
 	defer closer()
 
 	docs := []networkDoc{}
-	err = networksCollection.Find(nil).All(&docs)
+	err = networksCollection.Find(bson.D{{"env-uuid", st.EnvironTag().Id()}}).All(&docs)
 	if err != nil {
 		return nil, errors.Annotatef(err, "cannot get all networks")
 	}
@@ -1296,7 +1384,7 @@ func (st *State) AllServices() (services []*Service, err error) {
 	defer closer()
 
 	sdocs := []serviceDoc{}
-	err = servicesCollection.Find(bson.D{}).All(&sdocs)
+	err = servicesCollection.Find(bson.D{{"env-uuid", st.EnvironTag().Id()}}).All(&sdocs)
 	if err != nil {
 		return nil, errors.Errorf("cannot get all services")
 	}
@@ -1323,6 +1411,17 @@ func (st *State) localID(ID string) string {
 	return ID
 }
 
+// strictLocalID returns the local id value by removing the
+// environment UUID prefix. If there is no prefix matching the
+// State's environment, an error is returned.
+func (st *State) strictLocalID(ID string) (string, error) {
+	prefix := st.EnvironTag().Id() + ":"
+	if !strings.HasPrefix(ID, prefix) || len(ID) <= len(prefix) {
+		return "", errors.Errorf("unexpected id: %#v", ID)
+	}
+	return ID[len(prefix):], nil
+}
+
 // InferEndpoints returns the endpoints corresponding to the supplied names.
 // There must be 1 or 2 supplied names, of the form <service>[:<relation>].
 // If the supplied names uniquely specify a possible relation, or if they
@@ -1379,13 +1478,38 @@ outer:
 	if len(filtered) == 1 {
 		return filtered[0], nil
 	}
+	return nil, &AmbiguousRelationError{
+		Names:      names,
+		Candidates: candidates,
+	}
+}
+
+// AmbiguousRelationError is returned by InferEndpoints when the
+// supplied names could refer to more than one relation.
+type AmbiguousRelationError struct {
+	// Names holds the names given to InferEndpoints.
+	Names []string
+
+	// Candidates holds the endpoints of each relation that the
+	// names could refer to.
+	Candidates [][]Endpoint
+}
+
+func (e *AmbiguousRelationError) Error() string {
 	keys := []string{}
-	for _, cand := range candidates {
+	for _, cand := range e.Candidates {
 		keys = append(keys, fmt.Sprintf("%q", relationKey(cand)))
 	}
 	sort.Strings(keys)
-	return nil, errors.Errorf("ambiguous relation: %q could refer to %s",
-		strings.Join(names, " "), strings.Join(keys, "; "))
+	return fmt.Sprintf("ambiguous relation: %q could refer to %s",
+		strings.Join(e.Names, " "), strings.Join(keys, "; "))
+}
+
+// IsAmbiguousRelationError returns whether err is an
+// *AmbiguousRelationError.
+func IsAmbiguousRelationError(err error) bool {
+	_, ok := errors.Cause(err).(*AmbiguousRelationError)
+	return ok
 }
 
 func isPeer(ep Endpoint) bool {
@@ -1437,6 +1561,13 @@ func (st *State) endpoints(name string, filter func(ep Endpoint) bool) ([]Endpoi
 
 // AddRelation creates a new relation with the given endpoints.
 func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
+	return st.AddRelationVia("", eps...)
+}
+
+// AddRelationVia creates a new relation with the given endpoints, whose
+// traffic should use the named network. If networkName is empty, the
+// relation is not bound to a network.
+func (st *State) AddRelationVia(networkName string, eps ...Endpoint) (r *Relation, err error) {
 	key := relationKey(eps)
 	defer errors.DeferredAnnotatef(&err, "cannot add relation %q", key)
 	// Enforce basic endpoint sanity. The epCount restrictions may be relaxed
@@ -1467,7 +1598,7 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 	var doc *relationDoc
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		// Perform initial relation sanity check.
-		if exists, err := isNotDead(st.db, relationsC, key); err != nil {
+		if exists, err := isNotDead(st.db, relationsC, st.docID(key)); err != nil {
 			return nil, errors.Trace(err)
 		} else if exists {
 			return nil, errors.Errorf("relation already exists")
@@ -1502,6 +1633,18 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 		if matchSeries && len(series) != 1 {
 			return nil, errors.Errorf("principal and subordinate services' series must match")
 		}
+		if networkName != "" {
+			if _, err := st.Network(networkName); errors.IsNotFound(err) {
+				return nil, errors.Errorf("network %q does not exist", networkName)
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, txn.Op{
+				C:      networksC,
+				Id:     st.docID(networkName),
+				Assert: txn.DocExists,
+			})
+		}
 		// Create a new unique id if that has not already been done, and add
 		// an operation to create the relation document.
 		if id == -1 {
@@ -1511,14 +1654,16 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 			}
 		}
 		doc = &relationDoc{
+			DocID:     st.docID(key),
 			Key:       key,
+			EnvUUID:   st.EnvironTag().Id(),
 			Id:        id,
 			Endpoints: eps,
 			Life:      Alive,
 		}
 		ops = append(ops, txn.Op{
 			C:      relationsC,
-			Id:     doc.Key,
+			Id:     doc.DocID,
 			Assert: txn.DocMissing,
 			Insert: doc,
 		})
@@ -1542,7 +1687,7 @@ func (st *State) KeyRelation(key string) (*Relation, error) {
 	defer closer()
 
 	doc := relationDoc{}
-	err := relations.Find(bson.D{{"_id", key}}).One(&doc)
+	err := relations.FindId(st.docID(key)).One(&doc)
 	if err == mgo.ErrNotFound {
 		return nil, errors.NotFoundf("relation %q", key)
 	}
@@ -1558,7 +1703,7 @@ func (st *State) Relation(id int) (*Relation, error) {
 	defer closer()
 
 	doc := relationDoc{}
-	err := relations.Find(bson.D{{"id", id}}).One(&doc)
+	err := relations.Find(bson.D{{"env-uuid", st.EnvironTag().Id()}, {"id", id}}).One(&doc)
 	if err == mgo.ErrNotFound {
 		return nil, errors.NotFoundf("relation %d", id)
 	}
@@ -1574,7 +1719,7 @@ func (st *State) AllRelations() (relations []*Relation, err error) {
 	defer closer()
 
 	docs := relationDocSlice{}
-	err = relationsCollection.Find(nil).All(&docs)
+	err = relationsCollection.Find(bson.D{{"env-uuid", st.EnvironTag().Id()}}).All(&docs)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot get all relations")
 	}
@@ -1517,6 +1517,22 @@ func (s *StateSuite) TestInferEndpoints(c *gc.C) {
 	}
 }
 
+func (s *StateSuite) TestInferEndpointsAmbiguousError(c *gc.C) {
+	s.AddTestingService(c, "ms", s.AddTestingCharm(c, "mysql-alternative"))
+	s.AddTestingService(c, "wp", s.AddTestingCharm(c, "wordpress"))
+
+	_, err := s.State.InferEndpoints("ms", "wp")
+	c.Assert(err, jc.Satisfies, state.IsAmbiguousRelationError)
+	ambiguous := err.(*state.AmbiguousRelationError)
+	c.Assert(ambiguous.Names, gc.DeepEquals, []string{"ms", "wp"})
+	c.Assert(ambiguous.Candidates, gc.HasLen, 2)
+	for _, cand := range ambiguous.Candidates {
+		c.Assert(cand, gc.HasLen, 2)
+		c.Assert(cand[0].ServiceName, gc.Equals, "ms")
+		c.Assert(cand[1].ServiceName, gc.Equals, "wp")
+	}
+}
+
 func (s *StateSuite) TestEnvironConfig(c *gc.C) {
 	attrs := map[string]interface{}{
 		"authorized-keys": "different-keys",
@@ -2096,7 +2112,7 @@ func (s *StateSuite) TestWatchEnvironConfigCorruptConfig(c *gc.C) {
 
 	// Corrupt the environment configuration.
 	settings := s.Session.DB("juju").C("settings")
-	err = settings.UpdateId("e", bson.D{{"$unset", bson.D{{"name", 1}}}})
+	err = settings.UpdateId(state.DocID(s.State, "e"), bson.D{{"$unset", bson.D{{"name", 1}}}})
 	c.Assert(err, gc.IsNil)
 
 	s.State.StartSync()
@@ -2128,7 +2144,7 @@ func (s *StateSuite) TestWatchEnvironConfigCorruptConfig(c *gc.C) {
 	}
 
 	// Fix the configuration.
-	err = settings.UpdateId("e", bson.D{{"$set", bson.D{{"name", "foo"}}}})
+	err = settings.UpdateId(state.DocID(s.State, "e"), bson.D{{"$set", bson.D{{"name", "foo"}}}})
 	c.Assert(err, gc.IsNil)
 	fixed := cfg.AllAttrs()
 	err = s.State.UpdateEnvironConfig(fixed, nil, nil)
@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"gopkg.in/mgo.v2"
@@ -72,13 +73,19 @@ type StatusGetter interface {
 }
 
 // statusDoc represents a entity status in Mongodb.  The implicit
-// _id field is explicitly set to the global key of the associated
-// entity in the document's creation transaction, but omitted to allow
-// direct use of the document in both create and update transactions.
+// _id field is explicitly set to the environment-prefixed global key of
+// the associated entity in the document's creation transaction, but
+// omitted to allow direct use of the document in both create and update
+// transactions.
 type statusDoc struct {
+	EnvUUID    string `bson:"env-uuid"`
 	Status     Status
 	StatusInfo string
 	StatusData map[string]interface{}
+
+	// Since holds the time the status last changed. It is zero for
+	// statuses set by versions of juju that did not record it.
+	Since time.Time
 }
 
 // validateSet returns an error if the statusDoc does not represent a sane
@@ -99,7 +106,9 @@ func (doc statusDoc) validateSet(allowPending bool) error {
 			return fmt.Errorf("cannot set status %q without info", doc.Status)
 		}
 	}
-	if doc.StatusData != nil && doc.Status != StatusError {
+	// Status data is allowed on errors and, to report provisioning
+	// progress, while pending.
+	if doc.StatusData != nil && doc.Status != StatusError && doc.Status != StatusPending {
 		return fmt.Errorf("cannot set status data when status is %q", doc.Status)
 	}
 	return nil
@@ -113,7 +122,7 @@ func getStatus(st *State, globalKey string) (statusDoc, error) {
 	defer closer()
 
 	var doc statusDoc
-	err := statuses.FindId(globalKey).One(&doc)
+	err := statuses.FindId(st.docID(globalKey)).One(&doc)
 	if err == mgo.ErrNotFound {
 		return statusDoc{}, errors.NotFoundf("status")
 	}
@@ -123,12 +132,26 @@ func getStatus(st *State, globalKey string) (statusDoc, error) {
 	return doc, nil
 }
 
+// statusSince returns the time from which the status in doc should be
+// considered current for the entity with the given global key: the
+// time already recorded if the entity's status and info are unchanged,
+// and the current time otherwise.
+func statusSince(st *State, globalKey string, doc statusDoc) time.Time {
+	current, err := getStatus(st, globalKey)
+	if err == nil && !current.Since.IsZero() &&
+		current.Status == doc.Status && current.StatusInfo == doc.StatusInfo {
+		return current.Since
+	}
+	return nowToTheSecond()
+}
+
 // createStatusOp returns the operation needed to create the given
 // status document associated with the given globalKey.
 func createStatusOp(st *State, globalKey string, doc statusDoc) txn.Op {
+	doc.EnvUUID = st.EnvironTag().Id()
 	return txn.Op{
 		C:      statusesC,
-		Id:     globalKey,
+		Id:     st.docID(globalKey),
 		Assert: txn.DocMissing,
 		Insert: doc,
 	}
@@ -137,9 +160,10 @@ func createStatusOp(st *State, globalKey string, doc statusDoc) txn.Op {
 // updateStatusOp returns the operations needed to update the given
 // status document associated with the given globalKey.
 func updateStatusOp(st *State, globalKey string, doc statusDoc) txn.Op {
+	doc.EnvUUID = st.EnvironTag().Id()
 	return txn.Op{
 		C:      statusesC,
-		Id:     globalKey,
+		Id:     st.docID(globalKey),
 		Assert: txn.DocExists,
 		Update: bson.D{{"$set", doc}},
 	}
@@ -150,7 +174,7 @@ func updateStatusOp(st *State, globalKey string, doc statusDoc) txn.Op {
 func removeStatusOp(st *State, globalKey string) txn.Op {
 	return txn.Op{
 		C:      statusesC,
-		Id:     globalKey,
+		Id:     st.docID(globalKey),
 		Remove: true,
 	}
 }
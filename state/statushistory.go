@@ -0,0 +1,123 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// statusHistoryLimit holds the number of status transitions recorded
+// for each entity. Older transitions are discarded.
+const statusHistoryLimit = 100
+
+// StatusHistoryEntry describes a single status transition of an
+// entity.
+type StatusHistoryEntry struct {
+	Status Status
+	Info   string
+	Since  time.Time
+}
+
+// statusHistoryDoc records the most recent status transitions of an
+// entity, oldest first.
+type statusHistoryDoc struct {
+	DocID   string                  `bson:"_id"`
+	EnvUUID string                  `bson:"env-uuid"`
+	Entries []statusHistoryEntryDoc `bson:"entries"`
+}
+
+type statusHistoryEntryDoc struct {
+	Status Status    `bson:"status"`
+	Info   string    `bson:"info"`
+	Since  time.Time `bson:"since"`
+}
+
+// addStatusHistoryOp returns the operation needed to append the status
+// in doc to the history of the entity with the given global key,
+// discarding the oldest entry once statusHistoryLimit is reached.
+func addStatusHistoryOp(st *State, key string, doc statusDoc) (txn.Op, error) {
+	entry := statusHistoryEntryDoc{
+		Status: doc.Status,
+		Info:   doc.StatusInfo,
+		Since:  doc.Since,
+	}
+	docID := st.docID(key)
+	_, err := readStatusHistory(st, key)
+	if errors.IsNotFound(err) {
+		return txn.Op{
+			C:      statusHistoryC,
+			Id:     docID,
+			Assert: txn.DocMissing,
+			Insert: &statusHistoryDoc{
+				DocID:   docID,
+				EnvUUID: st.EnvironTag().Id(),
+				Entries: []statusHistoryEntryDoc{entry},
+			},
+		}, nil
+	} else if err != nil {
+		return txn.Op{}, err
+	}
+	return txn.Op{
+		C:      statusHistoryC,
+		Id:     docID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$push", bson.D{{"entries", bson.D{
+			{"$each", []statusHistoryEntryDoc{entry}},
+			{"$slice", -statusHistoryLimit},
+		}}}}},
+	}, nil
+}
+
+// StatusHistory returns up to size of the unit's most recent status
+// transitions, newest first.
+func (u *Unit) StatusHistory(size int) ([]StatusHistoryEntry, error) {
+	if size <= 0 {
+		return nil, errors.NotValidf("history size %d", size)
+	}
+	doc, err := readStatusHistory(u.st, u.globalKey())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get status history for unit %q", u)
+	}
+	var history []StatusHistoryEntry
+	for i := len(doc.Entries) - 1; i >= 0 && len(history) < size; i-- {
+		entry := doc.Entries[i]
+		history = append(history, StatusHistoryEntry{
+			Status: entry.Status,
+			Info:   entry.Info,
+			Since:  entry.Since,
+		})
+	}
+	return history, nil
+}
+
+func readStatusHistory(st *State, key string) (*statusHistoryDoc, error) {
+	history, closer := st.getCollection(statusHistoryC)
+	defer closer()
+
+	var doc statusHistoryDoc
+	if err := history.FindId(st.docID(key)).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("status history")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read status history")
+	}
+	return &doc, nil
+}
+
+// removeStatusHistoryOp returns the operation needed to remove the
+// status history of the entity with the given global key. It is a
+// no-op if no history has been recorded.
+func removeStatusHistoryOp(st *State, key string) txn.Op {
+	return txn.Op{
+		C:      statusHistoryC,
+		Id:     st.docID(key),
+		Remove: true,
+	}
+}
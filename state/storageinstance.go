@@ -0,0 +1,311 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// StorageKind defines the type of a storage instance.
+type StorageKind string
+
+const (
+	// StorageKindBlock is a block device, which the charm formats
+	// and mounts itself.
+	StorageKindBlock StorageKind = "block"
+
+	// StorageKindFilesystem is a filesystem, which is mounted before
+	// it is attached to the unit.
+	StorageKindFilesystem StorageKind = "filesystem"
+)
+
+// storageInstanceDoc records a storage instance owned by a unit. The
+// storage instance is attached to the unit once it has a location,
+// and detached once the unit's agent has run the storage-detaching
+// hook for a Dying instance.
+type storageInstanceDoc struct {
+	DocID      string            `bson:"_id"`
+	EnvUUID    string            `bson:"env-uuid"`
+	Id         string            `bson:"id"`
+	Kind       StorageKind       `bson:"kind"`
+	Owner      string            `bson:"owner"`
+	Life       Life              `bson:"life"`
+	Location   string            `bson:"location,omitempty"`
+	Attributes map[string]string `bson:"attributes,omitempty"`
+}
+
+// StorageInstance represents a block device or filesystem owned by a
+// unit.
+type StorageInstance struct {
+	st  *State
+	doc storageInstanceDoc
+}
+
+// storageInstanceKey returns the key of the storage instance with the
+// given id owned by the named unit. Keys begin with the unit name so
+// that a unit's storage instances can be watched without reading them.
+func storageInstanceKey(unitName, id string) string {
+	return unitName + "#" + id
+}
+
+// AddStorageInstance adds a storage instance of the given kind to the
+// unit. The instance's id is made from the storage name and a number
+// unique in the environment, such as "data/0". The instance is not
+// attached to the unit until its location is set.
+func (u *Unit) AddStorageInstance(name string, kind StorageKind) (_ *StorageInstance, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add storage %q to unit %q", name, u)
+	if name == "" || strings.ContainsAny(name, "/#") {
+		return nil, errors.Errorf("invalid storage name")
+	}
+	switch kind {
+	case StorageKindBlock, StorageKindFilesystem:
+	default:
+		return nil, errors.Errorf("invalid storage kind %q", kind)
+	}
+	seq, err := u.st.sequence("storage")
+	if err != nil {
+		return nil, err
+	}
+	id := fmt.Sprintf("%s/%d", name, seq)
+	doc := storageInstanceDoc{
+		DocID:   u.st.docID(storageInstanceKey(u.doc.Name, id)),
+		EnvUUID: u.st.EnvironTag().Id(),
+		Id:      id,
+		Kind:    kind,
+		Owner:   u.doc.Name,
+		Life:    Alive,
+	}
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      storageInstancesC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, unitNotAliveErr
+	} else if err != nil {
+		return nil, err
+	}
+	return &StorageInstance{st: u.st, doc: doc}, nil
+}
+
+// StorageInstance returns the storage instance with the given id
+// owned by the unit.
+func (u *Unit) StorageInstance(id string) (*StorageInstance, error) {
+	instances, closer := u.st.getCollection(storageInstancesC)
+	defer closer()
+
+	var doc storageInstanceDoc
+	err := instances.FindId(u.st.docID(storageInstanceKey(u.doc.Name, id))).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("storage instance %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get storage instance %q", id)
+	}
+	return &StorageInstance{st: u.st, doc: doc}, nil
+}
+
+// StorageInstances returns the storage instances owned by the unit,
+// ordered by id.
+func (u *Unit) StorageInstances() ([]*StorageInstance, error) {
+	instances, closer := u.st.getCollection(storageInstancesC)
+	defer closer()
+
+	var docs []storageInstanceDoc
+	query := bson.D{{"env-uuid", u.st.EnvironTag().Id()}, {"owner", u.doc.Name}}
+	if err := instances.Find(query).Sort("id").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get storage instances of unit %q", u)
+	}
+	result := make([]*StorageInstance, len(docs))
+	for i, doc := range docs {
+		result[i] = &StorageInstance{st: u.st, doc: doc}
+	}
+	return result, nil
+}
+
+// WatchStorageInstances returns a watcher that notifies when any of
+// the unit's storage instances is added, changed or removed.
+func (u *Unit) WatchStorageInstances() NotifyWatcher {
+	prefix := u.st.docID(storageInstanceKey(u.doc.Name, ""))
+	filter := func(key interface{}) bool {
+		k, ok := key.(string)
+		return ok && strings.HasPrefix(k, prefix)
+	}
+	return newFilteredCollectionWatcher(u.st, storageInstancesC, filter)
+}
+
+// Id returns the storage instance's id, such as "data/0".
+func (s *StorageInstance) Id() string {
+	return s.doc.Id
+}
+
+// StorageName returns the name of the storage that the instance
+// provides, such as "data".
+func (s *StorageInstance) StorageName() string {
+	return s.doc.Id[:strings.LastIndex(s.doc.Id, "/")]
+}
+
+// Kind returns the kind of the storage instance.
+func (s *StorageInstance) Kind() StorageKind {
+	return s.doc.Kind
+}
+
+// Owner returns the name of the unit owning the storage instance.
+func (s *StorageInstance) Owner() string {
+	return s.doc.Owner
+}
+
+// Life returns the storage instance's lifecycle state.
+func (s *StorageInstance) Life() Life {
+	return s.doc.Life
+}
+
+// Location returns the path of the block device, or the mount point
+// of the filesystem, and whether it has been set. The storage instance
+// is not attached to its unit until it has a location.
+func (s *StorageInstance) Location() (string, bool) {
+	return s.doc.Location, s.doc.Location != ""
+}
+
+// Attributes returns the provider-specific attributes of the storage
+// instance, such as its size or filesystem type.
+func (s *StorageInstance) Attributes() map[string]string {
+	result := make(map[string]string, len(s.doc.Attributes))
+	for key, value := range s.doc.Attributes {
+		result[unescapeReplacer.Replace(key)] = value
+	}
+	return result
+}
+
+// SetLocation records where the storage instance has been made
+// available on its unit's machine, and any attributes describing it.
+// The location of an instance cannot be changed once set.
+func (s *StorageInstance) SetLocation(location string, attributes map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set location of storage instance %q", s.doc.Id)
+	if location == "" {
+		return errors.New("empty location")
+	}
+	escaped := make(map[string]string, len(attributes))
+	for key, value := range attributes {
+		escaped[escapeReplacer.Replace(key)] = value
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, err
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.New("storage instance is not alive")
+		}
+		if s.doc.Location != "" {
+			return nil, errors.Errorf("already attached at %q", s.doc.Location)
+		}
+		return []txn.Op{{
+			C:  storageInstancesC,
+			Id: s.doc.DocID,
+			Assert: bson.D{
+				{"life", Alive},
+				{"location", bson.D{{"$exists", false}}},
+			},
+			Update: bson.D{{"$set", bson.D{
+				{"location", location},
+				{"attributes", escaped},
+			}}},
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return err
+	}
+	s.doc.Location = location
+	s.doc.Attributes = escaped
+	return nil
+}
+
+// Destroy sets the storage instance's lifecycle to Dying, so that the
+// owning unit detaches it. It does nothing if the instance is already
+// Dying or Dead.
+func (s *StorageInstance) Destroy() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot destroy storage instance %q", s.doc.Id)
+	ops := []txn.Op{{
+		C:      storageInstancesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+	}}
+	if err := s.st.runTransaction(ops); err != nil && err != txn.ErrAborted {
+		return err
+	}
+	return s.Refresh()
+}
+
+// Remove removes the storage instance, once its unit has finished
+// detaching it. It fails if the instance is Alive, and does nothing if
+// it has already been removed.
+func (s *StorageInstance) Remove() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove storage instance %q", s.doc.Id)
+	if s.doc.Life == Alive {
+		return errors.New("storage instance is alive")
+	}
+	ops := []txn.Op{{
+		C:      storageInstancesC,
+		Id:     s.doc.DocID,
+		Assert: bson.D{{"life", bson.D{{"$ne", Alive}}}},
+		Remove: true,
+	}}
+	if err := s.st.runTransaction(ops); err != nil && err != txn.ErrAborted {
+		return err
+	}
+	return nil
+}
+
+// Refresh refreshes the contents of the storage instance from the
+// underlying state. It returns an error that satisfies
+// errors.IsNotFound if the instance has been removed.
+func (s *StorageInstance) Refresh() error {
+	instances, closer := s.st.getCollection(storageInstancesC)
+	defer closer()
+
+	var doc storageInstanceDoc
+	err := instances.FindId(s.doc.DocID).One(&doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("storage instance %q", s.doc.Id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot refresh storage instance %q", s.doc.Id)
+	}
+	s.doc = doc
+	return nil
+}
+
+// removeStorageInstancesForUnit removes all the storage instances owned
+// by the named unit, once the unit itself has been removed.
+func (st *State) removeStorageInstancesForUnit(unitName string) error {
+	instances, closer := st.getCollection(storageInstancesC)
+	defer closer()
+
+	var docs []storageInstanceDoc
+	query := bson.D{{"env-uuid", st.EnvironTag().Id()}, {"owner", unitName}}
+	if err := instances.Find(query).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return errors.Annotatef(err, "cannot get storage instances of unit %q", unitName)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      storageInstancesC,
+			Id:     doc.DocID,
+			Remove: true,
+		}
+	}
+	return st.runTransaction(ops)
+}
@@ -0,0 +1,152 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type StorageInstanceSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&StorageInstanceSuite{})
+
+func (s *StorageInstanceSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit, err = service.AddUnit()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *StorageInstanceSuite) TestAddStorageInstance(c *gc.C) {
+	data, err := s.unit.AddStorageInstance("data", state.StorageKindFilesystem)
+	c.Assert(err, gc.IsNil)
+	c.Assert(data.Id(), gc.Equals, "data/0")
+	c.Assert(data.StorageName(), gc.Equals, "data")
+	c.Assert(data.Kind(), gc.Equals, state.StorageKindFilesystem)
+	c.Assert(data.Owner(), gc.Equals, "wordpress/0")
+	c.Assert(data.Life(), gc.Equals, state.Alive)
+	_, ok := data.Location()
+	c.Assert(ok, jc.IsFalse)
+
+	logs, err := s.unit.AddStorageInstance("logs", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	c.Assert(logs.Id(), gc.Equals, "logs/1")
+
+	instances, err := s.unit.StorageInstances()
+	c.Assert(err, gc.IsNil)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[0].Id(), gc.Equals, "data/0")
+	c.Assert(instances[1].Id(), gc.Equals, "logs/1")
+
+	instance, err := s.unit.StorageInstance("logs/1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(instance.Kind(), gc.Equals, state.StorageKindBlock)
+	_, err = s.unit.StorageInstance("logs/2")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageInstanceSuite) TestAddStorageInstanceInvalid(c *gc.C) {
+	_, err := s.unit.AddStorageInstance("data/0", state.StorageKindBlock)
+	c.Assert(err, gc.ErrorMatches, `cannot add storage "data/0" to unit "wordpress/0": invalid storage name`)
+	_, err = s.unit.AddStorageInstance("data", "tape")
+	c.Assert(err, gc.ErrorMatches, `cannot add storage "data" to unit "wordpress/0": invalid storage kind "tape"`)
+
+	err = s.unit.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	_, err = s.unit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.ErrorMatches, `cannot add storage "data" to unit "wordpress/0": unit is not alive`)
+}
+
+func (s *StorageInstanceSuite) TestSetLocation(c *gc.C) {
+	data, err := s.unit.AddStorageInstance("data", state.StorageKindFilesystem)
+	c.Assert(err, gc.IsNil)
+	err = data.SetLocation("/srv/data", map[string]string{"size": "1024", "fs.type": "ext4"})
+	c.Assert(err, gc.IsNil)
+
+	instance, err := s.unit.StorageInstance("data/0")
+	c.Assert(err, gc.IsNil)
+	location, ok := instance.Location()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(location, gc.Equals, "/srv/data")
+	c.Assert(instance.Attributes(), jc.DeepEquals, map[string]string{"size": "1024", "fs.type": "ext4"})
+
+	err = data.SetLocation("/srv/other", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set location of storage instance "data/0": already attached at "/srv/data"`)
+}
+
+func (s *StorageInstanceSuite) TestDestroyAndRemove(c *gc.C) {
+	data, err := s.unit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	err = data.Remove()
+	c.Assert(err, gc.ErrorMatches, `cannot remove storage instance "data/0": storage instance is alive`)
+
+	err = data.Destroy()
+	c.Assert(err, gc.IsNil)
+	c.Assert(data.Life(), gc.Equals, state.Dying)
+	err = data.SetLocation("/dev/sdb", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set location of storage instance "data/0": storage instance is not alive`)
+	err = data.Destroy()
+	c.Assert(err, gc.IsNil)
+
+	err = data.Remove()
+	c.Assert(err, gc.IsNil)
+	err = data.Remove()
+	c.Assert(err, gc.IsNil)
+	_, err = s.unit.StorageInstance("data/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageInstanceSuite) TestRemovedWithUnit(c *gc.C) {
+	_, err := s.unit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.unit.Remove()
+	c.Assert(err, gc.IsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, gc.IsNil)
+
+	instances, err := s.unit.StorageInstances()
+	c.Assert(err, gc.IsNil)
+	c.Assert(instances, gc.HasLen, 0)
+}
+
+func (s *StorageInstanceSuite) TestWatchStorageInstances(c *gc.C) {
+	w := s.unit.WatchStorageInstances()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	data, err := s.unit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = data.SetLocation("/dev/sdb", nil)
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	// Storage instances of other units are not reported.
+	service, err := s.unit.Service()
+	c.Assert(err, gc.IsNil)
+	otherUnit, err := service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	_, err = otherUnit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	wc.AssertNoChange()
+
+	err = data.Destroy()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = data.Remove()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+}
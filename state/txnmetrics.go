@@ -0,0 +1,128 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sync"
+	"time"
+
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// txnLatencyBounds holds the upper bounds of the buckets into which
+// transaction latencies are counted. Latencies above the last bound
+// are counted in a final, unbounded bucket.
+var txnLatencyBounds = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	5 * time.Second,
+}
+
+// TxnLatencyBucket counts the transactions that completed within
+// a range of latencies.
+type TxnLatencyBucket struct {
+	// UpperBound holds the longest latency counted in the bucket.
+	// It is zero for the final bucket, which has no upper bound.
+	UpperBound time.Duration
+
+	// Count holds the number of transactions counted in the bucket.
+	Count int64
+}
+
+// TxnMetrics holds counts of the transactions run by all State
+// instances in the process since it started.
+type TxnMetrics struct {
+	// Transactions holds the number of transactions run.
+	Transactions int64
+
+	// Attempts holds the number of times transaction operations
+	// were built and run, including retries.
+	Attempts int64
+
+	// Retries holds the number of attempts made after the first
+	// attempt at a transaction was aborted.
+	Retries int64
+
+	// Aborted holds the number of transactions that failed because
+	// their assertions did not hold.
+	Aborted int64
+
+	// ExcessiveContention holds the number of transactions that
+	// were abandoned after too many aborted attempts.
+	ExcessiveContention int64
+
+	// Failed holds the number of transactions that failed for
+	// any other reason.
+	Failed int64
+
+	// Latency holds the number of transactions completed within
+	// each range of latencies, in increasing order.
+	Latency []TxnLatencyBucket
+}
+
+// txnMetricsCollector accumulates TxnMetrics.
+type txnMetricsCollector struct {
+	mu      sync.Mutex
+	metrics TxnMetrics
+}
+
+var txnMetrics = newTxnMetricsCollector()
+
+func newTxnMetricsCollector() *txnMetricsCollector {
+	buckets := make([]TxnLatencyBucket, len(txnLatencyBounds)+1)
+	for i, bound := range txnLatencyBounds {
+		buckets[i].UpperBound = bound
+	}
+	return &txnMetricsCollector{
+		metrics: TxnMetrics{Latency: buckets},
+	}
+}
+
+// record counts a transaction that took the given number of attempts
+// and the given time to complete with the given error.
+func (m *txnMetricsCollector) record(attempts int, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metrics.Transactions++
+	m.metrics.Attempts += int64(attempts)
+	if attempts > 1 {
+		m.metrics.Retries += int64(attempts - 1)
+	}
+	switch err {
+	case nil:
+	case txn.ErrAborted:
+		m.metrics.Aborted++
+	case jujutxn.ErrExcessiveContention:
+		m.metrics.ExcessiveContention++
+	default:
+		m.metrics.Failed++
+	}
+	buckets := m.metrics.Latency
+	for i := range buckets {
+		if i == len(buckets)-1 || latency <= buckets[i].UpperBound {
+			buckets[i].Count++
+			break
+		}
+	}
+}
+
+// snapshot returns a copy of the metrics collected so far.
+func (m *txnMetricsCollector) snapshot() TxnMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics := m.metrics
+	metrics.Latency = append([]TxnLatencyBucket(nil), m.metrics.Latency...)
+	return metrics
+}
+
+// TransactionMetrics returns counts of the transactions run by
+// all State instances in the process, so that contention in the
+// transaction layer can be diagnosed.
+func TransactionMetrics() TxnMetrics {
+	return txnMetrics.snapshot()
+}
@@ -0,0 +1,72 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type TxnMetricsSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&TxnMetricsSuite{})
+
+func (s *TxnMetricsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *TxnMetricsSuite) TestRetries(c *gc.C) {
+	before := state.TransactionMetrics()
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := s.service.SetMinUnits(41)
+		c.Assert(err, gc.IsNil)
+	}).Check()
+	err := s.service.SetMinUnits(42)
+	c.Assert(err, gc.IsNil)
+
+	after := state.TransactionMetrics()
+	c.Assert(after.Transactions-before.Transactions, gc.Equals, int64(2))
+	c.Assert(after.Attempts-before.Attempts, gc.Equals, int64(3))
+	c.Assert(after.Retries-before.Retries, gc.Equals, int64(1))
+	c.Assert(after.Aborted-before.Aborted, gc.Equals, int64(0))
+	c.Assert(after.Failed-before.Failed, gc.Equals, int64(0))
+	s.assertLatencyCounted(c, before, after, 2)
+}
+
+func (s *TxnMetricsSuite) TestAborted(c *gc.C) {
+	offer := state.ServiceOffer{
+		URL:         "local:/u/admin/blog",
+		ServiceName: "wordpress",
+		Endpoints:   []string{"url"},
+	}
+	err := s.State.AddServiceOffer(offer)
+	c.Assert(err, gc.IsNil)
+
+	before := state.TransactionMetrics()
+	err = s.State.AddServiceOffer(offer)
+	c.Assert(err, gc.ErrorMatches, ".*service offer already exists")
+
+	after := state.TransactionMetrics()
+	c.Assert(after.Transactions-before.Transactions, gc.Equals, int64(1))
+	c.Assert(after.Attempts-before.Attempts, gc.Equals, int64(1))
+	c.Assert(after.Aborted-before.Aborted, gc.Equals, int64(1))
+	s.assertLatencyCounted(c, before, after, 1)
+}
+
+func (s *TxnMetricsSuite) assertLatencyCounted(c *gc.C, before, after state.TxnMetrics, expect int64) {
+	c.Assert(after.Latency, gc.HasLen, len(before.Latency))
+	var counted int64
+	for i := range after.Latency {
+		counted += after.Latency[i].Count - before.Latency[i].Count
+	}
+	c.Assert(counted, gc.Equals, expect)
+	c.Assert(after.Latency[len(after.Latency)-1].UpperBound, gc.Equals, time.Duration(0))
+}
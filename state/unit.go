@@ -351,7 +351,7 @@ func (u *Unit) destroyOps() ([]txn.Op, error) {
 	}
 	ops := []txn.Op{{
 		C:      statusesC,
-		Id:     sdocId,
+		Id:     u.st.docID(sdocId),
 		Assert: bson.D{{"status", StatusPending}},
 	}, minUnitsOp}
 	removeAsserts := append(isAliveDoc, unitHasNoSubordinates...)
@@ -403,17 +403,18 @@ func (u *Unit) destroyHostOps(s *Service) (ops []txn.Op, err error) {
 		containerCheck = false
 	} else {
 		ops = append(ops, txn.Op{
-			C:  containerRefsC,
-			Id: m.doc.DocID,
-			Assert: bson.D{{"$or", []bson.D{
-				{{"children", bson.D{{"$size", 0}}}},
-				{{"children", bson.D{{"$exists", false}}}},
-			}}},
+			C:      containerRefsC,
+			Id:     m.doc.DocID,
+			Assert: machineHasNoContainers,
 		})
 	}
 
 	machineCheck := true // whether host machine conditions allow destroy
-	if len(m.doc.Principals) != 1 || m.doc.Principals[0] != u.doc.Name {
+	if m.doc.Life != Alive {
+		// A machine that is already Dying needs no change, and one that
+		// was force-destroyed is already Dead.
+		machineCheck = false
+	} else if len(m.doc.Principals) != 1 || m.doc.Principals[0] != u.doc.Name {
 		machineCheck = false
 	} else if hasJob(m.doc.Jobs, JobManageEnviron) {
 		// Check that the machine does not have any responsibilities that
@@ -428,12 +429,14 @@ func (u *Unit) destroyHostOps(s *Service) (ops []txn.Op, err error) {
 	var machineAssert bson.D
 	if machineCheck {
 		machineAssert = bson.D{{"$and", []bson.D{
+			bson.D{{"life", Alive}},
 			bson.D{{"principals", []string{u.doc.Name}}},
 			bson.D{{"jobs", bson.D{{"$nin", []MachineJob{JobManageEnviron}}}}},
 			bson.D{{"hasvote", bson.D{{"$ne", true}}}},
 		}}}
 	} else {
 		machineAssert = bson.D{{"$or", []bson.D{
+			bson.D{{"life", bson.D{{"$ne", Alive}}}},
 			bson.D{{"principals", bson.D{{"$ne", []string{u.doc.Name}}}}},
 			bson.D{{"jobs", bson.D{{"$in", []MachineJob{JobManageEnviron}}}}},
 			bson.D{{"hasvote", true}},
@@ -657,21 +660,35 @@ func (u *Unit) addressesOfMachine() []network.Address {
 }
 
 // PublicAddress returns the public address of the unit and whether it is valid.
+// The environment's public-address-preference setting is honoured.
 func (u *Unit) PublicAddress() (string, bool) {
 	var publicAddress string
 	addresses := u.addressesOfMachine()
 	if len(addresses) > 0 {
-		publicAddress = network.SelectPublicAddress(addresses)
+		var prefs network.AddressPreferences
+		if cfg, err := u.st.EnvironConfig(); err == nil {
+			prefs = cfg.PublicAddressPreferences()
+		} else {
+			unitLogger.Errorf("unit %v cannot get environment config: %v", u, err)
+		}
+		publicAddress = network.SelectPublicAddressWithPreferences(addresses, prefs)
 	}
 	return publicAddress, publicAddress != ""
 }
 
 // PrivateAddress returns the private address of the unit and whether it is valid.
+// The environment's private-address-preference setting is honoured.
 func (u *Unit) PrivateAddress() (string, bool) {
 	var privateAddress string
 	addresses := u.addressesOfMachine()
 	if len(addresses) > 0 {
-		privateAddress = network.SelectInternalAddress(addresses, false)
+		var prefs network.AddressPreferences
+		if cfg, err := u.st.EnvironConfig(); err == nil {
+			prefs = cfg.PrivateAddressPreferences()
+		} else {
+			unitLogger.Errorf("unit %v cannot get environment config: %v", u, err)
+		}
+		privateAddress = network.SelectInternalAddressWithPreferences(addresses, prefs)
 	}
 	return privateAddress, privateAddress != ""
 }
@@ -705,6 +722,17 @@ func (u *Unit) Status() (status Status, info string, data map[string]interface{}
 	return
 }
 
+// StatusSince returns the time the unit's status last changed. The
+// time is zero if the status was set by a version of juju that did
+// not record it.
+func (u *Unit) StatusSince() (time.Time, error) {
+	doc, err := getStatus(u.st, u.globalKey())
+	if err != nil {
+		return time.Time{}, err
+	}
+	return doc.Since, nil
+}
+
 // SetStatus sets the status of the unit. The optional values
 // allow to pass additional helpful status data.
 func (u *Unit) SetStatus(status Status, info string, data map[string]interface{}) error {
@@ -716,16 +744,39 @@ func (u *Unit) SetStatus(status Status, info string, data map[string]interface{}
 	if err := doc.validateSet(false); err != nil {
 		return err
 	}
-	ops := []txn.Op{{
-		C:      unitsC,
-		Id:     u.doc.DocID,
-		Assert: notDeadDoc,
-	},
-		updateStatusOp(u.st, u.globalKey(), doc),
+	key := u.globalKey()
+	doc.Since = statusSince(u.st, key, doc)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if notDead, err := isNotDead(u.st.db, unitsC, u.doc.DocID); err != nil {
+				return nil, err
+			} else if !notDead {
+				return nil, ErrDead
+			}
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		},
+			updateStatusOp(u.st, key, doc),
+		}
+		// Only transitions are recorded in the unit's status history.
+		current, err := getStatus(u.st, key)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if err != nil || current.Status != doc.Status || current.StatusInfo != doc.StatusInfo {
+			historyOp, err := addStatusHistoryOp(u.st, key, doc)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, historyOp)
+		}
+		return ops, nil
 	}
-	err := u.st.runTransaction(ops)
-	if err != nil {
-		return fmt.Errorf("cannot set status of unit %q: %v", u, onAbort(err, ErrDead))
+	if err := u.st.run(buildTxn); err != nil {
+		return fmt.Errorf("cannot set status of unit %q: %v", u, err)
 	}
 	return nil
 }
@@ -1341,7 +1392,8 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 	// If we need empty machines, first build up a list of machine ids which have containers
 	// so we can exclude those.
 	if requireEmpty {
-		err = containerRefsCollection.Find(bson.D{hasContainerTerm}).All(&containerRefs)
+		sel := bson.D{{"env-uuid", u.st.EnvironTag().Id()}, hasContainerTerm}
+		err = containerRefsCollection.Find(sel).All(&containerRefs)
 		if err != nil {
 			return nil, closer, err
 		}
@@ -1351,6 +1403,7 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		machinesWithContainers[i] = u.st.docID(cref.Id)
 	}
 	terms := bson.D{
+		{"env-uuid", u.st.EnvironTag().Id()},
 		{"life", Alive},
 		{"series", u.doc.Series},
 		{"jobs", []MachineJob{JobHostUnits}},
@@ -1376,7 +1429,7 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 	// be suitable, but we don't know that right now and it's best
 	// to err on the side of caution and exclude such machines.
 	var suitableInstanceData []instanceData
-	var suitableTerms bson.D
+	suitableTerms := bson.D{{"env-uuid", u.st.EnvironTag().Id()}}
 	if cons.Arch != nil && *cons.Arch != "" {
 		suitableTerms = append(suitableTerms, bson.DocElem{"arch", *cons.Arch})
 	}
@@ -1395,7 +1448,7 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 	if cons.Tags != nil && len(*cons.Tags) > 0 {
 		suitableTerms = append(suitableTerms, bson.DocElem{"tags", bson.D{{"$all", *cons.Tags}}})
 	}
-	if len(suitableTerms) > 0 {
+	if len(suitableTerms) > 1 {
 		instanceData := db.C(instanceDataC)
 		err := instanceData.Find(suitableTerms).Select(bson.M{"_id": 1}).All(&suitableInstanceData)
 		if err != nil {
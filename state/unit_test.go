@@ -4,13 +4,16 @@
 package state_test
 
 import (
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/txn"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -226,6 +229,30 @@ func (s *UnitSuite) TestPublicAddress(c *gc.C) {
 	c.Assert(ok, gc.Equals, true)
 }
 
+func (s *UnitSuite) TestAddressPreferences(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, gc.IsNil)
+	public := network.NewAddress("8.8.8.8", network.ScopePublic)
+	private := network.NewAddress("10.0.0.1", network.ScopeCloudLocal)
+	err = machine.SetAddresses(public, private)
+	c.Assert(err, gc.IsNil)
+
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"public-address-preference":  "private-ip",
+		"private-address-preference": "public-ip",
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	address, ok := s.unit.PublicAddress()
+	c.Check(address, gc.Equals, "10.0.0.1")
+	c.Assert(ok, gc.Equals, true)
+	address, ok = s.unit.PrivateAddress()
+	c.Check(address, gc.Equals, "8.8.8.8")
+	c.Assert(ok, gc.Equals, true)
+}
+
 func (s *UnitSuite) TestPublicAddressMachineAddresses(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, gc.IsNil)
@@ -579,6 +606,82 @@ func (s *UnitSuite) TestGetSetStatusWhileNotAlive(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "status not found")
 }
 
+func (s *UnitSuite) TestStatusSince(c *gc.C) {
+	since, err := s.unit.StatusSince()
+	c.Assert(err, gc.IsNil)
+	c.Assert(since.IsZero(), jc.IsFalse)
+
+	// Move the recorded time into the past, so that changes to it can
+	// be seen.
+	past := time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)
+	setSince := func() {
+		statuses := s.MgoSuite.Session.DB("juju").C("statuses")
+		err := statuses.UpdateId(state.DocID(s.State, "u#wordpress/0"), bson.D{{"$set", bson.D{{"since", past}}}})
+		c.Assert(err, gc.IsNil)
+	}
+
+	err = s.unit.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+	setSince()
+	err = s.unit.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+	since, err = s.unit.StatusSince()
+	c.Assert(err, gc.IsNil)
+	c.Assert(since.Equal(past), jc.IsTrue)
+
+	err = s.unit.SetStatus(state.StatusError, "failed", nil)
+	c.Assert(err, gc.IsNil)
+	since, err = s.unit.StatusSince()
+	c.Assert(err, gc.IsNil)
+	c.Assert(since.After(past), jc.IsTrue)
+}
+
+func (s *UnitSuite) TestStatusHistory(c *gc.C) {
+	history, err := s.unit.StatusHistory(10)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, 0)
+
+	err = s.unit.SetStatus(state.StatusInstalled, "", nil)
+	c.Assert(err, gc.IsNil)
+	err = s.unit.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+	// Setting an unchanged status records no transition.
+	err = s.unit.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+	err = s.unit.SetStatus(state.StatusError, "hook failed", nil)
+	c.Assert(err, gc.IsNil)
+
+	history, err = s.unit.StatusHistory(10)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, 3)
+	c.Assert(history[0].Status, gc.Equals, state.StatusError)
+	c.Assert(history[0].Info, gc.Equals, "hook failed")
+	c.Assert(history[0].Since.IsZero(), jc.IsFalse)
+	c.Assert(history[1].Status, gc.Equals, state.StatusStarted)
+	c.Assert(history[2].Status, gc.Equals, state.StatusInstalled)
+
+	history, err = s.unit.StatusHistory(1)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Status, gc.Equals, state.StatusError)
+
+	_, err = s.unit.StatusHistory(0)
+	c.Assert(err, gc.ErrorMatches, "history size 0 not valid")
+}
+
+func (s *UnitSuite) TestStatusHistoryIsBounded(c *gc.C) {
+	limit := state.StatusHistoryLimit
+	for i := 0; i < limit+5; i++ {
+		err := s.unit.SetStatus(state.StatusError, fmt.Sprintf("failure %d", i), nil)
+		c.Assert(err, gc.IsNil)
+	}
+	history, err := s.unit.StatusHistory(limit + 5)
+	c.Assert(err, gc.IsNil)
+	c.Assert(history, gc.HasLen, limit)
+	c.Assert(history[0].Info, gc.Equals, fmt.Sprintf("failure %d", limit+4))
+	c.Assert(history[limit-1].Info, gc.Equals, "failure 5")
+}
+
 func (s *UnitSuite) TestGetSetStatusDataStandard(c *gc.C) {
 	err := s.unit.SetStatus(state.StatusStarted, "", nil)
 	c.Assert(err, gc.IsNil)
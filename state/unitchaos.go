@@ -0,0 +1,161 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// UnitChaos holds the faults that a unit's agent is instructed to
+// inject into its hook execution, so that charm authors can exercise
+// their charms' error handling.
+type UnitChaos struct {
+	// FailHooks holds the names of the hooks to fail instead of
+	// running them, such as "install" or "db-relation-changed".
+	// The name "*" fails every hook.
+	FailHooks []string
+
+	// HookDelay holds how long to wait before running each hook.
+	HookDelay time.Duration
+
+	// Restart holds whether the unit's agent should restart before
+	// running its next hook. The agent clears it when it restarts.
+	Restart bool
+}
+
+// IsZero reports whether no faults are to be injected.
+func (c UnitChaos) IsZero() bool {
+	return len(c.FailHooks) == 0 && c.HookDelay == 0 && !c.Restart
+}
+
+// unitChaosDoc records the faults to inject into a unit's hooks.
+type unitChaosDoc struct {
+	DocID     string        `bson:"_id"`
+	EnvUUID   string        `bson:"env-uuid"`
+	Unit      string        `bson:"unit"`
+	FailHooks []string      `bson:"failhooks"`
+	HookDelay time.Duration `bson:"hookdelay"`
+	Restart   bool          `bson:"restart"`
+}
+
+// SetChaos replaces the faults that the unit's agent injects into its
+// hook execution. Setting the zero UnitChaos clears them.
+func (u *Unit) SetChaos(chaos UnitChaos) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set chaos for unit %q", u)
+	key := u.globalKey()
+	doc := unitChaosDoc{
+		DocID:     u.st.docID(key),
+		EnvUUID:   u.st.EnvironTag().Id(),
+		Unit:      u.Name(),
+		FailHooks: chaos.FailHooks,
+		HookDelay: chaos.HookDelay,
+		Restart:   chaos.Restart,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if notDead, err := isNotDead(u.st.db, unitsC, u.doc.DocID); err != nil {
+			return nil, err
+		} else if !notDead {
+			return nil, ErrDead
+		}
+		_, err := readUnitChaos(u.st, key)
+		exists := err == nil
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		var op txn.Op
+		switch {
+		case chaos.IsZero() && !exists:
+			return nil, jujutxn.ErrNoOperations
+		case chaos.IsZero():
+			op = removeUnitChaosOp(u.st, key)
+		case exists:
+			op = txn.Op{
+				C:      unitChaosC,
+				Id:     doc.DocID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{
+					{"failhooks", doc.FailHooks},
+					{"hookdelay", doc.HookDelay},
+					{"restart", doc.Restart},
+				}}},
+			}
+		default:
+			op = txn.Op{
+				C:      unitChaosC,
+				Id:     doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}
+		}
+		return []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}, op}, nil
+	}
+	return u.st.run(buildTxn)
+}
+
+// Chaos returns the faults that the unit's agent injects into its
+// hook execution. The zero UnitChaos is returned if none are set.
+func (u *Unit) Chaos() (UnitChaos, error) {
+	doc, err := readUnitChaos(u.st, u.globalKey())
+	if errors.IsNotFound(err) {
+		return UnitChaos{}, nil
+	} else if err != nil {
+		return UnitChaos{}, err
+	}
+	return UnitChaos{
+		FailHooks: doc.FailHooks,
+		HookDelay: doc.HookDelay,
+		Restart:   doc.Restart,
+	}, nil
+}
+
+// ClearChaosRestart records that the unit's agent has restarted as
+// instructed, so that it does not restart again.
+func (u *Unit) ClearChaosRestart() error {
+	ops := []txn.Op{{
+		C:      unitChaosC,
+		Id:     u.st.docID(u.globalKey()),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"restart", false}}}},
+	}}
+	err := u.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		// The chaos was cleared altogether.
+		return nil
+	}
+	return errors.Annotatef(err, "cannot clear chaos restart for unit %q", u)
+}
+
+func readUnitChaos(st *State, key string) (*unitChaosDoc, error) {
+	chaos, closer := st.getCollection(unitChaosC)
+	defer closer()
+
+	var doc unitChaosDoc
+	if err := chaos.FindId(st.docID(key)).One(&doc); err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("unit chaos")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read unit chaos")
+	}
+	return &doc, nil
+}
+
+// removeUnitChaosOp returns the operation required to remove the
+// chaos settings for the unit with the given global key. It is a
+// no-op if none are set.
+func removeUnitChaosOp(st *State, key string) txn.Op {
+	return txn.Op{
+		C:      unitChaosC,
+		Id:     st.docID(key),
+		Remove: true,
+	}
+}
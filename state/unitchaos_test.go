@@ -0,0 +1,95 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type UnitChaosSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&UnitChaosSuite{})
+
+func (s *UnitChaosSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit, err = service.AddUnit()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *UnitChaosSuite) TestSetChaos(c *gc.C) {
+	chaos, err := s.unit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos, gc.DeepEquals, state.UnitChaos{})
+
+	expect := state.UnitChaos{
+		FailHooks: []string{"install", "db-relation-changed"},
+		HookDelay: 30 * time.Second,
+		Restart:   true,
+	}
+	err = s.unit.SetChaos(expect)
+	c.Assert(err, gc.IsNil)
+	chaos, err = s.unit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos, gc.DeepEquals, expect)
+
+	// Setting chaos again replaces it.
+	expect = state.UnitChaos{FailHooks: []string{"*"}}
+	err = s.unit.SetChaos(expect)
+	c.Assert(err, gc.IsNil)
+	chaos, err = s.unit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos, gc.DeepEquals, expect)
+
+	// Setting the zero value clears it.
+	err = s.unit.SetChaos(state.UnitChaos{})
+	c.Assert(err, gc.IsNil)
+	chaos, err = s.unit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos, gc.DeepEquals, state.UnitChaos{})
+	err = s.unit.SetChaos(state.UnitChaos{})
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *UnitChaosSuite) TestClearChaosRestart(c *gc.C) {
+	err := s.unit.ClearChaosRestart()
+	c.Assert(err, gc.IsNil)
+
+	err = s.unit.SetChaos(state.UnitChaos{HookDelay: time.Second, Restart: true})
+	c.Assert(err, gc.IsNil)
+	err = s.unit.ClearChaosRestart()
+	c.Assert(err, gc.IsNil)
+	chaos, err := s.unit.Chaos()
+	c.Assert(err, gc.IsNil)
+	c.Assert(chaos, gc.DeepEquals, state.UnitChaos{HookDelay: time.Second})
+}
+
+func (s *UnitChaosSuite) TestSetChaosDeadUnit(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.unit.SetChaos(state.UnitChaos{Restart: true})
+	c.Assert(err, gc.ErrorMatches, `cannot set chaos for unit "wordpress/0": not found or dead`)
+}
+
+func (s *UnitChaosSuite) TestChaosRemovedWithUnit(c *gc.C) {
+	err := s.unit.SetChaos(state.UnitChaos{Restart: true})
+	c.Assert(err, gc.IsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.unit.Remove()
+	c.Assert(err, gc.IsNil)
+
+	coll := s.MgoSuite.Session.DB("juju").C("unitchaos")
+	n, err := coll.Count()
+	c.Assert(err, gc.IsNil)
+	c.Assert(n, gc.Equals, 0)
+}
@@ -0,0 +1,184 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// unitGroupDoc records a named selection of units, so that operations
+// can be staged across the same units repeatedly.
+type unitGroupDoc struct {
+	DocID    string   `bson:"_id"`
+	EnvUUID  string   `bson:"env-uuid"`
+	Name     string   `bson:"name"`
+	Patterns []string `bson:"patterns"`
+}
+
+// UnitGroup represents a named group of units, defined by patterns
+// matching unit names.
+type UnitGroup struct {
+	st  *State
+	doc unitGroupDoc
+}
+
+var (
+	validUnitGroupName   = regexp.MustCompile("^[a-z][a-z0-9-]*$")
+	validUnitPatternPart = regexp.MustCompile("^[a-z0-9-*]+$")
+)
+
+// IsValidUnitGroupName returns whether name is a valid unit group name.
+func IsValidUnitGroupName(name string) bool {
+	return validUnitGroupName.MatchString(name)
+}
+
+// validateUnitPattern returns an error if pattern is not a valid unit
+// name pattern: a service name pattern, optionally followed by a slash
+// and a unit number pattern, using only alphanumeric characters,
+// hyphens and asterisks.
+func validateUnitPattern(pattern string) error {
+	fields := strings.Split(pattern, "/")
+	if len(fields) > 2 {
+		return fmt.Errorf("pattern %q contains too many '/' characters", pattern)
+	}
+	for _, f := range fields {
+		if !validUnitPatternPart.MatchString(f) {
+			return fmt.Errorf("pattern %q contains invalid characters", pattern)
+		}
+	}
+	return nil
+}
+
+// AddUnitGroup saves a unit group with the given name, matching the
+// units whose names match any of the given patterns. A pattern without
+// a unit number matches all the units of the matching services.
+func (st *State) AddUnitGroup(name string, patterns []string) (_ *UnitGroup, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add unit group %q", name)
+	if !IsValidUnitGroupName(name) {
+		return nil, errors.New("invalid name")
+	}
+	if len(patterns) == 0 {
+		return nil, errors.New("no patterns specified")
+	}
+	for _, pattern := range patterns {
+		if err := validateUnitPattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+	doc := unitGroupDoc{
+		DocID:    st.docID(name),
+		EnvUUID:  st.EnvironTag().Id(),
+		Name:     name,
+		Patterns: patterns,
+	}
+	ops := []txn.Op{{
+		C:      unitGroupsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.AlreadyExistsf("unit group %q", name)
+	} else if err != nil {
+		return nil, err
+	}
+	return &UnitGroup{st: st, doc: doc}, nil
+}
+
+// UnitGroup returns the unit group with the given name.
+func (st *State) UnitGroup(name string) (*UnitGroup, error) {
+	groups, closer := st.getCollection(unitGroupsC)
+	defer closer()
+
+	var doc unitGroupDoc
+	err := groups.FindId(st.docID(name)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("unit group %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get unit group %q", name)
+	}
+	return &UnitGroup{st: st, doc: doc}, nil
+}
+
+// AllUnitGroups returns all the unit groups in the environment,
+// ordered by name.
+func (st *State) AllUnitGroups() ([]*UnitGroup, error) {
+	groups, closer := st.getCollection(unitGroupsC)
+	defer closer()
+
+	var docs []unitGroupDoc
+	if err := groups.Find(bson.D{{"env-uuid", st.EnvironTag().Id()}}).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all unit groups")
+	}
+	result := make([]*UnitGroup, len(docs))
+	for i, doc := range docs {
+		result[i] = &UnitGroup{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// Name returns the name of the unit group.
+func (g *UnitGroup) Name() string {
+	return g.doc.Name
+}
+
+// Patterns returns the unit name patterns defining the group.
+func (g *UnitGroup) Patterns() []string {
+	return append([]string(nil), g.doc.Patterns...)
+}
+
+// Remove removes the unit group. The units themselves are unaffected.
+func (g *UnitGroup) Remove() error {
+	ops := []txn.Op{{
+		C:      unitGroupsC,
+		Id:     g.doc.DocID,
+		Remove: true,
+	}}
+	if err := g.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove unit group %q", g.doc.Name)
+	}
+	return nil
+}
+
+// UnitNames returns the names of the units currently matching the
+// group's patterns, in sorted order.
+func (g *UnitGroup) UnitNames() ([]string, error) {
+	patterns := make([]string, len(g.doc.Patterns))
+	for i, pattern := range g.doc.Patterns {
+		if !strings.Contains(pattern, "/") {
+			pattern += "/*"
+		}
+		patterns[i] = pattern
+	}
+	services, err := g.st.AllServices()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, service := range services {
+		units, err := service.AllUnits()
+		if err != nil {
+			return nil, err
+		}
+		for _, unit := range units {
+			for _, pattern := range patterns {
+				if ok, _ := path.Match(pattern, unit.Name()); ok {
+					names = append(names, unit.Name())
+					break
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
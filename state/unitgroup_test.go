@@ -0,0 +1,151 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type UnitGroupSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UnitGroupSuite{})
+
+func (s *UnitGroupSuite) addUnits(c *gc.C, serviceName string, count int) {
+	svc := s.AddTestingService(c, serviceName, s.AddTestingCharm(c, "wordpress"))
+	for i := 0; i < count; i++ {
+		_, err := svc.AddUnit()
+		c.Assert(err, gc.IsNil)
+	}
+}
+
+func (s *UnitGroupSuite) TestAddUnitGroup(c *gc.C) {
+	group, err := s.State.AddUnitGroup("canary", []string{"web/0", "blog"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(group.Name(), gc.Equals, "canary")
+	c.Assert(group.Patterns(), gc.DeepEquals, []string{"web/0", "blog"})
+
+	group, err = s.State.UnitGroup("canary")
+	c.Assert(err, gc.IsNil)
+	c.Assert(group.Name(), gc.Equals, "canary")
+	c.Assert(group.Patterns(), gc.DeepEquals, []string{"web/0", "blog"})
+
+	_, err = s.State.AddUnitGroup("canary", []string{"web/1"})
+	c.Assert(err, gc.ErrorMatches, `cannot add unit group "canary": unit group "canary" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *UnitGroupSuite) TestAddUnitGroupInvalid(c *gc.C) {
+	for i, t := range []struct {
+		name     string
+		patterns []string
+		err      string
+	}{{
+		name:     "Canary",
+		patterns: []string{"web/0"},
+		err:      `cannot add unit group "Canary": invalid name`,
+	}, {
+		name: "canary",
+		err:  `cannot add unit group "canary": no patterns specified`,
+	}, {
+		name:     "canary",
+		patterns: []string{"web/0/1"},
+		err:      `cannot add unit group "canary": pattern "web/0/1" contains too many '/' characters`,
+	}, {
+		name:     "canary",
+		patterns: []string{"web/[01]"},
+		err:      `cannot add unit group "canary": pattern "web/\[01\]" contains invalid characters`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.AddUnitGroup(t.name, t.patterns)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *UnitGroupSuite) TestUnitNames(c *gc.C) {
+	s.addUnits(c, "web", 3)
+	s.addUnits(c, "blog", 2)
+	s.addUnits(c, "webfront", 1)
+
+	group, err := s.State.AddUnitGroup("canary", []string{"web/0", "web/2", "blog"})
+	c.Assert(err, gc.IsNil)
+	names, err := group.UnitNames()
+	c.Assert(err, gc.IsNil)
+	c.Assert(names, gc.DeepEquals, []string{"blog/0", "blog/1", "web/0", "web/2"})
+
+	group, err = s.State.AddUnitGroup("webs", []string{"web*/0"})
+	c.Assert(err, gc.IsNil)
+	names, err = group.UnitNames()
+	c.Assert(err, gc.IsNil)
+	c.Assert(names, gc.DeepEquals, []string{"web/0", "webfront/0"})
+}
+
+func (s *UnitGroupSuite) TestAllUnitGroupsAndRemove(c *gc.C) {
+	_, err := s.State.AddUnitGroup("zeta", []string{"web"})
+	c.Assert(err, gc.IsNil)
+	alpha, err := s.State.AddUnitGroup("alpha", []string{"blog"})
+	c.Assert(err, gc.IsNil)
+
+	groups, err := s.State.AllUnitGroups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(groups, gc.HasLen, 2)
+	c.Assert(groups[0].Name(), gc.Equals, "alpha")
+	c.Assert(groups[1].Name(), gc.Equals, "zeta")
+
+	err = alpha.Remove()
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.UnitGroup("alpha")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	groups, err = s.State.AllUnitGroups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(groups, gc.HasLen, 1)
+}
+
+func (s *UnitGroupSuite) TestUnitGroupsPerEnvironment(c *gc.C) {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, gc.IsNil)
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"name": "hosted",
+		"uuid": uuid.String(),
+	})
+	_, st, err := s.State.NewHostedEnvironment(cfg, s.owner)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	// Groups with the same name may be added to each environment.
+	_, err = s.State.AddUnitGroup("canary", []string{"web/0"})
+	c.Assert(err, gc.IsNil)
+	hosted, err := st.AddUnitGroup("canary", []string{"blog"})
+	c.Assert(err, gc.IsNil)
+	_, err = st.AddUnitGroup("zeta", []string{"blog/1"})
+	c.Assert(err, gc.IsNil)
+
+	groups, err := s.State.AllUnitGroups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(groups, gc.HasLen, 1)
+	c.Assert(groups[0].Patterns(), gc.DeepEquals, []string{"web/0"})
+	_, err = s.State.UnitGroup("zeta")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	groups, err = st.AllUnitGroups()
+	c.Assert(err, gc.IsNil)
+	c.Assert(groups, gc.HasLen, 2)
+	c.Assert(groups[0].Patterns(), gc.DeepEquals, []string{"blog"})
+	c.Assert(groups[1].Name(), gc.Equals, "zeta")
+
+	// Removing a group leaves the other environment's group alone.
+	err = hosted.Remove()
+	c.Assert(err, gc.IsNil)
+	group, err := s.State.UnitGroup("canary")
+	c.Assert(err, gc.IsNil)
+	c.Assert(group.Patterns(), gc.DeepEquals, []string{"web/0"})
+	_, err = st.UnitGroup("canary")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
@@ -178,16 +179,14 @@ func beginUnitMigrationOps(st *State, unit *Unit, machineId string) (
 	if errors.IsNotFound(err) {
 		// No ports document on this machine yet, let's add ops to
 		// create an empty one first.
-		pdoc := portsDoc{
-			Id:    portsId,
-			Ports: []PortRange{},
-		}
+		pdoc := newPortsDoc(st, portsId)
+		pdoc.Ports = []PortRange{}
 		ops = append(ops, txn.Op{
 			C:      openedPortsC,
-			Id:     portsId,
+			Id:     pdoc.DocID,
 			Insert: pdoc,
 		})
-		machinePorts = &Ports{st, pdoc, true}
+		machinePorts = &Ports{st, *pdoc, true}
 		upgradesLogger.Debugf(
 			"created ports for machine %q, network %q",
 			machineId, network.DefaultPublic,
@@ -285,7 +284,7 @@ func finishUnitMigrationOps(
 		}
 		ops = append(ops, txn.Op{
 			C:      openedPortsC,
-			Id:     portsId,
+			Id:     st.docID(portsId),
 			Update: bson.D{{"$addToSet", bson.D{{"ports", portRange}}}},
 		})
 		migratedPorts += portRange.Length()
@@ -407,7 +406,7 @@ func CreateUnitMeterStatus(st *State) error {
 	for _, uDoc := range unitSlice {
 		unit := &Unit{st: st, doc: uDoc}
 		upgradesLogger.Infof("creating meter status doc for unit %q", unit)
-		cnt, err := meterStatuses.FindId(unit.globalKey()).Count()
+		cnt, err := meterStatuses.FindId(st.docID(unit.globalKey())).Count()
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -535,6 +534,158 @@ func AddEnvUUIDToInstanceData(st *State) error {
 	return addEnvUUIDToEntityCollection(st, instanceDataC, "machineid")
 }
 
+// AddEnvUUIDToSettings prepends the environment UUID to the ID of
+// all settings docs and adds new "env-uuid" field.
+func AddEnvUUIDToSettings(st *State) error {
+	return addEnvUUIDToEntityCollection(st, settingsC, "")
+}
+
+// AddEnvUUIDToConstraints prepends the environment UUID to the ID of
+// all constraints docs and adds new "env-uuid" field.
+func AddEnvUUIDToConstraints(st *State) error {
+	return addEnvUUIDToEntityCollection(st, constraintsC, "")
+}
+
+// AddEnvUUIDToSettingsRefs prepends the environment UUID to the ID of
+// all settingsRef docs and adds new "env-uuid" field.
+func AddEnvUUIDToSettingsRefs(st *State) error {
+	return addEnvUUIDToEntityCollection(st, settingsrefsC, "")
+}
+
+// AddEnvUUIDToRelations prepends the environment UUID to the ID of
+// all relations docs and adds new "env-uuid" and "key" fields.
+func AddEnvUUIDToRelations(st *State) error {
+	return addEnvUUIDToEntityCollection(st, relationsC, "key")
+}
+
+// AddEnvUUIDToRelationScopes prepends the environment UUID to the ID of
+// all relationscopes docs and adds new "env-uuid" and "key" fields.
+func AddEnvUUIDToRelationScopes(st *State) error {
+	return addEnvUUIDToEntityCollection(st, relationScopesC, "key")
+}
+
+// AddEnvUUIDToMinUnits prepends the environment UUID to the ID of
+// all minUnits docs and adds new "env-uuid" and "servicename" fields.
+func AddEnvUUIDToMinUnits(st *State) error {
+	return addEnvUUIDToEntityCollection(st, minUnitsC, "servicename")
+}
+
+// AddEnvUUIDToAnnotations prepends the environment UUID to the ID of
+// all annotation docs and adds new "env-uuid" and "globalkey" fields.
+func AddEnvUUIDToAnnotations(st *State) error {
+	return addEnvUUIDToEntityCollection(st, annotationsC, "globalkey")
+}
+
+// AddEnvUUIDToStatuses prepends the environment UUID to the ID of
+// all status docs and adds new "env-uuid" field.
+func AddEnvUUIDToStatuses(st *State) error {
+	return addEnvUUIDToEntityCollection(st, statusesC, "")
+}
+
+// AddEnvUUIDToOpenPorts prepends the environment UUID to the ID of
+// all openedPorts docs and adds new "env-uuid" and "id" fields.
+func AddEnvUUIDToOpenPorts(st *State) error {
+	return addEnvUUIDToEntityCollection(st, openedPortsC, "id")
+}
+
+// AddEnvUUIDToMeterStatus prepends the environment UUID to the ID of
+// all meterStatus docs and adds new "env-uuid" field.
+func AddEnvUUIDToMeterStatus(st *State) error {
+	return addEnvUUIDToEntityCollection(st, meterStatusC, "")
+}
+
+// AddEnvUUIDToRequestedNetworks prepends the environment UUID to the ID
+// of all requestedNetworks docs and adds new "env-uuid" field.
+func AddEnvUUIDToRequestedNetworks(st *State) error {
+	return addEnvUUIDToEntityCollection(st, requestedNetworksC, "")
+}
+
+// AddEnvUUIDToSequences prepends the environment UUID to the ID of
+// all sequence docs and adds new "env-uuid" and "name" fields. The
+// sequence collection is not written with transactions, so neither
+// is this step.
+func AddEnvUUIDToSequences(st *State) error {
+	env, err := st.Environment()
+	if err != nil {
+		return errors.Annotate(err, "failed to load environment")
+	}
+
+	sequences, closer := st.getCollection(sequenceC)
+	defer closer()
+
+	upgradesLogger.Debugf("adding the env uuid %q to the %s collection", env.UUID(), sequenceC)
+	var oldDocs []struct {
+		Name    string `bson:"_id"`
+		Counter int
+	}
+	err = sequences.Find(bson.D{{"env-uuid", bson.D{{"$exists", false}}}}).All(&oldDocs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, oldDoc := range oldDocs {
+		if err := st.ensureSequence(oldDoc.Name, oldDoc.Counter); err != nil {
+			return errors.Trace(err)
+		}
+		if err := sequences.RemoveId(oldDoc.Name); err != nil && err != mgo.ErrNotFound {
+			return errors.Annotatef(err, "cannot migrate %q sequence", oldDoc.Name)
+		}
+	}
+	return nil
+}
+
+// AddEnvUUIDToNetworks prepends the environment UUID to the ID of
+// all network docs and adds new "env-uuid" and "name" fields.
+func AddEnvUUIDToNetworks(st *State) error {
+	return addEnvUUIDToEntityCollection(st, networksC, "name")
+}
+
+// AddEnvUUIDToNetworkInterfaces adds the new "env-uuid" field to all
+// networkinterfaces docs.
+func AddEnvUUIDToNetworkInterfaces(st *State) error {
+	return addEnvUUIDField(st, networkInterfacesC)
+}
+
+// AddEnvUUIDToCleanups adds the new "env-uuid" field to all cleanup
+// docs.
+func AddEnvUUIDToCleanups(st *State) error {
+	return addEnvUUIDField(st, cleanupsC)
+}
+
+// addEnvUUIDField adds the "env-uuid" field to all docs in the named
+// collection that do not yet record their environment. It is used for
+// collections whose ids are already unique across environments.
+func addEnvUUIDField(st *State, collName string) error {
+	env, err := st.Environment()
+	if err != nil {
+		return errors.Annotate(err, "failed to load environment")
+	}
+
+	coll, closer := st.getCollection(collName)
+	defer closer()
+
+	upgradesLogger.Debugf("adding the env uuid %q to the %s collection", env.UUID(), collName)
+	iter := coll.Find(bson.D{{"env-uuid", bson.D{{"$exists", false}}}}).Select(bson.D{{"_id", 1}}).Iter()
+	defer iter.Close()
+	ops := []txn.Op{}
+	var doc bson.M
+	for iter.Next(&doc) {
+		ops = append(ops, txn.Op{
+			C:      collName,
+			Id:     doc["_id"],
+			Assert: bson.D{{"env-uuid", bson.D{{"$exists", false}}}},
+			Update: bson.D{{"$set", bson.D{{"env-uuid", env.UUID()}}}},
+		})
+		doc = nil
+	}
+	if err = iter.Err(); err != nil {
+		return errors.Trace(err)
+	}
+	return st.runTransaction(ops)
+}
+
+// addEnvUUIDToEntityCollection prepends the environment UUID to the ID
+// of all docs in the named collection that do not yet record their
+// environment. The old ID is kept in fieldForOldID, if specified.
 func addEnvUUIDToEntityCollection(st *State, collName, fieldForOldID string) error {
 	env, err := st.Environment()
 	if err != nil {
@@ -554,7 +705,9 @@ func addEnvUUIDToEntityCollection(st *State, collName, fieldForOldID string) err
 		// The "_id" field becomes the new "name" field.
 		oldID := doc["_id"].(string)
 		id := st.docID(oldID)
-		doc[fieldForOldID] = oldID
+		if fieldForOldID != "" {
+			doc[fieldForOldID] = oldID
+		}
 		doc["_id"] = id
 		doc["env-uuid"] = uuid
 		ops = append(ops,
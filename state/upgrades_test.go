@@ -18,6 +18,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/testing"
 )
@@ -379,6 +380,367 @@ func (s *upgradesSuite) TestAddEnvUUIDToContainerRefsIdempotent(c *gc.C) {
 	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToContainerRefs, containerRefsC)
 }
 
+func (s *upgradesSuite) TestAddEnvUUIDToSettings(c *gc.C) {
+	s.addLegacyDoc(c, settingsC, bson.M{"_id": "s#wordpress#cs:quantal/wordpress-3", "blog-title": "foo"})
+
+	err := AddEnvUUIDToSettings(s.state)
+	c.Assert(err, gc.IsNil)
+
+	coll, closer := s.state.getCollection(settingsC)
+	defer closer()
+	var doc map[string]interface{}
+	err = coll.FindId("s#wordpress#cs:quantal/wordpress-3").One(&doc)
+	c.Assert(err, gc.Equals, mgo.ErrNotFound)
+
+	settings, err := readSettings(s.state, "s#wordpress#cs:quantal/wordpress-3")
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings.Map(), gc.DeepEquals, map[string]interface{}{"blog-title": "foo"})
+
+	// Running the step again changes nothing.
+	err = AddEnvUUIDToSettings(s.state)
+	c.Assert(err, gc.IsNil)
+	settings, err = readSettings(s.state, "s#wordpress#cs:quantal/wordpress-3")
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings.Map(), gc.DeepEquals, map[string]interface{}{"blog-title": "foo"})
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToConstraints(c *gc.C) {
+	s.addLegacyDoc(c, constraintsC, bson.M{"_id": "s#wordpress", "mem": uint64(2048)})
+
+	err := AddEnvUUIDToConstraints(s.state)
+	c.Assert(err, gc.IsNil)
+
+	cons, err := readConstraints(s.state, "s#wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.MustParse("mem=2048M"))
+
+	coll, closer := s.state.getCollection(constraintsC)
+	defer closer()
+	var doc constraintsDoc
+	err = coll.FindId(s.state.docID("s#wordpress")).One(&doc)
+	c.Assert(err, gc.IsNil)
+	c.Assert(doc.EnvUUID, gc.Equals, s.state.EnvironTag().Id())
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToSettingsRefs(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToSettingsRefs, settingsrefsC,
+		bson.M{
+			"_id":      "s#wordpress#cs:quantal/wordpress-3",
+			"refcount": 1,
+		},
+		bson.M{
+			"_id":      "s#mysql#cs:quantal/mysql-1",
+			"refcount": 2,
+		},
+	)
+	defer closer()
+
+	var newDoc settingsRefsDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.RefCount, gc.Equals, 1)
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.RefCount, gc.Equals, 2)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToSettingsRefsIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToSettingsRefs, settingsrefsC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToRelations(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToRelations, relationsC,
+		bson.M{
+			"_id":  "wordpress:db mysql:server",
+			"id":   0,
+			"life": Alive,
+		},
+		bson.M{
+			"_id":  "riak:ring",
+			"id":   1,
+			"life": Dying,
+		},
+	)
+	defer closer()
+
+	var newDoc relationDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.Key, gc.Equals, "wordpress:db mysql:server")
+	c.Assert(newDoc.Id, gc.Equals, 0)
+	c.Assert(newDoc.Life, gc.Equals, Alive)
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.Key, gc.Equals, "riak:ring")
+	c.Assert(newDoc.Id, gc.Equals, 1)
+	c.Assert(newDoc.Life, gc.Equals, Dying)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToRelationsIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToRelations, relationsC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToRelationScopes(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToRelationScopes, relationScopesC,
+		bson.M{
+			"_id": "r#0#requirer#wordpress/0",
+		},
+		bson.M{
+			"_id":       "r#0#provider#mysql/0",
+			"departing": true,
+		},
+	)
+	defer closer()
+
+	var newDoc relationScopeDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.Key, gc.Equals, "r#0#requirer#wordpress/0")
+	c.Assert(newDoc.Departing, jc.IsFalse)
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.Key, gc.Equals, "r#0#provider#mysql/0")
+	c.Assert(newDoc.Departing, jc.IsTrue)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToRelationScopesIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToRelationScopes, relationScopesC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToMinUnits(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToMinUnits, minUnitsC,
+		bson.M{
+			"_id":   "wordpress",
+			"revno": 1,
+		},
+		bson.M{
+			"_id":   "mediawiki",
+			"revno": 2,
+		},
+	)
+	defer closer()
+
+	var newDoc minUnitsDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.ServiceName, gc.Equals, "wordpress")
+	c.Assert(newDoc.Revno, gc.Equals, 1)
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.ServiceName, gc.Equals, "mediawiki")
+	c.Assert(newDoc.Revno, gc.Equals, 2)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToMinUnitsIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToMinUnits, minUnitsC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToAnnotations(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToAnnotations, annotationsC,
+		bson.M{
+			"_id":         "m#0",
+			"tag":         "machine-0",
+			"annotations": bson.M{"foo": "bar"},
+		},
+		bson.M{
+			"_id":         "s#wordpress",
+			"tag":         "service-wordpress",
+			"annotations": bson.M{"arble": "baz"},
+		},
+	)
+	defer closer()
+
+	var newDoc annotatorDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.GlobalKey, gc.Equals, "m#0")
+	c.Assert(newDoc.Tag, gc.Equals, "machine-0")
+	c.Assert(newDoc.Annotations, gc.DeepEquals, map[string]string{"foo": "bar"})
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.GlobalKey, gc.Equals, "s#wordpress")
+	c.Assert(newDoc.Tag, gc.Equals, "service-wordpress")
+	c.Assert(newDoc.Annotations, gc.DeepEquals, map[string]string{"arble": "baz"})
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToAnnotationsIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToAnnotations, annotationsC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToStatuses(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToStatuses, statusesC,
+		bson.M{
+			"_id":        "m#0",
+			"status":     StatusStarted,
+			"statusinfo": "",
+		},
+		bson.M{
+			"_id":        "u#wordpress/0",
+			"status":     StatusError,
+			"statusinfo": "hook failed",
+		},
+	)
+	defer closer()
+
+	var newDoc statusDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.Status, gc.Equals, StatusStarted)
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.Status, gc.Equals, StatusError)
+	c.Assert(newDoc.StatusInfo, gc.Equals, "hook failed")
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToStatusesIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToStatuses, statusesC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToOpenPorts(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToOpenPorts, openedPortsC,
+		bson.M{
+			"_id": "m#0#net#juju-public",
+		},
+		bson.M{
+			"_id": "m#1#net#juju-public",
+		},
+	)
+	defer closer()
+
+	var newDoc portsDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.Id, gc.Equals, "m#0#net#juju-public")
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.Id, gc.Equals, "m#1#net#juju-public")
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToOpenPortsIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToOpenPorts, openedPortsC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToMeterStatus(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToMeterStatus, meterStatusC,
+		bson.M{
+			"_id":  "u#wordpress/0",
+			"code": MeterNotSet,
+		},
+		bson.M{
+			"_id":  "u#mysql/0",
+			"code": MeterRed,
+			"info": "failed",
+		},
+	)
+	defer closer()
+
+	var newDoc meterStatusDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.Code, gc.Equals, MeterNotSet)
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.Code, gc.Equals, MeterRed)
+	c.Assert(newDoc.Info, gc.Equals, "failed")
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToMeterStatusIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToMeterStatus, meterStatusC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToRequestedNetworks(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToRequestedNetworks, requestedNetworksC,
+		bson.M{
+			"_id":      "m#0",
+			"networks": []string{"net1"},
+		},
+		bson.M{
+			"_id":      "s#wordpress",
+			"networks": []string{"net1", "net2"},
+		},
+	)
+	defer closer()
+
+	var newDoc requestedNetworksDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.Networks, gc.DeepEquals, []string{"net1"})
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.Networks, gc.DeepEquals, []string{"net1", "net2"})
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToRequestedNetworksIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToRequestedNetworks, requestedNetworksC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToNetworks(c *gc.C) {
+	coll, closer, newIDs := s.checkAddEnvUUIDToCollection(c, AddEnvUUIDToNetworks, networksC,
+		bson.M{
+			"_id":        "net1",
+			"providerid": "vlan-42",
+			"cidr":       "0.1.2.0/24",
+		},
+		bson.M{
+			"_id":        "net2",
+			"providerid": "vlan-69",
+			"cidr":       "0.2.2.0/24",
+		},
+	)
+	defer closer()
+
+	var newDoc networkDoc
+	s.FindId(c, coll, newIDs[0], &newDoc)
+	c.Assert(newDoc.Name, gc.Equals, "net1")
+	c.Assert(newDoc.CIDR, gc.Equals, "0.1.2.0/24")
+
+	s.FindId(c, coll, newIDs[1], &newDoc)
+	c.Assert(newDoc.Name, gc.Equals, "net2")
+	c.Assert(newDoc.CIDR, gc.Equals, "0.2.2.0/24")
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToNetworksIdempotent(c *gc.C) {
+	s.checkAddEnvUUIDToCollectionIdempotent(c, AddEnvUUIDToNetworks, networksC)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToSequences(c *gc.C) {
+	sequences, closer := s.state.getCollection(sequenceC)
+	defer closer()
+	err := sequences.Insert(
+		bson.M{"_id": "machine", "counter": 3},
+		bson.M{"_id": "storage", "counter": 1},
+	)
+	c.Assert(err, gc.IsNil)
+
+	err = AddEnvUUIDToSequences(s.state)
+	c.Assert(err, gc.IsNil)
+	// Running the step again changes nothing.
+	err = AddEnvUUIDToSequences(s.state)
+	c.Assert(err, gc.IsNil)
+
+	count, err := sequences.Find(bson.D{{"env-uuid", bson.D{{"$exists", false}}}}).Count()
+	c.Assert(err, gc.IsNil)
+	c.Assert(count, gc.Equals, 0)
+
+	next, err := s.state.sequence("machine")
+	c.Assert(err, gc.IsNil)
+	c.Assert(next, gc.Equals, 3)
+	next, err = s.state.sequence("storage")
+	c.Assert(err, gc.IsNil)
+	c.Assert(next, gc.Equals, 1)
+}
+
+func (s *upgradesSuite) TestAddEnvUUIDToCleanups(c *gc.C) {
+	id := bson.NewObjectId()
+	s.addLegacyDoc(c, cleanupsC, bson.M{"_id": id.Hex(), "kind": "units", "prefix": "wordpress/"})
+
+	err := AddEnvUUIDToCleanups(s.state)
+	c.Assert(err, gc.IsNil)
+	// Running the step again changes nothing.
+	err = AddEnvUUIDToCleanups(s.state)
+	c.Assert(err, gc.IsNil)
+
+	cleanups, closer := s.state.getCollection(cleanupsC)
+	defer closer()
+	var docs []map[string]string
+	err = cleanups.Find(nil).All(&docs)
+	c.Assert(err, gc.IsNil)
+	c.Assert(docs, gc.HasLen, 1)
+	c.Assert(docs[0]["env-uuid"], gc.Equals, s.state.EnvironTag().Id())
+	c.Assert(docs[0]["prefix"], gc.Equals, "wordpress/")
+}
+
 func (s *upgradesSuite) checkAddEnvUUIDToCollection(
 	c *gc.C,
 	upgradeStep func(*State) error,
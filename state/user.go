@@ -329,6 +329,57 @@ func (u *User) setDeactivated(value bool) error {
 	return nil
 }
 
+// Remove removes the user from the database, along with its access
+// to all environments. The owner of the state server environment
+// cannot be removed.
+func (u *User) Remove() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove user %q", u.Name())
+	environment, err := u.st.StateServerEnvironment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if u.doc.Name == environment.Owner().Name() {
+		return errors.Unauthorizedf("cannot remove state server environment owner")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if exists, err := u.st.checkUserExists(u.Name()); err != nil {
+				return nil, errors.Trace(err)
+			} else if !exists {
+				return nil, errors.NotFoundf("user %q", u.Name())
+			}
+		}
+		ops := []txn.Op{{
+			C:      usersC,
+			Id:     u.Name(),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}
+		envUsers, closer := u.st.getCollection(envUsersC)
+		defer closer()
+		var docs []envUserDoc
+		query := bson.D{{"user", u.UserTag().Username()}}
+		if err := envUsers.Find(query).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, doc := range docs {
+			ops = append(ops, txn.Op{
+				C:      envUsersC,
+				Id:     doc.ID,
+				Remove: true,
+			})
+		}
+		// The user's API tokens must not outlive it, or they would
+		// log in as any user later made with the same name.
+		tokenOps, err := removeAPITokensOps(u.st, u.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, tokenOps...), nil
+	}
+	return u.st.run(buildTxn)
+}
+
 // IsDisabled returns whether the user is currently enabled.
 func (u *User) IsDisabled() bool {
 	// Yes, this is a cached value, but in practice the user object is
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -200,6 +201,29 @@ func (s *UserSuite) TestCantDisableAdmin(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cannot disable state server environment owner")
 }
 
+func (s *UserSuite) TestRemove(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, gc.IsNil)
+
+	err = user.Remove()
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = user.Remove()
+	c.Assert(err, gc.ErrorMatches, `cannot remove user "bob": user "bob" not found`)
+}
+
+func (s *UserSuite) TestCantRemoveAdmin(c *gc.C) {
+	user, err := s.State.User(s.owner)
+	c.Assert(err, gc.IsNil)
+	err = user.Remove()
+	c.Assert(err, gc.ErrorMatches, `cannot remove user "admin": cannot remove state server environment owner`)
+}
+
 func (s *UserSuite) TestAllUsers(c *gc.C) {
 	// Create in non-alphabetical order.
 	s.factory.MakeUser(c, &factory.UserParams{Name: "conrad"})
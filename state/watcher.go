@@ -154,6 +154,20 @@ type lifecycleWatcher struct {
 	life map[string]Life
 }
 
+// envFilter returns a watcher filter that accepts the ids of documents
+// belonging to st's environment for which filter, if not nil, also
+// returns true.
+func envFilter(st *State, filter func(interface{}) bool) func(interface{}) bool {
+	prefix := st.docID("")
+	return func(id interface{}) bool {
+		docID, ok := id.(string)
+		if !ok || !strings.HasPrefix(docID, prefix) {
+			return false
+		}
+		return filter == nil || filter(id)
+	}
+}
+
 func collFactory(st *State, collName string) func() (*mgo.Collection, func()) {
 	return func() (*mgo.Collection, func()) {
 		return st.getCollection(collName)
@@ -183,8 +197,8 @@ func (s *Service) WatchRelations() StringsWatcher {
 	members := bson.D{{"endpoints.servicename", s.doc.Name}}
 	prefix := s.doc.Name + ":"
 	infix := " " + prefix
-	filter := func(key interface{}) bool {
-		k := key.(string)
+	filter := func(docID interface{}) bool {
+		k := s.st.localID(docID.(string))
 		return strings.HasPrefix(k, prefix) || strings.Contains(k, infix)
 	}
 	return newLifecycleWatcher(s.st, relationsC, members, filter)
@@ -226,13 +240,17 @@ func (m *Machine) containersWatcher(isChildRegexp string) StringsWatcher {
 	return newLifecycleWatcher(m.st, machinesC, members, filter)
 }
 
+// newLifecycleWatcher returns a watcher of the lifecycles of the
+// documents in the named collection that belong to st's environment
+// and match members. Changes to documents are only considered when
+// filter, if not nil, accepts their ids.
 func newLifecycleWatcher(st *State, collName string, members bson.D, filter func(key interface{}) bool) StringsWatcher {
 	w := &lifecycleWatcher{
 		commonWatcher: commonWatcher{st: st},
 		coll:          collFactory(st, collName),
 		collName:      collName,
-		members:       members,
-		filter:        filter,
+		members:       append(bson.D{{"env-uuid", st.EnvironTag().Id()}}, members...),
+		filter:        envFilter(st, filter),
 		life:          make(map[string]Life),
 		out:           make(chan []string),
 	}
@@ -412,7 +430,7 @@ func (w *minUnitsWatcher) initial() (set.Strings, error) {
 	newMinUnits, closer := w.st.getCollection(minUnitsC)
 	defer closer()
 
-	iter := newMinUnits.Find(nil).Iter()
+	iter := newMinUnits.Find(bson.D{{"env-uuid", w.st.EnvironTag().Id()}}).Iter()
 	for iter.Next(&doc) {
 		w.known[doc.ServiceName] = doc.Revno
 		serviceNames.Add(doc.ServiceName)
@@ -421,7 +439,8 @@ func (w *minUnitsWatcher) initial() (set.Strings, error) {
 }
 
 func (w *minUnitsWatcher) merge(serviceNames set.Strings, change watcher.Change) error {
-	serviceName := change.Id.(string)
+	docID := change.Id.(string)
+	serviceName := w.st.localID(docID)
 	if change.Revno == -1 {
 		delete(w.known, serviceName)
 		serviceNames.Remove(serviceName)
@@ -430,7 +449,7 @@ func (w *minUnitsWatcher) merge(serviceNames set.Strings, change watcher.Change)
 	doc := minUnitsDoc{}
 	newMinUnits, closer := w.st.getCollection(minUnitsC)
 	defer closer()
-	if err := newMinUnits.FindId(serviceName).One(&doc); err != nil {
+	if err := newMinUnits.FindId(docID).One(&doc); err != nil {
 		return err
 	}
 	revno, known := w.known[serviceName]
@@ -443,7 +462,7 @@ func (w *minUnitsWatcher) merge(serviceNames set.Strings, change watcher.Change)
 
 func (w *minUnitsWatcher) loop() (err error) {
 	ch := make(chan watcher.Change)
-	w.st.watcher.WatchCollection(minUnitsC, ch)
+	w.st.watcher.WatchCollectionWithFilter(minUnitsC, ch, envFilter(w.st, nil))
 	defer w.st.watcher.UnwatchCollection(minUnitsC, ch)
 	serviceNames, err := w.initial()
 	if err != nil {
@@ -545,7 +564,7 @@ type RelationScopeWatcher struct {
 func newRelationScopeWatcher(st *State, scope, ignore string) *RelationScopeWatcher {
 	w := &RelationScopeWatcher{
 		commonWatcher: commonWatcher{st: st},
-		prefix:        scope + "#",
+		prefix:        st.docID(scope + "#"),
 		ignore:        ignore,
 		out:           make(chan *RelationScopeChange),
 	}
@@ -604,7 +623,7 @@ func (w *RelationScopeWatcher) mergeChanges(info *scopeInfo, ids map[interface{}
 			if exists {
 				existIds = append(existIds, id)
 			} else {
-				doc := &relationScopeDoc{Key: id}
+				doc := &relationScopeDoc{Key: w.st.localID(id)}
 				info.remove(doc.unitName())
 			}
 		default:
@@ -744,7 +763,7 @@ func (w *relationUnitsWatcher) mergeScope(changes *params.RelationUnitsChange, c
 			return err
 		}
 		changes.Departed = remove(changes.Departed, name)
-		w.st.watcher.Watch(settingsC, key, revno, w.updates)
+		w.st.watcher.Watch(settingsC, w.st.docID(key), revno, w.updates)
 		w.watching.Add(key)
 	}
 	for _, name := range c.Left {
@@ -753,7 +772,7 @@ func (w *relationUnitsWatcher) mergeScope(changes *params.RelationUnitsChange, c
 		if changes.Changed != nil {
 			delete(changes.Changed, name)
 		}
-		w.st.watcher.Unwatch(settingsC, key, w.updates)
+		w.st.watcher.Unwatch(settingsC, w.st.docID(key), w.updates)
 		w.watching.Remove(key)
 	}
 	return nil
@@ -773,7 +792,7 @@ func remove(strs []string, s string) []string {
 func (w *relationUnitsWatcher) finish() {
 	watcher.Stop(w.sw, &w.tomb)
 	for _, watchedValue := range w.watching.Values() {
-		w.st.watcher.Unwatch(settingsC, watchedValue, w.updates)
+		w.st.watcher.Unwatch(settingsC, w.st.docID(watchedValue), w.updates)
 	}
 	close(w.updates)
 	close(w.out)
@@ -809,7 +828,7 @@ func (w *relationUnitsWatcher) loop() (err error) {
 			if !ok {
 				logger.Warningf("ignoring bad relation scope id: %#v", c.Id)
 			}
-			setRelationUnitChangeVersion(&changes, id, c.Revno)
+			setRelationUnitChangeVersion(&changes, w.st.localID(id), c.Revno)
 			out = w.out
 		case out <- changes:
 			sentInitial = true
@@ -1140,8 +1159,8 @@ func (w *settingsWatcher) loop(key string) (err error) {
 	} else if !errors.IsNotFound(err) {
 		return err
 	}
-	w.st.watcher.Watch(settingsC, key, revno, ch)
-	defer w.st.watcher.Unwatch(settingsC, key, ch)
+	w.st.watcher.Watch(settingsC, w.st.docID(key), revno, ch)
+	defer w.st.watcher.Unwatch(settingsC, w.st.docID(key), ch)
 	out := w.out
 	if revno == -1 {
 		out = nil
@@ -1202,6 +1221,12 @@ func (e *Environment) Watch() NotifyWatcher {
 	return newEntityWatcher(e.st, environmentsC, e.doc.UUID)
 }
 
+// WatchMigrationTarget returns a watcher for observing changes to the
+// state server that the environment has been moved to.
+func (st *State) WatchMigrationTarget() NotifyWatcher {
+	return newEntityWatcher(st, environmentsC, st.environTag.Id())
+}
+
 // WatchUpgradeInfo returns a watcher for observing changes to upgrade
 // synchronisation state.
 func (st *State) WatchUpgradeInfo() NotifyWatcher {
@@ -1212,7 +1237,7 @@ func (st *State) WatchUpgradeInfo() NotifyWatcher {
 // Config to change. This differs from WatchEnvironConfig in that the watcher
 // is a NotifyWatcher that does not give content during Changes()
 func (st *State) WatchForEnvironConfigChanges() NotifyWatcher {
-	return newEntityWatcher(st, settingsC, environGlobalKey)
+	return newEntityWatcher(st, settingsC, st.docID(environGlobalKey))
 }
 
 // WatchAPIHostPorts returns a NotifyWatcher that notifies
@@ -1232,13 +1257,13 @@ func (u *Unit) WatchConfigSettings() (NotifyWatcher, error) {
 		return nil, fmt.Errorf("unit charm not set")
 	}
 	settingsKey := serviceSettingsKey(u.doc.Service, u.doc.CharmURL)
-	return newEntityWatcher(u.st, settingsC, settingsKey), nil
+	return newEntityWatcher(u.st, settingsC, u.st.docID(settingsKey)), nil
 }
 
 // WatchMeterStatus returns a watcher observing the changes to the unit's
 // meter status.
 func (u *Unit) WatchMeterStatus() NotifyWatcher {
-	return newEntityWatcher(u.st, meterStatusC, u.globalKey())
+	return newEntityWatcher(u.st, meterStatusC, u.st.docID(u.globalKey()))
 }
 
 func newEntityWatcher(st *State, collName string, key interface{}) NotifyWatcher {
@@ -1588,6 +1613,211 @@ func (w *cleanupWatcher) loop() (err error) {
 	}
 }
 
+// collectionWatcher notifies when any document in a collection is
+// added, changed or removed.
+type collectionWatcher struct {
+	commonWatcher
+	out chan struct{}
+
+	// filter, if not nil, is used to exclude events for documents
+	// that are not of interest.
+	filter func(interface{}) bool
+}
+
+var _ Watcher = (*collectionWatcher)(nil)
+
+func newCollectionWatcher(st *State, collName string) NotifyWatcher {
+	return newFilteredCollectionWatcher(st, collName, nil)
+}
+
+// newFilteredCollectionWatcher returns a watcher that notifies when any
+// document of st's environment in a collection for which filter returns
+// true is added, changed or removed.
+func newFilteredCollectionWatcher(st *State, collName string, filter func(interface{}) bool) NotifyWatcher {
+	w := &collectionWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+		filter:        envFilter(st, filter),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop(collName))
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *collectionWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *collectionWatcher) loop(collName string) error {
+	in := make(chan watcher.Change)
+
+	w.st.watcher.WatchCollectionWithFilter(collName, in, w.filter)
+	defer w.st.watcher.UnwatchCollection(collName, in)
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// agentVersionWatcher notifies when the environment configuration, and
+// thus possibly the agent-version setting, changes, or when any agent
+// version pin is set or cleared.
+type agentVersionWatcher struct {
+	commonWatcher
+	out chan struct{}
+}
+
+var _ Watcher = (*agentVersionWatcher)(nil)
+
+// WatchAgentVersion returns a NotifyWatcher that notifies when the
+// agent version desired for any agent in the environment might have
+// changed.
+func (st *State) WatchAgentVersion() NotifyWatcher {
+	w := &agentVersionWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *agentVersionWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *agentVersionWatcher) loop() error {
+	settings, closer := w.st.getCollection(settingsC)
+	settingsKey := w.st.docID(environGlobalKey)
+	txnRevno, err := getTxnRevno(settings, settingsKey)
+	closer()
+	if err != nil {
+		return err
+	}
+	in := make(chan watcher.Change)
+	w.st.watcher.Watch(settingsC, settingsKey, txnRevno, in)
+	defer w.st.watcher.Unwatch(settingsC, settingsKey, in)
+	w.st.watcher.WatchCollection(agentVersionPinsC, in)
+	defer w.st.watcher.UnwatchCollection(agentVersionPinsC, in)
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// forwardedPortsWatcher notifies when the ports that should be
+// forwarded from a host machine to its containers may have changed.
+type forwardedPortsWatcher struct {
+	commonWatcher
+	out     chan struct{}
+	filters map[string]func(interface{}) bool
+}
+
+var _ Watcher = (*forwardedPortsWatcher)(nil)
+
+// WatchForwardedPorts returns a NotifyWatcher that notifies when a
+// container of the machine is added, removed or changed, when the
+// ports opened on the machine or its containers change, or when any
+// service in the environment is changed, such as by being exposed.
+func (m *Machine) WatchForwardedPorts() NotifyWatcher {
+	st := m.st
+	hasPrefix := func(prefixes ...string) func(interface{}) bool {
+		return envFilter(st, func(id interface{}) bool {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(id.(string), prefix) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	hostId := m.doc.Id
+	w := &forwardedPortsWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan struct{}),
+		filters: map[string]func(interface{}) bool{
+			machinesC: hasPrefix(st.docID(hostId + "/")),
+			// Opened ports documents are keyed by machine id and
+			// network name; see portsGlobalKey.
+			openedPortsC: hasPrefix(
+				st.docID("m#"+hostId+"#"),
+				st.docID("m#"+hostId+"/"),
+			),
+			servicesC: envFilter(st, nil),
+		},
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *forwardedPortsWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *forwardedPortsWatcher) loop() error {
+	in := make(chan watcher.Change)
+	for collName, filter := range w.filters {
+		w.st.watcher.WatchCollectionWithFilter(collName, in, filter)
+		defer w.st.watcher.UnwatchCollection(collName, in)
+	}
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // idPrefixWatcher is a StringsWatcher that watches for changes on the
 // specified collection that match common prefixes
 type idPrefixWatcher struct {
@@ -1820,7 +2050,7 @@ func (w *machineInterfacesWatcher) Changes() <-chan struct{} {
 func (w *machineInterfacesWatcher) initial() (map[bson.ObjectId]bool, error) {
 	known := make(map[bson.ObjectId]bool)
 	doc := networkInterfaceDoc{}
-	query := bson.D{{"machineid", w.machineId}}
+	query := bson.D{{"env-uuid", w.st.EnvironTag().Id()}, {"machineid", w.machineId}}
 	fields := bson.D{{"_id", 1}, {"isdisabled", 1}}
 
 	networkInterfaces, closer := w.st.getCollection(networkInterfacesC)
@@ -1856,7 +2086,7 @@ func (w *machineInterfacesWatcher) merge(changes, initial map[bson.ObjectId]bool
 			if err != nil && err != mgo.ErrNotFound {
 				return err
 			}
-			if doc.MachineId != w.machineId {
+			if doc.EnvUUID != w.st.EnvironTag().Id() || doc.MachineId != w.machineId {
 				// Not our machine.
 				continue
 			}
@@ -1950,10 +2180,11 @@ func (w *openedPortsWatcher) Changes() <-chan []string {
 	return w.out
 }
 
-// transformId converts a global key for a ports document (e.g.
-// "m#42#n#juju-public") into a colon-separated string with the
+// transformId converts the id of a ports document, with the global
+// key "m#42#n#juju-public", into a colon-separated string with the
 // machine id and network name (e.g. "42:juju-public").
-func (w *openedPortsWatcher) transformId(globalKey string) (string, error) {
+func (w *openedPortsWatcher) transformId(docID string) (string, error) {
+	globalKey := w.st.localID(docID)
 	machineId, err := extractPortsIdPart(globalKey, machineIdPart)
 	if err != nil {
 		return "", errors.Annotatef(err, "cannot parse ports key %q", globalKey)
@@ -1971,12 +2202,13 @@ func (w *openedPortsWatcher) initial() (set.Strings, error) {
 
 	portDocs := set.NewStrings()
 	var doc portsDoc
-	iter := ports.Find(nil).Select(bson.D{{"_id", 1}, {"txn-revno", 1}}).Iter()
+	query := bson.D{{"env-uuid", w.st.EnvironTag().Id()}}
+	iter := ports.Find(query).Select(bson.D{{"_id", 1}, {"txn-revno", 1}}).Iter()
 	for iter.Next(&doc) {
 		if doc.TxnRevno != -1 {
-			w.known[doc.Id] = doc.TxnRevno
+			w.known[doc.DocID] = doc.TxnRevno
 		}
-		if changeId, err := w.transformId(doc.Id); err != nil {
+		if changeId, err := w.transformId(doc.DocID); err != nil {
 			logger.Errorf(err.Error())
 		} else {
 			portDocs.Add(changeId)
@@ -1991,7 +2223,7 @@ func (w *openedPortsWatcher) loop() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	w.st.watcher.WatchCollection(openedPortsC, in)
+	w.st.watcher.WatchCollectionWithFilter(openedPortsC, in, envFilter(w.st, nil))
 	defer w.st.watcher.UnwatchCollection(openedPortsC, in)
 
 	out := w.out
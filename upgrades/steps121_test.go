@@ -45,6 +45,21 @@ func (s *steps121Suite) TestStepsFor121a3(c *gc.C) {
 		"prepend the environment UUID to the ID of all instanceData docs",
 		"prepend the environment UUID to the ID of all containerRef docs",
 		"prepend the environment UUID to the ID of all reboot docs",
+		"prepend the environment UUID to the ID of all settings docs",
+		"prepend the environment UUID to the ID of all constraints docs",
+		"prepend the environment UUID to the ID of all settingsRefs docs",
+		"prepend the environment UUID to the ID of all relations docs",
+		"prepend the environment UUID to the ID of all relationscopes docs",
+		"prepend the environment UUID to the ID of all minUnits docs",
+		"prepend the environment UUID to the ID of all annotations docs",
+		"prepend the environment UUID to the ID of all statuses docs",
+		"prepend the environment UUID to the ID of all openPorts docs",
+		"prepend the environment UUID to the ID of all meterStatus docs",
+		"prepend the environment UUID to the ID of all requestedNetworks docs",
+		"prepend the environment UUID to the ID of all sequence docs",
+		"prepend the environment UUID to the ID of all networks docs",
+		"add the environment UUID to all networkinterfaces docs",
+		"add the environment UUID to all cleanup docs",
 	}
 	assertSteps(c, version.MustParse("1.21-alpha3"), expectedSteps)
 }
@@ -69,7 +69,7 @@ func migrateToolsStorage(st *state.State, agentConfig agent.Config) error {
 		[]simplestreams.DataSource{datasource},
 		simplestreams.CloudSpec{},
 		config.AgentStream(),
-		-1, -1, tools.Filter{})
+		-1, -1, tools.Filter{}, config.RequireSignedTools())
 	if err == tools.ErrNoMatches {
 		// No tools in provider storage: nothing to do.
 		return nil
@@ -0,0 +1,107 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package anonymize removes credentials, certificates and other secrets
+// from diagnostic output, so that it can be attached to public bug
+// reports.
+//
+// Which values are secret is not guessed here: callers say so, usually
+// from the Secret flag of the configuration schema. The only values
+// recognised as secret by their content are PEM-encoded certificates
+// and keys.
+//
+// Identifiers are replaced with placeholders derived from a salt that
+// is chosen afresh for each output, so that values that were equal
+// remain equal within one output, but cannot be recovered by hashing
+// likely values or correlated across outputs.
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// Redacted replaces secret values, which are never hashed.
+const Redacted = "REDACTED"
+
+// saltSize holds the size in bytes of an Anonymizer's salt.
+const saltSize = 32
+
+// IsSensitiveValue reports whether the value is recognisably a secret
+// regardless of its key, such as a PEM-encoded certificate or key.
+func IsSensitiveValue(value string) bool {
+	return strings.Contains(value, "-----BEGIN ")
+}
+
+// Anonymizer replaces the identifiers in one output with placeholders.
+type Anonymizer struct {
+	salt []byte
+}
+
+// New returns an Anonymizer with a new random salt.
+func New() (*Anonymizer, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("cannot generate anonymization salt: %v", err)
+	}
+	return &Anonymizer{salt}, nil
+}
+
+// Identifier returns a placeholder for a value that is not secret but
+// identifies the user's environment, such as a host name or an
+// environment UUID. The placeholder is derived from an HMAC of the
+// value keyed with the Anonymizer's salt, and is empty if the value is.
+func (a *Anonymizer) Identifier(value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(value))
+	return fmt.Sprintf("anon-%x", mac.Sum(nil)[:4])
+}
+
+// Config returns a copy of the configuration attributes with the
+// values of the keys for which secret returns true, and any other
+// recognisably secret values, replaced by Redacted. Nested maps are
+// anonymized in the same way.
+func Config(attrs map[string]interface{}, secret func(key string) bool) map[string]interface{} {
+	if attrs == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(attrs))
+	for key, value := range attrs {
+		result[key] = Value(value, secret(key))
+	}
+	return result
+}
+
+// Value returns the anonymized form of a configuration value: Redacted
+// if the value is secret or recognisably secret, and the value itself
+// otherwise. Empty values are never redacted, since they reveal
+// nothing.
+func Value(value interface{}, secret bool) interface{} {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		if secret {
+			return Redacted
+		}
+		return Config(value, func(string) bool { return false })
+	case string:
+		if value == "" {
+			return value
+		}
+		if secret || IsSensitiveValue(value) {
+			return Redacted
+		}
+		return value
+	}
+	if secret {
+		return Redacted
+	}
+	return value
+}
@@ -0,0 +1,75 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package anonymize_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/anonymize"
+)
+
+type anonymizeSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&anonymizeSuite{})
+
+func (*anonymizeSuite) TestIdentifier(c *gc.C) {
+	a, err := anonymize.New()
+	c.Assert(err, gc.IsNil)
+	c.Assert(a.Identifier(""), gc.Equals, "")
+	id := a.Identifier("juju-host-1")
+	c.Assert(id, gc.Matches, "anon-[0-9a-f]{8}")
+	c.Assert(a.Identifier("juju-host-1"), gc.Equals, id)
+	c.Assert(a.Identifier("juju-host-2"), gc.Not(gc.Equals), id)
+}
+
+func (*anonymizeSuite) TestIdentifierSalted(c *gc.C) {
+	// Another output gets different placeholders for the same
+	// values, so that they cannot be looked up.
+	a, err := anonymize.New()
+	c.Assert(err, gc.IsNil)
+	b, err := anonymize.New()
+	c.Assert(err, gc.IsNil)
+	c.Assert(a.Identifier("juju-host-1"), gc.Not(gc.Equals), b.Identifier("juju-host-1"))
+}
+
+func (*anonymizeSuite) TestConfig(c *gc.C) {
+	attrs := map[string]interface{}{
+		"name":           "sample",
+		"admin-secret":   "sekrit",
+		"ca-cert":        testing.CACert,
+		"bootstrap-host": testing.CACert,
+		"secret-key":     "",
+		"token-count":    3,
+		"access-key":     "not-marked-secret",
+		"firewall-mode":  nil,
+		"nested": map[string]interface{}{
+			"user": "bob",
+			"cert": testing.CACert,
+		},
+	}
+	secret := func(key string) bool {
+		return key == "admin-secret" || key == "secret-key" || key == "token-count"
+	}
+	c.Assert(anonymize.Config(attrs, secret), jc.DeepEquals, map[string]interface{}{
+		"name":           "sample",
+		"admin-secret":   anonymize.Redacted,
+		"ca-cert":        anonymize.Redacted,
+		"bootstrap-host": anonymize.Redacted,
+		"secret-key":     "",
+		"token-count":    anonymize.Redacted,
+		"access-key":     "not-marked-secret",
+		"firewall-mode":  nil,
+		"nested": map[string]interface{}{
+			"user": "bob",
+			"cert": anonymize.Redacted,
+		},
+	})
+	// The original attributes are unchanged.
+	c.Assert(attrs["admin-secret"], gc.Equals, "sekrit")
+	c.Assert(anonymize.Config(nil, secret), gc.IsNil)
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package entitylog provides loggers that tag each message with the
+// entity it concerns. An agent acting on behalf of several entities,
+// such as a machine agent deploying units, uses them so that debug-log
+// can pick out the messages about a single entity from the agent's
+// output.
+package entitylog
+
+import (
+	"strings"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+)
+
+// Prefix returns the structured prefix that marks a log message as
+// concerning the entity with the given tag.
+func Prefix(tag names.Tag) string {
+	return "[" + tag.String() + "]"
+}
+
+// ParsePrefix returns the entity tag held in the given prefix, which
+// is a single field of a log line. It returns false if the field is
+// not an entity prefix.
+func ParsePrefix(field string) (string, bool) {
+	if !strings.HasPrefix(field, "[") || !strings.HasSuffix(field, "]") {
+		return "", false
+	}
+	tag := field[1 : len(field)-1]
+	if _, err := names.ParseTag(tag); err != nil {
+		return "", false
+	}
+	return tag, true
+}
+
+// Logger logs messages through a loggo.Logger, prefixing each of them
+// with the tag of an entity.
+type Logger struct {
+	logger loggo.Logger
+	prefix string
+}
+
+// New returns a Logger that logs messages about the entity with the
+// given tag to the given logger.
+func New(logger loggo.Logger, tag names.Tag) Logger {
+	return Logger{
+		logger: logger,
+		prefix: Prefix(tag) + " ",
+	}
+}
+
+// logf logs the message at the given level. It is called directly by
+// the exported methods so that the location of their caller, rather
+// than that of Logger, is recorded.
+func (l Logger) logf(level loggo.Level, format string, args ...interface{}) {
+	l.logger.LogCallf(3, level, l.prefix+format, args...)
+}
+
+// Criticalf logs the message at critical level.
+func (l Logger) Criticalf(format string, args ...interface{}) {
+	l.logf(loggo.CRITICAL, format, args...)
+}
+
+// Errorf logs the message at error level.
+func (l Logger) Errorf(format string, args ...interface{}) {
+	l.logf(loggo.ERROR, format, args...)
+}
+
+// Warningf logs the message at warning level.
+func (l Logger) Warningf(format string, args ...interface{}) {
+	l.logf(loggo.WARNING, format, args...)
+}
+
+// Infof logs the message at info level.
+func (l Logger) Infof(format string, args ...interface{}) {
+	l.logf(loggo.INFO, format, args...)
+}
+
+// Debugf logs the message at debug level.
+func (l Logger) Debugf(format string, args ...interface{}) {
+	l.logf(loggo.DEBUG, format, args...)
+}
+
+// Tracef logs the message at trace level.
+func (l Logger) Tracef(format string, args ...interface{}) {
+	l.logf(loggo.TRACE, format, args...)
+}
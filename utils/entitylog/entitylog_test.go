@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package entitylog_test
+
+import (
+	"path/filepath"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/entitylog"
+)
+
+type entityLogSuite struct{}
+
+var _ = gc.Suite(&entityLogSuite{})
+
+func (*entityLogSuite) SetUpTest(c *gc.C) {
+	loggo.ResetLoggers()
+	loggo.ResetWriters()
+	err := loggo.ConfigureLoggers(`<root>=ERROR; entitylog.test=DEBUG`)
+	c.Assert(err, gc.IsNil)
+}
+
+func (*entityLogSuite) TearDownTest(c *gc.C) {
+	loggo.ResetLoggers()
+	loggo.ResetWriters()
+}
+
+func (*entityLogSuite) TestPrefix(c *gc.C) {
+	c.Assert(entitylog.Prefix(names.NewUnitTag("mysql/0")), gc.Equals, "[unit-mysql-0]")
+}
+
+func (*entityLogSuite) TestParsePrefix(c *gc.C) {
+	for i, test := range []struct {
+		field string
+		tag   string
+		ok    bool
+	}{
+		{"[unit-mysql-0]", "unit-mysql-0", true},
+		{"[machine-1-lxc-0]", "machine-1-lxc-0", true},
+		{"unit-mysql-0", "", false},
+		{"[unit-mysql-0", "", false},
+		{"[gc]", "", false},
+		{"[]", "", false},
+	} {
+		c.Logf("test %d: %q", i, test.field)
+		tag, ok := entitylog.ParsePrefix(test.field)
+		c.Check(tag, gc.Equals, test.tag)
+		c.Check(ok, gc.Equals, test.ok)
+	}
+}
+
+func (*entityLogSuite) TestLoggerPrefixesMessages(c *gc.C) {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("entity-log", &tw, loggo.DEBUG), gc.IsNil)
+
+	logger := entitylog.New(loggo.GetLogger("entitylog.test"), names.NewUnitTag("mysql/0"))
+	logger.Infof("deploying unit %q", "mysql/0")
+	logger.Debugf("hello")
+	logger.Tracef("not shown")
+
+	c.Check(tw.Log(), jc.LogMatches, []jc.SimpleMessage{
+		{loggo.INFO, `\[unit-mysql-0\] deploying unit "mysql/0"`},
+		{loggo.DEBUG, `\[unit-mysql-0\] hello`},
+	})
+	// The location recorded is that of the caller.
+	for _, entry := range tw.Log() {
+		c.Check(filepath.Base(entry.Filename), gc.Equals, "entitylog_test.go")
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package featureflag reports which development feature flags are
+// set. Flags are given as a comma-separated list in the
+// JUJU_DEV_FEATURE_FLAGS environment variable, and enable features
+// that are not yet ready for general use.
+package featureflag
+
+import (
+	"os"
+	"strings"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+// Enabled reports whether the named feature flag is set. Flag names
+// are not case sensitive.
+func Enabled(flag string) bool {
+	flag = strings.ToLower(strings.TrimSpace(flag))
+	for _, name := range strings.Split(os.Getenv(osenv.JujuFeatureFlagEnvKey), ",") {
+		if strings.ToLower(strings.TrimSpace(name)) == flag && flag != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package featureflag_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/utils/featureflag"
+)
+
+type featureFlagSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&featureFlagSuite{})
+
+func (s *featureFlagSuite) TestEnabled(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, "magic, JES ,other")
+	c.Assert(featureflag.Enabled("jes"), jc.IsTrue)
+	c.Assert(featureflag.Enabled("magic"), jc.IsTrue)
+	c.Assert(featureflag.Enabled("other"), jc.IsTrue)
+	c.Assert(featureflag.Enabled("missing"), jc.IsFalse)
+	c.Assert(featureflag.Enabled(""), jc.IsFalse)
+}
+
+func (s *featureFlagSuite) TestNoneSet(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuFeatureFlagEnvKey, "")
+	c.Assert(featureflag.Enabled("jes"), jc.IsFalse)
+}
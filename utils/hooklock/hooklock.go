@@ -0,0 +1,168 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hooklock implements the lock that the agents on a machine
+// use to isolate hook execution from other work.
+//
+// The lock is made of a gate, the "uniter-hook-execution" lock, and
+// any number of slots named after it with a numeric suffix. Hooks
+// that may run alongside others take the gate only for as long as it
+// takes to claim a free slot, and hold that slot while they run. Work
+// that must not run alongside any hook holds the gate, and waits for
+// every slot to be released before it starts; since slots are only
+// ever claimed through the gate, no hook can start until it is done.
+package hooklock
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/fslock"
+)
+
+var logger = loggo.GetLogger("juju.utils.hooklock")
+
+// Name is the name of the gate lock.
+const Name = "uniter-hook-execution"
+
+// RetryDelay is how long to wait before checking the slots again
+// when they are all held, or while waiting for them to be released.
+var RetryDelay = time.Second
+
+// Lock is a machine's hook execution lock.
+type Lock struct {
+	dir  string
+	gate *fslock.Lock
+}
+
+// New returns the hook execution lock kept in the given directory.
+func New(lockDir string) (*Lock, error) {
+	gate, err := fslock.NewLock(lockDir, Name)
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{dir: lockDir, gate: gate}, nil
+}
+
+// Lock acquires the lock exclusively, blocking until it is held and
+// no hook is running. It must be released with Unlock.
+func (l *Lock) Lock(message string) error {
+	_, err := l.LockExclusive(message, func() error { return nil })
+	return err
+}
+
+// Unlock releases the lock acquired with Lock.
+func (l *Lock) Unlock() error {
+	return l.gate.Unlock()
+}
+
+// LockExclusive acquires the lock exclusively, and returns the lock
+// that must be unlocked to release it. It calls continueFunc while it
+// waits, and gives up with its error if it returns one.
+func (l *Lock) LockExclusive(message string, continueFunc func() error) (*fslock.Lock, error) {
+	if err := l.gate.LockWithFunc(message, continueFunc); err != nil {
+		return nil, err
+	}
+	for {
+		held, err := l.slotsHeld()
+		if err == nil && held {
+			err = continueFunc()
+		}
+		if err != nil {
+			if unlockErr := l.gate.Unlock(); unlockErr != nil {
+				logger.Errorf("cannot release %s lock: %v", Name, unlockErr)
+			}
+			return nil, err
+		}
+		if !held {
+			return l.gate, nil
+		}
+		time.Sleep(RetryDelay)
+	}
+}
+
+// LockShared acquires one of width slots of the lock, and returns the
+// slot lock, which must be unlocked to release it. It calls
+// continueFunc while it waits, and gives up with its error if it
+// returns one.
+func (l *Lock) LockShared(message string, width int, continueFunc func() error) (*fslock.Lock, error) {
+	for {
+		if err := l.gate.LockWithFunc(message, continueFunc); err != nil {
+			return nil, err
+		}
+		slot, err := l.claimSlot(message, width)
+		if unlockErr := l.gate.Unlock(); unlockErr != nil {
+			logger.Errorf("cannot release %s lock: %v", Name, unlockErr)
+		}
+		if err != nil || slot != nil {
+			return slot, err
+		}
+		if err := continueFunc(); err != nil {
+			return nil, err
+		}
+		time.Sleep(RetryDelay)
+	}
+}
+
+var errSlotBusy = errors.New("hook execution slot is busy")
+
+// claimSlot returns the first of width slots that it can lock without
+// waiting, or nil if they are all held.
+func (l *Lock) claimSlot(message string, width int) (*fslock.Lock, error) {
+	busy := func() error { return errSlotBusy }
+	for n := 1; n <= width; n++ {
+		slot, err := fslock.NewLock(l.dir, fmt.Sprintf("%s-%d", Name, n))
+		if err != nil {
+			return nil, err
+		}
+		if err := slot.LockWithFunc(message, busy); err == nil {
+			return slot, nil
+		} else if err != errSlotBusy {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// slotsHeld reports whether any slot of the lock is held.
+func (l *Lock) slotsHeld() (bool, error) {
+	slots, err := l.slots()
+	if err != nil {
+		return false, err
+	}
+	for _, slot := range slots {
+		if slot.IsLocked() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (l *Lock) slots() ([]*fslock.Lock, error) {
+	paths, err := filepath.Glob(filepath.Join(l.dir, Name+"-*"))
+	if err != nil {
+		return nil, err
+	}
+	var slots []*fslock.Lock
+	for _, path := range paths {
+		slot, err := fslock.NewLock(l.dir, filepath.Base(path))
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// All returns the gate and every slot of the lock, so that locks left
+// held by an agent that died can be broken.
+func (l *Lock) All() ([]*fslock.Lock, error) {
+	slots, err := l.slots()
+	if err != nil {
+		return nil, err
+	}
+	return append([]*fslock.Lock{l.gate}, slots...), nil
+}
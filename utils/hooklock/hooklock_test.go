@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hooklock_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/fslock"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/hooklock"
+)
+
+type hookLockSuite struct {
+	testing.IsolationSuite
+	lockDir string
+	lock    *hooklock.Lock
+}
+
+var _ = gc.Suite(&hookLockSuite{})
+
+func (s *hookLockSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.PatchValue(&hooklock.RetryDelay, 10*time.Millisecond)
+	s.PatchValue(&fslock.LockWaitDelay, 10*time.Millisecond)
+	s.lockDir = c.MkDir()
+	var err error
+	s.lock, err = hooklock.New(s.lockDir)
+	c.Assert(err, gc.IsNil)
+}
+
+func noWait() error {
+	return nil
+}
+
+func (s *hookLockSuite) TestSharedUsesFreeSlot(c *gc.C) {
+	first, err := s.lock.LockShared("u/0: running hook", 2, noWait)
+	c.Assert(err, gc.IsNil)
+	defer first.Unlock()
+	second, err := s.lock.LockShared("u/1: running hook", 2, noWait)
+	c.Assert(err, gc.IsNil)
+	defer second.Unlock()
+
+	c.Assert(first, gc.Not(gc.DeepEquals), second)
+	c.Assert(first.IsLockHeld(), jc.IsTrue)
+	c.Assert(second.IsLockHeld(), jc.IsTrue)
+	c.Assert(second.Message(), gc.Equals, "u/1: running hook")
+}
+
+func (s *hookLockSuite) TestSharedWaitsForFreeSlot(c *gc.C) {
+	first, err := s.lock.LockShared("u/0: running hook", 1, noWait)
+	c.Assert(err, gc.IsNil)
+
+	errGiveUp := errors.New("give up")
+	_, err = s.lock.LockShared("u/1: running hook", 1, func() error {
+		return errGiveUp
+	})
+	c.Assert(err, gc.Equals, errGiveUp)
+
+	err = first.Unlock()
+	c.Assert(err, gc.IsNil)
+	second, err := s.lock.LockShared("u/1: running hook", 1, noWait)
+	c.Assert(err, gc.IsNil)
+	err = second.Unlock()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *hookLockSuite) TestExclusiveWaitsForSharedHolders(c *gc.C) {
+	shared, err := s.lock.LockShared("u/0: running hook", 4, noWait)
+	c.Assert(err, gc.IsNil)
+
+	locked := make(chan error, 1)
+	go func() {
+		locked <- s.lock.Lock("juju-run")
+	}()
+	select {
+	case <-locked:
+		c.Fatalf("exclusive lock acquired while a hook is running")
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	err = shared.Unlock()
+	c.Assert(err, gc.IsNil)
+	select {
+	case err := <-locked:
+		c.Assert(err, gc.IsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("exclusive lock not acquired")
+	}
+
+	// No hook can start while the lock is held exclusively.
+	errGiveUp := errors.New("give up")
+	_, err = s.lock.LockShared("u/1: running hook", 4, func() error {
+		return errGiveUp
+	})
+	c.Assert(err, gc.Equals, errGiveUp)
+
+	err = s.lock.Unlock()
+	c.Assert(err, gc.IsNil)
+	shared, err = s.lock.LockShared("u/1: running hook", 4, noWait)
+	c.Assert(err, gc.IsNil)
+	err = shared.Unlock()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *hookLockSuite) TestAll(c *gc.C) {
+	shared, err := s.lock.LockShared("u/0: running hook", 2, noWait)
+	c.Assert(err, gc.IsNil)
+	defer shared.Unlock()
+
+	locks, err := s.lock.All()
+	c.Assert(err, gc.IsNil)
+	c.Assert(locks, gc.HasLen, 2)
+	c.Assert(locks[0].IsLocked(), jc.IsFalse)
+	c.Assert(locks[1].Message(), gc.Equals, "u/0: running hook")
+}
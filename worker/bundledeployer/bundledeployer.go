@@ -0,0 +1,242 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package bundledeployer implements the state server worker that runs
+// the bundle deployments accepted by the Bundles API facade.
+package bundledeployer
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.bundledeployer")
+
+// BundleDeployer runs pending bundle deployments. A deployment that
+// fails is rolled back: its services are destroyed, along with any
+// machine added for its units. A deployment left running by a state
+// server that stopped is rolled back in the same way.
+type BundleDeployer struct {
+	st *state.State
+}
+
+// NewBundleDeployer returns a worker.Worker that runs the environment's
+// bundle deployments.
+func NewBundleDeployer(st *state.State) worker.Worker {
+	return worker.NewNotifyWorker(&BundleDeployer{st: st})
+}
+
+func (d *BundleDeployer) SetUp() (watcher.NotifyWatcher, error) {
+	return d.st.WatchBundleDeployments(), nil
+}
+
+func (d *BundleDeployer) Handle() error {
+	deployments, err := d.st.UnfinishedBundleDeployments()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, deployment := range deployments {
+		var deployErr error
+		switch deployment.Status() {
+		case state.BundleDeploymentPending:
+			if err := deployment.Start(); err != nil {
+				// The deployment was refused, and so changed nothing.
+				logger.Errorf("%v", err)
+				continue
+			}
+			logger.Infof("deploying bundle %q", deployment.Id())
+			deployErr = deploy(d.st, deployment)
+			if deployErr == nil {
+				if err := deployment.Finish(nil); err != nil {
+					return errors.Trace(err)
+				}
+				logger.Infof("deployed bundle %q", deployment.Id())
+				continue
+			}
+		case state.BundleDeploymentRunning:
+			// Deployments are only ever run by this worker, so a
+			// running one was interrupted.
+			deployErr = errors.New("deployment was interrupted")
+		}
+		logger.Errorf("cannot deploy bundle %q: %v", deployment.Id(), deployErr)
+		// If the rollback fails, the deployment is left running, so
+		// that it is rolled back again when the worker restarts.
+		if err := rollback(d.st, deployment); err != nil {
+			return errors.Annotatef(err, "cannot roll back bundle deployment %q", deployment.Id())
+		}
+		if err := deployment.Finish(deployErr); err != nil {
+			return errors.Trace(err)
+		}
+		logger.Infof("rolled back bundle %q", deployment.Id())
+	}
+	return nil
+}
+
+func (d *BundleDeployer) TearDown() error {
+	// Nothing to clean up, only state is the watcher.
+	return nil
+}
+
+// deploy makes the changes called for by the deployment's plan,
+// recording each one as it is made. Units are added last, so that no
+// machines are started if any other change fails.
+func deploy(st *state.State, deployment *state.BundleDeployment) error {
+	plan := deployment.Plan()
+	for _, spec := range plan.Services {
+		if err := deployService(st, deployment, spec); err != nil {
+			return errors.Annotatef(err, "cannot deploy service %q", spec.Name)
+		}
+	}
+	for _, endpoints := range plan.Relations {
+		id := strings.Join(endpoints, " ")
+		eps, err := st.InferEndpoints(endpoints...)
+		if err != nil {
+			return errors.Annotatef(err, "cannot add relation %q", id)
+		}
+		if _, err := st.AddRelation(eps...); err != nil {
+			return errors.Annotatef(err, "cannot add relation %q", id)
+		}
+		if err := deployment.RecordChange("add-relation", id); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, spec := range plan.Services {
+		service, err := st.Service(spec.Name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, directive := range spec.To {
+			if err := addUnits(st, deployment, service, 1, directive); err != nil {
+				return err
+			}
+		}
+		if n := spec.NumUnits - len(spec.To); n > 0 {
+			if err := addUnits(st, deployment, service, n, ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deployService deploys and, if required, exposes a single service of
+// the deployment's plan.
+func deployService(st *state.State, deployment *state.BundleDeployment, spec state.BundleService) error {
+	curl, err := charm.ParseURL(spec.CharmURL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ch, err := st.Charm(curl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var settings charm.Settings
+	if spec.ConfigYAML != "" {
+		settings, err = ch.Config().ParseSettingsYAML([]byte(spec.ConfigYAML), spec.Name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	cons, err := constraints.Parse(spec.Constraints)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	service, err := juju.DeployService(st, juju.DeployServiceParams{
+		ServiceName:    spec.Name,
+		ServiceOwner:   deployment.Owner().String(),
+		Charm:          ch,
+		ConfigSettings: settings,
+		Constraints:    cons,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := deployment.RecordChange("deploy", spec.Name); err != nil {
+		return errors.Trace(err)
+	}
+	if !spec.Expose {
+		return nil
+	}
+	if err := service.SetExposed(); err != nil {
+		return errors.Trace(err)
+	}
+	return deployment.RecordChange("expose", spec.Name)
+}
+
+func addUnits(st *state.State, deployment *state.BundleDeployment, service *state.Service, n int, directive string) error {
+	units, err := juju.AddUnits(st, service, n, directive)
+	if err != nil {
+		return errors.Annotatef(err, "cannot add units to service %q", service.Name())
+	}
+	for _, unit := range units {
+		if err := deployment.RecordChange("add-unit", unit.Name()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// rollback undoes the changes made by the deployment. Its services are
+// destroyed, which removes their relations and units, and any machine
+// that did not exist when the deployment started and that hosts one of
+// its units is force-destroyed, so that its instance is stopped.
+// Running rollback again after a partial failure is safe.
+func rollback(st *state.State, deployment *state.BundleDeployment) error {
+	existing := set.NewStrings(deployment.ExistingMachines()...)
+	doomed := make(set.Strings)
+	for _, spec := range deployment.Plan().Services {
+		service, err := st.Service(spec.Name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		units, err := service.AllUnits()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, unit := range units {
+			machineId, err := unit.AssignedMachineId()
+			if state.IsNotAssigned(err) {
+				continue
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			// A new container may have been added on a new machine;
+			// destroying the outermost new machine removes both.
+			for !existing.Contains(machineId) {
+				parentId := state.ParentId(machineId)
+				if parentId == "" || existing.Contains(parentId) {
+					doomed.Add(machineId)
+					break
+				}
+				machineId = parentId
+			}
+		}
+		if err := service.Destroy(); err != nil {
+			return errors.Annotatef(err, "cannot destroy service %q", spec.Name)
+		}
+	}
+	for _, machineId := range doomed.SortedValues() {
+		machine, err := st.Machine(machineId)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if err := machine.ForceDestroy(); err != nil {
+			return errors.Annotatef(err, "cannot destroy machine %s", machineId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundledeployer_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/bundledeployer"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type BundleDeployerSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&BundleDeployerSuite{})
+
+var _ worker.NotifyWatchHandler = (*bundledeployer.BundleDeployer)(nil)
+
+// waitFinished starts a bundle deployer and waits for the given
+// deployment to finish.
+func (s *BundleDeployerSuite) waitFinished(c *gc.C, deployment *state.BundleDeployment) {
+	d := bundledeployer.NewBundleDeployer(s.State)
+	defer func() { c.Assert(worker.Stop(d), gc.IsNil) }()
+
+	timeout := time.After(coretesting.LongWait)
+	for {
+		s.State.StartSync()
+		select {
+		case <-time.After(coretesting.ShortWait):
+			err := deployment.Refresh()
+			c.Assert(err, gc.IsNil)
+			switch deployment.Status() {
+			case state.BundleDeploymentDone, state.BundleDeploymentFailed:
+				return
+			}
+		case <-timeout:
+			c.Fatalf("timed out waiting for bundle deployment")
+		}
+	}
+}
+
+func (s *BundleDeployerSuite) TestDeploy(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	wordpress := s.AddTestingCharm(c, "wordpress")
+	mysql := s.AddTestingCharm(c, "mysql")
+	deployment, err := s.State.AddBundleDeployment(s.AdminUserTag(c), state.BundlePlan{
+		Services: []state.BundleService{{
+			Name:        "mysql",
+			CharmURL:    mysql.URL().String(),
+			Constraints: "mem=4G",
+			NumUnits:    1,
+		}, {
+			Name:       "wordpress",
+			CharmURL:   wordpress.URL().String(),
+			ConfigYAML: "wordpress:\n  blog-title: Bundled\n",
+			Expose:     true,
+			NumUnits:   2,
+			To:         []string{machine.Id()},
+		}},
+		Relations: [][]string{{"wordpress:db", "mysql:server"}},
+	})
+	c.Assert(err, gc.IsNil)
+
+	s.waitFinished(c, deployment)
+	c.Assert(deployment.Status(), gc.Equals, state.BundleDeploymentDone)
+	c.Assert(deployment.Changes(), gc.DeepEquals, []state.BundleChange{
+		{Kind: "deploy", Id: "mysql"},
+		{Kind: "deploy", Id: "wordpress"},
+		{Kind: "expose", Id: "wordpress"},
+		{Kind: "add-relation", Id: "wordpress:db mysql:server"},
+		{Kind: "add-unit", Id: "mysql/0"},
+		{Kind: "add-unit", Id: "wordpress/0"},
+		{Kind: "add-unit", Id: "wordpress/1"},
+	})
+
+	svc, err := s.State.Service("wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(svc.IsExposed(), gc.Equals, true)
+	settings, err := svc.ConfigSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings["blog-title"], gc.Equals, "Bundled")
+	rels, err := svc.Relations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, gc.IsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, gc.IsNil)
+	c.Assert(machineId, gc.Equals, machine.Id())
+
+	svc, err = s.State.Service("mysql")
+	c.Assert(err, gc.IsNil)
+	cons, err := svc.Constraints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(*cons.Mem, gc.Equals, uint64(4096))
+}
+
+func (s *BundleDeployerSuite) TestFailureRollsBack(c *gc.C) {
+	existing, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	wordpress := s.AddTestingCharm(c, "wordpress")
+	mysql := s.AddTestingCharm(c, "mysql")
+	deployment, err := s.State.AddBundleDeployment(s.AdminUserTag(c), state.BundlePlan{
+		Services: []state.BundleService{{
+			Name:     "wordpress",
+			CharmURL: wordpress.URL().String(),
+			NumUnits: 1,
+			To:       []string{"lxc:" + existing.Id()},
+		}, {
+			Name:     "mysql",
+			CharmURL: mysql.URL().String(),
+			NumUnits: 1,
+			To:       []string{"42"},
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+
+	s.waitFinished(c, deployment)
+	c.Assert(deployment.Status(), gc.Equals, state.BundleDeploymentFailed)
+	c.Assert(deployment.Error(), gc.Matches, `cannot add units to service "mysql": .*`)
+	s.assertRolledBack(c, existing)
+}
+
+func (s *BundleDeployerSuite) TestInterruptedDeploymentRolledBack(c *gc.C) {
+	existing, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	wordpress := s.AddTestingCharm(c, "wordpress")
+	deployment, err := s.State.AddBundleDeployment(s.AdminUserTag(c), state.BundlePlan{
+		Services: []state.BundleService{{
+			Name:     "wordpress",
+			CharmURL: wordpress.URL().String(),
+			NumUnits: 1,
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+
+	// Simulate a state server that stopped after deploying a unit to
+	// a new machine.
+	err = deployment.Start()
+	c.Assert(err, gc.IsNil)
+	svc := s.AddTestingService(c, "wordpress", wordpress)
+	unit, err := svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, gc.IsNil)
+
+	s.waitFinished(c, deployment)
+	c.Assert(deployment.Status(), gc.Equals, state.BundleDeploymentFailed)
+	c.Assert(deployment.Error(), gc.Equals, "deployment was interrupted")
+	s.assertRolledBack(c, existing)
+}
+
+// assertRolledBack checks that every service and every machine other
+// than the given one is being removed.
+func (s *BundleDeployerSuite) assertRolledBack(c *gc.C, existing *state.Machine) {
+	services, err := s.State.AllServices()
+	c.Assert(err, gc.IsNil)
+	for _, svc := range services {
+		c.Check(svc.Life(), gc.Not(gc.Equals), state.Alive)
+	}
+	machines, err := s.State.AllMachines()
+	c.Assert(err, gc.IsNil)
+	for _, m := range machines {
+		if m.Id() == existing.Id() {
+			c.Check(m.Life(), gc.Equals, state.Alive)
+			continue
+		}
+		c.Check(m.Life(), gc.Not(gc.Equals), state.Alive)
+	}
+}
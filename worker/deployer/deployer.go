@@ -16,6 +16,7 @@ import (
 	apideployer "github.com/juju/juju/api/deployer"
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/utils/entitylog"
 	"github.com/juju/juju/worker"
 )
 
@@ -104,7 +105,7 @@ func (d *Deployer) Handle(unitNames []string) error {
 func (d *Deployer) changed(unitName string) error {
 	unitTag := names.NewUnitTag(unitName)
 	// Determine unit life state, and whether we're responsible for it.
-	logger.Infof("checking unit %q", unitName)
+	unitLogger(unitName).Infof("checking unit %q", unitName)
 	var life params.Life
 	unit, err := d.st.Unit(unitTag)
 	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
@@ -144,7 +145,7 @@ func (d *Deployer) deploy(unit *apideployer.Unit) error {
 	if d.deployed.Contains(unit.Name()) {
 		panic("must not re-deploy a deployed unit")
 	}
-	logger.Infof("deploying unit %q", unitName)
+	unitLogger(unitName).Infof("deploying unit %q", unitName)
 	initialPassword, err := utils.RandomPassword()
 	if err != nil {
 		return err
@@ -165,7 +166,7 @@ func (d *Deployer) recall(unitName string) error {
 	if !d.deployed.Contains(unitName) {
 		panic("must not recall a unit that is not deployed")
 	}
-	logger.Infof("recalling unit %q", unitName)
+	unitLogger(unitName).Infof("recalling unit %q", unitName)
 	if err := d.ctx.RecallUnit(unitName); err != nil {
 		return err
 	}
@@ -182,10 +183,17 @@ func (d *Deployer) remove(unit *apideployer.Unit) error {
 	} else if unit.Life() == params.Alive {
 		panic("must not remove an Alive unit")
 	}
-	logger.Infof("removing unit %q", unitName)
+	unitLogger(unitName).Infof("removing unit %q", unitName)
 	return unit.Remove()
 }
 
+// unitLogger returns a logger that tags its messages with the named
+// unit, so that they can be told apart from those about the other
+// units deployed on the machine.
+func unitLogger(unitName string) entitylog.Logger {
+	return entitylog.New(logger, names.NewUnitTag(unitName))
+}
+
 func (d *Deployer) TearDown() error {
 	// Nothing to do here.
 	return nil
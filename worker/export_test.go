@@ -4,6 +4,8 @@
 package worker
 
 import (
+	"time"
+
 	"github.com/juju/juju/state/watcher"
 )
 
@@ -26,3 +28,7 @@ func SetEnsureErr(f func(watcher.Errer) error) {
 func EnsureErr() func(watcher.Errer) error {
 	return ensureErr
 }
+
+func RestartPolicyDelay(policy RestartPolicy, failures int) time.Duration {
+	return policy.delay(failures)
+}
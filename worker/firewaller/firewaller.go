@@ -40,6 +40,8 @@ type Firewaller struct {
 	exposedChange   chan *exposedChange
 	globalMode      bool
 	globalPortRef   map[network.PortRange]int
+	migrator        environs.InstanceFirewallMigrator
+	migrating       bool
 	machinePorts    map[names.MachineTag]machineRanges
 }
 
@@ -89,6 +91,21 @@ func NewFirewaller(st *apifirewaller.State) (_ worker.Worker, err error) {
 	case config.FwGlobal:
 		fw.globalMode = true
 		fw.globalPortRef = make(map[network.PortRange]int)
+	case config.FwInstance:
+		// A migration from global mode may have been interrupted by
+		// a restart or a failure; if so, it is finished as machines
+		// are started and reconciled.
+		if migrator, ok := fw.environ.(environs.InstanceFirewallMigrator); ok {
+			exists, err := migrator.HasGlobalFirewall()
+			if err != nil {
+				return nil, errors.Annotate(err, "cannot check for global firewall")
+			}
+			if exists {
+				logger.Infof("resuming migration from global to instance firewall mode")
+				fw.migrator = migrator
+				fw.migrating = true
+			}
+		}
 	case config.FwNone:
 		logger.Warningf("stopping firewaller - firewall-mode is %q", config.FwNone)
 		return nil, errors.Errorf("firewaller is disabled when firewall-mode is %q", config.FwNone)
@@ -124,6 +141,10 @@ func (fw *Firewaller) loop() error {
 			}
 			if err := fw.environ.SetConfig(config); err != nil {
 				logger.Errorf("loaded invalid environment configuration: %v", err)
+			} else if fw.globalMode && config.FirewallMode() == instanceMode {
+				if err := fw.migrateToInstanceMode(reconciled); err != nil {
+					return errors.Annotate(err, "cannot migrate to instance firewall mode")
+				}
 			}
 		case change, ok := <-fw.machinesWatcher.Changes():
 			if !ok {
@@ -143,6 +164,11 @@ func (fw *Firewaller) loop() error {
 				if err != nil {
 					return err
 				}
+				if fw.migrating {
+					if err := fw.finishMigration(); err != nil {
+						return errors.Annotate(err, "cannot migrate to instance firewall mode")
+					}
+				}
 			}
 		case change, ok := <-portsChange:
 			if !ok {
@@ -163,6 +189,7 @@ func (fw *Firewaller) loop() error {
 			}
 		case change := <-fw.exposedChange:
 			change.serviced.exposed = change.exposed
+			change.serviced.unexposedPorts = change.unexposedPorts
 			unitds := []*unitData{}
 			for _, unitd := range change.serviced.unitds {
 				unitds = append(unitds, unitd)
@@ -201,6 +228,11 @@ func (fw *Firewaller) startMachine(tag names.MachineTag) error {
 		if !ok {
 			return watcher.EnsureErr(unitw)
 		}
+		if fw.migrating {
+			if _, _, err := fw.ensureInstanceFirewall(machined); err != nil {
+				return errors.Annotate(err, "cannot migrate to instance firewall mode")
+			}
+		}
 		fw.machineds[tag] = machined
 		err = fw.unitsChanged(&unitsChange{machined, change})
 		if err != nil {
@@ -272,14 +304,19 @@ func (fw *Firewaller) startService(service *apifirewaller.Service) error {
 	if err != nil {
 		return err
 	}
+	unexposedPorts, err := service.UnexposedPorts()
+	if err != nil {
+		return err
+	}
 	serviced := &serviceData{
-		fw:      fw,
-		service: service,
-		exposed: exposed,
-		unitds:  make(map[names.UnitTag]*unitData),
+		fw:             fw,
+		service:        service,
+		exposed:        exposed,
+		unexposedPorts: unexposedPorts,
+		unitds:         make(map[names.UnitTag]*unitData),
 	}
 	fw.serviceds[service.Tag()] = serviced
-	go serviced.watchLoop(serviced.exposed)
+	go serviced.watchLoop(serviced.exposed, serviced.unexposedPorts)
 	return nil
 }
 
@@ -299,7 +336,7 @@ func (fw *Firewaller) reconcileGlobal() error {
 				delete(machined.unitds, unitTag)
 				continue
 			}
-			if unitd.serviced.exposed {
+			if unitd.serviced.isExposed(portRange) {
 				collector[portRange] = true
 			}
 		}
@@ -383,6 +420,102 @@ func (fw *Firewaller) reconcileInstances() error {
 	return nil
 }
 
+// instanceMode holds config.FwInstance under a name that is not
+// shadowed by the environment config in loop.
+const instanceMode = config.FwInstance
+
+// migrateToInstanceMode switches the firewaller from global to
+// instance mode after the environment's firewall-mode has been changed.
+// Every provisioned machine is given its own firewall holding the ports
+// it has open, and the global firewall is removed once no instance
+// depends on it any more. If the machines have not been reconciled yet,
+// they are migrated as they are started instead.
+func (fw *Firewaller) migrateToInstanceMode(reconciled bool) error {
+	migrator, ok := fw.environ.(environs.InstanceFirewallMigrator)
+	if !ok {
+		return errors.NotSupportedf("changing firewall-mode on environment %q", fw.environ.Config().Name())
+	}
+	logger.Infof("migrating from global to instance firewall mode")
+	fw.globalMode = false
+	fw.globalPortRef = nil
+	fw.migrator = migrator
+	fw.migrating = true
+	if !reconciled {
+		return nil
+	}
+	for _, machined := range fw.machineds {
+		instanceId, ok, err := fw.ensureInstanceFirewall(machined)
+		if err != nil {
+			return err
+		}
+		if !ok || len(machined.openedPorts) == 0 {
+			continue
+		}
+		instances, err := fw.environ.Instances([]instance.Id{instanceId})
+		if err != nil {
+			return err
+		}
+		logger.Infof("opening instance port ranges %v for %q", machined.openedPorts, machined.tag)
+		if err := instances[0].OpenPorts(machined.tag.Id(), machined.openedPorts); err != nil {
+			return err
+		}
+	}
+	return fw.finishMigration()
+}
+
+// ensureInstanceFirewall gives the machine's instance a firewall of its
+// own, and returns the instance's id. It returns false if the machine
+// has been removed or has not been provisioned yet.
+func (fw *Firewaller) ensureInstanceFirewall(machined *machineData) (instance.Id, bool, error) {
+	instanceId, ok, err := fw.instanceId(machined)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	if err := fw.migrator.EnsureInstanceFirewall(machined.tag.Id(), instanceId); err != nil {
+		return "", false, errors.Annotatef(err, "cannot create firewall for %q", machined.tag)
+	}
+	return instanceId, true, nil
+}
+
+// finishMigration removes the global firewall, once every machine's
+// instance has a firewall of its own.
+func (fw *Firewaller) finishMigration() error {
+	var ids []instance.Id
+	for _, machined := range fw.machineds {
+		instanceId, ok, err := fw.instanceId(machined)
+		if err != nil {
+			return err
+		}
+		if ok {
+			ids = append(ids, instanceId)
+		}
+	}
+	if err := fw.migrator.RemoveGlobalFirewall(ids); err != nil {
+		return errors.Annotate(err, "cannot remove global firewall")
+	}
+	fw.migrating = false
+	logger.Infof("migrated to instance firewall mode")
+	return nil
+}
+
+// instanceId returns the id of the machine's instance. It returns false
+// if the machine has been removed or has not been provisioned yet.
+func (fw *Firewaller) instanceId(machined *machineData) (instance.Id, bool, error) {
+	m, err := machined.machine()
+	if params.IsCodeNotFound(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	instanceId, err := m.InstanceId()
+	if params.IsCodeNotProvisioned(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	return instanceId, true, nil
+}
+
 // unitsChanged responds to changes to the assigned units.
 func (fw *Firewaller) unitsChanged(change *unitsChange) error {
 	changed := []*unitData{}
@@ -506,7 +639,7 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 			delete(machined.unitds, unitTag)
 			continue
 		}
-		if unitd.serviced.exposed {
+		if unitd.serviced.isExposed(portRange) {
 			want = append(want, portRange)
 		}
 	}
@@ -762,23 +895,41 @@ type unitData struct {
 	machined *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific service.
+// exposedChange contains the changed exposed flag and unexposed
+// ports for one specific service.
 type exposedChange struct {
-	serviced *serviceData
-	exposed  bool
+	serviced       *serviceData
+	exposed        bool
+	unexposedPorts []network.PortRange
 }
 
 // serviceData holds service details and watches exposure changes.
 type serviceData struct {
-	tomb    tomb.Tomb
-	fw      *Firewaller
-	service *apifirewaller.Service
-	exposed bool
-	unitds  map[names.UnitTag]*unitData
+	tomb           tomb.Tomb
+	fw             *Firewaller
+	service        *apifirewaller.Service
+	exposed        bool
+	unexposedPorts []network.PortRange
+	unitds         map[names.UnitTag]*unitData
+}
+
+// isExposed reports whether the given port range of the service's
+// units should be open to the outside world.
+func (sd *serviceData) isExposed(portRange network.PortRange) bool {
+	if !sd.exposed {
+		return false
+	}
+	for _, unexposed := range sd.unexposedPorts {
+		if portRange.ConflictsWith(unexposed) {
+			return false
+		}
+	}
+	return true
 }
 
-// watchLoop watches the service's exposed flag for changes.
-func (sd *serviceData) watchLoop(exposed bool) {
+// watchLoop watches the service's exposed flag and unexposed ports
+// for changes.
+func (sd *serviceData) watchLoop(exposed bool, unexposedPorts []network.PortRange) {
 	defer sd.tomb.Done()
 	w, err := sd.service.Watch()
 	if err != nil {
@@ -806,12 +957,17 @@ func (sd *serviceData) watchLoop(exposed bool) {
 				sd.fw.tomb.Kill(err)
 				return
 			}
-			if change == exposed {
+			portsChange, err := sd.service.UnexposedPorts()
+			if err != nil {
+				sd.fw.tomb.Kill(err)
+				return
+			}
+			if change == exposed && samePortRanges(portsChange, unexposedPorts) {
 				continue
 			}
-			exposed = change
+			exposed, unexposedPorts = change, portsChange
 			select {
-			case sd.fw.exposedChange <- &exposedChange{sd, change}:
+			case sd.fw.exposedChange <- &exposedChange{sd, change, portsChange}:
 			case <-sd.tomb.Dying():
 				return
 			}
@@ -839,6 +995,12 @@ next:
 	return
 }
 
+// samePortRanges reports whether A and B hold the same port ranges,
+// regardless of order.
+func samePortRanges(A, B []network.PortRange) bool {
+	return len(diffRanges(A, B)) == 0 && len(diffRanges(B, A)) == 0
+}
+
 // parsePortsKey parses a ports document global key coming from the
 // ports watcher (e.g. "42:juju-public") and returns the machine and
 // network tags from its components (in the last example "machine-42"
@@ -401,6 +401,37 @@ func (s *InstanceModeSuite) TestSetClearExposedService(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestUnexposeServicePorts(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposed()
+	c.Assert(err, gc.IsNil)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, gc.IsNil)
+	err = u.OpenPort("tcp", 8080)
+	c.Assert(err, gc.IsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}})
+
+	// UnexposePorts closes only the given ports.
+	err = svc.UnexposePorts([]network.PortRange{{8080, 8080, "tcp"}})
+	c.Assert(err, gc.IsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+
+	// SetExposed opens all the ports again.
+	err = svc.SetExposed()
+	c.Assert(err, gc.IsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}, {8080, 8080, "tcp"}})
+}
+
 func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, gc.IsNil)
@@ -783,6 +814,67 @@ func (s *GlobalModeSuite) TestRestartPortCount(c *gc.C) {
 	s.assertEnvironPorts(c, nil)
 }
 
+func (s *GlobalModeSuite) TestMigrateToInstanceMode(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposed()
+	c.Assert(err, gc.IsNil)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPorts("tcp", 80, 90)
+	c.Assert(err, gc.IsNil)
+
+	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp"}})
+
+	// Switching to instance mode moves the ports onto the instance
+	// and removes the global firewall.
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"firewall-mode": config.FwInstance}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	s.assertEnvironPorts(c, nil)
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 90, "tcp"}})
+
+	// Further changes are made on the instance.
+	err = u.OpenPort("tcp", 8080)
+	c.Assert(err, gc.IsNil)
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 90, "tcp"}, {8080, 8080, "tcp"}})
+}
+
+func (s *GlobalModeSuite) TestMigrationResumedAfterRestart(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, gc.IsNil)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposed()
+	c.Assert(err, gc.IsNil)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPorts("tcp", 80, 90)
+	c.Assert(err, gc.IsNil)
+
+	s.assertEnvironPorts(c, []network.PortRange{{80, 90, "tcp"}})
+
+	// The firewall-mode is changed while the firewaller is not
+	// running, so it never sees the change happen.
+	err = worker.Stop(fw)
+	c.Assert(err, gc.IsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"firewall-mode": config.FwInstance}, nil, nil)
+	c.Assert(err, gc.IsNil)
+
+	// Once restarted, it finds the global firewall left behind and
+	// finishes the migration.
+	fw, err = firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	s.assertEnvironPorts(c, nil)
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 90, "tcp"}})
+}
+
 type NoneModeSuite struct {
 	firewallerBaseSuite
 }
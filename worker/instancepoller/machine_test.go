@@ -109,11 +109,25 @@ func (s *machineSuite) TestLongPollIntervalWhenHasAllInstanceInfo(c *gc.C) {
 	c.Assert(count, jc.GreaterThan, 2)
 }
 
+func (s *machineSuite) TestConfiguredLongPollInterval(c *gc.C) {
+	s.PatchValue(&ShortPoll, coretesting.LongWait)
+	s.PatchValue(&LongPoll, coretesting.LongWait)
+	context := &testMachineContext{longPoll: 1 * time.Millisecond}
+	count := countContextPolls(c, context, testAddrs, "i1234", "running", state.StatusStarted)
+	c.Assert(count, jc.GreaterThan, 2)
+}
+
 // countPolls sets up a machine loop with the given
 // addresses and status to be returned from getInstanceInfo,
 // waits for coretesting.ShortWait, and returns the
 // number of times the instance is polled.
 func countPolls(c *gc.C, addrs []network.Address, instId, instStatus string, machineStatus state.Status) int {
+	return countContextPolls(c, &testMachineContext{}, addrs, instId, instStatus, machineStatus)
+}
+
+// countContextPolls is like countPolls, but runs the machine
+// loop with the given context.
+func countContextPolls(c *gc.C, context *testMachineContext, addrs []network.Address, instId, instStatus string, machineStatus state.Status) int {
 	count := int32(0)
 	getInstanceInfo := func(id instance.Id) (instanceInfo, error) {
 		c.Check(string(id), gc.Equals, instId)
@@ -123,10 +137,8 @@ func countPolls(c *gc.C, addrs []network.Address, instId, instStatus string, mac
 		}
 		return instanceInfo{addrs, instStatus}, nil
 	}
-	context := &testMachineContext{
-		getInstanceInfo: getInstanceInfo,
-		dyingc:          make(chan struct{}),
-	}
+	context.getInstanceInfo = getInstanceInfo
+	context.dyingc = make(chan struct{})
 	m := &testMachine{
 		id:         "99",
 		instanceId: instance.Id(instId),
@@ -302,6 +314,7 @@ func instanceInfoGetter(
 type testMachineContext struct {
 	killAllErr      error
 	getInstanceInfo func(instance.Id) (instanceInfo, error)
+	longPoll        time.Duration
 	dyingc          chan struct{}
 }
 
@@ -316,6 +329,13 @@ func (context *testMachineContext) instanceInfo(id instance.Id) (instanceInfo, e
 	return context.getInstanceInfo(id)
 }
 
+func (context *testMachineContext) longPollInterval() time.Duration {
+	if context.longPoll != 0 {
+		return context.longPoll
+	}
+	return LongPoll
+}
+
 func (context *testMachineContext) dying() <-chan struct{} {
 	return context.dyingc
 }
@@ -24,7 +24,8 @@ var logger = loggo.GetLogger("juju.worker.instanceupdater")
 // with an exponent of ShortPollBackoff until a maximum(ish) of LongPoll.
 //
 // When a machine has an address and is started LongPoll will be used to
-// check that the instance address or status has not changed.
+// check that the instance address or status has not changed, unless the
+// environment's instance-poll-interval setting says otherwise.
 var (
 	ShortPoll        = 1 * time.Second
 	ShortPollBackoff = 2.0
@@ -53,6 +54,7 @@ type instanceInfo struct {
 type machineContext interface {
 	killAll(err error)
 	instanceInfo(id instance.Id) (instanceInfo, error)
+	longPollInterval() time.Duration
 	dying() <-chan struct{}
 }
 
@@ -202,8 +204,8 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 			}
 			if len(instInfo.addresses) > 0 && instInfo.status != "" && machineStatus == state.StatusStarted {
 				// We've got at least one address and a status and instance is started, so poll infrequently.
-				pollInterval = LongPoll
-			} else if pollInterval < LongPoll {
+				pollInterval = context.longPollInterval()
+			} else if longPoll := context.longPollInterval(); pollInterval < longPoll {
 				// We have no addresses or not started - poll increasingly rarely
 				// until we do.
 				pollInterval = time.Duration(float64(pollInterval) * ShortPollBackoff)
@@ -4,6 +4,8 @@
 package instancepoller
 
 import (
+	"time"
+
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/state"
@@ -65,6 +67,15 @@ func (u *updaterWorker) getMachine(id string) (machine, error) {
 	return u.st.Machine(id)
 }
 
+// longPollInterval returns the interval configured in the environment
+// for polling started machines, falling back to LongPoll.
+func (u *updaterWorker) longPollInterval() time.Duration {
+	if interval, ok := u.observer.Environ().Config().InstancePollInterval(); ok {
+		return interval
+	}
+	return LongPoll
+}
+
 func (u *updaterWorker) dying() <-chan struct{} {
 	return u.tomb.Dying()
 }
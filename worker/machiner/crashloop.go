@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machiner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/machiner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+// crashLoopPeriod is how often the agent's workers are checked for
+// crash loops.
+var crashLoopPeriod = time.Minute
+
+// crashLoopFailures is the number of consecutive failures after which
+// a worker is reported as crash-looping.
+const crashLoopFailures = 3
+
+// NewCrashLoopReporter returns a worker that periodically checks the
+// workers reported on by the given reporter, and records any that keep
+// failing in the machine's status info, so that a crash-looping worker
+// shows up in status rather than silently restarting.
+func NewCrashLoopReporter(st *machiner.State, agentConfig agent.Config, reporter worker.Reporter) worker.Worker {
+	tag := agentConfig.Tag().(names.MachineTag)
+	var machine *machiner.Machine
+	var lastInfo string
+	check := func(stop <-chan struct{}) error {
+		info := crashLoopInfo(reporter.Report())
+		if info == "" && lastInfo == "" {
+			return nil
+		}
+		if machine == nil {
+			m, err := st.Machine(tag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			machine = m
+		}
+		// The status is set again while workers are crash-looping,
+		// as the machiner resets it whenever it restarts.
+		if err := machine.SetStatus(params.StatusStarted, info, nil); err != nil {
+			return errors.Annotate(err, "cannot report crash-looping workers")
+		}
+		if info != lastInfo {
+			if info != "" {
+				logger.Warningf("%s", info)
+			}
+			lastInfo = info
+		}
+		return nil
+	}
+	return worker.NewPeriodicWorker(check, crashLoopPeriod)
+}
+
+// crashLoopInfo returns a description of the crash-looping workers in
+// the given reports, or "" if there are none. Nested workers are named
+// by their path, such as "api/uniter".
+func crashLoopInfo(reports map[string]worker.WorkerReport) string {
+	var looping []string
+	var collect func(prefix string, reports map[string]worker.WorkerReport)
+	collect = func(prefix string, reports map[string]worker.WorkerReport) {
+		for id, report := range reports {
+			name := prefix + id
+			if report.Failures >= crashLoopFailures {
+				looping = append(looping, fmt.Sprintf("%s (%d failures: %s)", name, report.Failures, report.LastError))
+			}
+			collect(name+"/", report.Workers)
+		}
+	}
+	collect("", reports)
+	if len(looping) == 0 {
+		return ""
+	}
+	sort.Strings(looping)
+	return "workers restarting repeatedly: " + strings.Join(looping, ", ")
+}
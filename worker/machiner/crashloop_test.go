@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machiner_test
+
+import (
+	"sync"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/machiner"
+)
+
+type CrashLoopSuite struct{}
+
+var _ = gc.Suite(&CrashLoopSuite{})
+
+func (*CrashLoopSuite) TestCrashLoopInfo(c *gc.C) {
+	c.Assert(machiner.CrashLoopInfo(nil), gc.Equals, "")
+	reports := map[string]worker.WorkerReport{
+		"api": {
+			State: "started",
+			Workers: map[string]worker.WorkerReport{
+				"uniter":     {State: "starting", Restarts: 7, Failures: 4, LastError: "boom"},
+				"firewaller": {State: "started", Restarts: 9, Failures: 1, LastError: "bang"},
+			},
+		},
+		"termination": {State: "started"},
+	}
+	c.Assert(machiner.CrashLoopInfo(reports), gc.Equals,
+		"workers restarting repeatedly: api/uniter (4 failures: boom)")
+
+	reports["termination"] = worker.WorkerReport{State: "starting", Restarts: 3, Failures: 3, LastError: "oops"}
+	c.Assert(machiner.CrashLoopInfo(reports), gc.Equals,
+		"workers restarting repeatedly: api/uniter (4 failures: boom), termination (3 failures: oops)")
+}
+
+// fakeReporter reports on a single worker.
+type fakeReporter struct {
+	mu     sync.Mutex
+	report worker.WorkerReport
+}
+
+func (r *fakeReporter) setReport(report worker.WorkerReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report = report
+}
+
+func (r *fakeReporter) Report() map[string]worker.WorkerReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return map[string]worker.WorkerReport{"deployer": r.report}
+}
+
+func (s *MachinerSuite) waitForStatusInfo(c *gc.C, expected string) {
+	timeout := time.After(worstCase)
+	for {
+		select {
+		case <-timeout:
+			c.Fatalf("timed out waiting for status info %q", expected)
+		case <-time.After(10 * time.Millisecond):
+			status, info, _, err := s.machine.Status()
+			c.Assert(err, gc.IsNil)
+			if info == expected {
+				c.Assert(status, gc.Equals, state.StatusStarted)
+				return
+			}
+		}
+	}
+}
+
+func (s *MachinerSuite) TestCrashLoopReporter(c *gc.C) {
+	s.PatchValue(machiner.CrashLoopPeriod, 10*time.Millisecond)
+	reporter := &fakeReporter{report: worker.WorkerReport{State: "started"}}
+	cfg := &utilizationConfig{tag: s.apiMachine.Tag(), dataDir: c.MkDir()}
+	w := machiner.NewCrashLoopReporter(s.machinerState, cfg, reporter)
+	defer worker.Stop(w)
+
+	reporter.setReport(worker.WorkerReport{State: "starting", Restarts: 5, Failures: 5, LastError: "boom"})
+	s.waitForStatusInfo(c, "workers restarting repeatedly: deployer (5 failures: boom)")
+
+	reporter.setReport(worker.WorkerReport{State: "started", Restarts: 5})
+	s.waitForStatusInfo(c, "")
+}
@@ -0,0 +1,13 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build windows
+
+package machiner
+
+import "github.com/juju/errors"
+
+// diskFree is not implemented on windows.
+func diskFree(path string) (uint64, error) {
+	return 0, errors.NotImplementedf("disk free space")
+}
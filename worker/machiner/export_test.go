@@ -4,3 +4,16 @@
 package machiner
 
 var InterfaceAddrs = &interfaceAddrs
+
+var (
+	UtilizationPeriod = &utilizationPeriod
+	LoadAvgFile       = &loadAvgFile
+	MemInfoFile       = &memInfoFile
+	ReadLoadAverage   = readLoadAverage
+	ReadMemInfo       = readMemInfo
+)
+
+var (
+	CrashLoopPeriod = &crashLoopPeriod
+	CrashLoopInfo   = crashLoopInfo
+)
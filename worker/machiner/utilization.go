@@ -0,0 +1,148 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machiner
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/machiner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+// utilizationPeriod is how often the resource usage of the host is
+// reported.
+var utilizationPeriod = 5 * time.Minute
+
+var (
+	loadAvgFile = "/proc/loadavg"
+	memInfoFile = "/proc/meminfo"
+)
+
+// hostUtilization holds the resource usage of the host. Memory and
+// disk sizes are in megabytes.
+type hostUtilization struct {
+	loadAverage float64
+	memoryUsed  uint64
+	memoryTotal uint64
+	diskFree    uint64
+}
+
+// NewUtilizationReporter returns a worker that periodically records
+// the load average, memory usage and free space on the filesystem
+// holding the agent's data directory, so that hosts running short of
+// resources show up in status before their agents fail.
+func NewUtilizationReporter(st *machiner.State, agentConfig agent.Config) worker.Worker {
+	tag := agentConfig.Tag().(names.MachineTag)
+	dataDir := agentConfig.DataDir()
+	var machine *machiner.Machine
+	var unsupported bool
+	report := func(stop <-chan struct{}) error {
+		if unsupported {
+			return nil
+		}
+		if machine == nil {
+			m, err := st.Machine(tag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			machine = m
+		}
+		u, err := readHostUtilization(dataDir)
+		if err != nil {
+			logger.Warningf("cannot determine host utilization: %v", err)
+			return nil
+		}
+		err = machine.SetUtilization(u.loadAverage, u.memoryUsed, u.memoryTotal, u.diskFree)
+		if params.IsCodeNotImplemented(err) {
+			logger.Infof("API server does not record host utilization")
+			unsupported = true
+			return nil
+		}
+		return errors.Annotate(err, "cannot report host utilization")
+	}
+	return worker.NewPeriodicWorker(report, utilizationPeriod)
+}
+
+func readHostUtilization(dataDir string) (hostUtilization, error) {
+	var u hostUtilization
+	var err error
+	if u.loadAverage, err = readLoadAverage(loadAvgFile); err != nil {
+		return u, err
+	}
+	if u.memoryUsed, u.memoryTotal, err = readMemInfo(memInfoFile); err != nil {
+		return u, err
+	}
+	if u.diskFree, err = diskFree(dataDir); err != nil {
+		return u, errors.Annotatef(err, "cannot determine free space for %q", dataDir)
+	}
+	return u, nil
+}
+
+// readLoadAverage returns the one minute load average from a file in
+// the format of /proc/loadavg.
+func readLoadAverage(path string) (float64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, errors.Errorf("cannot parse %s", path)
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, errors.Annotatef(err, "cannot parse %s", path)
+	}
+	return load, nil
+}
+
+// readMemInfo returns the used and total memory, in megabytes, from a
+// file in the format of /proc/meminfo. Memory used for buffers and
+// the page cache is not counted as used.
+func readMemInfo(path string) (used, total uint64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	defer f.Close()
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = kb
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	total, ok := values["MemTotal"]
+	if !ok {
+		return 0, 0, errors.Errorf("cannot find MemTotal in %s", path)
+	}
+	available, ok := values["MemAvailable"]
+	if !ok {
+		// Older kernels do not report MemAvailable.
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	if available > total {
+		available = total
+	}
+	return (total - available) / 1024, total / 1024, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machiner_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/machiner"
+)
+
+type UtilizationSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&UtilizationSuite{})
+
+const memInfo = `
+MemTotal:        2048000 kB
+MemFree:          102400 kB
+MemAvailable:     512000 kB
+Buffers:          204800 kB
+Cached:           409600 kB
+`
+
+func writeFile(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "file")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, gc.IsNil)
+	return path
+}
+
+func (s *UtilizationSuite) TestReadLoadAverage(c *gc.C) {
+	load, err := machiner.ReadLoadAverage(writeFile(c, "0.52 0.58 0.59 1/467 12345\n"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(load, gc.Equals, 0.52)
+
+	_, err = machiner.ReadLoadAverage(writeFile(c, "bogus\n"))
+	c.Assert(err, gc.ErrorMatches, "cannot parse .*: .*")
+}
+
+func (s *UtilizationSuite) TestReadMemInfo(c *gc.C) {
+	used, total, err := machiner.ReadMemInfo(writeFile(c, memInfo))
+	c.Assert(err, gc.IsNil)
+	c.Assert(total, gc.Equals, uint64(2000))
+	c.Assert(used, gc.Equals, uint64(1500))
+}
+
+func (s *UtilizationSuite) TestReadMemInfoWithoutMemAvailable(c *gc.C) {
+	content := `
+MemTotal:        2048000 kB
+MemFree:          102400 kB
+Buffers:          204800 kB
+Cached:           409600 kB
+`
+	used, total, err := machiner.ReadMemInfo(writeFile(c, content))
+	c.Assert(err, gc.IsNil)
+	c.Assert(total, gc.Equals, uint64(2000))
+	c.Assert(used, gc.Equals, uint64(1300))
+}
+
+func (s *UtilizationSuite) TestReadMemInfoWithoutMemTotal(c *gc.C) {
+	_, _, err := machiner.ReadMemInfo(writeFile(c, "MemFree: 1024 kB\n"))
+	c.Assert(err, gc.ErrorMatches, "cannot find MemTotal in .*")
+}
+
+type utilizationConfig struct {
+	agent.Config
+	tag     names.Tag
+	dataDir string
+}
+
+func (cfg *utilizationConfig) Tag() names.Tag {
+	return cfg.tag
+}
+
+func (cfg *utilizationConfig) DataDir() string {
+	return cfg.dataDir
+}
+
+func (s *MachinerSuite) TestUtilizationReporter(c *gc.C) {
+	s.PatchValue(machiner.UtilizationPeriod, 10*time.Millisecond)
+	s.PatchValue(machiner.LoadAvgFile, writeFile(c, "1.50 0.58 0.59 1/467 12345\n"))
+	s.PatchValue(machiner.MemInfoFile, writeFile(c, memInfo))
+	cfg := &utilizationConfig{tag: s.apiMachine.Tag(), dataDir: c.MkDir()}
+	w := machiner.NewUtilizationReporter(s.machinerState, cfg)
+	defer worker.Stop(w)
+
+	timeout := time.After(worstCase)
+	for {
+		select {
+		case <-timeout:
+			c.Fatalf("timed out waiting for utilization to be reported")
+		case <-time.After(10 * time.Millisecond):
+			u, err := s.machine.Utilization()
+			if errors.IsNotFound(err) {
+				continue
+			}
+			c.Assert(err, gc.IsNil)
+			c.Assert(u.LoadAverage, gc.Equals, 1.5)
+			c.Assert(u.MemoryUsed, gc.Equals, uint64(1500))
+			c.Assert(u.MemoryTotal, gc.Equals, uint64(2000))
+			c.Assert(u.DiskFree > 0, gc.Equals, true)
+			return
+		}
+	}
+}
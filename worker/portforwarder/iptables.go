@@ -0,0 +1,92 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package portforwarder
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+// chainName is the iptables nat chain holding the port forwarding
+// rules managed by juju.
+const chainName = "juju-port-forward"
+
+// runIptables runs iptables with the given arguments. It is a
+// variable so that it can be replaced for testing.
+var runIptables = func(args ...string) error {
+	output, err := exec.Command("iptables", args...).CombinedOutput()
+	if err != nil {
+		return errors.Errorf("iptables %s: %v (%s)",
+			strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// sshPort is the port on which the host's ssh server listens. It is
+// always reserved by the iptables Forwarder.
+var sshPort = network.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"}
+
+// NewIptablesForwarder returns a Forwarder that forwards ports with
+// DNAT rules in a dedicated iptables chain. Only traffic addressed to
+// the host itself is forwarded. The host's ssh port, and the given
+// reserved port ranges, which the host itself listens on, are never
+// forwarded; asking to forward them is an error.
+func NewIptablesForwarder(reserved ...network.PortRange) Forwarder {
+	return iptablesForwarder{
+		reserved: append([]network.PortRange{sshPort}, reserved...),
+	}
+}
+
+type iptablesForwarder struct {
+	reserved []network.PortRange
+}
+
+// SetForwards implements Forwarder.SetForwards.
+func (f iptablesForwarder) SetForwards(forwards []params.PortForward) error {
+	for _, forward := range forwards {
+		portRange := network.PortRange{
+			FromPort: forward.FromPort,
+			ToPort:   forward.ToPort,
+			Protocol: forward.Protocol,
+		}
+		for _, reserved := range f.reserved {
+			if reserved.ConflictsWith(portRange) {
+				return errors.Errorf(
+					"cannot forward %v to container %s: conflicts with %v on the host",
+					portRange, forward.ContainerId, reserved,
+				)
+			}
+		}
+	}
+	// Creating the chain fails if it already exists, which is fine.
+	runIptables("-t", "nat", "-N", chainName)
+	jump := []string{"PREROUTING", "-m", "addrtype", "--dst-type", "LOCAL", "-j", chainName}
+	if err := runIptables(append([]string{"-t", "nat", "-C"}, jump...)...); err != nil {
+		if err := runIptables(append([]string{"-t", "nat", "-A"}, jump...)...); err != nil {
+			return errors.Annotate(err, "cannot add port forwarding chain")
+		}
+	}
+	if err := runIptables("-t", "nat", "-F", chainName); err != nil {
+		return errors.Annotate(err, "cannot flush port forwarding rules")
+	}
+	for _, forward := range forwards {
+		err := runIptables(
+			"-t", "nat", "-A", chainName,
+			"-p", forward.Protocol,
+			"--dport", fmt.Sprintf("%d:%d", forward.FromPort, forward.ToPort),
+			"-j", "DNAT",
+			"--to-destination", fmt.Sprintf("%s:%d-%d", forward.Address, forward.FromPort, forward.ToPort),
+		)
+		if err != nil {
+			return errors.Annotate(err, "cannot add port forwarding rule")
+		}
+	}
+	return nil
+}
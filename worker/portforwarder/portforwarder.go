@@ -0,0 +1,129 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package portforwarder
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.portforwarder")
+
+// ForwardsGetter is implemented by the PortForwarder API facade.
+type ForwardsGetter interface {
+	WatchForwardedPorts(hostTag names.MachineTag) (watcher.NotifyWatcher, error)
+	ForwardedPorts(hostTag names.MachineTag) ([]params.PortForward, error)
+}
+
+// Forwarder applies port forwarding rules on the host machine.
+type Forwarder interface {
+	// SetForwards replaces any existing port forwarding rules
+	// with the given ones.
+	SetForwards(forwards []params.PortForward) error
+}
+
+// PortForwarder forwards the ports opened by units of exposed services
+// in the containers of a host machine.
+type PortForwarder struct {
+	getter    ForwardsGetter
+	hostTag   names.MachineTag
+	forwarder Forwarder
+	current   []params.PortForward
+	applied   bool
+}
+
+// NewPortForwarder returns a worker that forwards the ports opened by
+// units of exposed services in the containers of the given host machine
+// from the host to the containers, so that the services are reachable
+// through the host's addresses. The forwards are updated whenever the
+// host's containers, their opened ports, or the services change.
+func NewPortForwarder(getter ForwardsGetter, hostTag names.MachineTag, forwarder Forwarder) worker.Worker {
+	return worker.NewNotifyWorker(&PortForwarder{
+		getter:    getter,
+		hostTag:   hostTag,
+		forwarder: forwarder,
+	})
+}
+
+func (p *PortForwarder) SetUp() (watcher.NotifyWatcher, error) {
+	return p.getter.WatchForwardedPorts(p.hostTag)
+}
+
+func (p *PortForwarder) Handle() error {
+	forwards, err := p.getter.ForwardedPorts(p.hostTag)
+	if err != nil {
+		return err
+	}
+	forwards = selectForwards(forwards)
+	if p.applied && reflect.DeepEqual(forwards, p.current) {
+		return nil
+	}
+	logger.Infof("forwarding %d port ranges to containers of %v", len(forwards), p.hostTag)
+	if err := p.forwarder.SetForwards(forwards); err != nil {
+		return err
+	}
+	p.current, p.applied = forwards, true
+	return nil
+}
+
+func (p *PortForwarder) TearDown() error {
+	// Nothing to clean up; the forwards stay in place until the
+	// worker next starts.
+	return nil
+}
+
+// selectForwards returns the forwards in a stable order, dropping any
+// that overlap a port range already forwarded to another container,
+// as only one container can receive traffic for each host port.
+func selectForwards(forwards []params.PortForward) []params.PortForward {
+	sorted := make([]params.PortForward, len(forwards))
+	copy(sorted, forwards)
+	sort.Sort(byContainerAndPort(sorted))
+	var selected []params.PortForward
+	for _, forward := range sorted {
+		if conflict, ok := findConflict(selected, forward); ok {
+			if conflict != forward {
+				logger.Warningf(
+					"cannot forward %s ports %d-%d to container %s: already forwarded to container %s",
+					forward.Protocol, forward.FromPort, forward.ToPort, forward.ContainerId, conflict.ContainerId,
+				)
+			}
+			continue
+		}
+		selected = append(selected, forward)
+	}
+	return selected
+}
+
+func findConflict(forwards []params.PortForward, forward params.PortForward) (params.PortForward, bool) {
+	for _, existing := range forwards {
+		if existing.Protocol == forward.Protocol &&
+			existing.FromPort <= forward.ToPort &&
+			forward.FromPort <= existing.ToPort {
+			return existing, true
+		}
+	}
+	return params.PortForward{}, false
+}
+
+type byContainerAndPort []params.PortForward
+
+func (f byContainerAndPort) Len() int      { return len(f) }
+func (f byContainerAndPort) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+func (f byContainerAndPort) Less(i, j int) bool {
+	if f[i].ContainerId != f[j].ContainerId {
+		return f[i].ContainerId < f[j].ContainerId
+	}
+	if f[i].Protocol != f[j].Protocol {
+		return f[i].Protocol < f[j].Protocol
+	}
+	return f[i].FromPort < f[j].FromPort
+}
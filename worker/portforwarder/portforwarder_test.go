@@ -0,0 +1,204 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package portforwarder_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/portforwarder"
+)
+
+type portForwarderSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&portForwarderSuite{})
+
+var hostTag = names.NewMachineTag("1")
+
+type fakeWatcher struct {
+	changes chan struct{}
+}
+
+func (w *fakeWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *fakeWatcher) Stop() error {
+	return nil
+}
+
+func (w *fakeWatcher) Err() error {
+	return nil
+}
+
+type fakeGetter struct {
+	watcher  *fakeWatcher
+	forwards chan []params.PortForward
+}
+
+func (g *fakeGetter) WatchForwardedPorts(tag names.MachineTag) (watcher.NotifyWatcher, error) {
+	if tag != hostTag {
+		return nil, errors.New("unexpected tag")
+	}
+	return g.watcher, nil
+}
+
+func (g *fakeGetter) ForwardedPorts(tag names.MachineTag) ([]params.PortForward, error) {
+	if tag != hostTag {
+		return nil, errors.New("unexpected tag")
+	}
+	select {
+	case forwards := <-g.forwards:
+		return forwards, nil
+	case <-time.After(coretesting.LongWait):
+		return nil, errors.New("no forwards requested")
+	}
+}
+
+type fakeForwarder struct {
+	applied chan []params.PortForward
+}
+
+func (f *fakeForwarder) SetForwards(forwards []params.PortForward) error {
+	f.applied <- forwards
+	return nil
+}
+
+func (s *portForwarderSuite) TestForwardsChanges(c *gc.C) {
+	getter := &fakeGetter{
+		watcher:  &fakeWatcher{make(chan struct{})},
+		forwards: make(chan []params.PortForward),
+	}
+	forwarder := &fakeForwarder{make(chan []params.PortForward, 10)}
+	w := portforwarder.NewPortForwarder(getter, hostTag, forwarder)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), gc.IsNil)
+	}()
+
+	http := params.PortForward{"1/lxc/0", "10.0.3.5", "tcp", 80, 80}
+	https := params.PortForward{"1/lxc/0", "10.0.3.5", "tcp", 443, 443}
+	conflicting := params.PortForward{"1/lxc/1", "10.0.3.6", "tcp", 80, 90}
+
+	// Each change reported by the watcher causes the forwards
+	// to be read again.
+	sendForwards := func(forwards ...params.PortForward) {
+		select {
+		case getter.watcher.changes <- struct{}{}:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("change not read")
+		}
+		select {
+		case getter.forwards <- forwards:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("forwards not requested")
+		}
+	}
+	assertApplied := func(expect ...params.PortForward) {
+		select {
+		case forwards := <-forwarder.applied:
+			c.Assert(forwards, gc.DeepEquals, expect)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("forwards not applied")
+		}
+	}
+	assertNotApplied := func() {
+		select {
+		case forwards := <-forwarder.applied:
+			c.Fatalf("unexpected forwards applied: %v", forwards)
+		case <-time.After(coretesting.ShortWait):
+		}
+	}
+
+	// The first set of forwards is always applied, even if empty.
+	sendForwards()
+	assertApplied()
+
+	// Unchanged forwards are not reapplied.
+	sendForwards()
+	assertNotApplied()
+
+	// Forwards are applied in a stable order, and ranges conflicting
+	// with ones already forwarded to another container are dropped.
+	sendForwards(https, conflicting, http)
+	assertApplied(http, https)
+	sendForwards(http, conflicting, https)
+	assertNotApplied()
+}
+
+type iptablesSuite struct {
+	coretesting.BaseSuite
+	calls [][]string
+}
+
+var _ = gc.Suite(&iptablesSuite{})
+
+func (s *iptablesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.calls = nil
+	s.PatchValue(portforwarder.RunIptables, func(args ...string) error {
+		s.calls = append(s.calls, args)
+		if args[2] == "-N" || args[2] == "-C" {
+			return errors.New("exists or not found")
+		}
+		return nil
+	})
+}
+
+func (s *iptablesSuite) TestSetForwards(c *gc.C) {
+	forwarder := portforwarder.NewIptablesForwarder()
+	err := forwarder.SetForwards([]params.PortForward{
+		{"1/lxc/0", "10.0.3.5", "tcp", 80, 80},
+		{"1/lxc/1", "10.0.3.6", "udp", 5000, 5010},
+	})
+	c.Assert(err, gc.IsNil)
+	chain := portforwarder.ChainName
+	c.Assert(s.calls, gc.DeepEquals, [][]string{
+		{"-t", "nat", "-N", chain},
+		{"-t", "nat", "-C", "PREROUTING", "-m", "addrtype", "--dst-type", "LOCAL", "-j", chain},
+		{"-t", "nat", "-A", "PREROUTING", "-m", "addrtype", "--dst-type", "LOCAL", "-j", chain},
+		{"-t", "nat", "-F", chain},
+		{"-t", "nat", "-A", chain, "-p", "tcp", "--dport", "80:80", "-j", "DNAT", "--to-destination", "10.0.3.5:80-80"},
+		{"-t", "nat", "-A", chain, "-p", "udp", "--dport", "5000:5010", "-j", "DNAT", "--to-destination", "10.0.3.6:5000-5010"},
+	})
+}
+
+func (s *iptablesSuite) TestSetForwardsRefusesHostPorts(c *gc.C) {
+	forwarder := portforwarder.NewIptablesForwarder(network.PortRange{
+		FromPort: 17070,
+		ToPort:   17070,
+		Protocol: "tcp",
+	})
+	err := forwarder.SetForwards([]params.PortForward{
+		{"1/lxc/0", "10.0.3.5", "tcp", 20, 30},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot forward 20-30/tcp to container 1/lxc/0: conflicts with 22/tcp on the host`)
+	err = forwarder.SetForwards([]params.PortForward{
+		{"1/lxc/0", "10.0.3.5", "udp", 22, 22},
+		{"1/lxc/0", "10.0.3.5", "tcp", 17000, 18000},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot forward 17000-18000/tcp to container 1/lxc/0: conflicts with 17070/tcp on the host`)
+	// No rules are changed when a forward is refused.
+	c.Assert(s.calls, gc.HasLen, 0)
+}
+
+func (s *iptablesSuite) TestSetForwardsError(c *gc.C) {
+	s.PatchValue(portforwarder.RunIptables, func(args ...string) error {
+		if args[2] == "-F" {
+			return errors.New("permission denied")
+		}
+		return nil
+	})
+	err := portforwarder.NewIptablesForwarder().SetForwards(nil)
+	c.Assert(err, gc.ErrorMatches, "cannot flush port forwarding rules: permission denied")
+}
@@ -8,7 +8,6 @@ import (
 	"sync/atomic"
 
 	"github.com/juju/errors"
-	"github.com/juju/utils/fslock"
 
 	"github.com/juju/juju/agent"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
@@ -20,6 +19,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/hooklock"
 	"github.com/juju/juju/worker"
 )
 
@@ -32,7 +32,7 @@ type ContainerSetup struct {
 	provisioner         *apiprovisioner.State
 	machine             *apiprovisioner.Machine
 	config              agent.Config
-	initLock            *fslock.Lock
+	initLock            *hooklock.Lock
 
 	// Save the workerName so the worker thread can be stopped.
 	workerName string
@@ -48,7 +48,7 @@ type ContainerSetup struct {
 // containers are created on the given machine.
 func NewContainerSetupHandler(runner worker.Runner, workerName string, supportedContainers []instance.ContainerType,
 	machine *apiprovisioner.Machine, provisioner *apiprovisioner.State,
-	config agent.Config, initLock *fslock.Lock) worker.StringsWatchHandler {
+	config agent.Config, initLock *hooklock.Lock) worker.StringsWatchHandler {
 
 	return &ContainerSetup{
 		runner:              runner,
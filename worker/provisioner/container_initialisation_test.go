@@ -11,7 +11,6 @@ import (
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/apt"
-	"github.com/juju/utils/fslock"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/agent"
@@ -21,6 +20,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/hooklock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/provisioner"
@@ -33,7 +33,7 @@ type ContainerSetupSuite struct {
 	// Record the apt commands issued as part of container initialisation
 	aptCmdChan  <-chan *exec.Cmd
 	initLockDir string
-	initLock    *fslock.Lock
+	initLock    *hooklock.Lock
 }
 
 var _ = gc.Suite(&ContainerSetupSuite{})
@@ -65,7 +65,7 @@ func (s *ContainerSetupSuite) SetUpTest(c *gc.C) {
 
 	// Create a new container initialisation lock.
 	s.initLockDir = c.MkDir()
-	initLock, err := fslock.NewLock(s.initLockDir, "container-init")
+	initLock, err := hooklock.New(s.initLockDir)
 	c.Assert(err, gc.IsNil)
 	s.initLock = initLock
 }
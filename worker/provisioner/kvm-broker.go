@@ -63,6 +63,7 @@ func (broker *kvmBroker) StartInstance(args environs.StartInstanceParams) (insta
 	series := args.Tools.OneSeries()
 	args.MachineConfig.MachineContainerType = instance.KVM
 	args.MachineConfig.Tools = args.Tools[0]
+	args.MachineConfig.Constraints = args.Constraints
 
 	config, err := broker.api.ContainerConfig()
 	if err != nil {
@@ -62,6 +62,7 @@ func (broker *lxcBroker) StartInstance(args environs.StartInstanceParams) (insta
 	series := args.Tools.OneSeries()
 	args.MachineConfig.MachineContainerType = instance.LXC
 	args.MachineConfig.Tools = args.Tools[0]
+	args.MachineConfig.Constraints = args.Constraints
 
 	config, err := broker.api.ContainerConfig()
 	if err != nil {
@@ -532,6 +532,23 @@ func (task *provisionerTask) setErrorStatus(message string, machine *apiprovisio
 	return nil
 }
 
+// Provisioning stages recorded in the "provisioning" status data of
+// pending machines, so users can tell how far provisioning has got.
+const (
+	provisioningRequested = "requesting instance"
+	provisioningStarted   = "instance started"
+)
+
+// setProvisioningStatus records the given provisioning stage in the
+// pending status of the machine. Failure to do so is logged but is
+// not fatal, as the status is informational only.
+func (task *provisionerTask) setProvisioningStatus(machine *apiprovisioner.Machine, info, stage string) {
+	data := map[string]interface{}{"provisioning": stage}
+	if err := machine.SetStatus(params.StatusPending, info, data); err != nil {
+		logger.Warningf("cannot record provisioning progress for machine %q: %v", machine, err)
+	}
+}
+
 func (task *provisionerTask) prepareNetworkAndInterfaces(networkInfo []network.Info) (
 	networks []params.Network, ifaces []params.NetworkInterface) {
 	if len(networkInfo) == 0 {
@@ -566,6 +583,7 @@ func (task *provisionerTask) startMachine(
 	startInstanceParams environs.StartInstanceParams,
 ) error {
 
+	task.setProvisioningStatus(machine, "starting instance", provisioningRequested)
 	inst, metadata, networkInfo, err := task.broker.StartInstance(startInstanceParams)
 	if err != nil {
 		// Set the state to error, so the machine will be skipped next
@@ -582,6 +600,7 @@ func (task *provisionerTask) startMachine(
 		return fmt.Errorf("cannot provision instance %v for machine %q with networks: not implemented", inst.Id(), machine)
 	} else if err == nil {
 		logger.Infof("started machine %s as instance %s with hardware %q, networks %v, interfaces %v", machine, inst.Id(), metadata, networks, ifaces)
+		task.setProvisioningStatus(machine, "waiting for machine agent", provisioningStarted)
 		return nil
 	}
 	// We need to stop the instance right away here, set error status and go on.
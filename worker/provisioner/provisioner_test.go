@@ -431,6 +431,31 @@ func (s *ProvisionerSuite) TestSimple(c *gc.C) {
 	s.waitRemoved(c, m)
 }
 
+func (s *ProvisionerSuite) TestProvisioningProgressStatus(c *gc.C) {
+	p := s.newEnvironProvisioner(c)
+	defer stop(c, p)
+
+	m, err := s.addMachine()
+	c.Assert(err, gc.IsNil)
+	s.checkStartInstance(c, m)
+
+	// Once the instance is started, the machine remains pending
+	// until its agent comes up, and its status says so.
+	t0 := time.Now()
+	for time.Since(t0) < coretesting.LongWait {
+		status, info, data, err := m.Status()
+		c.Assert(err, gc.IsNil)
+		c.Assert(status, gc.Equals, state.StatusPending)
+		if data["provisioning"] != "instance started" {
+			time.Sleep(coretesting.ShortWait)
+			continue
+		}
+		c.Assert(info, gc.Equals, "waiting for machine agent")
+		return
+	}
+	c.Fatalf("machine status does not report provisioning progress")
+}
+
 func (s *ProvisionerSuite) TestConstraints(c *gc.C) {
 	// Create a machine with non-standard constraints.
 	m, err := s.addMachine()
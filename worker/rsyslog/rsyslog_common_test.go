@@ -93,3 +93,42 @@ func (s *RsyslogSuite) TestModeForwarding(c *gc.C) {
 
 	c.Assert(*rsyslog.SyslogTargets, gc.HasLen, 2)
 }
+
+func (s *RsyslogSuite) TestModeForwardingExternalHost(c *gc.C) {
+	err := s.APIState.Client().EnvironmentSet(map[string]interface{}{
+		"syslog-host":    "logs.example.com:6514",
+		"syslog-ca-cert": coretesting.CACert,
+	})
+	c.Assert(err, gc.IsNil)
+	dialed := make(chan string, 10)
+	s.PatchValue(rsyslog.DialSyslog, func(network, raddr string, priority syslog.Priority, tag string, tlsCfg *tls.Config) (*syslog.Writer, error) {
+		dialed <- raddr + " " + tlsCfg.ServerName
+		return &syslog.Writer{}, nil
+	})
+	st, m := s.OpenAPIAsNewMachine(c, state.JobHostUnits)
+	addrs := []string{"0.1.2.3", "0.2.4.6"}
+	worker, err := rsyslog.NewRsyslogConfigWorker(st.Rsyslog(), rsyslog.RsyslogModeForwarding, m.Tag(), "", addrs)
+	c.Assert(err, gc.IsNil)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Logs are forwarded only to the external collector.
+	select {
+	case target := <-dialed:
+		c.Assert(target, gc.Equals, "logs.example.com:6514 logs.example.com")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for syslog connection")
+	}
+
+	// Changing the collector reconnects to the new one.
+	err = s.APIState.Client().EnvironmentSet(map[string]interface{}{
+		"syslog-host": "logs2.example.com:6514",
+	})
+	c.Assert(err, gc.IsNil)
+	select {
+	case target := <-dialed:
+		c.Assert(target, gc.Equals, "logs2.example.com:6514 logs2.example.com")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for syslog reconnection")
+	}
+}
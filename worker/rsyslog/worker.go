@@ -152,7 +152,7 @@ func (h *RsyslogConfigHandler) TearDown() error {
 // We explicitly set the ServerName field, this ensures that even if we are connecting
 // via an IP address and are using an old certificate (pre 1.20.9), we can still
 // successfully connect.
-func (h *RsyslogConfigHandler) composeTLS(caCert string) (*tls.Config, error) {
+func (h *RsyslogConfigHandler) composeTLS(caCert, serverName string) (*tls.Config, error) {
 	cert := x509.NewCertPool()
 	ok := cert.AppendCertsFromPEM([]byte(caCert))
 	if !ok {
@@ -160,25 +160,36 @@ func (h *RsyslogConfigHandler) composeTLS(caCert string) (*tls.Config, error) {
 	}
 	return &tls.Config{
 		RootCAs:    cert,
-		ServerName: "juju-rsyslog",
+		ServerName: serverName,
 	}, nil
 }
 
-func (h *RsyslogConfigHandler) replaceRemoteLogger(caCert string) error {
-	tlsConf, err := h.composeTLS(caCert)
-	if err != nil {
-		return err
-	}
-
-	var newLoggers []*rsyslog.Writer
-	var wrapLoggers []io.Writer
+// stateServerTargets returns the addresses of the rsyslog
+// listeners on the state servers.
+func (h *RsyslogConfigHandler) stateServerTargets() []string {
+	var targets []string
 	for _, j := range h.syslogConfig.StateServerAddresses {
 		host, _, err := net.SplitHostPort(j)
 		if err != nil {
 			// No port was found
 			host = j
 		}
-		target := fmt.Sprintf("%s:%d", host, h.syslogConfig.Port)
+		targets = append(targets, fmt.Sprintf("%s:%d", host, h.syslogConfig.Port))
+	}
+	return targets
+}
+
+// replaceRemoteLogger forwards logging to the given targets over TLS,
+// verifying their certificates against caCert and serverName.
+func (h *RsyslogConfigHandler) replaceRemoteLogger(caCert, serverName string, targets []string) error {
+	tlsConf, err := h.composeTLS(caCert, serverName)
+	if err != nil {
+		return err
+	}
+
+	var newLoggers []*rsyslog.Writer
+	var wrapLoggers []io.Writer
+	for _, target := range targets {
 		writer, err := dialSyslog("tcp", target, rsyslog.LOG_DEBUG, "juju-"+h.tag.String(), tlsConf)
 		if err != nil {
 			return err
@@ -213,6 +224,20 @@ func (h *RsyslogConfigHandler) Handle() error {
 		return errors.Annotate(err, "cannot get environ config")
 	}
 	rsyslogCACert := cfg.CACert
+	if h.mode == RsyslogModeForwarding && cfg.ExternalHost != "" {
+		// Logs go to an external collector instead of the state
+		// servers, which need not have generated their certificate.
+		host, _, err := net.SplitHostPort(cfg.ExternalHost)
+		if err != nil {
+			return errors.Annotate(err, "invalid external syslog host")
+		}
+		if err := h.replaceRemoteLogger(cfg.ExternalCACert, host, []string{cfg.ExternalHost}); err != nil {
+			return err
+		}
+		h.syslogPort = cfg.Port
+		h.rsyslogCACert = rsyslogCACert
+		return nil
+	}
 	if rsyslogCACert == "" {
 		return nil
 	}
@@ -222,7 +247,7 @@ func (h *RsyslogConfigHandler) Handle() error {
 		if err := writeFileAtomic(h.syslogConfig.CACertPath(), []byte(rsyslogCACert), 0644, 0, 0); err != nil {
 			return errors.Annotate(err, "cannot write CA certificate")
 		}
-		if err := h.replaceRemoteLogger(rsyslogCACert); err != nil {
+		if err := h.replaceRemoteLogger(rsyslogCACert, "juju-rsyslog", h.stateServerTargets()); err != nil {
 			return err
 		}
 	} else {
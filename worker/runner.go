@@ -5,6 +5,8 @@ package worker
 
 import (
 	"errors"
+	"math"
+	"math/rand"
 	"time"
 
 	"launchpad.net/tomb"
@@ -14,6 +16,66 @@ import (
 // will wait between exiting and restarting.
 var RestartDelay = 3 * time.Second
 
+// MaxRestartDelay holds the longest time that a worker failing
+// repeatedly will wait between exiting and restarting.
+var MaxRestartDelay = 5 * time.Minute
+
+// RestartPolicy determines how long a runner waits before restarting
+// a worker that exited with an error. The delay grows exponentially
+// with each consecutive failure of the worker, and is varied randomly
+// so that many workers failing together do not restart together.
+type RestartPolicy struct {
+	// Delay holds the delay before restarting a worker after its
+	// first failure.
+	Delay time.Duration
+
+	// MaxDelay holds the longest delay, before jitter is added.
+	// If it is zero, the delay is not capped.
+	MaxDelay time.Duration
+
+	// Factor holds the factor by which the delay grows with each
+	// consecutive failure. Factors below 1 are treated as 1.
+	Factor float64
+
+	// Jitter holds the largest fraction of the delay that is
+	// randomly added to it.
+	Jitter float64
+
+	// ResetAfter holds how long a worker must run before its
+	// failures are no longer counted as consecutive.
+	ResetAfter time.Duration
+}
+
+// DefaultRestartPolicy returns the policy used for workers started
+// with StartWorker. It starts at RestartDelay and doubles with each
+// consecutive failure up to MaxRestartDelay.
+func DefaultRestartPolicy() RestartPolicy {
+	return RestartPolicy{
+		Delay:      RestartDelay,
+		MaxDelay:   MaxRestartDelay,
+		Factor:     2,
+		Jitter:     0.2,
+		ResetAfter: time.Minute,
+	}
+}
+
+// delay returns how long to wait before restarting a worker that has
+// failed the given number of consecutive times.
+func (p RestartPolicy) delay(failures int) time.Duration {
+	if failures < 1 {
+		failures = 1
+	}
+	factor := math.Max(p.Factor, 1)
+	delay := float64(p.Delay) * math.Pow(factor, float64(failures-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
 // Worker is implemented by a running worker.
 type Worker interface {
 	// Kill asks the worker to stop without necessarily
@@ -28,9 +90,41 @@ type Worker interface {
 type Runner interface {
 	Worker
 	StartWorker(id string, startFunc func() (Worker, error)) error
+	StartWorkerWithPolicy(id string, policy RestartPolicy, startFunc func() (Worker, error)) error
 	StopWorker(id string) error
 }
 
+// WorkerReport describes the state of a worker started by a runner.
+type WorkerReport struct {
+	// State holds "started" while the worker is running, "starting"
+	// while it waits to be started or restarted, and "stopping"
+	// while it is being stopped.
+	State string `json:"state"`
+
+	// Restarts holds the number of times the worker has been
+	// restarted after exiting with an error, and Failures the
+	// number of those failures that were consecutive. A worker that
+	// keeps failing soon after starting is crash-looping.
+	Restarts int `json:"restarts,omitempty"`
+	Failures int `json:"failures,omitempty"`
+
+	// LastError holds the last error the worker exited with, and
+	// LastErrorTime when it did so.
+	LastError     string     `json:"last-error,omitempty"`
+	LastErrorTime *time.Time `json:"last-error-time,omitempty"`
+
+	// Workers holds the reports of the workers run by the worker,
+	// when it is itself a Reporter.
+	Workers map[string]WorkerReport `json:"workers,omitempty"`
+}
+
+// Reporter is implemented by workers that can report on the state of
+// the workers they run.
+type Reporter interface {
+	// Report returns the reports of the workers, keyed by id.
+	Report() map[string]WorkerReport
+}
+
 // runner runs a set of workers, restarting them as necessary
 // when they fail.
 type runner struct {
@@ -39,15 +133,20 @@ type runner struct {
 	stopc         chan string
 	donec         chan doneInfo
 	startedc      chan startInfo
+	reportc       chan chan map[string]workerReport
 	isFatal       func(error) bool
 	moreImportant func(err0, err1 error) bool
 }
 
-var _ Runner = (*runner)(nil)
+var (
+	_ Runner   = (*runner)(nil)
+	_ Reporter = (*runner)(nil)
+)
 
 type startReq struct {
-	id    string
-	start func() (Worker, error)
+	id     string
+	policy RestartPolicy
+	start  func() (Worker, error)
 }
 
 type startInfo struct {
@@ -76,6 +175,7 @@ func NewRunner(isFatal func(error) bool, moreImportant func(err0, err1 error) bo
 		stopc:         make(chan string),
 		donec:         make(chan doneInfo),
 		startedc:      make(chan startInfo),
+		reportc:       make(chan chan map[string]workerReport),
 		isFatal:       isFatal,
 		moreImportant: moreImportant,
 	}
@@ -97,8 +197,14 @@ var ErrDead = errors.New("worker runner is not running")
 //
 // StartWorker returns ErrDead if the runner is not running.
 func (runner *runner) StartWorker(id string, startFunc func() (Worker, error)) error {
+	return runner.StartWorkerWithPolicy(id, DefaultRestartPolicy(), startFunc)
+}
+
+// StartWorkerWithPolicy is like StartWorker, but restarts the worker
+// after it fails according to the given policy.
+func (runner *runner) StartWorkerWithPolicy(id string, policy RestartPolicy, startFunc func() (Worker, error)) error {
 	select {
-	case runner.startc <- startReq{id, startFunc}:
+	case runner.startc <- startReq{id, policy, startFunc}:
 		return nil
 	case <-runner.tomb.Dead():
 	}
@@ -127,6 +233,34 @@ func (runner *runner) Kill() {
 	runner.tomb.Kill(nil)
 }
 
+// workerReport holds the report of a worker along with the worker
+// itself, so that its own workers can be reported on.
+type workerReport struct {
+	WorkerReport
+	worker Worker
+}
+
+// Report implements Reporter. It returns nil if the runner is not
+// running.
+func (runner *runner) Report() map[string]WorkerReport {
+	reply := make(chan map[string]workerReport, 1)
+	select {
+	case runner.reportc <- reply:
+	case <-runner.tomb.Dead():
+		return nil
+	}
+	reports := make(map[string]WorkerReport)
+	for id, report := range <-reply {
+		// The workers are asked for their own reports here rather
+		// than in the run loop, so a slow worker cannot block it.
+		if reporter, ok := report.worker.(Reporter); ok {
+			report.Workers = reporter.Report()
+		}
+		reports[id] = report.WorkerReport
+	}
+	return reports
+}
+
 // Stop kills the given worker and waits for it to exit.
 func Stop(worker Worker) error {
 	worker.Kill()
@@ -134,10 +268,47 @@ func Stop(worker Worker) error {
 }
 
 type workerInfo struct {
-	start        func() (Worker, error)
-	worker       Worker
-	restartDelay time.Duration
-	stopping     bool
+	start      func() (Worker, error)
+	worker     Worker
+	policy     RestartPolicy
+	restartNow bool
+	stopping   bool
+
+	// started and startedAt record whether and when the worker was
+	// last started, and failures how many times in a row it has
+	// failed since, for the restart policy.
+	started   bool
+	startedAt time.Time
+	failures  int
+
+	// restarts, lastErr and lastErrTime are kept for reporting.
+	restarts    int
+	lastErr     error
+	lastErrTime time.Time
+}
+
+// report returns a report on the worker.
+func (info *workerInfo) report() workerReport {
+	report := workerReport{
+		WorkerReport: WorkerReport{
+			State:    "starting",
+			Restarts: info.restarts,
+			Failures: info.failures,
+		},
+		worker: info.worker,
+	}
+	switch {
+	case info.stopping:
+		report.State = "stopping"
+	case info.started:
+		report.State = "started"
+	}
+	if info.lastErr != nil {
+		lastErrTime := info.lastErrTime
+		report.LastError = info.lastErr.Error()
+		report.LastErrorTime = &lastErrTime
+	}
+	return report
 }
 
 func (runner *runner) run() error {
@@ -171,8 +342,8 @@ func (runner *runner) run() error {
 			info := workers[req.id]
 			if info == nil {
 				workers[req.id] = &workerInfo{
-					start:        req.start,
-					restartDelay: RestartDelay,
+					start:  req.start,
+					policy: req.policy,
 				}
 				go runner.runWorker(0, req.id, req.start)
 				break
@@ -186,7 +357,8 @@ func (runner *runner) run() error {
 			// does stop, we'll restart it immediately with
 			// the new start function.
 			info.start = req.start
-			info.restartDelay = 0
+			info.policy = req.policy
+			info.restartNow = true
 		case id := <-runner.stopc:
 			if info := workers[id]; info != nil {
 				killWorker(id, info)
@@ -194,11 +366,29 @@ func (runner *runner) run() error {
 		case info := <-runner.startedc:
 			workerInfo := workers[info.id]
 			workerInfo.worker = info.worker
+			workerInfo.started = true
+			workerInfo.startedAt = time.Now()
 			if isDying {
 				killWorker(info.id, workerInfo)
 			}
+		case reply := <-runner.reportc:
+			reports := make(map[string]workerReport)
+			for id, info := range workers {
+				reports[id] = info.report()
+			}
+			reply <- reports
 		case info := <-runner.donec:
 			workerInfo := workers[info.id]
+			if info.err != nil {
+				now := time.Now()
+				if workerInfo.started && now.Sub(workerInfo.startedAt) >= workerInfo.policy.ResetAfter {
+					workerInfo.failures = 0
+				}
+				workerInfo.failures++
+				workerInfo.lastErr = info.err
+				workerInfo.lastErrTime = now
+			}
+			workerInfo.started = false
 			if !workerInfo.stopping && info.err == nil {
 				delete(workers, info.id)
 				break
@@ -225,8 +415,15 @@ func (runner *runner) run() error {
 				delete(workers, info.id)
 				break
 			}
-			go runner.runWorker(workerInfo.restartDelay, info.id, workerInfo.start)
-			workerInfo.restartDelay = RestartDelay
+			delay := workerInfo.policy.delay(workerInfo.failures)
+			if workerInfo.restartNow {
+				delay = 0
+				workerInfo.restartNow = false
+				workerInfo.failures = 0
+			} else if info.err != nil {
+				workerInfo.restarts++
+			}
+			go runner.runWorker(delay, info.id, workerInfo.start)
 		}
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"launchpad.net/tomb"
 
@@ -82,6 +83,68 @@ func (*runnerSuite) TestOneWorkerRestart(c *gc.C) {
 	starter.assertStarted(c, false)
 }
 
+func (*runnerSuite) TestReport(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	starter := newTestWorkerStarter()
+	err := runner.StartWorker("id", testWorkerStart(starter))
+	c.Assert(err, gc.IsNil)
+	starter.assertStarted(c, true)
+	assertReport(c, runner, "id", "started", 0, "")
+
+	starter.die <- fmt.Errorf("an error")
+	starter.assertStarted(c, false)
+	starter.assertStarted(c, true)
+	assertReport(c, runner, "id", "started", 1, "an error")
+
+	c.Assert(worker.Stop(runner), gc.IsNil)
+	starter.assertStarted(c, false)
+	c.Assert(runner.(worker.Reporter).Report(), gc.IsNil)
+}
+
+func (*runnerSuite) TestReportNested(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	inner := worker.NewRunner(noneFatal, noImportance)
+	err := runner.StartWorker("inner", func() (worker.Worker, error) {
+		return inner, nil
+	})
+	c.Assert(err, gc.IsNil)
+	starter := newTestWorkerStarter()
+	err = inner.StartWorker("id", testWorkerStart(starter))
+	c.Assert(err, gc.IsNil)
+	starter.assertStarted(c, true)
+	assertReport(c, inner, "id", "started", 0, "")
+
+	var reports map[string]worker.WorkerReport
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		reports = runner.(worker.Reporter).Report()
+		if reports["inner"].State == "started" {
+			break
+		}
+	}
+	c.Assert(reports["inner"].Workers, gc.DeepEquals, map[string]worker.WorkerReport{
+		"id": {State: "started"},
+	})
+
+	c.Assert(worker.Stop(runner), gc.IsNil)
+	starter.assertStarted(c, false)
+}
+
+// assertReport waits for the runner to report the worker with the
+// given id in the given state, and checks its restarts and last error.
+func assertReport(c *gc.C, runner worker.Runner, id, state string, restarts int, lastError string) {
+	var report worker.WorkerReport
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		report = runner.(worker.Reporter).Report()[id]
+		if report.State == state && report.Restarts == restarts {
+			break
+		}
+	}
+	c.Assert(report.State, gc.Equals, state)
+	c.Assert(report.Restarts, gc.Equals, restarts)
+	c.Assert(report.LastError, gc.Equals, lastError)
+	c.Assert(report.LastErrorTime == nil, gc.Equals, lastError == "")
+}
+
 func (*runnerSuite) TestOneWorkerStartFatalError(c *gc.C) {
 	runner := worker.NewRunner(allFatal, noImportance)
 	starter := newTestWorkerStarter()
@@ -175,6 +238,75 @@ func (*runnerSuite) TestOneWorkerRestartDelay(c *gc.C) {
 	c.Assert(worker.Stop(runner), gc.IsNil)
 }
 
+func (*runnerSuite) TestRestartPolicyDelay(c *gc.C) {
+	policy := worker.RestartPolicy{
+		Delay:    time.Second,
+		MaxDelay: 10 * time.Second,
+		Factor:   2,
+	}
+	for failures, expected := range []time.Duration{
+		time.Second, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second,
+	} {
+		c.Check(worker.RestartPolicyDelay(policy, failures), gc.Equals, expected)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := worker.RestartPolicyDelay(policy, 3)
+		c.Assert(delay >= 4*time.Second, jc.IsTrue)
+		c.Assert(delay <= 6*time.Second, jc.IsTrue)
+	}
+
+	policy = worker.RestartPolicy{Delay: time.Second}
+	c.Check(worker.RestartPolicyDelay(policy, 5), gc.Equals, time.Second)
+}
+
+func (*runnerSuite) TestStartWorkerWithPolicy(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	starter := newTestWorkerStarter()
+	policy := worker.RestartPolicy{
+		Delay:      time.Millisecond,
+		Factor:     2,
+		ResetAfter: time.Hour,
+	}
+	err := runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, gc.IsNil)
+	starter.assertStarted(c, true)
+
+	// Consecutive failures are counted, and delay restarts
+	// increasingly.
+	for i := 0; i < 3; i++ {
+		starter.die <- fmt.Errorf("an error")
+		starter.assertStarted(c, false)
+		starter.assertStarted(c, true)
+	}
+	assertReport(c, runner, "id", "started", 3, "an error")
+	c.Assert(runner.(worker.Reporter).Report()["id"].Failures, gc.Equals, 3)
+
+	c.Assert(worker.Stop(runner), gc.IsNil)
+	starter.assertStarted(c, false)
+}
+
+func (*runnerSuite) TestFailuresResetAfterRunning(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	starter := newTestWorkerStarter()
+	policy := worker.RestartPolicy{Delay: time.Millisecond, ResetAfter: 0}
+	err := runner.StartWorkerWithPolicy("id", policy, testWorkerStart(starter))
+	c.Assert(err, gc.IsNil)
+	starter.assertStarted(c, true)
+
+	for i := 0; i < 3; i++ {
+		starter.die <- fmt.Errorf("an error")
+		starter.assertStarted(c, false)
+		starter.assertStarted(c, true)
+	}
+	assertReport(c, runner, "id", "started", 3, "an error")
+	c.Assert(runner.(worker.Reporter).Report()["id"].Failures, gc.Equals, 1)
+
+	c.Assert(worker.Stop(runner), gc.IsNil)
+	starter.assertStarted(c, false)
+}
+
 type errorLevel int
 
 func (e errorLevel) Error() string {
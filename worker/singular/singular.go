@@ -93,13 +93,17 @@ func (r *runner) pinger() {
 }
 
 func (r *runner) StartWorker(id string, startFunc func() (worker.Worker, error)) error {
+	return r.StartWorkerWithPolicy(id, worker.DefaultRestartPolicy(), startFunc)
+}
+
+func (r *runner) StartWorkerWithPolicy(id string, policy worker.RestartPolicy, startFunc func() (worker.Worker, error)) error {
 	if r.isMaster {
 		// We are master; the started workers should
 		// encounter an error as they do what they're supposed
 		// to do - we can just start the worker in the
 		// underlying runner.
 		logger.Infof("starting %q", id)
-		return r.Runner.StartWorker(id, startFunc)
+		return r.Runner.StartWorkerWithPolicy(id, policy, startFunc)
 	}
 	logger.Infof("standby %q", id)
 	// We're not master, so don't start the worker, but start a pinger so
@@ -107,7 +111,7 @@ func (r *runner) StartWorker(id string, startFunc func() (worker.Worker, error))
 	r.startPingerOnce.Do(func() {
 		go r.pinger()
 	})
-	return r.Runner.StartWorker(id, func() (worker.Worker, error) {
+	return r.Runner.StartWorkerWithPolicy(id, policy, func() (worker.Worker, error) {
 		return worker.NewSimpleWorker(r.waitPinger), nil
 	})
 }
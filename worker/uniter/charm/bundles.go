@@ -44,6 +44,10 @@ func (d *BundlesDir) Read(info BundleInfo, abort <-chan struct{}) (Bundle, error
 // download fetches the supplied charm and checks that it has the correct sha256
 // hash, then copies it into the directory. If a value is received on abort, the
 // download will be stopped.
+//
+// A charm store charm whose archive cannot be fetched from environment
+// storage is fetched through the API server's charm store proxy instead,
+// so that units without access to the charm store can still get it.
 func (d *BundlesDir) download(info BundleInfo, abort <-chan struct{}) (err error) {
 	archiveURL := info.ArchiveURL()
 	defer errors.DeferredAnnotatef(&err, "failed to download charm %q from %q", info.URL(), archiveURL)
@@ -59,12 +63,35 @@ func (d *BundlesDir) download(info BundleInfo, abort <-chan struct{}) (err error
 	// and the data transferred is not sensitive, so this
 	// does not pose a problem.
 	dl := downloader.New(aurl, dir, utils.NoVerifySSLHostnames)
+	err = d.completeDownload(info, dl, abort)
+	storeInfo, ok := info.(StoreBundleInfo)
+	if err == nil || !ok || err == errAborted {
+		return err
+	}
+	storeURL := storeInfo.StoreArchiveURL()
+	if storeURL == nil {
+		return err
+	}
+	logger.Warningf("cannot download %s from environment storage: %v", info.URL(), err)
+	logger.Infof("downloading %s from the charm store proxy at %s", info.URL(), storeURL)
+	user, password := storeInfo.ArchiveCredentials()
+	credentials := downloader.Credentials{User: user, Password: password}
+	dl = downloader.NewWithCredentials(storeURL.String(), dir, credentials, utils.NoVerifySSLHostnames)
+	return d.completeDownload(info, dl, abort)
+}
+
+// errAborted is returned when a download is aborted.
+var errAborted = fmt.Errorf("aborted")
+
+// completeDownload waits for the given download of the supplied charm
+// to finish, checks its sha256 hash, and moves it into the directory.
+func (d *BundlesDir) completeDownload(info BundleInfo, dl *downloader.Download, abort <-chan struct{}) error {
 	defer dl.Stop()
 	for {
 		select {
 		case <-abort:
 			logger.Infof("download aborted")
-			return fmt.Errorf("aborted")
+			return errAborted
 		case st := <-dl.Done():
 			if st.Err != nil {
 				return st.Err
@@ -5,6 +5,7 @@ package charm_test
 
 import (
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
@@ -103,6 +104,49 @@ func (i *mockArchiveURLCharm) ArchiveURL() *url.URL {
 	return i.archiveURL
 }
 
+type mockStoreCharm struct {
+	charm.BundleInfo
+	archiveURL *url.URL
+	storeURL   *url.URL
+}
+
+func (i *mockStoreCharm) ArchiveURL() *url.URL {
+	return i.archiveURL
+}
+
+func (i *mockStoreCharm) StoreArchiveURL() *url.URL {
+	return i.storeURL
+}
+
+func (i *mockStoreCharm) ArchiveCredentials() (string, string) {
+	return "unit-wordpress-0", "sekrit"
+}
+
+func (s *BundlesDirSuite) TestGetFromCharmStore(c *gc.C) {
+	d := charm.NewBundlesDir(filepath.Join(c.MkDir(), "bundles"))
+	apiCharm, sch, bundata := s.AddCharm(c)
+	storeURL, err := url.Parse(s.URL("/charmstore?url=cs:quantal/dummy-1"))
+	c.Assert(err, gc.IsNil)
+	info := &mockStoreCharm{apiCharm, apiCharm.ArchiveURL(), storeURL}
+
+	// The charm is missing from environment storage, so it is
+	// fetched through the charm store proxy with the unit's
+	// credentials.
+	gitjujutesting.Server.Response(404, nil, nil)
+	gitjujutesting.Server.Response(200, nil, bundata)
+	ch, err := d.Read(info, nil)
+	c.Assert(err, gc.IsNil)
+	assertCharm(c, ch, sch)
+
+	req := gitjujutesting.Server.WaitRequest()
+	c.Assert(req.URL.Path, gc.Equals, "/some/charm.bundle")
+	c.Assert(req.Header.Get("Authorization"), gc.Equals, "")
+	req = gitjujutesting.Server.WaitRequest()
+	c.Assert(req.URL.Path, gc.Equals, "/charmstore")
+	auth := base64.StdEncoding.EncodeToString([]byte("unit-wordpress-0:sekrit"))
+	c.Assert(req.Header.Get("Authorization"), gc.Equals, "Basic "+auth)
+}
+
 func (s *BundlesDirSuite) TestGet(c *gc.C) {
 	basedir := c.MkDir()
 	bunsdir := filepath.Join(basedir, "random", "bundles")
@@ -46,6 +46,22 @@ type BundleInfo interface {
 	ArchiveSha256() (string, error)
 }
 
+// StoreBundleInfo is implemented by a BundleInfo whose bundle can also
+// be fetched from the charm store, through the API server's charm store
+// proxy.
+type StoreBundleInfo interface {
+	BundleInfo
+
+	// StoreArchiveURL returns the location of the bundle data in the
+	// charm store proxy, or nil if the bundle is not a charm store
+	// charm.
+	StoreArchiveURL() *url.URL
+
+	// ArchiveCredentials returns the user and password with which to
+	// authenticate to the charm store proxy.
+	ArchiveCredentials() (user, password string)
+}
+
 // BundleReader provides a mechanism for getting a Bundle from a BundleInfo.
 type BundleReader interface {
 
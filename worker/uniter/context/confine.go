@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// hookConfinement confines a charm hook, and the processes it starts,
+// to the context's memory and CPU time limits. How it does so depends
+// on the platform: see newHookConfinement.
+type hookConfinement interface {
+	// wrap returns the command that runs the given hook command
+	// inside the confinement.
+	wrap(hookCmd []string) []string
+
+	// started is called once the hook process has been started.
+	started(ps *exec.Cmd) error
+
+	// exceeded returns a description of the limit that the exited
+	// hook process exceeded, or the empty string if it exceeded none.
+	exceeded(ps *exec.Cmd) string
+
+	// kill kills every process in the confinement.
+	kill() error
+
+	// release releases the confinement's resources once the hook
+	// process has exited. Processes left behind by the hook are not
+	// killed.
+	release()
+}
+
+// hookConfinementName returns a name for the confinement of the hook
+// run in the given context, made only of characters that are safe in
+// file names and shell commands.
+func hookConfinementName(contextId string) string {
+	return "juju-" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '-'
+	}, contextId)
+}
+
+// cpuLimitMessage and memoryLimitMessage describe the limits that a
+// hook may exceed.
+func cpuLimitMessage(limit time.Duration) string {
+	return fmt.Sprintf("used more than %v of CPU time", limit)
+}
+
+func memoryLimitMessage(limit uint64) string {
+	return fmt.Sprintf("used more than %dMB of memory", limit)
+}
@@ -0,0 +1,240 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package context
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	// cgroupRoot holds the directory under which the cgroup
+	// hierarchies are mounted.
+	cgroupRoot = "/sys/fs/cgroup"
+
+	// cgroupCPUPollInterval holds how often the CPU time used by a
+	// hook's cgroup is checked against its limit.
+	cgroupCPUPollInterval = 250 * time.Millisecond
+)
+
+// newHookConfinement returns a confinement that puts the hook, and
+// every process it starts, in a cgroup of its own, so that the limits
+// apply to all of them together. If the cgroup cannot be made, the
+// limits are applied to each process with setrlimit instead.
+func newHookConfinement(name string, memoryLimit uint64, cpuLimit time.Duration) hookConfinement {
+	cg, err := newCgroupConfinement(name, memoryLimit, cpuLimit)
+	if err != nil {
+		logger.Warningf("cannot confine hook in a cgroup, limiting each of its processes instead: %v", err)
+		return &rlimitConfinement{memoryLimit, cpuLimit}
+	}
+	return cg
+}
+
+// cgroupConfinement confines a hook to a memory cgroup, which the
+// kernel enforces, and a cpuacct cgroup, whose CPU time is polled
+// and the whole cgroup killed once it is used up.
+type cgroupConfinement struct {
+	memoryLimit uint64
+	cpuLimit    time.Duration
+	dirs        []string
+	memoryDir   string
+	cpuDir      string
+
+	mu    sync.Mutex
+	cause string
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func newCgroupConfinement(name string, memoryLimit uint64, cpuLimit time.Duration) (_ *cgroupConfinement, err error) {
+	cg := &cgroupConfinement{
+		memoryLimit: memoryLimit,
+		cpuLimit:    cpuLimit,
+	}
+	defer func() {
+		if err != nil {
+			cg.release()
+		}
+	}()
+	if memoryLimit > 0 {
+		if cg.memoryDir, err = cg.makeCgroup("memory", name); err != nil {
+			return nil, err
+		}
+		limit := strconv.FormatUint(memoryLimit*1024*1024, 10)
+		if err = writeCgroupFile(cg.memoryDir, "memory.limit_in_bytes", limit); err != nil {
+			return nil, err
+		}
+	}
+	if cpuLimit > 0 {
+		if cg.cpuDir, err = cg.makeCgroup("cpuacct", name); err != nil {
+			return nil, err
+		}
+	}
+	return cg, nil
+}
+
+// makeCgroup makes the named cgroup in the given hierarchy.
+func (cg *cgroupConfinement) makeCgroup(subsystem, name string) (string, error) {
+	hierarchy := filepath.Join(cgroupRoot, subsystem)
+	if _, err := os.Stat(filepath.Join(hierarchy, "cgroup.procs")); err != nil {
+		return "", fmt.Errorf("%s cgroup hierarchy not available: %v", subsystem, err)
+	}
+	parent := filepath.Join(hierarchy, "juju")
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(parent, name)
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return "", err
+	}
+	cg.dirs = append(cg.dirs, dir)
+	return dir, nil
+}
+
+func writeCgroupFile(dir, file, value string) error {
+	return ioutil.WriteFile(filepath.Join(dir, file), []byte(value), 0644)
+}
+
+func readCgroupFile(dir, file string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, file))
+	return strings.TrimSpace(string(data)), err
+}
+
+// wrap returns a command that moves its shell into the hook's cgroups
+// before executing the hook, so that no process started by the hook
+// escapes them.
+func (cg *cgroupConfinement) wrap(hookCmd []string) []string {
+	var joins []string
+	for _, dir := range cg.dirs {
+		joins = append(joins, fmt.Sprintf("echo $$ > '%s'", filepath.Join(dir, "cgroup.procs")))
+	}
+	script := strings.Join(joins, " && ") + ` && exec "$0" "$@"`
+	return append([]string{"/bin/sh", "-c", script}, hookCmd...)
+}
+
+func (cg *cgroupConfinement) started(ps *exec.Cmd) error {
+	if cg.cpuDir == "" {
+		return nil
+	}
+	cg.stop = make(chan struct{})
+	cg.done = make(chan struct{})
+	go cg.pollCPU()
+	return nil
+}
+
+// pollCPU kills the hook's cgroup once it has used more CPU time than
+// its limit allows.
+func (cg *cgroupConfinement) pollCPU() {
+	defer close(cg.done)
+	for {
+		select {
+		case <-cg.stop:
+			return
+		case <-time.After(cgroupCPUPollInterval):
+		}
+		usage, err := readCgroupFile(cg.cpuDir, "cpuacct.usage")
+		if err != nil {
+			logger.Warningf("cannot read CPU time used by hook: %v", err)
+			continue
+		}
+		ns, err := strconv.ParseInt(usage, 10, 64)
+		if err != nil || time.Duration(ns) <= cg.cpuLimit {
+			continue
+		}
+		cg.mu.Lock()
+		cg.cause = cpuLimitMessage(cg.cpuLimit)
+		cg.mu.Unlock()
+		if err := cg.kill(); err != nil {
+			logger.Warningf("cannot kill hook that exceeded its CPU time limit: %v", err)
+		}
+		return
+	}
+}
+
+// exceeded returns why the hook's cgroup was killed, if it was. A hook
+// whose process was killed by the kernel for using too much memory
+// usually fails without having been killed itself, so any hook that
+// hit its memory limit is reported as having exceeded it.
+func (cg *cgroupConfinement) exceeded(ps *exec.Cmd) string {
+	cg.mu.Lock()
+	cause := cg.cause
+	cg.mu.Unlock()
+	if cause != "" {
+		return cause
+	}
+	if cg.memoryDir != "" && cg.memoryLimitHit() {
+		return memoryLimitMessage(cg.memoryLimit)
+	}
+	return ""
+}
+
+// memoryLimitHit reports whether a process in the hook's memory cgroup
+// was killed for using too much memory or, on kernels that do not
+// count such kills, whether the cgroup's usage ever reached its limit.
+func (cg *cgroupConfinement) memoryLimitHit() bool {
+	if f, err := os.Open(filepath.Join(cg.memoryDir, "memory.oom_control")); err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 2 && fields[0] == "oom_kill" {
+				return fields[1] != "0"
+			}
+		}
+	}
+	failcnt, err := readCgroupFile(cg.memoryDir, "memory.failcnt")
+	return err == nil && failcnt != "0"
+}
+
+// kill kills every process in the hook's cgroups, including any that
+// have left the hook's process group.
+func (cg *cgroupConfinement) kill() error {
+	var firstErr error
+	for _, dir := range cg.dirs {
+		procs, err := readCgroupFile(dir, "cgroup.procs")
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, field := range strings.Fields(procs) {
+			pid, err := strconv.Atoi(field)
+			if err != nil {
+				continue
+			}
+			if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// release stops watching the hook's CPU time and removes its cgroups.
+// A cgroup that still holds processes left running by the hook cannot
+// be removed, and is left behind.
+func (cg *cgroupConfinement) release() {
+	if cg.stop != nil {
+		close(cg.stop)
+		<-cg.done
+		cg.stop = nil
+	}
+	for _, dir := range cg.dirs {
+		if err := os.Remove(dir); err != nil {
+			logger.Debugf("cannot remove hook cgroup: %v", err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package context_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/context"
+)
+
+type CgroupSuite struct {
+	HookContextSuite
+	root string
+}
+
+var _ = gc.Suite(&CgroupSuite{})
+
+func (s *CgroupSuite) SetUpTest(c *gc.C) {
+	s.HookContextSuite.SetUpTest(c)
+	// Plain files stand in for the cgroup hierarchies; the hook joins
+	// its "cgroups" by writing its pid to them, as it would the real
+	// ones.
+	s.root = c.MkDir()
+	for _, subsystem := range []string{"memory", "cpuacct"} {
+		dir := filepath.Join(s.root, subsystem)
+		err := os.Mkdir(dir, 0755)
+		c.Assert(err, gc.IsNil)
+		err = ioutil.WriteFile(filepath.Join(dir, "cgroup.procs"), nil, 0644)
+		c.Assert(err, gc.IsNil)
+	}
+	s.PatchValue(context.CgroupRoot, s.root)
+	s.PatchValue(context.CgroupCPUPollInterval, 10*time.Millisecond)
+}
+
+func (s *CgroupSuite) runHook(c *gc.C, spec hookSpec, memoryLimit uint64, cpuLimit time.Duration) error {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, gc.IsNil)
+	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies, false)
+	charmDir, _ := makeCharm(c, spec)
+	ctx.SetHookLimits(memoryLimit, cpuLimit)
+	// The timeout only stops a broken test from running forever.
+	ctx.SetHookTimeout(time.Minute)
+	return ctx.RunHook(spec.name, charmDir, c.MkDir(), "/path/to/socket")
+}
+
+func (s *CgroupSuite) TestMemoryLimitSet(c *gc.C) {
+	limitFiles := filepath.Join(s.root, "memory", "juju", "*", "memory.limit_in_bytes")
+	outPath := filepath.Join(c.MkDir(), "limit")
+	err := s.runHook(c, hookSpec{
+		name:     "install",
+		perm:     0700,
+		commands: []string{"cat " + limitFiles + " > " + outPath},
+	}, 512, 0)
+	c.Assert(err, gc.IsNil)
+	data, err := ioutil.ReadFile(outPath)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "536870912")
+}
+
+func (s *CgroupSuite) TestMemoryLimitExceeded(c *gc.C) {
+	oomControl := filepath.Join(s.root, "memory", "juju", "*", "memory.oom_control")
+	err := s.runHook(c, hookSpec{
+		name: "install",
+		perm: 0700,
+		code: 1,
+		commands: []string{
+			"for f in " + filepath.Dir(oomControl) + "; do printf 'under_oom 0\\noom_kill 1\\n' > $f/memory.oom_control; done",
+		},
+	}, 512, 0)
+	c.Assert(err, gc.ErrorMatches, "install exceeded its resource limits: used more than 512MB of memory")
+	c.Assert(err, jc.Satisfies, context.IsHookLimitError)
+}
+
+func (s *CgroupSuite) TestMemoryLimitNotReached(c *gc.C) {
+	err := s.runHook(c, hookSpec{
+		name: "install",
+		perm: 0700,
+		code: 1,
+		commands: []string{
+			"for f in " + filepath.Join(s.root, "memory", "juju", "*") + "; do printf 'oom_kill 0\\n' > $f/memory.oom_control; done",
+		},
+	}, 512, 0)
+	c.Assert(err, gc.ErrorMatches, "exit status 1")
+}
+
+func (s *CgroupSuite) TestCPULimitExceeded(c *gc.C) {
+	usage := filepath.Join(s.root, "cpuacct", "juju", "*")
+	start := time.Now()
+	err := s.runHook(c, hookSpec{
+		name:     "install",
+		perm:     0700,
+		spin:     true,
+		commands: []string{"for f in " + usage + "; do echo 2000000000 > $f/cpuacct.usage; done"},
+	}, 0, time.Second)
+	c.Assert(err, gc.ErrorMatches, "install exceeded its resource limits: used more than 1s of CPU time")
+	c.Assert(err, jc.Satisfies, context.IsHookLimitError)
+	c.Assert(time.Since(start) < 5*time.Second, jc.IsTrue)
+}
@@ -0,0 +1,76 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package context
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// rlimitConfinement applies the limits to the hook process with
+// setrlimit. Each process the hook starts gets a fresh budget, so this
+// only stops a single runaway process; it is used where cgroups are
+// not available. A hook that runs out of address space usually just
+// fails, so only CPU time limits are reported as exceeded.
+type rlimitConfinement struct {
+	memoryLimit uint64
+	cpuLimit    time.Duration
+}
+
+func (r *rlimitConfinement) wrap(hookCmd []string) []string {
+	var limits []string
+	if r.memoryLimit > 0 {
+		limits = append(limits, fmt.Sprintf("ulimit -v %d", r.memoryLimit*1024))
+	}
+	if r.cpuLimit > 0 {
+		// The soft limit raises SIGXCPU; the hard limit, a second
+		// later, kills a process that ignores it.
+		seconds := (r.cpuLimit + time.Second - 1) / time.Second
+		limits = append(limits,
+			fmt.Sprintf("ulimit -H -t %d", seconds+1),
+			fmt.Sprintf("ulimit -S -t %d", seconds),
+		)
+	}
+	script := strings.Join(limits, " && ") + ` && exec "$0" "$@"`
+	return append([]string{"/bin/sh", "-c", script}, hookCmd...)
+}
+
+func (r *rlimitConfinement) started(ps *exec.Cmd) error {
+	return nil
+}
+
+// exceeded reads the cause of the hook's exit from its wait status and
+// resource usage: a hook killed by SIGXCPU, or by SIGKILL once it has
+// used up its CPU time, exceeded its CPU time limit.
+func (r *rlimitConfinement) exceeded(ps *exec.Cmd) string {
+	if r.cpuLimit == 0 || ps.ProcessState == nil {
+		return ""
+	}
+	status, ok := ps.ProcessState.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+	used := ps.ProcessState.UserTime() + ps.ProcessState.SystemTime()
+	switch status.Signal() {
+	case syscall.SIGXCPU:
+		return cpuLimitMessage(r.cpuLimit)
+	case syscall.SIGKILL:
+		if used >= r.cpuLimit {
+			return cpuLimitMessage(r.cpuLimit)
+		}
+	}
+	return ""
+}
+
+func (r *rlimitConfinement) kill() error {
+	// The hook's process group is killed by the caller.
+	return nil
+}
+
+func (r *rlimitConfinement) release() {}
@@ -0,0 +1,16 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !linux,!windows
+
+package context
+
+import (
+	"time"
+)
+
+// newHookConfinement returns a confinement that applies the given
+// limits to each process of the hook, since there are no cgroups.
+func newHookConfinement(name string, memoryLimit uint64, cpuLimit time.Duration) hookConfinement {
+	return &rlimitConfinement{memoryLimit, cpuLimit}
+}
@@ -0,0 +1,203 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build windows
+
+package context
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	modkernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procCreateJobObjectW          = modkernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject   = modkernel32.NewProc("SetInformationJobObject")
+	procQueryInformationJobObject = modkernel32.NewProc("QueryInformationJobObject")
+	procAssignProcessToJobObject  = modkernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject        = modkernel32.NewProc("TerminateJobObject")
+)
+
+const (
+	jobObjectBasicAccountingInformationClass = 1
+	jobObjectExtendedLimitInformationClass   = 9
+
+	jobObjectLimitJobTime   = 0x00000004
+	jobObjectLimitJobMemory = 0x00000200
+
+	processSetQuota  = 0x0100
+	processTerminate = 0x0001
+)
+
+type jobObjectBasicLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+type jobObjectExtendedLimitInformation struct {
+	BasicLimitInformation jobObjectBasicLimitInformation
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+type jobObjectBasicAccountingInformation struct {
+	TotalUserTime             int64
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+}
+
+// newHookConfinement returns a confinement that puts the hook in a job
+// object, whose limits windows applies to the hook and every process
+// it starts together. If the job object cannot be made, the hook runs
+// without limits.
+func newHookConfinement(name string, memoryLimit uint64, cpuLimit time.Duration) hookConfinement {
+	job, err := newJobConfinement(memoryLimit, cpuLimit)
+	if err != nil {
+		logger.Warningf("cannot make job object for hook, running it without limits: %v", err)
+		return unconfined{}
+	}
+	return job
+}
+
+// jobConfinement confines a hook to a job object. The hook is added to
+// the job as soon as it has started, so a process it starts before
+// then escapes the limits.
+type jobConfinement struct {
+	memoryLimit uint64
+	cpuLimit    time.Duration
+	handle      syscall.Handle
+}
+
+func newJobConfinement(memoryLimit uint64, cpuLimit time.Duration) (*jobConfinement, error) {
+	r, _, err := procCreateJobObjectW.Call(0, 0)
+	if r == 0 {
+		return nil, err
+	}
+	job := &jobConfinement{
+		memoryLimit: memoryLimit,
+		cpuLimit:    cpuLimit,
+		handle:      syscall.Handle(r),
+	}
+	var info jobObjectExtendedLimitInformation
+	if memoryLimit > 0 {
+		info.BasicLimitInformation.LimitFlags |= jobObjectLimitJobMemory
+		info.JobMemoryLimit = uintptr(memoryLimit * 1024 * 1024)
+	}
+	if cpuLimit > 0 {
+		// Job times are counted in units of 100 nanoseconds.
+		info.BasicLimitInformation.LimitFlags |= jobObjectLimitJobTime
+		info.BasicLimitInformation.PerJobUserTimeLimit = int64(cpuLimit / 100)
+	}
+	r, _, err = procSetInformationJobObject.Call(
+		uintptr(job.handle),
+		jobObjectExtendedLimitInformationClass,
+		uintptr(unsafe.Pointer(&info)),
+		unsafe.Sizeof(info),
+	)
+	if r == 0 {
+		job.release()
+		return nil, err
+	}
+	return job, nil
+}
+
+func (job *jobConfinement) wrap(hookCmd []string) []string {
+	return hookCmd
+}
+
+func (job *jobConfinement) started(ps *exec.Cmd) error {
+	process, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(ps.Process.Pid))
+	if err != nil {
+		return err
+	}
+	defer syscall.CloseHandle(process)
+	r, _, err := procAssignProcessToJobObject.Call(uintptr(job.handle), uintptr(process))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// exceeded compares the CPU time and peak memory used by the job with
+// its limits. Windows stops every process in a job that uses up its
+// CPU time, and fails the allocations of one that reaches its memory
+// limit.
+func (job *jobConfinement) exceeded(ps *exec.Cmd) string {
+	if job.cpuLimit > 0 {
+		var accounting jobObjectBasicAccountingInformation
+		if job.query(jobObjectBasicAccountingInformationClass, unsafe.Pointer(&accounting), unsafe.Sizeof(accounting)) {
+			if time.Duration(accounting.TotalUserTime)*100 >= job.cpuLimit {
+				return cpuLimitMessage(job.cpuLimit)
+			}
+		}
+	}
+	if job.memoryLimit > 0 {
+		var info jobObjectExtendedLimitInformation
+		if job.query(jobObjectExtendedLimitInformationClass, unsafe.Pointer(&info), unsafe.Sizeof(info)) {
+			if uint64(info.PeakJobMemoryUsed) >= job.memoryLimit*1024*1024 {
+				return memoryLimitMessage(job.memoryLimit)
+			}
+		}
+	}
+	return ""
+}
+
+func (job *jobConfinement) query(class uintptr, info unsafe.Pointer, size uintptr) bool {
+	r, _, err := procQueryInformationJobObject.Call(uintptr(job.handle), class, uintptr(info), size, 0)
+	if r == 0 {
+		logger.Warningf("cannot query hook job object: %v", err)
+		return false
+	}
+	return true
+}
+
+func (job *jobConfinement) kill() error {
+	r, _, err := procTerminateJobObject.Call(uintptr(job.handle), 1)
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// release closes the job object. Processes left running by the hook
+// keep running.
+func (job *jobConfinement) release() {
+	syscall.CloseHandle(job.handle)
+}
+
+// unconfined applies no limits.
+type unconfined struct{}
+
+func (unconfined) wrap(hookCmd []string) []string { return hookCmd }
+func (unconfined) started(ps *exec.Cmd) error     { return nil }
+func (unconfined) exceeded(ps *exec.Cmd) string   { return "" }
+func (unconfined) kill() error                    { return nil }
+func (unconfined) release()                       {}
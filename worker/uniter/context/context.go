@@ -46,9 +46,16 @@ type HookContext struct {
 	// address.
 	publicAddress string
 
+	// machineAddresses is the cached value of all the addresses of
+	// the unit's assigned machine.
+	machineAddresses []network.Address
+
 	// configSettings holds the service configuration.
 	configSettings charm.Settings
 
+	// leaderSettings holds the service's leader settings, once read.
+	leaderSettings map[string]string
+
 	// id identifies the context.
 	id string
 
@@ -76,6 +83,15 @@ type HookContext struct {
 	// of, keyed on relation id.
 	relations map[int]*ContextRelation
 
+	// storageId identifies the storage instance for which a storage
+	// hook is executing. It is empty if the context is not running a
+	// storage hook.
+	storageId string
+
+	// storage holds the storage instances attached to the unit, keyed
+	// on id, once read.
+	storage map[string]*ContextStorage
+
 	// apiAddrs contains the API server addresses.
 	apiAddrs []string
 
@@ -85,6 +101,10 @@ type HookContext struct {
 	// proxySettings are the current proxy settings that the uniter knows about.
 	proxySettings proxy.Settings
 
+	// maxRelationSettingsSize holds the largest size, in bytes, that
+	// the unit's settings in a relation may grow to.
+	maxRelationSettingsSize int
+
 	// metrics are the metrics recorded by calls to add-metric.
 	metrics []jujuc.Metric
 
@@ -106,6 +126,20 @@ type HookContext struct {
 	// assignedMachineTag contains the tag of the unit's assigned
 	// machine.
 	assignedMachineTag names.MachineTag
+
+	// hookTimeout is how long a charm hook may run before it is
+	// killed. Hooks may run indefinitely if it is zero.
+	hookTimeout time.Duration
+
+	// hookMemoryLimit is the most memory, in megabytes, that a charm
+	// hook and the processes it starts may use; it is not limited if
+	// zero.
+	hookMemoryLimit uint64
+
+	// hookCPULimit is the most CPU time a charm hook and the processes
+	// it starts may use before they are killed; it is not limited if
+	// zero.
+	hookCPULimit time.Duration
 }
 
 func (ctx *HookContext) Id() string {
@@ -124,6 +158,10 @@ func (ctx *HookContext) PrivateAddress() (string, bool) {
 	return ctx.privateAddress, ctx.privateAddress != ""
 }
 
+func (ctx *HookContext) MachineAddresses() []network.Address {
+	return ctx.machineAddresses
+}
+
 func (ctx *HookContext) OpenPorts(protocol string, fromPort, toPort int) error {
 	return tryOpenPorts(
 		protocol, fromPort, toPort,
@@ -170,6 +208,68 @@ func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	return result, nil
 }
 
+// IsLeader returns whether the unit is the leader of its service. It
+// only reads the leadership lease; the uniter is responsible for
+// claiming and renewing it.
+func (ctx *HookContext) IsLeader() (bool, error) {
+	return ctx.unit.IsLeader()
+}
+
+// LeaderSettings returns the settings written by the service's leader.
+func (ctx *HookContext) LeaderSettings() (map[string]string, error) {
+	if ctx.leaderSettings == nil {
+		var err error
+		ctx.leaderSettings, err = ctx.unit.LeaderSettings()
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := map[string]string{}
+	for key, value := range ctx.leaderSettings {
+		result[key] = value
+	}
+	return result, nil
+}
+
+// WriteLeaderSettings immediately writes the supplied changes to the
+// service's leader settings; it fails if the unit is not the leader.
+func (ctx *HookContext) WriteLeaderSettings(settings map[string]string) error {
+	if err := ctx.unit.MergeLeaderSettings(settings); err != nil {
+		return err
+	}
+	// Force the settings to be reread on next access.
+	ctx.leaderSettings = nil
+	return nil
+}
+
+// HookStorageId returns the id of the storage instance for which a
+// storage hook is executing, and whether one is.
+func (ctx *HookContext) HookStorageId() (string, bool) {
+	return ctx.storageId, ctx.storageId != ""
+}
+
+// Storage returns the attached storage instance with the given id.
+// Storage instances that have not yet been given a location are not
+// attached, and cannot be returned.
+func (ctx *HookContext) Storage(id string) (jujuc.ContextStorage, error) {
+	if ctx.storage == nil {
+		instances, err := ctx.unit.StorageInstances()
+		if err != nil {
+			return nil, err
+		}
+		ctx.storage = make(map[string]*ContextStorage)
+		for _, instance := range instances {
+			if instance.Location != "" {
+				ctx.storage[instance.Id] = NewContextStorage(instance)
+			}
+		}
+	}
+	if storage, found := ctx.storage[id]; found {
+		return storage, nil
+	}
+	return nil, errors.NotFoundf("storage instance %q", id)
+}
+
 // ActionParams simply returns the arguments to the Action.
 func (ctx *HookContext) ActionParams() (map[string]interface{}, error) {
 	if ctx.actionData == nil {
@@ -238,6 +338,12 @@ func (ctx *HookContext) RelationIds() []int {
 	return ids
 }
 
+// MaxRelationSettingsSize returns the largest size, in bytes, that the
+// unit's settings in a relation may grow to.
+func (ctx *HookContext) MaxRelationSettingsSize() int {
+	return ctx.maxRelationSettingsSize
+}
+
 // AddMetrics adds metrics to the hook context.
 func (ctx *HookContext) AddMetrics(key, value string, created time.Time) error {
 	if !ctx.canAddMetrics {
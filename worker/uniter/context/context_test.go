@@ -105,6 +105,34 @@ func (s *InterfaceSuite) TestConfigCaching(c *gc.C) {
 	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
 }
 
+func (s *InterfaceSuite) TestLeadership(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	isLeader, err := ctx.IsLeader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(isLeader, jc.IsFalse)
+
+	// The context does not claim leadership itself; the uniter does.
+	err = s.service.ClaimLeadership(s.unit.Name())
+	c.Assert(err, gc.IsNil)
+	isLeader, err = ctx.IsLeader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(isLeader, jc.IsTrue)
+
+	settings, err := ctx.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.HasLen, 0)
+	err = ctx.WriteLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, gc.IsNil)
+	settings, err = ctx.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"foo": "bar"})
+
+	// Changes made by the leader are written immediately.
+	stateSettings, err := s.service.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(stateSettings, gc.DeepEquals, map[string]string{"foo": "bar"})
+}
+
 func (s *InterfaceSuite) TestValidatePortRange(c *gc.C) {
 	tests := []struct {
 		about     string
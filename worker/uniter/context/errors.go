@@ -3,6 +3,11 @@
 
 package context
 
+import (
+	"fmt"
+	"time"
+)
+
 type missingHookError struct {
 	hookName string
 }
@@ -15,3 +20,35 @@ func IsMissingHookError(err error) bool {
 	_, ok := err.(*missingHookError)
 	return ok
 }
+
+type hookTimeoutError struct {
+	hookName string
+	timeout  time.Duration
+}
+
+func (e *hookTimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %v", e.hookName, e.timeout)
+}
+
+// IsHookTimeoutError reports whether err was returned because a hook
+// ran for longer than the hook timeout and was killed.
+func IsHookTimeoutError(err error) bool {
+	_, ok := err.(*hookTimeoutError)
+	return ok
+}
+
+type hookLimitError struct {
+	hookName string
+	cause    string
+}
+
+func (e *hookLimitError) Error() string {
+	return fmt.Sprintf("%s exceeded its resource limits: %s", e.hookName, e.cause)
+}
+
+// IsHookLimitError reports whether err was returned because a hook
+// was killed for exceeding its resource limits.
+func IsHookLimitError(err error) bool {
+	_, ok := err.(*hookLimitError)
+	return ok
+}
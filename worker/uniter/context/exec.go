@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 	utilexec "github.com/juju/utils/exec"
@@ -167,12 +168,26 @@ func (ctx *HookContext) runCharmHookWithLocation(hookName, charmLocation, charmD
 	if session, _ := debugctx.FindSession(); session != nil && session.MatchHook(hookName) {
 		logger.Infof("executing %s via debug-hooks", hookName)
 		err = session.RunHook(hookName, charmDir, env)
+		ctx.saveDebugHooksTranscript(session)
 	} else {
 		err = ctx.runCharmHook(hookName, charmDir, env, charmLocation)
 	}
 	return ctx.finalizeContext(hookName, err)
 }
 
+// saveDebugHooksTranscript records the transcript of the debug-hooks
+// session, so that it can be retrieved by the client once the session
+// has ended. Failure to do so does not affect the outcome of the hook.
+func (ctx *HookContext) saveDebugHooksTranscript(session *unitdebug.ServerSession) {
+	transcript, err := session.Transcript()
+	if err == nil {
+		err = ctx.unit.SetDebugHooksTranscript(transcript)
+	}
+	if err != nil {
+		logger.Warningf("cannot save debug-hooks transcript: %v", err)
+	}
+}
+
 func lookPath(hook string) (string, error) {
 	hookFile, err := exec.LookPath(hook)
 	if err != nil {
@@ -244,9 +259,16 @@ func (ctx *HookContext) runCharmHook(hookName, charmDir string, env []string, ch
 		return err
 	}
 	hookCmd := hookCommand(hook)
+	var confinement hookConfinement
+	if ctx.hookMemoryLimit > 0 || ctx.hookCPULimit > 0 {
+		confinement = newHookConfinement(hookConfinementName(ctx.id), ctx.hookMemoryLimit, ctx.hookCPULimit)
+		defer confinement.release()
+		hookCmd = confinement.wrap(hookCmd)
+	}
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
 	ps.Env = env
 	ps.Dir = charmDir
+	setHookProcessGroup(ps)
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
 		return fmt.Errorf("cannot make logging pipe: %v", err)
@@ -262,8 +284,66 @@ func (ctx *HookContext) runCharmHook(hookName, charmDir string, env []string, ch
 	err = ps.Start()
 	outWriter.Close()
 	if err == nil {
-		err = ps.Wait()
+		if confinement != nil {
+			if err := confinement.started(ps); err != nil {
+				logger.Warningf("cannot confine %q hook: %v", hookName, err)
+			}
+		}
+		err = ctx.waitHook(hookName, ps, confinement)
 	}
 	hookLogger.stop()
+	if err != nil && confinement != nil && !IsHookTimeoutError(err) {
+		if cause := confinement.exceeded(ps); cause != "" {
+			return &hookLimitError{hookName, cause}
+		}
+	}
 	return err
 }
+
+// SetHookLimits sets the most memory, in megabytes, and the most CPU
+// time that a charm hook run in the context, together with the
+// processes it starts, may use. Zero limits are not applied.
+//
+// Where possible the hook is confined to a cgroup on linux, or a job
+// object on windows, which the limits apply to as a whole. Otherwise
+// the limits are applied to each of its processes separately.
+func (ctx *HookContext) SetHookLimits(memoryLimit uint64, cpuLimit time.Duration) {
+	ctx.hookMemoryLimit = memoryLimit
+	ctx.hookCPULimit = cpuLimit
+}
+
+// SetHookTimeout sets how long a charm hook run in the context may run
+// before it is killed and treated as failed. Hooks may run indefinitely
+// if the timeout is zero.
+func (ctx *HookContext) SetHookTimeout(timeout time.Duration) {
+	ctx.hookTimeout = timeout
+}
+
+// waitHook waits for the started hook process to exit. If it runs for
+// longer than the hook timeout, it is killed along with every process
+// it started, such as a package manager that would otherwise keep its
+// lock and break the hook's next attempt.
+func (ctx *HookContext) waitHook(hookName string, ps *exec.Cmd, confinement hookConfinement) error {
+	if ctx.hookTimeout <= 0 {
+		return ps.Wait()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- ps.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(ctx.hookTimeout):
+	}
+	if err := killHookProcessGroup(ps); err != nil {
+		logger.Warningf("cannot kill %q hook: %v", hookName, err)
+	}
+	if confinement != nil {
+		if err := confinement.kill(); err != nil {
+			logger.Warningf("cannot kill processes confined with %q hook: %v", hookName, err)
+		}
+	}
+	<-done
+	return &hookTimeoutError{hookName, ctx.hookTimeout}
+}
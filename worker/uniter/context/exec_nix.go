@@ -0,0 +1,24 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package context
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setHookProcessGroup arranges for the hook process to be started in
+// its own process group, which the processes it starts join too.
+func setHookProcessGroup(ps *exec.Cmd) {
+	ps.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killHookProcessGroup kills the started hook process and every process
+// in its process group, so that nothing started by the hook is left
+// running, holding locks, once the hook has been abandoned.
+func killHookProcessGroup(ps *exec.Cmd) error {
+	return syscall.Kill(-ps.Process.Pid, syscall.SIGKILL)
+}
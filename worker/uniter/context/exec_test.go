@@ -20,6 +20,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/uniter/context"
 )
@@ -221,6 +222,15 @@ type hookSpec struct {
 	stderr string
 	// background holds a string to print in the background after 0.2s.
 	background string
+	// sleep holds the number of seconds for which the hook sleeps
+	// before exiting.
+	sleep int
+	// spin causes the hook to loop forever, using as much CPU time as
+	// it can.
+	spin bool
+	// commands holds shell commands that the hook runs before
+	// sleeping or spinning.
+	commands []string
 }
 
 // makeCharm constructs a fake charm dir containing a single named hook
@@ -259,6 +269,15 @@ func makeCharm(c *gc.C, spec hookSpec) (charmDir, outPath string) {
 		// expected.
 		printf("(sleep 0.2; echo %s; sleep 10) &", spec.background)
 	}
+	for _, command := range spec.commands {
+		printf("%s", command)
+	}
+	if spec.sleep != 0 {
+		printf("sleep %d", spec.sleep)
+	}
+	if spec.spin {
+		printf("while :; do :; done")
+	}
 	printf("exit %d", spec.code)
 	return charmDir, outPath
 }
@@ -550,6 +569,88 @@ func (s *RunHookSuite) TestRunHookMetricSending(c *gc.C) {
 	c.Assert(metrics[0].Value, gc.Equals, "50")
 }
 
+func (s *RunHookSuite) TestRunHookTimeout(c *gc.C) {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, gc.IsNil)
+	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies, false)
+	charmDir, _ := makeCharm(c, hookSpec{
+		name:  "install",
+		perm:  0700,
+		sleep: 10,
+	})
+	ctx.SetHookTimeout(100 * time.Millisecond)
+
+	start := time.Now()
+	err = ctx.RunHook("install", charmDir, c.MkDir(), "/path/to/socket")
+	c.Assert(err, gc.ErrorMatches, "install timed out after 100ms")
+	c.Assert(err, jc.Satisfies, context.IsHookTimeoutError)
+	c.Assert(time.Since(start) < 5*time.Second, jc.IsTrue)
+}
+
+func (s *RunHookSuite) TestRunHookTimeoutKillsChildren(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("the test hook is a bash script")
+	}
+	uuid, err := utils.NewUUID()
+	c.Assert(err, gc.IsNil)
+	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies, false)
+	// The hook's child keeps the hook's output open; if it were left
+	// running, the hook would not finish until the child did.
+	charmDir, _ := makeCharm(c, hookSpec{
+		name:       "install",
+		perm:       0700,
+		background: "child",
+		sleep:      10,
+	})
+	ctx.SetHookTimeout(100 * time.Millisecond)
+
+	start := time.Now()
+	err = ctx.RunHook("install", charmDir, c.MkDir(), "/path/to/socket")
+	c.Assert(err, jc.Satisfies, context.IsHookTimeoutError)
+	c.Assert(time.Since(start) < 5*time.Second, jc.IsTrue)
+}
+
+func (s *RunHookSuite) TestRunHookCPULimit(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hook resource limits are not supported on windows")
+	}
+	uuid, err := utils.NewUUID()
+	c.Assert(err, gc.IsNil)
+	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies, false)
+	charmDir, _ := makeCharm(c, hookSpec{
+		name: "install",
+		perm: 0700,
+		spin: true,
+	})
+	ctx.SetHookLimits(0, time.Second)
+	// The timeout only stops a broken test from spinning forever.
+	ctx.SetHookTimeout(coretesting.LongWait)
+
+	err = ctx.RunHook("install", charmDir, c.MkDir(), "/path/to/socket")
+	c.Assert(err, gc.ErrorMatches, "install exceeded its resource limits: used more than 1s of CPU time")
+	c.Assert(err, jc.Satisfies, context.IsHookLimitError)
+}
+
+func (s *RunHookSuite) TestRunHookSignalWithinLimits(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("the test hook is a bash script")
+	}
+	uuid, err := utils.NewUUID()
+	c.Assert(err, gc.IsNil)
+	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies, false)
+	charmDir, _ := makeCharm(c, hookSpec{
+		name:     "install",
+		perm:     0700,
+		commands: []string{"kill -KILL $$"},
+	})
+	ctx.SetHookLimits(512, time.Minute)
+
+	// A hook killed by a signal without reaching its limits just fails.
+	err = ctx.RunHook("install", charmDir, c.MkDir(), "/path/to/socket")
+	c.Assert(err, gc.ErrorMatches, "signal: killed")
+	c.Assert(err, gc.Not(jc.Satisfies), context.IsHookLimitError)
+}
+
 func (s *RunHookSuite) TestRunHookMetricSendingDisabled(c *gc.C) {
 	uuid, err := utils.NewUUID()
 	c.Assert(err, gc.IsNil)
@@ -0,0 +1,27 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build windows
+
+package context
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setHookProcessGroup does nothing on windows, where the processes
+// started by a hook are found through the hook's process tree instead.
+func setHookProcessGroup(ps *exec.Cmd) {}
+
+// killHookProcessGroup kills the started hook process and every process
+// it started, so that nothing started by the hook is left running once
+// the hook has been abandoned.
+func killHookProcessGroup(ps *exec.Cmd) error {
+	pid := strconv.Itoa(ps.Process.Pid)
+	if err := exec.Command("taskkill", "/F", "/T", "/PID", pid).Run(); err != nil {
+		logger.Warningf("cannot kill process tree of %s: %v", pid, err)
+		return ps.Process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build linux
+
+package context
+
+var (
+	CgroupRoot            = &cgroupRoot
+	CgroupCPUPollInterval = &cgroupCPUPollInterval
+)
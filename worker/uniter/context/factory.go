@@ -133,6 +133,10 @@ func (f *factory) NewHookContext(hookInfo hook.Info) (*HookContext, error) {
 		}
 		hookName = fmt.Sprintf("%s-%s", relation.Name(), hookInfo.Kind)
 	}
+	if hook.IsStorage(hookInfo.Kind) {
+		ctx.storageId = hookInfo.StorageId
+		hookName = StorageHookName(hookInfo)
+	}
 	ctx.id = f.newId(hookName)
 	return ctx, nil
 }
@@ -191,6 +195,10 @@ func (f *factory) updateContext(ctx *HookContext) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	ctx.machineAddresses, err = f.state.MachineAddresses(f.machineTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	statusCode, statusInfo, err := f.unit.MeterStatus()
 	if err != nil {
@@ -208,6 +216,7 @@ func (f *factory) updateContext(ctx *HookContext) (err error) {
 		return err
 	}
 	ctx.proxySettings = environConfig.ProxySettings()
+	ctx.maxRelationSettingsSize = environConfig.MaxRelationSettingsSize()
 
 	// Calling these last, because there's a potential race: they're not guaranteed
 	// to be set in time to be needed for a hook. If they're not, we just leave them
@@ -4,12 +4,15 @@
 package context_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v4/hooks"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/uniter/context"
 	"github.com/juju/juju/worker/uniter/hook"
 )
@@ -53,6 +56,7 @@ func (s *FactorySuite) AssertCoreContext(c *gc.C, ctx *context.HookContext) {
 	name, uuid := ctx.EnvInfo()
 	c.Assert(name, gc.Equals, env.Name())
 	c.Assert(uuid, gc.Equals, env.UUID())
+	c.Assert(ctx.MaxRelationSettingsSize(), gc.Equals, config.DefaultMaxRelationSettingsSize)
 
 	c.Assert(ctx.RelationIds(), gc.HasLen, 2)
 
@@ -195,6 +199,38 @@ func (s *FactorySuite) TestNewHookContextWithBadRelation(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unknown relation id: 12345`)
 }
 
+func (s *FactorySuite) TestNewHookContextWithStorage(c *gc.C) {
+	data, err := s.unit.AddStorageInstance("data", state.StorageKindFilesystem)
+	c.Assert(err, gc.IsNil)
+	err = data.SetLocation("/srv/data", map[string]string{"size": "1024"})
+	c.Assert(err, gc.IsNil)
+	// The logs storage has no location, so is not yet attached.
+	_, err = s.unit.AddStorageInstance("logs", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := s.factory.NewHookContext(hook.Info{
+		Kind:      hook.StorageAttached,
+		StorageId: "data/0",
+	})
+	c.Assert(err, gc.IsNil)
+	s.AssertCoreContext(c, ctx)
+	s.AssertNotActionContext(c, ctx)
+	s.AssertNotRelationContext(c, ctx)
+	c.Assert(ctx.Id(), gc.Matches, `u/0-data-storage-attached-\d+`)
+	storageId, ok := ctx.HookStorageId()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(storageId, gc.Equals, "data/0")
+
+	storage, err := ctx.Storage("data/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(storage.Id(), gc.Equals, "data/0")
+	c.Assert(storage.Kind(), gc.Equals, "filesystem")
+	c.Assert(storage.Location(), gc.Equals, "/srv/data")
+	c.Assert(storage.Attributes(), jc.DeepEquals, map[string]string{"size": "1024"})
+	_, err = ctx.Storage("logs/1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *FactorySuite) TestNewActionContext(c *gc.C) {
 	tag := names.NewActionTag("blah_a_1")
 	params := map[string]interface{}{"foo": "bar"}
@@ -6,6 +6,7 @@ package context
 import (
 	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
@@ -15,6 +16,42 @@ import (
 // SettingsMap is a map from unit name to relation settings.
 type SettingsMap map[string]params.RelationSettings
 
+// SettingsSnapshot holds the most recently known settings of the remote
+// units in a relation, so that they can still be read by the hooks that
+// run after those units have departed. It outlives individual contexts,
+// and is safe for concurrent use.
+type SettingsSnapshot struct {
+	mu       sync.Mutex
+	settings SettingsMap
+}
+
+// NewSettingsSnapshot returns an empty SettingsSnapshot.
+func NewSettingsSnapshot() *SettingsSnapshot {
+	return &SettingsSnapshot{settings: make(SettingsMap)}
+}
+
+func (s *SettingsSnapshot) get(unit string) params.RelationSettings {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.settings[unit]
+}
+
+func (s *SettingsSnapshot) set(unit string, settings params.RelationSettings) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if settings == nil {
+		delete(s.settings, unit)
+	} else {
+		s.settings[unit] = settings
+	}
+}
+
 // ContextRelation is the implementation of jujuc.ContextRelation.
 type ContextRelation struct {
 	ru *uniter.RelationUnit
@@ -30,6 +67,10 @@ type ContextRelation struct {
 	// for units that are not currently participating in the relation. Its
 	// contents should be cleared whenever a new hook is executed.
 	cache SettingsMap
+
+	// snapshot, if not nil, records the settings of members as they
+	// are read, and supplies the settings of departed units.
+	snapshot *SettingsSnapshot
 }
 
 // NewContextRelation creates a new context for the given relation unit.
@@ -43,6 +84,13 @@ func NewContextRelation(ru *uniter.RelationUnit, members map[string]int64) *Cont
 	return ctx
 }
 
+// SetSnapshot makes the context record the settings of members in s
+// as they are read, and use it to supply the final settings of units
+// that have departed the relation.
+func (ctx *ContextRelation) SetSnapshot(s *SettingsSnapshot) {
+	ctx.snapshot = s
+}
+
 // WriteSettings persists all changes made to the unit's relation settings.
 func (ctx *ContextRelation) WriteSettings() (err error) {
 	if ctx.settings != nil {
@@ -65,12 +113,22 @@ func (ctx *ContextRelation) ClearCache() {
 func (ctx *ContextRelation) UpdateMembers(members SettingsMap) {
 	for m, s := range members {
 		ctx.members[m] = s
+		ctx.snapshot.set(m, s)
 	}
 }
 
 // DeleteMember drops the membership and cache of a single remote unit, without
-// perturbing settings for the remaining members.
+// perturbing settings for the remaining members. If the context has a
+// snapshot, the unit's final settings are kept in it.
 func (ctx *ContextRelation) DeleteMember(unitName string) {
+	if ctx.snapshot != nil && ctx.snapshot.get(unitName) == nil {
+		settings, err := ctx.ru.ReadSettings(unitName)
+		if err != nil {
+			logger.Warningf("cannot record final settings of departed unit %q: %v", unitName, err)
+		} else {
+			ctx.snapshot.set(unitName, settings)
+		}
+	}
 	delete(ctx.members, unitName)
 }
 
@@ -108,7 +166,12 @@ func (ctx *ContextRelation) Settings() (jujuc.Settings, error) {
 func (ctx *ContextRelation) ReadSettings(unit string) (settings params.RelationSettings, err error) {
 	settings, member := ctx.members[unit]
 	if settings == nil {
-		if settings = ctx.cache[unit]; settings == nil {
+		if settings = ctx.cache[unit]; settings == nil && !member {
+			// Departed units' settings are taken from the snapshot, as
+			// they may no longer be available from state.
+			settings = ctx.snapshot.get(unit)
+		}
+		if settings == nil {
 			settings, err = ctx.ru.ReadSettings(unit)
 			if err != nil {
 				return nil, err
@@ -117,6 +180,7 @@ func (ctx *ContextRelation) ReadSettings(unit string) (settings params.RelationS
 	}
 	if member {
 		ctx.members[unit] = settings
+		ctx.snapshot.set(unit, settings)
 	} else {
 		ctx.cache[unit] = settings
 	}
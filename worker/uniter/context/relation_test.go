@@ -192,6 +192,53 @@ func (s *ContextRelationSuite) TestNonMemberCaching(c *gc.C) {
 	c.Assert(m["ping"], gc.Equals, "pow")
 }
 
+func (s *ContextRelationSuite) TestDepartedSnapshot(c *gc.C) {
+	unit, err := s.svc.AddUnit()
+	c.Assert(err, gc.IsNil)
+	ru, err := s.rel.Unit(unit)
+	c.Assert(err, gc.IsNil)
+	err = ru.EnterScope(map[string]interface{}{"user": "fred"})
+	c.Assert(err, gc.IsNil)
+	snapshot := context.NewSettingsSnapshot()
+
+	// The unit departs without its settings ever having been read.
+	ctx := context.NewContextRelation(s.apiRelUnit, map[string]int64{"u/1": 0})
+	ctx.SetSnapshot(snapshot)
+	ctx.DeleteMember("u/1")
+	c.Assert(ctx.UnitNames(), gc.HasLen, 0)
+	err = ru.LeaveScope()
+	c.Assert(err, gc.IsNil)
+	settings, err := ru.Settings()
+	c.Assert(err, gc.IsNil)
+	settings.Set("user", "jim")
+	_, err = settings.Write()
+	c.Assert(err, gc.IsNil)
+
+	// Later contexts sharing the snapshot see the final settings the
+	// unit had when it departed, regardless of the contents of state.
+	ctx = context.NewContextRelation(s.apiRelUnit, nil)
+	ctx.SetSnapshot(snapshot)
+	m, err := ctx.ReadSettings("u/1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(m["user"], gc.Equals, "fred")
+}
+
+func (s *ContextRelationSuite) TestSnapshotRecordsMemberReads(c *gc.C) {
+	snapshot := context.NewSettingsSnapshot()
+	ctx := context.NewContextRelation(s.apiRelUnit, nil)
+	ctx.SetSnapshot(snapshot)
+	ctx.UpdateMembers(context.SettingsMap{"u/1": {"user": "fred"}})
+
+	// Settings of members are recorded, so the unit need not be read
+	// from state when it departs.
+	ctx.DeleteMember("u/1")
+	ctx = context.NewContextRelation(s.apiRelUnit, nil)
+	ctx.SetSnapshot(snapshot)
+	m, err := ctx.ReadSettings("u/1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(m, gc.DeepEquals, params.RelationSettings{"user": "fred"})
+}
+
 func (s *ContextRelationSuite) TestSettings(c *gc.C) {
 	ctx := context.NewContextRelation(s.apiRelUnit, nil)
 
@@ -0,0 +1,55 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/uniter/hook"
+)
+
+// StorageHookName returns the name of the hook to run for a storage
+// hook, which is prefixed with the name of the storage, such as
+// "data-storage-attached" for the storage instance "data/0".
+func StorageHookName(hi hook.Info) string {
+	name := hi.StorageId
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[:i]
+	}
+	return fmt.Sprintf("%s-%s", name, hi.Kind)
+}
+
+// ContextStorage is the implementation of jujuc.ContextStorage for a
+// storage instance attached to the unit.
+type ContextStorage struct {
+	instance params.StorageInstance
+}
+
+// NewContextStorage returns a ContextStorage for the given storage
+// instance.
+func NewContextStorage(instance params.StorageInstance) *ContextStorage {
+	return &ContextStorage{instance}
+}
+
+func (s *ContextStorage) Id() string {
+	return s.instance.Id
+}
+
+func (s *ContextStorage) Kind() string {
+	return s.instance.Kind
+}
+
+func (s *ContextStorage) Location() string {
+	return s.instance.Location
+}
+
+func (s *ContextStorage) Attributes() map[string]string {
+	result := make(map[string]string, len(s.instance.Attributes))
+	for key, value := range s.instance.Attributes {
+		result[key] = value
+	}
+	return result
+}
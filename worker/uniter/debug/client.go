@@ -29,6 +29,7 @@ func ClientScript(c *HooksContext, hooks []string) string {
 	s = strings.Replace(s, "{tmux_conf}", tmuxConf, 1)
 	s = strings.Replace(s, "{entry_flock}", c.ClientFileLock(), -1)
 	s = strings.Replace(s, "{exit_flock}", c.ClientExitFileLock(), -1)
+	s = strings.Replace(s, "{transcript}", c.TranscriptFile(), -1)
 
 	yamlArgs := encodeArgs(hooks)
 	base64Args := base64.StdEncoding.EncodeToString(yamlArgs)
@@ -57,6 +58,9 @@ exec 8>&-
 # Write out the debug-hooks args.
 echo "{hook_args}" | base64 -d > {entry_flock}
 
+# Clear the transcript of any previous debug-hooks session.
+: > {transcript}
+
 # Lock the juju-<unit>-debug-exit lockfile.
 flock -n 9 || exit 1
 
@@ -29,6 +29,8 @@ func (*DebugHooksClientSuite) TestClientScript(c *gc.C) {
 	c.Assert(result, gc.Matches, fmt.Sprintf("(.|\n)*\\) 9>%s(.|\n)*", regexp.QuoteMeta(ctx.ClientExitFileLock())))
 	//) 8>{entry_flock}
 	c.Assert(result, gc.Matches, fmt.Sprintf("(.|\n)*\\) 8>%s(.|\n)*", regexp.QuoteMeta(ctx.ClientFileLock())))
+	// : > {transcript}
+	c.Assert(result, gc.Matches, fmt.Sprintf("(.|\n)*: > %s\n(.|\n)*", regexp.QuoteMeta(ctx.TranscriptFile())))
 
 	// nil is the same as empty slice is the same as "*".
 	// Also, if "*" is present as well as a named hook,
@@ -30,6 +30,12 @@ func (c *HooksContext) ClientExitFileLock() string {
 	return c.ClientFileLock() + "-exit"
 }
 
+// TranscriptFile returns the path of the file recording the output
+// of the hooks debugged in the current debug-hooks session.
+func (c *HooksContext) TranscriptFile() string {
+	return c.ClientFileLock() + "-transcript"
+}
+
 func (c *HooksContext) tmuxSessionName() string {
 	return c.Unit
 }
@@ -21,4 +21,5 @@ func (*DebugHooksCommonSuite) TestHooksContext(c *gc.C) {
 	ctx.FlockDir = "/var/lib/juju"
 	c.Assert(ctx.ClientFileLock(), gc.Equals, "/var/lib/juju/juju-unit-foo-8-debug-hooks")
 	c.Assert(ctx.ClientExitFileLock(), gc.Equals, "/var/lib/juju/juju-unit-foo-8-debug-hooks-exit")
+	c.Assert(ctx.TranscriptFile(), gc.Equals, "/var/lib/juju/juju-unit-foo-8-debug-hooks-transcript")
 }
@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path"
 
 	"github.com/juju/utils/set"
 	goyaml "gopkg.in/yaml.v1"
@@ -21,9 +22,30 @@ type ServerSession struct {
 }
 
 // MatchHook returns true if the specified hook name matches
-// the hook specified by the debug-hooks client.
+// any of the hook names or glob patterns (e.g. "relation-*")
+// specified by the debug-hooks client.
 func (s *ServerSession) MatchHook(hookName string) bool {
-	return s.hooks.IsEmpty() || s.hooks.Contains(hookName)
+	if s.hooks.IsEmpty() {
+		return true
+	}
+	for _, pattern := range s.hooks.Values() {
+		if matched, _ := path.Match(pattern, hookName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Transcript returns the output recorded so far for the hooks
+// debugged in the session.
+func (s *ServerSession) Transcript() (string, error) {
+	data, err := ioutil.ReadFile(s.TranscriptFile())
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
 
 // waitClientExit executes flock, waiting for the SSH client to exit.
@@ -35,7 +57,7 @@ var waitClientExit = func(s *ServerSession) {
 
 // RunHook "runs" the hook with the specified name via debug-hooks.
 func (s *ServerSession) RunHook(hookName, charmDir string, env []string) error {
-	env = append(env, "JUJU_HOOK_NAME="+hookName)
+	env = append(env, "JUJU_HOOK_NAME="+hookName, "JUJU_DEBUG_TRANSCRIPT="+s.TranscriptFile())
 	cmd := exec.Command("/bin/bash", "-s")
 	cmd.Env = env
 	cmd.Dir = charmDir
@@ -117,7 +139,10 @@ exec /bin/bash --noprofile --init-file $JUJU_DEBUG/init.sh
 END
 chmod +x $JUJU_DEBUG/hook.sh
 
-tmux new-window -t $JUJU_UNIT_NAME -n $JUJU_HOOK_NAME "$JUJU_DEBUG/hook.sh"
+# Open a window for the hook, recording its output in the session transcript.
+echo "=== $JUJU_HOOK_NAME ===" >> $JUJU_DEBUG_TRANSCRIPT
+PANE=$(tmux new-window -P -F '#{pane_id}' -t $JUJU_UNIT_NAME -n $JUJU_HOOK_NAME "$JUJU_DEBUG/hook.sh")
+tmux pipe-pane -t "$PANE" "cat >> $JUJU_DEBUG_TRANSCRIPT"
 
 # If we exit for whatever reason, kill the hook shell.
 exit_handler() {
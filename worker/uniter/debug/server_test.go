@@ -89,6 +89,37 @@ func (s *DebugHooksServerSuite) TestFindSession(c *gc.C) {
 	c.Assert(session.MatchHook("bar"), jc.IsTrue)
 	c.Assert(session.MatchHook("baz"), jc.IsTrue)
 	c.Assert(session.MatchHook("foo bar baz"), jc.IsFalse)
+
+	// Hook names may be glob patterns.
+	err = ioutil.WriteFile(s.ctx.ClientFileLock(), []byte(`hooks: [config-changed, db-relation-*]`), 0777)
+	c.Assert(err, gc.IsNil)
+	session, err = s.ctx.FindSession()
+	c.Assert(session, gc.NotNil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(session.MatchHook("config-changed"), jc.IsTrue)
+	c.Assert(session.MatchHook("db-relation-joined"), jc.IsTrue)
+	c.Assert(session.MatchHook("db-relation-departed"), jc.IsTrue)
+	c.Assert(session.MatchHook("cache-relation-joined"), jc.IsFalse)
+	c.Assert(session.MatchHook("install"), jc.IsFalse)
+}
+
+func (s *DebugHooksServerSuite) TestTranscript(c *gc.C) {
+	err := ioutil.WriteFile(s.ctx.ClientFileLock(), []byte{}, 0777)
+	c.Assert(err, gc.IsNil)
+	session, err := s.ctx.FindSession()
+	c.Assert(session, gc.NotNil)
+	c.Assert(err, gc.IsNil)
+
+	// No transcript has been recorded yet.
+	transcript, err := session.Transcript()
+	c.Assert(err, gc.IsNil)
+	c.Assert(transcript, gc.Equals, "")
+
+	err = ioutil.WriteFile(s.ctx.TranscriptFile(), []byte("=== install ===\n$ ls\n"), 0644)
+	c.Assert(err, gc.IsNil)
+	transcript, err = session.Transcript()
+	c.Assert(err, gc.IsNil)
+	c.Assert(transcript, gc.Equals, "=== install ===\n$ ls\n")
 }
 
 func (s *DebugHooksServerSuite) TestRunHookExceptional(c *gc.C) {
@@ -6,6 +6,10 @@ package uniter
 import (
 	"fmt"
 	"time"
+
+	"github.com/juju/utils/fslock"
+
+	"github.com/juju/juju/utils/hooklock"
 )
 
 func SetUniterObserver(u *Uniter, observer UniterExecutionObserver) {
@@ -46,3 +50,11 @@ func NewManualTicker() *ManualTicker {
 		c: make(chan time.Time, 1),
 	}
 }
+
+// AcquireHookLock acquires a hook execution lock as a uniter using the
+// given hook-concurrency would.
+func AcquireHookLock(hookLock *hooklock.Lock, concurrency int, message string, exclusive bool) (*fslock.Lock, error) {
+	u := &Uniter{hookLock: hookLock}
+	u.setHookConcurrency(concurrency)
+	return u.acquireHookLock(message, exclusive)
+}
@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -23,6 +24,11 @@ import (
 
 var filterLogger = loggo.GetLogger("juju.worker.uniter.filter")
 
+// leadershipClaimInterval is the interval at which the filter claims,
+// or renews, leadership of the unit's service. It must be comfortably
+// shorter than the leadership lease held by the state server.
+var leadershipClaimInterval = 30 * time.Second
+
 // filter collects unit, service, and service config information from separate
 // state watchers, and presents it as events on channels designed specifically
 // for the convenience of the uniter.
@@ -37,18 +43,24 @@ type filter struct {
 	// The out* chans, when set to the corresponding out*On chan (rather than
 	// nil) indicate that an event of the appropriate type is ready to send
 	// to the client.
-	outConfig        chan struct{}
-	outConfigOn      chan struct{}
-	outAction        chan *hook.Info
-	outActionOn      chan *hook.Info
-	outUpgrade       chan *charm.URL
-	outUpgradeOn     chan *charm.URL
-	outResolved      chan params.ResolvedMode
-	outResolvedOn    chan params.ResolvedMode
-	outRelations     chan []int
-	outRelationsOn   chan []int
-	outMeterStatus   chan struct{}
-	outMeterStatusOn chan struct{}
+	outConfig           chan struct{}
+	outConfigOn         chan struct{}
+	outAction           chan *hook.Info
+	outActionOn         chan *hook.Info
+	outUpgrade          chan *charm.URL
+	outUpgradeOn        chan *charm.URL
+	outResolved         chan params.ResolvedMode
+	outResolvedOn       chan params.ResolvedMode
+	outRelations        chan []int
+	outRelationsOn      chan []int
+	outMeterStatus      chan struct{}
+	outMeterStatusOn    chan struct{}
+	outLeaderElected    chan struct{}
+	outLeaderElectedOn  chan struct{}
+	outLeaderSettings   chan struct{}
+	outLeaderSettingsOn chan struct{}
+	outStorage          chan struct{}
+	outStorageOn        chan struct{}
 	// The want* chans are used to indicate that the filter should send
 	// events if it has them available.
 	wantForcedUpgrade chan bool
@@ -98,33 +110,43 @@ type filter struct {
 	// meterStatusCode and meterStatusInfo reflect the meter status values of the unit.
 	meterStatusCode string
 	meterStatusInfo string
+
+	// isLeader records whether the unit's most recent leadership
+	// claim succeeded.
+	isLeader bool
 }
 
 // newFilter returns a filter that handles state changes pertaining to the
 // supplied unit.
 func newFilter(st *uniter.State, unitTag names.UnitTag) (*filter, error) {
 	f := &filter{
-		st:                st,
-		outUnitDying:      make(chan struct{}),
-		outConfig:         make(chan struct{}),
-		outConfigOn:       make(chan struct{}),
-		outAction:         make(chan *hook.Info),
-		outActionOn:       make(chan *hook.Info),
-		outUpgrade:        make(chan *charm.URL),
-		outUpgradeOn:      make(chan *charm.URL),
-		outResolved:       make(chan params.ResolvedMode),
-		outResolvedOn:     make(chan params.ResolvedMode),
-		outRelations:      make(chan []int),
-		outRelationsOn:    make(chan []int),
-		outMeterStatus:    make(chan struct{}),
-		outMeterStatusOn:  make(chan struct{}),
-		wantForcedUpgrade: make(chan bool),
-		wantResolved:      make(chan struct{}),
-		discardConfig:     make(chan struct{}),
-		setCharm:          make(chan *charm.URL),
-		didSetCharm:       make(chan struct{}),
-		clearResolved:     make(chan struct{}),
-		didClearResolved:  make(chan struct{}),
+		st:                  st,
+		outUnitDying:        make(chan struct{}),
+		outConfig:           make(chan struct{}),
+		outConfigOn:         make(chan struct{}),
+		outAction:           make(chan *hook.Info),
+		outActionOn:         make(chan *hook.Info),
+		outUpgrade:          make(chan *charm.URL),
+		outUpgradeOn:        make(chan *charm.URL),
+		outResolved:         make(chan params.ResolvedMode),
+		outResolvedOn:       make(chan params.ResolvedMode),
+		outRelations:        make(chan []int),
+		outRelationsOn:      make(chan []int),
+		outMeterStatus:      make(chan struct{}),
+		outMeterStatusOn:    make(chan struct{}),
+		outLeaderElected:    make(chan struct{}),
+		outLeaderElectedOn:  make(chan struct{}),
+		outLeaderSettings:   make(chan struct{}),
+		outLeaderSettingsOn: make(chan struct{}),
+		outStorage:          make(chan struct{}),
+		outStorageOn:        make(chan struct{}),
+		wantForcedUpgrade:   make(chan bool),
+		wantResolved:        make(chan struct{}),
+		discardConfig:       make(chan struct{}),
+		setCharm:            make(chan *charm.URL),
+		didSetCharm:         make(chan struct{}),
+		clearResolved:       make(chan struct{}),
+		didClearResolved:    make(chan struct{}),
 	}
 	go func() {
 		defer f.tomb.Done()
@@ -174,6 +196,24 @@ func (f *filter) MeterStatusEvents() <-chan struct{} {
 	return f.outMeterStatusOn
 }
 
+// LeaderElectedEvents returns a channel that will receive a signal when
+// the unit becomes the leader of its service.
+func (f *filter) LeaderElectedEvents() <-chan struct{} {
+	return f.outLeaderElectedOn
+}
+
+// LeaderSettingsEvents returns a channel that will receive a signal when
+// the service's leader settings change while the unit is not the leader.
+func (f *filter) LeaderSettingsEvents() <-chan struct{} {
+	return f.outLeaderSettingsOn
+}
+
+// StorageEvents returns a channel that will receive a signal when the
+// unit's storage instances change.
+func (f *filter) StorageEvents() <-chan struct{} {
+	return f.outStorageOn
+}
+
 // ConfigEvents returns a channel that will receive a signal whenever the service's
 // configuration changes, or when an event is explicitly requested.
 func (f *filter) ConfigEvents() <-chan struct{} {
@@ -342,6 +382,38 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		return err
 	}
 	defer watcher.Stop(addressesw, &f.tomb)
+	// Leadership is not supported by older state servers, in which
+	// case we neither claim leadership nor watch leader settings.
+	var leaderSettingsChanges <-chan struct{}
+	leaderSettingsw, err := f.unit.WatchLeaderSettings()
+	if errors.IsNotImplemented(err) {
+		filterLogger.Infof("leadership not supported by the state server")
+	} else if err != nil {
+		return err
+	} else {
+		defer watcher.Stop(leaderSettingsw, &f.tomb)
+		leaderSettingsChanges = leaderSettingsw.Changes()
+		// Ignore the initial event; leader-settings-changed only
+		// reports subsequent changes.
+		if _, ok := <-leaderSettingsChanges; !ok {
+			return watcher.EnsureErr(leaderSettingsw)
+		}
+	}
+	var claimLeadership <-chan time.Time
+	if leaderSettingsChanges != nil {
+		claimLeadership = time.After(0)
+	}
+	// Nor is storage, in which case no storage events are sent.
+	var storageChanges <-chan struct{}
+	storagew, err := f.unit.WatchStorageInstances()
+	if errors.IsNotImplemented(err) {
+		filterLogger.Infof("storage not supported by the state server")
+	} else if err != nil {
+		return err
+	} else {
+		defer watcher.Stop(storagew, &f.tomb)
+		storageChanges = storagew.Changes()
+	}
 
 	// Config events cannot be meaningfully discarded until one is available;
 	// once we receive the initial change, we unblock discard requests by
@@ -403,6 +475,27 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			// address change causes config-changed event
 			filterLogger.Debugf("preparing new config event")
 			f.outConfig = f.outConfigOn
+		case _, ok = <-leaderSettingsChanges:
+			filterLogger.Debugf("got leader settings change")
+			if !ok {
+				return watcher.EnsureErr(leaderSettingsw)
+			}
+			if !f.isLeader {
+				filterLogger.Debugf("preparing new leader settings event")
+				f.outLeaderSettings = f.outLeaderSettingsOn
+			}
+		case _, ok = <-storageChanges:
+			filterLogger.Debugf("got storage change")
+			if !ok {
+				return watcher.EnsureErr(storagew)
+			}
+			filterLogger.Debugf("preparing new storage event")
+			f.outStorage = f.outStorageOn
+		case <-claimLeadership:
+			if err = f.leadershipClaimDue(); err != nil {
+				return errors.Trace(err)
+			}
+			claimLeadership = time.After(leadershipClaimInterval)
 		case ids, ok := <-actionsw.Changes():
 			filterLogger.Debugf("got %d actions", len(ids))
 			if !ok {
@@ -449,6 +542,15 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		case f.outMeterStatus <- nothing:
 			filterLogger.Debugf("sent meter status change event")
 			f.outMeterStatus = nil
+		case f.outLeaderElected <- nothing:
+			filterLogger.Debugf("sent leader elected event")
+			f.outLeaderElected = nil
+		case f.outLeaderSettings <- nothing:
+			filterLogger.Debugf("sent leader settings event")
+			f.outLeaderSettings = nil
+		case f.outStorage <- nothing:
+			filterLogger.Debugf("sent storage event")
+			f.outStorage = nil
 		// Handle explicit requests.
 		case curl := <-f.setCharm:
 			filterLogger.Debugf("changing charm to %q", curl)
@@ -536,6 +638,33 @@ func (f *filter) meterStatusChanged() error {
 	return nil
 }
 
+// leadershipClaimDue claims, or renews, leadership of the unit's
+// service while the unit is alive, and prepares a leader elected
+// event when the unit becomes leader.
+func (f *filter) leadershipClaimDue() error {
+	if f.life != params.Alive {
+		// A unit that is going away should let its lease expire,
+		// so that another unit can take over.
+		f.isLeader = false
+		f.outLeaderElected = nil
+		return nil
+	}
+	isLeader, err := f.unit.ClaimLeadership()
+	if err != nil {
+		return err
+	}
+	if isLeader && !f.isLeader {
+		filterLogger.Infof("unit is now the service leader")
+		f.outLeaderElected = f.outLeaderElectedOn
+		f.outLeaderSettings = nil
+	} else if !isLeader && f.isLeader {
+		filterLogger.Infof("unit is no longer the service leader")
+		f.outLeaderElected = nil
+	}
+	f.isLeader = isLeader
+	return nil
+}
+
 // unitChanged responds to changes in the unit.
 func (f *filter) unitChanged() error {
 	if err := f.unit.Refresh(); err != nil {
@@ -707,3 +707,90 @@ func (s *FilterSuite) TestMeterStatusEvents(c *gc.C) {
 	}
 	assertChange()
 }
+
+func (s *FilterSuite) TestLeaderElectedEvents(c *gc.C) {
+	f, err := newFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, f)
+	asserter := coretesting.NotifyAsserterC{
+		Precond: func() { s.BackingState.StartSync() },
+		C:       c,
+		Chan:    f.LeaderElectedEvents(),
+	}
+	// The unit claims leadership as soon as the filter starts.
+	asserter.AssertOneReceive()
+	leader, err := s.wordpress.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, s.unit.Name())
+
+	// The leader does not see changes to leader settings.
+	settingsAsserter := coretesting.NotifyAsserterC{
+		Precond: func() { s.BackingState.StartSync() },
+		C:       c,
+		Chan:    f.LeaderSettingsEvents(),
+	}
+	err = s.wordpress.UpdateLeaderSettings(s.unit.Name(), map[string]string{"foo": "bar"})
+	c.Assert(err, gc.IsNil)
+	settingsAsserter.AssertNoReceive()
+}
+
+func (s *FilterSuite) TestLeaderSettingsEvents(c *gc.C) {
+	// Make another unit the leader, so that the filter's unit is not.
+	otherUnit, err := s.wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	err = s.wordpress.ClaimLeadership(otherUnit.Name())
+	c.Assert(err, gc.IsNil)
+
+	f, err := newFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, f)
+	electedAsserter := coretesting.NotifyAsserterC{
+		Precond: func() { s.BackingState.StartSync() },
+		C:       c,
+		Chan:    f.LeaderElectedEvents(),
+	}
+	asserter := coretesting.NotifyAsserterC{
+		Precond: func() { s.BackingState.StartSync() },
+		C:       c,
+		Chan:    f.LeaderSettingsEvents(),
+	}
+	electedAsserter.AssertNoReceive()
+	// Initial settings do not trigger an event.
+	asserter.AssertNoReceive()
+
+	err = s.wordpress.UpdateLeaderSettings(otherUnit.Name(), map[string]string{"foo": "bar"})
+	c.Assert(err, gc.IsNil)
+	asserter.AssertOneReceive()
+
+	// Make sure bundled events arrive properly.
+	for i := 0; i < 5; i++ {
+		err = s.wordpress.UpdateLeaderSettings(otherUnit.Name(), map[string]string{"foo": fmt.Sprint(i)})
+		c.Assert(err, gc.IsNil)
+	}
+	asserter.AssertOneReceive()
+}
+
+func (s *FilterSuite) TestStorageEvents(c *gc.C) {
+	f, err := newFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, f)
+	asserter := coretesting.NotifyAsserterC{
+		Precond: func() { s.BackingState.StartSync() },
+		C:       c,
+		Chan:    f.StorageEvents(),
+	}
+	// The initial storage instances trigger an event, so that the
+	// uniter can catch up with any changes it missed.
+	asserter.AssertOneReceive()
+
+	data, err := s.unit.AddStorageInstance("data", state.StorageKindBlock)
+	c.Assert(err, gc.IsNil)
+	asserter.AssertOneReceive()
+
+	// Make sure bundled events arrive properly.
+	err = data.SetLocation("/dev/sdb", nil)
+	c.Assert(err, gc.IsNil)
+	err = data.Destroy()
+	c.Assert(err, gc.IsNil)
+	asserter.AssertOneReceive()
+}
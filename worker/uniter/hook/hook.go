@@ -11,6 +11,36 @@ import (
 	"gopkg.in/juju/charm.v4/hooks"
 )
 
+// The charm hooks package does not yet know about leadership, so the
+// leadership hook kinds are defined here.
+const (
+	// LeaderElected is run when the unit becomes the leader of its
+	// service.
+	LeaderElected hooks.Kind = "leader-elected"
+
+	// LeaderSettingsChanged is run on units that are not the leader
+	// of their service when the service's leader settings change.
+	LeaderSettingsChanged hooks.Kind = "leader-settings-changed"
+)
+
+// Nor does it know about storage, so the storage hook kinds are also
+// defined here. As with relation hooks, the hook run is named for the
+// storage, such as "data-storage-attached".
+const (
+	// StorageAttached is run when a storage instance is attached to
+	// the unit.
+	StorageAttached hooks.Kind = "storage-attached"
+
+	// StorageDetaching is run when a storage instance attached to the
+	// unit is about to be detached.
+	StorageDetaching hooks.Kind = "storage-detaching"
+)
+
+// IsStorage returns whether the hook kind is a storage hook.
+func IsStorage(kind hooks.Kind) bool {
+	return kind == StorageAttached || kind == StorageDetaching
+}
+
 // Info holds details required to execute a hook. Not all fields are
 // relevant to all Kind values.
 type Info struct {
@@ -31,6 +61,11 @@ type Info struct {
 	// ActionId is the state State.actions ID of the Action document to
 	// be retrieved by RunHook.
 	ActionId string `yaml:"action-id,omitempty"`
+
+	// StorageId is the id of the storage instance associated with the
+	// hook, such as "data/0". It is only set when Kind indicates a
+	// storage hook.
+	StorageId string `yaml:"storage-id,omitempty"`
 }
 
 // Validate returns an error if the info is not valid.
@@ -41,7 +76,13 @@ func (hi Info) Validate() error {
 			return fmt.Errorf("%q hook requires a remote unit", hi.Kind)
 		}
 		fallthrough
-	case hooks.Install, hooks.Start, hooks.ConfigChanged, hooks.UpgradeCharm, hooks.Stop, hooks.RelationBroken, hooks.CollectMetrics, hooks.MeterStatusChanged:
+	case hooks.Install, hooks.Start, hooks.ConfigChanged, hooks.UpgradeCharm, hooks.Stop, hooks.RelationBroken, hooks.CollectMetrics, hooks.MeterStatusChanged,
+		LeaderElected, LeaderSettingsChanged:
+		return nil
+	case StorageAttached, StorageDetaching:
+		if hi.StorageId == "" {
+			return fmt.Errorf("%q hook requires a storage id", hi.Kind)
+		}
 		return nil
 	case hooks.Action:
 		if !names.IsValidAction(hi.ActionId) {
@@ -36,6 +36,17 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.ConfigChanged}, ""},
 	{hook.Info{Kind: hooks.CollectMetrics}, ""},
 	{hook.Info{Kind: hooks.MeterStatusChanged}, ""},
+	{hook.Info{Kind: hook.LeaderElected}, ""},
+	{hook.Info{Kind: hook.LeaderSettingsChanged}, ""},
+	{
+		hook.Info{Kind: hook.StorageAttached},
+		`"storage-attached" hook requires a storage id`,
+	}, {
+		hook.Info{Kind: hook.StorageDetaching},
+		`"storage-detaching" hook requires a storage id`,
+	},
+	{hook.Info{Kind: hook.StorageAttached, StorageId: "data/0"}, ""},
+	{hook.Info{Kind: hook.StorageDetaching, StorageId: "data/0"}, ""},
 	{
 		hook.Info{Kind: hooks.Action},
 		`action id "" cannot be parsed as an action tag`,
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/juju/utils/fslock"
+	"gopkg.in/juju/charm.v4/hooks"
+	"launchpad.net/tomb"
+)
+
+// The units on a machine share a hook execution lock, which juju-run
+// and other workers also take when they need isolation from hooks.
+// Up to hook-concurrency hooks hold it at once; hooks that typically
+// install packages, and commands run in a hook context, hold it
+// exclusively, so that co-located units never contend for the dpkg
+// lock.
+
+// exclusiveHook reports whether the given hook must not run alongside
+// the hooks of other units on the machine, whatever the environment's
+// hook-concurrency.
+func exclusiveHook(kind hooks.Kind) bool {
+	switch kind {
+	case hooks.Install, hooks.UpgradeCharm:
+		return true
+	}
+	return false
+}
+
+// setHookConcurrency records how many hooks may run at once on the
+// unit's machine.
+func (u *Uniter) setHookConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	atomic.StoreInt32(&u.hookConcurrency, int32(n))
+}
+
+// acquireHookLock acquires the hook execution lock with the given
+// message, and returns the lock that must be unlocked to release it.
+// Unless exclusive is true, the lock is shared with up to
+// hook-concurrency hooks.
+func (u *Uniter) acquireHookLock(message string, exclusive bool) (*fslock.Lock, error) {
+	// We want to make sure we don't block forever when locking, but take the
+	// tomb into account.
+	checkTomb := func() error {
+		select {
+		case <-u.tomb.Dying():
+			return tomb.ErrDying
+		default:
+			// no-op to fall through to return.
+		}
+		return nil
+	}
+	if exclusive {
+		return u.hookLock.LockExclusive(message, checkTomb)
+	}
+	width := int(atomic.LoadInt32(&u.hookConcurrency))
+	return u.hookLock.LockShared(message, width, checkTomb)
+}
+
+// breakStaleHookLocks breaks any hook execution locks recorded as held
+// by this unit. It is likely that the unit agent died while running a
+// hook, and has been restarted by upstart.
+func (u *Uniter) breakStaleHookLocks() error {
+	locks, err := u.hookLock.All()
+	if err != nil {
+		return err
+	}
+	for _, lock := range locks {
+		message := lock.Message()
+		if !lock.IsLocked() || message == "" {
+			continue
+		}
+		parts := strings.SplitN(message, ":", 2)
+		if len(parts) > 1 && parts[0] == u.unit.Name() {
+			if err := lock.BreakLock(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
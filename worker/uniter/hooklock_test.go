@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/fslock"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/hooklock"
+	"github.com/juju/juju/worker/uniter"
+)
+
+type HookLockSuite struct {
+	testing.IsolationSuite
+	hookLock *hooklock.Lock
+}
+
+var _ = gc.Suite(&HookLockSuite{})
+
+func (s *HookLockSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.PatchValue(&hooklock.RetryDelay, 10*time.Millisecond)
+	s.PatchValue(&fslock.LockWaitDelay, 10*time.Millisecond)
+	var err error
+	s.hookLock, err = hooklock.New(filepath.Join(c.MkDir(), "locks"))
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *HookLockSuite) TestSingleLock(c *gc.C) {
+	lock, err := uniter.AcquireHookLock(s.hookLock, 1, "u/0: running hook", false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(lock.IsLockHeld(), jc.IsTrue)
+	err = lock.Unlock()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *HookLockSuite) TestConcurrentHooksUseFreeLock(c *gc.C) {
+	first, err := uniter.AcquireHookLock(s.hookLock, 2, "u/0: running hook", false)
+	c.Assert(err, gc.IsNil)
+	defer first.Unlock()
+
+	lock, err := uniter.AcquireHookLock(s.hookLock, 2, "u/1: running hook", false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(lock.IsLockHeld(), jc.IsTrue)
+	c.Assert(lock.Message(), gc.Equals, "u/1: running hook")
+	err = lock.Unlock()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *HookLockSuite) TestExclusiveHooksWaitForAllHooks(c *gc.C) {
+	first, err := uniter.AcquireHookLock(s.hookLock, 4, "u/0: running hook", false)
+	c.Assert(err, gc.IsNil)
+
+	locked := make(chan error, 1)
+	go func() {
+		lock, err := uniter.AcquireHookLock(s.hookLock, 4, "u/1: running hook \"install\"", true)
+		if err == nil {
+			err = lock.Unlock()
+		}
+		locked <- err
+	}()
+	select {
+	case <-locked:
+		c.Fatalf("exclusive hook ran alongside another hook")
+	case <-time.After(coretesting.ShortWait):
+	}
+	err = first.Unlock()
+	c.Assert(err, gc.IsNil)
+	select {
+	case err := <-locked:
+		c.Assert(err, gc.IsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("exclusive hook never ran")
+	}
+}
@@ -28,6 +28,10 @@ type Context interface {
 	// PrivateAddress returns the executing unit's private address.
 	PrivateAddress() (string, bool)
 
+	// MachineAddresses returns all the addresses of the executing
+	// unit's machine.
+	MachineAddresses() []network.Address
+
 	// OpenPorst marks the supplied port range for opening when the
 	// executing unit's service is exposed.
 	OpenPorts(protocol string, fromPort, toPort int) error
@@ -75,12 +79,60 @@ type Context interface {
 	// currently participating in.
 	RelationIds() []int
 
+	// MaxRelationSettingsSize returns the largest size, in bytes, that
+	// the executing unit's settings in a relation may grow to, or zero
+	// if the size is not limited.
+	MaxRelationSettingsSize() int
+
 	// OwnerTag returns the user tag of the service the executing
 	// units belongs to.
 	OwnerTag() string
 
 	// AddMetric records a metric to return after hook execution.
 	AddMetrics(string, string, time.Time) error
+
+	// IsLeader returns whether the executing unit is the leader of
+	// its service.
+	IsLeader() (bool, error)
+
+	// LeaderSettings returns the settings written by the leader of
+	// the executing unit's service.
+	LeaderSettings() (map[string]string, error)
+
+	// WriteLeaderSettings applies the supplied changes to the leader
+	// settings of the executing unit's service; keys with empty values
+	// are deleted. It fails if the executing unit is not the leader.
+	WriteLeaderSettings(map[string]string) error
+
+	// HookStorageId returns the id of the storage instance the hook
+	// execution is associated with if there is one, and whether there
+	// is.
+	HookStorageId() (string, bool)
+
+	// Storage returns the attached storage instance with the supplied
+	// id owned by the executing unit. An error satisfying
+	// errors.IsNotFound is returned if there is none.
+	Storage(id string) (ContextStorage, error)
+}
+
+// ContextStorage expresses the capabilities of a hook with respect to
+// a storage instance attached to the unit.
+type ContextStorage interface {
+
+	// Id returns the id of the storage instance, such as "data/0".
+	Id() string
+
+	// Kind returns the kind of the storage instance: "block" or
+	// "filesystem".
+	Kind() string
+
+	// Location returns the path of the block device, or the mount
+	// point of the filesystem.
+	Location() string
+
+	// Attributes returns the provider-specific attributes of the
+	// storage instance.
+	Attributes() map[string]string
 }
 
 // ContextRelation expresses the capabilities of a hook with respect to a relation.
@@ -0,0 +1,52 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type IsLeaderSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&IsLeaderSuite{})
+
+var isLeaderTests = []struct {
+	isLeader bool
+	args     []string
+	out      string
+}{
+	{true, nil, "True\n"},
+	{false, nil, "False\n"},
+	{true, []string{"--format", "yaml"}, "true\n"},
+	{false, []string{"--format", "json"}, "false\n"},
+}
+
+func (s *IsLeaderSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range isLeaderTests {
+		c.Logf("test %d: %v %v", i, t.isLeader, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.isLeader = t.isLeader
+		com, err := jujuc.NewCommand(hctx, cmdString("is-leader"))
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *IsLeaderSuite) TestUnknownArg(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("is-leader"))
+	c.Assert(err, gc.IsNil)
+	err = testing.InitCommand(com, []string{"blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
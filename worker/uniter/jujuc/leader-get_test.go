@@ -0,0 +1,53 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type LeaderGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&LeaderGetSuite{})
+
+var leaderGetTests = []struct {
+	args []string
+	out  string
+}{
+	{nil, "foo: bar\nspam: eggs\n"},
+	{[]string{"-"}, "foo: bar\nspam: eggs\n"},
+	{[]string{"foo"}, "bar\n"},
+	{[]string{"missing"}, ""},
+	{[]string{"--format", "json"}, `{"foo":"bar","spam":"eggs"}` + "\n"},
+	{[]string{"--format", "json", "foo"}, `"bar"` + "\n"},
+}
+
+func (s *LeaderGetSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range leaderGetTests {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.leaderSettings = map[string]string{"foo": "bar", "spam": "eggs"}
+		com, err := jujuc.NewCommand(hctx, cmdString("leader-get"))
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *LeaderGetSuite) TestUnknownArg(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-get"))
+	c.Assert(err, gc.IsNil)
+	err = testing.InitCommand(com, []string{"foo", "blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
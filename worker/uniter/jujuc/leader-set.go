@@ -0,0 +1,57 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// LeaderSetCommand implements the leader-set command.
+type LeaderSetCommand struct {
+	cmd.CommandBase
+	ctx      Context
+	Settings map[string]string
+}
+
+func NewLeaderSetCommand(ctx Context) cmd.Command {
+	return &LeaderSetCommand{ctx: ctx, Settings: map[string]string{}}
+}
+
+func (c *LeaderSetCommand) Info() *cmd.Info {
+	doc := `
+leader-set immediately writes the key/value pairs to the state server, which
+will then inform non-leader units of the change. It will fail if called without
+arguments, or if called by a unit that is not currently service leader. Setting
+a key to an empty value deletes it.
+`
+	return &cmd.Info{
+		Name:    "leader-set",
+		Args:    "<key>=<value> [...]",
+		Purpose: "write service leadership settings",
+		Doc:     doc,
+	}
+}
+
+func (c *LeaderSetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no settings specified")
+	}
+	for _, kv := range args {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return fmt.Errorf(`expected "key=value", got %q`, kv)
+		}
+		c.Settings[parts[0]] = parts[1]
+	}
+	return nil
+}
+
+func (c *LeaderSetCommand) Run(_ *cmd.Context) error {
+	err := c.ctx.WriteLeaderSettings(c.Settings)
+	return errors.Annotate(err, "cannot write leadership settings")
+}
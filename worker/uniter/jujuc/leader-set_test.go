@@ -0,0 +1,63 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type LeaderSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&LeaderSetSuite{})
+
+func (s *LeaderSetSuite) TestInit(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no settings specified"},
+		{[]string{"foo"}, `expected "key=value", got "foo"`},
+		{[]string{"=bar"}, `expected "key=value", got "=bar"`},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		com, err := jujuc.NewCommand(hctx, cmdString("leader-set"))
+		c.Assert(err, gc.IsNil)
+		err = testing.InitCommand(com, t.args)
+		c.Assert(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *LeaderSetSuite) TestRun(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.isLeader = true
+	hctx.leaderSettings = map[string]string{"foo": "bar", "spam": "eggs"}
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-set"))
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"foo=baz", "spam=", "new=value=with=equals"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(hctx.leaderSettings, gc.DeepEquals, map[string]string{
+		"foo": "baz",
+		"new": "value=with=equals",
+	})
+}
+
+func (s *LeaderSetSuite) TestRunNotLeader(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-set"))
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"foo=bar"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "error: cannot write leadership settings: not the leader\n")
+	c.Assert(hctx.leaderSettings, gc.HasLen, 0)
+}
@@ -93,6 +93,11 @@ func (c *RelationGetCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 	if c.Key == "" {
+		if settings == nil {
+			// Unset settings are written as an empty map, not
+			// null, so that the output is always a map.
+			settings = params.RelationSettings{}
+		}
 		return c.out.Write(ctx, settings)
 	}
 	if value, ok := settings[c.Key]; ok {
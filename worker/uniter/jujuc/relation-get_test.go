@@ -27,6 +27,7 @@ func (s *RelationGetSuite) SetUpTest(c *gc.C) {
 	s.rels[0].units["u/0"]["private-address"] = "foo: bar\n"
 	s.rels[1].units["m/0"] = Settings{"pew": "pew\npew\n"}
 	s.rels[1].units["u/1"] = Settings{"value": "12345"}
+	s.rels[1].units["u/2"] = Settings{}
 }
 
 var relationGetTests = []struct {
@@ -156,6 +157,11 @@ var relationGetTests = []struct {
 		relid:   1,
 		args:    []string{"missing", "u/1", "--format", "json"},
 		out:     `null`,
+	}, {
+		summary: "json formatting 5",
+		relid:   1,
+		args:    []string{"-", "u/2", "--format", "json"},
+		out:     `{}`,
 	}, {
 		summary: "yaml formatting 1",
 		relid:   1,
@@ -10,6 +10,8 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
 )
 
 // RelationSetCommand implements the relation-set command.
@@ -47,6 +49,10 @@ func (c *RelationSetCommand) Init(args []string) error {
 		if len(parts) != 2 || len(parts[0]) == 0 {
 			return fmt.Errorf(`expected "key=value", got %q`, kv)
 		}
+		if strings.HasPrefix(parts[0], params.ReservedRelationSettingsPrefix) {
+			return fmt.Errorf("cannot set %q: keys starting with %q are reserved",
+				parts[0], params.ReservedRelationSettingsPrefix)
+		}
 		c.Settings[parts[0]] = parts[1]
 	}
 	return nil
@@ -64,6 +70,9 @@ func (c *RelationSetCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return errors.Annotate(err, "cannot read relation settings")
 	}
+	if err := c.checkSize(settings.Map()); err != nil {
+		return err
+	}
 	for k, v := range c.Settings {
 		if v != "" {
 			settings.Set(k, v)
@@ -73,3 +82,27 @@ func (c *RelationSetCommand) Run(ctx *cmd.Context) (err error) {
 	}
 	return nil
 }
+
+// checkSize returns an error if applying the command's settings to the
+// given settings would make them larger than the context allows.
+func (c *RelationSetCommand) checkSize(current params.RelationSettings) error {
+	maxSize := c.ctx.MaxRelationSettingsSize()
+	if maxSize <= 0 {
+		return nil
+	}
+	updated := make(params.RelationSettings)
+	for k, v := range current {
+		updated[k] = v
+	}
+	for k, v := range c.Settings {
+		if v != "" {
+			updated[k] = v
+		} else {
+			delete(updated, k)
+		}
+	}
+	if size := updated.Size(); size > maxSize {
+		return fmt.Errorf("relation settings too large: %d bytes exceeds the limit of %d bytes", size, maxSize)
+	}
+	return nil
+}
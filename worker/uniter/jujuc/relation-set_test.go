@@ -146,6 +146,10 @@ var relationSetInitTests = []struct {
 		args:     []string{"foo=123", "bar=true", "baz=4.5", "qux="},
 		relid:    1,
 		settings: map[string]string{"foo": "123", "bar": "true", "baz": "4.5", "qux": ""},
+	}, {
+		ctxrelid: 1,
+		args:     []string{"juju-foo=bar"},
+		err:      `cannot set "juju-foo": keys starting with "juju-" are reserved`,
 	},
 }
 
@@ -214,6 +218,28 @@ func (s *RelationSetSuite) TestRun(c *gc.C) {
 	}
 }
 
+func (s *RelationSetSuite) TestRunTooLarge(c *gc.C) {
+	hctx := s.GetHookContext(c, 0, "")
+	hctx.maxRelationSettingsSize = 20
+	basic := Settings{"base": "value"}
+	hctx.rels[1].units["u/0"] = basic
+
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-set"))
+	c.Assert(err, gc.IsNil)
+	rset := com.(*jujuc.RelationSetCommand)
+	rset.RelationId = 1
+	rset.Settings = map[string]string{"foo": "a-value-that-is-too-long"}
+	err = com.Run(testing.Context(c))
+	c.Assert(err, gc.ErrorMatches, "relation settings too large: 36 bytes exceeds the limit of 20 bytes")
+	c.Assert(hctx.rels[1].units["u/0"], gc.DeepEquals, Settings{"base": "value"})
+
+	// Removing settings makes room for others.
+	rset.Settings = map[string]string{"base": "", "foo": "bar"}
+	err = com.Run(testing.Context(c))
+	c.Assert(err, gc.IsNil)
+	c.Assert(hctx.rels[1].units["u/0"], gc.DeepEquals, Settings{"foo": "bar"})
+}
+
 func (s *RelationSetSuite) TestRunDeprecationWarning(c *gc.C) {
 	hctx := s.GetHookContext(c, 0, "")
 	com, _ := jujuc.NewCommand(hctx, cmdString("relation-set"))
@@ -41,6 +41,10 @@ var newCommands = map[string]func(Context) cmd.Command{
 	"unit-get" + cmdSuffix:      NewUnitGetCommand,
 	"owner-get" + cmdSuffix:     NewOwnerGetCommand,
 	"add-metric" + cmdSuffix:    NewAddMetricCommand,
+	"is-leader" + cmdSuffix:     NewIsLeaderCommand,
+	"leader-get" + cmdSuffix:    NewLeaderGetCommand,
+	"leader-set" + cmdSuffix:    NewLeaderSetCommand,
+	"storage-get" + cmdSuffix:   NewStorageGetCommand,
 }
 
 // CommandNames returns the names of all jujuc commands.
@@ -202,6 +202,10 @@ var newCommandTests = []struct {
 	{"relation-list", ""},
 	{"relation-set", ""},
 	{"unit-get", ""},
+	{"is-leader", ""},
+	{"leader-get", ""},
+	{"leader-set", ""},
+	{"storage-get", ""},
 	// The error message contains .exe on Windows
 	{"random", "unknown command: random(.exe)?"},
 }
@@ -0,0 +1,81 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// StorageGetCommand implements the storage-get command.
+type StorageGetCommand struct {
+	cmd.CommandBase
+	ctx       Context
+	StorageId string
+	Key       string // The key to show. If empty, show all.
+	out       cmd.Output
+}
+
+func NewStorageGetCommand(ctx Context) cmd.Command {
+	return &StorageGetCommand{ctx: ctx}
+}
+
+func (c *StorageGetCommand) Info() *cmd.Info {
+	doc := `
+storage-get prints information about a storage instance attached to the unit:
+its "kind", "location" and provider-specific "attributes". If a key is given,
+only that value is printed.
+
+-s defaults to the storage instance of the executing storage hook, and must be
+given in other hooks.
+`
+	return &cmd.Info{
+		Name:    "storage-get",
+		Args:    "[<key>]",
+		Purpose: "print information about a storage instance",
+		Doc:     doc,
+	}
+}
+
+func (c *StorageGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	c.StorageId, _ = c.ctx.HookStorageId()
+	f.StringVar(&c.StorageId, "s", c.StorageId, "specify a storage instance by id")
+}
+
+func (c *StorageGetCommand) Init(args []string) error {
+	if c.StorageId == "" {
+		return fmt.Errorf("no storage instance specified")
+	}
+	if args == nil {
+		return nil
+	}
+	c.Key = args[0]
+	if c.Key == "-" {
+		c.Key = ""
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *StorageGetCommand) Run(ctx *cmd.Context) error {
+	storage, err := c.ctx.Storage(c.StorageId)
+	if err != nil {
+		return errors.Annotatef(err, "cannot read storage instance %q", c.StorageId)
+	}
+	info := map[string]interface{}{
+		"kind":       storage.Kind(),
+		"location":   storage.Location(),
+		"attributes": storage.Attributes(),
+	}
+	if c.Key == "" {
+		return c.out.Write(ctx, info)
+	}
+	if value, ok := info[c.Key]; ok {
+		return c.out.Write(ctx, value)
+	}
+	return fmt.Errorf("unknown key %q", c.Key)
+}
@@ -0,0 +1,90 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type StorageGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&StorageGetSuite{})
+
+var storageGetTests = []struct {
+	args []string
+	out  string
+}{
+	{nil, "attributes:\n  size: \"1024\"\nkind: filesystem\nlocation: /srv/data\n"},
+	{[]string{"-"}, "attributes:\n  size: \"1024\"\nkind: filesystem\nlocation: /srv/data\n"},
+	{[]string{"location"}, "/srv/data\n"},
+	{[]string{"kind"}, "filesystem\n"},
+	{[]string{"--format", "json", "location"}, `"/srv/data"` + "\n"},
+	{[]string{"-s", "data/0", "location"}, "/srv/data\n"},
+}
+
+func (s *StorageGetSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range storageGetTests {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.storageId = "data/0"
+		com, err := jujuc.NewCommand(hctx, cmdString("storage-get"))
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *StorageGetSuite) TestOutsideStorageHook(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("storage-get"))
+	c.Assert(err, gc.IsNil)
+	err = testing.InitCommand(com, nil)
+	c.Assert(err, gc.ErrorMatches, "no storage instance specified")
+
+	com, err = jujuc.NewCommand(hctx, cmdString("storage-get"))
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"-s", "data/0", "kind"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "filesystem\n")
+}
+
+func (s *StorageGetSuite) TestUnknownStorage(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("storage-get"))
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"-s", "logs/1"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "error: cannot read storage instance \"logs/1\": storage instance \"logs/1\" not found\n")
+}
+
+func (s *StorageGetSuite) TestUnknownKey(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.storageId = "data/0"
+	com, err := jujuc.NewCommand(hctx, cmdString("storage-get"))
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"size"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "error: unknown key \"size\"\n")
+}
+
+func (s *StorageGetSuite) TestUnknownArg(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.storageId = "data/0"
+	com, err := jujuc.NewCommand(hctx, cmdString("storage-get"))
+	c.Assert(err, gc.IsNil)
+	err = testing.InitCommand(com, []string{"location", "blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
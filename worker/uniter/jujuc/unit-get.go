@@ -24,10 +24,14 @@ func NewUnitGetCommand(ctx Context) cmd.Command {
 }
 
 func (c *UnitGetCommand) Info() *cmd.Info {
+	doc := `network-settings prints all the addresses of the unit's machine, with
+their scope and network, and the private addresses of the units in each
+relation the unit participates in, keyed by relation id.`
 	return &cmd.Info{
 		Name:    "unit-get",
 		Args:    "<setting>",
-		Purpose: "print public-address or private-address",
+		Purpose: "print public-address, private-address or network-settings",
+		Doc:     doc,
 	}
 }
 
@@ -39,7 +43,9 @@ func (c *UnitGetCommand) Init(args []string) error {
 	if args == nil {
 		return errors.New("no setting specified")
 	}
-	if args[0] != "private-address" && args[0] != "public-address" {
+	switch args[0] {
+	case "private-address", "public-address", "network-settings":
+	default:
 		return fmt.Errorf("unknown setting %q", args[0])
 	}
 	c.Key = args[0]
@@ -47,6 +53,13 @@ func (c *UnitGetCommand) Init(args []string) error {
 }
 
 func (c *UnitGetCommand) Run(ctx *cmd.Context) error {
+	if c.Key == "network-settings" {
+		settings, err := c.networkSettings()
+		if err != nil {
+			return err
+		}
+		return c.out.Write(ctx, settings)
+	}
 	value, ok := "", false
 	if c.Key == "private-address" {
 		value, ok = c.ctx.PrivateAddress()
@@ -58,3 +71,49 @@ func (c *UnitGetCommand) Run(ctx *cmd.Context) error {
 	}
 	return c.out.Write(ctx, value)
 }
+
+// networkSettings returns the unit's addresses, all the addresses of
+// its machine, and the private addresses of related units keyed by
+// relation id and unit name.
+func (c *UnitGetCommand) networkSettings() (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+	if value, ok := c.ctx.PublicAddress(); ok {
+		settings["public-address"] = value
+	}
+	if value, ok := c.ctx.PrivateAddress(); ok {
+		settings["private-address"] = value
+	}
+	addresses := []map[string]string{}
+	for _, addr := range c.ctx.MachineAddresses() {
+		address := map[string]string{
+			"value": addr.Value,
+			"type":  string(addr.Type),
+			"scope": string(addr.Scope),
+		}
+		if addr.NetworkName != "" {
+			address["network"] = addr.NetworkName
+		}
+		addresses = append(addresses, address)
+	}
+	settings["addresses"] = addresses
+	related := make(map[string]map[string]string)
+	for _, id := range c.ctx.RelationIds() {
+		r, found := c.ctx.Relation(id)
+		if !found {
+			continue
+		}
+		units := make(map[string]string)
+		for _, unitName := range r.UnitNames() {
+			unitSettings, err := r.ReadSettings(unitName)
+			if err != nil {
+				return nil, err
+			}
+			if value, ok := unitSettings["private-address"]; ok {
+				units[unitName] = value
+			}
+		}
+		related[r.FakeId()] = units
+	}
+	settings["related-units"] = related
+	return settings, nil
+}
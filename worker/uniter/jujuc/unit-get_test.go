@@ -57,13 +57,17 @@ func (s *UnitGetSuite) TestHelp(c *gc.C) {
 	code := cmd.Main(com, ctx, []string{"--help"})
 	c.Assert(code, gc.Equals, 0)
 	c.Assert(bufferString(ctx.Stdout), gc.Equals, `usage: unit-get [options] <setting>
-purpose: print public-address or private-address
+purpose: print public-address, private-address or network-settings
 
 options:
 --format  (= smart)
     specify output format (json|smart|yaml)
 -o, --output (= "")
     specify an output file
+
+network-settings prints all the addresses of the unit's machine, with
+their scope and network, and the private addresses of the units in each
+relation the unit participates in, keyed by relation id.
 `)
 	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
 }
@@ -80,6 +84,30 @@ func (s *UnitGetSuite) TestOutputPath(c *gc.C) {
 	c.Assert(string(content), gc.Equals, "192.168.0.99\n")
 }
 
+func (s *UnitGetSuite) TestNetworkSettings(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"network-settings"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `
+addresses:
+- scope: local-cloud
+  type: ipv4
+  value: 192.168.0.99
+- scope: public
+  type: hostname
+  value: gimli.minecraft.testing.invalid
+private-address: 192.168.0.99
+public-address: gimli.minecraft.testing.invalid
+related-units:
+  peer0:0:
+    u/0: u-0.testing.invalid
+  peer1:1:
+    u/0: u-0.testing.invalid
+`[1:])
+}
+
 func (s *UnitGetSuite) TestUnknownSetting(c *gc.C) {
 	com := s.createCommand(c)
 	err := testing.InitCommand(com, []string{"protected-address"})
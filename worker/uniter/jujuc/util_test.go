@@ -11,6 +11,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/juju/errors"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v4"
 
@@ -63,6 +64,14 @@ func (s *ContextSuite) GetHookContext(c *gc.C, relid int, remote string) *Contex
 		relid:  relid,
 		remote: remote,
 		rels:   s.rels,
+		storage: map[string]*ContextStorage{
+			"data/0": {
+				id:         "data/0",
+				kind:       "filesystem",
+				location:   "/srv/data",
+				attributes: map[string]string{"size": "1024"},
+			},
+		},
 	}
 }
 
@@ -78,12 +87,17 @@ func setSettings(c *gc.C, ru *state.RelationUnit, settings map[string]interface{
 }
 
 type Context struct {
-	ports         []network.PortRange
-	relid         int
-	remote        string
-	rels          map[int]*ContextRelation
-	metrics       []jujuc.Metric
-	canAddMetrics bool
+	ports                   []network.PortRange
+	relid                   int
+	remote                  string
+	rels                    map[int]*ContextRelation
+	metrics                 []jujuc.Metric
+	canAddMetrics           bool
+	isLeader                bool
+	leaderSettings          map[string]string
+	maxRelationSettingsSize int
+	storageId               string
+	storage                 map[string]*ContextStorage
 }
 
 func (c *Context) AddMetrics(key, value string, created time.Time) error {
@@ -106,6 +120,13 @@ func (c *Context) PrivateAddress() (string, bool) {
 	return "192.168.0.99", true
 }
 
+func (c *Context) MachineAddresses() []network.Address {
+	return []network.Address{
+		network.NewAddress("192.168.0.99", network.ScopeCloudLocal),
+		network.NewAddress("gimli.minecraft.testing.invalid", network.ScopePublic),
+	}
+}
+
 func (c *Context) OpenPorts(protocol string, fromPort, toPort int) error {
 	c.ports = append(c.ports, network.PortRange{
 		Protocol: protocol,
@@ -183,10 +204,77 @@ func (c *Context) RelationIds() []int {
 	return ids
 }
 
+func (c *Context) MaxRelationSettingsSize() int {
+	return c.maxRelationSettingsSize
+}
+
 func (c *Context) OwnerTag() string {
 	return "test-owner"
 }
 
+func (c *Context) IsLeader() (bool, error) {
+	return c.isLeader, nil
+}
+
+func (c *Context) LeaderSettings() (map[string]string, error) {
+	settings := map[string]string{}
+	for k, v := range c.leaderSettings {
+		settings[k] = v
+	}
+	return settings, nil
+}
+
+func (c *Context) WriteLeaderSettings(settings map[string]string) error {
+	if !c.isLeader {
+		return fmt.Errorf("not the leader")
+	}
+	if c.leaderSettings == nil {
+		c.leaderSettings = map[string]string{}
+	}
+	for k, v := range settings {
+		if v == "" {
+			delete(c.leaderSettings, k)
+		} else {
+			c.leaderSettings[k] = v
+		}
+	}
+	return nil
+}
+
+func (c *Context) HookStorageId() (string, bool) {
+	return c.storageId, c.storageId != ""
+}
+
+func (c *Context) Storage(id string) (jujuc.ContextStorage, error) {
+	if storage, found := c.storage[id]; found {
+		return storage, nil
+	}
+	return nil, errors.NotFoundf("storage instance %q", id)
+}
+
+type ContextStorage struct {
+	id         string
+	kind       string
+	location   string
+	attributes map[string]string
+}
+
+func (s *ContextStorage) Id() string {
+	return s.id
+}
+
+func (s *ContextStorage) Kind() string {
+	return s.kind
+}
+
+func (s *ContextStorage) Location() string {
+	return s.location
+}
+
+func (s *ContextStorage) Attributes() map[string]string {
+	return s.attributes
+}
+
 type ContextRelation struct {
 	id    int
 	name  string
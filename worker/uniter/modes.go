@@ -43,6 +43,7 @@ func ModeContinue(u *Uniter) (next Mode, err error) {
 		} else if err != nil {
 			return nil, err
 		}
+		u.stopHookKilled = u.operationState.StopHookKilled
 	}
 
 	// Filter out states not related to charm deployment.
@@ -163,7 +164,11 @@ func ModeStopping(u *Uniter) (next Mode, err error) {
 // ModeTerminating marks the unit dead and returns ErrTerminateAgent.
 func ModeTerminating(u *Uniter) (next Mode, err error) {
 	defer modeContext("ModeTerminating", &err)()
-	if err = u.unit.SetStatus(params.StatusStopped, "", nil); err != nil {
+	var info string
+	if u.stopHookKilled != "" {
+		info = fmt.Sprintf("forced stop: %s", u.stopHookKilled)
+	}
+	if err = u.unit.SetStatus(params.StatusStopped, info, nil); err != nil {
 		return nil, err
 	}
 	w, err := u.unit.Watch()
@@ -209,6 +214,7 @@ func ModeTerminating(u *Uniter) (next Mode, err error) {
 // * service configuration changes
 // * charm upgrade requests
 // * relation changes
+// * storage changes
 // * unit death
 func ModeAbide(u *Uniter) (next Mode, err error) {
 	defer modeContext("ModeAbide", &err)()
@@ -232,6 +238,13 @@ func ModeAbide(u *Uniter) (next Mode, err error) {
 			}
 		}
 	}()
+	// Catch up with any storage changes still outstanding after a
+	// storage hook failed or the uniter restarted.
+	if err := u.runStorageHooks(); err == errHookFailed {
+		return ModeHookError, nil
+	} else if err != nil {
+		return nil, err
+	}
 	select {
 	case <-u.f.UnitDying():
 		return modeAbideDyingLoop(u)
@@ -256,6 +269,10 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 			return modeAbideDyingLoop(u)
 		case <-u.f.MeterStatusEvents():
 			hi = hook.Info{Kind: hooks.MeterStatusChanged}
+		case <-u.f.LeaderElectedEvents():
+			hi = hook.Info{Kind: hook.LeaderElected}
+		case <-u.f.LeaderSettingsEvents():
+			hi = hook.Info{Kind: hook.LeaderSettingsChanged}
 		case <-u.f.ConfigEvents():
 			hi = hook.Info{Kind: hooks.ConfigChanged}
 		case info := <-u.f.ActionEvents():
@@ -272,6 +289,13 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 				r.StartHooks()
 			}
 			continue
+		case <-u.f.StorageEvents():
+			if err := u.runStorageHooks(); err == errHookFailed {
+				return ModeHookError, nil
+			} else if err != nil {
+				return nil, err
+			}
+			continue
 		case curl := <-u.f.UpgradeEvents():
 			return ModeUpgrading(curl), nil
 		}
@@ -312,6 +336,13 @@ func modeAbideDyingLoop(u *Uniter) (next Mode, err error) {
 		case info := <-u.f.ActionEvents():
 			hi = hook.Info{Kind: info.Kind, ActionId: info.ActionId}
 		case hi = <-u.relationHooks:
+		case <-u.f.StorageEvents():
+			if err := u.runStorageHooks(); err == errHookFailed {
+				return ModeHookError, nil
+			} else if err != nil {
+				return nil, err
+			}
+			continue
 		}
 		if err = u.runHook(hi); err == errHookFailed {
 			return ModeHookError, nil
@@ -323,6 +354,7 @@ func modeAbideDyingLoop(u *Uniter) (next Mode, err error) {
 
 // ModeHookError is responsible for watching and responding to:
 // * user resolution of hook errors
+// * automatic retries of the failed hook, as configured by the hook policy
 // * forced charm upgrade requests
 func ModeHookError(u *Uniter) (next Mode, err error) {
 	// TODO(binary132): In case of a crashed Action, simply set it to
@@ -345,10 +377,41 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 	}
 	u.f.WantResolvedEvent()
 	u.f.WantUpgradeEvent(true)
+
+	// Schedule automatic retries of the failed hook, if the service's
+	// hook policy asks for them; each retry waits twice as long as the
+	// one before.
+	policy, err := u.hookPolicy()
+	if err != nil {
+		return nil, err
+	}
+	retries := 0
+	retryDelay := policy.RetryDelay
+	var retry <-chan time.Time
+	if policy.RetryCount > 0 {
+		retry = time.After(retryDelay)
+	}
 	for {
 		select {
 		case <-u.tomb.Dying():
 			return nil, tomb.ErrDying
+		case <-retry:
+			retries++
+			logger.Infof("retrying hook %q (attempt %d of %d)", u.currentHookName(), retries, policy.RetryCount)
+			err = u.runHook(*u.operationState.Hook)
+			if err == errHookFailed {
+				retry = nil
+				if retries < policy.RetryCount {
+					if retryDelay *= 2; retryDelay > maxHookRetryDelay {
+						retryDelay = maxHookRetryDelay
+					}
+					retry = time.After(retryDelay)
+				}
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			return ModeContinue, nil
 		case rm := <-u.f.ResolvedEvents():
 			switch rm {
 			case params.ResolvedRetryHooks:
@@ -78,6 +78,12 @@ type State struct {
 	// It's set to nil if the hook was not run at all. Recording time as int64
 	// because the yaml encoder cannot encode the time.Time struct.
 	CollectMetricsTime int64 `yaml:"collectmetricstime,omitempty"`
+
+	// StopHookKilled holds why the stop hook was killed after
+	// overrunning its grace period, if it was. It is kept so that
+	// the kill is still reported if the agent restarts before the
+	// unit is removed.
+	StopHookKilled string `yaml:"stophookkilled,omitempty"`
 }
 
 // validate returns an error if the state violates expectations.
@@ -153,7 +159,7 @@ func (f *StateFile) Read() (*State, error) {
 }
 
 // Write stores the supplied state to the file.
-func (f *StateFile) Write(started bool, kind Kind, step Step, hi *hook.Info, url *charm.URL, metricsTime int64) error {
+func (f *StateFile) Write(started bool, kind Kind, step Step, hi *hook.Info, url *charm.URL, metricsTime int64, stopHookKilled string) error {
 	st := &State{
 		Started:            started,
 		Kind:               kind,
@@ -161,6 +167,7 @@ func (f *StateFile) Write(started bool, kind Kind, step Step, hi *hook.Info, url
 		Hook:               hi,
 		CharmURL:           url,
 		CollectMetricsTime: metricsTime,
+		StopHookKilled:     stopHookKilled,
 	}
 	if err := st.validate(); err != nil {
 		panic(err)
@@ -163,6 +163,13 @@ var stateTests = []struct {
 			Hook:               relhook,
 			CollectMetricsTime: now.Unix(),
 		},
+	}, {
+		st: operation.State{
+			Kind:           operation.Continue,
+			Step:           operation.Done,
+			Hook:           &hook.Info{Kind: hooks.Stop},
+			StopHookKilled: "stop timed out after 1s",
+		},
 	},
 }
 
@@ -174,7 +181,7 @@ func (s *StateFileSuite) TestStates(c *gc.C) {
 		_, err := file.Read()
 		c.Assert(err, gc.Equals, operation.ErrNoStateFile)
 		write := func() {
-			err := file.Write(t.st.Started, t.st.Kind, t.st.Step, t.st.Hook, t.st.CharmURL, t.st.CollectMetricsTime)
+			err := file.Write(t.st.Started, t.st.Kind, t.st.Step, t.st.Hook, t.st.CharmURL, t.st.CollectMetricsTime, t.st.StopHookKilled)
 			c.Assert(err, gc.IsNil)
 		}
 		if t.err != "" {
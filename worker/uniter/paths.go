@@ -66,6 +66,10 @@ type StatePaths struct {
 	// DeployerDir holds metadata about charms that are installing or have
 	// been installed.
 	DeployerDir string
+
+	// StorageFile holds the ids of the storage instances the uniter
+	// has attached.
+	StorageFile string
 }
 
 // NewPaths returns the set of filesystem paths that the supplied unit should
@@ -99,6 +103,7 @@ func NewPaths(dataDir string, unitTag names.UnitTag) Paths {
 			RelationsDir:   join(stateDir, "relations"),
 			BundlesDir:     join(stateDir, "bundles"),
 			DeployerDir:    join(stateDir, "deployer"),
+			StorageFile:    join(stateDir, "storage"),
 		},
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"gopkg.in/juju/charm.v4/hooks"
 
 	apiuniter "github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/context"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/relation"
@@ -16,26 +17,33 @@ import (
 
 // Relationer manages a unit's presence in a relation.
 type Relationer struct {
-	ru    *apiuniter.RelationUnit
-	dir   *relation.StateDir
-	queue relation.HookQueue
-	hooks chan<- hook.Info
-	dying bool
+	ru       *apiuniter.RelationUnit
+	dir      *relation.StateDir
+	queue    relation.HookQueue
+	hooks    chan<- hook.Info
+	dying    bool
+	snapshot *context.SettingsSnapshot
 }
 
 // NewRelationer creates a new Relationer. The unit will not join the
 // relation until explicitly requested.
 func NewRelationer(ru *apiuniter.RelationUnit, dir *relation.StateDir, hooks chan<- hook.Info) *Relationer {
 	return &Relationer{
-		ru:    ru,
-		dir:   dir,
-		hooks: hooks,
+		ru:       ru,
+		dir:      dir,
+		hooks:    hooks,
+		snapshot: context.NewSettingsSnapshot(),
 	}
 }
 
 // Context returns a fresh ContextRelation representing r's current state.
+// The settings of remote units are snapshotted across contexts, so that
+// relation-departed and relation-broken hooks can read the final settings
+// of departed units.
 func (r *Relationer) Context() *context.ContextRelation {
-	return context.NewContextRelation(r.ru, r.dir.State().Members)
+	ctx := context.NewContextRelation(r.ru, r.dir.State().Members)
+	ctx.SetSnapshot(r.snapshot)
+	return ctx
 }
 
 // IsImplicit returns whether the local relation endpoint is implicit. Implicit
@@ -62,6 +70,33 @@ func (r *Relationer) Join() error {
 	return r.ru.EnterScope()
 }
 
+// UpdatePrivateAddress rewrites the unit's private-address setting in
+// the relation if the unit's address has changed since it was recorded,
+// so that counterpart units are told about the new address. Relations
+// bound to a network keep the address in that network chosen when the
+// unit entered scope.
+func (r *Relationer) UpdatePrivateAddress() error {
+	if r.dying || r.IsImplicit() || r.ru.Relation().Network() != "" {
+		return nil
+	}
+	address, err := r.ru.PrivateAddress()
+	if params.IsCodeNoAddressSet(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	settings, err := r.ru.Settings()
+	if err != nil {
+		return err
+	}
+	if settings.Map()["private-address"] == address {
+		return nil
+	}
+	logger.Infof("updating private-address of relation %v to %q", r.ru.Relation(), address)
+	settings.Set("private-address", address)
+	return settings.Write()
+}
+
 // SetDying informs the relationer that the unit is departing the relation,
 // and that the only hooks it should send henceforth are -departed hooks,
 // until the relation is empty, followed by a -broken hook.
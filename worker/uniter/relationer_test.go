@@ -171,6 +171,36 @@ func (s *RelationerSuite) TestEnterLeaveScope(c *gc.C) {
 	}
 }
 
+func (s *RelationerSuite) TestUpdatePrivateAddress(c *gc.C) {
+	r := uniter.NewRelationer(s.apiRelUnit, s.dir, s.hooks)
+	err := r.Join()
+	c.Assert(err, gc.IsNil)
+	ru, _ := s.AddRelationUnit(c, "u/1")
+	settings, err := ru.ReadSettings("u/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings["private-address"], gc.Equals, "u-0.testing.invalid")
+
+	// Nothing changes while the address is the same.
+	err = r.UpdatePrivateAddress()
+	c.Assert(err, gc.IsNil)
+
+	// Move the unit's machine to a new address; the relation
+	// settings follow it.
+	unit, err := s.State.Unit("u/0")
+	c.Assert(err, gc.IsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, gc.IsNil)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, gc.IsNil)
+	err = machine.SetAddresses(network.NewAddress("u-0.moved.invalid", network.ScopeCloudLocal))
+	c.Assert(err, gc.IsNil)
+	err = r.UpdatePrivateAddress()
+	c.Assert(err, gc.IsNil)
+	settings, err = ru.ReadSettings("u/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings["private-address"], gc.Equals, "u-0.moved.invalid")
+}
+
 func (s *RelationerSuite) TestStartStopHooks(c *gc.C) {
 	ru1, _ := s.AddRelationUnit(c, "u/1")
 	ru2, _ := s.AddRelationUnit(c, "u/2")
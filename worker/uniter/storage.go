@@ -0,0 +1,128 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/uniter/hook"
+)
+
+// storageTracker records which of the unit's storage instances have
+// been attached, by running their storage-attached hooks, and decides
+// which storage hooks should run next.
+type storageTracker struct {
+	unit     *uniter.Unit
+	path     string
+	attached set.Strings
+}
+
+// newStorageTracker returns a storageTracker for the unit that
+// persists the ids of the attached storage instances in the file at
+// path.
+func newStorageTracker(unit *uniter.Unit, path string) (*storageTracker, error) {
+	var ids []string
+	if err := utils.ReadYaml(path, &ids); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Annotate(err, "cannot read attached storage")
+	}
+	return &storageTracker{
+		unit:     unit,
+		path:     path,
+		attached: set.NewStrings(ids...),
+	}, nil
+}
+
+// nextHook returns the next storage hook to run, or nil if there is
+// none. A storage-attached hook runs for each alive instance that has
+// been given a location, and a storage-detaching hook for each attached
+// instance that is no longer alive. Instances that are no longer alive
+// but were never attached are detached without running any hook.
+func (t *storageTracker) nextHook() (*hook.Info, error) {
+	instances, err := t.unit.StorageInstances()
+	if errors.IsNotImplemented(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	known := set.NewStrings()
+	for _, instance := range instances {
+		known.Add(instance.Id)
+		attached := t.attached.Contains(instance.Id)
+		switch {
+		case instance.Life == params.Alive && instance.Location != "" && !attached:
+			return &hook.Info{Kind: hook.StorageAttached, StorageId: instance.Id}, nil
+		case instance.Life != params.Alive && attached:
+			return &hook.Info{Kind: hook.StorageDetaching, StorageId: instance.Id}, nil
+		case instance.Life != params.Alive:
+			if err := t.detached(instance.Id); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+	// Forget any instances that were removed after they were detached,
+	// but before that was recorded.
+	if removed := t.attached.Difference(known); !removed.IsEmpty() {
+		t.attached = t.attached.Difference(removed)
+		if err := t.write(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return nil, nil
+}
+
+// commitHook records the completion of a storage hook.
+func (t *storageTracker) commitHook(hi hook.Info) error {
+	switch hi.Kind {
+	case hook.StorageAttached:
+		t.attached.Add(hi.StorageId)
+		return t.write()
+	case hook.StorageDetaching:
+		t.attached.Remove(hi.StorageId)
+		if err := t.write(); err != nil {
+			return err
+		}
+		return t.detached(hi.StorageId)
+	}
+	return nil
+}
+
+// detached tells the state server that the unit has finished with the
+// storage instance, so that it can be removed.
+func (t *storageTracker) detached(storageId string) error {
+	err := t.unit.StorageDetached(storageId)
+	if params.IsCodeNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (t *storageTracker) write() error {
+	if err := utils.WriteYaml(t.path, t.attached.SortedValues()); err != nil {
+		return errors.Annotate(err, "cannot record attached storage")
+	}
+	return nil
+}
+
+// runStorageHooks runs storage hooks until the unit's attached storage
+// instances are up to date.
+func (u *Uniter) runStorageHooks() error {
+	for {
+		hi, err := u.storage.nextHook()
+		if err != nil {
+			return err
+		}
+		if hi == nil {
+			return nil
+		}
+		if err := u.runHook(*hi); err != nil {
+			return err
+		}
+	}
+}
@@ -8,7 +8,6 @@ import (
 	stderrors "errors"
 	"fmt"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/juju/cmd"
@@ -16,7 +15,6 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils/exec"
-	"github.com/juju/utils/fslock"
 	corecharm "gopkg.in/juju/charm.v4"
 	"gopkg.in/juju/charm.v4/hooks"
 	"launchpad.net/tomb"
@@ -25,6 +23,7 @@ import (
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/utils/hooklock"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/uniter/charm"
@@ -40,6 +39,10 @@ var logger = loggo.GetLogger("juju.worker.uniter")
 const (
 	// interval at which the unit's metrics should be collected
 	metricsPollInterval = 5 * time.Minute
+
+	// maxHookRetryDelay is the longest the uniter will wait between
+	// automatic retries of a failed hook.
+	maxHookRetryDelay = 30 * time.Minute
 )
 
 // A UniterExecutionObserver gets the appropriate methods called when a hook
@@ -74,17 +77,27 @@ type Uniter struct {
 	service       *uniter.Service
 	relationers   map[int]*Relationer
 	relationHooks chan hook.Info
+	storage       *storageTracker
 
 	paths              Paths
 	deployer           charm.Deployer
 	operationState     *operation.State
 	operationStateFile *operation.StateFile
 	contextFactory     context.Factory
-	hookLock           *fslock.Lock
+	hookLock           *hooklock.Lock
 	runListener        *RunListener
 
+	// hookConcurrency holds the environment's hook-concurrency, and
+	// is accessed atomically.
+	hookConcurrency int32
+
 	ranConfigChanged bool
 
+	// stopHookKilled holds why the stop hook was killed after
+	// overrunning its grace period, if it was. It is recorded in
+	// the operation state.
+	stopHookKilled string
+
 	// The execution observer is only used in tests at this stage. Should this
 	// need to be extended, perhaps a list of observers would be needed.
 	observer UniterExecutionObserver
@@ -93,11 +106,12 @@ type Uniter struct {
 // NewUniter creates a new Uniter which will install, run, and upgrade
 // a charm on behalf of the unit with the given unitTag, by executing
 // hooks and operations provoked by changes in st.
-func NewUniter(st *uniter.State, unitTag names.UnitTag, dataDir string, hookLock *fslock.Lock) *Uniter {
+func NewUniter(st *uniter.State, unitTag names.UnitTag, dataDir string, hookLock *hooklock.Lock) *Uniter {
 	u := &Uniter{
-		st:       st,
-		paths:    NewPaths(dataDir, unitTag),
-		hookLock: hookLock,
+		st:              st,
+		paths:           NewPaths(dataDir, unitTag),
+		hookLock:        hookLock,
+		hookConcurrency: 1,
 	}
 	go func() {
 		defer u.tomb.Done()
@@ -148,18 +162,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 }
 
 func (u *Uniter) setupLocks() (err error) {
-	if message := u.hookLock.Message(); u.hookLock.IsLocked() && message != "" {
-		// Look to see if it was us that held the lock before.  If it was, we
-		// should be safe enough to break it, as it is likely that we died
-		// before unlocking, and have been restarted by upstart.
-		parts := strings.SplitN(message, ":", 2)
-		if len(parts) > 1 && parts[0] == u.unit.Name() {
-			if err := u.hookLock.BreakLock(); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	return u.breakStaleHookLocks()
 }
 
 func (u *Uniter) init(unitTag names.UnitTag) (err error) {
@@ -190,6 +193,10 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 
 	u.relationers = map[int]*Relationer{}
 	u.relationHooks = make(chan hook.Info)
+	u.storage, err = newStorageTracker(u.unit, u.paths.State.StorageFile)
+	if err != nil {
+		return err
+	}
 	u.deployer, err = charm.NewDeployer(
 		u.paths.State.CharmDir,
 		u.paths.State.DeployerDir,
@@ -265,6 +272,7 @@ func (u *Uniter) writeOperationState(kind operation.Kind, step operation.Step, h
 		Hook:               hi,
 		CharmURL:           url,
 		CollectMetricsTime: collectMetricsTime,
+		StopHookKilled:     u.stopHookKilled,
 	}
 	if err := u.operationStateFile.Write(
 		operationState.Started,
@@ -273,6 +281,7 @@ func (u *Uniter) writeOperationState(kind operation.Kind, step operation.Step, h
 		operationState.Hook,
 		operationState.CharmURL,
 		operationState.CollectMetricsTime,
+		operationState.StopHookKilled,
 	); err != nil {
 		return err
 	}
@@ -352,6 +361,10 @@ func (u *Uniter) deploy(curl *corecharm.URL, reason operation.Kind) error {
 // operation is not affected by the error.
 var errHookFailed = stderrors.New("hook execution failed")
 
+// errChaosRestart indicates that the Uniter stopped because chaos testing
+// asked for the unit's agent to be restarted.
+var errChaosRestart = stderrors.New("restarting at chaos testing request")
+
 func (u *Uniter) getRelationContexts() map[int]*context.ContextRelation {
 	ctxRelations := map[int]*context.ContextRelation{}
 	for id, r := range u.relationers {
@@ -360,24 +373,6 @@ func (u *Uniter) getRelationContexts() map[int]*context.ContextRelation {
 	return ctxRelations
 }
 
-func (u *Uniter) acquireHookLock(message string) (err error) {
-	// We want to make sure we don't block forever when locking, but take the
-	// tomb into account.
-	checkTomb := func() error {
-		select {
-		case <-u.tomb.Dying():
-			return tomb.ErrDying
-		default:
-			// no-op to fall through to return.
-		}
-		return nil
-	}
-	if err = u.hookLock.LockWithFunc(message, checkTomb); err != nil {
-		return err
-	}
-	return nil
-}
-
 func (u *Uniter) startJujucServer(context *context.HookContext) (*jujuc.Server, error) {
 	// Prepare server.
 	getCmd := func(ctxId, cmdName string) (cmd.Command, error) {
@@ -398,10 +393,11 @@ func (u *Uniter) startJujucServer(context *context.HookContext) (*jujuc.Server,
 func (u *Uniter) RunCommands(commands string) (results *exec.ExecResponse, err error) {
 	logger.Tracef("run commands: %s", commands)
 	lockMessage := fmt.Sprintf("%s: running commands", u.unit.Name())
-	if err = u.acquireHookLock(lockMessage); err != nil {
+	hookLock, err := u.acquireHookLock(lockMessage, true)
+	if err != nil {
 		return nil, err
 	}
-	defer u.hookLock.Unlock()
+	defer hookLock.Unlock()
 
 	hctx, err := u.contextFactory.NewRunContext()
 	if err != nil {
@@ -482,10 +478,11 @@ func (u *Uniter) runAction(hi hook.Info) (err error) {
 	}
 
 	lockMessage := fmt.Sprintf("%s: running hook %q", u.unit.Name(), actionName)
-	if err = u.acquireHookLock(lockMessage); err != nil {
+	hookLock, err := u.acquireHookLock(lockMessage, false)
+	if err != nil {
 		return err
 	}
-	defer u.hookLock.Unlock()
+	defer hookLock.Unlock()
 
 	hctx, err := u.contextFactory.NewActionContext(tag, actionName, actionParams)
 	if err != nil {
@@ -534,6 +531,40 @@ func (u *Uniter) runAction(hi hook.Info) (err error) {
 	return u.commitHook(hi)
 }
 
+// hookPolicy returns the timeout and retry policy that applies to the
+// unit's hooks. The zero policy, under which hooks never time out and
+// are never retried automatically, is returned if the API server is
+// too old to supply one.
+func (u *Uniter) hookPolicy() (uniter.HookPolicy, error) {
+	policy, err := u.service.HookPolicy()
+	if errors.IsNotImplemented(err) || params.IsCodeNotImplemented(err) {
+		return uniter.HookPolicy{}, nil
+	}
+	return policy, err
+}
+
+// chaos returns the faults that the unit has been asked to inject into
+// its hook execution. No faults are returned if the API server is too
+// old to supply them.
+func (u *Uniter) chaos() (uniter.Chaos, error) {
+	chaos, err := u.unit.Chaos()
+	if errors.IsNotImplemented(err) || params.IsCodeNotImplemented(err) {
+		return uniter.Chaos{}, nil
+	}
+	return chaos, err
+}
+
+// failsHook reports whether the named hook is one that chaos testing
+// has asked to fail.
+func failsHook(chaos uniter.Chaos, hookName string) bool {
+	for _, name := range chaos.FailHooks {
+		if name == "*" || name == hookName {
+			return true
+		}
+	}
+	return false
+}
+
 // runHook executes the supplied hook.Info in an appropriate hook context. If
 // the hook itself fails to execute, it returns errHookFailed.
 func (u *Uniter) runHook(hi hook.Info) (err error) {
@@ -555,16 +586,61 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 			return err
 		}
 	}
+	if hook.IsStorage(hi.Kind) {
+		hookName = context.StorageHookName(hi)
+	}
+	chaos, err := u.chaos()
+	if err != nil {
+		return err
+	}
+	if chaos.Restart {
+		logger.Infof("restarting before %q hook at chaos testing request", hookName)
+		if err := u.unit.ClearChaosRestart(); err != nil {
+			return err
+		}
+		return errChaosRestart
+	}
+	if chaos.HookDelay > 0 {
+		logger.Infof("delaying %q hook by %v at chaos testing request", hookName, chaos.HookDelay)
+		select {
+		case <-u.tomb.Dying():
+			return tomb.ErrDying
+		case <-time.After(chaos.HookDelay):
+		}
+	}
+	if hi.Kind == hooks.ConfigChanged {
+		// config-changed also runs when the unit's address changes;
+		// make sure related units see the new address too.
+		if err := u.updateRelationAddresses(); err != nil {
+			return err
+		}
+	}
 	lockMessage := fmt.Sprintf("%s: running hook %q", u.unit.Name(), hookName)
-	if err = u.acquireHookLock(lockMessage); err != nil {
+	hookLock, err := u.acquireHookLock(lockMessage, exclusiveHook(hi.Kind))
+	if err != nil {
 		return err
 	}
-	defer u.hookLock.Unlock()
+	defer hookLock.Unlock()
 
 	hctx, err := u.contextFactory.NewHookContext(hi)
 	if err != nil {
 		return err
 	}
+	policy, err := u.hookPolicy()
+	if err != nil {
+		return err
+	}
+	// A stop hook given a grace period is killed once it has run for
+	// that long. This bounds how long unit removal waits for it, and
+	// also how long the agent waits for it when asked to shut down,
+	// as it is on machine shutdown.
+	timeout := policy.Timeout
+	stopGracePeriod := hi.Kind == hooks.Stop && policy.StopGracePeriod > 0
+	if stopGracePeriod {
+		timeout = policy.StopGracePeriod
+	}
+	hctx.SetHookTimeout(timeout)
+	hctx.SetHookLimits(policy.MemoryLimit, policy.CPULimit)
 
 	srv, err := u.startJujucServer(hctx)
 	if err != nil {
@@ -576,6 +652,11 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 	if err := u.writeOperationState(operation.RunHook, operation.Pending, &hi, nil); err != nil {
 		return err
 	}
+	if failsHook(chaos, hookName) {
+		logger.Errorf("hook %q failed: failure injected by chaos testing", hookName)
+		u.notifyHookFailed(hookName, hctx)
+		return errHookFailed
+	}
 	logger.Infof("running %q hook", hookName)
 
 	ranHook := true
@@ -583,6 +664,12 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 
 	if context.IsMissingHookError(err) {
 		ranHook = false
+	} else if stopGracePeriod && context.IsHookTimeoutError(err) {
+		// A stuck stop hook must not keep the unit from being
+		// removed, so it is treated as having completed.
+		logger.Warningf("hook %q killed: %s; continuing unit removal", hookName, err)
+		u.stopHookKilled = err.Error()
+		ranHook = false
 	} else if err != nil {
 		logger.Errorf("hook %q failed: %s", hookName, err)
 		u.notifyHookFailed(hookName, hctx)
@@ -594,12 +681,23 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 	if ranHook {
 		logger.Infof("ran %q hook", hookName)
 		u.notifyHookCompleted(hookName, hctx)
-	} else {
+	} else if context.IsMissingHookError(err) {
 		logger.Infof("skipped %q hook (missing)", hookName)
 	}
 	return u.commitHook(hi)
 }
 
+// updateRelationAddresses updates the unit's private-address setting in
+// every relation it has joined.
+func (u *Uniter) updateRelationAddresses() error {
+	for _, r := range u.relationers {
+		if err := r.UpdatePrivateAddress(); err != nil {
+			return errors.Annotatef(err, "cannot update address in relation %v", r.ru.Relation())
+		}
+	}
+	return nil
+}
+
 // commitHook ensures that state is consistent with the supplied hook, and
 // that the fact of the hook's completion is persisted.
 func (u *Uniter) commitHook(hi hook.Info) error {
@@ -612,6 +710,11 @@ func (u *Uniter) commitHook(hi hook.Info) error {
 			delete(u.relationers, hi.RelationId)
 		}
 	}
+	if hook.IsStorage(hi.Kind) {
+		if err := u.storage.commitHook(hi); err != nil {
+			return err
+		}
+	}
 	if hi.Kind == hooks.ConfigChanged {
 		u.ranConfigChanged = true
 	}
@@ -632,6 +735,8 @@ func (u *Uniter) currentHookName() string {
 		hookName = fmt.Sprintf("%s-%s", name, hookInfo.Kind)
 	} else if hookInfo.Kind == hooks.Action {
 		hookName = fmt.Sprintf("%s-%s", hookName, hookInfo.ActionId)
+	} else if hook.IsStorage(hookInfo.Kind) {
+		hookName = context.StorageHookName(*hookInfo)
 	}
 	return hookName
 }
@@ -857,6 +962,7 @@ func (u *Uniter) watchForProxyChanges(environWatcher apiwatcher.NotifyWatcher) {
 					proxySettings := environConfig.ProxySettings()
 					logger.Debugf("Updating proxy settings: %#v", proxySettings)
 					proxySettings.SetEnvironmentValues()
+					u.setHookConcurrency(environConfig.HookConcurrency())
 				}
 			}
 		}
@@ -39,6 +39,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/hooklock"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/uniter"
 	"github.com/juju/juju/worker/uniter/charm"
@@ -471,6 +472,35 @@ func (s *UniterSuite) TestUniterMultipleErrors(c *gc.C) {
 	s.runUniterTests(c, multipleErrorsTests)
 }
 
+var chaosTests = []uniterTest{
+	ut(
+		"injected hook failure",
+		createCharm{},
+		serveCharm{},
+		ensureStateWorker{},
+		createServiceAndUnit{},
+		setChaos{FailHooks: []string{"install"}},
+		startUniter{},
+		waitAddresses{},
+		waitUnit{
+			status: params.StatusError,
+			info:   `hook failed: "install"`,
+			data: map[string]interface{}{
+				"hook": "install",
+			},
+		},
+		waitHooks{},
+		setChaos{},
+		resolveError{state.ResolvedRetryHooks},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+	),
+}
+
+func (s *UniterSuite) TestUniterChaos(c *gc.C) {
+	s.runUniterTests(c, chaosTests)
+}
+
 var configChangedHookTests = []uniterTest{
 	ut(
 		"config-changed hook fail and resolve",
@@ -624,6 +654,55 @@ var dyingReactionTests = []uniterTest{
 		unitDead,
 		waitUniterDead{},
 		waitHooks{},
+	), ut(
+		"stuck stop hook killed after grace period",
+		createCharm{
+			customize: func(c *gc.C, ctx *context, path string) {
+				appendHook(c, path, "stop", "\nexec sleep 60\n")
+			},
+		},
+		serveCharm{},
+		custom{func(c *gc.C, ctx *context) {
+			err := ctx.st.UpdateEnvironConfig(map[string]interface{}{
+				"stop-hook-grace-period": 1,
+			}, nil, nil)
+			c.Assert(err, gc.IsNil)
+		}},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		unitDying,
+		waitHooks{"stop"},
+		waitUniterDead{},
+		waitUnit{
+			status: params.StatusStopped,
+			info:   "forced stop: stop timed out after 1s",
+		},
+	), ut(
+		"stuck stop hook fails without grace period",
+		createCharm{
+			customize: func(c *gc.C, ctx *context, path string) {
+				appendHook(c, path, "stop", "\nexec sleep 60\n")
+			},
+		},
+		serveCharm{},
+		custom{func(c *gc.C, ctx *context) {
+			err := ctx.st.UpdateEnvironConfig(map[string]interface{}{
+				"hook-timeout": 1,
+			}, nil, nil)
+			c.Assert(err, gc.IsNil)
+		}},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		unitDying,
+		waitUnit{
+			status: params.StatusError,
+			info:   `hook failed: "stop"`,
+			data: map[string]interface{}{
+				"hook": "stop",
+			},
+		},
 	),
 }
 
@@ -1316,6 +1395,59 @@ func (s *UniterSuite) TestUniterMeterStatusChanged(c *gc.C) {
 	s.runUniterTests(c, meterStatusEventTests)
 }
 
+var storageEventTests = []uniterTest{
+	ut(
+		"storage hooks run as storage is attached and detached",
+		quickStart{},
+		addStorage{name: "data"},
+		waitHooks{"data-storage-attached"},
+		destroyStorage{"data/0"},
+		waitHooks{"data-storage-detaching"},
+		waitStorageRemoved{"data/0"},
+	),
+	ut(
+		"storage without a location is not attached",
+		quickStart{},
+		addStorage{name: "data", unattached: true},
+		waitNoHooks{},
+		destroyStorage{"data/0"},
+		waitStorageRemoved{"data/0"},
+		waitNoHooks{},
+	),
+	ut(
+		"storage attached while the uniter was stopped",
+		quickStart{},
+		stopUniter{},
+		addStorage{name: "data"},
+		startUniter{},
+		waitHooks{"data-storage-attached"},
+	),
+	ut(
+		"failed storage hook is retried once resolved",
+		createCharm{badHooks: []string{"data-storage-attached"}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		addStorage{name: "data"},
+		waitHooks{"fail-data-storage-attached"},
+		waitUnit{
+			status: params.StatusError,
+			info:   `hook failed: "data-storage-attached"`,
+			data: map[string]interface{}{
+				"hook": "data-storage-attached",
+			},
+		},
+		fixHook{"data-storage-attached"},
+		resolveError{state.ResolvedRetryHooks},
+		waitHooks{"data-storage-attached"},
+	),
+}
+
+func (s *UniterSuite) TestUniterStorageHooks(c *gc.C) {
+	s.runUniterTests(c, storageEventTests)
+}
+
 var collectMetricsEventTests = []uniterTest{
 	ut(
 		"collect-metrics event triggered by manual timer",
@@ -1760,6 +1892,7 @@ var charmHooks = []string{
 	"install", "start", "config-changed", "upgrade-charm", "stop",
 	"db-relation-joined", "db-relation-changed", "db-relation-departed",
 	"db-relation-broken", "meter-status-changed", "collect-metrics",
+	"data-storage-attached", "data-storage-detaching",
 }
 
 func (s createCharm) step(c *gc.C, ctx *context) {
@@ -1892,7 +2025,7 @@ func (s startUniter) step(c *gc.C, ctx *context) {
 		panic(err.Error())
 	}
 	locksDir := filepath.Join(ctx.dataDir, "locks")
-	lock, err := fslock.NewLock(locksDir, "uniter-hook-execution")
+	lock, err := hooklock.New(locksDir)
 	c.Assert(err, gc.IsNil)
 	if ctx.ticker == nil {
 		ctx.ticker = uniter.NewManualTicker()
@@ -2294,6 +2427,52 @@ func (s changeMeterStatus) step(c *gc.C, ctx *context) {
 	c.Assert(err, gc.IsNil)
 }
 
+type addStorage struct {
+	name       string
+	unattached bool
+}
+
+func (s addStorage) step(c *gc.C, ctx *context) {
+	instance, err := ctx.unit.AddStorageInstance(s.name, state.StorageKindFilesystem)
+	c.Assert(err, gc.IsNil)
+	if !s.unattached {
+		err = instance.SetLocation("/srv/"+s.name, nil)
+		c.Assert(err, gc.IsNil)
+	}
+}
+
+type destroyStorage struct {
+	id string
+}
+
+func (s destroyStorage) step(c *gc.C, ctx *context) {
+	instance, err := ctx.unit.StorageInstance(s.id)
+	c.Assert(err, gc.IsNil)
+	err = instance.Destroy()
+	c.Assert(err, gc.IsNil)
+}
+
+type waitStorageRemoved struct {
+	id string
+}
+
+func (s waitStorageRemoved) step(c *gc.C, ctx *context) {
+	timeout := time.After(worstCase)
+	for {
+		ctx.s.BackingState.StartSync()
+		select {
+		case <-timeout:
+			c.Fatalf("timed out waiting for storage instance %q to be removed", s.id)
+		case <-time.After(coretesting.ShortWait):
+			_, err := ctx.unit.StorageInstance(s.id)
+			if errors.IsNotFound(err) {
+				return
+			}
+			c.Assert(err, gc.IsNil)
+		}
+	}
+}
+
 type metricsTick struct{}
 
 func (s metricsTick) step(c *gc.C, ctx *context) {
@@ -2718,7 +2897,7 @@ func renameRelation(c *gc.C, charmPath, oldName, newName string) {
 
 func createHookLock(c *gc.C, dataDir string) *fslock.Lock {
 	lockDir := filepath.Join(dataDir, "locks")
-	lock, err := fslock.NewLock(lockDir, "uniter-hook-execution")
+	lock, err := fslock.NewLock(lockDir, hooklock.Name)
 	c.Assert(err, gc.IsNil)
 	return lock
 }
@@ -2751,6 +2930,17 @@ var verifyHookSyncLockLocked = custom{func(c *gc.C, ctx *context) {
 	c.Assert(lock.IsLocked(), jc.IsTrue)
 }}
 
+type setChaos state.UnitChaos
+
+func (s setChaos) step(c *gc.C, ctx *context) {
+	err := ctx.st.UpdateEnvironConfig(map[string]interface{}{
+		"enable-chaos-testing": true,
+	}, nil, nil)
+	c.Assert(err, gc.IsNil)
+	err = ctx.unit.SetChaos(state.UnitChaos(s))
+	c.Assert(err, gc.IsNil)
+}
+
 type setProxySettings proxy.Settings
 
 func (s setProxySettings) step(c *gc.C, ctx *context) {
@@ -4,10 +4,17 @@
 package upgrader
 
 import (
+	"errors"
+
 	"github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/version"
 )
 
+// ErrStateServerMoved is returned by an Upgrader when the agent's
+// environment has been moved to another state server, and the agent's
+// configuration has been changed to connect to it.
+var ErrStateServerMoved = errors.New("must reconnect: the environment has moved to another state server")
+
 // UpgradeReadyError is returned by an Upgrader to report that
 // an upgrade is ready to be performed and a restart is due.
 type UpgradeReadyError struct {
@@ -16,6 +16,8 @@ import (
 	"github.com/juju/juju/agent"
 	agenttools "github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/api/upgrader"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/watcher"
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
@@ -29,6 +31,12 @@ var retryAfter = func() <-chan time.Time {
 
 var logger = loggo.GetLogger("juju.worker.upgrader")
 
+// StateServerSetter records the addresses and CA certificate of the
+// state server that an agent should connect to.
+type StateServerSetter interface {
+	SetStateServer(servers [][]network.HostPort, caCert string) error
+}
+
 // Upgrader represents a worker that watches the state for upgrade
 // requests.
 type Upgrader struct {
@@ -38,6 +46,7 @@ type Upgrader struct {
 	tag              names.Tag
 	origAgentVersion version.Number
 	isUpgradeRunning func() bool
+	stateServer      StateServerSetter
 }
 
 // NewUpgrader returns a new upgrader worker. It watches changes to the
@@ -46,11 +55,17 @@ type Upgrader struct {
 // an upgrade is needed, the worker will exit with an UpgradeReadyError
 // holding details of the requested upgrade. The tools will have been
 // downloaded and unpacked.
+//
+// The worker also watches for the agent's environment being moved to
+// another state server. When it is, the new state server's addresses
+// and CA certificate are recorded with stateServer, and the worker
+// exits with ErrStateServerMoved so that the agent reconnects.
 func NewUpgrader(
 	st *upgrader.State,
 	agentConfig agent.Config,
 	origAgentVersion version.Number,
 	isUpgradeRunning func() bool,
+	stateServer StateServerSetter,
 ) *Upgrader {
 	u := &Upgrader{
 		st:               st,
@@ -58,6 +73,7 @@ func NewUpgrader(
 		tag:              agentConfig.Tag(),
 		origAgentVersion: origAgentVersion,
 		isUpgradeRunning: isUpgradeRunning,
+		stateServer:      stateServer,
 	}
 	go func() {
 		defer u.tomb.Done()
@@ -110,6 +126,11 @@ func (u *Upgrader) loop() error {
 	}
 	changes := versionWatcher.Changes()
 	defer watcher.Stop(versionWatcher, &u.tomb)
+	targetWatcher, err := u.st.WatchMigrationTarget(u.tag.String())
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop(targetWatcher, &u.tomb)
 	var retry <-chan time.Time
 	// We don't read on the dying channel until we have received the
 	// initial event from the API version watcher, thus ensuring
@@ -132,6 +153,14 @@ func (u *Upgrader) loop() error {
 			}
 			logger.Infof("desired tool version: %v", wantVersion)
 			dying = u.tomb.Dying()
+		case _, ok := <-targetWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(targetWatcher)
+			}
+			if err := u.checkMigrationTarget(); err != nil {
+				return err
+			}
+			continue
 		case <-retry:
 		case <-dying:
 			return nil
@@ -177,6 +206,23 @@ func (u *Upgrader) loop() error {
 	}
 }
 
+// checkMigrationTarget records the state server that the agent's
+// environment has been moved to, if any, and returns
+// ErrStateServerMoved if it has been moved.
+func (u *Upgrader) checkMigrationTarget() error {
+	target, err := u.st.MigrationTarget(u.tag.String())
+	if params.IsCodeNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	logger.Infof("environment moved to state server at %v", target.APIHostPorts)
+	if err := u.stateServer.SetStateServer(target.APIHostPorts, target.CACert); err != nil {
+		return fmt.Errorf("cannot record new state server: %v", err)
+	}
+	return ErrStateServerMoved
+}
+
 func toBinaryVersion(vers version.Number) version.Binary {
 	outVers := version.Current
 	outVers.Number = vers
@@ -22,6 +22,7 @@ import (
 	envtesting "github.com/juju/juju/environs/testing"
 	envtools "github.com/juju/juju/environs/tools"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
@@ -43,6 +44,7 @@ type UpgraderSuite struct {
 	oldRetryAfter  func() <-chan time.Time
 	confVersion    version.Number
 	upgradeRunning bool
+	stateServer    mockStateServerSetter
 }
 
 type AllowedTargetVersionSuite struct{}
@@ -85,6 +87,17 @@ func agentConfig(tag names.Tag, datadir string) agent.Config {
 	}
 }
 
+type mockStateServerSetter struct {
+	servers [][]network.HostPort
+	caCert  string
+}
+
+func (mock *mockStateServerSetter) SetStateServer(servers [][]network.HostPort, caCert string) error {
+	mock.servers = servers
+	mock.caCert = caCert
+	return nil
+}
+
 func (s *UpgraderSuite) makeUpgrader(c *gc.C) *upgrader.Upgrader {
 	err := s.machine.SetAgentVersion(version.Current)
 	c.Assert(err, gc.IsNil)
@@ -93,6 +106,7 @@ func (s *UpgraderSuite) makeUpgrader(c *gc.C) *upgrader.Upgrader {
 		agentConfig(s.machine.Tag(), s.DataDir()),
 		s.confVersion,
 		func() bool { return s.upgradeRunning },
+		&s.stateServer,
 	)
 }
 
@@ -163,6 +177,42 @@ func (s *UpgraderSuite) TestUpgraderUpgradesImmediately(c *gc.C) {
 	envtesting.CheckTools(c, foundTools, newTools)
 }
 
+func (s *UpgraderSuite) TestUpgraderRecordsMigrationTarget(c *gc.C) {
+	u := s.makeUpgrader(c)
+	defer u.Stop()
+	s.BackingState.StartSync()
+	c.Assert(s.stateServer.servers, gc.IsNil)
+
+	hostPorts := [][]network.HostPort{
+		network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+	}
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	err = env.SetMigrationTarget(state.MigrationTarget{
+		APIHostPorts: hostPorts,
+		CACert:       "new-ca-cert",
+	})
+	c.Assert(err, gc.IsNil)
+	s.BackingState.StartSync()
+
+	select {
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("upgrader did not stop")
+	case err = <-waitErr(u):
+	}
+	c.Assert(err, gc.Equals, upgrader.ErrStateServerMoved)
+	c.Assert(s.stateServer.servers, jc.DeepEquals, hostPorts)
+	c.Assert(s.stateServer.caCert, gc.Equals, "new-ca-cert")
+}
+
+func waitErr(u *upgrader.Upgrader) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- u.Wait()
+	}()
+	return done
+}
+
 func (s *UpgraderSuite) TestUpgraderRetryAndChanged(c *gc.C) {
 	stor := s.DefaultToolsStorage
 	oldTools := envtesting.PrimeTools(c, stor, s.DataDir(), version.MustParseBinary("5.4.3-precise-amd64"))